@@ -27,6 +27,7 @@ type AuthService struct {
 	lockoutDuration  time.Duration
 	sessionDuration  time.Duration
 	cleanupInterval  time.Duration
+	restoreWindow    time.Duration
 }
 
 // NewAuthService creates a new authentication service
@@ -51,6 +52,7 @@ func NewAuthService(
 		lockoutDuration:  time.Minute * 15,
 		sessionDuration:  time.Hour * 24 * 30, // 30 days
 		cleanupInterval:  time.Hour * 24,      // Daily cleanup
+		restoreWindow:    time.Hour * 24 * 30, // as long as the user service keeps deleted users
 	}
 }
 
@@ -298,6 +300,223 @@ func (s *AuthService) LogoutAll(ctx context.Context, userID string) error {
 	return nil
 }
 
+// DeactivateUser blocks login for a user and revokes all of their sessions
+func (s *AuthService) DeactivateUser(ctx context.Context, userID string) error {
+	logger := s.logger.With(
+		zap.String("operation", "deactivate_user"),
+		zap.String("user_id", userID),
+	)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		logger.Warn("Failed to get user", zap.Error(err))
+		return err
+	}
+
+	if user.Status != "inactive" {
+		now := time.Now()
+		user.Status = "inactive"
+		user.DeactivatedAt = &now
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			logger.Error("Failed to deactivate user", zap.Error(err))
+			return err
+		}
+	}
+
+	// Existing sessions would otherwise keep working until they expire
+	if err := s.LogoutAll(ctx, userID); err != nil {
+		logger.Error("Failed to revoke user sessions", zap.Error(err))
+		return err
+	}
+
+	s.auditLogger.LogSecurityEvent(ctx, &domain.SecurityEvent{
+		ID:          uuid.New().String(),
+		EventType:   "account_deactivated",
+		UserID:      userID,
+		Severity:    "medium",
+		Description: "Account deactivated after user deletion",
+		Timestamp:   time.Now(),
+	})
+
+	logger.Info("User deactivated successfully")
+	return nil
+}
+
+// ReactivateUser allows a previously deactivated user to log in again
+func (s *AuthService) ReactivateUser(ctx context.Context, userID string) error {
+	logger := s.logger.With(
+		zap.String("operation", "reactivate_user"),
+		zap.String("user_id", userID),
+	)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		logger.Warn("Failed to get user", zap.Error(err))
+		return err
+	}
+
+	if user.Status == "active" {
+		return nil
+	}
+
+	// Users deleted longer ago than the restore window stay deleted, whatever the caller says
+	if user.DeactivatedAt != nil && time.Since(*user.DeactivatedAt) > s.restoreWindow {
+		logger.Warn("Restore window expired", zap.Time("deactivated_at", *user.DeactivatedAt))
+		return domain.NewAuthError(domain.AUTH_034, "Restore window expired", "The account was deactivated too long ago to be reactivated")
+	}
+
+	user.Status = "active"
+	user.DeactivatedAt = nil
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logger.Error("Failed to reactivate user", zap.Error(err))
+		return err
+	}
+
+	s.auditLogger.LogSecurityEvent(ctx, &domain.SecurityEvent{
+		ID:          uuid.New().String(),
+		EventType:   "account_reactivated",
+		UserID:      userID,
+		Severity:    "low",
+		Description: "Account reactivated after user restore",
+		Timestamp:   time.Now(),
+	})
+
+	logger.Info("User reactivated successfully")
+	return nil
+}
+
+// ProvisionUser creates the login identity of a user imported or created by the user service, keyed
+// on the same ID. The account has no password until one is set through the reset flow. Calling it
+// again updates the email and name and leaves the password alone.
+func (s *AuthService) ProvisionUser(ctx context.Context, userID string, request *domain.ProvisionUserRequest) error {
+	logger := s.logger.With(
+		zap.String("operation", "provision_user"),
+		zap.String("user_id", userID),
+	)
+
+	if existing, err := s.userRepo.GetByEmail(ctx, request.Email); err == nil && existing.ID != userID {
+		logger.Warn("Email registered to another user", zap.String("existing_user_id", existing.ID))
+		return domain.NewAuthError(domain.AUTH_035, "Email already registered", "The email address belongs to another account")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if authErr, ok := err.(*domain.AuthError); !ok || authErr.Code != domain.AUTH_016 {
+			logger.Error("Failed to get user", zap.Error(err))
+			return err
+		}
+
+		user = &domain.User{
+			ID:        userID,
+			Email:     request.Email,
+			FirstName: request.FirstName,
+			LastName:  request.LastName,
+			Role:      "applicant",
+			Status:    "active",
+		}
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			logger.Error("Failed to create user", zap.Error(err))
+			return err
+		}
+
+		logger.Info("User provisioned successfully")
+		return nil
+	}
+
+	user.Email = request.Email
+	user.FirstName = request.FirstName
+	user.LastName = request.LastName
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logger.Error("Failed to update user", zap.Error(err))
+		return err
+	}
+
+	logger.Info("Provisioned user updated successfully")
+	return nil
+}
+
+// RevokeCredentials invalidates a user's password and revokes all of their sessions, so the
+// account can only be used again once a new password is set
+func (s *AuthService) RevokeCredentials(ctx context.Context, userID string) error {
+	logger := s.logger.With(
+		zap.String("operation", "revoke_credentials"),
+		zap.String("user_id", userID),
+	)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		logger.Warn("Failed to get user", zap.Error(err))
+		return err
+	}
+
+	// No password verifies against an empty hash
+	user.PasswordHash = ""
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logger.Error("Failed to revoke password", zap.Error(err))
+		return err
+	}
+
+	if err := s.LogoutAll(ctx, userID); err != nil {
+		logger.Error("Failed to revoke user sessions", zap.Error(err))
+		return err
+	}
+
+	s.auditLogger.LogSecurityEvent(ctx, &domain.SecurityEvent{
+		ID:          uuid.New().String(),
+		EventType:   "credentials_revoked",
+		UserID:      userID,
+		Severity:    "medium",
+		Description: "Password and sessions revoked for a forced password reset",
+		Timestamp:   time.Now(),
+	})
+
+	logger.Info("User credentials revoked successfully")
+	return nil
+}
+
+// SetPassword replaces a user's password and revokes any sessions opened with the old one
+func (s *AuthService) SetPassword(ctx context.Context, userID, password string) error {
+	logger := s.logger.With(
+		zap.String("operation", "set_password"),
+		zap.String("user_id", userID),
+	)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		logger.Warn("Failed to get user", zap.Error(err))
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		logger.Error("Failed to hash password", zap.Error(err))
+		return domain.NewAuthError(domain.AUTH_017, "Password update failed", "Failed to hash password")
+	}
+
+	user.PasswordHash = string(hash)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logger.Error("Failed to set password", zap.Error(err))
+		return err
+	}
+
+	if err := s.LogoutAll(ctx, userID); err != nil {
+		logger.Error("Failed to revoke user sessions", zap.Error(err))
+		return err
+	}
+
+	s.auditLogger.LogSecurityEvent(ctx, &domain.SecurityEvent{
+		ID:          uuid.New().String(),
+		EventType:   "password_reset",
+		UserID:      userID,
+		Severity:    "low",
+		Description: "Password set through a forced password reset",
+		Timestamp:   time.Now(),
+	})
+
+	logger.Info("User password set successfully")
+	return nil
+}
+
 // ValidateAccessToken validates and parses an access token
 func (s *AuthService) ValidateAccessToken(ctx context.Context, token string) (*domain.AuthContext, error) {
 	claims, err := s.tokenManager.ValidateAccessToken(ctx, token)
@@ -32,6 +32,9 @@ type Config struct {
 		Issuer     string        `yaml:"issuer" json:"issuer"`
 		TTL        time.Duration `yaml:"ttl" json:"ttl"`
 	} `yaml:"jwt" json:"jwt"`
+	Internal struct {
+		ServiceToken string `yaml:"service_token" json:"service_token"`
+	} `yaml:"internal" json:"internal"`
 }
 
 func main() {
@@ -148,6 +151,9 @@ func loadConfig() (*Config, error) {
 		}
 	}
 
+	// Internal service-to-service configuration
+	cfg.Internal.ServiceToken = getEnv("INTERNAL_SERVICE_TOKEN", "")
+
 	return cfg, nil
 }
 
@@ -276,6 +282,10 @@ func initServer(config *Config, authService *application.AuthService, appLogger
 		authHandler.RegisterRoutes(auth, authMiddleware)
 	}
 
+	// Internal routes used by other services to propagate account status
+	internal := router.Group("/internal/v1")
+	authHandler.RegisterInternalRoutes(internal, authMiddleware, config.Internal.ServiceToken)
+
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -25,6 +25,17 @@ type AuthService interface {
 	GetUserByEmail(ctx context.Context, email string) (*User, error)
 	UpdateLastLogin(ctx context.Context, userID string) error
 
+	// Account status propagated from the user service
+	DeactivateUser(ctx context.Context, userID string) error
+	ReactivateUser(ctx context.Context, userID string) error
+
+	// ProvisionUser creates the login identity of a user created outside sign-up, without a password
+	ProvisionUser(ctx context.Context, userID string, request *ProvisionUserRequest) error
+
+	// Credentials reset by an administrator through the user service
+	RevokeCredentials(ctx context.Context, userID string) error
+	SetPassword(ctx context.Context, userID, password string) error
+
 	// Session management
 	CreateSession(ctx context.Context, userID, ipAddress, userAgent string) (*Session, error)
 	GetSession(ctx context.Context, sessionID string) (*Session, error)
@@ -146,6 +157,8 @@ const (
 	AUTH_018 = "AUTH_018" // Cache error
 	AUTH_019 = "AUTH_019" // Token generation failed
 	AUTH_020 = "AUTH_020" // Invalid request format
+	AUTH_034 = "AUTH_034" // Restore window expired
+	AUTH_035 = "AUTH_035" // Email registered to another user
 )
 
 // NewAuthError creates a new authentication error
@@ -16,6 +16,9 @@ type User struct {
 	Status       string    `json:"status" db:"status"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	// DeactivatedAt is when the account was deactivated along with its user; the user can only be
+	// restored, and the account reactivated, within the restore window
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty" db:"deactivated_at"`
 }
 
 // Session represents an active user session
@@ -50,6 +53,18 @@ type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+// ProvisionUserRequest creates or updates the login identity of a user created in the user service
+type ProvisionUserRequest struct {
+	Email     string `json:"email" binding:"required,email"`
+	FirstName string `json:"first_name" binding:"required"`
+	LastName  string `json:"last_name" binding:"required"`
+}
+
+// SetPasswordRequest carries a new password set through the user service's reset flow
+type SetPasswordRequest struct {
+	Password string `json:"password" binding:"required,min=8"`
+}
+
 // HTTPSignatureConfig represents HTTP signature configuration
 type HTTPSignatureConfig struct {
 	Algorithm    string        `json:"algorithm"`
@@ -33,7 +33,7 @@ func (r *PostgresUserRepository) GetByID(ctx context.Context, id string) (*domai
 	)
 
 	query := `
-		SELECT id, email, password_hash, first_name, last_name, role, status, created_at, updated_at
+		SELECT id, email, password_hash, first_name, last_name, role, status, created_at, updated_at, deactivated_at
 		FROM users 
 		WHERE id = $1 AND deleted_at IS NULL`
 
@@ -60,7 +60,7 @@ func (r *PostgresUserRepository) GetByEmail(ctx context.Context, email string) (
 	)
 
 	query := `
-		SELECT id, email, password_hash, first_name, last_name, role, status, created_at, updated_at
+		SELECT id, email, password_hash, first_name, last_name, role, status, created_at, updated_at, deactivated_at
 		FROM users 
 		WHERE LOWER(email) = LOWER($1) AND deleted_at IS NULL`
 
@@ -117,14 +117,14 @@ func (r *PostgresUserRepository) Update(ctx context.Context, user *domain.User)
 	query := `
 		UPDATE users 
 		SET email = $2, password_hash = $3, first_name = $4, last_name = $5, 
-		    role = $6, status = $7, updated_at = $8
+		    role = $6, status = $7, updated_at = $8, deactivated_at = $9
 		WHERE id = $1 AND deleted_at IS NULL`
 
 	user.UpdatedAt = time.Now()
 
 	result, err := r.db.ExecContext(ctx, query,
 		user.ID, user.Email, user.PasswordHash, user.FirstName, user.LastName,
-		user.Role, user.Status, user.UpdatedAt)
+		user.Role, user.Status, user.UpdatedAt, user.DeactivatedAt)
 
 	if err != nil {
 		logger.Error("Failed to update user", zap.Error(err))
@@ -0,0 +1,101 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/auth/domain"
+)
+
+// RegisterInternalRoutes registers service-to-service routes guarded by the internal service token
+func (h *AuthHandler) RegisterInternalRoutes(router *gin.RouterGroup, authMiddleware *AuthMiddleware, serviceToken string) {
+	router.Use(authMiddleware.RequireServiceToken(serviceToken))
+	router.PUT("/users/:id", h.ProvisionUser)
+	router.POST("/users/:id/deactivate", h.DeactivateUser)
+	router.POST("/users/:id/reactivate", h.ReactivateUser)
+	router.POST("/users/:id/revoke-credentials", h.RevokeCredentials)
+	router.PUT("/users/:id/password", h.SetPassword)
+}
+
+// ProvisionUser handles a login identity created for a user imported by the user service
+// PUT /internal/v1/users/:id
+func (h *AuthHandler) ProvisionUser(c *gin.Context) {
+	var request domain.ProvisionUserRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, domain.AUTH_020, nil)
+		return
+	}
+
+	h.updateAccountStatus(c, "provision_user", func(ctx context.Context, userID string) error {
+		return h.authService.ProvisionUser(ctx, userID, &request)
+	})
+}
+
+// DeactivateUser handles account deactivation pushed by the user service
+// POST /internal/v1/users/:id/deactivate
+func (h *AuthHandler) DeactivateUser(c *gin.Context) {
+	h.updateAccountStatus(c, "deactivate_user", h.authService.DeactivateUser)
+}
+
+// ReactivateUser handles account reactivation pushed by the user service
+// POST /internal/v1/users/:id/reactivate
+func (h *AuthHandler) ReactivateUser(c *gin.Context) {
+	h.updateAccountStatus(c, "reactivate_user", h.authService.ReactivateUser)
+}
+
+// RevokeCredentials handles a forced password reset started in the user service
+// POST /internal/v1/users/:id/revoke-credentials
+func (h *AuthHandler) RevokeCredentials(c *gin.Context) {
+	h.updateAccountStatus(c, "revoke_credentials", h.authService.RevokeCredentials)
+}
+
+// SetPassword handles a password reset redeemed in the user service
+// PUT /internal/v1/users/:id/password
+func (h *AuthHandler) SetPassword(c *gin.Context) {
+	var request domain.SetPasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, domain.AUTH_020, nil)
+		return
+	}
+
+	h.updateAccountStatus(c, "set_password", func(ctx context.Context, userID string) error {
+		return h.authService.SetPassword(ctx, userID, request.Password)
+	})
+}
+
+func (h *AuthHandler) updateAccountStatus(c *gin.Context, operation string, update func(ctx context.Context, userID string) error) {
+	userID := c.Param("id")
+	logger := h.logger.With(
+		zap.String("operation", operation),
+		zap.String("user_id", userID),
+	)
+
+	if err := update(c.Request.Context(), userID); err != nil {
+		if authErr, ok := err.(*domain.AuthError); ok {
+			logger.Warn("Account status update failed", zap.String("error_code", authErr.Code))
+
+			statusCode := http.StatusInternalServerError
+			switch authErr.Code {
+			case domain.AUTH_016:
+				statusCode = http.StatusNotFound
+			case domain.AUTH_034:
+				statusCode = http.StatusGone
+			case domain.AUTH_035:
+				statusCode = http.StatusConflict
+			}
+
+			h.respondWithError(c, statusCode, authErr.Code, nil)
+			return
+		}
+
+		logger.Error("Unexpected error during account status update", zap.Error(err))
+		h.respondWithError(c, http.StatusInternalServerError, domain.AUTH_017, nil)
+		return
+	}
+
+	logger.Info("Account status updated")
+	h.respondWithSuccess(c, nil, "ACCOUNT_STATUS_UPDATED", nil)
+}
@@ -1,6 +1,7 @@
 package interfaces
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"strings"
 	"time"
@@ -197,6 +198,21 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 	}
 }
 
+// RequireServiceToken middleware admits internal callers presenting the shared service token
+func (m *AuthMiddleware) RequireServiceToken(serviceToken string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if serviceToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(serviceToken)) != 1 {
+			m.logger.Warn("Rejected internal call with invalid service token",
+				zap.String("path", c.Request.URL.Path))
+			m.respondWithError(c, http.StatusUnauthorized, domain.AUTH_004, "Invalid service token")
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // HTTPSignatureAuth middleware validates HTTP signatures
 func (m *AuthMiddleware) HTTPSignatureAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
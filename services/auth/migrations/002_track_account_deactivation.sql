@@ -0,0 +1,8 @@
+-- Migration: 002_track_account_deactivation.sql
+-- Description: Record when an account was deactivated along with its user, so it is only
+-- reactivated while the user can still be restored
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMP;
+
+-- Accounts already inactive are taken to have been deactivated when they were last updated
+UPDATE users SET deactivated_at = updated_at WHERE status = 'inactive' AND deactivated_at IS NULL;
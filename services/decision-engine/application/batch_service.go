@@ -0,0 +1,397 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// BatchDecisionService decides batches of applications asynchronously, for portfolio re-scoring
+// and marketing pre-approvals. Each batch runs as a job in the instance that accepted it:
+// applications are decided by a bounded pool of workers, shared by all jobs, and their decisions
+// and item results are saved in bulk as they complete.
+type BatchDecisionService struct {
+	decisions    *DecisionEngineService
+	batchRepo    domain.BatchRepository
+	decisionRepo domain.DecisionRepository
+	policy       domain.BatchPolicy
+	workers      chan struct{} // one slot per application being decided, across all jobs
+	logger       *zap.Logger
+
+	mu         sync.Mutex
+	activeJobs int
+}
+
+// NewBatchDecisionService creates a new batch decision service; unset policy limits take the
+// defaults
+func NewBatchDecisionService(
+	decisions *DecisionEngineService,
+	batchRepo domain.BatchRepository,
+	decisionRepo domain.DecisionRepository,
+	policy domain.BatchPolicy,
+	logger *zap.Logger,
+) *BatchDecisionService {
+	policy = policy.WithDefaults()
+	return &BatchDecisionService{
+		decisions:    decisions,
+		batchRepo:    batchRepo,
+		decisionRepo: decisionRepo,
+		policy:       policy,
+		workers:      make(chan struct{}, policy.MaxConcurrency),
+		logger:       logger,
+	}
+}
+
+// batchOutcome is a decided application waiting to be saved
+type batchOutcome struct {
+	result   domain.BatchItemResult
+	decision *domain.DecisionResponse
+	request  *domain.DecisionRequest
+	late     []*sourceFetch // data sources the decision is provisional on
+}
+
+// SubmitBatch accepts a batch of applications and starts deciding them in the background. The
+// returned job is pending; its progress and results are read back by id.
+func (s *BatchDecisionService) SubmitBatch(ctx context.Context, request *domain.BatchDecisionRequest, requestedBy string) (*domain.BatchJob, error) {
+	if err := request.Normalize(s.policy); err != nil {
+		return nil, &domain.DecisionError{
+			Code:        domain.ERROR_INVALID_REQUEST,
+			Message:     "Invalid batch decision request",
+			Description: err.Error(),
+			HTTPStatus:  400,
+		}
+	}
+
+	if !s.reserveJob() {
+		return nil, &domain.DecisionError{
+			Code:        domain.ERROR_BATCH_CAPACITY,
+			Message:     "Too many batch decision jobs are running",
+			Description: fmt.Sprintf("At most %d batch jobs may run at once; retry when one finishes", s.policy.MaxActiveJobs),
+			HTTPStatus:  429,
+		}
+	}
+
+	now := time.Now().UTC()
+	job := &domain.BatchJob{
+		Purpose:     request.Purpose,
+		Reference:   request.Reference,
+		Status:      domain.BatchJobPending,
+		Concurrency: request.Concurrency,
+		Total:       len(request.Applications),
+		Decisions:   map[domain.DecisionType]int{},
+		RequestedBy: requestedBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.batchRepo.CreateBatchJob(ctx, job); err != nil {
+		s.releaseJob()
+		return nil, s.databaseError(err)
+	}
+
+	s.logger.Info("Batch decision job accepted",
+		zap.Int64("job_id", job.ID),
+		zap.String("purpose", string(job.Purpose)),
+		zap.Int("applications", job.Total),
+		zap.Int("concurrency", job.Concurrency),
+		zap.String("requested_by", requestedBy),
+	)
+
+	accepted := *job
+	accepted.Decisions = map[domain.DecisionType]int{}
+	go s.run(job, request.Applications)
+	return &accepted, nil
+}
+
+// GetBatchJob returns a batch job and its progress
+func (s *BatchDecisionService) GetBatchJob(ctx context.Context, id int64) (*domain.BatchJob, error) {
+	job, err := s.batchRepo.GetBatchJob(ctx, id)
+	if err != nil {
+		return nil, s.repositoryError(id, err)
+	}
+	return job, nil
+}
+
+// GetBatchResults returns a page of a batch job's item results
+func (s *BatchDecisionService) GetBatchResults(ctx context.Context, query domain.BatchResultsQuery) (*domain.BatchResultsPage, error) {
+	if query.Status != "" && !query.Status.IsValid() {
+		return nil, &domain.DecisionError{
+			Code:        domain.ERROR_INVALID_REQUEST,
+			Message:     "Invalid result status",
+			Description: fmt.Sprintf("status must be %s or %s", domain.BatchItemSucceeded, domain.BatchItemFailed),
+			HTTPStatus:  400,
+		}
+	}
+	if query.Limit <= 0 {
+		query.Limit = domain.DefaultBatchResultsLimit
+	}
+	if query.Limit > domain.MaxBatchResultsLimit {
+		query.Limit = domain.MaxBatchResultsLimit
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+
+	if _, err := s.batchRepo.GetBatchJob(ctx, query.JobID); err != nil {
+		return nil, s.repositoryError(query.JobID, err)
+	}
+	page, err := s.batchRepo.GetBatchResults(ctx, query)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	return page, nil
+}
+
+// Start marks jobs failed once they stop saving progress, checking every interval until ctx is
+// cancelled. Jobs are not resumed: a batch cut off by a restart is resubmitted.
+func (s *BatchDecisionService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now().UTC()
+			failed, err := s.batchRepo.FailStaleBatchJobs(ctx, now.Add(-s.policy.StaleAfter), "Job stopped saving progress; its instance may have restarted", now)
+			if err != nil {
+				s.logger.Warn("Failed to check for stale batch decision jobs", zap.Error(err))
+				continue
+			}
+			if failed > 0 {
+				s.logger.Warn("Marked stale batch decision jobs failed", zap.Int("jobs", failed))
+			}
+		}
+	}
+}
+
+// run decides a job's applications and saves them in chunks of the flush size. A job whose
+// results cannot be saved stops taking on applications and fails.
+func (s *BatchDecisionService) run(job *domain.BatchJob, applications []domain.DecisionRequest) {
+	defer s.releaseJob()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := s.logger.With(zap.Int64("job_id", job.ID), zap.String("operation", "run_batch"))
+
+	started := time.Now().UTC()
+	job.Status = domain.BatchJobRunning
+	job.StartedAt = &started
+	job.UpdatedAt = started
+	s.saveJob(ctx, job, logger)
+
+	outcomes := make(chan batchOutcome, job.Concurrency)
+	go s.dispatch(ctx, job, applications, outcomes)
+
+	pending := make([]batchOutcome, 0, s.policy.FlushSize)
+	var saveErr error
+	for outcome := range outcomes {
+		if saveErr != nil {
+			continue
+		}
+		pending = append(pending, outcome)
+		if len(pending) < s.policy.FlushSize {
+			continue
+		}
+		if saveErr = s.flush(ctx, job, pending, logger); saveErr != nil {
+			cancel()
+		}
+		pending = pending[:0]
+	}
+	if saveErr == nil {
+		saveErr = s.flush(ctx, job, pending, logger)
+	}
+
+	finished := time.Now().UTC()
+	job.Status = domain.BatchJobCompleted
+	if saveErr != nil {
+		job.Status = domain.BatchJobFailed
+		job.Error = saveErr.Error()
+	}
+	job.CompletedAt = &finished
+	job.UpdatedAt = finished
+	s.saveJob(context.Background(), job, logger)
+
+	logger.Info("Batch decision job finished",
+		zap.String("status", string(job.Status)),
+		zap.Int("processed", job.Processed),
+		zap.Int("succeeded", job.Succeeded),
+		zap.Int("failed", job.Failed),
+		zap.Duration("duration", finished.Sub(started)),
+	)
+}
+
+// dispatch decides the applications, at most the job's concurrency at a time and only while a
+// shared worker slot is free, and closes outcomes once every started decision is done
+func (s *BatchDecisionService) dispatch(ctx context.Context, job *domain.BatchJob, applications []domain.DecisionRequest, outcomes chan<- batchOutcome) {
+	var wg sync.WaitGroup
+	defer func() {
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	jobSlots := make(chan struct{}, job.Concurrency)
+	for i := range applications {
+		select {
+		case <-ctx.Done():
+			return
+		case jobSlots <- struct{}{}:
+		}
+		select {
+		case <-ctx.Done():
+			<-jobSlots
+			return
+		case s.workers <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(index int, request domain.DecisionRequest) {
+			defer wg.Done()
+			defer func() {
+				<-s.workers
+				<-jobSlots
+			}()
+			outcomes <- s.decideItem(ctx, job.ID, index, &request)
+		}(i, applications[i])
+	}
+}
+
+// decideItem decides one application; failures become failed item results
+func (s *BatchDecisionService) decideItem(ctx context.Context, jobID int64, index int, request *domain.DecisionRequest) (outcome batchOutcome) {
+	outcome.result = domain.BatchItemResult{
+		JobID:         jobID,
+		ItemIndex:     index,
+		ApplicationID: request.ApplicationID,
+		Status:        domain.BatchItemSucceeded,
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("Batch decision panicked", zap.Int64("job_id", jobID), zap.Int("item_index", index), zap.Any("panic", r))
+			outcome.decision, outcome.request = nil, nil
+			outcome.result.Fail(fmt.Errorf("decision failed: %v", r))
+		}
+		outcome.result.ProcessedAt = time.Now().UTC()
+	}()
+
+	if err := request.Validate(); err != nil {
+		outcome.result.Fail(err)
+		return outcome
+	}
+	decision, late, err := s.decisions.decide(ctx, request)
+	if err != nil {
+		outcome.result.Fail(err)
+		return outcome
+	}
+
+	outcome.decision = decision
+	outcome.request = request
+	outcome.late = late
+	outcome.result.Decision = decision.Decision
+	outcome.result.RiskCategory = decision.RiskCategory
+	outcome.result.MaxAmount = decision.MaxAmount
+	outcome.result.InterestRate = decision.InterestRate
+	return outcome
+}
+
+// flush saves a chunk of decisions in bulk, then their item results, and records the chunk in
+// the job's progress. Decisions that cannot be saved fail their items; results that cannot be
+// saved fail the job.
+func (s *BatchDecisionService) flush(ctx context.Context, job *domain.BatchJob, chunk []batchOutcome, logger *zap.Logger) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+
+	var (
+		decisions []*domain.DecisionResponse
+		requests  []*domain.DecisionRequest
+	)
+	for _, outcome := range chunk {
+		if outcome.decision != nil {
+			decisions = append(decisions, outcome.decision)
+			requests = append(requests, outcome.request)
+		}
+	}
+	saveErr := s.decisionRepo.SaveDecisions(ctx, decisions, requests)
+	if saveErr != nil {
+		logger.Error("Failed to save batch decisions", zap.Int("decisions", len(decisions)), zap.Error(saveErr))
+	}
+
+	results := make([]domain.BatchItemResult, len(chunk))
+	for i, outcome := range chunk {
+		results[i] = outcome.result
+		if outcome.decision == nil {
+			continue
+		}
+		if saveErr != nil {
+			results[i].Fail(s.databaseError(saveErr))
+			continue
+		}
+		results[i].DecisionID = outcome.decision.DecisionID
+		s.decisions.awaitLateData(outcome.request, outcome.late)
+	}
+	if err := s.batchRepo.SaveBatchResults(ctx, results); err != nil {
+		logger.Error("Failed to save batch results", zap.Error(err))
+		return fmt.Errorf("failed to save results: %w", err)
+	}
+
+	for i := range results {
+		job.Record(&results[i])
+	}
+	job.UpdatedAt = time.Now().UTC()
+	s.saveJob(ctx, job, logger)
+	return nil
+}
+
+// saveJob saves a job's progress; a failed save only delays what readers see
+func (s *BatchDecisionService) saveJob(ctx context.Context, job *domain.BatchJob, logger *zap.Logger) {
+	if err := s.batchRepo.UpdateBatchJob(ctx, job); err != nil {
+		logger.Warn("Failed to save batch job progress", zap.Error(err))
+	}
+}
+
+// reserveJob takes one of the policy's active job slots, reporting false when none is free
+func (s *BatchDecisionService) reserveJob() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.activeJobs >= s.policy.MaxActiveJobs {
+		return false
+	}
+	s.activeJobs++
+	return true
+}
+
+func (s *BatchDecisionService) releaseJob() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.activeJobs--
+}
+
+func (s *BatchDecisionService) repositoryError(id int64, err error) error {
+	if isNotFound(err) {
+		return &domain.DecisionError{
+			Code:        domain.ERROR_BATCH_JOB_NOT_FOUND,
+			Message:     "Batch decision job not found",
+			Description: fmt.Sprintf("No batch decision job found with id %d", id),
+			HTTPStatus:  404,
+		}
+	}
+	return s.databaseError(err)
+}
+
+func (s *BatchDecisionService) databaseError(err error) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_DATABASE_ERROR,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
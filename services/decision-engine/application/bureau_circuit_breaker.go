@@ -0,0 +1,271 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// bureauBreakerMetrics counts, per bureau, "<bureau>.opened" (times the breaker opened),
+// "<bureau>.rejected" (pulls refused while open) and "<bureau>.retries". It is published at
+// /debug/vars.
+var bureauBreakerMetrics = expvar.NewMap("decision_bureau_circuit_breaker")
+
+// BreakerPolicy configures bureau circuit breakers and retries
+type BreakerPolicy struct {
+	FailureThreshold int           // consecutive failures that open a breaker
+	OpenTimeout      time.Duration // how long a breaker stays open before a trial pull
+	RetryAttempts    int           // retries after the first attempt
+	RetryBaseDelay   time.Duration // first retry delay, doubled on each retry
+	RetryMaxDelay    time.Duration
+}
+
+// withDefaults fills in unset settings
+func (p BreakerPolicy) withDefaults() BreakerPolicy {
+	if p.FailureThreshold <= 0 {
+		p.FailureThreshold = 5
+	}
+	if p.OpenTimeout <= 0 {
+		p.OpenTimeout = 30 * time.Second
+	}
+	if p.RetryAttempts < 0 {
+		p.RetryAttempts = 0
+	}
+	if p.RetryBaseDelay <= 0 {
+		p.RetryBaseDelay = 200 * time.Millisecond
+	}
+	if p.RetryMaxDelay < p.RetryBaseDelay {
+		p.RetryMaxDelay = 2 * time.Second
+	}
+	return p
+}
+
+// BureauCircuitBreaker implements domain.CreditBureauClient over another bureau client. Failed
+// pulls are retried with jittered exponential backoff, and a bureau that keeps failing is cut
+// off by its circuit breaker until a trial pull succeeds. Request errors, such as missing
+// consent, are returned as is and do not count against the bureau.
+type BureauCircuitBreaker struct {
+	bureaus domain.CreditBureauClient
+	policy  BreakerPolicy
+	logger  *zap.Logger
+
+	mu     sync.Mutex
+	health map[domain.Bureau]*bureauBreaker
+	rand   *rand.Rand
+}
+
+// bureauBreaker is the breaker state of one bureau
+type bureauBreaker struct {
+	domain.BureauHealth
+	probing bool // a half-open trial pull is in flight
+}
+
+// NewBureauCircuitBreaker creates a new bureau circuit breaker
+func NewBureauCircuitBreaker(bureaus domain.CreditBureauClient, policy BreakerPolicy, logger *zap.Logger) *BureauCircuitBreaker {
+	health := make(map[domain.Bureau]*bureauBreaker, len(domain.Bureaus))
+	for _, bureau := range domain.Bureaus {
+		health[bureau] = &bureauBreaker{BureauHealth: domain.BureauHealth{Bureau: bureau, State: domain.BreakerClosed}}
+	}
+
+	return &BureauCircuitBreaker{
+		bureaus: bureaus,
+		policy:  policy.withDefaults(),
+		logger:  logger,
+		health:  health,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// PullBureauReport pulls a bureau report through the bureau's breaker, retrying failures
+func (b *BureauCircuitBreaker) PullBureauReport(ctx context.Context, bureau domain.Bureau, request *domain.CreditReportRequest) (*domain.BureauReport, error) {
+	logger := b.logger.With(zap.String("bureau", string(bureau)), zap.String("user_id", request.UserID))
+
+	var lastErr error
+	for attempt := 0; attempt <= b.policy.RetryAttempts; attempt++ {
+		if attempt > 0 {
+			delay := b.backoff(attempt)
+			bureauBreakerMetrics.Add(string(bureau)+".retries", 1)
+			logger.Info("Retrying bureau pull", zap.Int("attempt", attempt+1), zap.Duration("delay", delay), zap.Error(lastErr))
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		if err := b.allow(bureau); err != nil {
+			bureauBreakerMetrics.Add(string(bureau)+".rejected", 1)
+			if lastErr != nil {
+				return nil, b.failureError(bureau, lastErr)
+			}
+			return nil, err
+		}
+
+		report, err := b.bureaus.PullBureauReport(ctx, bureau, request)
+		if err == nil {
+			b.recordSuccess(bureau)
+			return report, nil
+		}
+
+		var decisionErr *domain.DecisionError
+		if errors.As(err, &decisionErr) || ctx.Err() != nil {
+			// The request, not the bureau, failed: release a trial slot without judging the bureau
+			b.release(bureau)
+			return nil, err
+		}
+
+		lastErr = err
+		b.recordFailure(bureau, err)
+	}
+
+	return nil, b.failureError(bureau, lastErr)
+}
+
+// Health returns the breaker state of every bureau
+func (b *BureauCircuitBreaker) Health() []domain.BureauHealth {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	health := make([]domain.BureauHealth, 0, len(domain.Bureaus))
+	for _, bureau := range domain.Bureaus {
+		health = append(health, b.health[bureau].BureauHealth)
+	}
+	return health
+}
+
+// allow reports whether a pull may go to the bureau, moving an open breaker to half-open once
+// its timeout has passed
+func (b *BureauCircuitBreaker) allow(bureau domain.Bureau) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	breaker, ok := b.health[bureau]
+	if !ok {
+		return fmt.Errorf("unknown credit bureau: %s", bureau)
+	}
+
+	switch breaker.State {
+	case domain.BreakerOpen:
+		if breaker.RetryAt != nil && time.Now().UTC().Before(*breaker.RetryAt) {
+			return unavailableError(bureau, breaker.RetryAt)
+		}
+		breaker.State = domain.BreakerHalfOpen
+		breaker.probing = true
+		b.logger.Info("Bureau circuit breaker half-open, sending trial pull", zap.String("bureau", string(bureau)))
+	case domain.BreakerHalfOpen:
+		if breaker.probing {
+			return unavailableError(bureau, breaker.RetryAt)
+		}
+		breaker.probing = true
+	}
+	return nil
+}
+
+func (b *BureauCircuitBreaker) recordSuccess(bureau domain.Bureau) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	breaker := b.health[bureau]
+	if breaker.State != domain.BreakerClosed {
+		b.logger.Info("Bureau circuit breaker closed", zap.String("bureau", string(bureau)))
+	}
+	now := time.Now().UTC()
+	breaker.State = domain.BreakerClosed
+	breaker.ConsecutiveFailures = 0
+	breaker.LastSuccessAt = &now
+	breaker.OpenedAt = nil
+	breaker.RetryAt = nil
+	breaker.probing = false
+}
+
+func (b *BureauCircuitBreaker) recordFailure(bureau domain.Bureau, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	breaker := b.health[bureau]
+	now := time.Now().UTC()
+	breaker.ConsecutiveFailures++
+	breaker.LastError = err.Error()
+	breaker.LastFailureAt = &now
+
+	if breaker.State == domain.BreakerHalfOpen || breaker.ConsecutiveFailures >= b.policy.FailureThreshold {
+		retryAt := now.Add(b.policy.OpenTimeout)
+		breaker.State = domain.BreakerOpen
+		breaker.OpenedAt = &now
+		breaker.RetryAt = &retryAt
+		breaker.probing = false
+
+		bureauBreakerMetrics.Add(string(bureau)+".opened", 1)
+		b.logger.Error("Bureau circuit breaker opened",
+			zap.String("bureau", string(bureau)),
+			zap.Int("consecutive_failures", breaker.ConsecutiveFailures),
+			zap.Time("retry_at", retryAt),
+			zap.Error(err),
+		)
+	}
+}
+
+// release ends a trial pull that said nothing about the bureau's health
+func (b *BureauCircuitBreaker) release(bureau domain.Bureau) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if breaker, ok := b.health[bureau]; ok {
+		breaker.probing = false
+	}
+}
+
+// backoff returns the delay before a retry: exponential from the base delay, capped at the
+// maximum, with full jitter over its upper half
+func (b *BureauCircuitBreaker) backoff(attempt int) time.Duration {
+	delay := b.policy.RetryBaseDelay << uint(attempt-1)
+	if delay <= 0 || delay > b.policy.RetryMaxDelay {
+		delay = b.policy.RetryMaxDelay
+	}
+
+	b.mu.Lock()
+	jitter := time.Duration(b.rand.Int63n(int64(delay/2) + 1))
+	b.mu.Unlock()
+	return delay/2 + jitter
+}
+
+// failureError reports a bureau that failed every attempt; timeouts are reported as 504
+func (b *BureauCircuitBreaker) failureError(bureau domain.Bureau, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &domain.DecisionError{
+			Code:        domain.ERROR_EXTERNAL_SERVICE,
+			Message:     "Credit bureau timed out",
+			Description: fmt.Sprintf("%s did not respond in time: %v", bureau, err),
+			HTTPStatus:  504,
+		}
+	}
+	return &domain.DecisionError{
+		Code:        domain.ERROR_EXTERNAL_SERVICE,
+		Message:     "Credit bureau request failed",
+		Description: fmt.Sprintf("%s: %v", bureau, err),
+		HTTPStatus:  502,
+	}
+}
+
+func unavailableError(bureau domain.Bureau, retryAt *time.Time) error {
+	description := fmt.Sprintf("%s is unavailable after repeated failures", bureau)
+	if retryAt != nil {
+		description = fmt.Sprintf("%s; retrying after %s", description, retryAt.Format(time.RFC3339))
+	}
+	return &domain.DecisionError{
+		Code:        domain.ERROR_BUREAU_UNAVAILABLE,
+		Message:     "Credit bureau unavailable",
+		Description: description,
+		HTTPStatus:  503,
+	}
+}
@@ -0,0 +1,130 @@
+package application
+
+import (
+	"context"
+	"expvar"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// creditReportCacheMetrics counts cache lookups: "hits" (served from a stored report, of which
+// "index_hits" were found through the index), "misses" and "forced_refreshes". It is published
+// at /debug/vars.
+var creditReportCacheMetrics = expvar.NewMap("decision_credit_report_cache")
+
+// CreditReportCache implements domain.CreditBureauClient over another bureau client, reusing the
+// report last pulled for an applicant from a bureau while it is within the cache TTL. A soft
+// report never answers a hard request. Stored reports are the cache; the index, when
+// configured, finds them without searching.
+type CreditReportCache struct {
+	bureaus domain.CreditBureauClient
+	repo    domain.CreditReportRepository
+	index   domain.CreditReportIndex // optional
+	policy  domain.CreditReportCachePolicy
+	logger  *zap.Logger
+}
+
+// NewCreditReportCache creates a new credit report cache. A nil index looks reports up in the
+// repository only.
+func NewCreditReportCache(bureaus domain.CreditBureauClient, repo domain.CreditReportRepository, index domain.CreditReportIndex, policy domain.CreditReportCachePolicy, logger *zap.Logger) *CreditReportCache {
+	return &CreditReportCache{
+		bureaus: bureaus,
+		repo:    repo,
+		index:   index,
+		policy:  policy,
+		logger:  logger,
+	}
+}
+
+// PullBureauReport returns the cached report for the applicant and bureau when it is still fresh,
+// and pulls the bureau otherwise. ForceRefresh always pulls the bureau.
+func (c *CreditReportCache) PullBureauReport(ctx context.Context, bureau domain.Bureau, request *domain.CreditReportRequest) (*domain.BureauReport, error) {
+	logger := c.logger.With(
+		zap.String("user_id", request.UserID),
+		zap.String("bureau", string(bureau)),
+	)
+
+	if c.policy.TTL <= 0 || request.SSNToken == "" {
+		return c.bureaus.PullBureauReport(ctx, bureau, request)
+	}
+
+	if request.ForceRefresh {
+		creditReportCacheMetrics.Add("forced_refreshes", 1)
+		logger.Info("Credit report cache bypassed by forced refresh")
+		c.forget(ctx, request.SSNToken, bureau)
+		return c.bureaus.PullBureauReport(ctx, bureau, request)
+	}
+
+	if report := c.lookup(ctx, bureau, request); report != nil {
+		creditReportCacheMetrics.Add("hits", 1)
+		logger.Info("Credit report served from cache",
+			zap.Int64("report_id", report.ID),
+			zap.Time("pulled_at", report.PulledAt),
+		)
+		report.Cached = true
+		return report, nil
+	}
+
+	creditReportCacheMetrics.Add("misses", 1)
+	return c.bureaus.PullBureauReport(ctx, bureau, request)
+}
+
+// lookup finds a fresh stored report through the index, then in the repository; lookup failures
+// are logged and treated as misses
+func (c *CreditReportCache) lookup(ctx context.Context, bureau domain.Bureau, request *domain.CreditReportRequest) *domain.BureauReport {
+	logger := c.logger.With(zap.String("user_id", request.UserID), zap.String("bureau", string(bureau)))
+	now := time.Now().UTC()
+
+	if c.index != nil {
+		id, err := c.index.LookupBureauReport(ctx, request.SSNToken, bureau)
+		if err != nil {
+			logger.Warn("Credit report index lookup failed", zap.Error(err))
+		} else if id > 0 {
+			report, err := c.repo.GetBureauReport(ctx, id)
+			if err != nil {
+				logger.Warn("Failed to load indexed credit report", zap.Int64("report_id", id), zap.Error(err))
+			} else if c.reusable(report, request, now) {
+				creditReportCacheMetrics.Add("index_hits", 1)
+				return report
+			}
+		}
+	}
+
+	pullTypes := request.EffectivePullType().ReusablePullTypes()
+	report, err := c.repo.GetLatestBureauReport(ctx, request.UserID, request.SSNToken, bureau, pullTypes, now.Add(-c.policy.TTL))
+	if err != nil {
+		logger.Warn("Credit report cache lookup failed", zap.Error(err))
+		return nil
+	}
+	if report == nil || !c.reusable(report, request, now) {
+		return nil
+	}
+
+	if c.index != nil {
+		if err := c.index.IndexBureauReport(ctx, request.SSNToken, bureau, report.ID, c.policy.Remaining(report.PulledAt, now)); err != nil {
+			logger.Warn("Failed to index credit report", zap.Int64("report_id", report.ID), zap.Error(err))
+		}
+	}
+	return report
+}
+
+// reusable reports whether a stored report is fresh, was pulled for the requesting user and was
+// pulled hard enough for the request
+func (c *CreditReportCache) reusable(report *domain.BureauReport, request *domain.CreditReportRequest, now time.Time) bool {
+	return report.Report != nil &&
+		report.Report.UserID == request.UserID &&
+		report.PullType.Serves(request.EffectivePullType()) &&
+		c.policy.Fresh(report.PulledAt, now)
+}
+
+// forget drops the index entry so the next lookup finds the refreshed report
+func (c *CreditReportCache) forget(ctx context.Context, ssnToken string, bureau domain.Bureau) {
+	if c.index == nil {
+		return
+	}
+	if err := c.index.ForgetBureauReport(ctx, ssnToken, bureau); err != nil {
+		c.logger.Warn("Failed to remove credit report index entry", zap.String("bureau", string(bureau)), zap.Error(err))
+	}
+}
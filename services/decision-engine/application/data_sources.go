@@ -0,0 +1,207 @@
+package application
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// redecisionTimeout bounds a re-decision made when late data arrives
+const redecisionTimeout = 30 * time.Second
+
+// sourceFetch is a data source fetched for a decision in the background. Its result is a function
+// recording the data with the request, so the request is only changed by the goroutine deciding.
+type sourceFetch struct {
+	source   domain.DataSource
+	sla      time.Duration
+	deadline time.Time // end of the SLA
+	result   chan func(*domain.DecisionRequest)
+}
+
+// startFetch starts fetching a source. The fetch outlives the caller's context: it runs until its
+// SLA and the late data window have passed, so a late result can still re-decide the application.
+func (s *DecisionEngineService) startFetch(
+	ctx context.Context,
+	source domain.DataSource,
+	fetch func(ctx context.Context) func(*domain.DecisionRequest),
+) *sourceFetch {
+	sla := s.sla.SLA(source)
+	f := &sourceFetch{
+		source:   source,
+		sla:      sla,
+		deadline: time.Now().Add(sla),
+		result:   make(chan func(*domain.DecisionRequest), 1),
+	}
+	fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sla+s.sla.LateDataWindow)
+	go func() {
+		defer cancel()
+		defer func() {
+			if r := recover(); r != nil {
+				s.logger.Error("Data source fetch panicked", zap.String("source", string(source)), zap.Any("panic", r))
+				f.result <- func(*domain.DecisionRequest) {}
+			}
+		}()
+		f.result <- fetch(fetchCtx)
+	}()
+	return f
+}
+
+// wait waits for a fetch's result until its SLA passes or ctx is done, reporting false when the
+// result has not arrived
+func (f *sourceFetch) wait(ctx context.Context) (func(*domain.DecisionRequest), bool) {
+	timer := time.NewTimer(time.Until(f.deadline))
+	defer timer.Stop()
+	select {
+	case record := <-f.result:
+		return record, true
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	// A result arriving as the SLA passes is still in time
+	select {
+	case record := <-f.result:
+		return record, true
+	default:
+		return nil, false
+	}
+}
+
+// gatherData enriches a request from the fraud vendor and the applicant's credit report, fetched
+// concurrently, and records what arrives within each source's SLA. Sources that miss their SLA are
+// recorded as pending and returned, still running; the decision proceeds without them. Sources
+// already recorded, or pending on an earlier decision, are not fetched again.
+func (s *DecisionEngineService) gatherData(ctx context.Context, request *domain.DecisionRequest) []*sourceFetch {
+	logger := s.logger.With(zap.String("application_id", request.ApplicationID))
+
+	var fetches []*sourceFetch
+
+	// Screen the applicant with the fraud vendor; the score feeds the fraud knockout rule
+	if s.fraud != nil && !request.HasFraudScore() && request.FraudScreening() == nil &&
+		!request.IsSourcePending(domain.DataSourceFraudScreening) {
+		screeningRequest := request.FraudScreeningRequest()
+		fetches = append(fetches, s.startFetch(ctx, domain.DataSourceFraudScreening, func(ctx context.Context) func(*domain.DecisionRequest) {
+			screening := s.fraud.Screen(ctx, screeningRequest)
+			return func(request *domain.DecisionRequest) {
+				request.RecordFraudScreening(screening)
+			}
+		}))
+	}
+
+	// Load the tradelines the applicant's income is estimated from
+	if s.income != nil && request.IncomeEstimate() == nil && s.income.UsesCreditReport(request) &&
+		!request.IsSourcePending(domain.DataSourceCreditReport) {
+		applicationID, userID, reportID := request.ApplicationID, request.UserID, request.CreditReportID()
+		fetches = append(fetches, s.startFetch(ctx, domain.DataSourceCreditReport, func(ctx context.Context) func(*domain.DecisionRequest) {
+			tradelines := s.income.Tradelines(ctx, applicationID, userID, reportID)
+			return func(request *domain.DecisionRequest) {
+				s.income.RecordEstimate(request, tradelines)
+			}
+		}))
+	}
+
+	var late []*sourceFetch
+	for _, fetch := range fetches {
+		if record, ok := fetch.wait(ctx); ok {
+			record(request)
+			continue
+		}
+		logger.Warn("Data source missed its SLA, deciding provisionally without it",
+			zap.String("source", string(fetch.source)),
+			zap.Duration("sla", fetch.sla))
+		request.RecordPendingSource(domain.PendingSource{
+			Source:   fetch.source,
+			SLAMs:    fetch.sla.Milliseconds(),
+			MissedAt: time.Now().UTC(),
+		})
+		late = append(late, fetch)
+	}
+
+	// Estimate income from occupation and region alone while the credit report is pending, or
+	// when there is none
+	if s.income != nil && request.IncomeEstimate() == nil {
+		s.income.RecordEstimate(request, nil)
+	}
+	return late
+}
+
+// lateData is a source's result arriving after its SLA
+type lateData struct {
+	source domain.DataSource
+	record func(*domain.DecisionRequest)
+}
+
+// awaitLateData waits in the background for the sources a provisional decision was made without,
+// and re-decides the application on a copy of its request as each one arrives. A source that fails
+// or runs out its late data window still arrives, as the data it could get.
+func (s *DecisionEngineService) awaitLateData(request *domain.DecisionRequest, late []*sourceFetch) {
+	if len(late) == 0 {
+		return
+	}
+	copied := *request
+	copied.AdditionalData = maps.Clone(request.AdditionalData)
+	request = &copied
+
+	arrivals := make(chan lateData, len(late))
+	forward := func(fetch *sourceFetch) {
+		go func() {
+			arrivals <- lateData{source: fetch.source, record: <-fetch.result}
+		}()
+	}
+	for _, fetch := range late {
+		forward(fetch)
+	}
+
+	go func() {
+		for waiting := len(late); waiting > 0; waiting-- {
+			arrival := <-arrivals
+			arrival.record(request)
+			request.ResolvePendingSource(arrival.source)
+
+			for _, fetch := range s.redecide(request, arrival.source) {
+				forward(fetch)
+				waiting++
+			}
+		}
+	}()
+}
+
+// redecide decides an application again once a late source has arrived and saves the decision,
+// which replaces the provisional one as the application's latest, then tells the loan service. It
+// returns any sources the re-decision is itself left waiting for.
+func (s *DecisionEngineService) redecide(request *domain.DecisionRequest, source domain.DataSource) []*sourceFetch {
+	logger := s.logger.With(
+		zap.String("application_id", request.ApplicationID),
+		zap.String("source", string(source)),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), redecisionTimeout)
+	defer cancel()
+
+	decision, late, err := s.decide(ctx, request)
+	if err != nil {
+		logger.Error("Failed to re-decide application with late data", zap.Error(err))
+		return nil
+	}
+	if err := s.decisionRepo.SaveDecisionRequest(ctx, request); err != nil {
+		logger.Error("Failed to save decision request", zap.Error(err))
+	}
+	if err := s.decisionRepo.SaveDecision(ctx, decision, request); err != nil {
+		// A re-decision that is not saved is not the application's latest, so nothing downstream
+		// is told of it
+		logger.Error("Failed to save re-decision", zap.Error(err))
+		return late
+	}
+	if s.redecisions != nil {
+		if err := s.redecisions.NotifyRedecision(ctx, decision); err != nil {
+			logger.Error("Failed to notify the loan service of the re-decision", zap.Error(err))
+		}
+	}
+
+	logger.Info("Application re-decided with late data",
+		zap.String("decision", string(decision.Decision)),
+		zap.Bool("provisional", decision.Provisional),
+		zap.Int64("decision_id", decision.DecisionID))
+	return late
+}
@@ -0,0 +1,230 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// DecisionReplayService re-executes stored decisions for regulator and dispute investigations.
+// A decision is replayed with the request it was made on, the rule versions it recorded, the
+// scorecard it was scored with and the underwriting policy it was held to, and the result is compared with the stored decision. Decisions
+// made before rule versions were recorded are replayed with the versions in force at the time.
+type DecisionReplayService struct {
+	decisions     *DecisionEngineService
+	engine        *RulesEngine
+	rulesRepo     domain.RulesRepository
+	scorecardRepo domain.ScorecardRepository
+	decisionRepo  domain.DecisionRepository
+	logger        *zap.Logger
+}
+
+// NewDecisionReplayService creates a new decision replay service
+func NewDecisionReplayService(
+	decisions *DecisionEngineService,
+	engine *RulesEngine,
+	rulesRepo domain.RulesRepository,
+	scorecardRepo domain.ScorecardRepository,
+	decisionRepo domain.DecisionRepository,
+	logger *zap.Logger,
+) *DecisionReplayService {
+	return &DecisionReplayService{
+		decisions:     decisions,
+		engine:        engine,
+		rulesRepo:     rulesRepo,
+		scorecardRepo: scorecardRepo,
+		decisionRepo:  decisionRepo,
+		logger:        logger,
+	}
+}
+
+// ReplayDecision replays a stored decision and reports where the replay diverges from it
+func (s *DecisionReplayService) ReplayDecision(ctx context.Context, decisionID int64, requestedBy string) (*domain.DecisionReplay, error) {
+	logger := s.logger.With(
+		zap.Int64("decision_id", decisionID),
+		zap.String("requested_by", requestedBy),
+		zap.String("operation", "replay_decision"),
+	)
+
+	original, err := s.decisionRepo.GetDecisionByID(ctx, decisionID)
+	if err != nil {
+		return nil, s.repositoryError(decisionID, err)
+	}
+	inputs, err := s.decisionRepo.GetDecisionInputs(ctx, decisionID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, &domain.DecisionError{
+				Code:        domain.ERROR_INSUFFICIENT_DATA,
+				Message:     "Decision cannot be replayed",
+				Description: fmt.Sprintf("No decision request is stored for decision %d", decisionID),
+				HTTPStatus:  422,
+			}
+		}
+		return nil, s.databaseError(err)
+	}
+
+	replay := &domain.DecisionReplay{
+		DecisionID:    decisionID,
+		ApplicationID: original.ApplicationID,
+		DecidedAt:     original.DecisionDate,
+		ReplayedAt:    time.Now().UTC(),
+		InputSource:   inputs.Source,
+		Request:       &inputs.Request,
+		Original:      original,
+	}
+	if inputs.Source == domain.ReplayInputsApplication {
+		replay.Notes = append(replay.Notes, "The decision was saved before its request was kept; it was replayed with the latest request stored for the application, which may postdate the decision")
+	}
+
+	rules, err := s.rulesAt(ctx, original, replay)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := compileRules(rules)
+	if err != nil {
+		logger.Error("Failed to compile historical rule versions", zap.Error(err))
+		return nil, replayError(err)
+	}
+	replay.RuleVersions = versionedIDs(compiled)
+
+	scorer := s.scorerFor(ctx, original, replay)
+	request := &inputs.Request
+	assessment, err := NewRiskAssessmentService(s.logger, scorer).AssessRisk(request)
+	if err != nil {
+		return nil, replayError(err)
+	}
+
+	decision, err := s.engine.evaluate(compiled, request, assessment)
+	if err != nil {
+		logger.Error("Failed to evaluate historical rule versions", zap.Error(err))
+		return nil, replayError(err)
+	}
+	s.decisions.applyCollateralPolicy(decision, request, assessment, original.DecisionDate)
+	if s.decisions.policies != nil {
+		policy, err := s.decisions.policies.PolicyFor(ctx, request.Tenant(), original.PolicyVersion, original.DecisionDate)
+		if err != nil {
+			return nil, err
+		}
+		s.decisions.applyUnderwritingPolicy(decision, request, assessment, policy)
+	}
+	s.decisions.applyFraudReview(decision, request)
+	s.decisions.applyIncomeVerification(decision, request)
+	s.decisions.applyPendingSources(decision, request)
+	decision.AdverseActionReasons = domain.BuildAdverseActionReasons(decision)
+	s.decisions.enhanceDecision(decision, request, assessment)
+
+	replay.Replayed = decision
+	replay.Divergences = domain.CompareDecisions(original, decision)
+	replay.Diverged = len(replay.Divergences) > 0
+
+	logger.Info("Decision replayed",
+		zap.String("application_id", original.ApplicationID),
+		zap.String("input_source", string(replay.InputSource)),
+		zap.String("original_decision", string(original.Decision)),
+		zap.String("replayed_decision", string(decision.Decision)),
+		zap.Bool("diverged", replay.Diverged),
+		zap.Int("divergences", len(replay.Divergences)),
+	)
+	return replay, nil
+}
+
+// rulesAt returns the rule versions a decision was evaluated with, pinned in force. Decisions that
+// did not record their versions get the versions in force at the decision date.
+func (s *DecisionReplayService) rulesAt(ctx context.Context, original *domain.DecisionResponse, replay *domain.DecisionReplay) ([]domain.DecisionRule, error) {
+	var rules []domain.DecisionRule
+
+	if len(original.RuleVersions) > 0 {
+		for _, ref := range original.RuleVersions {
+			ruleID, version, err := domain.ParseVersionedID(ref)
+			if err != nil {
+				return nil, replayError(err)
+			}
+			rule, err := s.rulesRepo.GetRuleVersion(ctx, ruleID, version)
+			if err != nil {
+				if isNotFound(err) {
+					replay.Notes = append(replay.Notes, fmt.Sprintf("Rule version %s no longer exists and was not replayed", ref))
+					continue
+				}
+				return nil, s.databaseError(err)
+			}
+			rules = append(rules, proposedRule(*rule))
+		}
+		return rules, nil
+	}
+
+	replay.Notes = append(replay.Notes, "The decision did not record its rule versions; it was replayed with the rule versions in force at the decision date")
+	for _, status := range []domain.RuleStatus{domain.RuleStatusPublished, domain.RuleStatusRetired} {
+		versions, err := s.rulesRepo.ListRuleVersions(ctx, status)
+		if err != nil {
+			return nil, s.databaseError(err)
+		}
+		for _, rule := range versions {
+			if rule.InForceAt(original.DecisionDate) {
+				rules = append(rules, proposedRule(rule))
+			}
+		}
+	}
+	return rules, nil
+}
+
+// scorerFor returns a scorer for the scorecard a decision was scored with, or nil when it was not
+// scored or the scorecard no longer exists
+func (s *DecisionReplayService) scorerFor(ctx context.Context, original *domain.DecisionResponse, replay *domain.DecisionReplay) domain.ApplicationScorer {
+	if original.RiskAssessment == nil || original.RiskAssessment.Scorecard == nil || s.scorecardRepo == nil {
+		return nil
+	}
+
+	scorecardID := original.RiskAssessment.Scorecard.ScorecardID
+	scorecard, err := s.scorecardRepo.GetScorecard(ctx, scorecardID)
+	if err != nil {
+		s.logger.Warn("Failed to load historical scorecard", zap.String("scorecard_id", scorecardID), zap.Error(err))
+		replay.Notes = append(replay.Notes, fmt.Sprintf("Scorecard %s could not be loaded and was not replayed", scorecardID))
+		return nil
+	}
+	replay.ScorecardID = scorecard.ID
+	return scorecardScorer{scorecard: scorecard}
+}
+
+// scorecardScorer scores applications with one fixed scorecard
+type scorecardScorer struct {
+	scorecard *domain.Scorecard
+}
+
+// ScoreApplication implements domain.ApplicationScorer
+func (s scorecardScorer) ScoreApplication(request *domain.DecisionRequest, assessment *domain.RiskAssessment) (*domain.ScorecardResult, error) {
+	return s.scorecard.Score(domain.RuleFacts(request, assessment)), nil
+}
+
+func (s *DecisionReplayService) repositoryError(decisionID int64, err error) error {
+	if isNotFound(err) {
+		return &domain.DecisionError{
+			Code:        domain.ERROR_DECISION_NOT_FOUND,
+			Message:     "Decision not found",
+			Description: fmt.Sprintf("No decision found with id %d", decisionID),
+			HTTPStatus:  404,
+		}
+	}
+	return s.databaseError(err)
+}
+
+func (s *DecisionReplayService) databaseError(err error) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_DATABASE_ERROR,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
+
+// replayError reports a decision whose historical rules or scorecard could not be re-executed
+func replayError(err error) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_RULE_EVALUATION,
+		Message:     "Decision replay failed",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
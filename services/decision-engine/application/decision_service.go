@@ -2,10 +2,13 @@ package application
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math"
 	"time"
 
 	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"github.com/huuhoait/los-demo/services/shared/pkg/dti"
 	"go.uber.org/zap"
 )
 
@@ -13,21 +16,51 @@ import (
 type DecisionEngineService struct {
 	riskService  domain.RiskAssessmentService
 	rulesService domain.RulesEngineService
+	challengers  domain.ChallengerService
+	policies     *PolicyService
+	fraud        *FraudScreeningService
+	income       *IncomeEstimationService
+	dti          *dti.Calculator
+	sla          domain.SourceSLAPolicy
+	tenants      *domain.TenantRegistry
 	decisionRepo domain.DecisionRepository
+	redecisions  domain.RedecisionNotifier
 	logger       *zap.Logger
 }
 
-// NewDecisionEngineService creates a new decision engine service
+// NewDecisionEngineService creates a new decision engine service; challengers may be nil to run
+// without champion/challenger strategies, policies nil to run without underwriting policies, fraud
+// nil to run without fraud screening, income nil to run without income estimation, and
+// dtiCalculator nil to apply the default DTI debt treatments. sla sets how long decisions wait for
+// the fraud vendor and credit reports before deciding provisionally, and tenants holds the lending
+// programs requests may be decided under; nil knows only the default program. redecisions is told
+// of decisions made again with late data, and may be nil when nothing downstream needs telling.
 func NewDecisionEngineService(
 	riskService domain.RiskAssessmentService,
 	rulesService domain.RulesEngineService,
+	challengers domain.ChallengerService,
+	policies *PolicyService,
+	fraud *FraudScreeningService,
+	income *IncomeEstimationService,
+	dtiCalculator *dti.Calculator,
+	sla domain.SourceSLAPolicy,
+	tenants *domain.TenantRegistry,
 	decisionRepo domain.DecisionRepository,
+	redecisions domain.RedecisionNotifier,
 	logger *zap.Logger,
 ) *DecisionEngineService {
 	return &DecisionEngineService{
 		riskService:  riskService,
 		rulesService: rulesService,
+		challengers:  challengers,
+		policies:     policies,
+		fraud:        fraud,
+		income:       income,
+		dti:          dtiCalculator,
+		sla:          sla.WithDefaults(),
+		tenants:      tenants,
 		decisionRepo: decisionRepo,
+		redecisions:  redecisions,
 		logger:       logger,
 	}
 }
@@ -37,22 +70,81 @@ func (s *DecisionEngineService) MakeDecision(ctx context.Context, request *domai
 	logger := s.logger.With(
 		zap.String("application_id", request.ApplicationID),
 		zap.String("user_id", request.UserID),
+		zap.String("tenant_id", request.Tenant()),
 		zap.Float64("loan_amount", request.LoanAmount),
 	)
 
 	logger.Info("Processing decision request")
 
+	decision, late, err := s.decide(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// A caller that has given up will not act on the decision, so it is neither returned nor saved
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		logger.Warn("Decision deadline exceeded before the decision was saved")
+		return nil, &domain.DecisionError{
+			Code:        domain.ERROR_DEADLINE_EXCEEDED,
+			Message:     "Decision deadline exceeded",
+			Description: "The caller's deadline passed before the decision completed",
+			HTTPStatus:  504,
+		}
+	}
+
+	// Save the request, kept for rule simulations, and the decision made on it
+	if err := s.decisionRepo.SaveDecisionRequest(ctx, request); err != nil {
+		logger.Error("Failed to save decision request", zap.Error(err))
+		// Don't fail the request if saving fails
+	}
+	if err := s.decisionRepo.SaveDecision(ctx, decision, request); err != nil {
+		logger.Error("Failed to save decision", zap.Error(err))
+		// Don't fail the request if saving fails
+	}
+
+	// Re-decide provisional decisions when the data they were made without arrives
+	s.awaitLateData(request, late)
+
+	logger.Info("Decision completed",
+		zap.String("decision", string(decision.Decision)),
+		zap.Float64("risk_score", decision.RiskScore),
+		zap.String("risk_category", string(decision.RiskCategory)),
+	)
+
+	return decision, nil
+}
+
+// decide validates a request and makes a decision on it without saving either; batch jobs save
+// the decisions they make in bulk. It also returns the data sources still running after missing
+// their SLA, for the caller to await once the provisional decision is saved.
+func (s *DecisionEngineService) decide(ctx context.Context, request *domain.DecisionRequest) (*domain.DecisionResponse, []*sourceFetch, error) {
+	logger := s.logger.With(zap.String("application_id", request.ApplicationID))
+
 	// Validate request
 	if err := s.ValidateRequest(request); err != nil {
 		logger.Error("Request validation failed", zap.Error(err))
-		return nil, err
+		return nil, nil, err
+	}
+
+	// Record the lending program's rate floor for the usury limit knockout rule
+	tenant, _ := s.tenants.Lookup(request.TenantID)
+	request.RecordRateFloor(tenant.Pricing.RateFloor)
+
+	// Screen the applicant with the fraud vendor and estimate their income to check the stated
+	// income is reasonable, each within its source's SLA
+	late := s.gatherData(ctx, request)
+
+	// Qualify income and debts under the DTI debt treatments
+	if request.QualifyingDTI() == nil {
+		qualifying := s.dti.Calculate(request.DTIBorrower())
+		request.RecordQualifyingDTI(&qualifying)
 	}
 
 	// Perform risk assessment
 	riskAssessment, err := s.riskService.AssessRisk(request)
 	if err != nil {
 		logger.Error("Risk assessment failed", zap.Error(err))
-		return nil, &domain.DecisionError{
+		return nil, late, &domain.DecisionError{
 			Code:        domain.ERROR_RISK_ASSESSMENT,
 			Message:     "Risk assessment failed",
 			Description: err.Error(),
@@ -64,7 +156,7 @@ func (s *DecisionEngineService) MakeDecision(ctx context.Context, request *domai
 	decision, err := s.rulesService.EvaluateRules(request, riskAssessment)
 	if err != nil {
 		logger.Error("Rules evaluation failed", zap.Error(err))
-		return nil, &domain.DecisionError{
+		return nil, late, &domain.DecisionError{
 			Code:        domain.ERROR_RULE_EVALUATION,
 			Message:     "Rules evaluation failed",
 			Description: err.Error(),
@@ -72,26 +164,79 @@ func (s *DecisionEngineService) MakeDecision(ctx context.Context, request *domai
 		}
 	}
 
+	// Check secured loans against the collateral policy
+	s.applyCollateralPolicy(decision, request, riskAssessment, time.Now())
+
+	// Hold the decision to the lending program's underwriting policy in force
+	policy := s.policyInForce(request.Tenant(), time.Now())
+	s.applyUnderwritingPolicy(decision, request, riskAssessment, policy)
+
+	// Route applications the fraud screening flags to manual review
+	s.applyFraudReview(decision, request)
+
+	// Require stated incomes far above the estimate to be verified
+	s.applyIncomeVerification(decision, request)
+
+	// Mark decisions made without data sources that missed their SLA as provisional
+	s.applyPendingSources(decision, request)
+
+	// Give the principal reasons for denials and counteroffers
+	decision.AdverseActionReasons = domain.BuildAdverseActionReasons(decision)
+
 	// Enhance decision with additional logic
 	s.enhanceDecision(decision, request, riskAssessment)
 
-	// Save decision
-	if err := s.decisionRepo.SaveDecision(ctx, decision); err != nil {
-		logger.Error("Failed to save decision", zap.Error(err))
-		// Don't fail the request if saving fails
+	// Shadow the decision with the active challenger, which is recorded but never enforced
+	s.shadowDecision(ctx, request, riskAssessment, policy, decision)
+
+	return decision, late, nil
+}
+
+// shadowDecision records the active challenger strategy's decision alongside the champion's.
+// Failures are logged and never affect the enforced decision.
+func (s *DecisionEngineService) shadowDecision(
+	ctx context.Context,
+	request *domain.DecisionRequest,
+	assessment *domain.RiskAssessment,
+	policy *domain.UnderwritingPolicy,
+	champion *domain.DecisionResponse,
+) {
+	if s.challengers == nil {
+		return
 	}
+	logger := s.logger.With(zap.String("application_id", request.ApplicationID))
 
-	logger.Info("Decision completed",
-		zap.String("decision", string(decision.Decision)),
-		zap.Float64("risk_score", decision.RiskScore),
-		zap.String("risk_category", string(decision.RiskCategory)),
-	)
+	strategy, challenger, err := s.challengers.EvaluateChallenger(ctx, request, assessment)
+	if err != nil {
+		logger.Warn("Challenger evaluation failed", zap.Error(err))
+		return
+	}
+	if strategy == nil {
+		return
+	}
 
-	return decision, nil
+	// The challenger replaces the rules only; the collateral and underwriting policies apply to both
+	s.applyCollateralPolicy(challenger, request, assessment, time.Now())
+	s.applyUnderwritingPolicy(challenger, request, assessment, policy)
+	s.applyFraudReview(challenger, request)
+	s.applyIncomeVerification(challenger, request)
+
+	if err := s.challengers.RecordChallengerDecision(ctx, strategy, champion, challenger); err != nil {
+		logger.Warn("Failed to record challenger decision", zap.String("strategy_id", strategy.ID), zap.Error(err))
+		return
+	}
+	logger.Debug("Challenger decision recorded",
+		zap.String("strategy_id", strategy.ID),
+		zap.String("champion_decision", string(champion.Decision)),
+		zap.String("challenger_decision", string(challenger.Decision)))
 }
 
 // ValidateRequest validates the decision request
 func (s *DecisionEngineService) ValidateRequest(request *domain.DecisionRequest) error {
+	if _, err := s.tenants.Resolve(request.TenantID); err != nil {
+		return err
+	}
+
 	if request.ApplicationID == "" {
 		return &domain.DecisionError{
 			Code:       domain.ERROR_INVALID_REQUEST,
@@ -132,15 +277,177 @@ func (s *DecisionEngineService) ValidateRequest(request *domain.DecisionRequest)
 		}
 	}
 
+	if request.IsSecured() && request.Collateral.Value <= 0 {
+		return &domain.DecisionError{
+			Code:       domain.ERROR_INVALID_REQUEST,
+			Message:    "Collateral value must be positive",
+			HTTPStatus: 400,
+		}
+	}
+
 	return nil
 }
 
+// applyCollateralPolicy denies secured loans above the maximum loan-to-value ratio for their
+// collateral and sends approvals secured by stated or stale valuations to review. Valuations are
+// judged stale as of at.
+func (s *DecisionEngineService) applyCollateralPolicy(
+	decision *domain.DecisionResponse,
+	request *domain.DecisionRequest,
+	assessment *domain.RiskAssessment,
+	at time.Time,
+) {
+	if !request.IsSecured() {
+		return
+	}
+	decision.AppliedRules = append(decision.AppliedRules, "max_ltv_ratio")
+
+	maxLTV := request.MaxLTVRatio()
+	if assessment.LTVRatio > maxLTV {
+		reason := fmt.Sprintf("Loan-to-value ratio of %.2f exceeds the %.2f maximum for %s collateral", assessment.LTVRatio, maxLTV, request.Collateral.Type)
+		decision.RecordKnockout(domain.RuleHit{
+			RuleID:        "max_ltv_ratio",
+			Name:          "Maximum loan-to-value ratio",
+			Category:      domain.RuleCategoryCollateral,
+			Action:        domain.ActionDecision,
+			Decision:      domain.DecisionDeny,
+			Knockout:      true,
+			Reason:        reason,
+			AdverseAction: domain.AdverseActionCollateral,
+		})
+		decision.Recommendations = append(decision.Recommendations,
+			fmt.Sprintf("Reduce the loan amount to $%.0f or less, or add a down payment", math.Floor(request.Collateral.Value*maxLTV)))
+		return
+	}
+
+	if decision.Decision == domain.DecisionDeny || !request.Collateral.NeedsValuation(at) {
+		return
+	}
+	decision.ReviewRequired = true
+	if decision.Decision == domain.DecisionApprove {
+		decision.Decision = domain.DecisionConditional
+	}
+}
+
+// policyInForce returns the lending program's underwriting policy in force at t, or nil when there
+// is none
+func (s *DecisionEngineService) policyInForce(tenant string, t time.Time) *domain.UnderwritingPolicy {
+	if s.policies == nil {
+		return nil
+	}
+	return s.policies.PolicyInForce(tenant, t)
+}
+
+// applyUnderwritingPolicy holds the decision to an underwriting policy: applications outside its
+// credit, debt, income or loan limits are denied, and approvals outside its auto-approval
+// thresholds are sent to manual review. The decision records the policy version applied.
+func (s *DecisionEngineService) applyUnderwritingPolicy(
+	decision *domain.DecisionResponse,
+	request *domain.DecisionRequest,
+	assessment *domain.RiskAssessment,
+	policy *domain.UnderwritingPolicy,
+) {
+	if policy == nil {
+		return
+	}
+	decision.PolicyVersion = policy.Version
+	decision.AppliedRules = append(decision.AppliedRules, "underwriting_policy")
+
+	if knockouts := policy.Knockouts(request, assessment.DTIRatio); len(knockouts) > 0 {
+		for _, knockout := range knockouts {
+			decision.RecordKnockout(knockout)
+		}
+		return
+	}
+
+	if decision.Decision == domain.DecisionDeny {
+		return
+	}
+	amount := request.LoanAmount
+	if decision.MaxAmount > 0 && decision.MaxAmount < amount {
+		amount = decision.MaxAmount
+	}
+	reasons := policy.ReviewReasons(request, assessment.DTIRatio, amount)
+	if len(reasons) == 0 {
+		return
+	}
+	decision.ReviewRequired = true
+	if decision.Decision == domain.DecisionApprove {
+		decision.Decision = domain.DecisionConditional
+	}
+	for _, reason := range reasons {
+		decision.Conditions = append(decision.Conditions, "Manual underwriting review: "+reason)
+	}
+}
+
+// applyFraudReview routes a decision to manual review when the fraud screening recorded with its
+// request calls for review. Scores high enough to deny are left to the fraud knockout rule.
+func (s *DecisionEngineService) applyFraudReview(decision *domain.DecisionResponse, request *domain.DecisionRequest) {
+	screening := request.FraudScreening()
+	if screening == nil {
+		return
+	}
+	if s.fraud != nil {
+		s.fraud.Reassess(screening)
+	}
+	decision.Fraud = screening
+
+	if decision.Decision == domain.DecisionDeny || len(screening.ReviewReasons) == 0 {
+		return
+	}
+	decision.Decision = domain.DecisionManualReview
+	decision.ReviewRequired = true
+	decision.AppliedRules = append(decision.AppliedRules, "fraud_screening")
+	for _, reason := range screening.ReviewReasons {
+		decision.Conditions = append(decision.Conditions, "Fraud review: "+reason)
+	}
+}
+
+// applyPendingSources marks a decision made without data sources that missed their SLA as
+// provisional, on condition of the pending data; it is re-decided when the data arrives
+func (s *DecisionEngineService) applyPendingSources(decision *domain.DecisionResponse, request *domain.DecisionRequest) {
+	pending := request.PendingSources()
+	if len(pending) == 0 {
+		return
+	}
+	decision.Provisional = true
+	decision.PendingSources = pending
+	for _, source := range pending {
+		decision.Conditions = append(decision.Conditions,
+			fmt.Sprintf("Pending %s: decision is provisional until it arrives", source.Source.Description()))
+	}
+}
+
+// applyIncomeVerification requires income verification when the stated income recorded with the
+// request is too far above the estimated income; approvals become conditional on it
+func (s *DecisionEngineService) applyIncomeVerification(decision *domain.DecisionResponse, request *domain.DecisionRequest) {
+	estimate := request.IncomeEstimate()
+	if estimate == nil {
+		return
+	}
+	if s.income != nil {
+		s.income.Reassess(estimate)
+	}
+	decision.IncomeEstimate = estimate
+
+	if decision.Decision == domain.DecisionDeny || !estimate.VarianceFlag {
+		return
+	}
+	if decision.Decision == domain.DecisionApprove {
+		decision.Decision = domain.DecisionConditional
+	}
+	decision.AppliedRules = append(decision.AppliedRules, "income_estimation")
+	decision.Conditions = append(decision.Conditions, "Income verification required: "+estimate.Reason)
+}
+
 // enhanceDecision adds additional business logic to the decision
 func (s *DecisionEngineService) enhanceDecision(
 	decision *domain.DecisionResponse,
 	request *domain.DecisionRequest,
 	assessment *domain.RiskAssessment,
 ) {
+	decision.QualifyingDTI = request.QualifyingDTI()
+
 	// Set expiration date for approvals
 	if decision.Decision == domain.DecisionApprove {
 		expiresAt := time.Now().Add(30 * 24 * time.Hour) // 30 days
@@ -148,17 +455,17 @@ func (s *DecisionEngineService) enhanceDecision(
 	}
 
 	// Add conditions based on risk factors
-	s.addConditions(decision, assessment)
+	s.addConditions(decision, request, assessment)
 
 	// Set required documents
 	s.setRequiredDocuments(decision, request, assessment)
 
-	// Adjust interest rate based on additional factors
-	s.adjustInterestRate(decision, request, assessment)
+	// Price the decision from the same risk inputs
+	s.priceDecision(decision, request, assessment)
 }
 
 // addConditions adds loan conditions based on risk assessment
-func (s *DecisionEngineService) addConditions(decision *domain.DecisionResponse, assessment *domain.RiskAssessment) {
+func (s *DecisionEngineService) addConditions(decision *domain.DecisionResponse, request *domain.DecisionRequest, assessment *domain.RiskAssessment) {
 	conditions := []string{}
 
 	// DTI ratio conditions
@@ -176,7 +483,15 @@ func (s *DecisionEngineService) addConditions(decision *domain.DecisionResponse,
 		conditions = append(conditions, "Employment verification within 30 days")
 	}
 
-	decision.Conditions = conditions
+	// Collateral conditions
+	if request.IsSecured() {
+		if request.Collateral.NeedsValuation(time.Now()) {
+			conditions = append(conditions, "Independent collateral valuation required before funding")
+		}
+		conditions = append(conditions, "Lien on the collateral must be recorded before funding")
+	}
+
+	decision.Conditions = append(decision.Conditions, conditions...)
 }
 
 // setRequiredDocuments sets required documents based on risk factors
@@ -207,56 +522,148 @@ func (s *DecisionEngineService) setRequiredDocuments(
 		docs = append(docs, "Debt statements", "Monthly budget plan")
 	}
 
+	// Stated income well above the estimate must be evidenced
+	if decision.IncomeEstimate != nil && decision.IncomeEstimate.VarianceFlag {
+		docs = append(docs, "Recent pay stubs (2)", "W-2 or tax return (most recent year)")
+	}
+
+	// Collateral documents for secured loans
+	if request.IsSecured() {
+		switch request.Collateral.Type {
+		case domain.CollateralTypeVehicle:
+			docs = append(docs, "Vehicle title", "Proof of insurance")
+		default:
+			docs = append(docs, "Proof of ownership", "Proof of insurance")
+		}
+	}
+
 	decision.RequiredDocs = docs
 }
 
-// adjustInterestRate adjusts interest rate based on comprehensive risk analysis
-func (s *DecisionEngineService) adjustInterestRate(
+// priceDecision prices the decision from the request and risk assessment it was made on, under
+// the lending program's pricing matrix: the base rate for the loan purpose plus risk-based margin
+// adjustments, within the rate floor and ceiling, and the origination fee for the risk category
+// less any waivers. Denied decisions keep the rate but carry no pricing block.
+func (s *DecisionEngineService) priceDecision(
 	decision *domain.DecisionResponse,
 	request *domain.DecisionRequest,
 	assessment *domain.RiskAssessment,
 ) {
-	baseRate := s.getBaseInterestRate(request.LoanPurpose)
-
-	// Risk adjustment
-	riskAdjustment := assessment.OverallScore * 0.05 // Up to 5% adjustment
-
-	// Credit score adjustment
-	creditAdjustment := s.getCreditScoreAdjustment(request.CreditScore)
-
-	// DTI adjustment
-	dtiAdjustment := s.getDTIAdjustment(assessment.DTIRatio)
-
-	// Employment type adjustment
-	employmentAdjustment := s.getEmploymentAdjustment(request.EmploymentType)
+	matrix := domain.DefaultPricingMatrix()
+	if tenant, ok := s.tenants.Lookup(request.TenantID); ok {
+		matrix = tenant.Pricing
+	}
+	pricing := &domain.DecisionPricing{
+		ModelVersion: domain.PricingModelVersion,
+		BaseRate:     matrix.BaseRate(request.LoanPurpose),
+		RateFloor:    matrix.RateFloor,
+		RateCeiling:  matrix.RateCeiling,
+	}
+	// The state usury limit caps the rate; limits below the floor are knocked out before pricing
+	if limit := request.UsuryRateLimit(); limit > 0 && limit < pricing.RateCeiling {
+		pricing.RateCeiling = limit
+	}
 
-	// Calculate final rate
-	finalRate := baseRate + riskAdjustment + creditAdjustment + dtiAdjustment + employmentAdjustment
+	adjustments := []domain.MarginAdjustment{
+		{
+			Factor:      domain.PricingFactorRiskScore,
+			Adjustment:  assessment.OverallScore * 0.05, // Up to 5% adjustment
+			Description: fmt.Sprintf("Overall risk score %.2f", assessment.OverallScore),
+		},
+		{
+			Factor:      domain.PricingFactorCreditScore,
+			Adjustment:  s.getCreditScoreAdjustment(request.CreditScore),
+			Description: fmt.Sprintf("Credit score %d", request.CreditScore),
+		},
+		{
+			Factor:      domain.PricingFactorDTI,
+			Adjustment:  s.getDTIAdjustment(assessment.DTIRatio),
+			Description: fmt.Sprintf("Debt-to-income ratio %.1f%%", assessment.DTIRatio*100),
+		},
+		{
+			Factor:      domain.PricingFactorEmployment,
+			Adjustment:  s.getEmploymentAdjustment(request.EmploymentType),
+			Description: fmt.Sprintf("Employment type %s", request.EmploymentType),
+		},
+	}
+	if request.IsSecured() {
+		adjustments = append(adjustments, domain.MarginAdjustment{
+			Factor:      domain.PricingFactorCollateral,
+			Adjustment:  s.getLTVAdjustment(request, assessment.LTVRatio),
+			Description: fmt.Sprintf("Loan-to-value ratio %.1f%% on %s collateral", assessment.LTVRatio*100, request.Collateral.Type),
+		})
+	}
+	// The rate is priced from the exact margin; the adjustments are reported rounded
+	margin := 0.0
+	for i := range adjustments {
+		margin += adjustments[i].Adjustment
+		adjustments[i].Adjustment = roundRate(adjustments[i].Adjustment)
+	}
+	pricing.MarginAdjustments = adjustments
+	pricing.Margin = roundRate(margin)
 
 	// Apply floor and ceiling
-	finalRate = math.Max(finalRate, 5.0)  // Minimum 5%
-	finalRate = math.Min(finalRate, 25.0) // Maximum 25%
+	finalRate := math.Max(pricing.BaseRate+margin, pricing.RateFloor)
+	finalRate = math.Min(finalRate, pricing.RateCeiling)
+	pricing.Rate = roundRate(finalRate)
+
+	pricing.OriginationFeeRate = matrix.OriginationFeeRates[decision.RiskCategory]
+	pricing.FeeWaivers = s.feeWaivers(request, assessment, pricing.OriginationFeeRate)
+	pricing.NetOriginationFeeRate = pricing.OriginationFeeRate
+	for _, waiver := range pricing.FeeWaivers {
+		pricing.NetOriginationFeeRate -= waiver.WaivedRate
+	}
+	pricing.NetOriginationFeeRate = roundRate(math.Max(pricing.NetOriginationFeeRate, 0))
+
+	pricing.LoanAmount = request.LoanAmount
+	if decision.MaxAmount > 0 && decision.MaxAmount < pricing.LoanAmount {
+		pricing.LoanAmount = decision.MaxAmount
+	}
+	pricing.TermMonths = request.RequestedTerm
+	if pricing.TermMonths <= 0 {
+		pricing.TermMonths = request.LoanTermMonths
+	}
+	if pricing.TermMonths > 0 {
+		monthlyPayment := domain.AmortizedPayment(pricing.LoanAmount, pricing.Rate, pricing.TermMonths)
+		pricing.MonthlyPayment = roundRate(monthlyPayment)
+		pricing.APR = roundRate(domain.AnnualPercentageRate(pricing.LoanAmount, pricing.NetOriginationFeeRate, monthlyPayment, pricing.TermMonths))
+	}
 
-	decision.InterestRate = math.Round(finalRate*100) / 100 // Round to 2 decimal places
+	decision.InterestRate = pricing.Rate
+	if decision.Decision != domain.DecisionDeny {
+		decision.Pricing = pricing
+	}
 }
 
-// getBaseInterestRate returns base interest rate by loan purpose
-func (s *DecisionEngineService) getBaseInterestRate(purpose domain.LoanPurpose) float64 {
-	rates := map[domain.LoanPurpose]float64{
-		domain.PurposePersonal:          8.5,
-		domain.PurposeDebtConsolidation: 7.5,
-		domain.PurposeHomeImprovement:   7.0,
-		domain.PurposeBusiness:          9.0,
-		domain.PurposeEducation:         6.5,
-		domain.PurposeMedical:           8.0,
-		domain.PurposeVacation:          10.0,
-		domain.PurposeOther:             9.5,
+// feeWaivers returns the waivers of the origination fee the applicant qualifies for: borrowers
+// with excellent credit and low debt pay none, and well-secured loans pay half
+func (s *DecisionEngineService) feeWaivers(request *domain.DecisionRequest, assessment *domain.RiskAssessment, originationFeeRate float64) []domain.FeeWaiver {
+	if originationFeeRate <= 0 {
+		return nil
 	}
 
-	if rate, exists := rates[purpose]; exists {
-		return rate
+	switch {
+	case request.CreditScore >= 760 && assessment.DTIRatio <= 0.20:
+		return []domain.FeeWaiver{{
+			Fee:        domain.FeeOrigination,
+			Code:       "EXCELLENT_CREDIT",
+			WaivedRate: originationFeeRate,
+			Reason:     "Credit score of 760 or more with a debt-to-income ratio of 20% or less",
+		}}
+	case request.IsSecured() && assessment.LTVRatio > 0 && assessment.LTVRatio <= 0.80:
+		return []domain.FeeWaiver{{
+			Fee:        domain.FeeOrigination,
+			Code:       "WELL_SECURED",
+			WaivedRate: roundRate(originationFeeRate / 2),
+			Reason:     "Secured with a loan-to-value ratio of 80% or less",
+		}}
 	}
-	return 9.0 // Default rate
+	return nil
+}
+
+// roundRate rounds a rate or amount to 2 decimal places
+func roundRate(value float64) float64 {
+	return math.Round(value*100) / 100
 }
 
 // getCreditScoreAdjustment returns interest rate adjustment based on credit score
@@ -289,6 +696,22 @@ func (s *DecisionEngineService) getDTIAdjustment(dtiRatio float64) float64 {
 	}
 }
 
+// getLTVAdjustment returns interest rate adjustment based on the loan-to-value ratio of secured loans
+func (s *DecisionEngineService) getLTVAdjustment(request *domain.DecisionRequest, ltvRatio float64) float64 {
+	if !request.IsSecured() {
+		return 0.0 // No adjustment for unsecured loans
+	}
+
+	switch {
+	case ltvRatio <= 0.80:
+		return -1.0 // Well-secured discount
+	case ltvRatio <= 1.00:
+		return -0.5 // Secured discount
+	default:
+		return 0.0 // No adjustment
+	}
+}
+
 // getEmploymentAdjustment returns interest rate adjustment based on employment type
 func (s *DecisionEngineService) getEmploymentAdjustment(employmentType domain.EmploymentType) float64 {
 	adjustments := map[domain.EmploymentType]float64{
@@ -321,10 +744,41 @@ func (s *DecisionEngineService) GetDecision(ctx context.Context, applicationID s
 		}
 	}
 
+	// The risk category is not stored; it is derived again from the saved risk score
+	if decision.RiskAssessment != nil {
+		decision.RiskCategory = s.riskService.CategorizeRisk(decision.RiskScore)
+	}
+
 	logger.Debug("Decision retrieved successfully")
 	return decision, nil
 }
 
+// GetDecisionExplanation explains a saved decision by its id
+func (s *DecisionEngineService) GetDecisionExplanation(ctx context.Context, decisionID int64) (*domain.DecisionExplanation, error) {
+	logger := s.logger.With(zap.Int64("decision_id", decisionID))
+
+	decision, err := s.decisionRepo.GetDecisionByID(ctx, decisionID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, &domain.DecisionError{
+				Code:        domain.ERROR_DECISION_NOT_FOUND,
+				Message:     "Decision not found",
+				Description: fmt.Sprintf("No decision found with id %d", decisionID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to retrieve decision", zap.Error(err))
+		return nil, &domain.DecisionError{
+			Code:        domain.ERROR_DATABASE_ERROR,
+			Message:     "Failed to retrieve decision",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	return domain.NewDecisionExplanation(decision), nil
+}
+
 // GetDecisionHistory retrieves decision history for a user
 func (s *DecisionEngineService) GetDecisionHistory(ctx context.Context, userID string) ([]domain.DecisionResponse, error) {
 	logger := s.logger.With(zap.String("user_id", userID))
@@ -375,28 +829,26 @@ func (s *DecisionEngineService) GetStatistics(ctx context.Context, dateFrom, dat
 	return stats, nil
 }
 
-// GetDecisionRules gets all active decision rules
-func (s *DecisionEngineService) GetDecisionRules(ctx context.Context) ([]domain.DecisionRule, error) {
-	logger := s.logger.With(zap.String("operation", "get_decision_rules"))
+// GetDecisionRules gets the lending program's active decision rules in evaluation order
+func (s *DecisionEngineService) GetDecisionRules(ctx context.Context, tenant string) ([]domain.DecisionRule, error) {
+	logger := s.logger.With(zap.String("operation", "get_decision_rules"), zap.String("tenant_id", domain.TenantOrDefault(tenant)))
 
-	// For now, return mock rules since we don't have a rules repository
-	rules := []domain.DecisionRule{
-		{
-			ID:          "rule_001",
-			Name:        "Minimum Credit Score",
-			Description: "Reject applications with credit score below 600",
-			Category:    domain.RuleCategoryCredit,
-			Priority:    1,
-			Active:      true,
-		},
-		{
-			ID:          "rule_002",
-			Name:        "Maximum DTI Ratio",
-			Description: "Reject applications with DTI ratio above 45%",
-			Category:    domain.RuleCategoryDebt,
-			Priority:    2,
-			Active:      true,
-		},
+	active, err := s.rulesService.GetActiveRules()
+	if err != nil {
+		logger.Error("Failed to get decision rules", zap.Error(err))
+		return nil, &domain.DecisionError{
+			Code:        domain.ERROR_RULE_EVALUATION,
+			Message:     "Failed to get decision rules",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	var rules []domain.DecisionRule
+	for _, rule := range active {
+		if rule.Tenant() == domain.TenantOrDefault(tenant) {
+			rules = append(rules, rule)
+		}
 	}
 
 	logger.Debug("Decision rules retrieved", zap.Int("count", len(rules)))
@@ -0,0 +1,56 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// FraudScreeningService screens applications with the fraud vendor and routes risky ones to
+// manual review under the fraud policy
+type FraudScreeningService struct {
+	vendor domain.FraudDetectionService
+	policy domain.FraudPolicy
+	logger *zap.Logger
+}
+
+// NewFraudScreeningService creates a new fraud screening service
+func NewFraudScreeningService(vendor domain.FraudDetectionService, policy domain.FraudPolicy, logger *zap.Logger) *FraudScreeningService {
+	return &FraudScreeningService{
+		vendor: vendor,
+		policy: policy.WithDefaults(),
+		logger: logger,
+	}
+}
+
+// Screen screens an application and assesses the result under the fraud policy. A vendor failure
+// is not an error: the result is marked unavailable, and the policy decides whether that needs
+// review.
+func (s *FraudScreeningService) Screen(ctx context.Context, request *domain.FraudScreeningRequest) *domain.FraudScreeningResult {
+	logger := s.logger.With(zap.String("application_id", request.ApplicationID))
+
+	result, err := s.vendor.Screen(ctx, request)
+	if err != nil {
+		logger.Warn("Fraud screening failed", zap.Error(err))
+		result = &domain.FraudScreeningResult{
+			Unavailable: true,
+			ScreenedAt:  time.Now(),
+		}
+	}
+	s.policy.Assess(result)
+
+	logger.Info("Fraud screening completed",
+		zap.String("vendor", result.Vendor),
+		zap.Float64("fraud_score", result.Score),
+		zap.Strings("reason_codes", result.ReasonCodes),
+		zap.String("recommendation", string(result.Recommendation)),
+		zap.Bool("unavailable", result.Unavailable))
+	return result
+}
+
+// Reassess assesses a recorded screening under the current policy
+func (s *FraudScreeningService) Reassess(screening *domain.FraudScreeningResult) {
+	s.policy.Assess(screening)
+}
@@ -0,0 +1,74 @@
+package application
+
+import (
+	"context"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// IncomeEstimationService estimates the income expected for an applicant and flags stated incomes
+// too far above it for verification
+type IncomeEstimationService struct {
+	reports domain.CreditReportRepository
+	policy  domain.IncomeEstimationPolicy
+	logger  *zap.Logger
+}
+
+// NewIncomeEstimationService creates a new income estimation service; reports may be nil to
+// estimate from occupation and region only
+func NewIncomeEstimationService(reports domain.CreditReportRepository, policy domain.IncomeEstimationPolicy, logger *zap.Logger) *IncomeEstimationService {
+	return &IncomeEstimationService{
+		reports: reports,
+		policy:  policy.WithDefaults(),
+		logger:  logger,
+	}
+}
+
+// UsesCreditReport reports whether the request's income estimate draws on a tri-merge report
+func (s *IncomeEstimationService) UsesCreditReport(request *domain.DecisionRequest) bool {
+	return s.reports != nil && request.CreditReportID() > 0
+}
+
+// Tradelines loads the tradelines of a user's tri-merge report for income estimation; nil when the
+// report cannot be loaded or belongs to another user
+func (s *IncomeEstimationService) Tradelines(ctx context.Context, applicationID, userID string, reportID int64) []domain.MergedTradeline {
+	if s.reports == nil || reportID <= 0 {
+		return nil
+	}
+	logger := s.logger.With(zap.String("application_id", applicationID), zap.Int64("credit_report_id", reportID))
+
+	report, err := s.reports.GetTriMergeReport(ctx, reportID)
+	switch {
+	case err != nil:
+		logger.Warn("Failed to load credit report for income estimation", zap.Error(err))
+		return nil
+	case report.UserID != userID:
+		logger.Warn("Credit report belongs to another user, estimating income without tradelines")
+		return nil
+	}
+	return report.Tradelines
+}
+
+// RecordEstimate estimates the applicant's income from occupation and region, blended with the
+// tradelines when there are any, and records the estimate with the request in place of any
+// recorded before
+func (s *IncomeEstimationService) RecordEstimate(request *domain.DecisionRequest, tradelines []domain.MergedTradeline) {
+	logger := s.logger.With(zap.String("application_id", request.ApplicationID))
+
+	occupation, _ := request.AdditionalData[domain.AdditionalDataOccupation].(string)
+	region, _ := request.AdditionalData[domain.AdditionalDataRegion].(string)
+	estimate := s.policy.EstimateIncome(occupation, region, request.AnnualIncome, tradelines)
+	request.RecordIncomeEstimate(estimate)
+
+	logger.Info("Income estimated",
+		zap.Float64("estimated_income", estimate.EstimatedIncome),
+		zap.Float64("stated_income", estimate.StatedIncome),
+		zap.Float64("variance", estimate.Variance),
+		zap.Bool("variance_flag", estimate.VarianceFlag))
+}
+
+// Reassess assesses a recorded estimate under the current policy
+func (s *IncomeEstimationService) Reassess(estimate *domain.IncomeEstimate) {
+	s.policy.Assess(estimate)
+}
@@ -0,0 +1,442 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// PolicyService manages versioned underwriting policies under maker/checker approval. A maker
+// writes a draft and submits it; a checker other than the maker approves it, putting it in force
+// from its effective date, or rejects it back to draft. Each lending program has its own policy
+// versions, numbered independently. Approved versions are kept loaded so the
+// policy in force is applied to decisions without a restart, and reloaded periodically so
+// approvals made through another instance take effect here.
+type PolicyService struct {
+	repo   domain.PolicyRepository
+	logger *zap.Logger
+
+	mu       sync.RWMutex
+	approved []domain.UnderwritingPolicy
+}
+
+// NewPolicyService creates a new policy service
+func NewPolicyService(repo domain.PolicyRepository, logger *zap.Logger) *PolicyService {
+	return &PolicyService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// LoadPolicies loads the approved policy versions of every lending program from the repository
+func (s *PolicyService) LoadPolicies(ctx context.Context) error {
+	policies, err := s.repo.ListPolicies(ctx, "", domain.PolicyStatusApproved)
+	if err != nil {
+		s.logger.Error("Failed to load underwriting policies", zap.Error(err))
+		return fmt.Errorf("failed to load underwriting policies: %w", err)
+	}
+
+	s.mu.Lock()
+	s.approved = policies
+	s.mu.Unlock()
+
+	if inForce := domain.PolicyInForce(policies, domain.DefaultTenantID, time.Now()); inForce != nil {
+		s.logger.Debug("Underwriting policies loaded", zap.Int("approved", len(policies)), zap.String("in_force", inForce.Version))
+	} else {
+		s.logger.Warn("No underwriting policy in force for the default lending program; its decisions are made on the decision rules alone",
+			zap.Int("approved", len(policies)))
+	}
+	return nil
+}
+
+// Start reloads the approved policies every interval until ctx is cancelled
+func (s *PolicyService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.LoadPolicies(ctx); err != nil {
+				s.logger.Warn("Failed to reload underwriting policies; keeping the current policies", zap.Error(err))
+			}
+		}
+	}
+}
+
+// PolicyInForce returns the lending program's loaded policy version in force at t, or nil when
+// there is none. Approved versions with a later effective date take over without a reload.
+func (s *PolicyService) PolicyInForce(tenant string, t time.Time) *domain.UnderwritingPolicy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return domain.PolicyInForce(s.approved, tenant, t)
+}
+
+// PolicyFor returns the policy a decision was made under: the version it recorded, or the version
+// in force at the decision date for decisions that recorded none
+func (s *PolicyService) PolicyFor(ctx context.Context, tenant, version string, decidedAt time.Time) (*domain.UnderwritingPolicy, error) {
+	if version == "" {
+		return s.PolicyInForce(tenant, decidedAt), nil
+	}
+	return s.GetPolicy(ctx, tenant, version)
+}
+
+// ListPolicies returns the lending program's policy versions with a status, or every version when
+// status is empty
+func (s *PolicyService) ListPolicies(ctx context.Context, tenant string, status domain.PolicyStatus) ([]domain.UnderwritingPolicy, error) {
+	if status != "" && !status.IsValid() {
+		return nil, invalidPolicyError(fmt.Errorf("unknown policy status %q", status))
+	}
+
+	policies, err := s.repo.ListPolicies(ctx, domain.TenantOrDefault(tenant), status)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	return policies, nil
+}
+
+// GetPolicy returns a single policy version of a lending program
+func (s *PolicyService) GetPolicy(ctx context.Context, tenant, version string) (*domain.UnderwritingPolicy, error) {
+	policy, err := s.repo.GetPolicy(ctx, domain.TenantOrDefault(tenant), version)
+	if err != nil {
+		return nil, s.repositoryError(version, err)
+	}
+	return policy, nil
+}
+
+// GetActivePolicy returns the lending program's policy version in force now
+func (s *PolicyService) GetActivePolicy(tenant string) (*domain.UnderwritingPolicy, error) {
+	policy := s.PolicyInForce(tenant, time.Now())
+	if policy == nil {
+		return nil, &domain.DecisionError{
+			Code:        domain.ERROR_POLICY_NOT_FOUND,
+			Message:     "Policy not found",
+			Description: "No underwriting policy is in force",
+			HTTPStatus:  404,
+		}
+	}
+	return policy, nil
+}
+
+// GetPolicyHistory returns the changes made to a policy version, oldest first
+func (s *PolicyService) GetPolicyHistory(ctx context.Context, tenant, version string) ([]domain.PolicyEvent, error) {
+	events, err := s.repo.GetPolicyEvents(ctx, domain.TenantOrDefault(tenant), version)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	if len(events) == 0 {
+		return nil, policyNotFoundError(version)
+	}
+	return events, nil
+}
+
+// CreatePolicy creates a draft policy version for a lending program. The version defaults to 1.0.0
+// for the program's first version and the next minor version otherwise, and must be greater than
+// every existing version of the program.
+func (s *PolicyService) CreatePolicy(ctx context.Context, tenant string, req *domain.PolicyRequest, performedBy string) (*domain.UnderwritingPolicy, error) {
+	tenant = domain.TenantOrDefault(tenant)
+	existing, err := s.repo.ListPolicies(ctx, tenant, "")
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	version := req.Version
+	if len(existing) == 0 {
+		if version == "" {
+			version = domain.InitialRuleVersion
+		}
+	} else {
+		latest := existing[len(existing)-1].Version
+		if version == "" {
+			version = domain.NextMinorVersion(latest)
+		}
+		if domain.CompareVersions(version, latest) <= 0 {
+			return nil, policyConflictError(fmt.Sprintf("Version %s must be greater than the latest version %s", version, latest))
+		}
+	}
+
+	now := time.Now().UTC()
+	policy := &domain.UnderwritingPolicy{
+		Version:   version,
+		TenantID:  tenant,
+		Status:    domain.PolicyStatusDraft,
+		CreatedBy: performedBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	req.Apply(policy)
+	if err := validatePolicy(policy); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreatePolicy(ctx, policy); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, policyConflictError(fmt.Sprintf("Policy version %s already exists", version))
+		}
+		return nil, s.databaseError(err)
+	}
+
+	s.logger.Info("Policy draft created",
+		zap.String("tenant_id", tenant),
+		zap.String("policy_version", version),
+		zap.String("performed_by", performedBy))
+	return policy, nil
+}
+
+// UpdateDraft replaces the definition of a draft version
+func (s *PolicyService) UpdateDraft(ctx context.Context, tenant, version string, req *domain.PolicyRequest, performedBy string) (*domain.UnderwritingPolicy, error) {
+	policy, err := s.getWithStatus(ctx, tenant, version, domain.PolicyStatusDraft)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Apply(policy)
+	if err := validatePolicy(policy); err != nil {
+		return nil, err
+	}
+	policy.UpdatedAt = time.Now().UTC()
+
+	if err := s.update(ctx, policy, domain.PolicyStatusDraft, domain.PolicyEventUpdated, performedBy, ""); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Policy draft updated", zap.String("policy_version", version), zap.String("performed_by", performedBy))
+	return policy, nil
+}
+
+// DiscardDraft deletes a draft version
+func (s *PolicyService) DiscardDraft(ctx context.Context, tenant, version, performedBy string) error {
+	policy, err := s.getWithStatus(ctx, tenant, version, domain.PolicyStatusDraft)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteDraft(ctx, policy.TenantID, version, performedBy); err != nil {
+		return s.repositoryError(version, err)
+	}
+
+	s.logger.Info("Policy draft discarded", zap.String("policy_version", version), zap.String("performed_by", performedBy))
+	return nil
+}
+
+// SubmitForApproval freezes a draft and sends it to a checker
+func (s *PolicyService) SubmitForApproval(ctx context.Context, tenant, version, performedBy string) (*domain.UnderwritingPolicy, error) {
+	policy, err := s.getWithStatus(ctx, tenant, version, domain.PolicyStatusDraft)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	policy.Status = domain.PolicyStatusPendingApproval
+	policy.SubmittedBy = performedBy
+	policy.SubmittedAt = &now
+	policy.UpdatedAt = now
+
+	if err := s.update(ctx, policy, domain.PolicyStatusDraft, domain.PolicyEventSubmitted, performedBy, ""); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Policy submitted for approval", zap.String("policy_version", version), zap.String("performed_by", performedBy))
+	return policy, nil
+}
+
+// ApprovePolicy approves a submitted version, in force from its effective date or now, whichever
+// is later. The checker must not be the maker who wrote or submitted it.
+func (s *PolicyService) ApprovePolicy(ctx context.Context, tenant, version, performedBy, comment string) (*domain.UnderwritingPolicy, error) {
+	policy, err := s.getForReview(ctx, tenant, version, performedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if policy.EffectiveFrom == nil || policy.EffectiveFrom.Before(now) {
+		policy.EffectiveFrom = &now
+	}
+	policy.Status = domain.PolicyStatusApproved
+	policy.ReviewedBy = performedBy
+	policy.ReviewedAt = &now
+	policy.ReviewComment = comment
+	policy.UpdatedAt = now
+
+	if err := s.update(ctx, policy, domain.PolicyStatusPendingApproval, domain.PolicyEventApproved, performedBy, comment); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Policy approved",
+		zap.String("tenant_id", policy.TenantID),
+		zap.String("policy_version", version),
+		zap.Time("effective_from", *policy.EffectiveFrom),
+		zap.String("maker", policy.CreatedBy),
+		zap.String("checker", performedBy))
+	s.reload(ctx)
+	return policy, nil
+}
+
+// RejectPolicy sends a submitted version back to draft with the checker's reasons
+func (s *PolicyService) RejectPolicy(ctx context.Context, tenant, version, performedBy, comment string) (*domain.UnderwritingPolicy, error) {
+	if strings.TrimSpace(comment) == "" {
+		return nil, invalidPolicyError(fmt.Errorf("a comment explaining the rejection is required"))
+	}
+	policy, err := s.getForReview(ctx, tenant, version, performedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	policy.Status = domain.PolicyStatusDraft
+	policy.SubmittedBy = ""
+	policy.SubmittedAt = nil
+	policy.ReviewedBy = performedBy
+	policy.ReviewedAt = &now
+	policy.ReviewComment = comment
+	policy.UpdatedAt = now
+
+	if err := s.update(ctx, policy, domain.PolicyStatusPendingApproval, domain.PolicyEventRejected, performedBy, comment); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Policy rejected", zap.String("policy_version", version), zap.String("checker", performedBy))
+	return policy, nil
+}
+
+// RetirePolicy withdraws an approved version that has not yet taken effect. A version in force
+// is replaced by approving a newer one instead.
+func (s *PolicyService) RetirePolicy(ctx context.Context, tenant, version, performedBy string) (*domain.UnderwritingPolicy, error) {
+	policy, err := s.getWithStatus(ctx, tenant, version, domain.PolicyStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if !policy.EffectiveFrom.After(now) {
+		return nil, policyConflictError(fmt.Sprintf("Policy %s took effect at %s; approve a new version to replace it", version, policy.EffectiveFrom.Format(time.RFC3339)))
+	}
+	policy.Status = domain.PolicyStatusRetired
+	policy.RetiredAt = &now
+	policy.UpdatedAt = now
+
+	if err := s.update(ctx, policy, domain.PolicyStatusApproved, domain.PolicyEventRetired, performedBy, ""); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Policy retired", zap.String("policy_version", version), zap.String("performed_by", performedBy))
+	s.reload(ctx)
+	return policy, nil
+}
+
+// getWithStatus returns a policy version that must have a status
+func (s *PolicyService) getWithStatus(ctx context.Context, tenant, version string, status domain.PolicyStatus) (*domain.UnderwritingPolicy, error) {
+	policy, err := s.GetPolicy(ctx, tenant, version)
+	if err != nil {
+		return nil, err
+	}
+	if policy.Status != status {
+		return nil, policyConflictError(fmt.Sprintf("Policy %s is %s; only %s versions can be changed this way", version, policy.Status, status))
+	}
+	return policy, nil
+}
+
+// getForReview returns a submitted version the actor may approve or reject
+func (s *PolicyService) getForReview(ctx context.Context, tenant, version, performedBy string) (*domain.UnderwritingPolicy, error) {
+	policy, err := s.getWithStatus(ctx, tenant, version, domain.PolicyStatusPendingApproval)
+	if err != nil {
+		return nil, err
+	}
+	if policy.IsMaker(performedBy) {
+		return nil, &domain.DecisionError{
+			Code:        domain.ERROR_POLICY_SELF_APPROVAL,
+			Message:     "Self-approval not allowed",
+			Description: fmt.Sprintf("Policy %s was written or submitted by %s and must be reviewed by another user", version, performedBy),
+			HTTPStatus:  403,
+		}
+	}
+	return policy, nil
+}
+
+// update saves a change to a version that must still have the expected status
+func (s *PolicyService) update(ctx context.Context, policy *domain.UnderwritingPolicy, expected domain.PolicyStatus, event domain.PolicyEventType, performedBy, comment string) error {
+	if err := s.repo.UpdatePolicy(ctx, policy, expected, event, performedBy, comment); err != nil {
+		if isNotFound(err) {
+			return policyConflictError(fmt.Sprintf("Policy %s is no longer %s", policy.Version, expected))
+		}
+		return s.databaseError(err)
+	}
+	return nil
+}
+
+// reload puts approved policy changes in force on this instance; other instances pick them up on
+// their next reload
+func (s *PolicyService) reload(ctx context.Context) {
+	if err := s.LoadPolicies(ctx); err != nil {
+		s.logger.Warn("Failed to reload underwriting policies after a change", zap.Error(err))
+	}
+}
+
+func (s *PolicyService) repositoryError(version string, err error) error {
+	if isNotFound(err) {
+		return policyNotFoundError(version)
+	}
+	return s.databaseError(err)
+}
+
+func (s *PolicyService) databaseError(err error) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_DATABASE_ERROR,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
+
+// validatePolicy checks a policy version's number, name and thresholds
+func validatePolicy(policy *domain.UnderwritingPolicy) error {
+	if _, err := domain.ParseVersion(policy.Version); err != nil {
+		return invalidPolicyError(err)
+	}
+	if strings.TrimSpace(policy.Name) == "" {
+		return invalidPolicyError(fmt.Errorf("name is required"))
+	}
+	if err := policy.Thresholds.Validate(); err != nil {
+		return invalidPolicyError(err)
+	}
+	return nil
+}
+
+// invalidPolicyError reports a policy definition or review that cannot be accepted
+func invalidPolicyError(err error) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_INVALID_REQUEST,
+		Message:     "Invalid underwriting policy",
+		Description: err.Error(),
+		HTTPStatus:  400,
+	}
+}
+
+func policyNotFoundError(version string) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_POLICY_NOT_FOUND,
+		Message:     "Policy not found",
+		Description: fmt.Sprintf("No underwriting policy found for version %s", version),
+		HTTPStatus:  404,
+	}
+}
+
+func policyConflictError(description string) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_POLICY_CONFLICT,
+		Message:     "Policy state conflict",
+		Description: description,
+		HTTPStatus:  409,
+	}
+}
@@ -0,0 +1,150 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+const (
+	// redecisionRetryBase is the wait before the first retry of a failed re-decision notification;
+	// it doubles with each further failure, up to redecisionRetryMax
+	redecisionRetryBase = 30 * time.Second
+	redecisionRetryMax  = time.Hour
+	// redecisionClaimLimit bounds the notifications retried per tick
+	redecisionClaimLimit = 50
+)
+
+// RedecisionDeliveryService tells the loan service of re-decisions through an outbox: each is
+// queued before it is sent, and ones the loan service does not accept, or that an instance stopped
+// before sending, are retried with backoff until it does. The loan service records a re-decision
+// once however often it is told of it.
+type RedecisionDeliveryService struct {
+	outbox       domain.RedecisionOutbox
+	decisionRepo domain.DecisionRepository
+	notifier     domain.RedecisionNotifier
+	logger       *zap.Logger
+}
+
+// NewRedecisionDeliveryService creates a new re-decision delivery service sending through notifier
+func NewRedecisionDeliveryService(
+	outbox domain.RedecisionOutbox,
+	decisionRepo domain.DecisionRepository,
+	notifier domain.RedecisionNotifier,
+	logger *zap.Logger,
+) *RedecisionDeliveryService {
+	return &RedecisionDeliveryService{
+		outbox:       outbox,
+		decisionRepo: decisionRepo,
+		notifier:     notifier,
+		logger:       logger,
+	}
+}
+
+// NotifyRedecision queues a saved re-decision and tries to send it at once. It fails only when
+// the re-decision could be neither queued nor sent; one queued but not sent is retried by Start.
+func (s *RedecisionDeliveryService) NotifyRedecision(ctx context.Context, decision *domain.DecisionResponse) error {
+	logger := s.logger.With(
+		zap.String("application_id", decision.ApplicationID),
+		zap.Int64("decision_id", decision.DecisionID),
+	)
+
+	now := time.Now().UTC()
+	queueErr := s.outbox.EnqueueRedecisionNotification(ctx, decision.DecisionID, decision.ApplicationID, now)
+	if queueErr != nil {
+		logger.Error("Failed to queue re-decision notification, sending it without retries", zap.Error(queueErr))
+	}
+
+	sendErr := s.notifier.NotifyRedecision(ctx, decision)
+	if queueErr != nil {
+		return sendErr
+	}
+	s.recordAttempt(ctx, logger, domain.RedecisionNotification{
+		DecisionID:    decision.DecisionID,
+		ApplicationID: decision.ApplicationID,
+		CreatedAt:     now,
+	}, sendErr)
+	return nil
+}
+
+// Start retries due re-decision notifications every interval until ctx is cancelled
+func (s *RedecisionDeliveryService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.retryDue(ctx)
+		}
+	}
+}
+
+// retryDue sends the notifications that are due, each with the decision as it was saved
+func (s *RedecisionDeliveryService) retryDue(ctx context.Context) {
+	notifications, err := s.outbox.ClaimDueRedecisionNotifications(ctx, redecisionClaimLimit, redecisionTimeout)
+	if err != nil {
+		s.logger.Warn("Failed to check for re-decision notifications to retry", zap.Error(err))
+		return
+	}
+
+	for _, notification := range notifications {
+		logger := s.logger.With(
+			zap.String("application_id", notification.ApplicationID),
+			zap.Int64("decision_id", notification.DecisionID),
+			zap.Int("attempts", notification.Attempts),
+		)
+
+		sendCtx, cancel := context.WithTimeout(ctx, redecisionTimeout)
+		decision, err := s.decisionRepo.GetDecisionByID(sendCtx, notification.DecisionID)
+		if err != nil {
+			err = fmt.Errorf("failed to load re-decision: %w", err)
+		} else {
+			err = s.notifier.NotifyRedecision(sendCtx, decision)
+		}
+		s.recordAttempt(sendCtx, logger, notification, err)
+		cancel()
+	}
+}
+
+// recordAttempt marks a notification delivered, or schedules its next attempt when sendErr is set
+func (s *RedecisionDeliveryService) recordAttempt(ctx context.Context, logger *zap.Logger, notification domain.RedecisionNotification, sendErr error) {
+	now := time.Now().UTC()
+	if sendErr == nil {
+		if err := s.outbox.MarkRedecisionNotificationDelivered(ctx, notification.DecisionID, now); err != nil {
+			// Left undelivered, it is sent again, which the loan service ignores
+			logger.Warn("Failed to mark re-decision notification delivered", zap.Error(err))
+		}
+		return
+	}
+
+	next := now.Add(redecisionRetryDelay(notification.Attempts + 1))
+	logger.Warn("Failed to notify the loan service of the re-decision, will retry",
+		zap.Time("next_attempt_at", next),
+		zap.Error(sendErr))
+	if err := s.outbox.RescheduleRedecisionNotification(ctx, notification.DecisionID, sendErr.Error(), next); err != nil {
+		// Its claim lease runs out, so it is retried all the same
+		logger.Warn("Failed to reschedule re-decision notification", zap.Error(err))
+	}
+}
+
+// redecisionRetryDelay is the wait before retrying a notification that has failed attempts times
+func redecisionRetryDelay(attempts int) time.Duration {
+	delay := redecisionRetryBase
+	for i := 1; i < attempts && delay < redecisionRetryMax; i++ {
+		delay *= 2
+	}
+	if delay > redecisionRetryMax {
+		delay = redecisionRetryMax
+	}
+	return delay
+}
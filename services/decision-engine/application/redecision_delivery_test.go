@@ -0,0 +1,159 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+)
+
+// stubRedecisionOutbox keeps notifications in memory
+type stubRedecisionOutbox struct {
+	queueErr    error
+	queued      []int64
+	due         []domain.RedecisionNotification
+	delivered   []int64
+	rescheduled map[int64]time.Time
+}
+
+func (o *stubRedecisionOutbox) EnqueueRedecisionNotification(ctx context.Context, decisionID int64, applicationID string, at time.Time) error {
+	if o.queueErr != nil {
+		return o.queueErr
+	}
+	o.queued = append(o.queued, decisionID)
+	return nil
+}
+
+func (o *stubRedecisionOutbox) ClaimDueRedecisionNotifications(ctx context.Context, limit int, lease time.Duration) ([]domain.RedecisionNotification, error) {
+	due := o.due
+	o.due = nil
+	return due, nil
+}
+
+func (o *stubRedecisionOutbox) MarkRedecisionNotificationDelivered(ctx context.Context, decisionID int64, at time.Time) error {
+	o.delivered = append(o.delivered, decisionID)
+	return nil
+}
+
+func (o *stubRedecisionOutbox) RescheduleRedecisionNotification(ctx context.Context, decisionID int64, lastError string, next time.Time) error {
+	if o.rescheduled == nil {
+		o.rescheduled = make(map[int64]time.Time)
+	}
+	o.rescheduled[decisionID] = next
+	return nil
+}
+
+// stubRedecisionNotifier fails while err is set and records the decisions it was told of
+type stubRedecisionNotifier struct {
+	err      error
+	notified []int64
+}
+
+func (n *stubRedecisionNotifier) NotifyRedecision(ctx context.Context, decision *domain.DecisionResponse) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.notified = append(n.notified, decision.DecisionID)
+	return nil
+}
+
+// stubDecisionLookup serves saved decisions by id
+type stubDecisionLookup struct {
+	domain.DecisionRepository
+	decisions map[int64]*domain.DecisionResponse
+}
+
+func (r *stubDecisionLookup) GetDecisionByID(ctx context.Context, id int64) (*domain.DecisionResponse, error) {
+	if decision, ok := r.decisions[id]; ok {
+		return decision, nil
+	}
+	return nil, errors.New("decision not found")
+}
+
+func TestRedecisionDeliveryService_NotifyRedecision(t *testing.T) {
+	decision := &domain.DecisionResponse{DecisionID: 7, ApplicationID: "app-1", Decision: domain.DecisionApprove}
+
+	t.Run("delivered at once", func(t *testing.T) {
+		outbox := &stubRedecisionOutbox{}
+		notifier := &stubRedecisionNotifier{}
+		service := NewRedecisionDeliveryService(outbox, &stubDecisionLookup{}, notifier, zap.NewNop())
+
+		require.NoError(t, service.NotifyRedecision(context.Background(), decision))
+		assert.Equal(t, []int64{7}, outbox.queued)
+		assert.Equal(t, []int64{7}, notifier.notified)
+		assert.Equal(t, []int64{7}, outbox.delivered)
+		assert.Empty(t, outbox.rescheduled)
+	})
+
+	t.Run("loan service down is retried later", func(t *testing.T) {
+		outbox := &stubRedecisionOutbox{}
+		notifier := &stubRedecisionNotifier{err: errors.New("unexpected re-decision status: 503")}
+		service := NewRedecisionDeliveryService(outbox, &stubDecisionLookup{}, notifier, zap.NewNop())
+
+		before := time.Now().UTC()
+		require.NoError(t, service.NotifyRedecision(context.Background(), decision))
+		assert.Equal(t, []int64{7}, outbox.queued)
+		assert.Empty(t, outbox.delivered)
+		require.Contains(t, outbox.rescheduled, int64(7))
+		assert.False(t, outbox.rescheduled[7].Before(before.Add(redecisionRetryBase)))
+	})
+
+	t.Run("not queued and not sent fails", func(t *testing.T) {
+		outbox := &stubRedecisionOutbox{queueErr: errors.New("connection refused")}
+		notifier := &stubRedecisionNotifier{err: errors.New("unexpected re-decision status: 503")}
+		service := NewRedecisionDeliveryService(outbox, &stubDecisionLookup{}, notifier, zap.NewNop())
+
+		assert.Error(t, service.NotifyRedecision(context.Background(), decision))
+		assert.Empty(t, outbox.rescheduled)
+	})
+}
+
+func TestRedecisionDeliveryService_RetryDue(t *testing.T) {
+	decisions := &stubDecisionLookup{decisions: map[int64]*domain.DecisionResponse{
+		7: {DecisionID: 7, ApplicationID: "app-1", Decision: domain.DecisionApprove},
+	}}
+
+	t.Run("sends the saved decision and marks it delivered", func(t *testing.T) {
+		outbox := &stubRedecisionOutbox{due: []domain.RedecisionNotification{{DecisionID: 7, ApplicationID: "app-1", Attempts: 2}}}
+		notifier := &stubRedecisionNotifier{}
+		service := NewRedecisionDeliveryService(outbox, decisions, notifier, zap.NewNop())
+
+		service.retryDue(context.Background())
+		assert.Equal(t, []int64{7}, notifier.notified)
+		assert.Equal(t, []int64{7}, outbox.delivered)
+	})
+
+	t.Run("failure backs off further", func(t *testing.T) {
+		outbox := &stubRedecisionOutbox{due: []domain.RedecisionNotification{{DecisionID: 7, ApplicationID: "app-1", Attempts: 2}}}
+		notifier := &stubRedecisionNotifier{err: errors.New("failed to call loan service")}
+		service := NewRedecisionDeliveryService(outbox, decisions, notifier, zap.NewNop())
+
+		before := time.Now().UTC()
+		service.retryDue(context.Background())
+		assert.Empty(t, outbox.delivered)
+		require.Contains(t, outbox.rescheduled, int64(7))
+		assert.False(t, outbox.rescheduled[7].Before(before.Add(4*redecisionRetryBase)))
+	})
+}
+
+func TestRedecisionRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: redecisionRetryBase},
+		{attempts: 2, want: 2 * redecisionRetryBase},
+		{attempts: 4, want: 8 * redecisionRetryBase},
+		{attempts: 30, want: redecisionRetryMax},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, redecisionRetryDelay(tt.attempts), "attempts %d", tt.attempts)
+	}
+}
@@ -10,12 +10,15 @@ import (
 
 // RiskAssessmentService implements risk assessment logic
 type RiskAssessmentService struct {
+	scorer domain.ApplicationScorer
 	logger *zap.Logger
 }
 
-// NewRiskAssessmentService creates a new risk assessment service
-func NewRiskAssessmentService(logger *zap.Logger) *RiskAssessmentService {
+// NewRiskAssessmentService creates a new risk assessment service. The scorer may be nil, in which
+// case the overall score comes from the bureau score and category scores alone.
+func NewRiskAssessmentService(logger *zap.Logger, scorer domain.ApplicationScorer) *RiskAssessmentService {
 	return &RiskAssessmentService{
+		scorer: scorer,
 		logger: logger,
 	}
 }
@@ -31,6 +34,7 @@ func (s *RiskAssessmentService) AssessRisk(request *domain.DecisionRequest) (*do
 
 	assessment := &domain.RiskAssessment{
 		DTIRatio: request.CalculateDTI(),
+		LTVRatio: request.CalculateLTV(),
 	}
 
 	// Calculate category scores
@@ -45,6 +49,18 @@ func (s *RiskAssessmentService) AssessRisk(request *domain.DecisionRequest) (*do
 	// Calculate overall risk score
 	assessment.OverallScore = s.CalculateRiskScore(assessment)
 
+	// Blend in the active scorecard. A scorecard failure falls back to the raw score rather than
+	// failing the decision.
+	if s.scorer != nil {
+		result, err := s.scorer.ScoreApplication(request, assessment)
+		if err != nil {
+			logger.Warn("Scorecard scoring failed, using raw risk score", zap.Error(err))
+		} else if result != nil {
+			assessment.Scorecard = result
+			assessment.OverallScore = s.blendScorecard(assessment.OverallScore, result)
+		}
+	}
+
 	// Identify mitigating factors
 	assessment.MitigatingFactors = s.identifyMitigatingFactors(request, assessment)
 
@@ -64,8 +80,39 @@ func (s *RiskAssessmentService) calculateCategoryScores(request *domain.Decision
 		IncomeRisk:     s.calculateIncomeRisk(request),
 		DebtRisk:       s.calculateDebtRisk(request),
 		EmploymentRisk: s.calculateEmploymentRisk(request),
-		CollateralRisk: 0.0, // Not applicable for unsecured loans
+		CollateralRisk: s.calculateCollateralRisk(request),
+	}
+}
+
+// calculateCollateralRisk calculates collateral-based risk score from the loan-to-value ratio
+func (s *RiskAssessmentService) calculateCollateralRisk(request *domain.DecisionRequest) float64 {
+	if !request.IsSecured() {
+		return 0.0 // Not applicable for unsecured loans
+	}
+
+	var riskScore float64
+
+	switch ltvRatio := request.CalculateLTV(); {
+	case ltvRatio <= 0:
+		riskScore = 1.0 // Collateral without a value
+	case ltvRatio <= 0.60:
+		riskScore = 0.1 // Well secured
+	case ltvRatio <= 0.80:
+		riskScore = 0.3
+	case ltvRatio <= 1.00:
+		riskScore = 0.5
+	case ltvRatio <= 1.25:
+		riskScore = 0.7 // Loan exceeds the collateral's value
+	default:
+		riskScore = 1.0
+	}
+
+	// Values only the borrower has estimated are less reliable
+	if request.Collateral.ValuationSource == domain.ValuationSourceStated {
+		riskScore += 0.1
 	}
+
+	return math.Min(riskScore, 1.0)
 }
 
 // calculateCreditRisk calculates credit-based risk score (0-1, higher is riskier)
@@ -294,6 +341,21 @@ func (s *RiskAssessmentService) identifyRiskFactors(request *domain.DecisionRequ
 		})
 	}
 
+	// Collateral factors
+	if request.IsSecured() && assessment.LTVRatio > 1.0 {
+		impact := "MEDIUM"
+		if assessment.LTVRatio > request.MaxLTVRatio() {
+			impact = "HIGH"
+		}
+		factors = append(factors, domain.RiskFactor{
+			Category:    "COLLATERAL",
+			Factor:      "High Loan-to-Value Ratio",
+			Impact:      impact,
+			Score:       assessment.CategoryScores.CollateralRisk,
+			Description: fmt.Sprintf("Loan amount is %.0f%% of the $%.0f %s collateral value", assessment.LTVRatio*100, request.Collateral.Value, request.Collateral.Type),
+		})
+	}
+
 	// Payment history factors
 	if assessment.PaymentHistory.PaymentScore < 0.7 {
 		factors = append(factors, domain.RiskFactor{
@@ -342,6 +404,11 @@ func (s *RiskAssessmentService) identifyMitigatingFactors(request *domain.Decisi
 		factors = append(factors, "Excellent payment history demonstrates reliability")
 	}
 
+	// Well-secured loan
+	if request.IsSecured() && assessment.LTVRatio > 0 && assessment.LTVRatio <= 0.80 {
+		factors = append(factors, "Collateral value comfortably exceeds the loan amount")
+	}
+
 	// Debt consolidation purpose (generally lower risk)
 	if request.LoanPurpose == domain.PurposeDebtConsolidation {
 		factors = append(factors, "Debt consolidation may improve overall financial position")
@@ -358,7 +425,7 @@ func (s *RiskAssessmentService) CalculateRiskScore(assessment *domain.RiskAssess
 		"debt":       0.25, // Debt management is crucial
 		"income":     0.20, // Income capacity matters
 		"employment": 0.15, // Employment stability
-		"collateral": 0.05, // Minimal for unsecured loans
+		"collateral": 0.05, // Zero risk for unsecured loans
 	}
 
 	score := assessment.CategoryScores.CreditRisk*weights["credit"] +
@@ -379,6 +446,12 @@ func (s *RiskAssessmentService) CalculateRiskScore(assessment *domain.RiskAssess
 	return math.Max(0.0, math.Min(1.0, score))
 }
 
+// blendScorecard weights the scorecard's risk into the raw risk score
+func (s *RiskAssessmentService) blendScorecard(score float64, result *domain.ScorecardResult) float64 {
+	blended := (1-result.RiskWeight)*score + result.RiskWeight*result.Risk
+	return math.Max(0.0, math.Min(1.0, blended))
+}
+
 // CategorizeRisk categorizes risk level based on score
 func (s *RiskAssessmentService) CategorizeRisk(score float64) domain.RiskCategory {
 	switch {
@@ -0,0 +1,322 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// RuleService manages versioned decision rules. Rules are written as drafts, which may be edited
+// or discarded; publishing a draft freezes it and puts it in force from its effective date, and
+// retiring takes it out of force. Every change is kept in the rule history. Each rule belongs to
+// one lending program, and another program's rules are not found.
+type RuleService struct {
+	repo   domain.RulesRepository
+	engine *RulesEngine
+	logger *zap.Logger
+}
+
+// NewRuleService creates a new rule service that reloads engine when the published rules change
+func NewRuleService(repo domain.RulesRepository, engine *RulesEngine, logger *zap.Logger) *RuleService {
+	return &RuleService{
+		repo:   repo,
+		engine: engine,
+		logger: logger,
+	}
+}
+
+// ListRules returns the lending program's rule versions with a status, or every version when
+// status is empty
+func (s *RuleService) ListRules(ctx context.Context, tenant string, status domain.RuleStatus) ([]domain.DecisionRule, error) {
+	if status != "" && !status.IsValid() {
+		return nil, invalidRuleError(fmt.Errorf("unknown rule status %q", status))
+	}
+
+	all, err := s.repo.ListRuleVersions(ctx, status)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	var rules []domain.DecisionRule
+	for _, rule := range all {
+		if rule.Tenant() == domain.TenantOrDefault(tenant) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// GetRuleVersions returns every version of one of the lending program's rules, oldest first
+func (s *RuleService) GetRuleVersions(ctx context.Context, tenant, ruleID string) ([]domain.DecisionRule, error) {
+	versions, err := s.repo.GetRuleVersions(ctx, ruleID)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	if len(versions) == 0 || versions[0].Tenant() != domain.TenantOrDefault(tenant) {
+		return nil, ruleNotFoundError(ruleID)
+	}
+	return versions, nil
+}
+
+// GetRuleVersion returns a single version of one of the lending program's rules, e.g. one a past
+// decision was made with
+func (s *RuleService) GetRuleVersion(ctx context.Context, tenant, ruleID, version string) (*domain.DecisionRule, error) {
+	rule, err := s.repo.GetRuleVersion(ctx, ruleID, version)
+	if err != nil {
+		return nil, s.repositoryError(ruleID+"@"+version, err)
+	}
+	if rule.Tenant() != domain.TenantOrDefault(tenant) {
+		return nil, ruleNotFoundError(ruleID + "@" + version)
+	}
+	return rule, nil
+}
+
+// GetRuleHistory returns the changes made to one of the lending program's rules, oldest first
+func (s *RuleService) GetRuleHistory(ctx context.Context, tenant, ruleID string) ([]domain.RuleEvent, error) {
+	if _, err := s.GetRuleVersions(ctx, tenant, ruleID); err != nil {
+		return nil, err
+	}
+
+	events, err := s.repo.GetRuleEvents(ctx, ruleID)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	if len(events) == 0 {
+		return nil, ruleNotFoundError(ruleID)
+	}
+	return events, nil
+}
+
+// CreateRule creates the first draft of a new rule for a lending program. Rule ids are unique
+// across programs.
+func (s *RuleService) CreateRule(ctx context.Context, tenant string, req *domain.RuleVersionRequest, performedBy string) (*domain.DecisionRule, error) {
+	existing, err := s.repo.GetRuleVersions(ctx, req.ID)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	if len(existing) > 0 {
+		return nil, ruleConflictError(fmt.Sprintf("Rule %s already exists; create a new version of it instead", req.ID))
+	}
+
+	version := req.Version
+	if version == "" {
+		version = domain.InitialRuleVersion
+	}
+	return s.createDraft(ctx, tenant, req.ID, version, req, performedBy)
+}
+
+// CreateVersion creates a new draft version of an existing rule. The version defaults to the next
+// minor version and must be greater than every existing version.
+func (s *RuleService) CreateVersion(ctx context.Context, tenant, ruleID string, req *domain.RuleVersionRequest, performedBy string) (*domain.DecisionRule, error) {
+	versions, err := s.GetRuleVersions(ctx, tenant, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	latest := versions[len(versions)-1].Version
+
+	version := req.Version
+	if version == "" {
+		version = domain.NextMinorVersion(latest)
+	}
+	if domain.CompareVersions(version, latest) <= 0 {
+		return nil, ruleConflictError(fmt.Sprintf("Version %s must be greater than the latest version %s", version, latest))
+	}
+	return s.createDraft(ctx, tenant, ruleID, version, req, performedBy)
+}
+
+// UpdateDraft replaces the definition of a draft version
+func (s *RuleService) UpdateDraft(ctx context.Context, tenant, ruleID, version string, req *domain.RuleVersionRequest, performedBy string) (*domain.DecisionRule, error) {
+	rule, err := s.getDraft(ctx, tenant, ruleID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Apply(rule)
+	if _, err := compileRule(rule); err != nil {
+		return nil, invalidRuleError(err)
+	}
+	rule.UpdatedAt = time.Now().UTC()
+
+	if err := s.repo.UpdateDraft(ctx, rule, performedBy); err != nil {
+		return nil, s.repositoryError(rule.VersionedID(), err)
+	}
+
+	s.logger.Info("Rule draft updated", zap.String("rule", rule.VersionedID()), zap.String("performed_by", performedBy))
+	return rule, nil
+}
+
+// DiscardDraft deletes a draft version
+func (s *RuleService) DiscardDraft(ctx context.Context, tenant, ruleID, version, performedBy string) error {
+	if _, err := s.getDraft(ctx, tenant, ruleID, version); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteDraft(ctx, ruleID, version, performedBy); err != nil {
+		return s.repositoryError(ruleID+"@"+version, err)
+	}
+
+	s.logger.Info("Rule draft discarded", zap.String("rule", ruleID+"@"+version), zap.String("performed_by", performedBy))
+	return nil
+}
+
+// PublishVersion publishes a draft version, effective from its effective date or now
+func (s *RuleService) PublishVersion(ctx context.Context, tenant, ruleID, version, performedBy string) (*domain.DecisionRule, error) {
+	rule, err := s.getDraft(ctx, tenant, ruleID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := compileRule(rule); err != nil {
+		return nil, invalidRuleError(err)
+	}
+
+	now := time.Now().UTC()
+	if rule.EffectiveFrom == nil {
+		rule.EffectiveFrom = &now
+	}
+	if rule.ExpiresAt != nil && !rule.ExpiresAt.After(now) {
+		return nil, ruleConflictError(fmt.Sprintf("Rule %s expired at %s and cannot be published", rule.VersionedID(), rule.ExpiresAt.Format(time.RFC3339)))
+	}
+	rule.Status = domain.RuleStatusPublished
+	rule.PublishedBy = performedBy
+	rule.PublishedAt = &now
+	rule.UpdatedAt = now
+
+	if err := s.repo.PublishRuleVersion(ctx, rule); err != nil {
+		return nil, s.repositoryError(rule.VersionedID(), err)
+	}
+
+	s.logger.Info("Rule version published",
+		zap.String("rule", rule.VersionedID()),
+		zap.Time("effective_from", *rule.EffectiveFrom),
+		zap.String("performed_by", performedBy))
+	s.reloadEngine(ctx)
+	return rule, nil
+}
+
+// RetireVersion takes a published version out of force
+func (s *RuleService) RetireVersion(ctx context.Context, tenant, ruleID, version, performedBy string) (*domain.DecisionRule, error) {
+	rule, err := s.GetRuleVersion(ctx, tenant, ruleID, version)
+	if err != nil {
+		return nil, err
+	}
+	if rule.Status != domain.RuleStatusPublished {
+		return nil, ruleConflictError(fmt.Sprintf("Rule %s is %s; only published versions can be retired", rule.VersionedID(), rule.Status))
+	}
+
+	now := time.Now().UTC()
+	if err := s.repo.RetireRuleVersion(ctx, ruleID, version, performedBy, now); err != nil {
+		return nil, s.repositoryError(rule.VersionedID(), err)
+	}
+	rule.Status = domain.RuleStatusRetired
+	rule.RetiredAt = &now
+	rule.UpdatedAt = now
+
+	s.logger.Info("Rule version retired", zap.String("rule", rule.VersionedID()), zap.String("performed_by", performedBy))
+	s.reloadEngine(ctx)
+	return rule, nil
+}
+
+// createDraft validates and stores a new draft version
+func (s *RuleService) createDraft(ctx context.Context, tenant, ruleID, version string, req *domain.RuleVersionRequest, performedBy string) (*domain.DecisionRule, error) {
+	now := time.Now().UTC()
+	rule := &domain.DecisionRule{
+		ID:        ruleID,
+		Version:   version,
+		TenantID:  domain.TenantOrDefault(tenant),
+		Status:    domain.RuleStatusDraft,
+		CreatedBy: performedBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	req.Apply(rule)
+
+	if _, err := compileRule(rule); err != nil {
+		return nil, invalidRuleError(err)
+	}
+
+	if err := s.repo.CreateRuleVersion(ctx, rule); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, ruleConflictError(fmt.Sprintf("Rule %s already exists", rule.VersionedID()))
+		}
+		return nil, s.databaseError(err)
+	}
+
+	s.logger.Info("Rule draft created",
+		zap.String("rule", rule.VersionedID()),
+		zap.String("tenant_id", rule.TenantID),
+		zap.String("performed_by", performedBy))
+	return rule, nil
+}
+
+// getDraft returns a rule version that must still be a draft
+func (s *RuleService) getDraft(ctx context.Context, tenant, ruleID, version string) (*domain.DecisionRule, error) {
+	rule, err := s.GetRuleVersion(ctx, tenant, ruleID, version)
+	if err != nil {
+		return nil, err
+	}
+	if rule.Status != domain.RuleStatusDraft {
+		return nil, ruleConflictError(fmt.Sprintf("Rule %s is %s and can no longer be changed; create a new version instead", rule.VersionedID(), rule.Status))
+	}
+	return rule, nil
+}
+
+// reloadEngine puts published rule changes in force on this instance; other instances pick them
+// up on their next reload
+func (s *RuleService) reloadEngine(ctx context.Context) {
+	if err := s.engine.LoadRules(ctx); err != nil {
+		s.logger.Warn("Failed to reload decision rules after a change", zap.Error(err))
+	}
+}
+
+func (s *RuleService) repositoryError(ref string, err error) error {
+	if isNotFound(err) {
+		return ruleNotFoundError(ref)
+	}
+	return s.databaseError(err)
+}
+
+// isNotFound reports whether the rules repository found no matching rule version
+func isNotFound(err error) bool {
+	return strings.Contains(err.Error(), "not found")
+}
+
+func (s *RuleService) databaseError(err error) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_DATABASE_ERROR,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
+
+// invalidRuleError reports a rule definition that cannot be compiled
+func invalidRuleError(err error) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_INVALID_REQUEST,
+		Message:     "Invalid decision rule",
+		Description: err.Error(),
+		HTTPStatus:  400,
+	}
+}
+
+func ruleNotFoundError(ref string) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_RULE_NOT_FOUND,
+		Message:     "Rule not found",
+		Description: fmt.Sprintf("No decision rule found for %s", ref),
+		HTTPStatus:  404,
+	}
+}
+
+func ruleConflictError(description string) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_RULE_CONFLICT,
+		Message:     "Rule state conflict",
+		Description: description,
+		HTTPStatus:  409,
+	}
+}
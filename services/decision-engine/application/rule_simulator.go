@@ -0,0 +1,282 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// RuleSimulator backtests proposed rule sets by replaying stored decision requests against both the
+// published rules in force and the proposal, so the effect of a rule change on approval rates and
+// expected losses is known before it is published. Both sides are replayed rather than compared
+// with the stored decisions, so the deltas reflect only the rule change. A simulation covers one
+// lending program: its rules and the requests decided under it.
+type RuleSimulator struct {
+	rulesRepo    domain.RulesRepository
+	decisionRepo domain.DecisionRepository
+	engine       *RulesEngine
+	logger       *zap.Logger
+}
+
+// NewRuleSimulator creates a new rule simulator
+func NewRuleSimulator(rulesRepo domain.RulesRepository, decisionRepo domain.DecisionRepository, engine *RulesEngine, logger *zap.Logger) *RuleSimulator {
+	return &RuleSimulator{
+		rulesRepo:    rulesRepo,
+		decisionRepo: decisionRepo,
+		engine:       engine,
+		logger:       logger,
+	}
+}
+
+// Simulate replays the decision requests in the request window against the proposed rule set
+func (s *RuleSimulator) Simulate(ctx context.Context, req *domain.SimulationRequest) (*domain.SimulationResult, error) {
+	now := time.Now().UTC()
+	if err := req.Normalize(now); err != nil {
+		return nil, simulationRequestError(err)
+	}
+
+	tenant := domain.TenantOrDefault(req.TenantID)
+	logger := s.logger.With(
+		zap.String("operation", "simulate_rules"),
+		zap.String("tenant_id", tenant),
+		zap.Time("from", *req.From),
+		zap.Time("to", *req.To),
+	)
+
+	activeRules, err := s.engine.GetActiveRules()
+	if err != nil {
+		return nil, err
+	}
+	var baselineRules []domain.DecisionRule
+	for _, rule := range activeRules {
+		if rule.Tenant() == tenant {
+			baselineRules = append(baselineRules, rule)
+		}
+	}
+	proposedRules, err := proposeRuleSet(ctx, s.rulesRepo, tenant, baselineRules, &req.RuleSetChanges)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline, err := compileRules(baselineRules)
+	if err != nil {
+		return nil, invalidRuleError(err)
+	}
+	proposed, err := compileRules(proposedRules)
+	if err != nil {
+		return nil, invalidRuleError(err)
+	}
+
+	history, err := s.decisionRepo.GetHistoricalDecisions(ctx, domain.DecisionHistoryQuery{
+		TenantID: tenant,
+		From:     *req.From,
+		To:       *req.To,
+		Limit:    req.Limit,
+	})
+	if err != nil {
+		return nil, &domain.DecisionError{
+			Code:        domain.ERROR_DATABASE_ERROR,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	result := &domain.SimulationResult{
+		From:                 *req.From,
+		To:                   *req.To,
+		LossGivenDefault:     req.LossGivenDefault,
+		BaselineRuleVersions: versionedIDs(baseline),
+		ProposedRuleVersions: versionedIDs(proposed),
+		Baseline:             domain.NewSimulationMetrics(),
+		Proposed:             domain.NewSimulationMetrics(),
+		DecisionChanges:      make(map[string]int),
+		AffectedApplications: []domain.AffectedApplication{},
+		SimulatedAt:          now,
+	}
+
+	for i := range history {
+		historical := &history[i]
+		if err := s.replay(historical, baseline, proposed, req.LossGivenDefault, result); err != nil {
+			logger.Warn("Skipping decision request that could not be replayed",
+				zap.String("application_id", historical.Request.ApplicationID),
+				zap.Error(err))
+			result.ApplicationsSkipped++
+		}
+	}
+
+	result.Baseline.Finalize(result.ApplicationsReplayed)
+	result.Proposed.Finalize(result.ApplicationsReplayed)
+	result.Delta = domain.NewSimulationDelta(result.Baseline, result.Proposed)
+
+	logger.Info("Rule simulation completed",
+		zap.Int("replayed", result.ApplicationsReplayed),
+		zap.Int("skipped", result.ApplicationsSkipped),
+		zap.Int("affected", result.AffectedCount),
+		zap.Float64("approval_rate_delta", result.Delta.ApprovalRate),
+		zap.Float64("expected_loss_delta", result.Delta.ExpectedLoss))
+	return result, nil
+}
+
+// proposeRuleSet applies rule set changes to the published rules in force. Proposed versions are
+// treated as in force regardless of their status and effective dates. With a tenant, stored
+// versions must belong to that lending program; rules defined inline belong to the tenant, or to
+// the default program when there is none.
+func proposeRuleSet(ctx context.Context, rulesRepo domain.RulesRepository, tenant string, published []domain.DecisionRule, req *domain.RuleSetChanges) ([]domain.DecisionRule, error) {
+	byID := make(map[string]domain.DecisionRule, len(published))
+	var order []string
+	set := func(rule domain.DecisionRule) {
+		if _, ok := byID[rule.ID]; !ok {
+			order = append(order, rule.ID)
+		}
+		byID[rule.ID] = rule
+	}
+
+	publishedVersions := make(map[string]string, len(published))
+	for _, rule := range published {
+		publishedVersions[rule.ID] = rule.Version
+		if !req.ReplacePublished {
+			set(rule)
+		}
+	}
+
+	for _, ref := range req.RuleVersions {
+		ruleID, version, err := domain.ParseVersionedID(ref)
+		if err != nil {
+			return nil, invalidRuleError(err)
+		}
+		rule, err := rulesRepo.GetRuleVersion(ctx, ruleID, version)
+		if err != nil {
+			if isNotFound(err) {
+				return nil, ruleNotFoundError(ref)
+			}
+			return nil, err
+		}
+		if tenant != "" && rule.Tenant() != tenant {
+			return nil, ruleNotFoundError(ref)
+		}
+		set(proposedRule(*rule))
+	}
+
+	for i := range req.Rules {
+		definition := &req.Rules[i]
+		rule := domain.DecisionRule{ID: definition.ID, Version: definition.Version, TenantID: domain.TenantOrDefault(tenant)}
+		if rule.Version == "" {
+			rule.Version = domain.InitialRuleVersion
+			if current, ok := publishedVersions[rule.ID]; ok {
+				rule.Version = domain.NextMinorVersion(current)
+			}
+		}
+		definition.Apply(&rule)
+		set(proposedRule(rule))
+	}
+
+	for _, ruleID := range req.RemoveRules {
+		if _, ok := byID[ruleID]; !ok {
+			return nil, ruleNotFoundError(ruleID)
+		}
+		delete(byID, ruleID)
+	}
+
+	rules := make([]domain.DecisionRule, 0, len(byID))
+	for _, ruleID := range order {
+		if rule, ok := byID[ruleID]; ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// replay evaluates one stored request against both rule sets and records the outcome
+func (s *RuleSimulator) replay(historical *domain.HistoricalDecision, baseline, proposed []compiledRule, lossGivenDefault float64, result *domain.SimulationResult) error {
+	request := &historical.Request
+	assessment, err := s.engine.riskService.AssessRisk(request)
+	if err != nil {
+		return fmt.Errorf("risk assessment failed: %w", err)
+	}
+
+	before, err := s.engine.evaluate(baseline, request, assessment)
+	if err != nil {
+		return fmt.Errorf("baseline rules failed: %w", err)
+	}
+	after, err := s.engine.evaluate(proposed, request, assessment)
+	if err != nil {
+		return fmt.Errorf("proposed rules failed: %w", err)
+	}
+
+	pd := domain.ProbabilityOfDefault[before.RiskCategory]
+	beforeLoss := expectedLoss(before, pd, lossGivenDefault)
+	afterLoss := expectedLoss(after, pd, lossGivenDefault)
+
+	result.ApplicationsReplayed++
+	result.Baseline.Record(before.Decision, before.ApprovedAmount, beforeLoss)
+	result.Proposed.Record(after.Decision, after.ApprovedAmount, afterLoss)
+
+	amountChanged := math.Abs(after.ApprovedAmount-before.ApprovedAmount) >= 0.01
+	if before.Decision == after.Decision && !amountChanged {
+		return nil
+	}
+
+	result.AffectedCount++
+	if before.Decision != after.Decision {
+		result.DecisionChanges[domain.DecisionChangeKey(before.Decision, after.Decision)]++
+	}
+	if len(result.AffectedApplications) >= domain.MaxSimulationAffected {
+		result.AffectedListTruncated = true
+		return nil
+	}
+
+	result.AffectedApplications = append(result.AffectedApplications, domain.AffectedApplication{
+		ApplicationID:      request.ApplicationID,
+		RequestedAt:        request.RequestedAt,
+		LoanAmount:         request.LoanAmount,
+		RiskCategory:       before.RiskCategory,
+		HistoricalDecision: historical.Decision,
+		BaselineDecision:   before.Decision,
+		ProposedDecision:   after.Decision,
+		BaselineAmount:     before.ApprovedAmount,
+		ProposedAmount:     after.ApprovedAmount,
+		ProposedReason:     after.DecisionReason,
+		ProposedRules:      after.AppliedRules,
+		ExpectedLossDelta:  afterLoss - beforeLoss,
+	})
+	return nil
+}
+
+// proposedRule puts a proposed rule version in force
+func proposedRule(rule domain.DecisionRule) domain.DecisionRule {
+	rule.Status = domain.RuleStatusPublished
+	rule.EffectiveFrom = nil
+	rule.ExpiresAt = nil
+	return rule
+}
+
+// expectedLoss estimates the loss on an approved amount as PD x LGD x exposure
+func expectedLoss(decision *domain.DecisionResponse, probabilityOfDefault, lossGivenDefault float64) float64 {
+	if !decision.Decision.IsApproval() {
+		return 0
+	}
+	return decision.ApprovedAmount * probabilityOfDefault * lossGivenDefault
+}
+
+func versionedIDs(rules []compiledRule) []string {
+	ids := make([]string, 0, len(rules))
+	for _, compiled := range rules {
+		ids = append(ids, compiled.rule.VersionedID())
+	}
+	return ids
+}
+
+// simulationRequestError reports a simulation request that cannot be run
+func simulationRequestError(err error) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_INVALID_REQUEST,
+		Message:     "Invalid simulation request",
+		Description: err.Error(),
+		HTTPStatus:  400,
+	}
+}
@@ -0,0 +1,372 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Knetic/govaluate"
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// ruleFunctions are the functions rule expressions may call in addition to govaluate's operators
+var ruleFunctions = map[string]govaluate.ExpressionFunction{
+	"contains": func(args ...interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("contains expects 2 arguments, got %d", len(args))
+		}
+		value, ok1 := args[0].(string)
+		substring, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("contains expects string arguments")
+		}
+		return strings.Contains(value, substring), nil
+	},
+}
+
+// compiledRule is an active rule with its parsed expression
+type compiledRule struct {
+	rule          domain.DecisionRule
+	expression    *govaluate.EvaluableExpression
+	adverseAction domain.AdverseActionCode
+}
+
+// RulesEngine evaluates the published decision rules persisted in the rules repository in two
+// stages. Knockout rules in force run first, in priority order (lowest first), and the first match
+// ends evaluation with its decision. Scoring rules run only when no knockout matched, also in
+// priority order, and the most restrictive decision of all matching rules wins. Every lending
+// program's rules are loaded together, but a request is only evaluated against its own program's.
+type RulesEngine struct {
+	repo        domain.RulesRepository
+	riskService domain.RiskAssessmentService
+	logger      *zap.Logger
+
+	mu    sync.RWMutex
+	rules []compiledRule
+}
+
+// NewRulesEngine creates a new rules engine; call LoadRules before evaluating
+func NewRulesEngine(repo domain.RulesRepository, riskService domain.RiskAssessmentService, logger *zap.Logger) *RulesEngine {
+	return &RulesEngine{
+		repo:        repo,
+		riskService: riskService,
+		logger:      logger,
+	}
+}
+
+// LoadRules loads and compiles the published rule versions from the repository. The current rule
+// set is kept when any version fails to compile, so a bad definition never partially applies.
+func (e *RulesEngine) LoadRules(ctx context.Context) error {
+	logger := e.logger.With(zap.String("operation", "load_rules"))
+
+	rules, err := e.repo.GetPublishedRules(ctx)
+	if err != nil {
+		logger.Error("Failed to load decision rules", zap.Error(err))
+		return fmt.Errorf("failed to load decision rules: %w", err)
+	}
+
+	compiled, err := compileRules(rules)
+	if err != nil {
+		logger.Error("Failed to compile decision rules", zap.Error(err))
+		return err
+	}
+
+	e.mu.Lock()
+	e.rules = compiled
+	e.mu.Unlock()
+
+	if len(compiled) == 0 {
+		logger.Warn("No published decision rules loaded; every application will be approved")
+	}
+	logger.Debug("Decision rules loaded", zap.Int("count", len(compiled)))
+	return nil
+}
+
+// Start reloads the published rules every interval until ctx is cancelled, so rules published
+// through another instance take effect here
+func (e *RulesEngine) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.LoadRules(ctx); err != nil {
+				e.logger.Warn("Failed to reload decision rules; keeping the current rule set", zap.Error(err))
+			}
+		}
+	}
+}
+
+// EvaluateRules implements domain.RulesEngineService
+func (e *RulesEngine) EvaluateRules(request *domain.DecisionRequest, assessment *domain.RiskAssessment) (*domain.DecisionResponse, error) {
+	return e.evaluate(e.loadedRules(), request, assessment)
+}
+
+// loadedRules returns the compiled rules last loaded from the repository
+func (e *RulesEngine) loadedRules() []compiledRule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.rules
+}
+
+// evaluate runs the rule versions of rules in force now against a request. Rules of other lending
+// programs are never evaluated, whichever rule set they are passed in.
+func (e *RulesEngine) evaluate(rules []compiledRule, request *domain.DecisionRequest, assessment *domain.RiskAssessment) (*domain.DecisionResponse, error) {
+	logger := e.logger.With(
+		zap.String("application_id", request.ApplicationID),
+		zap.String("operation", "evaluate_rules"),
+	)
+
+	response := &domain.DecisionResponse{
+		TenantID:        request.Tenant(),
+		ApplicationID:   request.ApplicationID,
+		Decision:        domain.DecisionApprove,
+		RiskScore:       assessment.OverallScore,
+		RiskCategory:    e.riskService.CategorizeRisk(assessment.OverallScore),
+		CreditScore:     request.CreditScore,
+		ApprovedAmount:  request.LoanAmount,
+		RequestedAmount: request.LoanAmount,
+		MaxAmount:       request.LoanAmount,
+		RiskFactors:     append([]domain.RiskFactor{}, assessment.RiskFactors...),
+		DecisionDate:    time.Now(),
+		RiskAssessment:  assessment,
+	}
+	reason := ""
+
+	now := time.Now()
+	tenant := request.Tenant()
+	facts := domain.RuleFacts(request, assessment)
+
+	// Knockout stage: the first matching knockout ends the decision and skips scoring
+	knockout := domain.StageResult{Stage: domain.RuleStageKnockout, Status: domain.StageStatusPassed, Decision: domain.DecisionApprove}
+	for _, compiled := range rules {
+		rule := compiled.rule
+		if rule.Tenant() != tenant || rule.Stage() != domain.RuleStageKnockout || !rule.InForce(now) {
+			continue
+		}
+		matched, err := e.match(logger, compiled, facts, response, &knockout)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			continue
+		}
+
+		logger.Info("Knockout rule matched", zap.String("rule", rule.VersionedID()))
+		response.Decision = rule.Action.Decision
+		finalizeDecision(response, request, rule.Action.Reason)
+		knockout.Status = domain.StageStatusKnockedOut
+		knockout.Decision = response.Decision
+		knockout.Reason = response.Reason
+		response.Stages = []domain.StageResult{
+			knockout,
+			{Stage: domain.RuleStageScoring, Status: domain.StageStatusSkipped},
+		}
+		return response, nil
+	}
+
+	// Scoring stage: every scoring rule in force runs and the most restrictive decision wins
+	scoring := domain.StageResult{Stage: domain.RuleStageScoring, Status: domain.StageStatusCompleted}
+	for _, compiled := range rules {
+		rule := compiled.rule
+		if rule.Tenant() != tenant || rule.Stage() != domain.RuleStageScoring || !rule.InForce(now) {
+			continue
+		}
+		matched, err := e.match(logger, compiled, facts, response, &scoring)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			continue
+		}
+
+		switch rule.Action.Type {
+		case domain.ActionDecision:
+			if rule.Action.Decision.MoreRestrictive(response.Decision) {
+				response.Decision = rule.Action.Decision
+				reason = rule.Action.Reason
+			}
+		case domain.ActionAdjustment:
+			applyAdjustments(response, request, rule.Action.Adjustments)
+		case domain.ActionRequirement:
+			response.Conditions = append(response.Conditions, rule.Action.Reason)
+		case domain.ActionFlag:
+			impact := "MEDIUM"
+			if rule.Action.RequireReview {
+				impact = "HIGH"
+			}
+			response.RiskFactors = append(response.RiskFactors, domain.RiskFactor{
+				Category:    string(rule.Category),
+				Factor:      rule.Name,
+				Impact:      impact,
+				Description: rule.Action.Reason,
+			})
+		}
+	}
+
+	finalizeDecision(response, request, reason)
+	scoring.Decision = response.Decision
+	scoring.Reason = response.Reason
+	response.Stages = []domain.StageResult{knockout, scoring}
+	logger.Debug("Decision rules evaluated",
+		zap.String("decision", string(response.Decision)),
+		zap.Strings("applied_rules", response.AppliedRules))
+	return response, nil
+}
+
+// match evaluates a rule in force against the facts, recording it in the response and its stage,
+// and records the hit when the rule matches
+func (e *RulesEngine) match(
+	logger *zap.Logger,
+	compiled compiledRule,
+	facts map[string]interface{},
+	response *domain.DecisionResponse,
+	stage *domain.StageResult,
+) (bool, error) {
+	rule := compiled.rule
+	response.RuleVersions = append(response.RuleVersions, rule.VersionedID())
+	stage.RulesEvaluated = append(stage.RulesEvaluated, rule.VersionedID())
+
+	result, err := compiled.expression.Evaluate(facts)
+	if err != nil {
+		logger.Error("Failed to evaluate decision rule", zap.String("rule", rule.VersionedID()), zap.Error(err))
+		return false, fmt.Errorf("failed to evaluate rule %s: %w", rule.VersionedID(), err)
+	}
+	matched, ok := result.(bool)
+	if !ok {
+		return false, fmt.Errorf("rule %s evaluated to %v instead of a boolean", rule.VersionedID(), result)
+	}
+	if !matched {
+		return false, nil
+	}
+
+	response.AppliedRules = append(response.AppliedRules, rule.ID)
+	response.RuleHits = append(response.RuleHits, domain.RuleHit{
+		RuleID:        rule.ID,
+		Version:       rule.Version,
+		Name:          rule.Name,
+		Category:      rule.Category,
+		Action:        rule.Action.Type,
+		Decision:      rule.Action.Decision,
+		Knockout:      rule.Knockout,
+		Reason:        rule.Action.Reason,
+		AdverseAction: compiled.adverseAction,
+	})
+	stage.RuleHits = append(stage.RuleHits, rule.ID)
+	if rule.Action.RequireReview {
+		response.ReviewRequired = true
+	}
+	return true, nil
+}
+
+// GetActiveRules implements domain.RulesEngineService, returning the rule versions of every lending
+// program in force now
+func (e *RulesEngine) GetActiveRules() ([]domain.DecisionRule, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	now := time.Now()
+	rules := make([]domain.DecisionRule, 0, len(e.rules))
+	for _, compiled := range e.rules {
+		if compiled.rule.InForce(now) {
+			rules = append(rules, compiled.rule)
+		}
+	}
+	return rules, nil
+}
+
+// compileRules compiles a rule set and orders it by priority, failing on the first invalid rule
+func compileRules(rules []domain.DecisionRule) ([]compiledRule, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		expression, err := compileRule(&rule)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile decision rule %s: %w", rule.VersionedID(), err)
+		}
+		adverseAction := rule.Action.AdverseActionCode
+		if adverseAction == "" {
+			adverseAction = domain.AdverseActionForFacts(expression.Vars())
+		}
+		compiled = append(compiled, compiledRule{rule: rule, expression: expression, adverseAction: adverseAction})
+	}
+
+	sort.SliceStable(compiled, func(i, j int) bool {
+		if compiled[i].rule.Priority != compiled[j].rule.Priority {
+			return compiled[i].rule.Priority < compiled[j].rule.Priority
+		}
+		return compiled[i].rule.ID < compiled[j].rule.ID
+	})
+	return compiled, nil
+}
+
+// compileRule validates a rule and parses its expression, rejecting references to unknown facts
+func compileRule(rule *domain.DecisionRule) (*govaluate.EvaluableExpression, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	source, err := rule.RuleExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	expression, err := govaluate.NewEvaluableExpressionWithFunctions(source, ruleFunctions)
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: invalid expression %q: %w", rule.ID, source, err)
+	}
+
+	for _, name := range expression.Vars() {
+		if !domain.IsRuleFact(name) {
+			return nil, fmt.Errorf("rule %s: unknown field %q", rule.ID, name)
+		}
+	}
+	return expression, nil
+}
+
+// applyAdjustments lowers the approvable amount by the caps of an adjustment rule
+func applyAdjustments(response *domain.DecisionResponse, request *domain.DecisionRequest, adjustments map[string]interface{}) {
+	if max, ok := adjustments[domain.AdjustmentMaxAmount].(float64); ok {
+		response.MaxAmount = math.Min(response.MaxAmount, max)
+	}
+	if multiple, ok := adjustments[domain.AdjustmentMaxLoanToIncome].(float64); ok {
+		response.MaxAmount = math.Min(response.MaxAmount, math.Floor(request.AnnualIncome*multiple))
+	}
+}
+
+// finalizeDecision settles the approved amount and reason once rule evaluation ends. Approvals
+// above the adjusted maximum become conditional approvals for the maximum.
+func finalizeDecision(response *domain.DecisionResponse, request *domain.DecisionRequest, reason string) {
+	switch response.Decision {
+	case domain.DecisionDeny:
+		response.ApprovedAmount = 0
+	case domain.DecisionApprove, domain.DecisionConditional:
+		if response.MaxAmount < request.LoanAmount {
+			response.Decision = domain.DecisionConditional
+			response.ApprovedAmount = response.MaxAmount
+			if reason == "" {
+				reason = fmt.Sprintf("Approved for a reduced amount of $%.0f", response.MaxAmount)
+			}
+		}
+		if response.ReviewRequired && response.Decision == domain.DecisionApprove {
+			response.Decision = domain.DecisionConditional
+		}
+	}
+
+	if reason == "" {
+		reason = "All decision rules passed"
+	}
+	response.DecisionReason = reason
+	response.Reason = reason
+}
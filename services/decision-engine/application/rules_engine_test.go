@@ -0,0 +1,217 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+)
+
+// stubRulesRepository serves a fixed set of published rules
+type stubRulesRepository struct {
+	domain.RulesRepository
+	rules []domain.DecisionRule
+}
+
+func (r *stubRulesRepository) GetPublishedRules(ctx context.Context) ([]domain.DecisionRule, error) {
+	return r.rules, nil
+}
+
+// publishedRule returns a published rule version in force
+func publishedRule(id string, priority int, knockout bool, expression string, action domain.RuleAction) domain.DecisionRule {
+	publishedAt := time.Now().Add(-time.Hour)
+	return domain.DecisionRule{
+		ID:          id,
+		Version:     "1.0.0",
+		Status:      domain.RuleStatusPublished,
+		Name:        id,
+		Category:    domain.RuleCategoryCredit,
+		Priority:    priority,
+		Expression:  expression,
+		Action:      action,
+		Knockout:    knockout,
+		PublishedAt: &publishedAt,
+	}
+}
+
+func deny(reason string) domain.RuleAction {
+	return domain.RuleAction{Type: domain.ActionDecision, Decision: domain.DecisionDeny, Reason: reason}
+}
+
+func TestRulesEngine_EvaluateRules(t *testing.T) {
+	expired := publishedRule("expired_deny", 1, true, "credit_score < 800", deny("Expired rule"))
+	expiredAt := time.Now().Add(-time.Minute)
+	expired.ExpiresAt = &expiredAt
+
+	otherTenant := publishedRule("other_program_deny", 1, true, "credit_score < 800", deny("Other program"))
+	otherTenant.TenantID = "partner"
+
+	tests := []struct {
+		name         string
+		rules        []domain.DecisionRule
+		creditScore  int
+		wantDecision domain.DecisionType
+		wantReason   string
+		wantApproved float64
+		wantApplied  []string
+		wantVersions []string
+		wantStages   []domain.StageStatus
+	}{
+		{
+			name:         "no rules approves",
+			creditScore:  720,
+			wantDecision: domain.DecisionApprove,
+			wantReason:   "All decision rules passed",
+			wantApproved: 10000,
+			wantStages:   []domain.StageStatus{domain.StageStatusPassed, domain.StageStatusCompleted},
+		},
+		{
+			name: "knockout short-circuits the remaining rules",
+			rules: []domain.DecisionRule{
+				publishedRule("min_score", 10, true, "credit_score < 600", deny("Credit score below minimum")),
+				publishedRule("later_knockout", 20, true, "credit_score < 650", deny("Later knockout")),
+				publishedRule("review_low_score", 30, false, "credit_score < 700",
+					domain.RuleAction{Type: domain.ActionDecision, Decision: domain.DecisionManualReview, Reason: "Review"}),
+			},
+			creditScore:  550,
+			wantDecision: domain.DecisionDeny,
+			wantReason:   "Credit score below minimum",
+			wantApplied:  []string{"min_score"},
+			wantVersions: []string{"min_score@1.0.0"},
+			wantStages:   []domain.StageStatus{domain.StageStatusKnockedOut, domain.StageStatusSkipped},
+		},
+		{
+			name: "knockouts run in priority order",
+			rules: []domain.DecisionRule{
+				publishedRule("second", 20, true, "credit_score < 650", deny("Second knockout")),
+				publishedRule("first", 10, true, "credit_score < 650", deny("First knockout")),
+			},
+			creditScore:  600,
+			wantDecision: domain.DecisionDeny,
+			wantReason:   "First knockout",
+			wantApplied:  []string{"first"},
+			wantVersions: []string{"first@1.0.0"},
+			wantStages:   []domain.StageStatus{domain.StageStatusKnockedOut, domain.StageStatusSkipped},
+		},
+		{
+			name: "most restrictive scoring decision wins",
+			rules: []domain.DecisionRule{
+				publishedRule("review_low_score", 10, false, "credit_score < 700",
+					domain.RuleAction{Type: domain.ActionDecision, Decision: domain.DecisionManualReview, Reason: "Manual review"}),
+				publishedRule("deny_high_dti", 20, false, "loan_amount > 5000", deny("Loan too large")),
+			},
+			creditScore:  680,
+			wantDecision: domain.DecisionDeny,
+			wantReason:   "Loan too large",
+			wantApplied:  []string{"review_low_score", "deny_high_dti"},
+			wantVersions: []string{"review_low_score@1.0.0", "deny_high_dti@1.0.0"},
+			wantStages:   []domain.StageStatus{domain.StageStatusPassed, domain.StageStatusCompleted},
+		},
+		{
+			name: "amount cap makes the approval conditional",
+			rules: []domain.DecisionRule{
+				publishedRule("cap_amount", 10, false, "credit_score < 750",
+					domain.RuleAction{Type: domain.ActionAdjustment, Adjustments: map[string]interface{}{domain.AdjustmentMaxAmount: 8000.0}}),
+			},
+			creditScore:  720,
+			wantDecision: domain.DecisionConditional,
+			wantReason:   "Approved for a reduced amount of $8000",
+			wantApproved: 8000,
+			wantApplied:  []string{"cap_amount"},
+			wantVersions: []string{"cap_amount@1.0.0"},
+			wantStages:   []domain.StageStatus{domain.StageStatusPassed, domain.StageStatusCompleted},
+		},
+		{
+			name: "flag requiring review makes the approval conditional",
+			rules: []domain.DecisionRule{
+				publishedRule("thin_file", 10, false, "credit_score < 750",
+					domain.RuleAction{Type: domain.ActionFlag, Reason: "Thin credit file", RequireReview: true}),
+			},
+			creditScore:  720,
+			wantDecision: domain.DecisionConditional,
+			wantReason:   "All decision rules passed",
+			wantApproved: 10000,
+			wantApplied:  []string{"thin_file"},
+			wantVersions: []string{"thin_file@1.0.0"},
+			wantStages:   []domain.StageStatus{domain.StageStatusPassed, domain.StageStatusCompleted},
+		},
+		{
+			name:         "rules not in force and of other programs are not evaluated",
+			rules:        []domain.DecisionRule{expired, otherTenant},
+			creditScore:  720,
+			wantDecision: domain.DecisionApprove,
+			wantReason:   "All decision rules passed",
+			wantApproved: 10000,
+			wantStages:   []domain.StageStatus{domain.StageStatusPassed, domain.StageStatusCompleted},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zap.NewNop()
+			engine := NewRulesEngine(&stubRulesRepository{rules: tt.rules}, NewRiskAssessmentService(logger, nil), logger)
+			require.NoError(t, engine.LoadRules(context.Background()))
+
+			request := &domain.DecisionRequest{
+				ApplicationID: "app-1",
+				UserID:        "user-1",
+				LoanAmount:    10000,
+				AnnualIncome:  60000,
+				MonthlyIncome: 5000,
+				CreditScore:   tt.creditScore,
+				RequestedTerm: 36,
+			}
+			response, err := engine.EvaluateRules(request, &domain.RiskAssessment{OverallScore: 0.2})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantDecision, response.Decision)
+			assert.Equal(t, tt.wantReason, response.Reason)
+			assert.Equal(t, tt.wantApproved, response.ApprovedAmount)
+			assert.Equal(t, tt.wantApplied, response.AppliedRules)
+			assert.Equal(t, tt.wantVersions, response.RuleVersions)
+			assert.Equal(t, domain.RiskLow, response.RiskCategory)
+			assert.Equal(t, tt.creditScore, response.CreditScore)
+
+			require.Len(t, response.Stages, len(tt.wantStages))
+			for i, status := range tt.wantStages {
+				assert.Equal(t, status, response.Stages[i].Status)
+			}
+		})
+	}
+}
+
+func TestRulesEngine_LoadRulesKeepsRuleSetOnInvalidRule(t *testing.T) {
+	logger := zap.NewNop()
+	repo := &stubRulesRepository{rules: []domain.DecisionRule{
+		publishedRule("min_score", 10, true, "credit_score < 600", deny("Credit score below minimum")),
+	}}
+	engine := NewRulesEngine(repo, NewRiskAssessmentService(logger, nil), logger)
+	require.NoError(t, engine.LoadRules(context.Background()))
+
+	tests := []struct {
+		name string
+		rule domain.DecisionRule
+	}{
+		{name: "unknown fact", rule: publishedRule("unknown_fact", 10, true, "shoe_size > 10", deny("Unknown"))},
+		{name: "invalid expression", rule: publishedRule("bad_syntax", 10, true, "credit_score <", deny("Bad"))},
+		{name: "knockout that is not a decision", rule: publishedRule("flag_knockout", 10, true, "credit_score < 600",
+			domain.RuleAction{Type: domain.ActionFlag, Reason: "Flag"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo.rules = []domain.DecisionRule{tt.rule}
+			assert.Error(t, engine.LoadRules(context.Background()))
+
+			rules, err := engine.GetActiveRules()
+			require.NoError(t, err)
+			require.Len(t, rules, 1)
+			assert.Equal(t, "min_score", rules[0].ID)
+		})
+	}
+}
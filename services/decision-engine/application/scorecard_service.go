@@ -0,0 +1,285 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// ScorecardService manages scorecards and scores applications with the active one. The active
+// scorecard is cached and reloaded periodically, so activations made through another instance
+// take effect here.
+type ScorecardService struct {
+	repo   domain.ScorecardRepository
+	raw    *RiskAssessmentService // scorecard-free assessment used to test-score applications
+	logger *zap.Logger
+
+	mu     sync.RWMutex
+	active *domain.Scorecard
+}
+
+// NewScorecardService creates a new scorecard service
+func NewScorecardService(repo domain.ScorecardRepository, logger *zap.Logger) *ScorecardService {
+	return &ScorecardService{
+		repo:   repo,
+		raw:    NewRiskAssessmentService(logger, nil),
+		logger: logger,
+	}
+}
+
+// LoadActive loads the active scorecard from the repository
+func (s *ScorecardService) LoadActive(ctx context.Context) error {
+	scorecard, err := s.repo.GetActiveScorecard(ctx)
+	if err != nil {
+		s.logger.Error("Failed to load active scorecard", zap.Error(err))
+		return fmt.Errorf("failed to load active scorecard: %w", err)
+	}
+
+	s.mu.Lock()
+	s.active = scorecard
+	s.mu.Unlock()
+
+	if scorecard != nil {
+		s.logger.Debug("Active scorecard loaded", zap.String("scorecard_id", scorecard.ID))
+	}
+	return nil
+}
+
+// Start reloads the active scorecard every interval until ctx is cancelled
+func (s *ScorecardService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.LoadActive(ctx); err != nil {
+				s.logger.Warn("Failed to reload active scorecard; keeping the current scorecard", zap.Error(err))
+			}
+		}
+	}
+}
+
+// ScoreApplication implements domain.ApplicationScorer
+func (s *ScorecardService) ScoreApplication(request *domain.DecisionRequest, assessment *domain.RiskAssessment) (*domain.ScorecardResult, error) {
+	s.mu.RLock()
+	scorecard := s.active
+	s.mu.RUnlock()
+
+	if scorecard == nil {
+		return nil, nil
+	}
+	return scorecard.Score(domain.RuleFacts(request, assessment)), nil
+}
+
+// TestScore scores an application with any scorecard, without blending it into a decision
+func (s *ScorecardService) TestScore(ctx context.Context, id string, request *domain.DecisionRequest) (*domain.ScorecardResult, error) {
+	scorecard, err := s.GetScorecard(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	assessment, err := s.raw.AssessRisk(request)
+	if err != nil {
+		return nil, err
+	}
+	return scorecard.Score(domain.RuleFacts(request, assessment)), nil
+}
+
+// CreateScorecard creates a draft scorecard
+func (s *ScorecardService) CreateScorecard(ctx context.Context, req *domain.ScorecardRequest, performedBy string) (*domain.Scorecard, error) {
+	now := time.Now().UTC()
+	scorecard := &domain.Scorecard{
+		ID:        req.ID,
+		Status:    domain.ScorecardStatusDraft,
+		CreatedBy: performedBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	req.Apply(scorecard)
+	if err := scorecard.Validate(); err != nil {
+		return nil, scorecardRequestError(err)
+	}
+
+	if err := s.repo.CreateScorecard(ctx, scorecard); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, scorecardConflictError(fmt.Sprintf("Scorecard %s already exists", req.ID))
+		}
+		return nil, s.databaseError(err)
+	}
+
+	s.logger.Info("Scorecard created", zap.String("scorecard_id", scorecard.ID), zap.String("performed_by", performedBy))
+	return scorecard, nil
+}
+
+// ListScorecards returns the scorecards with a status, or every scorecard when status is empty
+func (s *ScorecardService) ListScorecards(ctx context.Context, status domain.ScorecardStatus) ([]domain.Scorecard, error) {
+	if status != "" && !status.IsValid() {
+		return nil, scorecardRequestError(fmt.Errorf("unknown scorecard status %q", status))
+	}
+
+	scorecards, err := s.repo.ListScorecards(ctx, status)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	return scorecards, nil
+}
+
+// GetScorecard returns a scorecard
+func (s *ScorecardService) GetScorecard(ctx context.Context, id string) (*domain.Scorecard, error) {
+	scorecard, err := s.repo.GetScorecard(ctx, id)
+	if err != nil {
+		return nil, s.repositoryError(id, err)
+	}
+	return scorecard, nil
+}
+
+// UpdateScorecard replaces the definition of a draft scorecard
+func (s *ScorecardService) UpdateScorecard(ctx context.Context, id string, req *domain.ScorecardRequest, performedBy string) (*domain.Scorecard, error) {
+	scorecard, err := s.getDraft(ctx, id, "edited")
+	if err != nil {
+		return nil, err
+	}
+	req.Apply(scorecard)
+	if err := scorecard.Validate(); err != nil {
+		return nil, scorecardRequestError(err)
+	}
+
+	scorecard.UpdatedAt = time.Now().UTC()
+	if err := s.repo.UpdateDraft(ctx, scorecard); err != nil {
+		return nil, s.repositoryError(id, err)
+	}
+
+	s.logger.Info("Scorecard updated", zap.String("scorecard_id", id), zap.String("performed_by", performedBy))
+	return scorecard, nil
+}
+
+// DeleteScorecard deletes a draft scorecard
+func (s *ScorecardService) DeleteScorecard(ctx context.Context, id, performedBy string) error {
+	if _, err := s.getDraft(ctx, id, "deleted"); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteDraft(ctx, id); err != nil {
+		return s.repositoryError(id, err)
+	}
+
+	s.logger.Info("Scorecard deleted", zap.String("scorecard_id", id), zap.String("performed_by", performedBy))
+	return nil
+}
+
+// ActivateScorecard makes a draft the active scorecard, retiring the one it replaces
+func (s *ScorecardService) ActivateScorecard(ctx context.Context, id, performedBy string) (*domain.Scorecard, error) {
+	scorecard, err := s.getDraft(ctx, id, "activated")
+	if err != nil {
+		return nil, err
+	}
+	if err := scorecard.Validate(); err != nil {
+		return nil, scorecardRequestError(err)
+	}
+
+	now := time.Now().UTC()
+	scorecard.Status = domain.ScorecardStatusActive
+	scorecard.ActivatedBy = performedBy
+	scorecard.ActivatedAt = &now
+	scorecard.UpdatedAt = now
+	if err := s.repo.ActivateScorecard(ctx, scorecard); err != nil {
+		return nil, s.repositoryError(id, err)
+	}
+	s.reloadActive(ctx)
+
+	s.logger.Info("Scorecard activated", zap.String("scorecard_id", id), zap.String("performed_by", performedBy))
+	return scorecard, nil
+}
+
+// RetireScorecard retires the active scorecard; applications are then scored without one
+func (s *ScorecardService) RetireScorecard(ctx context.Context, id, performedBy string) (*domain.Scorecard, error) {
+	scorecard, err := s.GetScorecard(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if scorecard.Status != domain.ScorecardStatusActive {
+		return nil, scorecardConflictError(fmt.Sprintf("Scorecard %s is %s; only the active scorecard can be retired", id, scorecard.Status))
+	}
+
+	now := time.Now().UTC()
+	if err := s.repo.RetireScorecard(ctx, id, now); err != nil {
+		return nil, s.repositoryError(id, err)
+	}
+	scorecard.Status = domain.ScorecardStatusRetired
+	scorecard.RetiredAt = &now
+	scorecard.UpdatedAt = now
+	s.reloadActive(ctx)
+
+	s.logger.Info("Scorecard retired", zap.String("scorecard_id", id), zap.String("performed_by", performedBy))
+	return scorecard, nil
+}
+
+// getDraft returns a scorecard that is still a draft
+func (s *ScorecardService) getDraft(ctx context.Context, id, action string) (*domain.Scorecard, error) {
+	scorecard, err := s.GetScorecard(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if scorecard.Status != domain.ScorecardStatusDraft {
+		return nil, scorecardConflictError(fmt.Sprintf("Scorecard %s is %s; only drafts can be %s", id, scorecard.Status, action))
+	}
+	return scorecard, nil
+}
+
+// reloadActive applies an activation or retirement to the next decision on this instance; a
+// failure leaves the periodic reload to pick it up
+func (s *ScorecardService) reloadActive(ctx context.Context) {
+	if err := s.LoadActive(ctx); err != nil {
+		s.logger.Warn("Failed to reload active scorecard after change", zap.Error(err))
+	}
+}
+
+func (s *ScorecardService) repositoryError(id string, err error) error {
+	if isNotFound(err) {
+		return &domain.DecisionError{
+			Code:        domain.ERROR_SCORECARD_NOT_FOUND,
+			Message:     "Scorecard not found",
+			Description: fmt.Sprintf("No scorecard found for %s", id),
+			HTTPStatus:  404,
+		}
+	}
+	return s.databaseError(err)
+}
+
+func (s *ScorecardService) databaseError(err error) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_DATABASE_ERROR,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
+
+func scorecardRequestError(err error) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_INVALID_REQUEST,
+		Message:     "Invalid scorecard",
+		Description: err.Error(),
+		HTTPStatus:  400,
+	}
+}
+
+func scorecardConflictError(description string) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_SCORECARD_CONFLICT,
+		Message:     "Scorecard state conflict",
+		Description: description,
+		HTTPStatus:  409,
+	}
+}
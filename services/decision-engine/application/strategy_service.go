@@ -0,0 +1,418 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// strategyRefreshInterval is how long the active strategy is cached before it is reloaded, so
+// changes made through other instances take effect
+const strategyRefreshInterval = time.Minute
+
+// activeChallenger is the cached active strategy with its challenger rule set compiled on top of
+// the champion rules it was built from
+type activeChallenger struct {
+	strategy *domain.DecisionStrategy // nil when no strategy is active
+	champion string                   // champion rule versions the challenger was built on
+	rules    []compiledRule
+	loadedAt time.Time
+}
+
+// StrategyService manages champion/challenger strategies and shadows decisions with the active
+// challenger. The champion is the published rule set in force; the challenger is that rule set
+// with the strategy's changes applied, and is rebuilt whenever the champion changes.
+type StrategyService struct {
+	repo      domain.StrategyRepository
+	rulesRepo domain.RulesRepository
+	engine    *RulesEngine
+	logger    *zap.Logger
+
+	mu     sync.Mutex
+	active *activeChallenger
+}
+
+// NewStrategyService creates a new strategy service
+func NewStrategyService(repo domain.StrategyRepository, rulesRepo domain.RulesRepository, engine *RulesEngine, logger *zap.Logger) *StrategyService {
+	return &StrategyService{
+		repo:      repo,
+		rulesRepo: rulesRepo,
+		engine:    engine,
+		logger:    logger,
+	}
+}
+
+// EvaluateChallenger implements domain.ChallengerService
+func (s *StrategyService) EvaluateChallenger(ctx context.Context, request *domain.DecisionRequest, assessment *domain.RiskAssessment) (*domain.DecisionStrategy, *domain.DecisionResponse, error) {
+	active, err := s.activeChallenger(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if active.strategy == nil || !active.strategy.InChallengerTraffic(request.ApplicationID) {
+		return nil, nil, nil
+	}
+
+	challenger, err := s.engine.evaluate(active.rules, request, assessment)
+	if err != nil {
+		return nil, nil, fmt.Errorf("challenger rules failed: %w", err)
+	}
+	strategy := *active.strategy
+	return &strategy, challenger, nil
+}
+
+// RecordChallengerDecision implements domain.ChallengerService
+func (s *StrategyService) RecordChallengerDecision(ctx context.Context, strategy *domain.DecisionStrategy, champion, challenger *domain.DecisionResponse) error {
+	pd := domain.ProbabilityOfDefault[champion.RiskCategory]
+	record := &domain.ChallengerDecision{
+		StrategyID:             strategy.ID,
+		ApplicationID:          champion.ApplicationID,
+		RiskCategory:           champion.RiskCategory,
+		ChampionDecision:       champion.Decision,
+		ChallengerDecision:     challenger.Decision,
+		ChampionAmount:         approvedAmount(champion),
+		ChallengerAmount:       approvedAmount(challenger),
+		ChampionExpectedLoss:   expectedLoss(champion, pd, domain.DefaultLossGivenDefault),
+		ChallengerExpectedLoss: expectedLoss(challenger, pd, domain.DefaultLossGivenDefault),
+		ChallengerReason:       challenger.DecisionReason,
+		ChampionRuleVersions:   champion.RuleVersions,
+		ChallengerRuleVersions: challenger.RuleVersions,
+		CreatedAt:              time.Now().UTC(),
+	}
+	return s.repo.SaveChallengerDecision(ctx, record)
+}
+
+// CreateStrategy creates a paused strategy after checking its challenger rule set compiles
+func (s *StrategyService) CreateStrategy(ctx context.Context, req *domain.StrategyRequest, performedBy string) (*domain.DecisionStrategy, error) {
+	if err := req.Validate(); err != nil {
+		return nil, strategyRequestError(err)
+	}
+	if _, err := s.buildChallenger(ctx, &req.Challenger); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetStrategy(ctx, req.ID); err == nil {
+		return nil, strategyConflictError(fmt.Sprintf("Strategy %s already exists", req.ID))
+	} else if !isNotFound(err) {
+		return nil, s.databaseError(err)
+	}
+
+	now := time.Now().UTC()
+	strategy := &domain.DecisionStrategy{
+		ID:             req.ID,
+		Name:           req.Name,
+		Description:    req.Description,
+		Challenger:     req.Challenger,
+		TrafficPercent: req.TrafficPercent,
+		Status:         domain.StrategyStatusPaused,
+		CreatedBy:      performedBy,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.repo.CreateStrategy(ctx, strategy); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, strategyConflictError(fmt.Sprintf("Strategy %s already exists", req.ID))
+		}
+		return nil, s.databaseError(err)
+	}
+
+	s.logger.Info("Strategy created", zap.String("strategy_id", strategy.ID), zap.String("performed_by", performedBy))
+	return strategy, nil
+}
+
+// ListStrategies returns the strategies with a status, or every strategy when status is empty
+func (s *StrategyService) ListStrategies(ctx context.Context, status domain.StrategyStatus) ([]domain.DecisionStrategy, error) {
+	if status != "" && !status.IsValid() {
+		return nil, strategyRequestError(fmt.Errorf("unknown strategy status %q", status))
+	}
+
+	strategies, err := s.repo.ListStrategies(ctx, status)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	return strategies, nil
+}
+
+// GetStrategy returns a strategy
+func (s *StrategyService) GetStrategy(ctx context.Context, id string) (*domain.DecisionStrategy, error) {
+	strategy, err := s.repo.GetStrategy(ctx, id)
+	if err != nil {
+		return nil, s.repositoryError(id, err)
+	}
+	return strategy, nil
+}
+
+// SetTraffic changes the share of decisions a strategy's challenger shadows
+func (s *StrategyService) SetTraffic(ctx context.Context, id string, percent int, performedBy string) (*domain.DecisionStrategy, error) {
+	if err := domain.ValidateTrafficPercent(percent); err != nil {
+		return nil, strategyRequestError(err)
+	}
+
+	strategy, err := s.getOpenStrategy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	strategy.TrafficPercent = percent
+
+	if err := s.updateStrategy(ctx, strategy); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Strategy traffic changed",
+		zap.String("strategy_id", id),
+		zap.Int("traffic_percent", percent),
+		zap.String("performed_by", performedBy))
+	return strategy, nil
+}
+
+// ActivateStrategy starts shadowing decisions with a strategy's challenger
+func (s *StrategyService) ActivateStrategy(ctx context.Context, id, performedBy string) (*domain.DecisionStrategy, error) {
+	strategy, err := s.getOpenStrategy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if strategy.Status == domain.StrategyStatusActive {
+		return strategy, nil
+	}
+	if _, err := s.buildChallenger(ctx, &strategy.Challenger); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	strategy.Status = domain.StrategyStatusActive
+	if strategy.StartedAt == nil {
+		strategy.StartedAt = &now
+	}
+	if err := s.updateStrategy(ctx, strategy); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Strategy activated", zap.String("strategy_id", id), zap.String("performed_by", performedBy))
+	return strategy, nil
+}
+
+// PauseStrategy stops shadowing decisions until the strategy is activated again
+func (s *StrategyService) PauseStrategy(ctx context.Context, id, performedBy string) (*domain.DecisionStrategy, error) {
+	strategy, err := s.GetStrategy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if strategy.Status != domain.StrategyStatusActive {
+		return nil, strategyConflictError(fmt.Sprintf("Strategy %s is %s; only active strategies can be paused", id, strategy.Status))
+	}
+
+	strategy.Status = domain.StrategyStatusPaused
+	if err := s.updateStrategy(ctx, strategy); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Strategy paused", zap.String("strategy_id", id), zap.String("performed_by", performedBy))
+	return strategy, nil
+}
+
+// EndStrategy ends a strategy for good; its recorded decisions remain available for reports
+func (s *StrategyService) EndStrategy(ctx context.Context, id, performedBy string) (*domain.DecisionStrategy, error) {
+	strategy, err := s.getOpenStrategy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	strategy.Status = domain.StrategyStatusEnded
+	strategy.EndedAt = &now
+	if err := s.updateStrategy(ctx, strategy); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Strategy ended", zap.String("strategy_id", id), zap.String("performed_by", performedBy))
+	return strategy, nil
+}
+
+// GetReport compares the champion and challenger decisions recorded under a strategy, optionally
+// within a time window
+func (s *StrategyService) GetReport(ctx context.Context, id string, from, to *time.Time) (*domain.StrategyReport, error) {
+	strategy, err := s.GetStrategy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes, err := s.repo.GetChallengerOutcomes(ctx, id, from, to)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	disagreements, err := s.repo.GetChallengerDisagreements(ctx, id, from, to, domain.MaxStrategyDisagreements)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	report := &domain.StrategyReport{
+		Strategy:        *strategy,
+		From:            from,
+		To:              to,
+		Champion:        domain.NewSimulationMetrics(),
+		Challenger:      domain.NewSimulationMetrics(),
+		DecisionChanges: make(map[string]int),
+		Disagreements:   disagreements,
+		GeneratedAt:     time.Now().UTC(),
+	}
+	for _, outcome := range outcomes {
+		report.DecisionsCompared += outcome.Count
+		report.AgreementCount += outcome.Agreed
+		report.Champion.Add(outcome.ChampionDecision, outcome.Count, outcome.ChampionAmount, outcome.ChampionExpectedLoss)
+		report.Challenger.Add(outcome.ChallengerDecision, outcome.Count, outcome.ChallengerAmount, outcome.ChallengerExpectedLoss)
+		if outcome.ChampionDecision != outcome.ChallengerDecision {
+			report.DecisionChanges[domain.DecisionChangeKey(outcome.ChampionDecision, outcome.ChallengerDecision)] += outcome.Count
+		}
+	}
+
+	report.Champion.Finalize(report.DecisionsCompared)
+	report.Challenger.Finalize(report.DecisionsCompared)
+	report.Delta = domain.NewSimulationDelta(report.Champion, report.Challenger)
+	if report.DecisionsCompared > 0 {
+		report.AgreementRate = float64(report.AgreementCount) / float64(report.DecisionsCompared) * 100
+	}
+	return report, nil
+}
+
+// activeChallenger returns the cached active strategy, reloading it when it is stale or the
+// champion rules have changed since the challenger was built
+func (s *StrategyService) activeChallenger(ctx context.Context) (*activeChallenger, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	champion, err := s.engine.GetActiveRules()
+	if err != nil {
+		return nil, err
+	}
+	championKey := ruleSetKey(champion)
+	if s.active != nil && s.active.champion == championKey && time.Since(s.active.loadedAt) < strategyRefreshInterval {
+		return s.active, nil
+	}
+
+	active := &activeChallenger{champion: championKey, loadedAt: time.Now()}
+	s.active = active
+
+	strategy, err := s.repo.GetActiveStrategy(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load active strategy: %w", err)
+	}
+	if strategy == nil {
+		return active, nil
+	}
+
+	rules, err := s.buildChallenger(ctx, &strategy.Challenger)
+	if err != nil {
+		// Leave the challenger off until the next reload rather than failing decisions
+		return nil, fmt.Errorf("failed to build challenger of strategy %s: %w", strategy.ID, err)
+	}
+	active.strategy = strategy
+	active.rules = rules
+	return active, nil
+}
+
+// buildChallenger compiles a challenger rule set on top of the champion rules in force. Strategies
+// are platform-wide: the champion rules of every lending program are included, each still applied
+// only to its own program's requests, and inline challenger rules belong to the default program.
+func (s *StrategyService) buildChallenger(ctx context.Context, changes *domain.RuleSetChanges) ([]compiledRule, error) {
+	champion, err := s.engine.GetActiveRules()
+	if err != nil {
+		return nil, err
+	}
+	rules, err := proposeRuleSet(ctx, s.rulesRepo, "", champion, changes)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := compileRules(rules)
+	if err != nil {
+		return nil, invalidRuleError(err)
+	}
+	return compiled, nil
+}
+
+// getOpenStrategy returns a strategy that has not ended
+func (s *StrategyService) getOpenStrategy(ctx context.Context, id string) (*domain.DecisionStrategy, error) {
+	strategy, err := s.GetStrategy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if strategy.Status == domain.StrategyStatusEnded {
+		return nil, strategyConflictError(fmt.Sprintf("Strategy %s has ended and can no longer be changed", id))
+	}
+	return strategy, nil
+}
+
+// updateStrategy saves a strategy and drops the cached active strategy so the change applies to
+// the next decision on this instance
+func (s *StrategyService) updateStrategy(ctx context.Context, strategy *domain.DecisionStrategy) error {
+	strategy.UpdatedAt = time.Now().UTC()
+	if err := s.repo.UpdateStrategy(ctx, strategy); err != nil {
+		if strings.Contains(err.Error(), "already active") {
+			return strategyConflictError("Another strategy is already active; pause or end it first")
+		}
+		return s.repositoryError(strategy.ID, err)
+	}
+
+	s.mu.Lock()
+	s.active = nil
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *StrategyService) repositoryError(id string, err error) error {
+	if isNotFound(err) {
+		return &domain.DecisionError{
+			Code:        domain.ERROR_STRATEGY_NOT_FOUND,
+			Message:     "Strategy not found",
+			Description: fmt.Sprintf("No decision strategy found for %s", id),
+			HTTPStatus:  404,
+		}
+	}
+	return s.databaseError(err)
+}
+
+func (s *StrategyService) databaseError(err error) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_DATABASE_ERROR,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
+
+// ruleSetKey identifies a rule set by its rule versions
+func ruleSetKey(rules []domain.DecisionRule) string {
+	ids := make([]string, 0, len(rules))
+	for i := range rules {
+		ids = append(ids, rules[i].VersionedID())
+	}
+	return strings.Join(ids, ",")
+}
+
+// approvedAmount is the amount a decision lends, zero unless it is an approval
+func approvedAmount(decision *domain.DecisionResponse) float64 {
+	if !decision.Decision.IsApproval() {
+		return 0
+	}
+	return decision.ApprovedAmount
+}
+
+func strategyRequestError(err error) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_INVALID_REQUEST,
+		Message:     "Invalid decision strategy",
+		Description: err.Error(),
+		HTTPStatus:  400,
+	}
+}
+
+func strategyConflictError(description string) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_STRATEGY_CONFLICT,
+		Message:     "Strategy state conflict",
+		Description: description,
+		HTTPStatus:  409,
+	}
+}
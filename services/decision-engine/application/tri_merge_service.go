@@ -0,0 +1,338 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// TriMergeService pulls an applicant's report from every bureau in parallel and merges them.
+// A bureau that fails or times out leaves a partial merge; the merge fails only when no bureau
+// returns a report.
+type TriMergeService struct {
+	bureaus domain.CreditBureauClient
+	repo    domain.CreditReportRepository
+	policy  domain.ScoreSelectionPolicy
+	logger  *zap.Logger
+}
+
+// NewTriMergeService creates a new tri-merge service. The score policy defaults to the middle score.
+func NewTriMergeService(bureaus domain.CreditBureauClient, repo domain.CreditReportRepository, policy domain.ScoreSelectionPolicy, logger *zap.Logger) *TriMergeService {
+	if !policy.IsValid() {
+		policy = domain.ScorePolicyMiddle
+	}
+	return &TriMergeService{
+		bureaus: bureaus,
+		repo:    repo,
+		policy:  policy,
+		logger:  logger,
+	}
+}
+
+// bureauPull is the outcome of one bureau pull
+type bureauPull struct {
+	report *domain.BureauReport
+	result domain.BureauPullResult
+	err    error
+}
+
+// TriMerge pulls, merges and persists the reports of every bureau for an applicant
+func (s *TriMergeService) TriMerge(ctx context.Context, request *domain.TriMergeRequest) (*domain.TriMergeReport, error) {
+	logger := s.logger.With(
+		zap.String("user_id", request.UserID),
+		zap.String("application_id", request.ApplicationID),
+		zap.String("operation", "tri_merge"),
+	)
+
+	if request.UserID == "" || request.SSNToken == "" {
+		return nil, &domain.DecisionError{
+			Code:        domain.ERROR_INSUFFICIENT_DATA,
+			Message:     "Incomplete tri-merge request",
+			Description: "user_id and ssn_token are required",
+			HTTPStatus:  400,
+		}
+	}
+	if err := normalizePullType(&request.CreditReportRequest); err != nil {
+		return nil, err
+	}
+
+	requestedAt := time.Now().UTC()
+	pulls := s.pullAll(ctx, &request.CreditReportRequest)
+
+	var (
+		reports  []domain.BureauReport
+		results  []domain.BureauPullResult
+		failures []error
+	)
+	for _, pull := range pulls {
+		results = append(results, pull.result)
+		if pull.err != nil {
+			logger.Warn("Bureau pull failed", zap.String("bureau", string(pull.result.Bureau)), zap.Error(pull.err))
+			failures = append(failures, pull.err)
+			continue
+		}
+		reports = append(reports, *pull.report)
+	}
+
+	if len(reports) == 0 {
+		logger.Error("Every bureau pull failed")
+		return nil, pullFailureError(failures)
+	}
+
+	merged := domain.MergeCreditReports(reports, s.policy)
+	merged.UserID = request.UserID
+	merged.SSNToken = request.SSNToken
+	merged.ApplicationID = request.ApplicationID
+	merged.PullType = request.PullType
+	merged.Bureaus = results
+	merged.Status = domain.TriMergeComplete
+	if len(reports) < len(domain.Bureaus) {
+		merged.Status = domain.TriMergePartial
+	}
+	merged.RequestedAt = requestedAt
+	merged.CompletedAt = time.Now().UTC()
+
+	if err := s.repo.SaveTriMergeReport(ctx, merged, reports); err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	logger.Info("Tri-merge completed",
+		zap.Int64("merge_id", merged.ID),
+		zap.String("status", string(merged.Status)),
+		zap.String("pull_type", string(merged.PullType)),
+		zap.Int("selected_score", merged.SelectedScore),
+		zap.String("selected_bureau", string(merged.SelectedBureau)),
+		zap.Int("duplicates_removed", merged.DuplicatesRemoved),
+	)
+
+	return merged, nil
+}
+
+// pullAll pulls every bureau in parallel; each pull is bounded by its bureau's timeout
+func (s *TriMergeService) pullAll(ctx context.Context, request *domain.CreditReportRequest) []bureauPull {
+	pulls := make([]bureauPull, len(domain.Bureaus))
+
+	var wg sync.WaitGroup
+	for i, bureau := range domain.Bureaus {
+		wg.Add(1)
+		go func(i int, bureau domain.Bureau) {
+			defer wg.Done()
+			pulls[i] = s.pull(ctx, bureau, request)
+		}(i, bureau)
+	}
+	wg.Wait()
+
+	return pulls
+}
+
+// pull pulls one bureau and records how it went
+func (s *TriMergeService) pull(ctx context.Context, bureau domain.Bureau, request *domain.CreditReportRequest) bureauPull {
+	start := time.Now()
+	report, err := s.bureaus.PullBureauReport(ctx, bureau, request)
+	result := domain.BureauPullResult{
+		Bureau:     bureau,
+		Status:     domain.BureauPullSuccess,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+
+	var decisionErr *domain.DecisionError
+	switch {
+	case err == nil:
+		result.Score = report.Report.CreditScore
+		result.ScoreModel = report.Report.ScoreModel
+		result.Cached = report.Cached
+	case errors.As(err, &decisionErr) && decisionErr.Code == domain.ERROR_BUREAU_UNAVAILABLE:
+		result.Status = domain.BureauPullUnavailable
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &decisionErr) && decisionErr.HTTPStatus == 504:
+		result.Status = domain.BureauPullTimeout
+	default:
+		result.Status = domain.BureauPullFailed
+	}
+	if err != nil {
+		result.Error = pullErrorDetail(err)
+	}
+	return bureauPull{report: report, result: result, err: err}
+}
+
+// PullCreditReport pulls a single bureau report, starting with the preferred bureau and failing
+// over to the others in order when a bureau fails or its circuit breaker is open. A request
+// error, such as missing consent, stops the failover since every bureau would refuse it.
+func (s *TriMergeService) PullCreditReport(ctx context.Context, request *domain.BureauPullRequest) (*domain.BureauPullResponse, error) {
+	logger := s.logger.With(
+		zap.String("user_id", request.UserID),
+		zap.String("operation", "pull_credit_report"),
+	)
+
+	if request.UserID == "" || request.SSNToken == "" {
+		return nil, &domain.DecisionError{
+			Code:        domain.ERROR_INSUFFICIENT_DATA,
+			Message:     "Incomplete credit report request",
+			Description: "user_id and ssn_token are required",
+			HTTPStatus:  400,
+		}
+	}
+	if err := normalizePullType(&request.CreditReportRequest); err != nil {
+		return nil, err
+	}
+	if request.PreferredBureau != "" && !request.PreferredBureau.IsValid() {
+		return nil, &domain.DecisionError{
+			Code:        domain.ERROR_INVALID_REQUEST,
+			Message:     "Invalid credit report request",
+			Description: fmt.Sprintf("unknown bureau %q", request.PreferredBureau),
+			HTTPStatus:  400,
+		}
+	}
+
+	response := &domain.BureauPullResponse{Attempts: []domain.BureauPullResult{}}
+	var failures []error
+	for _, bureau := range failoverOrder(request.PreferredBureau) {
+		pull := s.pull(ctx, bureau, &request.CreditReportRequest)
+		response.Attempts = append(response.Attempts, pull.result)
+
+		if pull.err != nil {
+			var decisionErr *domain.DecisionError
+			if errors.As(pull.err, &decisionErr) && decisionErr.HTTPStatus < 500 {
+				return nil, pull.err
+			}
+			failures = append(failures, pull.err)
+			logger.Warn("Bureau pull failed, failing over to the next bureau", zap.String("bureau", string(bureau)), zap.Error(pull.err))
+			continue
+		}
+
+		if !pull.report.Cached {
+			if err := s.repo.SaveBureauReport(ctx, request.UserID, request.SSNToken, pull.report); err != nil {
+				return nil, s.databaseError(err)
+			}
+		}
+		response.Report = pull.report
+		response.FailedOver = len(failures) > 0
+
+		logger.Info("Credit report pulled",
+			zap.String("bureau", string(bureau)),
+			zap.String("pull_type", string(pull.report.PullType)),
+			zap.Bool("failed_over", response.FailedOver),
+			zap.Bool("cached", pull.report.Cached),
+		)
+		return response, nil
+	}
+
+	logger.Error("Every bureau pull failed")
+	return nil, pullFailureError(failures)
+}
+
+// normalizePullType validates the request's pull type and sets it to the pull the request makes,
+// so an unset pull type is resolved once for every bureau
+func normalizePullType(request *domain.CreditReportRequest) error {
+	if !request.ValidPullType() {
+		return &domain.DecisionError{
+			Code:        domain.ERROR_INVALID_REQUEST,
+			Message:     "Invalid credit report request",
+			Description: fmt.Sprintf("unknown pull type %q; use SOFT or HARD", request.PullType),
+			HTTPStatus:  400,
+		}
+	}
+	request.PullType = request.EffectivePullType()
+	return nil
+}
+
+// failoverOrder lists the bureaus with the preferred one first
+func failoverOrder(preferred domain.Bureau) []domain.Bureau {
+	order := make([]domain.Bureau, 0, len(domain.Bureaus))
+	if preferred != "" {
+		order = append(order, preferred)
+	}
+	for _, bureau := range domain.Bureaus {
+		if bureau != preferred {
+			order = append(order, bureau)
+		}
+	}
+	return order
+}
+
+// GetTriMergeReport returns a tri-merge report
+func (s *TriMergeService) GetTriMergeReport(ctx context.Context, id int64) (*domain.TriMergeReport, error) {
+	report, err := s.repo.GetTriMergeReport(ctx, id)
+	if err != nil {
+		return nil, s.repositoryError(id, err)
+	}
+	return report, nil
+}
+
+// GetBureauReports returns the raw bureau reports a tri-merge was built from
+func (s *TriMergeService) GetBureauReports(ctx context.Context, id int64) ([]domain.StoredBureauReport, error) {
+	if _, err := s.GetTriMergeReport(ctx, id); err != nil {
+		return nil, err
+	}
+
+	reports, err := s.repo.GetBureauReports(ctx, id)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	return reports, nil
+}
+
+// pullFailureError reports a tri-merge where no bureau returned a report. A request error every
+// bureau refused with, such as missing consent, is returned as is.
+func pullFailureError(failures []error) error {
+	var first *domain.DecisionError
+	if len(failures) > 0 && errors.As(failures[0], &first) && first.HTTPStatus < 500 {
+		shared := true
+		for _, err := range failures[1:] {
+			var decisionErr *domain.DecisionError
+			if !errors.As(err, &decisionErr) || decisionErr.Code != first.Code {
+				shared = false
+				break
+			}
+		}
+		if shared {
+			return first
+		}
+	}
+
+	messages := make([]string, 0, len(failures))
+	for _, err := range failures {
+		messages = append(messages, pullErrorDetail(err))
+	}
+	return &domain.DecisionError{
+		Code:        domain.ERROR_EXTERNAL_SERVICE,
+		Message:     "No credit bureau returned a report",
+		Description: strings.Join(messages, "; "),
+		HTTPStatus:  502,
+	}
+}
+
+// pullErrorDetail describes a failed pull, using the description of decision errors
+func pullErrorDetail(err error) string {
+	var decisionErr *domain.DecisionError
+	if errors.As(err, &decisionErr) && decisionErr.Description != "" {
+		return decisionErr.Description
+	}
+	return err.Error()
+}
+
+func (s *TriMergeService) repositoryError(id int64, err error) error {
+	if isNotFound(err) {
+		return &domain.DecisionError{
+			Code:        domain.ERROR_CREDIT_REPORT_NOT_FOUND,
+			Message:     "Credit report not found",
+			Description: fmt.Sprintf("No tri-merge report found for %d", id),
+			HTTPStatus:  404,
+		}
+	}
+	return s.databaseError(err)
+}
+
+func (s *TriMergeService) databaseError(err error) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_DATABASE_ERROR,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
@@ -3,11 +3,14 @@ package main
 import (
 	"context"
 	"database/sql"
+	"expvar"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -15,12 +18,19 @@ import (
 	"github.com/huuhoait/los-demo/services/decision-engine/domain"
 	"github.com/huuhoait/los-demo/services/decision-engine/infrastructure"
 	"github.com/huuhoait/los-demo/services/decision-engine/interfaces"
-	"github.com/huuhoait/los-demo/services/shared/pkg/config"
+	"github.com/huuhoait/los-demo/services/decision-engine/pkg/config"
+	"github.com/huuhoait/los-demo/services/shared/pkg/cache"
+	"github.com/huuhoait/los-demo/services/shared/pkg/dti"
 	"github.com/huuhoait/los-demo/services/shared/pkg/logger"
+	"github.com/huuhoait/los-demo/services/shared/pkg/rpc"
+	decisionv1 "github.com/huuhoait/los-demo/services/shared/proto/decision/v1"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
@@ -31,7 +41,12 @@ func main() {
 	}
 
 	// Initialize logger
-	zapLogger, err := logger.New(cfg.Logger.Level, cfg.Environment)
+	zapLogger, err := logger.New(logger.Config{
+		Level:       cfg.Logger.Level,
+		Format:      cfg.Logger.Format,
+		Output:      cfg.Logger.Output,
+		Environment: cfg.Environment,
+	})
 	if err != nil {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
@@ -47,16 +62,36 @@ func main() {
 	defer db.Close()
 
 	// Initialize services
-	decisionService, err := setupServices(db, cfg, logger)
+	svc, err := setupServices(db, cfg, logger)
 	if err != nil {
 		logger.Fatal("Failed to setup services", zap.Error(err))
 	}
 
-	// Initialize HTTP handler
-	handler := interfaces.NewDecisionHandler(decisionService, logger)
+	// Reload published rules so versions published through other instances take effect
+	reloadCtx, stopReload := context.WithCancel(context.Background())
+	defer stopReload()
+	go svc.rulesEngine.Start(reloadCtx, time.Minute)
+	go svc.scorecards.Start(reloadCtx, time.Minute)
+	go svc.policies.Start(reloadCtx, time.Minute)
+
+	// Fail batch jobs left unfinished by instances that stopped
+	go svc.batches.Start(reloadCtx, time.Minute)
+
+	// Retry re-decisions the loan service has not yet accepted
+	go svc.redecisions.Start(reloadCtx, 30*time.Second)
+
+	// Initialize HTTP handlers
+	handler := interfaces.NewDecisionHandler(svc.decisions, svc.replays, logger)
+	rulesHandler := interfaces.NewRulesHandler(svc.rules, svc.simulator, logger)
+	strategyHandler := interfaces.NewStrategyHandler(svc.strategies, logger)
+	scorecardHandler := interfaces.NewScorecardHandler(svc.scorecards, logger)
+	creditReportHandler := interfaces.NewCreditReportHandler(svc.triMerge, svc.bureauBreaker, logger)
+	batchHandler := interfaces.NewBatchHandler(svc.batches, logger)
+	policyHandler := interfaces.NewPolicyHandler(svc.policies, logger)
+	fraudHandler := interfaces.NewFraudHandler(svc.fraud, logger)
 
 	// Setup router
-	router := setupRouter(handler, cfg, logger)
+	router := setupRouter(handler, rulesHandler, strategyHandler, scorecardHandler, creditReportHandler, batchHandler, policyHandler, fraudHandler, svc.tenants, cfg, logger)
 
 	// Start server
 	server := &http.Server{
@@ -75,17 +110,61 @@ func main() {
 		}
 	}()
 
+	// Process metrics, including credit report cache hits, are only served on the internal debug listener
+	debugServer := newDebugServer(cfg.Server.DebugAddr)
+	if debugServer != nil {
+		go func() {
+			logger.Info("Starting debug server", zap.String("address", debugServer.Addr))
+			if err := debugServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("Failed to start debug server", zap.Error(err))
+			}
+		}()
+	}
+
+	// Serve DecisionService to internal callers over gRPC; REST stays the external API
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Port > 0 {
+		grpcServer, err = rpc.NewServer(cfg.GRPC, logger)
+		if err != nil {
+			logger.Fatal("Failed to create gRPC server", zap.Error(err))
+		}
+		decisionv1.RegisterDecisionServiceServer(grpcServer, interfaces.NewDecisionServer(svc.decisions, svc.tenants, logger))
+		healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.Port))
+		if err != nil {
+			logger.Fatal("Failed to listen for gRPC", zap.Error(err))
+		}
+		go func() {
+			logger.Info("Starting gRPC server", zap.Int("port", cfg.GRPC.Port))
+			if err := grpcServer.Serve(listener); err != nil {
+				logger.Error("gRPC server stopped", zap.Error(err))
+			}
+		}()
+	}
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	logger.Info("Shutting down server...")
+	stopReload()
 
 	// Give outstanding requests 30 seconds to complete
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	if debugServer != nil {
+		if err := debugServer.Shutdown(ctx); err != nil {
+			logger.Warn("Debug server forced to shutdown", zap.Error(err))
+		}
+	}
+
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
+
 	if err := server.Shutdown(ctx); err != nil {
 		logger.Error("Server forced to shutdown", zap.Error(err))
 	} else {
@@ -93,6 +172,21 @@ func main() {
 	}
 }
 
+// newDebugServer serves expvar metrics on a separate address, meant to be bound to loopback or a
+// private network; an empty address disables it
+func newDebugServer(addr string) *http.Server {
+	if addr == "" {
+		return nil
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
+
 // setupDatabase initializes database connection and runs migrations
 func setupDatabase(databaseURL string, logger *zap.Logger) (*sql.DB, error) {
 	logger.Info("Connecting to database")
@@ -117,29 +211,265 @@ func setupDatabase(databaseURL string, logger *zap.Logger) (*sql.DB, error) {
 	return db, nil
 }
 
+// services are the application services behind the HTTP handlers
+type services struct {
+	decisions     *application.DecisionEngineService
+	rules         *application.RuleService
+	simulator     *application.RuleSimulator
+	strategies    *application.StrategyService
+	scorecards    *application.ScorecardService
+	policies      *application.PolicyService
+	fraud         *application.FraudScreeningService
+	replays       *application.DecisionReplayService
+	batches       *application.BatchDecisionService
+	redecisions   *application.RedecisionDeliveryService
+	triMerge      *application.TriMergeService
+	bureauBreaker *application.BureauCircuitBreaker
+	rulesEngine   *application.RulesEngine
+	tenants       *domain.TenantRegistry
+}
+
 // setupServices initializes all application services
-func setupServices(db *sql.DB, cfg *config.Config, logger *zap.Logger) (*application.DecisionEngineService, error) {
+func setupServices(db *sql.DB, cfg *config.Config, logger *zap.Logger) (*services, error) {
+	// Scope rules, policies and pricing to the configured lending programs
+	tenants, err := lendingPrograms(cfg)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Lending programs configured", zap.Strings("tenants", tenants.IDs()))
+
 	// Initialize repositories
 	decisionRepo := infrastructure.NewDecisionRepository(db, logger)
 
+	// Blend the active scorecard into risk assessments
+	scorecardRepo := infrastructure.NewScorecardRepository(db, logger)
+	scorecardService := application.NewScorecardService(scorecardRepo, logger)
+	if err := scorecardService.LoadActive(context.Background()); err != nil {
+		return nil, err
+	}
+
 	// Initialize services
-	riskService := application.NewRiskAssessmentService(logger)
+	riskService := application.NewRiskAssessmentService(logger, scorecardService)
+
+	// Load the published decision rules persisted in the database
+	rulesRepo := infrastructure.NewRulesRepository(db, logger)
+	rulesEngine := application.NewRulesEngine(rulesRepo, riskService, logger)
+	if err := rulesEngine.LoadRules(context.Background()); err != nil {
+		return nil, err
+	}
+
+	// Shadow decisions with the active champion/challenger strategy
+	strategyRepo := infrastructure.NewStrategyRepository(db, logger)
+	strategyService := application.NewStrategyService(strategyRepo, rulesRepo, rulesEngine, logger)
+
+	// Pull and merge reports from all three bureaus, after consent checks and SSN detokenization
+	// through the user service; hard pulls also need a submitted application in the loan service.
+	// Sandbox mode serves recorded responses for test SSNs instead, for demos and load tests.
+	bureauConfig := cfg.ExternalServices.CreditBureau
+	userService := cfg.ExternalServices.UserService
+	loanService := cfg.ExternalServices.LoanService
+	loanServiceClient := infrastructure.NewLoanServiceClient(loanService.BaseURL, loanService.ServiceToken, loanService.Timeout, logger)
+	bureauRepo, err := infrastructure.NewCreditBureauRepository(
+		logger,
+		infrastructure.CreditBureauConfig{
+			ExperianEndpoint:   bureauConfig.Experian.Endpoint,
+			EquifaxEndpoint:    bureauConfig.Equifax.Endpoint,
+			TransUnionEndpoint: bureauConfig.TransUnion.Endpoint,
+			APITimeout:         bureauConfig.Timeout,
+			BureauTimeouts: map[domain.Bureau]time.Duration{
+				domain.BureauExperian:   bureauConfig.Experian.Timeout,
+				domain.BureauEquifax:    bureauConfig.Equifax.Timeout,
+				domain.BureauTransUnion: bureauConfig.TransUnion.Timeout,
+			},
+			RetryAttempts: bureauConfig.RetryCount,
+			Sandbox: infrastructure.BureauSandboxConfig{
+				Enabled:     bureauConfig.Sandbox.Enabled,
+				FixturesDir: bureauConfig.Sandbox.FixturesDir,
+				Latency:     bureauConfig.Sandbox.Latency,
+				ErrorRate:   bureauConfig.Sandbox.ErrorRate,
+			},
+		},
+		infrastructure.NewConsentClient(userService.BaseURL, userService.ServiceToken, userService.Timeout, logger),
+		loanServiceClient,
+		infrastructure.NewTokenVaultClient(userService.BaseURL, userService.ServiceToken, userService.Timeout, logger),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Retry failed bureau pulls and cut off bureaus that keep failing
+	bureauBreaker := application.NewBureauCircuitBreaker(bureauRepo, application.BreakerPolicy{
+		FailureThreshold: bureauConfig.CircuitBreaker.FailureThreshold,
+		OpenTimeout:      bureauConfig.CircuitBreaker.OpenTimeout,
+		RetryAttempts:    bureauConfig.RetryCount,
+		RetryBaseDelay:   bureauConfig.RetryBaseDelay,
+		RetryMaxDelay:    bureauConfig.RetryMaxDelay,
+	}, logger)
+
+	// Reuse reports pulled within the cache TTL, found through the Redis index when Redis is up
+	creditReportRepo := infrastructure.NewCreditReportRepository(db, logger)
+	var creditReportIndex domain.CreditReportIndex
+	redisPort, _ := strconv.Atoi(cfg.Redis.Port)
+	redisClient, err := cache.NewClient(cache.Config{
+		Host:     cfg.Redis.Host,
+		Port:     redisPort,
+		Password: cfg.Redis.Password,
+		Database: cfg.Redis.DB,
+		PoolSize: cfg.Redis.PoolSize,
+	})
+	if err != nil {
+		logger.Warn("Failed to connect to Redis, looking up cached credit reports in the database only", zap.Error(err))
+	} else {
+		creditReportIndex = infrastructure.NewRedisCreditReportIndex(redisClient, logger)
+	}
+	cacheTTL := bureauConfig.CacheTTL
+	if cacheTTL == 0 {
+		cacheTTL = domain.DefaultCreditReportCacheTTL
+	}
+	creditReportCache := application.NewCreditReportCache(
+		bureauBreaker,
+		creditReportRepo,
+		creditReportIndex,
+		domain.CreditReportCachePolicy{TTL: cacheTTL},
+		logger,
+	)
+
+	triMergeService := application.NewTriMergeService(
+		creditReportCache,
+		creditReportRepo,
+		domain.ScoreSelectionPolicy(bureauConfig.ScorePolicy),
+		logger,
+	)
+
+	// Hold decisions to the approved underwriting policy in force
+	policyService := application.NewPolicyService(infrastructure.NewPolicyRepository(db, logger), logger)
+	if err := policyService.LoadPolicies(context.Background()); err != nil {
+		return nil, err
+	}
 
-	// Create a mock rules service
-	rulesService := NewMockRulesService(logger)
+	// Screen applicants with the fraud vendor, when one is configured, and route risky applications
+	// to manual review
+	var fraudService *application.FraudScreeningService
+	if fraudConfig := cfg.ExternalServices.FraudVendor; fraudConfig.Endpoint != "" {
+		fraudService = application.NewFraudScreeningService(
+			infrastructure.NewFraudVendorClient(
+				infrastructure.FraudVendorConfig{
+					Vendor:     fraudConfig.Vendor,
+					Endpoint:   fraudConfig.Endpoint,
+					APIKey:     fraudConfig.APIKey,
+					Timeout:    fraudConfig.Timeout,
+					ScoreScale: fraudConfig.ScoreScale,
+				},
+				infrastructure.NewTokenVaultClient(userService.BaseURL, userService.ServiceToken, userService.Timeout, logger),
+				logger,
+			),
+			domain.FraudPolicy{
+				ReviewScore:           fraudConfig.ReviewScore,
+				SignalReviewScore:     fraudConfig.SignalReviewScore,
+				DenyScore:             fraudConfig.DenyScore,
+				ReviewWhenUnavailable: fraudConfig.ReviewWhenUnavailable,
+			},
+			logger,
+		)
+	} else {
+		logger.Warn("Fraud vendor not configured, decisions use only fraud scores supplied by the caller")
+	}
+
+	// Tell the loan service of re-decisions with late data through an outbox, retried until it
+	// accepts them
+	redecisionDelivery := application.NewRedecisionDeliveryService(
+		infrastructure.NewRedecisionRepository(db, logger),
+		decisionRepo,
+		loanServiceClient,
+		logger,
+	)
 
 	decisionService := application.NewDecisionEngineService(
 		riskService,
-		rulesService,
+		rulesEngine,
+		strategyService,
+		policyService,
+		fraudService,
+		application.NewIncomeEstimationService(creditReportRepo, domain.IncomeEstimationPolicy{
+			VarianceThreshold: cfg.DecisionEngine.IncomeEstimation.VarianceThreshold,
+			TradelineWeight:   cfg.DecisionEngine.IncomeEstimation.TradelineWeight,
+		}, logger),
+		dti.NewCalculator(cfg.DTI),
+		domain.SourceSLAPolicy{
+			FraudScreening: cfg.DecisionEngine.SourceSLA.FraudScreening,
+			CreditReport:   cfg.DecisionEngine.SourceSLA.CreditReport,
+			LateDataWindow: cfg.DecisionEngine.SourceSLA.LateDataWindow,
+		},
+		tenants,
 		decisionRepo,
+		redecisionDelivery,
 		logger,
 	)
 
-	return decisionService, nil
+	// Decide batches asynchronously within the configured concurrency limits
+	batchConfig := cfg.DecisionEngine.Batch
+	batchService := application.NewBatchDecisionService(
+		decisionService,
+		infrastructure.NewBatchRepository(db, logger),
+		decisionRepo,
+		domain.BatchPolicy{
+			MaxItems:           batchConfig.MaxItems,
+			DefaultConcurrency: batchConfig.DefaultConcurrency,
+			MaxConcurrency:     batchConfig.MaxConcurrency,
+			MaxActiveJobs:      batchConfig.MaxActiveJobs,
+			FlushSize:          batchConfig.FlushSize,
+			StaleAfter:         batchConfig.StaleAfter,
+		},
+		logger,
+	)
+
+	return &services{
+		decisions:     decisionService,
+		rules:         application.NewRuleService(rulesRepo, rulesEngine, logger),
+		batches:       batchService,
+		redecisions:   redecisionDelivery,
+		replays:       application.NewDecisionReplayService(decisionService, rulesEngine, rulesRepo, scorecardRepo, decisionRepo, logger),
+		simulator:     application.NewRuleSimulator(rulesRepo, decisionRepo, rulesEngine, logger),
+		strategies:    strategyService,
+		scorecards:    scorecardService,
+		policies:      policyService,
+		fraud:         fraudService,
+		triMerge:      triMergeService,
+		bureauBreaker: bureauBreaker,
+		rulesEngine:   rulesEngine,
+		tenants:       tenants,
+	}, nil
+}
+
+// lendingPrograms builds the registry of lending programs from the configuration
+func lendingPrograms(cfg *config.Config) (*domain.TenantRegistry, error) {
+	var tenants []domain.Tenant
+	for _, program := range cfg.DecisionEngine.Tenants {
+		pricing := domain.PricingMatrix{
+			BaseRates:           make(map[domain.LoanPurpose]float64, len(program.Pricing.BaseRates)),
+			DefaultBaseRate:     program.Pricing.DefaultBaseRate,
+			RateFloor:           program.Pricing.RateFloor,
+			RateCeiling:         program.Pricing.RateCeiling,
+			OriginationFeeRates: make(map[domain.RiskCategory]float64, len(program.Pricing.OriginationFeeRates)),
+		}
+		for purpose, rate := range program.Pricing.BaseRates {
+			pricing.BaseRates[domain.LoanPurpose(purpose)] = rate
+		}
+		for category, rate := range program.Pricing.OriginationFeeRates {
+			pricing.OriginationFeeRates[domain.RiskCategory(category)] = rate
+		}
+		tenants = append(tenants, domain.Tenant{ID: program.ID, Name: program.Name, Pricing: pricing})
+	}
+
+	registry, err := domain.NewTenantRegistry(tenants)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lending program configuration: %w", err)
+	}
+	return registry, nil
 }
 
 // setupRouter configures the HTTP router
-func setupRouter(handler *interfaces.DecisionHandler, cfg *config.Config, logger *zap.Logger) *gin.Engine {
+func setupRouter(handler *interfaces.DecisionHandler, rulesHandler *interfaces.RulesHandler, strategyHandler *interfaces.StrategyHandler, scorecardHandler *interfaces.ScorecardHandler, creditReportHandler *interfaces.CreditReportHandler, batchHandler *interfaces.BatchHandler, policyHandler *interfaces.PolicyHandler, fraudHandler *interfaces.FraudHandler, tenants *domain.TenantRegistry, cfg *config.Config, logger *zap.Logger) *gin.Engine {
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -162,51 +492,18 @@ func setupRouter(handler *interfaces.DecisionHandler, cfg *config.Config, logger
 		)
 	})
 
+	// Scope every request to the lending program in its X-Tenant-ID header
+	router.Use(interfaces.TenantScope(tenants, logger))
+
 	// Setup routes
 	handler.RegisterRoutes(router)
+	rulesHandler.RegisterRoutes(router)
+	strategyHandler.RegisterRoutes(router)
+	scorecardHandler.RegisterRoutes(router)
+	creditReportHandler.RegisterRoutes(router)
+	batchHandler.RegisterRoutes(router)
+	policyHandler.RegisterRoutes(router)
+	fraudHandler.RegisterRoutes(router)
 
 	return router
 }
-
-// MockRulesService provides a mock implementation of domain.RulesEngineService
-type MockRulesService struct {
-	logger *zap.Logger
-}
-
-// NewMockRulesService creates a new mock rules service
-func NewMockRulesService(logger *zap.Logger) *MockRulesService {
-	return &MockRulesService{
-		logger: logger,
-	}
-}
-
-// EvaluateRules implements domain.RulesEngineService
-func (m *MockRulesService) EvaluateRules(request *domain.DecisionRequest, assessment *domain.RiskAssessment) (*domain.DecisionResponse, error) {
-	// Mock implementation - always return approval for testing
-	return &domain.DecisionResponse{
-		ApplicationID: request.ApplicationID,
-		Decision:      domain.DecisionApprove,
-		RiskScore:     assessment.OverallScore,
-		RiskCategory:  domain.RiskLow,
-	}, nil
-}
-
-// GetActiveRules implements domain.RulesEngineService
-func (m *MockRulesService) GetActiveRules() ([]domain.DecisionRule, error) {
-	return []domain.DecisionRule{}, nil
-}
-
-// AddRule implements domain.RulesEngineService
-func (m *MockRulesService) AddRule(rule *domain.DecisionRule) error {
-	return nil
-}
-
-// UpdateRule implements domain.RulesEngineService
-func (m *MockRulesService) UpdateRule(rule *domain.DecisionRule) error {
-	return nil
-}
-
-// DeleteRule implements domain.RulesEngineService
-func (m *MockRulesService) DeleteRule(ruleID string) error {
-	return nil
-}
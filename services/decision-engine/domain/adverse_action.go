@@ -0,0 +1,286 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// AdverseActionCode is a standardized adverse action reason, modelled on the sample reasons of
+// Regulation B (ECOA) form C-1
+type AdverseActionCode string
+
+const (
+	AdverseActionIncompleteApplication  AdverseActionCode = "AA01"
+	AdverseActionInsufficientReferences AdverseActionCode = "AA02"
+	AdverseActionUnverifiedReferences   AdverseActionCode = "AA03"
+	AdverseActionLimitedCredit          AdverseActionCode = "AA04"
+	AdverseActionDelinquency            AdverseActionCode = "AA05"
+	AdverseActionCollection             AdverseActionCode = "AA06"
+	AdverseActionBankruptcy             AdverseActionCode = "AA07"
+	AdverseActionInquiries              AdverseActionCode = "AA08"
+	AdverseActionCollateral             AdverseActionCode = "AA09"
+	AdverseActionUnverifiedIncome       AdverseActionCode = "AA10"
+	AdverseActionExcessiveObligations   AdverseActionCode = "AA11"
+	AdverseActionInsufficientIncome     AdverseActionCode = "AA12"
+	AdverseActionIrregularEmployment    AdverseActionCode = "AA13"
+	AdverseActionEmploymentLength       AdverseActionCode = "AA14"
+	AdverseActionUnverifiedEmployment   AdverseActionCode = "AA15"
+	AdverseActionCreditScore            AdverseActionCode = "AA16"
+	AdverseActionUtilization            AdverseActionCode = "AA17"
+	AdverseActionTermsRequested         AdverseActionCode = "AA18"
+	AdverseActionOther                  AdverseActionCode = "AA99"
+)
+
+// AdverseActionDescriptions are the applicant-facing statements of each reason
+var AdverseActionDescriptions = map[AdverseActionCode]string{
+	AdverseActionIncompleteApplication:  "Credit application incomplete",
+	AdverseActionInsufficientReferences: "Insufficient number of credit references provided",
+	AdverseActionUnverifiedReferences:   "Unable to verify credit references",
+	AdverseActionLimitedCredit:          "Limited credit experience",
+	AdverseActionDelinquency:            "Delinquent past or present credit obligations with others",
+	AdverseActionCollection:             "Collection action or judgment",
+	AdverseActionBankruptcy:             "Bankruptcy",
+	AdverseActionInquiries:              "Number of recent inquiries on credit bureau report",
+	AdverseActionCollateral:             "Value or type of collateral not sufficient",
+	AdverseActionUnverifiedIncome:       "Unable to verify income",
+	AdverseActionExcessiveObligations:   "Excessive obligations in relation to income",
+	AdverseActionInsufficientIncome:     "Insufficient income for amount of credit requested",
+	AdverseActionIrregularEmployment:    "Temporary or irregular employment",
+	AdverseActionEmploymentLength:       "Length of employment",
+	AdverseActionUnverifiedEmployment:   "Unable to verify employment",
+	AdverseActionCreditScore:            "Credit score does not meet our requirements",
+	AdverseActionUtilization:            "Proportion of balances to credit limits is too high",
+	AdverseActionTermsRequested:         "We do not grant credit on the terms and conditions you requested",
+	AdverseActionOther:                  "Other",
+}
+
+// IsValid reports whether c is a known adverse action reason
+func (c AdverseActionCode) IsValid() bool {
+	_, ok := AdverseActionDescriptions[c]
+	return ok
+}
+
+// factAdverseActions maps rule facts to the adverse action reason a rule or scorecard
+// characteristic on that fact stands for. Derived facts such as overall_score have none.
+var factAdverseActions = map[string]AdverseActionCode{
+	"credit_score":       AdverseActionCreditScore,
+	"credit_risk":        AdverseActionCreditScore,
+	"payment_score":      AdverseActionDelinquency,
+	"on_time_payments":   AdverseActionDelinquency,
+	"late_payments":      AdverseActionDelinquency,
+	"defaults":           AdverseActionDelinquency,
+	"bankruptcies":       AdverseActionBankruptcy,
+	"credit_age_months":  AdverseActionLimitedCredit,
+	"credit_utilization": AdverseActionUtilization,
+	"dti_ratio":          AdverseActionExcessiveObligations,
+	"monthly_debt":       AdverseActionExcessiveObligations,
+	"debt_risk":          AdverseActionExcessiveObligations,
+	"annual_income":      AdverseActionInsufficientIncome,
+	"monthly_income":     AdverseActionInsufficientIncome,
+	"income_risk":        AdverseActionInsufficientIncome,
+	"loan_to_income":     AdverseActionInsufficientIncome,
+	"employment_type":    AdverseActionIrregularEmployment,
+	"employment_risk":    AdverseActionIrregularEmployment,
+	"ltv_ratio":          AdverseActionCollateral,
+	"collateral_value":   AdverseActionCollateral,
+	"collateral_type":    AdverseActionCollateral,
+	"collateral_risk":    AdverseActionCollateral,
+	"is_secured":         AdverseActionCollateral,
+	"loan_amount":        AdverseActionTermsRequested,
+	"requested_term":     AdverseActionTermsRequested,
+	"loan_term_months":   AdverseActionTermsRequested,
+	"loan_purpose":       AdverseActionTermsRequested,
+	"usury_rate_limit":   AdverseActionTermsRequested,
+}
+
+// MaxAdverseActionReasons is the number of principal reasons given; Regulation B considers more
+// than four unhelpful to applicants
+const MaxAdverseActionReasons = 4
+
+// AdverseActionForFacts returns the reason for the first fact that stands for one, or "" when
+// none does
+func AdverseActionForFacts(facts []string) AdverseActionCode {
+	for _, fact := range facts {
+		if code, ok := factAdverseActions[fact]; ok {
+			return code
+		}
+	}
+	return ""
+}
+
+// RuleHit records a rule that matched an application
+type RuleHit struct {
+	RuleID        string            `json:"rule_id"`
+	Version       string            `json:"version,omitempty"` // empty for built-in policies
+	Name          string            `json:"name"`
+	Category      RuleCategory      `json:"category"`
+	Action        ActionType        `json:"action"`
+	Decision      DecisionType      `json:"decision,omitempty"`
+	Knockout      bool              `json:"knockout,omitempty"`
+	Reason        string            `json:"reason,omitempty"`
+	AdverseAction AdverseActionCode `json:"adverse_action_code,omitempty"`
+}
+
+// Adverse action reason sources
+const (
+	AdverseActionSourceRule      = "RULE"
+	AdverseActionSourceScorecard = "SCORECARD"
+	AdverseActionSourceDecision  = "DECISION"
+)
+
+// AdverseActionReason is one principal reason for an adverse action, ranked from 1
+type AdverseActionReason struct {
+	Rank        int               `json:"rank"`
+	Code        AdverseActionCode `json:"code"`
+	Description string            `json:"description"`
+	Source      string            `json:"source"`              // RULE, SCORECARD or DECISION
+	Reference   string            `json:"reference,omitempty"` // rule id@version or scorecard characteristic
+	Detail      string            `json:"detail,omitempty"`    // the rule reason or scorecard reason code behind it
+}
+
+// IsAdverseAction reports whether a decision is an adverse action: a denial, or a counteroffer of
+// less than the amount requested
+func (d *DecisionResponse) IsAdverseAction() bool {
+	switch d.Decision {
+	case DecisionDeny:
+		return true
+	case DecisionApprove, DecisionConditional:
+		return d.RequestedAmount > 0 && d.MaxAmount < d.RequestedAmount
+	}
+	return false
+}
+
+// BuildAdverseActionReasons ranks the principal reasons for an adverse action: the rules that
+// denied or reduced the application, then the rules that flagged it, then the scorecard
+// characteristics that cost it the most points. Each reason is given once. A decision with no
+// attributable reason is given AA99 with the decision reason.
+func BuildAdverseActionReasons(decision *DecisionResponse) []AdverseActionReason {
+	if !decision.IsAdverseAction() {
+		return nil
+	}
+
+	candidates := make([][]AdverseActionReason, 4)
+	for _, hit := range decision.RuleHits {
+		if hit.AdverseAction == "" {
+			continue
+		}
+		tier := -1
+		switch {
+		case hit.Action == ActionDecision && (hit.Knockout || hit.Decision == DecisionDeny):
+			tier = 0
+		case hit.Action == ActionAdjustment && decision.Decision != DecisionDeny:
+			tier = 1
+		case hit.Action == ActionDecision && hit.Decision != DecisionApprove:
+			tier = 2
+		case hit.Action == ActionFlag:
+			tier = 3
+		}
+		if tier < 0 {
+			continue
+		}
+		reference := hit.RuleID
+		if hit.Version != "" {
+			reference = hit.RuleID + "@" + hit.Version
+		}
+		candidates[tier] = append(candidates[tier], AdverseActionReason{
+			Code:      hit.AdverseAction,
+			Source:    AdverseActionSourceRule,
+			Reference: reference,
+			Detail:    hit.Reason,
+		})
+	}
+
+	ranked := make([]AdverseActionReason, 0, MaxAdverseActionReasons)
+	seen := make(map[AdverseActionCode]bool)
+	add := func(reason AdverseActionReason) {
+		if len(ranked) == MaxAdverseActionReasons || seen[reason.Code] {
+			return
+		}
+		seen[reason.Code] = true
+		reason.Rank = len(ranked) + 1
+		reason.Description = AdverseActionDescriptions[reason.Code]
+		ranked = append(ranked, reason)
+	}
+
+	for _, tier := range candidates {
+		for _, reason := range tier {
+			add(reason)
+		}
+	}
+	if decision.RiskAssessment != nil && decision.RiskAssessment.Scorecard != nil {
+		for _, code := range decision.RiskAssessment.Scorecard.ReasonCodes {
+			if adverse := AdverseActionForFacts([]string{code.Field}); adverse != "" {
+				add(AdverseActionReason{
+					Code:      adverse,
+					Source:    AdverseActionSourceScorecard,
+					Reference: code.Characteristic,
+					Detail:    fmt.Sprintf("%s: %s", code.Code, code.Description),
+				})
+			}
+		}
+	}
+
+	if len(ranked) == 0 {
+		add(AdverseActionReason{
+			Code:   AdverseActionOther,
+			Source: AdverseActionSourceDecision,
+			Detail: decision.DecisionReason,
+		})
+	}
+	return ranked
+}
+
+// DecisionExplanation explains a past decision: the rules that matched, the score behind it and,
+// for adverse actions, the ranked reasons given to the applicant
+type DecisionExplanation struct {
+	DecisionID           int64                 `json:"decision_id"`
+	ApplicationID        string                `json:"application_id"`
+	Decision             DecisionType          `json:"decision"`
+	DecisionReason       string                `json:"decision_reason"`
+	DecisionDate         time.Time             `json:"decision_date"`
+	RequestedAmount      float64               `json:"requested_amount,omitempty"`
+	MaxAmount            float64               `json:"max_amount"`
+	RiskScore            float64               `json:"risk_score"`
+	AdverseAction        bool                  `json:"adverse_action"`
+	AdverseActionReasons []AdverseActionReason `json:"adverse_action_reasons"`
+	RuleHits             []RuleHit             `json:"rule_hits"`
+	RuleVersions         []string              `json:"rule_versions,omitempty"`
+	Scorecard            *ScorecardResult      `json:"scorecard,omitempty"`
+	RiskFactors          []RiskFactor          `json:"risk_factors,omitempty"`
+}
+
+// NewDecisionExplanation explains a stored decision. Decisions stored before reasons were recorded
+// have their reasons rebuilt from what was stored with them.
+func NewDecisionExplanation(decision *DecisionResponse) *DecisionExplanation {
+	explanation := &DecisionExplanation{
+		DecisionID:           decision.DecisionID,
+		ApplicationID:        decision.ApplicationID,
+		Decision:             decision.Decision,
+		DecisionReason:       decision.Reason,
+		DecisionDate:         decision.DecisionDate,
+		RequestedAmount:      decision.RequestedAmount,
+		MaxAmount:            decision.MaxAmount,
+		RiskScore:            decision.RiskScore,
+		AdverseAction:        decision.IsAdverseAction(),
+		AdverseActionReasons: decision.AdverseActionReasons,
+		RuleHits:             decision.RuleHits,
+		RuleVersions:         decision.RuleVersions,
+	}
+	if explanation.RuleHits == nil {
+		explanation.RuleHits = []RuleHit{}
+	}
+	if explanation.AdverseAction && len(explanation.AdverseActionReasons) == 0 {
+		explanation.AdverseActionReasons = BuildAdverseActionReasons(decision)
+	}
+	if explanation.AdverseActionReasons == nil {
+		explanation.AdverseActionReasons = []AdverseActionReason{}
+	}
+	if decision.RiskAssessment != nil {
+		explanation.Scorecard = decision.RiskAssessment.Scorecard
+		explanation.RiskFactors = decision.RiskAssessment.RiskFactors
+		if explanation.RiskScore == 0 {
+			explanation.RiskScore = decision.RiskAssessment.OverallScore
+		}
+	}
+	return explanation
+}
@@ -0,0 +1,241 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Batch decisioning defaults and limits
+const (
+	DefaultBatchMaxItems       = 1000
+	DefaultBatchConcurrency    = 4
+	DefaultBatchMaxConcurrency = 16
+	DefaultBatchMaxActiveJobs  = 4
+	DefaultBatchFlushSize      = 100
+	DefaultBatchResultsLimit   = 100
+	MaxBatchResultsLimit       = 1000
+	DefaultBatchStaleAfter     = 15 * time.Minute
+)
+
+// batchItemErrorLength caps the error message kept with a failed item
+const batchItemErrorLength = 500
+
+// BatchPurpose is why a batch of applications is decided
+type BatchPurpose string
+
+const (
+	// BatchPurposePortfolioRescore re-decides existing applications, e.g. after a rule change
+	BatchPurposePortfolioRescore BatchPurpose = "PORTFOLIO_RESCORE"
+	// BatchPurposePreApproval decides prospects for marketing pre-approval offers
+	BatchPurposePreApproval BatchPurpose = "PRE_APPROVAL"
+)
+
+// IsValid reports whether p is a known batch purpose
+func (p BatchPurpose) IsValid() bool {
+	return p == BatchPurposePortfolioRescore || p == BatchPurposePreApproval
+}
+
+// BatchJobStatus is the state of a batch decision job
+type BatchJobStatus string
+
+const (
+	BatchJobPending   BatchJobStatus = "PENDING"
+	BatchJobRunning   BatchJobStatus = "RUNNING"
+	BatchJobCompleted BatchJobStatus = "COMPLETED"
+	// BatchJobFailed means the job stopped before every application was decided, e.g. because
+	// its results could not be saved or the service restarted
+	BatchJobFailed BatchJobStatus = "FAILED"
+)
+
+// IsFinished reports whether the job has stopped running
+func (s BatchJobStatus) IsFinished() bool {
+	return s == BatchJobCompleted || s == BatchJobFailed
+}
+
+// BatchItemStatus is the outcome of deciding one application in a batch
+type BatchItemStatus string
+
+const (
+	BatchItemSucceeded BatchItemStatus = "SUCCEEDED"
+	BatchItemFailed    BatchItemStatus = "FAILED"
+)
+
+// IsValid reports whether s is a known item status
+func (s BatchItemStatus) IsValid() bool {
+	return s == BatchItemSucceeded || s == BatchItemFailed
+}
+
+// BatchPolicy limits batch decisioning
+type BatchPolicy struct {
+	MaxItems           int // applications accepted per batch
+	DefaultConcurrency int // applications decided at once by a job that does not ask
+	MaxConcurrency     int // applications decided at once across all jobs
+	MaxActiveJobs      int // jobs pending or running at once
+	FlushSize          int // results saved per write
+	// StaleAfter is how long an unfinished job may go without saving progress before it is
+	// taken to have been cut off and marked failed
+	StaleAfter time.Duration
+}
+
+// WithDefaults fills unset limits with the defaults
+func (p BatchPolicy) WithDefaults() BatchPolicy {
+	if p.MaxItems <= 0 {
+		p.MaxItems = DefaultBatchMaxItems
+	}
+	if p.MaxConcurrency <= 0 {
+		p.MaxConcurrency = DefaultBatchMaxConcurrency
+	}
+	if p.DefaultConcurrency <= 0 {
+		p.DefaultConcurrency = DefaultBatchConcurrency
+	}
+	if p.DefaultConcurrency > p.MaxConcurrency {
+		p.DefaultConcurrency = p.MaxConcurrency
+	}
+	if p.MaxActiveJobs <= 0 {
+		p.MaxActiveJobs = DefaultBatchMaxActiveJobs
+	}
+	if p.FlushSize <= 0 {
+		p.FlushSize = DefaultBatchFlushSize
+	}
+	if p.StaleAfter <= 0 {
+		p.StaleAfter = DefaultBatchStaleAfter
+	}
+	return p
+}
+
+// BatchDecisionRequest asks for a batch of applications to be decided asynchronously
+type BatchDecisionRequest struct {
+	Purpose      BatchPurpose      `json:"purpose"`
+	Reference    string            `json:"reference,omitempty"`   // caller's label, e.g. a campaign id
+	Concurrency  int               `json:"concurrency,omitempty"` // applications decided at once, capped by the service
+	Applications []DecisionRequest `json:"applications"`
+}
+
+// Normalize applies the policy's defaults and limits and checks the batch can be accepted.
+// Applications are validated one by one when they are decided.
+func (r *BatchDecisionRequest) Normalize(policy BatchPolicy) error {
+	r.Purpose = BatchPurpose(strings.ToUpper(string(r.Purpose)))
+	if !r.Purpose.IsValid() {
+		return fmt.Errorf("purpose must be %s or %s", BatchPurposePortfolioRescore, BatchPurposePreApproval)
+	}
+	if len(r.Applications) == 0 {
+		return errors.New("at least one application is required")
+	}
+	if len(r.Applications) > policy.MaxItems {
+		return fmt.Errorf("a batch may hold at most %d applications", policy.MaxItems)
+	}
+	if r.Concurrency < 0 {
+		return errors.New("concurrency must not be negative")
+	}
+	if r.Concurrency == 0 {
+		r.Concurrency = policy.DefaultConcurrency
+	}
+	if r.Concurrency > policy.MaxConcurrency {
+		r.Concurrency = policy.MaxConcurrency
+	}
+	return nil
+}
+
+// BatchJob tracks the asynchronous evaluation of a batch of applications
+type BatchJob struct {
+	ID          int64                `json:"id"`
+	Purpose     BatchPurpose         `json:"purpose"`
+	Reference   string               `json:"reference,omitempty"`
+	Status      BatchJobStatus       `json:"status"`
+	Concurrency int                  `json:"concurrency"`
+	Total       int                  `json:"total"`
+	Processed   int                  `json:"processed"`
+	Succeeded   int                  `json:"succeeded"`
+	Failed      int                  `json:"failed"`
+	Decisions   map[DecisionType]int `json:"decisions"` // succeeded applications by decision
+	Error       string               `json:"error,omitempty"`
+	RequestedBy string               `json:"requested_by,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+	StartedAt   *time.Time           `json:"started_at,omitempty"`
+	CompletedAt *time.Time           `json:"completed_at,omitempty"`
+}
+
+// Record counts a decided application towards the job's progress
+func (j *BatchJob) Record(result *BatchItemResult) {
+	j.Processed++
+	if result.Status == BatchItemFailed {
+		j.Failed++
+		return
+	}
+	j.Succeeded++
+	if j.Decisions == nil {
+		j.Decisions = map[DecisionType]int{}
+	}
+	j.Decisions[result.Decision]++
+}
+
+// BatchItemResult is the outcome of deciding one application in a batch. Successful results
+// point at the decision saved for the application.
+type BatchItemResult struct {
+	JobID         int64           `json:"job_id"`
+	ItemIndex     int             `json:"item_index"` // position in the submitted batch
+	ApplicationID string          `json:"application_id"`
+	Status        BatchItemStatus `json:"status"`
+	DecisionID    int64           `json:"decision_id,omitempty"`
+	Decision      DecisionType    `json:"decision,omitempty"`
+	RiskCategory  RiskCategory    `json:"risk_category,omitempty"`
+	MaxAmount     float64         `json:"max_amount,omitempty"`
+	InterestRate  float64         `json:"interest_rate,omitempty"`
+	ErrorCode     string          `json:"error_code,omitempty"`
+	ErrorMessage  string          `json:"error_message,omitempty"`
+	ProcessedAt   time.Time       `json:"processed_at"`
+}
+
+// Fail marks the result failed with an error, keeping DecisionError codes
+func (r *BatchItemResult) Fail(err error) {
+	r.Status = BatchItemFailed
+	r.DecisionID, r.Decision, r.RiskCategory, r.MaxAmount, r.InterestRate = 0, "", "", 0, 0
+	r.ErrorCode = ERROR_RULE_EVALUATION
+	r.ErrorMessage = err.Error()
+	var decisionErr *DecisionError
+	if errors.As(err, &decisionErr) {
+		r.ErrorCode = decisionErr.Code
+		if decisionErr.Description != "" {
+			r.ErrorMessage = decisionErr.Message + ": " + decisionErr.Description
+		}
+	}
+	if len(r.ErrorMessage) > batchItemErrorLength {
+		r.ErrorMessage = r.ErrorMessage[:batchItemErrorLength]
+	}
+}
+
+// BatchResultsQuery pages through a job's item results
+type BatchResultsQuery struct {
+	JobID  int64
+	Status BatchItemStatus // optional
+	Limit  int
+	Offset int
+}
+
+// BatchResultsPage is one page of a job's item results
+type BatchResultsPage struct {
+	JobID   int64             `json:"job_id"`
+	Total   int               `json:"total"` // results matching the query
+	Limit   int               `json:"limit"`
+	Offset  int               `json:"offset"`
+	Results []BatchItemResult `json:"results"`
+}
+
+// BatchRepository persists batch decision jobs and their item results
+type BatchRepository interface {
+	// CreateBatchJob saves a new job, setting its id
+	CreateBatchJob(ctx context.Context, job *BatchJob) error
+	// UpdateBatchJob saves a job's status, progress and update time
+	UpdateBatchJob(ctx context.Context, job *BatchJob) error
+	GetBatchJob(ctx context.Context, id int64) (*BatchJob, error)
+	// SaveBatchResults saves item results in one write
+	SaveBatchResults(ctx context.Context, results []BatchItemResult) error
+	GetBatchResults(ctx context.Context, query BatchResultsQuery) (*BatchResultsPage, error)
+	// FailStaleBatchJobs marks pending and running jobs not updated since staleBefore, e.g.
+	// because their instance stopped, failed and returns how many there were
+	FailStaleBatchJobs(ctx context.Context, staleBefore time.Time, reason string, at time.Time) (int, error)
+}
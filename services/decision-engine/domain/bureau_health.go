@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// BreakerState is the state of a bureau's circuit breaker
+type BreakerState string
+
+const (
+	// BreakerClosed lets pulls through
+	BreakerClosed BreakerState = "CLOSED"
+	// BreakerOpen rejects pulls until the open timeout passes
+	BreakerOpen BreakerState = "OPEN"
+	// BreakerHalfOpen lets one trial pull through; its outcome closes or reopens the breaker
+	BreakerHalfOpen BreakerState = "HALF_OPEN"
+)
+
+// BureauHealth is the circuit breaker state of one bureau
+type BureauHealth struct {
+	Bureau              Bureau       `json:"bureau"`
+	State               BreakerState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	LastError           string       `json:"last_error,omitempty"`
+	LastFailureAt       *time.Time   `json:"last_failure_at,omitempty"`
+	LastSuccessAt       *time.Time   `json:"last_success_at,omitempty"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+	RetryAt             *time.Time   `json:"retry_at,omitempty"` // when an open breaker lets a trial pull through
+}
+
+// BureauPullRequest pulls one bureau report, failing over to the other bureaus in order when
+// the preferred bureau is unavailable
+type BureauPullRequest struct {
+	CreditReportRequest
+	PreferredBureau Bureau `json:"preferred_bureau,omitempty"` // defaults to the first bureau
+}
+
+// BureauPullResponse is a single-bureau report and the pulls it took to get it
+type BureauPullResponse struct {
+	Report     *BureauReport      `json:"report"`
+	FailedOver bool               `json:"failed_over"`
+	Attempts   []BureauPullResult `json:"attempts"`
+}
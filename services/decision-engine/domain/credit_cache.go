@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultCreditReportCacheTTL is how long a pulled bureau report is reused when no TTL is configured
+const DefaultCreditReportCacheTTL = 30 * 24 * time.Hour
+
+// CreditReportCachePolicy decides whether a stored bureau report can be reused instead of
+// pulling the bureau again. A zero TTL disables reuse.
+type CreditReportCachePolicy struct {
+	TTL time.Duration
+}
+
+// Fresh reports whether a report pulled at pulledAt can still be reused at now
+func (p CreditReportCachePolicy) Fresh(pulledAt, now time.Time) bool {
+	return p.TTL > 0 && now.Sub(pulledAt) < p.TTL
+}
+
+// Remaining is how much longer a report pulled at pulledAt can be reused
+func (p CreditReportCachePolicy) Remaining(pulledAt, now time.Time) time.Duration {
+	if !p.Fresh(pulledAt, now) {
+		return 0
+	}
+	return p.TTL - now.Sub(pulledAt)
+}
+
+// CreditReportIndex points at the latest stored report for an SSN token and bureau, so cache
+// lookups can skip searching the stored reports. Entries expire with the report's TTL.
+type CreditReportIndex interface {
+	// LookupBureauReport returns the indexed report id, or 0 when nothing is indexed
+	LookupBureauReport(ctx context.Context, ssnToken string, bureau Bureau) (int64, error)
+	IndexBureauReport(ctx context.Context, ssnToken string, bureau Bureau, reportID int64, ttl time.Duration) error
+	ForgetBureauReport(ctx context.Context, ssnToken string, bureau Bureau) error
+}
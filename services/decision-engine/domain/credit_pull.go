@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"strings"
+)
+
+// PullType is the kind of credit pull a request makes. Soft pulls, used for pre-qualification,
+// leave no inquiry other lenders can see; hard pulls are made for submitted applications and
+// are reported as inquiries.
+type PullType string
+
+const (
+	PullTypeSoft PullType = InquiryTypeSoft
+	PullTypeHard PullType = InquiryTypeHard
+)
+
+// IsValid checks if the pull type is SOFT or HARD
+func (p PullType) IsValid() bool {
+	return p == PullTypeSoft || p == PullTypeHard
+}
+
+// Serves reports whether a report pulled with this pull type may answer a request for the
+// requested one: a hard report serves any request, a soft report only soft requests
+func (p PullType) Serves(requested PullType) bool {
+	return p == PullTypeHard || requested == PullTypeSoft
+}
+
+// ReusablePullTypes lists the pull types of stored reports that may answer a request of this
+// pull type
+func (p PullType) ReusablePullTypes() []PullType {
+	if p == PullTypeSoft {
+		return []PullType{PullTypeSoft, PullTypeHard}
+	}
+	return []PullType{PullTypeHard}
+}
+
+// EffectivePullType returns the request's pull type, falling back to its report type for
+// callers that predate pull types. Anything not marked soft is a hard pull.
+func (r *CreditReportRequest) EffectivePullType() PullType {
+	pullType := string(r.PullType)
+	if pullType == "" {
+		pullType = r.ReportType
+	}
+	if IsHardInquiry(pullType) {
+		return PullTypeHard
+	}
+	return PullTypeSoft
+}
+
+// ValidPullType reports whether the request's pull type, when set, is SOFT or HARD
+func (r *CreditReportRequest) ValidPullType() bool {
+	return r.PullType == "" || PullType(strings.ToUpper(string(r.PullType))).IsValid()
+}
+
+// CreditPullEligibility is the loan service's view of whether an application may trigger a
+// hard credit pull: it must have been submitted, must still be open and the borrower must have
+// consented to the pull
+type CreditPullEligibility struct {
+	ApplicationID string `json:"application_id"`
+	UserID        string `json:"user_id"`
+	State         string `json:"state"`
+	Submitted     bool   `json:"submitted"`
+	Consented     bool   `json:"consented"`
+	Eligible      bool   `json:"eligible"`
+}
+
+// ApplicationVerifier checks with the loan service whether an application may trigger a hard
+// credit pull; an unknown application returns nil
+type ApplicationVerifier interface {
+	GetCreditPullEligibility(ctx context.Context, applicationID string) (*CreditPullEligibility, error)
+}
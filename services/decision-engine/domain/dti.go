@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"encoding/json"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/dti"
+	"github.com/huuhoait/los-demo/services/shared/pkg/income"
+)
+
+// Obligations and income sources the loan service may supply in AdditionalData for the DTI debt
+// treatments and income rules, and the qualifying DTI the decision engine records there so replays
+// see the same ratio
+const (
+	AdditionalDataDTIObligations = "dti_obligations" // student loans, alimony and rental income, see dti.Obligations
+	AdditionalDataIncomeSources  = "income_sources"  // documented income by source, see income.Sources
+	AdditionalDataQualifyingDTI  = "qualifying_dti"
+)
+
+// DTIObligations returns the obligations supplied with the request, nil when none were
+func (dr *DecisionRequest) DTIObligations() *dti.Obligations {
+	var obligations dti.Obligations
+	if !decodeAdditionalData(dr.AdditionalData[AdditionalDataDTIObligations], &obligations) {
+		return nil
+	}
+	return &obligations
+}
+
+// IncomeSources returns the documented income sources supplied with the request, nil when none
+// were
+func (dr *DecisionRequest) IncomeSources() *income.Sources {
+	var sources income.Sources
+	if !decodeAdditionalData(dr.AdditionalData[AdditionalDataIncomeSources], &sources) {
+		return nil
+	}
+	return &sources
+}
+
+// DTIBorrower returns the applicant's figures for the DTI calculator
+func (dr *DecisionRequest) DTIBorrower() dti.Borrower {
+	return dti.Borrower{
+		MonthlyIncome: dr.MonthlyIncome,
+		MonthlyDebt:   dr.MonthlyDebt,
+		Obligations:   dr.DTIObligations(),
+		IncomeSources: dr.IncomeSources(),
+	}
+}
+
+// QualifyingDTI returns the qualifying DTI recorded with the request, or nil when none was
+func (dr *DecisionRequest) QualifyingDTI() *dti.Result {
+	if result, ok := dr.AdditionalData[AdditionalDataQualifyingDTI].(*dti.Result); ok {
+		return result
+	}
+	var result dti.Result
+	if !decodeAdditionalData(dr.AdditionalData[AdditionalDataQualifyingDTI], &result) {
+		return nil
+	}
+	return &result
+}
+
+// RecordQualifyingDTI records a qualifying DTI with the request
+func (dr *DecisionRequest) RecordQualifyingDTI(result *dti.Result) {
+	if dr.AdditionalData == nil {
+		dr.AdditionalData = make(map[string]interface{})
+	}
+	dr.AdditionalData[AdditionalDataQualifyingDTI] = result
+}
+
+// decodeAdditionalData converts a value held in AdditionalData, as given or as decoded JSON, into
+// target; false when there is no value or it does not convert
+func decodeAdditionalData(value interface{}, target interface{}) bool {
+	if value == nil {
+		return false
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, target) == nil
+}
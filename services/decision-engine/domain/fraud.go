@@ -0,0 +1,193 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Fraud screening defaults. The deny score matches the knockout_fraud_score rule, which denies
+// decisions at the same score.
+const (
+	DefaultFraudReviewScore       = 0.7
+	DefaultFraudSignalReviewScore = 0.8
+	DefaultFraudDenyScore         = 0.9
+)
+
+// Applicant details the loan service may supply in AdditionalData for fraud screening, and the
+// screening result the decision engine records there so replays see the same screening
+const (
+	AdditionalDataDeviceID       = "device_id"
+	AdditionalDataIPAddress      = "ip_address"
+	AdditionalDataEmail          = "email"
+	AdditionalDataPhone          = "phone"
+	AdditionalDataSSNToken       = "ssn_token"
+	AdditionalDataFraudScreening = "fraud_screening"
+)
+
+// FraudSignalCategory groups a fraud vendor's signals
+type FraudSignalCategory string
+
+const (
+	FraudSignalDevice    FraudSignalCategory = "DEVICE"    // device reputation and velocity
+	FraudSignalIdentity  FraudSignalCategory = "IDENTITY"  // identity theft and mismatched identity elements
+	FraudSignalSynthetic FraudSignalCategory = "SYNTHETIC" // synthetic identities built from real and invented data
+)
+
+// FraudRecommendation is what a fraud screening recommends doing with an application
+type FraudRecommendation string
+
+const (
+	FraudRecommendationClear  FraudRecommendation = "CLEAR"
+	FraudRecommendationReview FraudRecommendation = "REVIEW"
+	FraudRecommendationDeny   FraudRecommendation = "DENY"
+)
+
+// FraudSignal is one signal behind a fraud score; Score is normalized to 0 (clean) to 1
+type FraudSignal struct {
+	Category    FraudSignalCategory `json:"category"`
+	Code        string              `json:"code"`
+	Description string              `json:"description,omitempty"`
+	Score       float64             `json:"score"`
+}
+
+// FraudScreeningRequest is an application sent to the fraud vendor
+type FraudScreeningRequest struct {
+	ApplicationID string  `json:"application_id" binding:"required"`
+	UserID        string  `json:"user_id" binding:"required"`
+	LoanAmount    float64 `json:"loan_amount"`
+	DeviceID      string  `json:"device_id,omitempty"`
+	IPAddress     string  `json:"ip_address,omitempty"`
+	Email         string  `json:"email,omitempty"`
+	Phone         string  `json:"phone,omitempty"`
+	SSNToken      string  `json:"ssn_token,omitempty"` // vault token; the vendor adapter detokenizes it if needed
+}
+
+// FraudScreeningResult is a fraud vendor's assessment of an application. Score is normalized to
+// 0 (clean) to 1 whatever scale the vendor scores on; RawScore keeps the vendor's own score.
+type FraudScreeningResult struct {
+	Vendor         string              `json:"vendor"`
+	Score          float64             `json:"score"`
+	RawScore       float64             `json:"raw_score"`
+	Signals        []FraudSignal       `json:"signals,omitempty"`
+	ReasonCodes    []string            `json:"reason_codes,omitempty"`
+	Recommendation FraudRecommendation `json:"recommendation,omitempty"`
+	ReviewReasons  []string            `json:"review_reasons,omitempty"`
+	Unavailable    bool                `json:"unavailable,omitempty"` // the vendor could not be reached
+	ScreenedAt     time.Time           `json:"screened_at"`
+}
+
+// FraudDetectionService screens applications with a fraud vendor
+type FraudDetectionService interface {
+	Screen(ctx context.Context, request *FraudScreeningRequest) (*FraudScreeningResult, error)
+}
+
+// FraudPolicy sets the fraud scores that route applications to manual review. Scores at or above
+// DenyScore are denied by the knockout_fraud_score rule in decisions; DenyScore only sets the
+// recommendation on screenings requested directly.
+type FraudPolicy struct {
+	ReviewScore           float64 // overall score routing an application to review
+	SignalReviewScore     float64 // score of any one signal routing an application to review
+	DenyScore             float64
+	ReviewWhenUnavailable bool // route applications to review when the vendor cannot be reached
+}
+
+// WithDefaults fills unset scores with the defaults
+func (p FraudPolicy) WithDefaults() FraudPolicy {
+	if p.ReviewScore <= 0 {
+		p.ReviewScore = DefaultFraudReviewScore
+	}
+	if p.SignalReviewScore <= 0 {
+		p.SignalReviewScore = DefaultFraudSignalReviewScore
+	}
+	if p.DenyScore <= 0 {
+		p.DenyScore = DefaultFraudDenyScore
+	}
+	return p
+}
+
+// Assess sets a screening's review reasons and recommendation under the policy
+func (p FraudPolicy) Assess(result *FraudScreeningResult) {
+	result.ReviewReasons = nil
+	switch {
+	case result.Unavailable:
+		if p.ReviewWhenUnavailable {
+			result.ReviewReasons = append(result.ReviewReasons, "Fraud screening unavailable")
+		}
+	case result.Score >= p.DenyScore:
+		result.Recommendation = FraudRecommendationDeny
+		return
+	case result.Score >= p.ReviewScore:
+		result.ReviewReasons = append(result.ReviewReasons, fmt.Sprintf("Fraud score %.2f at or above %.2f", result.Score, p.ReviewScore))
+	}
+	for _, signal := range result.Signals {
+		if signal.Score >= p.SignalReviewScore {
+			result.ReviewReasons = append(result.ReviewReasons,
+				fmt.Sprintf("%s fraud signal %s scored %.2f", signal.Category, signal.Code, signal.Score))
+		}
+	}
+
+	result.Recommendation = FraudRecommendationClear
+	if len(result.ReviewReasons) > 0 {
+		result.Recommendation = FraudRecommendationReview
+	}
+}
+
+// FraudScreeningRequest builds the fraud screening request for a decision request
+func (dr *DecisionRequest) FraudScreeningRequest() *FraudScreeningRequest {
+	detail := func(key string) string {
+		value, _ := dr.AdditionalData[key].(string)
+		return value
+	}
+	return &FraudScreeningRequest{
+		ApplicationID: dr.ApplicationID,
+		UserID:        dr.UserID,
+		LoanAmount:    dr.LoanAmount,
+		DeviceID:      detail(AdditionalDataDeviceID),
+		IPAddress:     detail(AdditionalDataIPAddress),
+		Email:         detail(AdditionalDataEmail),
+		Phone:         detail(AdditionalDataPhone),
+		SSNToken:      detail(AdditionalDataSSNToken),
+	}
+}
+
+// HasFraudScore reports whether the request carries a fraud score, supplied by the caller or
+// recorded by an earlier screening
+func (dr *DecisionRequest) HasFraudScore() bool {
+	_, ok := dr.AdditionalData[AdditionalDataFraudScore].(float64)
+	return ok
+}
+
+// FraudScreening returns the fraud screening recorded with the request, or nil when none was. A
+// request loaded from storage holds the screening as decoded JSON, which is converted back.
+func (dr *DecisionRequest) FraudScreening() *FraudScreeningResult {
+	switch screening := dr.AdditionalData[AdditionalDataFraudScreening].(type) {
+	case nil:
+		return nil
+	case *FraudScreeningResult:
+		return screening
+	default:
+		data, err := json.Marshal(screening)
+		if err != nil {
+			return nil
+		}
+		var result FraudScreeningResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil
+		}
+		return &result
+	}
+}
+
+// RecordFraudScreening records a screening with the request; a completed screening also supplies
+// the fraud_score fact the knockout rules read
+func (dr *DecisionRequest) RecordFraudScreening(result *FraudScreeningResult) {
+	if dr.AdditionalData == nil {
+		dr.AdditionalData = make(map[string]interface{})
+	}
+	dr.AdditionalData[AdditionalDataFraudScreening] = result
+	if !result.Unavailable {
+		dr.AdditionalData[AdditionalDataFraudScore] = result.Score
+	}
+}
@@ -0,0 +1,203 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Income estimation defaults
+const (
+	DefaultIncomeVarianceThreshold = 0.5 // stated income 50% above the estimate forces verification
+	DefaultIncomeTradelineWeight   = 0.4 // weight of the tradeline estimate when the report has one
+	IncomeEstimateModelVersion     = "income-estimate-1.0"
+)
+
+// Applicant details the loan service may supply in AdditionalData for income estimation, and the
+// estimate the decision engine records there so replays see the same estimate
+const (
+	AdditionalDataOccupation     = "occupation"       // occupation group, see OccupationMedianIncomes
+	AdditionalDataRegion         = "region"           // two-letter state code
+	AdditionalDataCreditReportID = "credit_report_id" // tri-merge report whose tradelines are used
+	AdditionalDataIncomeEstimate = "income_estimate"
+)
+
+// OccupationMedianIncomes are median annual incomes by occupation group, in dollars
+var OccupationMedianIncomes = map[string]float64{
+	"management":     110000,
+	"professional":   90000,
+	"healthcare":     80000,
+	"technical":      75000,
+	"education":      58000,
+	"sales":          55000,
+	"trades":         55000,
+	"administrative": 45000,
+	"service":        35000,
+}
+
+// DefaultOccupationMedianIncome is used for occupations outside OccupationMedianIncomes
+const DefaultOccupationMedianIncome = 50000
+
+// RegionIncomeFactors adjust the occupation median for the cost of living in a state; states
+// without a factor use 1
+var RegionIncomeFactors = map[string]float64{
+	"CA": 1.2, "NY": 1.2, "MA": 1.2, "WA": 1.15, "NJ": 1.15, "CT": 1.15, "MD": 1.15, "DC": 1.25, "HI": 1.15,
+	"MS": 0.85, "AR": 0.85, "WV": 0.85, "AL": 0.88, "KY": 0.88, "OK": 0.88, "NM": 0.88, "LA": 0.88, "SD": 0.9,
+}
+
+// Tradeline income multiples: lenders typically extend mortgages of about four times income and
+// revolving limits of about half of income, so open balances and limits imply an income
+const (
+	mortgageIncomeMultiple  = 4.0
+	revolvingIncomeMultiple = 0.5
+)
+
+// IncomeEstimationPolicy configures the income reasonableness check
+type IncomeEstimationPolicy struct {
+	VarianceThreshold float64 // stated income above the estimate by more than this share is flagged
+	TradelineWeight   float64 // weight of the tradeline estimate, 0 to 1
+}
+
+// WithDefaults fills unset settings with the defaults
+func (p IncomeEstimationPolicy) WithDefaults() IncomeEstimationPolicy {
+	if p.VarianceThreshold <= 0 {
+		p.VarianceThreshold = DefaultIncomeVarianceThreshold
+	}
+	if p.TradelineWeight <= 0 || p.TradelineWeight > 1 {
+		p.TradelineWeight = DefaultIncomeTradelineWeight
+	}
+	return p
+}
+
+// IncomeEstimate compares stated income with the income expected for the applicant. Variance is
+// the share by which stated income exceeds the estimate; negative when stated income is lower.
+type IncomeEstimate struct {
+	ModelVersion       string  `json:"model_version"`
+	Occupation         string  `json:"occupation,omitempty"`
+	Region             string  `json:"region,omitempty"`
+	OccupationEstimate float64 `json:"occupation_estimate"` // occupation median adjusted for region
+	TradelineEstimate  float64 `json:"tradeline_estimate,omitempty"`
+	EstimatedIncome    float64 `json:"estimated_income"`
+	StatedIncome       float64 `json:"stated_income"`
+	Variance           float64 `json:"variance"`
+	Threshold          float64 `json:"threshold"`
+	VarianceFlag       bool    `json:"variance_flag"` // stated income must be verified
+	Reason             string  `json:"reason,omitempty"`
+}
+
+// EstimateIncome estimates an applicant's annual income from occupation and region, blended with
+// the income implied by their tradelines when any are given, and compares it with stated income
+func (p IncomeEstimationPolicy) EstimateIncome(occupation, region string, statedIncome float64, tradelines []MergedTradeline) *IncomeEstimate {
+	occupation = strings.ToLower(strings.TrimSpace(occupation))
+	region = strings.ToUpper(strings.TrimSpace(region))
+
+	median, ok := OccupationMedianIncomes[occupation]
+	if !ok {
+		median = DefaultOccupationMedianIncome
+	}
+	factor, ok := RegionIncomeFactors[region]
+	if !ok {
+		factor = 1
+	}
+
+	estimate := &IncomeEstimate{
+		ModelVersion:       IncomeEstimateModelVersion,
+		Occupation:         occupation,
+		Region:             region,
+		OccupationEstimate: math.Round(median * factor),
+		StatedIncome:       statedIncome,
+	}
+	estimate.TradelineEstimate = math.Round(TradelineImpliedIncome(tradelines))
+	estimate.EstimatedIncome = estimate.OccupationEstimate
+	if estimate.TradelineEstimate > 0 {
+		estimate.EstimatedIncome = math.Round(estimate.OccupationEstimate*(1-p.TradelineWeight) + estimate.TradelineEstimate*p.TradelineWeight)
+	}
+	p.Assess(estimate)
+	return estimate
+}
+
+// Assess sets an estimate's variance flag under the policy
+func (p IncomeEstimationPolicy) Assess(estimate *IncomeEstimate) {
+	estimate.Threshold = p.VarianceThreshold
+	estimate.Variance = 0
+	if estimate.EstimatedIncome > 0 {
+		estimate.Variance = math.Round((estimate.StatedIncome/estimate.EstimatedIncome-1)*1000) / 1000
+	}
+	estimate.VarianceFlag = estimate.Variance > p.VarianceThreshold
+	estimate.Reason = ""
+	if estimate.VarianceFlag {
+		estimate.Reason = fmt.Sprintf("Stated income %.0f is %.0f%% above the estimated income %.0f",
+			estimate.StatedIncome, estimate.Variance*100, estimate.EstimatedIncome)
+	}
+}
+
+// TradelineImpliedIncome estimates annual income from open tradelines: the larger of the income
+// implied by mortgage balances and by revolving credit limits. It is zero without either.
+func TradelineImpliedIncome(tradelines []MergedTradeline) float64 {
+	var mortgageBalance, revolvingLimit float64
+	for _, tradeline := range tradelines {
+		if !tradeline.IsActive {
+			continue
+		}
+		switch strings.ToUpper(tradeline.AccountType) {
+		case "MORTGAGE":
+			mortgageBalance += tradeline.Balance
+		case "CREDIT_CARD":
+			revolvingLimit += tradeline.CreditLimit
+		}
+	}
+	return math.Max(mortgageBalance/mortgageIncomeMultiple, revolvingLimit/revolvingIncomeMultiple)
+}
+
+// IncomeEstimate returns the income estimate recorded with the request, or nil when none was. A
+// request loaded from storage holds the estimate as decoded JSON, which is converted back.
+func (dr *DecisionRequest) IncomeEstimate() *IncomeEstimate {
+	switch estimate := dr.AdditionalData[AdditionalDataIncomeEstimate].(type) {
+	case nil:
+		return nil
+	case *IncomeEstimate:
+		return estimate
+	default:
+		data, err := json.Marshal(estimate)
+		if err != nil {
+			return nil
+		}
+		var result IncomeEstimate
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil
+		}
+		return &result
+	}
+}
+
+// RecordIncomeEstimate records an income estimate with the request
+func (dr *DecisionRequest) RecordIncomeEstimate(estimate *IncomeEstimate) {
+	if dr.AdditionalData == nil {
+		dr.AdditionalData = make(map[string]interface{})
+	}
+	dr.AdditionalData[AdditionalDataIncomeEstimate] = estimate
+}
+
+// CreditReportID returns the tri-merge report id supplied with the request, zero when none was.
+// Decoded JSON numbers are float64.
+func (dr *DecisionRequest) CreditReportID() int64 {
+	switch id := dr.AdditionalData[AdditionalDataCreditReportID].(type) {
+	case float64:
+		return int64(id)
+	case int64:
+		return id
+	case int:
+		return int64(id)
+	}
+	return 0
+}
+
+// IncomeVariance returns the income variance of the estimate recorded with the request, zero
+// when none was
+func (dr *DecisionRequest) IncomeVariance() float64 {
+	if estimate := dr.IncomeEstimate(); estimate != nil {
+		return estimate.Variance
+	}
+	return 0
+}
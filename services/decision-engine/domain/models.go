@@ -2,11 +2,15 @@ package domain
 
 import (
 	"context"
+	"strings"
 	"time"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/dti"
 )
 
 // DecisionRequest represents a loan decision request
 type DecisionRequest struct {
+	TenantID       string                 `json:"tenant_id,omitempty"` // lending program; the default program when empty
 	ApplicationID  string                 `json:"application_id" validate:"required"`
 	UserID         string                 `json:"user_id" validate:"required"`
 	CustomerID     string                 `json:"customer_id"`
@@ -19,44 +23,70 @@ type DecisionRequest struct {
 	RequestedTerm  int                    `json:"requested_term" validate:"required,min=12,max=84"`
 	LoanTermMonths int                    `json:"loan_term_months"`
 	LoanPurpose    LoanPurpose            `json:"loan_purpose" validate:"required"`
+	Collateral     *CollateralInfo        `json:"collateral,omitempty"` // secured products only
 	AdditionalData map[string]interface{} `json:"additional_data,omitempty"`
 	RequestedAt    time.Time              `json:"requested_at"`
 }
 
+// CollateralInfo describes the collateral securing a loan and its latest valuation
+type CollateralInfo struct {
+	Type            string    `json:"type"` // vehicle or asset
+	Value           float64   `json:"value"`
+	ValuationSource string    `json:"valuation_source"` // stated when only the borrower's estimate is known
+	ValuedAt        time.Time `json:"valued_at"`
+}
+
 // DecisionResponse represents the decision engine response
 type DecisionResponse struct {
-	ApplicationID   string          `json:"application_id"`
-	Decision        DecisionType    `json:"decision"`
-	RiskScore       float64         `json:"risk_score"`
-	RiskCategory    RiskCategory    `json:"risk_category"`
-	ConfidenceScore float64         `json:"confidence_score"`
-	InterestRate    float64         `json:"interest_rate"`
-	ApprovedAmount  float64         `json:"approved_amount,omitempty"`
-	MaxAmount       float64         `json:"max_amount"`
-	DecisionReason  string          `json:"decision_reason"`
-	Reason          string          `json:"reason"`
-	RiskFactors     []RiskFactor    `json:"risk_factors"`
-	Conditions      []string        `json:"conditions,omitempty"`
-	RequiredDocs    []string        `json:"required_documents,omitempty"`
-	DecisionDate    time.Time       `json:"decision_date"`
-	ExpiresAt       *time.Time      `json:"expires_at,omitempty"`
-	ReviewRequired  bool            `json:"review_required"`
-	ReviewerNotes   string          `json:"reviewer_notes,omitempty"`
-	RiskAssessment  *RiskAssessment `json:"risk_assessment,omitempty"`
-	AppliedRules    []string        `json:"applied_rules,omitempty"`
-	Recommendations []string        `json:"recommendations,omitempty"`
+	DecisionID      int64                 `json:"decision_id,omitempty"` // set once the decision is saved
+	TenantID        string                `json:"tenant_id,omitempty"`   // lending program decided under
+	ApplicationID   string                `json:"application_id"`
+	Decision        DecisionType          `json:"decision"`
+	RiskScore       float64               `json:"risk_score"`
+	RiskCategory    RiskCategory          `json:"risk_category"`
+	CreditScore     int                   `json:"credit_score,omitempty"` // bureau score the decision was made on
+	ConfidenceScore float64               `json:"confidence_score"`
+	InterestRate    float64               `json:"interest_rate"`
+	ApprovedAmount  float64               `json:"approved_amount,omitempty"`
+	RequestedAmount float64               `json:"requested_amount,omitempty"`
+	MaxAmount       float64               `json:"max_amount"`
+	DecisionReason  string                `json:"decision_reason"`
+	Reason          string                `json:"reason"`
+	RiskFactors     []RiskFactor          `json:"risk_factors"`
+	Conditions      []string              `json:"conditions,omitempty"`
+	RequiredDocs    []string              `json:"required_documents,omitempty"`
+	DecisionDate    time.Time             `json:"decision_date"`
+	ExpiresAt       *time.Time            `json:"expires_at,omitempty"`
+	ReviewRequired  bool                  `json:"review_required"`
+	ReviewerNotes   string                `json:"reviewer_notes,omitempty"`
+	RiskAssessment  *RiskAssessment       `json:"risk_assessment,omitempty"`
+	AppliedRules    []string              `json:"applied_rules,omitempty"`
+	RuleVersions    []string              `json:"rule_versions,omitempty"` // id@version of every rule evaluated
+	RuleHits        []RuleHit             `json:"rule_hits,omitempty"`
+	Recommendations []string              `json:"recommendations,omitempty"`
+	Pricing         *DecisionPricing      `json:"pricing,omitempty"`         // risk-based price; none for denials
+	PolicyVersion   string                `json:"policy_version,omitempty"`  // underwriting policy applied, if any was in force
+	Stages          []StageResult         `json:"stages,omitempty"`          // knockout and scoring stage results, in evaluation order
+	Fraud           *FraudScreeningResult `json:"fraud,omitempty"`           // fraud vendor screening, when the engine screened the applicant
+	IncomeEstimate  *IncomeEstimate       `json:"income_estimate,omitempty"` // expected income compared with stated income
+	QualifyingDTI   *dti.Result           `json:"qualifying_dti,omitempty"`  // income and debts behind the DTI ratio, after debt treatments
+	Provisional     bool                  `json:"provisional,omitempty"`     // made without data sources that missed their SLA; re-decided when they arrive
+	PendingSources  []PendingSource       `json:"pending_sources,omitempty"`
+
+	AdverseActionReasons []AdverseActionReason `json:"adverse_action_reasons,omitempty"` // ranked principal reasons for denials and counteroffers
 }
 
 // RiskAssessment contains detailed risk analysis
 type RiskAssessment struct {
-	OverallScore      float64        `json:"overall_score"`
-	CategoryScores    CategoryScores `json:"category_scores"`
-	DTIRatio          float64        `json:"dti_ratio"`
-	LTVRatio          float64        `json:"ltv_ratio,omitempty"`
-	CreditUtilization float64        `json:"credit_utilization,omitempty"`
-	PaymentHistory    PaymentHistory `json:"payment_history"`
-	RiskFactors       []RiskFactor   `json:"risk_factors"`
-	MitigatingFactors []string       `json:"mitigating_factors,omitempty"`
+	OverallScore      float64          `json:"overall_score"`
+	CategoryScores    CategoryScores   `json:"category_scores"`
+	DTIRatio          float64          `json:"dti_ratio"`
+	LTVRatio          float64          `json:"ltv_ratio,omitempty"`
+	CreditUtilization float64          `json:"credit_utilization,omitempty"`
+	PaymentHistory    PaymentHistory   `json:"payment_history"`
+	RiskFactors       []RiskFactor     `json:"risk_factors"`
+	MitigatingFactors []string         `json:"mitigating_factors,omitempty"`
+	Scorecard         *ScorecardResult `json:"scorecard,omitempty"` // active scorecard's result, blended into OverallScore
 }
 
 // CategoryScores represents risk scores by category
@@ -87,19 +117,32 @@ type RiskFactor struct {
 	Description string  `json:"description"`
 }
 
-// DecisionRule represents a business rule for decision making
+// DecisionRule represents one version of a business rule for decision making. Rules match when
+// their expression (or, without one, all of their conditions) holds; lower priorities are
+// evaluated first. Only drafts may be edited; published versions apply within their effective
+// window until they expire or are retired.
 type DecisionRule struct {
-	ID          string                 `json:"id"`
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	Category    RuleCategory           `json:"category"`
-	Priority    int                    `json:"priority"`
-	Conditions  []RuleCondition        `json:"conditions"`
-	Action      RuleAction             `json:"action"`
-	Active      bool                   `json:"active"`
-	CreatedAt   time.Time              `json:"created_at"`
-	UpdatedAt   time.Time              `json:"updated_at"`
-	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	ID            string                 `json:"id"`
+	TenantID      string                 `json:"tenant_id"` // lending program whose decisions the rule applies to
+	Version       string                 `json:"version"`   // semantic version, e.g. 1.2.0
+	Status        RuleStatus             `json:"status"`
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description"`
+	Category      RuleCategory           `json:"category"`
+	Priority      int                    `json:"priority"`
+	Expression    string                 `json:"expression,omitempty"` // e.g. "credit_score < 600 && !is_secured"
+	Conditions    []RuleCondition        `json:"conditions"`
+	Action        RuleAction             `json:"action"`
+	Knockout      bool                   `json:"knockout"`                 // evaluated in the knockout stage; a match ends the decision
+	EffectiveFrom *time.Time             `json:"effective_from,omitempty"` // defaults to the publish time
+	ExpiresAt     *time.Time             `json:"expires_at,omitempty"`
+	CreatedBy     string                 `json:"created_by"`
+	PublishedBy   string                 `json:"published_by,omitempty"`
+	PublishedAt   *time.Time             `json:"published_at,omitempty"`
+	RetiredAt     *time.Time             `json:"retired_at,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // RuleCondition represents a condition in a decision rule
@@ -117,6 +160,10 @@ type RuleAction struct {
 	Reason        string                 `json:"reason"`
 	Adjustments   map[string]interface{} `json:"adjustments,omitempty"`
 	RequireReview bool                   `json:"require_review"`
+
+	// AdverseActionCode is the reason given to applicants when the rule contributes to an adverse
+	// action. Defaults to the reason for the first fact the rule references.
+	AdverseActionCode AdverseActionCode `json:"adverse_action_code,omitempty"`
 }
 
 // Enums and Constants
@@ -170,7 +217,10 @@ const (
 	RuleCategoryIncome     RuleCategory = "INCOME"
 	RuleCategoryDebt       RuleCategory = "DEBT"
 	RuleCategoryEmployment RuleCategory = "EMPLOYMENT"
+	RuleCategoryCollateral RuleCategory = "COLLATERAL"
 	RuleCategoryGeneral    RuleCategory = "GENERAL"
+	RuleCategoryFraud      RuleCategory = "FRAUD"
+	RuleCategoryCompliance RuleCategory = "COMPLIANCE" // sanctions screening and usury limits
 )
 
 type ActionType string
@@ -198,26 +248,45 @@ type DecisionEngineService interface {
 type RulesEngineService interface {
 	EvaluateRules(request *DecisionRequest, assessment *RiskAssessment) (*DecisionResponse, error)
 	GetActiveRules() ([]DecisionRule, error)
-	AddRule(rule *DecisionRule) error
-	UpdateRule(rule *DecisionRule) error
-	DeleteRule(ruleID string) error
 }
 
 // Repository Interfaces
 type DecisionRepository interface {
-	SaveDecision(ctx context.Context, response *DecisionResponse) error
+	SaveDecision(ctx context.Context, response *DecisionResponse, request *DecisionRequest) error
+	SaveDecisionRequest(ctx context.Context, request *DecisionRequest) error
+	// SaveDecisions saves decisions with the requests they were made on in one transaction,
+	// setting their ids; requests[i] is the request for decisions[i]
+	SaveDecisions(ctx context.Context, decisions []*DecisionResponse, requests []*DecisionRequest) error
+	// GetDecisionInputs returns the request a decision was made on, for replays
+	GetDecisionInputs(ctx context.Context, decisionID int64) (*DecisionInputs, error)
+	GetHistoricalDecisions(ctx context.Context, query DecisionHistoryQuery) ([]HistoricalDecision, error)
 	GetDecision(applicationID string) (*DecisionResponse, error)
+	GetDecisionByID(ctx context.Context, id int64) (*DecisionResponse, error)
 	GetDecisionHistoryByUser(userID string) ([]DecisionResponse, error)
 	UpdateDecision(response *DecisionResponse) error
 }
 
+// ConsentVerifier checks whether a user has an active recorded consent
+type ConsentVerifier interface {
+	HasActiveConsent(ctx context.Context, userID string, consentType ConsentType) (bool, error)
+}
+
+// PIIDetokenizer resolves vault tokens to raw identifiers for bureau calls
+type PIIDetokenizer interface {
+	Detokenize(ctx context.Context, token string) (string, error)
+}
+
 type RulesRepository interface {
-	GetRules() ([]DecisionRule, error)
-	GetRule(ruleID string) (*DecisionRule, error)
-	SaveRule(rule *DecisionRule) error
-	UpdateRule(rule *DecisionRule) error
-	DeleteRule(ruleID string) error
-	GetRulesByCategory(category RuleCategory) ([]DecisionRule, error)
+	GetPublishedRules(ctx context.Context) ([]DecisionRule, error)
+	ListRuleVersions(ctx context.Context, status RuleStatus) ([]DecisionRule, error)
+	GetRuleVersions(ctx context.Context, ruleID string) ([]DecisionRule, error)
+	GetRuleVersion(ctx context.Context, ruleID, version string) (*DecisionRule, error)
+	CreateRuleVersion(ctx context.Context, rule *DecisionRule) error
+	UpdateDraft(ctx context.Context, rule *DecisionRule, performedBy string) error
+	DeleteDraft(ctx context.Context, ruleID, version, performedBy string) error
+	PublishRuleVersion(ctx context.Context, rule *DecisionRule) error
+	RetireRuleVersion(ctx context.Context, ruleID, version, performedBy string, retiredAt time.Time) error
+	GetRuleEvents(ctx context.Context, ruleID string) ([]RuleEvent, error)
 }
 
 // Value Objects
@@ -244,6 +313,15 @@ var (
 	MaxDTIRatio     = 0.45
 	MinAnnualIncome = 25000.0
 
+	// MaxLTVRatios caps the loan-to-value ratio of secured loans by collateral type; vehicles may be
+	// financed above their value to cover taxes and fees
+	MaxLTVRatios = map[string]float64{
+		CollateralTypeVehicle: 1.25,
+		CollateralTypeAsset:   0.80,
+	}
+	// MaxValuationAge is how old a collateral valuation may be before it must be refreshed
+	MaxValuationAge = 90 * 24 * time.Hour
+
 	CreditScoreRanges = map[RiskCategory]CreditScoreRange{
 		RiskLow:      {740, 850},
 		RiskMedium:   {670, 739},
@@ -259,14 +337,101 @@ var (
 	}
 )
 
+// Collateral types and the valuation source of borrower-stated values
+const (
+	CollateralTypeVehicle = "vehicle"
+	CollateralTypeAsset   = "asset"
+
+	ValuationSourceStated = "stated"
+)
+
 // Validation Methods
+
+// CalculateDTI returns the qualifying DTI recorded with the request, which applies the debt
+// treatments to the applicant's obligations, or stated debt over stated income when none was
 func (dr *DecisionRequest) CalculateDTI() float64 {
+	if qualifying := dr.QualifyingDTI(); qualifying != nil {
+		return qualifying.Ratio
+	}
 	if dr.MonthlyIncome <= 0 {
 		return 0
 	}
 	return dr.MonthlyDebt / dr.MonthlyIncome
 }
 
+// Screening signals supplied in AdditionalData by the loan service
+const (
+	AdditionalDataFraudScore     = "fraud_score"      // fraud vendor score, 0 (clean) to 1
+	AdditionalDataOFACMatch      = "ofac_match"       // the applicant matched a sanctions list
+	AdditionalDataUsuryRateLimit = "usury_rate_limit" // maximum annual rate allowed in the borrower's state, in percent
+)
+
+// AdditionalDataRateFloor records the lowest rate the lending program prices at, so replays and
+// simulations compare usury limits with the same floor
+const AdditionalDataRateFloor = "rate_floor"
+
+// FraudScore returns the fraud score supplied with the request, zero when none was
+func (dr *DecisionRequest) FraudScore() float64 {
+	score, _ := dr.AdditionalData[AdditionalDataFraudScore].(float64)
+	return score
+}
+
+// OFACMatch reports whether the applicant matched a sanctions list
+func (dr *DecisionRequest) OFACMatch() bool {
+	match, _ := dr.AdditionalData[AdditionalDataOFACMatch].(bool)
+	return match
+}
+
+// UsuryRateLimit returns the usury limit of the borrower's state in percent, zero when unknown
+func (dr *DecisionRequest) UsuryRateLimit() float64 {
+	limit, _ := dr.AdditionalData[AdditionalDataUsuryRateLimit].(float64)
+	return limit
+}
+
+// RateFloor returns the rate floor recorded with the request, or the default floor when none was
+func (dr *DecisionRequest) RateFloor() float64 {
+	if floor, ok := dr.AdditionalData[AdditionalDataRateFloor].(float64); ok && floor > 0 {
+		return floor
+	}
+	return PricingRateFloor
+}
+
+// RecordRateFloor records the lending program's rate floor with the request
+func (dr *DecisionRequest) RecordRateFloor(floor float64) {
+	if dr.AdditionalData == nil {
+		dr.AdditionalData = make(map[string]interface{})
+	}
+	dr.AdditionalData[AdditionalDataRateFloor] = floor
+}
+
+// IsSecured reports whether collateral secures the requested loan
+func (dr *DecisionRequest) IsSecured() bool {
+	return dr.Collateral != nil
+}
+
+// CalculateLTV returns the loan-to-value ratio of a secured loan; zero when unsecured or the
+// collateral has no value
+func (dr *DecisionRequest) CalculateLTV() float64 {
+	if dr.Collateral == nil || dr.Collateral.Value <= 0 {
+		return 0
+	}
+	return dr.LoanAmount / dr.Collateral.Value
+}
+
+// MaxLTVRatio returns the highest loan-to-value ratio allowed for a secured request's collateral
+func (dr *DecisionRequest) MaxLTVRatio() float64 {
+	if max, exists := MaxLTVRatios[dr.Collateral.Type]; exists {
+		return max
+	}
+	return MaxLTVRatios[CollateralTypeAsset]
+}
+
+// NeedsValuation reports whether the collateral must be independently valued before funding: it is
+// only valued at the borrower's estimate or its valuation is older than MaxValuationAge
+func (c *CollateralInfo) NeedsValuation(asOf time.Time) bool {
+	return c.ValuationSource == ValuationSourceStated || c.ValuedAt.IsZero() || asOf.Sub(c.ValuedAt) > MaxValuationAge
+}
+
 func (dr *DecisionRequest) IsValidCreditScore() bool {
 	return dr.CreditScore >= 300 && dr.CreditScore <= 850
 }
@@ -308,24 +473,63 @@ func (e *DecisionError) Error() string {
 }
 
 const (
-	ERROR_INVALID_REQUEST   = "DECISION_001"
-	ERROR_INSUFFICIENT_DATA = "DECISION_002"
-	ERROR_RULE_EVALUATION   = "DECISION_003"
-	ERROR_RISK_ASSESSMENT   = "DECISION_004"
-	ERROR_DATABASE_ERROR    = "DECISION_005"
-	ERROR_EXTERNAL_SERVICE  = "DECISION_006"
-	ERROR_BUSINESS_RULE     = "DECISION_007"
+	ERROR_INVALID_REQUEST         = "DECISION_001"
+	ERROR_INSUFFICIENT_DATA       = "DECISION_002"
+	ERROR_RULE_EVALUATION         = "DECISION_003"
+	ERROR_RISK_ASSESSMENT         = "DECISION_004"
+	ERROR_DATABASE_ERROR          = "DECISION_005"
+	ERROR_EXTERNAL_SERVICE        = "DECISION_006"
+	ERROR_BUSINESS_RULE           = "DECISION_007"
+	ERROR_CONSENT_REQUIRED        = "DECISION_008"
+	ERROR_RULE_NOT_FOUND          = "DECISION_009"
+	ERROR_RULE_CONFLICT           = "DECISION_010"
+	ERROR_STRATEGY_NOT_FOUND      = "DECISION_011"
+	ERROR_STRATEGY_CONFLICT       = "DECISION_012"
+	ERROR_SCORECARD_NOT_FOUND     = "DECISION_013"
+	ERROR_SCORECARD_CONFLICT      = "DECISION_014"
+	ERROR_DECISION_NOT_FOUND      = "DECISION_015"
+	ERROR_CREDIT_REPORT_NOT_FOUND = "DECISION_016"
+	ERROR_BUREAU_UNAVAILABLE      = "DECISION_017"
+	ERROR_HARD_PULL_NOT_ALLOWED   = "DECISION_018"
+	ERROR_BATCH_JOB_NOT_FOUND     = "DECISION_019"
+	ERROR_BATCH_CAPACITY          = "DECISION_020"
+	ERROR_POLICY_NOT_FOUND        = "DECISION_021"
+	ERROR_POLICY_CONFLICT         = "DECISION_022"
+	ERROR_POLICY_SELF_APPROVAL    = "DECISION_023"
+	ERROR_DEADLINE_EXCEEDED       = "DECISION_024"
+	ERROR_UNKNOWN_TENANT          = "DECISION_025"
+	ERROR_TENANT_MISMATCH         = "DECISION_026"
+)
+
+type ConsentType string
+
+const (
+	ConsentTypeCreditPull ConsentType = "credit_pull"
+)
+
+// Credit inquiry types
+const (
+	InquiryTypeHard = "HARD"
+	InquiryTypeSoft = "SOFT"
 )
 
+// IsHardInquiry reports whether a request/report type results in a hard inquiry.
+// Anything not explicitly marked as soft is treated as hard.
+func IsHardInquiry(inquiryType string) bool {
+	return !strings.EqualFold(inquiryType, InquiryTypeSoft)
+}
+
 // Credit-related types for external credit services
 type CreditScoreRequest struct {
 	UserID      string `json:"user_id"`
-	SSN         string `json:"ssn,omitempty"`
+	SSNToken    string `json:"ssn_token,omitempty"`
 	FirstName   string `json:"first_name"`
 	LastName    string `json:"last_name"`
 	DateOfBirth string `json:"date_of_birth"`
 	Address     string `json:"address,omitempty"`
 	RequestType string `json:"request_type"`
+	// ApplicationID is the application a hard pull is made for
+	ApplicationID string `json:"application_id,omitempty"`
 }
 
 type CreditScoreResponse struct {
@@ -344,12 +548,20 @@ type CreditScoreResponse struct {
 
 type CreditReportRequest struct {
 	UserID      string `json:"user_id"`
-	SSN         string `json:"ssn,omitempty"`
+	SSNToken    string `json:"ssn_token,omitempty"`
 	FirstName   string `json:"first_name"`
 	LastName    string `json:"last_name"`
 	DateOfBirth string `json:"date_of_birth"`
 	Address     string `json:"address,omitempty"`
 	ReportType  string `json:"report_type"`
+	// PullType is SOFT for pre-qualification or HARD for a submitted application; when unset the
+	// report type decides
+	PullType PullType `json:"pull_type,omitempty"`
+	// ApplicationID is the application a hard pull is made for; the loan service must report it
+	// submitted and consented to
+	ApplicationID string `json:"application_id,omitempty"`
+	// ForceRefresh pulls the bureau even when a cached report is still fresh
+	ForceRefresh bool `json:"force_refresh,omitempty"`
 }
 
 type CreditReport struct {
@@ -362,6 +574,7 @@ type CreditReport struct {
 	Inquiries         []CreditInquiry `json:"inquiries"`
 	PublicRecords     []PublicRecord  `json:"public_records,omitempty"`
 	Collections       []Collection    `json:"collections,omitempty"`
+	FraudAlerts       []FraudAlert    `json:"fraud_alerts,omitempty"`
 	PaymentHistory    PaymentHistory  `json:"payment_history"`
 	CreditUtilization float64         `json:"credit_utilization"`
 	ReportDate        time.Time       `json:"report_date"`
@@ -391,6 +604,13 @@ type CreditInquiry struct {
 	Purpose     string    `json:"purpose,omitempty"`
 }
 
+// Public record types
+const (
+	PublicRecordBankruptcy = "BANKRUPTCY"
+	PublicRecordJudgment   = "JUDGMENT"
+	PublicRecordTaxLien    = "TAX_LIEN"
+)
+
 type PublicRecord struct {
 	RecordID   string    `json:"record_id"`
 	RecordType string    `json:"record_type"`
@@ -400,6 +620,22 @@ type PublicRecord struct {
 	Status     string    `json:"status"`
 }
 
+// Fraud alert types a consumer can place on their credit file
+const (
+	FraudAlertInitial    = "INITIAL"     // one year, on suspicion of identity theft
+	FraudAlertExtended   = "EXTENDED"    // seven years, for a victim of identity theft
+	FraudAlertActiveDuty = "ACTIVE_DUTY" // one year, while a servicemember is deployed
+)
+
+// FraudAlert is a fraud alert on the consumer's credit file. Before extending credit a lender
+// must verify the applicant's identity, by calling the contact phone when one is given.
+type FraudAlert struct {
+	Type         string    `json:"type"`
+	ContactPhone string    `json:"contact_phone,omitempty"`
+	PlacedAt     time.Time `json:"placed_at"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+}
+
 type Collection struct {
 	CollectionID     string    `json:"collection_id"`
 	OriginalCreditor string    `json:"original_creditor"`
@@ -0,0 +1,281 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PolicyStatus is the approval status of an underwriting policy version
+type PolicyStatus string
+
+const (
+	PolicyStatusDraft           PolicyStatus = "DRAFT"
+	PolicyStatusPendingApproval PolicyStatus = "PENDING_APPROVAL"
+	// PolicyStatusApproved versions are in force from their effective date until a later approved
+	// version takes effect
+	PolicyStatusApproved PolicyStatus = "APPROVED"
+	// PolicyStatusRetired versions were approved but withdrawn before they took effect
+	PolicyStatusRetired PolicyStatus = "RETIRED"
+)
+
+// IsValid reports whether s is a known policy status
+func (s PolicyStatus) IsValid() bool {
+	switch s {
+	case PolicyStatusDraft, PolicyStatusPendingApproval, PolicyStatusApproved, PolicyStatusRetired:
+		return true
+	}
+	return false
+}
+
+// PolicyEventType is a change recorded in the policy history
+type PolicyEventType string
+
+const (
+	PolicyEventCreated   PolicyEventType = "CREATED"
+	PolicyEventUpdated   PolicyEventType = "UPDATED"
+	PolicyEventDiscarded PolicyEventType = "DISCARDED"
+	PolicyEventSubmitted PolicyEventType = "SUBMITTED"
+	PolicyEventApproved  PolicyEventType = "APPROVED"
+	PolicyEventRejected  PolicyEventType = "REJECTED"
+	PolicyEventRetired   PolicyEventType = "RETIRED"
+)
+
+// PolicyEvent is an immutable entry in the policy history with the version as it was after the change
+type PolicyEvent struct {
+	ID          int64               `json:"id"`
+	Version     string              `json:"version"`
+	Event       PolicyEventType     `json:"event"`
+	Snapshot    *UnderwritingPolicy `json:"snapshot,omitempty"`
+	Comment     string              `json:"comment,omitempty"`
+	PerformedBy string              `json:"performed_by"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
+
+// PolicyThresholds are the underwriting limits applications are held to. Zero values and empty
+// lists leave a limit unset.
+type PolicyThresholds struct {
+	MinCreditScore      int           `json:"min_credit_score"`
+	MaxDTIRatio         float64       `json:"max_dti_ratio"`
+	MinAnnualIncome     float64       `json:"min_annual_income"`
+	MinLoanAmount       float64       `json:"min_loan_amount"`
+	MaxLoanAmount       float64       `json:"max_loan_amount"`
+	AllowedLoanTerms    []int         `json:"allowed_loan_terms,omitempty"`
+	AllowedLoanPurposes []LoanPurpose `json:"allowed_loan_purposes,omitempty"`
+	// AutoApproval bounds approvals made without review; approvals outside them are made
+	// conditional on manual review
+	AutoApproval AutoApprovalThresholds `json:"auto_approval"`
+}
+
+// AutoApprovalThresholds bound the approvals made without manual review
+type AutoApprovalThresholds struct {
+	MinCreditScore int     `json:"min_credit_score"`
+	MaxDTIRatio    float64 `json:"max_dti_ratio"`
+	MaxLoanAmount  float64 `json:"max_loan_amount"`
+}
+
+// Validate checks the thresholds are within range and consistent
+func (t *PolicyThresholds) Validate() error {
+	if t.MinCreditScore != 0 && (t.MinCreditScore < 300 || t.MinCreditScore > 850) {
+		return fmt.Errorf("min_credit_score must be between 300 and 850")
+	}
+	if t.MaxDTIRatio < 0 || t.MaxDTIRatio > 1 {
+		return fmt.Errorf("max_dti_ratio must be between 0 and 1")
+	}
+	if t.MinAnnualIncome < 0 || t.MinLoanAmount < 0 || t.MaxLoanAmount < 0 {
+		return fmt.Errorf("income and loan amount limits must not be negative")
+	}
+	if t.MaxLoanAmount > 0 && t.MinLoanAmount > t.MaxLoanAmount {
+		return fmt.Errorf("min_loan_amount must not exceed max_loan_amount")
+	}
+	for _, term := range t.AllowedLoanTerms {
+		if term < 12 || term > 84 {
+			return fmt.Errorf("allowed loan term %d must be between 12 and 84 months", term)
+		}
+	}
+	for _, purpose := range t.AllowedLoanPurposes {
+		if purpose == "" {
+			return fmt.Errorf("allowed loan purposes must not be empty")
+		}
+	}
+
+	auto := t.AutoApproval
+	if auto.MinCreditScore != 0 && (auto.MinCreditScore < 300 || auto.MinCreditScore > 850) {
+		return fmt.Errorf("auto_approval.min_credit_score must be between 300 and 850")
+	}
+	if auto.MaxDTIRatio < 0 || auto.MaxDTIRatio > 1 {
+		return fmt.Errorf("auto_approval.max_dti_ratio must be between 0 and 1")
+	}
+	if auto.MaxLoanAmount < 0 {
+		return fmt.Errorf("auto_approval.max_loan_amount must not be negative")
+	}
+	return nil
+}
+
+// UnderwritingPolicy is one version of the underwriting thresholds. Versions are written as
+// drafts by a maker and submitted for approval; a checker other than the maker approves them,
+// which freezes them and puts them in force from their effective date, or rejects them back to
+// draft. Every change is kept in the policy history.
+type UnderwritingPolicy struct {
+	TenantID      string           `json:"tenant_id"` // lending program the policy holds decisions of
+	Version       string           `json:"version"`
+	Name          string           `json:"name"`
+	Description   string           `json:"description,omitempty"`
+	Status        PolicyStatus     `json:"status"`
+	Thresholds    PolicyThresholds `json:"thresholds"`
+	EffectiveFrom *time.Time       `json:"effective_from,omitempty"` // set to the approval time when empty or past
+	CreatedBy     string           `json:"created_by"`
+	SubmittedBy   string           `json:"submitted_by,omitempty"`
+	SubmittedAt   *time.Time       `json:"submitted_at,omitempty"`
+	ReviewedBy    string           `json:"reviewed_by,omitempty"` // checker who approved or last rejected the version
+	ReviewedAt    *time.Time       `json:"reviewed_at,omitempty"`
+	ReviewComment string           `json:"review_comment,omitempty"`
+	RetiredAt     *time.Time       `json:"retired_at,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+}
+
+// IsMaker reports whether actor wrote or submitted the version, and so may not approve it
+func (p *UnderwritingPolicy) IsMaker(actor string) bool {
+	return actor == p.CreatedBy || actor == p.SubmittedBy
+}
+
+// PolicyInForce returns a lending program's approved version in force at t: the one that took
+// effect last, with the highest version breaking ties. It returns nil when none is in force.
+func PolicyInForce(policies []UnderwritingPolicy, tenant string, t time.Time) *UnderwritingPolicy {
+	var inForce *UnderwritingPolicy
+	for i := range policies {
+		policy := &policies[i]
+		if TenantOrDefault(policy.TenantID) != tenant || policy.Status != PolicyStatusApproved || policy.EffectiveFrom == nil || t.Before(*policy.EffectiveFrom) {
+			continue
+		}
+		if inForce == nil || policy.EffectiveFrom.After(*inForce.EffectiveFrom) ||
+			(policy.EffectiveFrom.Equal(*inForce.EffectiveFrom) && CompareVersions(policy.Version, inForce.Version) > 0) {
+			inForce = policy
+		}
+	}
+	return inForce
+}
+
+// Knockouts returns a knockout hit for every limit of the policy the request falls outside
+func (p *UnderwritingPolicy) Knockouts(request *DecisionRequest, dtiRatio float64) []RuleHit {
+	t := p.Thresholds
+	var hits []RuleHit
+	knockout := func(id, name string, category RuleCategory, code AdverseActionCode, reason string) {
+		hits = append(hits, RuleHit{
+			RuleID:        id,
+			Version:       p.Version,
+			Name:          name,
+			Category:      category,
+			Action:        ActionDecision,
+			Decision:      DecisionDeny,
+			Knockout:      true,
+			Reason:        reason,
+			AdverseAction: code,
+		})
+	}
+
+	if t.MinCreditScore > 0 && request.CreditScore < t.MinCreditScore {
+		knockout("policy_min_credit_score", "Policy minimum credit score", RuleCategoryCredit, AdverseActionCreditScore,
+			fmt.Sprintf("Credit score of %d is below the policy minimum of %d", request.CreditScore, t.MinCreditScore))
+	}
+	if t.MaxDTIRatio > 0 && dtiRatio > t.MaxDTIRatio {
+		knockout("policy_max_dti_ratio", "Policy maximum debt-to-income ratio", RuleCategoryDebt, AdverseActionExcessiveObligations,
+			fmt.Sprintf("Debt-to-income ratio of %.2f exceeds the policy maximum of %.2f", dtiRatio, t.MaxDTIRatio))
+	}
+	if t.MinAnnualIncome > 0 && request.AnnualIncome < t.MinAnnualIncome {
+		knockout("policy_min_annual_income", "Policy minimum annual income", RuleCategoryIncome, AdverseActionInsufficientIncome,
+			fmt.Sprintf("Annual income of $%.0f is below the policy minimum of $%.0f", request.AnnualIncome, t.MinAnnualIncome))
+	}
+	if (t.MinLoanAmount > 0 && request.LoanAmount < t.MinLoanAmount) || (t.MaxLoanAmount > 0 && request.LoanAmount > t.MaxLoanAmount) {
+		knockout("policy_loan_amount", "Policy loan amount limits", RuleCategoryGeneral, AdverseActionTermsRequested,
+			fmt.Sprintf("Loan amount of $%.0f is outside the policy limits of $%.0f to $%.0f", request.LoanAmount, t.MinLoanAmount, t.MaxLoanAmount))
+	}
+	term := request.RequestedTerm
+	if term <= 0 {
+		term = request.LoanTermMonths
+	}
+	if len(t.AllowedLoanTerms) > 0 && !containsInt(t.AllowedLoanTerms, term) {
+		knockout("policy_loan_term", "Policy allowed loan terms", RuleCategoryGeneral, AdverseActionTermsRequested,
+			fmt.Sprintf("A term of %d months is not offered under the policy", term))
+	}
+	if len(t.AllowedLoanPurposes) > 0 && !containsPurpose(t.AllowedLoanPurposes, request.LoanPurpose) {
+		knockout("policy_loan_purpose", "Policy allowed loan purposes", RuleCategoryGeneral, AdverseActionTermsRequested,
+			fmt.Sprintf("Loans for %s are not offered under the policy", request.LoanPurpose))
+	}
+	return hits
+}
+
+// ReviewReasons returns why an approval of the request falls outside the policy's auto-approval
+// thresholds; none means it may be approved without review
+func (p *UnderwritingPolicy) ReviewReasons(request *DecisionRequest, dtiRatio, amount float64) []string {
+	auto := p.Thresholds.AutoApproval
+	var reasons []string
+	if auto.MinCreditScore > 0 && request.CreditScore < auto.MinCreditScore {
+		reasons = append(reasons, fmt.Sprintf("credit score below the auto-approval minimum of %d", auto.MinCreditScore))
+	}
+	if auto.MaxDTIRatio > 0 && dtiRatio > auto.MaxDTIRatio {
+		reasons = append(reasons, fmt.Sprintf("debt-to-income ratio above the auto-approval maximum of %.2f", auto.MaxDTIRatio))
+	}
+	if auto.MaxLoanAmount > 0 && amount > auto.MaxLoanAmount {
+		reasons = append(reasons, fmt.Sprintf("loan amount above the auto-approval maximum of $%.0f", auto.MaxLoanAmount))
+	}
+	return reasons
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPurpose(purposes []LoanPurpose, purpose LoanPurpose) bool {
+	for _, p := range purposes {
+		if strings.EqualFold(string(p), string(purpose)) {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyRequest defines the contents of a policy version. The effective date may be left empty to
+// take effect when approved.
+type PolicyRequest struct {
+	Version       string           `json:"version"` // defaults to 1.0.0 for the first version and the next minor version otherwise
+	Name          string           `json:"name" binding:"required"`
+	Description   string           `json:"description"`
+	Thresholds    PolicyThresholds `json:"thresholds"`
+	EffectiveFrom *time.Time       `json:"effective_from"`
+}
+
+// Apply copies the requested definition onto a policy version
+func (req *PolicyRequest) Apply(policy *UnderwritingPolicy) {
+	policy.Name = req.Name
+	policy.Description = req.Description
+	policy.Thresholds = req.Thresholds
+	policy.EffectiveFrom = req.EffectiveFrom
+}
+
+// PolicyReviewRequest carries a checker's comment on a version they approve or reject
+type PolicyReviewRequest struct {
+	Comment string `json:"comment"`
+}
+
+// PolicyRepository persists underwriting policy versions and their history. Every change is
+// recorded in the history in the same write.
+type PolicyRepository interface {
+	// ListPolicies returns the versions with a status, or every version when status is empty,
+	// oldest first. An empty tenant lists the versions of every lending program.
+	ListPolicies(ctx context.Context, tenant string, status PolicyStatus) ([]UnderwritingPolicy, error)
+	GetPolicy(ctx context.Context, tenant, version string) (*UnderwritingPolicy, error)
+	CreatePolicy(ctx context.Context, policy *UnderwritingPolicy) error
+	// UpdatePolicy saves a version's definition, status and review, recording event
+	UpdatePolicy(ctx context.Context, policy *UnderwritingPolicy, expected PolicyStatus, event PolicyEventType, performedBy, comment string) error
+	// DeleteDraft discards a draft version
+	DeleteDraft(ctx context.Context, tenant, version, performedBy string) error
+	GetPolicyEvents(ctx context.Context, tenant, version string) ([]PolicyEvent, error)
+}
@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+// PricingModelVersion identifies the risk-based pricing model decisions are priced with; it
+// changes whenever margins, fees or waivers change
+const PricingModelVersion = "risk-pricing-1"
+
+// Rate bounds applied after margin adjustments under the default pricing matrix, in percent
+const (
+	PricingRateFloor   = 5.0
+	PricingRateCeiling = 25.0
+)
+
+// DefaultBaseRates are the base rates by loan purpose under the default pricing matrix, in percent
+var DefaultBaseRates = map[LoanPurpose]float64{
+	PurposePersonal:          8.5,
+	PurposeDebtConsolidation: 7.5,
+	PurposeHomeImprovement:   7.0,
+	PurposeBusiness:          9.0,
+	PurposeEducation:         6.5,
+	PurposeMedical:           8.0,
+	PurposeVacation:          10.0,
+	PurposeOther:             9.5,
+}
+
+// DefaultPurposeBaseRate is the base rate for purposes without one, in percent
+const DefaultPurposeBaseRate = 9.0
+
+// PricingFactor is an input a margin adjustment is made for
+type PricingFactor string
+
+const (
+	PricingFactorRiskScore   PricingFactor = "RISK_SCORE"
+	PricingFactorCreditScore PricingFactor = "CREDIT_SCORE"
+	PricingFactorDTI         PricingFactor = "DTI_RATIO"
+	PricingFactorEmployment  PricingFactor = "EMPLOYMENT_TYPE"
+	PricingFactorCollateral  PricingFactor = "COLLATERAL_LTV"
+)
+
+// Fees a decision's pricing may waive
+const (
+	FeeOrigination = "ORIGINATION"
+)
+
+// OriginationFeeRates are the origination fees charged by risk category, in percent of the loan
+// amount, before waivers
+var OriginationFeeRates = map[RiskCategory]float64{
+	RiskLow:      1.0,
+	RiskMedium:   2.0,
+	RiskHigh:     3.0,
+	RiskCritical: 4.0,
+}
+
+// PricingMatrix is a lending program's base rates by loan purpose, rate bounds and origination
+// fees by risk category, all in percent. Risk-based margins and fee waivers are the same for every
+// program.
+type PricingMatrix struct {
+	BaseRates           map[LoanPurpose]float64  `json:"base_rates"`
+	DefaultBaseRate     float64                  `json:"default_base_rate"` // purposes without a base rate
+	RateFloor           float64                  `json:"rate_floor"`
+	RateCeiling         float64                  `json:"rate_ceiling"`
+	OriginationFeeRates map[RiskCategory]float64 `json:"origination_fee_rates"`
+}
+
+// DefaultPricingMatrix returns the pricing matrix of programs that configure none
+func DefaultPricingMatrix() PricingMatrix {
+	return PricingMatrix{}.WithDefaults()
+}
+
+// WithDefaults fills unset rates from the default pricing matrix. The maps are copied, so
+// programs never share them.
+func (m PricingMatrix) WithDefaults() PricingMatrix {
+	baseRates := make(map[LoanPurpose]float64, len(DefaultBaseRates))
+	for purpose, rate := range DefaultBaseRates {
+		baseRates[purpose] = rate
+	}
+	for purpose, rate := range m.BaseRates {
+		baseRates[purpose] = rate
+	}
+	m.BaseRates = baseRates
+
+	feeRates := make(map[RiskCategory]float64, len(OriginationFeeRates))
+	for category, rate := range OriginationFeeRates {
+		feeRates[category] = rate
+	}
+	for category, rate := range m.OriginationFeeRates {
+		feeRates[category] = rate
+	}
+	m.OriginationFeeRates = feeRates
+
+	if m.DefaultBaseRate <= 0 {
+		m.DefaultBaseRate = DefaultPurposeBaseRate
+	}
+	if m.RateFloor <= 0 {
+		m.RateFloor = PricingRateFloor
+	}
+	if m.RateCeiling <= 0 {
+		m.RateCeiling = PricingRateCeiling
+	}
+	return m
+}
+
+// Validate checks the rates are in range and the floor is below the ceiling
+func (m PricingMatrix) Validate() error {
+	for purpose, rate := range m.BaseRates {
+		if rate <= 0 || rate > 100 {
+			return fmt.Errorf("base rate for %s must be between 0 and 100", purpose)
+		}
+	}
+	for category, rate := range m.OriginationFeeRates {
+		if rate < 0 || rate > 100 {
+			return fmt.Errorf("origination fee rate for %s must be between 0 and 100", category)
+		}
+	}
+	if m.RateFloor < 0 || m.RateCeiling < 0 || m.RateCeiling > 100 {
+		return fmt.Errorf("rate floor and ceiling must be between 0 and 100")
+	}
+	if m.RateFloor > 0 && m.RateCeiling > 0 && m.RateFloor >= m.RateCeiling {
+		return fmt.Errorf("rate floor must be below the rate ceiling")
+	}
+	return nil
+}
+
+// BaseRate returns the base rate for a loan purpose
+func (m PricingMatrix) BaseRate(purpose LoanPurpose) float64 {
+	if rate, ok := m.BaseRates[purpose]; ok {
+		return rate
+	}
+	return m.DefaultBaseRate
+}
+
+// MarginAdjustment is one risk-based adjustment to the base rate, in percentage points
+type MarginAdjustment struct {
+	Factor      PricingFactor `json:"factor"`
+	Adjustment  float64       `json:"adjustment"`
+	Description string        `json:"description"`
+}
+
+// FeeWaiver waives all or part of a fee; WaivedRate is in percent of the loan amount
+type FeeWaiver struct {
+	Fee        string  `json:"fee"`
+	Code       string  `json:"code"`
+	WaivedRate float64 `json:"waived_rate"`
+	Reason     string  `json:"reason"`
+}
+
+// DecisionPricing is the risk-based price of a decision, computed from the same request and risk
+// assessment the decision was made on. It is the single pricing source for the loan service's
+// offers and the underwriting worker: Rate is BaseRate plus the margin adjustments, held within
+// the floor and ceiling, and the origination fee charged is OriginationFeeRate less the waivers.
+type DecisionPricing struct {
+	ModelVersion          string             `json:"model_version"`
+	BaseRate              float64            `json:"base_rate"`
+	MarginAdjustments     []MarginAdjustment `json:"margin_adjustments"`
+	Margin                float64            `json:"margin"` // sum of the margin adjustments
+	RateFloor             float64            `json:"rate_floor"`
+	RateCeiling           float64            `json:"rate_ceiling"`
+	Rate                  float64            `json:"rate"`
+	OriginationFeeRate    float64            `json:"origination_fee_rate"` // before waivers
+	FeeWaivers            []FeeWaiver        `json:"fee_waivers,omitempty"`
+	NetOriginationFeeRate float64            `json:"net_origination_fee_rate"` // charged, after waivers
+	LoanAmount            float64            `json:"loan_amount"`
+	TermMonths            int                `json:"term_months"`
+	MonthlyPayment        float64            `json:"monthly_payment"`
+	APR                   float64            `json:"apr"`
+}
+
+// AmortizedPayment returns the level monthly payment that repays principal over termMonths at an
+// annual rate in percent
+func AmortizedPayment(principal, annualRate float64, termMonths int) float64 {
+	if termMonths <= 0 {
+		return 0
+	}
+	monthlyRate := annualRate / 100 / 12
+	if monthlyRate == 0 {
+		return principal / float64(termMonths)
+	}
+	factor := math.Pow(1+monthlyRate, float64(termMonths))
+	return principal * monthlyRate * factor / (factor - 1)
+}
+
+// AnnualPercentageRate returns the annual rate, in percent, at which the payments repay the
+// amount actually received: the loan amount less the origination fee. It matches the loan
+// service's APR so offers priced from a decision show the same figures.
+func AnnualPercentageRate(principal, feeRate, monthlyPayment float64, termMonths int) float64 {
+	financed := principal * (1 - feeRate/100)
+	low, high := 0.0, 100.0
+	// Bisection on a fixed number of steps keeps the result identical across runs
+	for i := 0; i < 100; i++ {
+		mid := (low + high) / 2
+		if AmortizedPayment(financed, mid, termMonths) < monthlyPayment {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2
+}
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ReplayInputSource is where a replay's decision request came from
+type ReplayInputSource string
+
+const (
+	// ReplayInputsDecision is the request stored with the decision itself
+	ReplayInputsDecision ReplayInputSource = "DECISION"
+	// ReplayInputsApplication is the latest request stored for the application, used for decisions
+	// saved before their own request was kept; it may postdate the decision
+	ReplayInputsApplication ReplayInputSource = "APPLICATION"
+)
+
+// DecisionInputs is the request a stored decision was made on
+type DecisionInputs struct {
+	Request DecisionRequest
+	Source  ReplayInputSource
+}
+
+// DecisionReplay re-executes a stored decision with its stored inputs and the rule versions and
+// scorecard in force when it was made, for regulator and dispute investigations
+type DecisionReplay struct {
+	DecisionID    int64                `json:"decision_id"`
+	ApplicationID string               `json:"application_id"`
+	DecidedAt     time.Time            `json:"decided_at"`
+	ReplayedAt    time.Time            `json:"replayed_at"`
+	InputSource   ReplayInputSource    `json:"input_source"`
+	Request       *DecisionRequest     `json:"request"`
+	RuleVersions  []string             `json:"rule_versions"`          // id@version of every rule replayed
+	ScorecardID   string               `json:"scorecard_id,omitempty"` // scorecard replayed, when one was applied
+	Original      *DecisionResponse    `json:"original"`
+	Replayed      *DecisionResponse    `json:"replayed"`
+	Diverged      bool                 `json:"diverged"`
+	Divergences   []DecisionDivergence `json:"divergences"`
+	Notes         []string             `json:"notes,omitempty"` // caveats on how faithful the replay is
+}
+
+// DecisionDivergence is one field on which a replayed decision differs from the original
+type DecisionDivergence struct {
+	Field    string      `json:"field"`
+	Original interface{} `json:"original"`
+	Replayed interface{} `json:"replayed"`
+}
+
+// replayTolerance absorbs rounding in stored scores and amounts
+const replayTolerance = 0.005
+
+// CompareDecisions lists the fields on which a replayed decision differs from the stored original.
+// Only fields kept with stored decisions are compared.
+func CompareDecisions(original, replayed *DecisionResponse) []DecisionDivergence {
+	divergences := []DecisionDivergence{}
+	add := func(field string, before, after interface{}) {
+		divergences = append(divergences, DecisionDivergence{Field: field, Original: before, Replayed: after})
+	}
+
+	if original.Decision != replayed.Decision {
+		add("decision", original.Decision, replayed.Decision)
+	}
+	if math.Abs(original.MaxAmount-replayed.MaxAmount) > replayTolerance {
+		add("max_amount", original.MaxAmount, replayed.MaxAmount)
+	}
+	if math.Abs(original.InterestRate-replayed.InterestRate) > replayTolerance {
+		add("interest_rate", original.InterestRate, replayed.InterestRate)
+	}
+	if original.Reason != replayed.Reason {
+		add("reason", original.Reason, replayed.Reason)
+	}
+	if !sameStrings(original.AppliedRules, replayed.AppliedRules) {
+		add("applied_rules", original.AppliedRules, replayed.AppliedRules)
+	}
+	if len(original.RuleVersions) > 0 && !sameStrings(original.RuleVersions, replayed.RuleVersions) {
+		add("rule_versions", original.RuleVersions, replayed.RuleVersions)
+	}
+	if before, after := adverseActionCodes(original), adverseActionCodes(replayed); !sameStrings(before, after) {
+		add("adverse_action_reasons", before, after)
+	}
+	if len(original.Stages) > 0 {
+		for _, stage := range []RuleStage{RuleStageKnockout, RuleStageScoring} {
+			before, after := stageStatus(original, stage), stageStatus(replayed, stage)
+			if before != after {
+				add("stages."+strings.ToLower(string(stage)), before, after)
+			}
+		}
+	}
+
+	if original.RiskAssessment != nil && replayed.RiskAssessment != nil {
+		before, after := original.RiskAssessment, replayed.RiskAssessment
+		if math.Abs(before.OverallScore-after.OverallScore) > replayTolerance {
+			add("risk_assessment.overall_score", before.OverallScore, after.OverallScore)
+		}
+		if math.Abs(before.DTIRatio-after.DTIRatio) > replayTolerance {
+			add("risk_assessment.dti_ratio", before.DTIRatio, after.DTIRatio)
+		}
+		if scorecardSummary(before.Scorecard) != scorecardSummary(after.Scorecard) {
+			add("risk_assessment.scorecard", scorecardSummary(before.Scorecard), scorecardSummary(after.Scorecard))
+		}
+	}
+
+	if original.Pricing != nil && replayed.Pricing != nil {
+		before, after := original.Pricing, replayed.Pricing
+		if math.Abs(before.NetOriginationFeeRate-after.NetOriginationFeeRate) > replayTolerance {
+			add("pricing.net_origination_fee_rate", before.NetOriginationFeeRate, after.NetOriginationFeeRate)
+		}
+		if math.Abs(before.APR-after.APR) > replayTolerance {
+			add("pricing.apr", before.APR, after.APR)
+		}
+	}
+	return divergences
+}
+
+// InForceAt reports whether a rule version was in force at t: published by then, not yet retired
+// or superseded, and within its effective dates
+func (r *DecisionRule) InForceAt(t time.Time) bool {
+	if r.PublishedAt == nil || t.Before(*r.PublishedAt) {
+		return false
+	}
+	if r.RetiredAt != nil && !t.Before(*r.RetiredAt) {
+		return false
+	}
+	if r.EffectiveFrom != nil && t.Before(*r.EffectiveFrom) {
+		return false
+	}
+	return r.ExpiresAt == nil || t.Before(*r.ExpiresAt)
+}
+
+func adverseActionCodes(decision *DecisionResponse) []string {
+	codes := make([]string, 0, len(decision.AdverseActionReasons))
+	for _, reason := range decision.AdverseActionReasons {
+		codes = append(codes, string(reason.Code))
+	}
+	return codes
+}
+
+// stageStatus returns the status a decision recorded for a stage, or "" when it has none
+func stageStatus(decision *DecisionResponse, stage RuleStage) StageStatus {
+	if result := decision.Stage(stage); result != nil {
+		return result.Status
+	}
+	return ""
+}
+
+// scorecardSummary identifies a scorecard result by scorecard, score and band
+func scorecardSummary(result *ScorecardResult) string {
+	if result == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d:%s", result.ScorecardID, result.Score, result.Band)
+}
+
+func sameStrings(a, b []string) bool {
+	return strings.Join(a, "\x00") == strings.Join(b, "\x00")
+}
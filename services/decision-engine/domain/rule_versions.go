@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// RuleStatus is the lifecycle status of a rule version
+type RuleStatus string
+
+const (
+	RuleStatusDraft     RuleStatus = "DRAFT"
+	RuleStatusPublished RuleStatus = "PUBLISHED"
+	RuleStatusRetired   RuleStatus = "RETIRED"
+)
+
+// IsValid reports whether s is a known rule status
+func (s RuleStatus) IsValid() bool {
+	switch s {
+	case RuleStatusDraft, RuleStatusPublished, RuleStatusRetired:
+		return true
+	}
+	return false
+}
+
+// RuleEventType is a change recorded in a rule's history
+type RuleEventType string
+
+const (
+	RuleEventCreated    RuleEventType = "CREATED"
+	RuleEventUpdated    RuleEventType = "UPDATED"
+	RuleEventDiscarded  RuleEventType = "DISCARDED"
+	RuleEventPublished  RuleEventType = "PUBLISHED"
+	RuleEventSuperseded RuleEventType = "SUPERSEDED"
+	RuleEventRetired    RuleEventType = "RETIRED"
+)
+
+// RuleEvent is an immutable entry in a rule's history with the version as it was after the change
+type RuleEvent struct {
+	ID          int64         `json:"id"`
+	RuleID      string        `json:"rule_id"`
+	Version     string        `json:"version"`
+	Event       RuleEventType `json:"event"`
+	Snapshot    *DecisionRule `json:"snapshot,omitempty"`
+	PerformedBy string        `json:"performed_by"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// InitialRuleVersion is the version of a rule's first draft
+const InitialRuleVersion = "1.0.0"
+
+var semVerPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)$`)
+
+// ParseVersion parses a MAJOR.MINOR.PATCH semantic version
+func ParseVersion(version string) ([3]int, error) {
+	var parts [3]int
+	match := semVerPattern.FindStringSubmatch(version)
+	if match == nil {
+		return parts, fmt.Errorf("version %q is not a MAJOR.MINOR.PATCH semantic version", version)
+	}
+	for i := range parts {
+		parts[i], _ = strconv.Atoi(match[i+1])
+	}
+	return parts, nil
+}
+
+// CompareVersions orders two semantic versions, returning -1, 0 or 1; unparsable versions sort
+// first
+func CompareVersions(a, b string) int {
+	pa, errA := ParseVersion(a)
+	pb, errB := ParseVersion(b)
+	switch {
+	case errA != nil && errB != nil:
+		return 0
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+
+	for i := range pa {
+		if pa[i] != pb[i] {
+			if pa[i] < pb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// NextMinorVersion returns the version after version with the minor component bumped
+func NextMinorVersion(version string) string {
+	parts, err := ParseVersion(version)
+	if err != nil {
+		return InitialRuleVersion
+	}
+	return fmt.Sprintf("%d.%d.0", parts[0], parts[1]+1)
+}
+
+// VersionedID identifies a rule version in decisions, e.g. max_dti_ratio@1.2.0
+func (r *DecisionRule) VersionedID() string {
+	return r.ID + "@" + r.Version
+}
+
+// InForce reports whether a published version applies at t
+func (r *DecisionRule) InForce(t time.Time) bool {
+	if r.Status != RuleStatusPublished {
+		return false
+	}
+	if r.EffectiveFrom != nil && t.Before(*r.EffectiveFrom) {
+		return false
+	}
+	return r.ExpiresAt == nil || t.Before(*r.ExpiresAt)
+}
+
+// RuleVersionRequest defines the contents of a rule version. Effective dates may be left empty to
+// take effect when published and never expire.
+type RuleVersionRequest struct {
+	ID            string                 `json:"id"`      // new rules only
+	Version       string                 `json:"version"` // defaults to 1.0.0 for new rules and the next minor version otherwise
+	Name          string                 `json:"name" binding:"required"`
+	Description   string                 `json:"description"`
+	Category      RuleCategory           `json:"category" binding:"required"`
+	Priority      int                    `json:"priority"`
+	Expression    string                 `json:"expression"`
+	Conditions    []RuleCondition        `json:"conditions"`
+	Action        RuleAction             `json:"action"`
+	Knockout      bool                   `json:"knockout"`
+	EffectiveFrom *time.Time             `json:"effective_from"`
+	ExpiresAt     *time.Time             `json:"expires_at"`
+	Metadata      map[string]interface{} `json:"metadata"`
+}
+
+// Apply copies the requested definition onto a rule version
+func (req *RuleVersionRequest) Apply(rule *DecisionRule) {
+	rule.Name = req.Name
+	rule.Description = req.Description
+	rule.Category = req.Category
+	rule.Priority = req.Priority
+	rule.Expression = req.Expression
+	rule.Conditions = req.Conditions
+	rule.Action = req.Action
+	rule.Knockout = req.Knockout
+	rule.EffectiveFrom = req.EffectiveFrom
+	rule.ExpiresAt = req.ExpiresAt
+	rule.Metadata = req.Metadata
+}
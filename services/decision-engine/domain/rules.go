@@ -0,0 +1,235 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Adjustment keys understood by ADJUSTMENT rules
+const (
+	AdjustmentMaxAmount       = "max_amount"         // caps the approvable amount
+	AdjustmentMaxLoanToIncome = "max_loan_to_income" // caps the approvable amount at a multiple of annual income
+)
+
+var ruleIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// RuleFactNames are the variables rule expressions and conditions may reference
+var RuleFactNames = []string{
+	"application_id", "loan_amount", "annual_income", "monthly_income", "monthly_debt",
+	"credit_score", "employment_type", "requested_term", "loan_term_months", "loan_purpose",
+	"loan_to_income", "is_secured", "collateral_type", "collateral_value",
+	"overall_score", "dti_ratio", "ltv_ratio", "credit_utilization",
+	"credit_risk", "income_risk", "debt_risk", "employment_risk", "collateral_risk",
+	"on_time_payments", "late_payments", "defaults", "bankruptcies", "credit_age_months",
+	"payment_score", "risk_factor_count", "has_scorecard", "scorecard_score", "scorecard_band",
+	"fraud_score", "ofac_match", "usury_rate_limit", "rate_floor",
+}
+
+// RuleFacts flattens a decision request and its risk assessment into the variables rule expressions
+// are evaluated against. Numbers are float64 so expressions compare them uniformly.
+func RuleFacts(request *DecisionRequest, assessment *RiskAssessment) map[string]interface{} {
+	facts := map[string]interface{}{
+		"application_id":   request.ApplicationID,
+		"loan_amount":      request.LoanAmount,
+		"annual_income":    request.AnnualIncome,
+		"monthly_income":   request.MonthlyIncome,
+		"monthly_debt":     request.MonthlyDebt,
+		"credit_score":     float64(request.CreditScore),
+		"employment_type":  string(request.EmploymentType),
+		"requested_term":   float64(request.RequestedTerm),
+		"loan_term_months": float64(request.LoanTermMonths),
+		"loan_purpose":     string(request.LoanPurpose),
+		"loan_to_income":   request.GetLoanToIncomeRatio(),
+		"is_secured":       request.IsSecured(),
+		"collateral_type":  "",
+		"collateral_value": 0.0,
+
+		"overall_score":      assessment.OverallScore,
+		"dti_ratio":          assessment.DTIRatio,
+		"ltv_ratio":          assessment.LTVRatio,
+		"credit_utilization": assessment.CreditUtilization,
+		"credit_risk":        assessment.CategoryScores.CreditRisk,
+		"income_risk":        assessment.CategoryScores.IncomeRisk,
+		"debt_risk":          assessment.CategoryScores.DebtRisk,
+		"employment_risk":    assessment.CategoryScores.EmploymentRisk,
+		"collateral_risk":    assessment.CategoryScores.CollateralRisk,
+		"on_time_payments":   float64(assessment.PaymentHistory.OnTimePayments),
+		"late_payments":      float64(assessment.PaymentHistory.LatePayments),
+		"defaults":           float64(assessment.PaymentHistory.Defaults),
+		"bankruptcies":       float64(assessment.PaymentHistory.Bankruptcies),
+		"credit_age_months":  float64(assessment.PaymentHistory.CreditAge),
+		"payment_score":      assessment.PaymentHistory.PaymentScore,
+		"risk_factor_count":  float64(len(assessment.RiskFactors)),
+		"has_scorecard":      assessment.Scorecard != nil,
+		"scorecard_score":    0.0,
+		"scorecard_band":     "",
+
+		"fraud_score":      request.FraudScore(),
+		"income_variance":  request.IncomeVariance(),
+		"ofac_match":       request.OFACMatch(),
+		"usury_rate_limit": request.UsuryRateLimit(),
+		"rate_floor":       request.RateFloor(),
+	}
+
+	if request.Collateral != nil {
+		facts["collateral_type"] = request.Collateral.Type
+		facts["collateral_value"] = request.Collateral.Value
+	}
+	if assessment.Scorecard != nil {
+		facts["scorecard_score"] = float64(assessment.Scorecard.Score)
+		facts["scorecard_band"] = assessment.Scorecard.Band
+	}
+	return facts
+}
+
+// IsRuleFact reports whether name is a variable rules may reference
+func IsRuleFact(name string) bool {
+	for _, fact := range RuleFactNames {
+		if fact == name {
+			return true
+		}
+	}
+	return false
+}
+
+// decisionSeverity orders decisions from least to most restrictive
+var decisionSeverity = map[DecisionType]int{
+	DecisionApprove:      0,
+	DecisionConditional:  1,
+	DecisionManualReview: 2,
+	DecisionDeny:         3,
+}
+
+// MoreRestrictive reports whether decision d is more restrictive than other
+func (d DecisionType) MoreRestrictive(other DecisionType) bool {
+	return decisionSeverity[d] > decisionSeverity[other]
+}
+
+// IsValid reports whether d is a decision rules may produce
+func (d DecisionType) IsValid() bool {
+	_, ok := decisionSeverity[d]
+	return ok
+}
+
+// RuleExpression returns the expression a rule is evaluated with: its own expression, or its
+// conditions joined with &&
+func (r *DecisionRule) RuleExpression() (string, error) {
+	if strings.TrimSpace(r.Expression) != "" {
+		return r.Expression, nil
+	}
+	if len(r.Conditions) == 0 {
+		return "", fmt.Errorf("rule %s has neither an expression nor conditions", r.ID)
+	}
+
+	clauses := make([]string, 0, len(r.Conditions))
+	for _, condition := range r.Conditions {
+		clause, err := condition.expression()
+		if err != nil {
+			return "", fmt.Errorf("rule %s: %w", r.ID, err)
+		}
+		clauses = append(clauses, clause)
+	}
+	return strings.Join(clauses, " && "), nil
+}
+
+// Validate checks the parts of a rule that do not depend on the expression language
+func (r *DecisionRule) Validate() error {
+	if !ruleIDPattern.MatchString(r.ID) || strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("rule id (lowercase letters, digits, _ and -) and name are required")
+	}
+	if _, err := ParseVersion(r.Version); err != nil {
+		return fmt.Errorf("rule %s: %w", r.ID, err)
+	}
+	if r.EffectiveFrom != nil && r.ExpiresAt != nil && !r.ExpiresAt.After(*r.EffectiveFrom) {
+		return fmt.Errorf("rule %s: expires_at must be after effective_from", r.ID)
+	}
+
+	switch r.Action.Type {
+	case ActionDecision:
+		if !r.Action.Decision.IsValid() {
+			return fmt.Errorf("rule %s: decision action requires one of APPROVE, CONDITIONAL, MANUAL_REVIEW or DENY", r.ID)
+		}
+	case ActionAdjustment, ActionRequirement, ActionFlag:
+		if r.Knockout {
+			return fmt.Errorf("rule %s: only decision rules can be knockout rules", r.ID)
+		}
+	default:
+		return fmt.Errorf("rule %s: unknown action type %q", r.ID, r.Action.Type)
+	}
+
+	if r.Action.AdverseActionCode != "" && !r.Action.AdverseActionCode.IsValid() {
+		return fmt.Errorf("rule %s: unknown adverse action code %q", r.ID, r.Action.AdverseActionCode)
+	}
+
+	if r.Action.Type != ActionAdjustment && strings.TrimSpace(r.Action.Reason) == "" {
+		return fmt.Errorf("rule %s: a reason is required for %s actions", r.ID, r.Action.Type)
+	}
+
+	for key, value := range r.Action.Adjustments {
+		switch key {
+		case AdjustmentMaxAmount, AdjustmentMaxLoanToIncome:
+			if _, ok := value.(float64); !ok {
+				return fmt.Errorf("rule %s: adjustment %s must be a number", r.ID, key)
+			}
+		default:
+			return fmt.Errorf("rule %s: unknown adjustment %q", r.ID, key)
+		}
+	}
+	return nil
+}
+
+// expression translates a condition into an equivalent rule expression clause
+func (c RuleCondition) expression() (string, error) {
+	if !IsRuleFact(c.Field) {
+		return "", fmt.Errorf("unknown field %q", c.Field)
+	}
+
+	switch c.Operator {
+	case "gt", "lt", "eq", "gte", "lte":
+		operators := map[string]string{"gt": ">", "lt": "<", "eq": "==", "gte": ">=", "lte": "<="}
+		value, err := conditionLiteral(c.Value)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s %s %s", c.Field, operators[c.Operator], value), nil
+	case "in":
+		values, ok := c.Value.([]interface{})
+		if !ok || len(values) == 0 {
+			return "", fmt.Errorf("operator in on %s requires a non-empty array", c.Field)
+		}
+		literals := make([]string, 0, len(values))
+		for _, value := range values {
+			literal, err := conditionLiteral(value)
+			if err != nil {
+				return "", err
+			}
+			literals = append(literals, literal)
+		}
+		return fmt.Sprintf("%s IN (%s)", c.Field, strings.Join(literals, ", ")), nil
+	case "contains":
+		value, err := conditionLiteral(c.Value)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("contains(%s, %s)", c.Field, value), nil
+	default:
+		return "", fmt.Errorf("unknown operator %q", c.Operator)
+	}
+}
+
+// conditionLiteral renders a JSON condition value as an expression literal
+func conditionLiteral(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case float64:
+		return fmt.Sprintf("%v", v), nil
+	case int:
+		return fmt.Sprintf("%d", v), nil
+	case bool:
+		return fmt.Sprintf("%t", v), nil
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", `\'`) + "'", nil
+	default:
+		return "", fmt.Errorf("unsupported condition value %v", value)
+	}
+}
@@ -0,0 +1,387 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ScorecardStatus is the lifecycle status of a scorecard
+type ScorecardStatus string
+
+const (
+	ScorecardStatusDraft   ScorecardStatus = "DRAFT"
+	ScorecardStatusActive  ScorecardStatus = "ACTIVE"
+	ScorecardStatusRetired ScorecardStatus = "RETIRED"
+)
+
+// IsValid reports whether s is a known scorecard status
+func (s ScorecardStatus) IsValid() bool {
+	switch s {
+	case ScorecardStatusDraft, ScorecardStatusActive, ScorecardStatusRetired:
+		return true
+	}
+	return false
+}
+
+// Scorecard defaults and limits
+const (
+	DefaultScorecardRiskWeight = 0.5
+	MaxScorecardReasonCodes    = 4
+)
+
+// scorecardExcludedFields are facts a characteristic may not score: identifiers, and values that
+// are themselves derived from the scorecard or the overall risk score
+var scorecardExcludedFields = map[string]bool{
+	"application_id":    true,
+	"overall_score":     true,
+	"risk_factor_count": true,
+	"has_scorecard":     true,
+	"scorecard_score":   true,
+	"scorecard_band":    true,
+}
+
+// Scorecard is a points-based credit scorecard. Each characteristic scores one fact by the points
+// of the first attribute bin it falls in, and the score is the base points plus the points of
+// every characteristic. At most one scorecard is active; its score is blended into the overall
+// risk score alongside the raw bureau score.
+type Scorecard struct {
+	ID              string                    `json:"id"`
+	Name            string                    `json:"name"`
+	Description     string                    `json:"description,omitempty"`
+	Status          ScorecardStatus           `json:"status"`
+	BasePoints      int                       `json:"base_points"`
+	Characteristics []ScorecardCharacteristic `json:"characteristics"`
+	CutoffBands     []CutoffBand              `json:"cutoff_bands"`
+	RiskWeight      float64                   `json:"risk_weight"` // share of the overall risk score taken from the scorecard
+	CreatedBy       string                    `json:"created_by"`
+	CreatedAt       time.Time                 `json:"created_at"`
+	UpdatedAt       time.Time                 `json:"updated_at"`
+	ActivatedBy     string                    `json:"activated_by,omitempty"`
+	ActivatedAt     *time.Time                `json:"activated_at,omitempty"`
+	RetiredAt       *time.Time                `json:"retired_at,omitempty"`
+}
+
+// ScorecardCharacteristic scores one fact, e.g. credit_score or employment_type
+type ScorecardCharacteristic struct {
+	Name        string         `json:"name"`
+	Field       string         `json:"field"`       // rule fact scored, see RuleFactNames
+	ReasonCode  string         `json:"reason_code"` // reported when the characteristic costs points
+	Description string         `json:"description"` // reason given to the applicant, e.g. "Credit history is too short"
+	Bins        []AttributeBin `json:"bins"`
+}
+
+// AttributeBin awards points to values in a numeric range [min, max), to a list of categorical
+// values, or to every value when it sets neither
+type AttributeBin struct {
+	Label  string   `json:"label"`
+	Min    *float64 `json:"min,omitempty"`
+	Max    *float64 `json:"max,omitempty"`
+	Values []string `json:"values,omitempty"`
+	Points int      `json:"points"`
+}
+
+// CutoffBand is a named score band starting at MinScore, e.g. A from 700
+type CutoffBand struct {
+	Name         string       `json:"name"`
+	MinScore     int          `json:"min_score"`
+	RiskCategory RiskCategory `json:"risk_category,omitempty"`
+	Description  string       `json:"description,omitempty"`
+}
+
+// ScorecardRequest defines the contents of a scorecard
+type ScorecardRequest struct {
+	ID              string                    `json:"id"` // new scorecards only
+	Name            string                    `json:"name" binding:"required"`
+	Description     string                    `json:"description"`
+	BasePoints      int                       `json:"base_points"`
+	Characteristics []ScorecardCharacteristic `json:"characteristics"`
+	CutoffBands     []CutoffBand              `json:"cutoff_bands"`
+	RiskWeight      *float64                  `json:"risk_weight"` // defaults to 0.5
+}
+
+// Apply copies the requested definition onto a scorecard
+func (req *ScorecardRequest) Apply(scorecard *Scorecard) {
+	scorecard.Name = req.Name
+	scorecard.Description = req.Description
+	scorecard.BasePoints = req.BasePoints
+	scorecard.Characteristics = req.Characteristics
+	scorecard.CutoffBands = req.CutoffBands
+	scorecard.RiskWeight = DefaultScorecardRiskWeight
+	if req.RiskWeight != nil {
+		scorecard.RiskWeight = *req.RiskWeight
+	}
+}
+
+// Validate checks a scorecard definition can score every application
+func (s *Scorecard) Validate() error {
+	if !ruleIDPattern.MatchString(s.ID) || strings.TrimSpace(s.Name) == "" {
+		return errors.New("scorecard id (lowercase letters, digits, _ and -) and name are required")
+	}
+	if s.RiskWeight < 0 || s.RiskWeight > 1 {
+		return errors.New("risk_weight must be between 0 and 1")
+	}
+	if len(s.Characteristics) == 0 {
+		return errors.New("at least one characteristic is required")
+	}
+
+	names := make(map[string]bool, len(s.Characteristics))
+	for i := range s.Characteristics {
+		characteristic := &s.Characteristics[i]
+		if err := characteristic.validate(); err != nil {
+			return fmt.Errorf("characteristic %q: %w", characteristic.Name, err)
+		}
+		if names[characteristic.Name] {
+			return fmt.Errorf("characteristic %q is defined twice", characteristic.Name)
+		}
+		names[characteristic.Name] = true
+	}
+
+	if len(s.CutoffBands) == 0 {
+		return errors.New("at least one cutoff band is required")
+	}
+	bands := make(map[string]bool, len(s.CutoffBands))
+	for _, band := range s.CutoffBands {
+		if strings.TrimSpace(band.Name) == "" {
+			return errors.New("cutoff bands must be named")
+		}
+		if bands[band.Name] {
+			return fmt.Errorf("cutoff band %q is defined twice", band.Name)
+		}
+		bands[band.Name] = true
+		if band.RiskCategory != "" {
+			if _, ok := ProbabilityOfDefault[band.RiskCategory]; !ok {
+				return fmt.Errorf("cutoff band %q: unknown risk category %q", band.Name, band.RiskCategory)
+			}
+		}
+	}
+	if s.MinScore() == s.MaxScore() {
+		return errors.New("characteristic points must allow more than one score")
+	}
+	return nil
+}
+
+func (c *ScorecardCharacteristic) validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return errors.New("name is required")
+	}
+	if !IsRuleFact(c.Field) || scorecardExcludedFields[c.Field] {
+		return fmt.Errorf("field %q cannot be scored", c.Field)
+	}
+	if strings.TrimSpace(c.ReasonCode) == "" {
+		return errors.New("reason_code is required")
+	}
+	if len(c.Bins) == 0 {
+		return errors.New("at least one bin is required")
+	}
+	for _, bin := range c.Bins {
+		if bin.Min != nil && bin.Max != nil && *bin.Min >= *bin.Max {
+			return fmt.Errorf("bin %q: min must be less than max", bin.Label)
+		}
+		if len(bin.Values) > 0 && (bin.Min != nil || bin.Max != nil) {
+			return fmt.Errorf("bin %q: use either a range or values, not both", bin.Label)
+		}
+	}
+	return nil
+}
+
+// matches reports whether a fact value falls in the bin
+func (b *AttributeBin) matches(value interface{}) bool {
+	switch {
+	case len(b.Values) > 0:
+		text := strings.ToLower(fmt.Sprint(value))
+		for _, candidate := range b.Values {
+			if strings.ToLower(candidate) == text {
+				return true
+			}
+		}
+		return false
+	case b.Min != nil || b.Max != nil:
+		number, ok := value.(float64)
+		if !ok {
+			return false
+		}
+		return (b.Min == nil || number >= *b.Min) && (b.Max == nil || number < *b.Max)
+	default:
+		return true
+	}
+}
+
+// maxPoints returns the most points the characteristic can award
+func (c *ScorecardCharacteristic) maxPoints() int {
+	max := c.Bins[0].Points
+	for _, bin := range c.Bins[1:] {
+		if bin.Points > max {
+			max = bin.Points
+		}
+	}
+	return max
+}
+
+// minPoints returns the fewest points the characteristic can award; values outside every bin
+// score zero
+func (c *ScorecardCharacteristic) minPoints() int {
+	min := 0
+	hasCatchAll := false
+	for i, bin := range c.Bins {
+		if i == 0 || bin.Points < min {
+			min = bin.Points
+		}
+		if len(bin.Values) == 0 && bin.Min == nil && bin.Max == nil {
+			hasCatchAll = true
+		}
+	}
+	if !hasCatchAll && min > 0 {
+		min = 0
+	}
+	return min
+}
+
+// MaxScore returns the highest score the scorecard can produce
+func (s *Scorecard) MaxScore() int {
+	score := s.BasePoints
+	for i := range s.Characteristics {
+		score += s.Characteristics[i].maxPoints()
+	}
+	return score
+}
+
+// MinScore returns the lowest score the scorecard can produce
+func (s *Scorecard) MinScore() int {
+	score := s.BasePoints
+	for i := range s.Characteristics {
+		score += s.Characteristics[i].minPoints()
+	}
+	return score
+}
+
+// CharacteristicScore is the points one characteristic awarded an application
+type CharacteristicScore struct {
+	Characteristic string      `json:"characteristic"`
+	Field          string      `json:"field"`
+	Value          interface{} `json:"value"`
+	Bin            string      `json:"bin,omitempty"` // empty when the value fell outside every bin
+	Points         int         `json:"points"`
+	MaxPoints      int         `json:"max_points"`
+}
+
+// ReasonCode explains a characteristic that cost an application points, largest shortfall first
+type ReasonCode struct {
+	Code           string `json:"code"`
+	Characteristic string `json:"characteristic"`
+	Field          string `json:"field"`
+	Description    string `json:"description"`
+	PointsLost     int    `json:"points_lost"`
+}
+
+// ScorecardResult is an application's score on a scorecard
+type ScorecardResult struct {
+	ScorecardID     string                `json:"scorecard_id"`
+	Score           int                   `json:"score"`
+	MinScore        int                   `json:"min_score"`
+	MaxScore        int                   `json:"max_score"`
+	Band            string                `json:"band"`
+	BandRisk        RiskCategory          `json:"band_risk_category,omitempty"`
+	Risk            float64               `json:"risk"` // 0 at the maximum score, 1 at the minimum
+	RiskWeight      float64               `json:"risk_weight"`
+	Characteristics []CharacteristicScore `json:"characteristics"`
+	ReasonCodes     []ReasonCode          `json:"reason_codes"`
+}
+
+// Score scores an application from the facts rules are evaluated against
+func (s *Scorecard) Score(facts map[string]interface{}) *ScorecardResult {
+	result := &ScorecardResult{
+		ScorecardID: s.ID,
+		Score:       s.BasePoints,
+		MinScore:    s.MinScore(),
+		MaxScore:    s.MaxScore(),
+		RiskWeight:  s.RiskWeight,
+		ReasonCodes: []ReasonCode{},
+	}
+
+	for i := range s.Characteristics {
+		characteristic := &s.Characteristics[i]
+		value := facts[characteristic.Field]
+		scored := CharacteristicScore{
+			Characteristic: characteristic.Name,
+			Field:          characteristic.Field,
+			Value:          value,
+			MaxPoints:      characteristic.maxPoints(),
+		}
+		for _, bin := range characteristic.Bins {
+			if bin.matches(value) {
+				scored.Bin = bin.Label
+				scored.Points = bin.Points
+				break
+			}
+		}
+		result.Score += scored.Points
+		result.Characteristics = append(result.Characteristics, scored)
+
+		if lost := scored.MaxPoints - scored.Points; lost > 0 {
+			result.ReasonCodes = append(result.ReasonCodes, ReasonCode{
+				Code:           characteristic.ReasonCode,
+				Characteristic: characteristic.Name,
+				Field:          characteristic.Field,
+				Description:    characteristic.Description,
+				PointsLost:     lost,
+			})
+		}
+	}
+
+	sort.SliceStable(result.ReasonCodes, func(i, j int) bool {
+		return result.ReasonCodes[i].PointsLost > result.ReasonCodes[j].PointsLost
+	})
+	if len(result.ReasonCodes) > MaxScorecardReasonCodes {
+		result.ReasonCodes = result.ReasonCodes[:MaxScorecardReasonCodes]
+	}
+
+	if band := s.band(result.Score); band != nil {
+		result.Band = band.Name
+		result.BandRisk = band.RiskCategory
+	}
+	if span := result.MaxScore - result.MinScore; span > 0 {
+		result.Risk = math.Max(0, math.Min(1, float64(result.MaxScore-result.Score)/float64(span)))
+	}
+	return result
+}
+
+// band returns the highest cutoff band the score reaches, or the lowest band when it reaches none
+func (s *Scorecard) band(score int) *CutoffBand {
+	var best, lowest *CutoffBand
+	for i := range s.CutoffBands {
+		band := &s.CutoffBands[i]
+		if lowest == nil || band.MinScore < lowest.MinScore {
+			lowest = band
+		}
+		if score >= band.MinScore && (best == nil || band.MinScore > best.MinScore) {
+			best = band
+		}
+	}
+	if best == nil {
+		return lowest
+	}
+	return best
+}
+
+// ApplicationScorer scores applications with the active scorecard
+type ApplicationScorer interface {
+	// ScoreApplication returns the active scorecard's result, or nil when no scorecard is active
+	ScoreApplication(request *DecisionRequest, assessment *RiskAssessment) (*ScorecardResult, error)
+}
+
+// ScorecardRepository persists scorecards
+type ScorecardRepository interface {
+	CreateScorecard(ctx context.Context, scorecard *Scorecard) error
+	GetScorecard(ctx context.Context, id string) (*Scorecard, error)
+	ListScorecards(ctx context.Context, status ScorecardStatus) ([]Scorecard, error)
+	GetActiveScorecard(ctx context.Context) (*Scorecard, error)
+	UpdateDraft(ctx context.Context, scorecard *Scorecard) error
+	DeleteDraft(ctx context.Context, id string) error
+	ActivateScorecard(ctx context.Context, scorecard *Scorecard) error // retires the previously active scorecard
+	RetireScorecard(ctx context.Context, id string, retiredAt time.Time) error
+}
@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Simulation defaults and limits
+const (
+	DefaultSimulationWindow = 90 * 24 * time.Hour
+	DefaultSimulationLimit  = 1000
+	MaxSimulationLimit      = 10000
+	MaxSimulationAffected   = 200
+	DefaultLossGivenDefault = 0.45
+)
+
+// ProbabilityOfDefault is the assumed default rate of approved loans by risk category, used to
+// estimate expected loss as approved amount x probability of default x loss given default
+var ProbabilityOfDefault = map[RiskCategory]float64{
+	RiskLow:      0.02,
+	RiskMedium:   0.06,
+	RiskHigh:     0.15,
+	RiskCritical: 0.30,
+}
+
+// HistoricalDecision is a stored decision request with the latest decision made on it
+type HistoricalDecision struct {
+	Request      DecisionRequest
+	Decision     DecisionType
+	MaxAmount    float64
+	RuleVersions []string
+	DecisionDate *time.Time
+}
+
+// DecisionHistoryQuery selects the stored decision requests replayed by a simulation
+type DecisionHistoryQuery struct {
+	TenantID string // lending program whose requests are returned; the default program when empty
+	From     time.Time
+	To       time.Time
+	Limit    int
+}
+
+// RuleSetChanges describes a proposed rule set as changes to the published rules in force, or a
+// replacement for them when replace_published is set. Changes apply in order: stored versions,
+// then inline definitions, then removals.
+type RuleSetChanges struct {
+	Rules            []RuleVersionRequest `json:"rules"`             // inline definitions, replacing rules with the same id
+	RuleVersions     []string             `json:"rule_versions"`     // stored versions such as drafts, e.g. max_dti_ratio@1.1.0
+	RemoveRules      []string             `json:"remove_rules"`      // rule ids to leave out
+	ReplacePublished bool                 `json:"replace_published"` // use only the proposed rules
+}
+
+// Validate checks the changes describe a rule change
+func (c *RuleSetChanges) Validate() error {
+	if len(c.Rules) == 0 && len(c.RuleVersions) == 0 && len(c.RemoveRules) == 0 {
+		return errors.New("at least one of rules, rule_versions or remove_rules is required")
+	}
+	return nil
+}
+
+// SimulationRequest describes a proposed rule set to replay against past decision requests
+type SimulationRequest struct {
+	TenantID string `json:"-"` // lending program simulated, taken from the caller's program
+	RuleSetChanges
+	From             *time.Time `json:"from"`               // defaults to 90 days before to
+	To               *time.Time `json:"to"`                 // defaults to now
+	Limit            int        `json:"limit"`              // most recent requests replayed, default 1000
+	LossGivenDefault float64    `json:"loss_given_default"` // defaults to 0.45
+}
+
+// Normalize applies the defaults and checks the request describes a rule change
+func (r *SimulationRequest) Normalize(now time.Time) error {
+	if err := r.RuleSetChanges.Validate(); err != nil {
+		return err
+	}
+
+	if r.To == nil {
+		r.To = &now
+	}
+	if r.From == nil {
+		from := r.To.Add(-DefaultSimulationWindow)
+		r.From = &from
+	}
+	if !r.From.Before(*r.To) {
+		return errors.New("from must be before to")
+	}
+
+	switch {
+	case r.Limit < 0:
+		return errors.New("limit must not be negative")
+	case r.Limit == 0:
+		r.Limit = DefaultSimulationLimit
+	case r.Limit > MaxSimulationLimit:
+		return fmt.Errorf("limit must be at most %d", MaxSimulationLimit)
+	}
+
+	switch {
+	case r.LossGivenDefault < 0 || r.LossGivenDefault > 1:
+		return errors.New("loss_given_default must be between 0 and 1")
+	case r.LossGivenDefault == 0:
+		r.LossGivenDefault = DefaultLossGivenDefault
+	}
+	return nil
+}
+
+// ParseVersionedID splits a rule version reference such as max_dti_ratio@1.1.0
+func ParseVersionedID(ref string) (ruleID, version string, err error) {
+	parts := strings.SplitN(ref, "@", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("rule version %q must be written as rule_id@version", ref)
+	}
+	return parts[0], parts[1], nil
+}
+
+// SimulationMetrics summarizes the decisions a rule set makes on the replayed requests
+type SimulationMetrics struct {
+	Decisions        map[DecisionType]int `json:"decisions"`
+	ApprovedCount    int                  `json:"approved_count"` // approvals and conditional approvals
+	ApprovalRate     float64              `json:"approval_rate"`  // percentage of requests approved
+	ApprovedVolume   float64              `json:"approved_volume"`
+	ExpectedLoss     float64              `json:"expected_loss"`
+	ExpectedLossRate float64              `json:"expected_loss_rate"` // percentage of approved volume
+}
+
+// NewSimulationMetrics creates empty metrics
+func NewSimulationMetrics() SimulationMetrics {
+	return SimulationMetrics{Decisions: make(map[DecisionType]int)}
+}
+
+// Record adds a decision to the metrics
+func (m *SimulationMetrics) Record(decision DecisionType, approvedAmount, expectedLoss float64) {
+	m.Add(decision, 1, approvedAmount, expectedLoss)
+}
+
+// Add adds count decisions with their total approved amount and expected loss to the metrics
+func (m *SimulationMetrics) Add(decision DecisionType, count int, approvedAmount, expectedLoss float64) {
+	m.Decisions[decision] += count
+	if decision.IsApproval() {
+		m.ApprovedCount += count
+		m.ApprovedVolume += approvedAmount
+		m.ExpectedLoss += expectedLoss
+	}
+}
+
+// Finalize computes the rates once every decision is recorded
+func (m *SimulationMetrics) Finalize(total int) {
+	if total > 0 {
+		m.ApprovalRate = float64(m.ApprovedCount) / float64(total) * 100
+	}
+	if m.ApprovedVolume > 0 {
+		m.ExpectedLossRate = m.ExpectedLoss / m.ApprovedVolume * 100
+	}
+}
+
+// IsApproval reports whether a decision lends money, with or without conditions
+func (d DecisionType) IsApproval() bool {
+	return d == DecisionApprove || d == DecisionConditional
+}
+
+// SimulationDelta is the change from the baseline to the proposed rule set
+type SimulationDelta struct {
+	ApprovalRate     float64 `json:"approval_rate"` // percentage points
+	ApprovedCount    int     `json:"approved_count"`
+	ApprovedVolume   float64 `json:"approved_volume"`
+	ExpectedLoss     float64 `json:"expected_loss"`
+	ExpectedLossRate float64 `json:"expected_loss_rate"` // percentage points
+}
+
+// NewSimulationDelta returns the change from baseline to proposed metrics
+func NewSimulationDelta(baseline, proposed SimulationMetrics) SimulationDelta {
+	return SimulationDelta{
+		ApprovalRate:     proposed.ApprovalRate - baseline.ApprovalRate,
+		ApprovedCount:    proposed.ApprovedCount - baseline.ApprovedCount,
+		ApprovedVolume:   proposed.ApprovedVolume - baseline.ApprovedVolume,
+		ExpectedLoss:     proposed.ExpectedLoss - baseline.ExpectedLoss,
+		ExpectedLossRate: proposed.ExpectedLossRate - baseline.ExpectedLossRate,
+	}
+}
+
+// DecisionChangeKey names a change of decision in decision change counts, e.g. APPROVE_TO_DENY
+func DecisionChangeKey(from, to DecisionType) string {
+	return string(from) + "_TO_" + string(to)
+}
+
+// AffectedApplication is a replayed request whose decision or approved amount would change
+type AffectedApplication struct {
+	ApplicationID      string       `json:"application_id"`
+	RequestedAt        time.Time    `json:"requested_at"`
+	LoanAmount         float64      `json:"loan_amount"`
+	RiskCategory       RiskCategory `json:"risk_category"`
+	HistoricalDecision DecisionType `json:"historical_decision,omitempty"`
+	BaselineDecision   DecisionType `json:"baseline_decision"`
+	ProposedDecision   DecisionType `json:"proposed_decision"`
+	BaselineAmount     float64      `json:"baseline_amount"`
+	ProposedAmount     float64      `json:"proposed_amount"`
+	ProposedReason     string       `json:"proposed_reason"`
+	ProposedRules      []string     `json:"proposed_rules,omitempty"` // rules matched under the proposed set
+	ExpectedLossDelta  float64      `json:"expected_loss_delta"`
+}
+
+// SimulationResult compares the published rules in force with a proposed rule set on the same
+// replayed requests
+type SimulationResult struct {
+	From                  time.Time             `json:"from"`
+	To                    time.Time             `json:"to"`
+	ApplicationsReplayed  int                   `json:"applications_replayed"`
+	ApplicationsSkipped   int                   `json:"applications_skipped"` // requests that could not be assessed
+	LossGivenDefault      float64               `json:"loss_given_default"`
+	BaselineRuleVersions  []string              `json:"baseline_rule_versions"`
+	ProposedRuleVersions  []string              `json:"proposed_rule_versions"`
+	Baseline              SimulationMetrics     `json:"baseline"`
+	Proposed              SimulationMetrics     `json:"proposed"`
+	Delta                 SimulationDelta       `json:"delta"`
+	DecisionChanges       map[string]int        `json:"decision_changes"` // e.g. APPROVE_TO_DENY
+	AffectedCount         int                   `json:"affected_count"`
+	AffectedApplications  []AffectedApplication `json:"affected_applications"`
+	AffectedListTruncated bool                  `json:"affected_list_truncated"`
+	SimulatedAt           time.Time             `json:"simulated_at"`
+}
@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// DataSource is an external source a decision request is enriched from while deciding
+type DataSource string
+
+const (
+	DataSourceFraudScreening DataSource = "FRAUD_SCREENING" // fraud vendor screening
+	DataSourceCreditReport   DataSource = "CREDIT_REPORT"   // tri-merge report tradelines behind the income estimate
+)
+
+// Description returns the source's name for conditions and logs
+func (s DataSource) Description() string {
+	switch s {
+	case DataSourceFraudScreening:
+		return "fraud screening"
+	case DataSourceCreditReport:
+		return "credit report"
+	}
+	return string(s)
+}
+
+// Data source SLA defaults
+const (
+	DefaultFraudScreeningSLA = 3 * time.Second
+	DefaultCreditReportSLA   = 2 * time.Second
+	DefaultLateDataWindow    = 2 * time.Minute
+)
+
+// AdditionalDataPendingSources records the sources a provisional decision was made without, so
+// replays see the same provisional decision
+const AdditionalDataPendingSources = "pending_sources"
+
+// SourceSLAPolicy sets how long a decision waits for each data source. A source that misses its
+// SLA is left pending and the decision is made provisionally on the data available. The source
+// keeps running for LateDataWindow after its SLA, and its result re-decides the application.
+type SourceSLAPolicy struct {
+	FraudScreening time.Duration
+	CreditReport   time.Duration
+	LateDataWindow time.Duration
+}
+
+// WithDefaults fills unset settings with the defaults
+func (p SourceSLAPolicy) WithDefaults() SourceSLAPolicy {
+	if p.FraudScreening <= 0 {
+		p.FraudScreening = DefaultFraudScreeningSLA
+	}
+	if p.CreditReport <= 0 {
+		p.CreditReport = DefaultCreditReportSLA
+	}
+	if p.LateDataWindow <= 0 {
+		p.LateDataWindow = DefaultLateDataWindow
+	}
+	return p
+}
+
+// SLA returns how long a decision waits for a source
+func (p SourceSLAPolicy) SLA(source DataSource) time.Duration {
+	switch source {
+	case DataSourceFraudScreening:
+		return p.FraudScreening
+	case DataSourceCreditReport:
+		return p.CreditReport
+	}
+	return 0
+}
+
+// PendingSource is a data source that missed its SLA, and that a provisional decision was made
+// without
+type PendingSource struct {
+	Source   DataSource `json:"source"`
+	SLAMs    int64      `json:"sla_ms"`
+	MissedAt time.Time  `json:"missed_at"`
+}
+
+// PendingSources returns the sources recorded as pending with the request
+func (dr *DecisionRequest) PendingSources() []PendingSource {
+	var pending []PendingSource
+	if !decodeAdditionalData(dr.AdditionalData[AdditionalDataPendingSources], &pending) {
+		return nil
+	}
+	return pending
+}
+
+// IsSourcePending reports whether a source is recorded as pending with the request
+func (dr *DecisionRequest) IsSourcePending(source DataSource) bool {
+	for _, pending := range dr.PendingSources() {
+		if pending.Source == source {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordPendingSource records a source as pending with the request
+func (dr *DecisionRequest) RecordPendingSource(pending PendingSource) {
+	if dr.AdditionalData == nil {
+		dr.AdditionalData = make(map[string]interface{})
+	}
+	dr.AdditionalData[AdditionalDataPendingSources] = append(dr.PendingSources(), pending)
+}
+
+// ResolvePendingSource removes a source from those pending once its data has arrived
+func (dr *DecisionRequest) ResolvePendingSource(source DataSource) {
+	var remaining []PendingSource
+	for _, pending := range dr.PendingSources() {
+		if pending.Source != source {
+			remaining = append(remaining, pending)
+		}
+	}
+	if len(remaining) == 0 {
+		delete(dr.AdditionalData, AdditionalDataPendingSources)
+		return
+	}
+	dr.AdditionalData[AdditionalDataPendingSources] = remaining
+}
+
+// RedecisionNotifier tells the loan service an application was re-decided with late data, so a
+// provisional decision held in review is not acted on without the decision that replaced it
+type RedecisionNotifier interface {
+	NotifyRedecision(ctx context.Context, decision *DecisionResponse) error
+}
+
+// RedecisionNotification is a re-decision the loan service is still to be told of
+type RedecisionNotification struct {
+	DecisionID    int64
+	ApplicationID string
+	Attempts      int
+	CreatedAt     time.Time
+}
+
+// RedecisionOutbox keeps re-decisions until the loan service has been told of them, so a
+// notification that fails, or is cut off by a restart, is retried
+type RedecisionOutbox interface {
+	// EnqueueRedecisionNotification queues a saved re-decision, due at once
+	EnqueueRedecisionNotification(ctx context.Context, decisionID int64, applicationID string, at time.Time) error
+	// ClaimDueRedecisionNotifications returns up to limit notifications that are due and moves
+	// their next attempt lease later, so other instances do not send them at the same time
+	ClaimDueRedecisionNotifications(ctx context.Context, limit int, lease time.Duration) ([]RedecisionNotification, error)
+	MarkRedecisionNotificationDelivered(ctx context.Context, decisionID int64, at time.Time) error
+	// RescheduleRedecisionNotification records a failed attempt and when to try again
+	RescheduleRedecisionNotification(ctx context.Context, decisionID int64, lastError string, next time.Time) error
+}
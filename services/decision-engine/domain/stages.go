@@ -0,0 +1,80 @@
+package domain
+
+// RuleStage is a stage of rule evaluation. Hard knockouts run first and end the decision with a
+// denial; scoring and pricing rules run only for applications that clear every knockout.
+type RuleStage string
+
+const (
+	RuleStageKnockout RuleStage = "KNOCKOUT"
+	RuleStageScoring  RuleStage = "SCORING"
+)
+
+// Stage returns the stage a rule is evaluated in: knockout rules in the knockout stage, every
+// other rule in the scoring stage
+func (r *DecisionRule) Stage() RuleStage {
+	if r.Knockout {
+		return RuleStageKnockout
+	}
+	return RuleStageScoring
+}
+
+// StageStatus is the outcome of a stage
+type StageStatus string
+
+const (
+	StageStatusPassed     StageStatus = "PASSED"      // the knockout stage found no knockout
+	StageStatusKnockedOut StageStatus = "KNOCKED_OUT" // a knockout matched; the decision is final
+	StageStatusCompleted  StageStatus = "COMPLETED"   // the scoring stage ran
+	StageStatusSkipped    StageStatus = "SKIPPED"     // the scoring stage did not run after a knockout
+)
+
+// StageResult is the outcome of one rule evaluation stage
+type StageResult struct {
+	Stage          RuleStage    `json:"stage"`
+	Status         StageStatus  `json:"status"`
+	Decision       DecisionType `json:"decision,omitempty"` // the stage's decision; none when skipped
+	Reason         string       `json:"reason,omitempty"`
+	RulesEvaluated []string     `json:"rules_evaluated,omitempty"` // id@version of every rule evaluated
+	RuleHits       []string     `json:"rule_hits,omitempty"`       // ids of the rules that matched
+}
+
+// Stage returns a decision's result for a stage, or nil when the stage was not recorded
+func (d *DecisionResponse) Stage(stage RuleStage) *StageResult {
+	for i := range d.Stages {
+		if d.Stages[i].Stage == stage {
+			return &d.Stages[i]
+		}
+	}
+	return nil
+}
+
+// KnockedOut reports whether a hard knockout denied the decision
+func (d *DecisionResponse) KnockedOut() bool {
+	result := d.Stage(RuleStageKnockout)
+	return result != nil && result.Status == StageStatusKnockedOut
+}
+
+// RecordKnockout denies a decision on a knockout found outside the rules engine, such as a
+// collateral or underwriting policy limit, and records it in the knockout stage. The scoring stage
+// keeps its result, but the knockout overrides its decision; the first knockout gives the reason.
+func (d *DecisionResponse) RecordKnockout(hit RuleHit) {
+	d.RuleHits = append(d.RuleHits, hit)
+
+	result := d.Stage(RuleStageKnockout)
+	if result == nil {
+		d.Stages = append([]StageResult{{Stage: RuleStageKnockout}}, d.Stages...)
+		result = &d.Stages[0]
+	}
+	result.RuleHits = append(result.RuleHits, hit.RuleID)
+	if result.Status == StageStatusKnockedOut && d.Decision == DecisionDeny {
+		return
+	}
+
+	result.Status = StageStatusKnockedOut
+	result.Decision = DecisionDeny
+	result.Reason = hit.Reason
+	d.Decision = DecisionDeny
+	d.ApprovedAmount = 0
+	d.DecisionReason = hit.Reason
+	d.Reason = hit.Reason
+}
@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+)
+
+// StrategyStatus is the lifecycle status of a champion/challenger strategy
+type StrategyStatus string
+
+const (
+	StrategyStatusPaused StrategyStatus = "PAUSED"
+	StrategyStatusActive StrategyStatus = "ACTIVE"
+	StrategyStatusEnded  StrategyStatus = "ENDED"
+)
+
+// IsValid reports whether s is a known strategy status
+func (s StrategyStatus) IsValid() bool {
+	switch s {
+	case StrategyStatusPaused, StrategyStatusActive, StrategyStatusEnded:
+		return true
+	}
+	return false
+}
+
+// MaxStrategyDisagreements is the number of recent disagreements listed in a strategy report
+const MaxStrategyDisagreements = 50
+
+// DecisionStrategy runs a challenger rule set alongside the published (champion) rules on a share of
+// decision traffic. The challenger's decisions are recorded for comparison but never enforced.
+// At most one strategy is active at a time.
+type DecisionStrategy struct {
+	ID             string         `json:"id"`
+	Name           string         `json:"name"`
+	Description    string         `json:"description,omitempty"`
+	Challenger     RuleSetChanges `json:"challenger"`
+	TrafficPercent int            `json:"traffic_percent"` // share of decisions shadowed by the challenger
+	Status         StrategyStatus `json:"status"`
+	CreatedBy      string         `json:"created_by"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	StartedAt      *time.Time     `json:"started_at,omitempty"` // first activation
+	EndedAt        *time.Time     `json:"ended_at,omitempty"`
+}
+
+// StrategyRequest creates a strategy, paused until it is activated
+type StrategyRequest struct {
+	ID             string         `json:"id"`
+	Name           string         `json:"name" binding:"required"`
+	Description    string         `json:"description"`
+	Challenger     RuleSetChanges `json:"challenger"`
+	TrafficPercent int            `json:"traffic_percent"`
+}
+
+// Validate checks a new strategy definition
+func (r *StrategyRequest) Validate() error {
+	if !ruleIDPattern.MatchString(r.ID) || strings.TrimSpace(r.Name) == "" {
+		return errors.New("strategy id (lowercase letters, digits, _ and -) and name are required")
+	}
+	if err := ValidateTrafficPercent(r.TrafficPercent); err != nil {
+		return err
+	}
+	if err := r.Challenger.Validate(); err != nil {
+		return fmt.Errorf("challenger: %w", err)
+	}
+	return nil
+}
+
+// ValidateTrafficPercent checks a traffic split is a whole percentage
+func ValidateTrafficPercent(percent int) error {
+	if percent < 0 || percent > 100 {
+		return errors.New("traffic_percent must be between 0 and 100")
+	}
+	return nil
+}
+
+// InChallengerTraffic reports whether an application falls in the challenger's share of traffic.
+// The split is a stable hash of the strategy and application, so an application re-decided under
+// the same strategy is always treated the same way.
+func (s *DecisionStrategy) InChallengerTraffic(applicationID string) bool {
+	if s.TrafficPercent <= 0 {
+		return false
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(s.ID + ":" + applicationID))
+	return int(hash.Sum32()%100) < s.TrafficPercent
+}
+
+// ChallengerDecision records the champion and challenger decisions made on one application
+type ChallengerDecision struct {
+	ID                     int64        `json:"id"`
+	StrategyID             string       `json:"strategy_id"`
+	ApplicationID          string       `json:"application_id"`
+	RiskCategory           RiskCategory `json:"risk_category"`
+	ChampionDecision       DecisionType `json:"champion_decision"`
+	ChallengerDecision     DecisionType `json:"challenger_decision"`
+	ChampionAmount         float64      `json:"champion_amount"`
+	ChallengerAmount       float64      `json:"challenger_amount"`
+	ChampionExpectedLoss   float64      `json:"champion_expected_loss"`
+	ChallengerExpectedLoss float64      `json:"challenger_expected_loss"`
+	ChallengerReason       string       `json:"challenger_reason"`
+	ChampionRuleVersions   []string     `json:"champion_rule_versions,omitempty"`
+	ChallengerRuleVersions []string     `json:"challenger_rule_versions,omitempty"`
+	CreatedAt              time.Time    `json:"created_at"`
+}
+
+// ChallengerOutcome totals the recorded challenger decisions with the same pair of decisions
+type ChallengerOutcome struct {
+	ChampionDecision       DecisionType
+	ChallengerDecision     DecisionType
+	Count                  int
+	Agreed                 int
+	ChampionAmount         float64
+	ChallengerAmount       float64
+	ChampionExpectedLoss   float64
+	ChallengerExpectedLoss float64
+}
+
+// StrategyReport compares the champion and challenger on the decisions shadowed by a strategy
+type StrategyReport struct {
+	Strategy          DecisionStrategy     `json:"strategy"`
+	From              *time.Time           `json:"from,omitempty"`
+	To                *time.Time           `json:"to,omitempty"`
+	DecisionsCompared int                  `json:"decisions_compared"`
+	AgreementCount    int                  `json:"agreement_count"`
+	AgreementRate     float64              `json:"agreement_rate"` // percentage of decisions compared
+	Champion          SimulationMetrics    `json:"champion"`
+	Challenger        SimulationMetrics    `json:"challenger"`
+	Delta             SimulationDelta      `json:"delta"` // challenger minus champion
+	DecisionChanges   map[string]int       `json:"decision_changes"`
+	Disagreements     []ChallengerDecision `json:"recent_disagreements"`
+	GeneratedAt       time.Time            `json:"generated_at"`
+}
+
+// ChallengerService shadows decisions with the active champion/challenger strategy
+type ChallengerService interface {
+	// EvaluateChallenger returns the active strategy and the challenger's decision, or nils when
+	// no strategy is active or the application is outside the challenger's traffic
+	EvaluateChallenger(ctx context.Context, request *DecisionRequest, assessment *RiskAssessment) (*DecisionStrategy, *DecisionResponse, error)
+	RecordChallengerDecision(ctx context.Context, strategy *DecisionStrategy, champion, challenger *DecisionResponse) error
+}
+
+// StrategyRepository persists strategies and the challenger decisions made under them
+type StrategyRepository interface {
+	CreateStrategy(ctx context.Context, strategy *DecisionStrategy) error
+	GetStrategy(ctx context.Context, id string) (*DecisionStrategy, error)
+	ListStrategies(ctx context.Context, status StrategyStatus) ([]DecisionStrategy, error)
+	GetActiveStrategy(ctx context.Context) (*DecisionStrategy, error)
+	UpdateStrategy(ctx context.Context, strategy *DecisionStrategy) error
+	SaveChallengerDecision(ctx context.Context, decision *ChallengerDecision) error
+	GetChallengerOutcomes(ctx context.Context, strategyID string, from, to *time.Time) ([]ChallengerOutcome, error)
+	GetChallengerDisagreements(ctx context.Context, strategyID string, from, to *time.Time, limit int) ([]ChallengerDecision, error)
+}
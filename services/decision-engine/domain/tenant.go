@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// DefaultTenantID is the lending program of requests that name none, and the owner of the rules
+// and policies created before lending programs were scoped
+const DefaultTenantID = "default"
+
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Tenant is a lending program. Each program decides on its own decision rules, underwriting
+// policies and pricing matrix; no program's configuration takes part in another's decisions.
+type Tenant struct {
+	ID      string        `json:"id"`
+	Name    string        `json:"name"`
+	Pricing PricingMatrix `json:"pricing"`
+}
+
+// TenantRegistry holds the configured lending programs. The default program is always present.
+type TenantRegistry struct {
+	tenants map[string]*Tenant
+}
+
+// NewTenantRegistry creates a registry of the configured programs; pricing left unset falls back
+// to the default pricing matrix
+func NewTenantRegistry(tenants []Tenant) (*TenantRegistry, error) {
+	registry := &TenantRegistry{tenants: make(map[string]*Tenant, len(tenants)+1)}
+	for i := range tenants {
+		tenant := tenants[i]
+		if !tenantIDPattern.MatchString(tenant.ID) {
+			return nil, fmt.Errorf("tenant id %q must be lowercase letters, digits, _ and -", tenant.ID)
+		}
+		if _, exists := registry.tenants[tenant.ID]; exists {
+			return nil, fmt.Errorf("tenant %s is configured twice", tenant.ID)
+		}
+		if err := tenant.Pricing.Validate(); err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", tenant.ID, err)
+		}
+		tenant.Pricing = tenant.Pricing.WithDefaults()
+		registry.tenants[tenant.ID] = &tenant
+	}
+	if _, exists := registry.tenants[DefaultTenantID]; !exists {
+		registry.tenants[DefaultTenantID] = &Tenant{ID: DefaultTenantID, Name: "Default", Pricing: DefaultPricingMatrix()}
+	}
+	return registry, nil
+}
+
+// Lookup returns a program by id; an empty id is the default program. A nil registry knows only
+// the default program.
+func (r *TenantRegistry) Lookup(id string) (*Tenant, bool) {
+	id = TenantOrDefault(id)
+	if r == nil {
+		if id != DefaultTenantID {
+			return nil, false
+		}
+		return &Tenant{ID: DefaultTenantID, Name: "Default", Pricing: DefaultPricingMatrix()}, true
+	}
+	tenant, ok := r.tenants[id]
+	return tenant, ok
+}
+
+// Resolve returns a program by id like Lookup, failing with ERROR_UNKNOWN_TENANT for programs
+// that are not configured
+func (r *TenantRegistry) Resolve(id string) (*Tenant, error) {
+	tenant, ok := r.Lookup(id)
+	if !ok {
+		return nil, &DecisionError{
+			Code:        ERROR_UNKNOWN_TENANT,
+			Message:     "Unknown lending program",
+			Description: fmt.Sprintf("Lending program %q is not configured", id),
+			HTTPStatus:  400,
+		}
+	}
+	return tenant, nil
+}
+
+// IDs returns the ids of the configured programs, sorted
+func (r *TenantRegistry) IDs() []string {
+	if r == nil {
+		return []string{DefaultTenantID}
+	}
+	ids := make([]string, 0, len(r.tenants))
+	for id := range r.tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// TenantOrDefault returns id, or the default program's id when it is empty
+func TenantOrDefault(id string) string {
+	if id == "" {
+		return DefaultTenantID
+	}
+	return id
+}
+
+// Tenant returns the lending program the request is decided under
+func (dr *DecisionRequest) Tenant() string {
+	return TenantOrDefault(dr.TenantID)
+}
+
+// Tenant returns the lending program that owns the rule
+func (r *DecisionRule) Tenant() string {
+	return TenantOrDefault(r.TenantID)
+}
@@ -0,0 +1,374 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Bureau is a consumer credit bureau
+type Bureau string
+
+const (
+	BureauExperian   Bureau = "EXPERIAN"
+	BureauEquifax    Bureau = "EQUIFAX"
+	BureauTransUnion Bureau = "TRANSUNION"
+)
+
+// Bureaus are the bureaus pulled by a tri-merge, in report order
+var Bureaus = []Bureau{BureauExperian, BureauEquifax, BureauTransUnion}
+
+// IsValid reports whether b is a known bureau
+func (b Bureau) IsValid() bool {
+	for _, bureau := range Bureaus {
+		if b == bureau {
+			return true
+		}
+	}
+	return false
+}
+
+// ScoreSelectionPolicy chooses the score a tri-merge reports from the bureau scores
+type ScoreSelectionPolicy string
+
+const (
+	// ScorePolicyMiddle takes the middle of three scores, the lower of two
+	ScorePolicyMiddle ScoreSelectionPolicy = "MIDDLE"
+	// ScorePolicyLowest takes the lowest score
+	ScorePolicyLowest ScoreSelectionPolicy = "LOWEST"
+)
+
+// IsValid reports whether p is a known score selection policy
+func (p ScoreSelectionPolicy) IsValid() bool {
+	return p == ScorePolicyMiddle || p == ScorePolicyLowest
+}
+
+// BureauPullStatus is the outcome of one bureau pull
+type BureauPullStatus string
+
+const (
+	BureauPullSuccess BureauPullStatus = "SUCCESS"
+	BureauPullFailed  BureauPullStatus = "FAILED"
+	BureauPullTimeout BureauPullStatus = "TIMEOUT"
+	// BureauPullUnavailable means the bureau's circuit breaker was open and it was not called
+	BureauPullUnavailable BureauPullStatus = "UNAVAILABLE"
+)
+
+// TriMergeStatus is COMPLETE when every bureau returned a report and PARTIAL otherwise
+type TriMergeStatus string
+
+const (
+	TriMergeComplete TriMergeStatus = "COMPLETE"
+	TriMergePartial  TriMergeStatus = "PARTIAL"
+)
+
+// BureauReport is one bureau's report: the payload as received, and the same report normalized.
+// A cached report is a stored report reused instead of pulling the bureau again.
+type BureauReport struct {
+	ID        int64           `json:"id,omitempty"`
+	Bureau    Bureau          `json:"bureau"`
+	RawReport json.RawMessage `json:"raw_report"`
+	Report    *CreditReport   `json:"report"`
+	PullType  PullType        `json:"pull_type"`
+	Product   string          `json:"product,omitempty"` // the bureau product pulled
+	PulledAt  time.Time       `json:"pulled_at"`
+	Cached    bool            `json:"cached"`
+}
+
+// StoredBureauReport is a raw bureau report kept with the tri-merge it was pulled for
+type StoredBureauReport struct {
+	ID        int64           `json:"id"`
+	MergeID   int64           `json:"merge_id"`
+	Bureau    Bureau          `json:"bureau"`
+	UserID    string          `json:"user_id"`
+	SSNToken  string          `json:"-"`
+	RawReport json.RawMessage `json:"raw_report"`
+	PullType  PullType        `json:"pull_type"`
+	PulledAt  time.Time       `json:"pulled_at"`
+	// SourceReportID is the stored report this one was reused from, when served from the cache
+	SourceReportID int64 `json:"source_report_id,omitempty"`
+}
+
+// BureauPullResult records how one bureau pull in a tri-merge went
+type BureauPullResult struct {
+	Bureau     Bureau           `json:"bureau"`
+	Status     BureauPullStatus `json:"status"`
+	Score      int              `json:"score,omitempty"`
+	ScoreModel string           `json:"score_model,omitempty"`
+	Cached     bool             `json:"cached"`
+	Error      string           `json:"error,omitempty"`
+	DurationMs int64            `json:"duration_ms"`
+}
+
+// BureauScore is the score one bureau reported
+type BureauScore struct {
+	Bureau     Bureau `json:"bureau"`
+	Score      int    `json:"score"`
+	ScoreModel string `json:"score_model,omitempty"`
+}
+
+// MergedTradeline is a tradeline reported by one or more bureaus
+type MergedTradeline struct {
+	CreditAccount
+	ReportedBy []Bureau `json:"reported_by"`
+}
+
+// MergedInquiry is an inquiry reported by one or more bureaus
+type MergedInquiry struct {
+	CreditInquiry
+	ReportedBy []Bureau `json:"reported_by"`
+}
+
+// TriMergeRequest pulls and merges the reports of every bureau for an applicant
+type TriMergeRequest struct {
+	CreditReportRequest
+}
+
+// TriMergeReport merges the reports of up to three bureaus into one view of an applicant's credit
+type TriMergeReport struct {
+	ID                int64                `json:"id"`
+	UserID            string               `json:"user_id"`
+	SSNToken          string               `json:"-"`
+	ApplicationID     string               `json:"application_id,omitempty"`
+	PullType          PullType             `json:"pull_type"`
+	Status            TriMergeStatus       `json:"status"`
+	ScorePolicy       ScoreSelectionPolicy `json:"score_policy"`
+	SelectedScore     int                  `json:"selected_score"`
+	SelectedBureau    Bureau               `json:"selected_bureau"`
+	Scores            []BureauScore        `json:"scores"`
+	Bureaus           []BureauPullResult   `json:"bureaus"`
+	Tradelines        []MergedTradeline    `json:"tradelines"`
+	Inquiries         []MergedInquiry      `json:"inquiries"`
+	PublicRecords     []PublicRecord       `json:"public_records"`
+	Collections       []Collection         `json:"collections"`
+	PaymentHistory    PaymentHistory       `json:"payment_history"` // worst reported by any bureau
+	CreditUtilization float64              `json:"credit_utilization"`
+	DuplicatesRemoved int                  `json:"duplicates_removed"`
+	RequestedAt       time.Time            `json:"requested_at"`
+	CompletedAt       time.Time            `json:"completed_at"`
+}
+
+// SelectScore chooses the reported score. Middle takes the median of three scores and the lower
+// of two; both policies take the only score when one bureau responded.
+func SelectScore(scores []BureauScore, policy ScoreSelectionPolicy) (BureauScore, bool) {
+	if len(scores) == 0 {
+		return BureauScore{}, false
+	}
+
+	sorted := append([]BureauScore{}, scores...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Score < sorted[j].Score })
+	if policy == ScorePolicyMiddle && len(sorted) >= 3 {
+		return sorted[len(sorted)/2], true
+	}
+	return sorted[0], true
+}
+
+// paymentStatusSeverity orders normalized tradeline payment statuses from best to worst
+var paymentStatusSeverity = map[string]int{
+	"CURRENT":       0,
+	"30_DAYS_LATE":  1,
+	"60_DAYS_LATE":  2,
+	"90_DAYS_LATE":  3,
+	"120_DAYS_LATE": 4,
+	"COLLECTION":    5,
+	"CHARGE_OFF":    6,
+}
+
+// WorsePaymentStatus reports whether status a is worse than status b
+func WorsePaymentStatus(a, b string) bool {
+	return paymentStatusSeverity[a] > paymentStatusSeverity[b]
+}
+
+// MergeCreditReports merges normalized bureau reports. Tradelines reported by several bureaus
+// are kept once, as the most recently reported copy with the worst payment status any bureau
+// reported; inquiries, public records and collections are de-duplicated the same way.
+func MergeCreditReports(reports []BureauReport, policy ScoreSelectionPolicy) *TriMergeReport {
+	merged := &TriMergeReport{
+		ScorePolicy:   policy,
+		Scores:        []BureauScore{},
+		Tradelines:    []MergedTradeline{},
+		Inquiries:     []MergedInquiry{},
+		PublicRecords: []PublicRecord{},
+		Collections:   []Collection{},
+	}
+
+	tradelines := make(map[string]int)
+	inquiries := make(map[string]int)
+	records := make(map[string]bool)
+	collections := make(map[string]bool)
+	historySet := false
+
+	for _, bureauReport := range reports {
+		report := bureauReport.Report
+		if report == nil {
+			continue
+		}
+		merged.Scores = append(merged.Scores, BureauScore{
+			Bureau:     bureauReport.Bureau,
+			Score:      report.CreditScore,
+			ScoreModel: report.ScoreModel,
+		})
+
+		for _, account := range report.Accounts {
+			key := tradelineKey(account)
+			if i, ok := tradelines[key]; ok {
+				existing := &merged.Tradelines[i]
+				worst := existing.PaymentStatus
+				if WorsePaymentStatus(account.PaymentStatus, worst) {
+					worst = account.PaymentStatus
+				}
+				if account.LastReported.After(existing.LastReported) {
+					existing.CreditAccount = account
+				}
+				existing.PaymentStatus = worst
+				existing.ReportedBy = append(existing.ReportedBy, bureauReport.Bureau)
+				merged.DuplicatesRemoved++
+				continue
+			}
+			tradelines[key] = len(merged.Tradelines)
+			merged.Tradelines = append(merged.Tradelines, MergedTradeline{CreditAccount: account, ReportedBy: []Bureau{bureauReport.Bureau}})
+		}
+
+		for _, inquiry := range report.Inquiries {
+			key := strings.Join([]string{normalizeName(inquiry.Creditor), strings.ToUpper(inquiry.InquiryType), inquiry.InquiryDate.Format("2006-01-02")}, "|")
+			if i, ok := inquiries[key]; ok {
+				merged.Inquiries[i].ReportedBy = append(merged.Inquiries[i].ReportedBy, bureauReport.Bureau)
+				merged.DuplicatesRemoved++
+				continue
+			}
+			inquiries[key] = len(merged.Inquiries)
+			merged.Inquiries = append(merged.Inquiries, MergedInquiry{CreditInquiry: inquiry, ReportedBy: []Bureau{bureauReport.Bureau}})
+		}
+
+		for _, record := range report.PublicRecords {
+			key := strings.Join([]string{strings.ToUpper(record.RecordType), record.FilingDate.Format("2006-01-02"), fmt.Sprintf("%.2f", record.Amount)}, "|")
+			if records[key] {
+				merged.DuplicatesRemoved++
+				continue
+			}
+			records[key] = true
+			merged.PublicRecords = append(merged.PublicRecords, record)
+		}
+
+		for _, collection := range report.Collections {
+			key := strings.Join([]string{normalizeName(collection.OriginalCreditor), normalizeName(collection.CollectionAgency), fmt.Sprintf("%.2f", collection.OriginalAmount)}, "|")
+			if collections[key] {
+				merged.DuplicatesRemoved++
+				continue
+			}
+			collections[key] = true
+			merged.Collections = append(merged.Collections, collection)
+		}
+
+		if !historySet {
+			merged.PaymentHistory = report.PaymentHistory
+			historySet = true
+		} else {
+			merged.PaymentHistory = worsePaymentHistory(merged.PaymentHistory, report.PaymentHistory)
+		}
+	}
+
+	if selected, ok := SelectScore(merged.Scores, policy); ok {
+		merged.SelectedScore = selected.Score
+		merged.SelectedBureau = selected.Bureau
+	}
+	merged.CreditUtilization = revolvingUtilization(merged.Tradelines)
+	return merged
+}
+
+// tradelineKey identifies a tradeline across bureaus by creditor, account type, open month and
+// the last four digits of the account number, which bureaus mask differently
+func tradelineKey(account CreditAccount) string {
+	digits := make([]rune, 0, len(account.AccountID))
+	for _, r := range account.AccountID {
+		if unicode.IsDigit(r) {
+			digits = append(digits, r)
+		}
+	}
+	if len(digits) > 4 {
+		digits = digits[len(digits)-4:]
+	}
+	return strings.Join([]string{
+		normalizeName(account.Creditor),
+		strings.ToUpper(account.AccountType),
+		account.OpenDate.Format("2006-01"),
+		string(digits),
+	}, "|")
+}
+
+// normalizeName upper-cases a creditor name and drops punctuation and spacing
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// worsePaymentHistory combines two bureaus' payment histories, keeping the worse of each measure
+func worsePaymentHistory(a, b PaymentHistory) PaymentHistory {
+	worst := a
+	if b.LatePayments > worst.LatePayments {
+		worst.LatePayments = b.LatePayments
+	}
+	if b.Defaults > worst.Defaults {
+		worst.Defaults = b.Defaults
+	}
+	if b.Bankruptcies > worst.Bankruptcies {
+		worst.Bankruptcies = b.Bankruptcies
+	}
+	if b.OnTimePayments < worst.OnTimePayments {
+		worst.OnTimePayments = b.OnTimePayments
+	}
+	if b.PaymentScore < worst.PaymentScore {
+		worst.PaymentScore = b.PaymentScore
+	}
+	// The oldest account any bureau knows of sets the length of credit history
+	if b.CreditAge > worst.CreditAge {
+		worst.CreditAge = b.CreditAge
+	}
+	return worst
+}
+
+// revolvingUtilization is the percentage of revolving limits in use across merged tradelines
+func revolvingUtilization(tradelines []MergedTradeline) float64 {
+	var balance, limit float64
+	for _, tradeline := range tradelines {
+		if tradeline.CreditLimit > 0 {
+			balance += tradeline.Balance
+			limit += tradeline.CreditLimit
+		}
+	}
+	if limit == 0 {
+		return 0
+	}
+	return balance / limit * 100
+}
+
+// CreditBureauClient pulls reports from individual bureaus
+type CreditBureauClient interface {
+	PullBureauReport(ctx context.Context, bureau Bureau, request *CreditReportRequest) (*BureauReport, error)
+}
+
+// CreditReportRepository persists raw bureau reports and the tri-merge reports built from them
+type CreditReportRepository interface {
+	// SaveTriMergeReport saves a tri-merge report and the raw reports it was built from in one
+	// transaction, setting the ids of the report and of the raw reports
+	SaveTriMergeReport(ctx context.Context, report *TriMergeReport, rawReports []BureauReport) error
+	GetTriMergeReport(ctx context.Context, id int64) (*TriMergeReport, error)
+	GetBureauReports(ctx context.Context, mergeID int64) ([]StoredBureauReport, error)
+	// SaveBureauReport saves a report pulled outside a tri-merge, setting its id
+	SaveBureauReport(ctx context.Context, userID, ssnToken string, report *BureauReport) error
+	// GetBureauReport returns a stored bureau report, normalized
+	GetBureauReport(ctx context.Context, id int64) (*BureauReport, error)
+	// GetLatestBureauReport returns the newest report of one of the pull types pulled from a bureau
+	// for a user and SSN token since a time, or nil when there is none. Reports reused from the
+	// cache are not considered.
+	GetLatestBureauReport(ctx context.Context, userID, ssnToken string, bureau Bureau, pullTypes []PullType, since time.Time) (*BureauReport, error)
+}
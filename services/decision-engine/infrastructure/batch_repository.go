@@ -0,0 +1,304 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+const batchJobColumns = `id, purpose, reference, status, concurrency, total, processed, succeeded, failed,
+	decision_counts, error, requested_by, created_at, updated_at, started_at, completed_at`
+
+const batchResultColumns = `job_id, item_index, application_id, status, decision_id, decision, risk_category,
+	max_amount, interest_rate, error_code, error_message, processed_at`
+
+// BatchRepository implements batch decision job persistence
+type BatchRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewBatchRepository creates a new batch repository
+func NewBatchRepository(db *sql.DB, logger *zap.Logger) *BatchRepository {
+	return &BatchRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateBatchJob inserts a new job and sets its id
+func (r *BatchRepository) CreateBatchJob(ctx context.Context, job *domain.BatchJob) error {
+	countsJSON, err := json.Marshal(job.Decisions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal decision counts: %w", err)
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO decision_batch_jobs (
+			purpose, reference, status, concurrency, total, processed, succeeded, failed,
+			decision_counts, requested_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id`,
+		job.Purpose,
+		nullString(job.Reference),
+		job.Status,
+		job.Concurrency,
+		job.Total,
+		job.Processed,
+		job.Succeeded,
+		job.Failed,
+		countsJSON,
+		nullString(job.RequestedBy),
+		job.CreatedAt,
+		job.UpdatedAt,
+	).Scan(&job.ID)
+	if err != nil {
+		r.logger.Error("Failed to create batch job", zap.Error(err))
+		return fmt.Errorf("failed to create batch job: %w", err)
+	}
+	return nil
+}
+
+// UpdateBatchJob saves a job's status, progress and update time
+func (r *BatchRepository) UpdateBatchJob(ctx context.Context, job *domain.BatchJob) error {
+	countsJSON, err := json.Marshal(job.Decisions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal decision counts: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE decision_batch_jobs
+		SET status = $2, processed = $3, succeeded = $4, failed = $5, decision_counts = $6, error = $7,
+			updated_at = $8, started_at = $9, completed_at = $10
+		WHERE id = $1`,
+		job.ID,
+		job.Status,
+		job.Processed,
+		job.Succeeded,
+		job.Failed,
+		countsJSON,
+		nullString(job.Error),
+		job.UpdatedAt,
+		job.StartedAt,
+		job.CompletedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update batch job", zap.Int64("job_id", job.ID), zap.Error(err))
+		return fmt.Errorf("failed to update batch job: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update batch job: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("batch job not found: %d", job.ID)
+	}
+	return nil
+}
+
+// GetBatchJob returns a job by id
+func (r *BatchRepository) GetBatchJob(ctx context.Context, id int64) (*domain.BatchJob, error) {
+	var (
+		job        domain.BatchJob
+		reference  sql.NullString
+		countsJSON []byte
+		jobError   sql.NullString
+		requester  sql.NullString
+		startedAt  sql.NullTime
+		finishedAt sql.NullTime
+	)
+	err := r.db.QueryRowContext(ctx, `SELECT `+batchJobColumns+` FROM decision_batch_jobs WHERE id = $1`, id).Scan(
+		&job.ID,
+		&job.Purpose,
+		&reference,
+		&job.Status,
+		&job.Concurrency,
+		&job.Total,
+		&job.Processed,
+		&job.Succeeded,
+		&job.Failed,
+		&countsJSON,
+		&jobError,
+		&requester,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&startedAt,
+		&finishedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("batch job not found: %d", id)
+	}
+	if err != nil {
+		r.logger.Error("Failed to get batch job", zap.Int64("job_id", id), zap.Error(err))
+		return nil, fmt.Errorf("failed to get batch job: %w", err)
+	}
+
+	if err := json.Unmarshal(countsJSON, &job.Decisions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal decision counts: %w", err)
+	}
+	job.Reference = reference.String
+	job.Error = jobError.String
+	job.RequestedBy = requester.String
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		job.CompletedAt = &finishedAt.Time
+	}
+	return &job, nil
+}
+
+// SaveBatchResults inserts item results in one transaction
+func (r *BatchRepository) SaveBatchResults(ctx context.Context, results []domain.BatchItemResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	logger := r.logger.With(zap.Int64("job_id", results[0].JobID), zap.Int("results", len(results)))
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO decision_batch_results (`+batchResultColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare batch result insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, result := range results {
+		_, err := stmt.ExecContext(ctx,
+			result.JobID,
+			result.ItemIndex,
+			result.ApplicationID,
+			result.Status,
+			sql.NullInt64{Int64: result.DecisionID, Valid: result.DecisionID > 0},
+			nullString(string(result.Decision)),
+			nullString(string(result.RiskCategory)),
+			result.MaxAmount,
+			result.InterestRate,
+			nullString(result.ErrorCode),
+			nullString(result.ErrorMessage),
+			result.ProcessedAt,
+		)
+		if err != nil {
+			logger.Error("Failed to save batch result", zap.Int("item_index", result.ItemIndex), zap.Error(err))
+			return fmt.Errorf("failed to save batch result %d: %w", result.ItemIndex, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit transaction", zap.Error(err))
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetBatchResults returns a page of a job's item results in batch order
+func (r *BatchRepository) GetBatchResults(ctx context.Context, query domain.BatchResultsQuery) (*domain.BatchResultsPage, error) {
+	page := &domain.BatchResultsPage{
+		JobID:   query.JobID,
+		Limit:   query.Limit,
+		Offset:  query.Offset,
+		Results: []domain.BatchItemResult{},
+	}
+
+	condition := `job_id = $1`
+	args := []interface{}{query.JobID}
+	if query.Status != "" {
+		condition += ` AND status = $2`
+		args = append(args, query.Status)
+	}
+
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM decision_batch_results WHERE `+condition, args...).Scan(&page.Total); err != nil {
+		r.logger.Error("Failed to count batch results", zap.Int64("job_id", query.JobID), zap.Error(err))
+		return nil, fmt.Errorf("failed to count batch results: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT `+batchResultColumns+` FROM decision_batch_results WHERE %s ORDER BY item_index LIMIT %d OFFSET %d`,
+		condition, query.Limit, query.Offset), args...)
+	if err != nil {
+		r.logger.Error("Failed to get batch results", zap.Int64("job_id", query.JobID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get batch results: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			result       domain.BatchItemResult
+			decisionID   sql.NullInt64
+			decision     sql.NullString
+			riskCategory sql.NullString
+			maxAmount    sql.NullFloat64
+			interestRate sql.NullFloat64
+			errorCode    sql.NullString
+			errorMessage sql.NullString
+		)
+		err := rows.Scan(
+			&result.JobID,
+			&result.ItemIndex,
+			&result.ApplicationID,
+			&result.Status,
+			&decisionID,
+			&decision,
+			&riskCategory,
+			&maxAmount,
+			&interestRate,
+			&errorCode,
+			&errorMessage,
+			&result.ProcessedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan batch result: %w", err)
+		}
+		result.DecisionID = decisionID.Int64
+		result.Decision = domain.DecisionType(decision.String)
+		result.RiskCategory = domain.RiskCategory(riskCategory.String)
+		result.MaxAmount = maxAmount.Float64
+		result.InterestRate = interestRate.Float64
+		result.ErrorCode = errorCode.String
+		result.ErrorMessage = errorMessage.String
+		page.Results = append(page.Results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read batch results: %w", err)
+	}
+	return page, nil
+}
+
+// FailStaleBatchJobs marks pending and running jobs not updated since staleBefore failed. Jobs
+// run in the instance that accepted them, so a job that stops saving progress was cut off.
+func (r *BatchRepository) FailStaleBatchJobs(ctx context.Context, staleBefore time.Time, reason string, at time.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE decision_batch_jobs
+		SET status = $1, error = $2, completed_at = $3, updated_at = $3
+		WHERE status IN ($4, $5) AND updated_at < $6`,
+		domain.BatchJobFailed,
+		reason,
+		at,
+		domain.BatchJobPending,
+		domain.BatchJobRunning,
+		staleBefore,
+	)
+	if err != nil {
+		r.logger.Error("Failed to fail stale batch jobs", zap.Error(err))
+		return 0, fmt.Errorf("failed to fail stale batch jobs: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail stale batch jobs: %w", err)
+	}
+	return int(affected), nil
+}
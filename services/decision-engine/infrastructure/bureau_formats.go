@@ -0,0 +1,964 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+)
+
+// bureauFormat is the layout one bureau sends its reports in. The bureaus report the same
+// information but nest, name and format it their own way: Experian pads scores and writes dates as
+// MMDDYYYY, Equifax nests reports under consumers and writes ISO dates, TransUnion wraps the file
+// in its transaction envelope and sends numbers as zero-padded strings. A format reads a payload
+// back into the bureau's codes, which normalizeBureauReport maps onto the common report model.
+type bureauFormat interface {
+	// encode renders a report as the bureau sends it; simulated and recorded responses go through
+	// it so they reach normalization as a live response would
+	encode(raw *rawBureauReport) ([]byte, error)
+	decode(payload []byte) (*rawBureauReport, error)
+}
+
+var bureauFormats = map[domain.Bureau]bureauFormat{
+	domain.BureauExperian:   experianFormat{},
+	domain.BureauEquifax:    equifaxFormat{},
+	domain.BureauTransUnion: transUnionFormat{},
+}
+
+// parseBureauReport converts a bureau's report payload into the common report model
+func parseBureauReport(bureau domain.Bureau, payload []byte) (*domain.CreditReport, error) {
+	format, ok := bureauFormats[bureau]
+	if !ok {
+		return nil, fmt.Errorf("unknown credit bureau: %s", bureau)
+	}
+	raw, err := format.decode(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s report: %w", bureau, err)
+	}
+	return normalizeBureauReport(bureau, bureauProfiles[bureau], raw)
+}
+
+// Experian: a Credit Profile response. Every section is a list, dates are MMDDYYYY (MMYYYY for
+// open dates), the score is a zero-padded string and amounts are whole dollars.
+
+type experianReport struct {
+	CreditProfile []experianCreditProfile `json:"creditProfile"`
+}
+
+type experianCreditProfile struct {
+	HeaderRecord   []experianHeader       `json:"headerRecord"`
+	RiskModel      []experianRiskModel    `json:"riskModel"`
+	SSN            []experianSSN          `json:"ssn"`
+	Tradeline      []experianTradeline    `json:"tradeline"`
+	Inquiry        []experianInquiry      `json:"inquiry"`
+	PublicRecord   []experianPublicRecord `json:"publicRecord,omitempty"`
+	Collection     []experianCollection   `json:"collection,omitempty"`
+	FraudShield    []experianFraudAlert   `json:"fraudShield,omitempty"`
+	ProfileSummary experianProfileSummary `json:"profileSummary"`
+}
+
+type experianHeader struct {
+	ReportDate      string `json:"reportDate"` // MMDDYYYY
+	ReportTime      string `json:"reportTime"` // HHMMSS, UTC
+	ReferenceNumber string `json:"referenceNumber"`
+	Product         string `json:"product"`
+	InquiryType     string `json:"inquiryType"`
+}
+
+type experianRiskModel struct {
+	ModelIndicator string `json:"modelIndicator"`
+	Score          string `json:"score"` // zero-padded, e.g. 0678
+}
+
+type experianSSN struct {
+	Number string `json:"number"` // masked to the last four digits
+}
+
+type experianTradeline struct {
+	SubscriberName string `json:"subscriberName"`
+	AccountNumber  string `json:"accountNumber"`
+	AccountType    string `json:"accountType"`
+	OpenDate       string `json:"openDate"`   // MMYYYY
+	StatusDate     string `json:"statusDate"` // MMDDYYYY
+	BalanceAmount  int64  `json:"balanceAmount"`
+	Amount1        int64  `json:"amount1,omitempty"` // credit limit when amount1Qualifier is L
+	Amount1Qual    string `json:"amount1Qualifier,omitempty"`
+	Status         string `json:"status"`
+	MonthsHistory  string `json:"monthsHistory"`
+}
+
+type experianInquiry struct {
+	SubscriberName string `json:"subscriberName"`
+	Date           string `json:"date"` // MMDDYYYY
+	Type           string `json:"type"`
+	Purpose        string `json:"purpose,omitempty"`
+}
+
+type experianPublicRecord struct {
+	ReferenceNumber string `json:"referenceNumber"`
+	Evaluation      string `json:"evaluation"` // record type
+	CourtName       string `json:"courtName,omitempty"`
+	FilingDate      string `json:"filingDate"` // MMDDYYYY
+	Amount          int64  `json:"amount,omitempty"`
+	Status          string `json:"status"`
+}
+
+type experianCollection struct {
+	AccountNumber        string `json:"accountNumber"`
+	SubscriberName       string `json:"subscriberName"` // the collection agency
+	OriginalCreditorName string `json:"originalCreditorName"`
+	BalanceAmount        int64  `json:"balanceAmount"`
+	OriginalAmount       int64  `json:"originalAmount"`
+	StatusDate           string `json:"statusDate"` // MMDDYYYY
+	Status               string `json:"status"`
+}
+
+type experianFraudAlert struct {
+	Indicator   string `json:"indicator"`
+	Phone       string `json:"phone,omitempty"`
+	DatePlaced  string `json:"datePlaced"`            // MMDDYYYY
+	DateExpires string `json:"dateExpires,omitempty"` // MMDDYYYY
+}
+
+type experianProfileSummary struct {
+	PaidAccounts      int     `json:"paidAccounts"`
+	DelinquentCount   int     `json:"delinquentCount"`
+	DerogCount        int     `json:"derogCounter"`
+	BankruptcyCount   int     `json:"bankruptcyCount"`
+	OldestTradeMonths int     `json:"oldestTradeMonths"`
+	PaymentScore      float64 `json:"paymentScore"`
+}
+
+const (
+	experianDate  = "01022006"
+	experianMonth = "012006"
+	experianTime  = "150405"
+)
+
+type experianFormat struct{}
+
+func (experianFormat) encode(raw *rawBureauReport) ([]byte, error) {
+	reported := raw.ReportedAt.UTC()
+	profile := experianCreditProfile{
+		HeaderRecord: []experianHeader{{
+			ReportDate:      reported.Format(experianDate),
+			ReportTime:      reported.Format(experianTime),
+			ReferenceNumber: raw.ReferenceNumber,
+			Product:         raw.Product,
+			InquiryType:     raw.InquiryType,
+		}},
+		RiskModel: []experianRiskModel{{ModelIndicator: raw.ScoreModel, Score: fmt.Sprintf("%04d", raw.Score)}},
+		SSN:       []experianSSN{{Number: "XXXXX" + raw.SSNLast4}},
+		ProfileSummary: experianProfileSummary{
+			PaidAccounts:      raw.PaymentProfile.OnTimePayments,
+			DelinquentCount:   raw.PaymentProfile.LatePayments,
+			DerogCount:        raw.PaymentProfile.Defaults,
+			BankruptcyCount:   raw.PaymentProfile.Bankruptcies,
+			OldestTradeMonths: raw.PaymentProfile.CreditAge,
+			PaymentScore:      raw.PaymentProfile.PaymentScore,
+		},
+	}
+	for _, tradeline := range raw.Tradelines {
+		opened, err := time.Parse("2006-01", tradeline.Opened)
+		if err != nil {
+			return nil, fmt.Errorf("invalid open date %q: %w", tradeline.Opened, err)
+		}
+		line := experianTradeline{
+			SubscriberName: tradeline.Subscriber,
+			AccountNumber:  tradeline.AccountNumber,
+			AccountType:    tradeline.AccountType,
+			OpenDate:       opened.Format(experianMonth),
+			StatusDate:     tradeline.Reported.UTC().Format(experianDate),
+			BalanceAmount:  wholeDollars(tradeline.Balance),
+			Status:         tradeline.Status,
+			MonthsHistory:  strconv.Itoa(tradeline.MonthsReviewed),
+		}
+		if tradeline.CreditLimit > 0 {
+			line.Amount1, line.Amount1Qual = wholeDollars(tradeline.CreditLimit), "L"
+		}
+		profile.Tradeline = append(profile.Tradeline, line)
+	}
+	for _, inquiry := range raw.Inquiries {
+		date, err := reformatDate(inquiry.Date, "2006-01-02", experianDate)
+		if err != nil {
+			return nil, err
+		}
+		profile.Inquiry = append(profile.Inquiry, experianInquiry{
+			SubscriberName: inquiry.Subscriber,
+			Date:           date,
+			Type:           inquiry.Type,
+			Purpose:        inquiry.Purpose,
+		})
+	}
+	for _, record := range raw.PublicRecords {
+		filed, err := reformatDate(record.Filed, "2006-01-02", experianDate)
+		if err != nil {
+			return nil, err
+		}
+		profile.PublicRecord = append(profile.PublicRecord, experianPublicRecord{
+			ReferenceNumber: record.ReferenceNumber,
+			Evaluation:      record.Type,
+			CourtName:       record.Court,
+			FilingDate:      filed,
+			Amount:          wholeDollars(record.Amount),
+			Status:          record.Status,
+		})
+	}
+	for _, collection := range raw.Collections {
+		reported, err := reformatDate(collection.Reported, "2006-01-02", experianDate)
+		if err != nil {
+			return nil, err
+		}
+		profile.Collection = append(profile.Collection, experianCollection{
+			AccountNumber:        collection.AccountNumber,
+			SubscriberName:       collection.Agency,
+			OriginalCreditorName: collection.OriginalCreditor,
+			BalanceAmount:        wholeDollars(collection.Balance),
+			OriginalAmount:       wholeDollars(collection.OriginalAmount),
+			StatusDate:           reported,
+			Status:               collection.Status,
+		})
+	}
+	for _, alert := range raw.Alerts {
+		placed, err := reformatDate(alert.Placed, "2006-01-02", experianDate)
+		if err != nil {
+			return nil, err
+		}
+		expires, err := reformatDate(alert.Expires, "2006-01-02", experianDate)
+		if err != nil {
+			return nil, err
+		}
+		profile.FraudShield = append(profile.FraudShield, experianFraudAlert{
+			Indicator:   alert.Type,
+			Phone:       alert.ContactPhone,
+			DatePlaced:  placed,
+			DateExpires: expires,
+		})
+	}
+	return json.Marshal(experianReport{CreditProfile: []experianCreditProfile{profile}})
+}
+
+func (experianFormat) decode(payload []byte) (*rawBureauReport, error) {
+	var report experianReport
+	if err := json.Unmarshal(payload, &report); err != nil {
+		return nil, err
+	}
+	if len(report.CreditProfile) == 0 {
+		return nil, fmt.Errorf("no credit profile")
+	}
+	profile := report.CreditProfile[0]
+	if len(profile.HeaderRecord) == 0 {
+		return nil, fmt.Errorf("no header record")
+	}
+	header := profile.HeaderRecord[0]
+	reported, err := time.Parse(experianDate+experianTime, header.ReportDate+header.ReportTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid report date %q %q: %w", header.ReportDate, header.ReportTime, err)
+	}
+
+	raw := &rawBureauReport{
+		Bureau:          string(domain.BureauExperian),
+		ReferenceNumber: header.ReferenceNumber,
+		Product:         header.Product,
+		InquiryType:     header.InquiryType,
+		PaymentProfile: domain.PaymentHistory{
+			OnTimePayments: profile.ProfileSummary.PaidAccounts,
+			LatePayments:   profile.ProfileSummary.DelinquentCount,
+			Defaults:       profile.ProfileSummary.DerogCount,
+			Bankruptcies:   profile.ProfileSummary.BankruptcyCount,
+			CreditAge:      profile.ProfileSummary.OldestTradeMonths,
+			PaymentScore:   profile.ProfileSummary.PaymentScore,
+		},
+		ReportedAt: reported,
+	}
+	if len(profile.RiskModel) > 0 {
+		raw.ScoreModel = profile.RiskModel[0].ModelIndicator
+		if raw.Score, err = strconv.Atoi(profile.RiskModel[0].Score); err != nil {
+			return nil, fmt.Errorf("invalid score %q: %w", profile.RiskModel[0].Score, err)
+		}
+	}
+	if len(profile.SSN) > 0 {
+		raw.SSNLast4 = lastFour(profile.SSN[0].Number)
+	}
+
+	for _, line := range profile.Tradeline {
+		opened, err := reformatDate(line.OpenDate, experianMonth, "2006-01")
+		if err != nil {
+			return nil, err
+		}
+		reportedOn, err := time.Parse(experianDate, line.StatusDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status date %q: %w", line.StatusDate, err)
+		}
+		months, err := strconv.Atoi(line.MonthsHistory)
+		if err != nil {
+			return nil, fmt.Errorf("invalid months history %q: %w", line.MonthsHistory, err)
+		}
+		tradeline := rawBureauTradeline{
+			Subscriber:     line.SubscriberName,
+			AccountNumber:  line.AccountNumber,
+			AccountType:    line.AccountType,
+			Opened:         opened,
+			Reported:       reportedOn,
+			Balance:        float64(line.BalanceAmount),
+			Status:         line.Status,
+			MonthsReviewed: months,
+		}
+		if line.Amount1Qual == "L" {
+			tradeline.CreditLimit = float64(line.Amount1)
+		}
+		raw.Tradelines = append(raw.Tradelines, tradeline)
+	}
+	for _, inquiry := range profile.Inquiry {
+		date, err := reformatDate(inquiry.Date, experianDate, "2006-01-02")
+		if err != nil {
+			return nil, err
+		}
+		raw.Inquiries = append(raw.Inquiries, rawBureauInquiry{
+			Subscriber: inquiry.SubscriberName,
+			Date:       date,
+			Type:       inquiry.Type,
+			Purpose:    inquiry.Purpose,
+		})
+	}
+	for _, record := range profile.PublicRecord {
+		filed, err := reformatDate(record.FilingDate, experianDate, "2006-01-02")
+		if err != nil {
+			return nil, err
+		}
+		raw.PublicRecords = append(raw.PublicRecords, rawBureauPublicRecord{
+			ReferenceNumber: record.ReferenceNumber,
+			Type:            record.Evaluation,
+			Court:           record.CourtName,
+			Filed:           filed,
+			Amount:          float64(record.Amount),
+			Status:          record.Status,
+		})
+	}
+	for _, collection := range profile.Collection {
+		reportedOn, err := reformatDate(collection.StatusDate, experianDate, "2006-01-02")
+		if err != nil {
+			return nil, err
+		}
+		raw.Collections = append(raw.Collections, rawBureauCollection{
+			AccountNumber:    collection.AccountNumber,
+			Agency:           collection.SubscriberName,
+			OriginalCreditor: collection.OriginalCreditorName,
+			Balance:          float64(collection.BalanceAmount),
+			OriginalAmount:   float64(collection.OriginalAmount),
+			Reported:         reportedOn,
+			Status:           collection.Status,
+		})
+	}
+	for _, alert := range profile.FraudShield {
+		placed, err := reformatDate(alert.DatePlaced, experianDate, "2006-01-02")
+		if err != nil {
+			return nil, err
+		}
+		expires, err := reformatDate(alert.DateExpires, experianDate, "2006-01-02")
+		if err != nil {
+			return nil, err
+		}
+		raw.Alerts = append(raw.Alerts, rawBureauAlert{
+			Type:         alert.Indicator,
+			ContactPhone: alert.Phone,
+			Placed:       placed,
+			Expires:      expires,
+		})
+	}
+	return raw, nil
+}
+
+// Equifax: a US consumer credit report nested under consumers, with ISO dates, the score in the
+// models section and public records split into bankruptcies and other items.
+
+type equifaxReport struct {
+	Consumers equifaxConsumers `json:"consumers"`
+}
+
+type equifaxConsumers struct {
+	EquifaxUSConsumerCreditReport []equifaxCreditReport `json:"equifaxUSConsumerCreditReport"`
+}
+
+type equifaxCreditReport struct {
+	CustomerReferenceNumber string                `json:"customerReferenceNumber"`
+	ProductCode             string                `json:"productCode"`
+	InquiryIntent           string                `json:"inquiryIntent"`
+	ReportDate              time.Time             `json:"reportDate"`
+	SubjectSSN              string                `json:"subjectSocialNum"` // masked to the last four digits
+	Models                  []equifaxModel        `json:"models"`
+	Trades                  []equifaxTrade        `json:"trades"`
+	Inquiries               []equifaxInquiry      `json:"inquiries"`
+	Bankruptcies            []equifaxPublicRecord `json:"bankruptcies,omitempty"`
+	OtherItems              []equifaxPublicRecord `json:"otherItems,omitempty"` // judgments and liens
+	Collections             []equifaxCollection   `json:"collections,omitempty"`
+	AlertContacts           []equifaxAlert        `json:"alertContacts,omitempty"`
+	Attributes              domain.PaymentHistory `json:"attributes"`
+}
+
+type equifaxModel struct {
+	ModelNumber string `json:"modelNumber"`
+	Score       int    `json:"score"`
+}
+
+type equifaxTrade struct {
+	CustomerName      string  `json:"customerName"`
+	AccountNumber     string  `json:"accountNumber"`
+	PortfolioTypeCode string  `json:"portfolioTypeCode"`
+	DateOpened        string  `json:"dateOpened"`   // YYYY-MM-DD, first of the month
+	DateReported      string  `json:"dateReported"` // YYYY-MM-DD
+	Balance           float64 `json:"balance"`
+	CreditLimit       float64 `json:"creditLimit,omitempty"`
+	Rate              struct {
+		Code string `json:"code"`
+	} `json:"rate"`
+	MonthsReviewed int `json:"monthsReviewed"`
+}
+
+type equifaxInquiry struct {
+	CustomerName string `json:"customerName"`
+	InquiryDate  string `json:"inquiryDate"` // YYYY-MM-DD
+	Type         string `json:"type"`
+	Purpose      string `json:"purpose,omitempty"`
+}
+
+type equifaxPublicRecord struct {
+	ReferenceNumber string  `json:"referenceNumber"`
+	TypeCode        string  `json:"typeCode"`
+	CourtName       string  `json:"courtName,omitempty"`
+	DateFiled       string  `json:"dateFiled"` // YYYY-MM-DD
+	Amount          float64 `json:"amount,omitempty"`
+	Status          string  `json:"status"`
+}
+
+type equifaxCollection struct {
+	AccountNumber  string  `json:"accountNumber"`
+	CustomerName   string  `json:"customerName"` // the collection agency
+	CreditorName   string  `json:"creditorClassification"`
+	Balance        float64 `json:"balance"`
+	OriginalAmount float64 `json:"originalAmount"`
+	DateReported   string  `json:"dateReported"` // YYYY-MM-DD
+	StatusCode     string  `json:"statusCode"`
+}
+
+type equifaxAlert struct {
+	AlertType     string `json:"alertType"`
+	Phone         string `json:"telephoneNumber,omitempty"`
+	DateReported  string `json:"dateReported"`
+	EffectiveTill string `json:"effectiveTill,omitempty"`
+}
+
+type equifaxFormat struct{}
+
+func (equifaxFormat) encode(raw *rawBureauReport) ([]byte, error) {
+	report := equifaxCreditReport{
+		CustomerReferenceNumber: raw.ReferenceNumber,
+		ProductCode:             raw.Product,
+		InquiryIntent:           raw.InquiryType,
+		ReportDate:              raw.ReportedAt.UTC(),
+		SubjectSSN:              "*****" + raw.SSNLast4,
+		Models:                  []equifaxModel{{ModelNumber: raw.ScoreModel, Score: raw.Score}},
+		Attributes:              raw.PaymentProfile,
+	}
+	for _, tradeline := range raw.Tradelines {
+		trade := equifaxTrade{
+			CustomerName:      tradeline.Subscriber,
+			AccountNumber:     tradeline.AccountNumber,
+			PortfolioTypeCode: tradeline.AccountType,
+			DateOpened:        tradeline.Opened + "-01",
+			DateReported:      tradeline.Reported.UTC().Format("2006-01-02"),
+			Balance:           tradeline.Balance,
+			CreditLimit:       tradeline.CreditLimit,
+			MonthsReviewed:    tradeline.MonthsReviewed,
+		}
+		trade.Rate.Code = tradeline.Status
+		report.Trades = append(report.Trades, trade)
+	}
+	for _, inquiry := range raw.Inquiries {
+		report.Inquiries = append(report.Inquiries, equifaxInquiry{
+			CustomerName: inquiry.Subscriber,
+			InquiryDate:  inquiry.Date,
+			Type:         inquiry.Type,
+			Purpose:      inquiry.Purpose,
+		})
+	}
+	profile := bureauProfiles[domain.BureauEquifax]
+	for _, record := range raw.PublicRecords {
+		item := equifaxPublicRecord{
+			ReferenceNumber: record.ReferenceNumber,
+			TypeCode:        record.Type,
+			CourtName:       record.Court,
+			DateFiled:       record.Filed,
+			Amount:          record.Amount,
+			Status:          record.Status,
+		}
+		if record.Type == profile.recordTypes[domain.PublicRecordBankruptcy] {
+			report.Bankruptcies = append(report.Bankruptcies, item)
+		} else {
+			report.OtherItems = append(report.OtherItems, item)
+		}
+	}
+	for _, collection := range raw.Collections {
+		report.Collections = append(report.Collections, equifaxCollection{
+			AccountNumber:  collection.AccountNumber,
+			CustomerName:   collection.Agency,
+			CreditorName:   collection.OriginalCreditor,
+			Balance:        collection.Balance,
+			OriginalAmount: collection.OriginalAmount,
+			DateReported:   collection.Reported,
+			StatusCode:     collection.Status,
+		})
+	}
+	for _, alert := range raw.Alerts {
+		report.AlertContacts = append(report.AlertContacts, equifaxAlert{
+			AlertType:     alert.Type,
+			Phone:         alert.ContactPhone,
+			DateReported:  alert.Placed,
+			EffectiveTill: alert.Expires,
+		})
+	}
+	return json.Marshal(equifaxReport{Consumers: equifaxConsumers{
+		EquifaxUSConsumerCreditReport: []equifaxCreditReport{report},
+	}})
+}
+
+func (equifaxFormat) decode(payload []byte) (*rawBureauReport, error) {
+	var envelope equifaxReport
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, err
+	}
+	if len(envelope.Consumers.EquifaxUSConsumerCreditReport) == 0 {
+		return nil, fmt.Errorf("no consumer credit report")
+	}
+	report := envelope.Consumers.EquifaxUSConsumerCreditReport[0]
+
+	raw := &rawBureauReport{
+		Bureau:          string(domain.BureauEquifax),
+		ReferenceNumber: report.CustomerReferenceNumber,
+		Product:         report.ProductCode,
+		InquiryType:     report.InquiryIntent,
+		SSNLast4:        lastFour(report.SubjectSSN),
+		PaymentProfile:  report.Attributes,
+		ReportedAt:      report.ReportDate,
+	}
+	if len(report.Models) > 0 {
+		raw.ScoreModel, raw.Score = report.Models[0].ModelNumber, report.Models[0].Score
+	}
+
+	for _, trade := range report.Trades {
+		opened, err := reformatDate(trade.DateOpened, "2006-01-02", "2006-01")
+		if err != nil {
+			return nil, err
+		}
+		reportedOn, err := time.Parse("2006-01-02", trade.DateReported)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date reported %q: %w", trade.DateReported, err)
+		}
+		raw.Tradelines = append(raw.Tradelines, rawBureauTradeline{
+			Subscriber:     trade.CustomerName,
+			AccountNumber:  trade.AccountNumber,
+			AccountType:    trade.PortfolioTypeCode,
+			Opened:         opened,
+			Reported:       reportedOn,
+			Balance:        trade.Balance,
+			CreditLimit:    trade.CreditLimit,
+			Status:         trade.Rate.Code,
+			MonthsReviewed: trade.MonthsReviewed,
+		})
+	}
+	for _, inquiry := range report.Inquiries {
+		raw.Inquiries = append(raw.Inquiries, rawBureauInquiry{
+			Subscriber: inquiry.CustomerName,
+			Date:       inquiry.InquiryDate,
+			Type:       inquiry.Type,
+			Purpose:    inquiry.Purpose,
+		})
+	}
+	for _, record := range append(append([]equifaxPublicRecord{}, report.Bankruptcies...), report.OtherItems...) {
+		raw.PublicRecords = append(raw.PublicRecords, rawBureauPublicRecord{
+			ReferenceNumber: record.ReferenceNumber,
+			Type:            record.TypeCode,
+			Court:           record.CourtName,
+			Filed:           record.DateFiled,
+			Amount:          record.Amount,
+			Status:          record.Status,
+		})
+	}
+	for _, collection := range report.Collections {
+		raw.Collections = append(raw.Collections, rawBureauCollection{
+			AccountNumber:    collection.AccountNumber,
+			Agency:           collection.CustomerName,
+			OriginalCreditor: collection.CreditorName,
+			Balance:          collection.Balance,
+			OriginalAmount:   collection.OriginalAmount,
+			Reported:         collection.DateReported,
+			Status:           collection.StatusCode,
+		})
+	}
+	for _, alert := range report.AlertContacts {
+		raw.Alerts = append(raw.Alerts, rawBureauAlert{
+			Type:         alert.AlertType,
+			ContactPhone: alert.Phone,
+			Placed:       alert.DateReported,
+			Expires:      alert.EffectiveTill,
+		})
+	}
+	return raw, nil
+}
+
+// TransUnion: a credit report inside TransUnion's transaction envelope. Numbers are zero-padded
+// strings in whole dollars and the score comes from a scoring add-on product.
+
+type transUnionReport struct {
+	CreditBureau transUnionCreditBureau `json:"creditBureau"`
+}
+
+type transUnionCreditBureau struct {
+	TransactionControl transUnionTransactionControl `json:"transactionControl"`
+	Product            transUnionProduct            `json:"product"`
+}
+
+type transUnionTransactionControl struct {
+	TrackingNumber string `json:"trackingNumber"`
+	Timestamp      string `json:"timestamp"` // RFC 3339
+}
+
+type transUnionProduct struct {
+	Code           string            `json:"code"`
+	PermissibleUse string            `json:"permissiblePurpose"`
+	Subject        transUnionSubject `json:"subject"`
+}
+
+type transUnionSubject struct {
+	SubjectRecord transUnionSubjectRecord `json:"subjectRecord"`
+}
+
+type transUnionSubjectRecord struct {
+	Indicative   transUnionIndicative  `json:"indicative"`
+	Custom       transUnionCustom      `json:"custom"`
+	AddOnProduct []transUnionAddOn     `json:"addOnProduct"`
+	FileSummary  domain.PaymentHistory `json:"fileSummary"`
+}
+
+type transUnionIndicative struct {
+	SocialSecurity struct {
+		Number string `json:"number"` // masked to the last four digits
+	} `json:"socialSecurity"`
+}
+
+type transUnionCustom struct {
+	Credit transUnionCredit `json:"credit"`
+}
+
+type transUnionCredit struct {
+	Trade        []transUnionTrade        `json:"trade"`
+	Inquiry      []transUnionInquiry      `json:"inquiry"`
+	PublicRecord []transUnionPublicRecord `json:"publicRecord,omitempty"`
+	Collection   []transUnionCollection   `json:"collection,omitempty"`
+	FraudAlert   []transUnionFraudAlert   `json:"consumerStatement,omitempty"`
+}
+
+type transUnionAddOn struct {
+	Code       string `json:"code"`
+	ScoreModel struct {
+		Score struct {
+			Results string `json:"results"` // signed and zero-padded, e.g. +0687
+		} `json:"score"`
+	} `json:"scoreModel"`
+}
+
+type transUnionSubscriber struct {
+	Name struct {
+		Unparsed string `json:"unparsed"`
+	} `json:"name"`
+}
+
+type transUnionTrade struct {
+	Subscriber     transUnionSubscriber `json:"subscriber"`
+	AccountNumber  string               `json:"accountNumber"`
+	PortfolioType  string               `json:"portfolioType"`
+	DateOpened     string               `json:"dateOpened"`    // YYYY-MM-DD, first of the month
+	DateEffective  string               `json:"dateEffective"` // YYYY-MM-DD
+	CurrentBalance string               `json:"currentBalance"`
+	CreditLimit    string               `json:"creditLimit,omitempty"`
+	AccountRating  string               `json:"accountRating"`
+	PaymentHistory struct {
+		PaymentPattern struct {
+			MonthsReviewedCount string `json:"monthsReviewedCount"`
+		} `json:"paymentPattern"`
+	} `json:"paymentHistory"`
+}
+
+type transUnionInquiry struct {
+	Subscriber  transUnionSubscriber `json:"subscriber"`
+	Date        string               `json:"date"` // YYYY-MM-DD
+	AccountType string               `json:"accountType"`
+	Purpose     string               `json:"purpose,omitempty"`
+}
+
+type transUnionPublicRecord struct {
+	DocketNumber string `json:"docketNumber"`
+	Type         string `json:"type"`
+	Source       string `json:"source,omitempty"` // the court
+	DateFiled    string `json:"dateFiled"`        // YYYY-MM-DD
+	Liabilities  string `json:"liabilities,omitempty"`
+	Disposition  string `json:"disposition"`
+}
+
+type transUnionCollection struct {
+	AccountNumber    string               `json:"accountNumber"`
+	Subscriber       transUnionSubscriber `json:"subscriber"` // the collection agency
+	OriginalCreditor string               `json:"original"`
+	CurrentBalance   string               `json:"currentBalance"`
+	OriginalBalance  string               `json:"originalBalance"`
+	DateEffective    string               `json:"dateEffective"` // YYYY-MM-DD
+	AccountRating    string               `json:"accountRating"`
+}
+
+type transUnionFraudAlert struct {
+	Type        string `json:"type"`
+	Phone       string `json:"phone,omitempty"`
+	DateFiled   string `json:"dateFiled"`
+	DateExpires string `json:"dateExpires,omitempty"`
+}
+
+type transUnionFormat struct{}
+
+func (transUnionFormat) encode(raw *rawBureauReport) ([]byte, error) {
+	record := transUnionSubjectRecord{FileSummary: raw.PaymentProfile}
+	record.Indicative.SocialSecurity.Number = "XXXXX" + raw.SSNLast4
+	addOn := transUnionAddOn{Code: raw.ScoreModel}
+	addOn.ScoreModel.Score.Results = fmt.Sprintf("%+05d", raw.Score)
+	record.AddOnProduct = []transUnionAddOn{addOn}
+
+	credit := &record.Custom.Credit
+	for _, tradeline := range raw.Tradelines {
+		trade := transUnionTrade{
+			AccountNumber:  tradeline.AccountNumber,
+			PortfolioType:  tradeline.AccountType,
+			DateOpened:     tradeline.Opened + "-01",
+			DateEffective:  tradeline.Reported.UTC().Format("2006-01-02"),
+			CurrentBalance: transUnionAmount(tradeline.Balance),
+			AccountRating:  tradeline.Status,
+		}
+		trade.Subscriber.Name.Unparsed = tradeline.Subscriber
+		if tradeline.CreditLimit > 0 {
+			trade.CreditLimit = transUnionAmount(tradeline.CreditLimit)
+		}
+		trade.PaymentHistory.PaymentPattern.MonthsReviewedCount = fmt.Sprintf("%02d", tradeline.MonthsReviewed)
+		credit.Trade = append(credit.Trade, trade)
+	}
+	for _, inquiry := range raw.Inquiries {
+		entry := transUnionInquiry{Date: inquiry.Date, AccountType: inquiry.Type, Purpose: inquiry.Purpose}
+		entry.Subscriber.Name.Unparsed = inquiry.Subscriber
+		credit.Inquiry = append(credit.Inquiry, entry)
+	}
+	for _, publicRecord := range raw.PublicRecords {
+		entry := transUnionPublicRecord{
+			DocketNumber: publicRecord.ReferenceNumber,
+			Type:         publicRecord.Type,
+			Source:       publicRecord.Court,
+			DateFiled:    publicRecord.Filed,
+			Disposition:  publicRecord.Status,
+		}
+		if publicRecord.Amount > 0 {
+			entry.Liabilities = transUnionAmount(publicRecord.Amount)
+		}
+		credit.PublicRecord = append(credit.PublicRecord, entry)
+	}
+	for _, collection := range raw.Collections {
+		entry := transUnionCollection{
+			AccountNumber:    collection.AccountNumber,
+			OriginalCreditor: collection.OriginalCreditor,
+			CurrentBalance:   transUnionAmount(collection.Balance),
+			OriginalBalance:  transUnionAmount(collection.OriginalAmount),
+			DateEffective:    collection.Reported,
+			AccountRating:    collection.Status,
+		}
+		entry.Subscriber.Name.Unparsed = collection.Agency
+		credit.Collection = append(credit.Collection, entry)
+	}
+	for _, alert := range raw.Alerts {
+		credit.FraudAlert = append(credit.FraudAlert, transUnionFraudAlert{
+			Type:        alert.Type,
+			Phone:       alert.ContactPhone,
+			DateFiled:   alert.Placed,
+			DateExpires: alert.Expires,
+		})
+	}
+
+	return json.Marshal(transUnionReport{CreditBureau: transUnionCreditBureau{
+		TransactionControl: transUnionTransactionControl{
+			TrackingNumber: raw.ReferenceNumber,
+			Timestamp:      raw.ReportedAt.UTC().Format(time.RFC3339Nano),
+		},
+		Product: transUnionProduct{
+			Code:           raw.Product,
+			PermissibleUse: raw.InquiryType,
+			Subject:        transUnionSubject{SubjectRecord: record},
+		},
+	}})
+}
+
+func (transUnionFormat) decode(payload []byte) (*rawBureauReport, error) {
+	var report transUnionReport
+	if err := json.Unmarshal(payload, &report); err != nil {
+		return nil, err
+	}
+	control := report.CreditBureau.TransactionControl
+	product := report.CreditBureau.Product
+	record := product.Subject.SubjectRecord
+
+	reported, err := time.Parse(time.RFC3339Nano, control.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp %q: %w", control.Timestamp, err)
+	}
+	raw := &rawBureauReport{
+		Bureau:          string(domain.BureauTransUnion),
+		ReferenceNumber: control.TrackingNumber,
+		Product:         product.Code,
+		InquiryType:     product.PermissibleUse,
+		SSNLast4:        lastFour(record.Indicative.SocialSecurity.Number),
+		PaymentProfile:  record.FileSummary,
+		ReportedAt:      reported,
+	}
+	if len(record.AddOnProduct) > 0 {
+		addOn := record.AddOnProduct[0]
+		raw.ScoreModel = addOn.Code
+		if raw.Score, err = strconv.Atoi(addOn.ScoreModel.Score.Results); err != nil {
+			return nil, fmt.Errorf("invalid score %q: %w", addOn.ScoreModel.Score.Results, err)
+		}
+	}
+
+	credit := record.Custom.Credit
+	for _, trade := range credit.Trade {
+		opened, err := reformatDate(trade.DateOpened, "2006-01-02", "2006-01")
+		if err != nil {
+			return nil, err
+		}
+		reportedOn, err := time.Parse("2006-01-02", trade.DateEffective)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date effective %q: %w", trade.DateEffective, err)
+		}
+		balance, err := parseTransUnionAmount(trade.CurrentBalance)
+		if err != nil {
+			return nil, err
+		}
+		limit, err := parseTransUnionAmount(trade.CreditLimit)
+		if err != nil {
+			return nil, err
+		}
+		months, err := strconv.Atoi(trade.PaymentHistory.PaymentPattern.MonthsReviewedCount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid months reviewed %q: %w", trade.PaymentHistory.PaymentPattern.MonthsReviewedCount, err)
+		}
+		raw.Tradelines = append(raw.Tradelines, rawBureauTradeline{
+			Subscriber:     trade.Subscriber.Name.Unparsed,
+			AccountNumber:  trade.AccountNumber,
+			AccountType:    trade.PortfolioType,
+			Opened:         opened,
+			Reported:       reportedOn,
+			Balance:        balance,
+			CreditLimit:    limit,
+			Status:         trade.AccountRating,
+			MonthsReviewed: months,
+		})
+	}
+	for _, inquiry := range credit.Inquiry {
+		raw.Inquiries = append(raw.Inquiries, rawBureauInquiry{
+			Subscriber: inquiry.Subscriber.Name.Unparsed,
+			Date:       inquiry.Date,
+			Type:       inquiry.AccountType,
+			Purpose:    inquiry.Purpose,
+		})
+	}
+	for _, publicRecord := range credit.PublicRecord {
+		amount, err := parseTransUnionAmount(publicRecord.Liabilities)
+		if err != nil {
+			return nil, err
+		}
+		raw.PublicRecords = append(raw.PublicRecords, rawBureauPublicRecord{
+			ReferenceNumber: publicRecord.DocketNumber,
+			Type:            publicRecord.Type,
+			Court:           publicRecord.Source,
+			Filed:           publicRecord.DateFiled,
+			Amount:          amount,
+			Status:          publicRecord.Disposition,
+		})
+	}
+	for _, collection := range credit.Collection {
+		balance, err := parseTransUnionAmount(collection.CurrentBalance)
+		if err != nil {
+			return nil, err
+		}
+		original, err := parseTransUnionAmount(collection.OriginalBalance)
+		if err != nil {
+			return nil, err
+		}
+		raw.Collections = append(raw.Collections, rawBureauCollection{
+			AccountNumber:    collection.AccountNumber,
+			Agency:           collection.Subscriber.Name.Unparsed,
+			OriginalCreditor: collection.OriginalCreditor,
+			Balance:          balance,
+			OriginalAmount:   original,
+			Reported:         collection.DateEffective,
+			Status:           collection.AccountRating,
+		})
+	}
+	for _, alert := range credit.FraudAlert {
+		raw.Alerts = append(raw.Alerts, rawBureauAlert{
+			Type:         alert.Type,
+			ContactPhone: alert.Phone,
+			Placed:       alert.DateFiled,
+			Expires:      alert.DateExpires,
+		})
+	}
+	return raw, nil
+}
+
+// transUnionAmount writes an amount as TransUnion does, in whole dollars padded to nine digits
+func transUnionAmount(amount float64) string {
+	return fmt.Sprintf("%09d", wholeDollars(amount))
+}
+
+// parseTransUnionAmount reads a zero-padded TransUnion amount; an absent amount is zero
+func parseTransUnionAmount(amount string) (float64, error) {
+	if amount == "" {
+		return 0, nil
+	}
+	dollars, err := strconv.ParseInt(amount, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", amount, err)
+	}
+	return float64(dollars), nil
+}
+
+// wholeDollars rounds an amount to whole dollars, as Experian and TransUnion report them
+func wholeDollars(amount float64) int64 {
+	return int64(math.Round(amount))
+}
+
+// reformatDate rewrites a date from one layout to another; an empty date stays empty
+func reformatDate(date, from, to string) (string, error) {
+	if date == "" {
+		return "", nil
+	}
+	parsed, err := time.Parse(from, date)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q: %w", date, err)
+	}
+	return parsed.Format(to), nil
+}
+
+// lastFour returns the last four characters of a masked number
+func lastFour(number string) string {
+	number = strings.TrimSpace(number)
+	if len(number) <= 4 {
+		return number
+	}
+	return number[len(number)-4:]
+}
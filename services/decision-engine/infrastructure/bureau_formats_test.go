@@ -0,0 +1,111 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files from the current output")
+
+// bureauPayloads are the recorded report payloads of each bureau, in its own format
+var bureauPayloads = map[domain.Bureau]string{
+	domain.BureauExperian:   "experian",
+	domain.BureauEquifax:    "equifax",
+	domain.BureauTransUnion: "transunion",
+}
+
+func TestParseBureauReport_Golden(t *testing.T) {
+	for bureau, name := range bureauPayloads {
+		t.Run(name, func(t *testing.T) {
+			payload, err := os.ReadFile(filepath.Join("testdata", "bureau", name+".json"))
+			require.NoError(t, err)
+
+			report, err := parseBureauReport(bureau, payload)
+			require.NoError(t, err)
+			got, err := json.MarshalIndent(report, "", "  ")
+			require.NoError(t, err)
+			got = append(got, '\n')
+
+			golden := filepath.Join("testdata", "bureau", name+".golden.json")
+			if *updateGolden {
+				require.NoError(t, os.WriteFile(golden, got, 0o644))
+			}
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(want), string(got))
+		})
+	}
+}
+
+func TestParseBureauReport_FormatsAgree(t *testing.T) {
+	// The golden payloads record the same file at each bureau, so apart from each bureau's
+	// scoring and identity the normalized reports match
+	reports := make(map[domain.Bureau]*domain.CreditReport)
+	for bureau, name := range bureauPayloads {
+		payload, err := os.ReadFile(filepath.Join("testdata", "bureau", name+".json"))
+		require.NoError(t, err)
+		report, err := parseBureauReport(bureau, payload)
+		require.NoError(t, err)
+		report.CreditScore, report.ScoreModel, report.Bureau = 0, "", ""
+		for i := range report.Accounts {
+			report.Accounts[i].AccountID = ""
+		}
+		reports[bureau] = report
+	}
+
+	assert.Equal(t, reports[domain.BureauExperian], reports[domain.BureauEquifax])
+	assert.Equal(t, reports[domain.BureauExperian], reports[domain.BureauTransUnion])
+}
+
+func TestBureauFormats_RoundTrip(t *testing.T) {
+	for bureau, name := range bureauPayloads {
+		t.Run(name, func(t *testing.T) {
+			payload, err := os.ReadFile(filepath.Join("testdata", "bureau", name+".json"))
+			require.NoError(t, err)
+
+			raw, err := bureauFormats[bureau].decode(payload)
+			require.NoError(t, err)
+			encoded, err := bureauFormats[bureau].encode(raw)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(payload), string(encoded))
+		})
+	}
+}
+
+func TestParseBureauReport_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		bureau  domain.Bureau
+		payload string
+	}{
+		{name: "unknown bureau", bureau: "INNOVIS", payload: `{}`},
+		{name: "malformed payload", bureau: domain.BureauEquifax, payload: `{"consumers":`},
+		{name: "experian without a credit profile", bureau: domain.BureauExperian, payload: `{"creditProfile":[]}`},
+		{name: "equifax without a report", bureau: domain.BureauEquifax, payload: `{"consumers":{}}`},
+		{
+			name:    "transunion amount not a number",
+			bureau:  domain.BureauTransUnion,
+			payload: `{"creditBureau":{"transactionControl":{"timestamp":"2025-06-25T14:30:05Z"},"product":{"subject":{"subjectRecord":{"custom":{"credit":{"trade":[{"dateOpened":"2019-06-01","dateEffective":"2025-06-22","currentBalance":"1,940"}]}}}}}}}`,
+		},
+		{
+			name:    "unknown account type code",
+			bureau:  domain.BureauEquifax,
+			payload: `{"consumers":{"equifaxUSConsumerCreditReport":[{"reportDate":"2025-06-25T14:30:05Z","trades":[{"portfolioTypeCode":"CC","dateOpened":"2019-06-01","dateReported":"2025-06-22","rate":{"code":"1"}}]}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseBureauReport(tt.bureau, []byte(tt.payload))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,466 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// bureauProfile describes how one bureau formats its reports: each bureau sells its own
+// products for soft and hard pulls, scores with its own models, masks account numbers its own
+// way and reports account types and payment status in its own codes
+type bureauProfile struct {
+	products     map[domain.PullType]bureauProduct
+	scoreOffset  int
+	reportLag    int    // days behind the creditor's latest update
+	accountMask  string // printf format applied to the last four digits of the account number
+	accountTypes map[string]string
+	statusCodes  map[string]string
+	alertTypes   map[string]string
+	recordTypes  map[string]string // public record types
+}
+
+// bureauProduct is a bureau report product; soft pulls go to the bureau's pre-qualification
+// product, which leaves a soft inquiry only
+type bureauProduct struct {
+	name       string
+	scoreModel string
+}
+
+var bureauProfiles = map[domain.Bureau]bureauProfile{
+	domain.BureauExperian: {
+		products: map[domain.PullType]bureauProduct{
+			domain.PullTypeHard: {name: "CREDIT_PROFILE", scoreModel: "FICO_8"},
+			domain.PullTypeSoft: {name: "PREQUALIFICATION_CREDIT_REPORT", scoreModel: "VANTAGESCORE_3"},
+		},
+		reportLag:   3,
+		accountMask: "XXXXXXXX%s",
+		accountTypes: map[string]string{
+			"CREDIT_CARD": "CC", "AUTO_LOAN": "AU", "MORTGAGE": "MG", "PERSONAL_LOAN": "PL", "STUDENT_LOAN": "SL",
+		},
+		statusCodes: map[string]string{
+			"CURRENT": "CUR", "30_DAYS_LATE": "30", "60_DAYS_LATE": "60", "90_DAYS_LATE": "90",
+			"120_DAYS_LATE": "120", "COLLECTION": "COL", "CHARGE_OFF": "CO",
+		},
+		alertTypes: map[string]string{
+			domain.FraudAlertInitial: "IFA", domain.FraudAlertExtended: "EFA", domain.FraudAlertActiveDuty: "ADA",
+		},
+		recordTypes: map[string]string{
+			domain.PublicRecordBankruptcy: "BK", domain.PublicRecordJudgment: "CJ", domain.PublicRecordTaxLien: "TL",
+		},
+	},
+	domain.BureauEquifax: {
+		products: map[domain.PullType]bureauProduct{
+			domain.PullTypeHard: {name: "CONSUMER_CREDIT_REPORT", scoreModel: "BEACON_5"},
+			domain.PullTypeSoft: {name: "PREQUALIFICATION_ONE", scoreModel: "VANTAGESCORE_3"},
+		},
+		scoreOffset: -12,
+		reportLag:   6,
+		accountMask: "************%s",
+		accountTypes: map[string]string{
+			"CREDIT_CARD": "R-CC", "AUTO_LOAN": "I-AU", "MORTGAGE": "M-RE", "PERSONAL_LOAN": "I-PL", "STUDENT_LOAN": "I-ED",
+		},
+		statusCodes: map[string]string{
+			"CURRENT": "1", "30_DAYS_LATE": "2", "60_DAYS_LATE": "3", "90_DAYS_LATE": "4",
+			"120_DAYS_LATE": "5", "COLLECTION": "9", "CHARGE_OFF": "9B",
+		},
+		alertTypes: map[string]string{
+			domain.FraudAlertInitial: "FA-I", domain.FraudAlertExtended: "FA-E", domain.FraudAlertActiveDuty: "FA-AD",
+		},
+		recordTypes: map[string]string{
+			domain.PublicRecordBankruptcy: "B", domain.PublicRecordJudgment: "J", domain.PublicRecordTaxLien: "L",
+		},
+	},
+	domain.BureauTransUnion: {
+		products: map[domain.PullType]bureauProduct{
+			domain.PullTypeHard: {name: "CREDIT_REPORT", scoreModel: "FICO_CLASSIC_04"},
+			domain.PullTypeSoft: {name: "PRESCREEN_SOFT_INQUIRY", scoreModel: "VANTAGESCORE_3"},
+		},
+		scoreOffset: 9,
+		reportLag:   1,
+		accountMask: "****%s",
+		accountTypes: map[string]string{
+			"CREDIT_CARD": "CREDITCARD", "AUTO_LOAN": "AUTOMOBILE", "MORTGAGE": "REALESTATE", "PERSONAL_LOAN": "UNSECURED", "STUDENT_LOAN": "EDUCATION",
+		},
+		statusCodes: map[string]string{
+			"CURRENT": "01", "30_DAYS_LATE": "02", "60_DAYS_LATE": "03", "90_DAYS_LATE": "04",
+			"120_DAYS_LATE": "05", "COLLECTION": "UC", "CHARGE_OFF": "9P",
+		},
+		alertTypes: map[string]string{
+			domain.FraudAlertInitial: "INITIALFRAUD", domain.FraudAlertExtended: "EXTENDEDFRAUD", domain.FraudAlertActiveDuty: "ACTIVEDUTY",
+		},
+		recordTypes: map[string]string{
+			domain.PublicRecordBankruptcy: "BANKRUPTCY", domain.PublicRecordJudgment: "CIVILJUDGMENT", domain.PublicRecordTaxLien: "TAXLIEN",
+		},
+	},
+}
+
+// rawBureauReport is a bureau response in the bureau's own codes, before normalization. Each
+// bureau lays it out in its own format on the wire, see bureauFormats.
+type rawBureauReport struct {
+	Bureau          string                  `json:"bureau"`
+	ReferenceNumber string                  `json:"reference_number"`
+	Product         string                  `json:"product"`
+	InquiryType     string                  `json:"inquiry_type"`
+	ScoreModel      string                  `json:"score_model"`
+	Score           int                     `json:"score"`
+	SSNLast4        string                  `json:"ssn_last4"`
+	Tradelines      []rawBureauTradeline    `json:"tradelines"`
+	Inquiries       []rawBureauInquiry      `json:"inquiries"`
+	PublicRecords   []rawBureauPublicRecord `json:"public_records,omitempty"`
+	Collections     []rawBureauCollection   `json:"collections,omitempty"`
+	Alerts          []rawBureauAlert        `json:"alerts,omitempty"`
+	PaymentProfile  domain.PaymentHistory   `json:"payment_profile"`
+	ReportedAt      time.Time               `json:"reported_at"`
+}
+
+type rawBureauTradeline struct {
+	Subscriber     string    `json:"subscriber"`
+	AccountNumber  string    `json:"account_number"`
+	AccountType    string    `json:"account_type"`
+	Opened         string    `json:"opened"` // YYYY-MM
+	Reported       time.Time `json:"reported"`
+	Balance        float64   `json:"balance"`
+	CreditLimit    float64   `json:"credit_limit,omitempty"`
+	Status         string    `json:"status"`
+	MonthsReviewed int       `json:"months_reviewed"`
+}
+
+type rawBureauInquiry struct {
+	Subscriber string `json:"subscriber"`
+	Date       string `json:"date"` // YYYY-MM-DD
+	Type       string `json:"type"`
+	Purpose    string `json:"purpose,omitempty"`
+}
+
+type rawBureauPublicRecord struct {
+	ReferenceNumber string  `json:"reference_number"`
+	Type            string  `json:"type"`
+	Court           string  `json:"court,omitempty"`
+	Filed           string  `json:"filed"` // YYYY-MM-DD
+	Amount          float64 `json:"amount,omitempty"`
+	Status          string  `json:"status"`
+}
+
+type rawBureauCollection struct {
+	AccountNumber    string  `json:"account_number"`
+	Agency           string  `json:"agency"`
+	OriginalCreditor string  `json:"original_creditor"`
+	Balance          float64 `json:"balance"`
+	OriginalAmount   float64 `json:"original_amount"`
+	Reported         string  `json:"reported"` // YYYY-MM-DD
+	Status           string  `json:"status"`
+}
+
+type rawBureauAlert struct {
+	Type         string `json:"type"`
+	ContactPhone string `json:"contact_phone,omitempty"`
+	Placed       string `json:"placed"`            // YYYY-MM-DD
+	Expires      string `json:"expires,omitempty"` // YYYY-MM-DD
+}
+
+// BureauTimeout returns the timeout for pulls from a bureau, falling back to APITimeout
+func (c CreditBureauConfig) BureauTimeout(bureau domain.Bureau) time.Duration {
+	timeout := c.BureauTimeouts[bureau]
+	if timeout <= 0 {
+		return c.APITimeout
+	}
+	return timeout
+}
+
+// PullBureauReport implements domain.CreditBureauClient, pulling a report from one bureau within
+// that bureau's timeout. Soft pulls go to the bureau's pre-qualification product; hard pulls
+// need the borrower's consent and a submitted application. The report is returned both as
+// received and normalized.
+func (r *CreditBureauRepository) PullBureauReport(ctx context.Context, bureau domain.Bureau, request *domain.CreditReportRequest) (*domain.BureauReport, error) {
+	logger := r.logger.With(
+		zap.String("user_id", request.UserID),
+		zap.String("bureau", string(bureau)),
+		zap.String("operation", "pull_bureau_report"),
+	)
+
+	profile, ok := bureauProfiles[bureau]
+	if !ok {
+		return nil, fmt.Errorf("unknown credit bureau: %s", bureau)
+	}
+
+	if timeout := r.config.BureauTimeout(bureau); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	pullType := request.EffectivePullType()
+	product := profile.products[pullType]
+	if pullType == domain.PullTypeHard {
+		if err := r.requireHardPullAllowed(ctx, request.UserID, request.ApplicationID); err != nil {
+			logger.Warn("Hard credit pull refused", zap.String("application_id", request.ApplicationID), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	ssn, err := r.resolveSSN(ctx, request.SSNToken)
+	if err != nil {
+		logger.Error("Failed to resolve SSN token", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("Pulling bureau report",
+		zap.String("ssn", maskSSN(ssn)),
+		zap.String("pull_type", string(pullType)),
+		zap.String("product", product.name),
+	)
+
+	// In production, this would call the bureau product's endpoint; for now the response is
+	// simulated, or served from a recorded response in sandbox mode
+	var raw *rawBureauReport
+	if r.sandbox != nil {
+		raw, err = r.sandbox.respond(ctx, bureau, product, pullType, ssn)
+		if err != nil {
+			logger.Warn("Sandbox bureau pull failed", zap.Error(err))
+			return nil, err
+		}
+	} else {
+		raw = r.simulateBureauReport(bureau, profile, product, pullType, request, ssn)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// The response is rendered in the bureau's own format and read back through the
+	// normalization layer, as a live response would be
+	payload, err := bureauFormats[bureau].encode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s report: %w", bureau, err)
+	}
+	report, err := parseBureauReport(bureau, payload)
+	if err != nil {
+		logger.Error("Failed to normalize bureau report", zap.Error(err))
+		return nil, err
+	}
+	report.UserID = request.UserID
+	report.PersonalInfo = domain.PersonalInfo{
+		FirstName:   request.FirstName,
+		LastName:    request.LastName,
+		SSN:         maskSSN(ssn),
+		DateOfBirth: request.DateOfBirth,
+		Address:     request.Address,
+	}
+
+	logger.Info("Bureau report pulled",
+		zap.Int("credit_score", report.CreditScore),
+		zap.Int("account_count", len(report.Accounts)),
+	)
+
+	return &domain.BureauReport{
+		Bureau:    bureau,
+		RawReport: payload,
+		Report:    report,
+		PullType:  pullType,
+		Product:   product.name,
+		PulledAt:  report.ReportDate,
+	}, nil
+}
+
+// simulateBureauReport simulates one bureau's response. The bureaus see the same accounts, but
+// Equifax is missing the oldest tradeline and the bureaus last heard from creditors on
+// different days.
+func (r *CreditBureauRepository) simulateBureauReport(bureau domain.Bureau, profile bureauProfile, product bureauProduct, pullType domain.PullType, request *domain.CreditReportRequest, ssn string) *rawBureauReport {
+	base := r.simulateCreditBureauResponse(&domain.CreditScoreRequest{
+		FirstName: request.FirstName,
+		LastName:  request.LastName,
+	}, ssn)
+	score := base.CreditScore + profile.scoreOffset
+	if score > 850 {
+		score = 850
+	}
+	if score < 300 {
+		score = 300
+	}
+
+	now := time.Now().UTC()
+	raw := &rawBureauReport{
+		Bureau:          string(bureau),
+		ReferenceNumber: fmt.Sprintf("%s-%d", bureau, now.UnixNano()),
+		Product:         product.name,
+		InquiryType:     string(pullType),
+		ScoreModel:      product.scoreModel,
+		Score:           score,
+		SSNLast4:        ssn[len(ssn)-4:],
+		PaymentProfile:  r.generatePaymentHistory(score),
+		ReportedAt:      now,
+	}
+
+	// Accounts follow the base score so every bureau reports the same tradelines
+	accounts := r.generateSampleAccounts(base.CreditScore)
+	if bureau == domain.BureauEquifax && len(accounts) > 2 {
+		accounts = accounts[:len(accounts)-1]
+	}
+	for i, account := range accounts {
+		lastFour := fmt.Sprintf("%04d", (base.CreditScore*(i+7)+int(ssn[len(ssn)-1]))%10000)
+		raw.Tradelines = append(raw.Tradelines, rawBureauTradeline{
+			Subscriber:     account.Creditor,
+			AccountNumber:  fmt.Sprintf(profile.accountMask, lastFour),
+			AccountType:    profile.accountTypes[account.AccountType],
+			Opened:         account.OpenDate.Format("2006-01"),
+			Reported:       now.AddDate(0, 0, -profile.reportLag-i*7),
+			Balance:        account.Balance,
+			CreditLimit:    account.CreditLimit,
+			Status:         profile.statusCodes[account.PaymentStatus],
+			MonthsReviewed: account.MonthsReviewed,
+		})
+	}
+
+	for _, inquiry := range r.generateSampleInquiries(base.CreditScore) {
+		raw.Inquiries = append(raw.Inquiries, rawBureauInquiry{
+			Subscriber: inquiry.Creditor,
+			Date:       inquiry.InquiryDate.Format("2006-01-02"),
+			Type:       inquiry.InquiryType,
+			Purpose:    inquiry.Purpose,
+		})
+	}
+
+	return raw
+}
+
+// normalizeBureauReport converts a bureau's codes and formats into the common report model
+func normalizeBureauReport(bureau domain.Bureau, profile bureauProfile, raw *rawBureauReport) (*domain.CreditReport, error) {
+	accountTypes := invertCodes(profile.accountTypes)
+	statuses := invertCodes(profile.statusCodes)
+
+	report := &domain.CreditReport{
+		CreditScore:    raw.Score,
+		ScoreModel:     raw.ScoreModel,
+		Bureau:         string(bureau),
+		Accounts:       make([]domain.CreditAccount, 0, len(raw.Tradelines)),
+		Inquiries:      make([]domain.CreditInquiry, 0, len(raw.Inquiries)),
+		PaymentHistory: raw.PaymentProfile,
+		ReportDate:     raw.ReportedAt,
+		IsValid:        true,
+	}
+
+	var balance, limit float64
+	for _, tradeline := range raw.Tradelines {
+		opened, err := time.Parse("2006-01", tradeline.Opened)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s open date %q: %w", bureau, tradeline.Opened, err)
+		}
+		status, ok := statuses[tradeline.Status]
+		if !ok {
+			return nil, fmt.Errorf("unknown %s payment status code %q", bureau, tradeline.Status)
+		}
+		accountType, ok := accountTypes[tradeline.AccountType]
+		if !ok {
+			return nil, fmt.Errorf("unknown %s account type code %q", bureau, tradeline.AccountType)
+		}
+
+		account := domain.CreditAccount{
+			AccountID:      tradeline.AccountNumber,
+			AccountType:    accountType,
+			Creditor:       strings.TrimSpace(tradeline.Subscriber),
+			Balance:        tradeline.Balance,
+			CreditLimit:    tradeline.CreditLimit,
+			PaymentStatus:  status,
+			OpenDate:       opened,
+			LastReported:   tradeline.Reported,
+			MonthsReviewed: tradeline.MonthsReviewed,
+			IsActive:       true,
+		}
+		if account.CreditLimit > 0 {
+			account.Utilization = account.Balance / account.CreditLimit * 100
+			balance += account.Balance
+			limit += account.CreditLimit
+		}
+		report.Accounts = append(report.Accounts, account)
+	}
+	if limit > 0 {
+		report.CreditUtilization = balance / limit * 100
+	}
+
+	for _, inquiry := range raw.Inquiries {
+		date, err := time.Parse("2006-01-02", inquiry.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s inquiry date %q: %w", bureau, inquiry.Date, err)
+		}
+		report.Inquiries = append(report.Inquiries, domain.CreditInquiry{
+			InquiryType: strings.ToUpper(inquiry.Type),
+			Creditor:    strings.TrimSpace(inquiry.Subscriber),
+			InquiryDate: date,
+			Purpose:     inquiry.Purpose,
+		})
+	}
+
+	recordTypes := invertCodes(profile.recordTypes)
+	for _, record := range raw.PublicRecords {
+		recordType, ok := recordTypes[record.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown %s public record type %q", bureau, record.Type)
+		}
+		filed, err := time.Parse("2006-01-02", record.Filed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s public record filing date %q: %w", bureau, record.Filed, err)
+		}
+		report.PublicRecords = append(report.PublicRecords, domain.PublicRecord{
+			RecordID:   record.ReferenceNumber,
+			RecordType: recordType,
+			CourtName:  strings.TrimSpace(record.Court),
+			FilingDate: filed,
+			Amount:     record.Amount,
+			Status:     strings.ToUpper(strings.TrimSpace(record.Status)),
+		})
+	}
+
+	for _, collection := range raw.Collections {
+		reported, err := time.Parse("2006-01-02", collection.Reported)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s collection date %q: %w", bureau, collection.Reported, err)
+		}
+		report.Collections = append(report.Collections, domain.Collection{
+			CollectionID:     collection.AccountNumber,
+			OriginalCreditor: strings.TrimSpace(collection.OriginalCreditor),
+			CollectionAgency: strings.TrimSpace(collection.Agency),
+			Balance:          collection.Balance,
+			OriginalAmount:   collection.OriginalAmount,
+			DateReported:     reported,
+			Status:           strings.ToUpper(strings.TrimSpace(collection.Status)),
+		})
+	}
+
+	alertTypes := invertCodes(profile.alertTypes)
+	for _, alert := range raw.Alerts {
+		alertType, ok := alertTypes[alert.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown %s fraud alert type %q", bureau, alert.Type)
+		}
+		placed, err := time.Parse("2006-01-02", alert.Placed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s fraud alert date %q: %w", bureau, alert.Placed, err)
+		}
+		fraudAlert := domain.FraudAlert{
+			Type:         alertType,
+			ContactPhone: strings.TrimSpace(alert.ContactPhone),
+			PlacedAt:     placed,
+		}
+		if alert.Expires != "" {
+			if fraudAlert.ExpiresAt, err = time.Parse("2006-01-02", alert.Expires); err != nil {
+				return nil, fmt.Errorf("invalid %s fraud alert expiry %q: %w", bureau, alert.Expires, err)
+			}
+		}
+		report.FraudAlerts = append(report.FraudAlerts, fraudAlert)
+	}
+
+	return report, nil
+}
+
+// invertCodes maps a bureau's codes back to the normalized values
+func invertCodes(codes map[string]string) map[string]string {
+	inverted := make(map[string]string, len(codes))
+	for normalized, code := range codes {
+		inverted[code] = normalized
+	}
+	return inverted
+}
@@ -0,0 +1,174 @@
+package infrastructure
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+)
+
+// recordedBureauFixtures are the built-in sandbox responses, one file per test SSN
+//
+//go:embed fixtures/bureau/*.json
+var recordedBureauFixtures embed.FS
+
+// Errors a sandbox fixture can inject in place of a bureau's report
+const (
+	sandboxErrorUnavailable = "unavailable" // the bureau refuses the pull at once
+	sandboxErrorTimeout     = "timeout"     // the bureau never answers; the pull runs into its timeout
+)
+
+// BureauSandboxConfig configures sandbox mode, in which bureau pulls are served from recorded
+// responses keyed by test SSN instead of the bureaus
+type BureauSandboxConfig struct {
+	Enabled     bool
+	FixturesDir string        // extra recorded responses; a file for a built-in SSN replaces it
+	Latency     time.Duration // added to every sandbox pull, on top of a fixture's own latency
+	ErrorRate   float64       // share of sandbox pulls failed at random, 0 to 1
+}
+
+// bureauFixture is a recorded response from each bureau for one test SSN
+type bureauFixture struct {
+	SSN         string                                  `json:"ssn"`
+	Description string                                  `json:"description"`
+	Bureaus     map[domain.Bureau]bureauFixtureResponse `json:"bureaus"`
+}
+
+// bureauFixtureResponse is one bureau's recorded report, or the error it answers with
+type bureauFixtureResponse struct {
+	LatencyMs int              `json:"latency_ms,omitempty"`
+	Error     string           `json:"error,omitempty"`
+	Report    *rawBureauReport `json:"report,omitempty"`
+}
+
+// bureauSandbox serves recorded bureau responses
+type bureauSandbox struct {
+	config   BureauSandboxConfig
+	fixtures map[string]*bureauFixture
+}
+
+// newBureauSandbox loads the built-in fixtures and those in the configured fixtures directory
+func newBureauSandbox(config BureauSandboxConfig) (*bureauSandbox, error) {
+	sandbox := &bureauSandbox{
+		config:   config,
+		fixtures: make(map[string]*bureauFixture),
+	}
+	builtIn, err := fs.Sub(recordedBureauFixtures, "fixtures/bureau")
+	if err != nil {
+		return nil, err
+	}
+	if err := sandbox.load(builtIn); err != nil {
+		return nil, err
+	}
+	if config.FixturesDir != "" {
+		if err := sandbox.load(os.DirFS(config.FixturesDir)); err != nil {
+			return nil, err
+		}
+	}
+	return sandbox, nil
+}
+
+// load reads every JSON fixture at the top of fsys
+func (s *bureauSandbox) load(fsys fs.FS) error {
+	paths, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return fmt.Errorf("failed to read bureau fixture %s: %w", path, err)
+		}
+		var fixture bureauFixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			return fmt.Errorf("failed to decode bureau fixture %s: %w", path, err)
+		}
+		if fixture.SSN == "" {
+			fixture.SSN = strings.TrimSuffix(filepath.Base(path), ".json")
+		}
+		s.fixtures[normalizeSSN(fixture.SSN)] = &fixture
+	}
+	return nil
+}
+
+// ssns returns the test SSNs the sandbox has recorded responses for
+func (s *bureauSandbox) ssns() []string {
+	ssns := make([]string, 0, len(s.fixtures))
+	for ssn := range s.fixtures {
+		ssns = append(ssns, ssn)
+	}
+	sort.Strings(ssns)
+	return ssns
+}
+
+// respond serves a bureau's recorded response for an SSN after the configured latency. The
+// product pulled and the report date are those of the pull, not the recording.
+func (s *bureauSandbox) respond(ctx context.Context, bureau domain.Bureau, product bureauProduct, pullType domain.PullType, ssn string) (*rawBureauReport, error) {
+	fixture, ok := s.fixtures[normalizeSSN(ssn)]
+	if !ok {
+		return nil, fmt.Errorf("%s sandbox has no recorded response for SSN %s", bureau, maskSSN(ssn))
+	}
+	response, ok := fixture.Bureaus[bureau]
+	if !ok {
+		return nil, fmt.Errorf("%s sandbox has no recorded response for SSN %s", bureau, maskSSN(ssn))
+	}
+
+	latency := s.config.Latency + time.Duration(response.LatencyMs)*time.Millisecond
+	if err := sleepContext(ctx, latency); err != nil {
+		return nil, err
+	}
+
+	if s.config.ErrorRate > 0 && rand.Float64() < s.config.ErrorRate {
+		return nil, fmt.Errorf("%s sandbox injected a bureau failure", bureau)
+	}
+	switch response.Error {
+	case "":
+	case sandboxErrorTimeout:
+		<-ctx.Done()
+		return nil, ctx.Err()
+	case sandboxErrorUnavailable:
+		return nil, fmt.Errorf("%s is unavailable", bureau)
+	default:
+		return nil, fmt.Errorf("%s returned an error: %s", bureau, response.Error)
+	}
+	if response.Report == nil {
+		return nil, fmt.Errorf("%s sandbox fixture for SSN %s has no report", bureau, maskSSN(ssn))
+	}
+
+	raw := *response.Report
+	raw.Bureau = string(bureau)
+	raw.Product = product.name
+	raw.InquiryType = string(pullType)
+	raw.ScoreModel = product.scoreModel
+	raw.ReportedAt = time.Now()
+	return &raw, nil
+}
+
+// sleepContext waits for d, or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// normalizeSSN strips the dashes from an SSN
+func normalizeSSN(ssn string) string {
+	return strings.ReplaceAll(strings.TrimSpace(ssn), "-", "")
+}
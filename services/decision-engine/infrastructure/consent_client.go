@@ -0,0 +1,84 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// ConsentClient verifies user consents against the user service's internal API
+type ConsentClient struct {
+	baseURL      string
+	serviceToken string
+	httpClient   *http.Client
+	logger       *zap.Logger
+}
+
+// NewConsentClient creates a new user service consent client. The service token must carry the
+// users:consents:read scope.
+func NewConsentClient(baseURL, serviceToken string, timeout time.Duration, logger *zap.Logger) *ConsentClient {
+	return &ConsentClient{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		serviceToken: serviceToken,
+		httpClient:   &http.Client{Timeout: timeout},
+		logger:       logger,
+	}
+}
+
+// HasActiveConsent checks whether the user has an active consent of the given type
+func (c *ConsentClient) HasActiveConsent(ctx context.Context, userID string, consentType domain.ConsentType) (bool, error) {
+	logger := c.logger.With(
+		zap.String("user_id", userID),
+		zap.String("consent_type", string(consentType)),
+		zap.String("operation", "has_active_consent"),
+	)
+
+	endpoint := fmt.Sprintf("%s/internal/v1/users/%s/consents/%s",
+		c.baseURL, url.PathEscape(userID), url.PathEscape(string(consentType)))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to build consent request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.serviceToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Consent lookup failed", zap.Error(err))
+		return false, fmt.Errorf("failed to query consent: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		logger.Info("No active consent recorded")
+		return false, nil
+	default:
+		logger.Error("Unexpected consent lookup response", zap.Int("status", resp.StatusCode))
+		return false, fmt.Errorf("unexpected consent lookup status: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Success bool `json:"success"`
+		Data    struct {
+			ConsentType string     `json:"consent_type"`
+			RevokedAt   *time.Time `json:"revoked_at"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return false, fmt.Errorf("failed to decode consent response: %w", err)
+	}
+
+	active := body.Success && body.Data.ConsentType == string(consentType) && body.Data.RevokedAt == nil
+	logger.Debug("Consent lookup completed", zap.Bool("active", active))
+	return active, nil
+}
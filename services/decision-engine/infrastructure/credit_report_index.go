@@ -0,0 +1,69 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"github.com/huuhoait/los-demo/services/shared/pkg/cache"
+	"go.uber.org/zap"
+)
+
+// creditReportIndexPrefix namespaces the index keys in the shared Redis database
+const creditReportIndexPrefix = "decision-engine:credit-report"
+
+// RedisCreditReportIndex implements domain.CreditReportIndex in Redis, keyed on SSN token and
+// bureau. Keys expire when the indexed report leaves the cache TTL.
+type RedisCreditReportIndex struct {
+	client *cache.Client
+	logger *zap.Logger
+}
+
+// NewRedisCreditReportIndex creates a new Redis credit report index
+func NewRedisCreditReportIndex(client *cache.Client, logger *zap.Logger) *RedisCreditReportIndex {
+	return &RedisCreditReportIndex{
+		client: client,
+		logger: logger,
+	}
+}
+
+// LookupBureauReport returns the indexed report id, or 0 when nothing is indexed
+func (i *RedisCreditReportIndex) LookupBureauReport(ctx context.Context, ssnToken string, bureau domain.Bureau) (int64, error) {
+	value, err := i.client.GetString(ctx, creditReportIndexKey(ssnToken, bureau))
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up credit report index: %w", err)
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid credit report index entry %q: %w", value, err)
+	}
+	return id, nil
+}
+
+// IndexBureauReport points the SSN token and bureau at a stored report for ttl
+func (i *RedisCreditReportIndex) IndexBureauReport(ctx context.Context, ssnToken string, bureau domain.Bureau, reportID int64, ttl time.Duration) error {
+	if err := i.client.SetString(ctx, creditReportIndexKey(ssnToken, bureau), strconv.FormatInt(reportID, 10), ttl); err != nil {
+		return fmt.Errorf("failed to index credit report: %w", err)
+	}
+	return nil
+}
+
+// ForgetBureauReport removes the index entry for an SSN token and bureau
+func (i *RedisCreditReportIndex) ForgetBureauReport(ctx context.Context, ssnToken string, bureau domain.Bureau) error {
+	if err := i.client.Delete(ctx, creditReportIndexKey(ssnToken, bureau)); err != nil {
+		return fmt.Errorf("failed to remove credit report index entry: %w", err)
+	}
+	return nil
+}
+
+func creditReportIndexKey(ssnToken string, bureau domain.Bureau) string {
+	return fmt.Sprintf("%s:%s:%s", creditReportIndexPrefix, ssnToken, bureau)
+}
@@ -0,0 +1,263 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// CreditReportRepository implements tri-merge and raw bureau report persistence
+type CreditReportRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewCreditReportRepository creates a new credit report repository
+func NewCreditReportRepository(db *sql.DB, logger *zap.Logger) *CreditReportRepository {
+	return &CreditReportRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SaveTriMergeReport saves a tri-merge report and the raw bureau reports it was built from in
+// one transaction
+func (r *CreditReportRepository) SaveTriMergeReport(ctx context.Context, report *domain.TriMergeReport, rawReports []domain.BureauReport) error {
+	logger := r.logger.With(zap.String("user_id", report.UserID))
+
+	mergedJSON, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tri-merge report: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO tri_merge_reports (
+			user_id, ssn_token, application_id, pull_type, status, score_policy, selected_score, selected_bureau,
+			merged_report, requested_at, completed_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id`,
+		report.UserID,
+		report.SSNToken,
+		nullString(report.ApplicationID),
+		report.PullType,
+		report.Status,
+		report.ScorePolicy,
+		report.SelectedScore,
+		report.SelectedBureau,
+		mergedJSON,
+		report.RequestedAt,
+		report.CompletedAt,
+	).Scan(&id)
+	if err != nil {
+		logger.Error("Failed to save tri-merge report", zap.Error(err))
+		return fmt.Errorf("failed to save tri-merge report: %w", err)
+	}
+
+	reportIDs := make([]int64, len(rawReports))
+	for i, raw := range rawReports {
+		normalizedJSON, err := json.Marshal(raw.Report)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s report: %w", raw.Bureau, err)
+		}
+
+		// A cached report is stored again for this merge, pointing at the report it reused
+		var sourceID sql.NullInt64
+		if raw.Cached {
+			sourceID = sql.NullInt64{Int64: raw.ID, Valid: raw.ID > 0}
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO credit_bureau_reports (
+				merge_id, bureau, user_id, ssn_token, pull_type, product, raw_report, normalized_report,
+				source_report_id, pulled_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING id`,
+			id,
+			raw.Bureau,
+			report.UserID,
+			report.SSNToken,
+			raw.PullType,
+			nullString(raw.Product),
+			[]byte(raw.RawReport),
+			normalizedJSON,
+			sourceID,
+			raw.PulledAt,
+		).Scan(&reportIDs[i])
+		if err != nil {
+			logger.Error("Failed to save bureau report", zap.String("bureau", string(raw.Bureau)), zap.Error(err))
+			return fmt.Errorf("failed to save %s report: %w", raw.Bureau, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit transaction", zap.Error(err))
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	report.ID = id
+	for i := range rawReports {
+		if !rawReports[i].Cached {
+			rawReports[i].ID = reportIDs[i]
+		}
+	}
+	return nil
+}
+
+// SaveBureauReport saves a report pulled outside a tri-merge
+func (r *CreditReportRepository) SaveBureauReport(ctx context.Context, userID, ssnToken string, report *domain.BureauReport) error {
+	normalizedJSON, err := json.Marshal(report.Report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s report: %w", report.Bureau, err)
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO credit_bureau_reports (bureau, user_id, ssn_token, pull_type, product, raw_report, normalized_report, pulled_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`,
+		report.Bureau,
+		userID,
+		ssnToken,
+		report.PullType,
+		nullString(report.Product),
+		[]byte(report.RawReport),
+		normalizedJSON,
+		report.PulledAt,
+	).Scan(&report.ID)
+	if err != nil {
+		r.logger.Error("Failed to save bureau report", zap.String("user_id", userID), zap.String("bureau", string(report.Bureau)), zap.Error(err))
+		return fmt.Errorf("failed to save %s report: %w", report.Bureau, err)
+	}
+	return nil
+}
+
+// GetTriMergeReport returns a tri-merge report by id
+func (r *CreditReportRepository) GetTriMergeReport(ctx context.Context, id int64) (*domain.TriMergeReport, error) {
+	var (
+		mergedJSON []byte
+		ssnToken   string
+	)
+	err := r.db.QueryRowContext(ctx, `SELECT merged_report, ssn_token FROM tri_merge_reports WHERE id = $1`, id).Scan(&mergedJSON, &ssnToken)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("tri-merge report not found: %d", id)
+	}
+	if err != nil {
+		r.logger.Error("Failed to get tri-merge report", zap.Int64("merge_id", id), zap.Error(err))
+		return nil, fmt.Errorf("failed to get tri-merge report: %w", err)
+	}
+
+	var report domain.TriMergeReport
+	if err := json.Unmarshal(mergedJSON, &report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tri-merge report: %w", err)
+	}
+	report.ID = id
+	report.SSNToken = ssnToken
+	return &report, nil
+}
+
+// GetBureauReports returns the raw bureau reports a tri-merge was built from
+func (r *CreditReportRepository) GetBureauReports(ctx context.Context, mergeID int64) ([]domain.StoredBureauReport, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, merge_id, bureau, user_id, ssn_token, pull_type, raw_report, source_report_id, pulled_at
+		FROM credit_bureau_reports WHERE merge_id = $1 ORDER BY id`, mergeID)
+	if err != nil {
+		r.logger.Error("Failed to query bureau reports", zap.Int64("merge_id", mergeID), zap.Error(err))
+		return nil, fmt.Errorf("failed to query bureau reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := make([]domain.StoredBureauReport, 0)
+	for rows.Next() {
+		var (
+			report   domain.StoredBureauReport
+			rawJSON  []byte
+			sourceID sql.NullInt64
+		)
+		if err := rows.Scan(&report.ID, &report.MergeID, &report.Bureau, &report.UserID, &report.SSNToken, &report.PullType, &rawJSON, &sourceID, &report.PulledAt); err != nil {
+			return nil, fmt.Errorf("failed to scan bureau report: %w", err)
+		}
+		report.RawReport = json.RawMessage(rawJSON)
+		report.SourceReportID = sourceID.Int64
+		reports = append(reports, report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read bureau reports: %w", err)
+	}
+	return reports, nil
+}
+
+// GetBureauReport returns a stored bureau report, normalized
+func (r *CreditReportRepository) GetBureauReport(ctx context.Context, id int64) (*domain.BureauReport, error) {
+	report, err := r.scanBureauReport(r.db.QueryRowContext(ctx, `
+		SELECT id, bureau, pull_type, COALESCE(product, ''), raw_report, normalized_report, pulled_at
+		FROM credit_bureau_reports WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("bureau report not found: %d", id)
+	}
+	if err != nil {
+		r.logger.Error("Failed to get bureau report", zap.Int64("report_id", id), zap.Error(err))
+		return nil, fmt.Errorf("failed to get bureau report: %w", err)
+	}
+	return report, nil
+}
+
+// GetLatestBureauReport returns the newest report of one of the pull types pulled from a bureau
+// for a user and SSN token since a time, or nil when there is none
+func (r *CreditReportRepository) GetLatestBureauReport(ctx context.Context, userID, ssnToken string, bureau domain.Bureau, pullTypes []domain.PullType, since time.Time) (*domain.BureauReport, error) {
+	types := make([]string, len(pullTypes))
+	for i, pullType := range pullTypes {
+		types[i] = string(pullType)
+	}
+
+	report, err := r.scanBureauReport(r.db.QueryRowContext(ctx, `
+		SELECT id, bureau, pull_type, COALESCE(product, ''), raw_report, normalized_report, pulled_at
+		FROM credit_bureau_reports
+		WHERE ssn_token = $1 AND bureau = $2 AND user_id = $3 AND pull_type = ANY($4) AND pulled_at >= $5
+			AND source_report_id IS NULL AND normalized_report IS NOT NULL
+		ORDER BY pulled_at DESC
+		LIMIT 1`,
+		ssnToken, bureau, userID, pq.Array(types), since))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Error("Failed to get latest bureau report", zap.String("bureau", string(bureau)), zap.Error(err))
+		return nil, fmt.Errorf("failed to get latest bureau report: %w", err)
+	}
+	return report, nil
+}
+
+// scanBureauReport scans a stored bureau report with its normalized form; reports stored before
+// normalized reports were kept have no Report
+func (r *CreditReportRepository) scanBureauReport(row *sql.Row) (*domain.BureauReport, error) {
+	var (
+		report         domain.BureauReport
+		rawJSON        []byte
+		normalizedJSON []byte
+	)
+	if err := row.Scan(&report.ID, &report.Bureau, &report.PullType, &report.Product, &rawJSON, &normalizedJSON, &report.PulledAt); err != nil {
+		return nil, err
+	}
+	report.RawReport = json.RawMessage(rawJSON)
+	if len(normalizedJSON) > 0 {
+		var normalized domain.CreditReport
+		if err := json.Unmarshal(normalizedJSON, &normalized); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal normalized report: %w", err)
+		}
+		report.Report = &normalized
+	}
+	return &report, nil
+}
@@ -11,8 +11,12 @@ import (
 
 // CreditBureauRepository handles external credit bureau integrations
 type CreditBureauRepository struct {
-	logger *zap.Logger
-	config CreditBureauConfig
+	logger              *zap.Logger
+	config              CreditBureauConfig
+	consentVerifier     domain.ConsentVerifier
+	applicationVerifier domain.ApplicationVerifier
+	detokenizer         domain.PIIDetokenizer
+	sandbox             *bureauSandbox // serves recorded responses instead of the bureaus; nil outside sandbox mode
 }
 
 // CreditBureauConfig holds configuration for credit bureau services
@@ -21,29 +25,70 @@ type CreditBureauConfig struct {
 	EquifaxEndpoint    string
 	TransUnionEndpoint string
 	APITimeout         time.Duration
+	BureauTimeouts     map[domain.Bureau]time.Duration // per-bureau pull timeouts; APITimeout when unset
 	RetryAttempts      int
+	Sandbox            BureauSandboxConfig
 }
 
-// NewCreditBureauRepository creates a new credit bureau repository
-func NewCreditBureauRepository(logger *zap.Logger, config CreditBureauConfig) *CreditBureauRepository {
-	return &CreditBureauRepository{
-		logger: logger,
-		config: config,
+// NewCreditBureauRepository creates a new credit bureau repository. In sandbox mode, pulls are
+// served from recorded responses; a fixtures directory that cannot be loaded is an error.
+func NewCreditBureauRepository(logger *zap.Logger, config CreditBureauConfig, consentVerifier domain.ConsentVerifier, applicationVerifier domain.ApplicationVerifier, detokenizer domain.PIIDetokenizer) (*CreditBureauRepository, error) {
+	repo := &CreditBureauRepository{
+		logger:              logger,
+		config:              config,
+		consentVerifier:     consentVerifier,
+		applicationVerifier: applicationVerifier,
+		detokenizer:         detokenizer,
 	}
+	if config.Sandbox.Enabled {
+		sandbox, err := newBureauSandbox(config.Sandbox)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load credit bureau sandbox fixtures: %w", err)
+		}
+		repo.sandbox = sandbox
+		logger.Warn("Credit bureau sandbox mode enabled; bureau pulls are served from recorded responses",
+			zap.Strings("test_ssns", sandbox.ssns()),
+			zap.String("fixtures_dir", config.Sandbox.FixturesDir),
+			zap.Duration("latency", config.Sandbox.Latency),
+			zap.Float64("error_rate", config.Sandbox.ErrorRate))
+	}
+	return repo, nil
 }
 
 // GetCreditScore retrieves credit score from primary bureau
 func (r *CreditBureauRepository) GetCreditScore(ctx context.Context, request *domain.CreditScoreRequest) (*domain.CreditScoreResponse, error) {
 	logger := r.logger.With(
-		zap.String("ssn", maskSSN(request.SSN)),
+		zap.String("user_id", request.UserID),
 		zap.String("operation", "get_credit_score"),
 	)
 
-	logger.Info("Retrieving credit score from bureau")
+	if domain.IsHardInquiry(request.RequestType) {
+		if err := r.requireHardPullAllowed(ctx, request.UserID, request.ApplicationID); err != nil {
+			logger.Warn("Hard credit pull refused", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	ssn, err := r.resolveSSN(ctx, request.SSNToken)
+	if err != nil {
+		logger.Error("Failed to resolve SSN token", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("Retrieving credit score from bureau", zap.String("ssn", maskSSN(ssn)))
 
 	// In production, this would make actual API calls to credit bureaus
 	// For now, we'll simulate the response based on provided data
-	response := r.simulateCreditBureauResponse(request)
+	var response *domain.CreditScoreResponse
+	if r.sandbox != nil {
+		response, err = r.sandboxCreditScore(ctx, request, ssn)
+		if err != nil {
+			logger.Warn("Sandbox credit score pull failed", zap.Error(err))
+			return nil, err
+		}
+	} else {
+		response = r.simulateCreditBureauResponse(request, ssn)
+	}
 
 	logger.Info("Credit score retrieved",
 		zap.Int("credit_score", response.CreditScore),
@@ -56,14 +101,27 @@ func (r *CreditBureauRepository) GetCreditScore(ctx context.Context, request *do
 // GetDetailedCreditReport retrieves full credit report
 func (r *CreditBureauRepository) GetDetailedCreditReport(ctx context.Context, request *domain.CreditReportRequest) (*domain.CreditReport, error) {
 	logger := r.logger.With(
-		zap.String("ssn", maskSSN(request.SSN)),
+		zap.String("user_id", request.UserID),
 		zap.String("operation", "get_credit_report"),
 	)
 
-	logger.Info("Retrieving detailed credit report")
+	if request.EffectivePullType() == domain.PullTypeHard {
+		if err := r.requireHardPullAllowed(ctx, request.UserID, request.ApplicationID); err != nil {
+			logger.Warn("Hard credit pull refused", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	ssn, err := r.resolveSSN(ctx, request.SSNToken)
+	if err != nil {
+		logger.Error("Failed to resolve SSN token", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("Retrieving detailed credit report", zap.String("ssn", maskSSN(ssn)))
 
 	// Simulate detailed credit report
-	report := r.simulateDetailedCreditReport(request)
+	report := r.simulateDetailedCreditReport(request, ssn)
 
 	logger.Info("Credit report retrieved",
 		zap.Int("account_count", len(report.Accounts)),
@@ -73,12 +131,153 @@ func (r *CreditBureauRepository) GetDetailedCreditReport(ctx context.Context, re
 	return report, nil
 }
 
+// requireHardPullAllowed refuses a hard pull unless the user has an active credit pull consent and
+// the loan service reports the user's application submitted, still open and consented to. Soft
+// pulls are for pre-qualification, before there is a submitted application. Fails closed when no
+// application verifier is configured or the lookup fails.
+func (r *CreditBureauRepository) requireHardPullAllowed(ctx context.Context, userID, applicationID string) error {
+	if err := r.requireCreditPullConsent(ctx, userID); err != nil {
+		return err
+	}
+
+	if applicationID == "" {
+		return hardPullNotAllowedError("application_id is required for a hard pull; pre-qualification uses a soft pull")
+	}
+	if r.applicationVerifier == nil {
+		return hardPullNotAllowedError("application verification is not configured")
+	}
+
+	eligibility, err := r.applicationVerifier.GetCreditPullEligibility(ctx, applicationID)
+	if err != nil {
+		return &domain.DecisionError{
+			Code:        domain.ERROR_EXTERNAL_SERVICE,
+			Message:     "Unable to verify application for hard credit pull",
+			Description: err.Error(),
+			HTTPStatus:  503,
+		}
+	}
+
+	switch {
+	case eligibility == nil:
+		return hardPullNotAllowedError(fmt.Sprintf("application %s not found", applicationID))
+	case eligibility.UserID != userID:
+		return hardPullNotAllowedError(fmt.Sprintf("application %s belongs to another user", applicationID))
+	case !eligibility.Submitted:
+		return hardPullNotAllowedError(fmt.Sprintf("application %s has not been submitted", applicationID))
+	case !eligibility.Consented:
+		return &domain.DecisionError{
+			Code:        domain.ERROR_CONSENT_REQUIRED,
+			Message:     "Credit pull consent required",
+			Description: fmt.Sprintf("no credit pull consent recorded for application %s", applicationID),
+			HTTPStatus:  403,
+		}
+	case !eligibility.Eligible:
+		return hardPullNotAllowedError(fmt.Sprintf("application %s is %s", applicationID, eligibility.State))
+	}
+	return nil
+}
+
+func hardPullNotAllowedError(description string) error {
+	return &domain.DecisionError{
+		Code:        domain.ERROR_HARD_PULL_NOT_ALLOWED,
+		Message:     "Hard credit pull not allowed",
+		Description: description,
+		HTTPStatus:  403,
+	}
+}
+
+// requireCreditPullConsent refuses a hard pull unless the user has an active credit pull consent.
+// Fails closed when no consent verifier is configured or the lookup fails.
+func (r *CreditBureauRepository) requireCreditPullConsent(ctx context.Context, userID string) error {
+	if r.consentVerifier == nil {
+		return &domain.DecisionError{
+			Code:        domain.ERROR_CONSENT_REQUIRED,
+			Message:     "Credit pull consent required",
+			Description: "consent verification is not configured",
+			HTTPStatus:  403,
+		}
+	}
+
+	if userID == "" {
+		return &domain.DecisionError{
+			Code:        domain.ERROR_CONSENT_REQUIRED,
+			Message:     "Credit pull consent required",
+			Description: "user_id is required to verify consent",
+			HTTPStatus:  403,
+		}
+	}
+
+	consented, err := r.consentVerifier.HasActiveConsent(ctx, userID, domain.ConsentTypeCreditPull)
+	if err != nil {
+		return &domain.DecisionError{
+			Code:        domain.ERROR_EXTERNAL_SERVICE,
+			Message:     "Unable to verify credit pull consent",
+			Description: err.Error(),
+			HTTPStatus:  503,
+		}
+	}
+
+	if !consented {
+		return &domain.DecisionError{
+			Code:        domain.ERROR_CONSENT_REQUIRED,
+			Message:     "Credit pull consent required",
+			Description: "no active credit pull consent recorded for user",
+			HTTPStatus:  403,
+		}
+	}
+
+	return nil
+}
+
+// resolveSSN exchanges an SSN token for the raw SSN immediately before the bureau call.
+// The raw value is never written back onto the request or persisted.
+func (r *CreditBureauRepository) resolveSSN(ctx context.Context, token string) (string, error) {
+	if token == "" {
+		return "", &domain.DecisionError{
+			Code:        domain.ERROR_INSUFFICIENT_DATA,
+			Message:     "SSN token required",
+			Description: "ssn_token is required for a bureau request",
+			HTTPStatus:  400,
+		}
+	}
+
+	if r.detokenizer == nil {
+		return "", &domain.DecisionError{
+			Code:        domain.ERROR_EXTERNAL_SERVICE,
+			Message:     "Unable to resolve SSN token",
+			Description: "token vault is not configured",
+			HTTPStatus:  503,
+		}
+	}
+
+	ssn, err := r.detokenizer.Detokenize(ctx, token)
+	if err != nil {
+		return "", &domain.DecisionError{
+			Code:        domain.ERROR_EXTERNAL_SERVICE,
+			Message:     "Unable to resolve SSN token",
+			Description: err.Error(),
+			HTTPStatus:  503,
+		}
+	}
+
+	if err := ValidateSSN(ssn); err != nil {
+		return "", &domain.DecisionError{
+			Code:        domain.ERROR_INVALID_REQUEST,
+			Message:     "Invalid SSN",
+			Description: err.Error(),
+			HTTPStatus:  400,
+		}
+	}
+
+	return ssn, nil
+}
+
 // simulateCreditBureauResponse simulates credit bureau API response
-func (r *CreditBureauRepository) simulateCreditBureauResponse(request *domain.CreditScoreRequest) *domain.CreditScoreResponse {
+func (r *CreditBureauRepository) simulateCreditBureauResponse(request *domain.CreditScoreRequest, ssn string) *domain.CreditScoreResponse {
 	// This is simulation logic - in production, replace with actual API calls
 
 	// Use last 4 digits of SSN to generate consistent but varied scores
-	lastFour := request.SSN[len(request.SSN)-4:]
+	lastFour := ssn[len(ssn)-4:]
 	seed := 0
 	for _, char := range lastFour {
 		seed += int(char)
@@ -110,19 +309,40 @@ func (r *CreditBureauRepository) simulateCreditBureauResponse(request *domain.Cr
 	}
 }
 
+// sandboxCreditScore serves the primary bureau's recorded score for an SSN
+func (r *CreditBureauRepository) sandboxCreditScore(ctx context.Context, request *domain.CreditScoreRequest, ssn string) (*domain.CreditScoreResponse, error) {
+	pullType := domain.PullTypeSoft
+	if domain.IsHardInquiry(request.RequestType) {
+		pullType = domain.PullTypeHard
+	}
+	product := bureauProfiles[domain.BureauExperian].products[pullType]
+	raw, err := r.sandbox.respond(ctx, domain.BureauExperian, product, pullType, ssn)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.CreditScoreResponse{
+		CreditScore: raw.Score,
+		ScoreType:   "FICO",
+		ScoreRange:  "300-850",
+		Bureau:      raw.Bureau,
+		ReportDate:  raw.ReportedAt,
+		FactorCodes: r.generateFactorCodes(raw.Score),
+		RiskLevel:   r.categorizeRiskLevel(raw.Score),
+	}, nil
+}
+
 // simulateDetailedCreditReport simulates detailed credit report
-func (r *CreditBureauRepository) simulateDetailedCreditReport(request *domain.CreditReportRequest) *domain.CreditReport {
+func (r *CreditBureauRepository) simulateDetailedCreditReport(request *domain.CreditReportRequest, ssn string) *domain.CreditReport {
 	creditScore := r.simulateCreditBureauResponse(&domain.CreditScoreRequest{
-		SSN:       request.SSN,
 		FirstName: request.FirstName,
 		LastName:  request.LastName,
-	})
+	}, ssn)
 
 	report := &domain.CreditReport{
 		PersonalInfo: domain.PersonalInfo{
 			FirstName:   request.FirstName,
 			LastName:    request.LastName,
-			SSN:         maskSSN(request.SSN),
+			SSN:         maskSSN(ssn),
 			DateOfBirth: request.DateOfBirth,
 			Address:     request.Address,
 		},
@@ -26,8 +26,9 @@ func NewDecisionRepository(db *sql.DB, logger *zap.Logger) *DecisionRepository {
 	}
 }
 
-// SaveDecision saves a decision to the database
-func (r *DecisionRepository) SaveDecision(ctx context.Context, decision *domain.DecisionResponse) error {
+// SaveDecision saves a decision to the database with the request it was made on, kept so the
+// decision can be replayed; request may be nil
+func (r *DecisionRepository) SaveDecision(ctx context.Context, decision *domain.DecisionResponse, request *domain.DecisionRequest) error {
 	logger := r.logger.With(
 		zap.String("application_id", decision.ApplicationID),
 		zap.String("operation", "save_decision"),
@@ -35,37 +36,162 @@ func (r *DecisionRepository) SaveDecision(ctx context.Context, decision *domain.
 
 	logger.Info("Saving decision to database")
 
-	// Serialize complex objects to JSON
+	args, err := decisionArgs(decision, request)
+	if err != nil {
+		logger.Error("Failed to marshal decision", zap.Error(err))
+		return err
+	}
+
+	var decisionID int64
+	if err := r.db.QueryRowContext(ctx, insertDecisionQuery, args...).Scan(&decisionID); err != nil {
+		logger.Error("Failed to save decision", zap.Error(err))
+		return fmt.Errorf("failed to save decision: %w", err)
+	}
+
+	decision.DecisionID = decisionID
+	logger.Info("Decision saved successfully", zap.Int64("decision_id", decisionID))
+	return nil
+}
+
+// SaveDecisions saves decisions made in bulk, each with its request, in one transaction. The
+// requests also replace the latest request stored for their applications, as SaveDecisionRequest
+// does. requests[i] is the request for decisions[i].
+func (r *DecisionRepository) SaveDecisions(ctx context.Context, decisions []*domain.DecisionResponse, requests []*domain.DecisionRequest) error {
+	logger := r.logger.With(
+		zap.Int("decisions", len(decisions)),
+		zap.String("operation", "save_decisions"),
+	)
+
+	if len(decisions) != len(requests) {
+		return fmt.Errorf("got %d decisions but %d requests", len(decisions), len(requests))
+	}
+	if len(decisions) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	requestStmt, err := tx.PrepareContext(ctx, upsertDecisionRequestQuery)
+	if err != nil {
+		return fmt.Errorf("failed to prepare decision request insert: %w", err)
+	}
+	defer requestStmt.Close()
+
+	decisionStmt, err := tx.PrepareContext(ctx, insertDecisionQuery)
+	if err != nil {
+		return fmt.Errorf("failed to prepare decision insert: %w", err)
+	}
+	defer decisionStmt.Close()
+
+	ids := make([]int64, len(decisions))
+	for i, decision := range decisions {
+		requestArgs, err := decisionRequestArgs(requests[i])
+		if err != nil {
+			return err
+		}
+		var requestID int64
+		if err := requestStmt.QueryRowContext(ctx, requestArgs...).Scan(&requestID); err != nil {
+			logger.Error("Failed to save decision request", zap.String("application_id", requests[i].ApplicationID), zap.Error(err))
+			return fmt.Errorf("failed to save decision request for %s: %w", requests[i].ApplicationID, err)
+		}
+
+		args, err := decisionArgs(decision, requests[i])
+		if err != nil {
+			return err
+		}
+		if err := decisionStmt.QueryRowContext(ctx, args...).Scan(&ids[i]); err != nil {
+			logger.Error("Failed to save decision", zap.String("application_id", decision.ApplicationID), zap.Error(err))
+			return fmt.Errorf("failed to save decision for %s: %w", decision.ApplicationID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit transaction", zap.Error(err))
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	for i, decision := range decisions {
+		decision.DecisionID = ids[i]
+	}
+	logger.Info("Decisions saved successfully")
+	return nil
+}
+
+const insertDecisionQuery = `
+	INSERT INTO decisions (
+		application_id, decision, confidence_score, interest_rate, 
+		max_amount, reason, risk_assessment, applied_rules, 
+		recommendations, rule_versions, rule_hits, adverse_action_reasons,
+		pricing, policy_version, stages, pending_sources, request_payload, decision_date, created_at
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
+	) RETURNING id`
+
+// decisionArgs serializes a decision, and the request it was made on when there is one, into the
+// arguments of insertDecisionQuery
+func decisionArgs(decision *domain.DecisionResponse, request *domain.DecisionRequest) ([]interface{}, error) {
 	riskAssessmentJSON, err := json.Marshal(decision.RiskAssessment)
 	if err != nil {
-		logger.Error("Failed to marshal risk assessment", zap.Error(err))
-		return fmt.Errorf("failed to marshal risk assessment: %w", err)
+		return nil, fmt.Errorf("failed to marshal risk assessment: %w", err)
 	}
 
 	appliedRulesJSON, err := json.Marshal(decision.AppliedRules)
 	if err != nil {
-		logger.Error("Failed to marshal applied rules", zap.Error(err))
-		return fmt.Errorf("failed to marshal applied rules: %w", err)
+		return nil, fmt.Errorf("failed to marshal applied rules: %w", err)
 	}
 
 	recommendationsJSON, err := json.Marshal(decision.Recommendations)
 	if err != nil {
-		logger.Error("Failed to marshal recommendations", zap.Error(err))
-		return fmt.Errorf("failed to marshal recommendations: %w", err)
+		return nil, fmt.Errorf("failed to marshal recommendations: %w", err)
 	}
 
-	// Insert decision record
-	query := `
-		INSERT INTO decisions (
-			application_id, decision, confidence_score, interest_rate, 
-			max_amount, reason, risk_assessment, applied_rules, 
-			recommendations, decision_date, created_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
-		) RETURNING id`
+	ruleVersionsJSON, err := json.Marshal(decision.RuleVersions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rule versions: %w", err)
+	}
 
-	var decisionID int64
-	err = r.db.QueryRowContext(ctx, query,
+	ruleHitsJSON, err := json.Marshal(decision.RuleHits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rule hits: %w", err)
+	}
+
+	adverseActionReasonsJSON, err := json.Marshal(decision.AdverseActionReasons)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal adverse action reasons: %w", err)
+	}
+
+	var pricingJSON []byte
+	if decision.Pricing != nil {
+		if pricingJSON, err = json.Marshal(decision.Pricing); err != nil {
+			return nil, fmt.Errorf("failed to marshal pricing: %w", err)
+		}
+	}
+
+	stagesJSON, err := json.Marshal(decision.Stages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal stages: %w", err)
+	}
+
+	var pendingSourcesJSON []byte
+	if len(decision.PendingSources) > 0 {
+		if pendingSourcesJSON, err = json.Marshal(decision.PendingSources); err != nil {
+			return nil, fmt.Errorf("failed to marshal pending sources: %w", err)
+		}
+	}
+
+	var requestPayloadJSON []byte
+	if request != nil {
+		if requestPayloadJSON, err = json.Marshal(request); err != nil {
+			return nil, fmt.Errorf("failed to marshal decision request: %w", err)
+		}
+	}
+
+	return []interface{}{
 		decision.ApplicationID,
 		decision.Decision,
 		decision.ConfidenceScore,
@@ -75,42 +201,74 @@ func (r *DecisionRepository) SaveDecision(ctx context.Context, decision *domain.
 		riskAssessmentJSON,
 		appliedRulesJSON,
 		recommendationsJSON,
+		ruleVersionsJSON,
+		ruleHitsJSON,
+		adverseActionReasonsJSON,
+		pricingJSON,
+		nullString(decision.PolicyVersion),
+		stagesJSON,
+		pendingSourcesJSON,
+		requestPayloadJSON,
 		decision.DecisionDate,
 		time.Now(),
-	).Scan(&decisionID)
-
-	if err != nil {
-		logger.Error("Failed to save decision", zap.Error(err))
-		return fmt.Errorf("failed to save decision: %w", err)
-	}
-
-	logger.Info("Decision saved successfully", zap.Int64("decision_id", decisionID))
-	return nil
+	}, nil
 }
 
-// GetDecisionByApplicationID retrieves a decision by application ID
+// GetDecisionByApplicationID retrieves the latest decision made on an application
 func (r *DecisionRepository) GetDecisionByApplicationID(ctx context.Context, applicationID string) (*domain.DecisionResponse, error) {
 	logger := r.logger.With(
 		zap.String("application_id", applicationID),
 		zap.String("operation", "get_decision"),
 	)
 
+	decision, err := r.queryDecision(ctx, logger, `d.application_id = $1`, applicationID)
+	if err == sql.ErrNoRows {
+		logger.Info("No decision found for application")
+		return nil, fmt.Errorf("decision not found for application %s", applicationID)
+	}
+	return decision, err
+}
+
+// GetDecisionByID retrieves a decision by its id
+func (r *DecisionRepository) GetDecisionByID(ctx context.Context, id int64) (*domain.DecisionResponse, error) {
+	logger := r.logger.With(
+		zap.Int64("decision_id", id),
+		zap.String("operation", "get_decision_by_id"),
+	)
+
+	decision, err := r.queryDecision(ctx, logger, `d.id = $1`, id)
+	if err == sql.ErrNoRows {
+		logger.Info("No decision found")
+		return nil, fmt.Errorf("decision not found: %d", id)
+	}
+	return decision, err
+}
+
+// queryDecision retrieves the latest decision matching a condition, with the amount requested.
+// It returns sql.ErrNoRows when no decision matches.
+func (r *DecisionRepository) queryDecision(ctx context.Context, logger *zap.Logger, condition string, arg interface{}) (*domain.DecisionResponse, error) {
 	logger.Info("Retrieving decision from database")
 
 	query := `
-		SELECT application_id, decision, confidence_score, interest_rate,
-			   max_amount, reason, risk_assessment, applied_rules,
-			   recommendations, decision_date, created_at
-		FROM decisions 
-		WHERE application_id = $1 
-		ORDER BY created_at DESC 
+		SELECT d.id, d.application_id, d.decision, d.confidence_score, d.interest_rate,
+			   d.max_amount, d.reason, d.risk_assessment, d.applied_rules,
+			   d.recommendations, d.rule_versions, d.rule_hits, d.adverse_action_reasons, d.pricing,
+			   d.policy_version, d.stages, d.pending_sources, d.decision_date, d.created_at, COALESCE(dr.loan_amount, 0),
+			   COALESCE(dr.tenant_id, ''), COALESCE(dr.credit_score, 0)
+		FROM decisions d
+		LEFT JOIN decision_requests dr ON dr.application_id = d.application_id
+		WHERE ` + condition + `
+		ORDER BY d.created_at DESC
 		LIMIT 1`
 
 	var decision domain.DecisionResponse
-	var riskAssessmentJSON, appliedRulesJSON, recommendationsJSON []byte
+	var riskAssessmentJSON, appliedRulesJSON, recommendationsJSON, ruleVersionsJSON []byte
+	var ruleHitsJSON, adverseActionReasonsJSON, pricingJSON, stagesJSON, pendingSourcesJSON []byte
+	var policyVersion sql.NullString
 	var createdAt time.Time
 
-	err := r.db.QueryRowContext(ctx, query, applicationID).Scan(
+	err := r.db.QueryRowContext(ctx, query, arg).Scan(
+		&decision.DecisionID,
 		&decision.ApplicationID,
 		&decision.Decision,
 		&decision.ConfidenceScore,
@@ -120,24 +278,38 @@ func (r *DecisionRepository) GetDecisionByApplicationID(ctx context.Context, app
 		&riskAssessmentJSON,
 		&appliedRulesJSON,
 		&recommendationsJSON,
+		&ruleVersionsJSON,
+		&ruleHitsJSON,
+		&adverseActionReasonsJSON,
+		&pricingJSON,
+		&policyVersion,
+		&stagesJSON,
+		&pendingSourcesJSON,
 		&decision.DecisionDate,
 		&createdAt,
+		&decision.RequestedAmount,
+		&decision.TenantID,
+		&decision.CreditScore,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			logger.Info("No decision found for application")
-			return nil, fmt.Errorf("decision not found for application %s", applicationID)
+			return nil, err
 		}
 		logger.Error("Failed to retrieve decision", zap.Error(err))
 		return nil, fmt.Errorf("failed to retrieve decision: %w", err)
 	}
+	decision.DecisionReason = decision.Reason
+	decision.PolicyVersion = policyVersion.String
 
 	// Deserialize JSON fields
 	if err := json.Unmarshal(riskAssessmentJSON, &decision.RiskAssessment); err != nil {
 		logger.Error("Failed to unmarshal risk assessment", zap.Error(err))
 		return nil, fmt.Errorf("failed to unmarshal risk assessment: %w", err)
 	}
+	if decision.RiskAssessment != nil {
+		decision.RiskScore = decision.RiskAssessment.OverallScore
+	}
 
 	if err := json.Unmarshal(appliedRulesJSON, &decision.AppliedRules); err != nil {
 		logger.Error("Failed to unmarshal applied rules", zap.Error(err))
@@ -149,11 +321,130 @@ func (r *DecisionRepository) GetDecisionByApplicationID(ctx context.Context, app
 		return nil, fmt.Errorf("failed to unmarshal recommendations: %w", err)
 	}
 
+	// Decisions made before rule versioning have no rule versions, and decisions made before
+	// adverse action reasons were recorded have no rule hits or reasons
+	if len(ruleVersionsJSON) > 0 {
+		if err := json.Unmarshal(ruleVersionsJSON, &decision.RuleVersions); err != nil {
+			logger.Error("Failed to unmarshal rule versions", zap.Error(err))
+			return nil, fmt.Errorf("failed to unmarshal rule versions: %w", err)
+		}
+	}
+	if len(ruleHitsJSON) > 0 {
+		if err := json.Unmarshal(ruleHitsJSON, &decision.RuleHits); err != nil {
+			logger.Error("Failed to unmarshal rule hits", zap.Error(err))
+			return nil, fmt.Errorf("failed to unmarshal rule hits: %w", err)
+		}
+	}
+	if len(adverseActionReasonsJSON) > 0 {
+		if err := json.Unmarshal(adverseActionReasonsJSON, &decision.AdverseActionReasons); err != nil {
+			logger.Error("Failed to unmarshal adverse action reasons", zap.Error(err))
+			return nil, fmt.Errorf("failed to unmarshal adverse action reasons: %w", err)
+		}
+	}
+	// Denials and decisions made before pricing was recorded have no pricing
+	if len(pricingJSON) > 0 {
+		if err := json.Unmarshal(pricingJSON, &decision.Pricing); err != nil {
+			logger.Error("Failed to unmarshal pricing", zap.Error(err))
+			return nil, fmt.Errorf("failed to unmarshal pricing: %w", err)
+		}
+	}
+	// Decisions made before rules were staged have no stage results
+	if len(stagesJSON) > 0 {
+		if err := json.Unmarshal(stagesJSON, &decision.Stages); err != nil {
+			logger.Error("Failed to unmarshal stages", zap.Error(err))
+			return nil, fmt.Errorf("failed to unmarshal stages: %w", err)
+		}
+	}
+	// Only provisional decisions have pending sources
+	if len(pendingSourcesJSON) > 0 {
+		if err := json.Unmarshal(pendingSourcesJSON, &decision.PendingSources); err != nil {
+			logger.Error("Failed to unmarshal pending sources", zap.Error(err))
+			return nil, fmt.Errorf("failed to unmarshal pending sources: %w", err)
+		}
+		decision.Provisional = len(decision.PendingSources) > 0
+	}
+
 	logger.Info("Decision retrieved successfully")
 	return &decision, nil
 }
 
-// SaveDecisionRequest saves the original decision request
+// GetDecisionInputs retrieves the request a decision was made on. Decisions saved before their own
+// request was kept fall back to the latest request stored for the application, rebuilt from its
+// columns when it has no payload either.
+func (r *DecisionRepository) GetDecisionInputs(ctx context.Context, decisionID int64) (*domain.DecisionInputs, error) {
+	logger := r.logger.With(
+		zap.Int64("decision_id", decisionID),
+		zap.String("operation", "get_decision_inputs"),
+	)
+
+	query := `
+		SELECT d.request_payload, dr.request_payload, d.application_id, COALESCE(dr.user_id, ''),
+			   COALESCE(dr.customer_id, ''), COALESCE(dr.loan_amount, 0), COALESCE(dr.annual_income, 0),
+			   COALESCE(dr.monthly_income, 0), COALESCE(dr.monthly_debt, 0), COALESCE(dr.credit_score, 0),
+			   COALESCE(dr.employment_type, ''), COALESCE(dr.requested_term, 0),
+			   COALESCE(dr.loan_term_months, 0), COALESCE(dr.loan_purpose, ''), dr.requested_at
+		FROM decisions d
+		LEFT JOIN decision_requests dr ON dr.application_id = d.application_id
+		WHERE d.id = $1`
+
+	var (
+		inputs                                  domain.DecisionInputs
+		decisionPayloadJSON, requestPayloadJSON []byte
+		requestedAt                             sql.NullTime
+	)
+	request := &inputs.Request
+	err := r.db.QueryRowContext(ctx, query, decisionID).Scan(
+		&decisionPayloadJSON,
+		&requestPayloadJSON,
+		&request.ApplicationID,
+		&request.UserID,
+		&request.CustomerID,
+		&request.LoanAmount,
+		&request.AnnualIncome,
+		&request.MonthlyIncome,
+		&request.MonthlyDebt,
+		&request.CreditScore,
+		&request.EmploymentType,
+		&request.RequestedTerm,
+		&request.LoanTermMonths,
+		&request.LoanPurpose,
+		&requestedAt,
+	)
+	if err == sql.ErrNoRows {
+		logger.Info("No decision found")
+		return nil, fmt.Errorf("decision not found: %d", decisionID)
+	}
+	if err != nil {
+		logger.Error("Failed to retrieve decision inputs", zap.Error(err))
+		return nil, fmt.Errorf("failed to retrieve decision inputs: %w", err)
+	}
+
+	if len(decisionPayloadJSON) > 0 {
+		inputs.Source = domain.ReplayInputsDecision
+		inputs.Request = domain.DecisionRequest{}
+		if err := json.Unmarshal(decisionPayloadJSON, &inputs.Request); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal decision request: %w", err)
+		}
+		return &inputs, nil
+	}
+
+	if !requestedAt.Valid {
+		logger.Info("No decision request stored for decision")
+		return nil, fmt.Errorf("decision request not found for decision %d", decisionID)
+	}
+	inputs.Source = domain.ReplayInputsApplication
+	request.RequestedAt = requestedAt.Time
+	if len(requestPayloadJSON) > 0 {
+		if err := json.Unmarshal(requestPayloadJSON, request); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal decision request: %w", err)
+		}
+	}
+	return &inputs, nil
+}
+
+// SaveDecisionRequest saves the original decision request, replacing any earlier request for the
+// application. The full request is kept in request_payload so it can be replayed by rule
+// simulations.
 func (r *DecisionRepository) SaveDecisionRequest(ctx context.Context, request *domain.DecisionRequest) error {
 	logger := r.logger.With(
 		zap.String("application_id", request.ApplicationID),
@@ -162,31 +453,17 @@ func (r *DecisionRepository) SaveDecisionRequest(ctx context.Context, request *d
 
 	logger.Info("Saving decision request to database")
 
-	query := `
-		INSERT INTO decision_requests (
-			application_id, customer_id, loan_amount, loan_purpose, 
-			loan_term_months, annual_income, monthly_income, credit_score,
-			employment_type, requested_amount, created_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
-		) RETURNING id`
+	args, err := decisionRequestArgs(request)
+	if err != nil {
+		logger.Error("Failed to marshal decision request", zap.Error(err))
+		return err
+	}
 
 	var requestID int64
-	err := r.db.QueryRowContext(ctx, query,
-		request.ApplicationID,
-		request.CustomerID,
-		request.LoanAmount,
-		request.LoanPurpose,
-		request.LoanTermMonths,
-		request.AnnualIncome,
-		request.MonthlyIncome,
-		request.CreditScore,
-		request.EmploymentType,
-		request.LoanAmount, // requested_amount same as loan_amount for now
-		time.Now(),
-	).Scan(&requestID)
-
-	if err != nil {
+	if err := r.db.QueryRowContext(ctx, upsertDecisionRequestQuery, args...).Scan(&requestID); err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("application %s belongs to another lending program", request.ApplicationID)
+		}
 		logger.Error("Failed to save decision request", zap.Error(err))
 		return fmt.Errorf("failed to save decision request: %w", err)
 	}
@@ -195,6 +472,71 @@ func (r *DecisionRepository) SaveDecisionRequest(ctx context.Context, request *d
 	return nil
 }
 
+const upsertDecisionRequestQuery = `
+	INSERT INTO decision_requests (
+		application_id, user_id, customer_id, loan_amount, annual_income,
+		monthly_income, monthly_debt, credit_score, employment_type,
+		requested_term, loan_term_months, loan_purpose, additional_data,
+		request_payload, requested_at, tenant_id
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+	)
+	ON CONFLICT (application_id) DO UPDATE SET
+		user_id = EXCLUDED.user_id,
+		customer_id = EXCLUDED.customer_id,
+		loan_amount = EXCLUDED.loan_amount,
+		annual_income = EXCLUDED.annual_income,
+		monthly_income = EXCLUDED.monthly_income,
+		monthly_debt = EXCLUDED.monthly_debt,
+		credit_score = EXCLUDED.credit_score,
+		employment_type = EXCLUDED.employment_type,
+		requested_term = EXCLUDED.requested_term,
+		loan_term_months = EXCLUDED.loan_term_months,
+		loan_purpose = EXCLUDED.loan_purpose,
+		additional_data = EXCLUDED.additional_data,
+		request_payload = EXCLUDED.request_payload,
+		requested_at = EXCLUDED.requested_at
+	WHERE decision_requests.tenant_id = EXCLUDED.tenant_id
+	RETURNING id`
+
+// decisionRequestArgs serializes a decision request into the arguments of
+// upsertDecisionRequestQuery
+func decisionRequestArgs(request *domain.DecisionRequest) ([]interface{}, error) {
+	additionalDataJSON, err := json.Marshal(request.AdditionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal additional data: %w", err)
+	}
+
+	payloadJSON, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal decision request: %w", err)
+	}
+
+	requestedAt := request.RequestedAt
+	if requestedAt.IsZero() {
+		requestedAt = time.Now()
+	}
+
+	return []interface{}{
+		request.ApplicationID,
+		request.UserID,
+		nullString(request.CustomerID),
+		request.LoanAmount,
+		request.AnnualIncome,
+		request.MonthlyIncome,
+		request.MonthlyDebt,
+		request.CreditScore,
+		request.EmploymentType,
+		request.RequestedTerm,
+		request.LoanTermMonths,
+		request.LoanPurpose,
+		additionalDataJSON,
+		payloadJSON,
+		requestedAt,
+		request.Tenant(),
+	}, nil
+}
+
 // GetDecisionHistory retrieves decision history for a customer
 func (r *DecisionRepository) GetDecisionHistory(ctx context.Context, customerID string, limit int) ([]*domain.DecisionResponse, error) {
 	logger := r.logger.With(
@@ -208,7 +550,7 @@ func (r *DecisionRepository) GetDecisionHistory(ctx context.Context, customerID
 	query := `
 		SELECT d.application_id, d.decision, d.confidence_score, d.interest_rate,
 			   d.max_amount, d.reason, d.risk_assessment, d.applied_rules,
-			   d.recommendations, d.decision_date, d.created_at
+			   d.recommendations, d.rule_versions, d.decision_date, d.created_at
 		FROM decisions d
 		JOIN decision_requests dr ON d.application_id = dr.application_id
 		WHERE dr.customer_id = $1
@@ -226,7 +568,7 @@ func (r *DecisionRepository) GetDecisionHistory(ctx context.Context, customerID
 
 	for rows.Next() {
 		var decision domain.DecisionResponse
-		var riskAssessmentJSON, appliedRulesJSON, recommendationsJSON []byte
+		var riskAssessmentJSON, appliedRulesJSON, recommendationsJSON, ruleVersionsJSON []byte
 		var createdAt time.Time
 
 		err := rows.Scan(
@@ -239,6 +581,7 @@ func (r *DecisionRepository) GetDecisionHistory(ctx context.Context, customerID
 			&riskAssessmentJSON,
 			&appliedRulesJSON,
 			&recommendationsJSON,
+			&ruleVersionsJSON,
 			&decision.DecisionDate,
 			&createdAt,
 		)
@@ -264,6 +607,13 @@ func (r *DecisionRepository) GetDecisionHistory(ctx context.Context, customerID
 			continue
 		}
 
+		if len(ruleVersionsJSON) > 0 {
+			if err := json.Unmarshal(ruleVersionsJSON, &decision.RuleVersions); err != nil {
+				logger.Error("Failed to unmarshal rule versions", zap.Error(err))
+				continue
+			}
+		}
+
 		decisions = append(decisions, &decision)
 	}
 
@@ -276,6 +626,111 @@ func (r *DecisionRepository) GetDecisionHistory(ctx context.Context, customerID
 	return decisions, nil
 }
 
+// GetHistoricalDecisions returns the most recent decision requests made in the query window, with
+// the latest decision made on each
+func (r *DecisionRepository) GetHistoricalDecisions(ctx context.Context, query domain.DecisionHistoryQuery) ([]domain.HistoricalDecision, error) {
+	logger := r.logger.With(
+		zap.Time("from", query.From),
+		zap.Time("to", query.To),
+		zap.Int("limit", query.Limit),
+		zap.String("operation", "get_historical_decisions"),
+	)
+
+	logger.Info("Retrieving historical decision requests")
+
+	sqlQuery := `
+		SELECT dr.request_payload, dr.application_id, dr.user_id, COALESCE(dr.customer_id, ''),
+			   dr.loan_amount, dr.annual_income, dr.monthly_income, COALESCE(dr.monthly_debt, 0),
+			   dr.credit_score, dr.employment_type, dr.requested_term,
+			   COALESCE(dr.loan_term_months, 0), dr.loan_purpose, dr.requested_at,
+			   d.decision, d.max_amount, d.rule_versions, d.decision_date
+		FROM decision_requests dr
+		LEFT JOIN LATERAL (
+			SELECT decision, max_amount, rule_versions, decision_date
+			FROM decisions
+			WHERE application_id = dr.application_id
+			ORDER BY created_at DESC
+			LIMIT 1
+		) d ON true
+		WHERE dr.requested_at >= $1 AND dr.requested_at < $2 AND dr.tenant_id = $4
+		ORDER BY dr.requested_at DESC
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, query.From, query.To, query.Limit, domain.TenantOrDefault(query.TenantID))
+	if err != nil {
+		logger.Error("Failed to query historical decision requests", zap.Error(err))
+		return nil, fmt.Errorf("failed to query historical decision requests: %w", err)
+	}
+	defer rows.Close()
+
+	var history []domain.HistoricalDecision
+
+	for rows.Next() {
+		var historical domain.HistoricalDecision
+		var request domain.DecisionRequest
+		var payloadJSON, ruleVersionsJSON []byte
+		var decision sql.NullString
+		var maxAmount sql.NullFloat64
+		var decisionDate sql.NullTime
+
+		err := rows.Scan(
+			&payloadJSON,
+			&request.ApplicationID,
+			&request.UserID,
+			&request.CustomerID,
+			&request.LoanAmount,
+			&request.AnnualIncome,
+			&request.MonthlyIncome,
+			&request.MonthlyDebt,
+			&request.CreditScore,
+			&request.EmploymentType,
+			&request.RequestedTerm,
+			&request.LoanTermMonths,
+			&request.LoanPurpose,
+			&request.RequestedAt,
+			&decision,
+			&maxAmount,
+			&ruleVersionsJSON,
+			&decisionDate,
+		)
+
+		if err != nil {
+			logger.Error("Failed to scan historical decision row", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan historical decision row: %w", err)
+		}
+
+		// Requests saved before request_payload was recorded are rebuilt from their columns
+		historical.Request = request
+		if len(payloadJSON) > 0 {
+			if err := json.Unmarshal(payloadJSON, &historical.Request); err != nil {
+				logger.Error("Failed to unmarshal decision request", zap.String("application_id", request.ApplicationID), zap.Error(err))
+				continue
+			}
+		}
+
+		historical.Decision = domain.DecisionType(decision.String)
+		historical.MaxAmount = maxAmount.Float64
+		if decisionDate.Valid {
+			historical.DecisionDate = &decisionDate.Time
+		}
+		if len(ruleVersionsJSON) > 0 {
+			if err := json.Unmarshal(ruleVersionsJSON, &historical.RuleVersions); err != nil {
+				logger.Error("Failed to unmarshal rule versions", zap.String("application_id", request.ApplicationID), zap.Error(err))
+			}
+		}
+
+		history = append(history, historical)
+	}
+
+	if err = rows.Err(); err != nil {
+		logger.Error("Error iterating over historical decision rows", zap.Error(err))
+		return nil, fmt.Errorf("error iterating over historical decision rows: %w", err)
+	}
+
+	logger.Info("Historical decision requests retrieved", zap.Int("count", len(history)))
+	return history, nil
+}
+
 // GetDecisionStatistics retrieves statistics about decisions
 func (r *DecisionRepository) GetDecisionStatistics(ctx context.Context, dateFrom, dateTo time.Time) (*domain.DecisionStatistics, error) {
 	logger := r.logger.With(
@@ -351,19 +806,25 @@ func (r *DecisionRepository) InitializeDatabase(ctx context.Context) error {
 		CREATE TABLE IF NOT EXISTS decision_requests (
 			id BIGSERIAL PRIMARY KEY,
 			application_id VARCHAR(255) UNIQUE NOT NULL,
-			customer_id VARCHAR(255) NOT NULL,
+			user_id VARCHAR(255) NOT NULL,
+			customer_id VARCHAR(255),
 			loan_amount DECIMAL(15,2) NOT NULL,
-			loan_purpose VARCHAR(100) NOT NULL,
-			loan_term_months INTEGER NOT NULL,
 			annual_income DECIMAL(15,2) NOT NULL,
 			monthly_income DECIMAL(15,2) NOT NULL,
+			monthly_debt DECIMAL(15,2) DEFAULT 0,
 			credit_score INTEGER NOT NULL,
 			employment_type VARCHAR(50) NOT NULL,
-			requested_amount DECIMAL(15,2) NOT NULL,
-			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			requested_term INTEGER NOT NULL,
+			loan_term_months INTEGER,
+			loan_purpose VARCHAR(100) NOT NULL,
+			tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+			additional_data JSONB,
+			request_payload JSONB,
+			requested_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
 			INDEX idx_customer_id (customer_id),
 			INDEX idx_application_id (application_id),
-			INDEX idx_created_at (created_at)
+			INDEX idx_requested_at (requested_at)
 		)`
 
 	// Create decisions table
@@ -379,6 +840,14 @@ func (r *DecisionRepository) InitializeDatabase(ctx context.Context) error {
 			risk_assessment JSONB,
 			applied_rules JSONB,
 			recommendations JSONB,
+			rule_versions JSONB,
+			rule_hits JSONB,
+			adverse_action_reasons JSONB,
+			pricing JSONB,
+			policy_version VARCHAR(20),
+			stages JSONB,
+			pending_sources JSONB,
+			request_payload JSONB,
 			decision_date TIMESTAMP WITH TIME ZONE NOT NULL,
 			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
 			INDEX idx_application_id (application_id),
@@ -429,5 +898,5 @@ func (r *DecisionRepository) GetDecisionHistoryByUser(userID string) ([]domain.D
 func (r *DecisionRepository) UpdateDecision(response *domain.DecisionResponse) error {
 	// Implementation would update an existing decision
 	// For now, just save it as new
-	return r.SaveDecision(context.Background(), response)
+	return r.SaveDecision(context.Background(), response, nil)
 }
@@ -0,0 +1,176 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// FraudVendorConfig holds configuration for the fraud vendor
+type FraudVendorConfig struct {
+	Vendor     string // vendor name recorded with each screening, e.g. sift or sentilink
+	Endpoint   string
+	APIKey     string
+	Timeout    time.Duration
+	ScoreScale float64 // the vendor's maximum score, e.g. 100 or 1000; scores are divided by it
+}
+
+// FraudVendorClient screens applications with a Sift/SentiLink-style fraud vendor that scores
+// device, identity and synthetic-identity risk and explains the score with reason codes
+type FraudVendorClient struct {
+	config      FraudVendorConfig
+	detokenizer domain.PIIDetokenizer
+	httpClient  *http.Client
+	logger      *zap.Logger
+}
+
+// NewFraudVendorClient creates a new fraud vendor client; detokenizer may be nil to screen
+// without the applicant's SSN
+func NewFraudVendorClient(config FraudVendorConfig, detokenizer domain.PIIDetokenizer, logger *zap.Logger) *FraudVendorClient {
+	if config.ScoreScale <= 0 {
+		config.ScoreScale = 1
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	return &FraudVendorClient{
+		config:      config,
+		detokenizer: detokenizer,
+		httpClient:  &http.Client{Timeout: config.Timeout},
+		logger:      logger,
+	}
+}
+
+// fraudVendorRequest is the vendor's scoring request
+type fraudVendorRequest struct {
+	ApplicationID string  `json:"application_id"`
+	UserID        string  `json:"user_id"`
+	Amount        float64 `json:"amount"`
+	Device        struct {
+		ID        string `json:"id,omitempty"`
+		IPAddress string `json:"ip_address,omitempty"`
+	} `json:"device"`
+	Identity struct {
+		Email string `json:"email,omitempty"`
+		Phone string `json:"phone,omitempty"`
+		SSN   string `json:"ssn,omitempty"`
+	} `json:"identity"`
+}
+
+// fraudVendorResponse is the vendor's score and the signals behind it, on the vendor's scale
+type fraudVendorResponse struct {
+	Score   float64 `json:"score"`
+	Reasons []struct {
+		Code        string  `json:"code"`
+		Category    string  `json:"category"`
+		Description string  `json:"description"`
+		Score       float64 `json:"score"`
+	} `json:"reasons"`
+}
+
+// Screen scores an application with the fraud vendor
+func (c *FraudVendorClient) Screen(ctx context.Context, request *domain.FraudScreeningRequest) (*domain.FraudScreeningResult, error) {
+	logger := c.logger.With(
+		zap.String("application_id", request.ApplicationID),
+		zap.String("vendor", c.config.Vendor),
+		zap.String("operation", "fraud_screen"),
+	)
+
+	payload := fraudVendorRequest{
+		ApplicationID: request.ApplicationID,
+		UserID:        request.UserID,
+		Amount:        request.LoanAmount,
+	}
+	payload.Device.ID = request.DeviceID
+	payload.Device.IPAddress = request.IPAddress
+	payload.Identity.Email = request.Email
+	payload.Identity.Phone = request.Phone
+	if request.SSNToken != "" && c.detokenizer != nil {
+		ssn, err := c.detokenizer.Detokenize(ctx, request.SSNToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve SSN token for fraud screening: %w", err)
+		}
+		payload.Identity.SSN = ssn
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode fraud screening request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.Endpoint+"/v1/score", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build fraud screening request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Fraud vendor request failed", zap.Error(err))
+		return nil, fmt.Errorf("failed to call fraud vendor: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("Unexpected fraud vendor response", zap.Int("status", resp.StatusCode))
+		return nil, fmt.Errorf("unexpected fraud vendor status: %d", resp.StatusCode)
+	}
+
+	var scored fraudVendorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&scored); err != nil {
+		return nil, fmt.Errorf("failed to decode fraud vendor response: %w", err)
+	}
+
+	result := &domain.FraudScreeningResult{
+		Vendor:     c.config.Vendor,
+		Score:      c.normalize(scored.Score),
+		RawScore:   scored.Score,
+		ScreenedAt: time.Now(),
+	}
+	for _, reason := range scored.Reasons {
+		result.Signals = append(result.Signals, domain.FraudSignal{
+			Category:    fraudSignalCategory(reason.Category),
+			Code:        reason.Code,
+			Description: reason.Description,
+			Score:       c.normalize(reason.Score),
+		})
+		result.ReasonCodes = append(result.ReasonCodes, reason.Code)
+	}
+
+	logger.Debug("Fraud vendor scored application",
+		zap.Float64("raw_score", result.RawScore),
+		zap.Float64("fraud_score", result.Score),
+	)
+	return result, nil
+}
+
+// normalize maps a score on the vendor's scale to 0 (clean) to 1
+func (c *FraudVendorClient) normalize(score float64) float64 {
+	normalized := score / c.config.ScoreScale
+	if normalized < 0 {
+		return 0
+	}
+	if normalized > 1 {
+		return 1
+	}
+	return normalized
+}
+
+// fraudSignalCategory maps a vendor's reason category onto a signal category; reasons the vendor
+// does not categorize are treated as identity signals
+func fraudSignalCategory(category string) domain.FraudSignalCategory {
+	switch strings.ToLower(category) {
+	case "device", "ip", "velocity":
+		return domain.FraudSignalDevice
+	case "synthetic", "synthetic_identity", "synthetic_fraud":
+		return domain.FraudSignalSynthetic
+	default:
+		return domain.FraudSignalIdentity
+	}
+}
@@ -0,0 +1,133 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// LoanServiceClient reads application state over the loan service's internal API
+type LoanServiceClient struct {
+	baseURL      string
+	serviceToken string
+	httpClient   *http.Client
+	logger       *zap.Logger
+}
+
+// NewLoanServiceClient creates a new loan service client
+func NewLoanServiceClient(baseURL, serviceToken string, timeout time.Duration, logger *zap.Logger) *LoanServiceClient {
+	return &LoanServiceClient{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		serviceToken: serviceToken,
+		httpClient:   &http.Client{Timeout: timeout},
+		logger:       logger,
+	}
+}
+
+// GetCreditPullEligibility asks the loan service whether an application may trigger a hard
+// credit pull; an unknown application returns nil
+func (c *LoanServiceClient) GetCreditPullEligibility(ctx context.Context, applicationID string) (*domain.CreditPullEligibility, error) {
+	logger := c.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "get_credit_pull_eligibility"),
+	)
+
+	endpoint := fmt.Sprintf("%s/internal/v1/applications/%s/credit-pull-eligibility", c.baseURL, url.PathEscape(applicationID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build credit pull eligibility request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.serviceToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Loan service request failed", zap.Error(err))
+		return nil, fmt.Errorf("failed to call loan service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		logger.Info("Application not found")
+		return nil, nil
+	default:
+		logger.Error("Unexpected loan service response", zap.Int("status", resp.StatusCode))
+		return nil, fmt.Errorf("unexpected credit pull eligibility status: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data domain.CreditPullEligibility `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode credit pull eligibility response: %w", err)
+	}
+
+	logger.Debug("Credit pull eligibility retrieved",
+		zap.String("state", result.Data.State),
+		zap.Bool("eligible", result.Data.Eligible),
+	)
+	return &result.Data, nil
+}
+
+// redecisionNotice is what the loan service is told of a re-decision
+type redecisionNotice struct {
+	DecisionID   int64               `json:"decision_id"`
+	Decision     domain.DecisionType `json:"decision"`
+	RiskCategory domain.RiskCategory `json:"risk_category"`
+	Reason       string              `json:"reason"`
+	Provisional  bool                `json:"provisional"`
+	DecidedAt    time.Time           `json:"decided_at"`
+}
+
+// NotifyRedecision tells the loan service an application was re-decided with late data
+func (c *LoanServiceClient) NotifyRedecision(ctx context.Context, decision *domain.DecisionResponse) error {
+	logger := c.logger.With(
+		zap.String("application_id", decision.ApplicationID),
+		zap.String("operation", "notify_redecision"),
+	)
+
+	body, err := json.Marshal(redecisionNotice{
+		DecisionID:   decision.DecisionID,
+		Decision:     decision.Decision,
+		RiskCategory: decision.RiskCategory,
+		Reason:       decision.Reason,
+		Provisional:  decision.Provisional,
+		DecidedAt:    decision.DecisionDate,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode re-decision: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/internal/v1/applications/%s/redecisions", c.baseURL, url.PathEscape(decision.ApplicationID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build re-decision request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.serviceToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Loan service request failed", zap.Error(err))
+		return fmt.Errorf("failed to call loan service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("Unexpected loan service response", zap.Int("status", resp.StatusCode))
+		return fmt.Errorf("unexpected re-decision status: %d", resp.StatusCode)
+	}
+
+	logger.Info("Loan service notified of re-decision", zap.String("decision", string(decision.Decision)))
+	return nil
+}
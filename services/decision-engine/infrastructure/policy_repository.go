@@ -0,0 +1,273 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+const policyColumns = `version, name, description, status, thresholds, effective_from, created_by, submitted_by,
+	submitted_at, reviewed_by, reviewed_at, review_comment, retired_at, created_at, updated_at, tenant_id`
+
+// PolicyRepository implements versioned underwriting policy persistence. Versions are numbered
+// per lending program. Every change is appended to underwriting_policy_events in the same
+// transaction.
+type PolicyRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewPolicyRepository creates a new policy repository
+func NewPolicyRepository(db *sql.DB, logger *zap.Logger) *PolicyRepository {
+	return &PolicyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListPolicies returns the policy versions with a status, or every version when status is empty,
+// oldest first. An empty tenant lists the versions of every lending program.
+func (r *PolicyRepository) ListPolicies(ctx context.Context, tenant string, status domain.PolicyStatus) ([]domain.UnderwritingPolicy, error) {
+	query := `SELECT ` + policyColumns + ` FROM underwriting_policies WHERE ($1 = '' OR tenant_id = $1)`
+	args := []interface{}{tenant}
+	if status != "" {
+		query += ` AND status = $2`
+		args = append(args, status)
+	}
+	policies, err := r.queryPolicies(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(policies, func(i, j int) bool {
+		if policies[i].TenantID != policies[j].TenantID {
+			return policies[i].TenantID < policies[j].TenantID
+		}
+		return domain.CompareVersions(policies[i].Version, policies[j].Version) < 0
+	})
+	return policies, nil
+}
+
+// GetPolicy returns a single policy version of a lending program
+func (r *PolicyRepository) GetPolicy(ctx context.Context, tenant, version string) (*domain.UnderwritingPolicy, error) {
+	policies, err := r.queryPolicies(ctx, `SELECT `+policyColumns+` FROM underwriting_policies WHERE tenant_id = $1 AND version = $2`, tenant, version)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, fmt.Errorf("policy version not found: %s", version)
+	}
+	return &policies[0], nil
+}
+
+// CreatePolicy inserts a new draft version
+func (r *PolicyRepository) CreatePolicy(ctx context.Context, policy *domain.UnderwritingPolicy) error {
+	return r.inTx(ctx, "create_policy", policy.Version, func(tx *sql.Tx) error {
+		thresholdsJSON, err := json.Marshal(policy.Thresholds)
+		if err != nil {
+			return fmt.Errorf("failed to marshal policy thresholds: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO underwriting_policies (`+policyColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`,
+			policy.Version, policy.Name, nullString(policy.Description), policy.Status, thresholdsJSON,
+			policy.EffectiveFrom, policy.CreatedBy, nullString(policy.SubmittedBy), policy.SubmittedAt,
+			nullString(policy.ReviewedBy), policy.ReviewedAt, nullString(policy.ReviewComment), policy.RetiredAt,
+			policy.CreatedAt, policy.UpdatedAt, policy.TenantID,
+		); err != nil {
+			return fmt.Errorf("failed to create policy version: %w", err)
+		}
+
+		return recordPolicyEvent(ctx, tx, policy, domain.PolicyEventCreated, policy.CreatedBy, "", policy.CreatedAt)
+	})
+}
+
+// UpdatePolicy saves a version's definition, status and review if it still has the expected
+// status, recording event
+func (r *PolicyRepository) UpdatePolicy(ctx context.Context, policy *domain.UnderwritingPolicy, expected domain.PolicyStatus, event domain.PolicyEventType, performedBy, comment string) error {
+	return r.inTx(ctx, "update_policy", policy.Version, func(tx *sql.Tx) error {
+		thresholdsJSON, err := json.Marshal(policy.Thresholds)
+		if err != nil {
+			return fmt.Errorf("failed to marshal policy thresholds: %w", err)
+		}
+
+		result, err := tx.ExecContext(ctx, `
+			UPDATE underwriting_policies
+			SET name = $2, description = $3, status = $4, thresholds = $5, effective_from = $6, submitted_by = $7,
+				submitted_at = $8, reviewed_by = $9, reviewed_at = $10, review_comment = $11, retired_at = $12,
+				updated_at = $13
+			WHERE version = $1 AND status = $14 AND tenant_id = $15`,
+			policy.Version, policy.Name, nullString(policy.Description), policy.Status, thresholdsJSON,
+			policy.EffectiveFrom, nullString(policy.SubmittedBy), policy.SubmittedAt, nullString(policy.ReviewedBy),
+			policy.ReviewedAt, nullString(policy.ReviewComment), policy.RetiredAt, policy.UpdatedAt, expected,
+			policy.TenantID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update policy version: %w", err)
+		}
+		if err := expectPolicyRow(result, policy.Version); err != nil {
+			return err
+		}
+
+		return recordPolicyEvent(ctx, tx, policy, event, performedBy, comment, policy.UpdatedAt)
+	})
+}
+
+// DeleteDraft discards a draft version of a lending program
+func (r *PolicyRepository) DeleteDraft(ctx context.Context, tenant, version, performedBy string) error {
+	return r.inTx(ctx, "delete_policy_draft", version, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM underwriting_policies WHERE tenant_id = $1 AND version = $2 AND status = $3`,
+			tenant, version, domain.PolicyStatusDraft)
+		if err != nil {
+			return fmt.Errorf("failed to delete policy draft: %w", err)
+		}
+		if err := expectPolicyRow(result, version); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO underwriting_policy_events (tenant_id, version, event, snapshot, comment, performed_by, created_at)
+			VALUES ($1, $2, $3, NULL, NULL, $4, $5)`,
+			tenant, version, domain.PolicyEventDiscarded, performedBy, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("failed to record policy event: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetPolicyEvents returns the history of a lending program's policy version, oldest first
+func (r *PolicyRepository) GetPolicyEvents(ctx context.Context, tenant, version string) ([]domain.PolicyEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, version, event, snapshot, comment, performed_by, created_at
+		FROM underwriting_policy_events
+		WHERE tenant_id = $1 AND version = $2
+		ORDER BY created_at, id`, tenant, version)
+	if err != nil {
+		r.logger.Error("Failed to query policy events", zap.String("policy_version", version), zap.Error(err))
+		return nil, fmt.Errorf("failed to query policy events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]domain.PolicyEvent, 0)
+	for rows.Next() {
+		var event domain.PolicyEvent
+		var snapshotJSON []byte
+		var comment sql.NullString
+		if err := rows.Scan(&event.ID, &event.Version, &event.Event, &snapshotJSON, &comment,
+			&event.PerformedBy, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan policy event: %w", err)
+		}
+		event.Comment = comment.String
+		if len(snapshotJSON) > 0 {
+			if err := json.Unmarshal(snapshotJSON, &event.Snapshot); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal policy event snapshot: %w", err)
+			}
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over policy events: %w", err)
+	}
+	return events, nil
+}
+
+// inTx runs fn in a transaction, logging failures
+func (r *PolicyRepository) inTx(ctx context.Context, operation, version string, fn func(tx *sql.Tx) error) error {
+	logger := r.logger.With(
+		zap.String("policy_version", version),
+		zap.String("operation", operation),
+	)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		logger.Error("Policy operation failed", zap.Error(err))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit transaction", zap.Error(err))
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// queryPolicies runs a policy query and decodes the rows
+func (r *PolicyRepository) queryPolicies(ctx context.Context, query string, args ...interface{}) ([]domain.UnderwritingPolicy, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to query policies", zap.Error(err))
+		return nil, fmt.Errorf("failed to query policies: %w", err)
+	}
+	defer rows.Close()
+
+	policies := make([]domain.UnderwritingPolicy, 0)
+	for rows.Next() {
+		var policy domain.UnderwritingPolicy
+		var thresholdsJSON []byte
+		var description, submittedBy, reviewedBy, reviewComment sql.NullString
+
+		if err := rows.Scan(
+			&policy.Version, &policy.Name, &description, &policy.Status, &thresholdsJSON, &policy.EffectiveFrom,
+			&policy.CreatedBy, &submittedBy, &policy.SubmittedAt, &reviewedBy, &policy.ReviewedAt,
+			&reviewComment, &policy.RetiredAt, &policy.CreatedAt, &policy.UpdatedAt, &policy.TenantID,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan policy row: %w", err)
+		}
+		policy.Description = description.String
+		policy.SubmittedBy = submittedBy.String
+		policy.ReviewedBy = reviewedBy.String
+		policy.ReviewComment = reviewComment.String
+
+		if err := json.Unmarshal(thresholdsJSON, &policy.Thresholds); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal thresholds of policy %s: %w", policy.Version, err)
+		}
+		policies = append(policies, policy)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over policy rows: %w", err)
+	}
+	return policies, nil
+}
+
+// recordPolicyEvent appends a change to the policy history
+func recordPolicyEvent(ctx context.Context, tx *sql.Tx, policy *domain.UnderwritingPolicy, event domain.PolicyEventType, performedBy, comment string, at time.Time) error {
+	snapshotJSON, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("failed to marshal policy snapshot: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO underwriting_policy_events (tenant_id, version, event, snapshot, comment, performed_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		policy.TenantID, policy.Version, event, snapshotJSON, nullString(comment), performedBy, at,
+	); err != nil {
+		return fmt.Errorf("failed to record policy event: %w", err)
+	}
+	return nil
+}
+
+// expectPolicyRow maps an update or delete that matched nothing to a not-found error
+func expectPolicyRow(result sql.Result, version string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("policy version not found: %s", version)
+	}
+	return nil
+}
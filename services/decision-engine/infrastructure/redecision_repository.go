@@ -0,0 +1,111 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// RedecisionRepository implements the outbox of re-decisions the loan service is still to be told of
+type RedecisionRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewRedecisionRepository creates a new re-decision notification repository
+func NewRedecisionRepository(db *sql.DB, logger *zap.Logger) *RedecisionRepository {
+	return &RedecisionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// EnqueueRedecisionNotification queues a re-decision; queuing one already queued changes nothing
+func (r *RedecisionRepository) EnqueueRedecisionNotification(ctx context.Context, decisionID int64, applicationID string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO redecision_notifications (decision_id, application_id, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (decision_id) DO NOTHING`,
+		decisionID, applicationID, at,
+	)
+	if err != nil {
+		r.logger.Error("Failed to queue re-decision notification",
+			zap.Int64("decision_id", decisionID),
+			zap.Error(err))
+		return fmt.Errorf("failed to queue re-decision notification: %w", err)
+	}
+	return nil
+}
+
+// ClaimDueRedecisionNotifications uses SKIP LOCKED so instances never claim the same notification
+func (r *RedecisionRepository) ClaimDueRedecisionNotifications(ctx context.Context, limit int, lease time.Duration) ([]domain.RedecisionNotification, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE redecision_notifications
+		SET next_attempt_at = NOW() + $2 * INTERVAL '1 second'
+		WHERE decision_id IN (
+			SELECT decision_id FROM redecision_notifications
+			WHERE delivered_at IS NULL AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING decision_id, application_id, attempts, created_at`,
+		limit, int(lease.Seconds()),
+	)
+	if err != nil {
+		r.logger.Error("Failed to claim re-decision notifications", zap.Error(err))
+		return nil, fmt.Errorf("failed to claim re-decision notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []domain.RedecisionNotification
+	for rows.Next() {
+		var notification domain.RedecisionNotification
+		if err := rows.Scan(&notification.DecisionID, &notification.ApplicationID, &notification.Attempts, &notification.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan re-decision notification: %w", err)
+		}
+		notifications = append(notifications, notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read re-decision notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// MarkRedecisionNotificationDelivered records that the loan service accepted a re-decision
+func (r *RedecisionRepository) MarkRedecisionNotificationDelivered(ctx context.Context, decisionID int64, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE redecision_notifications
+		SET delivered_at = $2, attempts = attempts + 1, last_error = NULL
+		WHERE decision_id = $1`,
+		decisionID, at,
+	)
+	if err != nil {
+		r.logger.Error("Failed to mark re-decision notification delivered",
+			zap.Int64("decision_id", decisionID),
+			zap.Error(err))
+		return fmt.Errorf("failed to mark re-decision notification delivered: %w", err)
+	}
+	return nil
+}
+
+// RescheduleRedecisionNotification records a failed attempt and when to try again
+func (r *RedecisionRepository) RescheduleRedecisionNotification(ctx context.Context, decisionID int64, lastError string, next time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE redecision_notifications
+		SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+		WHERE decision_id = $1 AND delivered_at IS NULL`,
+		decisionID, lastError, next,
+	)
+	if err != nil {
+		r.logger.Error("Failed to reschedule re-decision notification",
+			zap.Int64("decision_id", decisionID),
+			zap.Error(err))
+		return fmt.Errorf("failed to reschedule re-decision notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,428 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+const ruleColumns = `id, version, status, name, description, category, priority, expression, conditions, action, knockout,
+	effective_from, expires_at, created_by, published_by, published_at, retired_at, metadata, created_at, updated_at, tenant_id`
+
+// RulesRepository implements versioned decision rule persistence. Every change is appended to
+// decision_rule_events in the same transaction.
+type RulesRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewRulesRepository creates a new rules repository
+func NewRulesRepository(db *sql.DB, logger *zap.Logger) *RulesRepository {
+	return &RulesRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetPublishedRules returns every published rule version in evaluation order
+func (r *RulesRepository) GetPublishedRules(ctx context.Context) ([]domain.DecisionRule, error) {
+	return r.queryRules(ctx, `SELECT `+ruleColumns+` FROM decision_rules WHERE status = $1 ORDER BY priority, id`, domain.RuleStatusPublished)
+}
+
+// ListRuleVersions returns the rule versions with a status, or every version when status is empty,
+// ordered by rule and version
+func (r *RulesRepository) ListRuleVersions(ctx context.Context, status domain.RuleStatus) ([]domain.DecisionRule, error) {
+	var rules []domain.DecisionRule
+	var err error
+	if status == "" {
+		rules, err = r.queryRules(ctx, `SELECT `+ruleColumns+` FROM decision_rules`)
+	} else {
+		rules, err = r.queryRules(ctx, `SELECT `+ruleColumns+` FROM decision_rules WHERE status = $1`, status)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sortRuleVersions(rules)
+	return rules, nil
+}
+
+// GetRuleVersions returns every version of a rule, oldest first
+func (r *RulesRepository) GetRuleVersions(ctx context.Context, ruleID string) ([]domain.DecisionRule, error) {
+	rules, err := r.queryRules(ctx, `SELECT `+ruleColumns+` FROM decision_rules WHERE id = $1`, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	sortRuleVersions(rules)
+	return rules, nil
+}
+
+// GetRuleVersion returns a single rule version
+func (r *RulesRepository) GetRuleVersion(ctx context.Context, ruleID, version string) (*domain.DecisionRule, error) {
+	rules, err := r.queryRules(ctx, `SELECT `+ruleColumns+` FROM decision_rules WHERE id = $1 AND version = $2`, ruleID, version)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("rule version not found: %s@%s", ruleID, version)
+	}
+	return &rules[0], nil
+}
+
+// CreateRuleVersion inserts a new draft version
+func (r *RulesRepository) CreateRuleVersion(ctx context.Context, rule *domain.DecisionRule) error {
+	return r.inTx(ctx, "create_rule_version", rule.ID, func(tx *sql.Tx) error {
+		conditionsJSON, actionJSON, metadataJSON, err := marshalRule(rule)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO decision_rules (`+ruleColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)`,
+			rule.ID, rule.Version, rule.Status, rule.Name, rule.Description, rule.Category, rule.Priority,
+			rule.Expression, conditionsJSON, actionJSON, rule.Knockout, rule.EffectiveFrom, rule.ExpiresAt,
+			rule.CreatedBy, nullString(rule.PublishedBy), rule.PublishedAt, rule.RetiredAt, metadataJSON,
+			rule.CreatedAt, rule.UpdatedAt, rule.Tenant(),
+		); err != nil {
+			return fmt.Errorf("failed to create rule version: %w", err)
+		}
+
+		return recordRuleEvent(ctx, tx, rule, domain.RuleEventCreated, rule.CreatedBy, rule.CreatedAt)
+	})
+}
+
+// UpdateDraft replaces the definition of a draft version
+func (r *RulesRepository) UpdateDraft(ctx context.Context, rule *domain.DecisionRule, performedBy string) error {
+	return r.inTx(ctx, "update_draft", rule.ID, func(tx *sql.Tx) error {
+		conditionsJSON, actionJSON, metadataJSON, err := marshalRule(rule)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, `
+			UPDATE decision_rules
+			SET name = $3, description = $4, category = $5, priority = $6, expression = $7, conditions = $8,
+				action = $9, knockout = $10, effective_from = $11, expires_at = $12, metadata = $13, updated_at = $14
+			WHERE id = $1 AND version = $2 AND status = $15`,
+			rule.ID, rule.Version, rule.Name, rule.Description, rule.Category, rule.Priority, rule.Expression,
+			conditionsJSON, actionJSON, rule.Knockout, rule.EffectiveFrom, rule.ExpiresAt, metadataJSON,
+			rule.UpdatedAt, domain.RuleStatusDraft,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update draft: %w", err)
+		}
+		if err := expectOneRow(result, rule); err != nil {
+			return err
+		}
+
+		return recordRuleEvent(ctx, tx, rule, domain.RuleEventUpdated, performedBy, rule.UpdatedAt)
+	})
+}
+
+// DeleteDraft discards a draft version
+func (r *RulesRepository) DeleteDraft(ctx context.Context, ruleID, version, performedBy string) error {
+	rule := &domain.DecisionRule{ID: ruleID, Version: version}
+	return r.inTx(ctx, "delete_draft", ruleID, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM decision_rules WHERE id = $1 AND version = $2 AND status = $3`,
+			ruleID, version, domain.RuleStatusDraft)
+		if err != nil {
+			return fmt.Errorf("failed to delete draft: %w", err)
+		}
+		if err := expectOneRow(result, rule); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO decision_rule_events (rule_id, version, event, snapshot, performed_by, created_at)
+			VALUES ($1, $2, $3, NULL, $4, $5)`,
+			ruleID, version, domain.RuleEventDiscarded, performedBy, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("failed to record rule event: %w", err)
+		}
+		return nil
+	})
+}
+
+// PublishRuleVersion publishes a draft version. Published versions of the same rule taking effect
+// at or after it are retired, and the others expire when it takes effect, so exactly one version
+// of a rule is in force at any time.
+func (r *RulesRepository) PublishRuleVersion(ctx context.Context, rule *domain.DecisionRule) error {
+	return r.inTx(ctx, "publish_rule_version", rule.ID, func(tx *sql.Tx) error {
+		superseded, err := r.supersedePublished(ctx, tx, rule)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, `
+			UPDATE decision_rules
+			SET status = $3, effective_from = $4, published_by = $5, published_at = $6, updated_at = $6
+			WHERE id = $1 AND version = $2 AND status = $7`,
+			rule.ID, rule.Version, domain.RuleStatusPublished, rule.EffectiveFrom, rule.PublishedBy,
+			rule.PublishedAt, domain.RuleStatusDraft,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to publish rule version: %w", err)
+		}
+		if err := expectOneRow(result, rule); err != nil {
+			return err
+		}
+
+		for i := range superseded {
+			if err := recordRuleEvent(ctx, tx, &superseded[i], domain.RuleEventSuperseded, rule.PublishedBy, *rule.PublishedAt); err != nil {
+				return err
+			}
+		}
+		return recordRuleEvent(ctx, tx, rule, domain.RuleEventPublished, rule.PublishedBy, *rule.PublishedAt)
+	})
+}
+
+// supersedePublished retires or caps the expiry of the published versions a new version replaces
+// and returns them as changed
+func (r *RulesRepository) supersedePublished(ctx context.Context, tx *sql.Tx, rule *domain.DecisionRule) ([]domain.DecisionRule, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+ruleColumns+` FROM decision_rules
+		WHERE id = $1 AND version <> $2 AND status = $3
+		FOR UPDATE`,
+		rule.ID, rule.Version, domain.RuleStatusPublished)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query published versions: %w", err)
+	}
+	published, err := scanRules(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	effectiveFrom := *rule.EffectiveFrom
+	var superseded []domain.DecisionRule
+	for _, previous := range published {
+		switch {
+		case previous.EffectiveFrom != nil && !previous.EffectiveFrom.Before(effectiveFrom):
+			retiredAt := *rule.PublishedAt
+			previous.Status = domain.RuleStatusRetired
+			previous.RetiredAt = &retiredAt
+		case previous.ExpiresAt == nil || previous.ExpiresAt.After(effectiveFrom):
+			previous.ExpiresAt = &effectiveFrom
+		default:
+			continue
+		}
+		previous.UpdatedAt = *rule.PublishedAt
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE decision_rules SET status = $3, expires_at = $4, retired_at = $5, updated_at = $6
+			WHERE id = $1 AND version = $2`,
+			previous.ID, previous.Version, previous.Status, previous.ExpiresAt, previous.RetiredAt, previous.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to supersede rule version %s: %w", previous.VersionedID(), err)
+		}
+		superseded = append(superseded, previous)
+	}
+	return superseded, nil
+}
+
+// RetireRuleVersion retires a published version
+func (r *RulesRepository) RetireRuleVersion(ctx context.Context, ruleID, version, performedBy string, retiredAt time.Time) error {
+	return r.inTx(ctx, "retire_rule_version", ruleID, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE decision_rules SET status = $3, retired_at = $4, updated_at = $4
+			WHERE id = $1 AND version = $2 AND status = $5`,
+			ruleID, version, domain.RuleStatusRetired, retiredAt, domain.RuleStatusPublished)
+		if err != nil {
+			return fmt.Errorf("failed to retire rule version: %w", err)
+		}
+		if err := expectOneRow(result, &domain.DecisionRule{ID: ruleID, Version: version}); err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx, `SELECT `+ruleColumns+` FROM decision_rules WHERE id = $1 AND version = $2`, ruleID, version)
+		if err != nil {
+			return fmt.Errorf("failed to query retired version: %w", err)
+		}
+		retired, err := scanRules(rows)
+		if err != nil {
+			return err
+		}
+		return recordRuleEvent(ctx, tx, &retired[0], domain.RuleEventRetired, performedBy, retiredAt)
+	})
+}
+
+// GetRuleEvents returns a rule's history, oldest first
+func (r *RulesRepository) GetRuleEvents(ctx context.Context, ruleID string) ([]domain.RuleEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, rule_id, version, event, snapshot, performed_by, created_at
+		FROM decision_rule_events
+		WHERE rule_id = $1
+		ORDER BY created_at, id`, ruleID)
+	if err != nil {
+		r.logger.Error("Failed to query rule events", zap.String("rule_id", ruleID), zap.Error(err))
+		return nil, fmt.Errorf("failed to query rule events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]domain.RuleEvent, 0)
+	for rows.Next() {
+		var event domain.RuleEvent
+		var snapshotJSON []byte
+		if err := rows.Scan(&event.ID, &event.RuleID, &event.Version, &event.Event, &snapshotJSON,
+			&event.PerformedBy, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan rule event: %w", err)
+		}
+		if len(snapshotJSON) > 0 {
+			if err := json.Unmarshal(snapshotJSON, &event.Snapshot); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal rule event snapshot: %w", err)
+			}
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rule events: %w", err)
+	}
+	return events, nil
+}
+
+// inTx runs fn in a transaction, logging failures
+func (r *RulesRepository) inTx(ctx context.Context, operation, ruleID string, fn func(tx *sql.Tx) error) error {
+	logger := r.logger.With(
+		zap.String("rule_id", ruleID),
+		zap.String("operation", operation),
+	)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		logger.Error("Rule operation failed", zap.Error(err))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit transaction", zap.Error(err))
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// queryRules runs a rule query
+func (r *RulesRepository) queryRules(ctx context.Context, query string, args ...interface{}) ([]domain.DecisionRule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to query rules", zap.Error(err))
+		return nil, fmt.Errorf("failed to query rules: %w", err)
+	}
+	return scanRules(rows)
+}
+
+// scanRules decodes and closes rule rows
+func scanRules(rows *sql.Rows) ([]domain.DecisionRule, error) {
+	defer rows.Close()
+
+	rules := make([]domain.DecisionRule, 0)
+	for rows.Next() {
+		var rule domain.DecisionRule
+		var conditionsJSON, actionJSON, metadataJSON []byte
+		var publishedBy sql.NullString
+
+		if err := rows.Scan(
+			&rule.ID, &rule.Version, &rule.Status, &rule.Name, &rule.Description, &rule.Category,
+			&rule.Priority, &rule.Expression, &conditionsJSON, &actionJSON, &rule.Knockout,
+			&rule.EffectiveFrom, &rule.ExpiresAt, &rule.CreatedBy, &publishedBy, &rule.PublishedAt,
+			&rule.RetiredAt, &metadataJSON, &rule.CreatedAt, &rule.UpdatedAt, &rule.TenantID,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan rule row: %w", err)
+		}
+		rule.PublishedBy = publishedBy.String
+
+		if err := json.Unmarshal(conditionsJSON, &rule.Conditions); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal conditions of rule %s: %w", rule.VersionedID(), err)
+		}
+		if err := json.Unmarshal(actionJSON, &rule.Action); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal action of rule %s: %w", rule.VersionedID(), err)
+		}
+		if len(metadataJSON) > 0 {
+			if err := json.Unmarshal(metadataJSON, &rule.Metadata); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal metadata of rule %s: %w", rule.VersionedID(), err)
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rule rows: %w", err)
+	}
+	return rules, nil
+}
+
+// recordRuleEvent appends a change to the rule history
+func recordRuleEvent(ctx context.Context, tx *sql.Tx, rule *domain.DecisionRule, event domain.RuleEventType, performedBy string, at time.Time) error {
+	snapshotJSON, err := json.Marshal(rule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rule snapshot: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO decision_rule_events (rule_id, version, event, snapshot, performed_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		rule.ID, rule.Version, event, snapshotJSON, performedBy, at,
+	); err != nil {
+		return fmt.Errorf("failed to record rule event: %w", err)
+	}
+	return nil
+}
+
+// expectOneRow maps an update or delete that matched nothing to a not-found error
+func expectOneRow(result sql.Result, rule *domain.DecisionRule) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("rule version not found: %s", rule.VersionedID())
+	}
+	return nil
+}
+
+// sortRuleVersions orders rule versions by rule ID, then semantic version
+func sortRuleVersions(rules []domain.DecisionRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].ID != rules[j].ID {
+			return rules[i].ID < rules[j].ID
+		}
+		return domain.CompareVersions(rules[i].Version, rules[j].Version) < 0
+	})
+}
+
+// marshalRule serializes the JSON columns of a rule
+func marshalRule(rule *domain.DecisionRule) (conditions, action, metadata []byte, err error) {
+	if rule.Conditions == nil {
+		conditions = []byte("[]")
+	} else if conditions, err = json.Marshal(rule.Conditions); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to marshal conditions: %w", err)
+	}
+
+	if action, err = json.Marshal(rule.Action); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to marshal action: %w", err)
+	}
+
+	if rule.Metadata != nil {
+		if metadata, err = json.Marshal(rule.Metadata); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+	}
+	return conditions, action, metadata, nil
+}
+
+// nullString stores empty strings as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
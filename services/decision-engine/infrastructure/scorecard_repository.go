@@ -0,0 +1,275 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+const scorecardColumns = `id, name, description, status, base_points, characteristics, cutoff_bands,
+	risk_weight, created_by, created_at, updated_at, activated_by, activated_at, retired_at`
+
+// ScorecardRepository implements scorecard persistence
+type ScorecardRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewScorecardRepository creates a new scorecard repository
+func NewScorecardRepository(db *sql.DB, logger *zap.Logger) *ScorecardRepository {
+	return &ScorecardRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateScorecard inserts a new scorecard
+func (r *ScorecardRepository) CreateScorecard(ctx context.Context, scorecard *domain.Scorecard) error {
+	characteristicsJSON, cutoffBandsJSON, err := marshalScorecard(scorecard)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO scorecards (
+			id, name, description, status, base_points, characteristics, cutoff_bands, risk_weight,
+			created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		scorecard.ID,
+		scorecard.Name,
+		nullString(scorecard.Description),
+		scorecard.Status,
+		scorecard.BasePoints,
+		characteristicsJSON,
+		cutoffBandsJSON,
+		scorecard.RiskWeight,
+		scorecard.CreatedBy,
+		scorecard.CreatedAt,
+		scorecard.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create scorecard", zap.String("scorecard_id", scorecard.ID), zap.Error(err))
+		return fmt.Errorf("failed to create scorecard: %w", err)
+	}
+	return nil
+}
+
+// GetScorecard returns a scorecard by id
+func (r *ScorecardRepository) GetScorecard(ctx context.Context, id string) (*domain.Scorecard, error) {
+	scorecards, err := r.queryScorecards(ctx, `SELECT `+scorecardColumns+` FROM scorecards WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(scorecards) == 0 {
+		return nil, fmt.Errorf("scorecard not found: %s", id)
+	}
+	return &scorecards[0], nil
+}
+
+// ListScorecards returns the scorecards with a status, or every scorecard when status is empty,
+// newest first
+func (r *ScorecardRepository) ListScorecards(ctx context.Context, status domain.ScorecardStatus) ([]domain.Scorecard, error) {
+	if status == "" {
+		return r.queryScorecards(ctx, `SELECT `+scorecardColumns+` FROM scorecards ORDER BY created_at DESC`)
+	}
+	return r.queryScorecards(ctx, `SELECT `+scorecardColumns+` FROM scorecards WHERE status = $1 ORDER BY created_at DESC`, status)
+}
+
+// GetActiveScorecard returns the active scorecard, or nil when none is active
+func (r *ScorecardRepository) GetActiveScorecard(ctx context.Context) (*domain.Scorecard, error) {
+	scorecards, err := r.queryScorecards(ctx, `SELECT `+scorecardColumns+` FROM scorecards WHERE status = $1`, domain.ScorecardStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	if len(scorecards) == 0 {
+		return nil, nil
+	}
+	return &scorecards[0], nil
+}
+
+// UpdateDraft saves the definition of a draft scorecard
+func (r *ScorecardRepository) UpdateDraft(ctx context.Context, scorecard *domain.Scorecard) error {
+	characteristicsJSON, cutoffBandsJSON, err := marshalScorecard(scorecard)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE scorecards
+		SET name = $2, description = $3, base_points = $4, characteristics = $5, cutoff_bands = $6,
+			risk_weight = $7, updated_at = $8
+		WHERE id = $1 AND status = $9`,
+		scorecard.ID,
+		scorecard.Name,
+		nullString(scorecard.Description),
+		scorecard.BasePoints,
+		characteristicsJSON,
+		cutoffBandsJSON,
+		scorecard.RiskWeight,
+		scorecard.UpdatedAt,
+		domain.ScorecardStatusDraft,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update scorecard", zap.String("scorecard_id", scorecard.ID), zap.Error(err))
+		return fmt.Errorf("failed to update scorecard: %w", err)
+	}
+	return requireAffected(result, "draft scorecard not found: "+scorecard.ID)
+}
+
+// DeleteDraft deletes a draft scorecard
+func (r *ScorecardRepository) DeleteDraft(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM scorecards WHERE id = $1 AND status = $2`, id, domain.ScorecardStatusDraft)
+	if err != nil {
+		r.logger.Error("Failed to delete scorecard", zap.String("scorecard_id", id), zap.Error(err))
+		return fmt.Errorf("failed to delete scorecard: %w", err)
+	}
+	return requireAffected(result, "draft scorecard not found: "+id)
+}
+
+// ActivateScorecard retires the active scorecard and activates a draft in one transaction
+func (r *ScorecardRepository) ActivateScorecard(ctx context.Context, scorecard *domain.Scorecard) error {
+	logger := r.logger.With(zap.String("scorecard_id", scorecard.ID))
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE scorecards SET status = $1, retired_at = $2, updated_at = $2
+		WHERE status = $3`,
+		domain.ScorecardStatusRetired,
+		scorecard.ActivatedAt,
+		domain.ScorecardStatusActive,
+	); err != nil {
+		logger.Error("Failed to retire active scorecard", zap.Error(err))
+		return fmt.Errorf("failed to retire active scorecard: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE scorecards SET status = $2, activated_by = $3, activated_at = $4, updated_at = $4
+		WHERE id = $1 AND status = $5`,
+		scorecard.ID,
+		domain.ScorecardStatusActive,
+		scorecard.ActivatedBy,
+		scorecard.ActivatedAt,
+		domain.ScorecardStatusDraft,
+	)
+	if err != nil {
+		logger.Error("Failed to activate scorecard", zap.Error(err))
+		return fmt.Errorf("failed to activate scorecard: %w", err)
+	}
+	if err := requireAffected(result, "draft scorecard not found: "+scorecard.ID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit transaction", zap.Error(err))
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// RetireScorecard retires the active scorecard, leaving applications scored without one
+func (r *ScorecardRepository) RetireScorecard(ctx context.Context, id string, retiredAt time.Time) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE scorecards SET status = $2, retired_at = $3, updated_at = $3
+		WHERE id = $1 AND status = $4`,
+		id,
+		domain.ScorecardStatusRetired,
+		retiredAt,
+		domain.ScorecardStatusActive,
+	)
+	if err != nil {
+		r.logger.Error("Failed to retire scorecard", zap.String("scorecard_id", id), zap.Error(err))
+		return fmt.Errorf("failed to retire scorecard: %w", err)
+	}
+	return requireAffected(result, "active scorecard not found: "+id)
+}
+
+// marshalScorecard encodes a scorecard's characteristics and cutoff bands
+func marshalScorecard(scorecard *domain.Scorecard) ([]byte, []byte, error) {
+	characteristicsJSON, err := json.Marshal(scorecard.Characteristics)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal characteristics: %w", err)
+	}
+	cutoffBandsJSON, err := json.Marshal(scorecard.CutoffBands)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal cutoff bands: %w", err)
+	}
+	return characteristicsJSON, cutoffBandsJSON, nil
+}
+
+// requireAffected returns a not found error when a statement changed no rows
+func requireAffected(result sql.Result, notFound string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s", notFound)
+	}
+	return nil
+}
+
+// queryScorecards runs a scorecard query
+func (r *ScorecardRepository) queryScorecards(ctx context.Context, query string, args ...interface{}) ([]domain.Scorecard, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to query scorecards", zap.Error(err))
+		return nil, fmt.Errorf("failed to query scorecards: %w", err)
+	}
+	defer rows.Close()
+
+	scorecards := make([]domain.Scorecard, 0)
+	for rows.Next() {
+		var scorecard domain.Scorecard
+		var description, activatedBy sql.NullString
+		var characteristicsJSON, cutoffBandsJSON []byte
+		var activatedAt, retiredAt sql.NullTime
+		if err := rows.Scan(
+			&scorecard.ID,
+			&scorecard.Name,
+			&description,
+			&scorecard.Status,
+			&scorecard.BasePoints,
+			&characteristicsJSON,
+			&cutoffBandsJSON,
+			&scorecard.RiskWeight,
+			&scorecard.CreatedBy,
+			&scorecard.CreatedAt,
+			&scorecard.UpdatedAt,
+			&activatedBy,
+			&activatedAt,
+			&retiredAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan scorecard: %w", err)
+		}
+		scorecard.Description = description.String
+		scorecard.ActivatedBy = activatedBy.String
+		if err := json.Unmarshal(characteristicsJSON, &scorecard.Characteristics); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal characteristics of scorecard %s: %w", scorecard.ID, err)
+		}
+		if err := json.Unmarshal(cutoffBandsJSON, &scorecard.CutoffBands); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal cutoff bands of scorecard %s: %w", scorecard.ID, err)
+		}
+		if activatedAt.Valid {
+			scorecard.ActivatedAt = &activatedAt.Time
+		}
+		if retiredAt.Valid {
+			scorecard.RetiredAt = &retiredAt.Time
+		}
+		scorecards = append(scorecards, scorecard)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over scorecards: %w", err)
+	}
+	return scorecards, nil
+}
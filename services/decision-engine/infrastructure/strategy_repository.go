@@ -0,0 +1,322 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+const strategyColumns = `id, name, description, challenger, traffic_percent, status, created_by,
+	created_at, updated_at, started_at, ended_at`
+
+const challengerDecisionColumns = `id, strategy_id, application_id, risk_category, champion_decision,
+	challenger_decision, champion_amount, challenger_amount, champion_expected_loss,
+	challenger_expected_loss, challenger_reason, champion_rule_versions, challenger_rule_versions, created_at`
+
+// StrategyRepository implements champion/challenger strategy persistence
+type StrategyRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewStrategyRepository creates a new strategy repository
+func NewStrategyRepository(db *sql.DB, logger *zap.Logger) *StrategyRepository {
+	return &StrategyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateStrategy inserts a new strategy
+func (r *StrategyRepository) CreateStrategy(ctx context.Context, strategy *domain.DecisionStrategy) error {
+	challengerJSON, err := json.Marshal(strategy.Challenger)
+	if err != nil {
+		return fmt.Errorf("failed to marshal challenger: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO decision_strategies (
+			id, name, description, challenger, traffic_percent, status, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		strategy.ID,
+		strategy.Name,
+		nullString(strategy.Description),
+		challengerJSON,
+		strategy.TrafficPercent,
+		strategy.Status,
+		strategy.CreatedBy,
+		strategy.CreatedAt,
+		strategy.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create strategy", zap.String("strategy_id", strategy.ID), zap.Error(err))
+		return fmt.Errorf("failed to create strategy: %w", err)
+	}
+	return nil
+}
+
+// GetStrategy returns a strategy by id
+func (r *StrategyRepository) GetStrategy(ctx context.Context, id string) (*domain.DecisionStrategy, error) {
+	strategies, err := r.queryStrategies(ctx, `SELECT `+strategyColumns+` FROM decision_strategies WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(strategies) == 0 {
+		return nil, fmt.Errorf("strategy not found: %s", id)
+	}
+	return &strategies[0], nil
+}
+
+// ListStrategies returns the strategies with a status, or every strategy when status is empty,
+// newest first
+func (r *StrategyRepository) ListStrategies(ctx context.Context, status domain.StrategyStatus) ([]domain.DecisionStrategy, error) {
+	if status == "" {
+		return r.queryStrategies(ctx, `SELECT `+strategyColumns+` FROM decision_strategies ORDER BY created_at DESC`)
+	}
+	return r.queryStrategies(ctx, `SELECT `+strategyColumns+` FROM decision_strategies WHERE status = $1 ORDER BY created_at DESC`, status)
+}
+
+// GetActiveStrategy returns the active strategy, or nil when none is active
+func (r *StrategyRepository) GetActiveStrategy(ctx context.Context) (*domain.DecisionStrategy, error) {
+	strategies, err := r.queryStrategies(ctx, `SELECT `+strategyColumns+` FROM decision_strategies WHERE status = $1`, domain.StrategyStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	if len(strategies) == 0 {
+		return nil, nil
+	}
+	return &strategies[0], nil
+}
+
+// UpdateStrategy saves a strategy's traffic split and status
+func (r *StrategyRepository) UpdateStrategy(ctx context.Context, strategy *domain.DecisionStrategy) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE decision_strategies
+		SET traffic_percent = $2, status = $3, updated_at = $4, started_at = $5, ended_at = $6
+		WHERE id = $1`,
+		strategy.ID,
+		strategy.TrafficPercent,
+		strategy.Status,
+		strategy.UpdatedAt,
+		strategy.StartedAt,
+		strategy.EndedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update strategy", zap.String("strategy_id", strategy.ID), zap.Error(err))
+		if strings.Contains(err.Error(), "idx_decision_strategies_one_active") {
+			return fmt.Errorf("another strategy is already active: %w", err)
+		}
+		return fmt.Errorf("failed to update strategy: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update strategy: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("strategy not found: %s", strategy.ID)
+	}
+	return nil
+}
+
+// SaveChallengerDecision records the champion and challenger decisions made on an application
+func (r *StrategyRepository) SaveChallengerDecision(ctx context.Context, decision *domain.ChallengerDecision) error {
+	championVersionsJSON, err := json.Marshal(decision.ChampionRuleVersions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal champion rule versions: %w", err)
+	}
+	challengerVersionsJSON, err := json.Marshal(decision.ChallengerRuleVersions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal challenger rule versions: %w", err)
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO challenger_decisions (
+			strategy_id, application_id, risk_category, champion_decision, challenger_decision,
+			champion_amount, challenger_amount, champion_expected_loss, challenger_expected_loss,
+			challenger_reason, champion_rule_versions, challenger_rule_versions, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id`,
+		decision.StrategyID,
+		decision.ApplicationID,
+		decision.RiskCategory,
+		decision.ChampionDecision,
+		decision.ChallengerDecision,
+		decision.ChampionAmount,
+		decision.ChallengerAmount,
+		decision.ChampionExpectedLoss,
+		decision.ChallengerExpectedLoss,
+		nullString(decision.ChallengerReason),
+		championVersionsJSON,
+		challengerVersionsJSON,
+		decision.CreatedAt,
+	).Scan(&decision.ID)
+	if err != nil {
+		r.logger.Error("Failed to save challenger decision",
+			zap.String("strategy_id", decision.StrategyID),
+			zap.String("application_id", decision.ApplicationID),
+			zap.Error(err))
+		return fmt.Errorf("failed to save challenger decision: %w", err)
+	}
+	return nil
+}
+
+// GetChallengerOutcomes totals a strategy's challenger decisions by champion and challenger
+// decision, optionally within a time window
+func (r *StrategyRepository) GetChallengerOutcomes(ctx context.Context, strategyID string, from, to *time.Time) ([]domain.ChallengerOutcome, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT champion_decision, challenger_decision, COUNT(*),
+			   COUNT(*) FILTER (WHERE champion_decision = challenger_decision AND champion_amount = challenger_amount),
+			   COALESCE(SUM(champion_amount), 0), COALESCE(SUM(challenger_amount), 0),
+			   COALESCE(SUM(champion_expected_loss), 0), COALESCE(SUM(challenger_expected_loss), 0)
+		FROM challenger_decisions
+		WHERE strategy_id = $1
+		  AND ($2::timestamptz IS NULL OR created_at >= $2)
+		  AND ($3::timestamptz IS NULL OR created_at < $3)
+		GROUP BY champion_decision, challenger_decision`,
+		strategyID, from, to,
+	)
+	if err != nil {
+		r.logger.Error("Failed to query challenger outcomes", zap.String("strategy_id", strategyID), zap.Error(err))
+		return nil, fmt.Errorf("failed to query challenger outcomes: %w", err)
+	}
+	defer rows.Close()
+
+	var outcomes []domain.ChallengerOutcome
+	for rows.Next() {
+		var outcome domain.ChallengerOutcome
+		if err := rows.Scan(
+			&outcome.ChampionDecision,
+			&outcome.ChallengerDecision,
+			&outcome.Count,
+			&outcome.Agreed,
+			&outcome.ChampionAmount,
+			&outcome.ChallengerAmount,
+			&outcome.ChampionExpectedLoss,
+			&outcome.ChallengerExpectedLoss,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan challenger outcome: %w", err)
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over challenger outcomes: %w", err)
+	}
+	return outcomes, nil
+}
+
+// GetChallengerDisagreements returns a strategy's most recent challenger decisions that differ from
+// the champion's, optionally within a time window
+func (r *StrategyRepository) GetChallengerDisagreements(ctx context.Context, strategyID string, from, to *time.Time, limit int) ([]domain.ChallengerDecision, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+challengerDecisionColumns+`
+		FROM challenger_decisions
+		WHERE strategy_id = $1
+		  AND ($2::timestamptz IS NULL OR created_at >= $2)
+		  AND ($3::timestamptz IS NULL OR created_at < $3)
+		  AND (champion_decision <> challenger_decision OR champion_amount <> challenger_amount)
+		ORDER BY created_at DESC
+		LIMIT $4`,
+		strategyID, from, to, limit,
+	)
+	if err != nil {
+		r.logger.Error("Failed to query challenger disagreements", zap.String("strategy_id", strategyID), zap.Error(err))
+		return nil, fmt.Errorf("failed to query challenger disagreements: %w", err)
+	}
+	defer rows.Close()
+
+	decisions := make([]domain.ChallengerDecision, 0)
+	for rows.Next() {
+		var decision domain.ChallengerDecision
+		var reason sql.NullString
+		var championVersionsJSON, challengerVersionsJSON []byte
+		if err := rows.Scan(
+			&decision.ID,
+			&decision.StrategyID,
+			&decision.ApplicationID,
+			&decision.RiskCategory,
+			&decision.ChampionDecision,
+			&decision.ChallengerDecision,
+			&decision.ChampionAmount,
+			&decision.ChallengerAmount,
+			&decision.ChampionExpectedLoss,
+			&decision.ChallengerExpectedLoss,
+			&reason,
+			&championVersionsJSON,
+			&challengerVersionsJSON,
+			&decision.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan challenger decision: %w", err)
+		}
+		decision.ChallengerReason = reason.String
+		if len(championVersionsJSON) > 0 {
+			if err := json.Unmarshal(championVersionsJSON, &decision.ChampionRuleVersions); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal champion rule versions: %w", err)
+			}
+		}
+		if len(challengerVersionsJSON) > 0 {
+			if err := json.Unmarshal(challengerVersionsJSON, &decision.ChallengerRuleVersions); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal challenger rule versions: %w", err)
+			}
+		}
+		decisions = append(decisions, decision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over challenger decisions: %w", err)
+	}
+	return decisions, nil
+}
+
+// queryStrategies runs a strategy query
+func (r *StrategyRepository) queryStrategies(ctx context.Context, query string, args ...interface{}) ([]domain.DecisionStrategy, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to query strategies", zap.Error(err))
+		return nil, fmt.Errorf("failed to query strategies: %w", err)
+	}
+	defer rows.Close()
+
+	strategies := make([]domain.DecisionStrategy, 0)
+	for rows.Next() {
+		var strategy domain.DecisionStrategy
+		var description sql.NullString
+		var challengerJSON []byte
+		var startedAt, endedAt sql.NullTime
+		if err := rows.Scan(
+			&strategy.ID,
+			&strategy.Name,
+			&description,
+			&challengerJSON,
+			&strategy.TrafficPercent,
+			&strategy.Status,
+			&strategy.CreatedBy,
+			&strategy.CreatedAt,
+			&strategy.UpdatedAt,
+			&startedAt,
+			&endedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan strategy: %w", err)
+		}
+		strategy.Description = description.String
+		if err := json.Unmarshal(challengerJSON, &strategy.Challenger); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal challenger of strategy %s: %w", strategy.ID, err)
+		}
+		if startedAt.Valid {
+			strategy.StartedAt = &startedAt.Time
+		}
+		if endedAt.Valid {
+			strategy.EndedAt = &endedAt.Time
+		}
+		strategies = append(strategies, strategy)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over strategies: %w", err)
+	}
+	return strategies, nil
+}
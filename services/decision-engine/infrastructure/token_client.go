@@ -0,0 +1,76 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TokenVaultClient resolves SSN tokens through the user service internal token vault.
+// The service token it presents carries the detokenize scope, which no other caller holds.
+type TokenVaultClient struct {
+	baseURL      string
+	serviceToken string
+	httpClient   *http.Client
+	logger       *zap.Logger
+}
+
+// NewTokenVaultClient creates a new token vault client
+func NewTokenVaultClient(baseURL, serviceToken string, timeout time.Duration, logger *zap.Logger) *TokenVaultClient {
+	return &TokenVaultClient{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		serviceToken: serviceToken,
+		httpClient:   &http.Client{Timeout: timeout},
+		logger:       logger,
+	}
+}
+
+// Detokenize returns the raw value behind a vault token
+func (c *TokenVaultClient) Detokenize(ctx context.Context, token string) (string, error) {
+	logger := c.logger.With(zap.String("operation", "detokenize"))
+
+	payload, err := json.Marshal(map[string]string{"token": token})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal detokenize request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/internal/v1/tokens/detokenize", bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to build detokenize request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.serviceToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Detokenize request failed", zap.Error(err))
+		return "", fmt.Errorf("failed to call token vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("Unexpected detokenize response", zap.Int("status", resp.StatusCode))
+		return "", fmt.Errorf("unexpected detokenize status: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Value string `json:"value"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode detokenize response: %w", err)
+	}
+	if !body.Success || body.Data.Value == "" {
+		return "", fmt.Errorf("token vault returned no value")
+	}
+
+	return body.Data.Value, nil
+}
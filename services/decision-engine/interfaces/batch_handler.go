@@ -0,0 +1,165 @@
+package interfaces
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/huuhoait/los-demo/services/decision-engine/application"
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// BatchHandler handles HTTP requests for batch decisioning
+type BatchHandler struct {
+	batchService *application.BatchDecisionService
+	logger       *zap.Logger
+}
+
+// NewBatchHandler creates a new batch handler
+func NewBatchHandler(batchService *application.BatchDecisionService, logger *zap.Logger) *BatchHandler {
+	return &BatchHandler{
+		batchService: batchService,
+		logger:       logger,
+	}
+}
+
+// SubmitBatch handles POST /api/v1/decisions/batch, accepting applications to be decided
+// asynchronously
+func (h *BatchHandler) SubmitBatch(c *gin.Context) {
+	var request domain.BatchDecisionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.logger.Warn("Invalid batch decision payload", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Decide every application under the caller's lending program
+	applications := make([]*domain.DecisionRequest, len(request.Applications))
+	for i := range request.Applications {
+		applications[i] = &request.Applications[i]
+	}
+	if err := scopeDecisionRequests(c, applications...); err != nil {
+		h.respondError(c, "submit_batch", err)
+		return
+	}
+
+	job, err := h.batchService.SubmitBatch(c.Request.Context(), &request, c.GetHeader("X-User-ID"))
+	if err != nil {
+		h.respondError(c, "submit_batch", err)
+		return
+	}
+
+	c.Header("Location", "/api/v1/decisions/batch/"+strconv.FormatInt(job.ID, 10))
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetBatchJob handles GET /api/v1/decisions/batch/:jobId
+func (h *BatchHandler) GetBatchJob(c *gin.Context) {
+	jobID, ok := h.jobID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.batchService.GetBatchJob(c.Request.Context(), jobID)
+	if err != nil {
+		h.respondError(c, "get_batch_job", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// GetBatchResults handles GET /api/v1/decisions/batch/:jobId/results, paging through item
+// results in batch order, optionally filtered by status
+func (h *BatchHandler) GetBatchResults(c *gin.Context) {
+	jobID, ok := h.jobID(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(domain.DefaultBatchResultsLimit)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid limit",
+			"details": "limit must be an integer",
+		})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid offset",
+			"details": "offset must be an integer",
+		})
+		return
+	}
+
+	page, err := h.batchService.GetBatchResults(c.Request.Context(), domain.BatchResultsQuery{
+		JobID:  jobID,
+		Status: domain.BatchItemStatus(strings.ToUpper(c.Query("status"))),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		h.respondError(c, "get_batch_results", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// jobID parses the batch job id path parameter
+func (h *BatchHandler) jobID(c *gin.Context) (int64, bool) {
+	jobID, err := strconv.ParseInt(c.Param("jobId"), 10, 64)
+	if err != nil || jobID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid batch job ID",
+			"details": "Batch job ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return jobID, true
+}
+
+// respondError maps service errors to error responses
+func (h *BatchHandler) respondError(c *gin.Context, operation string, err error) {
+	logger := h.logger.With(
+		zap.String("endpoint", operation),
+		zap.String("job_id", c.Param("jobId")),
+	)
+
+	if decisionErr, ok := err.(*domain.DecisionError); ok {
+		if decisionErr.HTTPStatus >= http.StatusInternalServerError {
+			logger.Error("Batch decision operation failed", zap.Error(err))
+		} else {
+			logger.Warn("Batch decision operation rejected", zap.String("code", decisionErr.Code), zap.String("details", decisionErr.Description))
+		}
+		c.JSON(decisionErr.HTTPStatus, gin.H{
+			"error":   decisionErr.Message,
+			"code":    decisionErr.Code,
+			"details": decisionErr.Description,
+		})
+		return
+	}
+
+	logger.Error("Batch decision operation failed", zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Internal server error",
+		"details": err.Error(),
+	})
+}
+
+// RegisterRoutes registers the batch decision routes
+func (h *BatchHandler) RegisterRoutes(router *gin.Engine) {
+	batches := router.Group("/api/v1/decisions/batch")
+	{
+		batches.POST("", h.SubmitBatch)
+		batches.GET("/:jobId", h.GetBatchJob)
+		batches.GET("/:jobId/results", h.GetBatchResults)
+	}
+}
@@ -0,0 +1,166 @@
+package interfaces
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/huuhoait/los-demo/services/decision-engine/application"
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// CreditReportHandler handles HTTP requests for credit reports
+type CreditReportHandler struct {
+	triMergeService *application.TriMergeService
+	breaker         *application.BureauCircuitBreaker
+	logger          *zap.Logger
+}
+
+// NewCreditReportHandler creates a new credit report handler
+func NewCreditReportHandler(triMergeService *application.TriMergeService, breaker *application.BureauCircuitBreaker, logger *zap.Logger) *CreditReportHandler {
+	return &CreditReportHandler{
+		triMergeService: triMergeService,
+		breaker:         breaker,
+		logger:          logger,
+	}
+}
+
+// PullCreditReport handles POST /api/v1/credit-reports/pull, pulling one bureau with failover
+func (h *CreditReportHandler) PullCreditReport(c *gin.Context) {
+	var request domain.BureauPullRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.logger.Warn("Invalid credit report payload", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.triMergeService.PullCreditReport(c.Request.Context(), &request)
+	if err != nil {
+		h.respondError(c, "pull_credit_report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetBureauHealth handles GET /api/v1/credit-reports/bureaus/health
+func (h *CreditReportHandler) GetBureauHealth(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"bureaus": h.breaker.Health(),
+	})
+}
+
+// TriMerge handles POST /api/v1/credit-reports/tri-merge
+func (h *CreditReportHandler) TriMerge(c *gin.Context) {
+	var request domain.TriMergeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.logger.Warn("Invalid tri-merge payload", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	report, err := h.triMergeService.TriMerge(c.Request.Context(), &request)
+	if err != nil {
+		h.respondError(c, "tri_merge", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// GetTriMergeReport handles GET /api/v1/credit-reports/tri-merge/:mergeId
+func (h *CreditReportHandler) GetTriMergeReport(c *gin.Context) {
+	mergeID, ok := h.mergeID(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.triMergeService.GetTriMergeReport(c.Request.Context(), mergeID)
+	if err != nil {
+		h.respondError(c, "get_tri_merge_report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetBureauReports handles GET /api/v1/credit-reports/tri-merge/:mergeId/bureau-reports,
+// returning the raw bureau reports as received
+func (h *CreditReportHandler) GetBureauReports(c *gin.Context) {
+	mergeID, ok := h.mergeID(c)
+	if !ok {
+		return
+	}
+
+	reports, err := h.triMergeService.GetBureauReports(c.Request.Context(), mergeID)
+	if err != nil {
+		h.respondError(c, "get_bureau_reports", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"merge_id": mergeID,
+		"reports":  reports,
+		"count":    len(reports),
+	})
+}
+
+// mergeID parses the tri-merge id path parameter
+func (h *CreditReportHandler) mergeID(c *gin.Context) (int64, bool) {
+	mergeID, err := strconv.ParseInt(c.Param("mergeId"), 10, 64)
+	if err != nil || mergeID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid tri-merge ID",
+			"details": "Tri-merge ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return mergeID, true
+}
+
+// respondError maps service errors to error responses
+func (h *CreditReportHandler) respondError(c *gin.Context, operation string, err error) {
+	logger := h.logger.With(
+		zap.String("endpoint", operation),
+		zap.String("merge_id", c.Param("mergeId")),
+	)
+
+	if decisionErr, ok := err.(*domain.DecisionError); ok {
+		if decisionErr.HTTPStatus >= http.StatusInternalServerError {
+			logger.Error("Credit report operation failed", zap.Error(err))
+		} else {
+			logger.Warn("Credit report operation rejected", zap.String("code", decisionErr.Code), zap.String("details", decisionErr.Description))
+		}
+		c.JSON(decisionErr.HTTPStatus, gin.H{
+			"error":   decisionErr.Message,
+			"code":    decisionErr.Code,
+			"details": decisionErr.Description,
+		})
+		return
+	}
+
+	logger.Error("Credit report operation failed", zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Internal server error",
+		"details": err.Error(),
+	})
+}
+
+// RegisterRoutes registers the credit report routes
+func (h *CreditReportHandler) RegisterRoutes(router *gin.Engine) {
+	reports := router.Group("/api/v1/credit-reports")
+	{
+		reports.POST("/pull", h.PullCreditReport)
+		reports.GET("/bureaus/health", h.GetBureauHealth)
+		reports.POST("/tri-merge", h.TriMerge)
+		reports.GET("/tri-merge/:mergeId", h.GetTriMergeReport)
+		reports.GET("/tri-merge/:mergeId/bureau-reports", h.GetBureauReports)
+	}
+}
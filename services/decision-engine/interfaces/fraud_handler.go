@@ -0,0 +1,56 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/huuhoait/los-demo/services/decision-engine/application"
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// FraudHandler handles HTTP requests for fraud screening
+type FraudHandler struct {
+	fraudService *application.FraudScreeningService
+	logger       *zap.Logger
+}
+
+// NewFraudHandler creates a new fraud handler; fraudService is nil when no fraud vendor is
+// configured
+func NewFraudHandler(fraudService *application.FraudScreeningService, logger *zap.Logger) *FraudHandler {
+	return &FraudHandler{
+		fraudService: fraudService,
+		logger:       logger,
+	}
+}
+
+// ScreenApplication handles POST /api/v1/fraud/screenings, screening an application with the fraud
+// vendor and recommending whether to clear, review or deny it
+func (h *FraudHandler) ScreenApplication(c *gin.Context) {
+	if h.fraudService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Fraud screening unavailable",
+			"code":    domain.ERROR_EXTERNAL_SERVICE,
+			"details": "no fraud vendor is configured",
+		})
+		return
+	}
+
+	var request domain.FraudScreeningRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.logger.Warn("Invalid fraud screening payload", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"code":    domain.ERROR_INVALID_REQUEST,
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.fraudService.Screen(c.Request.Context(), &request))
+}
+
+// RegisterRoutes registers the fraud screening routes
+func (h *FraudHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/api/v1/fraud/screenings", h.ScreenApplication)
+}
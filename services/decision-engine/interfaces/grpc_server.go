@@ -0,0 +1,183 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/huuhoait/los-demo/services/decision-engine/application"
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"github.com/huuhoait/los-demo/services/shared/pkg/rpc"
+	decisionv1 "github.com/huuhoait/los-demo/services/shared/proto/decision/v1"
+)
+
+// DecisionServer serves the DecisionService gRPC contract to internal callers. Callers are
+// authenticated by the transport's mTLS, so requests are decided as received, under the lending
+// program they name.
+type DecisionServer struct {
+	decisionv1.UnimplementedDecisionServiceServer
+	decisionService *application.DecisionEngineService
+	tenants         *domain.TenantRegistry
+	logger          *zap.Logger
+}
+
+// NewDecisionServer creates a new DecisionService gRPC server
+func NewDecisionServer(decisionService *application.DecisionEngineService, tenants *domain.TenantRegistry, logger *zap.Logger) *DecisionServer {
+	return &DecisionServer{
+		decisionService: decisionService,
+		tenants:         tenants,
+		logger:          logger,
+	}
+}
+
+// MakeDecision decides an application, as POST /api/v1/decisions does
+func (s *DecisionServer) MakeDecision(ctx context.Context, req *decisionv1.MakeDecisionRequest) (*decisionv1.Decision, error) {
+	logger := s.logger.With(
+		zap.String("rpc", "make_decision"),
+		zap.String("application_id", req.GetApplicationId()),
+	)
+
+	tenant, err := s.tenants.Resolve(req.GetTenantId())
+	if err != nil {
+		return nil, decisionStatus(err)
+	}
+
+	request := &domain.DecisionRequest{
+		TenantID:       tenant.ID,
+		ApplicationID:  req.GetApplicationId(),
+		UserID:         req.GetUserId(),
+		CustomerID:     req.GetCustomerId(),
+		LoanAmount:     req.GetLoanAmount(),
+		AnnualIncome:   req.GetAnnualIncome(),
+		MonthlyIncome:  req.GetMonthlyIncome(),
+		MonthlyDebt:    req.GetMonthlyDebt(),
+		CreditScore:    int(req.GetCreditScore()),
+		EmploymentType: domain.EmploymentType(req.GetEmploymentType()),
+		RequestedTerm:  int(req.GetRequestedTermMonths()),
+		LoanPurpose:    domain.LoanPurpose(req.GetLoanPurpose()),
+		RequestedAt:    time.Now().UTC(),
+	}
+	if collateral := req.GetCollateral(); collateral != nil {
+		request.Collateral = &domain.CollateralInfo{
+			Type:            collateral.GetType(),
+			Value:           collateral.GetValue(),
+			ValuationSource: collateral.GetValuationSource(),
+			ValuedAt:        collateral.GetValuedAt().AsTime(),
+		}
+	}
+
+	if err := request.Validate(); err != nil {
+		logger.Warn("Request validation failed", zap.Error(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	decision, err := s.decisionService.MakeDecision(ctx, request)
+	if err != nil {
+		logger.Warn("Failed to process decision", zap.Error(err))
+		return nil, decisionStatus(err)
+	}
+
+	return decisionMessage(decision), nil
+}
+
+// GetDecision returns the saved decision on an application, as GET /api/v1/decisions/:applicationId does
+func (s *DecisionServer) GetDecision(ctx context.Context, req *decisionv1.GetDecisionRequest) (*decisionv1.Decision, error) {
+	if req.GetApplicationId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "application_id is required")
+	}
+
+	decision, err := s.decisionService.GetDecision(ctx, req.GetApplicationId())
+	if err != nil {
+		s.logger.Warn("Failed to retrieve decision",
+			zap.String("rpc", "get_decision"),
+			zap.String("application_id", req.GetApplicationId()),
+			zap.Error(err))
+		return nil, status.Error(codes.NotFound, "decision not found")
+	}
+
+	return decisionMessage(decision), nil
+}
+
+// decisionStatus reports a decision error with the gRPC code matching its HTTP status
+func decisionStatus(err error) error {
+	var decisionErr *domain.DecisionError
+	switch {
+	case errors.As(err, &decisionErr):
+		code := codes.Internal
+		if decisionErr.HTTPStatus != 0 {
+			code = rpc.CodeFromHTTPStatus(decisionErr.HTTPStatus)
+		}
+		return status.Errorf(code, "%s: %s", decisionErr.Code, decisionErr.Message)
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "decision deadline exceeded")
+	default:
+		return status.Error(codes.Internal, "failed to process decision")
+	}
+}
+
+// decisionMessage converts a decision to its DecisionService message
+func decisionMessage(decision *domain.DecisionResponse) *decisionv1.Decision {
+	message := &decisionv1.Decision{
+		ApplicationId:     decision.ApplicationID,
+		Decision:          string(decision.Decision),
+		RiskScore:         decision.RiskScore,
+		RiskCategory:      string(decision.RiskCategory),
+		ConfidenceScore:   decision.ConfidenceScore,
+		InterestRate:      decision.InterestRate,
+		ApprovedAmount:    decision.ApprovedAmount,
+		MaxAmount:         decision.MaxAmount,
+		DecisionReason:    decision.DecisionReason,
+		Conditions:        decision.Conditions,
+		RequiredDocuments: decision.RequiredDocs,
+		ReviewRequired:    decision.ReviewRequired,
+		AppliedRules:      decision.AppliedRules,
+		DecisionDate:      timestamppb.New(decision.DecisionDate),
+		CreditScore:       int32(decision.CreditScore),
+		Provisional:       decision.Provisional,
+	}
+	if decision.ExpiresAt != nil {
+		message.ExpiresAt = timestamppb.New(*decision.ExpiresAt)
+	}
+	for _, factor := range decision.RiskFactors {
+		message.RiskFactors = append(message.RiskFactors, &decisionv1.RiskFactor{
+			Category:    factor.Category,
+			Factor:      factor.Factor,
+			Impact:      factor.Impact,
+			Score:       factor.Score,
+			Description: factor.Description,
+		})
+	}
+	if pricing := decision.Pricing; pricing != nil {
+		message.Pricing = &decisionv1.Pricing{
+			ModelVersion:          pricing.ModelVersion,
+			BaseRate:              pricing.BaseRate,
+			Margin:                pricing.Margin,
+			Rate:                  pricing.Rate,
+			OriginationFeeRate:    pricing.OriginationFeeRate,
+			NetOriginationFeeRate: pricing.NetOriginationFeeRate,
+			Apr:                   pricing.APR,
+			MonthlyPayment:        pricing.MonthlyPayment,
+		}
+		for _, adjustment := range pricing.MarginAdjustments {
+			message.Pricing.MarginAdjustments = append(message.Pricing.MarginAdjustments, &decisionv1.MarginAdjustment{
+				Factor:      string(adjustment.Factor),
+				Adjustment:  adjustment.Adjustment,
+				Description: adjustment.Description,
+			})
+		}
+		for _, waiver := range pricing.FeeWaivers {
+			message.Pricing.FeeWaivers = append(message.Pricing.FeeWaivers, &decisionv1.FeeWaiver{
+				Fee:        waiver.Fee,
+				Code:       waiver.Code,
+				WaivedRate: waiver.WaivedRate,
+				Reason:     waiver.Reason,
+			})
+		}
+	}
+	return message
+}
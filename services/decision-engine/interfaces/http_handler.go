@@ -1,6 +1,8 @@
 package interfaces
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -14,13 +16,15 @@ import (
 // DecisionHandler handles HTTP requests for decision engine
 type DecisionHandler struct {
 	decisionService *application.DecisionEngineService
+	replayService   *application.DecisionReplayService
 	logger          *zap.Logger
 }
 
 // NewDecisionHandler creates a new decision handler
-func NewDecisionHandler(decisionService *application.DecisionEngineService, logger *zap.Logger) *DecisionHandler {
+func NewDecisionHandler(decisionService *application.DecisionEngineService, replayService *application.DecisionReplayService, logger *zap.Logger) *DecisionHandler {
 	return &DecisionHandler{
 		decisionService: decisionService,
+		replayService:   replayService,
 		logger:          logger,
 	}
 }
@@ -37,6 +41,7 @@ func (h *DecisionHandler) MakeDecision(c *gin.Context) {
 		logger.Error("Invalid request payload", zap.Error(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid request payload",
+			"code":    domain.ERROR_INVALID_REQUEST,
 			"details": err.Error(),
 		})
 		return
@@ -47,25 +52,61 @@ func (h *DecisionHandler) MakeDecision(c *gin.Context) {
 		logger.Error("Request validation failed", zap.Error(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Request validation failed",
+			"code":    domain.ERROR_INVALID_REQUEST,
 			"details": err.Error(),
 		})
 		return
 	}
 
+	// Decide under the caller's lending program
+	if err := scopeDecisionRequests(c, &request); err != nil {
+		decisionErr := err.(*domain.DecisionError)
+		logger.Warn("Decision request for another lending program rejected", zap.Error(err))
+		c.JSON(decisionErr.HTTPStatus, gin.H{
+			"error":   decisionErr.Message,
+			"code":    decisionErr.Code,
+			"details": decisionErr.Description,
+		})
+		return
+	}
+
 	logger.Info("Processing decision request",
 		zap.String("application_id", request.ApplicationID),
 		zap.String("customer_id", request.CustomerID),
+		zap.String("tenant_id", request.TenantID),
 		zap.Float64("loan_amount", request.LoanAmount),
 	)
 
 	// Process decision
 	response, err := h.decisionService.MakeDecision(c.Request.Context(), &request)
 	if err != nil {
-		logger.Error("Failed to process decision", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to process decision",
-			"details": err.Error(),
-		})
+		var decisionErr *domain.DecisionError
+		switch {
+		case errors.As(err, &decisionErr):
+			status := decisionErr.HTTPStatus
+			if status == 0 {
+				status = http.StatusInternalServerError
+			}
+			logger.Warn("Failed to process decision", zap.String("code", decisionErr.Code), zap.Error(err))
+			c.JSON(status, gin.H{
+				"error":   decisionErr.Message,
+				"code":    decisionErr.Code,
+				"details": decisionErr.Description,
+			})
+		case errors.Is(err, context.DeadlineExceeded):
+			logger.Warn("Decision deadline exceeded", zap.Error(err))
+			c.JSON(http.StatusGatewayTimeout, gin.H{
+				"error":   "Decision deadline exceeded",
+				"code":    domain.ERROR_DEADLINE_EXCEEDED,
+				"details": err.Error(),
+			})
+		default:
+			logger.Error("Failed to process decision", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to process decision",
+				"details": err.Error(),
+			})
+		}
 		return
 	}
 
@@ -112,6 +153,86 @@ func (h *DecisionHandler) GetDecision(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// GetDecisionExplanation handles GET /api/v1/decisions/explanations/:decisionId
+func (h *DecisionHandler) GetDecisionExplanation(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("endpoint", "get_decision_explanation"),
+		zap.String("method", "GET"),
+		zap.String("decision_id", c.Param("decisionId")),
+	)
+
+	decisionID, err := strconv.ParseInt(c.Param("decisionId"), 10, 64)
+	if err != nil || decisionID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid decision ID",
+			"details": "Decision ID must be a positive integer",
+		})
+		return
+	}
+
+	explanation, err := h.decisionService.GetDecisionExplanation(c.Request.Context(), decisionID)
+	if err != nil {
+		if decisionErr, ok := err.(*domain.DecisionError); ok {
+			logger.Warn("Failed to explain decision", zap.String("code", decisionErr.Code), zap.Error(err))
+			c.JSON(decisionErr.HTTPStatus, gin.H{
+				"error":   decisionErr.Message,
+				"code":    decisionErr.Code,
+				"details": decisionErr.Description,
+			})
+			return
+		}
+		logger.Error("Failed to explain decision", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal server error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, explanation)
+}
+
+// ReplayDecision handles GET /api/v1/decisions/:id/replay, re-executing a stored decision with
+// its stored inputs and the rule versions in force when it was made. The path segment is the
+// decision id; it shares the :applicationId wildcard because gin allows one name per segment.
+func (h *DecisionHandler) ReplayDecision(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("endpoint", "replay_decision"),
+		zap.String("method", "GET"),
+		zap.String("decision_id", c.Param("applicationId")),
+	)
+
+	decisionID, err := strconv.ParseInt(c.Param("applicationId"), 10, 64)
+	if err != nil || decisionID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid decision ID",
+			"details": "Decision ID must be a positive integer",
+		})
+		return
+	}
+
+	replay, err := h.replayService.ReplayDecision(c.Request.Context(), decisionID, c.GetHeader("X-User-ID"))
+	if err != nil {
+		if decisionErr, ok := err.(*domain.DecisionError); ok {
+			logger.Warn("Failed to replay decision", zap.String("code", decisionErr.Code), zap.Error(err))
+			c.JSON(decisionErr.HTTPStatus, gin.H{
+				"error":   decisionErr.Message,
+				"code":    decisionErr.Code,
+				"details": decisionErr.Description,
+			})
+			return
+		}
+		logger.Error("Failed to replay decision", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal server error",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, replay)
+}
+
 // GetDecisionHistory handles GET /api/v1/customers/:customerId/decisions
 func (h *DecisionHandler) GetDecisionHistory(c *gin.Context) {
 	customerID := c.Param("customerId")
@@ -262,6 +383,17 @@ func (h *DecisionHandler) ValidateDecisionRequest(c *gin.Context) {
 		})
 		return
 	}
+	if err := scopeDecisionRequests(c, &request); err != nil {
+		decisionErr := err.(*domain.DecisionError)
+		logger.Warn("Decision request for another lending program rejected", zap.Error(err))
+		c.JSON(decisionErr.HTTPStatus, gin.H{
+			"valid":   false,
+			"error":   decisionErr.Message,
+			"code":    decisionErr.Code,
+			"details": decisionErr.Description,
+		})
+		return
+	}
 
 	logger.Info("Request validation successful")
 
@@ -272,16 +404,18 @@ func (h *DecisionHandler) ValidateDecisionRequest(c *gin.Context) {
 	})
 }
 
-// GetDecisionRules handles GET /api/v1/decisions/rules
+// GetDecisionRules handles GET /api/v1/decisions/rules, listing the caller's lending program's
+// rules in force
 func (h *DecisionHandler) GetDecisionRules(c *gin.Context) {
 	logger := h.logger.With(
 		zap.String("endpoint", "get_decision_rules"),
 		zap.String("method", "GET"),
+		zap.String("tenant_id", tenantID(c)),
 	)
 
 	logger.Info("Retrieving decision rules")
 
-	rules, err := h.decisionService.GetDecisionRules(c.Request.Context())
+	rules, err := h.decisionService.GetDecisionRules(c.Request.Context(), tenantID(c))
 	if err != nil {
 		logger.Error("Failed to retrieve decision rules", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -316,6 +450,31 @@ func (h *DecisionHandler) ErrorHandler() gin.HandlerFunc {
 	})
 }
 
+// RequestDeadline is a middleware that bounds a request's context by the caller's deadline, sent
+// in the X-Request-Deadline header as an RFC 3339 timestamp, so work the caller has given up on
+// stops early. Requests without the header, or with one that does not parse, are not bounded.
+func (h *DecisionHandler) RequestDeadline() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("X-Request-Deadline")
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		deadline, err := time.Parse(time.RFC3339Nano, header)
+		if err != nil {
+			h.logger.Warn("Ignoring invalid request deadline", zap.String("deadline", header), zap.Error(err))
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithDeadline(c.Request.Context(), deadline)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
 // RequestLogger is a middleware for request logging
 func (h *DecisionHandler) RequestLogger() gin.HandlerFunc {
 	return gin.LoggerWithWriter(gin.DefaultWriter, "/health")
@@ -344,6 +503,7 @@ func (h *DecisionHandler) SetupRoutes(router *gin.Engine) {
 	router.Use(h.RequestLogger())
 	router.Use(h.ErrorHandler())
 	router.Use(h.CORSMiddleware())
+	router.Use(h.RequestDeadline())
 
 	// Health check
 	router.GET("/health", h.HealthCheck)
@@ -357,7 +517,9 @@ func (h *DecisionHandler) SetupRoutes(router *gin.Engine) {
 			decisions.POST("/validate", h.ValidateDecisionRequest)
 			decisions.GET("/rules", h.GetDecisionRules)
 			decisions.GET("/statistics", h.GetStatistics)
+			decisions.GET("/explanations/:decisionId", h.GetDecisionExplanation)
 			decisions.GET("/:applicationId", h.GetDecision)
+			decisions.GET("/:applicationId/replay", h.ReplayDecision)
 		}
 
 		customers := v1.Group("/customers")
@@ -0,0 +1,257 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/huuhoait/los-demo/services/decision-engine/application"
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// PolicyHandler handles HTTP requests for underwriting policy management
+type PolicyHandler struct {
+	policyService *application.PolicyService
+	logger        *zap.Logger
+}
+
+// NewPolicyHandler creates a new policy handler
+func NewPolicyHandler(policyService *application.PolicyService, logger *zap.Logger) *PolicyHandler {
+	return &PolicyHandler{
+		policyService: policyService,
+		logger:        logger,
+	}
+}
+
+// ListPolicies handles GET /api/v1/policies?status=DRAFT|PENDING_APPROVAL|APPROVED|RETIRED
+func (h *PolicyHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.policyService.ListPolicies(c.Request.Context(), tenantID(c), domain.PolicyStatus(c.Query("status")))
+	if err != nil {
+		h.respondError(c, "list_policies", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"policies": policies,
+		"count":    len(policies),
+	})
+}
+
+// GetActivePolicy handles GET /api/v1/policies/active, returning the caller's lending program's
+// policy in force now
+func (h *PolicyHandler) GetActivePolicy(c *gin.Context) {
+	policy, err := h.policyService.GetActivePolicy(tenantID(c))
+	if err != nil {
+		h.respondError(c, "get_active_policy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// CreatePolicy handles POST /api/v1/policies, creating a draft version
+func (h *PolicyHandler) CreatePolicy(c *gin.Context) {
+	if !requireActor(c) {
+		return
+	}
+
+	var request domain.PolicyRequest
+	if !h.bindPolicy(c, &request) {
+		return
+	}
+
+	policy, err := h.policyService.CreatePolicy(c.Request.Context(), tenantID(c), &request, c.GetHeader("X-User-ID"))
+	if err != nil {
+		h.respondError(c, "create_policy", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, policy)
+}
+
+// GetPolicy handles GET /api/v1/policies/:version
+func (h *PolicyHandler) GetPolicy(c *gin.Context) {
+	policy, err := h.policyService.GetPolicy(c.Request.Context(), tenantID(c), c.Param("version"))
+	if err != nil {
+		h.respondError(c, "get_policy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// UpdatePolicy handles PUT /api/v1/policies/:version, editing a draft version
+func (h *PolicyHandler) UpdatePolicy(c *gin.Context) {
+	if !requireActor(c) {
+		return
+	}
+
+	var request domain.PolicyRequest
+	if !h.bindPolicy(c, &request) {
+		return
+	}
+
+	policy, err := h.policyService.UpdateDraft(c.Request.Context(), tenantID(c), c.Param("version"), &request, c.GetHeader("X-User-ID"))
+	if err != nil {
+		h.respondError(c, "update_policy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// DeletePolicy handles DELETE /api/v1/policies/:version, discarding a draft version
+func (h *PolicyHandler) DeletePolicy(c *gin.Context) {
+	if !requireActor(c) {
+		return
+	}
+
+	if err := h.policyService.DiscardDraft(c.Request.Context(), tenantID(c), c.Param("version"), c.GetHeader("X-User-ID")); err != nil {
+		h.respondError(c, "delete_policy", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SubmitPolicy handles POST /api/v1/policies/:version/submit, sending a draft for approval
+func (h *PolicyHandler) SubmitPolicy(c *gin.Context) {
+	if !requireActor(c) {
+		return
+	}
+
+	policy, err := h.policyService.SubmitForApproval(c.Request.Context(), tenantID(c), c.Param("version"), c.GetHeader("X-User-ID"))
+	if err != nil {
+		h.respondError(c, "submit_policy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// ApprovePolicy handles POST /api/v1/policies/:version/approve; the checker may not be the maker
+func (h *PolicyHandler) ApprovePolicy(c *gin.Context) {
+	h.review(c, "approve_policy", h.policyService.ApprovePolicy)
+}
+
+// RejectPolicy handles POST /api/v1/policies/:version/reject, returning the version to draft
+func (h *PolicyHandler) RejectPolicy(c *gin.Context) {
+	h.review(c, "reject_policy", h.policyService.RejectPolicy)
+}
+
+// RetirePolicy handles POST /api/v1/policies/:version/retire, withdrawing an approved version
+// before it takes effect
+func (h *PolicyHandler) RetirePolicy(c *gin.Context) {
+	if !requireActor(c) {
+		return
+	}
+
+	policy, err := h.policyService.RetirePolicy(c.Request.Context(), tenantID(c), c.Param("version"), c.GetHeader("X-User-ID"))
+	if err != nil {
+		h.respondError(c, "retire_policy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// GetPolicyHistory handles GET /api/v1/policies/:version/history
+func (h *PolicyHandler) GetPolicyHistory(c *gin.Context) {
+	events, err := h.policyService.GetPolicyHistory(c.Request.Context(), tenantID(c), c.Param("version"))
+	if err != nil {
+		h.respondError(c, "get_policy_history", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"version": c.Param("version"),
+		"events":  events,
+		"count":   len(events),
+	})
+}
+
+// bindPolicy binds a policy definition
+func (h *PolicyHandler) bindPolicy(c *gin.Context, request *domain.PolicyRequest) bool {
+	if err := c.ShouldBindJSON(request); err != nil {
+		h.logger.Warn("Invalid policy payload", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// review runs a checker's review identified by the X-User-ID header, with an optional comment
+func (h *PolicyHandler) review(c *gin.Context, operation string, decide func(ctx context.Context, tenant, version, performedBy, comment string) (*domain.UnderwritingPolicy, error)) {
+	if !requireActor(c) {
+		return
+	}
+
+	var request domain.PolicyReviewRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request payload",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	policy, err := decide(c.Request.Context(), tenantID(c), c.Param("version"), c.GetHeader("X-User-ID"), request.Comment)
+	if err != nil {
+		h.respondError(c, operation, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// respondError maps service errors to error responses
+func (h *PolicyHandler) respondError(c *gin.Context, operation string, err error) {
+	logger := h.logger.With(
+		zap.String("endpoint", operation),
+		zap.String("policy_version", c.Param("version")),
+	)
+
+	if decisionErr, ok := err.(*domain.DecisionError); ok {
+		if decisionErr.HTTPStatus >= http.StatusInternalServerError {
+			logger.Error("Policy operation failed", zap.Error(err))
+		} else {
+			logger.Warn("Policy operation rejected", zap.String("code", decisionErr.Code), zap.String("details", decisionErr.Description))
+		}
+		c.JSON(decisionErr.HTTPStatus, gin.H{
+			"error":   decisionErr.Message,
+			"code":    decisionErr.Code,
+			"details": decisionErr.Description,
+		})
+		return
+	}
+
+	logger.Error("Policy operation failed", zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Internal server error",
+		"details": err.Error(),
+	})
+}
+
+// RegisterRoutes registers the underwriting policy routes
+func (h *PolicyHandler) RegisterRoutes(router *gin.Engine) {
+	policies := router.Group("/api/v1/policies")
+	{
+		policies.GET("", h.ListPolicies)
+		policies.POST("", h.CreatePolicy)
+		policies.GET("/active", h.GetActivePolicy)
+		policies.GET("/:version", h.GetPolicy)
+		policies.PUT("/:version", h.UpdatePolicy)
+		policies.DELETE("/:version", h.DeletePolicy)
+		policies.POST("/:version/submit", h.SubmitPolicy)
+		policies.POST("/:version/approve", h.ApprovePolicy)
+		policies.POST("/:version/reject", h.RejectPolicy)
+		policies.POST("/:version/retire", h.RetirePolicy)
+		policies.GET("/:version/history", h.GetPolicyHistory)
+	}
+}
@@ -0,0 +1,270 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/huuhoait/los-demo/services/decision-engine/application"
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// RulesHandler handles HTTP requests for managing versioned decision rules
+type RulesHandler struct {
+	ruleService *application.RuleService
+	simulator   *application.RuleSimulator
+	logger      *zap.Logger
+}
+
+// NewRulesHandler creates a new rules handler
+func NewRulesHandler(ruleService *application.RuleService, simulator *application.RuleSimulator, logger *zap.Logger) *RulesHandler {
+	return &RulesHandler{
+		ruleService: ruleService,
+		simulator:   simulator,
+		logger:      logger,
+	}
+}
+
+// ListRules handles GET /api/v1/rules?status=DRAFT|PUBLISHED|RETIRED
+func (h *RulesHandler) ListRules(c *gin.Context) {
+	rules, err := h.ruleService.ListRules(c.Request.Context(), tenantID(c), domain.RuleStatus(c.Query("status")))
+	if err != nil {
+		h.respondError(c, "list_rules", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rules": rules,
+		"count": len(rules),
+	})
+}
+
+// CreateRule handles POST /api/v1/rules
+func (h *RulesHandler) CreateRule(c *gin.Context) {
+	var request domain.RuleVersionRequest
+	if !h.bindRequest(c, &request) {
+		return
+	}
+
+	rule, err := h.ruleService.CreateRule(c.Request.Context(), tenantID(c), &request, c.GetHeader("X-User-ID"))
+	if err != nil {
+		h.respondError(c, "create_rule", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// GetRuleVersions handles GET /api/v1/rules/:ruleId/versions
+func (h *RulesHandler) GetRuleVersions(c *gin.Context) {
+	versions, err := h.ruleService.GetRuleVersions(c.Request.Context(), tenantID(c), c.Param("ruleId"))
+	if err != nil {
+		h.respondError(c, "get_rule_versions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"versions": versions,
+		"count":    len(versions),
+	})
+}
+
+// CreateVersion handles POST /api/v1/rules/:ruleId/versions
+func (h *RulesHandler) CreateVersion(c *gin.Context) {
+	var request domain.RuleVersionRequest
+	if !h.bindRequest(c, &request) {
+		return
+	}
+
+	rule, err := h.ruleService.CreateVersion(c.Request.Context(), tenantID(c), c.Param("ruleId"), &request, c.GetHeader("X-User-ID"))
+	if err != nil {
+		h.respondError(c, "create_rule_version", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// GetRuleVersion handles GET /api/v1/rules/:ruleId/versions/:version
+func (h *RulesHandler) GetRuleVersion(c *gin.Context) {
+	rule, err := h.ruleService.GetRuleVersion(c.Request.Context(), tenantID(c), c.Param("ruleId"), c.Param("version"))
+	if err != nil {
+		h.respondError(c, "get_rule_version", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// UpdateDraft handles PUT /api/v1/rules/:ruleId/versions/:version
+func (h *RulesHandler) UpdateDraft(c *gin.Context) {
+	var request domain.RuleVersionRequest
+	if !h.bindRequest(c, &request) {
+		return
+	}
+
+	rule, err := h.ruleService.UpdateDraft(c.Request.Context(), tenantID(c), c.Param("ruleId"), c.Param("version"), &request, c.GetHeader("X-User-ID"))
+	if err != nil {
+		h.respondError(c, "update_rule_draft", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DiscardDraft handles DELETE /api/v1/rules/:ruleId/versions/:version
+func (h *RulesHandler) DiscardDraft(c *gin.Context) {
+	if !requireActor(c) {
+		return
+	}
+
+	if err := h.ruleService.DiscardDraft(c.Request.Context(), tenantID(c), c.Param("ruleId"), c.Param("version"), c.GetHeader("X-User-ID")); err != nil {
+		h.respondError(c, "discard_rule_draft", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PublishVersion handles POST /api/v1/rules/:ruleId/versions/:version/publish
+func (h *RulesHandler) PublishVersion(c *gin.Context) {
+	if !requireActor(c) {
+		return
+	}
+
+	rule, err := h.ruleService.PublishVersion(c.Request.Context(), tenantID(c), c.Param("ruleId"), c.Param("version"), c.GetHeader("X-User-ID"))
+	if err != nil {
+		h.respondError(c, "publish_rule_version", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// RetireVersion handles POST /api/v1/rules/:ruleId/versions/:version/retire
+func (h *RulesHandler) RetireVersion(c *gin.Context) {
+	if !requireActor(c) {
+		return
+	}
+
+	rule, err := h.ruleService.RetireVersion(c.Request.Context(), tenantID(c), c.Param("ruleId"), c.Param("version"), c.GetHeader("X-User-ID"))
+	if err != nil {
+		h.respondError(c, "retire_rule_version", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// SimulateRules handles POST /api/v1/rules/simulate, backtesting a proposed rule set against
+// the caller's lending program's past decision requests without changing any rules
+func (h *RulesHandler) SimulateRules(c *gin.Context) {
+	var request domain.SimulationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.logger.Warn("Invalid simulation payload", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	request.TenantID = tenantID(c)
+	result, err := h.simulator.Simulate(c.Request.Context(), &request)
+	if err != nil {
+		h.respondError(c, "simulate_rules", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetRuleHistory handles GET /api/v1/rules/:ruleId/history
+func (h *RulesHandler) GetRuleHistory(c *gin.Context) {
+	events, err := h.ruleService.GetRuleHistory(c.Request.Context(), tenantID(c), c.Param("ruleId"))
+	if err != nil {
+		h.respondError(c, "get_rule_history", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"count":  len(events),
+	})
+}
+
+// bindRequest binds a rule version body and checks the caller is identified
+func (h *RulesHandler) bindRequest(c *gin.Context, request *domain.RuleVersionRequest) bool {
+	if !requireActor(c) {
+		return false
+	}
+
+	if err := c.ShouldBindJSON(request); err != nil {
+		h.logger.Warn("Invalid rule payload", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// requireActor rejects changes without the X-User-ID header identifying who made them
+func requireActor(c *gin.Context) bool {
+	if c.GetHeader("X-User-ID") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "X-User-ID header is required",
+			"details": "Changes are recorded against the user who made them",
+		})
+		return false
+	}
+	return true
+}
+
+// respondError maps service errors to error responses
+func (h *RulesHandler) respondError(c *gin.Context, operation string, err error) {
+	logger := h.logger.With(
+		zap.String("endpoint", operation),
+		zap.String("rule_id", c.Param("ruleId")),
+		zap.String("tenant_id", tenantID(c)),
+	)
+
+	if decisionErr, ok := err.(*domain.DecisionError); ok {
+		if decisionErr.HTTPStatus >= http.StatusInternalServerError {
+			logger.Error("Rule operation failed", zap.Error(err))
+		} else {
+			logger.Warn("Rule operation rejected", zap.String("code", decisionErr.Code), zap.String("details", decisionErr.Description))
+		}
+		c.JSON(decisionErr.HTTPStatus, gin.H{
+			"error":   decisionErr.Message,
+			"code":    decisionErr.Code,
+			"details": decisionErr.Description,
+		})
+		return
+	}
+
+	logger.Error("Rule operation failed", zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Internal server error",
+		"details": err.Error(),
+	})
+}
+
+// RegisterRoutes registers the rule management routes
+func (h *RulesHandler) RegisterRoutes(router *gin.Engine) {
+	rules := router.Group("/api/v1/rules")
+	{
+		rules.GET("", h.ListRules)
+		rules.POST("", h.CreateRule)
+		rules.POST("/simulate", h.SimulateRules)
+		rules.GET("/:ruleId/history", h.GetRuleHistory)
+		rules.GET("/:ruleId/versions", h.GetRuleVersions)
+		rules.POST("/:ruleId/versions", h.CreateVersion)
+		rules.GET("/:ruleId/versions/:version", h.GetRuleVersion)
+		rules.PUT("/:ruleId/versions/:version", h.UpdateDraft)
+		rules.DELETE("/:ruleId/versions/:version", h.DiscardDraft)
+		rules.POST("/:ruleId/versions/:version/publish", h.PublishVersion)
+		rules.POST("/:ruleId/versions/:version/retire", h.RetireVersion)
+	}
+}
@@ -0,0 +1,213 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/huuhoait/los-demo/services/decision-engine/application"
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// ScorecardHandler handles HTTP requests for scorecard management
+type ScorecardHandler struct {
+	scorecardService *application.ScorecardService
+	logger           *zap.Logger
+}
+
+// NewScorecardHandler creates a new scorecard handler
+func NewScorecardHandler(scorecardService *application.ScorecardService, logger *zap.Logger) *ScorecardHandler {
+	return &ScorecardHandler{
+		scorecardService: scorecardService,
+		logger:           logger,
+	}
+}
+
+// ListScorecards handles GET /api/v1/scorecards?status=DRAFT|ACTIVE|RETIRED
+func (h *ScorecardHandler) ListScorecards(c *gin.Context) {
+	scorecards, err := h.scorecardService.ListScorecards(c.Request.Context(), domain.ScorecardStatus(c.Query("status")))
+	if err != nil {
+		h.respondError(c, "list_scorecards", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scorecards": scorecards,
+		"count":      len(scorecards),
+	})
+}
+
+// CreateScorecard handles POST /api/v1/scorecards
+func (h *ScorecardHandler) CreateScorecard(c *gin.Context) {
+	if !requireActor(c) {
+		return
+	}
+
+	var request domain.ScorecardRequest
+	if !h.bindScorecard(c, &request) {
+		return
+	}
+
+	scorecard, err := h.scorecardService.CreateScorecard(c.Request.Context(), &request, c.GetHeader("X-User-ID"))
+	if err != nil {
+		h.respondError(c, "create_scorecard", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, scorecard)
+}
+
+// GetScorecard handles GET /api/v1/scorecards/:scorecardId
+func (h *ScorecardHandler) GetScorecard(c *gin.Context) {
+	scorecard, err := h.scorecardService.GetScorecard(c.Request.Context(), c.Param("scorecardId"))
+	if err != nil {
+		h.respondError(c, "get_scorecard", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, scorecard)
+}
+
+// UpdateScorecard handles PUT /api/v1/scorecards/:scorecardId
+func (h *ScorecardHandler) UpdateScorecard(c *gin.Context) {
+	if !requireActor(c) {
+		return
+	}
+
+	var request domain.ScorecardRequest
+	if !h.bindScorecard(c, &request) {
+		return
+	}
+
+	scorecard, err := h.scorecardService.UpdateScorecard(c.Request.Context(), c.Param("scorecardId"), &request, c.GetHeader("X-User-ID"))
+	if err != nil {
+		h.respondError(c, "update_scorecard", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, scorecard)
+}
+
+// DeleteScorecard handles DELETE /api/v1/scorecards/:scorecardId
+func (h *ScorecardHandler) DeleteScorecard(c *gin.Context) {
+	if !requireActor(c) {
+		return
+	}
+
+	if err := h.scorecardService.DeleteScorecard(c.Request.Context(), c.Param("scorecardId"), c.GetHeader("X-User-ID")); err != nil {
+		h.respondError(c, "delete_scorecard", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ActivateScorecard handles POST /api/v1/scorecards/:scorecardId/activate
+func (h *ScorecardHandler) ActivateScorecard(c *gin.Context) {
+	h.changeStatus(c, "activate_scorecard", h.scorecardService.ActivateScorecard)
+}
+
+// RetireScorecard handles POST /api/v1/scorecards/:scorecardId/retire
+func (h *ScorecardHandler) RetireScorecard(c *gin.Context) {
+	h.changeStatus(c, "retire_scorecard", h.scorecardService.RetireScorecard)
+}
+
+// TestScore handles POST /api/v1/scorecards/:scorecardId/score, scoring a decision request
+// without making a decision
+func (h *ScorecardHandler) TestScore(c *gin.Context) {
+	var request domain.DecisionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Request validation failed",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.scorecardService.TestScore(c.Request.Context(), c.Param("scorecardId"), &request)
+	if err != nil {
+		h.respondError(c, "test_scorecard", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// bindScorecard binds a scorecard definition
+func (h *ScorecardHandler) bindScorecard(c *gin.Context, request *domain.ScorecardRequest) bool {
+	if err := c.ShouldBindJSON(request); err != nil {
+		h.logger.Warn("Invalid scorecard payload", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// changeStatus runs a status change identified by the X-User-ID header
+func (h *ScorecardHandler) changeStatus(c *gin.Context, operation string, change func(ctx context.Context, id, performedBy string) (*domain.Scorecard, error)) {
+	if !requireActor(c) {
+		return
+	}
+
+	scorecard, err := change(c.Request.Context(), c.Param("scorecardId"), c.GetHeader("X-User-ID"))
+	if err != nil {
+		h.respondError(c, operation, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, scorecard)
+}
+
+// respondError maps service errors to error responses
+func (h *ScorecardHandler) respondError(c *gin.Context, operation string, err error) {
+	logger := h.logger.With(
+		zap.String("endpoint", operation),
+		zap.String("scorecard_id", c.Param("scorecardId")),
+	)
+
+	if decisionErr, ok := err.(*domain.DecisionError); ok {
+		if decisionErr.HTTPStatus >= http.StatusInternalServerError {
+			logger.Error("Scorecard operation failed", zap.Error(err))
+		} else {
+			logger.Warn("Scorecard operation rejected", zap.String("code", decisionErr.Code), zap.String("details", decisionErr.Description))
+		}
+		c.JSON(decisionErr.HTTPStatus, gin.H{
+			"error":   decisionErr.Message,
+			"code":    decisionErr.Code,
+			"details": decisionErr.Description,
+		})
+		return
+	}
+
+	logger.Error("Scorecard operation failed", zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Internal server error",
+		"details": err.Error(),
+	})
+}
+
+// RegisterRoutes registers the scorecard management routes
+func (h *ScorecardHandler) RegisterRoutes(router *gin.Engine) {
+	scorecards := router.Group("/api/v1/scorecards")
+	{
+		scorecards.GET("", h.ListScorecards)
+		scorecards.POST("", h.CreateScorecard)
+		scorecards.GET("/:scorecardId", h.GetScorecard)
+		scorecards.PUT("/:scorecardId", h.UpdateScorecard)
+		scorecards.DELETE("/:scorecardId", h.DeleteScorecard)
+		scorecards.POST("/:scorecardId/activate", h.ActivateScorecard)
+		scorecards.POST("/:scorecardId/retire", h.RetireScorecard)
+		scorecards.POST("/:scorecardId/score", h.TestScore)
+	}
+}
@@ -0,0 +1,216 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/huuhoait/los-demo/services/decision-engine/application"
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// StrategyHandler handles HTTP requests for champion/challenger decision strategies
+type StrategyHandler struct {
+	strategyService *application.StrategyService
+	logger          *zap.Logger
+}
+
+// NewStrategyHandler creates a new strategy handler
+func NewStrategyHandler(strategyService *application.StrategyService, logger *zap.Logger) *StrategyHandler {
+	return &StrategyHandler{
+		strategyService: strategyService,
+		logger:          logger,
+	}
+}
+
+// TrafficRequest changes a strategy's traffic split
+type TrafficRequest struct {
+	TrafficPercent *int `json:"traffic_percent" binding:"required"`
+}
+
+// ListStrategies handles GET /api/v1/strategies?status=PAUSED|ACTIVE|ENDED
+func (h *StrategyHandler) ListStrategies(c *gin.Context) {
+	strategies, err := h.strategyService.ListStrategies(c.Request.Context(), domain.StrategyStatus(c.Query("status")))
+	if err != nil {
+		h.respondError(c, "list_strategies", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"strategies": strategies,
+		"count":      len(strategies),
+	})
+}
+
+// CreateStrategy handles POST /api/v1/strategies
+func (h *StrategyHandler) CreateStrategy(c *gin.Context) {
+	if !requireActor(c) {
+		return
+	}
+
+	var request domain.StrategyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.logger.Warn("Invalid strategy payload", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	strategy, err := h.strategyService.CreateStrategy(c.Request.Context(), &request, c.GetHeader("X-User-ID"))
+	if err != nil {
+		h.respondError(c, "create_strategy", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, strategy)
+}
+
+// GetStrategy handles GET /api/v1/strategies/:strategyId
+func (h *StrategyHandler) GetStrategy(c *gin.Context) {
+	strategy, err := h.strategyService.GetStrategy(c.Request.Context(), c.Param("strategyId"))
+	if err != nil {
+		h.respondError(c, "get_strategy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, strategy)
+}
+
+// SetTraffic handles PUT /api/v1/strategies/:strategyId/traffic
+func (h *StrategyHandler) SetTraffic(c *gin.Context) {
+	if !requireActor(c) {
+		return
+	}
+
+	var request TrafficRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	strategy, err := h.strategyService.SetTraffic(c.Request.Context(), c.Param("strategyId"), *request.TrafficPercent, c.GetHeader("X-User-ID"))
+	if err != nil {
+		h.respondError(c, "set_strategy_traffic", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, strategy)
+}
+
+// ActivateStrategy handles POST /api/v1/strategies/:strategyId/activate
+func (h *StrategyHandler) ActivateStrategy(c *gin.Context) {
+	h.changeStatus(c, "activate_strategy", h.strategyService.ActivateStrategy)
+}
+
+// PauseStrategy handles POST /api/v1/strategies/:strategyId/pause
+func (h *StrategyHandler) PauseStrategy(c *gin.Context) {
+	h.changeStatus(c, "pause_strategy", h.strategyService.PauseStrategy)
+}
+
+// EndStrategy handles POST /api/v1/strategies/:strategyId/end
+func (h *StrategyHandler) EndStrategy(c *gin.Context) {
+	h.changeStatus(c, "end_strategy", h.strategyService.EndStrategy)
+}
+
+// GetReport handles GET /api/v1/strategies/:strategyId/report?from=&to= (RFC 3339 timestamps)
+func (h *StrategyHandler) GetReport(c *gin.Context) {
+	from, ok := h.parseTime(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := h.parseTime(c, "to")
+	if !ok {
+		return
+	}
+
+	report, err := h.strategyService.GetReport(c.Request.Context(), c.Param("strategyId"), from, to)
+	if err != nil {
+		h.respondError(c, "get_strategy_report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// changeStatus runs a status change identified by the X-User-ID header
+func (h *StrategyHandler) changeStatus(c *gin.Context, operation string, change func(ctx context.Context, id, performedBy string) (*domain.DecisionStrategy, error)) {
+	if !requireActor(c) {
+		return
+	}
+
+	strategy, err := change(c.Request.Context(), c.Param("strategyId"), c.GetHeader("X-User-ID"))
+	if err != nil {
+		h.respondError(c, operation, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, strategy)
+}
+
+// parseTime parses an optional RFC 3339 query parameter
+func (h *StrategyHandler) parseTime(c *gin.Context, name string) (*time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid " + name + " parameter",
+			"details": "Use an RFC 3339 timestamp, e.g. 2024-01-31T00:00:00Z",
+		})
+		return nil, false
+	}
+	return &parsed, true
+}
+
+// respondError maps service errors to error responses
+func (h *StrategyHandler) respondError(c *gin.Context, operation string, err error) {
+	logger := h.logger.With(
+		zap.String("endpoint", operation),
+		zap.String("strategy_id", c.Param("strategyId")),
+	)
+
+	if decisionErr, ok := err.(*domain.DecisionError); ok {
+		if decisionErr.HTTPStatus >= http.StatusInternalServerError {
+			logger.Error("Strategy operation failed", zap.Error(err))
+		} else {
+			logger.Warn("Strategy operation rejected", zap.String("code", decisionErr.Code), zap.String("details", decisionErr.Description))
+		}
+		c.JSON(decisionErr.HTTPStatus, gin.H{
+			"error":   decisionErr.Message,
+			"code":    decisionErr.Code,
+			"details": decisionErr.Description,
+		})
+		return
+	}
+
+	logger.Error("Strategy operation failed", zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Internal server error",
+		"details": err.Error(),
+	})
+}
+
+// RegisterRoutes registers the strategy management routes
+func (h *StrategyHandler) RegisterRoutes(router *gin.Engine) {
+	strategies := router.Group("/api/v1/strategies")
+	{
+		strategies.GET("", h.ListStrategies)
+		strategies.POST("", h.CreateStrategy)
+		strategies.GET("/:strategyId", h.GetStrategy)
+		strategies.GET("/:strategyId/report", h.GetReport)
+		strategies.PUT("/:strategyId/traffic", h.SetTraffic)
+		strategies.POST("/:strategyId/activate", h.ActivateStrategy)
+		strategies.POST("/:strategyId/pause", h.PauseStrategy)
+		strategies.POST("/:strategyId/end", h.EndStrategy)
+	}
+}
@@ -0,0 +1,61 @@
+package interfaces
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/huuhoait/los-demo/services/decision-engine/domain"
+	"go.uber.org/zap"
+)
+
+// tenantContextKey is the gin context key holding the caller's lending program
+const tenantContextKey = "tenant_id"
+
+// TenantScope is a middleware that puts each request under the lending program named in the
+// X-Tenant-ID header, or the default program when there is none. Requests naming a program that
+// is not configured are rejected before they reach a handler.
+func TenantScope(tenants *domain.TenantRegistry, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenant, err := tenants.Resolve(c.GetHeader("X-Tenant-ID"))
+		if err != nil {
+			decisionErr := err.(*domain.DecisionError)
+			logger.Warn("Request for an unknown lending program rejected",
+				zap.String("tenant_id", c.GetHeader("X-Tenant-ID")),
+				zap.String("path", c.Request.URL.Path))
+			c.AbortWithStatusJSON(decisionErr.HTTPStatus, gin.H{
+				"error":   decisionErr.Message,
+				"code":    decisionErr.Code,
+				"details": decisionErr.Description,
+			})
+			return
+		}
+
+		c.Set(tenantContextKey, tenant.ID)
+		c.Next()
+	}
+}
+
+// tenantID returns the lending program the request is scoped to
+func tenantID(c *gin.Context) string {
+	return domain.TenantOrDefault(c.GetString(tenantContextKey))
+}
+
+// scopeDecisionRequests puts decision requests under the caller's lending program. A request
+// naming a different program is refused, so a caller can never decide under another program's
+// configuration.
+func scopeDecisionRequests(c *gin.Context, requests ...*domain.DecisionRequest) error {
+	tenant := tenantID(c)
+	for _, request := range requests {
+		if request.TenantID != "" && request.TenantID != tenant {
+			return &domain.DecisionError{
+				Code:        domain.ERROR_TENANT_MISMATCH,
+				Message:     "Lending program mismatch",
+				Description: fmt.Sprintf("Application %s names lending program %s but the request is scoped to %s", request.ApplicationID, request.TenantID, tenant),
+				HTTPStatus:  http.StatusForbidden,
+			}
+		}
+		request.TenantID = tenant
+	}
+	return nil
+}
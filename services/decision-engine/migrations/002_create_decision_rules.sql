@@ -0,0 +1,51 @@
+-- Decision rules evaluated by the rules engine
+
+CREATE TABLE IF NOT EXISTS decision_rules (
+    id VARCHAR(100) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    category VARCHAR(50) NOT NULL,
+    priority INTEGER NOT NULL,               -- lower priorities are evaluated first
+    expression TEXT NOT NULL DEFAULT '',     -- govaluate expression; empty when conditions are used
+    conditions JSONB NOT NULL DEFAULT '[]',
+    action JSONB NOT NULL,
+    knockout BOOLEAN NOT NULL DEFAULT FALSE, -- a match stops evaluation with the rule's decision
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    metadata JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_decision_rules_active_priority ON decision_rules(active, priority);
+CREATE INDEX IF NOT EXISTS idx_decision_rules_category ON decision_rules(category);
+
+-- Default credit policy
+INSERT INTO decision_rules (id, name, description, category, priority, expression, action, knockout) VALUES
+    ('knockout_min_credit_score', 'Minimum Credit Score', 'Deny applications with a credit score below 600', 'CREDIT', 10,
+     'credit_score < 600',
+     '{"type": "DECISION", "decision": "DENY", "reason": "Credit score below the 600 minimum"}', TRUE),
+    ('knockout_bankruptcy', 'Recent Bankruptcy', 'Deny applicants with a bankruptcy on file', 'CREDIT', 20,
+     'bankruptcies > 0',
+     '{"type": "DECISION", "decision": "DENY", "reason": "Bankruptcy on the credit file"}', TRUE),
+    ('knockout_no_income', 'No Verifiable Income', 'Deny unemployed applicants without sufficient other income', 'EMPLOYMENT', 30,
+     'employment_type == ''unemployed'' && annual_income < 25000',
+     '{"type": "DECISION", "decision": "DENY", "reason": "No verifiable source of income"}', TRUE),
+    ('max_dti_ratio', 'Maximum DTI Ratio', 'Deny applications with a debt-to-income ratio above 45%', 'DEBT', 100,
+     'dti_ratio > 0.45',
+     '{"type": "DECISION", "decision": "DENY", "reason": "Debt-to-income ratio exceeds 45%"}', FALSE),
+    ('min_annual_income', 'Minimum Annual Income', 'Deny applications with annual income below 25,000', 'INCOME', 110,
+     'annual_income < 25000',
+     '{"type": "DECISION", "decision": "DENY", "reason": "Annual income below the 25,000 minimum"}', FALSE),
+    ('high_risk_review', 'High Risk Review', 'Send high-risk applications to an underwriter', 'GENERAL', 200,
+     'overall_score > 0.6',
+     '{"type": "DECISION", "decision": "MANUAL_REVIEW", "reason": "Overall risk score requires underwriter review", "require_review": true}', FALSE),
+    ('max_loan_to_income', 'Maximum Loan to Income', 'Limit unsecured loans to half of annual income', 'INCOME', 300,
+     '!is_secured && loan_to_income > 0.5',
+     '{"type": "ADJUSTMENT", "reason": "Unsecured loans are limited to 50% of annual income", "adjustments": {"max_loan_to_income": 0.5}}', FALSE),
+    ('vehicle_gap_insurance', 'Vehicle Gap Insurance', 'Require gap insurance on vehicles financed above their value', 'COLLATERAL', 400,
+     'collateral_type == ''vehicle'' && ltv_ratio > 1.0',
+     '{"type": "REQUIREMENT", "reason": "Gap insurance required for vehicles financed above their value"}', FALSE),
+    ('late_payment_history', 'Late Payment History', 'Flag applicants with repeated late payments', 'CREDIT', 500,
+     'late_payments > 3',
+     '{"type": "FLAG", "reason": "More than three late payments on the credit file"}', FALSE)
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,94 @@
+-- Version decision rules: every row is one version of a rule, moving from DRAFT to PUBLISHED to
+-- RETIRED, and every change is appended to decision_rule_events
+
+ALTER TABLE decision_rules
+    ADD COLUMN IF NOT EXISTS version VARCHAR(20) NOT NULL DEFAULT '1.0.0',
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'DRAFT',
+    ADD COLUMN IF NOT EXISTS effective_from TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS created_by VARCHAR(255) NOT NULL DEFAULT 'system',
+    ADD COLUMN IF NOT EXISTS published_by VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS published_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS retired_at TIMESTAMP WITH TIME ZONE;
+
+-- Rules seeded before versioning become published 1.0.0 versions
+UPDATE decision_rules
+SET status = CASE WHEN active THEN 'PUBLISHED' ELSE 'RETIRED' END,
+    effective_from = created_at,
+    published_by = 'system',
+    published_at = created_at,
+    retired_at = CASE WHEN active THEN NULL ELSE updated_at END;
+
+ALTER TABLE decision_rules DROP CONSTRAINT IF EXISTS decision_rules_pkey;
+ALTER TABLE decision_rules ADD PRIMARY KEY (id, version);
+ALTER TABLE decision_rules DROP COLUMN IF EXISTS active;
+ALTER TABLE decision_rules ADD CONSTRAINT chk_decision_rules_status CHECK (status IN ('DRAFT', 'PUBLISHED', 'RETIRED'));
+
+DROP INDEX IF EXISTS idx_decision_rules_active_priority;
+CREATE INDEX IF NOT EXISTS idx_decision_rules_status ON decision_rules(status, priority);
+
+-- Published and retired versions are frozen: only their expiry, status and retirement may change,
+-- and they may not be deleted
+CREATE OR REPLACE FUNCTION protect_published_decision_rules() RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.status = 'DRAFT' THEN
+        IF TG_OP = 'DELETE' THEN
+            RETURN OLD;
+        END IF;
+        RETURN NEW;
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RAISE EXCEPTION 'decision rule % version % is % and cannot be deleted', OLD.id, OLD.version, OLD.status;
+    END IF;
+
+    IF NEW.name IS DISTINCT FROM OLD.name
+        OR NEW.description IS DISTINCT FROM OLD.description
+        OR NEW.category IS DISTINCT FROM OLD.category
+        OR NEW.priority IS DISTINCT FROM OLD.priority
+        OR NEW.expression IS DISTINCT FROM OLD.expression
+        OR NEW.conditions IS DISTINCT FROM OLD.conditions
+        OR NEW.action IS DISTINCT FROM OLD.action
+        OR NEW.knockout IS DISTINCT FROM OLD.knockout
+        OR NEW.effective_from IS DISTINCT FROM OLD.effective_from
+        OR (OLD.status = 'RETIRED' AND NEW.status <> 'RETIRED') THEN
+        RAISE EXCEPTION 'decision rule % version % is % and cannot be changed', OLD.id, OLD.version, OLD.status;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_protect_published_decision_rules ON decision_rules;
+CREATE TRIGGER trg_protect_published_decision_rules
+    BEFORE UPDATE OR DELETE ON decision_rules
+    FOR EACH ROW EXECUTE FUNCTION protect_published_decision_rules();
+
+-- Append-only history of rule changes
+CREATE TABLE IF NOT EXISTS decision_rule_events (
+    id BIGSERIAL PRIMARY KEY,
+    rule_id VARCHAR(100) NOT NULL,
+    version VARCHAR(20) NOT NULL,
+    event VARCHAR(20) NOT NULL,
+    snapshot JSONB,  -- the version as it was after the change
+    performed_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_decision_rule_events_rule ON decision_rule_events(rule_id, created_at);
+
+CREATE OR REPLACE FUNCTION reject_decision_rule_event_changes() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'decision rule history is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_decision_rule_events_append_only ON decision_rule_events;
+CREATE TRIGGER trg_decision_rule_events_append_only
+    BEFORE UPDATE OR DELETE ON decision_rule_events
+    FOR EACH ROW EXECUTE FUNCTION reject_decision_rule_event_changes();
+
+INSERT INTO decision_rule_events (rule_id, version, event, performed_by, created_at)
+SELECT id, version, status, 'system', created_at FROM decision_rules;
+
+-- Decisions record the exact rule versions they were evaluated with
+ALTER TABLE decisions ADD COLUMN IF NOT EXISTS rule_versions JSONB;
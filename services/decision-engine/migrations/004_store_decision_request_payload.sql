@@ -0,0 +1,4 @@
+-- Keep the full decision request so past applications can be replayed against proposed rules
+-- (POST /api/v1/rules/simulate). Requests saved before this migration are rebuilt from their
+-- columns, without collateral or additional data.
+ALTER TABLE decision_requests ADD COLUMN IF NOT EXISTS request_payload JSONB;
@@ -0,0 +1,40 @@
+-- Champion/challenger strategies: a challenger rule set shadows the published (champion) rules on
+-- a share of decision traffic, and its decisions are recorded for comparison but never enforced
+
+CREATE TABLE IF NOT EXISTS decision_strategies (
+    id VARCHAR(100) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    challenger JSONB NOT NULL,
+    traffic_percent INTEGER NOT NULL DEFAULT 0 CHECK (traffic_percent BETWEEN 0 AND 100),
+    status VARCHAR(20) NOT NULL DEFAULT 'PAUSED' CHECK (status IN ('PAUSED', 'ACTIVE', 'ENDED')),
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    ended_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Only one challenger runs at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_decision_strategies_one_active
+    ON decision_strategies (status) WHERE status = 'ACTIVE';
+
+CREATE TABLE IF NOT EXISTS challenger_decisions (
+    id BIGSERIAL PRIMARY KEY,
+    strategy_id VARCHAR(100) NOT NULL REFERENCES decision_strategies(id),
+    application_id VARCHAR(255) NOT NULL,
+    risk_category VARCHAR(20) NOT NULL,
+    champion_decision VARCHAR(50) NOT NULL,
+    challenger_decision VARCHAR(50) NOT NULL,
+    champion_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    challenger_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    champion_expected_loss DECIMAL(15,2) NOT NULL DEFAULT 0,
+    challenger_expected_loss DECIMAL(15,2) NOT NULL DEFAULT 0,
+    challenger_reason TEXT,
+    champion_rule_versions JSONB,
+    challenger_rule_versions JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_challenger_decisions_strategy ON challenger_decisions(strategy_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_challenger_decisions_application ON challenger_decisions(application_id);
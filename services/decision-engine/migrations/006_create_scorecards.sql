@@ -0,0 +1,23 @@
+-- Points-based scorecards: characteristics award points by attribute bin, and the active
+-- scorecard's score is blended into the overall risk score alongside the raw bureau score
+
+CREATE TABLE IF NOT EXISTS scorecards (
+    id VARCHAR(100) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'DRAFT' CHECK (status IN ('DRAFT', 'ACTIVE', 'RETIRED')),
+    base_points INTEGER NOT NULL DEFAULT 0,
+    characteristics JSONB NOT NULL,
+    cutoff_bands JSONB NOT NULL,
+    risk_weight DECIMAL(5,4) NOT NULL DEFAULT 0.5 CHECK (risk_weight BETWEEN 0 AND 1),
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    activated_by VARCHAR(255),
+    activated_at TIMESTAMP WITH TIME ZONE,
+    retired_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Only one scorecard scores applications at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_scorecards_one_active
+    ON scorecards (status) WHERE status = 'ACTIVE';
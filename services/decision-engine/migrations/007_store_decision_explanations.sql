@@ -0,0 +1,5 @@
+-- Keep the rules that matched each decision and the ranked adverse action reasons given for
+-- denials and counteroffers (GET /api/v1/decisions/explanations/:decisionId). Decisions saved
+-- before this migration have their reasons rebuilt from their stored risk assessment.
+ALTER TABLE decisions ADD COLUMN IF NOT EXISTS rule_hits JSONB;
+ALTER TABLE decisions ADD COLUMN IF NOT EXISTS adverse_action_reasons JSONB;
@@ -0,0 +1,32 @@
+-- Tri-merge credit reports: every raw bureau response is kept as received alongside the merged
+-- report built from the normalized responses
+
+CREATE TABLE IF NOT EXISTS tri_merge_reports (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    ssn_token VARCHAR(255) NOT NULL,
+    application_id VARCHAR(255),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('COMPLETE', 'PARTIAL')),
+    score_policy VARCHAR(20) NOT NULL CHECK (score_policy IN ('MIDDLE', 'LOWEST')),
+    selected_score INTEGER NOT NULL,
+    selected_bureau VARCHAR(20) NOT NULL,
+    merged_report JSONB NOT NULL,
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_tri_merge_reports_user_id ON tri_merge_reports (user_id);
+CREATE INDEX IF NOT EXISTS idx_tri_merge_reports_application_id ON tri_merge_reports (application_id);
+
+CREATE TABLE IF NOT EXISTS credit_bureau_reports (
+    id BIGSERIAL PRIMARY KEY,
+    merge_id BIGINT NOT NULL REFERENCES tri_merge_reports(id),
+    bureau VARCHAR(20) NOT NULL CHECK (bureau IN ('EXPERIAN', 'EQUIFAX', 'TRANSUNION')),
+    user_id VARCHAR(255) NOT NULL,
+    ssn_token VARCHAR(255) NOT NULL,
+    raw_report JSONB NOT NULL,
+    pulled_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_credit_bureau_reports_merge_id ON credit_bureau_reports (merge_id);
+CREATE INDEX IF NOT EXISTS idx_credit_bureau_reports_token_bureau ON credit_bureau_reports (ssn_token, bureau, pulled_at DESC);
@@ -0,0 +1,5 @@
+-- Reuse stored bureau reports within the cache TTL instead of pulling the bureau again. Each
+-- report keeps its normalized form for reuse; a merge built from a cached report records the
+-- report it reused.
+ALTER TABLE credit_bureau_reports ADD COLUMN IF NOT EXISTS normalized_report JSONB;
+ALTER TABLE credit_bureau_reports ADD COLUMN IF NOT EXISTS source_report_id BIGINT REFERENCES credit_bureau_reports(id);
@@ -0,0 +1,2 @@
+-- Reports pulled from a single bureau (with failover) are stored outside any tri-merge
+ALTER TABLE credit_bureau_reports ALTER COLUMN merge_id DROP NOT NULL;
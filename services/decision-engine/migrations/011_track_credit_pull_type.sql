@@ -0,0 +1,7 @@
+-- Record whether each report came from a soft (pre-qualification) or hard pull. Reports pulled
+-- before pull types were tracked were all hard pulls.
+ALTER TABLE credit_bureau_reports ADD COLUMN IF NOT EXISTS pull_type VARCHAR(10) NOT NULL DEFAULT 'HARD'
+    CHECK (pull_type IN ('SOFT', 'HARD'));
+ALTER TABLE credit_bureau_reports ADD COLUMN IF NOT EXISTS product VARCHAR(100);
+ALTER TABLE tri_merge_reports ADD COLUMN IF NOT EXISTS pull_type VARCHAR(10) NOT NULL DEFAULT 'HARD'
+    CHECK (pull_type IN ('SOFT', 'HARD'));
@@ -0,0 +1,4 @@
+-- Keep the request each decision was made on, so it can be replayed exactly
+-- (GET /api/v1/decisions/:id/replay). decision_requests holds only the latest request for each
+-- application; decisions saved before this migration are replayed from it.
+ALTER TABLE decisions ADD COLUMN IF NOT EXISTS request_payload JSONB;
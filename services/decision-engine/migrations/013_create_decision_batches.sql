@@ -0,0 +1,43 @@
+-- Batch decisioning: applications submitted to POST /api/v1/decisions/batch are decided
+-- asynchronously by a job; each application's outcome is kept as an item result pointing at the
+-- decision saved for it. Running jobs touch updated_at as they save progress; a job whose
+-- instance stopped goes stale and is marked failed.
+
+CREATE TABLE IF NOT EXISTS decision_batch_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    purpose VARCHAR(30) NOT NULL CHECK (purpose IN ('PORTFOLIO_RESCORE', 'PRE_APPROVAL')),
+    reference VARCHAR(255),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('PENDING', 'RUNNING', 'COMPLETED', 'FAILED')),
+    concurrency INTEGER NOT NULL,
+    total INTEGER NOT NULL,
+    processed INTEGER NOT NULL DEFAULT 0,
+    succeeded INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    decision_counts JSONB NOT NULL DEFAULT '{}',
+    error TEXT,
+    requested_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_decision_batch_jobs_status ON decision_batch_jobs (status, updated_at);
+
+CREATE TABLE IF NOT EXISTS decision_batch_results (
+    job_id BIGINT NOT NULL REFERENCES decision_batch_jobs(id),
+    item_index INTEGER NOT NULL,
+    application_id VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('SUCCEEDED', 'FAILED')),
+    decision_id BIGINT REFERENCES decisions(id),
+    decision VARCHAR(20),
+    risk_category VARCHAR(20),
+    max_amount DECIMAL(15,2),
+    interest_rate DECIMAL(5,2),
+    error_code VARCHAR(20),
+    error_message TEXT,
+    processed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (job_id, item_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_decision_batch_results_status ON decision_batch_results (job_id, status);
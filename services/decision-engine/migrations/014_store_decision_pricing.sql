@@ -0,0 +1,4 @@
+-- Keep the risk-based pricing block of each decision (rate, margin adjustments and fee waivers),
+-- the pricing source for the loan service's offers and the underwriting worker. Denials and
+-- decisions saved before this migration have none.
+ALTER TABLE decisions ADD COLUMN IF NOT EXISTS pricing JSONB;
@@ -0,0 +1,78 @@
+-- Versioned underwriting policies under maker/checker approval: every row is one version of the
+-- thresholds, moving from DRAFT to PENDING_APPROVAL to APPROVED (or back to DRAFT when rejected),
+-- and every change is appended to underwriting_policy_events. Approved versions are in force from
+-- their effective date until a later approved version takes effect.
+CREATE TABLE IF NOT EXISTS underwriting_policies (
+    version VARCHAR(20) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'DRAFT',
+    thresholds JSONB NOT NULL,
+    effective_from TIMESTAMP WITH TIME ZONE,
+    created_by VARCHAR(255) NOT NULL,
+    submitted_by VARCHAR(255),
+    submitted_at TIMESTAMP WITH TIME ZONE,
+    reviewed_by VARCHAR(255),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    review_comment TEXT,
+    retired_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_underwriting_policies_status CHECK (status IN ('DRAFT', 'PENDING_APPROVAL', 'APPROVED', 'RETIRED')),
+    -- The checker who approved a version is never its maker
+    CONSTRAINT chk_underwriting_policies_checker CHECK (
+        status NOT IN ('APPROVED', 'RETIRED')
+        OR (effective_from IS NOT NULL AND reviewed_by IS NOT NULL
+            AND reviewed_by <> created_by AND reviewed_by <> submitted_by)
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_underwriting_policies_status ON underwriting_policies(status, effective_from);
+
+-- Submitted, approved and retired versions are frozen: only their status and review may change,
+-- approved versions may only be retired, and none of them may be deleted
+CREATE OR REPLACE FUNCTION protect_submitted_underwriting_policies() RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.status = 'DRAFT' THEN
+        IF TG_OP = 'DELETE' THEN
+            RETURN OLD;
+        END IF;
+        RETURN NEW;
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RAISE EXCEPTION 'underwriting policy % is % and cannot be deleted', OLD.version, OLD.status;
+    END IF;
+
+    IF NEW.name IS DISTINCT FROM OLD.name
+        OR NEW.description IS DISTINCT FROM OLD.description
+        OR NEW.thresholds IS DISTINCT FROM OLD.thresholds
+        OR (OLD.status = 'APPROVED' AND NEW.status <> 'RETIRED')
+        OR (OLD.status = 'APPROVED' AND NEW.effective_from IS DISTINCT FROM OLD.effective_from)
+        OR OLD.status = 'RETIRED' THEN
+        RAISE EXCEPTION 'underwriting policy % is % and cannot be changed', OLD.version, OLD.status;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_protect_submitted_underwriting_policies ON underwriting_policies;
+CREATE TRIGGER trg_protect_submitted_underwriting_policies
+    BEFORE UPDATE OR DELETE ON underwriting_policies
+    FOR EACH ROW EXECUTE FUNCTION protect_submitted_underwriting_policies();
+
+-- Append-only history of policy changes
+CREATE TABLE IF NOT EXISTS underwriting_policy_events (
+    id BIGSERIAL PRIMARY KEY,
+    version VARCHAR(20) NOT NULL,
+    event VARCHAR(20) NOT NULL,
+    snapshot JSONB,  -- the version as it was after the change
+    comment TEXT,    -- the checker's comment on approvals and rejections
+    performed_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_underwriting_policy_events_version ON underwriting_policy_events(version, created_at);
+
+-- The policy version each decision was made under
+ALTER TABLE decisions ADD COLUMN IF NOT EXISTS policy_version VARCHAR(20);
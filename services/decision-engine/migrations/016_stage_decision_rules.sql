@@ -0,0 +1,32 @@
+-- Rules are evaluated in two stages: knockout rules first, ending the decision on the first match,
+-- then scoring rules. Hard knockouts for fraud, sanctions and usury limits join the knockout stage
+-- as published 1.0.0 versions, reading the screening signals the loan service sends in
+-- additional_data (fraud_score, ofac_match, usury_rate_limit).
+INSERT INTO decision_rules (
+    id, version, status, name, description, category, priority, expression, action, knockout,
+    effective_from, created_by, published_by, published_at
+) VALUES
+    ('knockout_ofac_match', '1.0.0', 'PUBLISHED', 'Sanctions Match', 'Deny applicants matching an OFAC sanctions list', 'COMPLIANCE', 1,
+     'ofac_match',
+     '{"type": "DECISION", "decision": "DENY", "reason": "Applicant matched a sanctions list"}', TRUE,
+     NOW(), 'system', 'system', NOW()),
+    ('knockout_fraud_score', '1.0.0', 'PUBLISHED', 'Fraud Score', 'Deny applications the fraud vendor scores at 0.9 or above', 'FRAUD', 2,
+     'fraud_score >= 0.9',
+     '{"type": "DECISION", "decision": "DENY", "reason": "Application failed fraud screening"}', TRUE,
+     NOW(), 'system', 'system', NOW()),
+    ('knockout_usury_limit', '1.0.0', 'PUBLISHED', 'Usury Limit', 'Deny applications where the state usury limit is below the lowest rate offered', 'COMPLIANCE', 3,
+     'usury_rate_limit > 0 && usury_rate_limit < rate_floor',
+     '{"type": "DECISION", "decision": "DENY", "reason": "No rate can be offered within the state usury limit", "adverse_action_code": "AA18"}', TRUE,
+     NOW(), 'system', 'system', NOW())
+ON CONFLICT (id, version) DO NOTHING;
+
+INSERT INTO decision_rule_events (rule_id, version, event, performed_by, created_at)
+SELECT id, version, status, 'system', created_at FROM decision_rules
+WHERE id IN ('knockout_ofac_match', 'knockout_fraud_score', 'knockout_usury_limit') AND version = '1.0.0'
+  AND NOT EXISTS (
+      SELECT 1 FROM decision_rule_events e
+      WHERE e.rule_id = decision_rules.id AND e.version = decision_rules.version
+  );
+
+-- The result of each stage, so the workflow can tell a hard knockout from a scoring denial
+ALTER TABLE decisions ADD COLUMN IF NOT EXISTS stages JSONB;
@@ -0,0 +1,3 @@
+-- Data sources that missed their SLA, which a provisional decision was made without. The decision
+-- is re-decided, and a new decision saved, when their data arrives.
+ALTER TABLE decisions ADD COLUMN IF NOT EXISTS pending_sources JSONB;
@@ -0,0 +1,23 @@
+-- Lending programs (tenants): each decision rule, underwriting policy version and decision request
+-- belongs to one program, and programs decide only on their own rules and policies. Everything that
+-- exists before programs were scoped belongs to the default program.
+
+-- Rule ids stay unique across programs; each rule belongs to the program that created it
+ALTER TABLE decision_rules ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+CREATE INDEX IF NOT EXISTS idx_decision_rules_tenant ON decision_rules(tenant_id, status, priority);
+
+-- Policy versions are numbered per program
+ALTER TABLE underwriting_policies ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE underwriting_policies DROP CONSTRAINT IF EXISTS underwriting_policies_pkey;
+ALTER TABLE underwriting_policies ADD PRIMARY KEY (tenant_id, version);
+
+DROP INDEX IF EXISTS idx_underwriting_policies_status;
+CREATE INDEX IF NOT EXISTS idx_underwriting_policies_status ON underwriting_policies(tenant_id, status, effective_from);
+
+ALTER TABLE underwriting_policy_events ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+DROP INDEX IF EXISTS idx_underwriting_policy_events_version;
+CREATE INDEX IF NOT EXISTS idx_underwriting_policy_events_version ON underwriting_policy_events(tenant_id, version, created_at);
+
+-- The program each application was decided under; an application never moves between programs
+ALTER TABLE decision_requests ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+CREATE INDEX IF NOT EXISTS idx_decision_requests_tenant ON decision_requests(tenant_id, requested_at);
@@ -0,0 +1,15 @@
+-- Re-decisions the loan service has to be told of. A re-decision is queued when it is saved and
+-- retried with backoff until the loan service accepts it, so a provisional decision held in review
+-- is never left without the decision that replaced it.
+CREATE TABLE IF NOT EXISTS redecision_notifications (
+    decision_id BIGINT PRIMARY KEY REFERENCES decisions(id),
+    application_id VARCHAR(255) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_redecision_notifications_due
+    ON redecision_notifications(next_attempt_at) WHERE delivered_at IS NULL;
@@ -0,0 +1,225 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/dti"
+	"github.com/huuhoait/los-demo/services/shared/pkg/rpc"
+
+	shared "github.com/huuhoait/los-demo/services/shared/pkg/config"
+)
+
+// Config holds the decision engine configuration
+type Config struct {
+	Environment      string                 `yaml:"environment"`
+	Server           ServerConfig           `yaml:"server"`
+	Database         DatabaseConfig         `yaml:"database"`
+	Redis            RedisConfig            `yaml:"redis"`
+	Logger           LoggerConfig           `yaml:"logger"`
+	DTI              dti.Config             `yaml:"dti"`
+	ExternalServices ExternalServicesConfig `yaml:"external_services"`
+	DecisionEngine   DecisionEngineConfig   `yaml:"decision_engine"`
+	GRPC             rpc.Config             `yaml:"grpc"` // DecisionService for internal callers
+}
+
+// ServerConfig holds HTTP server configuration
+type ServerConfig struct {
+	Port         string        `yaml:"port"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	// DebugAddr is the internal listener for /debug/vars; empty disables it
+	DebugAddr string `yaml:"debug_addr"`
+}
+
+// DatabaseConfig holds database configuration
+type DatabaseConfig struct {
+	URL string `yaml:"url"`
+}
+
+// RedisConfig holds Redis configuration
+type RedisConfig struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
+	PoolSize int    `yaml:"pool_size"`
+}
+
+// LoggerConfig holds logging configuration
+type LoggerConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+	Output string `yaml:"output"`
+}
+
+// ExternalServicesConfig holds the services the decision engine calls
+type ExternalServicesConfig struct {
+	CreditBureau CreditBureauConfig `yaml:"credit_bureau"`
+	UserService  ServiceConfig      `yaml:"user_service"` // consent lookups and SSN detokenization
+	LoanService  ServiceConfig      `yaml:"loan_service"` // applications hard pulls are made for
+	FraudVendor  FraudVendorConfig  `yaml:"fraud_vendor"` // screening is skipped without an endpoint
+}
+
+// ServiceConfig holds the endpoint of an internal service and the token it is called with
+type ServiceConfig struct {
+	BaseURL      string        `yaml:"base_url"`
+	ServiceToken string        `yaml:"service_token"`
+	Timeout      time.Duration `yaml:"timeout"`
+}
+
+// CreditBureauConfig holds the bureau endpoints and how pulls are retried, cut off and cached
+type CreditBureauConfig struct {
+	Experian   BureauEndpointConfig `yaml:"experian"`
+	Equifax    BureauEndpointConfig `yaml:"equifax"`
+	TransUnion BureauEndpointConfig `yaml:"transunion"`
+	Timeout    time.Duration        `yaml:"timeout"` // bureaus without a timeout of their own
+	RetryCount int                  `yaml:"retry_count"`
+	// Retries back off exponentially from RetryBaseDelay, with jitter, up to RetryMaxDelay
+	RetryBaseDelay time.Duration        `yaml:"retry_base_delay"`
+	RetryMaxDelay  time.Duration        `yaml:"retry_max_delay"`
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuit_breaker"`
+	ScorePolicy    string               `yaml:"score_policy"` // MIDDLE or LOWEST
+	CacheTTL       time.Duration        `yaml:"cache_ttl"`
+	Sandbox        BureauSandboxConfig  `yaml:"sandbox"`
+}
+
+// BureauEndpointConfig holds the endpoint of one bureau
+type BureauEndpointConfig struct {
+	Endpoint string        `yaml:"endpoint"`
+	Timeout  time.Duration `yaml:"timeout"`
+}
+
+// CircuitBreakerConfig holds when a failing bureau is skipped and for how long
+type CircuitBreakerConfig struct {
+	FailureThreshold int           `yaml:"failure_threshold"`
+	OpenTimeout      time.Duration `yaml:"open_timeout"`
+}
+
+// BureauSandboxConfig holds the sandbox serving recorded bureau responses for test SSNs
+type BureauSandboxConfig struct {
+	Enabled     bool          `yaml:"enabled"`
+	FixturesDir string        `yaml:"fixtures_dir"`
+	Latency     time.Duration `yaml:"latency"`
+	ErrorRate   float64       `yaml:"error_rate"`
+}
+
+// FraudVendorConfig holds the fraud vendor applicants are screened with and the scores, as a
+// share of ScoreScale, that send applications to review or deny them
+type FraudVendorConfig struct {
+	Vendor                string        `yaml:"vendor"`
+	Endpoint              string        `yaml:"endpoint"`
+	APIKey                string        `yaml:"api_key"`
+	Timeout               time.Duration `yaml:"timeout"`
+	ScoreScale            float64       `yaml:"score_scale"`
+	ReviewScore           float64       `yaml:"review_score"`
+	SignalReviewScore     float64       `yaml:"signal_review_score"`
+	DenyScore             float64       `yaml:"deny_score"`
+	ReviewWhenUnavailable bool          `yaml:"review_when_unavailable"`
+}
+
+// DecisionEngineConfig holds the settings of decisioning itself
+type DecisionEngineConfig struct {
+	Batch            BatchConfig            `yaml:"batch"`
+	IncomeEstimation IncomeEstimationConfig `yaml:"income_estimation"`
+	SourceSLA        SourceSLAConfig        `yaml:"source_sla"`
+	Tenants          []TenantConfig         `yaml:"tenants"` // lending programs besides default
+}
+
+// BatchConfig holds the limits of batch decisioning; unset limits take the defaults
+type BatchConfig struct {
+	MaxItems           int           `yaml:"max_items"`
+	DefaultConcurrency int           `yaml:"default_concurrency"`
+	MaxConcurrency     int           `yaml:"max_concurrency"`
+	MaxActiveJobs      int           `yaml:"max_active_jobs"`
+	FlushSize          int           `yaml:"flush_size"`
+	StaleAfter         time.Duration `yaml:"stale_after"`
+}
+
+// IncomeEstimationConfig holds when stated income too far above the estimate needs verifying
+type IncomeEstimationConfig struct {
+	VarianceThreshold float64 `yaml:"variance_threshold"`
+	TradelineWeight   float64 `yaml:"tradeline_weight"`
+}
+
+// SourceSLAConfig holds how long decisions wait for each data source before deciding
+// provisionally, and how long late data is still taken to re-decide
+type SourceSLAConfig struct {
+	FraudScreening time.Duration `yaml:"fraud_screening"`
+	CreditReport   time.Duration `yaml:"credit_report"`
+	LateDataWindow time.Duration `yaml:"late_data_window"`
+}
+
+// TenantConfig holds a lending program and its pricing matrix
+type TenantConfig struct {
+	ID      string        `yaml:"id"`
+	Name    string        `yaml:"name"`
+	Pricing PricingConfig `yaml:"pricing"`
+}
+
+// PricingConfig holds a program's rates; rates left out use the default matrix
+type PricingConfig struct {
+	BaseRates           map[string]float64 `yaml:"base_rates"` // by loan purpose
+	DefaultBaseRate     float64            `yaml:"default_base_rate"`
+	RateFloor           float64            `yaml:"rate_floor"`
+	RateCeiling         float64            `yaml:"rate_ceiling"`
+	OriginationFeeRates map[string]float64 `yaml:"origination_fee_rates"` // by risk category
+}
+
+// Load reads config.yaml from the config directory, then the overrides in the environment's
+// {environment}.yaml when there is one, then the environment variables
+func Load() (*Config, error) {
+	dir := shared.GetString("CONFIG_DIR", "config")
+
+	cfg := &Config{}
+	if err := shared.LoadFromFile(filepath.Join(dir, "config.yaml"), cfg); err != nil {
+		return nil, err
+	}
+
+	cfg.Environment = shared.GetString("ENVIRONMENT", shared.GetString("APP_ENV", "development"))
+	overrides := filepath.Join(dir, cfg.Environment+".yaml")
+	if _, err := os.Stat(overrides); err == nil {
+		if err := shared.LoadFromFile(overrides, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	overrideWithEnvVars(cfg)
+
+	if cfg.Server.Port == "" {
+		return nil, fmt.Errorf("server port is not configured")
+	}
+	return cfg, nil
+}
+
+// overrideWithEnvVars overrides configuration with environment variables
+func overrideWithEnvVars(cfg *Config) {
+	cfg.Server.Port = shared.GetString("PORT", cfg.Server.Port)
+	cfg.Server.ReadTimeout = shared.GetDuration("READ_TIMEOUT", cfg.Server.ReadTimeout)
+	cfg.Server.WriteTimeout = shared.GetDuration("WRITE_TIMEOUT", cfg.Server.WriteTimeout)
+	cfg.Server.IdleTimeout = shared.GetDuration("IDLE_TIMEOUT", cfg.Server.IdleTimeout)
+	cfg.Server.DebugAddr = shared.GetString("DEBUG_ADDR", cfg.Server.DebugAddr)
+
+	cfg.GRPC.Port = shared.GetInt("GRPC_PORT", cfg.GRPC.Port)
+	cfg.GRPC.CertFile = shared.GetString("GRPC_CERT_FILE", cfg.GRPC.CertFile)
+	cfg.GRPC.KeyFile = shared.GetString("GRPC_KEY_FILE", cfg.GRPC.KeyFile)
+	cfg.GRPC.CAFile = shared.GetString("GRPC_CA_FILE", cfg.GRPC.CAFile)
+
+	cfg.Database.URL = shared.GetString("DATABASE_URL", cfg.Database.URL)
+
+	cfg.Redis.Host = shared.GetString("REDIS_HOST", cfg.Redis.Host)
+	cfg.Redis.Port = shared.GetString("REDIS_PORT", cfg.Redis.Port)
+	cfg.Redis.Password = shared.GetString("REDIS_PASSWORD", cfg.Redis.Password)
+	cfg.Redis.DB = shared.GetInt("REDIS_DB", cfg.Redis.DB)
+
+	cfg.Logger.Level = shared.GetString("LOG_LEVEL", cfg.Logger.Level)
+
+	cfg.ExternalServices.UserService.BaseURL = shared.GetString("USER_SERVICE_URL", cfg.ExternalServices.UserService.BaseURL)
+	cfg.ExternalServices.UserService.ServiceToken = shared.GetString("USER_SERVICE_TOKEN", cfg.ExternalServices.UserService.ServiceToken)
+	cfg.ExternalServices.LoanService.BaseURL = shared.GetString("LOAN_SERVICE_URL", cfg.ExternalServices.LoanService.BaseURL)
+	cfg.ExternalServices.LoanService.ServiceToken = shared.GetString("LOAN_SERVICE_TOKEN", cfg.ExternalServices.LoanService.ServiceToken)
+	cfg.ExternalServices.FraudVendor.APIKey = shared.GetString("FRAUD_VENDOR_API_KEY", cfg.ExternalServices.FraudVendor.APIKey)
+}
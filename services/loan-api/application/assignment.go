@@ -0,0 +1,419 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// AssignmentRunResult summarizes a single auto-assignment pass
+type AssignmentRunResult struct {
+	Assigned   int `json:"assigned"`
+	Unassigned int `json:"unassigned"` // applications no officer had the skills or capacity for
+	Released   int `json:"released"`   // assignments of applications that left manual review
+}
+
+// AssignmentService routes applications in manual review to loan officers. Applications are
+// assigned in turn among the officers licensed in the borrower's state, not on time off and with
+// the skills and spare capacity they need; officers can claim unassigned applications, managers
+// can move them between officers and team leads can override the rules. Every change is kept as
+// history.
+type AssignmentService struct {
+	repo            LoanRepository
+	officers        []domain.LoanOfficer
+	highValueAmount float64
+	batchSize       int
+	logger          *zap.Logger
+}
+
+// NewAssignmentService creates a new assignment service for the given officers; officers without a
+// user ID or capacity are skipped
+func NewAssignmentService(repo LoanRepository, officers []domain.LoanOfficer, highValueAmount float64, batchSize int, logger *zap.Logger) *AssignmentService {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	valid := make([]domain.LoanOfficer, 0, len(officers))
+	for _, officer := range officers {
+		if officer.UserID == "" || officer.Capacity <= 0 {
+			logger.Warn("Skipping invalid loan officer",
+				zap.String("officer_id", officer.UserID),
+				zap.Int("capacity", officer.Capacity))
+			continue
+		}
+		valid = append(valid, officer)
+	}
+
+	return &AssignmentService{
+		repo:            repo,
+		officers:        valid,
+		highValueAmount: highValueAmount,
+		batchSize:       batchSize,
+		logger:          logger,
+	}
+}
+
+// Officers returns the loan officers applications are assigned to
+func (s *AssignmentService) Officers() []domain.LoanOfficer {
+	return s.officers
+}
+
+// AssignPending releases assignments of applications that have left manual review, then assigns
+// up to a batch of unassigned applications in manual review
+func (s *AssignmentService) AssignPending(ctx context.Context) (*AssignmentRunResult, error) {
+	result := &AssignmentRunResult{}
+	now := time.Now().UTC()
+
+	released, err := s.repo.ReleaseFinishedAssignments(ctx, now)
+	if err != nil {
+		return result, err
+	}
+	result.Released = released
+
+	candidates, err := s.repo.ListUnassignedReviews(ctx, s.batchSize)
+	if err != nil || len(candidates) == 0 {
+		return result, err
+	}
+
+	active, err := s.repo.CountActiveAssignments(ctx)
+	if err != nil {
+		return result, err
+	}
+	lastAssigned, err := s.repo.LatestAssignmentTimes(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	for _, candidate := range candidates {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		logger := s.logger.With(
+			zap.String("application_id", candidate.ApplicationID),
+			zap.String("operation", "assign_application"),
+		)
+
+		required := candidate.RequiredSkills(s.highValueAmount)
+		var officer *domain.LoanOfficer
+		var assignedAt time.Time
+		created := false
+		for {
+			selected, ok := domain.SelectOfficer(s.officers, active, lastAssigned, candidate, required, now)
+			if !ok {
+				break
+			}
+			officer = selected
+
+			// Each assignment gets its own time so the next turn goes to another officer
+			assignedAt = time.Now().UTC()
+			assignment := &domain.ApplicationAssignment{
+				ID:            uuid.New().String(),
+				ApplicationID: candidate.ApplicationID,
+				OfficerID:     officer.UserID,
+				Method:        domain.AssignmentAuto,
+				AssignedBy:    "system",
+				AssignedAt:    assignedAt,
+			}
+			created, err = s.repo.CreateAssignment(ctx, assignment, officer.Capacity)
+			if err != nil && strings.Contains(err.Error(), "at capacity") {
+				// filled by claims since the counts were read; try the next officer
+				active[officer.UserID] = officer.Capacity
+				officer = nil
+				continue
+			}
+			break
+		}
+		if officer == nil {
+			result.Unassigned++
+			logger.Warn("No loan officer available for application",
+				zap.Strings("required_skills", required),
+				zap.String("borrower_state", candidate.BorrowerState))
+			continue
+		}
+		if err != nil {
+			logger.Warn("Failed to assign application", zap.Error(err))
+			continue
+		}
+		if !created {
+			// claimed or assigned by another instance since it was listed
+			continue
+		}
+
+		active[officer.UserID]++
+		lastAssigned[officer.UserID] = assignedAt
+		result.Assigned++
+		logger.Info("Application assigned", zap.String("officer_id", officer.UserID))
+	}
+	return result, nil
+}
+
+// Claim assigns an unassigned application in manual review to the calling officer
+func (s *AssignmentService) Claim(ctx context.Context, applicationID, officerID string) (*domain.ApplicationAssignment, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("officer_id", officerID),
+		zap.String("operation", "claim_application"),
+	)
+
+	if err := s.requireManualReview(ctx, applicationID); err != nil {
+		return nil, err
+	}
+
+	if current, err := s.repo.GetActiveAssignment(ctx, applicationID); err == nil {
+		if current.OfficerID == officerID {
+			return current, nil
+		}
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_063,
+			Message:     "Application already assigned",
+			Description: "The application is assigned to another loan officer; ask a manager to reassign it",
+			HTTPStatus:  409,
+		}
+	} else if !strings.Contains(err.Error(), "not found") {
+		logger.Error("Failed to get active assignment", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	// The cap is enforced by the insert itself, so concurrent claims cannot overfill an officer
+	capacity := 0
+	officer := s.officer(officerID)
+	if officer != nil {
+		if err := s.requireLicensed(ctx, logger, officer, applicationID); err != nil {
+			return nil, err
+		}
+		capacity = officer.Capacity
+	}
+
+	assignment := &domain.ApplicationAssignment{
+		ID:            uuid.New().String(),
+		ApplicationID: applicationID,
+		OfficerID:     officerID,
+		Method:        domain.AssignmentClaim,
+		AssignedBy:    officerID,
+		AssignedAt:    time.Now().UTC(),
+	}
+	created, err := s.repo.CreateAssignment(ctx, assignment, capacity)
+	if err != nil {
+		if strings.Contains(err.Error(), "at capacity") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_064,
+				Message:     "Loan officer at capacity",
+				Description: fmt.Sprintf("Loan officers can hold at most %d applications in review", capacity),
+				HTTPStatus:  409,
+			}
+		}
+		logger.Error("Failed to claim application", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	if !created {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_063,
+			Message:     "Application already assigned",
+			Description: "The application was assigned to another loan officer while it was being claimed",
+			HTTPStatus:  409,
+		}
+	}
+
+	logger.Info("Application claimed")
+	return assignment, nil
+}
+
+// Reassign moves an application in manual review to another configured loan officer licensed in
+// the borrower's state and not on time off. Managers may assign past an officer's capacity.
+func (s *AssignmentService) Reassign(ctx context.Context, applicationID string, req *domain.ReassignApplicationRequest, performedBy string) (*domain.ApplicationAssignment, error) {
+	return s.reassign(ctx, applicationID, req, performedBy, domain.AssignmentReassign)
+}
+
+// Override places an application in manual review with the loan officer a team lead chooses,
+// whatever the officer's capacity, skills or time off. The officer must still be licensed in the
+// borrower's state.
+func (s *AssignmentService) Override(ctx context.Context, applicationID string, req *domain.ReassignApplicationRequest, performedBy string) (*domain.ApplicationAssignment, error) {
+	return s.reassign(ctx, applicationID, req, performedBy, domain.AssignmentOverride)
+}
+
+// reassign moves an application to the requested officer, replacing its active assignment
+func (s *AssignmentService) reassign(ctx context.Context, applicationID string, req *domain.ReassignApplicationRequest, performedBy string, method domain.AssignmentMethod) (*domain.ApplicationAssignment, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("officer_id", req.OfficerID),
+		zap.String("performed_by", performedBy),
+		zap.String("operation", string(method)+"_application"),
+	)
+
+	officer := s.officer(req.OfficerID)
+	if officer == nil {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: fmt.Sprintf("Unknown loan officer: %s", req.OfficerID),
+			HTTPStatus:  400,
+		}
+	}
+
+	if err := s.requireManualReview(ctx, applicationID); err != nil {
+		return nil, err
+	}
+	if err := s.requireLicensed(ctx, logger, officer, applicationID); err != nil {
+		return nil, err
+	}
+	if method != domain.AssignmentOverride {
+		if period, away := officer.OnTimeOff(time.Now().UTC()); away {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_078,
+				Message:     "Loan officer unavailable",
+				Description: fmt.Sprintf("%s is on time off until %s; a team lead can override the assignment", officer.Name, period.End.Format("2006-01-02")),
+				HTTPStatus:  409,
+			}
+		}
+	}
+
+	if current, err := s.repo.GetActiveAssignment(ctx, applicationID); err == nil && current.OfficerID == req.OfficerID {
+		return current, nil
+	}
+
+	assignment := &domain.ApplicationAssignment{
+		ID:            uuid.New().String(),
+		ApplicationID: applicationID,
+		OfficerID:     req.OfficerID,
+		Method:        method,
+		AssignedBy:    performedBy,
+		Reason:        req.Reason,
+		AssignedAt:    time.Now().UTC(),
+	}
+	if err := s.repo.ReassignApplication(ctx, assignment); err != nil {
+		logger.Error("Failed to reassign application", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	logger.Info("Application reassigned", zap.String("method", string(method)))
+	return assignment, nil
+}
+
+// Workload returns each loan officer's open reviews against their capacity, whether they are
+// taking new applications and when they were last assigned one
+func (s *AssignmentService) Workload(ctx context.Context) ([]*domain.OfficerWorkload, error) {
+	active, err := s.repo.CountActiveAssignments(ctx)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	lastAssigned, err := s.repo.LatestAssignmentTimes(ctx)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	now := time.Now().UTC()
+	workloads := make([]*domain.OfficerWorkload, 0, len(s.officers))
+	for _, officer := range s.officers {
+		workload := &domain.OfficerWorkload{
+			LoanOfficer: officer,
+			OpenReviews: active[officer.UserID],
+		}
+		period, away := officer.OnTimeOff(now)
+		if away {
+			workload.AwayUntil = &period.End
+		}
+		workload.Available = !away && workload.OpenReviews < officer.Capacity
+		if at, ok := lastAssigned[officer.UserID]; ok {
+			workload.LastAssignedAt = &at
+		}
+		workloads = append(workloads, workload)
+	}
+	return workloads, nil
+}
+
+// ListQueue returns the applications actively assigned to an officer
+func (s *AssignmentService) ListQueue(ctx context.Context, officerID string) ([]*domain.QueueItem, error) {
+	items, err := s.repo.ListOfficerQueue(ctx, officerID)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	return items, nil
+}
+
+// ListHistory returns an application's assignment history, oldest first
+func (s *AssignmentService) ListHistory(ctx context.Context, applicationID string) ([]*domain.ApplicationAssignment, error) {
+	if _, err := s.repo.GetApplicationByID(ctx, applicationID); err != nil {
+		return nil, s.applicationError(applicationID, err)
+	}
+
+	assignments, err := s.repo.ListAssignments(ctx, applicationID)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	return assignments, nil
+}
+
+// requireManualReview checks the application exists and is waiting for a reviewer
+func (s *AssignmentService) requireManualReview(ctx context.Context, applicationID string) error {
+	application, err := s.repo.GetApplicationByID(ctx, applicationID)
+	if err != nil {
+		return s.applicationError(applicationID, err)
+	}
+
+	if application.CurrentState != domain.StateManualReview {
+		return &domain.LoanError{
+			Code:        domain.LOAN_019,
+			Message:     "Application not in manual review",
+			Description: fmt.Sprintf("Application is in %s state; only applications in manual review are assigned to loan officers", application.CurrentState),
+			HTTPStatus:  409,
+		}
+	}
+	return nil
+}
+
+// requireLicensed checks the officer is licensed in the state of the application's borrower
+func (s *AssignmentService) requireLicensed(ctx context.Context, logger *zap.Logger, officer *domain.LoanOfficer, applicationID string) error {
+	candidate, err := s.repo.GetAssignmentCandidate(ctx, applicationID)
+	if err != nil {
+		logger.Error("Failed to get assignment candidate", zap.Error(err))
+		return s.applicationError(applicationID, err)
+	}
+
+	if !officer.IsLicensedIn(candidate.BorrowerState) {
+		return &domain.LoanError{
+			Code:        domain.LOAN_077,
+			Message:     "Loan officer not licensed in borrower's state",
+			Description: fmt.Sprintf("%s is not licensed to review loans to borrowers in %s", officer.Name, candidate.BorrowerState),
+			HTTPStatus:  409,
+		}
+	}
+	return nil
+}
+
+// officer returns the configured officer with the user ID, or nil
+func (s *AssignmentService) officer(userID string) *domain.LoanOfficer {
+	for i := range s.officers {
+		if s.officers[i].UserID == userID {
+			return &s.officers[i]
+		}
+	}
+	return nil
+}
+
+func (s *AssignmentService) applicationError(applicationID string, err error) error {
+	if strings.Contains(err.Error(), "not found") {
+		return &domain.LoanError{
+			Code:        domain.LOAN_010,
+			Message:     "Application not found",
+			Description: fmt.Sprintf("No application found with ID: %s", applicationID),
+			HTTPStatus:  404,
+		}
+	}
+	return s.databaseError(err)
+}
+
+func (s *AssignmentService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
@@ -0,0 +1,67 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// AssignmentJob assigns applications entering manual review to loan officers on a fixed interval
+type AssignmentJob struct {
+	assignments *AssignmentService
+	interval    time.Duration
+	logger      *zap.Logger
+}
+
+func NewAssignmentJob(
+	assignments *AssignmentService,
+	interval time.Duration,
+	logger *zap.Logger,
+) *AssignmentJob {
+	return &AssignmentJob{
+		assignments: assignments,
+		interval:    interval,
+		logger:      logger,
+	}
+}
+
+// Start runs auto-assignment on the configured interval until the context is cancelled
+func (j *AssignmentJob) Start(ctx context.Context) {
+	if j.interval <= 0 || len(j.assignments.Officers()) == 0 {
+		j.logger.Info("Assignment job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Assignment job stopped")
+			return
+		case <-ticker.C:
+			if _, err := j.RunOnce(ctx); err != nil {
+				j.logger.Error("Auto-assignment failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RunOnce assigns up to a batch of unassigned applications in manual review
+func (j *AssignmentJob) RunOnce(ctx context.Context) (*AssignmentRunResult, error) {
+	result, err := j.assignments.AssignPending(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	if result.Assigned+result.Unassigned+result.Released > 0 {
+		j.logger.Info("Auto-assignment completed",
+			zap.Int("assigned", result.Assigned),
+			zap.Int("unassigned", result.Unassigned),
+			zap.Int("released", result.Released),
+		)
+	}
+	return result, nil
+}
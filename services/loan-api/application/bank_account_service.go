@@ -0,0 +1,408 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/tokenization"
+)
+
+// PIIDetokenizer exchanges vault tokens for the raw identifiers they stand for
+type PIIDetokenizer interface {
+	Detokenize(ctx context.Context, token string) (string, error)
+}
+
+// AccountLinkProvider verifies bank account ownership through the borrower's online banking login
+type AccountLinkProvider interface {
+	CreateLinkToken(ctx context.Context, userID string) (*domain.PlaidLinkToken, error)
+	AuthenticateAccount(ctx context.Context, publicToken, accountID string) (*domain.AuthenticatedAccount, error)
+}
+
+// MicroDepositVerifier verifies bank account ownership by sending two small deposits the borrower
+// confirms the amounts of
+type MicroDepositVerifier interface {
+	// InitiateMicroDeposits sends the deposits and returns the provider's reference to the account
+	InitiateMicroDeposits(ctx context.Context, account *domain.BankAccount, destination *domain.DisbursementDestination) (string, error)
+	VerifyMicroDeposits(ctx context.Context, reference string, amount1, amount2 float64) (bool, error)
+}
+
+// BankAccountService links and verifies the bank accounts borrowers receive loan proceeds in.
+// Account and routing numbers are tokenized in the user service vault before they are stored.
+type BankAccountService struct {
+	repo          LoanRepository
+	userRepo      UserRepository
+	tokenizer     PIITokenizer
+	linkProvider  AccountLinkProvider
+	microDeposits MicroDepositVerifier
+	logger        *zap.Logger
+}
+
+// NewBankAccountService creates a new bank account service. A nil link provider disables Plaid
+// linking and a nil micro-deposit verifier disables adding accounts by number.
+func NewBankAccountService(
+	repo LoanRepository,
+	userRepo UserRepository,
+	tokenizer PIITokenizer,
+	linkProvider AccountLinkProvider,
+	microDeposits MicroDepositVerifier,
+	logger *zap.Logger,
+) *BankAccountService {
+	return &BankAccountService{
+		repo:          repo,
+		userRepo:      userRepo,
+		tokenizer:     tokenizer,
+		linkProvider:  linkProvider,
+		microDeposits: microDeposits,
+		logger:        logger,
+	}
+}
+
+// CreatePlaidLinkToken creates the token the borrower's client opens Plaid Link with
+func (s *BankAccountService) CreatePlaidLinkToken(ctx context.Context, userID string) (*domain.PlaidLinkToken, error) {
+	logger := s.logger.With(
+		zap.String("user_id", userID),
+		zap.String("operation", "create_plaid_link_token"),
+	)
+
+	if s.linkProvider == nil {
+		return nil, s.unavailableError("Plaid account linking is not configured")
+	}
+
+	token, err := s.linkProvider.CreateLinkToken(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to create link token", zap.Error(err))
+		return nil, s.providerError(err)
+	}
+	return token, nil
+}
+
+// LinkPlaidAccount links the account the borrower selected in Plaid Link. Plaid Auth proves the
+// borrower controls the account, so it is verified straight away.
+func (s *BankAccountService) LinkPlaidAccount(ctx context.Context, userID string, req *domain.LinkPlaidAccountRequest) (*domain.BankAccount, error) {
+	logger := s.logger.With(
+		zap.String("user_id", userID),
+		zap.String("operation", "link_plaid_account"),
+	)
+
+	if s.linkProvider == nil {
+		return nil, s.unavailableError("Plaid account linking is not configured")
+	}
+
+	authenticated, err := s.linkProvider.AuthenticateAccount(ctx, req.PublicToken, req.AccountID)
+	if err != nil {
+		logger.Error("Failed to authenticate account", zap.Error(err))
+		return nil, s.providerError(err)
+	}
+
+	institutionName := req.InstitutionName
+	if institutionName == "" {
+		institutionName = authenticated.Name
+	}
+
+	account, existing, err := s.prepareAccount(ctx, logger, userID, institutionName, authenticated.AccountType, authenticated.AccountNumber, authenticated.RoutingNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	account.VerificationMethod = domain.VerificationMethodPlaid
+	account.Status = domain.BankAccountVerified
+	account.PlaidAccountID = &authenticated.AccountID
+	account.VerifiedAt = &now
+	account.UpdatedAt = now
+
+	if existing {
+		err = s.repo.UpdateBankAccount(ctx, account)
+	} else {
+		err = s.repo.CreateBankAccount(ctx, account)
+	}
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	logger.Info("Bank account linked through Plaid", zap.String("bank_account_id", account.ID))
+	return account, nil
+}
+
+// AddBankAccount adds a bank account by its numbers and sends micro-deposits to verify it. Adding an
+// account that is already verified or has had its micro-deposits sent returns it; the deposits are
+// sent again for an account whose earlier attempt to send them failed.
+func (s *BankAccountService) AddBankAccount(ctx context.Context, userID string, req *domain.AddBankAccountRequest) (*domain.BankAccount, error) {
+	logger := s.logger.With(
+		zap.String("user_id", userID),
+		zap.String("operation", "add_bank_account"),
+	)
+
+	if !domain.IsValidRoutingNumber(req.RoutingNumber) {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid routing number",
+			Description: "The routing number is not a valid ABA routing number",
+			HTTPStatus:  400,
+		}
+	}
+	if s.microDeposits == nil {
+		return nil, s.unavailableError("Micro-deposit verification is not configured")
+	}
+
+	account, existing, err := s.prepareAccount(ctx, logger, userID, req.InstitutionName, req.AccountType, req.AccountNumber, req.RoutingNumber)
+	if err != nil {
+		return nil, err
+	}
+	if existing && (account.Status == domain.BankAccountVerified || account.MicroDepositsSentAt != nil) {
+		return account, nil
+	}
+
+	if !existing {
+		account.VerificationMethod = domain.VerificationMethodMicroDeposits
+		account.Status = domain.BankAccountPendingVerification
+		if err := s.repo.CreateBankAccount(ctx, account); err != nil {
+			return nil, s.databaseError(err)
+		}
+	}
+	logger = logger.With(zap.String("bank_account_id", account.ID))
+
+	borrower, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to get borrower", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	reference, err := s.microDeposits.InitiateMicroDeposits(ctx, account, &domain.DisbursementDestination{
+		UserID:        account.UserID,
+		Name:          strings.TrimSpace(borrower.FirstName + " " + borrower.LastName),
+		Email:         borrower.Email,
+		BankName:      req.InstitutionName,
+		AccountType:   req.AccountType,
+		AccountNumber: req.AccountNumber,
+		RoutingNumber: req.RoutingNumber,
+	})
+	if reference != "" {
+		account.ProviderReference = &reference
+	}
+	if err != nil {
+		logger.Error("Failed to initiate micro-deposits", zap.Error(err))
+		// The account stays pending without micro-deposits so adding it again resends them
+		if reference != "" {
+			account.UpdatedAt = time.Now().UTC()
+			if updateErr := s.repo.UpdateBankAccount(ctx, account); updateErr != nil {
+				logger.Warn("Failed to save provider reference", zap.Error(updateErr))
+			}
+		}
+		return nil, s.providerError(err)
+	}
+
+	now := time.Now().UTC()
+	account.MicroDepositsSentAt = &now
+	account.UpdatedAt = now
+	if err := s.repo.UpdateBankAccount(ctx, account); err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	logger.Info("Micro-deposits sent")
+	return account, nil
+}
+
+// VerifyMicroDeposits confirms the micro-deposit amounts the borrower found in their account. The
+// account fails verification once the attempts run out and must be added again.
+func (s *BankAccountService) VerifyMicroDeposits(ctx context.Context, userID, accountID string, req *domain.VerifyMicroDepositsRequest) (*domain.BankAccount, error) {
+	logger := s.logger.With(
+		zap.String("user_id", userID),
+		zap.String("bank_account_id", accountID),
+		zap.String("operation", "verify_micro_deposits"),
+	)
+
+	if s.microDeposits == nil {
+		return nil, s.unavailableError("Micro-deposit verification is not configured")
+	}
+
+	account, err := s.getOwnedAccount(ctx, logger, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Status != domain.BankAccountPendingVerification || account.MicroDepositsSentAt == nil || account.ProviderReference == nil {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_050,
+			Message:     "Bank account cannot be verified",
+			Description: fmt.Sprintf("Bank account is %s and is not awaiting micro-deposit verification", account.Status),
+			HTTPStatus:  409,
+		}
+	}
+
+	matched, err := s.microDeposits.VerifyMicroDeposits(ctx, *account.ProviderReference, req.Amount1, req.Amount2)
+	if err != nil {
+		logger.Error("Failed to verify micro-deposits", zap.Error(err))
+		return nil, s.providerError(err)
+	}
+
+	now := time.Now().UTC()
+	account.MicroDepositAttempts++
+	account.UpdatedAt = now
+	if matched {
+		account.Status = domain.BankAccountVerified
+		account.VerifiedAt = &now
+	} else if account.MicroDepositAttempts >= domain.MaxMicroDepositAttempts {
+		account.Status = domain.BankAccountVerificationFailed
+	}
+	if err := s.repo.UpdateBankAccount(ctx, account); err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	if !matched {
+		logger.Warn("Incorrect micro-deposit amounts", zap.Int("attempts", account.MicroDepositAttempts))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_048,
+			Message:     "Incorrect micro-deposit amounts",
+			Description: fmt.Sprintf("%d of %d attempts used", account.MicroDepositAttempts, domain.MaxMicroDepositAttempts),
+			HTTPStatus:  422,
+		}
+	}
+
+	logger.Info("Bank account verified by micro-deposits")
+	return account, nil
+}
+
+// ListBankAccounts lists the borrower's bank accounts
+func (s *BankAccountService) ListBankAccounts(ctx context.Context, userID string) ([]*domain.BankAccount, error) {
+	accounts, err := s.repo.ListBankAccounts(ctx, userID)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	return accounts, nil
+}
+
+// RemoveBankAccount removes a bank account so no further disbursements are sent to it
+func (s *BankAccountService) RemoveBankAccount(ctx context.Context, userID, accountID string) error {
+	logger := s.logger.With(
+		zap.String("user_id", userID),
+		zap.String("bank_account_id", accountID),
+		zap.String("operation", "remove_bank_account"),
+	)
+
+	account, err := s.getOwnedAccount(ctx, logger, userID, accountID)
+	if err != nil {
+		return err
+	}
+	if account.Status == domain.BankAccountRemoved {
+		return nil
+	}
+
+	account.Status = domain.BankAccountRemoved
+	account.UpdatedAt = time.Now().UTC()
+	if err := s.repo.UpdateBankAccount(ctx, account); err != nil {
+		return s.databaseError(err)
+	}
+
+	logger.Info("Bank account removed")
+	return nil
+}
+
+// prepareAccount tokenizes the account numbers and returns the borrower's active account with the
+// same numbers, reporting it as existing, or a new unsaved account
+func (s *BankAccountService) prepareAccount(ctx context.Context, logger *zap.Logger, userID, institutionName string, accountType domain.AccountType, accountNumber, routingNumber string) (*domain.BankAccount, bool, error) {
+	accountNumberToken, last4, err := s.tokenizer.Tokenize(ctx, tokenization.TokenTypeBankAccountNumber, accountNumber)
+	if err != nil {
+		logger.Error("Failed to tokenize account number", zap.Error(err))
+		return nil, false, s.tokenizationError(err)
+	}
+	routingNumberToken, _, err := s.tokenizer.Tokenize(ctx, tokenization.TokenTypeRoutingNumber, routingNumber)
+	if err != nil {
+		logger.Error("Failed to tokenize routing number", zap.Error(err))
+		return nil, false, s.tokenizationError(err)
+	}
+
+	existing, err := s.repo.GetActiveBankAccountByNumber(ctx, userID, accountNumberToken, routingNumberToken)
+	if err == nil {
+		return existing, true, nil
+	}
+	if !strings.Contains(err.Error(), "not found") {
+		logger.Error("Failed to get bank account", zap.Error(err))
+		return nil, false, s.databaseError(err)
+	}
+
+	now := time.Now().UTC()
+	return &domain.BankAccount{
+		ID:                 uuid.New().String(),
+		UserID:             userID,
+		InstitutionName:    institutionName,
+		AccountType:        accountType,
+		AccountNumberToken: accountNumberToken,
+		RoutingNumberToken: routingNumberToken,
+		AccountLast4:       last4,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}, false, nil
+}
+
+// getOwnedAccount retrieves a bank account, checking it belongs to the user
+func (s *BankAccountService) getOwnedAccount(ctx context.Context, logger *zap.Logger, userID, accountID string) (*domain.BankAccount, error) {
+	account, err := s.repo.GetBankAccount(ctx, accountID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Bank account not found",
+				Description: fmt.Sprintf("No bank account found with ID: %s", accountID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get bank account", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	if account.UserID != userID {
+		logger.Warn("User does not own bank account")
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_022,
+			Message:     "Unauthorized access",
+			Description: "Bank accounts can only be managed by their owner",
+			HTTPStatus:  403,
+		}
+	}
+	return account, nil
+}
+
+// unavailableError is returned while the verification method is not configured
+func (s *BankAccountService) unavailableError(description string) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_049,
+		Message:     "Bank account linking unavailable",
+		Description: description,
+		HTTPStatus:  503,
+	}
+}
+
+// providerError wraps a failure of the linking or micro-deposit provider
+func (s *BankAccountService) providerError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_049,
+		Message:     "Bank account linking unavailable",
+		Description: err.Error(),
+		HTTPStatus:  502,
+	}
+}
+
+// tokenizationError wraps a token vault failure
+func (s *BankAccountService) tokenizationError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_024,
+		Message:     "Failed to secure bank account numbers",
+		Description: err.Error(),
+		HTTPStatus:  502,
+	}
+}
+
+// databaseError wraps a repository failure
+func (s *BankAccountService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
@@ -0,0 +1,149 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// BulkTransition moves a batch of applications to one target state for operations staff. Each
+// application is checked against the state machine and moved in its own transaction, so the report
+// lists which applications moved, which were already there and why the others failed.
+func (s *LoanService) BulkTransition(ctx context.Context, req *domain.BulkTransitionRequest) (*domain.BulkTransitionReport, error) {
+	logger := s.logger.With(
+		zap.String("target_state", string(req.TargetState)),
+		zap.String("performed_by", req.PerformedBy),
+		zap.String("operation", "bulk_transition"),
+	)
+
+	if !req.TargetState.IsValid() || req.TargetState == domain.StateInitiated {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: fmt.Sprintf("Unknown target state: %s", req.TargetState),
+			HTTPStatus:  400,
+		}
+	}
+	if req.TargetState == domain.StateWithdrawn {
+		// withdrawals also expire offers and stop workflows, so they go through the withdraw endpoint
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: "Applications are withdrawn individually through the withdraw endpoint",
+			HTTPStatus:  400,
+		}
+	}
+	if len(req.ApplicationIDs) > domain.MaxBulkTransitionSize {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: fmt.Sprintf("At most %d applications can be transitioned at once", domain.MaxBulkTransitionSize),
+			HTTPStatus:  400,
+		}
+	}
+
+	report := &domain.BulkTransitionReport{
+		TargetState: req.TargetState,
+		Results:     make([]*domain.BulkTransitionItemResult, 0, len(req.ApplicationIDs)),
+	}
+
+	seen := make(map[string]bool, len(req.ApplicationIDs))
+	for _, id := range req.ApplicationIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		result := s.transitionOne(ctx, logger, id, req)
+		switch result.Outcome {
+		case domain.BulkTransitionSucceeded:
+			report.Succeeded++
+		case domain.BulkTransitionSkipped:
+			report.Skipped++
+		default:
+			report.Failed++
+		}
+		report.Results = append(report.Results, result)
+	}
+	report.Requested = len(report.Results)
+	report.CompletedAt = time.Now().UTC()
+
+	logger.Info("Bulk transition completed",
+		zap.Int("requested", report.Requested),
+		zap.Int("succeeded", report.Succeeded),
+		zap.Int("skipped", report.Skipped),
+		zap.Int("failed", report.Failed),
+	)
+	return report, nil
+}
+
+// transitionOne moves one application of a bulk transition and reports the outcome
+func (s *LoanService) transitionOne(ctx context.Context, logger *zap.Logger, applicationID string, req *domain.BulkTransitionRequest) *domain.BulkTransitionItemResult {
+	logger = logger.With(zap.String("application_id", applicationID))
+	result := &domain.BulkTransitionItemResult{
+		ApplicationID: applicationID,
+		ToState:       req.TargetState,
+	}
+	fail := func(code, message string) *domain.BulkTransitionItemResult {
+		result.Outcome = domain.BulkTransitionFailed
+		result.ErrorCode = code
+		result.Error = message
+		return result
+	}
+
+	application, err := s.repo.GetApplicationByID(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return fail(domain.LOAN_010, "Application not found")
+		}
+		logger.Error("Failed to get application", zap.Error(err))
+		return fail(domain.LOAN_023, "Database error")
+	}
+
+	fromState := application.CurrentState
+	result.FromState = &fromState
+
+	if fromState == req.TargetState {
+		result.Outcome = domain.BulkTransitionSkipped
+		return result
+	}
+	if !application.CanTransitionTo(req.TargetState) {
+		return fail(domain.LOAN_008, fmt.Sprintf("Invalid state transition from %s to %s", fromState, req.TargetState))
+	}
+
+	now := time.Now().UTC()
+	application.CurrentState = req.TargetState
+	application.Status = domain.StatusForState(req.TargetState, application.Status)
+	application.UpdatedAt = now
+
+	transition := &domain.StateTransition{
+		ID:               uuid.New().String(),
+		ApplicationID:    application.ID,
+		FromState:        &fromState,
+		ToState:          req.TargetState,
+		TransitionReason: req.Reason,
+		UserID:           &req.PerformedBy,
+		Metadata:         map[string]interface{}{"source": "bulk_transition"},
+		CreatedAt:        now,
+	}
+
+	if err := s.repo.TransitionApplicationState(ctx, application, transition); err != nil {
+		if strings.Contains(err.Error(), "state changed") {
+			logger.Warn("Application state changed during bulk transition")
+			return fail(domain.LOAN_013, "The application changed state while it was being transitioned")
+		}
+		logger.Error("Failed to transition application", zap.Error(err))
+		return fail(domain.LOAN_023, "Database error")
+	}
+
+	result.Outcome = domain.BulkTransitionSucceeded
+	result.TransitionID = transition.ID
+	return result
+}
@@ -0,0 +1,175 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/shared/pkg/dti"
+	"github.com/huuhoait/los-demo/services/shared/pkg/i18n"
+)
+
+// CalculatorService runs the public loan calculators with the same payment, DTI and affordability
+// math as pricing and pre-qualification, so the marketing site quotes the numbers the app does.
+// Nothing is stored and no borrower is needed.
+type CalculatorService struct {
+	policy PreQualificationPolicy
+	dti    *dti.Calculator
+	logger *zap.Logger
+}
+
+// NewCalculatorService creates a new calculator service evaluated against the pre-qualification policy
+func NewCalculatorService(policy PreQualificationPolicy, logger *zap.Logger) *CalculatorService {
+	return &CalculatorService{
+		policy: policy,
+		dti:    dti.NewCalculator(policy.DTI),
+		logger: logger,
+	}
+}
+
+// CalculatePayment returns the monthly payment, total interest and total paid of a loan
+func (s *CalculatorService) CalculatePayment(ctx context.Context, req *domain.PaymentCalculationRequest) (*domain.PaymentCalculation, error) {
+	if err := s.validateAmount(req.LoanAmount); err != nil {
+		return nil, err
+	}
+
+	rate := s.rateOrBase(req.AnnualRate)
+	monthlyPayment := amortizedPayment(req.LoanAmount, rate, req.TermMonths)
+	totalPaid := monthlyPayment * float64(req.TermMonths)
+
+	result := &domain.PaymentCalculation{
+		LoanAmount:     req.LoanAmount,
+		TermMonths:     req.TermMonths,
+		AnnualRate:     roundTo(rate, 2),
+		MonthlyPayment: roundTo(monthlyPayment, 2),
+		TotalInterest:  roundTo(totalPaid-req.LoanAmount, 2),
+		TotalPaid:      roundTo(totalPaid, 2),
+	}
+	result.Formatted = formatAmounts(ctx, map[string]float64{
+		"loan_amount":     result.LoanAmount,
+		"monthly_payment": result.MonthlyPayment,
+		"total_interest":  result.TotalInterest,
+		"total_paid":      result.TotalPaid,
+	})
+	return result, nil
+}
+
+// CalculateDTI returns the debt-to-income ratio of an income and its debts, qualified under the DTI
+// debt treatments as in pre-qualification, and, given a loan amount and term, the ratio after
+// taking that loan on
+func (s *CalculatorService) CalculateDTI(ctx context.Context, req *domain.DTICalculationRequest) (*domain.DTICalculation, error) {
+	qualifying := s.dti.Calculate(dti.Borrower{
+		MonthlyIncome: req.MonthlyIncome,
+		MonthlyDebt:   req.MonthlyDebt,
+		Obligations:   req.Obligations(),
+	})
+	result := &domain.DTICalculation{
+		DTIRatio:    roundTo(qualifying.Ratio, 4),
+		MaxDTIRatio: s.policy.MaxDTIRatio,
+		Adjustments: qualifying.Adjustments,
+	}
+	amounts := map[string]float64{
+		"monthly_income":        req.MonthlyIncome,
+		"monthly_debt_payments": req.MonthlyDebt,
+	}
+
+	ratio := result.DTIRatio
+	if req.LoanAmount > 0 {
+		if req.TermMonths == 0 {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_003,
+				Message:     "Invalid loan term",
+				Description: "term_months is required with loan_amount",
+				HTTPStatus:  400,
+			}
+		}
+		payment := roundTo(amortizedPayment(req.LoanAmount, s.rateOrBase(req.AnnualRate), req.TermMonths), 2)
+		withLoan := roundTo(qualifying.RatioWith(payment), 4)
+		result.LoanPayment = &payment
+		result.DTIRatioWithLoan = &withLoan
+		amounts["loan_payment"] = payment
+		ratio = withLoan
+	}
+	result.WithinLimit = ratio <= s.policy.MaxDTIRatio
+	result.Formatted = formatAmounts(ctx, amounts)
+	return result, nil
+}
+
+// CalculateAffordability returns the largest payment and loan an income and its debts support
+func (s *CalculatorService) CalculateAffordability(ctx context.Context, req *domain.AffordabilityRequest) (*domain.AffordabilityCalculation, error) {
+	term := req.TermMonths
+	if term == 0 {
+		term = preQualifyMaxTerm
+	}
+	rate := s.policy.MaxInterestRate
+	if req.AnnualRate != nil {
+		rate = *req.AnnualRate
+	}
+
+	qualifying := s.dti.Calculate(dti.Borrower{
+		MonthlyIncome: req.MonthlyIncome,
+		MonthlyDebt:   req.MonthlyDebt,
+		Obligations:   req.Obligations(),
+	})
+	maxPayment := maxAffordablePayment(qualifying.MonthlyIncome, qualifying.MonthlyDebt, s.policy.MaxDTIRatio)
+	maxLoanAmount := math.Min(maxPayment/amortizedPayment(1, rate, term), s.policy.MaxLoanAmount)
+	if maxLoanAmount < s.policy.MinLoanAmount {
+		maxLoanAmount = 0
+	}
+
+	result := &domain.AffordabilityCalculation{
+		TermMonths:        term,
+		AnnualRate:        roundTo(rate, 2),
+		MaxMonthlyPayment: roundTo(maxPayment, 2),
+		MaxLoanAmount:     math.Floor(maxLoanAmount/100) * 100,
+		MinLoanAmount:     s.policy.MinLoanAmount,
+	}
+	result.Formatted = formatAmounts(ctx, map[string]float64{
+		"max_monthly_payment": result.MaxMonthlyPayment,
+		"max_loan_amount":     result.MaxLoanAmount,
+		"min_loan_amount":     result.MinLoanAmount,
+	})
+	return result, nil
+}
+
+// validateAmount checks a loan amount is within the amounts the lender offers
+func (s *CalculatorService) validateAmount(amount float64) error {
+	if amount < s.policy.MinLoanAmount {
+		return &domain.LoanError{
+			Code:        domain.LOAN_005,
+			Message:     "Loan amount below minimum",
+			Description: fmt.Sprintf("The minimum loan amount is $%.2f", s.policy.MinLoanAmount),
+			HTTPStatus:  400,
+		}
+	}
+	if s.policy.MaxLoanAmount > 0 && amount > s.policy.MaxLoanAmount {
+		return &domain.LoanError{
+			Code:        domain.LOAN_006,
+			Message:     "Loan amount above maximum",
+			Description: fmt.Sprintf("The maximum loan amount is $%.2f", s.policy.MaxLoanAmount),
+			HTTPStatus:  400,
+		}
+	}
+	return nil
+}
+
+// rateOrBase returns the requested annual rate, or the lender's base rate when none was given
+func (s *CalculatorService) rateOrBase(rate *float64) float64 {
+	if rate != nil {
+		return *rate
+	}
+	return s.policy.BaseInterestRate
+}
+
+// formatAmounts writes each amount in the request's language
+func formatAmounts(ctx context.Context, amounts map[string]float64) map[string]string {
+	lang := i18n.GetLanguageFromContext(ctx)
+	formatted := make(map[string]string, len(amounts))
+	for field, amount := range amounts {
+		formatted[field] = i18n.FormatCurrency(lang, amount)
+	}
+	return formatted
+}
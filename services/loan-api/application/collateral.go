@@ -0,0 +1,230 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// ValuationProvider values pledged collateral, e.g. a vehicle pricing guide or an appraisal vendor
+type ValuationProvider interface {
+	Name() string
+	ValueCollateral(ctx context.Context, collateral *domain.Collateral) (*domain.CollateralValuation, error)
+}
+
+// CollateralService records the collateral securing applications for secured products and values
+// it through the valuation provider registered for its type. Collateral without a provider, or
+// whose provider fails when it is pledged, is valued at what the borrower stated so underwriting
+// can weigh the valuation source.
+type CollateralService struct {
+	repo      LoanRepository
+	providers map[domain.CollateralType]ValuationProvider
+	logger    *zap.Logger
+}
+
+// NewCollateralService creates a new collateral service
+func NewCollateralService(repo LoanRepository, logger *zap.Logger) *CollateralService {
+	return &CollateralService{
+		repo:      repo,
+		providers: make(map[domain.CollateralType]ValuationProvider),
+		logger:    logger,
+	}
+}
+
+// RegisterValuationProvider values collateral of the given type with the provider
+func (s *CollateralService) RegisterValuationProvider(collateralType domain.CollateralType, provider ValuationProvider) {
+	s.providers[collateralType] = provider
+}
+
+// PledgeCollateral records the collateral securing a new application, valued and with the
+// application's loan-to-value ratio at that valuation
+func (s *CollateralService) PledgeCollateral(ctx context.Context, application *domain.LoanApplication, req *domain.CollateralRequest) (*domain.Collateral, error) {
+	logger := s.logger.With(
+		zap.String("application_id", application.ID),
+		zap.String("collateral_type", string(req.Type)),
+		zap.String("operation", "pledge_collateral"),
+	)
+
+	now := time.Now().UTC()
+	collateral := &domain.Collateral{
+		ID:            uuid.New().String(),
+		ApplicationID: application.ID,
+		Type:          req.Type,
+		Vehicle:       req.Vehicle,
+		Asset:         req.Asset,
+		StatedValue:   req.StatedValue,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	valuation, err := s.value(ctx, collateral)
+	if err != nil {
+		logger.Warn("Collateral valuation failed, using the stated value", zap.Error(err))
+		valuation = s.statedValuation(collateral)
+	}
+	collateral.ApplyValuation(valuation, application.LoanAmount)
+
+	if err := s.repo.CreateCollateral(ctx, collateral); err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	logger.Info("Collateral pledged",
+		zap.String("valuation_source", collateral.ValuationSource),
+		zap.Float64("value", collateral.Value),
+		zap.Float64("ltv_ratio", collateral.LTVRatio))
+	return collateral, nil
+}
+
+// GetCollateral retrieves the collateral securing an application. A non-empty userID must own the
+// application.
+func (s *CollateralService) GetCollateral(ctx context.Context, applicationID, userID string) (*domain.Collateral, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "get_collateral"),
+	)
+
+	if userID != "" {
+		application, err := s.getApplication(ctx, logger, applicationID)
+		if err != nil {
+			return nil, err
+		}
+		if application.UserID != userID {
+			logger.Warn("User does not own application", zap.String("user_id", userID))
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_022,
+				Message:     "Unauthorized access",
+				Description: "Collateral can only be viewed by the application's owner",
+				HTTPStatus:  403,
+			}
+		}
+	}
+
+	return s.getCollateral(ctx, logger, applicationID)
+}
+
+// RevalueCollateral values an application's collateral again with its provider, e.g. before
+// underwriting decides on a stated or stale valuation, and updates the loan-to-value ratio
+func (s *CollateralService) RevalueCollateral(ctx context.Context, applicationID string) (*domain.Collateral, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "revalue_collateral"),
+	)
+
+	application, err := s.getApplication(ctx, logger, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	collateral, err := s.getCollateral(ctx, logger, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	valuation, err := s.value(ctx, collateral)
+	if err != nil {
+		logger.Error("Collateral valuation failed", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_057,
+			Message:     "Collateral cannot be valued",
+			Description: err.Error(),
+			HTTPStatus:  502,
+		}
+	}
+	previous := collateral.Value
+	collateral.ApplyValuation(valuation, application.LoanAmount)
+
+	if err := s.repo.UpdateCollateralValuation(ctx, collateral); err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	logger.Info("Collateral revalued",
+		zap.String("valuation_source", collateral.ValuationSource),
+		zap.Float64("previous_value", previous),
+		zap.Float64("value", collateral.Value),
+		zap.Float64("ltv_ratio", collateral.LTVRatio))
+	return collateral, nil
+}
+
+// value values collateral with the provider registered for its type, or at its stated value when
+// there is none
+func (s *CollateralService) value(ctx context.Context, collateral *domain.Collateral) (*domain.CollateralValuation, error) {
+	provider, ok := s.providers[collateral.Type]
+	if !ok {
+		return s.statedValuation(collateral), nil
+	}
+
+	valuation, err := provider.ValueCollateral(ctx, collateral)
+	if err != nil {
+		return nil, fmt.Errorf("%s valuation failed: %w", provider.Name(), err)
+	}
+	if valuation.Value <= 0 {
+		return nil, fmt.Errorf("%s returned no value for the collateral", provider.Name())
+	}
+	if valuation.Source == "" {
+		valuation.Source = provider.Name()
+	}
+	if valuation.ValuedAt.IsZero() {
+		valuation.ValuedAt = time.Now().UTC()
+	}
+	return valuation, nil
+}
+
+// statedValuation values collateral at what the borrower stated
+func (s *CollateralService) statedValuation(collateral *domain.Collateral) *domain.CollateralValuation {
+	return &domain.CollateralValuation{
+		Source:   domain.ValuationSourceStated,
+		Value:    collateral.StatedValue,
+		ValuedAt: time.Now().UTC(),
+	}
+}
+
+// getApplication retrieves an application, translating repository errors
+func (s *CollateralService) getApplication(ctx context.Context, logger *zap.Logger, applicationID string) (*domain.LoanApplication, error) {
+	application, err := s.repo.GetApplicationByID(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Application not found",
+				Description: fmt.Sprintf("No application found with ID: %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get application", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	return application, nil
+}
+
+// getCollateral retrieves an application's collateral, translating repository errors
+func (s *CollateralService) getCollateral(ctx context.Context, logger *zap.Logger, applicationID string) (*domain.Collateral, error) {
+	collateral, err := s.repo.GetCollateral(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Collateral not found",
+				Description: fmt.Sprintf("No collateral secures application %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get collateral", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	return collateral, nil
+}
+
+// databaseError wraps a repository failure
+func (s *CollateralService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
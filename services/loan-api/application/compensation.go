@@ -0,0 +1,274 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// CompensateWorkflow runs one compensation step for a workflow that failed midway, undoing a side
+// effect it left on the application. It is called by the failure workflow Conductor starts for
+// the failed one. Every step can be repeated safely, so a retried task does not undo twice.
+func (s *LoanService) CompensateWorkflow(ctx context.Context, applicationID string, req *domain.CompensationRequest) (*domain.Compensation, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("workflow_id", req.WorkflowID),
+		zap.String("operation", "compensate_workflow"),
+		zap.String("action", string(req.Action)),
+	)
+
+	application, err := s.GetApplication(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	startedAt, err := s.workflowStartTime(ctx, application.ID, req)
+	if err != nil {
+		logger.Warn("Failed workflow not found", zap.Error(err))
+		return nil, err
+	}
+
+	compensation := &domain.Compensation{
+		ApplicationID: application.ID,
+		WorkflowID:    req.WorkflowID,
+		Action:        req.Action,
+	}
+
+	reason, err := s.compensationBlocked(ctx, application)
+	if err != nil {
+		logger.Error("Failed to check whether the application can be compensated", zap.Error(err))
+		return nil, err
+	}
+	if reason != "" {
+		logger.Warn("Workflow side effects cannot be undone", zap.String("reason", reason))
+		compensation.Reason = reason
+		compensation.CompletedAt = time.Now().UTC()
+		return compensation, nil
+	}
+
+	switch req.Action {
+	case domain.CompensationRollbackState:
+		err = s.rollbackWorkflowState(ctx, logger, application, req, startedAt, compensation)
+	case domain.CompensationVoidOffers:
+		err = s.voidWorkflowOffers(ctx, logger, application, startedAt, compensation)
+	case domain.CompensationNotifyBorrower:
+		err = s.notifyWorkflowFailure(ctx, logger, application, compensation)
+	default:
+		err = &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: fmt.Sprintf("Unknown compensation action: %s", req.Action),
+			HTTPStatus:  400,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	compensation.CompletedAt = time.Now().UTC()
+	logger.Info("Compensation step completed",
+		zap.Bool("applied", compensation.Applied),
+		zap.String("reason", compensation.Reason))
+	return compensation, nil
+}
+
+// workflowStartTime returns when the failed workflow started, from its execution record or, for a
+// workflow the loan service did not start, from the request
+func (s *LoanService) workflowStartTime(ctx context.Context, applicationID string, req *domain.CompensationRequest) (time.Time, error) {
+	executions, err := s.repo.ListWorkflowExecutions(ctx, applicationID)
+	if err != nil {
+		return time.Time{}, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+	for _, execution := range executions {
+		if execution.WorkflowID == req.WorkflowID {
+			return execution.StartTime, nil
+		}
+	}
+	if req.WorkflowStartedAt != nil {
+		return req.WorkflowStartedAt.UTC(), nil
+	}
+	return time.Time{}, &domain.LoanError{
+		Code:        domain.LOAN_080,
+		Message:     "Workflow not found",
+		Description: fmt.Sprintf("Workflow %s has no execution record for this application and no start time was given", req.WorkflowID),
+		HTTPStatus:  404,
+	}
+}
+
+// compensationBlocked returns why the application's workflow side effects can no longer be
+// undone, or an empty string if they can
+func (s *LoanService) compensationBlocked(ctx context.Context, application *domain.LoanApplication) (string, error) {
+	if !application.IsCompensable() {
+		return fmt.Sprintf("Application is %s; its state is left as it is", application.CurrentState), nil
+	}
+
+	disbursement, err := s.repo.GetLatestDisbursement(ctx, application.ID)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		return "", &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+	if disbursement != nil && disbursement.Status != domain.DisbursementPending && disbursement.Status != domain.DisbursementFailed {
+		return fmt.Sprintf("Disbursement %s is %s and can no longer be stopped", disbursement.ID, disbursement.Status), nil
+	}
+	return "", nil
+}
+
+// rollbackWorkflowState returns the application to the state it was in when the failed workflow
+// started and cancels a disbursement waiting for funding. The compensating transition names the
+// workflow, so a repeated rollback finds it and does nothing.
+func (s *LoanService) rollbackWorkflowState(ctx context.Context, logger *zap.Logger, application *domain.LoanApplication, req *domain.CompensationRequest, startedAt time.Time, compensation *domain.Compensation) error {
+	transitions, err := s.repo.GetStateTransitions(ctx, application.ID)
+	if err != nil {
+		logger.Error("Failed to get state transitions", zap.Error(err))
+		return &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	marker := fmt.Sprintf("Workflow %s failed", req.WorkflowID)
+	var first *domain.StateTransition
+	for _, transition := range transitions {
+		if transition.CreatedAt.Before(startedAt) {
+			continue
+		}
+		if strings.HasPrefix(transition.TransitionReason, marker) {
+			compensation.Reason = "Application state already rolled back"
+			return nil
+		}
+		if first == nil {
+			first = transition
+		}
+	}
+
+	fromState := application.CurrentState
+	compensation.FromState = &fromState
+	if first == nil || first.FromState == nil || *first.FromState == fromState {
+		compensation.Reason = "Application state has not changed since the workflow started"
+		return nil
+	}
+
+	toState := *first.FromState
+	now := time.Now().UTC()
+	application.CurrentState = toState
+	application.Status = domain.StatusForState(toState, domain.StatusUnderReview)
+	application.UpdatedAt = now
+
+	reason := marker
+	if req.Reason != "" {
+		reason += ": " + req.Reason
+	}
+	transition := &domain.StateTransition{
+		ID:               uuid.New().String(),
+		ApplicationID:    application.ID,
+		FromState:        &fromState,
+		ToState:          toState,
+		TransitionReason: reason,
+		Automated:        true,
+		Metadata: map[string]interface{}{
+			"source":        "compensation",
+			"event":         "workflow_compensated",
+			"workflow_id":   req.WorkflowID,
+			"workflow_name": req.WorkflowName,
+		},
+		CreatedAt: now,
+	}
+	if err := s.repo.TransitionApplicationState(ctx, application, transition); err != nil {
+		if strings.Contains(err.Error(), "state changed") {
+			logger.Warn("Application state changed during rollback")
+			return &domain.LoanError{
+				Code:        domain.LOAN_013,
+				Message:     "State conflict",
+				Description: "The application changed state while it was being rolled back; please retry",
+				HTTPStatus:  409,
+			}
+		}
+		logger.Error("Failed to roll back application state", zap.Error(err))
+		return &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	compensation.Applied = true
+	compensation.ToState = &toState
+	compensation.DisbursementCancelled = s.cancelPendingDisbursement(ctx, logger, application.ID, reason, "compensation")
+
+	logger.Info("Application state rolled back",
+		zap.String("from_state", string(fromState)),
+		zap.String("to_state", string(toState)))
+	return nil
+}
+
+// voidWorkflowOffers voids the offers the failed workflow made, including a selected or accepted
+// one, so the borrower cannot go on to sign or fund them
+func (s *LoanService) voidWorkflowOffers(ctx context.Context, logger *zap.Logger, application *domain.LoanApplication, startedAt time.Time, compensation *domain.Compensation) error {
+	voided, err := s.repo.VoidOffers(ctx, application.ID, startedAt)
+	if err != nil {
+		logger.Error("Failed to void offers", zap.Error(err))
+		return &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	if len(voided) == 0 {
+		compensation.Reason = "No open offers were made by the workflow"
+		return nil
+	}
+	compensation.Applied = true
+	compensation.VoidedOffers = voided
+	logger.Info("Offers voided", zap.Int("offers", len(voided)))
+	return nil
+}
+
+// notifyWorkflowFailure tells the borrower their application could not be processed. A failed
+// send fails the step so the task is retried.
+func (s *LoanService) notifyWorkflowFailure(ctx context.Context, logger *zap.Logger, application *domain.LoanApplication, compensation *domain.Compensation) error {
+	if s.notifier == nil {
+		compensation.Reason = "Notifications are not configured"
+		return nil
+	}
+
+	if err := s.notifier.SendNotification(ctx, application.UserID,
+		"We couldn't finish processing your loan",
+		fmt.Sprintf("Something went wrong while we were processing loan application %s, so we've undone the changes in progress. Our team will look into it and let you know the next steps.", application.ApplicationNumber),
+		map[string]interface{}{
+			"action":         "loan_processing_failed",
+			"application_id": application.ID,
+			"workflow_id":    compensation.WorkflowID,
+		},
+	); err != nil {
+		logger.Error("Failed to send workflow failure notification", zap.Error(err))
+		return &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Failed to notify borrower",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	compensation.Applied = true
+	return nil
+}
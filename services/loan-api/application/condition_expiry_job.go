@@ -0,0 +1,67 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ConditionExpiryJob expires underwriting conditions past their due date on a fixed interval and
+// resumes the workflows waiting on conditions that can go ahead
+type ConditionExpiryJob struct {
+	conditions *ConditionService
+	interval   time.Duration
+	logger     *zap.Logger
+}
+
+func NewConditionExpiryJob(
+	conditions *ConditionService,
+	interval time.Duration,
+	logger *zap.Logger,
+) *ConditionExpiryJob {
+	return &ConditionExpiryJob{
+		conditions: conditions,
+		interval:   interval,
+		logger:     logger,
+	}
+}
+
+// Start runs condition expiry on the configured interval until the context is cancelled
+func (j *ConditionExpiryJob) Start(ctx context.Context) {
+	if j.interval <= 0 {
+		j.logger.Info("Condition expiry job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Condition expiry job stopped")
+			return
+		case <-ticker.C:
+			if err := j.RunOnce(ctx); err != nil {
+				j.logger.Error("Condition expiry failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RunOnce expires due conditions and resumes a batch of workflows waiting on conditions
+func (j *ConditionExpiryJob) RunOnce(ctx context.Context) error {
+	expired, resumed, err := j.conditions.ExpireAndResume(ctx)
+	if err != nil {
+		return err
+	}
+
+	if expired+resumed > 0 {
+		j.logger.Info("Condition expiry completed",
+			zap.Int("expired", expired),
+			zap.Int("workflows_resumed", resumed),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/workflow"
+)
+
+// ConditionService tracks underwriting conditions through their lifecycle. The underwriting worker
+// records the conditions of a conditional approval; borrowers upload evidence per condition,
+// underwriters waive conditions and pending conditions expire at their due date. A workflow
+// waiting on conditions is resumed once every critical one is received or waived, or as soon as
+// one expires.
+type ConditionService struct {
+	repo         LoanRepository
+	documents    DocumentStore
+	orchestrator *workflow.LoanWorkflowOrchestrator
+	batchSize    int
+	logger       *zap.Logger
+}
+
+// NewConditionService creates a new condition service
+func NewConditionService(repo LoanRepository, documents DocumentStore, orchestrator *workflow.LoanWorkflowOrchestrator, batchSize int, logger *zap.Logger) *ConditionService {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &ConditionService{
+		repo:         repo,
+		documents:    documents,
+		orchestrator: orchestrator,
+		batchSize:    batchSize,
+		logger:       logger,
+	}
+}
+
+// List lists the conditions placed on an application
+func (s *ConditionService) List(ctx context.Context, applicationID string) ([]*domain.UnderwritingCondition, error) {
+	conditions, err := s.repo.ListUnderwritingConditions(ctx, applicationID)
+	if err != nil {
+		s.logger.Error("Failed to list underwriting conditions",
+			zap.String("application_id", applicationID),
+			zap.String("operation", "list_underwriting_conditions"),
+			zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	return conditions, nil
+}
+
+// Get retrieves a condition placed on an application
+func (s *ConditionService) Get(ctx context.Context, applicationID, id string) (*domain.UnderwritingCondition, error) {
+	condition, err := s.repo.GetUnderwritingCondition(ctx, applicationID, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_072,
+				Message:     "Underwriting condition not found",
+				Description: fmt.Sprintf("No condition %s found on application %s", id, applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		return nil, s.databaseError(err)
+	}
+	return condition, nil
+}
+
+// UploadEvidence stores a borrower document as evidence for a condition and marks the condition
+// received. Evidence can be added until the condition is waived or expires.
+func (s *ConditionService) UploadEvidence(ctx context.Context, applicationID, conditionID string, upload *domain.DocumentUpload) (*domain.ConditionEvidenceResult, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("condition_id", conditionID),
+		zap.String("operation", "upload_condition_evidence"),
+	)
+
+	if err := upload.Validate(); err != nil {
+		logger.Warn("Invalid evidence upload",
+			zap.Int64("size", upload.Size),
+			zap.String("mime_type", upload.MimeType))
+		return nil, err
+	}
+
+	condition, err := s.Get(ctx, applicationID, conditionID)
+	if err != nil {
+		return nil, err
+	}
+	if !condition.AcceptsEvidence() {
+		return nil, s.stateError(condition)
+	}
+
+	application, err := s.repo.GetApplicationByID(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Application not found",
+				Description: fmt.Sprintf("No application found with ID: %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		return nil, s.databaseError(err)
+	}
+
+	if s.documents == nil {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Document storage unavailable",
+			Description: "No document store is configured",
+			HTTPStatus:  500,
+		}
+	}
+
+	document, err := s.documents.UploadUserDocument(ctx, application.UserID, upload)
+	if err != nil {
+		if loanErr, ok := err.(*domain.LoanError); ok {
+			// The document itself was rejected
+			return nil, loanErr
+		}
+		logger.Error("Failed to store evidence", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Failed to store document",
+			Description: err.Error(),
+			HTTPStatus:  502,
+		}
+	}
+
+	now := time.Now().UTC()
+	recorded, err := s.repo.RecordConditionEvidence(ctx, condition.ID, document.ID, now)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	if !recorded {
+		// Waived or expired while the document was being stored
+		logger.Warn("Condition closed before its evidence was recorded", zap.String("document_id", document.ID))
+		return nil, s.stateError(condition)
+	}
+
+	if condition.ResolvedAt == nil {
+		condition.ResolvedAt = &now
+	}
+	condition.Status = domain.ConditionReceived
+	condition.EvidenceDocumentIDs = append(condition.EvidenceDocumentIDs, document.ID)
+	condition.UpdatedAt = now
+
+	logger.Info("Condition evidence uploaded", zap.String("document_id", document.ID))
+
+	s.resumeIfCleared(ctx, logger, condition)
+
+	return &domain.ConditionEvidenceResult{
+		Document:  document,
+		Condition: condition,
+	}, nil
+}
+
+// Waive records that an underwriter no longer requires a condition
+func (s *ConditionService) Waive(ctx context.Context, applicationID, conditionID string, req *domain.WaiveConditionRequest, waivedBy string) (*domain.UnderwritingCondition, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("condition_id", conditionID),
+		zap.String("waived_by", waivedBy),
+		zap.String("operation", "waive_underwriting_condition"),
+	)
+
+	condition, err := s.Get(ctx, applicationID, conditionID)
+	if err != nil {
+		return nil, err
+	}
+	if !condition.AcceptsEvidence() {
+		return nil, s.stateError(condition)
+	}
+
+	now := time.Now().UTC()
+	reason := strings.TrimSpace(req.Reason)
+	waived, err := s.repo.WaiveUnderwritingCondition(ctx, condition.ID, waivedBy, reason, now)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	if !waived {
+		return nil, s.stateError(condition)
+	}
+
+	condition.Status = domain.ConditionWaived
+	condition.ResolvedAt = &now
+	condition.ResolvedBy = &waivedBy
+	condition.ResolutionNote = reason
+	condition.UpdatedAt = now
+
+	logger.Info("Underwriting condition waived")
+
+	s.resumeIfCleared(ctx, logger, condition)
+
+	return condition, nil
+}
+
+// ExpireAndResume expires pending conditions past their due date, then resumes up to a batch of
+// the workflows waiting on conditions that can go ahead. Resuming also retries workflows that
+// could not be resumed when their last condition cleared.
+func (s *ConditionService) ExpireAndResume(ctx context.Context) (expired, resumed int, err error) {
+	expired, err = s.repo.ExpireUnderwritingConditions(ctx, time.Now().UTC())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	workflowIDs, err := s.repo.ListConditionWorkflowsToResume(ctx, s.batchSize)
+	if err != nil {
+		return expired, 0, err
+	}
+	for _, workflowID := range workflowIDs {
+		logger := s.logger.With(
+			zap.String("workflow_instance_id", workflowID),
+			zap.String("operation", "resume_condition_workflow"),
+		)
+		if s.resumeWorkflow(ctx, logger, workflowID) {
+			resumed++
+		}
+	}
+	return expired, resumed, nil
+}
+
+// resumeIfCleared resumes the workflow waiting on a condition once its critical conditions allow
+func (s *ConditionService) resumeIfCleared(ctx context.Context, logger *zap.Logger, condition *domain.UnderwritingCondition) {
+	if condition.WorkflowInstanceID == nil {
+		return
+	}
+	s.resumeWorkflow(ctx, logger, *condition.WorkflowInstanceID)
+}
+
+// resumeWorkflow completes the task a workflow waits on its conditions with, reporting whether the
+// workflow no longer waits. Failures are logged; the expiry job retries them.
+func (s *ConditionService) resumeWorkflow(ctx context.Context, logger *zap.Logger, workflowID string) bool {
+	conditions, err := s.repo.ListWorkflowConditions(ctx, workflowID)
+	if err != nil {
+		logger.Warn("Failed to list workflow conditions", zap.Error(err))
+		return false
+	}
+	outcome := domain.ConditionsOutcome(conditions)
+	if outcome == "" || len(conditions) == 0 {
+		return false
+	}
+
+	if s.orchestrator == nil {
+		logger.Warn("Workflow orchestrator not configured, conditions workflow left waiting")
+		return false
+	}
+
+	taskReferenceName := "wait_for_conditions_ref"
+	if ref := conditions[0].TaskReferenceName; ref != nil && *ref != "" {
+		taskReferenceName = *ref
+	}
+
+	now := time.Now().UTC()
+	output := map[string]interface{}{
+		"outcome":    outcome,
+		"resolvedAt": now.Format(time.RFC3339),
+	}
+	if err := s.orchestrator.CompleteHumanTask(ctx, workflowID, taskReferenceName, output); err != nil {
+		loanErr, ok := err.(*domain.LoanError)
+		if !ok || (loanErr.Code != domain.LOAN_061 && loanErr.Code != domain.LOAN_062) {
+			logger.Warn("Failed to resume workflow waiting on conditions", zap.Error(err))
+			return false
+		}
+		// The workflow is gone or no longer waits on its conditions, so there is nothing to resume
+		logger.Warn("Workflow is not waiting on its conditions", zap.Error(err))
+	}
+
+	if err := s.repo.MarkConditionWorkflowResumed(ctx, workflowID, now); err != nil {
+		logger.Warn("Failed to record resumed conditions workflow", zap.Error(err))
+	}
+
+	logger.Info("Workflow waiting on conditions resumed", zap.String("outcome", outcome))
+	return true
+}
+
+func (s *ConditionService) stateError(condition *domain.UnderwritingCondition) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_073,
+		Message:     "Underwriting condition closed",
+		Description: fmt.Sprintf("Underwriting condition %s is %s", condition.ID, condition.Status),
+		HTTPStatus:  409,
+	}
+}
+
+func (s *ConditionService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
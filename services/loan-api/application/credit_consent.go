@@ -0,0 +1,216 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// CreditConsentService records borrowers' explicit consent to the hard credit pull underwriting
+// makes. Applications cannot be submitted without it and the consent is passed to the credit_check
+// task as evidence of permissible purpose.
+type CreditConsentService struct {
+	repo              LoanRepository
+	disclosureVersion string
+	logger            *zap.Logger
+}
+
+// NewCreditConsentService creates a new credit consent service; a non-empty disclosureVersion is the
+// only disclosure borrowers may consent to
+func NewCreditConsentService(repo LoanRepository, disclosureVersion string, logger *zap.Logger) *CreditConsentService {
+	return &CreditConsentService{
+		repo:              repo,
+		disclosureVersion: disclosureVersion,
+		logger:            logger,
+	}
+}
+
+// RecordConsent records the borrower's consent to the credit pull for an application they have not
+// yet submitted. Consent already recorded for the application is returned as is.
+func (s *CreditConsentService) RecordConsent(ctx context.Context, applicationID, userID, ipAddress, userAgent string, req *domain.CreditConsentRequest) (*domain.CreditConsent, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("user_id", userID),
+		zap.String("operation", "record_credit_consent"),
+	)
+
+	if !req.Accepted || strings.TrimSpace(req.DisclosureVersion) == "" {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid credit consent",
+			Description: "The credit pull disclosure must be accepted",
+			HTTPStatus:  400,
+		}
+	}
+	if s.disclosureVersion != "" && req.DisclosureVersion != s.disclosureVersion {
+		logger.Warn("Consent given to an outdated disclosure", zap.String("disclosure_version", req.DisclosureVersion))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_059,
+			Message:     "Disclosure version is not current",
+			Description: fmt.Sprintf("Consent must be given to credit pull disclosure version %s", s.disclosureVersion),
+			HTTPStatus:  400,
+		}
+	}
+
+	application, err := s.getOwnedApplication(ctx, logger, applicationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetCreditConsent(ctx, applicationID)
+	if err == nil {
+		logger.Info("Credit consent already recorded", zap.String("consent_id", existing.ID))
+		return existing, nil
+	}
+	if !strings.Contains(err.Error(), "not found") {
+		logger.Error("Failed to get credit consent", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	if application.CurrentState != domain.StateInitiated {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_019,
+			Message:     "Application already submitted",
+			Description: fmt.Sprintf("Application is in %s state; consent to the credit pull is recorded before submission", application.CurrentState),
+			HTTPStatus:  400,
+		}
+	}
+
+	now := time.Now().UTC()
+	consent := &domain.CreditConsent{
+		ID:                uuid.New().String(),
+		ApplicationID:     applicationID,
+		UserID:            userID,
+		DisclosureVersion: strings.TrimSpace(req.DisclosureVersion),
+		IPAddress:         ipAddress,
+		UserAgent:         userAgent,
+		ConsentedAt:       now,
+		CreatedAt:         now,
+	}
+	if err := s.repo.CreateCreditConsent(ctx, consent); err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	logger.Info("Credit consent recorded",
+		zap.String("consent_id", consent.ID),
+		zap.String("disclosure_version", consent.DisclosureVersion),
+		zap.String("ip_address", consent.IPAddress))
+	return consent, nil
+}
+
+// GetConsent retrieves the borrower's consent to the credit pull for an application. A non-empty
+// userID must own the application.
+func (s *CreditConsentService) GetConsent(ctx context.Context, applicationID, userID string) (*domain.CreditConsent, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "get_credit_consent"),
+	)
+
+	if _, err := s.getOwnedApplication(ctx, logger, applicationID, userID); err != nil {
+		return nil, err
+	}
+
+	consent, err := s.repo.GetCreditConsent(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, creditConsentRequiredError(applicationID)
+		}
+		logger.Error("Failed to get credit consent", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	return consent, nil
+}
+
+// GetCreditPullEligibility reports whether an application may trigger a hard credit pull. Only
+// submitted applications the borrower consented to are eligible, and only until they are closed
+// out.
+func (s *CreditConsentService) GetCreditPullEligibility(ctx context.Context, applicationID string) (*domain.CreditPullEligibility, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "get_credit_pull_eligibility"),
+	)
+
+	application, err := s.getOwnedApplication(ctx, logger, applicationID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	eligibility := &domain.CreditPullEligibility{
+		ApplicationID: applicationID,
+		UserID:        application.UserID,
+		State:         application.CurrentState,
+		Submitted:     application.CurrentState != domain.StateInitiated,
+	}
+
+	if _, err := s.repo.GetCreditConsent(ctx, applicationID); err == nil {
+		eligibility.Consented = true
+	} else if !strings.Contains(err.Error(), "not found") {
+		logger.Error("Failed to get credit consent", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	switch application.CurrentState {
+	case domain.StateDenied, domain.StateWithdrawn, domain.StateClosed, domain.StateOfferExpired, domain.StateOfferDeclined:
+	default:
+		eligibility.Eligible = eligibility.Submitted && eligibility.Consented
+	}
+
+	logger.Info("Credit pull eligibility checked",
+		zap.String("state", string(eligibility.State)),
+		zap.Bool("consented", eligibility.Consented),
+		zap.Bool("eligible", eligibility.Eligible))
+	return eligibility, nil
+}
+
+// getOwnedApplication retrieves an application, checking that a non-empty userID owns it
+func (s *CreditConsentService) getOwnedApplication(ctx context.Context, logger *zap.Logger, applicationID, userID string) (*domain.LoanApplication, error) {
+	application, err := s.repo.GetApplicationByID(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Application not found",
+				Description: fmt.Sprintf("No application found with ID: %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get application", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	if userID != "" && application.UserID != userID {
+		logger.Warn("User does not own application")
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_022,
+			Message:     "Unauthorized access",
+			Description: "Credit consent can only be given by the application's borrower",
+			HTTPStatus:  403,
+		}
+	}
+	return application, nil
+}
+
+// databaseError wraps a repository failure
+func (s *CreditConsentService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
+
+// creditConsentRequiredError is returned when an application has no consent to the credit pull
+func creditConsentRequiredError(applicationID string) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_058,
+		Message:     "Credit pull consent required",
+		Description: fmt.Sprintf("The borrower has not consented to the credit pull for application %s", applicationID),
+		HTTPStatus:  409,
+	}
+}
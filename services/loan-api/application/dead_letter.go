@@ -0,0 +1,262 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/workflow"
+)
+
+// DeadLetterService lets operators work the dead-letter queue of workflow tasks workers gave up
+// on: inspect a task's input and failure, correct the input and re-run the workflow from the task,
+// or discard it. It alerts a staff recipient when the number of open tasks reaches a threshold.
+type DeadLetterService struct {
+	repo           LoanRepository
+	orchestrator   *workflow.LoanWorkflowOrchestrator
+	notifier       Notifier
+	alertThreshold int
+	alertRecipient string
+	alerted        bool // the queue has been reported at or above the threshold since it was last below
+	logger         *zap.Logger
+}
+
+// NewDeadLetterService creates a new dead-letter service. Depth alerts are disabled without a
+// threshold or recipient.
+func NewDeadLetterService(repo LoanRepository, orchestrator *workflow.LoanWorkflowOrchestrator, notifier Notifier, alertThreshold int, alertRecipient string, logger *zap.Logger) *DeadLetterService {
+	return &DeadLetterService{
+		repo:           repo,
+		orchestrator:   orchestrator,
+		notifier:       notifier,
+		alertThreshold: alertThreshold,
+		alertRecipient: alertRecipient,
+		logger:         logger,
+	}
+}
+
+// AlertsEnabled reports whether queue depth is alerted on
+func (s *DeadLetterService) AlertsEnabled() bool {
+	return s.alertThreshold > 0 && s.alertRecipient != ""
+}
+
+// CheckDepth counts the open dead-lettered tasks and alerts once when the count reaches the
+// threshold; it alerts again only after the queue has drained below it
+func (s *DeadLetterService) CheckDepth(ctx context.Context) (int, error) {
+	depth, err := s.repo.CountOpenDeadLetters(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if depth < s.alertThreshold {
+		s.alerted = false
+		return depth, nil
+	}
+	if s.alerted || !s.AlertsEnabled() {
+		return depth, nil
+	}
+	if s.notifier == nil {
+		return depth, fmt.Errorf("no notifier configured")
+	}
+
+	if err := s.notifier.SendNotification(ctx, s.alertRecipient,
+		"Dead-letter queue needs attention",
+		fmt.Sprintf("%d workflow tasks have failed for good and are waiting in the dead-letter queue (alert threshold %d).", depth, s.alertThreshold),
+		map[string]interface{}{
+			"dead_letter_depth": depth,
+			"alert_threshold":   s.alertThreshold,
+		},
+	); err != nil {
+		return depth, fmt.Errorf("failed to send dead-letter alert: %w", err)
+	}
+	s.alerted = true
+	s.logger.Warn("Dead-letter queue depth alert sent",
+		zap.Int("depth", depth),
+		zap.Int("threshold", s.alertThreshold))
+	return depth, nil
+}
+
+// List lists dead-lettered tasks, most recently failed first
+func (s *DeadLetterService) List(ctx context.Context, query *domain.DeadLetterQuery) ([]*domain.DeadLetterTask, error) {
+	if query.Limit <= 0 {
+		query.Limit = 50
+	}
+	if query.Status != "" && !query.Status.IsValid() {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: fmt.Sprintf("Unknown dead-letter status: %s", query.Status),
+			HTTPStatus:  400,
+		}
+	}
+
+	tasks, err := s.repo.ListDeadLetters(ctx, query)
+	if err != nil {
+		s.logger.Error("Failed to list dead-lettered tasks", zap.String("operation", "list_dead_letters"), zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	return tasks, nil
+}
+
+// Get retrieves a dead-lettered task with its input and failure
+func (s *DeadLetterService) Get(ctx context.Context, id string) (*domain.DeadLetterTask, error) {
+	task, err := s.repo.GetDeadLetter(ctx, id)
+	if err != nil {
+		return nil, s.deadLetterError(id, err)
+	}
+	return task, nil
+}
+
+// UpdateInput replaces the input an open dead-lettered task will be re-run with
+func (s *DeadLetterService) UpdateInput(ctx context.Context, id string, req *domain.UpdateDeadLetterInputRequest, performedBy string) (*domain.DeadLetterTask, error) {
+	task, err := s.openTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	task.Input = req.Input
+	task.ResolutionNote = strings.TrimSpace(req.Note)
+	task.UpdatedAt = time.Now().UTC()
+	updated, err := s.repo.UpdateDeadLetterInput(ctx, task)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	if !updated {
+		return nil, s.resolvedError(id)
+	}
+
+	s.logger.Info("Dead-lettered task input updated",
+		zap.String("dead_letter_id", id),
+		zap.String("task_type", task.TaskType),
+		zap.String("performed_by", performedBy))
+	return task, nil
+}
+
+// Resubmit re-runs the task's workflow from the task with its current input and closes the task
+func (s *DeadLetterService) Resubmit(ctx context.Context, id string, req *domain.ResolveDeadLetterRequest, performedBy string) (*domain.DeadLetterTask, error) {
+	logger := s.logger.With(
+		zap.String("dead_letter_id", id),
+		zap.String("performed_by", performedBy),
+		zap.String("operation", "resubmit_dead_letter"),
+	)
+
+	if s.orchestrator == nil {
+		logger.Error("Workflow orchestrator not configured")
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_014,
+			Message:     "Workflow engine unavailable",
+			Description: "Tasks cannot be re-submitted because the workflow engine is not configured",
+			HTTPStatus:  503,
+		}
+	}
+
+	task, err := s.openTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.orchestrator.RerunWorkflowFromTask(ctx, task.WorkflowInstanceID, task.TaskID, task.Input); err != nil {
+		return nil, err
+	}
+
+	if err := s.resolve(ctx, task, domain.DeadLetterResubmitted, req.Note, performedBy); err != nil {
+		// Conductor has already re-run the workflow, so the caller must know the task is still open
+		logger.Error("Failed to close re-submitted task", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("Dead-lettered task re-submitted",
+		zap.String("task_type", task.TaskType),
+		zap.String("workflow_id", task.WorkflowInstanceID))
+	return task, nil
+}
+
+// Discard closes a dead-lettered task without re-running it
+func (s *DeadLetterService) Discard(ctx context.Context, id string, req *domain.ResolveDeadLetterRequest, performedBy string) (*domain.DeadLetterTask, error) {
+	if strings.TrimSpace(req.Note) == "" {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: "A note is required to discard a dead-lettered task",
+			HTTPStatus:  400,
+		}
+	}
+
+	task, err := s.openTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resolve(ctx, task, domain.DeadLetterDiscarded, req.Note, performedBy); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Dead-lettered task discarded",
+		zap.String("dead_letter_id", id),
+		zap.String("task_type", task.TaskType),
+		zap.String("performed_by", performedBy))
+	return task, nil
+}
+
+// openTask retrieves a dead-lettered task that has not been resolved
+func (s *DeadLetterService) openTask(ctx context.Context, id string) (*domain.DeadLetterTask, error) {
+	task, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != domain.DeadLetterOpen {
+		return nil, s.resolvedError(id)
+	}
+	return task, nil
+}
+
+// resolve records who closed the task, how and why
+func (s *DeadLetterService) resolve(ctx context.Context, task *domain.DeadLetterTask, status domain.DeadLetterStatus, note, performedBy string) error {
+	now := time.Now().UTC()
+	task.Status = status
+	task.ResolvedBy = &performedBy
+	task.ResolutionNote = strings.TrimSpace(note)
+	task.ResolvedAt = &now
+	task.UpdatedAt = now
+
+	resolved, err := s.repo.ResolveDeadLetter(ctx, task)
+	if err != nil {
+		return s.databaseError(err)
+	}
+	if !resolved {
+		return s.resolvedError(task.ID)
+	}
+	return nil
+}
+
+func (s *DeadLetterService) deadLetterError(id string, err error) error {
+	if strings.Contains(err.Error(), "not found") {
+		return &domain.LoanError{
+			Code:        domain.LOAN_065,
+			Message:     "Dead-lettered task not found",
+			Description: fmt.Sprintf("No dead-lettered task found with ID: %s", id),
+			HTTPStatus:  404,
+		}
+	}
+	return s.databaseError(err)
+}
+
+func (s *DeadLetterService) resolvedError(id string) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_066,
+		Message:     "Dead-lettered task already resolved",
+		Description: fmt.Sprintf("Dead-lettered task %s has already been re-submitted or discarded", id),
+		HTTPStatus:  409,
+	}
+}
+
+func (s *DeadLetterService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DeadLetterMonitorJob checks the depth of the dead-letter queue on a fixed interval and alerts
+// when it reaches the threshold
+type DeadLetterMonitorJob struct {
+	deadLetters *DeadLetterService
+	interval    time.Duration
+	logger      *zap.Logger
+}
+
+func NewDeadLetterMonitorJob(
+	deadLetters *DeadLetterService,
+	interval time.Duration,
+	logger *zap.Logger,
+) *DeadLetterMonitorJob {
+	return &DeadLetterMonitorJob{
+		deadLetters: deadLetters,
+		interval:    interval,
+		logger:      logger,
+	}
+}
+
+// Start runs depth checks on the configured interval until the context is cancelled
+func (j *DeadLetterMonitorJob) Start(ctx context.Context) {
+	if j.interval <= 0 || !j.deadLetters.AlertsEnabled() {
+		j.logger.Info("Dead-letter monitor job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Dead-letter monitor job stopped")
+			return
+		case <-ticker.C:
+			if _, err := j.RunOnce(ctx); err != nil {
+				j.logger.Error("Dead-letter depth check failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RunOnce checks the depth of the dead-letter queue once
+func (j *DeadLetterMonitorJob) RunOnce(ctx context.Context) (int, error) {
+	depth, err := j.deadLetters.CheckDepth(ctx)
+	if err != nil {
+		return depth, err
+	}
+
+	if depth > 0 {
+		j.logger.Info("Dead-letter depth checked", zap.Int("depth", depth))
+	}
+	return depth, nil
+}
@@ -0,0 +1,161 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// DemographicsService records borrowers' answers to the government monitoring questions. The
+// answers are kept apart from the application, are only available to the borrower, and are copied
+// into the compliance record the capture_compliance_data task writes when the application is
+// decided.
+type DemographicsService struct {
+	repo   LoanRepository
+	logger *zap.Logger
+}
+
+// NewDemographicsService creates a new demographics service
+func NewDemographicsService(repo LoanRepository, logger *zap.Logger) *DemographicsService {
+	return &DemographicsService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// RecordDemographics records the borrower's answers for an application, replacing earlier answers.
+// Answers can be given or changed until the application is decided.
+func (s *DemographicsService) RecordDemographics(ctx context.Context, applicationID, userID string, req *domain.ApplicantDemographicsRequest) (*domain.ApplicantDemographics, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("user_id", userID),
+		zap.String("operation", "record_demographics"),
+	)
+
+	application, err := s.getOwnedApplication(ctx, logger, applicationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch application.CurrentState {
+	case domain.StateInitiated, domain.StatePreQualified, domain.StateDocumentsSubmitted,
+		domain.StateIdentityVerified, domain.StateUnderwriting, domain.StateManualReview:
+	default:
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_075,
+			Message:     "Application already decided",
+			Description: fmt.Sprintf("Application is in %s state; demographic information is recorded before a decision", application.CurrentState),
+			HTTPStatus:  409,
+		}
+	}
+
+	now := time.Now().UTC()
+	demographics := &domain.ApplicantDemographics{
+		ID:               uuid.New().String(),
+		ApplicationID:    applicationID,
+		UserID:           userID,
+		Ethnicity:        exclusiveAnswers(req.Ethnicity),
+		Race:             exclusiveAnswers(req.Race),
+		Sex:              req.Sex,
+		CollectionMethod: domain.DemographicsCollectedOnline,
+		CollectedAt:      now,
+		UpdatedAt:        now,
+	}
+	if existing, err := s.repo.GetApplicantDemographics(ctx, applicationID); err == nil {
+		demographics.ID = existing.ID
+		demographics.CollectedAt = existing.CollectedAt
+	} else if !strings.Contains(err.Error(), "not found") {
+		logger.Error("Failed to get applicant demographics", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	if err := s.repo.SaveApplicantDemographics(ctx, demographics); err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	// The answers themselves are not logged
+	logger.Info("Applicant demographics recorded", zap.String("demographics_id", demographics.ID))
+	return demographics, nil
+}
+
+// GetDemographics retrieves the borrower's answers for an application
+func (s *DemographicsService) GetDemographics(ctx context.Context, applicationID, userID string) (*domain.ApplicantDemographics, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "get_demographics"),
+	)
+
+	if _, err := s.getOwnedApplication(ctx, logger, applicationID, userID); err != nil {
+		return nil, err
+	}
+
+	demographics, err := s.repo.GetApplicantDemographics(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_074,
+				Message:     "Demographic information not found",
+				Description: fmt.Sprintf("No demographic information has been provided for application %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get applicant demographics", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	return demographics, nil
+}
+
+// getOwnedApplication retrieves an application, checking that userID owns it. Staff are not
+// exempt: demographic information is only available to the borrower.
+func (s *DemographicsService) getOwnedApplication(ctx context.Context, logger *zap.Logger, applicationID, userID string) (*domain.LoanApplication, error) {
+	application, err := s.repo.GetApplicationByID(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Application not found",
+				Description: fmt.Sprintf("No application found with ID: %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get application", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	if application.UserID != userID {
+		logger.Warn("User does not own application")
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_022,
+			Message:     "Unauthorized access",
+			Description: "Demographic information is only available to the application's borrower",
+			HTTPStatus:  403,
+		}
+	}
+	return application, nil
+}
+
+// databaseError wraps a repository failure
+func (s *DemographicsService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
+
+// exclusiveAnswers drops the other answers to a question answered NOT_PROVIDED, which excludes
+// them
+func exclusiveAnswers(answers []string) []string {
+	for _, answer := range answers {
+		if answer == domain.DemographicNotProvided {
+			return []string{domain.DemographicNotProvided}
+		}
+	}
+	return answers
+}
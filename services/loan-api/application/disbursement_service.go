@@ -0,0 +1,751 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// DisbursementProvider originates ACH credits and reports their settlement
+type DisbursementProvider interface {
+	Name() string
+	// CreateTransfer originates the disbursement and returns the provider's transfer ID and its
+	// reference to the destination account, which is reused when the disbursement is retried
+	CreateTransfer(ctx context.Context, disbursement *domain.Disbursement, destination *domain.DisbursementDestination) (string, string, error)
+	// ParseWebhook verifies a provider notification and returns the transfer event it reports, or
+	// nil for events that do not settle a transfer
+	ParseWebhook(ctx context.Context, body []byte, header func(string) string) (*domain.TransferEvent, error)
+}
+
+// DisbursementService funds signed loans: it schedules a disbursement once the agreement is
+// signed, originates due disbursements in funding batches, retries returned transfers and moves
+// the application to funded, generating its repayment schedule, when the provider reports the
+// transfer settled. A refinance disbursement pays off the refinanced loan, which is closed when the
+// transfer settles, and only the rest is sent to the borrower.
+type DisbursementService struct {
+	repo        LoanRepository
+	userRepo    UserRepository
+	provider    DisbursementProvider
+	detokenizer PIIDetokenizer
+	repayments  RepaymentScheduler
+	notifier    Notifier
+	batchSize   int
+	maxAttempts int
+	retryDelay  time.Duration
+	logger      *zap.Logger
+}
+
+// NewDisbursementService creates a new disbursement service. A nil provider disables disbursement.
+func NewDisbursementService(
+	repo LoanRepository,
+	userRepo UserRepository,
+	provider DisbursementProvider,
+	detokenizer PIIDetokenizer,
+	repayments RepaymentScheduler,
+	notifier Notifier,
+	batchSize int,
+	maxAttempts int,
+	retryDelay time.Duration,
+	logger *zap.Logger,
+) *DisbursementService {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	return &DisbursementService{
+		repo:        repo,
+		userRepo:    userRepo,
+		provider:    provider,
+		detokenizer: detokenizer,
+		repayments:  repayments,
+		notifier:    notifier,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+		retryDelay:  retryDelay,
+		logger:      logger,
+	}
+}
+
+// ScheduleDisbursement schedules the selected offer's amount to be sent to the borrower's verified
+// bank account in the next funding batch, less the payoff of the loan a refinance replaces.
+// Scheduling again while a disbursement is in progress or
+// completed returns it; a new disbursement is only scheduled once the previous one has failed.
+func (s *DisbursementService) ScheduleDisbursement(ctx context.Context, applicationID string) (*domain.Disbursement, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "schedule_disbursement"),
+	)
+
+	if s.provider == nil {
+		return nil, s.unavailableError()
+	}
+
+	application, err := s.getApplication(ctx, logger, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetLatestDisbursement(ctx, applicationID)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		logger.Error("Failed to get disbursement", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	if existing != nil && existing.Status != domain.DisbursementFailed {
+		return existing, nil
+	}
+
+	if application.CurrentState != domain.StateDocumentsSigned {
+		return nil, s.cannotDisburseError(fmt.Sprintf("Application is in %s state; loans are disbursed once the agreement is signed", application.CurrentState))
+	}
+
+	offers, err := s.repo.ListOffers(ctx, applicationID)
+	if err != nil {
+		logger.Error("Failed to list offers", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	offer := findSelectedOffer(offers, "")
+	if offer == nil {
+		return nil, s.cannotDisburseError("No offer has been selected for this application")
+	}
+
+	var payoff *domain.PayoffQuote
+	if application.IsRefinance() {
+		if payoff, err = s.quotePayoff(ctx, *application.RefinancedApplicationID); err != nil {
+			logger.Warn("Payoff of refinanced loan cannot be quoted", zap.Error(err))
+			return nil, err
+		}
+		if offer.OfferAmount <= payoff.PayoffAmount {
+			return nil, s.cannotDisburseError(fmt.Sprintf("The $%.2f loan no longer covers the $%.2f payoff of the refinanced loan", offer.OfferAmount, payoff.PayoffAmount))
+		}
+	}
+
+	account, err := s.repo.GetVerifiedBankAccount(ctx, application.UserID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, s.cannotDisburseError("The borrower has no verified bank account to receive the funds")
+		}
+		logger.Error("Failed to get verified bank account", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	now := time.Now().UTC()
+	disbursement := &domain.Disbursement{
+		ID:                   uuid.New().String(),
+		ApplicationID:        applicationID,
+		OfferID:              offer.ID,
+		BankAccountID:        account.ID,
+		Amount:               offer.OfferAmount,
+		Provider:             s.provider.Name(),
+		DestinationLast4:     account.AccountLast4,
+		DestinationReference: account.ProviderReference,
+		Status:               domain.DisbursementPending,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+	if payoff != nil {
+		disbursement.PayoffApplicationID = &payoff.ApplicationID
+		disbursement.PayoffAmount = payoff.PayoffAmount
+	}
+	if err := s.repo.CreateDisbursement(ctx, disbursement); err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	metadata := map[string]interface{}{
+		"offer_id":        offer.ID,
+		"bank_account_id": account.ID,
+		"amount":          disbursement.Amount,
+	}
+	if payoff != nil {
+		metadata["payoff_application_id"] = payoff.ApplicationID
+		metadata["payoff_amount"] = payoff.PayoffAmount
+		metadata["payoff_good_through"] = payoff.GoodThrough
+	}
+	s.recordEvent(ctx, logger, disbursement, domain.DisbursementEventScheduled, nil, "", metadata)
+
+	logger.Info("Disbursement scheduled",
+		zap.String("disbursement_id", disbursement.ID),
+		zap.Float64("amount", disbursement.Amount),
+		zap.Float64("payoff_amount", disbursement.PayoffAmount))
+	return disbursement, nil
+}
+
+// GetDisbursement retrieves the disbursement most recently scheduled for an application with its
+// audit trail. A non-empty userID must own the application.
+func (s *DisbursementService) GetDisbursement(ctx context.Context, applicationID, userID string) (*domain.Disbursement, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "get_disbursement"),
+	)
+
+	application, err := s.getApplication(ctx, logger, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	if userID != "" && application.UserID != userID {
+		logger.Warn("User does not own application", zap.String("user_id", userID))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_022,
+			Message:     "Unauthorized access",
+			Description: "Disbursements can only be viewed by the application's owner",
+			HTTPStatus:  403,
+		}
+	}
+
+	disbursement, err := s.repo.GetLatestDisbursement(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Disbursement not found",
+				Description: fmt.Sprintf("No disbursement has been scheduled for application %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get disbursement", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	if disbursement.Events, err = s.repo.ListDisbursementEvents(ctx, disbursement.ID); err != nil {
+		logger.Error("Failed to list disbursement events", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	return disbursement, nil
+}
+
+// RunBatch originates every disbursement due as of now, up to the batch size, and records them as
+// a funding batch. Disbursements that cannot be originated are retried in a later batch until
+// their attempts run out. Returns nil when nothing was due.
+func (s *DisbursementService) RunBatch(ctx context.Context) (*domain.FundingBatch, error) {
+	logger := s.logger.With(zap.String("operation", "run_funding_batch"))
+
+	if s.provider == nil {
+		return nil, s.unavailableError()
+	}
+
+	now := time.Now().UTC()
+	batch := &domain.FundingBatch{
+		ID:        uuid.New().String(),
+		Provider:  s.provider.Name(),
+		Status:    domain.FundingBatchOpen,
+		CreatedAt: now,
+	}
+	logger = logger.With(zap.String("batch_id", batch.ID))
+
+	claimed, err := s.repo.ClaimDueDisbursements(ctx, batch.ID, now, s.batchSize)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+
+	if err := s.repo.CreateFundingBatch(ctx, batch); err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	for _, disbursement := range claimed {
+		if s.submit(ctx, logger, disbursement) {
+			batch.SubmittedCount++
+			batch.TotalAmount += disbursement.TransferAmount()
+		} else {
+			batch.FailedCount++
+		}
+	}
+
+	submittedAt := time.Now().UTC()
+	batch.Status = domain.FundingBatchSubmitted
+	batch.SubmittedAt = &submittedAt
+	if err := s.repo.UpdateFundingBatch(ctx, batch); err != nil {
+		return nil, s.databaseError(err)
+	}
+	batch.Disbursements = claimed
+
+	logger.Info("Funding batch submitted",
+		zap.Int("submitted", batch.SubmittedCount),
+		zap.Int("failed", batch.FailedCount),
+		zap.Float64("total_amount", batch.TotalAmount))
+	return batch, nil
+}
+
+// GetFundingBatch retrieves a funding batch with its disbursements
+func (s *DisbursementService) GetFundingBatch(ctx context.Context, batchID string) (*domain.FundingBatch, error) {
+	logger := s.logger.With(
+		zap.String("batch_id", batchID),
+		zap.String("operation", "get_funding_batch"),
+	)
+
+	batch, err := s.repo.GetFundingBatch(ctx, batchID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Funding batch not found",
+				Description: fmt.Sprintf("No funding batch found with ID: %s", batchID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get funding batch", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	if batch.Disbursements, err = s.repo.ListBatchDisbursements(ctx, batchID); err != nil {
+		logger.Error("Failed to list batch disbursements", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	return batch, nil
+}
+
+// HandleWebhook applies a provider notification. A settled transfer completes its disbursement and
+// funds the application; a returned transfer is retried when its return code allows. Errors are
+// returned so the provider retries the notification.
+func (s *DisbursementService) HandleWebhook(ctx context.Context, body []byte, header func(string) string) error {
+	logger := s.logger.With(zap.String("operation", "handle_disbursement_webhook"))
+
+	if s.provider == nil {
+		return s.unavailableError()
+	}
+
+	event, err := s.provider.ParseWebhook(ctx, body, header)
+	if err != nil {
+		logger.Warn("Rejected disbursement webhook", zap.Error(err))
+		return &domain.LoanError{
+			Code:        domain.LOAN_047,
+			Message:     "Invalid disbursement webhook",
+			Description: err.Error(),
+			HTTPStatus:  401,
+		}
+	}
+	if event == nil {
+		return nil
+	}
+
+	logger = logger.With(
+		zap.String("provider_transfer_id", event.ProviderTransferID),
+		zap.String("status", string(event.Status)),
+	)
+
+	disbursement, err := s.repo.GetDisbursementByProviderTransferID(ctx, s.provider.Name(), event.ProviderTransferID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			// Not one of ours, or a transfer superseded by a retry
+			logger.Warn("Webhook for unknown transfer")
+			return nil
+		}
+		logger.Error("Failed to get disbursement", zap.Error(err))
+		return s.databaseError(err)
+	}
+
+	if disbursement.Status != domain.DisbursementSubmitted {
+		return nil
+	}
+	logger = logger.With(
+		zap.String("disbursement_id", disbursement.ID),
+		zap.String("application_id", disbursement.ApplicationID),
+	)
+
+	if event.Status == domain.DisbursementCompleted {
+		return s.completeDisbursement(ctx, logger, disbursement, event)
+	}
+
+	fromStatus := disbursement.Status
+	disbursement.ReturnCode = nil
+	if event.ReturnCode != "" {
+		disbursement.ReturnCode = &event.ReturnCode
+	}
+	reason := event.Reason
+	if reason == "" {
+		reason = "Transfer returned"
+	}
+	disbursement.FailureReason = &reason
+	s.recordEvent(ctx, logger, disbursement, domain.DisbursementEventReturned, &fromStatus, reason, map[string]interface{}{
+		"provider_transfer_id": event.ProviderTransferID,
+		"return_code":          event.ReturnCode,
+	})
+
+	s.retryOrFail(ctx, logger, disbursement, reason, domain.IsRetryableReturn(event.ReturnCode))
+	if err := s.repo.UpdateDisbursement(ctx, disbursement); err != nil {
+		return s.databaseError(err)
+	}
+
+	logger.Info("Transfer returned",
+		zap.String("return_code", event.ReturnCode),
+		zap.String("disbursement_status", string(disbursement.Status)))
+	return nil
+}
+
+// submit originates a claimed disbursement, reporting whether the provider accepted it. A
+// disbursement whose application is no longer awaiting funding fails without being sent.
+func (s *DisbursementService) submit(ctx context.Context, logger *zap.Logger, disbursement *domain.Disbursement) bool {
+	logger = logger.With(
+		zap.String("disbursement_id", disbursement.ID),
+		zap.String("application_id", disbursement.ApplicationID),
+	)
+	fromStatus := disbursement.Status
+
+	destination, err := s.destination(ctx, disbursement)
+	if err != nil {
+		logger.Warn("Disbursement cannot be sent", zap.Error(err))
+		s.fail(ctx, logger, disbursement, &fromStatus, err.Error())
+		s.save(ctx, logger, disbursement)
+		return false
+	}
+
+	disbursement.Attempts++
+	transferID, destinationReference, err := s.provider.CreateTransfer(ctx, disbursement, destination)
+	if destinationReference != "" {
+		disbursement.DestinationReference = &destinationReference
+	}
+	if err != nil {
+		logger.Error("Failed to originate transfer", zap.Int("attempt", disbursement.Attempts), zap.Error(err))
+		reason := err.Error()
+		disbursement.FailureReason = &reason
+		s.recordEvent(ctx, logger, disbursement, domain.DisbursementEventSubmissionFailed, &fromStatus, reason, map[string]interface{}{
+			"attempt": disbursement.Attempts,
+		})
+		s.retryOrFail(ctx, logger, disbursement, reason, true)
+		s.save(ctx, logger, disbursement)
+		return false
+	}
+
+	now := time.Now().UTC()
+	disbursement.Status = domain.DisbursementSubmitted
+	disbursement.ProviderTransferID = &transferID
+	disbursement.NextAttemptAt = nil
+	disbursement.SubmittedAt = &now
+	disbursement.UpdatedAt = now
+	s.save(ctx, logger, disbursement)
+
+	s.recordEvent(ctx, logger, disbursement, domain.DisbursementEventSubmitted, &fromStatus, "", map[string]interface{}{
+		"provider_transfer_id": transferID,
+		"attempt":              disbursement.Attempts,
+		"batch_id":             disbursement.BatchID,
+	})
+	return true
+}
+
+// destination returns the borrower's bank account, checking the application is still awaiting
+// funding and the account is still verified
+func (s *DisbursementService) destination(ctx context.Context, disbursement *domain.Disbursement) (*domain.DisbursementDestination, error) {
+	application, err := s.repo.GetApplicationByID(ctx, disbursement.ApplicationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	if application.CurrentState != domain.StateDocumentsSigned {
+		return nil, fmt.Errorf("application is in %s state and is no longer awaiting funding", application.CurrentState)
+	}
+	if disbursement.PayoffApplicationID != nil {
+		loan, err := s.repo.GetApplicationByID(ctx, *disbursement.PayoffApplicationID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get refinanced loan: %w", err)
+		}
+		if !loan.CanBeRefinanced() {
+			return nil, fmt.Errorf("refinanced loan is in %s state and can no longer be paid off", loan.CurrentState)
+		}
+	}
+
+	if disbursement.BankAccountID == "" {
+		return nil, fmt.Errorf("disbursement has no bank account")
+	}
+	account, err := s.repo.GetBankAccount(ctx, disbursement.BankAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank account: %w", err)
+	}
+	if account.Status != domain.BankAccountVerified {
+		return nil, fmt.Errorf("bank account is %s and can no longer receive funds", account.Status)
+	}
+
+	borrower, err := s.userRepo.GetUserByID(ctx, application.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get borrower: %w", err)
+	}
+
+	destination := &domain.DisbursementDestination{
+		UserID:      application.UserID,
+		Name:        strings.TrimSpace(borrower.FirstName + " " + borrower.LastName),
+		Email:       borrower.Email,
+		BankName:    account.InstitutionName,
+		AccountType: account.AccountType,
+	}
+	// The provider only needs the numbers to register an account it has not seen before
+	if disbursement.DestinationReference == nil {
+		if destination.AccountNumber, err = s.detokenizer.Detokenize(ctx, account.AccountNumberToken); err != nil {
+			return nil, fmt.Errorf("failed to detokenize account number: %w", err)
+		}
+		if destination.RoutingNumber, err = s.detokenizer.Detokenize(ctx, account.RoutingNumberToken); err != nil {
+			return nil, fmt.Errorf("failed to detokenize routing number: %w", err)
+		}
+	}
+	return destination, nil
+}
+
+// retryOrFail schedules a failed transfer to be originated again after the retry delay while
+// attempts remain, or fails the disbursement
+func (s *DisbursementService) retryOrFail(ctx context.Context, logger *zap.Logger, disbursement *domain.Disbursement, reason string, retryable bool) {
+	fromStatus := disbursement.Status
+	if !retryable || disbursement.Attempts >= s.maxAttempts {
+		s.fail(ctx, logger, disbursement, &fromStatus, reason)
+		s.notify(ctx, logger, disbursement.ApplicationID,
+			"We couldn't send your loan funds",
+			"We were unable to deposit your loan into your bank account. Our team will contact you to arrange funding.",
+			"disbursement_failed")
+		return
+	}
+
+	now := time.Now().UTC()
+	nextAttemptAt := now.Add(s.retryDelay)
+	disbursement.Status = domain.DisbursementPending
+	disbursement.NextAttemptAt = &nextAttemptAt
+	disbursement.UpdatedAt = now
+
+	s.recordEvent(ctx, logger, disbursement, domain.DisbursementEventRetryScheduled, &fromStatus, reason, map[string]interface{}{
+		"attempts":        disbursement.Attempts,
+		"next_attempt_at": nextAttemptAt,
+	})
+}
+
+// fail marks a disbursement failed for good
+func (s *DisbursementService) fail(ctx context.Context, logger *zap.Logger, disbursement *domain.Disbursement, fromStatus *domain.DisbursementStatus, reason string) {
+	disbursement.Status = domain.DisbursementFailed
+	disbursement.FailureReason = &reason
+	disbursement.NextAttemptAt = nil
+	disbursement.UpdatedAt = time.Now().UTC()
+
+	s.recordEvent(ctx, logger, disbursement, domain.DisbursementEventFailed, fromStatus, reason, map[string]interface{}{
+		"attempts": disbursement.Attempts,
+	})
+}
+
+// completeDisbursement completes a settled disbursement, moves the application to funded and
+// generates its repayment schedule. The loan a refinance pays off is closed.
+func (s *DisbursementService) completeDisbursement(ctx context.Context, logger *zap.Logger, disbursement *domain.Disbursement, event *domain.TransferEvent) error {
+	now := time.Now().UTC()
+	completedAt := event.OccurredAt
+	if completedAt.IsZero() {
+		completedAt = now
+	}
+
+	fromStatus := disbursement.Status
+	disbursement.Status = domain.DisbursementCompleted
+	disbursement.CompletedAt = &completedAt
+	disbursement.UpdatedAt = now
+	if err := s.repo.UpdateDisbursement(ctx, disbursement); err != nil {
+		return s.databaseError(err)
+	}
+	s.recordEvent(ctx, logger, disbursement, domain.DisbursementEventCompleted, &fromStatus, "", map[string]interface{}{
+		"provider_transfer_id": event.ProviderTransferID,
+	})
+
+	application, err := s.getApplication(ctx, logger, disbursement.ApplicationID)
+	if err != nil {
+		return err
+	}
+	if !application.CanTransitionTo(domain.StateFunded) {
+		logger.Warn("Disbursement completed for application that cannot move to funded",
+			zap.String("current_state", string(application.CurrentState)))
+		return nil
+	}
+
+	fromState := application.CurrentState
+	application.CurrentState = domain.StateFunded
+	application.Status = domain.StatusFunded
+	application.UpdatedAt = now
+	if err := s.repo.UpdateApplication(ctx, application); err != nil {
+		logger.Error("Failed to move application to funded", zap.Error(err))
+		return s.databaseError(err)
+	}
+
+	transition := &domain.StateTransition{
+		ID:               uuid.New().String(),
+		ApplicationID:    application.ID,
+		FromState:        &fromState,
+		ToState:          domain.StateFunded,
+		TransitionReason: "Loan proceeds disbursed",
+		Automated:        true,
+		Metadata: map[string]interface{}{
+			"source":               "disbursement_webhook",
+			"disbursement_id":      disbursement.ID,
+			"provider_transfer_id": event.ProviderTransferID,
+			"amount":               disbursement.Amount,
+		},
+		CreatedAt: now,
+	}
+	if err := s.repo.CreateStateTransition(ctx, transition); err != nil {
+		logger.Warn("Failed to create state transition", zap.Error(err))
+	}
+
+	message := fmt.Sprintf("$%.2f has been deposited to your account ending in %s.", disbursement.TransferAmount(), disbursement.DestinationLast4)
+	if disbursement.PayoffApplicationID != nil {
+		s.closeRefinancedLoan(ctx, logger, disbursement)
+		message = fmt.Sprintf("Your previous loan has been paid off with $%.2f of your new loan. ", disbursement.PayoffAmount) + message
+	}
+	// The schedule can be generated again later, so a failure does not fail the notification
+	if schedule, err := s.repayments.GenerateSchedule(ctx, application.ID); err != nil {
+		logger.Error("Failed to generate repayment schedule", zap.Error(err))
+	} else {
+		message += fmt.Sprintf(" Your first payment of $%.2f is due on %s.", schedule.PaymentAmount, schedule.FirstPaymentDate.Format("January 2, 2006"))
+	}
+
+	s.notify(ctx, logger, application.ID, "Your loan has been funded", message, "loan_funded")
+
+	logger.Info("Loan funded", zap.Float64("amount", disbursement.Amount))
+	return nil
+}
+
+// closeRefinancedLoan closes the loan a settled refinance disbursement paid off. The disbursement has
+// already completed, so failures are only logged for servicing to close the loan by hand.
+func (s *DisbursementService) closeRefinancedLoan(ctx context.Context, logger *zap.Logger, disbursement *domain.Disbursement) {
+	loanID := *disbursement.PayoffApplicationID
+	logger = logger.With(zap.String("refinanced_application_id", loanID))
+
+	loan, err := s.repo.GetApplicationByID(ctx, loanID)
+	if err != nil {
+		logger.Error("Failed to get refinanced loan", zap.Error(err))
+		return
+	}
+	if !loan.CanTransitionTo(domain.StateClosed) {
+		logger.Warn("Refinanced loan cannot be closed", zap.String("current_state", string(loan.CurrentState)))
+		return
+	}
+
+	now := time.Now().UTC()
+	fromState := loan.CurrentState
+	loan.CurrentState = domain.StateClosed
+	loan.Status = domain.StatusClosed
+	loan.UpdatedAt = now
+	if err := s.repo.UpdateApplication(ctx, loan); err != nil {
+		logger.Error("Failed to close refinanced loan", zap.Error(err))
+		return
+	}
+
+	transition := &domain.StateTransition{
+		ID:               uuid.New().String(),
+		ApplicationID:    loan.ID,
+		FromState:        &fromState,
+		ToState:          domain.StateClosed,
+		TransitionReason: "Loan paid off by refinance",
+		Automated:        true,
+		Metadata: map[string]interface{}{
+			"source":                   "disbursement_webhook",
+			"refinance_application_id": disbursement.ApplicationID,
+			"disbursement_id":          disbursement.ID,
+			"payoff_amount":            disbursement.PayoffAmount,
+		},
+		CreatedAt: now,
+	}
+	if err := s.repo.CreateStateTransition(ctx, transition); err != nil {
+		logger.Warn("Failed to create state transition", zap.Error(err))
+	}
+
+	logger.Info("Refinanced loan paid off", zap.Float64("payoff_amount", disbursement.PayoffAmount))
+}
+
+// quotePayoff quotes the payoff of the loan a refinance application replaces
+func (s *DisbursementService) quotePayoff(ctx context.Context, loanID string) (*domain.PayoffQuote, error) {
+	loan, err := s.getApplication(ctx, s.logger, loanID)
+	if err != nil {
+		return nil, err
+	}
+	return quotePayoff(ctx, s.repo, loan, time.Now().UTC())
+}
+
+// save persists a disbursement updated during a funding batch; failures are only logged so the
+// rest of the batch is still submitted
+func (s *DisbursementService) save(ctx context.Context, logger *zap.Logger, disbursement *domain.Disbursement) {
+	if err := s.repo.UpdateDisbursement(ctx, disbursement); err != nil {
+		logger.Error("Failed to save disbursement", zap.Error(err))
+	}
+}
+
+// recordEvent adds a step to the disbursement's audit trail; failures are only logged
+func (s *DisbursementService) recordEvent(ctx context.Context, logger *zap.Logger, disbursement *domain.Disbursement, eventType domain.DisbursementEventType, fromStatus *domain.DisbursementStatus, detail string, metadata map[string]interface{}) {
+	event := &domain.DisbursementEvent{
+		ID:             uuid.New().String(),
+		DisbursementID: disbursement.ID,
+		ApplicationID:  disbursement.ApplicationID,
+		EventType:      eventType,
+		FromStatus:     fromStatus,
+		ToStatus:       disbursement.Status,
+		Detail:         detail,
+		Metadata:       metadata,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := s.repo.CreateDisbursementEvent(ctx, event); err != nil {
+		logger.Warn("Failed to record disbursement event", zap.String("event_type", string(eventType)), zap.Error(err))
+	}
+}
+
+// notify sends the borrower of an application a notification about their funds; failures are only logged
+func (s *DisbursementService) notify(ctx context.Context, logger *zap.Logger, applicationID, title, message, action string) {
+	if s.notifier == nil {
+		return
+	}
+	application, err := s.repo.GetApplicationByID(ctx, applicationID)
+	if err != nil {
+		logger.Warn("Failed to get application to notify borrower", zap.Error(err))
+		return
+	}
+	if err := s.notifier.SendNotification(ctx, application.UserID, title, message, map[string]interface{}{
+		"action":         action,
+		"application_id": applicationID,
+	}); err != nil {
+		logger.Warn("Failed to notify borrower", zap.String("action", action), zap.Error(err))
+	}
+}
+
+// getApplication retrieves an application, translating repository errors
+func (s *DisbursementService) getApplication(ctx context.Context, logger *zap.Logger, applicationID string) (*domain.LoanApplication, error) {
+	application, err := s.repo.GetApplicationByID(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Application not found",
+				Description: fmt.Sprintf("No application found with ID: %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get application", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	return application, nil
+}
+
+// cannotDisburseError is returned when the application cannot be disbursed yet
+func (s *DisbursementService) cannotDisburseError(description string) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_045,
+		Message:     "Disbursement cannot be scheduled",
+		Description: description,
+		HTTPStatus:  409,
+	}
+}
+
+// unavailableError is returned while no disbursement provider is configured
+func (s *DisbursementService) unavailableError() error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_046,
+		Message:     "Disbursement unavailable",
+		Description: "No disbursement provider is configured",
+		HTTPStatus:  503,
+	}
+}
+
+// databaseError wraps a repository failure
+func (s *DisbursementService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
@@ -0,0 +1,240 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// stubDisbursementRepository holds one application and bank account and records the disbursement
+// saves and audit events; methods submit does not use are left to the embedded nil interface
+type stubDisbursementRepository struct {
+	LoanRepository
+	application *domain.LoanApplication
+	account     *domain.BankAccount
+	saves       int
+	events      []domain.DisbursementEventType
+}
+
+func (r *stubDisbursementRepository) GetApplicationByID(ctx context.Context, id string) (*domain.LoanApplication, error) {
+	return r.application, nil
+}
+
+func (r *stubDisbursementRepository) GetBankAccount(ctx context.Context, id string) (*domain.BankAccount, error) {
+	return r.account, nil
+}
+
+func (r *stubDisbursementRepository) UpdateDisbursement(ctx context.Context, disbursement *domain.Disbursement) error {
+	r.saves++
+	return nil
+}
+
+func (r *stubDisbursementRepository) CreateDisbursementEvent(ctx context.Context, event *domain.DisbursementEvent) error {
+	r.events = append(r.events, event.EventType)
+	return nil
+}
+
+type stubBorrowerRepository struct {
+	UserRepository
+}
+
+func (r *stubBorrowerRepository) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
+	return &domain.User{ID: id, FirstName: "Jane", LastName: "Doe", Email: "jane@example.com"}, nil
+}
+
+// stubDetokenizer counts detokenizations, which only happen for accounts the provider has not seen
+type stubDetokenizer struct {
+	calls int
+}
+
+func (d *stubDetokenizer) Detokenize(ctx context.Context, token string) (string, error) {
+	d.calls++
+	return "detokenized-" + token, nil
+}
+
+// stubDisbursementProvider answers each CreateTransfer with the next result, recording what it was sent
+type stubDisbursementProvider struct {
+	results      []error
+	calls        int
+	destinations []*domain.DisbursementDestination
+	references   []*string
+}
+
+func (p *stubDisbursementProvider) Name() string { return "stub" }
+
+func (p *stubDisbursementProvider) CreateTransfer(ctx context.Context, disbursement *domain.Disbursement, destination *domain.DisbursementDestination) (string, string, error) {
+	p.destinations = append(p.destinations, destination)
+	p.references = append(p.references, disbursement.DestinationReference)
+	err := p.results[p.calls]
+	p.calls++
+	// The destination is registered before the transfer is attempted, so it is known even when
+	// the transfer fails
+	if err != nil {
+		return "", "funding-source-1", err
+	}
+	return "transfer-" + disbursement.ID, "funding-source-1", nil
+}
+
+func (p *stubDisbursementProvider) ParseWebhook(ctx context.Context, body []byte, header func(string) string) (*domain.TransferEvent, error) {
+	return nil, nil
+}
+
+func newTestDisbursementService(provider *stubDisbursementProvider, maxAttempts int) (*DisbursementService, *stubDisbursementRepository, *stubDetokenizer) {
+	repo := &stubDisbursementRepository{
+		application: &domain.LoanApplication{ID: "app-1", UserID: "user-1", CurrentState: domain.StateDocumentsSigned},
+		account: &domain.BankAccount{
+			ID:                 "account-1",
+			UserID:             "user-1",
+			AccountType:        domain.AccountType("checking"),
+			AccountNumberToken: "account-token",
+			RoutingNumberToken: "routing-token",
+			Status:             domain.BankAccountVerified,
+		},
+	}
+	detokenizer := &stubDetokenizer{}
+	service := NewDisbursementService(repo, &stubBorrowerRepository{}, provider, detokenizer, nil, nil, 10, maxAttempts, time.Hour, zap.NewNop())
+	return service, repo, detokenizer
+}
+
+func TestDisbursementService_Submit(t *testing.T) {
+	tests := []struct {
+		name        string
+		results     []error // the provider's answer to each submission
+		maxAttempts int
+		wantStatus  domain.DisbursementStatus
+		wantEvents  []domain.DisbursementEventType
+	}{
+		{
+			name:        "accepted on the first attempt",
+			results:     []error{nil},
+			maxAttempts: 3,
+			wantStatus:  domain.DisbursementSubmitted,
+			wantEvents:  []domain.DisbursementEventType{domain.DisbursementEventSubmitted},
+		},
+		{
+			name:        "accepted after a failed submission",
+			results:     []error{fmt.Errorf("timeout"), nil},
+			maxAttempts: 3,
+			wantStatus:  domain.DisbursementSubmitted,
+			wantEvents: []domain.DisbursementEventType{
+				domain.DisbursementEventSubmissionFailed,
+				domain.DisbursementEventRetryScheduled,
+				domain.DisbursementEventSubmitted,
+			},
+		},
+		{
+			name:        "fails once the attempts run out",
+			results:     []error{fmt.Errorf("timeout"), fmt.Errorf("timeout")},
+			maxAttempts: 2,
+			wantStatus:  domain.DisbursementFailed,
+			wantEvents: []domain.DisbursementEventType{
+				domain.DisbursementEventSubmissionFailed,
+				domain.DisbursementEventRetryScheduled,
+				domain.DisbursementEventSubmissionFailed,
+				domain.DisbursementEventFailed,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &stubDisbursementProvider{results: tt.results}
+			service, repo, detokenizer := newTestDisbursementService(provider, tt.maxAttempts)
+			disbursement := &domain.Disbursement{
+				ID:            "disbursement-1",
+				ApplicationID: "app-1",
+				BankAccountID: "account-1",
+				Amount:        10000,
+				Status:        domain.DisbursementQueued,
+			}
+
+			for attempt := range tt.results {
+				accepted := service.submit(context.Background(), zap.NewNop(), disbursement)
+				assert.Equal(t, tt.results[attempt] == nil, accepted)
+				if disbursement.Status == domain.DisbursementPending {
+					require.NotNil(t, disbursement.NextAttemptAt)
+					disbursement.Status = domain.DisbursementQueued
+				}
+			}
+
+			assert.Equal(t, tt.wantStatus, disbursement.Status)
+			assert.Equal(t, len(tt.results), disbursement.Attempts)
+			assert.Equal(t, tt.wantEvents, repo.events)
+			assert.Equal(t, len(tt.results), repo.saves)
+
+			// Every submission is for the same disbursement and borrower, and once the provider has
+			// registered the destination account it is sent to it again without the account numbers
+			require.Len(t, provider.destinations, len(tt.results))
+			for i, destination := range provider.destinations {
+				assert.Equal(t, "user-1", destination.UserID)
+				if i == 0 {
+					assert.Nil(t, provider.references[i])
+					assert.Equal(t, "detokenized-account-token", destination.AccountNumber)
+					continue
+				}
+				require.NotNil(t, provider.references[i])
+				assert.Equal(t, "funding-source-1", *provider.references[i])
+				assert.Empty(t, destination.AccountNumber)
+			}
+			assert.Equal(t, 2, detokenizer.calls)
+			require.NotNil(t, disbursement.DestinationReference)
+			assert.Equal(t, "funding-source-1", *disbursement.DestinationReference)
+		})
+	}
+}
+
+func TestDisbursementService_SubmitStopsWhenNoLongerAwaitingFunding(t *testing.T) {
+	provider := &stubDisbursementProvider{results: []error{nil}}
+	service, repo, _ := newTestDisbursementService(provider, 3)
+	repo.application.CurrentState = domain.StateWithdrawn
+	disbursement := &domain.Disbursement{ID: "disbursement-1", ApplicationID: "app-1", BankAccountID: "account-1", Status: domain.DisbursementQueued}
+
+	assert.False(t, service.submit(context.Background(), zap.NewNop(), disbursement))
+	assert.Equal(t, domain.DisbursementFailed, disbursement.Status)
+	assert.Zero(t, provider.calls)
+	assert.Zero(t, disbursement.Attempts)
+}
+
+func TestDisbursementService_RetryOrFail(t *testing.T) {
+	tests := []struct {
+		name       string
+		attempts   int
+		retryable  bool
+		wantStatus domain.DisbursementStatus
+		wantEvent  domain.DisbursementEventType
+	}{
+		{name: "retryable with attempts left", attempts: 1, retryable: true, wantStatus: domain.DisbursementPending, wantEvent: domain.DisbursementEventRetryScheduled},
+		{name: "retryable on the last attempt", attempts: 3, retryable: true, wantStatus: domain.DisbursementFailed, wantEvent: domain.DisbursementEventFailed},
+		{name: "not retryable", attempts: 1, retryable: false, wantStatus: domain.DisbursementFailed, wantEvent: domain.DisbursementEventFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, _ := newTestDisbursementService(&stubDisbursementProvider{}, 3)
+			disbursement := &domain.Disbursement{ID: "disbursement-1", ApplicationID: "app-1", Status: domain.DisbursementSubmitted, Attempts: tt.attempts}
+
+			before := time.Now().UTC()
+			service.retryOrFail(context.Background(), zap.NewNop(), disbursement, "R01", tt.retryable)
+
+			assert.Equal(t, tt.wantStatus, disbursement.Status)
+			assert.Equal(t, []domain.DisbursementEventType{tt.wantEvent}, repo.events)
+			// Deciding whether to retry never counts as an attempt; only submissions do
+			assert.Equal(t, tt.attempts, disbursement.Attempts)
+			if tt.wantStatus == domain.DisbursementPending {
+				require.NotNil(t, disbursement.NextAttemptAt)
+				assert.False(t, disbursement.NextAttemptAt.Before(before.Add(time.Hour)))
+				return
+			}
+			assert.Nil(t, disbursement.NextAttemptAt)
+			require.NotNil(t, disbursement.FailureReason)
+			assert.Equal(t, "R01", *disbursement.FailureReason)
+		})
+	}
+}
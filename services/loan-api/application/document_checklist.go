@@ -0,0 +1,419 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// evaluateDocumentChecklist applies the checklist rules to an application and persists the
+// requirements it produces; requirements the application already has are kept as they are. With
+// no condition the base checklist is evaluated, falling back to the default documents when no
+// rules are configured. With a condition, the rules for its type and the document types it names
+// are added, the latter requested from the primary borrower.
+func (s *LoanService) evaluateDocumentChecklist(ctx context.Context, app *domain.LoanApplication, condition *domain.UnderwritingCondition) ([]*domain.DocumentRequirement, error) {
+	rules, err := s.repo.ListDocumentRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conditionType := ""
+	if condition != nil {
+		conditionType = condition.ConditionType
+	}
+
+	now := time.Now().UTC()
+	var requirements []*domain.DocumentRequirement
+	seen := make(map[string]bool)
+	require := func(borrower, documentType, source, description string, ruleID *string) {
+		key := borrower + "/" + documentType
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+
+		requirement := &domain.DocumentRequirement{
+			ID:            uuid.New().String(),
+			ApplicationID: app.ID,
+			Borrower:      borrower,
+			DocumentType:  documentType,
+			Source:        source,
+			RuleID:        ruleID,
+			Description:   description,
+			Status:        domain.DocumentRequirementRequired,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if condition != nil {
+			requirement.ConditionID = &condition.ID
+		}
+		requirements = append(requirements, requirement)
+	}
+
+	borrowers := map[string]domain.EmploymentStatus{domain.ChecklistBorrower: app.EmploymentStatus}
+	if app.CoBorrower != nil {
+		borrowers[domain.ChecklistCoBorrower] = app.CoBorrower.EmploymentStatus
+	}
+
+	for _, borrower := range []string{domain.ChecklistBorrower, domain.ChecklistCoBorrower} {
+		status, ok := borrowers[borrower]
+		if !ok {
+			continue
+		}
+		for _, rule := range rules {
+			if rule.Matches(app, borrower, status, conditionType) {
+				ruleID := rule.ID
+				source := domain.RequirementSourceRule
+				if condition != nil {
+					source = domain.RequirementSourceCondition
+				}
+				require(borrower, rule.DocumentType, source, rule.Description, &ruleID)
+			}
+		}
+		if condition == nil && len(rules) == 0 {
+			for _, documentType := range domain.RequiredDocuments(status) {
+				require(borrower, documentType, domain.RequirementSourceDefault, "", nil)
+			}
+		}
+	}
+
+	if condition != nil {
+		for _, documentType := range condition.DocumentTypes {
+			require(domain.ChecklistBorrower, documentType, domain.RequirementSourceCondition, condition.Description, nil)
+		}
+	}
+
+	if len(requirements) > 0 {
+		if err := s.repo.AddDocumentRequirements(ctx, requirements); err != nil {
+			return nil, err
+		}
+	}
+
+	return requirements, nil
+}
+
+// documentChecklist retrieves an application's persisted checklist, evaluating the base checklist
+// first for applications created before checklists were persisted
+func (s *LoanService) documentChecklist(ctx context.Context, app *domain.LoanApplication) ([]*domain.DocumentRequirement, error) {
+	requirements, err := s.repo.ListDocumentRequirements(ctx, app.ID)
+	if err != nil || len(requirements) > 0 {
+		return requirements, err
+	}
+
+	if _, err := s.evaluateDocumentChecklist(ctx, app, nil); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDocumentRequirements(ctx, app.ID)
+}
+
+// GetDocumentChecklistStatus reports the collection progress of an application's document
+// checklist. Outstanding borrower documents are matched against the borrower's uploads in the user
+// service; when it is unavailable the last recorded progress is returned. A fully received
+// checklist completes document collection.
+func (s *LoanService) GetDocumentChecklistStatus(ctx context.Context, applicationID string) (*domain.DocumentChecklistStatus, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "get_document_checklist_status"),
+	)
+
+	application, err := s.GetApplication(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	requirements, err := s.documentChecklist(ctx, application)
+	if err != nil {
+		logger.Error("Failed to load document checklist", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	s.reconcileDocumentChecklist(ctx, logger, application, requirements)
+
+	status := domain.NewDocumentChecklistStatus(applicationID, requirements)
+	if status.Pending == 0 {
+		s.completeDocumentCollection(ctx, logger, application)
+	}
+
+	return status, nil
+}
+
+// reconcileDocumentChecklist marks outstanding borrower requirements received when the borrower
+// has uploaded a document that satisfies them. Co-borrower documents are collected outside the
+// user service and are not reconciled here.
+func (s *LoanService) reconcileDocumentChecklist(ctx context.Context, logger *zap.Logger, app *domain.LoanApplication, requirements []*domain.DocumentRequirement) {
+	if s.documents == nil {
+		return
+	}
+
+	outstanding := false
+	for _, requirement := range requirements {
+		if requirement.Borrower == domain.ChecklistBorrower && requirement.Status == domain.DocumentRequirementRequired {
+			outstanding = true
+			break
+		}
+	}
+	if !outstanding {
+		return
+	}
+
+	documents, err := s.documents.ListUserDocuments(ctx, app.UserID)
+	if err != nil {
+		logger.Warn("Failed to list borrower documents, checklist progress may be stale", zap.Error(err))
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, requirement := range requirements {
+		if requirement.Borrower != domain.ChecklistBorrower || requirement.Status != domain.DocumentRequirementRequired {
+			continue
+		}
+		for _, document := range documents {
+			if !domain.SatisfiedBy(requirement.DocumentType, document.DocumentType) {
+				continue
+			}
+			if err := s.repo.MarkDocumentRequirementReceived(ctx, requirement.ID, document.ID, now); err != nil {
+				logger.Warn("Failed to record received document",
+					zap.String("requirement_id", requirement.ID),
+					zap.Error(err))
+				break
+			}
+			documentID := document.ID
+			requirement.Status = domain.DocumentRequirementReceived
+			requirement.DocumentID = &documentID
+			requirement.UpdatedAt = now
+			break
+		}
+	}
+}
+
+// AddUnderwritingCondition places an underwriting condition on an application and adds the
+// documents it requires to the application's checklist
+func (s *LoanService) AddUnderwritingCondition(ctx context.Context, applicationID string, req *domain.AddConditionRequest) (*domain.DocumentChecklistStatus, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("condition_type", req.ConditionType),
+		zap.String("operation", "add_underwriting_condition"),
+	)
+
+	application, err := s.GetApplication(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the base checklist exists before it is extended
+	if _, err := s.documentChecklist(ctx, application); err != nil {
+		logger.Error("Failed to load document checklist", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	priority := req.Priority
+	if priority == "" {
+		priority = domain.ConditionPriorityMedium
+	}
+	now := time.Now().UTC()
+	condition := &domain.UnderwritingCondition{
+		ID:            uuid.New().String(),
+		ApplicationID: applicationID,
+		ConditionType: req.ConditionType,
+		Description:   req.Description,
+		DocumentTypes: req.DocumentTypes,
+		Priority:      priority,
+		Critical:      req.Critical,
+		Status:        domain.ConditionPending,
+		DueAt:         req.DueAt,
+		CreatedBy:     req.CreatedBy,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := s.repo.CreateUnderwritingCondition(ctx, condition); err != nil {
+		logger.Error("Failed to create underwriting condition", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Failed to add underwriting condition",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	added, err := s.evaluateDocumentChecklist(ctx, application, condition)
+	if err != nil {
+		logger.Error("Failed to add condition documents to checklist", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Failed to update document checklist",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	logger.Info("Underwriting condition added successfully",
+		zap.String("condition_id", condition.ID),
+		zap.Int("documents_required", len(added)),
+	)
+
+	return s.GetDocumentChecklistStatus(ctx, applicationID)
+}
+
+// UploadDocument stores a borrower document for an application in the user service and records it
+// against the checklist requirement it satisfies. Once every requirement has been received a
+// pre-qualified application moves to documents_submitted, which the workflow picks up.
+func (s *LoanService) UploadDocument(ctx context.Context, applicationID, userID string, upload *domain.DocumentUpload) (*domain.DocumentUploadResult, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("document_type", upload.DocumentType),
+		zap.String("operation", "upload_document"),
+	)
+
+	if err := upload.Validate(); err != nil {
+		logger.Warn("Invalid document upload",
+			zap.Int64("size", upload.Size),
+			zap.String("mime_type", upload.MimeType))
+		return nil, err
+	}
+
+	application, err := s.GetApplication(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userID != "" && application.UserID != userID {
+		logger.Warn("User does not own application", zap.String("user_id", userID))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_022,
+			Message:     "Unauthorized access",
+			Description: "Documents can only be uploaded by the application's owner",
+			HTTPStatus:  403,
+		}
+	}
+
+	if !application.CanUploadDocuments() {
+		logger.Warn("Documents cannot be uploaded in current state",
+			zap.String("current_state", string(application.CurrentState)))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_040,
+			Message:     "Documents cannot be uploaded",
+			Description: fmt.Sprintf("Application is in %s state and no longer collects documents", application.CurrentState),
+			HTTPStatus:  409,
+		}
+	}
+
+	requirements, err := s.documentChecklist(ctx, application)
+	if err != nil {
+		logger.Error("Failed to load document checklist", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	requirement := domain.OutstandingRequirement(requirements, upload.DocumentType, upload.Requirement)
+	if requirement == nil {
+		logger.Warn("Document does not satisfy an outstanding requirement", zap.String("requirement", upload.Requirement))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_039,
+			Message:     "Document not required",
+			Description: fmt.Sprintf("No outstanding checklist requirement is satisfied by a %s document", upload.DocumentType),
+			HTTPStatus:  422,
+		}
+	}
+
+	if s.documents == nil {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Document storage unavailable",
+			Description: "No document store is configured",
+			HTTPStatus:  500,
+		}
+	}
+
+	document, err := s.documents.UploadUserDocument(ctx, application.UserID, upload)
+	if err != nil {
+		if loanErr, ok := err.(*domain.LoanError); ok {
+			// The document itself was rejected
+			return nil, loanErr
+		}
+		logger.Error("Failed to store document", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Failed to store document",
+			Description: err.Error(),
+			HTTPStatus:  502,
+		}
+	}
+
+	now := time.Now().UTC()
+	if err := s.repo.MarkDocumentRequirementReceived(ctx, requirement.ID, document.ID, now); err != nil {
+		// The document is stored, so the requirement is picked up when the checklist is next reconciled
+		logger.Warn("Failed to record received document", zap.String("document_id", document.ID), zap.Error(err))
+	} else {
+		requirement.Status = domain.DocumentRequirementReceived
+		requirement.DocumentID = &document.ID
+		requirement.UpdatedAt = now
+	}
+
+	checklist := domain.NewDocumentChecklistStatus(applicationID, requirements)
+	if checklist.Pending == 0 {
+		s.completeDocumentCollection(ctx, logger, application)
+	}
+
+	logger.Info("Document uploaded successfully",
+		zap.String("document_id", document.ID),
+		zap.String("requirement", requirement.DocumentType),
+		zap.Int("pending", checklist.Pending),
+	)
+
+	return &domain.DocumentUploadResult{
+		Document:    document,
+		Requirement: requirement,
+		Checklist:   checklist,
+	}, nil
+}
+
+// completeDocumentCollection moves a pre-qualified application whose checklist has been fully
+// received to documents_submitted. Applications further along are left as they are.
+func (s *LoanService) completeDocumentCollection(ctx context.Context, logger *zap.Logger, application *domain.LoanApplication) {
+	if application.CurrentState != domain.StatePreQualified || !application.CanTransitionTo(domain.StateDocumentsSubmitted) {
+		return
+	}
+
+	fromState := application.CurrentState
+	application.CurrentState = domain.StateDocumentsSubmitted
+	application.UpdatedAt = time.Now().UTC()
+
+	if err := s.repo.UpdateApplication(ctx, application); err != nil {
+		logger.Warn("Failed to move application to documents submitted", zap.Error(err))
+		return
+	}
+
+	transition := &domain.StateTransition{
+		ID:               uuid.New().String(),
+		ApplicationID:    application.ID,
+		FromState:        &fromState,
+		ToState:          domain.StateDocumentsSubmitted,
+		TransitionReason: "All required documents received",
+		Automated:        true,
+		Metadata:         map[string]interface{}{"source": "document_upload"},
+		CreatedAt:        time.Now().UTC(),
+	}
+	if err := s.repo.CreateStateTransition(ctx, transition); err != nil {
+		logger.Warn("Failed to create state transition", zap.Error(err))
+	}
+}
@@ -0,0 +1,500 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// DocumentRenderer renders loan documents from templates
+type DocumentRenderer interface {
+	Render(documentType string, data *domain.DocumentData) (*domain.RenderedDocument, error)
+}
+
+// defaultAdverseActionReason is given when a denial recorded no reasons of its own
+const defaultAdverseActionReason = "Your application did not meet our credit criteria for the amount and term requested"
+
+// DocumentGenerationService renders loan agreements, TILA disclosures, approval letters and adverse
+// action notices for applications, stores them with the borrower's documents and serves them for
+// download
+type DocumentGenerationService struct {
+	repo      LoanRepository
+	userRepo  UserRepository
+	renderer  DocumentRenderer
+	documents DocumentStore
+	notifier  Notifier
+	logger    *zap.Logger
+}
+
+// NewDocumentGenerationService creates a new document generation service
+func NewDocumentGenerationService(repo LoanRepository, userRepo UserRepository, renderer DocumentRenderer, documents DocumentStore, notifier Notifier, logger *zap.Logger) *DocumentGenerationService {
+	return &DocumentGenerationService{
+		repo:      repo,
+		userRepo:  userRepo,
+		renderer:  renderer,
+		documents: documents,
+		notifier:  notifier,
+		logger:    logger,
+	}
+}
+
+// GenerateDocument renders a document for an application and stores it. Agreements, TILA
+// disclosures and approval letters carry the terms of the offer most recently selected; adverse
+// action notices are issued for denied applications with the reasons recorded on the denial.
+// generatedBy is empty when the document is generated automatically.
+func (s *DocumentGenerationService) GenerateDocument(ctx context.Context, applicationID, documentType, generatedBy string) (*domain.GeneratedDocument, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("document_type", documentType),
+		zap.String("operation", "generate_document"),
+	)
+
+	if !domain.IsGeneratedDocumentType(documentType) {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid document type",
+			Description: fmt.Sprintf("Documents of type %s are not generated", documentType),
+			HTTPStatus:  400,
+		}
+	}
+
+	application, err := s.getApplication(ctx, logger, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	borrower, err := s.userRepo.GetUserByID(ctx, application.UserID)
+	if err != nil {
+		logger.Error("Failed to get borrower", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	data := &domain.DocumentData{
+		Application: application,
+		Borrower:    borrower,
+		Date:        time.Now().UTC(),
+	}
+	if documentType == domain.DocumentAdverseActionNotice {
+		if application.CurrentState != domain.StateDenied {
+			return nil, s.cannotGenerateError(fmt.Sprintf("Application is in %s state; adverse action notices are issued once denied", application.CurrentState))
+		}
+		if data.Reasons, err = s.denialReasons(ctx, logger, applicationID); err != nil {
+			return nil, err
+		}
+	} else {
+		offers, err := s.repo.ListOffers(ctx, applicationID)
+		if err != nil {
+			logger.Error("Failed to list offers", zap.Error(err))
+			return nil, s.databaseError(err)
+		}
+		if data.Offer = findSelectedOffer(offers, ""); data.Offer == nil {
+			return nil, s.cannotGenerateError("No offer has been selected for this application")
+		}
+	}
+
+	document, err := s.renderAndStore(ctx, logger, documentType, data)
+	if err != nil {
+		return nil, err
+	}
+	if data.Offer != nil {
+		document.OfferID = &data.Offer.ID
+	}
+	if generatedBy != "" {
+		document.GeneratedBy = &generatedBy
+	}
+	if err := s.repo.CreateGeneratedDocument(ctx, document); err != nil {
+		logger.Error("Failed to record generated document",
+			zap.String("stored_document_id", document.StoredDocumentID),
+			zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	if documentType == domain.DocumentAdverseActionNotice && s.notifier != nil {
+		if err := s.notifier.SendNotification(ctx, application.UserID,
+			"An update on your loan application",
+			"A notice explaining our decision on your application is available in your documents.",
+			map[string]interface{}{
+				"action":         "adverse_action_notice",
+				"application_id": applicationID,
+				"document_id":    document.ID,
+			}); err != nil {
+			logger.Warn("Failed to notify borrower", zap.Error(err))
+		}
+	}
+
+	logger.Info("Document generated",
+		zap.String("document_id", document.ID),
+		zap.String("stored_document_id", document.StoredDocumentID),
+	)
+
+	return document, nil
+}
+
+// IssueDecisionLetter sends the borrower the letter telling them of the underwriting decision on
+// their application: an approval letter with the approved terms, or an adverse action notice with
+// the principal reasons for the denial. The letter is stored with the borrower's documents and
+// emailed to them, and the returned document records when it was delivered, from which the 30 days
+// allowed for notifying the borrower of the action taken are measured. A letter already delivered
+// for the decision is returned as it is, so retries do not send it twice.
+func (s *DocumentGenerationService) IssueDecisionLetter(ctx context.Context, applicationID string, request *domain.DecisionLetterRequest) (*domain.GeneratedDocument, error) {
+	documentType := domain.DocumentApprovalLetter
+	if request.Decision == "denied" {
+		documentType = domain.DocumentAdverseActionNotice
+	}
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("document_type", documentType),
+		zap.String("operation", "issue_decision_letter"),
+	)
+
+	application, err := s.getApplication(ctx, logger, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A letter generated by an earlier attempt is delivered rather than generated again
+	documents, err := s.repo.ListGeneratedDocuments(ctx, applicationID)
+	if err != nil {
+		logger.Error("Failed to list generated documents", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	var document *domain.GeneratedDocument
+	for _, generated := range documents {
+		if generated.DocumentType == documentType && generated.GeneratedBy == nil {
+			document = generated
+			break
+		}
+	}
+	if document != nil && document.DeliveredAt != nil {
+		logger.Info("Decision letter already delivered", zap.String("document_id", document.ID))
+		return document, nil
+	}
+
+	if document == nil {
+		borrower, err := s.userRepo.GetUserByID(ctx, application.UserID)
+		if err != nil {
+			logger.Error("Failed to get borrower", zap.Error(err))
+			return nil, s.databaseError(err)
+		}
+
+		data := &domain.DocumentData{
+			Application: application,
+			Borrower:    borrower,
+			Date:        time.Now().UTC(),
+		}
+		reasonCodes := []string{}
+		if documentType == domain.DocumentAdverseActionNotice {
+			for _, reason := range request.Reasons {
+				reasonCodes = append(reasonCodes, reason.Code)
+				if description := strings.TrimSpace(reason.Description); description != "" {
+					data.Reasons = append(data.Reasons, description)
+				} else {
+					data.Reasons = append(data.Reasons, reason.Code)
+				}
+			}
+			if len(data.Reasons) == 0 {
+				if data.Reasons, err = s.denialReasons(ctx, logger, applicationID); err != nil {
+					return nil, err
+				}
+			}
+		} else {
+			data.Offer = approvedTerms(application, request)
+		}
+
+		if document, err = s.renderAndStore(ctx, logger, documentType, data); err != nil {
+			return nil, err
+		}
+		document.ReasonCodes = reasonCodes
+		if err := s.repo.CreateGeneratedDocument(ctx, document); err != nil {
+			logger.Error("Failed to record generated document",
+				zap.String("stored_document_id", document.StoredDocumentID),
+				zap.Error(err))
+			return nil, s.databaseError(err)
+		}
+	}
+
+	if s.notifier == nil {
+		return nil, s.deliveryError(fmt.Errorf("no notifier is configured"))
+	}
+	title, message := "Your loan application has been approved", "Your approval letter is available in your documents."
+	if documentType == domain.DocumentAdverseActionNotice {
+		title, message = "An update on your loan application", "A notice explaining our decision on your application is available in your documents."
+	}
+	if err := s.notifier.SendEmailNotification(ctx, application.UserID, title, message, map[string]interface{}{
+		"action":         documentType,
+		"application_id": applicationID,
+		"document_id":    document.ID,
+	}); err != nil {
+		logger.Error("Failed to deliver decision letter", zap.String("document_id", document.ID), zap.Error(err))
+		return nil, s.deliveryError(err)
+	}
+
+	deliveredAt := time.Now().UTC()
+	if err := s.repo.MarkGeneratedDocumentDelivered(ctx, document.ID, deliveredAt); err != nil {
+		logger.Error("Failed to record decision letter delivery", zap.String("document_id", document.ID), zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	document.DeliveredAt = &deliveredAt
+
+	logger.Info("Decision letter delivered",
+		zap.String("document_id", document.ID),
+		zap.Strings("reason_codes", document.ReasonCodes),
+		zap.Time("delivered_at", deliveredAt),
+	)
+
+	return document, nil
+}
+
+// approvedTerms are the terms an approval letter gives, those of the decision where it set them and
+// otherwise those the borrower asked for
+func approvedTerms(application *domain.LoanApplication, request *domain.DecisionLetterRequest) *domain.LoanOffer {
+	terms := &domain.LoanOffer{
+		ApplicationID: application.ID,
+		OfferAmount:   request.ApprovedAmount,
+		TermMonths:    request.TermMonths,
+		InterestRate:  request.InterestRate,
+		APR:           request.APR,
+	}
+	if terms.OfferAmount <= 0 {
+		terms.OfferAmount = application.LoanAmount
+	}
+	if terms.TermMonths <= 0 {
+		terms.TermMonths = application.RequestedTerm
+	}
+	return terms
+}
+
+// renderAndStore renders a document and stores it with the borrower's documents, returning the
+// document for the caller to record
+func (s *DocumentGenerationService) renderAndStore(ctx context.Context, logger *zap.Logger, documentType string, data *domain.DocumentData) (*domain.GeneratedDocument, error) {
+	rendered, err := s.renderer.Render(documentType, data)
+	if err != nil {
+		logger.Error("Failed to render document", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Failed to generate document",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	if s.documents == nil {
+		return nil, s.storageError(fmt.Errorf("no document store is configured"))
+	}
+	stored, err := s.documents.UploadUserDocument(ctx, data.Application.UserID, &domain.DocumentUpload{
+		DocumentType: documentType,
+		FileName:     rendered.FileName,
+		MimeType:     rendered.MimeType,
+		Size:         int64(len(rendered.Content)),
+		Content:      bytes.NewReader(rendered.Content),
+	})
+	if err != nil {
+		logger.Error("Failed to store document", zap.Error(err))
+		return nil, s.storageError(err)
+	}
+
+	return &domain.GeneratedDocument{
+		ID:               uuid.New().String(),
+		ApplicationID:    data.Application.ID,
+		DocumentType:     documentType,
+		StoredDocumentID: stored.ID,
+		FileName:         rendered.FileName,
+		MimeType:         rendered.MimeType,
+		Size:             int64(len(rendered.Content)),
+		CreatedAt:        data.Date,
+	}, nil
+}
+
+// ListGeneratedDocuments lists the documents generated for an application, newest first. A
+// non-empty userID must own the application.
+func (s *DocumentGenerationService) ListGeneratedDocuments(ctx context.Context, applicationID, userID string) ([]*domain.GeneratedDocument, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "list_generated_documents"),
+	)
+
+	if _, err := s.getOwnedApplication(ctx, logger, applicationID, userID); err != nil {
+		return nil, err
+	}
+
+	documents, err := s.repo.ListGeneratedDocuments(ctx, applicationID)
+	if err != nil {
+		logger.Error("Failed to list generated documents", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	if documents == nil {
+		documents = []*domain.GeneratedDocument{}
+	}
+
+	return documents, nil
+}
+
+// DownloadGeneratedDocument opens a generated document's stored PDF. A non-empty userID must own
+// the application. The caller must close the returned content.
+func (s *DocumentGenerationService) DownloadGeneratedDocument(ctx context.Context, applicationID, documentID, userID string) (*domain.DocumentContent, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("document_id", documentID),
+		zap.String("operation", "download_generated_document"),
+	)
+
+	application, err := s.getOwnedApplication(ctx, logger, applicationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := s.repo.GetGeneratedDocument(ctx, documentID)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		logger.Error("Failed to get generated document", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	if document == nil || document.ApplicationID != applicationID {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_010,
+			Message:     "Document not found",
+			Description: fmt.Sprintf("No document %s was generated for application %s", documentID, applicationID),
+			HTTPStatus:  404,
+		}
+	}
+
+	if s.documents == nil {
+		return nil, s.storageError(fmt.Errorf("no document store is configured"))
+	}
+	content, err := s.documents.DownloadUserDocument(ctx, application.UserID, document.StoredDocumentID)
+	if err != nil {
+		logger.Error("Failed to download stored document",
+			zap.String("stored_document_id", document.StoredDocumentID),
+			zap.Error(err))
+		return nil, s.storageError(err)
+	}
+
+	// The name and type recorded at generation are authoritative
+	content.FileName = document.FileName
+	content.MimeType = document.MimeType
+	return content, nil
+}
+
+// denialReasons returns the principal reasons recorded when the application was denied, taken
+// from the transition's reasons metadata or, failing that, its semicolon separated reason
+func (s *DocumentGenerationService) denialReasons(ctx context.Context, logger *zap.Logger, applicationID string) ([]string, error) {
+	transitions, err := s.repo.GetStateTransitions(ctx, applicationID)
+	if err != nil {
+		logger.Error("Failed to get state transitions", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	var denial *domain.StateTransition
+	for _, transition := range transitions {
+		if transition.ToState == domain.StateDenied && (denial == nil || transition.CreatedAt.After(denial.CreatedAt)) {
+			denial = transition
+		}
+	}
+
+	var reasons []string
+	if denial != nil {
+		if recorded, ok := denial.Metadata["reasons"].([]interface{}); ok {
+			for _, reason := range recorded {
+				if text, ok := reason.(string); ok && strings.TrimSpace(text) != "" {
+					reasons = append(reasons, strings.TrimSpace(text))
+				}
+			}
+		}
+		if len(reasons) == 0 {
+			for _, reason := range strings.Split(denial.TransitionReason, ";") {
+				if reason = strings.TrimSpace(reason); reason != "" {
+					reasons = append(reasons, reason)
+				}
+			}
+		}
+	}
+
+	if len(reasons) == 0 {
+		reasons = []string{defaultAdverseActionReason}
+	}
+	return reasons, nil
+}
+
+// getOwnedApplication retrieves an application, checking a non-empty userID owns it
+func (s *DocumentGenerationService) getOwnedApplication(ctx context.Context, logger *zap.Logger, applicationID, userID string) (*domain.LoanApplication, error) {
+	application, err := s.getApplication(ctx, logger, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userID != "" && application.UserID != userID {
+		logger.Warn("User does not own application", zap.String("user_id", userID))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_022,
+			Message:     "Unauthorized access",
+			Description: "Documents can only be viewed by the application's owner",
+			HTTPStatus:  403,
+		}
+	}
+
+	return application, nil
+}
+
+// getApplication retrieves an application, translating repository errors
+func (s *DocumentGenerationService) getApplication(ctx context.Context, logger *zap.Logger, applicationID string) (*domain.LoanApplication, error) {
+	application, err := s.repo.GetApplicationByID(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Application not found",
+				Description: fmt.Sprintf("No application found with ID: %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get application", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	return application, nil
+}
+
+// cannotGenerateError is returned when the application's state does not allow the document
+func (s *DocumentGenerationService) cannotGenerateError(description string) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_044,
+		Message:     "Document cannot be generated",
+		Description: description,
+		HTTPStatus:  409,
+	}
+}
+
+// storageError wraps a failure to reach the user service's document storage
+func (s *DocumentGenerationService) storageError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Document storage unavailable",
+		Description: err.Error(),
+		HTTPStatus:  502,
+	}
+}
+
+// deliveryError wraps a failure to send a letter to the borrower
+func (s *DocumentGenerationService) deliveryError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Letter delivery unavailable",
+		Description: err.Error(),
+		HTTPStatus:  502,
+	}
+}
+
+// databaseError wraps a repository failure
+func (s *DocumentGenerationService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
@@ -0,0 +1,216 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// Duplicate scoring weights per shared signal; the score is capped at 1
+const (
+	duplicateWeightSSN    = 0.6
+	duplicateWeightEmail  = 0.5
+	duplicateWeightDevice = 0.3
+)
+
+// DuplicateDetectionService compares each new application's identity signals (SSN token,
+// normalized email and device) with other borrowers' applications made within a window and records
+// suspected duplicates. Pending matches route automated approval to manual review until an admin
+// links or dismisses them.
+type DuplicateDetectionService struct {
+	repo   LoanRepository
+	window time.Duration
+	logger *zap.Logger
+}
+
+// NewDuplicateDetectionService creates a new duplicate detection service comparing applications
+// made within window of each other
+func NewDuplicateDetectionService(repo LoanRepository, window time.Duration, logger *zap.Logger) *DuplicateDetectionService {
+	if window <= 0 {
+		window = 90 * 24 * time.Hour
+	}
+	return &DuplicateDetectionService{
+		repo:   repo,
+		window: window,
+		logger: logger,
+	}
+}
+
+// DetectDuplicates records the identity signals of a new application and a match for every other
+// borrower's application within the window that shares one of them
+func (s *DuplicateDetectionService) DetectDuplicates(ctx context.Context, application *domain.LoanApplication, user *domain.User, deviceID string) ([]*domain.DuplicateMatch, error) {
+	logger := s.logger.With(
+		zap.String("application_id", application.ID),
+		zap.String("operation", "detect_duplicates"),
+	)
+
+	fingerprint := &domain.ApplicationFingerprint{
+		ApplicationID: application.ID,
+		UserID:        application.UserID,
+		SSNToken:      user.SSNToken,
+		Email:         domain.NormalizeEmail(user.Email),
+		DeviceID:      strings.TrimSpace(deviceID),
+		CreatedAt:     application.CreatedAt,
+	}
+	if err := s.repo.CreateApplicationFingerprint(ctx, fingerprint); err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	candidates, err := s.repo.FindDuplicateCandidates(ctx, fingerprint, fingerprint.CreatedAt.Add(-s.window))
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	var matches []*domain.DuplicateMatch
+	for _, candidate := range candidates {
+		score, reasons := scoreDuplicate(fingerprint, candidate)
+		if len(reasons) == 0 {
+			continue
+		}
+
+		match := &domain.DuplicateMatch{
+			ID:                     uuid.New().String(),
+			ApplicationID:          application.ID,
+			CandidateApplicationID: candidate.ApplicationID,
+			CandidateUserID:        candidate.UserID,
+			Score:                  score,
+			Reasons:                reasons,
+			Status:                 domain.DuplicateMatchPending,
+			CreatedAt:              time.Now().UTC(),
+		}
+		if err := s.repo.CreateDuplicateMatch(ctx, match); err != nil {
+			logger.Warn("Failed to record duplicate match", zap.Error(err),
+				zap.String("candidate_application_id", candidate.ApplicationID))
+			continue
+		}
+		matches = append(matches, match)
+	}
+
+	if len(matches) > 0 {
+		logger.Warn("Suspected duplicate application flagged for manual review", zap.Int("match_count", len(matches)))
+	}
+	return matches, nil
+}
+
+// ListMatches lists the suspected duplicates of an application
+func (s *DuplicateDetectionService) ListMatches(ctx context.Context, applicationID string) ([]*domain.DuplicateMatch, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "list_duplicate_matches"),
+	)
+
+	if _, err := s.repo.GetApplicationByID(ctx, applicationID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Application not found",
+				Description: fmt.Sprintf("No application found with ID: %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get application", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	matches, err := s.repo.ListDuplicateMatches(ctx, applicationID)
+	if err != nil {
+		logger.Error("Failed to list duplicate matches", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	return matches, nil
+}
+
+// LinkMatch confirms that a suspected duplicate is the same applicant
+func (s *DuplicateDetectionService) LinkMatch(ctx context.Context, applicationID, matchID string, req *domain.ReviewDuplicateMatchRequest) (*domain.DuplicateMatch, error) {
+	return s.reviewMatch(ctx, applicationID, matchID, domain.DuplicateMatchLinked, req)
+}
+
+// DismissMatch clears a suspected duplicate as a false positive
+func (s *DuplicateDetectionService) DismissMatch(ctx context.Context, applicationID, matchID string, req *domain.ReviewDuplicateMatchRequest) (*domain.DuplicateMatch, error) {
+	return s.reviewMatch(ctx, applicationID, matchID, domain.DuplicateMatchDismissed, req)
+}
+
+// reviewMatch records an admin's decision on a pending match of the application
+func (s *DuplicateDetectionService) reviewMatch(ctx context.Context, applicationID, matchID string, status domain.DuplicateMatchStatus, req *domain.ReviewDuplicateMatchRequest) (*domain.DuplicateMatch, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("match_id", matchID),
+		zap.String("status", string(status)),
+		zap.String("operation", "review_duplicate_match"),
+	)
+
+	match, err := s.repo.GetDuplicateMatch(ctx, matchID)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		logger.Error("Failed to get duplicate match", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	if match == nil || match.ApplicationID != applicationID {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_010,
+			Message:     "Duplicate match not found",
+			Description: fmt.Sprintf("No duplicate match %s found for application %s", matchID, applicationID),
+			HTTPStatus:  404,
+		}
+	}
+	if match.Status != domain.DuplicateMatchPending {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_060,
+			Message:     "Duplicate match already reviewed",
+			Description: fmt.Sprintf("The duplicate match was already %s", match.Status),
+			HTTPStatus:  409,
+		}
+	}
+
+	now := time.Now().UTC()
+	match.Status = status
+	match.ReviewedBy = &req.ReviewedBy
+	match.ReviewedAt = &now
+	if note := strings.TrimSpace(req.Note); note != "" {
+		match.ReviewNote = &note
+	}
+
+	if err := s.repo.UpdateDuplicateMatchReview(ctx, match); err != nil {
+		logger.Error("Failed to update duplicate match review", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	logger.Info("Duplicate match reviewed", zap.String("reviewed_by", req.ReviewedBy))
+	return match, nil
+}
+
+// scoreDuplicate scores how strongly a candidate's signals suggest the same applicant and lists the
+// signals they share
+func scoreDuplicate(fingerprint, candidate *domain.ApplicationFingerprint) (float64, []string) {
+	var score float64
+	var reasons []string
+	if fingerprint.SSNToken != "" && fingerprint.SSNToken == candidate.SSNToken {
+		score += duplicateWeightSSN
+		reasons = append(reasons, domain.DuplicateReasonSSN)
+	}
+	if fingerprint.Email != "" && fingerprint.Email == candidate.Email {
+		score += duplicateWeightEmail
+		reasons = append(reasons, domain.DuplicateReasonEmail)
+	}
+	if fingerprint.DeviceID != "" && fingerprint.DeviceID == candidate.DeviceID {
+		score += duplicateWeightDevice
+		reasons = append(reasons, domain.DuplicateReasonDevice)
+	}
+	return math.Min(score, 1), reasons
+}
+
+// databaseError wraps a repository failure
+func (s *DuplicateDetectionService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
@@ -0,0 +1,63 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// FundingBatchJob originates the disbursements that are due in a funding batch on a fixed interval,
+// including returned transfers whose retry delay has passed
+type FundingBatchJob struct {
+	disbursements *DisbursementService
+	interval      time.Duration
+	logger        *zap.Logger
+}
+
+func NewFundingBatchJob(
+	disbursements *DisbursementService,
+	interval time.Duration,
+	logger *zap.Logger,
+) *FundingBatchJob {
+	return &FundingBatchJob{
+		disbursements: disbursements,
+		interval:      interval,
+		logger:        logger,
+	}
+}
+
+// Start runs funding batches on the configured interval until the context is cancelled
+func (j *FundingBatchJob) Start(ctx context.Context) {
+	if j.interval <= 0 || j.disbursements.provider == nil {
+		j.logger.Info("Funding batch job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Funding batch job stopped")
+			return
+		case <-ticker.C:
+			if err := j.RunOnce(ctx); err != nil {
+				j.logger.Error("Funding batch failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RunOnce submits a funding batch of the disbursements due now
+func (j *FundingBatchJob) RunOnce(ctx context.Context) error {
+	batch, err := j.disbursements.RunBatch(ctx)
+	if err != nil {
+		return err
+	}
+	if batch == nil {
+		j.logger.Debug("No disbursements due")
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/shared/pkg/payroll"
+)
+
+// payrollLookback is how far back paystubs are read when computing verified income
+const payrollLookback = 13 * 30 * 24 * time.Hour
+
+// IncomeVerificationService completes payroll income verifications. The underwriting worker starts
+// a verification and leaves it pending until the borrower connects their payroll account with the
+// returned connect token; when the aggregator reports their pay data has synced, the verified
+// income is computed and recorded on the verification, flagging a variance from the stated income
+// beyond the tolerance for underwriter review.
+type IncomeVerificationService struct {
+	repo              LoanRepository
+	provider          payroll.Provider
+	varianceTolerance float64
+	logger            *zap.Logger
+}
+
+// NewIncomeVerificationService creates a new income verification service. A nil provider disables
+// payroll webhooks.
+func NewIncomeVerificationService(repo LoanRepository, provider payroll.Provider, varianceTolerance float64, logger *zap.Logger) *IncomeVerificationService {
+	return &IncomeVerificationService{
+		repo:              repo,
+		provider:          provider,
+		varianceTolerance: varianceTolerance,
+		logger:            logger,
+	}
+}
+
+// Get retrieves the income verification of an application, with the token the borrower connects
+// their payroll account with while it is pending
+func (s *IncomeVerificationService) Get(ctx context.Context, applicationID string) (*domain.IncomeVerification, error) {
+	verification, err := s.repo.GetIncomeVerification(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_069,
+				Message:     "Income verification not found",
+				Description: fmt.Sprintf("No income verification found for application: %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		s.logger.Error("Failed to get income verification",
+			zap.String("operation", "get_income_verification"),
+			zap.String("application_id", applicationID),
+			zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	if verification.Status != domain.IncomeVerificationPending {
+		verification.ConnectToken = ""
+	}
+	return verification, nil
+}
+
+// HandleWebhook processes a payroll aggregator notification, completing the pending verification of
+// a borrower whose pay data has synced
+func (s *IncomeVerificationService) HandleWebhook(ctx context.Context, body []byte, header func(string) string) error {
+	logger := s.logger.With(zap.String("operation", "handle_payroll_webhook"))
+
+	if s.provider == nil {
+		return s.unavailableError()
+	}
+
+	event, err := s.provider.ParseWebhook(body, header)
+	if err != nil {
+		logger.Warn("Rejected payroll webhook", zap.Error(err))
+		return &domain.LoanError{
+			Code:        domain.LOAN_071,
+			Message:     "Invalid payroll webhook",
+			Description: err.Error(),
+			HTTPStatus:  401,
+		}
+	}
+	if event == nil {
+		return nil
+	}
+
+	logger = logger.With(
+		zap.String("event", event.Name),
+		zap.String("provider_user_id", event.ProviderUserID),
+	)
+
+	verification, err := s.repo.GetIncomeVerificationByProviderUser(ctx, event.ProviderUserID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			// Not one of ours, or a borrower from another environment on the same account
+			logger.Warn("Webhook for unknown payroll user")
+			return nil
+		}
+		logger.Error("Failed to get income verification", zap.Error(err))
+		return s.databaseError(err)
+	}
+	if verification.Status != domain.IncomeVerificationPending {
+		return nil
+	}
+	logger = logger.With(zap.String("application_id", verification.ApplicationID))
+
+	statedIncome := verification.StatedAnnualIncome
+	if statedIncome == 0 {
+		application, err := s.repo.GetApplicationByID(ctx, verification.ApplicationID)
+		if err != nil {
+			logger.Error("Failed to get application", zap.Error(err))
+			return s.databaseError(err)
+		}
+		statedIncome = application.AnnualIncome
+	}
+
+	paystubs, err := s.provider.GetPaystubs(ctx, event.ProviderUserID, time.Now().Add(-payrollLookback))
+	if err != nil {
+		logger.Error("Failed to get paystubs", zap.Error(err))
+		return fmt.Errorf("failed to get paystubs: %w", err)
+	}
+	employments, err := s.provider.GetEmployments(ctx, event.ProviderUserID)
+	if err != nil {
+		logger.Error("Failed to get employments", zap.Error(err))
+		return fmt.Errorf("failed to get employments: %w", err)
+	}
+
+	income, err := payroll.ComputeIncome(paystubs, employments, statedIncome, s.varianceTolerance)
+	if errors.Is(err, payroll.ErrNoIncomeData) {
+		logger.Info("No payroll income data yet, verification still pending")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	completed, err := s.repo.CompleteIncomeVerification(ctx, verification.ID, income)
+	if err != nil {
+		return s.databaseError(err)
+	}
+	if !completed {
+		// Completed meanwhile by the underwriting worker or a redelivered webhook
+		return nil
+	}
+
+	logger.Info("Income verified from payroll data",
+		zap.Float64("verified_annual_income", income.VerifiedAnnualIncome),
+		zap.Float64("variance_percent", income.VariancePercent),
+		zap.Bool("variance_flagged", income.VarianceFlagged))
+	return nil
+}
+
+func (s *IncomeVerificationService) unavailableError() error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_070,
+		Message:     "Payroll income verification unavailable",
+		Description: "No payroll provider is configured",
+		HTTPStatus:  503,
+	}
+}
+
+// databaseError wraps a repository failure
+func (s *IncomeVerificationService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
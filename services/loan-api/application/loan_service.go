@@ -3,16 +3,20 @@ package application
 import (
 	"context"
 	"fmt"
+	"math"
 	"math/rand"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/tokenization"
 	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/workflow"
 	"github.com/huuhoait/los-demo/services/shared/pkg/i18n"
+	"github.com/huuhoait/los-demo/services/shared/pkg/payroll"
 )
 
 // UserRepository interface for user data persistence
@@ -24,47 +28,318 @@ type UserRepository interface {
 	DeleteUser(ctx context.Context, id string) error
 }
 
+// PIITokenizer exchanges raw identifiers for opaque vault tokens
+type PIITokenizer interface {
+	Tokenize(ctx context.Context, tokenType, value string) (token string, last4 string, err error)
+}
+
+// AddressVerifier standardizes applicant addresses and flags undeliverable ones
+type AddressVerifier interface {
+	StandardizeAddress(ctx context.Context, address *domain.Address) (*domain.Address, *domain.AddressVerification, error)
+}
+
+// IdentityVerificationChecker reports whether a borrower's identity verification has expired
+type IdentityVerificationChecker interface {
+	IsIdentityVerificationStale(ctx context.Context, userID string) (bool, error)
+}
+
+// OfferPricer prices loan offers from the configured rate matrices
+type OfferPricer interface {
+	// PricingInputs returns the inputs an application's offers are priced from, taken from its
+	// underwriting decision
+	PricingInputs(ctx context.Context, applicationID string) (*domain.PricingRequest, error)
+	Quote(ctx context.Context, req *domain.PricingRequest) (*domain.PricingAudit, error)
+}
+
+// Notifier delivers notifications to borrowers
+type Notifier interface {
+	SendNotification(ctx context.Context, userID, title, message string, data map[string]interface{}) error
+	// SendEmailNotification also emails the notification to the user's address
+	SendEmailNotification(ctx context.Context, userID, title, message string, data map[string]interface{}) error
+}
+
+// DocumentStore lists, stores and retrieves a borrower's documents
+type DocumentStore interface {
+	ListUserDocuments(ctx context.Context, userID string) ([]*domain.BorrowerDocument, error)
+	UploadUserDocument(ctx context.Context, userID string, upload *domain.DocumentUpload) (*domain.BorrowerDocument, error)
+	DownloadUserDocument(ctx context.Context, userID, documentID string) (*domain.DocumentContent, error)
+}
+
+// AgreementSender sends the loan agreement for a selected offer to the borrower for signature
+type AgreementSender interface {
+	SendAgreement(ctx context.Context, applicationID, offerID string) (*domain.SignatureEnvelope, error)
+}
+
+// FundingScheduler schedules the disbursement of a signed loan
+type FundingScheduler interface {
+	ScheduleDisbursement(ctx context.Context, applicationID string) (*domain.Disbursement, error)
+}
+
+// RepaymentScheduler generates the repayment schedule of a funded loan
+type RepaymentScheduler interface {
+	GenerateSchedule(ctx context.Context, applicationID string) (*domain.RepaymentSchedule, error)
+}
+
+// RateLocker locks the rates of offers borrowers view and accept, and requires repricing once a
+// lock has expired; see RateLockService
+type RateLocker interface {
+	LockViewedOffers(ctx context.Context, applicationID string, offers []*domain.LoanOffer) error
+	AttachRateLocks(ctx context.Context, applicationID string, offers []*domain.LoanOffer) error
+	CheckRateLock(ctx context.Context, offer *domain.LoanOffer) error
+	LockAcceptedOffer(ctx context.Context, offer *domain.LoanOffer) error
+}
+
+// CollateralPledger records and values the collateral securing an application; see CollateralService
+type CollateralPledger interface {
+	PledgeCollateral(ctx context.Context, application *domain.LoanApplication, req *domain.CollateralRequest) (*domain.Collateral, error)
+}
+
+// DuplicateDetector flags applications sharing identity signals with other borrowers' recent
+// applications; see DuplicateDetectionService
+type DuplicateDetector interface {
+	DetectDuplicates(ctx context.Context, application *domain.LoanApplication, user *domain.User, deviceID string) ([]*domain.DuplicateMatch, error)
+}
+
+// DisclosureIssuer generates a disclosure for an application and stores it with the borrower's documents
+type DisclosureIssuer interface {
+	GenerateDocument(ctx context.Context, applicationID, documentType, generatedBy string) (*domain.GeneratedDocument, error)
+}
+
 // LoanRepository interface for data persistence
 type LoanRepository interface {
 	CreateApplication(ctx context.Context, app *domain.LoanApplication) error
 	GetApplicationByID(ctx context.Context, id string) (*domain.LoanApplication, error)
 	GetApplicationsByUserID(ctx context.Context, userID string) ([]*domain.LoanApplication, error)
+	ListApplications(ctx context.Context, query *domain.ApplicationListQuery) ([]*domain.LoanApplication, error)
+	CountApplications(ctx context.Context, query *domain.ApplicationListQuery) (int, error)
 	UpdateApplication(ctx context.Context, app *domain.LoanApplication) error
 	DeleteApplication(ctx context.Context, id string) error
+	ReassignApplications(ctx context.Context, fromUserID, toUserID string) (int, error)
+	GetUserLoanActivity(ctx context.Context, userID string) (*domain.UserLoanActivity, error)
+	GetApplicationStats(ctx context.Context, since time.Time) (*domain.ApplicationStats, error)
 
 	CreateOffer(ctx context.Context, offer *domain.LoanOffer) error
 	GetOfferByApplicationID(ctx context.Context, applicationID string) (*domain.LoanOffer, error)
 	UpdateOffer(ctx context.Context, offer *domain.LoanOffer) error
+	CreateOfferGroup(ctx context.Context, group *domain.OfferGroup) error
+	ListOfferGroups(ctx context.Context, applicationID string) ([]*domain.OfferGroup, error)
+	SelectOffer(ctx context.Context, groupID, offerID string) error
+	GetOfferByID(ctx context.Context, id string) (*domain.LoanOffer, error)
+	ListCounterOffers(ctx context.Context, applicationID string) ([]*domain.LoanOffer, error)
+	AcceptCounterOffer(ctx context.Context, offer *domain.LoanOffer) error
+	CreateNegotiation(ctx context.Context, negotiation *domain.OfferNegotiation) error
+	ListNegotiations(ctx context.Context, applicationID string) ([]*domain.OfferNegotiation, error)
+	ExpireOffers(ctx context.Context, asOf time.Time) (int, error)
+	VoidOffers(ctx context.Context, applicationID string, since time.Time) ([]string, error)
+	CreateRateLock(ctx context.Context, lock *domain.RateLock) error
+	GetRateLock(ctx context.Context, offerID string) (*domain.RateLock, error)
+	ListRateLocks(ctx context.Context, applicationID string) ([]*domain.RateLock, error)
+	RepriceOffer(ctx context.Context, offer *domain.LoanOffer, lock *domain.RateLock) error
+	CreateNote(ctx context.Context, note *domain.ApplicationNote) error
+	ListNotes(ctx context.Context, applicationID string, borrowerVisibleOnly bool) ([]*domain.ApplicationNote, error)
+	ListOffers(ctx context.Context, applicationID string) ([]*domain.LoanOffer, error)
+	ListWorkflowExecutions(ctx context.Context, applicationID string) ([]*domain.WorkflowExecution, error)
+	WithdrawApplication(ctx context.Context, app *domain.LoanApplication, withdrawal *domain.ApplicationWithdrawal) error
+	TransitionApplicationState(ctx context.Context, app *domain.LoanApplication, transition *domain.StateTransition) error
+	ListApplicationsWithLapsedOffers(ctx context.Context, afterID string, limit int) ([]domain.LapsedOffers, error)
+
+	ListDocumentRules(ctx context.Context) ([]*domain.DocumentRule, error)
+	AddDocumentRequirements(ctx context.Context, requirements []*domain.DocumentRequirement) error
+	ListDocumentRequirements(ctx context.Context, applicationID string) ([]*domain.DocumentRequirement, error)
+	MarkDocumentRequirementReceived(ctx context.Context, requirementID, documentID string, receivedAt time.Time) error
+	CreateUnderwritingCondition(ctx context.Context, condition *domain.UnderwritingCondition) error
+
+	CreateSignatureEnvelope(ctx context.Context, envelope *domain.SignatureEnvelope) error
+	GetSignatureEnvelopeByProviderID(ctx context.Context, provider, providerEnvelopeID string) (*domain.SignatureEnvelope, error)
+	GetLatestSignatureEnvelope(ctx context.Context, applicationID string) (*domain.SignatureEnvelope, error)
+	UpdateSignatureEnvelope(ctx context.Context, envelope *domain.SignatureEnvelope) error
+
+	CreateGeneratedDocument(ctx context.Context, document *domain.GeneratedDocument) error
+	MarkGeneratedDocumentDelivered(ctx context.Context, id string, deliveredAt time.Time) error
+	GetGeneratedDocument(ctx context.Context, id string) (*domain.GeneratedDocument, error)
+	ListGeneratedDocuments(ctx context.Context, applicationID string) ([]*domain.GeneratedDocument, error)
+
+	CreateDisbursement(ctx context.Context, disbursement *domain.Disbursement) error
+	GetLatestDisbursement(ctx context.Context, applicationID string) (*domain.Disbursement, error)
+	GetDisbursementByProviderTransferID(ctx context.Context, provider, providerTransferID string) (*domain.Disbursement, error)
+	UpdateDisbursement(ctx context.Context, disbursement *domain.Disbursement) error
+	ClaimDueDisbursements(ctx context.Context, batchID string, asOf time.Time, limit int) ([]*domain.Disbursement, error)
+	ListBatchDisbursements(ctx context.Context, batchID string) ([]*domain.Disbursement, error)
+	CreateFundingBatch(ctx context.Context, batch *domain.FundingBatch) error
+	UpdateFundingBatch(ctx context.Context, batch *domain.FundingBatch) error
+	GetFundingBatch(ctx context.Context, id string) (*domain.FundingBatch, error)
+	CreateDisbursementEvent(ctx context.Context, event *domain.DisbursementEvent) error
+	ListDisbursementEvents(ctx context.Context, disbursementID string) ([]*domain.DisbursementEvent, error)
+
+	CreateBankAccount(ctx context.Context, account *domain.BankAccount) error
+	GetBankAccount(ctx context.Context, id string) (*domain.BankAccount, error)
+	GetActiveBankAccountByNumber(ctx context.Context, userID, accountNumberToken, routingNumberToken string) (*domain.BankAccount, error)
+	GetVerifiedBankAccount(ctx context.Context, userID string) (*domain.BankAccount, error)
+	ListBankAccounts(ctx context.Context, userID string) ([]*domain.BankAccount, error)
+	UpdateBankAccount(ctx context.Context, account *domain.BankAccount) error
+
+	CreateRepaymentSchedule(ctx context.Context, schedule *domain.RepaymentSchedule) error
+	GetRepaymentSchedule(ctx context.Context, applicationID string) (*domain.RepaymentSchedule, error)
 
 	CreateStateTransition(ctx context.Context, transition *domain.StateTransition) error
 	GetStateTransitions(ctx context.Context, applicationID string) ([]*domain.StateTransition, error)
 
 	SaveWorkflowExecution(ctx context.Context, execution *domain.WorkflowExecution) error
 	GetWorkflowExecutionByApplicationID(ctx context.Context, applicationID string) (*domain.WorkflowExecution, error)
+
+	CreatePreQualification(ctx context.Context, result *domain.PreQualifyResult) error
+	GetPreQualification(ctx context.Context, id string) (*domain.PreQualifyResult, error)
+	MarkPreQualificationConverted(ctx context.Context, id, applicationID string) error
+
+	CreateCollateral(ctx context.Context, collateral *domain.Collateral) error
+	GetCollateral(ctx context.Context, applicationID string) (*domain.Collateral, error)
+	UpdateCollateralValuation(ctx context.Context, collateral *domain.Collateral) error
+
+	CreateCreditConsent(ctx context.Context, consent *domain.CreditConsent) error
+	GetCreditConsent(ctx context.Context, applicationID string) (*domain.CreditConsent, error)
+
+	SaveApplicantDemographics(ctx context.Context, demographics *domain.ApplicantDemographics) error
+	GetApplicantDemographics(ctx context.Context, applicationID string) (*domain.ApplicantDemographics, error)
+
+	CreateApplicationFingerprint(ctx context.Context, fingerprint *domain.ApplicationFingerprint) error
+	FindDuplicateCandidates(ctx context.Context, fingerprint *domain.ApplicationFingerprint, since time.Time) ([]*domain.ApplicationFingerprint, error)
+	CreateDuplicateMatch(ctx context.Context, match *domain.DuplicateMatch) error
+	GetDuplicateMatch(ctx context.Context, id string) (*domain.DuplicateMatch, error)
+	ListDuplicateMatches(ctx context.Context, applicationID string) ([]*domain.DuplicateMatch, error)
+	UpdateDuplicateMatchReview(ctx context.Context, match *domain.DuplicateMatch) error
+
+	CreateWebhookSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error
+	GetWebhookSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	ListWebhookSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	UpdateWebhookSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error
+	CreateWebhookEvent(ctx context.Context, event *domain.WebhookEvent) error
+	GetWebhookEvent(ctx context.Context, id string) (*domain.WebhookEvent, error)
+	ListUndispatchedWebhookEvents(ctx context.Context, limit int) ([]*domain.WebhookEvent, error)
+	MarkWebhookEventDispatched(ctx context.Context, id string, dispatchedAt time.Time) error
+	CreateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+	GetWebhookDelivery(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+	ListDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error)
+	ListWebhookDeliveries(ctx context.Context, query *domain.WebhookDeliveryQuery) ([]*domain.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+
+	// SLA escalation
+	ListSLACandidates(ctx context.Context, rule *domain.SLARule, now time.Time, limit int) ([]*domain.SLACandidate, error)
+	CreateSLABreach(ctx context.Context, breach *domain.SLABreach) (bool, error)
+	UpdateSLABreachError(ctx context.Context, id, actionError string) error
+	BumpApplicationPriority(ctx context.Context, applicationID string) error
+	ListSLABreaches(ctx context.Context, query *domain.SLABreachQuery, now time.Time) ([]*domain.SLABreach, error)
+
+	// Back-office search
+	SearchApplications(ctx context.Context, query *domain.ApplicationSearchQuery) ([]*domain.ApplicationSearchResult, int, error)
+
+	// Workflow operator actions
+	GetApplicationIDByWorkflowID(ctx context.Context, workflowID string) (string, error)
+	RecordWorkflowAction(ctx context.Context, action *domain.WorkflowAction) error
+
+	// Loan officer assignment
+	ListUnassignedReviews(ctx context.Context, limit int) ([]*domain.AssignmentCandidate, error)
+	GetAssignmentCandidate(ctx context.Context, applicationID string) (*domain.AssignmentCandidate, error)
+	CountActiveAssignments(ctx context.Context) (map[string]int, error)
+	LatestAssignmentTimes(ctx context.Context) (map[string]time.Time, error)
+	CreateAssignment(ctx context.Context, assignment *domain.ApplicationAssignment, capacity int) (bool, error)
+	ReassignApplication(ctx context.Context, assignment *domain.ApplicationAssignment) error
+	ReleaseFinishedAssignments(ctx context.Context, now time.Time) (int, error)
+	GetActiveAssignment(ctx context.Context, applicationID string) (*domain.ApplicationAssignment, error)
+	ListAssignments(ctx context.Context, applicationID string) ([]*domain.ApplicationAssignment, error)
+	ListOfficerQueue(ctx context.Context, officerID string) ([]*domain.QueueItem, error)
+
+	// Dead-letter queue of workflow tasks
+	ListDeadLetters(ctx context.Context, query *domain.DeadLetterQuery) ([]*domain.DeadLetterTask, error)
+	GetDeadLetter(ctx context.Context, id string) (*domain.DeadLetterTask, error)
+	UpdateDeadLetterInput(ctx context.Context, task *domain.DeadLetterTask) (bool, error)
+	ResolveDeadLetter(ctx context.Context, task *domain.DeadLetterTask) (bool, error)
+	CountOpenDeadLetters(ctx context.Context) (int, error)
+
+	// Manual review queue of underwriters
+	ListManualReviews(ctx context.Context, query *domain.ManualReviewQuery) ([]*domain.ManualReview, error)
+	GetManualReview(ctx context.Context, id string) (*domain.ManualReview, error)
+	ClaimManualReview(ctx context.Context, review *domain.ManualReview) (bool, error)
+	CompleteManualReview(ctx context.Context, review *domain.ManualReview) (bool, error)
+	RecordManualReviewRedecision(ctx context.Context, applicationID, note string) (int, error)
+
+	// Income verifications recorded by the underwriting worker
+	GetIncomeVerification(ctx context.Context, applicationID string) (*domain.IncomeVerification, error)
+	GetIncomeVerificationByProviderUser(ctx context.Context, providerUserID string) (*domain.IncomeVerification, error)
+	CompleteIncomeVerification(ctx context.Context, id string, income *payroll.Income) (bool, error)
+
+	// Underwriting condition lifecycle
+	ListUnderwritingConditions(ctx context.Context, applicationID string) ([]*domain.UnderwritingCondition, error)
+	ListWorkflowConditions(ctx context.Context, workflowInstanceID string) ([]*domain.UnderwritingCondition, error)
+	GetUnderwritingCondition(ctx context.Context, applicationID, id string) (*domain.UnderwritingCondition, error)
+	RecordConditionEvidence(ctx context.Context, id, documentID string, receivedAt time.Time) (bool, error)
+	WaiveUnderwritingCondition(ctx context.Context, id, waivedBy, reason string, waivedAt time.Time) (bool, error)
+	ExpireUnderwritingConditions(ctx context.Context, asOf time.Time) (int, error)
+	ListConditionWorkflowsToResume(ctx context.Context, limit int) ([]string, error)
+	MarkConditionWorkflowResumed(ctx context.Context, workflowInstanceID string, resumedAt time.Time) error
+}
+
+// offerValidity is how long a generated offer can be accepted
+const offerValidity = 30 * 24 * time.Hour
+
+// applicationStatsTTL bounds how stale cached application statistics may be
+const applicationStatsTTL = 5 * time.Minute
+
+// cachedApplicationStats is a statistics result kept until expiresAt
+type cachedApplicationStats struct {
+	stats     *domain.ApplicationStats
+	expiresAt time.Time
 }
 
 // LoanService handles loan business logic
 type LoanService struct {
 	userRepo             UserRepository
 	repo                 LoanRepository
+	tokenizer            PIITokenizer
+	addressVerifier      AddressVerifier
+	identityChecker      IdentityVerificationChecker
+	pricer               OfferPricer
+	notifier             Notifier
+	documents            DocumentStore
+	agreements           AgreementSender
+	disclosures          DisclosureIssuer
+	rateLocks            RateLocker
+	collateral           CollateralPledger
+	duplicates           DuplicateDetector
+	webhooks             WebhookPublisher
 	workflowOrchestrator *workflow.LoanWorkflowOrchestrator
 	logger               *zap.Logger
 	localizer            *i18n.Localizer
+
+	statsMu    sync.Mutex
+	statsCache map[int]cachedApplicationStats // keyed by reporting window in days
 }
 
 // NewLoanService creates a new loan service
-func NewLoanService(userRepo UserRepository, repo LoanRepository, workflowOrchestrator *workflow.LoanWorkflowOrchestrator, logger *zap.Logger, localizer *i18n.Localizer) *LoanService {
+func NewLoanService(userRepo UserRepository, repo LoanRepository, tokenizer PIITokenizer, addressVerifier AddressVerifier, identityChecker IdentityVerificationChecker, pricer OfferPricer, notifier Notifier, documents DocumentStore, agreements AgreementSender, disclosures DisclosureIssuer, rateLocks RateLocker, collateral CollateralPledger, duplicates DuplicateDetector, webhooks WebhookPublisher, workflowOrchestrator *workflow.LoanWorkflowOrchestrator, logger *zap.Logger, localizer *i18n.Localizer) *LoanService {
 	return &LoanService{
 		userRepo:             userRepo,
 		repo:                 repo,
+		tokenizer:            tokenizer,
+		addressVerifier:      addressVerifier,
+		identityChecker:      identityChecker,
+		pricer:               pricer,
+		notifier:             notifier,
+		documents:            documents,
+		agreements:           agreements,
+		disclosures:          disclosures,
+		rateLocks:            rateLocks,
+		collateral:           collateral,
+		duplicates:           duplicates,
+		webhooks:             webhooks,
 		workflowOrchestrator: workflowOrchestrator,
 		logger:               logger,
 		localizer:            localizer,
+		statsCache:           make(map[int]cachedApplicationStats),
 	}
 }
 
 // generateApplicationNumber generates a unique application number
-func (s *LoanService) generateApplicationNumber() string {
+func generateApplicationNumber() string {
 	// Generate application number with format: LOAN-YYYYMMDD-HHMMSS-XXXX
 	// Where XXXX is a random 4-digit number for uniqueness
 	now := time.Now().UTC()
@@ -105,14 +380,71 @@ func (s *LoanService) CreateApplication(ctx context.Context, req *domain.CreateA
 		}
 	}
 
+	applicationType := domain.ApplicationTypeNew
+	if req.RefinanceApplicationID != nil {
+		if err := s.checkRefinance(ctx, logger, req, existingUser); err != nil {
+			return nil, err
+		}
+		applicationType = domain.ApplicationTypeRefinance
+	}
+
 	var userID string
+	var addressVerification *domain.AddressVerification
+	applicant := existingUser
 	if existingUser != nil {
 		// User exists, use existing user ID
 		userID = existingUser.ID
 		logger.Info("Using existing user", zap.String("user_id", userID))
+
+		// A borrower whose identity verification has expired must re-verify before applying again.
+		// If the user service cannot say, the application is refused rather than let through unchecked.
+		stale, err := s.identityChecker.IsIdentityVerificationStale(ctx, userID)
+		if err != nil {
+			logger.Error("Identity verification check failed", zap.Error(err))
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_083,
+				Message:     "Identity verification unavailable",
+				Description: "Identity verification status could not be checked; try again later",
+				HTTPStatus:  503,
+			}
+		}
+		if stale {
+			logger.Info("Rejecting application with stale identity verification", zap.String("user_id", userID))
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_031,
+				Message:     "Identity verification expired",
+				Description: "Identity verification must be renewed before submitting a new application",
+				HTTPStatus:  403,
+			}
+		}
 	} else {
 		// Create new user
 		user := req.User
+
+		// Swap the raw SSN for a vault token so it is never persisted in this service
+		ssnToken, ssnLast4, err := s.tokenizer.Tokenize(ctx, tokenization.TokenTypeSSN, user.SSN)
+		if err != nil {
+			logger.Error("Failed to tokenize SSN", zap.Error(err))
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_024,
+				Message:     "Failed to secure applicant SSN",
+				Description: err.Error(),
+				HTTPStatus:  502,
+			}
+		}
+		user.SSN = ""
+		user.SSNToken = ssnToken
+		user.SSNLast4 = ssnLast4
+
+		// Standardize to CASS format; a provider outage should not block the application
+		standardized, verification, err := s.addressVerifier.StandardizeAddress(ctx, &user.Address)
+		if err != nil {
+			logger.Warn("Address verification failed, storing address as submitted", zap.Error(err))
+		} else {
+			user.Address = *standardized
+			addressVerification = verification
+		}
+
 		user.ID = uuid.New().String()
 		user.CreatedAt = time.Now().UTC()
 		user.UpdatedAt = time.Now().UTC()
@@ -128,23 +460,29 @@ func (s *LoanService) CreateApplication(ctx context.Context, req *domain.CreateA
 			}
 		}
 		logger.Info("User created successfully", zap.String("user_id", userID))
+		applicant = &user
 	}
 
 	// Create loan application
 	application := &domain.LoanApplication{
-		ID:                uuid.New().String(),
-		UserID:            userID,
-		ApplicationNumber: s.generateApplicationNumber(),
-		LoanAmount:        req.LoanAmount,
-		LoanPurpose:       req.LoanPurpose,
-		AnnualIncome:      req.AnnualIncome,
-		MonthlyIncome:     req.MonthlyIncome,
-		MonthlyDebt:       req.MonthlyDebt,
-		RequestedTerm:     req.RequestedTerm,
-		EmploymentStatus:  req.EmploymentStatus,
-		CurrentState:      domain.StateInitiated,
-		CreatedAt:         time.Now().UTC(),
-		UpdatedAt:         time.Now().UTC(),
+		ID:                      uuid.New().String(),
+		UserID:                  userID,
+		ApplicationNumber:       generateApplicationNumber(),
+		LoanAmount:              req.LoanAmount,
+		LoanPurpose:             req.LoanPurpose,
+		AnnualIncome:            req.AnnualIncome,
+		MonthlyIncome:           req.MonthlyIncome,
+		MonthlyDebt:             req.MonthlyDebt,
+		RequestedTerm:           req.RequestedTerm,
+		EmploymentStatus:        req.EmploymentStatus,
+		CoBorrower:              req.CoBorrower,
+		IncomeSources:           req.IncomeSources,
+		ApplicationType:         applicationType,
+		RefinancedApplicationID: req.RefinanceApplicationID,
+		CurrentState:            domain.StateInitiated,
+		CreatedAt:               time.Now().UTC(),
+		UpdatedAt:               time.Now().UTC(),
+		AddressVerification:     addressVerification,
 	}
 
 	// Save application to database
@@ -176,6 +514,32 @@ func (s *LoanService) CreateApplication(ctx context.Context, req *domain.CreateA
 		// Don't fail the entire operation for this
 	}
 
+	// Evaluate the document checklist so the workflow collects the documents it requires
+	requirements, err := s.evaluateDocumentChecklist(ctx, application, nil)
+	if err != nil {
+		logger.Warn("Failed to evaluate document checklist, default documents will be requested", zap.Error(err))
+	} else {
+		application.RequiredDocuments = domain.NewDocumentChecklist(requirements)
+	}
+
+	// Record the collateral securing the application so underwriting sees its loan-to-value ratio
+	if req.Collateral != nil && s.collateral != nil {
+		collateral, err := s.collateral.PledgeCollateral(ctx, application, req.Collateral)
+		if err != nil {
+			logger.Warn("Failed to record collateral", zap.Error(err))
+		} else {
+			application.Collateral = collateral
+		}
+	}
+
+	// Compare the applicant's SSN, email and device with other borrowers' recent applications; matches
+	// hold the application back from automated approval until they are reviewed
+	if s.duplicates != nil {
+		if _, err := s.duplicates.DetectDuplicates(ctx, application, applicant, req.DeviceID); err != nil {
+			logger.Warn("Failed to check for duplicate applications", zap.Error(err))
+		}
+	}
+
 	// Start initial workflow for the application
 	if s.workflowOrchestrator != nil {
 		logger.Info("Starting initial workflow for application",
@@ -251,16 +615,56 @@ func (s *LoanService) GetApplication(ctx context.Context, id string) (*domain.Lo
 	return application, nil
 }
 
-// GetApplicationsByUser retrieves all applications for a user
-func (s *LoanService) GetApplicationsByUser(ctx context.Context, userID string) ([]*domain.LoanApplication, error) {
+// GetApplicationOwner returns the owning user ID of an application, or "" when it does not exist
+func (s *LoanService) GetApplicationOwner(ctx context.Context, id string) (string, error) {
+	application, err := s.repo.GetApplicationByID(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return application.UserID, nil
+}
+
+// ListApplications returns one page of applications matching the query. Callers scope the query
+// to a single borrower by setting UserID; admin listings leave it empty.
+func (s *LoanService) ListApplications(ctx context.Context, query *domain.ApplicationListQuery) (*domain.ApplicationPage, error) {
+	query.ApplyDefaults()
+
 	logger := s.logger.With(
-		zap.String("user_id", userID),
-		zap.String("operation", "get_applications_by_user"),
+		zap.String("operation", "list_applications"),
+		zap.String("user_id", query.UserID),
+		zap.Int("page", query.Page),
+		zap.Int("page_size", query.PageSize),
 	)
 
-	applications, err := s.repo.GetApplicationsByUserID(ctx, userID)
+	validation := query.Validate()
+	if !validation.Valid {
+		logger.Warn("Invalid application list query", zap.Any("errors", validation.Errors))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: fmt.Sprintf("Validation errors: %v", validation.Errors),
+			HTTPStatus:  400,
+		}
+	}
+
+	applications, err := s.repo.ListApplications(ctx, query)
+	if err != nil {
+		logger.Error("Failed to list applications", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	total, err := s.repo.CountApplications(ctx, query)
 	if err != nil {
-		logger.Error("Failed to get applications", zap.Error(err))
+		logger.Error("Failed to count applications", zap.Error(err))
 		return nil, &domain.LoanError{
 			Code:        domain.LOAN_023,
 			Message:     "Database error",
@@ -269,7 +673,13 @@ func (s *LoanService) GetApplicationsByUser(ctx context.Context, userID string)
 		}
 	}
 
-	return applications, nil
+	return &domain.ApplicationPage{
+		Applications: applications,
+		Page:         query.Page,
+		PageSize:     query.PageSize,
+		Total:        total,
+		TotalPages:   (total + query.PageSize - 1) / query.PageSize,
+	}, nil
 }
 
 // UpdateApplication updates an existing loan application
@@ -300,6 +710,8 @@ func (s *LoanService) UpdateApplication(ctx context.Context, id string, req *dom
 		}
 	}
 
+	before := *application
+
 	// Update fields if provided
 	if req.LoanAmount != nil && *req.LoanAmount > 0 {
 		application.LoanAmount = *req.LoanAmount
@@ -322,6 +734,16 @@ func (s *LoanService) UpdateApplication(ctx context.Context, id string, req *dom
 
 	application.UpdatedAt = time.Now().UTC()
 
+	// A material change to an approved application makes its decision stale, so it is
+	// underwritten again before it can be funded
+	if changes := domain.MaterialChanges(&before, application); len(changes) > 0 && application.IsAwaitingFunding() {
+		if _, err := s.reunderwrite(ctx, application, changes, "application_updated"); err != nil {
+			return nil, err
+		}
+		logger.Info("Application updated and sent back to underwriting")
+		return application, nil
+	}
+
 	// Save updated application
 	if err := s.repo.UpdateApplication(ctx, application); err != nil {
 		logger.Error("Failed to update application", zap.Error(err))
@@ -377,6 +799,23 @@ func (s *LoanService) SubmitApplication(ctx context.Context, id string) (*domain
 		}
 	}
 
+	// Underwriting pulls credit once the application is submitted, which needs the borrower's consent
+	consent, err := s.repo.GetCreditConsent(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			logger.Warn("Application submitted without credit pull consent")
+			return nil, creditConsentRequiredError(id)
+		}
+		logger.Error("Failed to get credit consent", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+	logger = logger.With(zap.String("credit_consent_id", consent.ID))
+
 	// Update application state
 	application.CurrentState = domain.StatePreQualified
 	application.UpdatedAt = time.Now().UTC()
@@ -421,6 +860,12 @@ func (s *LoanService) SubmitApplication(ctx context.Context, id string) (*domain
 			AnnualIncome:     application.AnnualIncome,
 			MonthlyDebt:      application.MonthlyDebt,
 			EmploymentStatus: application.EmploymentStatus,
+			IncomeSources:    application.IncomeSources,
+		}
+		if application.CoBorrower != nil {
+			preQualifyReq.CoBorrowerAnnualIncome = application.CoBorrower.AnnualIncome
+			preQualifyReq.CoBorrowerMonthlyDebt = application.CoBorrower.MonthlyDebt
+			preQualifyReq.CoBorrowerEmploymentStatus = application.CoBorrower.EmploymentStatus
 		}
 
 		workflowExecution, err := s.workflowOrchestrator.StartPreQualificationWorkflow(ctx, application.UserID, preQualifyReq)
@@ -448,22 +893,1189 @@ func (s *LoanService) SubmitApplication(ctx context.Context, id string) (*domain
 	return application, nil
 }
 
-// GetApplicationStats retrieves application statistics
-func (s *LoanService) GetApplicationStats(ctx context.Context) (map[string]interface{}, error) {
+// WithdrawApplication cancels an application on the borrower's behalf before it is funded. Open
+// offers expire, the running workflow is terminated and the borrower is notified; a workflow or
+// notification failure does not undo the withdrawal.
+func (s *LoanService) WithdrawApplication(ctx context.Context, id, userID string, req *domain.WithdrawApplicationRequest) (*domain.ApplicationWithdrawal, error) {
+	logger := s.logger.With(
+		zap.String("application_id", id),
+		zap.String("operation", "withdraw_application"),
+	)
+
+	if validation := req.Validate(); !validation.Valid {
+		logger.Warn("Invalid withdrawal request", zap.Any("errors", validation.Errors))
+		description := "A comment is required when the withdrawal reason is other"
+		if _, ok := validation.Errors["reason"]; ok {
+			description = fmt.Sprintf("Withdrawal reason %s is reserved for automated withdrawals", req.Reason)
+		}
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: description,
+			HTTPStatus:  400,
+		}
+	}
+
+	application, err := s.repo.GetApplicationByID(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			logger.Warn("Application not found")
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Application not found",
+				Description: fmt.Sprintf("No application found with ID: %s", id),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get application", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	// Automated withdrawals are made by the system actor, which owns no application
+	if userID != "" && !req.Automated && application.UserID != userID {
+		logger.Warn("User does not own application", zap.String("user_id", userID))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_022,
+			Message:     "Unauthorized access",
+			Description: "Applications can only be withdrawn by their owner",
+			HTTPStatus:  403,
+		}
+	}
+
+	if !application.CanWithdraw() {
+		logger.Warn("Application cannot be withdrawn from current state",
+			zap.String("current_state", string(application.CurrentState)))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_036,
+			Message:     "Application cannot be withdrawn",
+			Description: fmt.Sprintf("Application is in %s state and cannot be withdrawn", application.CurrentState),
+			HTTPStatus:  409,
+		}
+	}
+
+	now := time.Now().UTC()
+	withdrawal := &domain.ApplicationWithdrawal{
+		ID:            uuid.New().String(),
+		ApplicationID: application.ID,
+		FromState:     application.CurrentState,
+		Reason:        req.Reason,
+		Comment:       req.Comment,
+		CreatedAt:     now,
+	}
+	if userID != "" && !req.Automated {
+		withdrawal.WithdrawnBy = &userID
+	}
+
+	application.CurrentState = domain.StateWithdrawn
+	application.Status = domain.StatusWithdrawn
+	application.UpdatedAt = now
+
+	if err := s.repo.WithdrawApplication(ctx, application, withdrawal); err != nil {
+		if strings.Contains(err.Error(), "state changed") {
+			logger.Warn("Application state changed during withdrawal")
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_013,
+				Message:     "State conflict",
+				Description: "The application changed state while it was being withdrawn; please retry",
+				HTTPStatus:  409,
+			}
+		}
+		logger.Error("Failed to withdraw application", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Failed to withdraw application",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	// The state transition is the application's audit trail
+	transition := &domain.StateTransition{
+		ID:               uuid.New().String(),
+		ApplicationID:    application.ID,
+		FromState:        &withdrawal.FromState,
+		ToState:          domain.StateWithdrawn,
+		TransitionReason: "Application withdrawn by borrower",
+		Automated:        req.Automated,
+		UserID:           withdrawal.WithdrawnBy,
+		Metadata: map[string]interface{}{
+			"source":        "api",
+			"event":         "application_withdrawn",
+			"withdrawal_id": withdrawal.ID,
+			"reason":        string(withdrawal.Reason),
+			"comment":       withdrawal.Comment,
+		},
+		CreatedAt: now,
+	}
+	if req.Automated {
+		actor := userID
+		if actor == "" {
+			actor = domain.WithdrawalActorSystem
+		}
+		transition.TransitionReason = fmt.Sprintf("Application withdrawn automatically: %s", withdrawal.Comment)
+		transition.UserID = &actor
+		if req.Source != "" {
+			transition.Metadata["source"] = req.Source
+		}
+	}
+	if err := s.repo.CreateStateTransition(ctx, transition); err != nil {
+		logger.Warn("Failed to create state transition", zap.Error(err))
+	}
+
+	withdrawal.WorkflowTerminated = s.terminateApplicationWorkflow(ctx, logger, application)
+
+	if s.notifier != nil {
+		if err := s.notifier.SendNotification(ctx, application.UserID,
+			"Your loan application was withdrawn",
+			fmt.Sprintf("Loan application %s has been withdrawn. You can start a new application at any time.", application.ApplicationNumber),
+			map[string]interface{}{
+				"action":         "loan_withdrawn",
+				"application_id": application.ID,
+			},
+		); err != nil {
+			logger.Warn("Failed to send withdrawal notification", zap.Error(err))
+		}
+	}
+
+	logger.Info("Application withdrawn successfully",
+		zap.String("from_state", string(withdrawal.FromState)),
+		zap.String("reason", string(withdrawal.Reason)),
+		zap.Bool("workflow_terminated", withdrawal.WorkflowTerminated),
+	)
+
+	return withdrawal, nil
+}
+
+// terminateApplicationWorkflow stops the application's running workflow, if it has one, and reports
+// whether it was terminated
+func (s *LoanService) terminateApplicationWorkflow(ctx context.Context, logger *zap.Logger, application *domain.LoanApplication) bool {
+	if s.workflowOrchestrator == nil {
+		return false
+	}
+
+	workflowID := ""
+	if application.WorkflowID != nil {
+		workflowID = *application.WorkflowID
+	} else if execution, err := s.repo.GetWorkflowExecutionByApplicationID(ctx, application.ID); err == nil {
+		workflowID = execution.WorkflowID
+	}
+	if workflowID == "" {
+		return false
+	}
+
+	if err := s.workflowOrchestrator.TerminateWorkflow(ctx, workflowID, "Application withdrawn by borrower"); err != nil {
+		logger.Warn("Failed to terminate workflow",
+			zap.String("workflow_id", workflowID),
+			zap.Error(err))
+		return false
+	}
+
+	return true
+}
+
+// ReassignApplications moves applications from a merged duplicate user to the surviving user
+func (s *LoanService) ReassignApplications(ctx context.Context, fromUserID, toUserID string) (int, error) {
+	logger := s.logger.With(
+		zap.String("from_user_id", fromUserID),
+		zap.String("to_user_id", toUserID),
+		zap.String("operation", "reassign_applications"),
+	)
+
+	if fromUserID == "" || toUserID == "" || fromUserID == toUserID {
+		return 0, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: "from_user_id and to_user_id are required and must differ",
+			HTTPStatus:  400,
+		}
+	}
+
+	count, err := s.repo.ReassignApplications(ctx, fromUserID, toUserID)
+	if err != nil {
+		logger.Error("Failed to reassign applications", zap.Error(err))
+		return 0, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	logger.Info("Applications reassigned", zap.Int("count", count))
+	return count, nil
+}
+
+// GetUserLoanActivity reports a user's open applications and most recent loan closure
+func (s *LoanService) GetUserLoanActivity(ctx context.Context, userID string) (*domain.UserLoanActivity, error) {
+	activity, err := s.repo.GetUserLoanActivity(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to get user loan activity", zap.String("user_id", userID), zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	return activity, nil
+}
+
+// GetApplicationStats aggregates applications created in the last given number of days.
+// Results are cached briefly since the aggregation scans the whole window.
+func (s *LoanService) GetApplicationStats(ctx context.Context, days int) (*domain.ApplicationStats, error) {
 	logger := s.logger.With(
 		zap.String("operation", "get_application_stats"),
+		zap.Int("days", days),
 	)
 
-	// For now, return basic stats
-	// In a real implementation, you would query the database for actual statistics
-	stats := map[string]interface{}{
-		"total_applications": 0,
-		"pending_review":     0,
-		"approved":           0,
-		"denied":             0,
-		"in_progress":        0,
+	if days <= 0 || days > domain.MaxStatsDays {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: fmt.Sprintf("days must be between 1 and %d", domain.MaxStatsDays),
+			HTTPStatus:  400,
+		}
+	}
+
+	s.statsMu.Lock()
+	cached, ok := s.statsCache[days]
+	s.statsMu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.stats, nil
 	}
 
-	logger.Info("Application stats retrieved")
-	return stats, nil
+	now := time.Now().UTC()
+	stats, err := s.repo.GetApplicationStats(ctx, now.AddDate(0, 0, -days))
+	if err != nil {
+		logger.Error("Failed to get application stats", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	stats.Days = days
+	stats.GeneratedAt = now
+	if decided := stats.ApprovedCount + stats.DeniedCount; decided > 0 {
+		stats.ApprovalRate = float64(stats.ApprovedCount) / float64(decided)
+	}
+	for i := range stats.Funnel {
+		if i == 0 {
+			if stats.Funnel[i].Count > 0 {
+				stats.Funnel[i].ConversionRate = 1
+			}
+			continue
+		}
+		if previous := stats.Funnel[i-1].Count; previous > 0 {
+			stats.Funnel[i].ConversionRate = float64(stats.Funnel[i].Count) / float64(previous)
+		}
+	}
+
+	s.statsMu.Lock()
+	s.statsCache[days] = cachedApplicationStats{stats: stats, expiresAt: now.Add(applicationStatsTTL)}
+	s.statsMu.Unlock()
+
+	logger.Info("Application stats computed", zap.Int("total_applications", stats.TotalApplications))
+	return stats, nil
+}
+
+// GenerateOffer prices a group of alternative offers for an approved application from the credit
+// score and risk level of its underwriting decision, using the decision engine's pricing saved
+// with the decision, else the rate matrices, across the standard terms and amount
+// options, and stores them together with their pricing audit records. Any earlier open offer group for the application is expired. Refinance
+// applications are only offered amounts that more than pay off the refinanced loan, and each offer
+// is compared with keeping that loan.
+func (s *LoanService) GenerateOffer(ctx context.Context, applicationID string) (*domain.OfferGroup, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "generate_offer"),
+	)
+
+	application, err := s.GetApplication(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if application.CurrentState != domain.StateApproved {
+		logger.Warn("Offer requested for application that is not approved",
+			zap.String("current_state", string(application.CurrentState)))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_019,
+			Message:     "Invalid application status",
+			Description: fmt.Sprintf("Offers can only be generated for approved applications, current state: %s", application.CurrentState),
+			HTTPStatus:  409,
+		}
+	}
+
+	inputs, err := s.pricer.PricingInputs(ctx, application.ID)
+	if err != nil {
+		logger.Warn("Offer pricing inputs unavailable", zap.Error(err))
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	amounts := offerAmounts(application.LoanAmount)
+	var payoff *domain.PayoffQuote
+	if application.IsRefinance() {
+		if payoff, err = s.quoteRefinancedLoan(ctx, application, now); err != nil {
+			logger.Warn("Payoff of refinanced loan cannot be quoted", zap.Error(err))
+			return nil, err
+		}
+		if amounts = refinanceAmounts(amounts, payoff); len(amounts) == 0 {
+			return nil, cannotRefinanceError(fmt.Sprintf("The loan amount of $%.2f no longer covers the $%.2f payoff of the refinanced loan", application.LoanAmount, payoff.PayoffAmount))
+		}
+	}
+
+	group := &domain.OfferGroup{
+		ID:            uuid.New().String(),
+		ApplicationID: application.ID,
+		Status:        domain.OfferGroupOpen,
+		ExpiresAt:     now.Add(offerValidity),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	for _, amount := range amounts {
+		for _, termMonths := range domain.OfferTerms {
+			audit, err := s.pricer.Quote(ctx, &domain.PricingRequest{
+				ApplicationID:   application.ID,
+				Product:         inputs.Product,
+				CreditScore:     inputs.CreditScore,
+				RiskLevel:       inputs.RiskLevel,
+				TermMonths:      termMonths,
+				LoanAmount:      amount,
+				DecisionPricing: inputs.DecisionPricing,
+				PricedAt:        now,
+			})
+			if err != nil {
+				// A matrix without a rate for this term just means one fewer option
+				if loanErr, ok := err.(*domain.LoanError); ok && loanErr.Code == domain.LOAN_034 {
+					continue
+				}
+				return nil, err
+			}
+
+			offer := &domain.LoanOffer{
+				ID:             uuid.New().String(),
+				ApplicationID:  application.ID,
+				OfferAmount:    amount,
+				InterestRate:   audit.InterestRate,
+				TermMonths:     termMonths,
+				MonthlyPayment: audit.MonthlyPayment,
+				TotalInterest:  audit.TotalInterest,
+				APR:            audit.APR,
+				ExpiresAt:      group.ExpiresAt,
+				Status:         domain.OfferStatusPending,
+				GroupID:        &group.ID,
+				CreatedAt:      now,
+				PricingAudit:   audit,
+			}
+			audit.OfferID = offer.ID
+			if payoff != nil {
+				offer.RefinanceComparison = domain.NewRefinanceComparison(payoff, offer)
+			}
+			group.Offers = append(group.Offers, offer)
+		}
+	}
+
+	if len(group.Offers) == 0 {
+		logger.Warn("No offer could be priced for the application")
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_034,
+			Message:     "No pricing rate available",
+			Description: fmt.Sprintf("No %s rate for credit score %d and risk %s at any offered term", inputs.Product, inputs.CreditScore, inputs.RiskLevel),
+			HTTPStatus:  422,
+		}
+	}
+
+	if err := s.repo.CreateOfferGroup(ctx, group); err != nil {
+		logger.Error("Failed to create offer group", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	logger.Info("Offers generated",
+		zap.String("group_id", group.ID),
+		zap.Int("offer_count", len(group.Offers)),
+	)
+	s.publishEvent(ctx, domain.WebhookOfferGenerated, application.ID, group)
+	return group, nil
+}
+
+// offerAmounts returns the distinct amounts offered for a requested amount, rounded down to the
+// nearest hundred and never below the minimum loan amount
+func offerAmounts(requested float64) []float64 {
+	var amounts []float64
+	seen := make(map[float64]bool)
+	for _, ratio := range domain.OfferAmountRatios {
+		amount := math.Floor(requested*ratio/100) * 100
+		if amount < 5000 || seen[amount] {
+			continue
+		}
+		seen[amount] = true
+		amounts = append(amounts, amount)
+	}
+	return amounts
+}
+
+// ListOfferGroups returns an application's offer groups, newest first. Pending offers shown to the
+// borrower have their rates locked.
+func (s *LoanService) ListOfferGroups(ctx context.Context, applicationID string) ([]*domain.OfferGroup, error) {
+	groups, err := s.listOfferGroups(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var offers []*domain.LoanOffer
+	for _, group := range groups {
+		offers = append(offers, group.Offers...)
+	}
+	s.lockViewedOffers(ctx, applicationID, offers)
+	return groups, nil
+}
+
+// listOfferGroups returns an application's offer groups, newest first
+func (s *LoanService) listOfferGroups(ctx context.Context, applicationID string) ([]*domain.OfferGroup, error) {
+	if _, err := s.GetApplication(ctx, applicationID); err != nil {
+		return nil, err
+	}
+
+	groups, err := s.repo.ListOfferGroups(ctx, applicationID)
+	if err != nil {
+		s.logger.Error("Failed to list offer groups", zap.Error(err), zap.String("application_id", applicationID))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	return groups, nil
+}
+
+// CompareOffers compares the offers of a group side by side, locking the rates of its pending
+// offers. Without a group ID the latest group is compared.
+func (s *LoanService) CompareOffers(ctx context.Context, applicationID, groupID string) (*domain.OfferComparison, error) {
+	group, err := s.findOfferGroup(ctx, applicationID, func(g *domain.OfferGroup) bool {
+		return groupID == "" || g.ID == groupID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.lockViewedOffers(ctx, applicationID, group.Offers)
+	return group.Compare(), nil
+}
+
+// SelectOffer selects one offer of an open group. The group's other offers expire. Counter offers
+// are selected the same way; see acceptCounterOffer. An offer whose rate is no longer locked must be
+// repriced first; the selected offer's rate is locked again through signing and funding. The
+// borrower then receives the selected offer's TILA disclosure and its agreement for signature.
+func (s *LoanService) SelectOffer(ctx context.Context, applicationID, offerID string) (*domain.OfferGroup, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("offer_id", offerID),
+		zap.String("operation", "select_offer"),
+	)
+
+	group, err := s.findOfferGroup(ctx, applicationID, func(g *domain.OfferGroup) bool {
+		return g.FindOffer(offerID) != nil
+	})
+	if err != nil {
+		if loanErr, ok := err.(*domain.LoanError); ok && loanErr.Code == domain.LOAN_010 {
+			group, err := s.acceptCounterOffer(ctx, applicationID, offerID)
+			if err == nil {
+				s.sendClosingDocuments(ctx, logger, applicationID, offerID)
+			}
+			return group, err
+		}
+		return nil, err
+	}
+
+	if group.Status != domain.OfferGroupOpen || time.Now().UTC().After(group.ExpiresAt) {
+		logger.Warn("Offer selected from a group that is no longer open",
+			zap.String("group_id", group.ID),
+			zap.String("group_status", string(group.Status)))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_009,
+			Message:     "Offer expired",
+			Description: fmt.Sprintf("Offer group %s is %s and its offers can no longer be selected", group.ID, group.Status),
+			HTTPStatus:  410,
+		}
+	}
+
+	selected := group.FindOffer(offerID)
+	if selected.Status != domain.OfferStatusPending {
+		logger.Warn("Offer selected after it was declined or countered", zap.String("offer_status", selected.Status))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_035,
+			Message:     "Offer not open for negotiation",
+			Description: fmt.Sprintf("Offer %s is %s", selected.ID, selected.Status),
+			HTTPStatus:  409,
+		}
+	}
+	if s.rateLocks != nil {
+		if err := s.rateLocks.CheckRateLock(ctx, selected); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.SelectOffer(ctx, group.ID, offerID); err != nil {
+		if strings.Contains(err.Error(), "not open") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_009,
+				Message:     "Offer expired",
+				Description: err.Error(),
+				HTTPStatus:  410,
+			}
+		}
+		logger.Error("Failed to select offer", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	now := time.Now().UTC()
+	group.Status = domain.OfferGroupSelected
+	group.SelectedOfferID = &offerID
+	group.UpdatedAt = now
+	for _, offer := range group.Offers {
+		if offer.ID == offerID {
+			offer.Status = domain.OfferStatusSelected
+		} else {
+			offer.Status = domain.OfferStatusExpired
+		}
+	}
+
+	logger.Info("Offer selected", zap.String("group_id", group.ID))
+	s.lockAcceptedOffer(ctx, logger, selected, group.Offers)
+	s.publishEvent(ctx, domain.WebhookOfferAccepted, applicationID, selected)
+	s.sendClosingDocuments(ctx, logger, applicationID, offerID)
+	return group, nil
+}
+
+// sendClosingDocuments issues the TILA disclosure for a selected offer and sends its agreement for
+// signature. The selection stands if either fails; both can be produced again from their endpoints.
+func (s *LoanService) sendClosingDocuments(ctx context.Context, logger *zap.Logger, applicationID, offerID string) {
+	if s.disclosures != nil {
+		if _, err := s.disclosures.GenerateDocument(ctx, applicationID, domain.DocumentTILADisclosure, ""); err != nil {
+			logger.Warn("Failed to issue TILA disclosure", zap.Error(err))
+		}
+	}
+	if s.agreements == nil {
+		return
+	}
+	if _, err := s.agreements.SendAgreement(ctx, applicationID, offerID); err != nil {
+		logger.Warn("Failed to send agreement for signature", zap.Error(err))
+	}
+}
+
+// acceptCounterOffer selects a pending counter offer. If the offer group it answers is still open,
+// the group closes with the counter offer as its selection. The result is the parent group, or a
+// single-offer group when the parent offer was not part of one.
+func (s *LoanService) acceptCounterOffer(ctx context.Context, applicationID, offerID string) (*domain.OfferGroup, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("offer_id", offerID),
+		zap.String("operation", "accept_counter_offer"),
+	)
+
+	offer, err := s.getNegotiableOffer(ctx, applicationID, offerID)
+	if err != nil {
+		return nil, err
+	}
+	if offer.ParentOfferID == nil {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_010,
+			Message:     "Offer not found",
+			Description: fmt.Sprintf("No matching offer found for application: %s", applicationID),
+			HTTPStatus:  404,
+		}
+	}
+	if s.rateLocks != nil {
+		if err := s.rateLocks.CheckRateLock(ctx, offer); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.AcceptCounterOffer(ctx, offer); err != nil {
+		if strings.Contains(err.Error(), "not open") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_009,
+				Message:     "Offer expired",
+				Description: err.Error(),
+				HTTPStatus:  410,
+			}
+		}
+		logger.Error("Failed to accept counter offer", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	now := time.Now().UTC()
+	s.recordNegotiation(ctx, &domain.OfferNegotiation{
+		ID:             uuid.New().String(),
+		ApplicationID:  applicationID,
+		OfferID:        *offer.ParentOfferID,
+		Action:         domain.NegotiationCounterAccepted,
+		Actor:          domain.NegotiationActorBorrower,
+		CounterOfferID: &offer.ID,
+		CreatedAt:      now,
+	})
+
+	offer.Status = domain.OfferStatusSelected
+	logger.Info("Counter offer accepted", zap.String("parent_offer_id", *offer.ParentOfferID))
+	s.publishEvent(ctx, domain.WebhookOfferAccepted, applicationID, offer)
+	s.signalCounterOfferResponse(ctx, logger, offer, map[string]interface{}{
+		"response":           domain.CounterOfferAccepted,
+		"acceptedAmount":     offer.OfferAmount,
+		"acceptedTermMonths": offer.TermMonths,
+		"acceptedRate":       offer.InterestRate,
+		"acceptedAPR":        offer.APR,
+	})
+
+	if group, err := s.findOfferGroup(ctx, applicationID, func(g *domain.OfferGroup) bool {
+		return g.FindOffer(*offer.ParentOfferID) != nil
+	}); err == nil {
+		group.Offers = append(group.Offers, offer)
+		s.lockAcceptedOffer(ctx, logger, offer, group.Offers)
+		return group, nil
+	}
+
+	s.lockAcceptedOffer(ctx, logger, offer, []*domain.LoanOffer{offer})
+	return &domain.OfferGroup{
+		ApplicationID:   applicationID,
+		Status:          domain.OfferGroupSelected,
+		SelectedOfferID: &offer.ID,
+		ExpiresAt:       offer.ExpiresAt,
+		CreatedAt:       offer.CreatedAt,
+		UpdatedAt:       now,
+		Offers:          []*domain.LoanOffer{offer},
+	}, nil
+}
+
+// DeclineOffer declines a pending offer or counter offer with the borrower's reason
+func (s *LoanService) DeclineOffer(ctx context.Context, applicationID, offerID string, req *domain.DeclineOfferRequest) (*domain.OfferNegotiation, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("offer_id", offerID),
+		zap.String("operation", "decline_offer"),
+	)
+
+	offer, err := s.getNegotiableOffer(ctx, applicationID, offerID)
+	if err != nil {
+		return nil, err
+	}
+
+	offer.Status = domain.OfferStatusDeclined
+	if err := s.repo.UpdateOffer(ctx, offer); err != nil {
+		logger.Error("Failed to decline offer", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	negotiation := &domain.OfferNegotiation{
+		ID:            uuid.New().String(),
+		ApplicationID: applicationID,
+		OfferID:       offer.ID,
+		Action:        domain.NegotiationDeclined,
+		Actor:         domain.NegotiationActorBorrower,
+		Reason:        req.Reason,
+		CreatedAt:     time.Now().UTC(),
+	}
+	s.recordNegotiation(ctx, negotiation)
+
+	logger.Info("Offer declined")
+	s.publishEvent(ctx, domain.WebhookOfferDeclined, applicationID, negotiation)
+	if offer.ParentOfferID != nil {
+		s.signalCounterOfferResponse(ctx, logger, offer, map[string]interface{}{
+			"response": domain.CounterOfferDeclined,
+			"reason":   req.Reason,
+		})
+	}
+	return negotiation, nil
+}
+
+// RespondToCounterOffer accepts or declines a pending counter offer. Either way the counter offer
+// workflow waiting on the borrower resumes; on acceptance it prices the accepted terms and records
+// the final underwriting result.
+func (s *LoanService) RespondToCounterOffer(ctx context.Context, applicationID, offerID string, req *domain.CounterOfferResponseRequest) (*domain.CounterOfferResponseResult, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("offer_id", offerID),
+		zap.String("operation", "respond_to_counter_offer"),
+	)
+
+	if req.Response == domain.CounterOfferDecline {
+		if strings.TrimSpace(req.Reason) == "" {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_020,
+				Message:     "Invalid request format",
+				Description: "A reason is required to decline a counter offer",
+				HTTPStatus:  400,
+			}
+		}
+
+		offer, err := s.getNegotiableOffer(ctx, applicationID, offerID)
+		if err != nil {
+			return nil, err
+		}
+		if offer.ParentOfferID == nil {
+			logger.Warn("Counter offer response to an offer that is not a counter offer")
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Offer not found",
+				Description: fmt.Sprintf("No matching counter offer found for application: %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+
+		negotiation, err := s.DeclineOffer(ctx, applicationID, offerID, &domain.DeclineOfferRequest{Reason: req.Reason})
+		if err != nil {
+			return nil, err
+		}
+		return &domain.CounterOfferResponseResult{Response: domain.CounterOfferDeclined, Negotiation: negotiation}, nil
+	}
+
+	group, err := s.acceptCounterOffer(ctx, applicationID, offerID)
+	if err != nil {
+		return nil, err
+	}
+	s.sendClosingDocuments(ctx, logger, applicationID, offerID)
+	return &domain.CounterOfferResponseResult{Response: domain.CounterOfferAccepted, OfferGroup: group}, nil
+}
+
+// signalCounterOfferResponse completes the counter offer workflow's wait for the borrower's
+// response. The response stands if the workflow cannot be resumed; it is logged for operators.
+func (s *LoanService) signalCounterOfferResponse(ctx context.Context, logger *zap.Logger, offer *domain.LoanOffer, output map[string]interface{}) {
+	if s.workflowOrchestrator == nil {
+		return
+	}
+
+	entries, err := s.repo.ListNegotiations(ctx, offer.ApplicationID)
+	if err != nil {
+		logger.Warn("Failed to find counter offer workflow", zap.Error(err))
+		return
+	}
+	workflowID := ""
+	for _, entry := range entries {
+		if entry.Action == domain.NegotiationCounterRequested && domain.CounterOfferID(entry.ID) == offer.ID {
+			workflowID = entry.WorkflowID
+			break
+		}
+	}
+	if workflowID == "" {
+		logger.Warn("No counter offer workflow to resume", zap.String("counter_offer_id", offer.ID))
+		return
+	}
+
+	output["counterOfferId"] = offer.ID
+	output["respondedAt"] = time.Now().UTC().Format(time.RFC3339)
+	if err := s.workflowOrchestrator.CompleteHumanTask(ctx, workflowID, "wait_for_counter_offer_response_ref", output); err != nil {
+		logger.Warn("Failed to resume counter offer workflow",
+			zap.String("workflow_id", workflowID),
+			zap.Error(err))
+		return
+	}
+	logger.Info("Counter offer workflow resumed",
+		zap.String("workflow_id", workflowID),
+		zap.Any("response", output["response"]))
+}
+
+// RequestCounterOffer records a borrower's request for different terms than an offer's and starts
+// the counter offer workflow. The counter offer appears in the negotiation thread once generated.
+func (s *LoanService) RequestCounterOffer(ctx context.Context, applicationID, offerID string, req *domain.CounterOfferRequest) (*domain.OfferNegotiation, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("offer_id", offerID),
+		zap.String("operation", "request_counter_offer"),
+	)
+
+	if validation := req.Validate(); !validation.Valid {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_030,
+			Message:     "Invalid offer terms",
+			Description: "A different amount or term is required",
+			HTTPStatus:  400,
+		}
+	}
+
+	offer, err := s.getNegotiableOffer(ctx, applicationID, offerID)
+	if err != nil {
+		return nil, err
+	}
+	if offer.ParentOfferID != nil {
+		logger.Warn("Counter requested on a counter offer")
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_035,
+			Message:     "Offer not open for negotiation",
+			Description: "A counter offer can be accepted or declined, not countered again",
+			HTTPStatus:  409,
+		}
+	}
+
+	negotiation := &domain.OfferNegotiation{
+		ID:                  uuid.New().String(),
+		ApplicationID:       applicationID,
+		OfferID:             offer.ID,
+		Action:              domain.NegotiationCounterRequested,
+		Actor:               domain.NegotiationActorBorrower,
+		Reason:              req.Reason,
+		RequestedAmount:     req.RequestedAmount,
+		RequestedTermMonths: req.RequestedTermMonths,
+		CreatedAt:           time.Now().UTC(),
+	}
+
+	// The offer is only marked countered once the workflow generating the counter offer is running,
+	// so a request that cannot be worked on leaves the offer open to accept or counter again
+	if s.workflowOrchestrator == nil {
+		logger.Error("Workflow orchestrator not configured")
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_014,
+			Message:     "Workflow engine unavailable",
+			Description: "Counter offers cannot be requested because the workflow engine is not configured",
+			HTTPStatus:  503,
+		}
+	}
+	workflowExecution, err := s.workflowOrchestrator.StartCounterOfferWorkflow(ctx, offer, negotiation)
+	if err != nil {
+		logger.Error("Failed to start counter offer workflow", zap.Error(err))
+		return nil, err
+	}
+	negotiation.WorkflowID = workflowExecution.WorkflowID
+
+	offer.Status = domain.OfferStatusCountered
+	if err := s.repo.UpdateOffer(ctx, offer); err != nil {
+		logger.Error("Failed to mark offer countered", zap.Error(err))
+		if terminateErr := s.workflowOrchestrator.TerminateWorkflow(ctx, negotiation.WorkflowID, "offer could not be marked countered"); terminateErr != nil {
+			logger.Error("Failed to terminate counter offer workflow",
+				zap.String("workflow_id", negotiation.WorkflowID),
+				zap.Error(terminateErr))
+		}
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	s.recordNegotiation(ctx, negotiation)
+
+	logger.Info("Counter offer requested", zap.String("workflow_id", negotiation.WorkflowID))
+	s.publishEvent(ctx, domain.WebhookOfferCounterRequested, applicationID, negotiation)
+	return negotiation, nil
+}
+
+// GetNegotiation returns an application's offer negotiation thread and its counter offers
+func (s *LoanService) GetNegotiation(ctx context.Context, applicationID string) (*domain.NegotiationThread, error) {
+	if _, err := s.GetApplication(ctx, applicationID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.repo.ListNegotiations(ctx, applicationID)
+	if err != nil {
+		s.logger.Error("Failed to list negotiation entries", zap.Error(err), zap.String("application_id", applicationID))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	counterOffers, err := s.repo.ListCounterOffers(ctx, applicationID)
+	if err != nil {
+		s.logger.Error("Failed to list counter offers", zap.Error(err), zap.String("application_id", applicationID))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	s.lockViewedOffers(ctx, applicationID, counterOffers)
+	return &domain.NegotiationThread{
+		ApplicationID: applicationID,
+		Entries:       entries,
+		CounterOffers: counterOffers,
+	}, nil
+}
+
+// AddNote posts a note on an application. Notes are internal unless shared with the borrower, and
+// mentioned staff are notified.
+func (s *LoanService) AddNote(ctx context.Context, applicationID string, req *domain.CreateNoteRequest) (*domain.ApplicationNote, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("author_id", req.AuthorID),
+		zap.String("operation", "add_note"),
+	)
+
+	if _, err := s.GetApplication(ctx, applicationID); err != nil {
+		return nil, err
+	}
+
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = domain.NoteVisibilityInternal
+	}
+
+	note := &domain.ApplicationNote{
+		ID:            uuid.New().String(),
+		ApplicationID: applicationID,
+		AuthorID:      req.AuthorID,
+		AuthorRole:    req.AuthorRole,
+		Visibility:    visibility,
+		Body:          req.Body,
+		Mentions:      uniqueMentions(req.Mentions, req.AuthorID),
+		Attachments:   req.Attachments,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	if err := s.repo.CreateNote(ctx, note); err != nil {
+		logger.Error("Failed to create note", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Failed to add note",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	if s.notifier != nil {
+		for _, userID := range note.Mentions {
+			if err := s.notifier.SendNotification(ctx, userID,
+				"You were mentioned in an application note",
+				note.Body,
+				map[string]interface{}{
+					"action":         "application_note_mention",
+					"application_id": applicationID,
+					"note_id":        note.ID,
+				},
+			); err != nil {
+				logger.Warn("Failed to notify mentioned user", zap.String("mentioned_user_id", userID), zap.Error(err))
+			}
+		}
+	}
+
+	logger.Info("Note added successfully",
+		zap.String("note_id", note.ID),
+		zap.String("visibility", string(note.Visibility)),
+		zap.Int("mentions", len(note.Mentions)),
+	)
+
+	return note, nil
+}
+
+// ListNotes returns an application's notes. Borrowers only see notes shared with them, without
+// staff identities.
+func (s *LoanService) ListNotes(ctx context.Context, applicationID string, includeInternal bool) ([]*domain.ApplicationNote, error) {
+	if _, err := s.GetApplication(ctx, applicationID); err != nil {
+		return nil, err
+	}
+
+	notes, err := s.repo.ListNotes(ctx, applicationID, !includeInternal)
+	if err != nil {
+		s.logger.Error("Failed to list notes", zap.Error(err), zap.String("application_id", applicationID))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	if !includeInternal {
+		for i, note := range notes {
+			notes[i] = note.BorrowerView()
+		}
+	}
+
+	return notes, nil
+}
+
+// uniqueMentions drops duplicate mentions and the author mentioning themselves
+func uniqueMentions(mentions []string, authorID string) []string {
+	seen := map[string]bool{authorID: true}
+	var unique []string
+	for _, userID := range mentions {
+		if !seen[userID] {
+			seen[userID] = true
+			unique = append(unique, userID)
+		}
+	}
+	return unique
+}
+
+// lockViewedOffers locks the rates of the pending offers shown to the borrower and attaches each
+// offer's lock. The offers are still shown if locking fails.
+func (s *LoanService) lockViewedOffers(ctx context.Context, applicationID string, offers []*domain.LoanOffer) {
+	if s.rateLocks == nil {
+		return
+	}
+	if err := s.rateLocks.LockViewedOffers(ctx, applicationID, offers); err != nil {
+		s.logger.Warn("Failed to lock viewed offers", zap.String("application_id", applicationID), zap.Error(err))
+	}
+}
+
+// lockAcceptedOffer locks the rate of the offer just selected and attaches the locks of the offers
+// shown with it. The selection stands if locking fails.
+func (s *LoanService) lockAcceptedOffer(ctx context.Context, logger *zap.Logger, selected *domain.LoanOffer, offers []*domain.LoanOffer) {
+	if s.rateLocks == nil {
+		return
+	}
+	if err := s.rateLocks.LockAcceptedOffer(ctx, selected); err != nil {
+		logger.Warn("Failed to lock accepted offer", zap.Error(err))
+	}
+	if err := s.rateLocks.AttachRateLocks(ctx, selected.ApplicationID, offers); err != nil {
+		logger.Warn("Failed to attach rate locks", zap.Error(err))
+	}
+}
+
+// getNegotiableOffer returns an application's offer if it is still pending and unexpired
+func (s *LoanService) getNegotiableOffer(ctx context.Context, applicationID, offerID string) (*domain.LoanOffer, error) {
+	offer, err := s.repo.GetOfferByID(ctx, offerID)
+	if err != nil || offer.ApplicationID != applicationID {
+		if err == nil || strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Offer not found",
+				Description: fmt.Sprintf("No matching offer found for application: %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		s.logger.Error("Failed to get offer", zap.Error(err), zap.String("offer_id", offerID))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	if offer.Status != domain.OfferStatusPending {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_035,
+			Message:     "Offer not open for negotiation",
+			Description: fmt.Sprintf("Offer %s is %s", offer.ID, offer.Status),
+			HTTPStatus:  409,
+		}
+	}
+	if time.Now().UTC().After(offer.ExpiresAt) {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_009,
+			Message:     "Offer expired",
+			Description: fmt.Sprintf("Offer %s expired at %s", offer.ID, offer.ExpiresAt.Format(time.RFC3339)),
+			HTTPStatus:  410,
+		}
+	}
+
+	return offer, nil
+}
+
+// recordNegotiation appends an entry to the negotiation thread. The offer change it records has
+// already been stored, so a failure here is logged rather than returned.
+func (s *LoanService) recordNegotiation(ctx context.Context, negotiation *domain.OfferNegotiation) {
+	if err := s.repo.CreateNegotiation(ctx, negotiation); err != nil {
+		s.logger.Warn("Failed to record negotiation entry",
+			zap.Error(err),
+			zap.String("application_id", negotiation.ApplicationID),
+			zap.String("action", string(negotiation.Action)))
+	}
+}
+
+// publishEvent queues a webhook event for partners. The change it reports has already been stored,
+// so a failure here is logged rather than returned.
+func (s *LoanService) publishEvent(ctx context.Context, eventType domain.WebhookEventType, applicationID string, data interface{}) {
+	if s.webhooks == nil {
+		return
+	}
+	if err := s.webhooks.Publish(ctx, eventType, applicationID, data); err != nil {
+		s.logger.Warn("Failed to publish webhook event",
+			zap.Error(err),
+			zap.String("application_id", applicationID),
+			zap.String("event_type", string(eventType)))
+	}
+}
+
+// findOfferGroup returns the newest of an application's offer groups matching the predicate
+func (s *LoanService) findOfferGroup(ctx context.Context, applicationID string, match func(*domain.OfferGroup) bool) (*domain.OfferGroup, error) {
+	groups, err := s.listOfferGroups(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		if match(group) {
+			return group, nil
+		}
+	}
+
+	return nil, &domain.LoanError{
+		Code:        domain.LOAN_010,
+		Message:     "Offer not found",
+		Description: fmt.Sprintf("No matching offer found for application: %s", applicationID),
+		HTTPStatus:  404,
+	}
+}
+
+// GetOffer returns the latest offer for an application, including its pricing audit and rate lock
+func (s *LoanService) GetOffer(ctx context.Context, applicationID string) (*domain.LoanOffer, error) {
+	offer, err := s.GetLatestOffer(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lockViewedOffers(ctx, applicationID, []*domain.LoanOffer{offer})
+	return offer, nil
+}
+
+// GetLatestOffer returns the latest offer for an application without locking its rate, for
+// internal callers that do not show the offer to the borrower
+func (s *LoanService) GetLatestOffer(ctx context.Context, applicationID string) (*domain.LoanOffer, error) {
+	offer, err := s.repo.GetOfferByApplicationID(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Offer not found",
+				Description: fmt.Sprintf("No offer found for application: %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		s.logger.Error("Failed to get offer", zap.Error(err), zap.String("application_id", applicationID))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	return offer, nil
 }
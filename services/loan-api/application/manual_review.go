@@ -0,0 +1,378 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/workflow"
+)
+
+// ManualReviewService runs the underwriters' review queue. The underwriting worker queues a review
+// when the workflow routes an application to manual review; underwriters claim reviews under the
+// loan officer assignment rules and complete them with a decision, which completes the workflow's
+// waiting human task. Approvals above the single approver limit are queued again as secondary
+// reviews, which an underwriter other than the first approver signs off before final approval.
+type ManualReviewService struct {
+	repo         LoanRepository
+	assignments  *AssignmentService
+	orchestrator *workflow.LoanWorkflowOrchestrator
+	logger       *zap.Logger
+}
+
+// NewManualReviewService creates a new manual review service
+func NewManualReviewService(repo LoanRepository, assignments *AssignmentService, orchestrator *workflow.LoanWorkflowOrchestrator, logger *zap.Logger) *ManualReviewService {
+	return &ManualReviewService{
+		repo:         repo,
+		assignments:  assignments,
+		orchestrator: orchestrator,
+		logger:       logger,
+	}
+}
+
+// List lists manual reviews, most urgent first
+func (s *ManualReviewService) List(ctx context.Context, query *domain.ManualReviewQuery) ([]*domain.ManualReview, error) {
+	if query.Limit <= 0 {
+		query.Limit = 50
+	}
+	if query.Status != "" && !query.Status.IsValid() {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: fmt.Sprintf("Unknown manual review status: %s", query.Status),
+			HTTPStatus:  400,
+		}
+	}
+
+	reviews, err := s.repo.ListManualReviews(ctx, query)
+	if err != nil {
+		s.logger.Error("Failed to list manual reviews", zap.String("operation", "list_manual_reviews"), zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	return reviews, nil
+}
+
+// Get retrieves a manual review
+func (s *ManualReviewService) Get(ctx context.Context, id string) (*domain.ManualReview, error) {
+	review, err := s.repo.GetManualReview(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_067,
+				Message:     "Manual review not found",
+				Description: fmt.Sprintf("No manual review found with ID: %s", id),
+				HTTPStatus:  404,
+			}
+		}
+		return nil, s.databaseError(err)
+	}
+	return review, nil
+}
+
+// Claim takes a pending review for the calling underwriter. The application is assigned to them
+// as by claiming it, so a review can only be claimed by the officer the application is assigned
+// to, or by anyone with capacity while it is unassigned.
+func (s *ManualReviewService) Claim(ctx context.Context, id, reviewerID string) (*domain.ManualReview, error) {
+	logger := s.logger.With(
+		zap.String("review_id", id),
+		zap.String("reviewer_id", reviewerID),
+		zap.String("operation", "claim_manual_review"),
+	)
+
+	review, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status == domain.ManualReviewInReview && review.AssignedTo != nil && *review.AssignedTo == reviewerID {
+		return review, nil
+	}
+	if review.Status != domain.ManualReviewPending {
+		return nil, s.stateError(review, "claimed")
+	}
+
+	// The application stays assigned to the first approver while another underwriter signs off
+	if review.ReviewType == domain.ManualReviewSecondary {
+		if err := s.requireSecondApprover(review, reviewerID); err != nil {
+			return nil, err
+		}
+	} else if _, err := s.assignments.Claim(ctx, review.ApplicationID, reviewerID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	review.Status = domain.ManualReviewInReview
+	review.AssignedTo = &reviewerID
+	review.ClaimedAt = &now
+	claimed, err := s.repo.ClaimManualReview(ctx, review)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	if !claimed {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_068,
+			Message:     "Manual review cannot be claimed",
+			Description: "The review was claimed by another underwriter while it was being claimed",
+			HTTPStatus:  409,
+		}
+	}
+
+	logger.Info("Manual review claimed", zap.String("application_id", review.ApplicationID))
+	return review, nil
+}
+
+// Complete records the decision on a claimed review and completes the workflow's waiting human
+// task with it. Only the underwriter who claimed the review, or a manager, may complete it.
+func (s *ManualReviewService) Complete(ctx context.Context, id string, req *domain.CompleteManualReviewRequest, reviewerID string, isManager bool) (*domain.ManualReview, error) {
+	logger := s.logger.With(
+		zap.String("review_id", id),
+		zap.String("reviewer_id", reviewerID),
+		zap.String("operation", "complete_manual_review"),
+	)
+
+	if req.Decision != domain.ManualReviewDeny && (req.ApprovedAmount == nil || req.InterestRate == nil) {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: "An approval requires the approved amount and interest rate",
+			HTTPStatus:  400,
+		}
+	}
+	if req.Decision == domain.ManualReviewConditional && len(req.Conditions) == 0 {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: "A conditional approval requires at least one condition",
+			HTTPStatus:  400,
+		}
+	}
+
+	if s.orchestrator == nil {
+		logger.Error("Workflow orchestrator not configured")
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_014,
+			Message:     "Workflow engine unavailable",
+			Description: "Reviews cannot be completed because the workflow engine is not configured",
+			HTTPStatus:  503,
+		}
+	}
+
+	review, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.ReviewType == domain.ManualReviewSecondary {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_068,
+			Message:     "Manual review cannot be completed",
+			Description: fmt.Sprintf("Manual review %s is a secondary review and must be signed off", review.ID),
+			HTTPStatus:  409,
+		}
+	}
+	if review.Status != domain.ManualReviewInReview {
+		return nil, s.stateError(review, "completed")
+	}
+	if (review.AssignedTo == nil || *review.AssignedTo != reviewerID) && !isManager {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_022,
+			Message:     "Access denied",
+			Description: "Only the underwriter who claimed the review or a manager can complete it",
+			HTTPStatus:  403,
+		}
+	}
+
+	now := time.Now().UTC()
+	output := map[string]interface{}{
+		"decision":          string(req.Decision),
+		"comments":          req.Notes,
+		"conditions":        req.Conditions,
+		"denialReasons":     req.DenialReasons,
+		"reviewerId":        reviewerID,
+		"reviewCompletedAt": now.Format(time.RFC3339),
+	}
+	if req.ApprovedAmount != nil {
+		output["approvedAmount"] = *req.ApprovedAmount
+	}
+	if req.InterestRate != nil {
+		output["interestRate"] = *req.InterestRate
+	}
+	if err := s.orchestrator.CompleteHumanTask(ctx, review.WorkflowInstanceID, review.TaskReferenceName, output); err != nil {
+		return nil, err
+	}
+
+	review.Status = domain.ManualReviewCompleted
+	review.Decision = &req.Decision
+	review.Notes = strings.TrimSpace(req.Notes)
+	review.ApprovedAmount = req.ApprovedAmount
+	review.InterestRate = req.InterestRate
+	review.Conditions = req.Conditions
+	review.DenialReasons = req.DenialReasons
+	review.CompletedAt = &now
+	review.CompletedBy = &reviewerID
+	completed, err := s.repo.CompleteManualReview(ctx, review)
+	if err != nil {
+		// The workflow already has the decision, so the caller must know the review is still open
+		logger.Error("Failed to record completed review", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	if !completed {
+		return nil, s.stateError(review, "completed")
+	}
+
+	logger.Info("Manual review completed",
+		zap.String("application_id", review.ApplicationID),
+		zap.String("decision", string(req.Decision)))
+	return review, nil
+}
+
+// SignOff records a second underwriter's sign-off of a large approval and completes the workflow's
+// waiting secondary review task with it: APPROVE lets final approval go ahead and DENY denies the
+// application. Only the underwriter who claimed the review may sign it off, and never the
+// underwriter who made the first approval.
+func (s *ManualReviewService) SignOff(ctx context.Context, id string, req *domain.SignOffManualReviewRequest, reviewerID string) (*domain.ManualReview, error) {
+	logger := s.logger.With(
+		zap.String("review_id", id),
+		zap.String("reviewer_id", reviewerID),
+		zap.String("operation", "sign_off_manual_review"),
+	)
+
+	if s.orchestrator == nil {
+		logger.Error("Workflow orchestrator not configured")
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_014,
+			Message:     "Workflow engine unavailable",
+			Description: "Reviews cannot be signed off because the workflow engine is not configured",
+			HTTPStatus:  503,
+		}
+	}
+
+	review, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.ReviewType != domain.ManualReviewSecondary {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_068,
+			Message:     "Manual review cannot be signed off",
+			Description: fmt.Sprintf("Manual review %s is not a secondary review", review.ID),
+			HTTPStatus:  409,
+		}
+	}
+	if review.Status != domain.ManualReviewInReview {
+		return nil, s.stateError(review, "signed off")
+	}
+	if err := s.requireSecondApprover(review, reviewerID); err != nil {
+		return nil, err
+	}
+	if review.AssignedTo == nil || *review.AssignedTo != reviewerID {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_022,
+			Message:     "Access denied",
+			Description: "Only the underwriter who claimed the secondary review can sign it off",
+			HTTPStatus:  403,
+		}
+	}
+
+	now := time.Now().UTC()
+	output := map[string]interface{}{
+		"decision":          string(req.Decision),
+		"comments":          req.Notes,
+		"reviewerId":        reviewerID,
+		"reviewCompletedAt": now.Format(time.RFC3339),
+	}
+	if req.Decision == domain.ManualReviewDeny {
+		review.DenialReasons = []string{"SECONDARY_REVIEW_DECLINED"}
+		output["denialReasons"] = review.DenialReasons
+	}
+	if err := s.orchestrator.CompleteHumanTask(ctx, review.WorkflowInstanceID, review.TaskReferenceName, output); err != nil {
+		return nil, err
+	}
+
+	review.Status = domain.ManualReviewCompleted
+	review.Decision = &req.Decision
+	review.Notes = strings.TrimSpace(req.Notes)
+	review.CompletedAt = &now
+	review.CompletedBy = &reviewerID
+	completed, err := s.repo.CompleteManualReview(ctx, review)
+	if err != nil {
+		// The workflow already has the sign-off, so the caller must know the review is still open
+		logger.Error("Failed to record signed off review", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	if !completed {
+		return nil, s.stateError(review, "signed off")
+	}
+
+	logger.Info("Secondary review signed off",
+		zap.String("application_id", review.ApplicationID),
+		zap.String("decision", string(req.Decision)))
+	return review, nil
+}
+
+// requireSecondApprover checks the underwriter is not the one who made the first approval
+func (s *ManualReviewService) requireSecondApprover(review *domain.ManualReview, reviewerID string) error {
+	if review.PrimaryReviewerID != nil && *review.PrimaryReviewerID == reviewerID {
+		return &domain.LoanError{
+			Code:        domain.LOAN_079,
+			Message:     "Second underwriter required",
+			Description: fmt.Sprintf("Secondary review %s must be signed off by an underwriter other than %s, who approved the loan", review.ID, reviewerID),
+			HTTPStatus:  409,
+		}
+	}
+	return nil
+}
+
+// RecordRedecision records the decision engine's re-decision of an application with late data on
+// its open review, so the underwriter deciding a provisional approval sees the decision that
+// replaced it. An application not held in review, such as one denied provisionally, has nothing
+// to record it on. The note names the decision, so a re-decision sent again is recorded once.
+func (s *ManualReviewService) RecordRedecision(ctx context.Context, applicationID string, redecision *domain.Redecision) error {
+	logger := s.logger.With(
+		zap.String("operation", "record_redecision"),
+		zap.String("application_id", applicationID),
+		zap.Int64("decision_id", redecision.DecisionID),
+		zap.String("decision", redecision.Decision),
+	)
+
+	note := fmt.Sprintf("Re-decision %d with late data: %s", redecision.DecisionID, redecision.Decision)
+	if redecision.Reason != "" {
+		note += " (" + redecision.Reason + ")"
+	}
+	if redecision.Provisional {
+		note += ", still provisional"
+	}
+
+	recorded, err := s.repo.RecordManualReviewRedecision(ctx, applicationID, note)
+	if err != nil {
+		logger.Error("Failed to record re-decision", zap.Error(err))
+		return s.databaseError(err)
+	}
+	if recorded == 0 {
+		logger.Info("Application has no open manual review without the re-decision, nothing recorded")
+		return nil
+	}
+
+	logger.Info("Re-decision recorded on manual review", zap.Int("reviews", recorded))
+	return nil
+}
+
+func (s *ManualReviewService) stateError(review *domain.ManualReview, action string) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_068,
+		Message:     fmt.Sprintf("Manual review cannot be %s", action),
+		Description: fmt.Sprintf("Manual review %s is %s", review.ID, review.Status),
+		HTTPStatus:  409,
+	}
+}
+
+func (s *ManualReviewService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
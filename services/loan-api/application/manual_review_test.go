@@ -0,0 +1,79 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// stubReviewRepository records re-decision notes on openReviews reviews, or fails with err
+type stubReviewRepository struct {
+	LoanRepository
+	openReviews   int
+	err           error
+	applicationID string
+	note          string
+}
+
+func (r *stubReviewRepository) RecordManualReviewRedecision(ctx context.Context, applicationID, note string) (int, error) {
+	r.applicationID = applicationID
+	r.note = note
+	return r.openReviews, r.err
+}
+
+func TestManualReviewService_RecordRedecision(t *testing.T) {
+	redecision := &domain.Redecision{
+		DecisionID:  42,
+		Decision:    "DENY",
+		Reason:      "fraud screening failed",
+		Provisional: false,
+	}
+
+	tests := []struct {
+		name        string
+		openReviews int
+		err         error
+		wantCode    string
+	}{
+		{name: "has an open review", openReviews: 1},
+		{name: "has no open review", openReviews: 0},
+		{name: "repository fails", err: errors.New("connection refused"), wantCode: domain.LOAN_023},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubReviewRepository{openReviews: tt.openReviews, err: tt.err}
+			service := NewManualReviewService(repo, nil, nil, zap.NewNop())
+
+			err := service.RecordRedecision(context.Background(), "app-1", redecision)
+			if tt.wantCode != "" {
+				var loanErr *domain.LoanError
+				require.ErrorAs(t, err, &loanErr)
+				assert.Equal(t, tt.wantCode, loanErr.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "app-1", repo.applicationID)
+			assert.Equal(t, "Re-decision 42 with late data: DENY (fraud screening failed)", repo.note)
+		})
+	}
+
+	t.Run("provisional re-decision is noted as such", func(t *testing.T) {
+		repo := &stubReviewRepository{openReviews: 1}
+		service := NewManualReviewService(repo, nil, nil, zap.NewNop())
+
+		err := service.RecordRedecision(context.Background(), "app-1", &domain.Redecision{
+			DecisionID:  43,
+			Decision:    "APPROVE",
+			Provisional: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Re-decision 43 with late data: APPROVE, still provisional", repo.note)
+	})
+}
@@ -0,0 +1,198 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// OfferExpiryJob expires offers past their expiry date and closes out approved applications left
+// without an open offer, prompting the borrower to apply again. An application whose offers the
+// borrower all declined moves to the offer declined state; otherwise at least one offer lapsed and it
+// moves to the offer expired state.
+type OfferExpiryJob struct {
+	repo      LoanRepository
+	notifier  Notifier
+	webhooks  WebhookPublisher
+	batchSize int
+	interval  time.Duration
+	logger    *zap.Logger
+}
+
+// OfferExpiryResult summarizes a single expiry pass
+type OfferExpiryResult struct {
+	ExpiredOffers        int `json:"expired_offers"`
+	ExpiredApplications  int `json:"expired_applications"`
+	DeclinedApplications int `json:"declined_applications"`
+	Notified             int `json:"notified"`
+	Failures             int `json:"failures"`
+}
+
+func NewOfferExpiryJob(
+	repo LoanRepository,
+	notifier Notifier,
+	webhooks WebhookPublisher,
+	batchSize int,
+	interval time.Duration,
+	logger *zap.Logger,
+) *OfferExpiryJob {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &OfferExpiryJob{
+		repo:      repo,
+		notifier:  notifier,
+		webhooks:  webhooks,
+		batchSize: batchSize,
+		interval:  interval,
+		logger:    logger,
+	}
+}
+
+// Start runs expiry passes on the configured interval until the context is cancelled
+func (j *OfferExpiryJob) Start(ctx context.Context) {
+	if j.interval <= 0 {
+		j.logger.Info("Offer expiry job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Offer expiry job stopped")
+			return
+		case <-ticker.C:
+			if _, err := j.RunOnce(ctx); err != nil {
+				j.logger.Error("Offer expiry pass failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RunOnce expires every offer that has lapsed as of now and then the applications left without one
+func (j *OfferExpiryJob) RunOnce(ctx context.Context) (*OfferExpiryResult, error) {
+	logger := j.logger.With(zap.String("operation", "offer_expiry"))
+	result := &OfferExpiryResult{}
+
+	expired, err := j.repo.ExpireOffers(ctx, time.Now().UTC())
+	if err != nil {
+		return result, err
+	}
+	result.ExpiredOffers = expired
+
+	// Transitioned applications drop out of the scan; the cursor skips the ones that failed
+	afterID := ""
+	for {
+		lapsed, err := j.repo.ListApplicationsWithLapsedOffers(ctx, afterID, j.batchSize)
+		if err != nil {
+			return result, err
+		}
+
+		for _, entry := range lapsed {
+			afterID = entry.ApplicationID
+			j.expire(ctx, logger, entry, result)
+		}
+
+		if len(lapsed) < j.batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	logger.Info("Offer expiry pass completed",
+		zap.Int("expired_offers", result.ExpiredOffers),
+		zap.Int("expired_applications", result.ExpiredApplications),
+		zap.Int("declined_applications", result.DeclinedApplications),
+		zap.Int("notified", result.Notified),
+		zap.Int("failures", result.Failures),
+	)
+
+	return result, ctx.Err()
+}
+
+func (j *OfferExpiryJob) expire(ctx context.Context, logger *zap.Logger, lapsed domain.LapsedOffers, result *OfferExpiryResult) {
+	logger = logger.With(zap.String("application_id", lapsed.ApplicationID))
+
+	toState, reason := domain.StateOfferExpired, "All offers expired"
+	subject, message := "Your loan offer has expired",
+		"Your loan offer expired before it was accepted. Please apply again to receive a new offer."
+	if lapsed.AllDeclined {
+		toState, reason = domain.StateOfferDeclined, "All offers declined"
+		subject, message = "Your loan application has been closed",
+			"You declined every offer made for this application. You can apply again at any time to receive new offers."
+	}
+
+	application, err := j.repo.GetApplicationByID(ctx, lapsed.ApplicationID)
+	if err != nil {
+		result.Failures++
+		logger.Warn("Failed to get application", zap.Error(err))
+		return
+	}
+
+	if !application.CanTransitionTo(toState) {
+		result.Failures++
+		logger.Warn("Application cannot move to lapsed offer state",
+			zap.String("current_state", string(application.CurrentState)),
+			zap.String("to_state", string(toState)))
+		return
+	}
+
+	fromState := application.CurrentState
+	application.CurrentState = toState
+	application.UpdatedAt = time.Now().UTC()
+
+	if err := j.repo.UpdateApplication(ctx, application); err != nil {
+		result.Failures++
+		logger.Warn("Failed to move application to lapsed offer state", zap.Error(err), zap.String("to_state", string(toState)))
+		return
+	}
+	if lapsed.AllDeclined {
+		result.DeclinedApplications++
+	} else {
+		result.ExpiredApplications++
+	}
+
+	transition := &domain.StateTransition{
+		ID:               uuid.New().String(),
+		ApplicationID:    application.ID,
+		FromState:        &fromState,
+		ToState:          toState,
+		TransitionReason: reason,
+		Automated:        true,
+		Metadata:         map[string]interface{}{"source": "offer_expiry_job"},
+		CreatedAt:        time.Now().UTC(),
+	}
+	if err := j.repo.CreateStateTransition(ctx, transition); err != nil {
+		logger.Warn("Failed to create state transition", zap.Error(err))
+	}
+
+	// Each declined offer already published its own event
+	if j.webhooks != nil && !lapsed.AllDeclined {
+		if err := j.webhooks.Publish(ctx, domain.WebhookOfferExpired, application.ID, map[string]interface{}{
+			"expired_at": application.UpdatedAt,
+		}); err != nil {
+			logger.Warn("Failed to publish offer expired event", zap.Error(err))
+		}
+	}
+
+	if j.notifier == nil {
+		return
+	}
+
+	if err := j.notifier.SendNotification(ctx, application.UserID, subject, message,
+		map[string]interface{}{
+			"action":         "loan_reapply",
+			"application_id": application.ID,
+		},
+	); err != nil {
+		logger.Warn("Failed to send re-application prompt", zap.Error(err))
+		return
+	}
+	result.Notified++
+}
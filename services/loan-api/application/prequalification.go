@@ -0,0 +1,321 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/shared/pkg/dti"
+)
+
+// minPreQualifyIncome is the lowest combined annual income a borrower can pre-qualify with
+const minPreQualifyIncome = 25000
+
+// preQualifyPaymentShare is the share of monthly income a new loan payment may take up
+const preQualifyPaymentShare = 0.25
+
+// preQualifyMaxTerm is the term, in months, the maximum loan amount is sized over
+const preQualifyMaxTerm = 60
+
+// PreQualificationPolicy holds the limits pre-qualification results are evaluated against
+type PreQualificationPolicy struct {
+	MinLoanAmount    float64
+	MaxLoanAmount    float64
+	MaxDTIRatio      float64
+	BaseInterestRate float64 // the best rate a borrower can be quoted before adjustments
+	MinInterestRate  float64
+	MaxInterestRate  float64
+	TTL              time.Duration // how long a result can be converted into an application
+	DTI              dti.Config    // debt treatments applied to student loans, alimony and rental income
+}
+
+// PreQualificationService gives borrowers an estimate of what they can borrow from their income and
+// debts, without a credit pull, and starts draft applications from qualified results
+type PreQualificationService struct {
+	repo     LoanRepository
+	userRepo UserRepository
+	policy   PreQualificationPolicy
+	dti      *dti.Calculator
+	logger   *zap.Logger
+}
+
+// NewPreQualificationService creates a new pre-qualification service
+func NewPreQualificationService(repo LoanRepository, userRepo UserRepository, policy PreQualificationPolicy, logger *zap.Logger) *PreQualificationService {
+	if policy.TTL <= 0 {
+		policy.TTL = 30 * 24 * time.Hour
+	}
+	return &PreQualificationService{
+		repo:     repo,
+		userRepo: userRepo,
+		policy:   policy,
+		dti:      dti.NewCalculator(policy.DTI),
+		logger:   logger,
+	}
+}
+
+// PreQualify evaluates a borrower's figures and stores the result until it expires. Co-borrower
+// income and debts are combined with the borrower's.
+func (s *PreQualificationService) PreQualify(ctx context.Context, userID string, req *domain.PreQualifyRequest) (*domain.PreQualifyResult, error) {
+	logger := s.logger.With(
+		zap.String("user_id", userID),
+		zap.String("operation", "pre_qualify"),
+	)
+
+	now := time.Now().UTC()
+	result := s.evaluate(req)
+	result.ID = uuid.New().String()
+	result.UserID = userID
+	result.Request = *req
+	result.ExpiresAt = now.Add(s.policy.TTL)
+	result.CreatedAt = now
+
+	if err := s.repo.CreatePreQualification(ctx, result); err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	logger.Info("Pre-qualification completed",
+		zap.String("prequalification_id", result.ID),
+		zap.Bool("qualified", result.Qualified),
+		zap.Float64("dti_ratio", result.DTIRatio),
+		zap.Float64("max_loan_amount", result.MaxLoanAmount))
+	return result, nil
+}
+
+// GetPreQualification retrieves a pre-qualification result. A non-empty userID must own it.
+func (s *PreQualificationService) GetPreQualification(ctx context.Context, id, userID string) (*domain.PreQualifyResult, error) {
+	logger := s.logger.With(
+		zap.String("prequalification_id", id),
+		zap.String("operation", "get_prequalification"),
+	)
+
+	result, err := s.repo.GetPreQualification(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Pre-qualification not found",
+				Description: fmt.Sprintf("No pre-qualification found with ID: %s", id),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get pre-qualification", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	if userID != "" && result.UserID != userID {
+		logger.Warn("User does not own pre-qualification", zap.String("user_id", userID))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_022,
+			Message:     "Unauthorized access",
+			Description: "Pre-qualifications can only be viewed by the borrower they were made for",
+			HTTPStatus:  403,
+		}
+	}
+	return result, nil
+}
+
+// ConvertToApplication starts a draft application from a qualified, unexpired pre-qualification,
+// carrying over the borrower's figures. The borrower completes and submits it as any other
+// application; co-borrower details are added then. Converting again returns the same application.
+func (s *PreQualificationService) ConvertToApplication(ctx context.Context, id, userID string, req *domain.ConvertPreQualificationRequest) (*domain.LoanApplication, error) {
+	logger := s.logger.With(
+		zap.String("prequalification_id", id),
+		zap.String("user_id", userID),
+		zap.String("operation", "convert_prequalification"),
+	)
+
+	result, err := s.GetPreQualification(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.ApplicationID != nil {
+		return s.getApplication(ctx, logger, *result.ApplicationID)
+	}
+	if result.IsExpired(time.Now().UTC()) {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_052,
+			Message:     "Pre-qualification expired",
+			Description: fmt.Sprintf("Pre-qualification expired at %s; pre-qualify again to apply", result.ExpiresAt.Format(time.RFC3339)),
+			HTTPStatus:  410,
+		}
+	}
+	if !result.Qualified {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_053,
+			Message:     "Pre-qualification cannot be converted",
+			Description: result.Message,
+			HTTPStatus:  409,
+		}
+	}
+
+	loanAmount := req.LoanAmount
+	if loanAmount == 0 {
+		loanAmount = math.Min(result.Request.LoanAmount, result.MaxLoanAmount)
+	}
+	if loanAmount > result.MaxLoanAmount {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_006,
+			Message:     "Loan amount above maximum",
+			Description: fmt.Sprintf("The pre-qualification supports up to $%.2f", result.MaxLoanAmount),
+			HTTPStatus:  400,
+		}
+	}
+	if !slices.Contains(result.RecommendedTerms, req.RequestedTerm) {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_003,
+			Message:     "Invalid loan term",
+			Description: fmt.Sprintf("Choose one of the pre-qualified terms: %v", result.RecommendedTerms),
+			HTTPStatus:  400,
+		}
+	}
+
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_021,
+				Message:     "User not found",
+				Description: "A borrower profile is required to start an application",
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get borrower", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	now := time.Now().UTC()
+	application := &domain.LoanApplication{
+		ID:                uuid.New().String(),
+		UserID:            userID,
+		ApplicationNumber: generateApplicationNumber(),
+		LoanAmount:        loanAmount,
+		LoanPurpose:       req.LoanPurpose,
+		AnnualIncome:      result.Request.AnnualIncome,
+		MonthlyIncome:     math.Round(result.Request.AnnualIncome/12*100) / 100,
+		MonthlyDebt:       result.Request.MonthlyDebt,
+		RequestedTerm:     req.RequestedTerm,
+		EmploymentStatus:  result.Request.EmploymentStatus,
+		ApplicationType:   domain.ApplicationTypeNew,
+		CurrentState:      domain.StateInitiated,
+		Status:            domain.StatusDraft,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := s.repo.CreateApplication(ctx, application); err != nil {
+		logger.Error("Failed to create application", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	if err := s.repo.MarkPreQualificationConverted(ctx, id, application.ID); err != nil {
+		// Converted concurrently; the application created here stays as an unsubmitted draft
+		logger.Warn("Failed to record pre-qualification conversion", zap.Error(err))
+	}
+
+	transition := &domain.StateTransition{
+		ID:               uuid.New().String(),
+		ApplicationID:    application.ID,
+		ToState:          domain.StateInitiated,
+		TransitionReason: "Application started from pre-qualification",
+		UserID:           &userID,
+		Metadata: map[string]interface{}{
+			"source":              "prequalification",
+			"prequalification_id": id,
+		},
+		CreatedAt: now,
+	}
+	if err := s.repo.CreateStateTransition(ctx, transition); err != nil {
+		logger.Warn("Failed to create state transition", zap.Error(err))
+	}
+
+	logger.Info("Pre-qualification converted", zap.String("application_id", application.ID))
+	return application, nil
+}
+
+// evaluate applies the policy to the borrower's figures. Income and debts are qualified under the
+// DTI debt treatments. The payment a new loan may take up is limited by both the payment share of
+// income and the room left under the DTI limit.
+func (s *PreQualificationService) evaluate(req *domain.PreQualifyRequest) *domain.PreQualifyResult {
+	qualifying := s.dti.Calculate(req.DTIBorrowers()...)
+	monthlyIncome := qualifying.MonthlyIncome
+	monthlyDebt := qualifying.MonthlyDebt
+	result := &domain.PreQualifyResult{RecommendedTerms: []int{}}
+	result.DTIRatio = roundTo(qualifying.Ratio, 4)
+
+	switch {
+	case req.EmploymentStatus == domain.EmploymentUnemployed && req.CoBorrowerAnnualIncome == 0:
+		result.Message = "Employment income is required to pre-qualify"
+		return result
+	case req.AnnualIncome+req.CoBorrowerAnnualIncome < minPreQualifyIncome:
+		result.Message = fmt.Sprintf("Annual income of at least $%d is required", minPreQualifyIncome)
+		return result
+	case result.DTIRatio > s.policy.MaxDTIRatio:
+		result.Message = "Your debt-to-income ratio is too high to pre-qualify"
+		return result
+	}
+
+	interestRate := s.policy.BaseInterestRate
+	if result.DTIRatio > 0.36 {
+		interestRate += 1.0
+	} else if result.DTIRatio > 0.30 {
+		interestRate += 0.5
+	}
+	if req.AnnualIncome+req.CoBorrowerAnnualIncome < 40000 {
+		interestRate += 0.5
+	}
+	minRate := math.Max(interestRate, s.policy.MinInterestRate)
+	maxRate := math.Min(interestRate+3.5, s.policy.MaxInterestRate)
+
+	maxPayment := maxAffordablePayment(monthlyIncome, monthlyDebt, s.policy.MaxDTIRatio)
+	maxLoanAmount := math.Min(maxPayment/amortizedPayment(1, maxRate, preQualifyMaxTerm), s.policy.MaxLoanAmount)
+	if maxLoanAmount < s.policy.MinLoanAmount {
+		result.Message = fmt.Sprintf("Your income and debts support less than the minimum loan of $%.0f", s.policy.MinLoanAmount)
+		return result
+	}
+
+	// Terms whose payment on the requested amount, up to the maximum, fits within the payment limit
+	amount := math.Min(req.LoanAmount, maxLoanAmount)
+	for _, term := range domain.OfferTerms {
+		if amortizedPayment(amount, maxRate, term) <= maxPayment {
+			result.RecommendedTerms = append(result.RecommendedTerms, term)
+		}
+	}
+
+	result.Qualified = true
+	result.MaxLoanAmount = math.Floor(maxLoanAmount/100) * 100
+	result.MinInterestRate = roundTo(minRate, 2)
+	result.MaxInterestRate = roundTo(maxRate, 2)
+	result.Message = "You are pre-qualified for a loan"
+	return result
+}
+
+// maxAffordablePayment is the largest monthly payment a new loan may take up: limited by both the
+// payment share of income and the room left under the DTI limit
+func maxAffordablePayment(monthlyIncome, monthlyDebt, maxDTIRatio float64) float64 {
+	return math.Max(0, math.Min(monthlyIncome*preQualifyPaymentShare, monthlyIncome*maxDTIRatio-monthlyDebt))
+}
+
+// getApplication retrieves the application a pre-qualification was converted into
+func (s *PreQualificationService) getApplication(ctx context.Context, logger *zap.Logger, applicationID string) (*domain.LoanApplication, error) {
+	application, err := s.repo.GetApplicationByID(ctx, applicationID)
+	if err != nil {
+		logger.Error("Failed to get converted application", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	return application, nil
+}
+
+// databaseError wraps a repository failure
+func (s *PreQualificationService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
@@ -0,0 +1,438 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// PricingRepository interface for rate matrix persistence
+type PricingRepository interface {
+	CreateRate(ctx context.Context, rate *domain.PricingRate) error
+	GetRate(ctx context.Context, id string) (*domain.PricingRate, error)
+	UpdateRate(ctx context.Context, rate *domain.PricingRate) error
+	DeleteRate(ctx context.Context, id string) error
+	ListRates(ctx context.Context, query *domain.PricingRateQuery) ([]*domain.PricingRate, error)
+	// FindRate returns the rate in effect at the given time for the product, tier, risk level and term
+	FindRate(ctx context.Context, product string, tier domain.CreditTier, riskLevel domain.RiskLevel, termMonths int, at time.Time) (*domain.PricingRate, error)
+}
+
+// DecisionSource looks up the decision engine's stored decision on an application
+type DecisionSource interface {
+	GetDecision(ctx context.Context, applicationID string) (*domain.UnderwritingDecision, error)
+}
+
+// PricingService prices offers from rate matrices stored in the database and manages those matrices
+type PricingService struct {
+	repo      PricingRepository
+	decisions DecisionSource
+	logger    *zap.Logger
+}
+
+// NewPricingService creates a new pricing service that reads the inputs offers are priced from
+// from the decision engine's stored decisions
+func NewPricingService(repo PricingRepository, decisions DecisionSource, logger *zap.Logger) *PricingService {
+	return &PricingService{
+		repo:      repo,
+		decisions: decisions,
+		logger:    logger,
+	}
+}
+
+// PricingInputs returns the pricing request an application's offers are priced from, filled in
+// from its underwriting decision and the decision engine's pricing saved with it; the caller sets the amount, term and pricing time. Provisional
+// decisions are held: their offers are priced once the decision is final.
+func (s *PricingService) PricingInputs(ctx context.Context, applicationID string) (*domain.PricingRequest, error) {
+	logger := s.logger.With(
+		zap.String("operation", "pricing_inputs"),
+		zap.String("application_id", applicationID),
+	)
+
+	decision, err := s.decisions.GetDecision(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			logger.Warn("Application has no underwriting decision")
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_081,
+				Message:     "Underwriting decision unavailable",
+				Description: "The application has no underwriting decision to price offers from",
+				HTTPStatus:  409,
+			}
+		}
+		logger.Error("Failed to get underwriting decision", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_081,
+			Message:     "Underwriting decision unavailable",
+			Description: err.Error(),
+			HTTPStatus:  502,
+		}
+	}
+	if decision.Provisional {
+		logger.Info("Underwriting decision is provisional, offers are held")
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_082,
+			Message:     "Underwriting decision is provisional",
+			Description: "The decision is waiting on late data sources; offers are priced once it is final",
+			HTTPStatus:  409,
+		}
+	}
+
+	inputs := &domain.PricingRequest{
+		ApplicationID: applicationID,
+		Product:       domain.ProductPersonalLoan,
+		CreditScore:   decision.CreditScore,
+		RiskLevel:     decision.RiskLevel,
+	}
+	// A decision without a usable price is priced from the rate matrix
+	if pricing := decision.Pricing; pricing != nil && pricing.Rate > 0 && pricing.Rate <= 100 &&
+		pricing.NetOriginationFeeRate >= 0 && pricing.NetOriginationFeeRate <= 100 {
+		inputs.DecisionPricing = pricing
+	}
+	return inputs, nil
+}
+
+// Quote prices a loan from the rate in effect at req.PricedAt, or from the decision engine's
+// pricing when the request carries it; see PricingInputs. The result is deterministic for a given set of inputs and
+// rate, and is returned as the audit record attached to the offer.
+func (s *PricingService) Quote(ctx context.Context, req *domain.PricingRequest) (*domain.PricingAudit, error) {
+	tier := domain.CreditTierForScore(req.CreditScore)
+	logger := s.logger.With(
+		zap.String("operation", "quote"),
+		zap.String("application_id", req.ApplicationID),
+		zap.String("product", req.Product),
+		zap.String("credit_tier", string(tier)),
+		zap.String("risk_level", string(req.RiskLevel)),
+		zap.Int("term_months", req.TermMonths),
+	)
+
+	if !req.RiskLevel.IsValid() || req.LoanAmount <= 0 || req.TermMonths <= 0 {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_030,
+			Message:     "Invalid offer terms",
+			Description: "Risk level, loan amount and term are required to price an offer",
+			HTTPStatus:  400,
+		}
+	}
+
+	if req.DecisionPricing != nil {
+		audit := priceAudit(req, tier, req.DecisionPricing.Rate, req.DecisionPricing.NetOriginationFeeRate)
+		audit.Source = domain.PricingSourceDecisionEngine
+		audit.DecisionPricing = req.DecisionPricing
+		audit.Fingerprint = audit.ComputeFingerprint()
+
+		logger.Info("Offer priced from decision",
+			zap.String("pricing_model_version", req.DecisionPricing.ModelVersion),
+			zap.Float64("interest_rate", audit.InterestRate),
+			zap.Float64("apr", audit.APR),
+		)
+		return audit, nil
+	}
+
+	rate, err := s.repo.FindRate(ctx, req.Product, tier, req.RiskLevel, req.TermMonths, req.PricedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			logger.Warn("No pricing rate matches the request")
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_034,
+				Message:     "No pricing rate available",
+				Description: fmt.Sprintf("No %s rate for tier %s, risk %s and %d months", req.Product, tier, req.RiskLevel, req.TermMonths),
+				HTTPStatus:  422,
+			}
+		}
+		logger.Error("Failed to find pricing rate", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	audit := priceAudit(req, tier, rate.InterestRate, rate.OriginationFeeRate)
+	audit.Source = domain.PricingSourceRateMatrix
+	audit.RateID = rate.ID
+	audit.RateEffectiveFrom = rate.EffectiveFrom
+	audit.Fingerprint = audit.ComputeFingerprint()
+
+	logger.Info("Offer priced",
+		zap.String("rate_id", rate.ID),
+		zap.Float64("interest_rate", audit.InterestRate),
+		zap.Float64("apr", audit.APR),
+	)
+
+	return audit, nil
+}
+
+// CreateRate adds an entry to a rate matrix. Entries may not overlap an existing entry for the
+// same product, tier and risk level in both term range and effective period.
+func (s *PricingService) CreateRate(ctx context.Context, actorID string, req *domain.CreatePricingRateRequest) (*domain.PricingRate, error) {
+	now := time.Now().UTC()
+	rate := &domain.PricingRate{
+		ID:                 uuid.New().String(),
+		Product:            req.Product,
+		CreditTier:         req.CreditTier,
+		RiskLevel:          req.RiskLevel,
+		MinTermMonths:      req.MinTermMonths,
+		MaxTermMonths:      req.MaxTermMonths,
+		InterestRate:       req.InterestRate,
+		OriginationFeeRate: req.OriginationFeeRate,
+		EffectiveFrom:      req.EffectiveFrom.UTC(),
+		CreatedBy:          actorID,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if req.EffectiveTo != nil {
+		effectiveTo := req.EffectiveTo.UTC()
+		rate.EffectiveTo = &effectiveTo
+	}
+
+	if err := s.checkRate(ctx, rate); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateRate(ctx, rate); err != nil {
+		s.logger.Error("Failed to create pricing rate", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	s.logger.Info("Pricing rate created",
+		zap.String("rate_id", rate.ID),
+		zap.String("actor_id", actorID),
+		zap.String("product", rate.Product),
+		zap.String("credit_tier", string(rate.CreditTier)),
+		zap.String("risk_level", string(rate.RiskLevel)),
+	)
+	return rate, nil
+}
+
+// GetRate retrieves a rate matrix entry by ID
+func (s *PricingService) GetRate(ctx context.Context, id string) (*domain.PricingRate, error) {
+	rate, err := s.repo.GetRate(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_032,
+				Message:     "Pricing rate not found",
+				Description: fmt.Sprintf("No pricing rate found with ID: %s", id),
+				HTTPStatus:  404,
+			}
+		}
+		s.logger.Error("Failed to get pricing rate", zap.Error(err), zap.String("rate_id", id))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	return rate, nil
+}
+
+// ListRates lists rate matrix entries matching the query
+func (s *PricingService) ListRates(ctx context.Context, query *domain.PricingRateQuery) ([]*domain.PricingRate, error) {
+	rates, err := s.repo.ListRates(ctx, query)
+	if err != nil {
+		s.logger.Error("Failed to list pricing rates", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	return rates, nil
+}
+
+// UpdateRate changes a rate matrix entry. Once a rate is in effect only its end date can change,
+// so offers already priced from it can be reproduced.
+func (s *PricingService) UpdateRate(ctx context.Context, actorID, id string, req *domain.UpdatePricingRateRequest) (*domain.PricingRate, error) {
+	rate, err := s.GetRate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if req.ChangesTerms() && !now.Before(rate.EffectiveFrom) {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_033,
+			Message:     "Pricing rate conflict",
+			Description: "Only the end date of a rate already in effect can be changed; add a new rate instead",
+			HTTPStatus:  409,
+		}
+	}
+
+	if req.MinTermMonths != nil {
+		rate.MinTermMonths = *req.MinTermMonths
+	}
+	if req.MaxTermMonths != nil {
+		rate.MaxTermMonths = *req.MaxTermMonths
+	}
+	if req.InterestRate != nil {
+		rate.InterestRate = *req.InterestRate
+	}
+	if req.OriginationFeeRate != nil {
+		rate.OriginationFeeRate = *req.OriginationFeeRate
+	}
+	if req.EffectiveFrom != nil {
+		rate.EffectiveFrom = req.EffectiveFrom.UTC()
+	}
+	if req.EffectiveTo != nil {
+		effectiveTo := req.EffectiveTo.UTC()
+		rate.EffectiveTo = &effectiveTo
+	}
+	rate.UpdatedAt = now
+
+	if err := s.checkRate(ctx, rate); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateRate(ctx, rate); err != nil {
+		s.logger.Error("Failed to update pricing rate", zap.Error(err), zap.String("rate_id", id))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	s.logger.Info("Pricing rate updated", zap.String("rate_id", id), zap.String("actor_id", actorID))
+	return rate, nil
+}
+
+// DeleteRate removes a rate matrix entry that has not yet taken effect
+func (s *PricingService) DeleteRate(ctx context.Context, actorID, id string) error {
+	rate, err := s.GetRate(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !time.Now().Before(rate.EffectiveFrom) {
+		return &domain.LoanError{
+			Code:        domain.LOAN_033,
+			Message:     "Pricing rate conflict",
+			Description: "A rate that has taken effect cannot be deleted; set its end date instead",
+			HTTPStatus:  409,
+		}
+	}
+
+	if err := s.repo.DeleteRate(ctx, id); err != nil {
+		s.logger.Error("Failed to delete pricing rate", zap.Error(err), zap.String("rate_id", id))
+		return &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	s.logger.Info("Pricing rate deleted", zap.String("rate_id", id), zap.String("actor_id", actorID))
+	return nil
+}
+
+// checkRate validates a rate and rejects it if it overlaps another entry of the same matrix
+func (s *PricingService) checkRate(ctx context.Context, rate *domain.PricingRate) error {
+	if validation := rate.Validate(); !validation.Valid {
+		return &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: fmt.Sprintf("Invalid pricing rate fields: %v", validation.Errors),
+			HTTPStatus:  400,
+		}
+	}
+
+	existing, err := s.repo.ListRates(ctx, &domain.PricingRateQuery{
+		Product:    rate.Product,
+		CreditTier: rate.CreditTier,
+		RiskLevel:  rate.RiskLevel,
+	})
+	if err != nil {
+		s.logger.Error("Failed to list pricing rates", zap.Error(err))
+		return &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	for _, other := range existing {
+		if other.ID != rate.ID && rate.Overlaps(other) {
+			return &domain.LoanError{
+				Code:        domain.LOAN_033,
+				Message:     "Pricing rate conflict",
+				Description: fmt.Sprintf("Rate overlaps existing rate %s", other.ID),
+				HTTPStatus:  409,
+			}
+		}
+	}
+
+	return nil
+}
+
+// priceAudit prices the request at an interest rate and origination fee, both in percent
+func priceAudit(req *domain.PricingRequest, tier domain.CreditTier, interestRate, originationFeeRate float64) *domain.PricingAudit {
+	monthlyPayment := amortizedPayment(req.LoanAmount, interestRate, req.TermMonths)
+	return &domain.PricingAudit{
+		ApplicationID:      req.ApplicationID,
+		Product:            req.Product,
+		CreditScore:        req.CreditScore,
+		CreditTier:         tier,
+		RiskLevel:          req.RiskLevel,
+		TermMonths:         req.TermMonths,
+		LoanAmount:         req.LoanAmount,
+		InterestRate:       interestRate,
+		OriginationFeeRate: originationFeeRate,
+		APR:                roundTo(annualPercentageRate(req.LoanAmount, originationFeeRate, monthlyPayment, req.TermMonths), 2),
+		MonthlyPayment:     roundTo(monthlyPayment, 2),
+		TotalInterest:      roundTo(monthlyPayment*float64(req.TermMonths)-req.LoanAmount, 2),
+		PricedAt:           req.PricedAt,
+	}
+}
+
+// amortizedPayment returns the fixed monthly payment for a loan at an annual percentage rate
+func amortizedPayment(principal, annualRate float64, termMonths int) float64 {
+	monthlyRate := annualRate / 100 / 12
+	if monthlyRate == 0 {
+		return principal / float64(termMonths)
+	}
+	factor := math.Pow(1+monthlyRate, float64(termMonths))
+	return principal * monthlyRate * factor / (factor - 1)
+}
+
+// annualPercentageRate finds the annual rate at which the payments repay the amount actually
+// received, i.e. the loan amount less the origination fee
+func annualPercentageRate(principal, feeRate, monthlyPayment float64, termMonths int) float64 {
+	financed := principal * (1 - feeRate/100)
+	low, high := 0.0, 100.0
+	// Bisection on a fixed number of steps keeps the result identical across runs
+	for i := 0; i < 100; i++ {
+		mid := (low + high) / 2
+		if amortizedPayment(financed, mid, termMonths) < monthlyPayment {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2
+}
+
+// roundTo rounds a value to the given number of decimal places
+func roundTo(value float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(value*scale) / scale
+}
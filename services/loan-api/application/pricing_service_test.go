@@ -0,0 +1,244 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// stubPricingRepository finds the one rate it holds, or fails with err
+type stubPricingRepository struct {
+	PricingRepository
+	rate *domain.PricingRate
+	err  error
+}
+
+func (r *stubPricingRepository) FindRate(ctx context.Context, product string, tier domain.CreditTier, riskLevel domain.RiskLevel, termMonths int, at time.Time) (*domain.PricingRate, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.rate == nil || r.rate.Product != product || r.rate.CreditTier != tier || r.rate.RiskLevel != riskLevel ||
+		termMonths < r.rate.MinTermMonths || termMonths > r.rate.MaxTermMonths {
+		return nil, fmt.Errorf("pricing rate not found")
+	}
+	return r.rate, nil
+}
+
+// stubDecisionSource returns the decision it holds, or fails with err
+type stubDecisionSource struct {
+	decision *domain.UnderwritingDecision
+	err      error
+}
+
+func (d *stubDecisionSource) GetDecision(ctx context.Context, applicationID string) (*domain.UnderwritingDecision, error) {
+	return d.decision, d.err
+}
+
+func TestPricingService_Quote(t *testing.T) {
+	pricedAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	goodLowRate := &domain.PricingRate{
+		ID:                 "rate-1",
+		Product:            domain.ProductPersonalLoan,
+		CreditTier:         domain.CreditTierGood,
+		RiskLevel:          domain.RiskLevelLow,
+		MinTermMonths:      12,
+		MaxTermMonths:      60,
+		InterestRate:       12,
+		OriginationFeeRate: 2,
+		EffectiveFrom:      pricedAt.AddDate(0, -1, 0),
+	}
+
+	tests := []struct {
+		name    string
+		req     domain.PricingRequest
+		repoErr error
+		// expected audit figures, or the expected error code
+		source         string
+		interestRate   float64
+		monthlyPayment float64
+		totalInterest  float64
+		apr            float64
+		errCode        string
+	}{
+		{
+			name:           "rate matrix",
+			req:            domain.PricingRequest{Product: domain.ProductPersonalLoan, CreditScore: 700, RiskLevel: domain.RiskLevelLow, TermMonths: 36, LoanAmount: 10000},
+			source:         domain.PricingSourceRateMatrix,
+			interestRate:   12,
+			monthlyPayment: 332.14,
+			totalInterest:  1957.15,
+			apr:            13.41,
+		},
+		{
+			name: "decision pricing overrides the rate matrix",
+			req: domain.PricingRequest{Product: domain.ProductPersonalLoan, CreditScore: 700, RiskLevel: domain.RiskLevelLow, TermMonths: 48, LoanAmount: 20000,
+				DecisionPricing: &domain.DecisionPricing{ModelVersion: "risk-pricing-1", Rate: 11.75, NetOriginationFeeRate: 1}},
+			source:         domain.PricingSourceDecisionEngine,
+			interestRate:   11.75,
+			monthlyPayment: 524.23,
+			totalInterest:  5162.8,
+			apr:            12.29,
+		},
+		{
+			name: "decision pricing without a fee has an APR equal to its rate",
+			req: domain.PricingRequest{Product: domain.ProductPersonalLoan, CreditScore: 800, RiskLevel: domain.RiskLevelLow, TermMonths: 36, LoanAmount: 10000,
+				DecisionPricing: &domain.DecisionPricing{Rate: 12}},
+			source:         domain.PricingSourceDecisionEngine,
+			interestRate:   12,
+			monthlyPayment: 332.14,
+			totalInterest:  1957.15,
+			apr:            12,
+		},
+		{
+			name:    "no rate for the credit tier",
+			req:     domain.PricingRequest{Product: domain.ProductPersonalLoan, CreditScore: 600, RiskLevel: domain.RiskLevelLow, TermMonths: 36, LoanAmount: 10000},
+			errCode: domain.LOAN_034,
+		},
+		{
+			name:    "no rate for the term",
+			req:     domain.PricingRequest{Product: domain.ProductPersonalLoan, CreditScore: 700, RiskLevel: domain.RiskLevelLow, TermMonths: 72, LoanAmount: 10000},
+			errCode: domain.LOAN_034,
+		},
+		{
+			name:    "invalid risk level",
+			req:     domain.PricingRequest{Product: domain.ProductPersonalLoan, CreditScore: 700, RiskLevel: "EXTREME", TermMonths: 36, LoanAmount: 10000},
+			errCode: domain.LOAN_030,
+		},
+		{
+			name:    "no amount",
+			req:     domain.PricingRequest{Product: domain.ProductPersonalLoan, CreditScore: 700, RiskLevel: domain.RiskLevelLow, TermMonths: 36},
+			errCode: domain.LOAN_030,
+		},
+		{
+			name:    "rate lookup fails",
+			req:     domain.PricingRequest{Product: domain.ProductPersonalLoan, CreditScore: 700, RiskLevel: domain.RiskLevelLow, TermMonths: 36, LoanAmount: 10000},
+			repoErr: fmt.Errorf("connection refused"),
+			errCode: domain.LOAN_023,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewPricingService(&stubPricingRepository{rate: goodLowRate, err: tt.repoErr}, &stubDecisionSource{}, zap.NewNop())
+			req := tt.req
+			req.ApplicationID = "app-1"
+			req.PricedAt = pricedAt
+
+			audit, err := service.Quote(context.Background(), &req)
+			if tt.errCode != "" {
+				require.Error(t, err)
+				loanErr, ok := err.(*domain.LoanError)
+				require.True(t, ok)
+				assert.Equal(t, tt.errCode, loanErr.Code)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.source, audit.Source)
+			assert.Equal(t, tt.interestRate, audit.InterestRate)
+			assert.Equal(t, tt.monthlyPayment, audit.MonthlyPayment)
+			assert.Equal(t, tt.totalInterest, audit.TotalInterest)
+			assert.Equal(t, tt.apr, audit.APR)
+			assert.Equal(t, domain.CreditTierForScore(req.CreditScore), audit.CreditTier)
+			assert.NotEmpty(t, audit.Fingerprint)
+		})
+	}
+}
+
+func TestPricingService_QuoteIsDeterministic(t *testing.T) {
+	service := NewPricingService(&stubPricingRepository{}, &stubDecisionSource{}, zap.NewNop())
+	req := &domain.PricingRequest{
+		ApplicationID:   "app-1",
+		Product:         domain.ProductPersonalLoan,
+		CreditScore:     720,
+		RiskLevel:       domain.RiskLevelMedium,
+		TermMonths:      60,
+		LoanAmount:      25000,
+		DecisionPricing: &domain.DecisionPricing{Rate: 8.5, NetOriginationFeeRate: 1},
+	}
+
+	first, err := service.Quote(context.Background(), req)
+	require.NoError(t, err)
+	req.PricedAt = req.PricedAt.Add(time.Hour)
+	second, err := service.Quote(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, first.Fingerprint, second.Fingerprint)
+	assert.Equal(t, 512.91, second.MonthlyPayment)
+	assert.Equal(t, 8.93, second.APR)
+}
+
+func TestPricingService_PricingInputs(t *testing.T) {
+	pricing := &domain.DecisionPricing{Rate: 11.75, NetOriginationFeeRate: 1}
+
+	tests := []struct {
+		name        string
+		decision    *domain.UnderwritingDecision
+		err         error
+		wantPricing *domain.DecisionPricing
+		errCode     string
+		errStatus   int
+	}{
+		{
+			name:        "final decision with pricing",
+			decision:    &domain.UnderwritingDecision{CreditScore: 720, RiskLevel: domain.RiskLevelLow, Pricing: pricing},
+			wantPricing: pricing,
+		},
+		{
+			name:     "final decision without pricing is priced from the rate matrix",
+			decision: &domain.UnderwritingDecision{CreditScore: 720, RiskLevel: domain.RiskLevelLow},
+		},
+		{
+			name:     "decision pricing without a rate is ignored",
+			decision: &domain.UnderwritingDecision{CreditScore: 720, RiskLevel: domain.RiskLevelLow, Pricing: &domain.DecisionPricing{}},
+		},
+		{
+			name:      "provisional decision is held",
+			decision:  &domain.UnderwritingDecision{CreditScore: 720, RiskLevel: domain.RiskLevelLow, Provisional: true},
+			errCode:   domain.LOAN_082,
+			errStatus: 409,
+		},
+		{
+			name:      "no decision",
+			err:       fmt.Errorf("decision not found for application app-1"),
+			errCode:   domain.LOAN_081,
+			errStatus: 409,
+		},
+		{
+			name:      "decision engine unavailable",
+			err:       fmt.Errorf("failed to call decision engine: connection refused"),
+			errCode:   domain.LOAN_081,
+			errStatus: 502,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewPricingService(&stubPricingRepository{}, &stubDecisionSource{decision: tt.decision, err: tt.err}, zap.NewNop())
+
+			inputs, err := service.PricingInputs(context.Background(), "app-1")
+			if tt.errCode != "" {
+				require.Error(t, err)
+				loanErr, ok := err.(*domain.LoanError)
+				require.True(t, ok)
+				assert.Equal(t, tt.errCode, loanErr.Code)
+				assert.Equal(t, tt.errStatus, loanErr.HTTPStatus)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "app-1", inputs.ApplicationID)
+			assert.Equal(t, domain.ProductPersonalLoan, inputs.Product)
+			assert.Equal(t, tt.decision.CreditScore, inputs.CreditScore)
+			assert.Equal(t, tt.decision.RiskLevel, inputs.RiskLevel)
+			assert.Equal(t, tt.wantPricing, inputs.DecisionPricing)
+		})
+	}
+}
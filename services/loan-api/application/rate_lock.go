@@ -0,0 +1,305 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// RateLockPolicy holds how long offered rates stay locked
+type RateLockPolicy struct {
+	Window         time.Duration            // products without their own window
+	ProductWindows map[string]time.Duration // keyed by product
+}
+
+// WindowFor returns the lock window of a product
+func (p RateLockPolicy) WindowFor(product string) time.Duration {
+	if window, ok := p.ProductWindows[product]; ok && window > 0 {
+		return window
+	}
+	return p.Window
+}
+
+// RateLockService locks offered rates for the product's lock window when the borrower views a
+// pending offer and again when they accept it. Once a pending offer's lock expires, or an offer
+// that was never locked has been priced for longer than the window, it must be repriced at current
+// rates before it can be selected.
+type RateLockService struct {
+	repo   LoanRepository
+	pricer OfferPricer
+	policy RateLockPolicy
+	logger *zap.Logger
+}
+
+// NewRateLockService creates a new rate lock service
+func NewRateLockService(repo LoanRepository, pricer OfferPricer, policy RateLockPolicy, logger *zap.Logger) *RateLockService {
+	if policy.Window <= 0 {
+		policy.Window = 72 * time.Hour
+	}
+	return &RateLockService{
+		repo:   repo,
+		pricer: pricer,
+		policy: policy,
+		logger: logger,
+	}
+}
+
+// LockViewedOffers locks the rates of the pending offers a borrower is shown that have not been
+// locked yet, and attaches each offer's lock. Expired locks are not renewed; those offers are
+// marked as requiring repricing.
+func (s *RateLockService) LockViewedOffers(ctx context.Context, applicationID string, offers []*domain.LoanOffer) error {
+	return s.attachRateLocks(ctx, applicationID, offers, true)
+}
+
+// AttachRateLocks attaches each offer's latest rate lock without locking any
+func (s *RateLockService) AttachRateLocks(ctx context.Context, applicationID string, offers []*domain.LoanOffer) error {
+	return s.attachRateLocks(ctx, applicationID, offers, false)
+}
+
+// CheckRateLock returns an error when an offer about to be selected must be repriced first
+func (s *RateLockService) CheckRateLock(ctx context.Context, offer *domain.LoanOffer) error {
+	lock, err := s.getRateLock(ctx, offer.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if !s.requiresRepricing(offer, lock, now) {
+		return nil
+	}
+
+	description := fmt.Sprintf("Offer %s was priced at %s and its rate is no longer locked; reprice the offer to continue",
+		offer.ID, offer.PricedAt().Format(time.RFC3339))
+	if lock != nil {
+		description = fmt.Sprintf("The rate on offer %s was locked until %s; reprice the offer to continue",
+			offer.ID, lock.ExpiresAt.Format(time.RFC3339))
+	}
+	s.logger.Warn("Offer selected after its rate lock expired",
+		zap.String("application_id", offer.ApplicationID),
+		zap.String("offer_id", offer.ID))
+	return &domain.LoanError{
+		Code:        domain.LOAN_054,
+		Message:     "Rate lock expired",
+		Description: description,
+		HTTPStatus:  409,
+	}
+}
+
+// LockAcceptedOffer locks the rate of an offer the borrower has just selected for a full lock
+// window, so it holds through signing and funding
+func (s *RateLockService) LockAcceptedOffer(ctx context.Context, offer *domain.LoanOffer) error {
+	now := time.Now().UTC()
+	lock := s.newRateLock(offer, domain.RateLockAccepted, now)
+	if err := s.repo.CreateRateLock(ctx, lock); err != nil {
+		s.logger.Error("Failed to lock accepted offer",
+			zap.String("offer_id", offer.ID),
+			zap.Error(err))
+		return s.databaseError(err)
+	}
+
+	s.attach(offer, lock, now)
+	s.logger.Info("Rate locked on accepted offer",
+		zap.String("application_id", offer.ApplicationID),
+		zap.String("offer_id", offer.ID),
+		zap.Time("expires_at", lock.ExpiresAt))
+	return nil
+}
+
+// RepriceOffer prices a pending offer again at the rates in effect now, from the application's
+// underwriting decision, and locks the new rate. Offers whose rate is still locked are returned unchanged.
+// Counter offers are priced in negotiation, not from the rate matrices, so they cannot be repriced;
+// the borrower requests new terms instead.
+func (s *RateLockService) RepriceOffer(ctx context.Context, applicationID, offerID string) (*domain.LoanOffer, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("offer_id", offerID),
+		zap.String("operation", "reprice_offer"),
+	)
+
+	offer, err := s.repo.GetOfferByID(ctx, offerID)
+	if err != nil || offer.ApplicationID != applicationID {
+		if err == nil || strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Offer not found",
+				Description: fmt.Sprintf("No matching offer found for application: %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get offer", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	now := time.Now().UTC()
+	if offer.Status != domain.OfferStatusPending {
+		return nil, s.cannotRepriceError(fmt.Sprintf("Offer %s is %s; only pending offers can be repriced", offer.ID, offer.Status))
+	}
+	if now.After(offer.ExpiresAt) {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_009,
+			Message:     "Offer expired",
+			Description: fmt.Sprintf("Offer %s expired at %s", offer.ID, offer.ExpiresAt.Format(time.RFC3339)),
+			HTTPStatus:  410,
+		}
+	}
+
+	lock, err := s.getRateLock(ctx, offer.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.requiresRepricing(offer, lock, now) {
+		s.attach(offer, lock, now)
+		return offer, nil
+	}
+	if offer.PricingAudit == nil {
+		return nil, s.cannotRepriceError("Counter offers cannot be repriced; request new terms instead")
+	}
+
+	// The inputs come from the underwriting decision as it stands now, not from the previous audit
+	inputs, err := s.pricer.PricingInputs(ctx, applicationID)
+	if err != nil {
+		logger.Warn("Offer pricing inputs unavailable", zap.Error(err))
+		return nil, err
+	}
+	previous := offer.PricingAudit
+	inputs.TermMonths = offer.TermMonths
+	inputs.LoanAmount = offer.OfferAmount
+	inputs.PricedAt = now
+	audit, err := s.pricer.Quote(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	audit.OfferID = offer.ID
+
+	offer.InterestRate = audit.InterestRate
+	offer.MonthlyPayment = audit.MonthlyPayment
+	offer.TotalInterest = audit.TotalInterest
+	offer.APR = audit.APR
+	offer.PricingAudit = audit
+
+	lock = s.newRateLock(offer, domain.RateLockRepriced, now)
+	if err := s.repo.RepriceOffer(ctx, offer, lock); err != nil {
+		if strings.Contains(err.Error(), "not pending") {
+			return nil, s.cannotRepriceError(fmt.Sprintf("Offer %s is no longer pending", offer.ID))
+		}
+		logger.Error("Failed to reprice offer", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	s.attach(offer, lock, now)
+	logger.Info("Offer repriced",
+		zap.Float64("previous_interest_rate", previous.InterestRate),
+		zap.Float64("interest_rate", offer.InterestRate),
+		zap.Time("lock_expires_at", lock.ExpiresAt))
+	return offer, nil
+}
+
+// attachRateLocks attaches each offer's latest lock, first locking unlocked pending offers when lock
+// is set
+func (s *RateLockService) attachRateLocks(ctx context.Context, applicationID string, offers []*domain.LoanOffer, lock bool) error {
+	if len(offers) == 0 {
+		return nil
+	}
+
+	locks, err := s.repo.ListRateLocks(ctx, applicationID)
+	if err != nil {
+		return s.databaseError(err)
+	}
+	latest := make(map[string]*domain.RateLock, len(locks))
+	for _, rateLock := range locks {
+		latest[rateLock.OfferID] = rateLock
+	}
+
+	now := time.Now().UTC()
+	for _, offer := range offers {
+		rateLock := latest[offer.ID]
+		if lock && rateLock == nil && offer.Status == domain.OfferStatusPending && now.Before(offer.ExpiresAt) &&
+			!s.requiresRepricing(offer, nil, now) {
+			rateLock = s.newRateLock(offer, domain.RateLockViewed, now)
+			if err := s.repo.CreateRateLock(ctx, rateLock); err != nil {
+				return s.databaseError(err)
+			}
+		}
+		s.attach(offer, rateLock, now)
+	}
+	return nil
+}
+
+// requiresRepricing reports whether a pending offer's rate is no longer locked: its latest lock has
+// expired or, when it has none, it was priced longer than a lock window ago
+func (s *RateLockService) requiresRepricing(offer *domain.LoanOffer, lock *domain.RateLock, now time.Time) bool {
+	if lock != nil {
+		return lock.IsExpired(now)
+	}
+	return !now.Before(offer.PricedAt().Add(s.policy.WindowFor(offer.Product())))
+}
+
+// attach sets an offer's lock and whether it must be repriced before it can be selected
+func (s *RateLockService) attach(offer *domain.LoanOffer, lock *domain.RateLock, now time.Time) {
+	if lock != nil {
+		lock.Status = lock.StatusAt(now)
+	}
+	offer.RateLock = lock
+	offer.RepricingRequired = offer.Status == domain.OfferStatusPending && now.Before(offer.ExpiresAt) &&
+		s.requiresRepricing(offer, lock, now)
+}
+
+// newRateLock locks an offer's current rate for its product's window. Locks on pending offers end
+// no later than the offer itself.
+func (s *RateLockService) newRateLock(offer *domain.LoanOffer, trigger domain.RateLockTrigger, now time.Time) *domain.RateLock {
+	product := offer.Product()
+	expiresAt := now.Add(s.policy.WindowFor(product))
+	if trigger != domain.RateLockAccepted && offer.ExpiresAt.Before(expiresAt) {
+		expiresAt = offer.ExpiresAt
+	}
+	return &domain.RateLock{
+		ID:            uuid.New().String(),
+		OfferID:       offer.ID,
+		ApplicationID: offer.ApplicationID,
+		Product:       product,
+		InterestRate:  offer.InterestRate,
+		APR:           offer.APR,
+		Trigger:       trigger,
+		LockedAt:      now,
+		ExpiresAt:     expiresAt,
+	}
+}
+
+// getRateLock returns an offer's latest lock, or nil when it has never been locked
+func (s *RateLockService) getRateLock(ctx context.Context, offerID string) (*domain.RateLock, error) {
+	lock, err := s.repo.GetRateLock(ctx, offerID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, nil
+		}
+		s.logger.Error("Failed to get rate lock", zap.String("offer_id", offerID), zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	return lock, nil
+}
+
+// cannotRepriceError is returned for offers that cannot be priced again
+func (s *RateLockService) cannotRepriceError(description string) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_055,
+		Message:     "Offer cannot be repriced",
+		Description: description,
+		HTTPStatus:  409,
+	}
+}
+
+// databaseError wraps a repository failure
+func (s *RateLockService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
@@ -0,0 +1,175 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// RefinanceService quotes the payoff of funded loans borrowers may refinance. Refinance applications
+// link to the loan they pay off when they are created; their offers are compared against keeping
+// that loan and their disbursement pays it off before the rest reaches the borrower.
+type RefinanceService struct {
+	repo   LoanRepository
+	logger *zap.Logger
+}
+
+// NewRefinanceService creates a new refinance service
+func NewRefinanceService(repo LoanRepository, logger *zap.Logger) *RefinanceService {
+	return &RefinanceService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// GetPayoffQuote quotes the current balance and payoff of a funded loan. A non-empty userID must own
+// the loan.
+func (s *RefinanceService) GetPayoffQuote(ctx context.Context, applicationID, userID string) (*domain.PayoffQuote, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "get_payoff_quote"),
+	)
+
+	application, err := s.repo.GetApplicationByID(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Application not found",
+				Description: fmt.Sprintf("No application found with ID: %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get application", zap.Error(err))
+		return nil, refinanceDatabaseError(err)
+	}
+	if userID != "" && application.UserID != userID {
+		logger.Warn("User does not own application", zap.String("user_id", userID))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_022,
+			Message:     "Unauthorized access",
+			Description: "Payoff quotes can only be requested by the loan's borrower",
+			HTTPStatus:  403,
+		}
+	}
+
+	quote, err := quotePayoff(ctx, s.repo, application, time.Now().UTC())
+	if err != nil {
+		logger.Warn("Payoff cannot be quoted", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("Payoff quoted",
+		zap.Float64("payoff_amount", quote.PayoffAmount),
+		zap.Time("good_through", quote.GoodThrough))
+	return quote, nil
+}
+
+// quotePayoff quotes the payoff of a funded loan as of the given time from its repayment schedule
+func quotePayoff(ctx context.Context, repo LoanRepository, loan *domain.LoanApplication, asOf time.Time) (*domain.PayoffQuote, error) {
+	if !loan.CanBeRefinanced() {
+		return nil, cannotRefinanceError(fmt.Sprintf("Application %s is in %s state; only funded loans that are being repaid can be refinanced", loan.ID, loan.CurrentState))
+	}
+
+	schedule, err := repo.GetRepaymentSchedule(ctx, loan.ID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, cannotRefinanceError(fmt.Sprintf("Loan %s has no repayment schedule to quote its payoff from", loan.ID))
+		}
+		return nil, refinanceDatabaseError(err)
+	}
+
+	quote := domain.NewPayoffQuote(schedule, asOf)
+	if quote.PayoffAmount <= 0 {
+		return nil, cannotRefinanceError(fmt.Sprintf("Loan %s has no balance left to pay off", loan.ID))
+	}
+	return quote, nil
+}
+
+// cannotRefinanceError is returned when a loan cannot be refinanced or a refinance cannot proceed
+func cannotRefinanceError(description string) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_056,
+		Message:     "Loan cannot be refinanced",
+		Description: description,
+		HTTPStatus:  409,
+	}
+}
+
+// refinanceDatabaseError wraps a repository failure while quoting a payoff
+func refinanceDatabaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
+
+// checkRefinance checks that the applicant owns the loan a new application refinances, that the
+// loan can be refinanced and that the requested amount covers its payoff
+func (s *LoanService) checkRefinance(ctx context.Context, logger *zap.Logger, req *domain.CreateApplicationRequest, applicant *domain.User) error {
+	loanID := *req.RefinanceApplicationID
+	logger = logger.With(zap.String("refinanced_application_id", loanID))
+
+	if applicant == nil {
+		return cannotRefinanceError("Only an existing borrower's loan can be refinanced")
+	}
+
+	loan, err := s.repo.GetApplicationByID(ctx, loanID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Application not found",
+				Description: fmt.Sprintf("No loan found to refinance with ID: %s", loanID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get loan to refinance", zap.Error(err))
+		return refinanceDatabaseError(err)
+	}
+	if loan.UserID != applicant.ID {
+		logger.Warn("Refinance requested for another borrower's loan", zap.String("user_id", applicant.ID))
+		return &domain.LoanError{
+			Code:        domain.LOAN_022,
+			Message:     "Unauthorized access",
+			Description: "Only the borrower's own loans can be refinanced",
+			HTTPStatus:  403,
+		}
+	}
+
+	quote, err := quotePayoff(ctx, s.repo, loan, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if req.LoanAmount < quote.PayoffAmount {
+		return cannotRefinanceError(fmt.Sprintf("The loan amount of $%.2f does not cover the $%.2f payoff of loan %s", req.LoanAmount, quote.PayoffAmount, loanID))
+	}
+	return nil
+}
+
+// quoteRefinancedLoan quotes the payoff of the loan a refinance application pays off
+func (s *LoanService) quoteRefinancedLoan(ctx context.Context, application *domain.LoanApplication, asOf time.Time) (*domain.PayoffQuote, error) {
+	loan, err := s.repo.GetApplicationByID(ctx, *application.RefinancedApplicationID)
+	if err != nil {
+		return nil, refinanceDatabaseError(err)
+	}
+	return quotePayoff(ctx, s.repo, loan, asOf)
+}
+
+// refinanceAmounts returns the offer amounts that pay off the refinanced loan with some left over
+func refinanceAmounts(amounts []float64, payoff *domain.PayoffQuote) []float64 {
+	var covering []float64
+	for _, amount := range amounts {
+		if amount > payoff.PayoffAmount {
+			covering = append(covering, amount)
+		}
+	}
+	return covering
+}
@@ -0,0 +1,176 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// RepaymentScheduleService generates the repayment schedules of funded loans and serves them to
+// borrowers and loan servicing
+type RepaymentScheduleService struct {
+	repo   LoanRepository
+	logger *zap.Logger
+}
+
+// NewRepaymentScheduleService creates a new repayment schedule service
+func NewRepaymentScheduleService(repo LoanRepository, logger *zap.Logger) *RepaymentScheduleService {
+	return &RepaymentScheduleService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// GenerateSchedule generates and stores the repayment schedule of a funded loan from the offer that
+// was disbursed. Payments are scheduled from when the disbursement settled, or from now for loans
+// funded without one. A loan's schedule is only generated once; generating it again returns it.
+func (s *RepaymentScheduleService) GenerateSchedule(ctx context.Context, applicationID string) (*domain.RepaymentSchedule, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "generate_repayment_schedule"),
+	)
+
+	existing, err := s.repo.GetRepaymentSchedule(ctx, applicationID)
+	if err == nil {
+		return existing, nil
+	}
+	if !strings.Contains(err.Error(), "not found") {
+		logger.Error("Failed to get repayment schedule", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	application, err := s.repo.GetApplicationByID(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Application not found",
+				Description: fmt.Sprintf("No application found with ID: %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get application", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	if application.CurrentState != domain.StateFunded && application.CurrentState != domain.StateActive {
+		return nil, s.cannotGenerateError(fmt.Sprintf("Application is in %s state; repayment schedules are generated once the loan is funded", application.CurrentState))
+	}
+
+	fundedAt := time.Now().UTC()
+	offerID := ""
+	var disbursementID *string
+	disbursement, err := s.repo.GetLatestDisbursement(ctx, applicationID)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		logger.Error("Failed to get disbursement", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	if disbursement != nil && disbursement.Status == domain.DisbursementCompleted {
+		offerID = disbursement.OfferID
+		disbursementID = &disbursement.ID
+		if disbursement.CompletedAt != nil {
+			fundedAt = *disbursement.CompletedAt
+		}
+	}
+
+	offers, err := s.repo.ListOffers(ctx, applicationID)
+	if err != nil {
+		logger.Error("Failed to list offers", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	offer := findSelectedOffer(offers, offerID)
+	if offer == nil {
+		return nil, s.cannotGenerateError("No offer has been selected for this application")
+	}
+
+	schedule := domain.NewRepaymentSchedule(offer, fundedAt, func() string { return uuid.New().String() })
+	schedule.DisbursementID = disbursementID
+	if err := s.repo.CreateRepaymentSchedule(ctx, schedule); err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			// Generated concurrently, e.g. by a redelivered settlement notification
+			if existing, getErr := s.repo.GetRepaymentSchedule(ctx, applicationID); getErr == nil {
+				return existing, nil
+			}
+		}
+		return nil, s.databaseError(err)
+	}
+
+	logger.Info("Repayment schedule generated",
+		zap.String("schedule_id", schedule.ID),
+		zap.Time("first_payment_date", schedule.FirstPaymentDate),
+		zap.Float64("payment_amount", schedule.PaymentAmount))
+	return schedule, nil
+}
+
+// GetSchedule retrieves a loan's repayment schedule. A non-empty userID must own the application.
+func (s *RepaymentScheduleService) GetSchedule(ctx context.Context, applicationID, userID string) (*domain.RepaymentSchedule, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "get_repayment_schedule"),
+	)
+
+	if userID != "" {
+		application, err := s.repo.GetApplicationByID(ctx, applicationID)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				return nil, s.notFoundError(applicationID)
+			}
+			logger.Error("Failed to get application", zap.Error(err))
+			return nil, s.databaseError(err)
+		}
+		if application.UserID != userID {
+			logger.Warn("User does not own application", zap.String("user_id", userID))
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_022,
+				Message:     "Unauthorized access",
+				Description: "Repayment schedules can only be viewed by the application's owner",
+				HTTPStatus:  403,
+			}
+		}
+	}
+
+	schedule, err := s.repo.GetRepaymentSchedule(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, s.notFoundError(applicationID)
+		}
+		logger.Error("Failed to get repayment schedule", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	return schedule, nil
+}
+
+// notFoundError is returned when an application has no repayment schedule
+func (s *RepaymentScheduleService) notFoundError(applicationID string) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_010,
+		Message:     "Repayment schedule not found",
+		Description: fmt.Sprintf("No repayment schedule has been generated for application %s", applicationID),
+		HTTPStatus:  404,
+	}
+}
+
+// cannotGenerateError is returned when the loan has not been funded
+func (s *RepaymentScheduleService) cannotGenerateError(description string) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_051,
+		Message:     "Repayment schedule cannot be generated",
+		Description: description,
+		HTTPStatus:  409,
+	}
+}
+
+// databaseError wraps a repository failure
+func (s *RepaymentScheduleService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
@@ -0,0 +1,267 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// ReportDerogatoryInformation sends an approved application back to underwriting when derogatory
+// credit information about the borrower arrives before it is funded. Applications not awaiting
+// funding are left as they are: one still being underwritten is decided on a fresh credit pull,
+// and one already funded is serviced as it stands.
+func (s *LoanService) ReportDerogatoryInformation(ctx context.Context, applicationID string, req *domain.DerogatoryInformationRequest) (*domain.Reunderwriting, error) {
+	application, err := s.GetApplication(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []domain.MaterialChange{req.MaterialChange()}
+	if !application.IsAwaitingFunding() {
+		s.logger.Info("Derogatory information reported for application not awaiting funding",
+			zap.String("application_id", applicationID),
+			zap.String("current_state", string(application.CurrentState)),
+			zap.String("item_type", req.ItemType))
+		return &domain.Reunderwriting{
+			ApplicationID: application.ID,
+			FromState:     application.CurrentState,
+			Changes:       changes,
+			Reason:        fmt.Sprintf("Application is in %s state; only approved applications awaiting funding are re-underwritten", application.CurrentState),
+		}, nil
+	}
+
+	return s.reunderwrite(ctx, application, changes, "derogatory_information")
+}
+
+// reunderwrite invalidates the decision of an approved application that changed materially before
+// funding: it blocks funding, sends the application back to underwriting with any edits made to
+// it, expires its offers and starts the re-underwriting workflow. Funds already sent to the
+// provider cannot be recalled, so an application whose disbursement is in flight is refused.
+func (s *LoanService) reunderwrite(ctx context.Context, application *domain.LoanApplication, changes []domain.MaterialChange, source string) (*domain.Reunderwriting, error) {
+	logger := s.logger.With(
+		zap.String("application_id", application.ID),
+		zap.String("operation", "reunderwrite_application"),
+		zap.String("source", source),
+	)
+
+	disbursement, err := s.repo.GetLatestDisbursement(ctx, application.ID)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		logger.Error("Failed to get disbursement", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+	if disbursement != nil && disbursement.Status != domain.DisbursementPending && disbursement.Status != domain.DisbursementFailed {
+		logger.Warn("Funds already in flight, application cannot be re-underwritten",
+			zap.String("disbursement_id", disbursement.ID),
+			zap.String("disbursement_status", string(disbursement.Status)))
+		return nil, fundsInFlightError(disbursement)
+	}
+
+	now := time.Now().UTC()
+	fromState := application.CurrentState
+	reunderwriting := &domain.Reunderwriting{
+		ApplicationID: application.ID,
+		FromState:     fromState,
+		Changes:       changes,
+	}
+
+	// Leaving the awaiting-funding states is what blocks funding: a funding batch only claims the
+	// disbursements of signed applications
+	application.CurrentState = domain.StateUnderwriting
+	application.Status = domain.StatusUnderReview
+	application.UpdatedAt = now
+	transition := &domain.StateTransition{
+		ID:               uuid.New().String(),
+		ApplicationID:    application.ID,
+		FromState:        &fromState,
+		ToState:          domain.StateUnderwriting,
+		TransitionReason: "Material change before funding: " + materialChangeSummary(changes),
+		Automated:        true,
+		Metadata: map[string]interface{}{
+			"source":  source,
+			"event":   "reunderwriting_started",
+			"changes": changes,
+		},
+		CreatedAt: now,
+	}
+	if err := s.repo.TransitionApplicationState(ctx, application, transition); err != nil {
+		if strings.Contains(err.Error(), "state changed") {
+			logger.Warn("Application state changed during re-underwriting")
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_013,
+				Message:     "State conflict",
+				Description: "The application changed state while it was being sent back to underwriting; please retry",
+				HTTPStatus:  409,
+			}
+		}
+		logger.Error("Failed to transition application", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	// Persist the edits that made the change along with the new state
+	if err := s.repo.UpdateApplication(ctx, application); err != nil {
+		logger.Error("Failed to update application", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Failed to update application",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	reunderwriting.DisbursementCancelled = s.cancelPendingDisbursement(ctx, logger, application.ID,
+		"Cancelled for re-underwriting: "+materialChangeSummary(changes), "reunderwriting")
+	s.expireOpenOffers(ctx, logger, application.ID)
+
+	if s.workflowOrchestrator != nil {
+		execution, err := s.workflowOrchestrator.StartReunderwritingWorkflow(ctx, application, changes)
+		if err != nil {
+			// The application stays in underwriting with funding blocked until it is decided again
+			logger.Error("Failed to start re-underwriting workflow", zap.Error(err))
+		} else {
+			application.WorkflowID = &execution.WorkflowID
+			if err := s.repo.UpdateApplication(ctx, application); err != nil {
+				logger.Error("Failed to update application with workflow ID", zap.Error(err))
+			}
+			if err := s.repo.SaveWorkflowExecution(ctx, &domain.WorkflowExecution{
+				ID:            uuid.New().String(),
+				WorkflowID:    execution.WorkflowID,
+				ApplicationID: application.ID,
+				Status:        execution.Status,
+				Input:         execution.Input,
+				StartTime:     execution.StartTime,
+				CreatedAt:     time.Now().UTC(),
+			}); err != nil {
+				logger.Error("Failed to save workflow execution", zap.Error(err))
+			}
+			reunderwriting.Started = true
+			reunderwriting.WorkflowID = &execution.WorkflowID
+			reunderwriting.StartedAt = &now
+		}
+	}
+
+	if s.notifier != nil {
+		if err := s.notifier.SendNotification(ctx, application.UserID,
+			"We're reviewing your loan again",
+			fmt.Sprintf("Loan application %s changed after it was approved, so we're reviewing it again before it can be funded. We'll let you know the outcome.", application.ApplicationNumber),
+			map[string]interface{}{
+				"action":         "loan_reunderwriting",
+				"application_id": application.ID,
+			},
+		); err != nil {
+			logger.Warn("Failed to send re-underwriting notification", zap.Error(err))
+		}
+	}
+
+	logger.Info("Application sent back to underwriting",
+		zap.String("from_state", string(fromState)),
+		zap.Int("changes", len(changes)),
+		zap.Bool("workflow_started", reunderwriting.Started),
+		zap.Bool("disbursement_cancelled", reunderwriting.DisbursementCancelled))
+
+	return reunderwriting, nil
+}
+
+// cancelPendingDisbursement fails the disbursement waiting for a funding batch, if any, so the
+// loan is not funded on the stale decision; failures are only logged
+func (s *LoanService) cancelPendingDisbursement(ctx context.Context, logger *zap.Logger, applicationID, reason, source string) bool {
+	disbursement, err := s.repo.GetLatestDisbursement(ctx, applicationID)
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			logger.Error("Failed to get disbursement", zap.Error(err))
+		}
+		return false
+	}
+	if disbursement.Status != domain.DisbursementPending {
+		if !disbursement.Status.IsFinal() {
+			// Claimed by a funding batch since the application was checked; completing it cannot
+			// fund the application while it is being underwritten
+			logger.Error("Disbursement claimed by a funding batch during re-underwriting",
+				zap.String("disbursement_id", disbursement.ID),
+				zap.String("disbursement_status", string(disbursement.Status)))
+		}
+		return false
+	}
+
+	now := time.Now().UTC()
+	fromStatus := disbursement.Status
+	disbursement.Status = domain.DisbursementFailed
+	disbursement.FailureReason = &reason
+	disbursement.NextAttemptAt = nil
+	disbursement.UpdatedAt = now
+	if err := s.repo.UpdateDisbursement(ctx, disbursement); err != nil {
+		logger.Error("Failed to cancel disbursement", zap.String("disbursement_id", disbursement.ID), zap.Error(err))
+		return false
+	}
+
+	if err := s.repo.CreateDisbursementEvent(ctx, &domain.DisbursementEvent{
+		ID:             uuid.New().String(),
+		DisbursementID: disbursement.ID,
+		ApplicationID:  applicationID,
+		EventType:      domain.DisbursementEventFailed,
+		FromStatus:     &fromStatus,
+		ToStatus:       disbursement.Status,
+		Detail:         reason,
+		Metadata:       map[string]interface{}{"source": source},
+		CreatedAt:      now,
+	}); err != nil {
+		logger.Warn("Failed to record disbursement event", zap.Error(err))
+	}
+
+	logger.Info("Pending disbursement cancelled", zap.String("disbursement_id", disbursement.ID))
+	return true
+}
+
+// expireOpenOffers expires the offers made on the stale decision, including a selected one, so
+// the borrower chooses from the offers of the new decision; failures are only logged
+func (s *LoanService) expireOpenOffers(ctx context.Context, logger *zap.Logger, applicationID string) {
+	offers, err := s.repo.ListOffers(ctx, applicationID)
+	if err != nil {
+		logger.Error("Failed to list offers", zap.Error(err))
+		return
+	}
+	for _, offer := range offers {
+		if offer.Status != domain.OfferStatusPending && offer.Status != domain.OfferStatusSelected {
+			continue
+		}
+		offer.Status = domain.OfferStatusExpired
+		if err := s.repo.UpdateOffer(ctx, offer); err != nil {
+			logger.Warn("Failed to expire offer", zap.String("offer_id", offer.ID), zap.Error(err))
+		}
+	}
+}
+
+// materialChangeSummary joins the descriptions of the changes
+func materialChangeSummary(changes []domain.MaterialChange) string {
+	descriptions := make([]string, len(changes))
+	for i, change := range changes {
+		descriptions[i] = change.Description
+	}
+	return strings.Join(descriptions, "; ")
+}
+
+// fundsInFlightError is the error for an application whose disbursement has been claimed by a
+// funding batch or sent to the provider
+func fundsInFlightError(disbursement *domain.Disbursement) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_076,
+		Message:     "Funds already in flight",
+		Description: fmt.Sprintf("Disbursement %s is %s and can no longer be stopped; the application cannot be changed", disbursement.ID, disbursement.Status),
+		HTTPStatus:  409,
+	}
+}
@@ -0,0 +1,72 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// ApplicationSearchService looks applications up for the back office by applicant name, email,
+// application number or note text, best match first
+type ApplicationSearchService struct {
+	repo   LoanRepository
+	logger *zap.Logger
+}
+
+// NewApplicationSearchService creates a new application search service
+func NewApplicationSearchService(repo LoanRepository, logger *zap.Logger) *ApplicationSearchService {
+	return &ApplicationSearchService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Search returns one page of the applications matching the query with the matched text highlighted
+func (s *ApplicationSearchService) Search(ctx context.Context, query *domain.ApplicationSearchQuery) (*domain.ApplicationSearchPage, error) {
+	query.ApplyDefaults()
+
+	logger := s.logger.With(
+		zap.String("operation", "search_applications"),
+		zap.Int("page", query.Page),
+		zap.Int("page_size", query.PageSize),
+	)
+
+	validation := query.Validate()
+	if !validation.Valid {
+		logger.Warn("Invalid application search query", zap.Any("errors", validation.Errors))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: fmt.Sprintf("Validation errors: %v", validation.Errors),
+			HTTPStatus:  400,
+		}
+	}
+
+	results, total, err := s.repo.SearchApplications(ctx, query)
+	if err != nil {
+		logger.Error("Failed to search applications", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	return &domain.ApplicationSearchPage{
+		Query:      query.Q,
+		Results:    results,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		Total:      total,
+		TotalPages: (total + query.PageSize - 1) / query.PageSize,
+	}, nil
+}
+
+// databaseError wraps a repository failure
+func (s *ApplicationSearchService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
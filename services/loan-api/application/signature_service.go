@@ -0,0 +1,441 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// ESignProvider sends agreements for electronic signature and reports their progress
+type ESignProvider interface {
+	Name() string
+	SendEnvelope(ctx context.Context, agreement *domain.RenderedDocument, signer domain.Signer) (string, error)
+	DownloadSignedDocument(ctx context.Context, providerEnvelopeID string) ([]byte, error)
+	// ParseWebhook verifies a provider notification and returns the event it reports, or nil for
+	// events that do not change the signature status
+	ParseWebhook(body []byte, header func(string) string) (*domain.SignatureEvent, error)
+}
+
+// SignatureService sends loan agreements for electronic signature once an offer is selected and
+// completes them when the provider reports the borrower has signed
+type SignatureService struct {
+	repo      LoanRepository
+	userRepo  UserRepository
+	provider  ESignProvider
+	renderer  DocumentRenderer
+	documents DocumentStore
+	funding   FundingScheduler
+	notifier  Notifier
+	logger    *zap.Logger
+}
+
+// NewSignatureService creates a new signature service. A nil provider disables e-signature; a nil
+// funding scheduler leaves signed loans to be disbursed manually.
+func NewSignatureService(repo LoanRepository, userRepo UserRepository, provider ESignProvider, renderer DocumentRenderer, documents DocumentStore, funding FundingScheduler, notifier Notifier, logger *zap.Logger) *SignatureService {
+	return &SignatureService{
+		repo:      repo,
+		userRepo:  userRepo,
+		provider:  provider,
+		renderer:  renderer,
+		documents: documents,
+		funding:   funding,
+		notifier:  notifier,
+		logger:    logger,
+	}
+}
+
+// SendAgreement generates the agreement for an approved application's selected offer and sends it
+// to the borrower for signature. An empty offerID sends the offer most recently selected. Sending
+// again while the same offer's agreement is awaiting signature returns the existing envelope.
+func (s *SignatureService) SendAgreement(ctx context.Context, applicationID, offerID string) (*domain.SignatureEnvelope, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "send_agreement"),
+	)
+
+	if s.provider == nil {
+		return nil, s.unavailableError()
+	}
+
+	application, err := s.getApplication(ctx, logger, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if application.CurrentState != domain.StateApproved {
+		logger.Warn("Agreement requested for application that is not approved",
+			zap.String("current_state", string(application.CurrentState)))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_041,
+			Message:     "Agreement cannot be sent",
+			Description: fmt.Sprintf("Application is in %s state; agreements are sent once approved", application.CurrentState),
+			HTTPStatus:  409,
+		}
+	}
+
+	offer, err := s.selectedOffer(ctx, logger, applicationID, offerID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetLatestSignatureEnvelope(ctx, applicationID)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		logger.Error("Failed to get signature envelope", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	if existing != nil && existing.OfferID == offer.ID && !existing.Status.IsFinal() {
+		return existing, nil
+	}
+
+	borrower, err := s.userRepo.GetUserByID(ctx, application.UserID)
+	if err != nil {
+		logger.Error("Failed to get borrower", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	now := time.Now().UTC()
+	agreement, err := s.renderer.Render(domain.DocumentLoanAgreement, &domain.DocumentData{
+		Application: application,
+		Offer:       offer,
+		Borrower:    borrower,
+		Date:        now,
+	})
+	if err != nil {
+		logger.Error("Failed to generate agreement", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Failed to generate agreement",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	providerEnvelopeID, err := s.provider.SendEnvelope(ctx, agreement, domain.Signer{
+		Name:  borrower.FirstName + " " + borrower.LastName,
+		Email: borrower.Email,
+	})
+	if err != nil {
+		logger.Error("Failed to send agreement for signature", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_042,
+			Message:     "E-signature unavailable",
+			Description: err.Error(),
+			HTTPStatus:  502,
+		}
+	}
+
+	envelope := &domain.SignatureEnvelope{
+		ID:                 uuid.New().String(),
+		ApplicationID:      applicationID,
+		OfferID:            offer.ID,
+		Provider:           s.provider.Name(),
+		ProviderEnvelopeID: providerEnvelopeID,
+		Status:             domain.SignatureSent,
+		SentAt:             now,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if err := s.repo.CreateSignatureEnvelope(ctx, envelope); err != nil {
+		logger.Error("Failed to record signature envelope",
+			zap.String("provider_envelope_id", providerEnvelopeID),
+			zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	s.notify(ctx, logger, application.UserID,
+		"Your loan agreement is ready to sign",
+		"We've emailed your loan agreement. Please review and sign it to continue.",
+		"loan_agreement_sent", applicationID)
+
+	logger.Info("Agreement sent for signature",
+		zap.String("envelope_id", envelope.ID),
+		zap.String("offer_id", offer.ID),
+	)
+
+	return envelope, nil
+}
+
+// GetSignature retrieves the agreement most recently sent for an application
+func (s *SignatureService) GetSignature(ctx context.Context, applicationID string) (*domain.SignatureEnvelope, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "get_signature"),
+	)
+
+	envelope, err := s.repo.GetLatestSignatureEnvelope(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Agreement not found",
+				Description: fmt.Sprintf("No agreement has been sent for application %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get signature envelope", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	return envelope, nil
+}
+
+// HandleWebhook applies a provider notification. When the borrower has signed, the signed
+// agreement is stored with their documents and the application moves to documents_signed. Errors
+// are returned so the provider retries the notification.
+func (s *SignatureService) HandleWebhook(ctx context.Context, body []byte, header func(string) string) error {
+	logger := s.logger.With(zap.String("operation", "handle_signature_webhook"))
+
+	if s.provider == nil {
+		return s.unavailableError()
+	}
+
+	event, err := s.provider.ParseWebhook(body, header)
+	if err != nil {
+		logger.Warn("Rejected signature webhook", zap.Error(err))
+		return &domain.LoanError{
+			Code:        domain.LOAN_043,
+			Message:     "Invalid e-signature webhook",
+			Description: err.Error(),
+			HTTPStatus:  401,
+		}
+	}
+	if event == nil {
+		return nil
+	}
+
+	logger = logger.With(
+		zap.String("provider_envelope_id", event.ProviderEnvelopeID),
+		zap.String("status", string(event.Status)),
+	)
+
+	envelope, err := s.repo.GetSignatureEnvelopeByProviderID(ctx, s.provider.Name(), event.ProviderEnvelopeID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			// Not one of ours, or sent from another environment on the same account
+			logger.Warn("Webhook for unknown envelope")
+			return nil
+		}
+		logger.Error("Failed to get signature envelope", zap.Error(err))
+		return s.databaseError(err)
+	}
+
+	if envelope.Status.IsFinal() || envelope.Status == event.Status {
+		return nil
+	}
+
+	if event.Status == domain.SignatureCompleted {
+		return s.completeSignature(ctx, logger, envelope, event)
+	}
+
+	envelope.Status = event.Status
+	envelope.UpdatedAt = time.Now().UTC()
+	if err := s.repo.UpdateSignatureEnvelope(ctx, envelope); err != nil {
+		return s.databaseError(err)
+	}
+
+	logger.Info("Signature envelope updated", zap.String("application_id", envelope.ApplicationID))
+	return nil
+}
+
+// completeSignature stores the signed agreement, moves the application to documents_signed and
+// schedules its disbursement
+func (s *SignatureService) completeSignature(ctx context.Context, logger *zap.Logger, envelope *domain.SignatureEnvelope, event *domain.SignatureEvent) error {
+	logger = logger.With(zap.String("application_id", envelope.ApplicationID))
+
+	application, err := s.getApplication(ctx, logger, envelope.ApplicationID)
+	if err != nil {
+		return err
+	}
+
+	signed, err := s.provider.DownloadSignedDocument(ctx, envelope.ProviderEnvelopeID)
+	if err != nil {
+		logger.Error("Failed to download signed agreement", zap.Error(err))
+		return &domain.LoanError{
+			Code:        domain.LOAN_042,
+			Message:     "E-signature unavailable",
+			Description: err.Error(),
+			HTTPStatus:  502,
+		}
+	}
+
+	if s.documents == nil {
+		return &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Document storage unavailable",
+			Description: "No document store is configured",
+			HTTPStatus:  500,
+		}
+	}
+
+	document, err := s.documents.UploadUserDocument(ctx, application.UserID, &domain.DocumentUpload{
+		DocumentType: domain.DocumentLoanAgreement,
+		FileName:     "loan-agreement-" + application.ApplicationNumber + ".pdf",
+		MimeType:     "application/pdf",
+		Size:         int64(len(signed)),
+		Content:      bytes.NewReader(signed),
+	})
+	if err != nil {
+		logger.Error("Failed to store signed agreement", zap.Error(err))
+		return &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Failed to store signed agreement",
+			Description: err.Error(),
+			HTTPStatus:  502,
+		}
+	}
+
+	now := time.Now().UTC()
+	completedAt := event.OccurredAt
+	if completedAt.IsZero() {
+		completedAt = now
+	}
+	envelope.Status = domain.SignatureCompleted
+	envelope.SignedDocumentID = &document.ID
+	envelope.CompletedAt = &completedAt
+	envelope.UpdatedAt = now
+	if err := s.repo.UpdateSignatureEnvelope(ctx, envelope); err != nil {
+		return s.databaseError(err)
+	}
+
+	if !application.CanTransitionTo(domain.StateDocumentsSigned) {
+		logger.Warn("Signed agreement received for application that cannot move to documents signed",
+			zap.String("current_state", string(application.CurrentState)))
+		return nil
+	}
+
+	fromState := application.CurrentState
+	application.CurrentState = domain.StateDocumentsSigned
+	application.UpdatedAt = now
+	if err := s.repo.UpdateApplication(ctx, application); err != nil {
+		logger.Error("Failed to move application to documents signed", zap.Error(err))
+		return s.databaseError(err)
+	}
+
+	transition := &domain.StateTransition{
+		ID:               uuid.New().String(),
+		ApplicationID:    application.ID,
+		FromState:        &fromState,
+		ToState:          domain.StateDocumentsSigned,
+		TransitionReason: "Loan agreement signed",
+		Automated:        true,
+		Metadata: map[string]interface{}{
+			"source":             "esign_webhook",
+			"envelope_id":        envelope.ID,
+			"signed_document_id": document.ID,
+		},
+		CreatedAt: now,
+	}
+	if err := s.repo.CreateStateTransition(ctx, transition); err != nil {
+		logger.Warn("Failed to create state transition", zap.Error(err))
+	}
+
+	s.notify(ctx, logger, application.UserID,
+		"Your loan agreement is signed",
+		"Thanks for signing. We're preparing your loan for funding.",
+		"loan_agreement_signed", application.ID)
+
+	// Funding can be scheduled again by an operator, so a failure here does not fail the webhook
+	if s.funding != nil {
+		if _, err := s.funding.ScheduleDisbursement(ctx, application.ID); err != nil {
+			logger.Warn("Failed to schedule disbursement", zap.Error(err))
+		}
+	}
+
+	logger.Info("Agreement signed", zap.String("signed_document_id", document.ID))
+	return nil
+}
+
+// selectedOffer finds the offer an agreement is sent for: the given offer, or the one most
+// recently selected. Only selected offers can be signed.
+func (s *SignatureService) selectedOffer(ctx context.Context, logger *zap.Logger, applicationID, offerID string) (*domain.LoanOffer, error) {
+	offers, err := s.repo.ListOffers(ctx, applicationID)
+	if err != nil {
+		logger.Error("Failed to list offers", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+
+	selected := findSelectedOffer(offers, offerID)
+	if selected == nil {
+		logger.Warn("No selected offer to send", zap.String("offer_id", offerID))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_041,
+			Message:     "Agreement cannot be sent",
+			Description: "No offer has been selected for this application",
+			HTTPStatus:  409,
+		}
+	}
+	return selected, nil
+}
+
+// findSelectedOffer returns the given offer if it is selected or, for an empty offerID, the offer
+// most recently selected
+func findSelectedOffer(offers []*domain.LoanOffer, offerID string) *domain.LoanOffer {
+	var selected *domain.LoanOffer
+	for _, offer := range offers {
+		if offer.Status != domain.OfferStatusSelected || (offerID != "" && offer.ID != offerID) {
+			continue
+		}
+		if selected == nil || offer.CreatedAt.After(selected.CreatedAt) {
+			selected = offer
+		}
+	}
+	return selected
+}
+
+// getApplication retrieves an application, translating repository errors
+func (s *SignatureService) getApplication(ctx context.Context, logger *zap.Logger, applicationID string) (*domain.LoanApplication, error) {
+	application, err := s.repo.GetApplicationByID(ctx, applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Application not found",
+				Description: fmt.Sprintf("No application found with ID: %s", applicationID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get application", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	return application, nil
+}
+
+// notify sends the borrower a notification about their agreement; failures are only logged
+func (s *SignatureService) notify(ctx context.Context, logger *zap.Logger, userID, title, message, action, applicationID string) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.SendNotification(ctx, userID, title, message, map[string]interface{}{
+		"action":         action,
+		"application_id": applicationID,
+	}); err != nil {
+		logger.Warn("Failed to notify borrower", zap.String("action", action), zap.Error(err))
+	}
+}
+
+// unavailableError is returned while no e-signature provider is configured
+func (s *SignatureService) unavailableError() error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_042,
+		Message:     "E-signature unavailable",
+		Description: "No e-signature provider is configured",
+		HTTPStatus:  503,
+	}
+}
+
+// databaseError wraps a repository failure
+func (s *SignatureService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
@@ -0,0 +1,205 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// ApplicationWithdrawer withdraws an application on the lender's behalf, e.g. LoanService
+type ApplicationWithdrawer interface {
+	WithdrawApplication(ctx context.Context, id, userID string, req *domain.WithdrawApplicationRequest) (*domain.ApplicationWithdrawal, error)
+}
+
+// SLACheckResult summarizes a single SLA pass
+type SLACheckResult struct {
+	Breaches int `json:"breaches"`
+	Failures int `json:"failures"` // breaches whose escalation action failed
+}
+
+// SLAService times how long applications stay in each state against configured SLAs and escalates
+// the ones that overstay: notifying the borrower or a staff recipient, raising the application's
+// review priority or withdrawing it. Each rule escalates an application once per stay in its state.
+type SLAService struct {
+	repo       LoanRepository
+	notifier   Notifier
+	withdrawer ApplicationWithdrawer
+	rules      []domain.SLARule
+	batchSize  int
+	logger     *zap.Logger
+}
+
+// NewSLAService creates a new SLA service for the given rules; rules with an unknown state or
+// action, or without a duration, are skipped
+func NewSLAService(repo LoanRepository, notifier Notifier, withdrawer ApplicationWithdrawer, rules []domain.SLARule, batchSize int, logger *zap.Logger) *SLAService {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	valid := make([]domain.SLARule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Name == "" || !rule.State.IsValid() || !rule.Action.IsValid() || rule.Within <= 0 {
+			logger.Warn("Skipping invalid SLA rule",
+				zap.String("sla_name", rule.Name),
+				zap.String("state", string(rule.State)),
+				zap.String("action", string(rule.Action)))
+			continue
+		}
+		valid = append(valid, rule)
+	}
+
+	return &SLAService{
+		repo:       repo,
+		notifier:   notifier,
+		withdrawer: withdrawer,
+		rules:      valid,
+		batchSize:  batchSize,
+		logger:     logger,
+	}
+}
+
+// Rules returns the SLA rules being enforced
+func (s *SLAService) Rules() []domain.SLARule {
+	return s.rules
+}
+
+// CheckSLAs escalates up to a batch of overdue applications per rule
+func (s *SLAService) CheckSLAs(ctx context.Context) (*SLACheckResult, error) {
+	result := &SLACheckResult{}
+	now := time.Now().UTC()
+
+	for i := range s.rules {
+		rule := &s.rules[i]
+		candidates, err := s.repo.ListSLACandidates(ctx, rule, now, s.batchSize)
+		if err != nil {
+			return result, err
+		}
+
+		for _, candidate := range candidates {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			s.escalate(ctx, rule, candidate, now, result)
+		}
+	}
+	return result, nil
+}
+
+// escalate records a breach of the rule and takes its action. The breach is recorded first so that
+// instances running the check concurrently escalate an application only once.
+func (s *SLAService) escalate(ctx context.Context, rule *domain.SLARule, candidate *domain.SLACandidate, now time.Time, result *SLACheckResult) {
+	logger := s.logger.With(
+		zap.String("application_id", candidate.ApplicationID),
+		zap.String("sla_name", rule.Name),
+		zap.String("action", string(rule.Action)),
+		zap.String("operation", "escalate_sla"),
+	)
+
+	breach := &domain.SLABreach{
+		ID:             uuid.New().String(),
+		ApplicationID:  candidate.ApplicationID,
+		SLAName:        rule.Name,
+		State:          candidate.State,
+		Action:         rule.Action,
+		StateEnteredAt: candidate.StateEnteredAt,
+		DueAt:          candidate.StateEnteredAt.Add(rule.Within),
+		BreachedAt:     now,
+	}
+	created, err := s.repo.CreateSLABreach(ctx, breach)
+	if err != nil {
+		result.Failures++
+		logger.Warn("Failed to record SLA breach", zap.Error(err))
+		return
+	}
+	if !created {
+		return
+	}
+	result.Breaches++
+
+	if err := s.act(ctx, rule, candidate); err != nil {
+		result.Failures++
+		logger.Warn("SLA escalation failed", zap.Error(err))
+		if err := s.repo.UpdateSLABreachError(ctx, breach.ID, err.Error()); err != nil {
+			logger.Warn("Failed to record SLA escalation failure", zap.Error(err))
+		}
+		return
+	}
+
+	logger.Info("SLA breach escalated", zap.Time("due_at", breach.DueAt))
+}
+
+// act takes the rule's escalation action on the application
+func (s *SLAService) act(ctx context.Context, rule *domain.SLARule, candidate *domain.SLACandidate) error {
+	switch rule.Action {
+	case domain.SLAActionNotify:
+		if s.notifier == nil {
+			return fmt.Errorf("no notifier configured")
+		}
+		recipient := rule.Recipient
+		title, message := "Your loan application needs attention",
+			"Your loan application has been waiting on the next step for a while. Please sign in to see what is needed."
+		if recipient == "" {
+			recipient = candidate.UserID
+		} else {
+			title = fmt.Sprintf("SLA breached: %s", rule.Name)
+			message = fmt.Sprintf("Application %s has been in %s for more than %s.", candidate.ApplicationID, candidate.State, rule.Within)
+		}
+		return s.notifier.SendNotification(ctx, recipient, title, message, map[string]interface{}{
+			"application_id": candidate.ApplicationID,
+			"sla_name":       rule.Name,
+			"state":          candidate.State,
+		})
+	case domain.SLAActionPriorityBump:
+		return s.repo.BumpApplicationPriority(ctx, candidate.ApplicationID)
+	case domain.SLAActionAutoWithdraw:
+		if s.withdrawer == nil {
+			return fmt.Errorf("no withdrawer configured")
+		}
+		_, err := s.withdrawer.WithdrawApplication(ctx, candidate.ApplicationID, domain.WithdrawalActorSystem, &domain.WithdrawApplicationRequest{
+			Reason:    domain.WithdrawalSLABreached,
+			Comment:   fmt.Sprintf("%s SLA breached after %s in %s", rule.Name, rule.Within, candidate.State),
+			Automated: true,
+			Source:    "sla",
+		})
+		return err
+	default:
+		return fmt.Errorf("unknown SLA action: %s", rule.Action)
+	}
+}
+
+// ListBreaches lists SLA breaches, newest first
+func (s *SLAService) ListBreaches(ctx context.Context, query *domain.SLABreachQuery) ([]*domain.SLABreach, error) {
+	if query.Limit <= 0 {
+		query.Limit = 50
+	}
+	if query.State != "" && !query.State.IsValid() {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: fmt.Sprintf("Unknown application state: %s", query.State),
+			HTTPStatus:  400,
+		}
+	}
+
+	breaches, err := s.repo.ListSLABreaches(ctx, query, time.Now().UTC())
+	if err != nil {
+		s.logger.Error("Failed to list SLA breaches", zap.String("operation", "list_sla_breaches"), zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	return breaches, nil
+}
+
+// databaseError wraps a repository failure
+func (s *SLAService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
@@ -0,0 +1,66 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SLAMonitorJob escalates applications that overstay their state's SLAs on a fixed interval
+type SLAMonitorJob struct {
+	sla      *SLAService
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+func NewSLAMonitorJob(
+	sla *SLAService,
+	interval time.Duration,
+	logger *zap.Logger,
+) *SLAMonitorJob {
+	return &SLAMonitorJob{
+		sla:      sla,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Start runs SLA checks on the configured interval until the context is cancelled
+func (j *SLAMonitorJob) Start(ctx context.Context) {
+	if j.interval <= 0 || len(j.sla.Rules()) == 0 {
+		j.logger.Info("SLA monitor job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("SLA monitor job stopped")
+			return
+		case <-ticker.C:
+			if _, err := j.RunOnce(ctx); err != nil {
+				j.logger.Error("SLA check failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RunOnce escalates every application currently past an SLA, up to a batch per rule
+func (j *SLAMonitorJob) RunOnce(ctx context.Context) (*SLACheckResult, error) {
+	result, err := j.sla.CheckSLAs(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	if result.Breaches+result.Failures > 0 {
+		j.logger.Info("SLA check completed",
+			zap.Int("breaches", result.Breaches),
+			zap.Int("failures", result.Failures),
+		)
+	}
+	return result, nil
+}
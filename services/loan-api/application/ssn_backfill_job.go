@@ -0,0 +1,102 @@
+package application
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/tokenization"
+)
+
+// PlaintextSSN is a user whose SSN predates tokenization and is still stored in plaintext
+type PlaintextSSN struct {
+	UserID string
+	SSN    string
+}
+
+// SSNBackfillRepository finds users with plaintext SSNs and replaces them with vault tokens
+type SSNBackfillRepository interface {
+	HasPlaintextSSNColumn(ctx context.Context) (bool, error)
+	ListPlaintextSSNs(ctx context.Context, limit int) ([]PlaintextSSN, error)
+	ReplaceSSNWithToken(ctx context.Context, userID, token, last4 string) error
+}
+
+// SSNBackfillJob tokenizes the SSNs stored in plaintext before users were given SSN tokens. It runs
+// in batches until none are left, after which the out-of-band migration can drop the plaintext
+// column; it refuses to run before. Once the column is gone the job has nothing to do.
+type SSNBackfillJob struct {
+	repo      SSNBackfillRepository
+	tokenizer PIITokenizer
+	batchSize int
+	logger    *zap.Logger
+}
+
+func NewSSNBackfillJob(repo SSNBackfillRepository, tokenizer PIITokenizer, batchSize int, logger *zap.Logger) *SSNBackfillJob {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &SSNBackfillJob{
+		repo:      repo,
+		tokenizer: tokenizer,
+		batchSize: batchSize,
+		logger:    logger,
+	}
+}
+
+// Start tokenizes batches of plaintext SSNs until none are left, a batch fails entirely or the
+// context is cancelled
+func (j *SSNBackfillJob) Start(ctx context.Context) {
+	present, err := j.repo.HasPlaintextSSNColumn(ctx)
+	if err != nil {
+		j.logger.Error("SSN backfill failed to check for the plaintext SSN column", zap.Error(err))
+		return
+	}
+	if !present {
+		j.logger.Debug("Plaintext SSN column dropped; SSN backfill not needed")
+		return
+	}
+
+	total := 0
+	for ctx.Err() == nil {
+		tokenized, remaining, err := j.RunOnce(ctx)
+		total += tokenized
+		if err != nil {
+			j.logger.Error("SSN backfill failed", zap.Error(err), zap.Int("tokenized", total))
+			return
+		}
+		if !remaining {
+			break
+		}
+		if tokenized == 0 {
+			j.logger.Error("SSN backfill made no progress; stopping", zap.Int("tokenized", total))
+			return
+		}
+	}
+	if ctx.Err() == nil {
+		j.logger.Info("SSN backfill completed; the plaintext SSN column can be dropped", zap.Int("tokenized", total))
+	}
+}
+
+// RunOnce tokenizes one batch of plaintext SSNs, reporting how many it tokenized and whether the
+// batch was full, so more may remain
+func (j *SSNBackfillJob) RunOnce(ctx context.Context) (int, bool, error) {
+	users, err := j.repo.ListPlaintextSSNs(ctx, j.batchSize)
+	if err != nil {
+		return 0, false, err
+	}
+
+	tokenized := 0
+	for _, user := range users {
+		token, last4, err := j.tokenizer.Tokenize(ctx, tokenization.TokenTypeSSN, user.SSN)
+		if err != nil {
+			j.logger.Warn("Failed to tokenize SSN", zap.String("user_id", user.UserID), zap.Error(err))
+			continue
+		}
+		if err := j.repo.ReplaceSSNWithToken(ctx, user.UserID, token, last4); err != nil {
+			j.logger.Warn("Failed to store SSN token", zap.String("user_id", user.UserID), zap.Error(err))
+			continue
+		}
+		tokenized++
+	}
+	return tokenized, len(users) == j.batchSize, nil
+}
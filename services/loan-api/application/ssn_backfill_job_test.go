@@ -0,0 +1,75 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// stubSSNBackfillRepository holds plaintext SSNs until they are replaced with tokens
+type stubSSNBackfillRepository struct {
+	columnDropped bool
+	plaintext     []PlaintextSSN
+	tokens        map[string]string
+	listed        int
+}
+
+func (r *stubSSNBackfillRepository) HasPlaintextSSNColumn(ctx context.Context) (bool, error) {
+	return !r.columnDropped, nil
+}
+
+func (r *stubSSNBackfillRepository) ListPlaintextSSNs(ctx context.Context, limit int) ([]PlaintextSSN, error) {
+	r.listed++
+	if r.columnDropped {
+		return nil, fmt.Errorf(`pq: column "ssn" does not exist`)
+	}
+	if len(r.plaintext) < limit {
+		limit = len(r.plaintext)
+	}
+	return append([]PlaintextSSN(nil), r.plaintext[:limit]...), nil
+}
+
+func (r *stubSSNBackfillRepository) ReplaceSSNWithToken(ctx context.Context, userID, token, last4 string) error {
+	for i, user := range r.plaintext {
+		if user.UserID == userID {
+			r.plaintext = append(r.plaintext[:i], r.plaintext[i+1:]...)
+			break
+		}
+	}
+	r.tokens[userID] = token
+	return nil
+}
+
+// stubTokenizer tokenizes every value
+type stubTokenizer struct{}
+
+func (stubTokenizer) Tokenize(ctx context.Context, tokenType, value string) (string, string, error) {
+	return "tok_" + value, value[len(value)-4:], nil
+}
+
+func TestSSNBackfillJob_Start(t *testing.T) {
+	t.Run("tokenizes every plaintext SSN in batches", func(t *testing.T) {
+		repo := &stubSSNBackfillRepository{tokens: make(map[string]string)}
+		for i := 0; i < 5; i++ {
+			repo.plaintext = append(repo.plaintext, PlaintextSSN{UserID: fmt.Sprintf("user-%d", i), SSN: fmt.Sprintf("12345678%d", i)})
+		}
+
+		NewSSNBackfillJob(repo, stubTokenizer{}, 2, zap.NewNop()).Start(context.Background())
+
+		assert.Empty(t, repo.plaintext)
+		assert.Len(t, repo.tokens, 5)
+		assert.Equal(t, "tok_123456780", repo.tokens["user-0"])
+	})
+
+	t.Run("does nothing once the plaintext column is dropped", func(t *testing.T) {
+		repo := &stubSSNBackfillRepository{columnDropped: true, tokens: make(map[string]string)}
+
+		NewSSNBackfillJob(repo, stubTokenizer{}, 2, zap.NewNop()).Start(context.Background())
+
+		assert.Zero(t, repo.listed)
+		assert.Empty(t, repo.tokens)
+	})
+}
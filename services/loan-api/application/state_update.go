@@ -0,0 +1,102 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// UpdateApplicationState moves an application to another state for an internal service, checked
+// against the state machine like any other transition. An application already in the target state
+// is returned unchanged, so callers can retry safely.
+func (s *LoanService) UpdateApplicationState(ctx context.Context, req *domain.StateUpdateRequest) (*domain.LoanApplication, error) {
+	logger := s.logger.With(
+		zap.String("application_id", req.ApplicationID),
+		zap.String("to_state", string(req.ToState)),
+		zap.String("operation", "update_application_state"),
+	)
+
+	if !req.ToState.IsValid() || req.ToState == domain.StateInitiated {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: fmt.Sprintf("Unknown target state: %s", req.ToState),
+			HTTPStatus:  400,
+		}
+	}
+	if req.ToState == domain.StateWithdrawn {
+		// withdrawals also expire offers and stop workflows, so they go through the withdraw endpoint
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: "Applications are withdrawn through the withdraw endpoint",
+			HTTPStatus:  400,
+		}
+	}
+
+	application, err := s.GetApplication(ctx, req.ApplicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromState := application.CurrentState
+	if fromState == req.ToState {
+		return application, nil
+	}
+	if !application.CanTransitionTo(req.ToState) {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_008,
+			Message:     "Invalid state transition",
+			Description: fmt.Sprintf("Invalid state transition from %s to %s", fromState, req.ToState),
+			HTTPStatus:  409,
+		}
+	}
+
+	now := time.Now().UTC()
+	application.CurrentState = req.ToState
+	application.Status = domain.StatusForState(req.ToState, application.Status)
+	application.UpdatedAt = now
+
+	metadata := map[string]interface{}{"source": "internal_rpc"}
+	for key, value := range req.Metadata {
+		metadata[key] = value
+	}
+	transition := &domain.StateTransition{
+		ID:               uuid.New().String(),
+		ApplicationID:    application.ID,
+		FromState:        &fromState,
+		ToState:          req.ToState,
+		TransitionReason: req.Reason,
+		Automated:        req.Automated,
+		Metadata:         metadata,
+		CreatedAt:        now,
+	}
+
+	if err := s.repo.TransitionApplicationState(ctx, application, transition); err != nil {
+		if strings.Contains(err.Error(), "state changed") {
+			logger.Warn("Application state changed during transition")
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_013,
+				Message:     "State conflict",
+				Description: "The application changed state while it was being transitioned",
+				HTTPStatus:  409,
+			}
+		}
+		logger.Error("Failed to transition application", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	logger.Info("Application state updated", zap.String("from_state", string(fromState)))
+	return application, nil
+}
@@ -0,0 +1,196 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// negotiationDescriptions maps negotiation actions to their timeline messages
+var negotiationDescriptions = map[domain.NegotiationAction]string{
+	domain.NegotiationDeclined:         "TIMELINE_OFFER_DECLINED",
+	domain.NegotiationCounterRequested: "TIMELINE_COUNTER_REQUESTED",
+	domain.NegotiationCounterOffered:   "TIMELINE_COUNTER_OFFERED",
+	domain.NegotiationCounterAccepted:  "TIMELINE_COUNTER_ACCEPTED",
+}
+
+// GetTimeline merges an application's state transitions, offers, negotiation, documents, workflow
+// executions and notes into one chronological feed. Borrowers only see notes shared with them and
+// no staff identities. Documents come from the user service; when it is unavailable the timeline
+// is returned without them.
+func (s *LoanService) GetTimeline(ctx context.Context, applicationID string, includeInternal bool) (*domain.ApplicationTimeline, error) {
+	logger := s.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "get_timeline"),
+	)
+
+	application, err := s.GetApplication(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*domain.TimelineEvent
+
+	transitions, err := s.repo.GetStateTransitions(ctx, applicationID)
+	if err != nil {
+		return nil, s.timelineSourceError(logger, "state transitions", err)
+	}
+	for _, transition := range transitions {
+		events = append(events, &domain.TimelineEvent{
+			ID:          transition.ID,
+			Type:        domain.TimelineStateChanged,
+			OccurredAt:  transition.CreatedAt,
+			Actor:       timelineUserActor(application, transition.UserID),
+			Description: s.describe(ctx, "WORKFLOW_"+strings.ToUpper(string(transition.ToState)), nil),
+			Details:     map[string]interface{}{"from_state": transition.FromState, "to_state": transition.ToState, "reason": transition.TransitionReason},
+		})
+	}
+
+	offers, err := s.repo.ListOffers(ctx, applicationID)
+	if err != nil {
+		return nil, s.timelineSourceError(logger, "offers", err)
+	}
+	for _, offer := range offers {
+		events = append(events, &domain.TimelineEvent{
+			ID:         offer.ID,
+			Type:       domain.TimelineOfferCreated,
+			OccurredAt: offer.CreatedAt,
+			Actor:      domain.TimelineActor{Type: domain.TimelineActorSystem},
+			Description: s.describe(ctx, "TIMELINE_OFFER_CREATED", map[string]interface{}{
+				"Amount":     fmt.Sprintf("%.2f", offer.OfferAmount),
+				"TermMonths": offer.TermMonths,
+				"APR":        fmt.Sprintf("%.2f", offer.APR),
+			}),
+			Details: map[string]interface{}{"offer_id": offer.ID, "status": offer.Status, "parent_offer_id": offer.ParentOfferID},
+		})
+	}
+
+	negotiations, err := s.repo.ListNegotiations(ctx, applicationID)
+	if err != nil {
+		return nil, s.timelineSourceError(logger, "negotiation", err)
+	}
+	for _, negotiation := range negotiations {
+		actor := domain.TimelineActor{Type: domain.TimelineActorSystem}
+		if negotiation.Actor == domain.NegotiationActorBorrower {
+			actor = domain.TimelineActor{Type: domain.TimelineActorBorrower, ID: application.UserID}
+		}
+		events = append(events, &domain.TimelineEvent{
+			ID:          negotiation.ID,
+			Type:        domain.TimelineOfferNegotiation,
+			OccurredAt:  negotiation.CreatedAt,
+			Actor:       actor,
+			Description: s.describe(ctx, negotiationDescriptions[negotiation.Action], nil),
+			Details:     map[string]interface{}{"offer_id": negotiation.OfferID, "action": negotiation.Action, "reason": negotiation.Reason},
+		})
+	}
+
+	if s.documents != nil {
+		documents, err := s.documents.ListUserDocuments(ctx, application.UserID)
+		if err != nil {
+			logger.Warn("Failed to list borrower documents", zap.Error(err))
+		}
+		for _, document := range documents {
+			if document.CreatedAt.Before(application.CreatedAt) {
+				continue
+			}
+			events = append(events, &domain.TimelineEvent{
+				ID:          document.ID,
+				Type:        domain.TimelineDocumentUploaded,
+				OccurredAt:  document.CreatedAt,
+				Actor:       domain.TimelineActor{Type: domain.TimelineActorBorrower, ID: application.UserID},
+				Description: s.describe(ctx, "TIMELINE_DOCUMENT_UPLOADED", map[string]interface{}{"DocumentType": document.DocumentType}),
+				Details:     map[string]interface{}{"document_id": document.ID, "document_type": document.DocumentType},
+			})
+		}
+	}
+
+	executions, err := s.repo.ListWorkflowExecutions(ctx, applicationID)
+	if err != nil {
+		return nil, s.timelineSourceError(logger, "workflow executions", err)
+	}
+	for _, execution := range executions {
+		details := map[string]interface{}{"workflow_id": execution.WorkflowID, "status": execution.Status}
+		events = append(events, &domain.TimelineEvent{
+			ID:          execution.ID + ":started",
+			Type:        domain.TimelineWorkflowStarted,
+			OccurredAt:  execution.StartTime,
+			Actor:       domain.TimelineActor{Type: domain.TimelineActorSystem},
+			Description: s.describe(ctx, "TIMELINE_WORKFLOW_STARTED", nil),
+			Details:     details,
+		})
+		if execution.EndTime != nil {
+			events = append(events, &domain.TimelineEvent{
+				ID:          execution.ID + ":completed",
+				Type:        domain.TimelineWorkflowCompleted,
+				OccurredAt:  *execution.EndTime,
+				Actor:       domain.TimelineActor{Type: domain.TimelineActorSystem},
+				Description: s.describe(ctx, "TIMELINE_WORKFLOW_COMPLETED", map[string]interface{}{"Status": execution.Status}),
+				Details:     details,
+			})
+		}
+	}
+
+	notes, err := s.repo.ListNotes(ctx, applicationID, !includeInternal)
+	if err != nil {
+		return nil, s.timelineSourceError(logger, "notes", err)
+	}
+	for _, note := range notes {
+		events = append(events, &domain.TimelineEvent{
+			ID:          note.ID,
+			Type:        domain.TimelineNoteAdded,
+			OccurredAt:  note.CreatedAt,
+			Actor:       domain.TimelineActor{Type: domain.TimelineActorStaff, ID: note.AuthorID, Role: string(note.AuthorRole)},
+			Description: s.describe(ctx, "TIMELINE_NOTE_ADDED_"+strings.ToUpper(string(note.AuthorRole)), nil),
+			Details:     map[string]interface{}{"note_id": note.ID, "body": note.Body, "visibility": note.Visibility, "attachments": note.Attachments},
+		})
+	}
+
+	if !includeInternal {
+		for _, event := range events {
+			if event.Actor.Type == domain.TimelineActorStaff {
+				event.Actor.ID = ""
+			}
+		}
+	}
+
+	domain.SortTimelineEvents(events)
+
+	return &domain.ApplicationTimeline{
+		ApplicationID: applicationID,
+		Events:        events,
+	}, nil
+}
+
+// timelineUserActor attributes an event to the borrower, a staff member or, with no user, the system
+func timelineUserActor(application *domain.LoanApplication, userID *string) domain.TimelineActor {
+	switch {
+	case userID == nil:
+		return domain.TimelineActor{Type: domain.TimelineActorSystem}
+	case *userID == application.UserID:
+		return domain.TimelineActor{Type: domain.TimelineActorBorrower, ID: *userID}
+	default:
+		return domain.TimelineActor{Type: domain.TimelineActorStaff, ID: *userID}
+	}
+}
+
+// describe localizes a timeline message in the request's language
+func (s *LoanService) describe(ctx context.Context, messageID string, templateData map[string]interface{}) string {
+	if s.localizer == nil {
+		return messageID
+	}
+	return s.localizer.Localize(ctx, messageID, templateData)
+}
+
+func (s *LoanService) timelineSourceError(logger *zap.Logger, source string, err error) error {
+	logger.Error("Failed to load timeline source", zap.String("source", source), zap.Error(err))
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
@@ -0,0 +1,472 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// maxWebhookRetryDelay caps the exponential backoff between delivery attempts
+const maxWebhookRetryDelay = 24 * time.Hour
+
+// WebhookPublisher queues events for delivery to partner webhook subscriptions; see WebhookService
+type WebhookPublisher interface {
+	Publish(ctx context.Context, eventType domain.WebhookEventType, applicationID string, data interface{}) error
+}
+
+// WebhookSender posts a signed payload to a subscription's endpoint, e.g. the webhook package's
+// Sender, returning the response status
+type WebhookSender interface {
+	Send(ctx context.Context, subscription *domain.WebhookSubscription, delivery *domain.WebhookDelivery, body []byte) (int, error)
+}
+
+// HostResolver resolves a webhook endpoint's host, e.g. net.DefaultResolver
+type HostResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// WebhookDeliveryPolicy bounds how webhook deliveries are attempted
+type WebhookDeliveryPolicy struct {
+	BatchSize   int           // events fanned out, and deliveries attempted, per pass
+	MaxAttempts int           // attempts of one delivery before it fails
+	RetryDelay  time.Duration // before the first retry; doubled after each further failure
+}
+
+// WebhookDispatchResult summarizes a single delivery pass
+type WebhookDispatchResult struct {
+	EventsDispatched int `json:"events_dispatched"`
+	Delivered        int `json:"delivered"`
+	Retrying         int `json:"retrying"`
+	Failed           int `json:"failed"`
+}
+
+// WebhookService notifies partners embedding the loan flow of application state changes and offer
+// events. Events are queued in an outbox, fanned out to the subscriptions of their type and posted
+// with an HMAC signature, retrying failed deliveries with exponential backoff.
+type WebhookService struct {
+	repo     LoanRepository
+	sender   WebhookSender
+	resolver HostResolver
+	policy   WebhookDeliveryPolicy
+	logger   *zap.Logger
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(repo LoanRepository, sender WebhookSender, policy WebhookDeliveryPolicy, logger *zap.Logger) *WebhookService {
+	if policy.BatchSize <= 0 {
+		policy.BatchSize = 100
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 8
+	}
+	if policy.RetryDelay <= 0 {
+		policy.RetryDelay = time.Minute
+	}
+	return &WebhookService{
+		repo:     repo,
+		sender:   sender,
+		resolver: net.DefaultResolver,
+		policy:   policy,
+		logger:   logger,
+	}
+}
+
+// CreateSubscription registers a partner endpoint. The returned subscription carries the signing
+// secret, which is not shown again.
+func (s *WebhookService) CreateSubscription(ctx context.Context, req *domain.CreateWebhookSubscriptionRequest) (*domain.WebhookSubscription, error) {
+	logger := s.logger.With(
+		zap.String("name", req.Name),
+		zap.String("operation", "create_webhook_subscription"),
+	)
+
+	if validation := req.Validate(); !validation.Valid {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid webhook subscription",
+			Description: fmt.Sprintf("Validation errors: %v", validation.Errors),
+			HTTPStatus:  400,
+		}
+	}
+
+	// Every address the host resolves to must be public. The sender checks again when it dials,
+	// since the DNS answer can change after registration.
+	if !s.resolvesToPublicAddresses(ctx, req.URL) {
+		logger.Warn("Webhook endpoint does not resolve to public addresses")
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid webhook subscription",
+			Description: "The webhook URL must resolve to public addresses only",
+			HTTPStatus:  400,
+		}
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		logger.Error("Failed to generate webhook secret", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Failed to create webhook subscription",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	now := time.Now().UTC()
+	subscription := &domain.WebhookSubscription{
+		ID:         uuid.New().String(),
+		Name:       strings.TrimSpace(req.Name),
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.repo.CreateWebhookSubscription(ctx, subscription); err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	logger.Info("Webhook subscription created", zap.String("subscription_id", subscription.ID))
+	return subscription, nil
+}
+
+// ListSubscriptions lists every webhook subscription without its secret
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	subscriptions, err := s.repo.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		s.logger.Error("Failed to list webhook subscriptions", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	for _, subscription := range subscriptions {
+		subscription.Secret = ""
+	}
+	return subscriptions, nil
+}
+
+// DeactivateSubscription stops a subscription from receiving events. Deliveries already queued are
+// still attempted.
+func (s *WebhookService) DeactivateSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	logger := s.logger.With(
+		zap.String("subscription_id", id),
+		zap.String("operation", "deactivate_webhook_subscription"),
+	)
+
+	subscription, err := s.getSubscription(ctx, logger, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription.Active {
+		subscription.Active = false
+		subscription.UpdatedAt = time.Now().UTC()
+		if err := s.repo.UpdateWebhookSubscription(ctx, subscription); err != nil {
+			return nil, s.databaseError(err)
+		}
+		logger.Info("Webhook subscription deactivated")
+	}
+
+	subscription.Secret = ""
+	return subscription, nil
+}
+
+// ListDeliveries lists a subscription's delivery log, newest first
+func (s *WebhookService) ListDeliveries(ctx context.Context, query *domain.WebhookDeliveryQuery) ([]*domain.WebhookDelivery, error) {
+	logger := s.logger.With(
+		zap.String("subscription_id", query.SubscriptionID),
+		zap.String("operation", "list_webhook_deliveries"),
+	)
+
+	if _, err := s.getSubscription(ctx, logger, query.SubscriptionID); err != nil {
+		return nil, err
+	}
+	if query.Limit <= 0 {
+		query.Limit = 50
+	}
+
+	deliveries, err := s.repo.ListWebhookDeliveries(ctx, query)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+	return deliveries, nil
+}
+
+// Redeliver queues a delivered or failed delivery again, with a fresh set of attempts
+func (s *WebhookService) Redeliver(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error) {
+	logger := s.logger.With(
+		zap.String("delivery_id", deliveryID),
+		zap.String("operation", "redeliver_webhook"),
+	)
+
+	delivery, err := s.repo.GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Webhook delivery not found",
+				Description: fmt.Sprintf("No webhook delivery found with ID: %s", deliveryID),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get webhook delivery", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	if delivery.Status == domain.WebhookDeliveryPending {
+		return delivery, nil
+	}
+
+	now := time.Now().UTC()
+	delivery.Status = domain.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = &now
+	delivery.UpdatedAt = now
+	if err := s.repo.UpdateWebhookDelivery(ctx, delivery); err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	logger.Info("Webhook delivery queued again")
+	return delivery, nil
+}
+
+// Publish queues an event about an application for delivery to the subscriptions of its type
+func (s *WebhookService) Publish(ctx context.Context, eventType domain.WebhookEventType, applicationID string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	return s.repo.CreateWebhookEvent(ctx, &domain.WebhookEvent{
+		ID:            uuid.New().String(),
+		Type:          eventType,
+		ApplicationID: applicationID,
+		Data:          payload,
+		CreatedAt:     time.Now().UTC(),
+	})
+}
+
+// DispatchEvents queues a delivery of each new event to every active subscription of its type
+func (s *WebhookService) DispatchEvents(ctx context.Context) (int, error) {
+	events, err := s.repo.ListUndispatchedWebhookEvents(ctx, s.policy.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	subscriptions, err := s.repo.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	dispatched := 0
+	for _, event := range events {
+		now := time.Now().UTC()
+		queued := true
+		for _, subscription := range subscriptions {
+			if !subscription.Subscribes(event.Type) {
+				continue
+			}
+			delivery := &domain.WebhookDelivery{
+				ID:             uuid.New().String(),
+				SubscriptionID: subscription.ID,
+				EventID:        event.ID,
+				EventType:      event.Type,
+				ApplicationID:  event.ApplicationID,
+				Status:         domain.WebhookDeliveryPending,
+				NextAttemptAt:  &now,
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			}
+			if err := s.repo.CreateWebhookDelivery(ctx, delivery); err != nil {
+				s.logger.Warn("Failed to queue webhook delivery", zap.Error(err),
+					zap.String("event_id", event.ID), zap.String("subscription_id", subscription.ID))
+				queued = false
+			}
+		}
+
+		// An event with a delivery that failed to queue is fanned out again on the next pass
+		if !queued {
+			continue
+		}
+		if err := s.repo.MarkWebhookEventDispatched(ctx, event.ID, now); err != nil {
+			s.logger.Warn("Failed to mark webhook event dispatched", zap.Error(err), zap.String("event_id", event.ID))
+			continue
+		}
+		dispatched++
+	}
+	return dispatched, nil
+}
+
+// DeliverDue attempts the deliveries whose next attempt is due
+func (s *WebhookService) DeliverDue(ctx context.Context, result *WebhookDispatchResult) error {
+	deliveries, err := s.repo.ListDueWebhookDeliveries(ctx, time.Now().UTC(), s.policy.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	subscriptions := make(map[string]*domain.WebhookSubscription)
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = s.repo.GetWebhookSubscription(ctx, delivery.SubscriptionID)
+			if err != nil {
+				s.logger.Warn("Failed to get webhook subscription", zap.Error(err), zap.String("delivery_id", delivery.ID))
+				continue
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		switch s.deliver(ctx, subscription, delivery) {
+		case domain.WebhookDeliveryDelivered:
+			result.Delivered++
+		case domain.WebhookDeliveryFailed:
+			result.Failed++
+		default:
+			result.Retrying++
+		}
+	}
+	return nil
+}
+
+// deliver makes one attempt of a delivery, records its outcome and returns the delivery's status
+func (s *WebhookService) deliver(ctx context.Context, subscription *domain.WebhookSubscription, delivery *domain.WebhookDelivery) domain.WebhookDeliveryStatus {
+	logger := s.logger.With(
+		zap.String("delivery_id", delivery.ID),
+		zap.String("subscription_id", subscription.ID),
+		zap.String("event_type", string(delivery.EventType)),
+	)
+
+	event, err := s.repo.GetWebhookEvent(ctx, delivery.EventID)
+	if err != nil {
+		logger.Warn("Failed to get webhook event", zap.Error(err))
+		return delivery.Status
+	}
+	body, err := json.Marshal(&domain.WebhookPayload{
+		ID:            event.ID,
+		Type:          event.Type,
+		ApplicationID: event.ApplicationID,
+		CreatedAt:     event.CreatedAt,
+		Data:          event.Data,
+	})
+	if err != nil {
+		logger.Error("Failed to encode webhook payload", zap.Error(err))
+		return delivery.Status
+	}
+
+	status, sendErr := s.sender.Send(ctx, subscription, delivery, body)
+
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.UpdatedAt = now
+	delivery.ResponseStatus = nil
+	if status != 0 {
+		delivery.ResponseStatus = &status
+	}
+	switch {
+	case sendErr == nil:
+		delivery.Status = domain.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = nil
+	case delivery.Attempts >= s.policy.MaxAttempts:
+		message := sendErr.Error()
+		delivery.Status = domain.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = &message
+		logger.Warn("Webhook delivery failed, out of attempts", zap.Int("attempts", delivery.Attempts), zap.Error(sendErr))
+	default:
+		message := sendErr.Error()
+		next := now.Add(s.retryDelay(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		delivery.LastError = &message
+		logger.Info("Webhook delivery failed, retrying",
+			zap.Int("attempts", delivery.Attempts), zap.Time("next_attempt_at", next), zap.Error(sendErr))
+	}
+
+	if err := s.repo.UpdateWebhookDelivery(ctx, delivery); err != nil {
+		logger.Error("Failed to record webhook delivery attempt", zap.Error(err))
+	}
+	return delivery.Status
+}
+
+// resolvesToPublicAddresses reports whether the endpoint's host resolves, and only to public addresses
+func (s *WebhookService) resolvesToPublicAddresses(ctx context.Context, endpointURL string) bool {
+	parsed, err := url.Parse(endpointURL)
+	if err != nil {
+		return false
+	}
+	addrs, err := s.resolver.LookupIPAddr(ctx, parsed.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return false
+	}
+	for _, addr := range addrs {
+		if !domain.IsPublicWebhookAddress(addr.IP) {
+			return false
+		}
+	}
+	return true
+}
+
+// retryDelay is the backoff after the given number of failed attempts
+func (s *WebhookService) retryDelay(attempts int) time.Duration {
+	delay := s.policy.RetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxWebhookRetryDelay {
+			return maxWebhookRetryDelay
+		}
+	}
+	return delay
+}
+
+// getSubscription retrieves a webhook subscription, translating repository errors
+func (s *WebhookService) getSubscription(ctx context.Context, logger *zap.Logger, id string) (*domain.WebhookSubscription, error) {
+	subscription, err := s.repo.GetWebhookSubscription(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &domain.LoanError{
+				Code:        domain.LOAN_010,
+				Message:     "Webhook subscription not found",
+				Description: fmt.Sprintf("No webhook subscription found with ID: %s", id),
+				HTTPStatus:  404,
+			}
+		}
+		logger.Error("Failed to get webhook subscription", zap.Error(err))
+		return nil, s.databaseError(err)
+	}
+	return subscription, nil
+}
+
+// databaseError wraps a repository failure
+func (s *WebhookService) databaseError(err error) error {
+	return &domain.LoanError{
+		Code:        domain.LOAN_023,
+		Message:     "Database error",
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
+
+// generateWebhookSecret returns a random HMAC key for a subscription
+func generateWebhookSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(key), nil
+}
@@ -0,0 +1,76 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// WebhookDispatchJob fans new webhook events out to their subscriptions and attempts the deliveries
+// that are due, including retries, on a fixed interval
+type WebhookDispatchJob struct {
+	webhooks *WebhookService
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+func NewWebhookDispatchJob(
+	webhooks *WebhookService,
+	interval time.Duration,
+	logger *zap.Logger,
+) *WebhookDispatchJob {
+	return &WebhookDispatchJob{
+		webhooks: webhooks,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Start runs delivery passes on the configured interval until the context is cancelled
+func (j *WebhookDispatchJob) Start(ctx context.Context) {
+	if j.interval <= 0 {
+		j.logger.Info("Webhook dispatch job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Webhook dispatch job stopped")
+			return
+		case <-ticker.C:
+			if _, err := j.RunOnce(ctx); err != nil {
+				j.logger.Error("Webhook dispatch pass failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RunOnce queues deliveries of new events and attempts every delivery due now
+func (j *WebhookDispatchJob) RunOnce(ctx context.Context) (*WebhookDispatchResult, error) {
+	result := &WebhookDispatchResult{}
+
+	dispatched, err := j.webhooks.DispatchEvents(ctx)
+	if err != nil {
+		return result, err
+	}
+	result.EventsDispatched = dispatched
+
+	if err := j.webhooks.DeliverDue(ctx, result); err != nil {
+		return result, err
+	}
+
+	if result.EventsDispatched+result.Delivered+result.Retrying+result.Failed > 0 {
+		j.logger.Info("Webhook dispatch pass completed",
+			zap.Int("events_dispatched", result.EventsDispatched),
+			zap.Int("delivered", result.Delivered),
+			zap.Int("retrying", result.Retrying),
+			zap.Int("failed", result.Failed),
+		)
+	}
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package application
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// stubWebhookRepository records the subscriptions it is asked to create
+type stubWebhookRepository struct {
+	LoanRepository
+	created []*domain.WebhookSubscription
+}
+
+func (r *stubWebhookRepository) CreateWebhookSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	r.created = append(r.created, subscription)
+	return nil
+}
+
+// stubResolver resolves every host to the addresses it holds
+type stubResolver struct {
+	addrs []string
+}
+
+func (r *stubResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	addrs := make([]net.IPAddr, 0, len(r.addrs))
+	for _, addr := range r.addrs {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(addr)})
+	}
+	return addrs, nil
+}
+
+func TestWebhookService_CreateSubscriptionChecksResolvedAddresses(t *testing.T) {
+	tests := []struct {
+		name    string
+		addrs   []string
+		allowed bool
+	}{
+		{name: "public addresses", addrs: []string{"203.0.113.10", "2001:db8::10"}, allowed: true},
+		{name: "host resolves to a private address", addrs: []string{"10.0.0.5"}, allowed: false},
+		{name: "host resolves to the metadata address", addrs: []string{"169.254.169.254"}, allowed: false},
+		{name: "one of the addresses is loopback", addrs: []string{"203.0.113.10", "127.0.0.1"}, allowed: false},
+		{name: "host does not resolve", addrs: nil, allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubWebhookRepository{}
+			service := NewWebhookService(repo, nil, WebhookDeliveryPolicy{}, zap.NewNop())
+			service.resolver = &stubResolver{addrs: tt.addrs}
+
+			subscription, err := service.CreateSubscription(context.Background(), &domain.CreateWebhookSubscriptionRequest{
+				Name: "Partner",
+				URL:  "https://partner.example.com/hooks",
+			})
+			if tt.allowed {
+				require.NoError(t, err)
+				assert.Len(t, repo.created, 1)
+				assert.Equal(t, subscription.ID, repo.created[0].ID)
+				return
+			}
+
+			var loanErr *domain.LoanError
+			require.ErrorAs(t, err, &loanErr)
+			assert.Equal(t, domain.LOAN_020, loanErr.Code)
+			assert.Empty(t, repo.created)
+		})
+	}
+}
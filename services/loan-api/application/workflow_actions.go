@@ -0,0 +1,164 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// PauseWorkflow pauses a running workflow in Conductor and records who paused it and why
+func (s *LoanService) PauseWorkflow(ctx context.Context, workflowID, performedBy, reason string) (*domain.WorkflowAction, error) {
+	return s.performWorkflowAction(ctx, workflowID, domain.WorkflowActionPause, performedBy, reason, func() error {
+		return s.workflowOrchestrator.PauseWorkflow(ctx, workflowID)
+	})
+}
+
+// ResumeWorkflow resumes a paused workflow in Conductor and records who resumed it
+func (s *LoanService) ResumeWorkflow(ctx context.Context, workflowID, performedBy, reason string) (*domain.WorkflowAction, error) {
+	return s.performWorkflowAction(ctx, workflowID, domain.WorkflowActionResume, performedBy, reason, func() error {
+		return s.workflowOrchestrator.ResumeWorkflow(ctx, workflowID)
+	})
+}
+
+// TerminateWorkflow terminates a workflow in Conductor, records who terminated it and why, and
+// reconciles the application the workflow was driving
+func (s *LoanService) TerminateWorkflow(ctx context.Context, workflowID, performedBy, reason string) (*domain.WorkflowAction, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Invalid request format",
+			Description: "A reason is required to terminate a workflow",
+			HTTPStatus:  400,
+		}
+	}
+
+	action, err := s.performWorkflowAction(ctx, workflowID, domain.WorkflowActionTerminate, performedBy, reason, func() error {
+		return s.workflowOrchestrator.TerminateWorkflow(ctx, workflowID, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if action.ApplicationID != nil {
+		s.reconcileTerminatedWorkflow(ctx, action)
+	}
+	return action, nil
+}
+
+// performWorkflowAction runs an operator action against Conductor and persists it
+func (s *LoanService) performWorkflowAction(ctx context.Context, workflowID string, actionType domain.WorkflowActionType, performedBy, reason string, run func() error) (*domain.WorkflowAction, error) {
+	logger := s.logger.With(
+		zap.String("workflow_id", workflowID),
+		zap.String("performed_by", performedBy),
+		zap.String("operation", string(actionType)+"_workflow"),
+	)
+
+	if s.workflowOrchestrator == nil {
+		logger.Error("Workflow orchestrator not configured")
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_014,
+			Message:     "Workflow engine unavailable",
+			Description: "Workflows cannot be changed because the workflow engine is not configured",
+			HTTPStatus:  503,
+		}
+	}
+
+	applicationID, err := s.repo.GetApplicationIDByWorkflowID(ctx, workflowID)
+	if err != nil {
+		logger.Error("Failed to look up workflow application", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	if err := run(); err != nil {
+		return nil, err
+	}
+
+	action := &domain.WorkflowAction{
+		ID:          uuid.New().String(),
+		WorkflowID:  workflowID,
+		Action:      actionType,
+		Reason:      strings.TrimSpace(reason),
+		PerformedBy: performedBy,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if applicationID != "" {
+		action.ApplicationID = &applicationID
+	}
+
+	if err := s.repo.RecordWorkflowAction(ctx, action); err != nil {
+		// Conductor has already acted, so the caller must know the audit record is missing
+		logger.Error("Failed to record workflow action", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_023,
+			Message:     "Database error",
+			Description: fmt.Sprintf("Workflow %s succeeded but could not be recorded: %v", actionType, err),
+			HTTPStatus:  500,
+		}
+	}
+
+	logger.Info("Workflow action performed", zap.String("action_id", action.ID))
+	return action, nil
+}
+
+// reconcileTerminatedWorkflow moves the application out of a state only the terminated workflow
+// could have advanced. Failures are logged and reported on the action rather than failing the
+// termination, which has already happened.
+func (s *LoanService) reconcileTerminatedWorkflow(ctx context.Context, action *domain.WorkflowAction) {
+	logger := s.logger.With(
+		zap.String("workflow_id", action.WorkflowID),
+		zap.String("application_id", *action.ApplicationID),
+		zap.String("operation", "reconcile_terminated_workflow"),
+	)
+
+	application, err := s.repo.GetApplicationByID(ctx, *action.ApplicationID)
+	if err != nil {
+		logger.Error("Failed to get application", zap.Error(err))
+		return
+	}
+
+	fromState := application.CurrentState
+	action.ApplicationState = &fromState
+
+	toState, ok := application.StateAfterWorkflowTermination()
+	if !ok {
+		return
+	}
+
+	now := time.Now().UTC()
+	application.CurrentState = toState
+	application.Status = domain.StatusForState(toState, application.Status)
+	application.UpdatedAt = now
+
+	transition := &domain.StateTransition{
+		ID:               uuid.New().String(),
+		ApplicationID:    application.ID,
+		FromState:        &fromState,
+		ToState:          toState,
+		TransitionReason: fmt.Sprintf("Workflow terminated: %s", action.Reason),
+		UserID:           &action.PerformedBy,
+		Metadata:         map[string]interface{}{"source": "workflow_termination", "workflow_id": action.WorkflowID},
+		CreatedAt:        now,
+	}
+
+	if err := s.repo.TransitionApplicationState(ctx, application, transition); err != nil {
+		logger.Error("Failed to reconcile application state", zap.Error(err))
+		return
+	}
+
+	action.ApplicationState = &toState
+	action.StateReconciled = true
+	logger.Info("Application state reconciled",
+		zap.String("from_state", string(fromState)),
+		zap.String("to_state", string(toState)))
+}
@@ -0,0 +1,88 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/workflow"
+)
+
+// GetWorkflowStatus returns a workflow execution's state, current task, failure reason and task
+// history as reported by Conductor
+func (s *LoanService) GetWorkflowStatus(ctx context.Context, workflowID string) (*domain.WorkflowStatusDetail, error) {
+	logger := s.logger.With(
+		zap.String("workflow_id", workflowID),
+		zap.String("operation", "get_workflow_status"),
+	)
+
+	if s.workflowOrchestrator == nil {
+		logger.Error("Workflow orchestrator not configured")
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_014,
+			Message:     "Workflow engine unavailable",
+			Description: "Workflow status cannot be retrieved because the workflow engine is not configured",
+			HTTPStatus:  503,
+		}
+	}
+
+	status, err := s.workflowOrchestrator.GetWorkflowStatus(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toWorkflowStatusDetail(status), nil
+}
+
+// toWorkflowStatusDetail maps Conductor's execution to the API's workflow status schema
+func toWorkflowStatusDetail(status *workflow.WorkflowStatus) *domain.WorkflowStatusDetail {
+	detail := &domain.WorkflowStatusDetail{
+		WorkflowID:    status.WorkflowID,
+		Name:          status.WorkflowName,
+		Version:       status.WorkflowVersion,
+		ApplicationID: status.CorrelationID,
+		Status:        domain.NormalizeWorkflowStatus(status.Status),
+		FailureReason: status.ReasonForIncompletion,
+		StartedAt:     optionalTime(status.StartTime),
+		EndedAt:       status.EndTime,
+		Tasks:         make([]domain.WorkflowTaskDetail, 0, len(status.Tasks)),
+	}
+	if detail.ApplicationID == "" {
+		detail.ApplicationID, _ = status.Input["applicationId"].(string)
+	}
+
+	for _, task := range status.Tasks {
+		taskDetail := domain.WorkflowTaskDetail{
+			TaskID:        task.TaskID,
+			Name:          task.ReferenceTaskName,
+			Type:          task.TaskType,
+			Status:        domain.NormalizeWorkflowStatus(task.Status),
+			Attempt:       task.RetryCount + 1,
+			FailureReason: task.ReasonForIncompletion,
+			ScheduledAt:   task.ScheduledTime,
+			StartedAt:     optionalTime(task.StartTime),
+			EndedAt:       task.EndTime,
+		}
+		detail.Tasks = append(detail.Tasks, taskDetail)
+
+		if domain.IsActiveTaskStatus(taskDetail.Status) {
+			current := taskDetail
+			detail.CurrentTask = &current
+		}
+		if detail.FailureReason == "" && taskDetail.FailureReason != "" && detail.Status == domain.WorkflowStateFailed {
+			detail.FailureReason = taskDetail.FailureReason
+		}
+	}
+
+	return detail
+}
+
+// optionalTime returns nil for the zero time so unset timestamps are omitted from responses
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -3,24 +3,49 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/huuhoait/los-demo/services/loan-api/application"
 	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/addressverification"
 	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/database/postgres"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/decisions"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/disbursement"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/docgen"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/documents"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/esign"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/identity"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/notification"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/plaid"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/tokenization"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/valuation"
+	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/webhook"
 	"github.com/huuhoait/los-demo/services/loan-api/infrastructure/workflow"
 	"github.com/huuhoait/los-demo/services/loan-api/interfaces"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/graph"
 	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+	"github.com/huuhoait/los-demo/services/shared/pkg/address"
+	"github.com/huuhoait/los-demo/services/shared/pkg/cache"
 	"github.com/huuhoait/los-demo/services/shared/pkg/config"
 	"github.com/huuhoait/los-demo/services/shared/pkg/i18n"
+	sharedMiddleware "github.com/huuhoait/los-demo/services/shared/pkg/middleware"
+	"github.com/huuhoait/los-demo/services/shared/pkg/payroll"
+	"github.com/huuhoait/los-demo/services/shared/pkg/rpc"
+	loanv1 "github.com/huuhoait/los-demo/services/shared/proto/loan/v1"
 )
 
 func main() {
@@ -75,28 +100,366 @@ func main() {
 	// Initialize repositories
 	var userRepo application.UserRepository
 	var loanRepo application.LoanRepository
+	var pricingRepo application.PricingRepository
+	var ssnBackfillRepo application.SSNBackfillRepository
 	if dbConnection != nil {
 		factory := postgres.NewFactory(dbConnection, logger)
 		userRepo = factory.GetUserRepository()
+		ssnBackfillRepo = factory.GetSSNBackfillRepository()
 		loanRepo = factory.GetLoanRepository()
+		pricingRepo = factory.GetPricingRepository()
 	} else {
 		// Use mock repositories for now
 		userRepo = &MockUserRepository{}
 		loanRepo = &MockLoanRepository{}
+		pricingRepo = &MockPricingRepository{}
 	}
 
 	// Initialize workflow orchestrator
 	conductorClient := workflow.NewConductorClientImpl(cfg.Conductor.BaseURL, logger)
 	workflowOrchestrator := workflow.NewLoanWorkflowOrchestrator(conductorClient, logger, localizer)
 
+	// Initialize SSN and bank account number tokenization against the user service token vault
+	tokenizer := tokenization.NewClient(
+		cfg.Services.UserService.BaseURL,
+		cfg.Services.UserService.ServiceToken,
+		time.Duration(cfg.Services.UserService.Timeout)*time.Second,
+		logger,
+	)
+
+	// Initialize identity verification lookups against the user service
+	identityChecker := identity.NewClient(
+		cfg.Services.UserService.BaseURL,
+		cfg.Services.UserService.ServiceToken,
+		time.Duration(cfg.Services.UserService.Timeout)*time.Second,
+		logger,
+	)
+
+	// Initialize lookups of underwriting decisions in the decision engine, over gRPC when its
+	// DecisionService is configured
+	var decisionSource application.DecisionSource = decisions.NewClient(
+		cfg.Services.DecisionEngine.BaseURL,
+		cfg.Services.DecisionEngine.ServiceToken,
+		time.Duration(cfg.Services.DecisionEngine.Timeout)*time.Second,
+		logger,
+	)
+	if target := cfg.Services.DecisionEngine.GRPCTarget; target != "" {
+		decisionConn, err := rpc.Dial(target, cfg.GRPC, logger)
+		if err != nil {
+			logger.Fatal("Failed to connect to the decision engine", zap.Error(err))
+		}
+		defer decisionConn.Close()
+		decisionSource = decisions.NewGRPCClient(decisionConn, logger)
+	}
+
+	// Initialize borrower notifications through the user service
+	notifier := notification.NewClient(
+		cfg.Services.UserService.BaseURL,
+		cfg.Services.UserService.ServiceToken,
+		time.Duration(cfg.Services.UserService.Timeout)*time.Second,
+		logger,
+	)
+
+	// Initialize borrower document storage in the user service
+	documentStore := documents.NewClient(
+		cfg.Services.UserService.BaseURL,
+		cfg.Services.UserService.ServiceToken,
+		time.Duration(cfg.Services.UserService.Timeout)*time.Second,
+		logger,
+	)
+
+	// Initialize rendering of loan agreements and disclosures
+	documentRenderer := docgen.NewRenderer(cfg.Application.LenderName, logger)
+
+	// Initialize electronic signature of loan agreements; disabled unless a provider is configured
+	var esignProvider application.ESignProvider
+	if strings.EqualFold(cfg.ESign.Provider, esign.ProviderDocuSign) {
+		esignProvider = esign.NewDocuSignClient(
+			cfg.ESign.BaseURL,
+			cfg.ESign.AccountID,
+			cfg.ESign.AccessToken,
+			cfg.ESign.WebhookSecret,
+			time.Duration(cfg.ESign.Timeout)*time.Second,
+			logger,
+		)
+	} else {
+		logger.Info("E-signature disabled; loan agreements will not be sent for signature")
+	}
+
+	// Initialize ACH disbursement of signed loans; disabled unless a provider is configured
+	// Dwolla also verifies bank accounts added by number with micro-deposits
+	var disbursementProvider application.DisbursementProvider
+	var microDepositVerifier application.MicroDepositVerifier
+	if strings.EqualFold(cfg.Disbursement.Provider, disbursement.ProviderDwolla) {
+		dwollaClient := disbursement.NewDwollaClient(
+			cfg.Disbursement.BaseURL,
+			cfg.Disbursement.APIKey,
+			cfg.Disbursement.APISecret,
+			cfg.Disbursement.SourceAccount,
+			cfg.Disbursement.WebhookSecret,
+			time.Duration(cfg.Disbursement.Timeout)*time.Second,
+			logger,
+		)
+		disbursementProvider = dwollaClient
+		microDepositVerifier = dwollaClient
+	} else {
+		logger.Info("Disbursement disabled; signed loans will not be funded")
+	}
+
+	// Initialize bank account linking with Plaid Auth; disabled unless credentials are configured
+	var accountLinkProvider application.AccountLinkProvider
+	if cfg.Plaid.ClientID != "" {
+		accountLinkProvider = plaid.NewClient(
+			cfg.Plaid.BaseURL,
+			cfg.Plaid.ClientID,
+			cfg.Plaid.Secret,
+			cfg.Plaid.ClientName,
+			time.Duration(cfg.Plaid.Timeout)*time.Second,
+			logger,
+		)
+	} else {
+		logger.Info("Plaid disabled; bank accounts can only be verified with micro-deposits")
+	}
+
+	// Initialize address verification
+	addressVerifier := addressverification.NewVerifier(address.NewVerifier(cfg.AddressVerification, logger), logger)
+
 	// Initialize services
-	loanService := application.NewLoanService(userRepo, loanRepo, workflowOrchestrator, logger, localizer)
+	pricingService := application.NewPricingService(pricingRepo, decisionSource, logger)
+	repaymentService := application.NewRepaymentScheduleService(loanRepo, logger)
+	disbursementService := application.NewDisbursementService(
+		loanRepo,
+		userRepo,
+		disbursementProvider,
+		tokenizer,
+		repaymentService,
+		notifier,
+		cfg.Disbursement.BatchSize,
+		cfg.Disbursement.MaxAttempts,
+		time.Duration(cfg.Disbursement.RetryDelay)*time.Minute,
+		logger,
+	)
+	bankAccountService := application.NewBankAccountService(loanRepo, userRepo, tokenizer, accountLinkProvider, microDepositVerifier, logger)
+	var fundingScheduler application.FundingScheduler
+	if disbursementProvider != nil {
+		fundingScheduler = disbursementService
+	}
+	signatureService := application.NewSignatureService(loanRepo, userRepo, esignProvider, documentRenderer, documentStore, fundingScheduler, notifier, logger)
+	documentService := application.NewDocumentGenerationService(loanRepo, userRepo, documentRenderer, documentStore, notifier, logger)
+	preQualificationPolicy := application.PreQualificationPolicy{
+		MinLoanAmount:    cfg.Application.MinLoanAmount,
+		MaxLoanAmount:    cfg.Application.MaxLoanAmount,
+		MaxDTIRatio:      cfg.Application.MaxDTIRatio,
+		BaseInterestRate: cfg.Application.DefaultInterestRate,
+		MinInterestRate:  cfg.Application.MinInterestRate,
+		MaxInterestRate:  cfg.Application.MaxInterestRate,
+		TTL:              time.Duration(cfg.Application.PreQualificationTTLHours) * time.Hour,
+		DTI:              cfg.DTI,
+	}
+	preQualificationService := application.NewPreQualificationService(loanRepo, userRepo, preQualificationPolicy, logger)
+	calculatorService := application.NewCalculatorService(preQualificationPolicy, logger)
+	rateLockWindows := make(map[string]time.Duration, len(cfg.RateLock.ProductWindowHours))
+	for product, hours := range cfg.RateLock.ProductWindowHours {
+		rateLockWindows[product] = time.Duration(hours) * time.Hour
+	}
+	rateLockService := application.NewRateLockService(loanRepo, pricingService, application.RateLockPolicy{
+		Window:         time.Duration(cfg.RateLock.WindowHours) * time.Hour,
+		ProductWindows: rateLockWindows,
+	}, logger)
+	refinanceService := application.NewRefinanceService(loanRepo, logger)
+
+	creditConsentService := application.NewCreditConsentService(loanRepo, cfg.Application.CreditConsentDisclosureVersion, logger)
+	demographicsService := application.NewDemographicsService(loanRepo, logger)
+	duplicateService := application.NewDuplicateDetectionService(loanRepo, time.Duration(cfg.Application.DuplicateWindowDays)*24*time.Hour, logger)
+	searchService := application.NewApplicationSearchService(loanRepo, logger)
+
+	// Value collateral with the configured provider; without one it is valued at its stated value
+	collateralService := application.NewCollateralService(loanRepo, logger)
+	if cfg.Valuation.BaseURL != "" {
+		valuationClient := valuation.NewClient(
+			cfg.Valuation.BaseURL,
+			cfg.Valuation.APIKey,
+			cfg.Valuation.Source,
+			time.Duration(cfg.Valuation.Timeout)*time.Second,
+			logger,
+		)
+		for _, collateralType := range cfg.Valuation.CollateralTypes {
+			collateralService.RegisterValuationProvider(domain.CollateralType(collateralType), valuationClient)
+		}
+	} else {
+		logger.Info("Collateral valuation disabled; collateral is valued at the borrower's stated value")
+	}
+
+	// Notify partner webhook subscriptions of application state changes and offer events
+	webhookService := application.NewWebhookService(
+		loanRepo,
+		webhook.NewSender(time.Duration(cfg.Webhooks.Timeout)*time.Second, logger),
+		application.WebhookDeliveryPolicy{
+			BatchSize:   cfg.Webhooks.BatchSize,
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+			RetryDelay:  time.Duration(cfg.Webhooks.RetryDelay) * time.Second,
+		},
+		logger,
+	)
+
+	loanService := application.NewLoanService(userRepo, loanRepo, tokenizer, addressVerifier, identityChecker, pricingService, notifier, documentStore, signatureService, documentService, rateLockService, collateralService, duplicateService, webhookService, workflowOrchestrator, logger, localizer)
+
+	// Escalate applications that overstay their state's SLA
+	slaRules := make([]domain.SLARule, 0, len(cfg.SLA.Rules))
+	for _, rule := range cfg.SLA.Rules {
+		slaRules = append(slaRules, domain.SLARule{
+			Name:      rule.Name,
+			State:     domain.ApplicationState(rule.State),
+			Within:    time.Duration(rule.WithinHours) * time.Hour,
+			Action:    domain.SLAAction(rule.Action),
+			Recipient: rule.Recipient,
+		})
+	}
+	slaService := application.NewSLAService(loanRepo, notifier, loanService, slaRules, cfg.SLA.BatchSize, logger)
+	slaMonitorJob := application.NewSLAMonitorJob(
+		slaService,
+		time.Duration(cfg.SLA.CheckInterval)*time.Second,
+		logger,
+	)
+
+	// Assign applications entering manual review to loan officers
+	officers := make([]domain.LoanOfficer, 0, len(cfg.Assignment.Officers))
+	for _, officer := range cfg.Assignment.Officers {
+		var timeOff []domain.TimeOffPeriod
+		for _, period := range officer.TimeOff {
+			start, startErr := time.Parse("2006-01-02", period.Start)
+			end, endErr := time.Parse("2006-01-02", period.End)
+			if startErr != nil || endErr != nil || end.Before(start) {
+				logger.Warn("Ignoring invalid loan officer time off",
+					zap.String("officer_id", officer.UserID),
+					zap.String("start", period.Start),
+					zap.String("end", period.End))
+				continue
+			}
+			timeOff = append(timeOff, domain.TimeOffPeriod{Start: start, End: end})
+		}
+		officers = append(officers, domain.LoanOfficer{
+			UserID:         officer.UserID,
+			Name:           officer.Name,
+			Capacity:       officer.Capacity,
+			Skills:         officer.Skills,
+			LicensedStates: officer.LicensedStates,
+			TimeOff:        timeOff,
+		})
+	}
+	assignmentService := application.NewAssignmentService(loanRepo, officers, cfg.Assignment.HighValueAmount, cfg.Assignment.BatchSize, logger)
+	assignmentJob := application.NewAssignmentJob(
+		assignmentService,
+		time.Duration(cfg.Assignment.AssignInterval)*time.Second,
+		logger,
+	)
+
+	// Let operators fix and re-submit workflow tasks workers gave up on, alerting on queue depth
+	deadLetterService := application.NewDeadLetterService(loanRepo, workflowOrchestrator, notifier, cfg.DeadLetter.AlertThreshold, cfg.DeadLetter.AlertRecipient, logger)
+	deadLetterMonitorJob := application.NewDeadLetterMonitorJob(
+		deadLetterService,
+		time.Duration(cfg.DeadLetter.CheckInterval)*time.Second,
+		logger,
+	)
+
+	// Let underwriters claim and decide the reviews the underwriting workflow waits on
+	manualReviewService := application.NewManualReviewService(loanRepo, assignmentService, workflowOrchestrator, logger)
+
+	// Initialize payroll income verification; disabled unless a provider is configured
+	payrollProvider := payroll.NewProvider(cfg.Payroll, logger)
+	if payrollProvider == nil {
+		logger.Info("Payroll income verification disabled; income will be left to document review")
+	}
+	incomeVerificationService := application.NewIncomeVerificationService(loanRepo, payrollProvider, cfg.Payroll.VarianceTolerance, logger)
+
+	// Track conditional approval conditions, resuming the workflow once the critical ones clear
+	conditionService := application.NewConditionService(loanRepo, documentStore, workflowOrchestrator, cfg.Application.ConditionExpiryBatchSize, logger)
+	conditionExpiryJob := application.NewConditionExpiryJob(
+		conditionService,
+		time.Duration(cfg.Application.ConditionExpiryCheckInterval)*time.Second,
+		logger,
+	)
+
+	// Expire lapsed offers and prompt borrowers to re-apply
+	offerExpiryJob := application.NewOfferExpiryJob(
+		loanRepo,
+		notifier,
+		webhookService,
+		cfg.Application.OfferExpiryBatchSize,
+		time.Duration(cfg.Application.OfferExpiryCheckInterval)*time.Second,
+		logger,
+	)
+
+	// Originate due disbursements, including retries of returned transfers, in funding batches
+	fundingBatchJob := application.NewFundingBatchJob(
+		disbursementService,
+		time.Duration(cfg.Disbursement.BatchInterval)*time.Second,
+		logger,
+	)
+
+	// Deliver webhook events, retrying failed deliveries with backoff
+	webhookDispatchJob := application.NewWebhookDispatchJob(
+		webhookService,
+		time.Duration(cfg.Webhooks.DispatchInterval)*time.Second,
+		logger,
+	)
 
 	// Initialize handlers
 	loanHandler := interfaces.NewLoanHandler(loanService, logger, localizer)
+	pricingHandler := interfaces.NewPricingHandler(pricingService, logger)
+	signatureHandler := interfaces.NewSignatureHandler(signatureService, logger)
+	disclosureHandler := interfaces.NewDisclosureHandler(documentService, logger)
+	disbursementHandler := interfaces.NewDisbursementHandler(disbursementService, logger)
+	bankAccountHandler := interfaces.NewBankAccountHandler(bankAccountService, logger)
+	repaymentHandler := interfaces.NewRepaymentHandler(repaymentService, logger)
+	preQualificationHandler := interfaces.NewPreQualificationHandler(preQualificationService, logger)
+	rateLockHandler := interfaces.NewRateLockHandler(rateLockService, logger)
+	refinanceHandler := interfaces.NewRefinanceHandler(refinanceService, logger)
+	collateralHandler := interfaces.NewCollateralHandler(collateralService, logger)
+	creditConsentHandler := interfaces.NewCreditConsentHandler(creditConsentService, logger)
+	demographicsHandler := interfaces.NewDemographicsHandler(demographicsService, logger)
+	duplicateHandler := interfaces.NewDuplicateHandler(duplicateService, logger)
+	webhookHandler := interfaces.NewWebhookHandler(webhookService, logger)
+	searchHandler := interfaces.NewSearchHandler(searchService, logger)
+	slaHandler := interfaces.NewSLAHandler(slaService, logger)
+	assignmentHandler := interfaces.NewAssignmentHandler(assignmentService, logger)
+	deadLetterHandler := interfaces.NewDeadLetterHandler(deadLetterService, logger)
+	manualReviewHandler := interfaces.NewManualReviewHandler(manualReviewService, logger)
+	incomeVerificationHandler := interfaces.NewIncomeVerificationHandler(incomeVerificationService, logger)
+	conditionHandler := interfaces.NewConditionHandler(conditionService, logger)
+	calculatorHandler := interfaces.NewCalculatorHandler(calculatorService, logger)
+	graphHandler := graph.NewHandler(graph.NewResolver(loanService, documentService, logger))
+
+	// Idempotency keys are shared across instances through Redis; without it they only hold per process
+	var idempotencyStore sharedMiddleware.IdempotencyStore
+	redisPort, _ := strconv.Atoi(cfg.Redis.Port)
+	redisClient, err := cache.NewClient(cache.Config{
+		Host:     cfg.Redis.Host,
+		Port:     redisPort,
+		Password: cfg.Redis.Password,
+		Database: cfg.Redis.DB,
+		PoolSize: cfg.Redis.PoolSize,
+	})
+	if err != nil {
+		logger.Warn("Failed to connect to Redis, using in-memory idempotency store", zap.Error(err))
+		idempotencyStore = sharedMiddleware.NewMemoryIdempotencyStore()
+	} else {
+		defer redisClient.Close()
+		idempotencyStore = sharedMiddleware.NewRedisIdempotencyStore(redisClient.Client)
+	}
+	idempotency := sharedMiddleware.IdempotencyMiddleware(sharedMiddleware.IdempotencyConfig{
+		Store:     idempotencyStore,
+		KeyPrefix: "loan-api:idempotency",
+		Logger:    logger,
+	})
+
+	// Borrowers authenticate with access tokens from the auth service and may only reach their
+	// own applications
+	authentication := middleware.RequireAuth(cfg.Security.JWTSecret)
+	ownership := middleware.ApplicationOwnership(loanService.GetApplicationOwner, logger)
 
 	// Setup HTTP server
-	router := setupRouter(logger, loanHandler, localizer)
+	router := setupRouter(logger, loanHandler, pricingHandler, signatureHandler, disclosureHandler, disbursementHandler, bankAccountHandler, repaymentHandler, preQualificationHandler, rateLockHandler, refinanceHandler, collateralHandler, creditConsentHandler, demographicsHandler, duplicateHandler, webhookHandler, searchHandler, slaHandler, assignmentHandler, deadLetterHandler, manualReviewHandler, incomeVerificationHandler, conditionHandler, calculatorHandler, graphHandler, localizer, cfg.Security.InternalServiceToken, authentication, ownership, idempotency)
 
 	server := &http.Server{
 		Addr:         cfg.GetServerAddr(),
@@ -105,6 +468,21 @@ func main() {
 		WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second,
 	}
 
+	// Start background jobs
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go offerExpiryJob.Start(jobCtx)
+	go fundingBatchJob.Start(jobCtx)
+	go webhookDispatchJob.Start(jobCtx)
+	go slaMonitorJob.Start(jobCtx)
+	go assignmentJob.Start(jobCtx)
+	go deadLetterMonitorJob.Start(jobCtx)
+	go conditionExpiryJob.Start(jobCtx)
+	// Tokenize SSNs stored before users had SSN tokens, until the plaintext column is dropped out of band
+	if ssnBackfillRepo != nil {
+		go application.NewSSNBackfillJob(ssnBackfillRepo, tokenizer, 100, logger).Start(jobCtx)
+	}
+
 	// Start server in a goroutine
 	go func() {
 		logger.Info("Starting HTTP server",
@@ -115,6 +493,29 @@ func main() {
 		}
 	}()
 
+	// Serve LoanService to internal callers over gRPC; REST stays the external API
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Port > 0 {
+		grpcServer, err = rpc.NewServer(cfg.GRPC, logger)
+		if err != nil {
+			logger.Fatal("Failed to create gRPC server", zap.Error(err))
+		}
+		loanv1.RegisterLoanServiceServer(grpcServer, interfaces.NewLoanServer(loanService, logger))
+		healthServer := health.NewServer()
+		healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.Port))
+		if err != nil {
+			logger.Fatal("Failed to listen for gRPC", zap.Error(err))
+		}
+		go func() {
+			logger.Info("Starting gRPC server", zap.Int("port", cfg.GRPC.Port))
+			if err := grpcServer.Serve(listener); err != nil {
+				logger.Error("gRPC server stopped", zap.Error(err))
+			}
+		}()
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -129,6 +530,9 @@ func main() {
 	if err := server.Shutdown(ctx); err != nil {
 		logger.Error("Server forced to shutdown", zap.Error(err))
 	}
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
 
 	logger.Info("Server exited")
 }
@@ -136,6 +540,7 @@ func main() {
 // Mock repositories for when database is not available
 type MockUserRepository struct{}
 type MockLoanRepository struct{}
+type MockPricingRepository struct{}
 
 func (m *MockUserRepository) CreateUser(ctx context.Context, user *domain.User) (string, error) {
 	return "mock-user-123", nil
@@ -169,6 +574,14 @@ func (m *MockLoanRepository) GetApplicationsByUserID(ctx context.Context, userID
 	return []*domain.LoanApplication{}, nil
 }
 
+func (m *MockLoanRepository) ListApplications(ctx context.Context, query *domain.ApplicationListQuery) ([]*domain.LoanApplication, error) {
+	return []*domain.LoanApplication{}, nil
+}
+
+func (m *MockLoanRepository) CountApplications(ctx context.Context, query *domain.ApplicationListQuery) (int, error) {
+	return 0, nil
+}
+
 func (m *MockLoanRepository) UpdateApplication(ctx context.Context, app *domain.LoanApplication) error {
 	return nil
 }
@@ -177,6 +590,23 @@ func (m *MockLoanRepository) DeleteApplication(ctx context.Context, id string) e
 	return nil
 }
 
+func (m *MockLoanRepository) ReassignApplications(ctx context.Context, fromUserID, toUserID string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockLoanRepository) GetUserLoanActivity(ctx context.Context, userID string) (*domain.UserLoanActivity, error) {
+	return &domain.UserLoanActivity{UserID: userID}, nil
+}
+
+func (m *MockLoanRepository) GetApplicationStats(ctx context.Context, since time.Time) (*domain.ApplicationStats, error) {
+	return &domain.ApplicationStats{
+		Since:    since,
+		ByStatus: map[domain.ApplicationStatus]int{},
+		ByState:  map[domain.ApplicationState]int{},
+		Funnel:   []domain.FunnelStage{},
+	}, nil
+}
+
 func (m *MockLoanRepository) CreateOffer(ctx context.Context, offer *domain.LoanOffer) error {
 	return nil
 }
@@ -189,6 +619,490 @@ func (m *MockLoanRepository) UpdateOffer(ctx context.Context, offer *domain.Loan
 	return nil
 }
 
+func (m *MockLoanRepository) CreateOfferGroup(ctx context.Context, group *domain.OfferGroup) error {
+	return nil
+}
+
+func (m *MockLoanRepository) ListOfferGroups(ctx context.Context, applicationID string) ([]*domain.OfferGroup, error) {
+	return []*domain.OfferGroup{}, nil
+}
+
+func (m *MockLoanRepository) SelectOffer(ctx context.Context, groupID, offerID string) error {
+	return nil
+}
+
+func (m *MockLoanRepository) GetOfferByID(ctx context.Context, id string) (*domain.LoanOffer, error) {
+	return nil, fmt.Errorf("not found")
+}
+
+func (m *MockLoanRepository) ListCounterOffers(ctx context.Context, applicationID string) ([]*domain.LoanOffer, error) {
+	return []*domain.LoanOffer{}, nil
+}
+
+func (m *MockLoanRepository) AcceptCounterOffer(ctx context.Context, offer *domain.LoanOffer) error {
+	return nil
+}
+
+func (m *MockLoanRepository) CreateNegotiation(ctx context.Context, negotiation *domain.OfferNegotiation) error {
+	return nil
+}
+
+func (m *MockLoanRepository) ListNegotiations(ctx context.Context, applicationID string) ([]*domain.OfferNegotiation, error) {
+	return []*domain.OfferNegotiation{}, nil
+}
+
+func (m *MockLoanRepository) WithdrawApplication(ctx context.Context, app *domain.LoanApplication, withdrawal *domain.ApplicationWithdrawal) error {
+	return nil
+}
+
+func (m *MockLoanRepository) TransitionApplicationState(ctx context.Context, app *domain.LoanApplication, transition *domain.StateTransition) error {
+	return nil
+}
+
+func (m *MockLoanRepository) CreateNote(ctx context.Context, note *domain.ApplicationNote) error {
+	return nil
+}
+
+func (m *MockLoanRepository) ListNotes(ctx context.Context, applicationID string, borrowerVisibleOnly bool) ([]*domain.ApplicationNote, error) {
+	return []*domain.ApplicationNote{}, nil
+}
+
+func (m *MockLoanRepository) ListOffers(ctx context.Context, applicationID string) ([]*domain.LoanOffer, error) {
+	return []*domain.LoanOffer{}, nil
+}
+
+func (m *MockLoanRepository) ListWorkflowExecutions(ctx context.Context, applicationID string) ([]*domain.WorkflowExecution, error) {
+	return []*domain.WorkflowExecution{}, nil
+}
+
+func (m *MockLoanRepository) ExpireOffers(ctx context.Context, asOf time.Time) (int, error) {
+	return 0, nil
+}
+
+func (m *MockLoanRepository) VoidOffers(ctx context.Context, applicationID string, since time.Time) ([]string, error) {
+	return []string{}, nil
+}
+
+func (m *MockLoanRepository) ListApplicationsWithLapsedOffers(ctx context.Context, afterID string, limit int) ([]domain.LapsedOffers, error) {
+	return []domain.LapsedOffers{}, nil
+}
+
+func (m *MockLoanRepository) ListDocumentRules(ctx context.Context) ([]*domain.DocumentRule, error) {
+	return []*domain.DocumentRule{}, nil
+}
+
+func (m *MockLoanRepository) AddDocumentRequirements(ctx context.Context, requirements []*domain.DocumentRequirement) error {
+	return nil
+}
+
+func (m *MockLoanRepository) ListDocumentRequirements(ctx context.Context, applicationID string) ([]*domain.DocumentRequirement, error) {
+	return []*domain.DocumentRequirement{}, nil
+}
+
+func (m *MockLoanRepository) MarkDocumentRequirementReceived(ctx context.Context, requirementID, documentID string, receivedAt time.Time) error {
+	return nil
+}
+
+func (m *MockLoanRepository) CreateUnderwritingCondition(ctx context.Context, condition *domain.UnderwritingCondition) error {
+	return nil
+}
+
+func (m *MockLoanRepository) CreateSignatureEnvelope(ctx context.Context, envelope *domain.SignatureEnvelope) error {
+	return nil
+}
+
+func (m *MockLoanRepository) GetSignatureEnvelopeByProviderID(ctx context.Context, provider, providerEnvelopeID string) (*domain.SignatureEnvelope, error) {
+	return nil, fmt.Errorf("signature envelope not found")
+}
+
+func (m *MockLoanRepository) GetLatestSignatureEnvelope(ctx context.Context, applicationID string) (*domain.SignatureEnvelope, error) {
+	return nil, fmt.Errorf("signature envelope not found")
+}
+
+func (m *MockLoanRepository) UpdateSignatureEnvelope(ctx context.Context, envelope *domain.SignatureEnvelope) error {
+	return nil
+}
+
+func (m *MockLoanRepository) CreateGeneratedDocument(ctx context.Context, document *domain.GeneratedDocument) error {
+	return nil
+}
+
+func (m *MockLoanRepository) MarkGeneratedDocumentDelivered(ctx context.Context, id string, deliveredAt time.Time) error {
+	return nil
+}
+
+func (m *MockLoanRepository) GetGeneratedDocument(ctx context.Context, id string) (*domain.GeneratedDocument, error) {
+	return nil, fmt.Errorf("generated document not found")
+}
+
+func (m *MockLoanRepository) ListGeneratedDocuments(ctx context.Context, applicationID string) ([]*domain.GeneratedDocument, error) {
+	return []*domain.GeneratedDocument{}, nil
+}
+
+func (m *MockLoanRepository) CreateDisbursement(ctx context.Context, disbursement *domain.Disbursement) error {
+	return nil
+}
+
+func (m *MockLoanRepository) GetLatestDisbursement(ctx context.Context, applicationID string) (*domain.Disbursement, error) {
+	return nil, fmt.Errorf("disbursement not found")
+}
+
+func (m *MockLoanRepository) GetDisbursementByProviderTransferID(ctx context.Context, provider, providerTransferID string) (*domain.Disbursement, error) {
+	return nil, fmt.Errorf("disbursement not found")
+}
+
+func (m *MockLoanRepository) UpdateDisbursement(ctx context.Context, disbursement *domain.Disbursement) error {
+	return nil
+}
+
+func (m *MockLoanRepository) ClaimDueDisbursements(ctx context.Context, batchID string, asOf time.Time, limit int) ([]*domain.Disbursement, error) {
+	return []*domain.Disbursement{}, nil
+}
+
+func (m *MockLoanRepository) ListBatchDisbursements(ctx context.Context, batchID string) ([]*domain.Disbursement, error) {
+	return []*domain.Disbursement{}, nil
+}
+
+func (m *MockLoanRepository) CreateFundingBatch(ctx context.Context, batch *domain.FundingBatch) error {
+	return nil
+}
+
+func (m *MockLoanRepository) UpdateFundingBatch(ctx context.Context, batch *domain.FundingBatch) error {
+	return nil
+}
+
+func (m *MockLoanRepository) GetFundingBatch(ctx context.Context, id string) (*domain.FundingBatch, error) {
+	return nil, fmt.Errorf("funding batch not found")
+}
+
+func (m *MockLoanRepository) CreateDisbursementEvent(ctx context.Context, event *domain.DisbursementEvent) error {
+	return nil
+}
+
+func (m *MockLoanRepository) ListDisbursementEvents(ctx context.Context, disbursementID string) ([]*domain.DisbursementEvent, error) {
+	return []*domain.DisbursementEvent{}, nil
+}
+
+func (m *MockLoanRepository) CreateBankAccount(ctx context.Context, account *domain.BankAccount) error {
+	return nil
+}
+
+func (m *MockLoanRepository) GetBankAccount(ctx context.Context, id string) (*domain.BankAccount, error) {
+	return nil, fmt.Errorf("bank account not found")
+}
+
+func (m *MockLoanRepository) GetActiveBankAccountByNumber(ctx context.Context, userID, accountNumberToken, routingNumberToken string) (*domain.BankAccount, error) {
+	return nil, fmt.Errorf("bank account not found")
+}
+
+func (m *MockLoanRepository) GetVerifiedBankAccount(ctx context.Context, userID string) (*domain.BankAccount, error) {
+	return nil, fmt.Errorf("bank account not found")
+}
+
+func (m *MockLoanRepository) ListBankAccounts(ctx context.Context, userID string) ([]*domain.BankAccount, error) {
+	return []*domain.BankAccount{}, nil
+}
+
+func (m *MockLoanRepository) UpdateBankAccount(ctx context.Context, account *domain.BankAccount) error {
+	return nil
+}
+
+func (m *MockLoanRepository) CreateRepaymentSchedule(ctx context.Context, schedule *domain.RepaymentSchedule) error {
+	return nil
+}
+
+func (m *MockLoanRepository) GetRepaymentSchedule(ctx context.Context, applicationID string) (*domain.RepaymentSchedule, error) {
+	return nil, fmt.Errorf("repayment schedule not found")
+}
+
+func (m *MockLoanRepository) CreateRateLock(ctx context.Context, lock *domain.RateLock) error {
+	return nil
+}
+
+func (m *MockLoanRepository) GetRateLock(ctx context.Context, offerID string) (*domain.RateLock, error) {
+	return nil, fmt.Errorf("rate lock not found")
+}
+
+func (m *MockLoanRepository) ListRateLocks(ctx context.Context, applicationID string) ([]*domain.RateLock, error) {
+	return []*domain.RateLock{}, nil
+}
+
+func (m *MockLoanRepository) RepriceOffer(ctx context.Context, offer *domain.LoanOffer, lock *domain.RateLock) error {
+	return nil
+}
+
+func (m *MockLoanRepository) CreatePreQualification(ctx context.Context, result *domain.PreQualifyResult) error {
+	return nil
+}
+
+func (m *MockLoanRepository) GetPreQualification(ctx context.Context, id string) (*domain.PreQualifyResult, error) {
+	return nil, fmt.Errorf("pre-qualification not found")
+}
+
+func (m *MockLoanRepository) MarkPreQualificationConverted(ctx context.Context, id, applicationID string) error {
+	return nil
+}
+
+func (m *MockLoanRepository) CreateCollateral(ctx context.Context, collateral *domain.Collateral) error {
+	return nil
+}
+
+func (m *MockLoanRepository) GetCollateral(ctx context.Context, applicationID string) (*domain.Collateral, error) {
+	return nil, fmt.Errorf("collateral not found")
+}
+
+func (m *MockLoanRepository) UpdateCollateralValuation(ctx context.Context, collateral *domain.Collateral) error {
+	return nil
+}
+
+func (m *MockLoanRepository) CreateCreditConsent(ctx context.Context, consent *domain.CreditConsent) error {
+	return nil
+}
+
+func (m *MockLoanRepository) GetCreditConsent(ctx context.Context, applicationID string) (*domain.CreditConsent, error) {
+	return nil, fmt.Errorf("credit consent not found")
+}
+
+func (m *MockLoanRepository) SaveApplicantDemographics(ctx context.Context, demographics *domain.ApplicantDemographics) error {
+	return nil
+}
+
+func (m *MockLoanRepository) GetApplicantDemographics(ctx context.Context, applicationID string) (*domain.ApplicantDemographics, error) {
+	return nil, fmt.Errorf("applicant demographics not found")
+}
+
+func (m *MockLoanRepository) CreateApplicationFingerprint(ctx context.Context, fingerprint *domain.ApplicationFingerprint) error {
+	return nil
+}
+
+func (m *MockLoanRepository) FindDuplicateCandidates(ctx context.Context, fingerprint *domain.ApplicationFingerprint, since time.Time) ([]*domain.ApplicationFingerprint, error) {
+	return nil, nil
+}
+
+func (m *MockLoanRepository) CreateDuplicateMatch(ctx context.Context, match *domain.DuplicateMatch) error {
+	return nil
+}
+
+func (m *MockLoanRepository) GetDuplicateMatch(ctx context.Context, id string) (*domain.DuplicateMatch, error) {
+	return nil, fmt.Errorf("duplicate match not found")
+}
+
+func (m *MockLoanRepository) ListDuplicateMatches(ctx context.Context, applicationID string) ([]*domain.DuplicateMatch, error) {
+	return nil, nil
+}
+
+func (m *MockLoanRepository) UpdateDuplicateMatchReview(ctx context.Context, match *domain.DuplicateMatch) error {
+	return nil
+}
+
+func (m *MockLoanRepository) CreateWebhookSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	return nil
+}
+
+func (m *MockLoanRepository) GetWebhookSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	return nil, fmt.Errorf("webhook subscription not found")
+}
+
+func (m *MockLoanRepository) ListWebhookSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	return nil, nil
+}
+
+func (m *MockLoanRepository) UpdateWebhookSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	return nil
+}
+
+func (m *MockLoanRepository) CreateWebhookEvent(ctx context.Context, event *domain.WebhookEvent) error {
+	return nil
+}
+
+func (m *MockLoanRepository) GetWebhookEvent(ctx context.Context, id string) (*domain.WebhookEvent, error) {
+	return nil, fmt.Errorf("webhook event not found")
+}
+
+func (m *MockLoanRepository) ListUndispatchedWebhookEvents(ctx context.Context, limit int) ([]*domain.WebhookEvent, error) {
+	return nil, nil
+}
+
+func (m *MockLoanRepository) MarkWebhookEventDispatched(ctx context.Context, id string, dispatchedAt time.Time) error {
+	return nil
+}
+
+func (m *MockLoanRepository) CreateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	return nil
+}
+
+func (m *MockLoanRepository) GetWebhookDelivery(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	return nil, fmt.Errorf("webhook delivery not found")
+}
+
+func (m *MockLoanRepository) ListDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (m *MockLoanRepository) ListWebhookDeliveries(ctx context.Context, query *domain.WebhookDeliveryQuery) ([]*domain.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (m *MockLoanRepository) UpdateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	return nil
+}
+
+func (m *MockLoanRepository) ListSLACandidates(ctx context.Context, rule *domain.SLARule, now time.Time, limit int) ([]*domain.SLACandidate, error) {
+	return []*domain.SLACandidate{}, nil
+}
+
+func (m *MockLoanRepository) CreateSLABreach(ctx context.Context, breach *domain.SLABreach) (bool, error) {
+	return true, nil
+}
+
+func (m *MockLoanRepository) UpdateSLABreachError(ctx context.Context, id, actionError string) error {
+	return nil
+}
+
+func (m *MockLoanRepository) BumpApplicationPriority(ctx context.Context, applicationID string) error {
+	return nil
+}
+
+func (m *MockLoanRepository) ListSLABreaches(ctx context.Context, query *domain.SLABreachQuery, now time.Time) ([]*domain.SLABreach, error) {
+	return []*domain.SLABreach{}, nil
+}
+
+func (m *MockLoanRepository) SearchApplications(ctx context.Context, query *domain.ApplicationSearchQuery) ([]*domain.ApplicationSearchResult, int, error) {
+	return []*domain.ApplicationSearchResult{}, 0, nil
+}
+
+func (m *MockLoanRepository) GetApplicationIDByWorkflowID(ctx context.Context, workflowID string) (string, error) {
+	return "", nil
+}
+
+func (m *MockLoanRepository) RecordWorkflowAction(ctx context.Context, action *domain.WorkflowAction) error {
+	return nil
+}
+
+func (m *MockLoanRepository) ListUnassignedReviews(ctx context.Context, limit int) ([]*domain.AssignmentCandidate, error) {
+	return nil, nil
+}
+
+func (m *MockLoanRepository) GetAssignmentCandidate(ctx context.Context, applicationID string) (*domain.AssignmentCandidate, error) {
+	return nil, fmt.Errorf("application not found")
+}
+
+func (m *MockLoanRepository) CountActiveAssignments(ctx context.Context) (map[string]int, error) {
+	return map[string]int{}, nil
+}
+
+func (m *MockLoanRepository) LatestAssignmentTimes(ctx context.Context) (map[string]time.Time, error) {
+	return map[string]time.Time{}, nil
+}
+
+func (m *MockLoanRepository) CreateAssignment(ctx context.Context, assignment *domain.ApplicationAssignment, capacity int) (bool, error) {
+	return true, nil
+}
+
+func (m *MockLoanRepository) ReassignApplication(ctx context.Context, assignment *domain.ApplicationAssignment) error {
+	return nil
+}
+
+func (m *MockLoanRepository) ReleaseFinishedAssignments(ctx context.Context, now time.Time) (int, error) {
+	return 0, nil
+}
+
+func (m *MockLoanRepository) GetActiveAssignment(ctx context.Context, applicationID string) (*domain.ApplicationAssignment, error) {
+	return nil, fmt.Errorf("assignment not found")
+}
+
+func (m *MockLoanRepository) ListAssignments(ctx context.Context, applicationID string) ([]*domain.ApplicationAssignment, error) {
+	return []*domain.ApplicationAssignment{}, nil
+}
+
+func (m *MockLoanRepository) ListOfficerQueue(ctx context.Context, officerID string) ([]*domain.QueueItem, error) {
+	return []*domain.QueueItem{}, nil
+}
+
+func (m *MockLoanRepository) ListDeadLetters(ctx context.Context, query *domain.DeadLetterQuery) ([]*domain.DeadLetterTask, error) {
+	return []*domain.DeadLetterTask{}, nil
+}
+
+func (m *MockLoanRepository) GetDeadLetter(ctx context.Context, id string) (*domain.DeadLetterTask, error) {
+	return nil, fmt.Errorf("dead-lettered task not found")
+}
+
+func (m *MockLoanRepository) UpdateDeadLetterInput(ctx context.Context, task *domain.DeadLetterTask) (bool, error) {
+	return false, nil
+}
+
+func (m *MockLoanRepository) ResolveDeadLetter(ctx context.Context, task *domain.DeadLetterTask) (bool, error) {
+	return false, nil
+}
+
+func (m *MockLoanRepository) CountOpenDeadLetters(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func (m *MockLoanRepository) ListManualReviews(ctx context.Context, query *domain.ManualReviewQuery) ([]*domain.ManualReview, error) {
+	return []*domain.ManualReview{}, nil
+}
+
+func (m *MockLoanRepository) GetManualReview(ctx context.Context, id string) (*domain.ManualReview, error) {
+	return nil, fmt.Errorf("manual review not found")
+}
+
+func (m *MockLoanRepository) ClaimManualReview(ctx context.Context, review *domain.ManualReview) (bool, error) {
+	return false, nil
+}
+
+func (m *MockLoanRepository) CompleteManualReview(ctx context.Context, review *domain.ManualReview) (bool, error) {
+	return false, nil
+}
+
+func (m *MockLoanRepository) RecordManualReviewRedecision(ctx context.Context, applicationID, note string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockLoanRepository) GetIncomeVerification(ctx context.Context, applicationID string) (*domain.IncomeVerification, error) {
+	return nil, fmt.Errorf("income verification not found")
+}
+
+func (m *MockLoanRepository) GetIncomeVerificationByProviderUser(ctx context.Context, providerUserID string) (*domain.IncomeVerification, error) {
+	return nil, fmt.Errorf("income verification not found")
+}
+
+func (m *MockLoanRepository) CompleteIncomeVerification(ctx context.Context, id string, income *payroll.Income) (bool, error) {
+	return false, nil
+}
+
+func (m *MockLoanRepository) ListUnderwritingConditions(ctx context.Context, applicationID string) ([]*domain.UnderwritingCondition, error) {
+	return []*domain.UnderwritingCondition{}, nil
+}
+
+func (m *MockLoanRepository) ListWorkflowConditions(ctx context.Context, workflowInstanceID string) ([]*domain.UnderwritingCondition, error) {
+	return []*domain.UnderwritingCondition{}, nil
+}
+
+func (m *MockLoanRepository) GetUnderwritingCondition(ctx context.Context, applicationID, id string) (*domain.UnderwritingCondition, error) {
+	return nil, fmt.Errorf("underwriting condition not found")
+}
+
+func (m *MockLoanRepository) RecordConditionEvidence(ctx context.Context, id, documentID string, receivedAt time.Time) (bool, error) {
+	return false, nil
+}
+
+func (m *MockLoanRepository) WaiveUnderwritingCondition(ctx context.Context, id, waivedBy, reason string, waivedAt time.Time) (bool, error) {
+	return false, nil
+}
+
+func (m *MockLoanRepository) ExpireUnderwritingConditions(ctx context.Context, asOf time.Time) (int, error) {
+	return 0, nil
+}
+
+func (m *MockLoanRepository) ListConditionWorkflowsToResume(ctx context.Context, limit int) ([]string, error) {
+	return []string{}, nil
+}
+
+func (m *MockLoanRepository) MarkConditionWorkflowResumed(ctx context.Context, workflowInstanceID string, resumedAt time.Time) error {
+	return nil
+}
+
 func (m *MockLoanRepository) CreateStateTransition(ctx context.Context, transition *domain.StateTransition) error {
 	return nil
 }
@@ -205,6 +1119,30 @@ func (m *MockLoanRepository) GetWorkflowExecutionByApplicationID(ctx context.Con
 	return nil, fmt.Errorf("not found")
 }
 
+func (m *MockPricingRepository) CreateRate(ctx context.Context, rate *domain.PricingRate) error {
+	return nil
+}
+
+func (m *MockPricingRepository) GetRate(ctx context.Context, id string) (*domain.PricingRate, error) {
+	return nil, fmt.Errorf("not found")
+}
+
+func (m *MockPricingRepository) UpdateRate(ctx context.Context, rate *domain.PricingRate) error {
+	return nil
+}
+
+func (m *MockPricingRepository) DeleteRate(ctx context.Context, id string) error {
+	return nil
+}
+
+func (m *MockPricingRepository) ListRates(ctx context.Context, query *domain.PricingRateQuery) ([]*domain.PricingRate, error) {
+	return []*domain.PricingRate{}, nil
+}
+
+func (m *MockPricingRepository) FindRate(ctx context.Context, product string, tier domain.CreditTier, riskLevel domain.RiskLevel, termMonths int, at time.Time) (*domain.PricingRate, error) {
+	return nil, fmt.Errorf("not found")
+}
+
 // initLogger initializes the zap logger
 func initLogger(cfg *config.BaseConfig) (*zap.Logger, error) {
 	var level zapcore.Level
@@ -255,7 +1193,7 @@ func initLogger(cfg *config.BaseConfig) (*zap.Logger, error) {
 }
 
 // setupRouter sets up the Gin router with middleware and routes
-func setupRouter(logger *zap.Logger, loanHandler *interfaces.LoanHandler, localizer *i18n.Localizer) *gin.Engine {
+func setupRouter(logger *zap.Logger, loanHandler *interfaces.LoanHandler, pricingHandler *interfaces.PricingHandler, signatureHandler *interfaces.SignatureHandler, disclosureHandler *interfaces.DisclosureHandler, disbursementHandler *interfaces.DisbursementHandler, bankAccountHandler *interfaces.BankAccountHandler, repaymentHandler *interfaces.RepaymentHandler, preQualificationHandler *interfaces.PreQualificationHandler, rateLockHandler *interfaces.RateLockHandler, refinanceHandler *interfaces.RefinanceHandler, collateralHandler *interfaces.CollateralHandler, creditConsentHandler *interfaces.CreditConsentHandler, demographicsHandler *interfaces.DemographicsHandler, duplicateHandler *interfaces.DuplicateHandler, webhookHandler *interfaces.WebhookHandler, searchHandler *interfaces.SearchHandler, slaHandler *interfaces.SLAHandler, assignmentHandler *interfaces.AssignmentHandler, deadLetterHandler *interfaces.DeadLetterHandler, manualReviewHandler *interfaces.ManualReviewHandler, incomeVerificationHandler *interfaces.IncomeVerificationHandler, conditionHandler *interfaces.ConditionHandler, calculatorHandler *interfaces.CalculatorHandler, graphHandler *graph.Handler, localizer *i18n.Localizer, internalServiceToken string, authentication, ownership, idempotency gin.HandlerFunc) *gin.Engine {
 	// Set Gin mode
 	gin.SetMode(gin.ReleaseMode)
 
@@ -281,11 +1219,91 @@ func setupRouter(logger *zap.Logger, loanHandler *interfaces.LoanHandler, locali
 
 	// API routes
 	v1 := router.Group("/v1")
+	{
+		// Register public routes
+		loanHandler.RegisterPublicRoutes(v1)
+		calculatorHandler.RegisterRoutes(v1)
+	}
+
+	protected := v1.Group("", authentication, ownership)
 	{
 		// Register loan routes
-		loanHandler.RegisterRoutes(v1)
+		loanHandler.RegisterRoutes(protected, idempotency)
+
+		// Register pricing admin routes
+		pricingHandler.RegisterRoutes(protected)
+
+		// Register loan agreement signature routes
+		signatureHandler.RegisterRoutes(protected)
+
+		// Register loan agreement and disclosure document routes
+		disclosureHandler.RegisterRoutes(protected)
+
+		// Register loan funding routes
+		disbursementHandler.RegisterRoutes(protected)
+
+		// Register borrower bank account routes
+		bankAccountHandler.RegisterRoutes(protected)
+
+		// Register repayment schedule routes
+		repaymentHandler.RegisterRoutes(protected)
+
+		// Register pre-qualification routes
+		preQualificationHandler.RegisterRoutes(protected)
+
+		// Register offer repricing routes
+		rateLockHandler.RegisterRoutes(protected)
+		refinanceHandler.RegisterRoutes(protected)
+
+		// Register collateral routes
+		collateralHandler.RegisterRoutes(protected)
+
+		// Register credit pull consent routes
+		creditConsentHandler.RegisterRoutes(protected)
+
+		// Register government monitoring information routes
+		demographicsHandler.RegisterRoutes(protected)
+
+		// Register duplicate application review routes
+		duplicateHandler.RegisterRoutes(protected)
+		webhookHandler.RegisterRoutes(protected)
+
+		// Register back-office search routes
+		searchHandler.RegisterRoutes(protected)
+
+		// Register SLA breach routes
+		slaHandler.RegisterRoutes(protected)
+
+		// Register loan officer assignment routes
+		assignmentHandler.RegisterRoutes(protected)
+
+		// Register dead-letter queue routes
+		deadLetterHandler.RegisterRoutes(protected)
+
+		// Register manual review queue routes
+		manualReviewHandler.RegisterRoutes(protected)
+
+		// Register income verification routes
+		incomeVerificationHandler.RegisterRoutes(protected)
+		conditionHandler.RegisterRoutes(protected)
+
+		// Register the GraphQL API; borrowers only see their own applications
+		graphHandler.RegisterRoutes(protected)
 	}
 
+	// E-signature, disbursement and payroll provider callbacks, authenticated by their signatures
+	webhooks := router.Group("/webhooks")
+	signatureHandler.RegisterWebhookRoutes(webhooks)
+	disbursementHandler.RegisterWebhookRoutes(webhooks)
+	incomeVerificationHandler.RegisterWebhookRoutes(webhooks)
+
+	// Internal service-to-service routes
+	internal := router.Group("/internal/v1")
+	loanHandler.RegisterInternalRoutes(internal, internalServiceToken)
+	creditConsentHandler.RegisterInternalRoutes(internal)
+	disclosureHandler.RegisterInternalRoutes(internal)
+	manualReviewHandler.RegisterInternalRoutes(internal)
+
 	return router
 }
 
@@ -294,7 +1312,7 @@ func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Language, X-Request-ID")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Language, X-Request-ID, Idempotency-Key")
 		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
 
 		if c.Request.Method == "OPTIONS" {
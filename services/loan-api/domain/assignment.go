@@ -0,0 +1,190 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Loan officer skills required by an application; an officer's other skills are loan purposes
+// they are preferred for
+const (
+	SkillHighValue = "high_value" // loans at or above the configured high-value amount
+	SkillJoint     = "joint"      // applications with a co-borrower
+)
+
+// AssignmentMethod is how an application came to be assigned to a loan officer
+type AssignmentMethod string
+
+const (
+	AssignmentAuto     AssignmentMethod = "auto"     // assigned on entering manual review
+	AssignmentClaim    AssignmentMethod = "claim"    // claimed by the officer
+	AssignmentReassign AssignmentMethod = "reassign" // moved by a manager
+	AssignmentOverride AssignmentMethod = "override" // placed by a team lead past the assignment rules
+)
+
+// LoanOfficer reviews applications in manual review, up to Capacity at a time
+type LoanOfficer struct {
+	UserID         string          `json:"user_id"`
+	Name           string          `json:"name"`
+	Capacity       int             `json:"capacity"`
+	Skills         []string        `json:"skills"`
+	LicensedStates []string        `json:"licensed_states,omitempty"` // empty when licensed in every state
+	TimeOff        []TimeOffPeriod `json:"time_off,omitempty"`
+}
+
+// TimeOffPeriod is a run of days a loan officer is away, from Start to End inclusive
+type TimeOffPeriod struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Covers reports whether the time falls on one of the period's days
+func (p TimeOffPeriod) Covers(at time.Time) bool {
+	return !at.Before(p.Start) && at.Before(p.End.AddDate(0, 0, 1))
+}
+
+// OnTimeOff returns the time off period the officer is away in at the time, if any
+func (o *LoanOfficer) OnTimeOff(at time.Time) (*TimeOffPeriod, bool) {
+	for i := range o.TimeOff {
+		if o.TimeOff[i].Covers(at) {
+			return &o.TimeOff[i], true
+		}
+	}
+	return nil, false
+}
+
+// IsLicensedIn reports whether the officer may review loans to borrowers in the state. An
+// application whose borrower's state is not known can go to any officer.
+func (o *LoanOfficer) IsLicensedIn(state string) bool {
+	if len(o.LicensedStates) == 0 || state == "" {
+		return true
+	}
+	for _, licensed := range o.LicensedStates {
+		if strings.EqualFold(licensed, state) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasSkill reports whether the officer has the skill
+func (o *LoanOfficer) HasSkill(skill string) bool {
+	for _, s := range o.Skills {
+		if s == skill {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplicationAssignment is one period an application spent assigned to a loan officer; the active
+// assignment has no ReleasedAt
+type ApplicationAssignment struct {
+	ID            string           `json:"id" db:"id"`
+	ApplicationID string           `json:"application_id" db:"application_id"`
+	OfficerID     string           `json:"officer_id" db:"officer_id"`
+	Method        AssignmentMethod `json:"method" db:"method"`
+	AssignedBy    string           `json:"assigned_by" db:"assigned_by"` // 'system' for automatic assignment
+	Reason        string           `json:"reason,omitempty" db:"reason"`
+	AssignedAt    time.Time        `json:"assigned_at" db:"assigned_at"`
+	ReleasedAt    *time.Time       `json:"released_at,omitempty" db:"released_at"`
+}
+
+// AssignmentCandidate is an application in manual review waiting for a loan officer
+type AssignmentCandidate struct {
+	ApplicationID  string      `db:"id"`
+	LoanAmount     float64     `db:"loan_amount"`
+	LoanPurpose    LoanPurpose `db:"loan_purpose"`
+	HasCoBorrower  bool        `db:"has_co_borrower"`
+	BorrowerState  string      `db:"borrower_state"`
+	Priority       int         `db:"priority"`
+	StateEnteredAt time.Time   `db:"state_entered_at"`
+}
+
+// RequiredSkills lists the skills an officer needs to review the application
+func (c *AssignmentCandidate) RequiredSkills(highValueAmount float64) []string {
+	var skills []string
+	if highValueAmount > 0 && c.LoanAmount >= highValueAmount {
+		skills = append(skills, SkillHighValue)
+	}
+	if c.HasCoBorrower {
+		skills = append(skills, SkillJoint)
+	}
+	return skills
+}
+
+// SelectOfficer picks the officer to assign the application to. The eligible officers are those
+// licensed in the borrower's state, not on time off, with the required skills and spare capacity;
+// of them, those preferring the loan purpose form the pool when there are any. Within the pool
+// officers take turns: the one assigned an application longest ago, or never, is picked. active
+// holds each officer's current assignment count and lastAssigned when each was last assigned one.
+func SelectOfficer(officers []LoanOfficer, active map[string]int, lastAssigned map[string]time.Time, candidate *AssignmentCandidate, required []string, now time.Time) (*LoanOfficer, bool) {
+	var eligible, preferred []*LoanOfficer
+	for i := range officers {
+		officer := &officers[i]
+		if active[officer.UserID] >= officer.Capacity || !officer.IsLicensedIn(candidate.BorrowerState) {
+			continue
+		}
+		if _, away := officer.OnTimeOff(now); away {
+			continue
+		}
+		qualified := true
+		for _, skill := range required {
+			if !officer.HasSkill(skill) {
+				qualified = false
+				break
+			}
+		}
+		if !qualified {
+			continue
+		}
+		eligible = append(eligible, officer)
+		if officer.HasSkill(string(candidate.LoanPurpose)) {
+			preferred = append(preferred, officer)
+		}
+	}
+
+	pool := eligible
+	if len(preferred) > 0 {
+		pool = preferred
+	}
+	if len(pool) == 0 {
+		return nil, false
+	}
+
+	sort.SliceStable(pool, func(i, j int) bool {
+		return lastAssigned[pool[i].UserID].Before(lastAssigned[pool[j].UserID])
+	})
+	return pool[0], true
+}
+
+// OfficerWorkload is a loan officer's open reviews against their capacity and whether they are
+// taking new applications
+type OfficerWorkload struct {
+	LoanOfficer
+	OpenReviews    int        `json:"open_reviews"`
+	Available      bool       `json:"available"`            // not on time off and below capacity
+	AwayUntil      *time.Time `json:"away_until,omitempty"` // last day of the officer's current time off
+	LastAssignedAt *time.Time `json:"last_assigned_at,omitempty"`
+}
+
+// QueueItem is an application in a loan officer's work queue
+type QueueItem struct {
+	ApplicationID     string           `json:"application_id" db:"id"`
+	ApplicationNumber string           `json:"application_number" db:"application_number"`
+	State             ApplicationState `json:"state" db:"current_state"`
+	LoanAmount        float64          `json:"loan_amount" db:"loan_amount"`
+	LoanPurpose       LoanPurpose      `json:"loan_purpose" db:"loan_purpose"`
+	Priority          int              `json:"priority" db:"priority"`
+	StateEnteredAt    time.Time        `json:"state_entered_at" db:"state_entered_at"`
+	AssignedAt        time.Time        `json:"assigned_at" db:"assigned_at"`
+	Method            AssignmentMethod `json:"method" db:"method"`
+}
+
+// ReassignApplicationRequest moves an application to another loan officer
+// @Description Request to reassign an application in manual review
+type ReassignApplicationRequest struct {
+	OfficerID string `json:"officer_id" binding:"required" example:"b3c1f9a2-7d4e-4c8a-9f10-2e5d6a7b8c90"`
+	Reason    string `json:"reason" binding:"required,max=500" example:"Rebalancing queues while the assigned officer is on leave"`
+}
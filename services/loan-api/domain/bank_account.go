@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"time"
+)
+
+// BankAccountStatus is the verification state of a borrower's bank account
+type BankAccountStatus string
+
+const (
+	BankAccountPendingVerification BankAccountStatus = "pending_verification" // micro-deposits sent, awaiting the amounts
+	BankAccountVerified            BankAccountStatus = "verified"
+	BankAccountVerificationFailed  BankAccountStatus = "verification_failed" // micro-deposit attempts ran out
+	BankAccountRemoved             BankAccountStatus = "removed"
+)
+
+// BankAccountVerificationMethod is how a bank account's ownership was proven
+type BankAccountVerificationMethod string
+
+const (
+	VerificationMethodPlaid         BankAccountVerificationMethod = "plaid"
+	VerificationMethodMicroDeposits BankAccountVerificationMethod = "micro_deposits"
+)
+
+// MaxMicroDepositAttempts is how many times a borrower can confirm micro-deposit amounts before the
+// account fails verification
+const MaxMicroDepositAttempts = 3
+
+// BankAccount is a bank account a borrower has linked to receive loan proceeds. The account and
+// routing numbers are held in the user service token vault; only their tokens are stored here.
+type BankAccount struct {
+	ID                   string                        `json:"id" db:"id"`
+	UserID               string                        `json:"user_id" db:"user_id"`
+	InstitutionName      string                        `json:"institution_name" db:"institution_name"`
+	AccountType          AccountType                   `json:"account_type" db:"account_type"`
+	AccountNumberToken   string                        `json:"-" db:"account_number_token"`
+	RoutingNumberToken   string                        `json:"-" db:"routing_number_token"`
+	AccountLast4         string                        `json:"account_last4" db:"account_last4"`
+	VerificationMethod   BankAccountVerificationMethod `json:"verification_method" db:"verification_method"`
+	Status               BankAccountStatus             `json:"status" db:"status"`
+	PlaidAccountID       *string                       `json:"-" db:"plaid_account_id"`
+	ProviderReference    *string                       `json:"-" db:"provider_reference"` // the disbursement provider's record of the account
+	MicroDepositAttempts int                           `json:"micro_deposit_attempts,omitempty" db:"micro_deposit_attempts"`
+	MicroDepositsSentAt  *time.Time                    `json:"micro_deposits_sent_at,omitempty" db:"micro_deposits_sent_at"`
+	VerifiedAt           *time.Time                    `json:"verified_at,omitempty" db:"verified_at"`
+	CreatedAt            time.Time                     `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time                     `json:"updated_at" db:"updated_at"`
+}
+
+// AddBankAccountRequest represents a request to add a bank account verified by micro-deposits
+// @Description Request to add a bank account by its account and routing numbers
+type AddBankAccountRequest struct {
+	InstitutionName string      `json:"institution_name" binding:"required,max=100" example:"Chase Bank"`
+	AccountType     AccountType `json:"account_type" binding:"required,oneof=checking savings" example:"checking"`
+	AccountNumber   string      `json:"account_number" binding:"required,numeric,min=4,max=17" example:"1234567890"`
+	RoutingNumber   string      `json:"routing_number" binding:"required,numeric,len=9" example:"021000021"`
+}
+
+// LinkPlaidAccountRequest represents a request to link a bank account selected in Plaid Link
+// @Description Request to link a bank account through Plaid
+type LinkPlaidAccountRequest struct {
+	PublicToken     string `json:"public_token" binding:"required" example:"public-sandbox-b0e2c4ee-a763-4df5-bfe9-46a46bce993d"`
+	AccountID       string `json:"account_id" binding:"required" example:"vzeNDwK7KQIm4yEog683uElbp9GRLEFXGK98D"`
+	InstitutionName string `json:"institution_name" binding:"max=100" example:"Chase"` // from the Link metadata
+}
+
+// VerifyMicroDepositsRequest represents the micro-deposit amounts a borrower found in their account
+// @Description Request to confirm the two micro-deposit amounts
+type VerifyMicroDepositsRequest struct {
+	Amount1 float64 `json:"amount1" binding:"required,gt=0,lt=1" example:"0.03"`
+	Amount2 float64 `json:"amount2" binding:"required,gt=0,lt=1" example:"0.09"`
+}
+
+// PlaidLinkToken is a token the borrower's client opens Plaid Link with
+type PlaidLinkToken struct {
+	LinkToken  string    `json:"link_token"`
+	Expiration time.Time `json:"expiration"`
+}
+
+// AuthenticatedAccount is a bank account's details as returned by an account authentication provider
+type AuthenticatedAccount struct {
+	AccountID     string
+	Name          string // the account's name at its institution
+	AccountType   AccountType
+	AccountNumber string
+	RoutingNumber string
+}
+
+// IsValidRoutingNumber reports whether a routing number has nine digits and a valid ABA checksum
+func IsValidRoutingNumber(routingNumber string) bool {
+	if len(routingNumber) != 9 {
+		return false
+	}
+	weights := [3]int{3, 7, 1}
+	sum := 0
+	for i, digit := range routingNumber {
+		if digit < '0' || digit > '9' {
+			return false
+		}
+		sum += int(digit-'0') * weights[i%3]
+	}
+	return sum%10 == 0
+}
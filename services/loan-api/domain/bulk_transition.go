@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"time"
+)
+
+// MaxBulkTransitionSize caps how many applications one bulk transition can move
+const MaxBulkTransitionSize = 100
+
+// BulkTransitionOutcome is what happened to one application in a bulk transition
+type BulkTransitionOutcome string
+
+const (
+	BulkTransitionSucceeded BulkTransitionOutcome = "succeeded"
+	BulkTransitionSkipped   BulkTransitionOutcome = "skipped" // already in the target state
+	BulkTransitionFailed    BulkTransitionOutcome = "failed"
+)
+
+// BulkTransitionRequest moves a batch of applications, e.g. ones stuck behind a failed workflow, to
+// one target state
+// @Description Request to move a batch of loan applications to a target state
+type BulkTransitionRequest struct {
+	ApplicationIDs []string         `json:"application_ids" binding:"required,min=1,max=100,dive,required"`
+	TargetState    ApplicationState `json:"target_state" binding:"required" example:"manual_review"`
+	Reason         string           `json:"reason" binding:"required,max=500" example:"Stuck after the underwriting worker outage on 2024-03-02"`
+	PerformedBy    string           `json:"performed_by" binding:"required" example:"ops@example.com"`
+}
+
+// BulkTransitionItemResult reports the outcome for one application of a bulk transition. Failed
+// items carry the error code and message that a single transition would have returned.
+type BulkTransitionItemResult struct {
+	ApplicationID string                `json:"application_id"`
+	Outcome       BulkTransitionOutcome `json:"outcome"`
+	FromState     *ApplicationState     `json:"from_state,omitempty"`
+	ToState       ApplicationState      `json:"to_state"`
+	TransitionID  string                `json:"transition_id,omitempty"`
+	ErrorCode     string                `json:"error_code,omitempty"`
+	Error         string                `json:"error,omitempty"`
+}
+
+// BulkTransitionReport is the per-item result of a bulk transition. Each application is moved in
+// its own transaction, so one failure does not roll back the others.
+type BulkTransitionReport struct {
+	TargetState ApplicationState            `json:"target_state"`
+	Requested   int                         `json:"requested"`
+	Succeeded   int                         `json:"succeeded"`
+	Skipped     int                         `json:"skipped"`
+	Failed      int                         `json:"failed"`
+	Results     []*BulkTransitionItemResult `json:"results"`
+	CompletedAt time.Time                   `json:"completed_at"`
+}
+
+// StatusForState returns the status an application in the given state should have, keeping the
+// current status for intermediate states that do not settle one
+func StatusForState(state ApplicationState, current ApplicationStatus) ApplicationStatus {
+	switch state {
+	case StateApproved:
+		return StatusApproved
+	case StateDenied:
+		return StatusDenied
+	case StateFunded:
+		return StatusFunded
+	case StateActive:
+		return StatusActive
+	case StateClosed:
+		return StatusClosed
+	case StateWithdrawn:
+		return StatusWithdrawn
+	}
+
+	switch current {
+	case StatusDraft:
+		return StatusSubmitted
+	case StatusApproved, StatusDenied, StatusFunded, StatusActive, StatusClosed:
+		return current
+	default:
+		return StatusUnderReview
+	}
+}
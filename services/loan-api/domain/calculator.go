@@ -0,0 +1,97 @@
+package domain
+
+import "github.com/huuhoait/los-demo/services/shared/pkg/dti"
+
+// PaymentCalculationRequest asks for the payment and cost of a loan; without a rate the lender's
+// base rate is used
+type PaymentCalculationRequest struct {
+	LoanAmount float64  `json:"loan_amount" form:"loan_amount" binding:"required,gt=0" example:"25000"`
+	TermMonths int      `json:"term_months" form:"term_months" binding:"required,min=12,max=84" example:"60"`
+	AnnualRate *float64 `json:"annual_rate,omitempty" form:"annual_rate" binding:"omitempty,min=0,max=100" example:"8.5"`
+}
+
+// PaymentCalculation is the fixed monthly payment of an amortized loan and what it costs in total
+type PaymentCalculation struct {
+	LoanAmount     float64           `json:"loan_amount"`
+	TermMonths     int               `json:"term_months"`
+	AnnualRate     float64           `json:"annual_rate"`
+	MonthlyPayment float64           `json:"monthly_payment"`
+	TotalInterest  float64           `json:"total_interest"`
+	TotalPaid      float64           `json:"total_paid"`
+	Formatted      map[string]string `json:"formatted"` // amounts written in the request's language
+}
+
+// DTICalculationRequest asks for a debt-to-income ratio, optionally including the payment of a
+// prospective loan
+type DTICalculationRequest struct {
+	MonthlyIncome float64  `json:"monthly_income" form:"monthly_income" binding:"required,gt=0" example:"6000"`
+	MonthlyDebt   float64  `json:"monthly_debt_payments" form:"monthly_debt_payments" binding:"min=0" example:"1200"`
+	LoanAmount    float64  `json:"loan_amount,omitempty" form:"loan_amount" binding:"omitempty,gt=0" example:"25000"`
+	TermMonths    int      `json:"term_months,omitempty" form:"term_months" binding:"omitempty,min=12,max=84" example:"60"`
+	AnnualRate    *float64 `json:"annual_rate,omitempty" form:"annual_rate" binding:"omitempty,min=0,max=100" example:"8.5"`
+	ObligationsQuery
+}
+
+// ObligationsQuery holds the debts and income the calculators qualify under the lender's debt
+// treatments rather than taking as stated; they are not part of monthly_debt_payments
+type ObligationsQuery struct {
+	StudentLoanBalance     float64 `json:"student_loan_balance,omitempty" form:"student_loan_balance" binding:"min=0" example:"30000"`
+	StudentLoanPayment     float64 `json:"student_loan_payment,omitempty" form:"student_loan_payment" binding:"min=0" example:"0"`
+	StudentLoanIncomeBased bool    `json:"student_loan_income_based,omitempty" form:"student_loan_income_based"`
+	AlimonyPayment         float64 `json:"alimony_payment,omitempty" form:"alimony_payment" binding:"min=0" example:"500"`
+	GrossRentalIncome      float64 `json:"gross_rental_income,omitempty" form:"gross_rental_income" binding:"min=0" example:"1800"`
+	RentalPropertyPayment  float64 `json:"rental_property_payment,omitempty" form:"rental_property_payment" binding:"min=0" example:"1100"`
+}
+
+// Obligations returns the queried obligations, nil when there are none
+func (q ObligationsQuery) Obligations() *dti.Obligations {
+	if q == (ObligationsQuery{}) {
+		return nil
+	}
+	obligations := &dti.Obligations{
+		AlimonyPayment:        q.AlimonyPayment,
+		GrossRentalIncome:     q.GrossRentalIncome,
+		RentalPropertyPayment: q.RentalPropertyPayment,
+	}
+	if q.StudentLoanBalance > 0 || q.StudentLoanPayment > 0 {
+		obligations.StudentLoans = []dti.StudentLoan{{
+			Balance:        q.StudentLoanBalance,
+			MonthlyPayment: q.StudentLoanPayment,
+			IncomeBased:    q.StudentLoanIncomeBased,
+		}}
+	}
+	return obligations
+}
+
+// DTICalculation is a debt-to-income ratio against the lender's limit. With a prospective loan the
+// ratio after taking it on is included.
+type DTICalculation struct {
+	DTIRatio         float64           `json:"dti_ratio"`
+	LoanPayment      *float64          `json:"loan_payment,omitempty"`
+	DTIRatioWithLoan *float64          `json:"dti_ratio_with_loan,omitempty"`
+	MaxDTIRatio      float64           `json:"max_dti_ratio"`
+	WithinLimit      bool              `json:"within_limit"`
+	Adjustments      []dti.Adjustment  `json:"adjustments,omitempty"` // debt treatments applied to the obligations
+	Formatted        map[string]string `json:"formatted"`
+}
+
+// AffordabilityRequest asks for the largest loan an income and its debts support; without a term
+// or rate the lender's sizing term and highest rate are used, as in pre-qualification
+type AffordabilityRequest struct {
+	MonthlyIncome float64  `json:"monthly_income" form:"monthly_income" binding:"required,gt=0" example:"6000"`
+	MonthlyDebt   float64  `json:"monthly_debt_payments" form:"monthly_debt_payments" binding:"min=0" example:"1200"`
+	TermMonths    int      `json:"term_months,omitempty" form:"term_months" binding:"omitempty,min=12,max=84" example:"60"`
+	AnnualRate    *float64 `json:"annual_rate,omitempty" form:"annual_rate" binding:"omitempty,min=0,max=100" example:"8.5"`
+	ObligationsQuery
+}
+
+// AffordabilityCalculation is the largest payment and loan an income supports under the lender's
+// payment share and DTI limits. MaxLoanAmount is 0 when it would fall below the minimum loan.
+type AffordabilityCalculation struct {
+	TermMonths        int               `json:"term_months"`
+	AnnualRate        float64           `json:"annual_rate"`
+	MaxMonthlyPayment float64           `json:"max_monthly_payment"`
+	MaxLoanAmount     float64           `json:"max_loan_amount"`
+	MinLoanAmount     float64           `json:"min_loan_amount"`
+	Formatted         map[string]string `json:"formatted"`
+}
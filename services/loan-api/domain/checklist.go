@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"time"
+)
+
+// Additional document types required by checklist rules and underwriting conditions
+const (
+	DocumentTaxReturns     = "tax_returns"
+	DocumentAssetStatement = "asset_statement"
+	DocumentProofOfAddress = "proof_of_address"
+)
+
+// Borrowers a document requirement applies to
+const (
+	ChecklistBorrower   = "borrower"
+	ChecklistCoBorrower = "co_borrower"
+	ChecklistAll        = "all" // rules only: both borrowers
+)
+
+// DocumentRequirementStatus is the collection status of a required document
+type DocumentRequirementStatus string
+
+const (
+	DocumentRequirementRequired DocumentRequirementStatus = "required"
+	DocumentRequirementReceived DocumentRequirementStatus = "received"
+	DocumentRequirementWaived   DocumentRequirementStatus = "waived"
+)
+
+// Where a document requirement came from
+const (
+	RequirementSourceRule      = "rule"
+	RequirementSourceCondition = "condition"
+	RequirementSourceDefault   = "default" // no rules were configured
+)
+
+// DocumentRule requires a document type from borrowers on applications it matches. Empty or nil
+// criteria match anything. Rules with a condition type only apply once an underwriting condition
+// of that type is added to the application.
+type DocumentRule struct {
+	ID                 string             `json:"id" db:"id"`
+	Product            string             `json:"product" db:"product"`
+	DocumentType       string             `json:"document_type" db:"document_type"`
+	AppliesTo          string             `json:"applies_to" db:"applies_to"`
+	MinLoanAmount      *float64           `json:"min_loan_amount,omitempty" db:"min_loan_amount"`
+	MaxLoanAmount      *float64           `json:"max_loan_amount,omitempty" db:"max_loan_amount"`
+	EmploymentStatuses []EmploymentStatus `json:"employment_statuses,omitempty" db:"employment_statuses"`
+	ConditionType      string             `json:"condition_type,omitempty" db:"condition_type"`
+	Description        string             `json:"description" db:"description"`
+}
+
+// Matches reports whether the rule requires its document from the given borrower, who has the
+// given employment status, on the application. conditionType is empty for the base checklist.
+func (r *DocumentRule) Matches(app *LoanApplication, borrower string, status EmploymentStatus, conditionType string) bool {
+	if r.Product != "" && r.Product != ProductPersonalLoan {
+		return false
+	}
+	if r.ConditionType != conditionType {
+		return false
+	}
+	if r.AppliesTo != ChecklistAll && r.AppliesTo != borrower {
+		return false
+	}
+	if r.MinLoanAmount != nil && app.LoanAmount < *r.MinLoanAmount {
+		return false
+	}
+	if r.MaxLoanAmount != nil && app.LoanAmount > *r.MaxLoanAmount {
+		return false
+	}
+	if len(r.EmploymentStatuses) == 0 {
+		return true
+	}
+	for _, s := range r.EmploymentStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// DocumentRequirement is a document an application's checklist requires from one of its borrowers
+type DocumentRequirement struct {
+	ID            string                    `json:"id" db:"id"`
+	ApplicationID string                    `json:"application_id" db:"application_id"`
+	Borrower      string                    `json:"borrower" db:"borrower"`
+	DocumentType  string                    `json:"document_type" db:"document_type"`
+	Source        string                    `json:"source" db:"source"`
+	RuleID        *string                   `json:"rule_id,omitempty" db:"rule_id"`
+	ConditionID   *string                   `json:"condition_id,omitempty" db:"condition_id"`
+	Description   string                    `json:"description,omitempty" db:"description"`
+	Status        DocumentRequirementStatus `json:"status" db:"status"`
+	DocumentID    *string                   `json:"document_id,omitempty" db:"document_id"` // the upload that satisfied it
+	CreatedAt     time.Time                 `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time                 `json:"updated_at" db:"updated_at"`
+}
+
+// DocumentChecklistStatus is the collection progress of an application's document checklist
+type DocumentChecklistStatus struct {
+	ApplicationID string                 `json:"application_id"`
+	Status        string                 `json:"status"` // pending or complete
+	TotalRequired int                    `json:"total_required"`
+	Collected     int                    `json:"collected"`
+	Pending       int                    `json:"pending"`
+	Requirements  []*DocumentRequirement `json:"requirements"`
+}
+
+// NewDocumentChecklistStatus summarizes the requirements of an application's checklist
+func NewDocumentChecklistStatus(applicationID string, requirements []*DocumentRequirement) *DocumentChecklistStatus {
+	status := &DocumentChecklistStatus{
+		ApplicationID: applicationID,
+		Status:        "complete",
+		Requirements:  requirements,
+	}
+	for _, requirement := range requirements {
+		switch requirement.Status {
+		case DocumentRequirementRequired:
+			status.TotalRequired++
+			status.Pending++
+			status.Status = "pending"
+		case DocumentRequirementReceived:
+			status.TotalRequired++
+			status.Collected++
+		}
+	}
+	return status
+}
+
+// NewDocumentChecklist lists the document types each borrower still has to provide or has
+// provided; waived requirements are left out
+func NewDocumentChecklist(requirements []*DocumentRequirement) *DocumentChecklist {
+	checklist := &DocumentChecklist{}
+	for _, requirement := range requirements {
+		if requirement.Status == DocumentRequirementWaived {
+			continue
+		}
+		if requirement.Borrower == ChecklistCoBorrower {
+			checklist.CoBorrower = append(checklist.CoBorrower, requirement.DocumentType)
+		} else {
+			checklist.Borrower = append(checklist.Borrower, requirement.DocumentType)
+		}
+	}
+	return checklist
+}
+
+// uploadedDocumentTypes maps checklist document types to the user service document types that
+// satisfy them
+var uploadedDocumentTypes = map[string][]string{
+	DocumentIncomeVerification:     {"pay_stub", "w2", "1099"},
+	DocumentEmploymentVerification: {"pay_stub", "w2"},
+	DocumentBankStatements:         {"bank_statement"},
+	DocumentIdentification:         {"drivers_license", "passport"},
+	DocumentTaxReturns:             {"w2", "1099"},
+	DocumentAssetStatement:         {"bank_statement"},
+	DocumentProofOfAddress:         {"utility_bill", "bank_statement"},
+}
+
+// SatisfiedBy reports whether an uploaded document of the given user service type satisfies the
+// checklist document type
+func SatisfiedBy(documentType, uploadedType string) bool {
+	for _, t := range uploadedDocumentTypes[documentType] {
+		if t == uploadedType {
+			return true
+		}
+	}
+	return documentType == uploadedType
+}
+
+// OutstandingRequirement returns the borrower's outstanding requirement that an uploaded document
+// of the given user service type satisfies. When checklistType is set only a requirement for that
+// checklist document type is considered.
+func OutstandingRequirement(requirements []*DocumentRequirement, uploadedType, checklistType string) *DocumentRequirement {
+	for _, requirement := range requirements {
+		if requirement.Borrower != ChecklistBorrower || requirement.Status != DocumentRequirementRequired {
+			continue
+		}
+		if checklistType != "" && requirement.DocumentType != checklistType {
+			continue
+		}
+		if SatisfiedBy(requirement.DocumentType, uploadedType) {
+			return requirement
+		}
+	}
+	return nil
+}
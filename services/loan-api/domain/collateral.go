@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// CollateralType is the kind of asset securing a loan
+type CollateralType string
+
+const (
+	CollateralVehicle CollateralType = "vehicle"
+	CollateralAsset   CollateralType = "asset" // other titled or serialized property, e.g. equipment or a boat
+)
+
+// IsValid checks if the collateral type is one of the known types
+func (t CollateralType) IsValid() bool {
+	return t == CollateralVehicle || t == CollateralAsset
+}
+
+// ValuationSourceStated is the valuation source of collateral valued at what the borrower stated
+const ValuationSourceStated = "stated"
+
+// VehicleDetails identifies a vehicle pledged as collateral
+type VehicleDetails struct {
+	VIN       string `json:"vin" binding:"omitempty,len=17" example:"1HGCM82633A004352"`
+	Year      int    `json:"year" example:"2021"`
+	Make      string `json:"make" example:"Honda"`
+	Model     string `json:"model" example:"Accord"`
+	Trim      string `json:"trim,omitempty" example:"EX-L"`
+	Mileage   int    `json:"mileage" example:"32000"`
+	Condition string `json:"condition,omitempty" example:"good"` // excellent, good, fair or poor
+}
+
+// AssetDetails describes collateral other than a vehicle
+type AssetDetails struct {
+	Category     string `json:"category" example:"equipment"`
+	Description  string `json:"description" example:"2019 Kubota L3901 tractor"`
+	SerialNumber string `json:"serial_number,omitempty"`
+}
+
+// CollateralRequest pledges an asset to secure an application
+type CollateralRequest struct {
+	Type        CollateralType  `json:"type" binding:"required" example:"vehicle"`
+	Vehicle     *VehicleDetails `json:"vehicle,omitempty"`
+	Asset       *AssetDetails   `json:"asset,omitempty"`
+	StatedValue float64         `json:"stated_value" binding:"required,gt=0" example:"28000"`
+}
+
+// Collateral is an asset securing an application, with its latest valuation and the application's
+// loan-to-value ratio at that valuation
+type Collateral struct {
+	ID                 string          `json:"id" db:"id"`
+	ApplicationID      string          `json:"application_id" db:"application_id"`
+	Type               CollateralType  `json:"type" db:"collateral_type"`
+	Vehicle            *VehicleDetails `json:"vehicle,omitempty" db:"vehicle"`
+	Asset              *AssetDetails   `json:"asset,omitempty" db:"asset"`
+	StatedValue        float64         `json:"stated_value" db:"stated_value"`
+	Value              float64         `json:"value" db:"value"`
+	ValuationSource    string          `json:"valuation_source" db:"valuation_source"`
+	ValuationReference *string         `json:"valuation_reference,omitempty" db:"valuation_reference"`
+	ValuedAt           time.Time       `json:"valued_at" db:"valued_at"`
+	LTVRatio           float64         `json:"ltv_ratio" db:"ltv_ratio"`
+	CreatedAt          time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// CollateralValuation is what a valuation provider reports an asset is worth
+type CollateralValuation struct {
+	Source    string    `json:"source"`
+	Value     float64   `json:"value"`
+	Reference string    `json:"reference,omitempty"` // the provider's identifier of the valuation
+	ValuedAt  time.Time `json:"valued_at"`
+}
+
+// ApplyValuation records a valuation and the loan-to-value ratio of the given loan amount at it
+func (c *Collateral) ApplyValuation(valuation *CollateralValuation, loanAmount float64) {
+	c.Value = valuation.Value
+	c.ValuationSource = valuation.Source
+	c.ValuationReference = nil
+	if valuation.Reference != "" {
+		c.ValuationReference = &valuation.Reference
+	}
+	c.ValuedAt = valuation.ValuedAt
+	c.LTVRatio = LoanToValue(loanAmount, valuation.Value)
+}
+
+// LoanToValue is the ratio of a loan amount to the value of the collateral securing it, rounded to
+// four decimal places; zero when the collateral has no value
+func LoanToValue(loanAmount, value float64) float64 {
+	if value <= 0 {
+		return 0
+	}
+	return math.Round(loanAmount/value*10000) / 10000
+}
+
+// Validate validates a collateral pledge: vehicles must be identified by VIN, year, make and model,
+// other assets by category and description
+func (req *CollateralRequest) Validate() *ValidationResult {
+	result := &ValidationResult{
+		Valid:  true,
+		Errors: make(map[string]string),
+	}
+
+	if !req.Type.IsValid() {
+		result.Valid = false
+		result.Errors["type"] = LOAN_020
+	}
+	if req.StatedValue <= 0 {
+		result.Valid = false
+		result.Errors["stated_value"] = LOAN_020
+	}
+
+	switch req.Type {
+	case CollateralVehicle:
+		if req.Vehicle == nil {
+			result.Valid = false
+			result.Errors["vehicle"] = LOAN_020
+			break
+		}
+		if len(req.Vehicle.VIN) != 17 {
+			result.Valid = false
+			result.Errors["vehicle.vin"] = LOAN_020
+		}
+		if req.Vehicle.Year < 1980 || req.Vehicle.Year > time.Now().Year()+1 {
+			result.Valid = false
+			result.Errors["vehicle.year"] = LOAN_020
+		}
+		if req.Vehicle.Make == "" {
+			result.Valid = false
+			result.Errors["vehicle.make"] = LOAN_020
+		}
+		if req.Vehicle.Model == "" {
+			result.Valid = false
+			result.Errors["vehicle.model"] = LOAN_020
+		}
+		if req.Vehicle.Mileage < 0 {
+			result.Valid = false
+			result.Errors["vehicle.mileage"] = LOAN_020
+		}
+	case CollateralAsset:
+		if req.Asset == nil {
+			result.Valid = false
+			result.Errors["asset"] = LOAN_020
+			break
+		}
+		if req.Asset.Category == "" {
+			result.Valid = false
+			result.Errors["asset.category"] = LOAN_020
+		}
+		if req.Asset.Description == "" {
+			result.Valid = false
+			result.Errors["asset.description"] = LOAN_020
+		}
+	}
+
+	return result
+}
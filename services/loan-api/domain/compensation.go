@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// CompensationAction is a step that undoes a side effect of a workflow that failed midway
+type CompensationAction string
+
+const (
+	CompensationRollbackState  CompensationAction = "rollback_state"  // return the application to its state before the workflow
+	CompensationVoidOffers     CompensationAction = "void_offers"     // void the offers the workflow made
+	CompensationNotifyBorrower CompensationAction = "notify_borrower" // tell the borrower processing stopped
+)
+
+// CompensationRequest asks for one compensation step for a workflow that failed
+// @Description Compensation step for a failed workflow
+type CompensationRequest struct {
+	Action       CompensationAction `json:"action" binding:"required,oneof=rollback_state void_offers notify_borrower" example:"rollback_state"`
+	WorkflowID   string             `json:"workflow_id" binding:"required" example:"5f1c7a2e-3b4d-4e8f-9a0b-1c2d3e4f5a6b"`
+	WorkflowName string             `json:"workflow_name,omitempty" example:"loan_processing_workflow"`
+	// WorkflowStartedAt is used when the workflow was not started through the loan service and
+	// has no execution record
+	WorkflowStartedAt *time.Time `json:"workflow_started_at,omitempty"`
+	Reason            string     `json:"reason,omitempty" binding:"max=1000" example:"Task generate_loan_documents failed"`
+}
+
+// Compensation is the outcome of a compensation step. Steps are safe to repeat: a step with
+// nothing left to undo is reported as not applied, with the reason.
+type Compensation struct {
+	ApplicationID string             `json:"application_id"`
+	WorkflowID    string             `json:"workflow_id"`
+	Action        CompensationAction `json:"action"`
+	Applied       bool               `json:"applied"`
+	Reason        string             `json:"reason,omitempty"` // why it was not applied
+	FromState     *ApplicationState  `json:"from_state,omitempty"`
+	ToState       *ApplicationState  `json:"to_state,omitempty"`
+	VoidedOffers  []string           `json:"voided_offers,omitempty"`
+	// DisbursementCancelled is set when rolling back cancelled a disbursement awaiting funding
+	DisbursementCancelled bool      `json:"disbursement_cancelled"`
+	CompletedAt           time.Time `json:"completed_at"`
+}
+
+// IsCompensable reports whether a failed workflow's side effects on the application can still be
+// undone; a funded loan is serviced as it stands and a withdrawn application is left withdrawn
+func (app *LoanApplication) IsCompensable() bool {
+	switch app.CurrentState {
+	case StateFunded, StateActive, StateClosed, StateWithdrawn:
+		return false
+	}
+	return true
+}
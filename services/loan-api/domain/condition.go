@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"time"
+)
+
+// ConditionStatus is where an underwriting condition stands in its lifecycle
+type ConditionStatus string
+
+const (
+	ConditionPending  ConditionStatus = "pending"  // waiting for the borrower's evidence
+	ConditionReceived ConditionStatus = "received" // the borrower uploaded evidence
+	ConditionWaived   ConditionStatus = "waived"   // an underwriter no longer requires it
+	ConditionExpired  ConditionStatus = "expired"  // its due date passed while pending
+)
+
+// IsCleared reports whether the condition no longer holds up the loan
+func (s ConditionStatus) IsCleared() bool {
+	return s == ConditionReceived || s == ConditionWaived
+}
+
+// Condition priorities, as assigned by the underwriting decision
+const (
+	ConditionPriorityCritical = "critical"
+	ConditionPriorityHigh     = "high"
+	ConditionPriorityMedium   = "medium"
+	ConditionPriorityLow      = "low"
+)
+
+// Outcomes the underwriting workflow's conditions task is completed with
+const (
+	ConditionsCleared = "CLEARED" // every critical condition was received or waived
+	ConditionsExpired = "EXPIRED" // a critical condition expired
+)
+
+// UnderwritingCondition is a condition placed on an application, which may require further
+// documents. Conditions of a conditional approval are recorded by the underwriting worker with the
+// workflow waiting on them; once every critical one is received or waived the workflow's waiting
+// task (TaskReferenceName) is completed and the approval goes ahead.
+type UnderwritingCondition struct {
+	ID                  string          `json:"id" db:"id"`
+	ApplicationID       string          `json:"application_id" db:"application_id"`
+	ConditionType       string          `json:"condition_type" db:"condition_type"`
+	Description         string          `json:"description" db:"description"`
+	DocumentTypes       []string        `json:"document_types,omitempty" db:"document_types"`
+	Priority            string          `json:"priority" db:"priority"`
+	Critical            bool            `json:"critical" db:"critical"`
+	Status              ConditionStatus `json:"status" db:"status"`
+	EvidenceDocumentIDs []string        `json:"evidence_document_ids,omitempty" db:"evidence_document_ids"`
+	WorkflowInstanceID  *string         `json:"workflow_instance_id,omitempty" db:"workflow_instance_id"`
+	TaskReferenceName   *string         `json:"task_reference_name,omitempty" db:"task_reference_name"`
+	DueAt               *time.Time      `json:"due_at,omitempty" db:"due_at"`
+	ResolvedAt          *time.Time      `json:"resolved_at,omitempty" db:"resolved_at"`
+	ResolvedBy          *string         `json:"resolved_by,omitempty" db:"resolved_by"`
+	ResolutionNote      string          `json:"resolution_note,omitempty" db:"resolution_note"`
+	CreatedBy           string          `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt           time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// AcceptsEvidence reports whether the borrower can still upload evidence for the condition
+func (c *UnderwritingCondition) AcceptsEvidence() bool {
+	return c.Status == ConditionPending || c.Status == ConditionReceived
+}
+
+// ConditionsOutcome returns the outcome a workflow waiting on the conditions is resumed with, or
+// an empty string while a critical condition is still pending
+func ConditionsOutcome(conditions []*UnderwritingCondition) string {
+	outcome := ConditionsCleared
+	for _, condition := range conditions {
+		if !condition.Critical {
+			continue
+		}
+		switch condition.Status {
+		case ConditionPending:
+			return ""
+		case ConditionExpired:
+			outcome = ConditionsExpired
+		}
+	}
+	return outcome
+}
+
+// AddConditionRequest represents a request to add an underwriting condition to an application
+// @Description Request to add an underwriting condition; its document types and any checklist rules for its type are added to the checklist
+type AddConditionRequest struct {
+	ConditionType string     `json:"condition_type" binding:"required,max=50" example:"income_reverification"`
+	Description   string     `json:"description" binding:"required,max=500" example:"Verify the most recent two months of income"`
+	DocumentTypes []string   `json:"document_types,omitempty" binding:"max=10,dive,required,max=50" example:"income_verification"`
+	Priority      string     `json:"priority,omitempty" binding:"omitempty,oneof=critical high medium low" example:"high"`
+	Critical      bool       `json:"critical,omitempty" example:"false"`
+	DueAt         *time.Time `json:"due_at,omitempty" example:"2024-02-15T00:00:00Z"`
+	CreatedBy     string     `json:"created_by,omitempty" example:"2b1e6f0a-5c3d-4e8f-9a7b-1c2d3e4f5a6b"`
+}
+
+// WaiveConditionRequest is an underwriter's reason for no longer requiring a condition
+type WaiveConditionRequest struct {
+	Reason string `json:"reason" binding:"required,max=500" example:"Employment confirmed by phone with the employer"`
+}
+
+// ConditionEvidenceResult is an uploaded piece of evidence together with the condition it was
+// recorded against
+type ConditionEvidenceResult struct {
+	Document  *BorrowerDocument      `json:"document"`
+	Condition *UnderwritingCondition `json:"condition"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// CreditConsent is a borrower's explicit consent to a hard credit pull for an application, kept as
+// evidence of the permissible purpose for the inquiry underwriting makes
+type CreditConsent struct {
+	ID                string    `json:"id" db:"id"`
+	ApplicationID     string    `json:"application_id" db:"application_id"`
+	UserID            string    `json:"user_id" db:"user_id"`
+	DisclosureVersion string    `json:"disclosure_version" db:"disclosure_version"` // the disclosure the borrower was shown
+	IPAddress         string    `json:"ip_address" db:"ip_address"`
+	UserAgent         string    `json:"user_agent,omitempty" db:"user_agent"`
+	ConsentedAt       time.Time `json:"consented_at" db:"consented_at"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreditConsentRequest records a borrower's consent to the credit pull disclosure they were shown
+type CreditConsentRequest struct {
+	DisclosureVersion string `json:"disclosure_version" binding:"required" example:"2024-01"`
+	Accepted          bool   `json:"accepted" example:"true"` // must be true
+}
+
+// CreditPullEligibility tells the decision engine whether an application may trigger a hard
+// credit pull: the application must have been submitted, must not be closed out and the borrower
+// must have consented to the pull. Pre-qualification before submission uses soft pulls.
+type CreditPullEligibility struct {
+	ApplicationID string           `json:"application_id"`
+	UserID        string           `json:"user_id"`
+	State         ApplicationState `json:"state"`
+	Submitted     bool             `json:"submitted"`
+	Consented     bool             `json:"consented"`
+	Eligible      bool             `json:"eligible"`
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"time"
+)
+
+// DeadLetterStatus is where a dead-lettered workflow task stands with operators
+type DeadLetterStatus string
+
+const (
+	DeadLetterOpen        DeadLetterStatus = "open"        // waiting for an operator
+	DeadLetterResubmitted DeadLetterStatus = "resubmitted" // workflow re-run from the task
+	DeadLetterDiscarded   DeadLetterStatus = "discarded"   // closed without re-running
+)
+
+// IsValid checks if the status is one of the known dead-letter statuses
+func (s DeadLetterStatus) IsValid() bool {
+	switch s {
+	case DeadLetterOpen, DeadLetterResubmitted, DeadLetterDiscarded:
+		return true
+	default:
+		return false
+	}
+}
+
+// DeadLetterTask is a workflow task a worker gave up on: it failed with a terminal error, or with a
+// transient one after every retry. It keeps the task's input and failure so an operator can fix
+// the input and re-run the workflow from the task.
+type DeadLetterTask struct {
+	ID                 string                 `json:"id" db:"id"`
+	TaskID             string                 `json:"task_id" db:"task_id"`
+	TaskType           string                 `json:"task_type" db:"task_type"`
+	WorkflowInstanceID string                 `json:"workflow_instance_id" db:"workflow_instance_id"`
+	ApplicationID      *string                `json:"application_id,omitempty" db:"application_id"`
+	Input              map[string]interface{} `json:"input" db:"input"`
+	Output             map[string]interface{} `json:"output,omitempty" db:"output"`
+	Error              string                 `json:"error" db:"error"`
+	Terminal           bool                   `json:"terminal" db:"terminal"`                   // retrying would fail the same way
+	Attempts           int                    `json:"attempts" db:"attempts"`                   // worker attempts on the last delivery
+	ConductorRetries   int                    `json:"conductor_retries" db:"conductor_retries"` // deliveries Conductor retried
+	Status             DeadLetterStatus       `json:"status" db:"status"`
+	ResolvedBy         *string                `json:"resolved_by,omitempty" db:"resolved_by"`
+	ResolutionNote     string                 `json:"resolution_note,omitempty" db:"resolution_note"`
+	FailedAt           time.Time              `json:"failed_at" db:"failed_at"`
+	UpdatedAt          time.Time              `json:"updated_at" db:"updated_at"`
+	ResolvedAt         *time.Time             `json:"resolved_at,omitempty" db:"resolved_at"`
+}
+
+// DeadLetterQuery filters the dead-letter listing
+type DeadLetterQuery struct {
+	Status        DeadLetterStatus `form:"status"`
+	TaskType      string           `form:"task_type"`
+	ApplicationID string           `form:"application_id"`
+	Limit         int              `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// UpdateDeadLetterInputRequest replaces the input a dead-lettered task is re-run with
+type UpdateDeadLetterInputRequest struct {
+	Input map[string]interface{} `json:"input" binding:"required"`
+	Note  string                 `json:"note"`
+}
+
+// ResolveDeadLetterRequest records why an operator re-submitted or discarded a dead-lettered task
+type ResolveDeadLetterRequest struct {
+	Note string `json:"note"`
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// Demographic answers a borrower may give to the government monitoring questions. NOT_PROVIDED
+// records that they chose not to answer.
+const (
+	DemographicNotProvided = "NOT_PROVIDED"
+
+	EthnicityHispanicOrLatino    = "HISPANIC_OR_LATINO"
+	EthnicityNotHispanicOrLatino = "NOT_HISPANIC_OR_LATINO"
+
+	RaceAmericanIndianOrAlaskaNative         = "AMERICAN_INDIAN_OR_ALASKA_NATIVE"
+	RaceAsian                                = "ASIAN"
+	RaceBlackOrAfricanAmerican               = "BLACK_OR_AFRICAN_AMERICAN"
+	RaceNativeHawaiianOrOtherPacificIslander = "NATIVE_HAWAIIAN_OR_OTHER_PACIFIC_ISLANDER"
+	RaceWhite                                = "WHITE"
+
+	SexMale   = "MALE"
+	SexFemale = "FEMALE"
+
+	// DemographicsCollectedOnline is how demographics given through the application are collected
+	DemographicsCollectedOnline = "ONLINE_APPLICATION"
+)
+
+// ApplicantDemographics is the borrower's answers to the government monitoring questions for an
+// application. They are kept apart from the application and never read by underwriting; they are
+// only copied into the compliance record captured when the application is decided.
+type ApplicantDemographics struct {
+	ID               string    `json:"id" db:"id"`
+	ApplicationID    string    `json:"application_id" db:"application_id"`
+	UserID           string    `json:"user_id" db:"user_id"`
+	Ethnicity        []string  `json:"ethnicity" db:"ethnicity"`
+	Race             []string  `json:"race" db:"race"`
+	Sex              string    `json:"sex" db:"sex"`
+	CollectionMethod string    `json:"collection_method" db:"collection_method"`
+	CollectedAt      time.Time `json:"collected_at" db:"collected_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ApplicantDemographicsRequest records the borrower's answers to the government monitoring
+// questions; each question may be answered NOT_PROVIDED
+type ApplicantDemographicsRequest struct {
+	Ethnicity []string `json:"ethnicity" binding:"required,min=1,dive,oneof=HISPANIC_OR_LATINO NOT_HISPANIC_OR_LATINO NOT_PROVIDED" example:"NOT_HISPANIC_OR_LATINO"`
+	Race      []string `json:"race" binding:"required,min=1,dive,oneof=AMERICAN_INDIAN_OR_ALASKA_NATIVE ASIAN BLACK_OR_AFRICAN_AMERICAN NATIVE_HAWAIIAN_OR_OTHER_PACIFIC_ISLANDER WHITE NOT_PROVIDED" example:"ASIAN"`
+	Sex       string   `json:"sex" binding:"required,oneof=MALE FEMALE NOT_PROVIDED" example:"NOT_PROVIDED"`
+}
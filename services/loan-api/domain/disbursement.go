@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"time"
+)
+
+// DisbursementStatus is the state of the ACH credit that funds a loan
+type DisbursementStatus string
+
+const (
+	DisbursementPending   DisbursementStatus = "pending"   // waiting for the next funding batch
+	DisbursementQueued    DisbursementStatus = "queued"    // claimed by a funding batch that is submitting it
+	DisbursementSubmitted DisbursementStatus = "submitted" // originated with the provider, awaiting settlement
+	DisbursementCompleted DisbursementStatus = "completed"
+	DisbursementFailed    DisbursementStatus = "failed" // returned or rejected with no retries left
+)
+
+// IsFinal reports whether the disbursement can no longer change
+func (s DisbursementStatus) IsFinal() bool {
+	return s == DisbursementCompleted || s == DisbursementFailed
+}
+
+// retryableReturnCodes are the ACH return codes NACHA allows an originator to present again
+var retryableReturnCodes = map[string]bool{
+	"R01": true, // insufficient funds
+	"R09": true, // uncollected funds
+}
+
+// IsRetryableReturn reports whether a transfer returned with the code can be originated again
+func IsRetryableReturn(returnCode string) bool {
+	return retryableReturnCodes[returnCode]
+}
+
+// Disbursement is the transfer of a signed loan's proceeds to the borrower's bank account
+type Disbursement struct {
+	ID                   string             `json:"id" db:"id"`
+	ApplicationID        string             `json:"application_id" db:"application_id"`
+	OfferID              string             `json:"offer_id" db:"offer_id"`
+	BankAccountID        string             `json:"bank_account_id" db:"bank_account_id"`
+	BatchID              *string            `json:"batch_id,omitempty" db:"batch_id"`
+	Amount               float64            `json:"amount" db:"amount"`
+	PayoffApplicationID  *string            `json:"payoff_application_id,omitempty" db:"payoff_application_id"` // the loan a refinance pays off
+	PayoffAmount         float64            `json:"payoff_amount,omitempty" db:"payoff_amount"`
+	Provider             string             `json:"provider" db:"provider"`
+	ProviderTransferID   *string            `json:"provider_transfer_id,omitempty" db:"provider_transfer_id"`
+	DestinationReference *string            `json:"-" db:"destination_reference"` // the provider's record of the destination account
+	DestinationLast4     string             `json:"destination_last4" db:"destination_last4"`
+	Status               DisbursementStatus `json:"status" db:"status"`
+	Attempts             int                `json:"attempts" db:"attempts"`
+	NextAttemptAt        *time.Time         `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	ReturnCode           *string            `json:"return_code,omitempty" db:"return_code"`
+	FailureReason        *string            `json:"failure_reason,omitempty" db:"failure_reason"`
+	SubmittedAt          *time.Time         `json:"submitted_at,omitempty" db:"submitted_at"`
+	CompletedAt          *time.Time         `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt            time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time          `json:"updated_at" db:"updated_at"`
+
+	// Events is the disbursement's audit trail; populated when a single disbursement is retrieved
+	Events []*DisbursementEvent `json:"events,omitempty" db:"-"`
+}
+
+// DisbursementEventType is a step recorded in a disbursement's audit trail
+type DisbursementEventType string
+
+const (
+	DisbursementEventScheduled        DisbursementEventType = "scheduled"
+	DisbursementEventSubmitted        DisbursementEventType = "submitted"
+	DisbursementEventSubmissionFailed DisbursementEventType = "submission_failed"
+	DisbursementEventCompleted        DisbursementEventType = "completed"
+	DisbursementEventReturned         DisbursementEventType = "returned"
+	DisbursementEventRetryScheduled   DisbursementEventType = "retry_scheduled"
+	DisbursementEventFailed           DisbursementEventType = "failed"
+)
+
+// DisbursementEvent is an audit record of a disbursement status change
+type DisbursementEvent struct {
+	ID             string                 `json:"id" db:"id"`
+	DisbursementID string                 `json:"disbursement_id" db:"disbursement_id"`
+	ApplicationID  string                 `json:"application_id" db:"application_id"`
+	EventType      DisbursementEventType  `json:"event_type" db:"event_type"`
+	FromStatus     *DisbursementStatus    `json:"from_status,omitempty" db:"from_status"`
+	ToStatus       DisbursementStatus     `json:"to_status" db:"to_status"`
+	Detail         string                 `json:"detail,omitempty" db:"detail"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty" db:"metadata"`
+	CreatedAt      time.Time              `json:"created_at" db:"created_at"`
+}
+
+// FundingBatchStatus is the state of a funding batch
+type FundingBatchStatus string
+
+const (
+	FundingBatchOpen      FundingBatchStatus = "open" // disbursements are being submitted
+	FundingBatchSubmitted FundingBatchStatus = "submitted"
+)
+
+// FundingBatch is one funding run: the due disbursements originated together
+type FundingBatch struct {
+	ID             string             `json:"id" db:"id"`
+	Provider       string             `json:"provider" db:"provider"`
+	Status         FundingBatchStatus `json:"status" db:"status"`
+	SubmittedCount int                `json:"submitted_count" db:"submitted_count"`
+	FailedCount    int                `json:"failed_count" db:"failed_count"` // could not be originated this run
+	TotalAmount    float64            `json:"total_amount" db:"total_amount"` // of the disbursements submitted
+	CreatedAt      time.Time          `json:"created_at" db:"created_at"`
+	SubmittedAt    *time.Time         `json:"submitted_at,omitempty" db:"submitted_at"`
+
+	// Disbursements are the batch's disbursements; populated when a single batch is retrieved
+	Disbursements []*Disbursement `json:"disbursements,omitempty" db:"-"`
+}
+
+// DisbursementDestination is the bank account a disbursement is credited to
+type DisbursementDestination struct {
+	UserID        string // the borrower, who the provider registers the account under
+	Name          string
+	Email         string
+	BankName      string
+	AccountType   AccountType
+	AccountNumber string
+	RoutingNumber string
+}
+
+// TransferEvent is a provider's notification that a transfer settled or failed
+type TransferEvent struct {
+	ProviderTransferID string
+	Status             DisbursementStatus // completed or failed
+	ReturnCode         string             // the ACH return code of a failed transfer, when known
+	Reason             string
+	OccurredAt         time.Time
+}
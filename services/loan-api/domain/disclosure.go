@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"io"
+	"time"
+)
+
+// Disclosures generated from application and offer data alongside the loan agreement, and the
+// letters telling the borrower of the underwriting decision
+const (
+	DocumentTILADisclosure      = "tila_disclosure"
+	DocumentAdverseActionNotice = "adverse_action_notice"
+	DocumentApprovalLetter      = "approval_letter"
+)
+
+// IsGeneratedDocumentType reports whether the loan service generates documents of the type
+func IsGeneratedDocumentType(documentType string) bool {
+	switch documentType {
+	case DocumentLoanAgreement, DocumentTILADisclosure, DocumentAdverseActionNotice, DocumentApprovalLetter:
+		return true
+	default:
+		return false
+	}
+}
+
+// GeneratedDocument is a document rendered for an application and stored with the borrower's
+// documents in the user service
+type GeneratedDocument struct {
+	ID               string     `json:"id" db:"id"`
+	ApplicationID    string     `json:"application_id" db:"application_id"`
+	DocumentType     string     `json:"document_type" db:"document_type"`
+	OfferID          *string    `json:"offer_id,omitempty" db:"offer_id"`           // the offer the terms were taken from
+	StoredDocumentID string     `json:"stored_document_id" db:"stored_document_id"` // the user service document
+	FileName         string     `json:"file_name" db:"file_name"`
+	MimeType         string     `json:"mime_type" db:"mime_type"`
+	Size             int64      `json:"size" db:"size"`
+	GeneratedBy      *string    `json:"generated_by,omitempty" db:"generated_by"` // nil when generated automatically
+	ReasonCodes      []string   `json:"reason_codes,omitempty" db:"reason_codes"` // the decision's reason codes, on decision letters
+	DeliveredAt      *time.Time `json:"delivered_at,omitempty" db:"delivered_at"` // when a decision letter was sent to the borrower
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+}
+
+// GenerateDocumentRequest represents a request to generate a document for an application
+// @Description Request to generate a loan document
+type GenerateDocumentRequest struct {
+	DocumentType string `json:"document_type" binding:"required,oneof=loan_agreement tila_disclosure adverse_action_notice approval_letter" example:"tila_disclosure"`
+}
+
+// DecisionLetterRequest asks for the letter telling the borrower of the underwriting decision on
+// their application: an approval letter with the approved terms, or an adverse action notice with
+// the principal reasons for the denial
+type DecisionLetterRequest struct {
+	Decision       string                 `json:"decision" binding:"required,oneof=approved denied" example:"denied"`
+	Reasons        []DecisionLetterReason `json:"reasons,omitempty"` // most significant first
+	ApprovedAmount float64                `json:"approved_amount,omitempty" example:"25000"`
+	TermMonths     int                    `json:"term_months,omitempty" example:"36"`
+	InterestRate   float64                `json:"interest_rate,omitempty" example:"8.5"`
+	APR            float64                `json:"apr,omitempty" example:"9.1"`
+}
+
+// DecisionLetterReason is a reason code of the decision and its description
+type DecisionLetterReason struct {
+	Code        string `json:"code" binding:"required" example:"DTI_TOO_HIGH"`
+	Description string `json:"description,omitempty" example:"Debt-to-income ratio too high"`
+}
+
+// DocumentData is the application data documents are rendered from
+type DocumentData struct {
+	Lender      string
+	Application *LoanApplication
+	Offer       *LoanOffer // nil for adverse action notices; the approved terms for approval letters
+	Borrower    *User
+	Reasons     []string // principal reasons for an adverse action
+	Date        time.Time
+}
+
+// BorrowerName is the borrower's full name
+func (d *DocumentData) BorrowerName() string {
+	return d.Borrower.FirstName + " " + d.Borrower.LastName
+}
+
+// TotalOfPayments is what the borrower will have paid after making every scheduled payment
+func (d *DocumentData) TotalOfPayments() float64 {
+	return d.Offer.MonthlyPayment * float64(d.Offer.TermMonths)
+}
+
+// FirstPaymentDate is when the first monthly payment falls due if the loan is funded on the
+// document's date
+func (d *DocumentData) FirstPaymentDate() time.Time {
+	return FirstPaymentDate(d.Date)
+}
+
+// RenderedDocument is a document produced from a template
+type RenderedDocument struct {
+	Title    string
+	FileName string
+	MimeType string
+	Content  []byte
+}
+
+// DocumentContent is a stored document's content, which the caller must close
+type DocumentContent struct {
+	FileName string
+	MimeType string
+	Size     int64
+	Content  io.ReadCloser
+}
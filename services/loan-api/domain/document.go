@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// MaxDocumentSize is the largest document file accepted for upload, matching the user service limit
+const MaxDocumentSize = 10 << 20
+
+// allowedDocumentMimeTypes are the document file formats accepted for upload
+var allowedDocumentMimeTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+}
+
+// BorrowerDocument is the metadata of a document the borrower uploaded to the user service
+type BorrowerDocument struct {
+	ID           string    `json:"id"`
+	DocumentType string    `json:"document_type"`
+	MimeType     string    `json:"mime_type"`
+	FileSize     int64     `json:"file_size"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// DocumentUpload is a borrower document being uploaded for an application. The content is
+// streamed to the user service rather than held in memory.
+type DocumentUpload struct {
+	DocumentType string // the user service document type, e.g. pay_stub
+	Requirement  string // the checklist document type it is uploaded for; empty to match any outstanding one
+	FileName     string
+	MimeType     string // detected from the content
+	Size         int64  // zero when the content is streamed and its length is not known up front
+	Content      io.Reader
+}
+
+// Validate checks the file's size and format. Streamed content is held to the size limit as it
+// is read, see LimitDocumentContent.
+func (u *DocumentUpload) Validate() *LoanError {
+	if u.Size < 0 || u.Size > MaxDocumentSize {
+		return documentTooLargeError()
+	}
+	if !allowedDocumentMimeTypes[u.MimeType] {
+		return &LoanError{
+			Code:        LOAN_038,
+			Message:     "Unsupported document format",
+			Description: fmt.Sprintf("Documents of type %s are not accepted", u.MimeType),
+			HTTPStatus:  415,
+		}
+	}
+	return nil
+}
+
+// LimitDocumentContent wraps streamed document content so that reading past MaxDocumentSize
+// fails with a document too large error
+func LimitDocumentContent(content io.Reader) io.Reader {
+	return &documentSizeLimiter{content: io.LimitReader(content, MaxDocumentSize+1)}
+}
+
+// documentSizeLimiter counts the bytes read from a document and fails once it exceeds the limit
+type documentSizeLimiter struct {
+	content io.Reader
+	read    int64
+}
+
+func (l *documentSizeLimiter) Read(p []byte) (int, error) {
+	n, err := l.content.Read(p)
+	l.read += int64(n)
+	if l.read > MaxDocumentSize {
+		return 0, documentTooLargeError()
+	}
+	return n, err
+}
+
+func documentTooLargeError() *LoanError {
+	return &LoanError{
+		Code:        LOAN_037,
+		Message:     "Document file too large",
+		Description: fmt.Sprintf("Document must be at most %d bytes", MaxDocumentSize),
+		HTTPStatus:  413,
+	}
+}
+
+// DocumentUploadResult is an uploaded document together with the checklist requirement it
+// satisfied and the checklist's progress afterwards
+type DocumentUploadResult struct {
+	Document    *BorrowerDocument        `json:"document"`
+	Requirement *DocumentRequirement     `json:"requirement"`
+	Checklist   *DocumentChecklistStatus `json:"checklist"`
+}
+
+// CanUploadDocuments reports whether documents are still being collected for the application.
+// Underwriting conditions may require further documents until a decision is made.
+func (app *LoanApplication) CanUploadDocuments() bool {
+	switch app.CurrentState {
+	case StateInitiated, StatePreQualified, StateDocumentsSubmitted, StateIdentityVerified,
+		StateUnderwriting, StateManualReview:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// ApplicationFingerprint is the identity signals an application was made with, compared against
+// other borrowers' recent applications to catch one person applying under several accounts
+type ApplicationFingerprint struct {
+	ApplicationID string    `json:"application_id" db:"application_id"`
+	UserID        string    `json:"user_id" db:"user_id"`
+	SSNToken      string    `json:"-" db:"ssn_token"` // vault tokens are deterministic, so equal SSNs share one
+	Email         string    `json:"email" db:"email_normalized"`
+	DeviceID      string    `json:"device_id,omitempty" db:"device_id"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// Signals an application can share with another borrower's application
+const (
+	DuplicateReasonSSN    = "ssn"
+	DuplicateReasonEmail  = "email"
+	DuplicateReasonDevice = "device"
+)
+
+// DuplicateMatchStatus is where a suspected duplicate is in admin review
+type DuplicateMatchStatus string
+
+const (
+	DuplicateMatchPending   DuplicateMatchStatus = "pending"
+	DuplicateMatchLinked    DuplicateMatchStatus = "linked"    // confirmed as the same applicant
+	DuplicateMatchDismissed DuplicateMatchStatus = "dismissed" // a false positive, e.g. a shared household device
+)
+
+// DuplicateMatch links an application to another borrower's application that shares identity
+// signals with it. Pending matches hold the application back from automated approval.
+type DuplicateMatch struct {
+	ID                     string               `json:"id" db:"id"`
+	ApplicationID          string               `json:"application_id" db:"application_id"`
+	CandidateApplicationID string               `json:"candidate_application_id" db:"candidate_application_id"`
+	CandidateUserID        string               `json:"candidate_user_id" db:"candidate_user_id"`
+	Score                  float64              `json:"score" db:"score"`
+	Reasons                []string             `json:"reasons" db:"reasons"`
+	Status                 DuplicateMatchStatus `json:"status" db:"status"`
+	ReviewedBy             *string              `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewNote             *string              `json:"review_note,omitempty" db:"review_note"`
+	ReviewedAt             *time.Time           `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt              time.Time            `json:"created_at" db:"created_at"`
+}
+
+// ReviewDuplicateMatchRequest records an admin's link or dismissal of a suspected duplicate
+type ReviewDuplicateMatchRequest struct {
+	ReviewedBy string `json:"reviewed_by" binding:"required" example:"admin@example.com"`
+	Note       string `json:"note,omitempty" example:"Same applicant, second account opened with a new email"`
+}
+
+// HasPendingDuplicateMatch reports whether any of the matches still awaits review
+func HasPendingDuplicateMatch(matches []*DuplicateMatch) bool {
+	for _, match := range matches {
+		if match.Status == DuplicateMatchPending {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeEmail folds the variations one mailbox can be written in: case, plus-addressing and, for
+// Gmail, dots in the local part
+func NormalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+
+	local, domainPart := email[:at], email[at+1:]
+	if plus := strings.Index(local, "+"); plus >= 0 {
+		local = local[:plus]
+	}
+	if domainPart == "googlemail.com" {
+		domainPart = "gmail.com"
+	}
+	if domainPart == "gmail.com" {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+
+	return local + "@" + domainPart
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+)
+
+// SignatureStatus is the state of an agreement sent for electronic signature
+type SignatureStatus string
+
+const (
+	SignatureSent      SignatureStatus = "sent"
+	SignatureDelivered SignatureStatus = "delivered" // the borrower opened the envelope
+	SignatureCompleted SignatureStatus = "completed"
+	SignatureDeclined  SignatureStatus = "declined"
+	SignatureVoided    SignatureStatus = "voided"
+)
+
+// IsFinal reports whether the envelope can no longer change
+func (s SignatureStatus) IsFinal() bool {
+	return s == SignatureCompleted || s == SignatureDeclined || s == SignatureVoided
+}
+
+// DocumentLoanAgreement is the user service document type signed agreements are stored as
+const DocumentLoanAgreement = "loan_agreement"
+
+// Anchors in the agreement where the provider places the borrower's signature and signing date
+const (
+	AgreementSignatureAnchor = "/borrower_signature/"
+	AgreementDateAnchor      = "/borrower_date/"
+)
+
+// SignatureEnvelope is a loan agreement sent to the borrower for electronic signature
+type SignatureEnvelope struct {
+	ID                 string          `json:"id" db:"id"`
+	ApplicationID      string          `json:"application_id" db:"application_id"`
+	OfferID            string          `json:"offer_id" db:"offer_id"`
+	Provider           string          `json:"provider" db:"provider"`
+	ProviderEnvelopeID string          `json:"provider_envelope_id" db:"provider_envelope_id"`
+	Status             SignatureStatus `json:"status" db:"status"`
+	SignedDocumentID   *string         `json:"signed_document_id,omitempty" db:"signed_document_id"` // the stored signed PDF
+	SentAt             time.Time       `json:"sent_at" db:"sent_at"`
+	CompletedAt        *time.Time      `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt          time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// SignatureEvent is a provider's notification that an envelope changed
+type SignatureEvent struct {
+	ProviderEnvelopeID string
+	Status             SignatureStatus
+	OccurredAt         time.Time
+}
+
+// Signer is the person an agreement is sent to for signature
+type Signer struct {
+	Name  string
+	Email string
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+)
+
+// IncomeVerificationStatus is where an application's income verification stands
+type IncomeVerificationStatus string
+
+const (
+	IncomeVerificationPending    IncomeVerificationStatus = "pending" // waiting for the borrower's payroll data
+	IncomeVerificationVerified   IncomeVerificationStatus = "verified"
+	IncomeVerificationUnverified IncomeVerificationStatus = "unverified" // left to document review
+	IncomeVerificationFailed     IncomeVerificationStatus = "failed"
+)
+
+// IncomeMethodPayroll is the verification method of income verified from payroll data
+const IncomeMethodPayroll = "payroll"
+
+// IncomeVerification is an application's income verification, recorded by the underwriting worker
+// and, for payroll verifications, completed when the payroll aggregator reports the borrower's pay
+// data has synced. The borrower connects their payroll account with the connect token.
+type IncomeVerification struct {
+	ID                    string                   `json:"id"`
+	ApplicationID         string                   `json:"application_id"`
+	UserID                string                   `json:"user_id"`
+	Method                string                   `json:"verification_method"`
+	Status                IncomeVerificationStatus `json:"verification_status"`
+	Provider              string                   `json:"provider,omitempty"`
+	ProviderUserID        string                   `json:"-"`
+	ConnectToken          string                   `json:"connect_token,omitempty"`
+	StatedAnnualIncome    float64                  `json:"stated_annual_income"`
+	VerifiedAnnualIncome  float64                  `json:"verified_annual_income"`
+	VerifiedMonthlyIncome float64                  `json:"verified_monthly_income"`
+	IncomeVariance        float64                  `json:"income_variance"`
+	IncomeVariancePercent float64                  `json:"income_variance_percent"`
+	IncomeVarianceFlagged bool                     `json:"income_variance_flagged"`
+	EmployerName          string                   `json:"employer_name,omitempty"`
+	VerificationNotes     string                   `json:"verification_notes,omitempty"`
+	VerifiedAt            time.Time                `json:"verified_at"`
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"time"
+)
+
+// ManualReviewStatus is where a manual review stands in the underwriters' queue
+type ManualReviewStatus string
+
+const (
+	ManualReviewPending   ManualReviewStatus = "pending"   // waiting for an underwriter to claim it
+	ManualReviewInReview  ManualReviewStatus = "in_review" // claimed by an underwriter
+	ManualReviewCompleted ManualReviewStatus = "completed" // decided; the workflow has the decision
+)
+
+// IsValid checks if the status is one of the known manual review statuses
+func (s ManualReviewStatus) IsValid() bool {
+	switch s {
+	case ManualReviewPending, ManualReviewInReview, ManualReviewCompleted:
+		return true
+	default:
+		return false
+	}
+}
+
+// ManualReviewType is why an application is in the review queue
+type ManualReviewType string
+
+const (
+	ManualReviewPrimary   ManualReviewType = "primary"   // the workflow routed the application to review
+	ManualReviewSecondary ManualReviewType = "secondary" // a large approval waits for a second underwriter's sign-off
+)
+
+// ManualReviewDecision is an underwriter's decision, as the underwriting workflow branches on it
+type ManualReviewDecision string
+
+const (
+	ManualReviewApprove     ManualReviewDecision = "APPROVE"
+	ManualReviewConditional ManualReviewDecision = "CONDITIONAL" // approve once the conditions clear
+	ManualReviewDeny        ManualReviewDecision = "DENY"
+)
+
+// ManualReview is an application the underwriting workflow routed to an underwriter. The
+// underwriting worker queues it; completing it completes the workflow's waiting human task with
+// the decision.
+type ManualReview struct {
+	ID                 string                `json:"id" db:"id"`
+	ApplicationID      string                `json:"application_id" db:"application_id"`
+	WorkflowInstanceID string                `json:"workflow_instance_id" db:"workflow_instance_id"`
+	TaskReferenceName  string                `json:"task_reference_name" db:"task_reference_name"`
+	Reason             string                `json:"reason,omitempty" db:"reason"`
+	RiskLevel          *string               `json:"risk_level,omitempty" db:"risk_level"`
+	Priority           string                `json:"priority" db:"priority"`
+	ReviewType         ManualReviewType      `json:"review_type" db:"review_type"`
+	PrimaryReviewerID  *string               `json:"primary_reviewer_id,omitempty" db:"primary_reviewer_id"` // first approver of a secondary review; nil for an automated approval
+	Status             ManualReviewStatus    `json:"status" db:"status"`
+	AssignedTo         *string               `json:"assigned_to,omitempty" db:"assigned_to"`
+	Decision           *ManualReviewDecision `json:"decision,omitempty" db:"decision"`
+	Notes              string                `json:"notes,omitempty" db:"notes"`
+	ApprovedAmount     *float64              `json:"approved_amount,omitempty" db:"approved_amount"`
+	InterestRate       *float64              `json:"interest_rate,omitempty" db:"interest_rate"`
+	Conditions         []string              `json:"conditions,omitempty" db:"conditions"`
+	DenialReasons      []string              `json:"denial_reasons,omitempty" db:"denial_reasons"`
+	DueAt              time.Time             `json:"due_at" db:"due_at"`
+	CreatedAt          time.Time             `json:"created_at" db:"created_at"`
+	ClaimedAt          *time.Time            `json:"claimed_at,omitempty" db:"claimed_at"`
+	CompletedAt        *time.Time            `json:"completed_at,omitempty" db:"completed_at"`
+	CompletedBy        *string               `json:"completed_by,omitempty" db:"completed_by"`
+}
+
+// ManualReviewQuery filters the manual review queue
+type ManualReviewQuery struct {
+	Status        ManualReviewStatus `form:"status"`
+	Priority      string             `form:"priority"`
+	ReviewType    ManualReviewType   `form:"review_type" binding:"omitempty,oneof=primary secondary"`
+	AssignedTo    string             `form:"assigned_to"`
+	ApplicationID string             `form:"application_id"`
+	Limit         int                `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// CompleteManualReviewRequest is an underwriter's decision on a claimed review
+type CompleteManualReviewRequest struct {
+	Decision       ManualReviewDecision `json:"decision" binding:"required,oneof=APPROVE CONDITIONAL DENY"`
+	Notes          string               `json:"notes" binding:"required"`
+	ApprovedAmount *float64             `json:"approved_amount,omitempty" binding:"omitempty,gt=0"`
+	InterestRate   *float64             `json:"interest_rate,omitempty" binding:"omitempty,gt=0"`
+	Conditions     []string             `json:"conditions,omitempty" binding:"max=20,dive,required,max=500"`
+	DenialReasons  []string             `json:"denial_reasons,omitempty"`
+}
+
+// Redecision is the decision engine's decision on an application made again once data a
+// provisional decision was made without arrived. Provisional approvals wait in manual review, so
+// the re-decision is recorded with the application's open review for the underwriter.
+type Redecision struct {
+	DecisionID   int64     `json:"decision_id"`
+	Decision     string    `json:"decision" binding:"required"`
+	RiskCategory string    `json:"risk_category"`
+	Reason       string    `json:"reason"`
+	Provisional  bool      `json:"provisional"` // still waiting for other late data
+	DecidedAt    time.Time `json:"decided_at"`
+}
+
+// SignOffManualReviewRequest is a second underwriter's sign-off of a large approval
+type SignOffManualReviewRequest struct {
+	Decision ManualReviewDecision `json:"decision" binding:"required,oneof=APPROVE DENY"`
+	Notes    string               `json:"notes" binding:"required"`
+}
@@ -1,7 +1,11 @@
 package domain
 
 import (
+	"sort"
 	"time"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/dti"
+	"github.com/huuhoait/los-demo/services/shared/pkg/income"
 )
 
 // Error codes for loan service
@@ -37,6 +41,59 @@ const (
 	LOAN_028 = "LOAN_028" // Manual review required
 	LOAN_029 = "LOAN_029" // Application already exists
 	LOAN_030 = "LOAN_030" // Invalid offer terms
+	LOAN_031 = "LOAN_031" // Identity verification expired
+	LOAN_032 = "LOAN_032" // Pricing rate not found
+	LOAN_033 = "LOAN_033" // Pricing rate conflict
+	LOAN_034 = "LOAN_034" // No pricing rate available
+	LOAN_035 = "LOAN_035" // Offer not open for negotiation
+	LOAN_036 = "LOAN_036" // Application cannot be withdrawn
+	LOAN_037 = "LOAN_037" // Document file too large
+	LOAN_038 = "LOAN_038" // Unsupported document format
+	LOAN_039 = "LOAN_039" // Document not required by the checklist
+	LOAN_040 = "LOAN_040" // Documents cannot be uploaded in the current state
+	LOAN_041 = "LOAN_041" // Agreement cannot be sent for signature
+	LOAN_042 = "LOAN_042" // E-signature unavailable
+	LOAN_043 = "LOAN_043" // Invalid e-signature webhook
+	LOAN_044 = "LOAN_044" // Document cannot be generated in the current state
+	LOAN_045 = "LOAN_045" // Disbursement cannot be scheduled
+	LOAN_046 = "LOAN_046" // Disbursement unavailable
+	LOAN_047 = "LOAN_047" // Invalid disbursement webhook
+	LOAN_048 = "LOAN_048" // Incorrect micro-deposit amounts
+	LOAN_049 = "LOAN_049" // Bank account linking unavailable
+	LOAN_050 = "LOAN_050" // Bank account cannot be verified in its current state
+	LOAN_051 = "LOAN_051" // Repayment schedule cannot be generated
+	LOAN_052 = "LOAN_052" // Pre-qualification expired
+	LOAN_053 = "LOAN_053" // Pre-qualification cannot be converted
+	LOAN_054 = "LOAN_054" // Rate lock expired
+	LOAN_055 = "LOAN_055" // Offer cannot be repriced
+	LOAN_056 = "LOAN_056" // Loan cannot be refinanced
+	LOAN_057 = "LOAN_057" // Collateral cannot be valued
+	LOAN_058 = "LOAN_058" // Credit pull consent required
+	LOAN_059 = "LOAN_059" // Credit pull disclosure version is not current
+	LOAN_060 = "LOAN_060" // Duplicate match already reviewed
+	LOAN_061 = "LOAN_061" // Workflow not found
+	LOAN_062 = "LOAN_062" // Workflow cannot perform the action in its current state
+	LOAN_063 = "LOAN_063" // Application already assigned to another officer
+	LOAN_064 = "LOAN_064" // Loan officer at capacity
+	LOAN_065 = "LOAN_065" // Dead-lettered task not found
+	LOAN_066 = "LOAN_066" // Dead-lettered task already resolved
+	LOAN_067 = "LOAN_067" // Manual review not found
+	LOAN_068 = "LOAN_068" // Manual review not in a claimable or completable state
+	LOAN_069 = "LOAN_069" // Income verification not found
+	LOAN_070 = "LOAN_070" // Payroll income verification unavailable
+	LOAN_071 = "LOAN_071" // Invalid payroll webhook
+	LOAN_072 = "LOAN_072" // Underwriting condition not found
+	LOAN_073 = "LOAN_073" // Underwriting condition already waived or expired
+	LOAN_074 = "LOAN_074" // Demographic information not found
+	LOAN_075 = "LOAN_075" // Application already decided
+	LOAN_076 = "LOAN_076" // Funds already in flight, application cannot be re-underwritten
+	LOAN_077 = "LOAN_077" // Loan officer not licensed in the borrower's state
+	LOAN_078 = "LOAN_078" // Loan officer on time off
+	LOAN_079 = "LOAN_079" // Secondary review needs an underwriter other than the first approver
+	LOAN_080 = "LOAN_080" // Failed workflow to compensate is unknown
+	LOAN_081 = "LOAN_081" // Underwriting decision unavailable
+	LOAN_082 = "LOAN_082" // Underwriting decision is provisional
+	LOAN_083 = "LOAN_083" // Identity verification status unavailable
 )
 
 // ApplicationState represents the state of a loan application
@@ -55,8 +112,23 @@ const (
 	StateFunded             ApplicationState = "funded"
 	StateActive             ApplicationState = "active"
 	StateClosed             ApplicationState = "closed"
+	StateOfferExpired       ApplicationState = "offer_expired"  // approved, but every offer lapsed unselected
+	StateOfferDeclined      ApplicationState = "offer_declined" // approved, but the borrower declined every offer
+	StateWithdrawn          ApplicationState = "withdrawn"      // cancelled by the borrower before funding
 )
 
+// IsValid checks if the state is one of the known application states
+func (s ApplicationState) IsValid() bool {
+	switch s {
+	case StateInitiated, StatePreQualified, StateDocumentsSubmitted, StateIdentityVerified, StateUnderwriting,
+		StateManualReview, StateApproved, StateDenied, StateDocumentsSigned, StateFunded, StateActive, StateClosed,
+		StateOfferExpired, StateOfferDeclined, StateWithdrawn:
+		return true
+	default:
+		return false
+	}
+}
+
 // ApplicationStatus represents the status of a loan application
 type ApplicationStatus string
 
@@ -69,8 +141,20 @@ const (
 	StatusFunded      ApplicationStatus = "funded"
 	StatusActive      ApplicationStatus = "active"
 	StatusClosed      ApplicationStatus = "closed"
+	StatusWithdrawn   ApplicationStatus = "withdrawn"
 )
 
+// IsValid checks if the status is one of the known application statuses
+func (s ApplicationStatus) IsValid() bool {
+	switch s {
+	case StatusDraft, StatusSubmitted, StatusUnderReview, StatusApproved, StatusDenied, StatusFunded, StatusActive, StatusClosed,
+		StatusWithdrawn:
+		return true
+	default:
+		return false
+	}
+}
+
 // LoanPurpose represents the purpose of the loan
 type LoanPurpose string
 
@@ -96,6 +180,11 @@ const (
 	EmploymentStudent      EmploymentStatus = "student"
 )
 
+// IsEmployed reports whether the status is paid employment that can be verified
+func (s EmploymentStatus) IsEmployed() bool {
+	return s == EmploymentFullTime || s == EmploymentPartTime || s == EmploymentSelfEmployed
+}
+
 // ResidenceType represents the type of residence
 type ResidenceType string
 
@@ -116,22 +205,37 @@ const (
 
 // LoanApplication represents a loan application
 type LoanApplication struct {
-	ID                string            `json:"id" db:"id"`
-	UserID            string            `json:"user_id" db:"user_id"`
-	ApplicationNumber string            `json:"application_number" db:"application_number"`
-	LoanAmount        float64           `json:"loan_amount" db:"loan_amount"`
-	LoanPurpose       LoanPurpose       `json:"loan_purpose" db:"loan_purpose"`
-	RequestedTerm     int               `json:"requested_term_months" db:"requested_term_months"`
-	AnnualIncome      float64           `json:"annual_income" db:"annual_income"`
-	MonthlyIncome     float64           `json:"monthly_income" db:"monthly_income"`
-	EmploymentStatus  EmploymentStatus  `json:"employment_status" db:"employment_status"`
-	MonthlyDebt       float64           `json:"monthly_debt_payments" db:"monthly_debt_payments"`
-	CurrentState      ApplicationState  `json:"current_state" db:"current_state"`
-	Status            ApplicationStatus `json:"status" db:"status"`
-	RiskScore         *int              `json:"risk_score" db:"risk_score"`
-	WorkflowID        *string           `json:"workflow_id" db:"workflow_id"`
-	CreatedAt         time.Time         `json:"created_at" db:"created_at"`
-	UpdatedAt         time.Time         `json:"updated_at" db:"updated_at"`
+	ID                      string            `json:"id" db:"id"`
+	UserID                  string            `json:"user_id" db:"user_id"`
+	ApplicationNumber       string            `json:"application_number" db:"application_number"`
+	LoanAmount              float64           `json:"loan_amount" db:"loan_amount"`
+	LoanPurpose             LoanPurpose       `json:"loan_purpose" db:"loan_purpose"`
+	RequestedTerm           int               `json:"requested_term_months" db:"requested_term_months"`
+	AnnualIncome            float64           `json:"annual_income" db:"annual_income"`
+	MonthlyIncome           float64           `json:"monthly_income" db:"monthly_income"`
+	EmploymentStatus        EmploymentStatus  `json:"employment_status" db:"employment_status"`
+	MonthlyDebt             float64           `json:"monthly_debt_payments" db:"monthly_debt_payments"`
+	CurrentState            ApplicationState  `json:"current_state" db:"current_state"`
+	Status                  ApplicationStatus `json:"status" db:"status"`
+	RiskScore               *int              `json:"risk_score" db:"risk_score"`
+	WorkflowID              *string           `json:"workflow_id" db:"workflow_id"`
+	CoBorrower              *CoBorrower       `json:"co_borrower,omitempty" db:"co_borrower"`
+	ApplicationType         ApplicationType   `json:"application_type" db:"application_type"`
+	RefinancedApplicationID *string           `json:"refinanced_application_id,omitempty" db:"refinanced_application_id"` // the funded loan a refinance pays off
+	IncomeSources           *income.Sources   `json:"income_sources,omitempty" db:"income_sources"`                       // documented income by source; its qualifying income replaces MonthlyIncome in DTI
+	CreatedAt               time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt               time.Time         `json:"updated_at" db:"updated_at"`
+
+	// AddressVerification is returned on creation so callers can act on suggestions; not persisted
+	AddressVerification *AddressVerification `json:"address_verification,omitempty" db:"-"`
+
+	// RequiredDocuments is the rules-driven checklist once it has been evaluated; it is persisted
+	// as document requirements rather than on the application
+	RequiredDocuments *DocumentChecklist `json:"required_documents,omitempty" db:"-"`
+
+	// Collateral secures the application when it is for a secured product; it is persisted
+	// separately and returned on creation
+	Collateral *Collateral `json:"collateral,omitempty" db:"-"`
 }
 
 // LoanOffer represents a loan offer
@@ -146,7 +250,114 @@ type LoanOffer struct {
 	APR            float64   `json:"apr" db:"apr"`
 	ExpiresAt      time.Time `json:"expires_at" db:"expires_at"`
 	Status         string    `json:"status" db:"status"`
+	GroupID        *string   `json:"group_id,omitempty" db:"group_id"`
+	ParentOfferID  *string   `json:"parent_offer_id,omitempty" db:"parent_offer_id"` // set on counter offers
 	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+
+	PricingAudit *PricingAudit `json:"pricing_audit,omitempty" db:"pricing_audit"`
+
+	// RefinanceComparison is set on offers of refinance applications
+	RefinanceComparison *RefinanceComparison `json:"refinance_comparison,omitempty" db:"refinance_comparison"`
+
+	RateLock          *RateLock `json:"rate_lock,omitempty" db:"-"`          // the offer's latest rate lock
+	RepricingRequired bool      `json:"repricing_required,omitempty" db:"-"` // its rate is no longer locked
+}
+
+// Offer statuses
+const (
+	OfferStatusPending  = "pending"
+	OfferStatusSelected = "selected"
+	OfferStatusExpired  = "expired"
+	OfferStatusVoided   = "voided" // made by a workflow that failed and was compensated
+)
+
+// OfferTerms are the terms, in months, each offer group is generated across
+var OfferTerms = []int{36, 48, 60}
+
+// OfferAmountRatios are the shares of the requested amount offered at each term
+var OfferAmountRatios = []float64{1, 0.75}
+
+// OfferGroupStatus represents the status of a set of alternative offers
+type OfferGroupStatus string
+
+const (
+	OfferGroupOpen     OfferGroupStatus = "open"
+	OfferGroupSelected OfferGroupStatus = "selected"
+	OfferGroupExpired  OfferGroupStatus = "expired"
+	OfferGroupVoided   OfferGroupStatus = "voided"
+)
+
+// OfferGroup is a set of alternative offers generated together for an application, of which the
+// borrower selects one
+type OfferGroup struct {
+	ID              string           `json:"id" db:"id"`
+	ApplicationID   string           `json:"application_id" db:"application_id"`
+	Status          OfferGroupStatus `json:"status" db:"status"`
+	SelectedOfferID *string          `json:"selected_offer_id,omitempty" db:"selected_offer_id"`
+	ExpiresAt       time.Time        `json:"expires_at" db:"expires_at"`
+	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at" db:"updated_at"`
+	Offers          []*LoanOffer     `json:"offers" db:"-"`
+}
+
+// FindOffer returns the group's offer with the given ID, or nil
+func (g *OfferGroup) FindOffer(offerID string) *LoanOffer {
+	for _, offer := range g.Offers {
+		if offer.ID == offerID {
+			return offer
+		}
+	}
+	return nil
+}
+
+// OfferComparison lays a group's offers side by side and picks out the best on each measure
+type OfferComparison struct {
+	GroupID                     string       `json:"group_id"`
+	Status                      string       `json:"status"`
+	ExpiresAt                   time.Time    `json:"expires_at"`
+	Offers                      []*LoanOffer `json:"offers"` // ordered by monthly payment
+	LowestMonthlyPaymentOfferID string       `json:"lowest_monthly_payment_offer_id,omitempty"`
+	LowestTotalInterestOfferID  string       `json:"lowest_total_interest_offer_id,omitempty"`
+	LowestAPROfferID            string       `json:"lowest_apr_offer_id,omitempty"`
+	HighestAmountOfferID        string       `json:"highest_amount_offer_id,omitempty"`
+}
+
+// Compare builds the side-by-side comparison of the group's offers
+func (g *OfferGroup) Compare() *OfferComparison {
+	comparison := &OfferComparison{
+		GroupID:   g.ID,
+		Status:    string(g.Status),
+		ExpiresAt: g.ExpiresAt,
+		Offers:    make([]*LoanOffer, len(g.Offers)),
+	}
+	copy(comparison.Offers, g.Offers)
+	sort.SliceStable(comparison.Offers, func(i, j int) bool {
+		return comparison.Offers[i].MonthlyPayment < comparison.Offers[j].MonthlyPayment
+	})
+
+	var lowestPayment, lowestInterest, lowestAPR, highestAmount *LoanOffer
+	for _, offer := range comparison.Offers {
+		if lowestPayment == nil || offer.MonthlyPayment < lowestPayment.MonthlyPayment {
+			lowestPayment = offer
+		}
+		if lowestInterest == nil || offer.TotalInterest < lowestInterest.TotalInterest {
+			lowestInterest = offer
+		}
+		if lowestAPR == nil || offer.APR < lowestAPR.APR {
+			lowestAPR = offer
+		}
+		if highestAmount == nil || offer.OfferAmount > highestAmount.OfferAmount {
+			highestAmount = offer
+		}
+	}
+	if lowestPayment != nil {
+		comparison.LowestMonthlyPaymentOfferID = lowestPayment.ID
+		comparison.LowestTotalInterestOfferID = lowestInterest.ID
+		comparison.LowestAPROfferID = lowestAPR.ID
+		comparison.HighestAmountOfferID = highestAmount.ID
+	}
+
+	return comparison
 }
 
 // StateTransition represents a state transition in the application workflow
@@ -162,6 +373,15 @@ type StateTransition struct {
 	CreatedAt        time.Time              `json:"created_at" db:"created_at"`
 }
 
+// StateUpdateRequest moves one application to another state on behalf of an internal service
+type StateUpdateRequest struct {
+	ApplicationID string
+	ToState       ApplicationState
+	Reason        string
+	Automated     bool
+	Metadata      map[string]interface{}
+}
+
 // WorkflowExecution represents a workflow execution
 type WorkflowExecution struct {
 	ID            string                 `json:"id"`
@@ -183,7 +403,9 @@ type User struct {
 	Email          string         `json:"email" binding:"required,email" example:"john.doe@example.com"`
 	PhoneNumber    string         `json:"phone_number" binding:"required" example:"+1234567890"`
 	DateOfBirth    time.Time      `json:"date_of_birth" binding:"required" example:"1990-01-01"`
-	SSN            string         `json:"ssn" binding:"required,len=9" example:"123456789"`
+	SSN            string         `json:"ssn,omitempty" binding:"required,len=9" example:"123456789"` // accepted on input only; replaced by SSNToken before persisting
+	SSNToken       string         `json:"ssn_token,omitempty" db:"ssn_token"`
+	SSNLast4       string         `json:"ssn_last4,omitempty" db:"ssn_last4"`
 	Address        Address        `json:"address" binding:"required"`
 	EmploymentInfo EmploymentInfo `json:"employment_info" binding:"required"`
 	BankingInfo    BankingInfo    `json:"banking_info" binding:"required"`
@@ -200,6 +422,19 @@ type Address struct {
 	Country       string        `json:"country" binding:"required" example:"USA"`
 	ResidenceType ResidenceType `json:"residence_type" binding:"required" example:"own"`
 	TimeAtAddress int           `json:"time_at_address_months" binding:"required,min=0" example:"24"` // months
+
+	// Populated by address verification
+	ZipPlus4             string `json:"zip_plus4,omitempty"`
+	DeliveryPointBarcode string `json:"delivery_point_barcode,omitempty"`
+	Undeliverable        bool   `json:"undeliverable,omitempty"`
+}
+
+// AddressVerification reports the outcome of verifying an applicant address
+type AddressVerification struct {
+	Provider     string    `json:"provider"`
+	Deliverable  bool      `json:"deliverable"`
+	DPVMatchCode string    `json:"dpv_match_code,omitempty"`
+	Suggestions  []Address `json:"suggestions,omitempty"`
 }
 
 // EmploymentInfo represents user's employment information
@@ -219,6 +454,35 @@ type BankingInfo struct {
 	RoutingNumber string      `json:"routing_number" binding:"required" example:"021000021"`
 }
 
+// CoBorrower represents a second borrower whose income and debts are underwritten together with
+// the primary applicant's
+type CoBorrower struct {
+	FirstName        string           `json:"first_name" binding:"required" example:"Jane"`
+	LastName         string           `json:"last_name" binding:"required" example:"Doe"`
+	Email            string           `json:"email" binding:"required,email" example:"jane.doe@example.com"`
+	PhoneNumber      string           `json:"phone_number" binding:"required" example:"+1234567891"`
+	DateOfBirth      time.Time        `json:"date_of_birth" binding:"required" example:"1991-02-01"`
+	AnnualIncome     float64          `json:"annual_income" binding:"min=0" example:"45000" minimum:"0"`
+	MonthlyIncome    float64          `json:"monthly_income" binding:"min=0" example:"3750" minimum:"0"`
+	EmploymentStatus EmploymentStatus `json:"employment_status" binding:"required" example:"full_time"`
+	EmploymentInfo   *EmploymentInfo  `json:"employment_info,omitempty" binding:"omitempty"`
+	MonthlyDebt      float64          `json:"monthly_debt_payments" binding:"min=0" example:"400" minimum:"0"`
+}
+
+// Document types collected from each borrower
+const (
+	DocumentIncomeVerification     = "income_verification"
+	DocumentEmploymentVerification = "employment_verification"
+	DocumentBankStatements         = "bank_statements"
+	DocumentIdentification         = "identification"
+)
+
+// DocumentChecklist lists the documents required from each borrower on an application
+type DocumentChecklist struct {
+	Borrower   []string `json:"borrower"`
+	CoBorrower []string `json:"co_borrower,omitempty"`
+}
+
 // CreateApplicationRequest represents a request to create a loan application
 // @Description Request to create a new loan application with user information
 type CreateApplicationRequest struct {
@@ -233,6 +497,23 @@ type CreateApplicationRequest struct {
 	MonthlyIncome    float64          `json:"monthly_income" binding:"required,min=0" example:"6250" minimum:"0"`
 	EmploymentStatus EmploymentStatus `json:"employment_status" binding:"required" example:"full_time"`
 	MonthlyDebt      float64          `json:"monthly_debt_payments" binding:"min=0" example:"1500" minimum:"0"`
+
+	// Optional co-borrower whose income and debts count towards the application
+	CoBorrower *CoBorrower `json:"co_borrower,omitempty" binding:"omitempty"`
+
+	// Optional documented income by source: wages, bonus, overtime and commission, self-employment
+	// and rental. Underwriting qualifies it under the lender's income rules in place of monthly_income.
+	IncomeSources *income.Sources `json:"income_sources,omitempty"`
+
+	// Optional funded loan of the same borrower to refinance; the loan amount must cover its payoff
+	RefinanceApplicationID *string `json:"refinance_application_id,omitempty" binding:"omitempty,uuid"`
+
+	// Optional asset securing the loan; it is valued and its loan-to-value ratio used in underwriting
+	Collateral *CollateralRequest `json:"collateral,omitempty" binding:"omitempty"`
+
+	// Optional fingerprint of the applicant's device, compared against other borrowers' applications
+	// to detect duplicates; the X-Device-ID header is used when it is absent
+	DeviceID string `json:"device_id,omitempty" binding:"omitempty,max=255"`
 }
 
 // UpdateApplicationRequest represents a request to update a loan application
@@ -246,6 +527,150 @@ type UpdateApplicationRequest struct {
 	MonthlyDebt      *float64          `json:"monthly_debt_payments,omitempty" binding:"omitempty,min=0"`
 }
 
+// ReassignApplicationsRequest moves applications from a merged duplicate user to the surviving user
+type ReassignApplicationsRequest struct {
+	FromUserID string `json:"from_user_id" binding:"required"`
+	ToUserID   string `json:"to_user_id" binding:"required"`
+}
+
+// UserLoanActivity summarizes a user's applications for services that key retention off loan closure
+type UserLoanActivity struct {
+	UserID           string     `json:"user_id"`
+	OpenApplications int        `json:"open_applications"`
+	LastClosedAt     *time.Time `json:"last_closed_at,omitempty"`
+}
+
+// ApplicationSortField identifies a column applications can be ordered by when listing
+type ApplicationSortField string
+
+const (
+	ApplicationSortCreatedAt  ApplicationSortField = "created_at"
+	ApplicationSortUpdatedAt  ApplicationSortField = "updated_at"
+	ApplicationSortLoanAmount ApplicationSortField = "loan_amount"
+)
+
+// Page size bounds for application listings
+const (
+	DefaultApplicationPageSize = 20
+	MaxApplicationPageSize     = 100
+)
+
+// ApplicationListQuery describes the filters, ordering and page window for listing applications.
+// Dates are inclusive calendar days in YYYY-MM-DD format.
+type ApplicationListQuery struct {
+	UserID      string               `json:"user_id,omitempty" form:"user_id"`
+	Status      ApplicationStatus    `json:"status,omitempty" form:"status"`
+	State       ApplicationState     `json:"state,omitempty" form:"state"`
+	CreatedFrom time.Time            `json:"created_from,omitempty" form:"created_from" time_format:"2006-01-02"`
+	CreatedTo   time.Time            `json:"created_to,omitempty" form:"created_to" time_format:"2006-01-02"`
+	SortBy      ApplicationSortField `json:"sort_by,omitempty" form:"sort_by"`
+	SortOrder   string               `json:"sort_order,omitempty" form:"sort_order"`
+	Page        int                  `json:"page,omitempty" form:"page"`
+	PageSize    int                  `json:"page_size,omitempty" form:"page_size"`
+}
+
+// ApplyDefaults fills in the default ordering and page window
+func (q *ApplicationListQuery) ApplyDefaults() {
+	if q.SortBy == "" {
+		q.SortBy = ApplicationSortCreatedAt
+	}
+	if q.SortOrder == "" {
+		q.SortOrder = "desc"
+	}
+	if q.Page <= 0 {
+		q.Page = 1
+	}
+	if q.PageSize <= 0 {
+		q.PageSize = DefaultApplicationPageSize
+	}
+	if q.PageSize > MaxApplicationPageSize {
+		q.PageSize = MaxApplicationPageSize
+	}
+}
+
+// Validate checks the filter values and ordering against the known statuses, states and columns
+func (q *ApplicationListQuery) Validate() *ValidationResult {
+	result := &ValidationResult{Valid: true, Errors: make(map[string]string)}
+
+	if q.Status != "" && !q.Status.IsValid() {
+		result.Errors["status"] = "Unknown application status"
+	}
+	if q.State != "" && !q.State.IsValid() {
+		result.Errors["state"] = "Unknown application state"
+	}
+	switch q.SortBy {
+	case ApplicationSortCreatedAt, ApplicationSortUpdatedAt, ApplicationSortLoanAmount:
+	default:
+		result.Errors["sort_by"] = "Sort field must be created_at, updated_at or loan_amount"
+	}
+	if q.SortOrder != "asc" && q.SortOrder != "desc" {
+		result.Errors["sort_order"] = "Sort order must be asc or desc"
+	}
+	if !q.CreatedFrom.IsZero() && !q.CreatedTo.IsZero() && q.CreatedTo.Before(q.CreatedFrom) {
+		result.Errors["created_to"] = "End date must not be before start date"
+	}
+
+	result.Valid = len(result.Errors) == 0
+	return result
+}
+
+// Offset returns the number of rows skipped before the requested page
+func (q *ApplicationListQuery) Offset() int {
+	return (q.Page - 1) * q.PageSize
+}
+
+// ApplicationPage is one page of an application listing along with pagination metadata
+type ApplicationPage struct {
+	Applications []*LoanApplication `json:"applications"`
+	Page         int                `json:"page"`
+	PageSize     int                `json:"page_size"`
+	Total        int                `json:"total"`
+	TotalPages   int                `json:"total_pages"`
+}
+
+// FunnelStates are the happy-path states, in order, that funnel conversion is measured across
+var FunnelStates = []ApplicationState{
+	StateInitiated,
+	StatePreQualified,
+	StateDocumentsSubmitted,
+	StateIdentityVerified,
+	StateUnderwriting,
+	StateApproved,
+	StateDocumentsSigned,
+	StateFunded,
+}
+
+// ApprovedStates are the states of applications that were approved, including those that moved on since
+var ApprovedStates = []ApplicationState{StateApproved, StateDocumentsSigned, StateFunded, StateActive, StateClosed}
+
+// Bounds for the statistics reporting window
+const (
+	DefaultStatsDays = 30
+	MaxStatsDays     = 365
+)
+
+// ApplicationStats summarizes applications created within the reporting window
+type ApplicationStats struct {
+	Days              int                       `json:"days"`
+	Since             time.Time                 `json:"since"`
+	TotalApplications int                       `json:"total_applications"`
+	ByStatus          map[ApplicationStatus]int `json:"by_status"`
+	ByState           map[ApplicationState]int  `json:"by_state"`
+	ApprovedCount     int                       `json:"approved_count"`
+	DeniedCount       int                       `json:"denied_count"`
+	ApprovalRate      float64                   `json:"approval_rate"` // approved share of decided applications
+	AverageLoanAmount float64                   `json:"average_loan_amount"`
+	Funnel            []FunnelStage             `json:"funnel"`
+	GeneratedAt       time.Time                 `json:"generated_at"`
+}
+
+// FunnelStage counts the applications that reached a state, directly or by moving past it
+type FunnelStage struct {
+	State          ApplicationState `json:"state"`
+	Count          int              `json:"count"`
+	ConversionRate float64          `json:"conversion_rate"` // share of the previous stage that reached this one
+}
+
 // PreQualifyRequest represents a pre-qualification request
 // @Description Request to perform loan pre-qualification
 type PreQualifyRequest struct {
@@ -253,18 +678,89 @@ type PreQualifyRequest struct {
 	AnnualIncome     float64          `json:"annual_income" binding:"required,min=0" example:"75000" minimum:"0"`
 	MonthlyDebt      float64          `json:"monthly_debt_payments" binding:"min=0" example:"1500" minimum:"0"`
 	EmploymentStatus EmploymentStatus `json:"employment_status" binding:"required" example:"full_time"`
+
+	// Co-borrower figures are optional; when set they are combined with the applicant's for DTI
+	CoBorrowerAnnualIncome     float64          `json:"co_borrower_annual_income,omitempty" binding:"min=0" example:"45000" minimum:"0"`
+	CoBorrowerMonthlyDebt      float64          `json:"co_borrower_monthly_debt_payments,omitempty" binding:"min=0" example:"400" minimum:"0"`
+	CoBorrowerEmploymentStatus EmploymentStatus `json:"co_borrower_employment_status,omitempty" example:"full_time"`
+
+	// Obligations qualified under the lender's debt treatments rather than taken as stated; they
+	// are not part of the monthly debt payments
+	Obligations           *dti.Obligations `json:"obligations,omitempty"`
+	CoBorrowerObligations *dti.Obligations `json:"co_borrower_obligations,omitempty"`
+
+	// Documented income by source, qualified under the lender's income rules in place of the
+	// annual income for DTI
+	IncomeSources           *income.Sources `json:"income_sources,omitempty"`
+	CoBorrowerIncomeSources *income.Sources `json:"co_borrower_income_sources,omitempty"`
+}
+
+// HasCoBorrower reports whether the request includes co-borrower figures
+func (req *PreQualifyRequest) HasCoBorrower() bool {
+	return req.CoBorrowerEmploymentStatus != "" || req.CoBorrowerAnnualIncome > 0 || req.CoBorrowerMonthlyDebt > 0 ||
+		req.CoBorrowerIncomeSources != nil
+}
+
+// CombinedMonthlyIncome returns the monthly income of the applicant and co-borrower together
+func (req *PreQualifyRequest) CombinedMonthlyIncome() float64 {
+	return (req.AnnualIncome + req.CoBorrowerAnnualIncome) / 12
+}
+
+// CombinedMonthlyDebt returns the monthly debt payments of the applicant and co-borrower together
+func (req *PreQualifyRequest) CombinedMonthlyDebt() float64 {
+	return req.MonthlyDebt + req.CoBorrowerMonthlyDebt
+}
+
+// DTIBorrowers returns the applicant's and any co-borrower's figures for the DTI calculator
+func (req *PreQualifyRequest) DTIBorrowers() []dti.Borrower {
+	borrowers := []dti.Borrower{{
+		MonthlyIncome: req.AnnualIncome / 12,
+		MonthlyDebt:   req.MonthlyDebt,
+		Obligations:   req.Obligations,
+		IncomeSources: req.IncomeSources,
+	}}
+	if req.HasCoBorrower() || req.CoBorrowerObligations != nil {
+		borrowers = append(borrowers, dti.Borrower{
+			MonthlyIncome: req.CoBorrowerAnnualIncome / 12,
+			MonthlyDebt:   req.CoBorrowerMonthlyDebt,
+			Obligations:   req.CoBorrowerObligations,
+			IncomeSources: req.CoBorrowerIncomeSources,
+		})
+	}
+	return borrowers
 }
 
-// PreQualifyResult represents a pre-qualification result
+// PreQualifyResult represents a pre-qualification result. Results are kept until they expire so a
+// qualified borrower can convert one into a draft application.
 // @Description Result of loan pre-qualification
 type PreQualifyResult struct {
-	Qualified        bool    `json:"qualified" example:"true"`
-	MaxLoanAmount    float64 `json:"max_loan_amount" example:"50000"`
-	MinInterestRate  float64 `json:"min_interest_rate" example:"8.5"`
-	MaxInterestRate  float64 `json:"max_interest_rate" example:"12.0"`
-	RecommendedTerms []int   `json:"recommended_terms"`
-	DTIRatio         float64 `json:"dti_ratio" example:"0.24"`
-	Message          string  `json:"message" example:"You are pre-qualified for a loan"`
+	ID               string            `json:"id" db:"id" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	UserID           string            `json:"user_id" db:"user_id"`
+	Request          PreQualifyRequest `json:"request" db:"request"` // the figures the result was evaluated from
+	Qualified        bool              `json:"qualified" db:"qualified" example:"true"`
+	MaxLoanAmount    float64           `json:"max_loan_amount" db:"max_loan_amount" example:"50000"`
+	MinInterestRate  float64           `json:"min_interest_rate" db:"min_interest_rate" example:"8.5"`
+	MaxInterestRate  float64           `json:"max_interest_rate" db:"max_interest_rate" example:"12.0"`
+	RecommendedTerms []int             `json:"recommended_terms" db:"recommended_terms"`
+	DTIRatio         float64           `json:"dti_ratio" db:"dti_ratio" example:"0.24"`
+	Message          string            `json:"message" db:"message" example:"You are pre-qualified for a loan"`
+	ApplicationID    *string           `json:"application_id,omitempty" db:"application_id"` // set once converted
+	ExpiresAt        time.Time         `json:"expires_at" db:"expires_at"`
+	CreatedAt        time.Time         `json:"created_at" db:"created_at"`
+}
+
+// IsExpired reports whether the result can no longer be converted into an application
+func (r *PreQualifyResult) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// ConvertPreQualificationRequest represents a request to start an application from a pre-qualification
+// @Description Request to convert a pre-qualification into a draft application
+type ConvertPreQualificationRequest struct {
+	LoanPurpose   LoanPurpose `json:"loan_purpose" binding:"required,oneof=debt_consolidation home_improvement medical vacation wedding major_purchase other" example:"debt_consolidation"`
+	RequestedTerm int         `json:"requested_term_months" binding:"required" example:"60"`
+	// LoanAmount defaults to the pre-qualification's requested amount, up to the maximum it qualified for
+	LoanAmount float64 `json:"loan_amount,omitempty" binding:"omitempty,min=5000,max=50000" example:"25000"`
 }
 
 // AcceptOfferRequest represents a request to accept a loan offer
@@ -409,9 +905,29 @@ func (req *CreateApplicationRequest) Validate() *ValidationResult {
 		result.Errors["monthly_income"] = LOAN_004
 	}
 
-	// Validate DTI ratio (monthly debt should not exceed 40% of monthly income)
-	if req.MonthlyIncome > 0 {
-		dtiRatio := req.MonthlyDebt / req.MonthlyIncome
+	if req.CoBorrower != nil {
+		for field, code := range req.CoBorrower.Validate().Errors {
+			result.Valid = false
+			result.Errors["co_borrower."+field] = code
+		}
+	}
+
+	if req.Collateral != nil {
+		for field, code := range req.Collateral.Validate().Errors {
+			result.Valid = false
+			result.Errors["collateral."+field] = code
+		}
+	}
+
+	// Validate DTI ratio (monthly debt should not exceed 40% of monthly income), combining the
+	// co-borrower's income and debts with the applicant's
+	monthlyIncome, monthlyDebt := req.MonthlyIncome, req.MonthlyDebt
+	if req.CoBorrower != nil {
+		monthlyIncome += req.CoBorrower.MonthlyIncome
+		monthlyDebt += req.CoBorrower.MonthlyDebt
+	}
+	if monthlyIncome > 0 {
+		dtiRatio := monthlyDebt / monthlyIncome
 		if dtiRatio > 0.4 {
 			result.Valid = false
 			result.Errors["monthly_debt_payments"] = LOAN_007
@@ -421,6 +937,69 @@ func (req *CreateApplicationRequest) Validate() *ValidationResult {
 	return result
 }
 
+// Validate validates co-borrower information
+func (cb *CoBorrower) Validate() *ValidationResult {
+	result := &ValidationResult{
+		Valid:  true,
+		Errors: make(map[string]string),
+	}
+
+	if cb.FirstName == "" {
+		result.Valid = false
+		result.Errors["first_name"] = LOAN_020
+	}
+	if cb.LastName == "" {
+		result.Valid = false
+		result.Errors["last_name"] = LOAN_020
+	}
+	if cb.Email == "" {
+		result.Valid = false
+		result.Errors["email"] = LOAN_020
+	}
+	if cb.PhoneNumber == "" {
+		result.Valid = false
+		result.Errors["phone_number"] = LOAN_020
+	}
+
+	eighteenYearsAgo := time.Now().AddDate(-18, 0, 0)
+	if cb.DateOfBirth.After(eighteenYearsAgo) {
+		result.Valid = false
+		result.Errors["date_of_birth"] = LOAN_020
+	}
+
+	if cb.AnnualIncome < 0 {
+		result.Valid = false
+		result.Errors["annual_income"] = LOAN_004
+	}
+	if cb.MonthlyIncome < 0 {
+		result.Valid = false
+		result.Errors["monthly_income"] = LOAN_004
+	}
+	if cb.MonthlyDebt < 0 {
+		result.Valid = false
+		result.Errors["monthly_debt_payments"] = LOAN_020
+	}
+	if cb.EmploymentStatus == "" {
+		result.Valid = false
+		result.Errors["employment_status"] = LOAN_020
+	}
+
+	// Employer details are needed to verify employment
+	if cb.EmploymentStatus.IsEmployed() {
+		if cb.EmploymentInfo == nil {
+			result.Valid = false
+			result.Errors["employment_info"] = LOAN_020
+		} else {
+			for field, code := range cb.EmploymentInfo.ValidateEmploymentInfo().Errors {
+				result.Valid = false
+				result.Errors["employment_info."+field] = code
+			}
+		}
+	}
+
+	return result
+}
+
 // ValidateUser validates user information
 func (u *User) ValidateUser() *ValidationResult {
 	result := &ValidationResult{
@@ -445,7 +1024,7 @@ func (u *User) ValidateUser() *ValidationResult {
 		result.Valid = false
 		result.Errors["phone_number"] = LOAN_020
 	}
-	if u.SSN == "" || len(u.SSN) != 9 {
+	if u.SSNToken == "" && len(u.SSN) != 9 {
 		result.Valid = false
 		result.Errors["ssn"] = LOAN_020
 	}
@@ -567,10 +1146,10 @@ func (u *User) IsAdult() bool {
 
 // MaskSSN returns a masked version of the SSN for display
 func (u *User) MaskSSN() string {
-	if len(u.SSN) != 9 {
+	if len(u.SSNLast4) != 4 {
 		return ""
 	}
-	return "***-**-" + u.SSN[5:]
+	return "***-**-" + u.SSNLast4
 }
 
 // MaskAccountNumber returns a masked version of the account number for display
@@ -605,12 +1184,66 @@ func (req *CreateApplicationRequest) GetUserInfo() map[string]interface{} {
 	}
 }
 
-// CalculateDTI calculates debt-to-income ratio
+// CombinedMonthlyIncome returns the monthly income of the applicant and any co-borrower together
+func (app *LoanApplication) CombinedMonthlyIncome() float64 {
+	income := app.MonthlyIncome
+	if app.CoBorrower != nil {
+		income += app.CoBorrower.MonthlyIncome
+	}
+	return income
+}
+
+// CombinedAnnualIncome returns the annual income of the applicant and any co-borrower together
+func (app *LoanApplication) CombinedAnnualIncome() float64 {
+	income := app.AnnualIncome
+	if app.CoBorrower != nil {
+		income += app.CoBorrower.AnnualIncome
+	}
+	return income
+}
+
+// CombinedMonthlyDebt returns the monthly debt payments of the applicant and any co-borrower together
+func (app *LoanApplication) CombinedMonthlyDebt() float64 {
+	debt := app.MonthlyDebt
+	if app.CoBorrower != nil {
+		debt += app.CoBorrower.MonthlyDebt
+	}
+	return debt
+}
+
+// CalculateDTI calculates the debt-to-income ratio, combining the co-borrower's income and debts
+// with the applicant's when there is one
 func (app *LoanApplication) CalculateDTI() float64 {
-	if app.MonthlyIncome <= 0 {
+	monthlyIncome := app.CombinedMonthlyIncome()
+	if monthlyIncome <= 0 {
 		return 0
 	}
-	return app.MonthlyDebt / app.MonthlyIncome
+	return app.CombinedMonthlyDebt() / monthlyIncome
+}
+
+// DocumentChecklist returns the documents required from the applicant and any co-borrower. Until
+// the rules-driven checklist has been evaluated the default documents are required.
+func (app *LoanApplication) DocumentChecklist() *DocumentChecklist {
+	if app.RequiredDocuments != nil {
+		return app.RequiredDocuments
+	}
+	checklist := &DocumentChecklist{
+		Borrower: RequiredDocuments(app.EmploymentStatus),
+	}
+	if app.CoBorrower != nil {
+		checklist.CoBorrower = RequiredDocuments(app.CoBorrower.EmploymentStatus)
+	}
+	return checklist
+}
+
+// RequiredDocuments returns the documents a borrower with the given employment status must provide.
+// Employment is only verified for borrowers who have an employer.
+func RequiredDocuments(status EmploymentStatus) []string {
+	documents := []string{DocumentIncomeVerification}
+	if status.IsEmployed() {
+		documents = append(documents, DocumentEmploymentVerification)
+	}
+	return append(documents, DocumentBankStatements, DocumentIdentification)
 }
 
 // IsExpired checks if a loan offer has expired
@@ -621,15 +1254,17 @@ func (offer *LoanOffer) IsExpired() bool {
 // CanTransitionTo checks if the application can transition to the given state
 func (app *LoanApplication) CanTransitionTo(newState ApplicationState) bool {
 	validTransitions := map[ApplicationState][]ApplicationState{
-		StateInitiated:          {StatePreQualified},
-		StatePreQualified:       {StateDocumentsSubmitted},
-		StateDocumentsSubmitted: {StateIdentityVerified},
-		StateIdentityVerified:   {StateUnderwriting},
-		StateUnderwriting:       {StateApproved, StateDenied, StateManualReview},
-		StateManualReview:       {StateApproved, StateDenied},
-		StateApproved:           {StateDocumentsSigned},
-		StateDocumentsSigned:    {StateFunded},
-		StateFunded:             {StateActive},
+		StateInitiated:          {StatePreQualified, StateWithdrawn},
+		StatePreQualified:       {StateDocumentsSubmitted, StateWithdrawn},
+		StateDocumentsSubmitted: {StateIdentityVerified, StateWithdrawn},
+		StateIdentityVerified:   {StateUnderwriting, StateWithdrawn},
+		StateUnderwriting:       {StateApproved, StateDenied, StateManualReview, StateWithdrawn},
+		StateManualReview:       {StateApproved, StateDenied, StateWithdrawn},
+		StateApproved:           {StateDocumentsSigned, StateOfferExpired, StateOfferDeclined, StateUnderwriting, StateWithdrawn}, // re-underwritten on a material change
+		StateOfferExpired:       {StateClosed, StateWithdrawn},
+		StateOfferDeclined:      {StateClosed, StateWithdrawn},
+		StateDocumentsSigned:    {StateFunded, StateUnderwriting, StateWithdrawn},
+		StateFunded:             {StateActive, StateClosed}, // closed when paid off by a refinance
 		StateActive:             {StateClosed},
 	}
 
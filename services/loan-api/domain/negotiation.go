@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Offer statuses reached through negotiation
+const (
+	OfferStatusDeclined  = "declined"
+	OfferStatusCountered = "countered" // the borrower asked for different terms and a counter offer is pending
+)
+
+// LapsedOffers is an approved application left without an open or selected offer
+type LapsedOffers struct {
+	ApplicationID string
+	// AllDeclined is set when the borrower declined every offer rather than letting any expire
+	AllDeclined bool
+}
+
+// NegotiationAction is a step in the negotiation of an offer
+type NegotiationAction string
+
+const (
+	NegotiationDeclined         NegotiationAction = "declined"
+	NegotiationCounterRequested NegotiationAction = "counter_requested"
+	NegotiationCounterOffered   NegotiationAction = "counter_offered"
+	NegotiationCounterAccepted  NegotiationAction = "counter_accepted"
+)
+
+// Negotiation actors
+const (
+	NegotiationActorBorrower = "borrower"
+	NegotiationActorLender   = "lender"
+)
+
+// OfferNegotiation is one entry in an application's offer negotiation thread
+type OfferNegotiation struct {
+	ID                  string            `json:"id" db:"id"`
+	ApplicationID       string            `json:"application_id" db:"application_id"`
+	OfferID             string            `json:"offer_id" db:"offer_id"`
+	Action              NegotiationAction `json:"action" db:"action"`
+	Actor               string            `json:"actor" db:"actor"`
+	Reason              string            `json:"reason,omitempty" db:"reason"`
+	RequestedAmount     *float64          `json:"requested_amount,omitempty" db:"requested_amount"`
+	RequestedTermMonths *int              `json:"requested_term_months,omitempty" db:"requested_term_months"`
+	CounterOfferID      *string           `json:"counter_offer_id,omitempty" db:"counter_offer_id"`
+	WorkflowID          string            `json:"workflow_id,omitempty" db:"workflow_id"`
+	CreatedAt           time.Time         `json:"created_at" db:"created_at"`
+}
+
+// CounterOfferID is the ID of the counter offer generated for a borrower's request for different
+// terms. It derives from the request's negotiation entry, so a retried recording task finds the
+// offer it already stored and a response to the counter offer finds the workflow that made it.
+func CounterOfferID(negotiationID string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(negotiationID)).String()
+}
+
+// Counter offer responses a borrower can give
+const (
+	CounterOfferAccept  = "ACCEPT"
+	CounterOfferDecline = "DECLINE"
+)
+
+// Outcomes the counter offer workflow's wait for the borrower's response completes with
+const (
+	CounterOfferAccepted = "ACCEPTED"
+	CounterOfferDeclined = "DECLINED"
+)
+
+// CounterOfferResponseRequest is a borrower's acceptance or decline of a counter offer
+// @Description Request to accept or decline a counter offer
+type CounterOfferResponseRequest struct {
+	Response string `json:"response" binding:"required,oneof=ACCEPT DECLINE" example:"ACCEPT"`
+	Reason   string `json:"reason,omitempty" binding:"max=500" example:"The payment is still too high"` // required to decline
+}
+
+// CounterOfferResponseResult is the outcome of a response to a counter offer: the offer group
+// closed with the counter offer when accepted, or the decline entry of the negotiation thread
+type CounterOfferResponseResult struct {
+	Response    string            `json:"response"`
+	OfferGroup  *OfferGroup       `json:"offer_group,omitempty"`
+	Negotiation *OfferNegotiation `json:"negotiation,omitempty"`
+}
+
+// NegotiationThread is an application's negotiation history together with the counter offers it produced
+type NegotiationThread struct {
+	ApplicationID string              `json:"application_id"`
+	Entries       []*OfferNegotiation `json:"entries"`
+	CounterOffers []*LoanOffer        `json:"counter_offers"`
+}
+
+// DeclineOfferRequest represents a request to decline an offer
+// @Description Request to decline a loan offer
+type DeclineOfferRequest struct {
+	Reason string `json:"reason" binding:"required,max=500" example:"The monthly payment is too high"`
+}
+
+// CounterOfferRequest represents a borrower's request for different terms than an offer's
+// @Description Request for different offer terms
+type CounterOfferRequest struct {
+	RequestedAmount     *float64 `json:"requested_amount,omitempty" binding:"omitempty,min=5000,max=50000" example:"20000"`
+	RequestedTermMonths *int     `json:"requested_term_months,omitempty" binding:"omitempty,min=12,max=84" example:"72"`
+	Reason              string   `json:"reason,omitempty" binding:"max=500" example:"A longer term would lower my payment"`
+}
+
+// Validate checks the request asks for at least one different term
+func (req *CounterOfferRequest) Validate() *ValidationResult {
+	result := &ValidationResult{
+		Valid:  true,
+		Errors: make(map[string]string),
+	}
+
+	if req.RequestedAmount == nil && req.RequestedTermMonths == nil {
+		result.Valid = false
+		result.Errors["requested_terms"] = LOAN_030
+	}
+
+	return result
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"time"
+)
+
+// NoteAuthorRole is the staff role a note was written in
+type NoteAuthorRole string
+
+const (
+	NoteAuthorUnderwriter  NoteAuthorRole = "underwriter"
+	NoteAuthorSupportAgent NoteAuthorRole = "support_agent"
+)
+
+// noteAuthorRoles maps the back-office roles issued by the auth service to the role a note is written in
+var noteAuthorRoles = map[string]NoteAuthorRole{
+	"junior_reviewer": NoteAuthorUnderwriter,
+	"senior_reviewer": NoteAuthorUnderwriter,
+	"manager":         NoteAuthorUnderwriter,
+	"admin":           NoteAuthorSupportAgent,
+	"super_admin":     NoteAuthorSupportAgent,
+}
+
+// NoteAuthorRoleFor returns the note author role for a caller's staff role
+func NoteAuthorRoleFor(staffRole string) (NoteAuthorRole, bool) {
+	role, ok := noteAuthorRoles[staffRole]
+	return role, ok
+}
+
+// NoteVisibility controls who can read a note
+type NoteVisibility string
+
+const (
+	NoteVisibilityInternal NoteVisibility = "internal" // staff only
+	NoteVisibilityBorrower NoteVisibility = "borrower" // also shown to the borrower
+)
+
+// NoteAttachment is a file referenced from a note
+type NoteAttachment struct {
+	FileName    string  `json:"file_name" binding:"required,max=255" example:"paystub-march.pdf"`
+	URL         string  `json:"url" binding:"required,url" example:"https://files.example.com/paystub-march.pdf"`
+	ContentType string  `json:"content_type,omitempty" binding:"max=100" example:"application/pdf"`
+	DocumentID  *string `json:"document_id,omitempty" example:"6f1c2b7e-9a0d-4f4e-8a52-2d4f0c1b3a77"`
+}
+
+// ApplicationNote is a comment posted on an application by an underwriter or support agent
+type ApplicationNote struct {
+	ID            string           `json:"id" db:"id"`
+	ApplicationID string           `json:"application_id" db:"application_id"`
+	AuthorID      string           `json:"author_id,omitempty" db:"author_id"`
+	AuthorRole    NoteAuthorRole   `json:"author_role" db:"author_role"`
+	Visibility    NoteVisibility   `json:"visibility" db:"visibility"`
+	Body          string           `json:"body" db:"body"`
+	Mentions      []string         `json:"mentions,omitempty" db:"mentions"` // user IDs of mentioned staff
+	Attachments   []NoteAttachment `json:"attachments,omitempty" db:"attachments"`
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+}
+
+// BorrowerView returns the note as shown to the borrower, without staff identities
+func (n *ApplicationNote) BorrowerView() *ApplicationNote {
+	view := *n
+	view.AuthorID = ""
+	view.Mentions = nil
+	return &view
+}
+
+// CreateNoteRequest represents a request to post a note on an application
+// @Description Request to post a note on a loan application
+type CreateNoteRequest struct {
+	AuthorID    string           `json:"-"` // set from the authenticated caller
+	AuthorRole  NoteAuthorRole   `json:"-"` // derived from the caller's role claim
+	Visibility  NoteVisibility   `json:"visibility,omitempty" binding:"omitempty,oneof=internal borrower" example:"internal"`
+	Body        string           `json:"body" binding:"required,max=5000" example:"Income verified against the March paystub"`
+	Mentions    []string         `json:"mentions,omitempty" binding:"max=20,dive,required"`
+	Attachments []NoteAttachment `json:"attachments,omitempty" binding:"max=10,dive"`
+}
@@ -0,0 +1,291 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// CreditTier groups credit scores into the bands used by rate matrices
+type CreditTier string
+
+const (
+	CreditTierExcellent CreditTier = "excellent"
+	CreditTierGood      CreditTier = "good"
+	CreditTierFair      CreditTier = "fair"
+	CreditTierPoor      CreditTier = "poor"
+)
+
+// IsValid reports whether the tier is a known credit tier
+func (t CreditTier) IsValid() bool {
+	switch t {
+	case CreditTierExcellent, CreditTierGood, CreditTierFair, CreditTierPoor:
+		return true
+	}
+	return false
+}
+
+// CreditTierForScore returns the credit tier a credit score falls in
+func CreditTierForScore(score int) CreditTier {
+	switch {
+	case score >= 740:
+		return CreditTierExcellent
+	case score >= 670:
+		return CreditTierGood
+	case score >= 580:
+		return CreditTierFair
+	default:
+		return CreditTierPoor
+	}
+}
+
+// RiskLevel is the underwriting risk level an offer is priced for
+type RiskLevel string
+
+const (
+	RiskLevelLow    RiskLevel = "LOW"
+	RiskLevelMedium RiskLevel = "MEDIUM"
+	RiskLevelHigh   RiskLevel = "HIGH"
+)
+
+// IsValid reports whether the level is a known risk level
+func (l RiskLevel) IsValid() bool {
+	switch l {
+	case RiskLevelLow, RiskLevelMedium, RiskLevelHigh:
+		return true
+	}
+	return false
+}
+
+// ProductPersonalLoan is the product offers are priced as when none is given
+const ProductPersonalLoan = "personal_loan"
+
+// PricingRate is one cell of a rate matrix: the rate for a product, credit tier and risk level
+// over a range of terms, valid from EffectiveFrom until EffectiveTo (open-ended when nil)
+type PricingRate struct {
+	ID                 string     `json:"id" db:"id"`
+	Product            string     `json:"product" db:"product"`
+	CreditTier         CreditTier `json:"credit_tier" db:"credit_tier"`
+	RiskLevel          RiskLevel  `json:"risk_level" db:"risk_level"`
+	MinTermMonths      int        `json:"min_term_months" db:"min_term_months"`
+	MaxTermMonths      int        `json:"max_term_months" db:"max_term_months"`
+	InterestRate       float64    `json:"interest_rate" db:"interest_rate"`               // annual, percent
+	OriginationFeeRate float64    `json:"origination_fee_rate" db:"origination_fee_rate"` // percent of the loan amount
+	EffectiveFrom      time.Time  `json:"effective_from" db:"effective_from"`
+	EffectiveTo        *time.Time `json:"effective_to,omitempty" db:"effective_to"`
+	CreatedBy          string     `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsEffective reports whether the rate applies at the given time
+func (r *PricingRate) IsEffective(at time.Time) bool {
+	return !at.Before(r.EffectiveFrom) && (r.EffectiveTo == nil || at.Before(*r.EffectiveTo))
+}
+
+// Overlaps reports whether both rates would match the same pricing request at the same time
+func (r *PricingRate) Overlaps(other *PricingRate) bool {
+	if r.Product != other.Product || r.CreditTier != other.CreditTier || r.RiskLevel != other.RiskLevel {
+		return false
+	}
+	if r.MaxTermMonths < other.MinTermMonths || other.MaxTermMonths < r.MinTermMonths {
+		return false
+	}
+	if r.EffectiveTo != nil && !r.EffectiveTo.After(other.EffectiveFrom) {
+		return false
+	}
+	if other.EffectiveTo != nil && !other.EffectiveTo.After(r.EffectiveFrom) {
+		return false
+	}
+	return true
+}
+
+// Validate checks the rate's fields are consistent
+func (r *PricingRate) Validate() *ValidationResult {
+	result := &ValidationResult{
+		Valid:  true,
+		Errors: make(map[string]string),
+	}
+
+	if r.Product == "" {
+		result.Valid = false
+		result.Errors["product"] = LOAN_020
+	}
+	if !r.CreditTier.IsValid() {
+		result.Valid = false
+		result.Errors["credit_tier"] = LOAN_020
+	}
+	if !r.RiskLevel.IsValid() {
+		result.Valid = false
+		result.Errors["risk_level"] = LOAN_020
+	}
+	if r.MinTermMonths < 12 || r.MaxTermMonths > 84 || r.MinTermMonths > r.MaxTermMonths {
+		result.Valid = false
+		result.Errors["term_months"] = LOAN_003
+	}
+	if r.InterestRate <= 0 || r.InterestRate > 36 {
+		result.Valid = false
+		result.Errors["interest_rate"] = LOAN_020
+	}
+	if r.OriginationFeeRate < 0 || r.OriginationFeeRate > 10 {
+		result.Valid = false
+		result.Errors["origination_fee_rate"] = LOAN_020
+	}
+	if r.EffectiveFrom.IsZero() {
+		result.Valid = false
+		result.Errors["effective_from"] = LOAN_020
+	}
+	if r.EffectiveTo != nil && !r.EffectiveTo.After(r.EffectiveFrom) {
+		result.Valid = false
+		result.Errors["effective_to"] = LOAN_020
+	}
+
+	return result
+}
+
+// CreatePricingRateRequest represents a request to add a rate matrix entry
+// @Description Request to add an entry to a pricing rate matrix
+type CreatePricingRateRequest struct {
+	Product            string     `json:"product" binding:"required" example:"personal_loan"`
+	CreditTier         CreditTier `json:"credit_tier" binding:"required" example:"good"`
+	RiskLevel          RiskLevel  `json:"risk_level" binding:"required" example:"LOW"`
+	MinTermMonths      int        `json:"min_term_months" binding:"required,min=12,max=84" example:"12"`
+	MaxTermMonths      int        `json:"max_term_months" binding:"required,min=12,max=84" example:"36"`
+	InterestRate       float64    `json:"interest_rate" binding:"required,gt=0" example:"9.5"`
+	OriginationFeeRate float64    `json:"origination_fee_rate" binding:"min=0" example:"1.0"`
+	EffectiveFrom      time.Time  `json:"effective_from" binding:"required" example:"2025-01-01T00:00:00Z"`
+	EffectiveTo        *time.Time `json:"effective_to,omitempty" example:"2025-12-31T00:00:00Z"`
+}
+
+// UpdatePricingRateRequest represents a request to change a rate matrix entry. Only the end date of
+// a rate already in effect can change, so offers priced from it stay reproducible.
+type UpdatePricingRateRequest struct {
+	MinTermMonths      *int       `json:"min_term_months,omitempty" binding:"omitempty,min=12,max=84"`
+	MaxTermMonths      *int       `json:"max_term_months,omitempty" binding:"omitempty,min=12,max=84"`
+	InterestRate       *float64   `json:"interest_rate,omitempty" binding:"omitempty,gt=0"`
+	OriginationFeeRate *float64   `json:"origination_fee_rate,omitempty" binding:"omitempty,min=0"`
+	EffectiveFrom      *time.Time `json:"effective_from,omitempty"`
+	EffectiveTo        *time.Time `json:"effective_to,omitempty"`
+}
+
+// ChangesTerms reports whether the request changes anything other than the end date
+func (req *UpdatePricingRateRequest) ChangesTerms() bool {
+	return req.MinTermMonths != nil || req.MaxTermMonths != nil || req.InterestRate != nil ||
+		req.OriginationFeeRate != nil || req.EffectiveFrom != nil
+}
+
+// PricingRateQuery filters rate matrix listings; ActiveAt limits results to rates in effect on that date
+type PricingRateQuery struct {
+	Product    string     `form:"product"`
+	CreditTier CreditTier `form:"credit_tier"`
+	RiskLevel  RiskLevel  `form:"risk_level"`
+	ActiveAt   time.Time  `form:"active_at" time_format:"2006-01-02"`
+}
+
+// Where an offer's rate and origination fee came from
+const (
+	PricingSourceRateMatrix     = "RATE_MATRIX"
+	PricingSourceDecisionEngine = "DECISION_ENGINE"
+)
+
+// DecisionPricing is the risk-based pricing block of a decision engine decision: the rate with its
+// margin adjustments, and the origination fee less any waivers. Offers priced from it use its rate
+// and net fee at every amount and term instead of the rate matrix.
+// @Description Risk-based pricing returned by the decision engine
+type DecisionPricing struct {
+	ModelVersion          string                    `json:"model_version" example:"risk-pricing-1"`
+	BaseRate              float64                   `json:"base_rate" example:"10.5"`
+	MarginAdjustments     []PricingMarginAdjustment `json:"margin_adjustments,omitempty"`
+	Margin                float64                   `json:"margin" example:"1.25"`
+	Rate                  float64                   `json:"rate" example:"11.75"`
+	OriginationFeeRate    float64                   `json:"origination_fee_rate" example:"2.0"`
+	FeeWaivers            []PricingFeeWaiver        `json:"fee_waivers,omitempty"`
+	NetOriginationFeeRate float64                   `json:"net_origination_fee_rate" example:"1.0"`
+}
+
+// PricingMarginAdjustment is one risk-based adjustment to the decision engine's base rate
+type PricingMarginAdjustment struct {
+	Factor      string  `json:"factor" example:"CREDIT_SCORE"`
+	Adjustment  float64 `json:"adjustment" example:"0.5"`
+	Description string  `json:"description,omitempty"`
+}
+
+// PricingFeeWaiver waives all or part of a fee, in percent of the loan amount
+type PricingFeeWaiver struct {
+	Fee        string  `json:"fee" example:"ORIGINATION"`
+	Code       string  `json:"code" example:"WELL_SECURED"`
+	WaivedRate float64 `json:"waived_rate" example:"1.0"`
+	Reason     string  `json:"reason,omitempty"`
+}
+
+// PricingRequest holds the inputs an offer is priced from. When DecisionPricing is set the offer
+// is priced from the decision rather than the rate matrix.
+type PricingRequest struct {
+	ApplicationID   string
+	Product         string
+	CreditScore     int
+	RiskLevel       RiskLevel
+	TermMonths      int
+	LoanAmount      float64
+	DecisionPricing *DecisionPricing
+	PricedAt        time.Time
+}
+
+// UnderwritingDecision is the decision engine's stored decision on an application. Offers are
+// priced from the credit score, risk level and pricing it was made with, never from figures a
+// caller sends.
+type UnderwritingDecision struct {
+	ApplicationID string
+	Decision      string
+	CreditScore   int
+	RiskLevel     RiskLevel
+	Pricing       *DecisionPricing // risk-based price; none for denials and decisions priced from the rate matrix
+	// Provisional decisions were made without data sources that missed their SLA and are
+	// re-decided when those arrive; offers are not priced from them
+	Provisional bool
+	DecidedAt   time.Time
+}
+
+// PricingAudit records how an offer was priced: the inputs, the rate matrix entry that matched or
+// the decision pricing used, and the resulting figures. The fingerprint covers everything except
+// PricedAt, so pricing the same inputs against the same rate always yields the same fingerprint.
+type PricingAudit struct {
+	ApplicationID string     `json:"application_id"`
+	OfferID       string     `json:"offer_id,omitempty"`
+	Product       string     `json:"product"`
+	CreditScore   int        `json:"credit_score"`
+	CreditTier    CreditTier `json:"credit_tier"`
+	RiskLevel     RiskLevel  `json:"risk_level"`
+	TermMonths    int        `json:"term_months"`
+	LoanAmount    float64    `json:"loan_amount"`
+	// Source is empty on audits from before decision pricing, which were all priced from the matrix
+	Source            string    `json:"source,omitempty"`
+	RateID            string    `json:"rate_id"`
+	RateEffectiveFrom time.Time `json:"rate_effective_from"`
+	// DecisionPricing is kept on offers priced from a decision so they are repriced from it too
+	DecisionPricing    *DecisionPricing `json:"decision_pricing,omitempty"`
+	InterestRate       float64          `json:"interest_rate"`
+	OriginationFeeRate float64          `json:"origination_fee_rate"`
+	APR                float64          `json:"apr"`
+	MonthlyPayment     float64          `json:"monthly_payment"`
+	TotalInterest      float64          `json:"total_interest"`
+	PricedAt           time.Time        `json:"priced_at"`
+	Fingerprint        string           `json:"fingerprint"`
+}
+
+// ComputeFingerprint hashes the pricing inputs, the matched rate and the priced figures. Offers
+// priced from a decision also cover the source and pricing model version; matrix fingerprints
+// are unchanged from before decision pricing.
+func (a *PricingAudit) ComputeFingerprint() string {
+	canonical := fmt.Sprintf("%s|%d|%s|%s|%d|%.2f|%s|%s|%.4f|%.4f|%.4f|%.2f|%.2f",
+		a.Product, a.CreditScore, a.CreditTier, a.RiskLevel, a.TermMonths, a.LoanAmount,
+		a.RateID, a.RateEffectiveFrom.UTC().Format(time.RFC3339Nano),
+		a.InterestRate, a.OriginationFeeRate, a.APR, a.MonthlyPayment, a.TotalInterest,
+	)
+	if a.Source == PricingSourceDecisionEngine && a.DecisionPricing != nil {
+		canonical += fmt.Sprintf("|%s|%s", a.Source, a.DecisionPricing.ModelVersion)
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:])
+}
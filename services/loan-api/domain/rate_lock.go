@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// RateLockTrigger is what locked an offer's rate
+type RateLockTrigger string
+
+const (
+	RateLockViewed   RateLockTrigger = "viewed"   // the borrower viewed the pending offer
+	RateLockAccepted RateLockTrigger = "accepted" // the borrower selected the offer
+	RateLockRepriced RateLockTrigger = "repriced" // the offer was priced again after its lock expired
+)
+
+// RateLockStatus reports whether a rate lock still holds
+type RateLockStatus string
+
+const (
+	RateLockActive  RateLockStatus = "active"
+	RateLockExpired RateLockStatus = "expired"
+)
+
+// RateLock guarantees an offer's interest rate and APR from LockedAt until ExpiresAt. An offer
+// whose lock has expired must be priced again at current rates before it can be selected.
+type RateLock struct {
+	ID            string          `json:"id" db:"id"`
+	OfferID       string          `json:"offer_id" db:"offer_id"`
+	ApplicationID string          `json:"application_id" db:"application_id"`
+	Product       string          `json:"product" db:"product"`
+	InterestRate  float64         `json:"interest_rate" db:"interest_rate"`
+	APR           float64         `json:"apr" db:"apr"`
+	Trigger       RateLockTrigger `json:"trigger" db:"trigger"`
+	Status        RateLockStatus  `json:"status" db:"-"`
+	LockedAt      time.Time       `json:"locked_at" db:"locked_at"`
+	ExpiresAt     time.Time       `json:"expires_at" db:"expires_at"`
+}
+
+// IsExpired reports whether the lock no longer holds at the given time
+func (l *RateLock) IsExpired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// StatusAt returns the lock's status at the given time
+func (l *RateLock) StatusAt(now time.Time) RateLockStatus {
+	if l.IsExpired(now) {
+		return RateLockExpired
+	}
+	return RateLockActive
+}
+
+// PricedAt returns when the offer's figures were priced: from its pricing audit, or when it was
+// made for offers priced outside the rate matrices, such as counter offers
+func (o *LoanOffer) PricedAt() time.Time {
+	if o.PricingAudit != nil && !o.PricingAudit.PricedAt.IsZero() {
+		return o.PricingAudit.PricedAt
+	}
+	return o.CreatedAt
+}
+
+// Product returns the product the offer was priced as
+func (o *LoanOffer) Product() string {
+	if o.PricingAudit != nil && o.PricingAudit.Product != "" {
+		return o.PricingAudit.Product
+	}
+	return ProductPersonalLoan
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// ApplicationType distinguishes applications for a new loan from those refinancing an existing one
+type ApplicationType string
+
+const (
+	ApplicationTypeNew       ApplicationType = "new"
+	ApplicationTypeRefinance ApplicationType = "refinance" // pays off the borrower's funded loan
+)
+
+// PayoffQuoteValidity is how long a payoff quote holds; interest is accrued through its end
+const PayoffQuoteValidity = 10 * 24 * time.Hour
+
+// IsRefinance reports whether the application refinances an existing loan
+func (app *LoanApplication) IsRefinance() bool {
+	return app.ApplicationType == ApplicationTypeRefinance && app.RefinancedApplicationID != nil
+}
+
+// CanBeRefinanced reports whether the application is a funded loan that is still being repaid
+func (app *LoanApplication) CanBeRefinanced() bool {
+	return app.CurrentState == StateFunded || app.CurrentState == StateActive
+}
+
+// PayoffQuote is the amount that pays off a funded loan in full through GoodThrough, together with
+// what keeping the loan would still cost
+type PayoffQuote struct {
+	ApplicationID     string    `json:"application_id"`
+	PrincipalBalance  float64   `json:"principal_balance"`
+	AccruedInterest   float64   `json:"accrued_interest"` // through GoodThrough
+	PerDiemInterest   float64   `json:"per_diem_interest"`
+	PayoffAmount      float64   `json:"payoff_amount"`
+	InterestRate      float64   `json:"interest_rate"`
+	MonthlyPayment    float64   `json:"monthly_payment"`
+	RemainingPayments int       `json:"remaining_payments"`
+	RemainingInterest float64   `json:"remaining_interest"` // scheduled interest still to be paid
+	MaturityDate      time.Time `json:"maturity_date"`
+	GoodThrough       time.Time `json:"good_through"`
+	QuotedAt          time.Time `json:"quoted_at"`
+}
+
+// NewPayoffQuote quotes the payoff of a loan from its repayment schedule. Installments due by asOf
+// are taken as paid; interest accrues daily, on an actual/365 basis, on the balance after the last
+// of them from its due date, or from funding, through the end of the quote's validity.
+func NewPayoffQuote(schedule *RepaymentSchedule, asOf time.Time) *PayoffQuote {
+	asOf = asOf.UTC()
+	quote := &PayoffQuote{
+		ApplicationID:    schedule.ApplicationID,
+		PrincipalBalance: schedule.Principal,
+		InterestRate:     schedule.InterestRate,
+		MonthlyPayment:   schedule.PaymentAmount,
+		MaturityDate:     schedule.MaturityDate,
+		GoodThrough:      asOf.Add(PayoffQuoteValidity),
+		QuotedAt:         asOf,
+	}
+
+	accruedFrom := schedule.FundedAt
+	for _, installment := range schedule.Installments {
+		if !installment.DueDate.After(asOf) {
+			quote.PrincipalBalance = installment.RemainingBalance
+			accruedFrom = installment.DueDate
+			continue
+		}
+		quote.RemainingPayments++
+		quote.RemainingInterest += installment.Interest
+	}
+	quote.RemainingInterest = roundCents(quote.RemainingInterest)
+
+	if quote.PrincipalBalance <= 0 {
+		quote.PrincipalBalance = 0
+		return quote
+	}
+
+	days := math.Ceil(quote.GoodThrough.Sub(accruedFrom).Hours() / 24)
+	quote.PerDiemInterest = roundCents(quote.PrincipalBalance * quote.InterestRate / 100 / 365)
+	quote.AccruedInterest = roundCents(quote.PrincipalBalance * quote.InterestRate / 100 / 365 * math.Max(days, 0))
+	quote.PayoffAmount = roundCents(quote.PrincipalBalance + quote.AccruedInterest)
+	return quote
+}
+
+// RefinanceComparison sets a refinance offer against keeping the loan it pays off. Positive savings
+// favour refinancing; cash out is what is left for the borrower once the prior loan is paid off.
+type RefinanceComparison struct {
+	RefinancedApplicationID  string  `json:"refinanced_application_id"`
+	PayoffAmount             float64 `json:"payoff_amount"`
+	CashOut                  float64 `json:"cash_out"`
+	CurrentInterestRate      float64 `json:"current_interest_rate"`
+	CurrentMonthlyPayment    float64 `json:"current_monthly_payment"`
+	CurrentRemainingPayments int     `json:"current_remaining_payments"`
+	CurrentRemainingInterest float64 `json:"current_remaining_interest"`
+	NewInterestRate          float64 `json:"new_interest_rate"`
+	NewMonthlyPayment        float64 `json:"new_monthly_payment"`
+	NewTermMonths            int     `json:"new_term_months"`
+	NewTotalInterest         float64 `json:"new_total_interest"`
+	MonthlyPaymentSavings    float64 `json:"monthly_payment_savings"`
+	InterestSavings          float64 `json:"interest_savings"`
+}
+
+// NewRefinanceComparison compares an offer with keeping the quoted loan
+func NewRefinanceComparison(quote *PayoffQuote, offer *LoanOffer) *RefinanceComparison {
+	return &RefinanceComparison{
+		RefinancedApplicationID:  quote.ApplicationID,
+		PayoffAmount:             quote.PayoffAmount,
+		CashOut:                  roundCents(offer.OfferAmount - quote.PayoffAmount),
+		CurrentInterestRate:      quote.InterestRate,
+		CurrentMonthlyPayment:    quote.MonthlyPayment,
+		CurrentRemainingPayments: quote.RemainingPayments,
+		CurrentRemainingInterest: quote.RemainingInterest,
+		NewInterestRate:          offer.InterestRate,
+		NewMonthlyPayment:        offer.MonthlyPayment,
+		NewTermMonths:            offer.TermMonths,
+		NewTotalInterest:         offer.TotalInterest,
+		MonthlyPaymentSavings:    roundCents(quote.MonthlyPayment - offer.MonthlyPayment),
+		InterestSavings:          roundCents(quote.RemainingInterest - offer.TotalInterest),
+	}
+}
+
+// TransferAmount is the part of the disbursement sent to the borrower's bank account; on a
+// refinance the rest pays off the prior loan
+func (d *Disbursement) TransferAmount() float64 {
+	return roundCents(d.Amount - d.PayoffAmount)
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// MaxPaymentDueDay is the latest day of the month payments fall due on, so every month has the
+// due date. Loans funded later in the month are due on this day.
+const MaxPaymentDueDay = 28
+
+// InstallmentStatus is the payment status of a scheduled installment
+type InstallmentStatus string
+
+const (
+	InstallmentScheduled InstallmentStatus = "scheduled"
+)
+
+// RepaymentSchedule is the schedule of monthly payments that repays a funded loan. It is generated
+// from the selected offer when the application is funded.
+type RepaymentSchedule struct {
+	ID               string    `json:"id" db:"id"`
+	ApplicationID    string    `json:"application_id" db:"application_id"`
+	OfferID          string    `json:"offer_id" db:"offer_id"`
+	DisbursementID   *string   `json:"disbursement_id,omitempty" db:"disbursement_id"`
+	Principal        float64   `json:"principal" db:"principal"`
+	InterestRate     float64   `json:"interest_rate" db:"interest_rate"`
+	TermMonths       int       `json:"term_months" db:"term_months"`
+	PaymentAmount    float64   `json:"payment_amount" db:"payment_amount"` // every installment but the last, which settles the balance
+	PaymentDueDay    int       `json:"payment_due_day" db:"payment_due_day"`
+	FundedAt         time.Time `json:"funded_at" db:"funded_at"`
+	FirstPaymentDate time.Time `json:"first_payment_date" db:"first_payment_date"`
+	MaturityDate     time.Time `json:"maturity_date" db:"maturity_date"`
+	TotalInterest    float64   `json:"total_interest" db:"total_interest"`
+	TotalOfPayments  float64   `json:"total_of_payments" db:"total_of_payments"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+
+	Installments []*RepaymentInstallment `json:"installments,omitempty" db:"-"`
+}
+
+// RepaymentInstallment is one scheduled monthly payment and how it splits between principal and interest
+type RepaymentInstallment struct {
+	ID                string            `json:"id" db:"id"`
+	ScheduleID        string            `json:"schedule_id" db:"schedule_id"`
+	ApplicationID     string            `json:"application_id" db:"application_id"`
+	InstallmentNumber int               `json:"installment_number" db:"installment_number"`
+	DueDate           time.Time         `json:"due_date" db:"due_date"`
+	PaymentAmount     float64           `json:"payment_amount" db:"payment_amount"`
+	Principal         float64           `json:"principal" db:"principal"`
+	Interest          float64           `json:"interest" db:"interest"`
+	RemainingBalance  float64           `json:"remaining_balance" db:"remaining_balance"` // after this payment
+	Status            InstallmentStatus `json:"status" db:"status"`
+	CreatedAt         time.Time         `json:"created_at" db:"created_at"`
+}
+
+// PaymentDueDay is the day of the month payments on a loan funded at the given time fall due
+func PaymentDueDay(fundedAt time.Time) int {
+	return min(fundedAt.UTC().Day(), MaxPaymentDueDay)
+}
+
+// FirstPaymentDate is when the first monthly payment on a loan funded at the given time falls due:
+// the payment due day of the following month, which is always at least 28 days after funding
+func FirstPaymentDate(fundedAt time.Time) time.Time {
+	return PaymentDueDate(fundedAt, 1)
+}
+
+// PaymentDueDate is when the nth monthly payment on a loan funded at the given time falls due
+func PaymentDueDate(fundedAt time.Time, installment int) time.Time {
+	funded := fundedAt.UTC()
+	return time.Date(funded.Year(), funded.Month()+time.Month(installment), PaymentDueDay(funded), 0, 0, 0, 0, time.UTC)
+}
+
+// NewRepaymentSchedule amortizes the offer's amount over its term in the offer's monthly payments,
+// starting the month after funding. Interest accrues monthly on the remaining balance and each
+// amount is rounded to the cent, so the last installment is adjusted to pay the loan off exactly.
+func NewRepaymentSchedule(offer *LoanOffer, fundedAt time.Time, newID func() string) *RepaymentSchedule {
+	now := time.Now().UTC()
+	schedule := &RepaymentSchedule{
+		ID:               newID(),
+		ApplicationID:    offer.ApplicationID,
+		OfferID:          offer.ID,
+		Principal:        offer.OfferAmount,
+		InterestRate:     offer.InterestRate,
+		TermMonths:       offer.TermMonths,
+		PaymentAmount:    offer.MonthlyPayment,
+		PaymentDueDay:    PaymentDueDay(fundedAt),
+		FundedAt:         fundedAt,
+		FirstPaymentDate: FirstPaymentDate(fundedAt),
+		MaturityDate:     PaymentDueDate(fundedAt, offer.TermMonths),
+		CreatedAt:        now,
+	}
+
+	monthlyRate := offer.InterestRate / 100 / 12
+	balance := offer.OfferAmount
+	for n := 1; n <= offer.TermMonths; n++ {
+		interest := roundCents(balance * monthlyRate)
+		payment := offer.MonthlyPayment
+		if n == offer.TermMonths {
+			payment = roundCents(balance + interest)
+		}
+		principal := roundCents(payment - interest)
+		balance = roundCents(balance - principal)
+
+		schedule.Installments = append(schedule.Installments, &RepaymentInstallment{
+			ID:                newID(),
+			ScheduleID:        schedule.ID,
+			ApplicationID:     offer.ApplicationID,
+			InstallmentNumber: n,
+			DueDate:           PaymentDueDate(fundedAt, n),
+			PaymentAmount:     payment,
+			Principal:         principal,
+			Interest:          interest,
+			RemainingBalance:  balance,
+			Status:            InstallmentScheduled,
+			CreatedAt:         now,
+		})
+		schedule.TotalInterest += interest
+		schedule.TotalOfPayments += payment
+	}
+	schedule.TotalInterest = roundCents(schedule.TotalInterest)
+	schedule.TotalOfPayments = roundCents(schedule.TotalOfPayments)
+
+	return schedule
+}
+
+// roundCents rounds an amount to the nearest cent
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentDueDate(t *testing.T) {
+	tests := []struct {
+		name        string
+		fundedAt    time.Time
+		installment int
+		want        time.Time
+	}{
+		{
+			name:        "first payment the following month on the funding day",
+			fundedAt:    time.Date(2026, 3, 15, 14, 30, 0, 0, time.UTC),
+			installment: 1,
+			want:        time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "funded after the 28th falls due on the 28th",
+			fundedAt:    time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+			installment: 1,
+			want:        time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "installments roll over the year",
+			fundedAt:    time.Date(2026, 11, 10, 0, 0, 0, 0, time.UTC),
+			installment: 3,
+			want:        time.Date(2027, 2, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "funding time is taken in UTC",
+			fundedAt:    time.Date(2026, 5, 31, 22, 0, 0, 0, time.FixedZone("UTC-5", -5*60*60)),
+			installment: 1,
+			want:        time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, PaymentDueDate(tt.fundedAt, tt.installment))
+		})
+	}
+}
+
+func TestNewRepaymentSchedule(t *testing.T) {
+	fundedAt := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		amount          float64
+		interestRate    float64
+		termMonths      int
+		monthlyPayment  float64
+		lastPayment     float64
+		totalInterest   float64
+		totalOfPayments float64
+	}{
+		{
+			name:            "36 months at 12%",
+			amount:          10000,
+			interestRate:    12,
+			termMonths:      36,
+			monthlyPayment:  332.14,
+			lastPayment:     332.28,
+			totalInterest:   1957.18,
+			totalOfPayments: 11957.18,
+		},
+		{
+			name:            "60 months at 8.5%",
+			amount:          25000,
+			interestRate:    8.5,
+			termMonths:      60,
+			monthlyPayment:  512.91,
+			lastPayment:     513.17,
+			totalInterest:   5774.86,
+			totalOfPayments: 30774.86,
+		},
+		{
+			name:            "interest free",
+			amount:          5000,
+			interestRate:    0,
+			termMonths:      48,
+			monthlyPayment:  104.17,
+			lastPayment:     104.01,
+			totalInterest:   0,
+			totalOfPayments: 5000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := 0
+			offer := &LoanOffer{
+				ID:             "offer-1",
+				ApplicationID:  "app-1",
+				OfferAmount:    tt.amount,
+				InterestRate:   tt.interestRate,
+				TermMonths:     tt.termMonths,
+				MonthlyPayment: tt.monthlyPayment,
+			}
+
+			schedule := NewRepaymentSchedule(offer, fundedAt, func() string {
+				ids++
+				return fmt.Sprintf("id-%d", ids)
+			})
+
+			require.Len(t, schedule.Installments, tt.termMonths)
+			assert.Equal(t, 15, schedule.PaymentDueDay)
+			assert.Equal(t, time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC), schedule.FirstPaymentDate)
+			assert.Equal(t, PaymentDueDate(fundedAt, tt.termMonths), schedule.MaturityDate)
+			assert.InDelta(t, tt.totalInterest, schedule.TotalInterest, 0.001)
+			assert.InDelta(t, tt.totalOfPayments, schedule.TotalOfPayments, 0.001)
+
+			principal := 0.0
+			for i, installment := range schedule.Installments {
+				assert.Equal(t, i+1, installment.InstallmentNumber)
+				assert.Equal(t, schedule.ID, installment.ScheduleID)
+				assert.Equal(t, InstallmentScheduled, installment.Status)
+				assert.InDelta(t, installment.PaymentAmount, installment.Principal+installment.Interest, 0.001)
+				if i < len(schedule.Installments)-1 {
+					assert.Equal(t, tt.monthlyPayment, installment.PaymentAmount)
+				}
+				principal += installment.Principal
+			}
+
+			last := schedule.Installments[len(schedule.Installments)-1]
+			assert.InDelta(t, tt.lastPayment, last.PaymentAmount, 0.001)
+			assert.Zero(t, last.RemainingBalance)
+			assert.Equal(t, schedule.MaturityDate, last.DueDate)
+			assert.InDelta(t, tt.amount, principal, 0.001)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaterialChangeType is a change to an application that makes its underwriting decision stale
+type MaterialChangeType string
+
+const (
+	MaterialChangeLoanAmount            MaterialChangeType = "loan_amount"
+	MaterialChangeRequestedTerm         MaterialChangeType = "requested_term"
+	MaterialChangeIncome                MaterialChangeType = "income"
+	MaterialChangeDebt                  MaterialChangeType = "debt"
+	MaterialChangeDerogatoryInformation MaterialChangeType = "derogatory_information"
+)
+
+// MaterialChange is a change to an application its prior decision did not take into account
+type MaterialChange struct {
+	Type        MaterialChangeType `json:"type"`
+	Description string             `json:"description"`
+}
+
+// MaterialChanges lists the changes between two versions of an application that the underwriting
+// decision depends on; a new loan purpose alone does not change the decision
+func MaterialChanges(before, after *LoanApplication) []MaterialChange {
+	var changes []MaterialChange
+	if before.LoanAmount != after.LoanAmount {
+		changes = append(changes, MaterialChange{
+			Type:        MaterialChangeLoanAmount,
+			Description: fmt.Sprintf("Loan amount changed from $%.2f to $%.2f", before.LoanAmount, after.LoanAmount),
+		})
+	}
+	if before.RequestedTerm != after.RequestedTerm {
+		changes = append(changes, MaterialChange{
+			Type:        MaterialChangeRequestedTerm,
+			Description: fmt.Sprintf("Requested term changed from %d to %d months", before.RequestedTerm, after.RequestedTerm),
+		})
+	}
+	if before.AnnualIncome != after.AnnualIncome || before.MonthlyIncome != after.MonthlyIncome {
+		changes = append(changes, MaterialChange{
+			Type:        MaterialChangeIncome,
+			Description: fmt.Sprintf("Monthly income changed from $%.2f to $%.2f", before.MonthlyIncome, after.MonthlyIncome),
+		})
+	}
+	if before.MonthlyDebt != after.MonthlyDebt {
+		changes = append(changes, MaterialChange{
+			Type:        MaterialChangeDebt,
+			Description: fmt.Sprintf("Monthly debt payments changed from $%.2f to $%.2f", before.MonthlyDebt, after.MonthlyDebt),
+		})
+	}
+	return changes
+}
+
+// IsAwaitingFunding reports whether the application has been approved but not yet funded, so a
+// material change must send it back to underwriting
+func (app *LoanApplication) IsAwaitingFunding() bool {
+	return app.CurrentState == StateApproved || app.CurrentState == StateDocumentsSigned
+}
+
+// DerogatoryInformationRequest reports derogatory credit information about a borrower that
+// arrived after their application was decided
+// @Description New derogatory credit information about the borrower
+type DerogatoryInformationRequest struct {
+	ItemType    string     `json:"item_type" binding:"required,oneof=bankruptcy judgment tax_lien collection charge_off late_payment" example:"collection"`
+	Bureau      string     `json:"bureau,omitempty" example:"EXPERIAN"`
+	ReportedAt  *time.Time `json:"reported_at,omitempty"`
+	Description string     `json:"description,omitempty" binding:"max=500" example:"Medical collection of $1,250 reported"`
+}
+
+// MaterialChange is the change the derogatory information makes to the application
+func (req *DerogatoryInformationRequest) MaterialChange() MaterialChange {
+	description := fmt.Sprintf("New %s reported", req.ItemType)
+	if req.Bureau != "" {
+		description += " by " + req.Bureau
+	}
+	if req.Description != "" {
+		description += ": " + req.Description
+	}
+	return MaterialChange{Type: MaterialChangeDerogatoryInformation, Description: description}
+}
+
+// Reunderwriting is the outcome of a material change to an application: whether its prior
+// decision was invalidated and the application sent back to underwriting before funding
+type Reunderwriting struct {
+	ApplicationID         string           `json:"application_id"`
+	FromState             ApplicationState `json:"from_state"`
+	Changes               []MaterialChange `json:"changes"`
+	Started               bool             `json:"started"`
+	Reason                string           `json:"reason,omitempty"` // why it was not started
+	WorkflowID            *string          `json:"workflow_id,omitempty"`
+	DisbursementCancelled bool             `json:"disbursement_cancelled"`
+	StartedAt             *time.Time       `json:"started_at,omitempty"`
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Application search limits
+const (
+	MinSearchTermLength     = 2
+	DefaultSearchPageSize   = 20
+	MaxSearchPageSize       = 100
+	SearchHighlightStartTag = "<mark>"
+	SearchHighlightStopTag  = "</mark>"
+)
+
+// Fields an application search term can match
+const (
+	SearchFieldApplicantName     = "applicant_name"
+	SearchFieldEmail             = "email"
+	SearchFieldApplicationNumber = "application_number"
+	SearchFieldNotes             = "notes"
+)
+
+// ApplicationSearchQuery is a back-office lookup over applicant name, email, application number and notes
+type ApplicationSearchQuery struct {
+	Q        string `json:"q" form:"q"`
+	Page     int    `json:"page,omitempty" form:"page"`
+	PageSize int    `json:"page_size,omitempty" form:"page_size"`
+}
+
+// ApplyDefaults trims the term and fills in the default page window
+func (q *ApplicationSearchQuery) ApplyDefaults() {
+	q.Q = strings.TrimSpace(q.Q)
+	if q.Page <= 0 {
+		q.Page = 1
+	}
+	if q.PageSize <= 0 {
+		q.PageSize = DefaultSearchPageSize
+	}
+	if q.PageSize > MaxSearchPageSize {
+		q.PageSize = MaxSearchPageSize
+	}
+}
+
+// Validate validates the search query
+func (q *ApplicationSearchQuery) Validate() *ValidationResult {
+	result := &ValidationResult{Valid: true, Errors: make(map[string]string)}
+
+	if len([]rune(q.Q)) < MinSearchTermLength {
+		result.Errors["q"] = "Search term must be at least 2 characters"
+	}
+
+	result.Valid = len(result.Errors) == 0
+	return result
+}
+
+// Offset returns the number of rows skipped before the requested page
+func (q *ApplicationSearchQuery) Offset() int {
+	return (q.Page - 1) * q.PageSize
+}
+
+// ApplicationSearchResult is one application matching a search, ranked by how well it matched.
+// Highlights hold the matching text of each matched field with the term wrapped in <mark> tags.
+type ApplicationSearchResult struct {
+	ApplicationID     string            `json:"application_id" db:"id"`
+	ApplicationNumber string            `json:"application_number" db:"application_number"`
+	UserID            string            `json:"user_id" db:"user_id"`
+	ApplicantName     string            `json:"applicant_name" db:"applicant_name"`
+	Email             string            `json:"email" db:"email"`
+	LoanAmount        float64           `json:"loan_amount" db:"loan_amount"`
+	CurrentState      ApplicationState  `json:"current_state" db:"current_state"`
+	Status            ApplicationStatus `json:"status" db:"status"`
+	CreatedAt         time.Time         `json:"created_at" db:"created_at"`
+	Rank              float64           `json:"rank" db:"rank"`
+	MatchedFields     []string          `json:"matched_fields"`
+	Highlights        map[string]string `json:"highlights,omitempty"`
+}
+
+// ApplicationSearchPage is one page of search results along with pagination metadata
+type ApplicationSearchPage struct {
+	Query      string                     `json:"query"`
+	Results    []*ApplicationSearchResult `json:"results"`
+	Page       int                        `json:"page"`
+	PageSize   int                        `json:"page_size"`
+	Total      int                        `json:"total"`
+	TotalPages int                        `json:"total_pages"`
+}
+
+// HighlightSearchTerm wraps every case-insensitive occurrence of the term in the text in <mark> tags.
+// Text the term only matched by similarity is returned unchanged.
+func HighlightSearchTerm(text, term string) string {
+	if term == "" {
+		return text
+	}
+
+	lowerText, lowerTerm := strings.ToLower(text), strings.ToLower(term)
+	if len(lowerText) != len(text) || len(lowerTerm) != len(term) {
+		// case folding changed byte offsets; fall back to an exact-case match
+		return strings.ReplaceAll(text, term, SearchHighlightStartTag+term+SearchHighlightStopTag)
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(lowerText, lowerTerm)
+		if i < 0 {
+			b.WriteString(text)
+			return b.String()
+		}
+		b.WriteString(text[:i])
+		b.WriteString(SearchHighlightStartTag)
+		b.WriteString(text[i : i+len(term)])
+		b.WriteString(SearchHighlightStopTag)
+		text, lowerText = text[i+len(term):], lowerText[i+len(term):]
+	}
+}
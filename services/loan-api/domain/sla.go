@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"time"
+)
+
+// SLAAction is the escalation taken when an application overstays an SLA
+type SLAAction string
+
+const (
+	SLAActionNotify       SLAAction = "notify"        // notify the borrower or a staff recipient
+	SLAActionPriorityBump SLAAction = "priority_bump" // raise the application's review priority
+	SLAActionAutoWithdraw SLAAction = "auto_withdraw" // withdraw the application
+)
+
+// IsValid checks if the action is one of the known SLA actions
+func (a SLAAction) IsValid() bool {
+	switch a {
+	case SLAActionNotify, SLAActionPriorityBump, SLAActionAutoWithdraw:
+		return true
+	default:
+		return false
+	}
+}
+
+// SLARule limits how long an application may stay in a state before it is escalated. Several rules
+// on one state form an escalation ladder, e.g. notify after 24h and bump priority after 48h.
+type SLARule struct {
+	Name      string
+	State     ApplicationState
+	Within    time.Duration
+	Action    SLAAction
+	Recipient string // user notified by the notify action; empty notifies the borrower
+}
+
+// SLACandidate is an application that has been in a rule's state past its SLA and not yet escalated
+// for this stay
+type SLACandidate struct {
+	ApplicationID  string           `json:"application_id" db:"id"`
+	UserID         string           `json:"user_id" db:"user_id"`
+	State          ApplicationState `json:"state" db:"current_state"`
+	StateEnteredAt time.Time        `json:"state_entered_at" db:"state_entered_at"`
+}
+
+// SLABreach records one SLA escalation of an application's stay in a state
+type SLABreach struct {
+	ID             string           `json:"id" db:"id"`
+	ApplicationID  string           `json:"application_id" db:"application_id"`
+	SLAName        string           `json:"sla_name" db:"sla_name"`
+	State          ApplicationState `json:"state" db:"state"`
+	Action         SLAAction        `json:"action" db:"action"`
+	StateEnteredAt time.Time        `json:"state_entered_at" db:"state_entered_at"`
+	DueAt          time.Time        `json:"due_at" db:"due_at"`
+	BreachedAt     time.Time        `json:"breached_at" db:"breached_at"`
+	ActionError    *string          `json:"action_error,omitempty" db:"action_error"`
+
+	// Open reports whether the application is still in the breached stay; not persisted
+	Open bool `json:"open" db:"-"`
+	// Priority is the application's current review priority; not persisted
+	Priority int `json:"priority" db:"-"`
+	// OverdueMinutes is how long past due an open stay is now; not persisted
+	OverdueMinutes int `json:"overdue_minutes" db:"-"`
+}
+
+// SLABreachQuery filters the SLA breach listing
+type SLABreachQuery struct {
+	State    ApplicationState `form:"state"`
+	SLAName  string           `form:"sla"`
+	OpenOnly bool             `form:"open_only"`
+	Limit    int              `form:"limit" binding:"omitempty,min=1,max=100"`
+}
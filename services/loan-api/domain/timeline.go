@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// TimelineEventType is the kind of event shown on an application timeline
+type TimelineEventType string
+
+const (
+	TimelineStateChanged      TimelineEventType = "state_changed"
+	TimelineOfferCreated      TimelineEventType = "offer_created"
+	TimelineOfferNegotiation  TimelineEventType = "offer_negotiation"
+	TimelineDocumentUploaded  TimelineEventType = "document_uploaded"
+	TimelineWorkflowStarted   TimelineEventType = "workflow_started"
+	TimelineWorkflowCompleted TimelineEventType = "workflow_completed"
+	TimelineNoteAdded         TimelineEventType = "note_added"
+)
+
+// Timeline actor types
+const (
+	TimelineActorBorrower = "borrower"
+	TimelineActorStaff    = "staff"
+	TimelineActorSystem   = "system"
+)
+
+// TimelineActor is who caused a timeline event
+type TimelineActor struct {
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"`
+	Role string `json:"role,omitempty"`
+}
+
+// TimelineEvent is one entry in an application's chronological activity feed
+type TimelineEvent struct {
+	ID          string                 `json:"id"`
+	Type        TimelineEventType      `json:"type"`
+	OccurredAt  time.Time              `json:"occurred_at"`
+	Actor       TimelineActor          `json:"actor"`
+	Description string                 `json:"description"`
+	Details     map[string]interface{} `json:"details,omitempty"`
+}
+
+// ApplicationTimeline is the merged activity feed of an application
+type ApplicationTimeline struct {
+	ApplicationID string           `json:"application_id"`
+	Events        []*TimelineEvent `json:"events"`
+}
+
+// SortTimelineEvents orders events oldest first, keeping the source order of simultaneous events
+func SortTimelineEvents(events []*TimelineEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].OccurredAt.Before(events[j].OccurredAt)
+	})
+}
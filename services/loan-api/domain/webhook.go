@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"encoding/json"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WebhookEventType is an event partners can subscribe to
+type WebhookEventType string
+
+const (
+	// WebhookApplicationStateChanged is recorded by the database for every state transition, whichever
+	// service made it
+	WebhookApplicationStateChanged WebhookEventType = "application.state_changed"
+
+	WebhookOfferGenerated        WebhookEventType = "offer.generated"
+	WebhookOfferAccepted         WebhookEventType = "offer.accepted"
+	WebhookOfferDeclined         WebhookEventType = "offer.declined"
+	WebhookOfferCounterRequested WebhookEventType = "offer.counter_requested"
+	WebhookOfferExpired          WebhookEventType = "offer.expired"
+)
+
+// WebhookEventTypes lists every event type partners can subscribe to
+var WebhookEventTypes = []WebhookEventType{
+	WebhookApplicationStateChanged,
+	WebhookOfferGenerated,
+	WebhookOfferAccepted,
+	WebhookOfferDeclined,
+	WebhookOfferCounterRequested,
+	WebhookOfferExpired,
+}
+
+// IsValid checks if the event type is one partners can subscribe to
+func (t WebhookEventType) IsValid() bool {
+	for _, eventType := range WebhookEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSubscription is a partner endpoint that receives signed events. The secret is the HMAC
+// key for the X-Webhook-Signature header and is only returned when the subscription is created.
+type WebhookSubscription struct {
+	ID         string             `json:"id" db:"id"`
+	Name       string             `json:"name" db:"name"`
+	URL        string             `json:"url" db:"url"`
+	Secret     string             `json:"secret,omitempty" db:"secret"`
+	EventTypes []WebhookEventType `json:"event_types" db:"event_types"` // empty subscribes to every event
+	Active     bool               `json:"active" db:"active"`
+	CreatedAt  time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// Subscribes reports whether the subscription receives events of the given type
+func (s *WebhookSubscription) Subscribes(eventType WebhookEventType) bool {
+	if !s.Active {
+		return false
+	}
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, subscribed := range s.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateWebhookSubscriptionRequest registers a partner endpoint for webhook events
+type CreateWebhookSubscriptionRequest struct {
+	Name       string             `json:"name" binding:"required,max=100" example:"Acme Marketplace"`
+	URL        string             `json:"url" binding:"required,url" example:"https://partner.example.com/los/webhooks"`
+	EventTypes []WebhookEventType `json:"event_types,omitempty" example:"application.state_changed,offer.accepted"` // empty subscribes to every event
+}
+
+// Validate validates a webhook subscription: the endpoint must use HTTPS, carry no credentials and
+// not name a non-public address, and every event type must be known. Hostnames are resolved and
+// checked by the webhook service.
+func (req *CreateWebhookSubscriptionRequest) Validate() *ValidationResult {
+	result := &ValidationResult{
+		Valid:  true,
+		Errors: make(map[string]string),
+	}
+
+	parsed, err := url.Parse(req.URL)
+	if err != nil || !strings.EqualFold(parsed.Scheme, "https") || parsed.Hostname() == "" || parsed.User != nil {
+		result.Valid = false
+		result.Errors["url"] = LOAN_020
+	} else if ip := net.ParseIP(parsed.Hostname()); ip != nil && !IsPublicWebhookAddress(ip) {
+		result.Valid = false
+		result.Errors["url"] = LOAN_020
+	}
+	for _, eventType := range req.EventTypes {
+		if !eventType.IsValid() {
+			result.Valid = false
+			result.Errors["event_types"] = LOAN_020
+			break
+		}
+	}
+
+	return result
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which net.IP does not treat as private
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPublicWebhookAddress reports whether a webhook may be delivered to ip. Loopback, private,
+// link-local, multicast and unspecified addresses are refused so endpoints cannot reach internal services.
+func IsPublicWebhookAddress(ip net.IP) bool {
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	if ip4 := ip.To4(); ip4 != nil && (ip4[0] == 0 || sharedAddressSpace.Contains(ip4)) {
+		return false
+	}
+	return true
+}
+
+// WebhookEvent is an event queued for delivery to the subscriptions of its type
+type WebhookEvent struct {
+	ID            string           `json:"id" db:"id"`
+	Type          WebhookEventType `json:"type" db:"event_type"`
+	ApplicationID string           `json:"application_id" db:"application_id"`
+	Data          json.RawMessage  `json:"data" db:"payload"`
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+}
+
+// WebhookDeliveryStatus is where delivery of an event to one subscription stands
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending" // waiting for its first attempt or a retry
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed" // out of attempts
+)
+
+// WebhookDelivery is the delivery of an event to one subscription and the outcome of its latest
+// attempt
+type WebhookDelivery struct {
+	ID             string                `json:"id" db:"id"`
+	SubscriptionID string                `json:"subscription_id" db:"subscription_id"`
+	EventID        string                `json:"event_id" db:"event_id"`
+	EventType      WebhookEventType      `json:"event_type" db:"event_type"`
+	ApplicationID  string                `json:"application_id" db:"application_id"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	ResponseStatus *int                  `json:"response_status,omitempty" db:"response_status"`
+	LastError      *string               `json:"last_error,omitempty" db:"last_error"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookDeliveryQuery filters a subscription's delivery log
+type WebhookDeliveryQuery struct {
+	SubscriptionID string                `form:"-"`
+	Status         WebhookDeliveryStatus `form:"status"`
+	Limit          int                   `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// WebhookPayload is the body posted to a subscription's endpoint
+type WebhookPayload struct {
+	ID            string           `json:"id"` // the event ID; stable across retries so partners can de-duplicate
+	Type          WebhookEventType `json:"type"`
+	ApplicationID string           `json:"application_id"`
+	CreatedAt     time.Time        `json:"created_at"`
+	Data          json.RawMessage  `json:"data"`
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"time"
+)
+
+// WithdrawalReason is why a borrower withdrew an application
+type WithdrawalReason string
+
+const (
+	WithdrawalFoundBetterOffer   WithdrawalReason = "found_better_offer"
+	WithdrawalNoLongerNeeded     WithdrawalReason = "no_longer_needed"
+	WithdrawalTermsNotAcceptable WithdrawalReason = "terms_not_acceptable"
+	WithdrawalProcessTooSlow     WithdrawalReason = "process_too_slow"
+	WithdrawalOther              WithdrawalReason = "other"
+
+	// WithdrawalSLABreached is recorded when an SLA rule withdraws an application; borrowers cannot choose it
+	WithdrawalSLABreached WithdrawalReason = "sla_breached"
+)
+
+// WithdrawalActorSystem is the actor of automated withdrawals
+const WithdrawalActorSystem = "system"
+
+// ApplicationWithdrawal records a borrower's withdrawal of an application
+type ApplicationWithdrawal struct {
+	ID            string           `json:"id" db:"id"`
+	ApplicationID string           `json:"application_id" db:"application_id"`
+	FromState     ApplicationState `json:"from_state" db:"from_state"`
+	Reason        WithdrawalReason `json:"reason" db:"reason"`
+	Comment       string           `json:"comment,omitempty" db:"comment"`
+	WithdrawnBy   *string          `json:"withdrawn_by,omitempty" db:"withdrawn_by"`
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+
+	// WorkflowTerminated reports whether the application's running workflow was stopped; not persisted
+	WorkflowTerminated bool `json:"workflow_terminated" db:"-"`
+}
+
+// WithdrawApplicationRequest represents a request to withdraw an application
+// @Description Request to withdraw a loan application
+type WithdrawApplicationRequest struct {
+	Reason  WithdrawalReason `json:"reason" binding:"required,oneof=found_better_offer no_longer_needed terms_not_acceptable process_too_slow other" example:"found_better_offer"`
+	Comment string           `json:"comment,omitempty" binding:"max=500" example:"Another lender offered a lower rate"`
+
+	// Automated marks a withdrawal the system makes on its own and Source names what made it; set by
+	// internal callers only
+	Automated bool   `json:"-"`
+	Source    string `json:"-"`
+}
+
+// Validate checks a comment explains an "other" withdrawal reason and only automated withdrawals
+// use the system's reasons
+func (req *WithdrawApplicationRequest) Validate() *ValidationResult {
+	result := &ValidationResult{
+		Valid:  true,
+		Errors: make(map[string]string),
+	}
+
+	if req.Reason == WithdrawalOther && req.Comment == "" {
+		result.Valid = false
+		result.Errors["comment"] = LOAN_020
+	}
+	if req.Reason == WithdrawalSLABreached && !req.Automated {
+		result.Valid = false
+		result.Errors["reason"] = LOAN_020
+	}
+
+	return result
+}
+
+// CanWithdraw reports whether the application may still be withdrawn; once funded it cannot be
+func (app *LoanApplication) CanWithdraw() bool {
+	return app.CanTransitionTo(StateWithdrawn)
+}
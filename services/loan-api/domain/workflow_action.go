@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+)
+
+// WorkflowActionType is an operator action taken on a running workflow
+type WorkflowActionType string
+
+const (
+	WorkflowActionPause     WorkflowActionType = "pause"
+	WorkflowActionResume    WorkflowActionType = "resume"
+	WorkflowActionTerminate WorkflowActionType = "terminate"
+)
+
+// Workflow execution statuses recorded in workflow_executions, in Conductor's spelling
+const (
+	WorkflowExecutionRunning    = "RUNNING"
+	WorkflowExecutionPaused     = "PAUSED"
+	WorkflowExecutionTerminated = "TERMINATED"
+)
+
+// ExecutionStatus is the workflow execution status the action leaves behind
+func (a WorkflowActionType) ExecutionStatus() string {
+	switch a {
+	case WorkflowActionPause:
+		return WorkflowExecutionPaused
+	case WorkflowActionTerminate:
+		return WorkflowExecutionTerminated
+	default:
+		return WorkflowExecutionRunning
+	}
+}
+
+// WorkflowAction records who paused, resumed or terminated a workflow and why
+type WorkflowAction struct {
+	ID            string             `json:"id" db:"id"`
+	WorkflowID    string             `json:"workflow_id" db:"workflow_id"`
+	ApplicationID *string            `json:"application_id,omitempty" db:"application_id"`
+	Action        WorkflowActionType `json:"action" db:"action"`
+	Reason        string             `json:"reason,omitempty" db:"reason"`
+	PerformedBy   string             `json:"performed_by" db:"performed_by"`
+	CreatedAt     time.Time          `json:"created_at" db:"created_at"`
+
+	// Reconciliation of the application after a termination; not persisted
+	ApplicationState *ApplicationState `json:"application_state,omitempty" db:"-"`
+	StateReconciled  bool              `json:"state_reconciled,omitempty" db:"-"`
+}
+
+// StateAfterWorkflowTermination is the state an application moves to once its workflow is
+// terminated. Automated underwriting cannot finish without the workflow, so the decision passes
+// to a reviewer; applications in other states are left for staff or the borrower to move on.
+func (app *LoanApplication) StateAfterWorkflowTermination() (ApplicationState, bool) {
+	if app.CurrentState == StateUnderwriting {
+		return StateManualReview, true
+	}
+	return "", false
+}
+
+// WorkflowActionRequest is the body of a pause, resume or terminate request
+// @Description Reason for pausing, resuming or terminating a workflow
+type WorkflowActionRequest struct {
+	Reason string `json:"reason,omitempty" binding:"max=500" example:"Borrower asked to put the application on hold"`
+}
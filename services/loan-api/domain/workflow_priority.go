@@ -0,0 +1,21 @@
+package domain
+
+// Conductor workflow priorities, 0 to 99, higher first. Every task of a workflow is queued at its
+// workflow's priority, so an urgent application's tasks are polled ahead of bulk work.
+const (
+	WorkflowPriorityBulk   = 10 // re-scores and other batch work
+	WorkflowPriorityNormal = 50
+	WorkflowPriorityHigh   = 80 // high-value applications
+	MaxWorkflowPriority    = 99
+)
+
+// HighValueLoanAmount is the loan amount from which an application's workflows run at high priority
+const HighValueLoanAmount = 35000
+
+// WorkflowPriority is the Conductor priority the application's workflows are started with
+func (a *LoanApplication) WorkflowPriority() int {
+	if a.LoanAmount >= HighValueLoanAmount {
+		return WorkflowPriorityHigh
+	}
+	return WorkflowPriorityNormal
+}
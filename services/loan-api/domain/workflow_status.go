@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Workflow execution states reported to API clients; Conductor's upper-case statuses are
+// normalized to these so the response schema does not change with the engine
+const (
+	WorkflowStateRunning    = "running"
+	WorkflowStateCompleted  = "completed"
+	WorkflowStateFailed     = "failed"
+	WorkflowStateTimedOut   = "timed_out"
+	WorkflowStateTerminated = "terminated"
+	WorkflowStatePaused     = "paused"
+)
+
+// WorkflowTaskDetail is one task in a workflow's execution history
+type WorkflowTaskDetail struct {
+	TaskID        string     `json:"task_id"`
+	Name          string     `json:"name"`
+	Type          string     `json:"type"`
+	Status        string     `json:"status"`
+	Attempt       int        `json:"attempt"`
+	FailureReason string     `json:"failure_reason,omitempty"`
+	ScheduledAt   *time.Time `json:"scheduled_at,omitempty"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	EndedAt       *time.Time `json:"ended_at,omitempty"`
+}
+
+// WorkflowStatusDetail is the state of a workflow execution: where it is, why it stopped and
+// the tasks it has run so far
+type WorkflowStatusDetail struct {
+	WorkflowID    string               `json:"workflow_id"`
+	Name          string               `json:"name"`
+	Version       int                  `json:"version"`
+	ApplicationID string               `json:"application_id,omitempty"`
+	Status        string               `json:"status"`
+	CurrentTask   *WorkflowTaskDetail  `json:"current_task,omitempty"`
+	FailureReason string               `json:"failure_reason,omitempty"`
+	StartedAt     *time.Time           `json:"started_at,omitempty"`
+	EndedAt       *time.Time           `json:"ended_at,omitempty"`
+	Tasks         []WorkflowTaskDetail `json:"tasks"`
+}
+
+// NormalizeWorkflowStatus maps an engine status such as IN_PROGRESS or TIMED_OUT to its
+// lower-case API form
+func NormalizeWorkflowStatus(status string) string {
+	return strings.ToLower(status)
+}
+
+// IsActiveTaskStatus reports whether a normalized task status means the task has not finished
+func IsActiveTaskStatus(status string) bool {
+	return status == "scheduled" || status == "in_progress"
+}
@@ -0,0 +1,81 @@
+package addressverification
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/shared/pkg/address"
+)
+
+// Verifier standardizes applicant addresses through the configured address provider
+type Verifier struct {
+	verifier address.Verifier
+	logger   *zap.Logger
+}
+
+// NewVerifier creates a new applicant address verifier
+func NewVerifier(verifier address.Verifier, logger *zap.Logger) *Verifier {
+	return &Verifier{
+		verifier: verifier,
+		logger:   logger,
+	}
+}
+
+// StandardizeAddress returns the CASS-standardized address, flagged if undeliverable, with any suggestions
+func (v *Verifier) StandardizeAddress(ctx context.Context, addr *domain.Address) (*domain.Address, *domain.AddressVerification, error) {
+	result, err := v.verifier.Verify(ctx, address.Address{
+		Street:  addr.StreetAddress,
+		City:    addr.City,
+		State:   addr.State,
+		ZipCode: addr.ZipCode,
+		Country: addr.Country,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	verification := &domain.AddressVerification{
+		Provider:     result.Provider,
+		Deliverable:  result.Deliverable,
+		DPVMatchCode: result.DPVMatchCode,
+	}
+	for _, suggestion := range result.Suggestions {
+		verification.Suggestions = append(verification.Suggestions, mergeAddress(*addr, suggestion))
+	}
+
+	// Without a provider match the submitted address is kept as entered and flagged
+	standardized := *addr
+	if result.Standardized != nil {
+		standardized = mergeAddress(*addr, *result.Standardized)
+		standardized.DeliveryPointBarcode = result.DeliveryPointBarcode
+	}
+	standardized.Undeliverable = !result.Deliverable
+
+	if standardized.Undeliverable {
+		v.logger.Info("Applicant address flagged as undeliverable",
+			zap.String("provider", result.Provider),
+			zap.String("dpv_match_code", result.DPVMatchCode),
+			zap.Int("suggestions", len(verification.Suggestions)),
+		)
+	}
+
+	return &standardized, verification, nil
+}
+
+// mergeAddress applies the postal fields from the provider while keeping applicant-supplied residence details
+func mergeAddress(original domain.Address, addr address.Address) domain.Address {
+	street := addr.Street
+	if addr.Secondary != "" {
+		street += " " + addr.Secondary
+	}
+	original.StreetAddress = street
+	original.City = addr.City
+	original.State = addr.State
+	original.ZipCode = addr.ZipCode
+	original.ZipPlus4 = addr.Plus4
+	original.DeliveryPointBarcode = ""
+	original.Undeliverable = false
+	return original
+}
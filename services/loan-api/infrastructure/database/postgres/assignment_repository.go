@@ -0,0 +1,350 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+const assignmentColumns = `id, application_id, officer_id, method, assigned_by, reason, assigned_at, released_at`
+
+// assignmentCandidateColumns are the columns scanned by scanAssignmentCandidate, of loan_applications
+// a joined to the borrower's users row u
+const assignmentCandidateColumns = `a.id, a.loan_amount, a.loan_purpose, a.co_borrower IS NOT NULL,
+	COALESCE(u.state, ''), a.priority, a.state_entered_at`
+
+// ListUnassignedReviews lists applications in manual review without an active assignment, highest
+// priority and longest waiting first
+func (r *LoanRepository) ListUnassignedReviews(ctx context.Context, limit int) ([]*domain.AssignmentCandidate, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+assignmentCandidateColumns+`
+		FROM loan_applications a
+		LEFT JOIN users u ON u.id = a.user_id
+		WHERE a.current_state = $1
+			AND NOT EXISTS (
+				SELECT 1 FROM application_assignments s
+				WHERE s.application_id = a.id AND s.released_at IS NULL
+			)
+		ORDER BY a.priority DESC, a.state_entered_at, a.id
+		LIMIT $2`,
+		domain.StateManualReview, limit)
+	if err != nil {
+		r.logger.Error("Failed to list unassigned reviews", zap.Error(err))
+		return nil, fmt.Errorf("failed to list unassigned reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []*domain.AssignmentCandidate
+	for rows.Next() {
+		candidate, err := scanAssignmentCandidate(rows)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, candidate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+	return candidates, nil
+}
+
+// GetAssignmentCandidate returns what assigning an application to a loan officer depends on,
+// whatever its state
+func (r *LoanRepository) GetAssignmentCandidate(ctx context.Context, applicationID string) (*domain.AssignmentCandidate, error) {
+	candidate, err := scanAssignmentCandidate(r.db.QueryRow(ctx, `
+		SELECT `+assignmentCandidateColumns+`
+		FROM loan_applications a
+		LEFT JOIN users u ON u.id = a.user_id
+		WHERE a.id = $1`, applicationID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("application not found: %s", applicationID)
+		}
+		r.logger.Error("Failed to get assignment candidate", zap.String("application_id", applicationID), zap.Error(err))
+		return nil, err
+	}
+	return candidate, nil
+}
+
+func scanAssignmentCandidate(row interface{ Scan(...interface{}) error }) (*domain.AssignmentCandidate, error) {
+	var candidate domain.AssignmentCandidate
+	if err := row.Scan(
+		&candidate.ApplicationID, &candidate.LoanAmount, &candidate.LoanPurpose,
+		&candidate.HasCoBorrower, &candidate.BorrowerState, &candidate.Priority, &candidate.StateEnteredAt,
+	); err != nil {
+		return nil, fmt.Errorf("failed to scan assignment candidate: %w", err)
+	}
+	return &candidate, nil
+}
+
+// CountActiveAssignments returns the number of active assignments held by each officer
+func (r *LoanRepository) CountActiveAssignments(ctx context.Context) (map[string]int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT officer_id, COUNT(*) FROM application_assignments
+		WHERE released_at IS NULL
+		GROUP BY officer_id`)
+	if err != nil {
+		r.logger.Error("Failed to count active assignments", zap.Error(err))
+		return nil, fmt.Errorf("failed to count active assignments: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var officerID string
+		var count int
+		if err := rows.Scan(&officerID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan assignment count: %w", err)
+		}
+		counts[officerID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+	return counts, nil
+}
+
+// LatestAssignmentTimes returns when each officer was last assigned an application, by any method
+func (r *LoanRepository) LatestAssignmentTimes(ctx context.Context) (map[string]time.Time, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT officer_id, MAX(assigned_at) FROM application_assignments
+		GROUP BY officer_id`)
+	if err != nil {
+		r.logger.Error("Failed to get latest assignment times", zap.Error(err))
+		return nil, fmt.Errorf("failed to get latest assignment times: %w", err)
+	}
+	defer rows.Close()
+
+	latest := make(map[string]time.Time)
+	for rows.Next() {
+		var officerID string
+		var assignedAt time.Time
+		if err := rows.Scan(&officerID, &assignedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan assignment time: %w", err)
+		}
+		latest[officerID] = assignedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+	return latest, nil
+}
+
+// CreateAssignment assigns an unassigned application, reporting false when it already has an
+// active assignment. A positive capacity caps the officer's active assignments: the officer's
+// assignments are serialized on an advisory lock and the insert only goes ahead while the officer
+// holds fewer than capacity, so concurrent claims and auto-assignment cannot overfill them.
+func (r *LoanRepository) CreateAssignment(ctx context.Context, assignment *domain.ApplicationAssignment, capacity int) (bool, error) {
+	logger := r.logger.With(
+		zap.String("operation", "create_assignment"),
+		zap.String("application_id", assignment.ApplicationID),
+		zap.String("officer_id", assignment.OfficerID),
+	)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if capacity > 0 {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('application_assignments:' || $1))`,
+			assignment.OfficerID); err != nil {
+			logger.Error("Failed to lock officer assignments", zap.Error(err))
+			return false, fmt.Errorf("failed to lock officer assignments: %w", err)
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO application_assignments (`+assignmentColumns+`)
+		SELECT $1, $2, $3, $4, $5, $6, $7, NULL
+		WHERE $8 <= 0 OR (
+			SELECT COUNT(*) FROM application_assignments
+			WHERE officer_id = $3 AND released_at IS NULL
+		) < $8
+		ON CONFLICT (application_id) WHERE released_at IS NULL DO NOTHING`,
+		assignment.ID, assignment.ApplicationID, assignment.OfficerID, assignment.Method,
+		assignment.AssignedBy, assignment.Reason, assignment.AssignedAt, capacity,
+	)
+	if err != nil {
+		logger.Error("Failed to create assignment", zap.Error(err))
+		return false, fmt.Errorf("failed to create assignment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		// Either the application was assigned first or the officer is full; the lock keeps the
+		// officer's count as the insert saw it
+		var assigned bool
+		if err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM application_assignments
+				WHERE application_id = $1 AND released_at IS NULL
+			)`, assignment.ApplicationID).Scan(&assigned); err != nil {
+			logger.Error("Failed to check active assignment", zap.Error(err))
+			return false, fmt.Errorf("failed to check active assignment: %w", err)
+		}
+		if !assigned {
+			return false, fmt.Errorf("officer at capacity: %s", assignment.OfficerID)
+		}
+		return false, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit assignment", zap.Error(err))
+		return false, fmt.Errorf("failed to commit assignment: %w", err)
+	}
+	return true, nil
+}
+
+// ReassignApplication releases the application's active assignment and records the new one
+func (r *LoanRepository) ReassignApplication(ctx context.Context, assignment *domain.ApplicationAssignment) error {
+	logger := r.logger.With(
+		zap.String("operation", "reassign_application"),
+		zap.String("application_id", assignment.ApplicationID),
+		zap.String("officer_id", assignment.OfficerID),
+	)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE application_assignments SET released_at = $1
+		WHERE application_id = $2 AND released_at IS NULL`,
+		assignment.AssignedAt, assignment.ApplicationID,
+	); err != nil {
+		logger.Error("Failed to release assignment", zap.Error(err))
+		return fmt.Errorf("failed to release assignment: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO application_assignments (`+assignmentColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULL)`,
+		assignment.ID, assignment.ApplicationID, assignment.OfficerID, assignment.Method,
+		assignment.AssignedBy, assignment.Reason, assignment.AssignedAt,
+	); err != nil {
+		logger.Error("Failed to create assignment", zap.Error(err))
+		return fmt.Errorf("failed to create assignment: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit reassignment", zap.Error(err))
+		return fmt.Errorf("failed to commit reassignment: %w", err)
+	}
+	return nil
+}
+
+// ReleaseFinishedAssignments releases active assignments of applications that have left manual
+// review and returns how many were released
+func (r *LoanRepository) ReleaseFinishedAssignments(ctx context.Context, now time.Time) (int, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE application_assignments s SET released_at = $1
+		FROM loan_applications a
+		WHERE a.id = s.application_id AND s.released_at IS NULL AND a.current_state <> $2`,
+		now, domain.StateManualReview)
+	if err != nil {
+		r.logger.Error("Failed to release finished assignments", zap.Error(err))
+		return 0, fmt.Errorf("failed to release finished assignments: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rowsAffected), nil
+}
+
+// GetActiveAssignment returns the application's active assignment
+func (r *LoanRepository) GetActiveAssignment(ctx context.Context, applicationID string) (*domain.ApplicationAssignment, error) {
+	var assignment domain.ApplicationAssignment
+	err := r.db.QueryRow(ctx, `
+		SELECT `+assignmentColumns+` FROM application_assignments
+		WHERE application_id = $1 AND released_at IS NULL`, applicationID).Scan(
+		&assignment.ID, &assignment.ApplicationID, &assignment.OfficerID, &assignment.Method,
+		&assignment.AssignedBy, &assignment.Reason, &assignment.AssignedAt, &assignment.ReleasedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("assignment not found: %s", applicationID)
+		}
+		r.logger.Error("Failed to get active assignment", zap.String("application_id", applicationID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get active assignment: %w", err)
+	}
+	return &assignment, nil
+}
+
+// ListAssignments returns an application's assignment history, oldest first
+func (r *LoanRepository) ListAssignments(ctx context.Context, applicationID string) ([]*domain.ApplicationAssignment, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+assignmentColumns+` FROM application_assignments
+		WHERE application_id = $1
+		ORDER BY assigned_at, id`, applicationID)
+	if err != nil {
+		r.logger.Error("Failed to list assignments", zap.String("application_id", applicationID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := make([]*domain.ApplicationAssignment, 0)
+	for rows.Next() {
+		var assignment domain.ApplicationAssignment
+		if err := rows.Scan(
+			&assignment.ID, &assignment.ApplicationID, &assignment.OfficerID, &assignment.Method,
+			&assignment.AssignedBy, &assignment.Reason, &assignment.AssignedAt, &assignment.ReleasedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan assignment: %w", err)
+		}
+		assignments = append(assignments, &assignment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+	return assignments, nil
+}
+
+// ListOfficerQueue returns the applications actively assigned to an officer, highest priority and
+// longest waiting first
+func (r *LoanRepository) ListOfficerQueue(ctx context.Context, officerID string) ([]*domain.QueueItem, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT a.id, a.application_number, a.current_state, a.loan_amount, a.loan_purpose,
+			a.priority, a.state_entered_at, s.assigned_at, s.method
+		FROM application_assignments s
+		JOIN loan_applications a ON a.id = s.application_id
+		WHERE s.officer_id = $1 AND s.released_at IS NULL
+		ORDER BY a.priority DESC, a.state_entered_at, a.id`, officerID)
+	if err != nil {
+		r.logger.Error("Failed to list officer queue", zap.String("officer_id", officerID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list officer queue: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]*domain.QueueItem, 0)
+	for rows.Next() {
+		var item domain.QueueItem
+		if err := rows.Scan(
+			&item.ApplicationID, &item.ApplicationNumber, &item.State, &item.LoanAmount, &item.LoanPurpose,
+			&item.Priority, &item.StateEnteredAt, &item.AssignedAt, &item.Method,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan queue item: %w", err)
+		}
+		items = append(items, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+	return items, nil
+}
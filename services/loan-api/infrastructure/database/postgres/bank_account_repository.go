@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// bankAccountColumns are the columns scanned by scanBankAccount
+const bankAccountColumns = `id, user_id, institution_name, account_type, account_number_token, routing_number_token,
+	account_last4, verification_method, status, plaid_account_id, provider_reference, micro_deposit_attempts,
+	micro_deposits_sent_at, verified_at, created_at, updated_at`
+
+// CreateBankAccount records a bank account linked by a borrower
+func (r *LoanRepository) CreateBankAccount(ctx context.Context, account *domain.BankAccount) error {
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO bank_accounts (
+			id, user_id, institution_name, account_type, account_number_token, routing_number_token, account_last4,
+			verification_method, status, plaid_account_id, provider_reference, micro_deposit_attempts,
+			micro_deposits_sent_at, verified_at, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+		)`,
+		account.ID, account.UserID, account.InstitutionName, account.AccountType, account.AccountNumberToken,
+		account.RoutingNumberToken, account.AccountLast4, account.VerificationMethod, account.Status,
+		account.PlaidAccountID, account.ProviderReference, account.MicroDepositAttempts, account.MicroDepositsSentAt,
+		account.VerifiedAt, account.CreatedAt, account.UpdatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create bank account",
+			zap.String("user_id", account.UserID),
+			zap.Error(err))
+		return fmt.Errorf("failed to create bank account: %w", err)
+	}
+	return nil
+}
+
+// GetBankAccount retrieves a bank account by ID
+func (r *LoanRepository) GetBankAccount(ctx context.Context, id string) (*domain.BankAccount, error) {
+	return r.getBankAccount(ctx, `WHERE id = $1`, id)
+}
+
+// GetActiveBankAccountByNumber retrieves the borrower's verified or pending account with the given
+// number tokens
+func (r *LoanRepository) GetActiveBankAccountByNumber(ctx context.Context, userID, accountNumberToken, routingNumberToken string) (*domain.BankAccount, error) {
+	return r.getBankAccount(ctx, `
+		WHERE user_id = $1 AND account_number_token = $2 AND routing_number_token = $3 AND status IN ($4, $5)`,
+		userID, accountNumberToken, routingNumberToken, domain.BankAccountPendingVerification, domain.BankAccountVerified)
+}
+
+// GetVerifiedBankAccount retrieves the bank account the borrower most recently verified
+func (r *LoanRepository) GetVerifiedBankAccount(ctx context.Context, userID string) (*domain.BankAccount, error) {
+	return r.getBankAccount(ctx, `WHERE user_id = $1 AND status = $2 ORDER BY verified_at DESC LIMIT 1`,
+		userID, domain.BankAccountVerified)
+}
+
+// getBankAccount retrieves the first bank account matching a WHERE clause
+func (r *LoanRepository) getBankAccount(ctx context.Context, where string, args ...interface{}) (*domain.BankAccount, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT `+bankAccountColumns+`
+		FROM bank_accounts `+where,
+		args...)
+
+	account, err := scanBankAccount(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("bank account not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank account: %w", err)
+	}
+	return account, nil
+}
+
+// ListBankAccounts lists a borrower's bank accounts that have not been removed, newest first
+func (r *LoanRepository) ListBankAccounts(ctx context.Context, userID string) ([]*domain.BankAccount, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+bankAccountColumns+`
+		FROM bank_accounts WHERE user_id = $1 AND status <> $2
+		ORDER BY created_at DESC, id`,
+		userID, domain.BankAccountRemoved)
+	if err != nil {
+		r.logger.Error("Failed to list bank accounts",
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to list bank accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []*domain.BankAccount
+	for rows.Next() {
+		account, err := scanBankAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bank account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// UpdateBankAccount saves a bank account's verification state
+func (r *LoanRepository) UpdateBankAccount(ctx context.Context, account *domain.BankAccount) error {
+	if _, err := r.db.Exec(ctx, `
+		UPDATE bank_accounts SET
+			institution_name = $1, verification_method = $2, status = $3, plaid_account_id = $4,
+			provider_reference = $5, micro_deposit_attempts = $6, micro_deposits_sent_at = $7, verified_at = $8,
+			updated_at = $9
+		WHERE id = $10`,
+		account.InstitutionName, account.VerificationMethod, account.Status, account.PlaidAccountID,
+		account.ProviderReference, account.MicroDepositAttempts, account.MicroDepositsSentAt, account.VerifiedAt,
+		account.UpdatedAt, account.ID,
+	); err != nil {
+		r.logger.Error("Failed to update bank account",
+			zap.String("bank_account_id", account.ID),
+			zap.Error(err))
+		return fmt.Errorf("failed to update bank account: %w", err)
+	}
+	return nil
+}
+
+// scanBankAccount scans a row of bankAccountColumns
+func scanBankAccount(row interface{ Scan(...interface{}) error }) (*domain.BankAccount, error) {
+	var account domain.BankAccount
+	var plaidAccountID, providerReference sql.NullString
+	var microDepositsSentAt, verifiedAt sql.NullTime
+	if err := row.Scan(
+		&account.ID, &account.UserID, &account.InstitutionName, &account.AccountType, &account.AccountNumberToken,
+		&account.RoutingNumberToken, &account.AccountLast4, &account.VerificationMethod, &account.Status,
+		&plaidAccountID, &providerReference, &account.MicroDepositAttempts, &microDepositsSentAt, &verifiedAt,
+		&account.CreatedAt, &account.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if plaidAccountID.Valid {
+		account.PlaidAccountID = &plaidAccountID.String
+	}
+	if providerReference.Valid {
+		account.ProviderReference = &providerReference.String
+	}
+	if microDepositsSentAt.Valid {
+		account.MicroDepositsSentAt = &microDepositsSentAt.Time
+	}
+	if verifiedAt.Valid {
+		account.VerifiedAt = &verifiedAt.Time
+	}
+	return &account, nil
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// CreateCollateral records the collateral securing an application
+func (r *LoanRepository) CreateCollateral(ctx context.Context, collateral *domain.Collateral) error {
+	vehicle, err := marshalCollateralDetails(collateral.Vehicle != nil, collateral.Vehicle)
+	if err != nil {
+		return fmt.Errorf("failed to encode vehicle: %w", err)
+	}
+	asset, err := marshalCollateralDetails(collateral.Asset != nil, collateral.Asset)
+	if err != nil {
+		return fmt.Errorf("failed to encode asset: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO application_collateral (
+			id, application_id, collateral_type, vehicle, asset, stated_value, value, valuation_source,
+			valuation_reference, valued_at, ltv_ratio, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+		)`,
+		collateral.ID, collateral.ApplicationID, collateral.Type, vehicle, asset, collateral.StatedValue,
+		collateral.Value, collateral.ValuationSource, collateral.ValuationReference, collateral.ValuedAt,
+		collateral.LTVRatio, collateral.CreatedAt, collateral.UpdatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create collateral",
+			zap.String("application_id", collateral.ApplicationID),
+			zap.Error(err))
+		return fmt.Errorf("failed to create collateral: %w", err)
+	}
+	return nil
+}
+
+// GetCollateral retrieves the collateral securing an application
+func (r *LoanRepository) GetCollateral(ctx context.Context, applicationID string) (*domain.Collateral, error) {
+	var collateral domain.Collateral
+	var vehicle, asset []byte
+	var valuationReference sql.NullString
+	err := r.db.QueryRow(ctx, `
+		SELECT id, application_id, collateral_type, vehicle, asset, stated_value, value, valuation_source,
+			valuation_reference, valued_at, ltv_ratio, created_at, updated_at
+		FROM application_collateral WHERE application_id = $1`,
+		applicationID,
+	).Scan(
+		&collateral.ID, &collateral.ApplicationID, &collateral.Type, &vehicle, &asset, &collateral.StatedValue,
+		&collateral.Value, &collateral.ValuationSource, &valuationReference, &collateral.ValuedAt,
+		&collateral.LTVRatio, &collateral.CreatedAt, &collateral.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("collateral not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collateral: %w", err)
+	}
+
+	if valuationReference.Valid {
+		collateral.ValuationReference = &valuationReference.String
+	}
+	if len(vehicle) > 0 {
+		collateral.Vehicle = &domain.VehicleDetails{}
+		if err := json.Unmarshal(vehicle, collateral.Vehicle); err != nil {
+			return nil, fmt.Errorf("failed to decode vehicle: %w", err)
+		}
+	}
+	if len(asset) > 0 {
+		collateral.Asset = &domain.AssetDetails{}
+		if err := json.Unmarshal(asset, collateral.Asset); err != nil {
+			return nil, fmt.Errorf("failed to decode asset: %w", err)
+		}
+	}
+	return &collateral, nil
+}
+
+// UpdateCollateralValuation stores a new valuation of an application's collateral and the
+// loan-to-value ratio at it
+func (r *LoanRepository) UpdateCollateralValuation(ctx context.Context, collateral *domain.Collateral) error {
+	result, err := r.db.Exec(ctx, `
+		UPDATE application_collateral SET
+			value = $1, valuation_source = $2, valuation_reference = $3, valued_at = $4, ltv_ratio = $5,
+			updated_at = $6
+		WHERE id = $7`,
+		collateral.Value, collateral.ValuationSource, collateral.ValuationReference, collateral.ValuedAt,
+		collateral.LTVRatio, time.Now().UTC(), collateral.ID,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update collateral valuation",
+			zap.String("collateral_id", collateral.ID),
+			zap.Error(err))
+		return fmt.Errorf("failed to update collateral valuation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("collateral not found")
+	}
+	return nil
+}
+
+// marshalCollateralDetails encodes vehicle or asset details for a JSONB column, or NULL when absent
+func marshalCollateralDetails(present bool, details interface{}) (interface{}, error) {
+	if !present {
+		return nil, nil
+	}
+	data, err := json.Marshal(details)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
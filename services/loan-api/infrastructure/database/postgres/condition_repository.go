@@ -0,0 +1,229 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// underwritingConditionColumns are the underwriting_conditions columns read by
+// scanUnderwritingCondition
+const underwritingConditionColumns = `id, application_id, condition_type, description, document_types, priority,
+	critical, status, evidence_document_ids, workflow_instance_id, task_reference_name, due_at, resolved_at,
+	resolved_by, resolution_note, created_by, created_at, updated_at`
+
+// ListUnderwritingConditions lists the conditions placed on an application, oldest first
+func (r *LoanRepository) ListUnderwritingConditions(ctx context.Context, applicationID string) ([]*domain.UnderwritingCondition, error) {
+	return r.queryUnderwritingConditions(ctx, `
+		SELECT `+underwritingConditionColumns+`
+		FROM underwriting_conditions WHERE application_id = $1
+		ORDER BY created_at, id`,
+		applicationID)
+}
+
+// ListWorkflowConditions lists the conditions a workflow waits on
+func (r *LoanRepository) ListWorkflowConditions(ctx context.Context, workflowInstanceID string) ([]*domain.UnderwritingCondition, error) {
+	return r.queryUnderwritingConditions(ctx, `
+		SELECT `+underwritingConditionColumns+`
+		FROM underwriting_conditions WHERE workflow_instance_id = $1
+		ORDER BY created_at, id`,
+		workflowInstanceID)
+}
+
+// GetUnderwritingCondition retrieves a condition placed on an application
+func (r *LoanRepository) GetUnderwritingCondition(ctx context.Context, applicationID, id string) (*domain.UnderwritingCondition, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT `+underwritingConditionColumns+`
+		FROM underwriting_conditions WHERE application_id = $1 AND id::text = $2`,
+		applicationID, id)
+
+	condition, err := scanUnderwritingCondition(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("underwriting condition not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get underwriting condition: %w", err)
+	}
+	return condition, nil
+}
+
+// RecordConditionEvidence adds an uploaded document to a condition's evidence, marking it
+// received. It reports false when the condition was waived or expired meanwhile.
+func (r *LoanRepository) RecordConditionEvidence(ctx context.Context, id, documentID string, receivedAt time.Time) (bool, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE underwriting_conditions SET
+			status = $1, evidence_document_ids = evidence_document_ids || to_jsonb($2::text),
+			resolved_at = COALESCE(resolved_at, $3), updated_at = $3
+		WHERE id::text = $4 AND status IN ($5, $1)`,
+		string(domain.ConditionReceived), documentID, receivedAt, id, string(domain.ConditionPending),
+	)
+	if err != nil {
+		r.logger.Error("Failed to record condition evidence",
+			zap.String("condition_id", id),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to record condition evidence: %w", err)
+	}
+	return conditionUpdated(result)
+}
+
+// WaiveUnderwritingCondition waives a pending or received condition. It reports false when the
+// condition was waived or expired meanwhile.
+func (r *LoanRepository) WaiveUnderwritingCondition(ctx context.Context, id, waivedBy, reason string, waivedAt time.Time) (bool, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE underwriting_conditions SET
+			status = $1, resolved_at = $2, resolved_by = $3, resolution_note = $4, updated_at = $2
+		WHERE id::text = $5 AND status IN ($6, $7)`,
+		string(domain.ConditionWaived), waivedAt, waivedBy, reason, id,
+		string(domain.ConditionPending), string(domain.ConditionReceived),
+	)
+	if err != nil {
+		r.logger.Error("Failed to waive underwriting condition",
+			zap.String("condition_id", id),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to waive underwriting condition: %w", err)
+	}
+	return conditionUpdated(result)
+}
+
+// ExpireUnderwritingConditions marks pending conditions whose due date has passed as of the given
+// time expired, returning the number expired
+func (r *LoanRepository) ExpireUnderwritingConditions(ctx context.Context, asOf time.Time) (int, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE underwriting_conditions SET status = $1, resolved_at = $2, updated_at = $2
+		WHERE status = $3 AND due_at IS NOT NULL AND due_at <= $2`,
+		string(domain.ConditionExpired), asOf, string(domain.ConditionPending),
+	)
+	if err != nil {
+		r.logger.Error("Failed to expire underwriting conditions", zap.Error(err))
+		return 0, fmt.Errorf("failed to expire underwriting conditions: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rowsAffected), nil
+}
+
+// ListConditionWorkflowsToResume lists up to limit workflows still waiting on conditions none of
+// whose critical conditions is pending
+func (r *LoanRepository) ListConditionWorkflowsToResume(ctx context.Context, limit int) ([]string, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT workflow_instance_id
+		FROM underwriting_conditions
+		WHERE workflow_instance_id IS NOT NULL AND workflow_resumed_at IS NULL
+		GROUP BY workflow_instance_id
+		HAVING COUNT(*) FILTER (WHERE critical AND status = $1) = 0
+		ORDER BY MAX(updated_at)
+		LIMIT $2`,
+		string(domain.ConditionPending), limit)
+	if err != nil {
+		r.logger.Error("Failed to list workflows waiting on conditions", zap.Error(err))
+		return nil, fmt.Errorf("failed to list workflows waiting on conditions: %w", err)
+	}
+	defer rows.Close()
+
+	workflowIDs := make([]string, 0)
+	for rows.Next() {
+		var workflowID string
+		if err := rows.Scan(&workflowID); err != nil {
+			return nil, fmt.Errorf("failed to scan workflow ID: %w", err)
+		}
+		workflowIDs = append(workflowIDs, workflowID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+	return workflowIDs, nil
+}
+
+// MarkConditionWorkflowResumed records that a workflow no longer waits on its conditions
+func (r *LoanRepository) MarkConditionWorkflowResumed(ctx context.Context, workflowInstanceID string, resumedAt time.Time) error {
+	if _, err := r.db.Exec(ctx, `
+		UPDATE underwriting_conditions SET workflow_resumed_at = $1
+		WHERE workflow_instance_id = $2 AND workflow_resumed_at IS NULL`,
+		resumedAt, workflowInstanceID,
+	); err != nil {
+		r.logger.Error("Failed to mark condition workflow resumed",
+			zap.String("workflow_instance_id", workflowInstanceID),
+			zap.Error(err))
+		return fmt.Errorf("failed to mark condition workflow resumed: %w", err)
+	}
+	return nil
+}
+
+func (r *LoanRepository) queryUnderwritingConditions(ctx context.Context, query string, args ...interface{}) ([]*domain.UnderwritingCondition, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list underwriting conditions", zap.Error(err))
+		return nil, fmt.Errorf("failed to list underwriting conditions: %w", err)
+	}
+	defer rows.Close()
+
+	conditions := make([]*domain.UnderwritingCondition, 0)
+	for rows.Next() {
+		condition, err := scanUnderwritingCondition(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan underwriting condition: %w", err)
+		}
+		conditions = append(conditions, condition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+	return conditions, nil
+}
+
+func conditionUpdated(result sql.Result) (bool, error) {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+func scanUnderwritingCondition(row interface{ Scan(...interface{}) error }) (*domain.UnderwritingCondition, error) {
+	var condition domain.UnderwritingCondition
+	var workflowInstanceID, taskReferenceName, resolvedBy, createdBy sql.NullString
+	var dueAt, resolvedAt sql.NullTime
+	var documentTypes, evidence []byte
+	if err := row.Scan(
+		&condition.ID, &condition.ApplicationID, &condition.ConditionType, &condition.Description,
+		&documentTypes, &condition.Priority, &condition.Critical, &condition.Status, &evidence,
+		&workflowInstanceID, &taskReferenceName, &dueAt, &resolvedAt, &resolvedBy,
+		&condition.ResolutionNote, &createdBy, &condition.CreatedAt, &condition.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if workflowInstanceID.Valid {
+		condition.WorkflowInstanceID = &workflowInstanceID.String
+	}
+	if taskReferenceName.Valid {
+		condition.TaskReferenceName = &taskReferenceName.String
+	}
+	if dueAt.Valid {
+		condition.DueAt = &dueAt.Time
+	}
+	if resolvedAt.Valid {
+		condition.ResolvedAt = &resolvedAt.Time
+	}
+	if resolvedBy.Valid {
+		condition.ResolvedBy = &resolvedBy.String
+	}
+	condition.CreatedBy = createdBy.String
+	if len(documentTypes) > 0 {
+		if err := json.Unmarshal(documentTypes, &condition.DocumentTypes); err != nil {
+			return nil, fmt.Errorf("failed to decode condition document types: %w", err)
+		}
+	}
+	if err := json.Unmarshal(evidence, &condition.EvidenceDocumentIDs); err != nil {
+		return nil, fmt.Errorf("failed to decode condition evidence: %w", err)
+	}
+	return &condition, nil
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// CreateCreditConsent records a borrower's consent to the credit pull for an application
+func (r *LoanRepository) CreateCreditConsent(ctx context.Context, consent *domain.CreditConsent) error {
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO credit_consents (
+			id, application_id, user_id, disclosure_version, ip_address, user_agent, consented_at, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)`,
+		consent.ID, consent.ApplicationID, consent.UserID, consent.DisclosureVersion, consent.IPAddress,
+		consent.UserAgent, consent.ConsentedAt, consent.CreatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create credit consent",
+			zap.String("application_id", consent.ApplicationID),
+			zap.Error(err))
+		return fmt.Errorf("failed to create credit consent: %w", err)
+	}
+	return nil
+}
+
+// GetCreditConsent retrieves the borrower's consent to the credit pull for an application
+func (r *LoanRepository) GetCreditConsent(ctx context.Context, applicationID string) (*domain.CreditConsent, error) {
+	var consent domain.CreditConsent
+	var userAgent sql.NullString
+	err := r.db.QueryRow(ctx, `
+		SELECT id, application_id, user_id, disclosure_version, ip_address, user_agent, consented_at, created_at
+		FROM credit_consents WHERE application_id = $1`,
+		applicationID,
+	).Scan(
+		&consent.ID, &consent.ApplicationID, &consent.UserID, &consent.DisclosureVersion, &consent.IPAddress,
+		&userAgent, &consent.ConsentedAt, &consent.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("credit consent not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit consent: %w", err)
+	}
+
+	consent.UserAgent = userAgent.String
+	return &consent, nil
+}
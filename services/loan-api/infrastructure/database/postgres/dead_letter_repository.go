@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// deadLetterColumns are the dead_letter_tasks columns read by scanDeadLetter
+const deadLetterColumns = `id, task_id, task_type, workflow_instance_id, application_id, input, output, error,
+	terminal, attempts, conductor_retries, status, resolved_by, resolution_note, failed_at, updated_at, resolved_at`
+
+// ListDeadLetters lists dead-lettered workflow tasks, most recently failed first
+func (r *LoanRepository) ListDeadLetters(ctx context.Context, query *domain.DeadLetterQuery) ([]*domain.DeadLetterTask, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+deadLetterColumns+`
+		FROM dead_letter_tasks
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR task_type = $2) AND ($3 = '' OR application_id = $3)
+		ORDER BY failed_at DESC, id
+		LIMIT $4`,
+		string(query.Status), query.TaskType, query.ApplicationID, query.Limit)
+	if err != nil {
+		r.logger.Error("Failed to list dead-lettered tasks", zap.Error(err))
+		return nil, fmt.Errorf("failed to list dead-lettered tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := make([]*domain.DeadLetterTask, 0)
+	for rows.Next() {
+		task, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dead-lettered task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+	return tasks, nil
+}
+
+// GetDeadLetter retrieves a dead-lettered workflow task
+func (r *LoanRepository) GetDeadLetter(ctx context.Context, id string) (*domain.DeadLetterTask, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT `+deadLetterColumns+`
+		FROM dead_letter_tasks WHERE id = $1`,
+		id)
+
+	task, err := scanDeadLetter(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("dead-lettered task not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dead-lettered task: %w", err)
+	}
+	return task, nil
+}
+
+// UpdateDeadLetterInput stores the corrected input of an open dead-lettered task. It reports
+// false when the task has been resolved meanwhile.
+func (r *LoanRepository) UpdateDeadLetterInput(ctx context.Context, task *domain.DeadLetterTask) (bool, error) {
+	input, err := json.Marshal(task.Input)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode task input: %w", err)
+	}
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE dead_letter_tasks SET input = $1, resolution_note = $2, updated_at = $3
+		WHERE id = $4 AND status = $5`,
+		input, task.ResolutionNote, task.UpdatedAt, task.ID, string(domain.DeadLetterOpen),
+	)
+	if err != nil {
+		r.logger.Error("Failed to update dead-lettered task input",
+			zap.String("dead_letter_id", task.ID),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to update dead-lettered task input: %w", err)
+	}
+	return deadLetterUpdated(result)
+}
+
+// ResolveDeadLetter closes an open dead-lettered task as re-submitted or discarded. It reports
+// false when another operator resolved it first.
+func (r *LoanRepository) ResolveDeadLetter(ctx context.Context, task *domain.DeadLetterTask) (bool, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE dead_letter_tasks SET
+			status = $1, resolved_by = $2, resolution_note = $3, resolved_at = $4, updated_at = $4
+		WHERE id = $5 AND status = $6`,
+		string(task.Status), task.ResolvedBy, task.ResolutionNote, task.ResolvedAt, task.ID,
+		string(domain.DeadLetterOpen),
+	)
+	if err != nil {
+		r.logger.Error("Failed to resolve dead-lettered task",
+			zap.String("dead_letter_id", task.ID),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to resolve dead-lettered task: %w", err)
+	}
+	return deadLetterUpdated(result)
+}
+
+// CountOpenDeadLetters counts the dead-lettered tasks waiting for an operator
+func (r *LoanRepository) CountOpenDeadLetters(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM dead_letter_tasks WHERE status = $1`,
+		string(domain.DeadLetterOpen),
+	).Scan(&count); err != nil {
+		r.logger.Error("Failed to count open dead-lettered tasks", zap.Error(err))
+		return 0, fmt.Errorf("failed to count open dead-lettered tasks: %w", err)
+	}
+	return count, nil
+}
+
+func deadLetterUpdated(result sql.Result) (bool, error) {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+func scanDeadLetter(row interface{ Scan(...interface{}) error }) (*domain.DeadLetterTask, error) {
+	var task domain.DeadLetterTask
+	var input, output []byte
+	var applicationID, resolvedBy sql.NullString
+	var resolvedAt sql.NullTime
+	if err := row.Scan(
+		&task.ID, &task.TaskID, &task.TaskType, &task.WorkflowInstanceID, &applicationID, &input, &output,
+		&task.Error, &task.Terminal, &task.Attempts, &task.ConductorRetries, &task.Status, &resolvedBy,
+		&task.ResolutionNote, &task.FailedAt, &task.UpdatedAt, &resolvedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if applicationID.Valid {
+		task.ApplicationID = &applicationID.String
+	}
+	if resolvedBy.Valid {
+		task.ResolvedBy = &resolvedBy.String
+	}
+	if resolvedAt.Valid {
+		task.ResolvedAt = &resolvedAt.Time
+	}
+	if err := json.Unmarshal(input, &task.Input); err != nil {
+		return nil, fmt.Errorf("failed to decode task input: %w", err)
+	}
+	if output != nil {
+		if err := json.Unmarshal(output, &task.Output); err != nil {
+			return nil, fmt.Errorf("failed to decode task output: %w", err)
+		}
+	}
+	return &task, nil
+}
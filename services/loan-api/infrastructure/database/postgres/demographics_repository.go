@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// SaveApplicantDemographics records the borrower's answers to the government monitoring questions
+// for an application, replacing earlier answers
+func (r *LoanRepository) SaveApplicantDemographics(ctx context.Context, demographics *domain.ApplicantDemographics) error {
+	ethnicity, err := json.Marshal(demographics.Ethnicity)
+	if err != nil {
+		return fmt.Errorf("failed to encode ethnicity: %w", err)
+	}
+	race, err := json.Marshal(demographics.Race)
+	if err != nil {
+		return fmt.Errorf("failed to encode race: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO applicant_demographics (
+			id, application_id, user_id, ethnicity, race, sex, collection_method, collected_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+		ON CONFLICT (application_id) DO UPDATE SET
+			ethnicity = EXCLUDED.ethnicity,
+			race = EXCLUDED.race,
+			sex = EXCLUDED.sex,
+			collection_method = EXCLUDED.collection_method,
+			updated_at = EXCLUDED.updated_at`,
+		demographics.ID, demographics.ApplicationID, demographics.UserID, string(ethnicity), string(race),
+		demographics.Sex, demographics.CollectionMethod, demographics.CollectedAt, demographics.UpdatedAt,
+	); err != nil {
+		r.logger.Error("Failed to save applicant demographics",
+			zap.String("application_id", demographics.ApplicationID),
+			zap.Error(err))
+		return fmt.Errorf("failed to save applicant demographics: %w", err)
+	}
+	return nil
+}
+
+// GetApplicantDemographics retrieves the borrower's answers to the government monitoring questions
+// for an application
+func (r *LoanRepository) GetApplicantDemographics(ctx context.Context, applicationID string) (*domain.ApplicantDemographics, error) {
+	var demographics domain.ApplicantDemographics
+	var ethnicity, race []byte
+	err := r.db.QueryRow(ctx, `
+		SELECT id, application_id, user_id, ethnicity, race, sex, collection_method, collected_at, updated_at
+		FROM applicant_demographics WHERE application_id = $1`,
+		applicationID,
+	).Scan(
+		&demographics.ID, &demographics.ApplicationID, &demographics.UserID, &ethnicity, &race,
+		&demographics.Sex, &demographics.CollectionMethod, &demographics.CollectedAt, &demographics.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("applicant demographics not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applicant demographics: %w", err)
+	}
+
+	if err := json.Unmarshal(ethnicity, &demographics.Ethnicity); err != nil {
+		return nil, fmt.Errorf("failed to decode ethnicity: %w", err)
+	}
+	if err := json.Unmarshal(race, &demographics.Race); err != nil {
+		return nil, fmt.Errorf("failed to decode race: %w", err)
+	}
+	return &demographics, nil
+}
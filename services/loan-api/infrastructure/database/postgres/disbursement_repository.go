@@ -0,0 +1,340 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// disbursementColumns are the columns scanned by scanDisbursement
+const disbursementColumns = `id, application_id, offer_id, bank_account_id, batch_id, amount, payoff_application_id,
+	payoff_amount, provider, provider_transfer_id, destination_reference, destination_last4, status, attempts, next_attempt_at, return_code, failure_reason,
+	submitted_at, completed_at, created_at, updated_at`
+
+// fundingBatchColumns are the columns scanned by GetFundingBatch
+const fundingBatchColumns = `id, provider, status, submitted_count, failed_count, total_amount, created_at, submitted_at`
+
+// CreateDisbursement records a disbursement scheduled for a signed loan
+func (r *LoanRepository) CreateDisbursement(ctx context.Context, disbursement *domain.Disbursement) error {
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO disbursements (
+			id, application_id, offer_id, bank_account_id, amount, payoff_application_id, payoff_amount, provider,
+			destination_reference, destination_last4, status, attempts, next_attempt_at, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+		)`,
+		disbursement.ID, disbursement.ApplicationID, disbursement.OfferID, disbursement.BankAccountID,
+		disbursement.Amount, disbursement.PayoffApplicationID, disbursement.PayoffAmount, disbursement.Provider, disbursement.DestinationReference, disbursement.DestinationLast4,
+		disbursement.Status, disbursement.Attempts, disbursement.NextAttemptAt, disbursement.CreatedAt,
+		disbursement.UpdatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create disbursement",
+			zap.String("application_id", disbursement.ApplicationID),
+			zap.Error(err))
+		return fmt.Errorf("failed to create disbursement: %w", err)
+	}
+	return nil
+}
+
+// GetLatestDisbursement retrieves the disbursement most recently scheduled for an application
+func (r *LoanRepository) GetLatestDisbursement(ctx context.Context, applicationID string) (*domain.Disbursement, error) {
+	return r.getDisbursement(ctx, `WHERE application_id = $1 ORDER BY created_at DESC LIMIT 1`, applicationID)
+}
+
+// GetDisbursementByProviderTransferID retrieves a disbursement by the provider's transfer ID
+func (r *LoanRepository) GetDisbursementByProviderTransferID(ctx context.Context, provider, providerTransferID string) (*domain.Disbursement, error) {
+	return r.getDisbursement(ctx, `WHERE provider = $1 AND provider_transfer_id = $2`, provider, providerTransferID)
+}
+
+// getDisbursement retrieves the first disbursement matching a WHERE clause
+func (r *LoanRepository) getDisbursement(ctx context.Context, where string, args ...interface{}) (*domain.Disbursement, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT `+disbursementColumns+`
+		FROM disbursements `+where,
+		args...)
+
+	disbursement, err := scanDisbursement(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("disbursement not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disbursement: %w", err)
+	}
+	return disbursement, nil
+}
+
+// UpdateDisbursement saves a disbursement's transfer, status and retry schedule
+func (r *LoanRepository) UpdateDisbursement(ctx context.Context, disbursement *domain.Disbursement) error {
+	if _, err := r.db.Exec(ctx, `
+		UPDATE disbursements SET
+			batch_id = $1, provider_transfer_id = $2, destination_reference = $3, status = $4, attempts = $5,
+			next_attempt_at = $6, return_code = $7, failure_reason = $8, submitted_at = $9, completed_at = $10,
+			updated_at = $11
+		WHERE id = $12`,
+		disbursement.BatchID, disbursement.ProviderTransferID, disbursement.DestinationReference, disbursement.Status,
+		disbursement.Attempts, disbursement.NextAttemptAt, disbursement.ReturnCode, disbursement.FailureReason,
+		disbursement.SubmittedAt, disbursement.CompletedAt, disbursement.UpdatedAt, disbursement.ID,
+	); err != nil {
+		r.logger.Error("Failed to update disbursement",
+			zap.String("disbursement_id", disbursement.ID),
+			zap.Error(err))
+		return fmt.Errorf("failed to update disbursement: %w", err)
+	}
+	return nil
+}
+
+// ClaimDueDisbursements queues up to limit pending disbursements whose next attempt is due as of
+// the given time on a funding batch, oldest first. Only disbursements of applications still in the
+// documents signed state are claimed, so an application sent back to underwriting is not funded.
+// Rows claimed by a concurrent batch are skipped.
+func (r *LoanRepository) ClaimDueDisbursements(ctx context.Context, batchID string, asOf time.Time, limit int) ([]*domain.Disbursement, error) {
+	logger := r.logger.With(
+		zap.String("operation", "claim_due_disbursements"),
+		zap.String("batch_id", batchID),
+	)
+
+	rows, err := r.db.Query(ctx, `
+		UPDATE disbursements SET status = $1, batch_id = $2, updated_at = $3
+		WHERE id IN (
+			SELECT d.id FROM disbursements d
+			JOIN loan_applications a ON a.id = d.application_id
+			WHERE d.status = $4 AND (d.next_attempt_at IS NULL OR d.next_attempt_at <= $3)
+				AND a.current_state = $6
+			ORDER BY d.created_at, d.id
+			LIMIT $5
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING `+disbursementColumns,
+		domain.DisbursementQueued, batchID, asOf, domain.DisbursementPending, limit, domain.StateDocumentsSigned)
+	if err != nil {
+		logger.Error("Failed to claim disbursements", zap.Error(err))
+		return nil, fmt.Errorf("failed to claim disbursements: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDisbursements(rows)
+}
+
+// ListBatchDisbursements lists the disbursements claimed by a funding batch, oldest first
+func (r *LoanRepository) ListBatchDisbursements(ctx context.Context, batchID string) ([]*domain.Disbursement, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+disbursementColumns+`
+		FROM disbursements WHERE batch_id = $1
+		ORDER BY created_at, id`,
+		batchID)
+	if err != nil {
+		r.logger.Error("Failed to list batch disbursements",
+			zap.String("batch_id", batchID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to list batch disbursements: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDisbursements(rows)
+}
+
+// CreateFundingBatch records a funding run
+func (r *LoanRepository) CreateFundingBatch(ctx context.Context, batch *domain.FundingBatch) error {
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO funding_batches (
+			id, provider, status, submitted_count, failed_count, total_amount, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)`,
+		batch.ID, batch.Provider, batch.Status, batch.SubmittedCount, batch.FailedCount, batch.TotalAmount,
+		batch.CreatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create funding batch", zap.Error(err))
+		return fmt.Errorf("failed to create funding batch: %w", err)
+	}
+	return nil
+}
+
+// UpdateFundingBatch saves a funding batch's status and totals
+func (r *LoanRepository) UpdateFundingBatch(ctx context.Context, batch *domain.FundingBatch) error {
+	if _, err := r.db.Exec(ctx, `
+		UPDATE funding_batches SET status = $1, submitted_count = $2, failed_count = $3, total_amount = $4,
+			submitted_at = $5
+		WHERE id = $6`,
+		batch.Status, batch.SubmittedCount, batch.FailedCount, batch.TotalAmount, batch.SubmittedAt, batch.ID,
+	); err != nil {
+		r.logger.Error("Failed to update funding batch",
+			zap.String("batch_id", batch.ID),
+			zap.Error(err))
+		return fmt.Errorf("failed to update funding batch: %w", err)
+	}
+	return nil
+}
+
+// GetFundingBatch retrieves a funding batch by ID
+func (r *LoanRepository) GetFundingBatch(ctx context.Context, id string) (*domain.FundingBatch, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT `+fundingBatchColumns+`
+		FROM funding_batches WHERE id = $1`,
+		id)
+
+	var batch domain.FundingBatch
+	var submittedAt sql.NullTime
+	err := row.Scan(
+		&batch.ID, &batch.Provider, &batch.Status, &batch.SubmittedCount, &batch.FailedCount, &batch.TotalAmount,
+		&batch.CreatedAt, &submittedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("funding batch not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funding batch: %w", err)
+	}
+
+	if submittedAt.Valid {
+		batch.SubmittedAt = &submittedAt.Time
+	}
+	return &batch, nil
+}
+
+// CreateDisbursementEvent records a step in a disbursement's audit trail
+func (r *LoanRepository) CreateDisbursementEvent(ctx context.Context, event *domain.DisbursementEvent) error {
+	metadata, err := marshalOptionalJSON(len(event.Metadata) > 0, event.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode disbursement event metadata: %w", err)
+	}
+
+	var detail interface{}
+	if event.Detail != "" {
+		detail = event.Detail
+	}
+
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO disbursement_events (
+			id, disbursement_id, application_id, event_type, from_status, to_status, detail, metadata, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)`,
+		event.ID, event.DisbursementID, event.ApplicationID, event.EventType, event.FromStatus, event.ToStatus,
+		detail, metadata, event.CreatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create disbursement event",
+			zap.String("disbursement_id", event.DisbursementID),
+			zap.String("event_type", string(event.EventType)),
+			zap.Error(err))
+		return fmt.Errorf("failed to create disbursement event: %w", err)
+	}
+	return nil
+}
+
+// ListDisbursementEvents lists a disbursement's audit trail, oldest first
+func (r *LoanRepository) ListDisbursementEvents(ctx context.Context, disbursementID string) ([]*domain.DisbursementEvent, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, disbursement_id, application_id, event_type, from_status, to_status, detail, metadata, created_at
+		FROM disbursement_events WHERE disbursement_id = $1
+		ORDER BY created_at, id`,
+		disbursementID)
+	if err != nil {
+		r.logger.Error("Failed to list disbursement events",
+			zap.String("disbursement_id", disbursementID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to list disbursement events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.DisbursementEvent
+	for rows.Next() {
+		var event domain.DisbursementEvent
+		var fromStatus, detail sql.NullString
+		var metadata []byte
+		if err := rows.Scan(
+			&event.ID, &event.DisbursementID, &event.ApplicationID, &event.EventType, &fromStatus, &event.ToStatus,
+			&detail, &metadata, &event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan disbursement event: %w", err)
+		}
+
+		if fromStatus.Valid {
+			status := domain.DisbursementStatus(fromStatus.String)
+			event.FromStatus = &status
+		}
+		event.Detail = detail.String
+		if len(metadata) > 0 {
+			if err := json.Unmarshal(metadata, &event.Metadata); err != nil {
+				return nil, fmt.Errorf("failed to decode disbursement event metadata: %w", err)
+			}
+		}
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return events, nil
+}
+
+// scanDisbursements scans every row of disbursementColumns
+func scanDisbursements(rows *sql.Rows) ([]*domain.Disbursement, error) {
+	var disbursements []*domain.Disbursement
+	for rows.Next() {
+		disbursement, err := scanDisbursement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan disbursement: %w", err)
+		}
+		disbursements = append(disbursements, disbursement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return disbursements, nil
+}
+
+// scanDisbursement scans a row of disbursementColumns
+func scanDisbursement(row interface{ Scan(...interface{}) error }) (*domain.Disbursement, error) {
+	var disbursement domain.Disbursement
+	var bankAccountID, batchID, payoffApplicationID, providerTransferID, destinationReference, returnCode, failureReason sql.NullString
+	var nextAttemptAt, submittedAt, completedAt sql.NullTime
+	if err := row.Scan(
+		&disbursement.ID, &disbursement.ApplicationID, &disbursement.OfferID, &bankAccountID, &batchID, &disbursement.Amount,
+		&payoffApplicationID, &disbursement.PayoffAmount, &disbursement.Provider, &providerTransferID, &destinationReference, &disbursement.DestinationLast4,
+		&disbursement.Status, &disbursement.Attempts, &nextAttemptAt, &returnCode, &failureReason, &submittedAt,
+		&completedAt, &disbursement.CreatedAt, &disbursement.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	// Disbursements scheduled before bank accounts were linked have none
+	disbursement.BankAccountID = bankAccountID.String
+	if batchID.Valid {
+		disbursement.BatchID = &batchID.String
+	}
+	if payoffApplicationID.Valid {
+		disbursement.PayoffApplicationID = &payoffApplicationID.String
+	}
+	if providerTransferID.Valid {
+		disbursement.ProviderTransferID = &providerTransferID.String
+	}
+	if destinationReference.Valid {
+		disbursement.DestinationReference = &destinationReference.String
+	}
+	if returnCode.Valid {
+		disbursement.ReturnCode = &returnCode.String
+	}
+	if failureReason.Valid {
+		disbursement.FailureReason = &failureReason.String
+	}
+	if nextAttemptAt.Valid {
+		disbursement.NextAttemptAt = &nextAttemptAt.Time
+	}
+	if submittedAt.Valid {
+		disbursement.SubmittedAt = &submittedAt.Time
+	}
+	if completedAt.Valid {
+		disbursement.CompletedAt = &completedAt.Time
+	}
+	return &disbursement, nil
+}
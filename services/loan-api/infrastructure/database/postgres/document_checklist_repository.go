@@ -0,0 +1,205 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// ListDocumentRules retrieves the active document checklist rules
+func (r *LoanRepository) ListDocumentRules(ctx context.Context) ([]*domain.DocumentRule, error) {
+	logger := r.logger.With(zap.String("operation", "list_document_rules"))
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, product, document_type, applies_to, min_loan_amount, max_loan_amount,
+			employment_statuses, condition_type, description
+		FROM document_rules WHERE active ORDER BY created_at, id`)
+	if err != nil {
+		logger.Error("Failed to list document rules", zap.Error(err))
+		return nil, fmt.Errorf("failed to list document rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*domain.DocumentRule
+	for rows.Next() {
+		var rule domain.DocumentRule
+		var minLoanAmount, maxLoanAmount sql.NullFloat64
+		var employmentStatuses []byte
+		var conditionType sql.NullString
+		if err := rows.Scan(
+			&rule.ID, &rule.Product, &rule.DocumentType, &rule.AppliesTo, &minLoanAmount, &maxLoanAmount,
+			&employmentStatuses, &conditionType, &rule.Description,
+		); err != nil {
+			logger.Error("Failed to scan document rule row", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan document rule: %w", err)
+		}
+
+		if minLoanAmount.Valid {
+			rule.MinLoanAmount = &minLoanAmount.Float64
+		}
+		if maxLoanAmount.Valid {
+			rule.MaxLoanAmount = &maxLoanAmount.Float64
+		}
+		if len(employmentStatuses) > 0 {
+			if err := json.Unmarshal(employmentStatuses, &rule.EmploymentStatuses); err != nil {
+				return nil, fmt.Errorf("failed to decode employment statuses: %w", err)
+			}
+		}
+		rule.ConditionType = conditionType.String
+		rules = append(rules, &rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return rules, nil
+}
+
+// AddDocumentRequirements adds requirements to an application's checklist. A document type
+// already required from the same borrower is left as it is.
+func (r *LoanRepository) AddDocumentRequirements(ctx context.Context, requirements []*domain.DocumentRequirement) error {
+	logger := r.logger.With(zap.String("operation", "add_document_requirements"))
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, requirement := range requirements {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO application_document_requirements (
+				id, application_id, borrower, document_type, source, rule_id, condition_id,
+				description, status, created_at, updated_at
+			) VALUES (
+				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10
+			) ON CONFLICT (application_id, borrower, document_type) DO NOTHING`,
+			requirement.ID, requirement.ApplicationID, requirement.Borrower, requirement.DocumentType,
+			requirement.Source, requirement.RuleID, requirement.ConditionID, requirement.Description,
+			requirement.Status, requirement.CreatedAt,
+		); err != nil {
+			logger.Error("Failed to add document requirement",
+				zap.String("application_id", requirement.ApplicationID),
+				zap.String("document_type", requirement.DocumentType),
+				zap.Error(err))
+			return fmt.Errorf("failed to add document requirement: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit document requirements", zap.Error(err))
+		return fmt.Errorf("failed to commit document requirements: %w", err)
+	}
+
+	return nil
+}
+
+// ListDocumentRequirements retrieves an application's document checklist
+func (r *LoanRepository) ListDocumentRequirements(ctx context.Context, applicationID string) ([]*domain.DocumentRequirement, error) {
+	logger := r.logger.With(
+		zap.String("operation", "list_document_requirements"),
+		zap.String("application_id", applicationID),
+	)
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, application_id, borrower, document_type, source, rule_id, condition_id,
+			description, status, document_id, created_at, updated_at
+		FROM application_document_requirements WHERE application_id = $1
+		ORDER BY borrower, created_at, document_type`, applicationID)
+	if err != nil {
+		logger.Error("Failed to list document requirements", zap.Error(err))
+		return nil, fmt.Errorf("failed to list document requirements: %w", err)
+	}
+	defer rows.Close()
+
+	requirements := []*domain.DocumentRequirement{}
+	for rows.Next() {
+		var requirement domain.DocumentRequirement
+		var ruleID, conditionID, documentID sql.NullString
+		if err := rows.Scan(
+			&requirement.ID, &requirement.ApplicationID, &requirement.Borrower, &requirement.DocumentType,
+			&requirement.Source, &ruleID, &conditionID, &requirement.Description, &requirement.Status,
+			&documentID, &requirement.CreatedAt, &requirement.UpdatedAt,
+		); err != nil {
+			logger.Error("Failed to scan document requirement row", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan document requirement: %w", err)
+		}
+
+		if ruleID.Valid {
+			requirement.RuleID = &ruleID.String
+		}
+		if conditionID.Valid {
+			requirement.ConditionID = &conditionID.String
+		}
+		if documentID.Valid {
+			requirement.DocumentID = &documentID.String
+		}
+		requirements = append(requirements, &requirement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return requirements, nil
+}
+
+// MarkDocumentRequirementReceived records the upload that satisfied a required document
+func (r *LoanRepository) MarkDocumentRequirementReceived(ctx context.Context, requirementID, documentID string, receivedAt time.Time) error {
+	if _, err := r.db.Exec(ctx, `
+		UPDATE application_document_requirements SET status = $1, document_id = $2, updated_at = $3
+		WHERE id = $4 AND status = $5`,
+		domain.DocumentRequirementReceived, documentID, receivedAt, requirementID, domain.DocumentRequirementRequired,
+	); err != nil {
+		r.logger.Error("Failed to mark document requirement received",
+			zap.String("requirement_id", requirementID),
+			zap.Error(err))
+		return fmt.Errorf("failed to update document requirement: %w", err)
+	}
+	return nil
+}
+
+// CreateUnderwritingCondition stores an underwriting condition placed on an application
+func (r *LoanRepository) CreateUnderwritingCondition(ctx context.Context, condition *domain.UnderwritingCondition) error {
+	logger := r.logger.With(
+		zap.String("operation", "create_underwriting_condition"),
+		zap.String("condition_id", condition.ID),
+		zap.String("application_id", condition.ApplicationID),
+	)
+
+	documentTypes, err := marshalOptionalJSON(len(condition.DocumentTypes) > 0, condition.DocumentTypes)
+	if err != nil {
+		return fmt.Errorf("failed to encode document types: %w", err)
+	}
+
+	var createdBy interface{}
+	if condition.CreatedBy != "" {
+		createdBy = condition.CreatedBy
+	}
+
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO underwriting_conditions (
+			id, application_id, condition_type, description, document_types, priority, critical,
+			status, due_at, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $11
+		)`,
+		condition.ID, condition.ApplicationID, condition.ConditionType, condition.Description,
+		documentTypes, condition.Priority, condition.Critical, string(condition.Status), condition.DueAt,
+		createdBy, condition.CreatedAt,
+	); err != nil {
+		logger.Error("Failed to create underwriting condition", zap.Error(err))
+		return fmt.Errorf("failed to create underwriting condition: %w", err)
+	}
+
+	logger.Info("Underwriting condition created successfully", zap.String("condition_type", condition.ConditionType))
+	return nil
+}
@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// duplicateMatchColumns are the columns scanned by scanDuplicateMatch
+const duplicateMatchColumns = `id, application_id, candidate_application_id, candidate_user_id, score, reasons, status,
+	reviewed_by, review_note, reviewed_at, created_at`
+
+// CreateApplicationFingerprint records the identity signals an application was made with
+func (r *LoanRepository) CreateApplicationFingerprint(ctx context.Context, fingerprint *domain.ApplicationFingerprint) error {
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO application_fingerprints (
+			application_id, user_id, ssn_token, email_normalized, device_id, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6
+		)
+		ON CONFLICT (application_id) DO NOTHING`,
+		fingerprint.ApplicationID, fingerprint.UserID, fingerprint.SSNToken, fingerprint.Email,
+		fingerprint.DeviceID, fingerprint.CreatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create application fingerprint",
+			zap.String("application_id", fingerprint.ApplicationID),
+			zap.Error(err))
+		return fmt.Errorf("failed to create application fingerprint: %w", err)
+	}
+	return nil
+}
+
+// FindDuplicateCandidates lists other borrowers' applications made since the given time that share
+// the fingerprint's SSN token, email or device
+func (r *LoanRepository) FindDuplicateCandidates(ctx context.Context, fingerprint *domain.ApplicationFingerprint, since time.Time) ([]*domain.ApplicationFingerprint, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT application_id, user_id, ssn_token, email_normalized, device_id, created_at
+		FROM application_fingerprints
+		WHERE user_id <> $1 AND created_at >= $2
+			AND ((ssn_token <> '' AND ssn_token = $3)
+				OR (email_normalized <> '' AND email_normalized = $4)
+				OR (device_id <> '' AND device_id = $5))
+		ORDER BY created_at DESC`,
+		fingerprint.UserID, since, fingerprint.SSNToken, fingerprint.Email, fingerprint.DeviceID)
+	if err != nil {
+		r.logger.Error("Failed to find duplicate candidates",
+			zap.String("application_id", fingerprint.ApplicationID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to find duplicate candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []*domain.ApplicationFingerprint
+	for rows.Next() {
+		var candidate domain.ApplicationFingerprint
+		if err := rows.Scan(
+			&candidate.ApplicationID, &candidate.UserID, &candidate.SSNToken, &candidate.Email,
+			&candidate.DeviceID, &candidate.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate candidate: %w", err)
+		}
+		candidates = append(candidates, &candidate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// CreateDuplicateMatch records a suspected duplicate for review
+func (r *LoanRepository) CreateDuplicateMatch(ctx context.Context, match *domain.DuplicateMatch) error {
+	reasons, err := json.Marshal(match.Reasons)
+	if err != nil {
+		return fmt.Errorf("failed to encode reasons: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO application_duplicate_matches (
+			id, application_id, candidate_application_id, candidate_user_id, score, reasons, status, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)
+		ON CONFLICT (application_id, candidate_application_id) DO NOTHING`,
+		match.ID, match.ApplicationID, match.CandidateApplicationID, match.CandidateUserID, match.Score,
+		string(reasons), match.Status, match.CreatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create duplicate match",
+			zap.String("application_id", match.ApplicationID),
+			zap.Error(err))
+		return fmt.Errorf("failed to create duplicate match: %w", err)
+	}
+	return nil
+}
+
+// GetDuplicateMatch retrieves a suspected duplicate by ID
+func (r *LoanRepository) GetDuplicateMatch(ctx context.Context, id string) (*domain.DuplicateMatch, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT `+duplicateMatchColumns+`
+		FROM application_duplicate_matches WHERE id = $1`,
+		id)
+
+	match, err := scanDuplicateMatch(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("duplicate match not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get duplicate match: %w", err)
+	}
+	return match, nil
+}
+
+// ListDuplicateMatches lists the suspected duplicates of an application, highest score first
+func (r *LoanRepository) ListDuplicateMatches(ctx context.Context, applicationID string) ([]*domain.DuplicateMatch, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+duplicateMatchColumns+`
+		FROM application_duplicate_matches WHERE application_id = $1
+		ORDER BY score DESC, created_at, id`,
+		applicationID)
+	if err != nil {
+		r.logger.Error("Failed to list duplicate matches",
+			zap.String("application_id", applicationID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to list duplicate matches: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []*domain.DuplicateMatch
+	for rows.Next() {
+		match, err := scanDuplicateMatch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate match: %w", err)
+		}
+		matches = append(matches, match)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return matches, nil
+}
+
+// UpdateDuplicateMatchReview saves an admin's review of a suspected duplicate
+func (r *LoanRepository) UpdateDuplicateMatchReview(ctx context.Context, match *domain.DuplicateMatch) error {
+	result, err := r.db.Exec(ctx, `
+		UPDATE application_duplicate_matches SET
+			status = $1, reviewed_by = $2, review_note = $3, reviewed_at = $4
+		WHERE id = $5`,
+		match.Status, match.ReviewedBy, match.ReviewNote, match.ReviewedAt, match.ID,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update duplicate match review",
+			zap.String("match_id", match.ID),
+			zap.Error(err))
+		return fmt.Errorf("failed to update duplicate match review: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("duplicate match not found")
+	}
+	return nil
+}
+
+func scanDuplicateMatch(row interface{ Scan(...interface{}) error }) (*domain.DuplicateMatch, error) {
+	var match domain.DuplicateMatch
+	var reasons []byte
+	var reviewedBy, reviewNote sql.NullString
+	var reviewedAt sql.NullTime
+	if err := row.Scan(
+		&match.ID, &match.ApplicationID, &match.CandidateApplicationID, &match.CandidateUserID, &match.Score,
+		&reasons, &match.Status, &reviewedBy, &reviewNote, &reviewedAt, &match.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if len(reasons) > 0 {
+		if err := json.Unmarshal(reasons, &match.Reasons); err != nil {
+			return nil, fmt.Errorf("failed to decode reasons: %w", err)
+		}
+	}
+	if reviewedBy.Valid {
+		match.ReviewedBy = &reviewedBy.String
+	}
+	if reviewNote.Valid {
+		match.ReviewNote = &reviewNote.String
+	}
+	if reviewedAt.Valid {
+		match.ReviewedAt = &reviewedAt.Time
+	}
+	return &match, nil
+}
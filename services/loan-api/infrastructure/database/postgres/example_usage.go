@@ -53,7 +53,8 @@ func ExampleUsage() {
 		Email:       "john.doe@example.com",
 		PhoneNumber: "+1234567890",
 		DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
-		SSN:         "123456789",
+		SSNToken:    "tok_ssn_3f9a1c7e5b2d4f6081a9c3e5b7d9f1a2",
+		SSNLast4:    "6789",
 		Address: domain.Address{
 			StreetAddress: "123 Main St",
 			City:          "New York",
@@ -25,11 +25,21 @@ func (f *Factory) GetUserRepository() application.UserRepository {
 	return NewUserRepository(f.connection, f.logger)
 }
 
+// GetSSNBackfillRepository returns the user repository for tokenizing plaintext SSNs
+func (f *Factory) GetSSNBackfillRepository() application.SSNBackfillRepository {
+	return NewUserRepository(f.connection, f.logger)
+}
+
 // GetLoanRepository returns a new LoanRepository instance
 func (f *Factory) GetLoanRepository() application.LoanRepository {
 	return NewLoanRepository(f.connection, f.logger)
 }
 
+// GetPricingRepository returns a new PricingRepository instance
+func (f *Factory) GetPricingRepository() application.PricingRepository {
+	return NewPricingRepository(f.connection, f.logger)
+}
+
 // GetConnection returns the database connection
 func (f *Factory) GetConnection() *Connection {
 	return f.connection
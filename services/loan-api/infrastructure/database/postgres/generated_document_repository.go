@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// generatedDocumentColumns are the columns scanned by scanGeneratedDocument
+const generatedDocumentColumns = `id, application_id, document_type, offer_id, stored_document_id, file_name,
+	mime_type, size, generated_by, reason_codes, delivered_at, created_at`
+
+// CreateGeneratedDocument records a document rendered for an application
+func (r *LoanRepository) CreateGeneratedDocument(ctx context.Context, document *domain.GeneratedDocument) error {
+	var reasonCodes []byte
+	if document.ReasonCodes != nil {
+		var err error
+		if reasonCodes, err = json.Marshal(document.ReasonCodes); err != nil {
+			return fmt.Errorf("failed to marshal reason codes: %w", err)
+		}
+	}
+
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO generated_documents (
+			id, application_id, document_type, offer_id, stored_document_id, file_name, mime_type, size,
+			generated_by, reason_codes, delivered_at, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+		)`,
+		document.ID, document.ApplicationID, document.DocumentType, document.OfferID, document.StoredDocumentID,
+		document.FileName, document.MimeType, document.Size, document.GeneratedBy, reasonCodes, document.DeliveredAt,
+		document.CreatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create generated document",
+			zap.String("application_id", document.ApplicationID),
+			zap.String("document_type", document.DocumentType),
+			zap.Error(err))
+		return fmt.Errorf("failed to create generated document: %w", err)
+	}
+	return nil
+}
+
+// MarkGeneratedDocumentDelivered records when a generated document was delivered to the borrower
+func (r *LoanRepository) MarkGeneratedDocumentDelivered(ctx context.Context, id string, deliveredAt time.Time) error {
+	result, err := r.db.Exec(ctx, `
+		UPDATE generated_documents SET delivered_at = $2 WHERE id = $1`,
+		id, deliveredAt)
+	if err != nil {
+		r.logger.Error("Failed to mark generated document delivered",
+			zap.String("document_id", id),
+			zap.Error(err))
+		return fmt.Errorf("failed to mark generated document delivered: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("generated document not found")
+	}
+	return nil
+}
+
+// GetGeneratedDocument retrieves a generated document by ID
+func (r *LoanRepository) GetGeneratedDocument(ctx context.Context, id string) (*domain.GeneratedDocument, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT `+generatedDocumentColumns+`
+		FROM generated_documents WHERE id = $1`,
+		id)
+
+	document, err := scanGeneratedDocument(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("generated document not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generated document: %w", err)
+	}
+	return document, nil
+}
+
+// ListGeneratedDocuments lists the documents generated for an application, newest first
+func (r *LoanRepository) ListGeneratedDocuments(ctx context.Context, applicationID string) ([]*domain.GeneratedDocument, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+generatedDocumentColumns+`
+		FROM generated_documents WHERE application_id = $1
+		ORDER BY created_at DESC, id`,
+		applicationID)
+	if err != nil {
+		r.logger.Error("Failed to list generated documents",
+			zap.String("application_id", applicationID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to list generated documents: %w", err)
+	}
+	defer rows.Close()
+
+	var documents []*domain.GeneratedDocument
+	for rows.Next() {
+		document, err := scanGeneratedDocument(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan generated document: %w", err)
+		}
+		documents = append(documents, document)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return documents, nil
+}
+
+// scanGeneratedDocument scans a row of generatedDocumentColumns
+func scanGeneratedDocument(row interface{ Scan(...interface{}) error }) (*domain.GeneratedDocument, error) {
+	var document domain.GeneratedDocument
+	var offerID, generatedBy sql.NullString
+	var reasonCodes []byte
+	var deliveredAt sql.NullTime
+	if err := row.Scan(
+		&document.ID, &document.ApplicationID, &document.DocumentType, &offerID, &document.StoredDocumentID,
+		&document.FileName, &document.MimeType, &document.Size, &generatedBy, &reasonCodes, &deliveredAt,
+		&document.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if offerID.Valid {
+		document.OfferID = &offerID.String
+	}
+	if generatedBy.Valid {
+		document.GeneratedBy = &generatedBy.String
+	}
+	if reasonCodes != nil {
+		if err := json.Unmarshal(reasonCodes, &document.ReasonCodes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal reason codes: %w", err)
+		}
+	}
+	if deliveredAt.Valid {
+		document.DeliveredAt = &deliveredAt.Time
+	}
+	return &document, nil
+}
@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/shared/pkg/payroll"
+)
+
+// Income verifications are recorded by the underwriting worker as JSON documents in
+// underwriting_income_verifications; the loan service reads them and completes payroll
+// verifications in place, using the worker's document keys.
+
+// incomeVerificationDocument is the part of the worker's verification document the loan service reads
+type incomeVerificationDocument struct {
+	StatedAnnualIncome    float64                `json:"stated_annual_income"`
+	VerifiedAnnualIncome  float64                `json:"verified_annual_income"`
+	VerifiedMonthlyIncome float64                `json:"verified_monthly_income"`
+	IncomeVariance        float64                `json:"income_variance"`
+	IncomeVariancePercent float64                `json:"income_variance_percent"`
+	IncomeVarianceFlagged bool                   `json:"income_variance_flagged"`
+	EmployerName          string                 `json:"employer_name"`
+	VerificationNotes     string                 `json:"verification_notes"`
+	VerificationData      map[string]interface{} `json:"verification_data"`
+	VerifiedAt            time.Time              `json:"verified_at"`
+}
+
+// GetIncomeVerification retrieves the latest income verification of an application
+func (r *LoanRepository) GetIncomeVerification(ctx context.Context, applicationID string) (*domain.IncomeVerification, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT id, application_id, user_id, verification_method, verification_status, verification
+		FROM underwriting_income_verifications WHERE application_id = $1
+		ORDER BY created_at DESC LIMIT 1`,
+		applicationID)
+
+	verification, err := scanIncomeVerification(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("income verification not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get income verification: %w", err)
+	}
+	return verification, nil
+}
+
+// GetIncomeVerificationByProviderUser retrieves the payroll verification of a payroll provider user
+func (r *LoanRepository) GetIncomeVerificationByProviderUser(ctx context.Context, providerUserID string) (*domain.IncomeVerification, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT id, application_id, user_id, verification_method, verification_status, verification
+		FROM underwriting_income_verifications
+		WHERE verification_method = $1 AND verification->'verification_data'->>'provider_user_id' = $2
+		ORDER BY created_at DESC LIMIT 1`,
+		domain.IncomeMethodPayroll, providerUserID)
+
+	verification, err := scanIncomeVerification(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("income verification not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get income verification: %w", err)
+	}
+	return verification, nil
+}
+
+// CompleteIncomeVerification records income verified from payroll data on a pending verification.
+// It reports false when the verification is no longer pending.
+func (r *LoanRepository) CompleteIncomeVerification(ctx context.Context, id string, income *payroll.Income) (bool, error) {
+	now := time.Now().UTC()
+	notes := fmt.Sprintf("Income verified from %d payroll paystubs", income.PaystubCount)
+	if income.Basis == "base_pay" {
+		notes = "Income verified from the base pay of the payroll employment"
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"verification_status":     string(domain.IncomeVerificationVerified),
+		"verified_annual_income":  income.VerifiedAnnualIncome,
+		"verified_monthly_income": income.VerifiedMonthlyIncome,
+		"stated_annual_income":    income.StatedAnnualIncome,
+		"income_variance":         income.Variance,
+		"income_variance_percent": income.VariancePercent,
+		"income_variance_flagged": income.VarianceFlagged,
+		"employer_name":           income.Employer,
+		"job_title":               income.JobTitle,
+		"employment_type":         income.EmploymentType,
+		"employment_start_date":   income.HireDate,
+		"pay_frequency":           income.PayFrequency,
+		"last_pay_stub_date":      income.LastPayDate,
+		"documents_provided":      []string{"payroll_" + income.Basis},
+		"verification_notes":      notes,
+		"verified_at":             now,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to encode income verification: %w", err)
+	}
+	payrollIncome, err := json.Marshal(income)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode payroll income: %w", err)
+	}
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE underwriting_income_verifications SET
+			verification_status = $1,
+			verification = jsonb_set(verification || $2::jsonb, '{verification_data,payroll_income}', $3::jsonb),
+			updated_at = $4
+		WHERE id = $5 AND verification_status = $6`,
+		string(domain.IncomeVerificationVerified), string(patch), string(payrollIncome), now, id,
+		string(domain.IncomeVerificationPending),
+	)
+	if err != nil {
+		r.logger.Error("Failed to complete income verification",
+			zap.String("verification_id", id),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to complete income verification: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+func scanIncomeVerification(row interface{ Scan(...interface{}) error }) (*domain.IncomeVerification, error) {
+	var verification domain.IncomeVerification
+	var status string
+	var document []byte
+	if err := row.Scan(
+		&verification.ID, &verification.ApplicationID, &verification.UserID, &verification.Method,
+		&status, &document,
+	); err != nil {
+		return nil, err
+	}
+	verification.Status = domain.IncomeVerificationStatus(status)
+
+	var doc incomeVerificationDocument
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode income verification: %w", err)
+	}
+	verification.StatedAnnualIncome = doc.StatedAnnualIncome
+	verification.VerifiedAnnualIncome = doc.VerifiedAnnualIncome
+	verification.VerifiedMonthlyIncome = doc.VerifiedMonthlyIncome
+	verification.IncomeVariance = doc.IncomeVariance
+	verification.IncomeVariancePercent = doc.IncomeVariancePercent
+	verification.IncomeVarianceFlagged = doc.IncomeVarianceFlagged
+	verification.EmployerName = doc.EmployerName
+	verification.VerificationNotes = doc.VerificationNotes
+	verification.VerifiedAt = doc.VerifiedAt
+	verification.Provider, _ = doc.VerificationData["provider"].(string)
+	verification.ProviderUserID, _ = doc.VerificationData["provider_user_id"].(string)
+	verification.ConnectToken, _ = doc.VerificationData["connect_token"].(string)
+	return &verification, nil
+}
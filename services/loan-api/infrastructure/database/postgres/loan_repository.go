@@ -3,12 +3,15 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/shared/pkg/income"
 )
 
 // LoanRepository implements domain.LoanRepository interface
@@ -37,16 +40,28 @@ func (r *LoanRepository) CreateApplication(ctx context.Context, app *domain.Loan
 		INSERT INTO loan_applications (
 			id, user_id, application_number, loan_amount, loan_purpose, requested_term_months,
 			annual_income, monthly_income, employment_status, monthly_debt_payments,
-			current_state, status, risk_score, workflow_id, created_at, updated_at
+			current_state, status, risk_score, workflow_id, co_borrower, application_type, refinanced_application_id,
+			income_sources, created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
 		)`
 
-	_, err := r.db.Exec(ctx, query,
+	coBorrower, err := marshalCoBorrower(app.CoBorrower)
+	if err != nil {
+		logger.Error("Failed to encode co-borrower", zap.Error(err))
+		return fmt.Errorf("failed to encode co-borrower: %w", err)
+	}
+	incomeSources, err := marshalIncomeSources(app.IncomeSources)
+	if err != nil {
+		logger.Error("Failed to encode income sources", zap.Error(err))
+		return fmt.Errorf("failed to encode income sources: %w", err)
+	}
+
+	_, err = r.db.Exec(ctx, query,
 		app.ID, app.UserID, app.ApplicationNumber, app.LoanAmount, app.LoanPurpose, app.RequestedTerm,
 		app.AnnualIncome, app.MonthlyIncome, app.EmploymentStatus, app.MonthlyDebt,
-		app.CurrentState, app.Status, app.RiskScore, app.WorkflowID,
-		time.Now().UTC(), time.Now().UTC(),
+		app.CurrentState, app.Status, app.RiskScore, app.WorkflowID, coBorrower, app.ApplicationType,
+		app.RefinancedApplicationID, incomeSources, time.Now().UTC(), time.Now().UTC(),
 	)
 
 	if err != nil {
@@ -54,241 +69,1511 @@ func (r *LoanRepository) CreateApplication(ctx context.Context, app *domain.Loan
 		return fmt.Errorf("failed to create application: %w", err)
 	}
 
-	logger.Info("Application created successfully", zap.String("application_id", app.ID))
-	return nil
+	logger.Info("Application created successfully", zap.String("application_id", app.ID))
+	return nil
+}
+
+// GetApplicationByID retrieves a loan application by ID
+func (r *LoanRepository) GetApplicationByID(ctx context.Context, id string) (*domain.LoanApplication, error) {
+	logger := r.logger.With(
+		zap.String("operation", "get_application_by_id"),
+		zap.String("application_id", id),
+	)
+
+	query := `
+		SELECT 
+			id, user_id, application_number, loan_amount, loan_purpose, requested_term_months,
+			annual_income, monthly_income, employment_status, monthly_debt_payments,
+			current_state, status, risk_score, workflow_id, co_borrower, application_type, refinanced_application_id,
+			income_sources, created_at, updated_at
+		FROM loan_applications WHERE id = $1`
+
+	var app domain.LoanApplication
+	var coBorrower, incomeSources []byte
+	var createdAt, updatedAt time.Time
+
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&app.ID, &app.UserID, &app.ApplicationNumber, &app.LoanAmount, &app.LoanPurpose, &app.RequestedTerm,
+		&app.AnnualIncome, &app.MonthlyIncome, &app.EmploymentStatus, &app.MonthlyDebt,
+		&app.CurrentState, &app.Status, &app.RiskScore, &app.WorkflowID, &coBorrower, &app.ApplicationType,
+		&app.RefinancedApplicationID, &incomeSources,
+		&createdAt, &updatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			logger.Warn("Application not found", zap.String("application_id", id))
+			return nil, fmt.Errorf("application not found: %s", id)
+		}
+		logger.Error("Failed to get application by ID", zap.Error(err))
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+
+	if app.CoBorrower, err = unmarshalCoBorrower(coBorrower); err != nil {
+		logger.Error("Failed to decode co-borrower", zap.Error(err))
+		return nil, fmt.Errorf("failed to decode co-borrower: %w", err)
+	}
+	if app.IncomeSources, err = unmarshalIncomeSources(incomeSources); err != nil {
+		logger.Error("Failed to decode income sources", zap.Error(err))
+		return nil, fmt.Errorf("failed to decode income sources: %w", err)
+	}
+
+	app.CreatedAt = createdAt
+	app.UpdatedAt = updatedAt
+
+	logger.Info("Application retrieved successfully", zap.String("application_id", id))
+	return &app, nil
+}
+
+// GetApplicationsByUserID retrieves all applications for a user
+func (r *LoanRepository) GetApplicationsByUserID(ctx context.Context, userID string) ([]*domain.LoanApplication, error) {
+	logger := r.logger.With(
+		zap.String("operation", "get_applications_by_user_id"),
+		zap.String("user_id", userID),
+	)
+
+	query := `
+		SELECT 
+			id, user_id, application_number, loan_amount, loan_purpose, requested_term_months,
+			annual_income, monthly_income, employment_status, monthly_debt_payments,
+			current_state, status, risk_score, workflow_id, co_borrower, application_type, refinanced_application_id,
+			income_sources, created_at, updated_at
+		FROM loan_applications WHERE user_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		logger.Error("Failed to query applications by user ID", zap.Error(err))
+		return nil, fmt.Errorf("failed to query applications: %w", err)
+	}
+	defer rows.Close()
+
+	var applications []*domain.LoanApplication
+	for rows.Next() {
+		var app domain.LoanApplication
+		var coBorrower, incomeSources []byte
+		var createdAt, updatedAt time.Time
+
+		err := rows.Scan(
+			&app.ID, &app.UserID, &app.ApplicationNumber, &app.LoanAmount, &app.LoanPurpose, &app.RequestedTerm,
+			&app.AnnualIncome, &app.MonthlyIncome, &app.EmploymentStatus, &app.MonthlyDebt,
+			&app.CurrentState, &app.Status, &app.RiskScore, &app.WorkflowID, &coBorrower, &app.ApplicationType,
+			&app.RefinancedApplicationID, &incomeSources,
+			&createdAt, &updatedAt,
+		)
+
+		if err != nil {
+			logger.Error("Failed to scan application row", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan application: %w", err)
+		}
+
+		if app.CoBorrower, err = unmarshalCoBorrower(coBorrower); err != nil {
+			logger.Error("Failed to decode co-borrower", zap.Error(err))
+			return nil, fmt.Errorf("failed to decode co-borrower: %w", err)
+		}
+		if app.IncomeSources, err = unmarshalIncomeSources(incomeSources); err != nil {
+			logger.Error("Failed to decode income sources", zap.Error(err))
+			return nil, fmt.Errorf("failed to decode income sources: %w", err)
+		}
+
+		app.CreatedAt = createdAt
+		app.UpdatedAt = updatedAt
+		applications = append(applications, &app)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating over application rows", zap.Error(err))
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	logger.Info("Applications retrieved successfully",
+		zap.String("user_id", userID),
+		zap.Int("count", len(applications)))
+	return applications, nil
+}
+
+// marshalCoBorrower encodes a co-borrower for the JSONB column; no co-borrower is stored as NULL
+func marshalCoBorrower(coBorrower *domain.CoBorrower) (interface{}, error) {
+	if coBorrower == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(coBorrower)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// unmarshalCoBorrower decodes the co_borrower column
+func unmarshalCoBorrower(data []byte) (*domain.CoBorrower, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var coBorrower domain.CoBorrower
+	if err := json.Unmarshal(data, &coBorrower); err != nil {
+		return nil, err
+	}
+	return &coBorrower, nil
+}
+
+// marshalIncomeSources encodes documented income sources for the JSONB column; none are stored as
+// NULL
+func marshalIncomeSources(sources *income.Sources) (interface{}, error) {
+	if sources == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(sources)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// unmarshalIncomeSources decodes the income_sources column
+func unmarshalIncomeSources(data []byte) (*income.Sources, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var sources income.Sources
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return nil, err
+	}
+	return &sources, nil
+}
+
+// applicationSortColumns maps listing sort fields to their columns
+var applicationSortColumns = map[domain.ApplicationSortField]string{
+	domain.ApplicationSortCreatedAt:  "created_at",
+	domain.ApplicationSortUpdatedAt:  "updated_at",
+	domain.ApplicationSortLoanAmount: "loan_amount",
+}
+
+// applicationListFilter builds the WHERE clause shared by ListApplications and CountApplications
+func applicationListFilter(query *domain.ApplicationListQuery) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if query.UserID != "" {
+		add("user_id = $%d", query.UserID)
+	}
+	if query.Status != "" {
+		add("status = $%d", query.Status)
+	}
+	if query.State != "" {
+		add("current_state = $%d", query.State)
+	}
+	if !query.CreatedFrom.IsZero() {
+		add("created_at >= $%d", query.CreatedFrom)
+	}
+	if !query.CreatedTo.IsZero() {
+		// The end date is inclusive, so match everything before the following day
+		add("created_at < $%d", query.CreatedTo.AddDate(0, 0, 1))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// ListApplications returns one page of applications matching the query's filters and ordering
+func (r *LoanRepository) ListApplications(ctx context.Context, query *domain.ApplicationListQuery) ([]*domain.LoanApplication, error) {
+	logger := r.logger.With(
+		zap.String("operation", "list_applications"),
+		zap.String("user_id", query.UserID),
+	)
+
+	where, args := applicationListFilter(query)
+
+	order := "DESC"
+	if query.SortOrder == "asc" {
+		order = "ASC"
+	}
+	sortColumn, ok := applicationSortColumns[query.SortBy]
+	if !ok {
+		sortColumn = "created_at"
+	}
+
+	// id breaks ties so pages stay stable when sort values repeat
+	args = append(args, query.PageSize, query.Offset())
+	sqlQuery := fmt.Sprintf(`
+		SELECT 
+			id, user_id, application_number, loan_amount, loan_purpose, requested_term_months,
+			annual_income, monthly_income, employment_status, monthly_debt_payments,
+			current_state, status, risk_score, workflow_id, co_borrower, application_type, refinanced_application_id,
+			income_sources, created_at, updated_at
+		FROM loan_applications%s
+		ORDER BY %s %s, id %s
+		LIMIT $%d OFFSET $%d`,
+		where, sortColumn, order, order, len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, sqlQuery, args...)
+	if err != nil {
+		logger.Error("Failed to list applications", zap.Error(err))
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+	defer rows.Close()
+
+	applications := make([]*domain.LoanApplication, 0, query.PageSize)
+	for rows.Next() {
+		var app domain.LoanApplication
+		var coBorrower, incomeSources []byte
+		err := rows.Scan(
+			&app.ID, &app.UserID, &app.ApplicationNumber, &app.LoanAmount, &app.LoanPurpose, &app.RequestedTerm,
+			&app.AnnualIncome, &app.MonthlyIncome, &app.EmploymentStatus, &app.MonthlyDebt,
+			&app.CurrentState, &app.Status, &app.RiskScore, &app.WorkflowID, &coBorrower, &app.ApplicationType,
+			&app.RefinancedApplicationID, &incomeSources,
+			&app.CreatedAt, &app.UpdatedAt,
+		)
+		if err != nil {
+			logger.Error("Failed to scan application row", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan application: %w", err)
+		}
+		if app.CoBorrower, err = unmarshalCoBorrower(coBorrower); err != nil {
+			logger.Error("Failed to decode co-borrower", zap.Error(err))
+			return nil, fmt.Errorf("failed to decode co-borrower: %w", err)
+		}
+		if app.IncomeSources, err = unmarshalIncomeSources(incomeSources); err != nil {
+			logger.Error("Failed to decode income sources", zap.Error(err))
+			return nil, fmt.Errorf("failed to decode income sources: %w", err)
+		}
+		applications = append(applications, &app)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating over application rows", zap.Error(err))
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return applications, nil
+}
+
+// CountApplications counts every application matching the query's filters, ignoring the page window
+func (r *LoanRepository) CountApplications(ctx context.Context, query *domain.ApplicationListQuery) (int, error) {
+	where, args := applicationListFilter(query)
+
+	var count int
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM loan_applications"+where, args...).Scan(&count); err != nil {
+		r.logger.Error("Failed to count applications", zap.Error(err))
+		return 0, fmt.Errorf("failed to count applications: %w", err)
+	}
+
+	return count, nil
+}
+
+// UpdateApplication updates an existing loan application
+func (r *LoanRepository) UpdateApplication(ctx context.Context, app *domain.LoanApplication) error {
+	logger := r.logger.With(
+		zap.String("operation", "update_application"),
+		zap.String("application_id", app.ID),
+	)
+
+	query := `
+		UPDATE loan_applications SET 
+			loan_amount = $1, loan_purpose = $2, requested_term_months = $3,
+			annual_income = $4, monthly_income = $5, employment_status = $6, monthly_debt_payments = $7,
+			current_state = $8, status = $9, risk_score = $10, workflow_id = $11, co_borrower = $12,
+			income_sources = $13, updated_at = $14
+		WHERE id = $15`
+
+	coBorrower, err := marshalCoBorrower(app.CoBorrower)
+	if err != nil {
+		logger.Error("Failed to encode co-borrower", zap.Error(err))
+		return fmt.Errorf("failed to encode co-borrower: %w", err)
+	}
+	incomeSources, err := marshalIncomeSources(app.IncomeSources)
+	if err != nil {
+		logger.Error("Failed to encode income sources", zap.Error(err))
+		return fmt.Errorf("failed to encode income sources: %w", err)
+	}
+
+	result, err := r.db.Exec(ctx, query,
+		app.LoanAmount, app.LoanPurpose, app.RequestedTerm,
+		app.AnnualIncome, app.MonthlyIncome, app.EmploymentStatus, app.MonthlyDebt,
+		app.CurrentState, app.Status, app.RiskScore, app.WorkflowID, coBorrower,
+		incomeSources, time.Now().UTC(), app.ID,
+	)
+
+	if err != nil {
+		logger.Error("Failed to update application", zap.Error(err))
+		return fmt.Errorf("failed to update application: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.Error("Failed to get rows affected", zap.Error(err))
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		logger.Warn("No application found to update", zap.String("application_id", app.ID))
+		return fmt.Errorf("application not found: %s", app.ID)
+	}
+
+	logger.Info("Application updated successfully", zap.String("application_id", app.ID))
+	return nil
+}
+
+// DeleteApplication deletes a loan application by ID
+func (r *LoanRepository) DeleteApplication(ctx context.Context, id string) error {
+	logger := r.logger.With(
+		zap.String("operation", "delete_application"),
+		zap.String("application_id", id),
+	)
+
+	query := `DELETE FROM loan_applications WHERE id = $1`
+
+	result, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		logger.Error("Failed to delete application", zap.Error(err))
+		return fmt.Errorf("failed to delete application: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.Error("Failed to get rows affected", zap.Error(err))
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		logger.Warn("No application found to delete", zap.String("application_id", id))
+		return fmt.Errorf("application not found: %s", id)
+	}
+
+	logger.Info("Application deleted successfully", zap.String("application_id", id))
+	return nil
+}
+
+// ReassignApplications moves every application owned by one user to another and returns how many moved
+func (r *LoanRepository) ReassignApplications(ctx context.Context, fromUserID, toUserID string) (int, error) {
+	logger := r.logger.With(
+		zap.String("operation", "reassign_applications"),
+		zap.String("from_user_id", fromUserID),
+		zap.String("to_user_id", toUserID),
+	)
+
+	query := `UPDATE loan_applications SET user_id = $1, updated_at = $2 WHERE user_id = $3`
+
+	result, err := r.db.Exec(ctx, query, toUserID, time.Now().UTC(), fromUserID)
+	if err != nil {
+		logger.Error("Failed to reassign applications", zap.Error(err))
+		return 0, fmt.Errorf("failed to reassign applications: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.Error("Failed to get rows affected", zap.Error(err))
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	logger.Info("Applications reassigned successfully", zap.Int64("count", rowsAffected))
+	return int(rowsAffected), nil
+}
+
+// GetUserLoanActivity counts a user's applications that have not reached a terminal state and
+// finds when their most recent loan was closed
+func (r *LoanRepository) GetUserLoanActivity(ctx context.Context, userID string) (*domain.UserLoanActivity, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE current_state NOT IN ($2, $3, $4, $5, $6)),
+			MAX(updated_at) FILTER (WHERE current_state = $2)
+		FROM loan_applications
+		WHERE user_id = $1`
+
+	activity := &domain.UserLoanActivity{UserID: userID}
+	err := r.db.QueryRow(ctx, query, userID, domain.StateClosed, domain.StateDenied, domain.StateWithdrawn,
+		domain.StateOfferExpired, domain.StateOfferDeclined).Scan(&activity.OpenApplications, &activity.LastClosedAt)
+	if err != nil {
+		r.logger.Error("Failed to get user loan activity", zap.String("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get user loan activity: %w", err)
+	}
+
+	return activity, nil
+}
+
+// statePlaceholders renders states as query placeholders starting after the given number of args
+func statePlaceholders(states []domain.ApplicationState, args []interface{}) (string, []interface{}) {
+	placeholders := make([]string, len(states))
+	for i, state := range states {
+		args = append(args, string(state))
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	return strings.Join(placeholders, ", "), args
+}
+
+// GetApplicationStats aggregates applications created since the given time. Rates are left for
+// the caller to derive from the counts.
+func (r *LoanRepository) GetApplicationStats(ctx context.Context, since time.Time) (*domain.ApplicationStats, error) {
+	logger := r.logger.With(
+		zap.String("operation", "get_application_stats"),
+		zap.Time("since", since),
+	)
+
+	stats := &domain.ApplicationStats{
+		Since:    since,
+		ByStatus: make(map[domain.ApplicationStatus]int),
+		ByState:  make(map[domain.ApplicationState]int),
+	}
+
+	approved, args := statePlaceholders(domain.ApprovedStates, []interface{}{since, string(domain.StateDenied)})
+	summaryQuery := fmt.Sprintf(`
+		SELECT
+			COUNT(*),
+			COALESCE(AVG(loan_amount), 0),
+			COUNT(*) FILTER (WHERE current_state::text IN (%s)),
+			COUNT(*) FILTER (WHERE current_state::text = $2)
+		FROM loan_applications
+		WHERE created_at >= $1`, approved)
+
+	err := r.db.QueryRow(ctx, summaryQuery, args...).Scan(
+		&stats.TotalApplications, &stats.AverageLoanAmount, &stats.ApprovedCount, &stats.DeniedCount,
+	)
+	if err != nil {
+		logger.Error("Failed to aggregate applications", zap.Error(err))
+		return nil, fmt.Errorf("failed to aggregate applications: %w", err)
+	}
+
+	// Applications created without a status are counted in the total but not broken out
+	err = r.countGrouped(ctx, `
+		SELECT status::text, COUNT(*) FROM loan_applications
+		WHERE created_at >= $1 AND COALESCE(status::text, '') <> ''
+		GROUP BY status`, since, func(key string, count int) {
+		stats.ByStatus[domain.ApplicationStatus(key)] = count
+	})
+	if err != nil {
+		logger.Error("Failed to count applications by status", zap.Error(err))
+		return nil, fmt.Errorf("failed to count applications by status: %w", err)
+	}
+
+	err = r.countGrouped(ctx, `
+		SELECT current_state::text, COUNT(*) FROM loan_applications
+		WHERE created_at >= $1
+		GROUP BY current_state`, since, func(key string, count int) {
+		stats.ByState[domain.ApplicationState(key)] = count
+	})
+	if err != nil {
+		logger.Error("Failed to count applications by state", zap.Error(err))
+		return nil, fmt.Errorf("failed to count applications by state: %w", err)
+	}
+
+	// Each application counts once, at the furthest funnel stage it reached by transition or current
+	// state; it has passed every earlier stage as well
+	funnelCases := make([]string, len(domain.FunnelStates))
+	funnelArgs := []interface{}{since}
+	for i, state := range domain.FunnelStates {
+		funnelArgs = append(funnelArgs, string(state))
+		funnelCases[i] = fmt.Sprintf("WHEN $%d THEN %d", len(funnelArgs), i)
+	}
+	funnelQuery := fmt.Sprintf(`
+		SELECT furthest, COUNT(*) FROM (
+			SELECT id, MAX(CASE stage %s END) AS furthest
+			FROM (
+				SELECT a.id, t.to_state::text AS stage
+				FROM loan_applications a
+				JOIN state_transitions t ON t.application_id = a.id
+				WHERE a.created_at >= $1
+				UNION ALL
+				SELECT id, current_state::text FROM loan_applications WHERE created_at >= $1
+			) reached
+			GROUP BY id
+		) furthest_stage
+		WHERE furthest IS NOT NULL
+		GROUP BY furthest`, strings.Join(funnelCases, " "))
+
+	rows, err := r.db.Query(ctx, funnelQuery, funnelArgs...)
+	if err != nil {
+		logger.Error("Failed to compute application funnel", zap.Error(err))
+		return nil, fmt.Errorf("failed to compute application funnel: %w", err)
+	}
+	defer rows.Close()
+
+	furthest := make([]int, len(domain.FunnelStates))
+	for rows.Next() {
+		var stage, count int
+		if err := rows.Scan(&stage, &count); err != nil {
+			logger.Error("Failed to scan funnel row", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan funnel row: %w", err)
+		}
+		furthest[stage] = count
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating over funnel rows", zap.Error(err))
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	stats.Funnel = make([]domain.FunnelStage, len(domain.FunnelStates))
+	reached := 0
+	for i := len(domain.FunnelStates) - 1; i >= 0; i-- {
+		reached += furthest[i]
+		stats.Funnel[i] = domain.FunnelStage{State: domain.FunnelStates[i], Count: reached}
+	}
+
+	return stats, nil
+}
+
+// countGrouped runs a two-column key/count query filtered by a since time
+func (r *LoanRepository) countGrouped(ctx context.Context, query string, since time.Time, add func(key string, count int)) error {
+	rows, err := r.db.Query(ctx, query, since)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			return err
+		}
+		add(key, count)
+	}
+
+	return rows.Err()
+}
+
+const insertOfferQuery = `
+		INSERT INTO loan_offers (
+			id, application_id, offer_amount, interest_rate, term_months,
+			monthly_payment, total_interest, apr, expires_at, status, group_id, parent_offer_id, pricing_audit,
+			refinance_comparison, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+		)`
+
+// offerInsertArgs returns the arguments for insertOfferQuery
+func offerInsertArgs(offer *domain.LoanOffer) ([]interface{}, error) {
+	var pricingAudit interface{}
+	if offer.PricingAudit != nil {
+		data, err := json.Marshal(offer.PricingAudit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode pricing audit: %w", err)
+		}
+		pricingAudit = string(data)
+	}
+
+	var refinanceComparison interface{}
+	if offer.RefinanceComparison != nil {
+		data, err := json.Marshal(offer.RefinanceComparison)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode refinance comparison: %w", err)
+		}
+		refinanceComparison = string(data)
+	}
+
+	return []interface{}{
+		offer.ID, offer.ApplicationID, offer.OfferAmount, offer.InterestRate, offer.TermMonths,
+		offer.MonthlyPayment, offer.TotalInterest, offer.APR, offer.ExpiresAt, offer.Status, offer.GroupID,
+		offer.ParentOfferID, pricingAudit, refinanceComparison, time.Now().UTC(),
+	}, nil
+}
+
+const offerColumns = `
+			id, application_id, offer_amount, interest_rate, term_months,
+			monthly_payment, total_interest, apr, expires_at, status, group_id, parent_offer_id, pricing_audit,
+			refinance_comparison, created_at`
+
+// scanOffer scans a row selected with offerColumns
+func scanOffer(row interface{ Scan(...interface{}) error }) (*domain.LoanOffer, error) {
+	var offer domain.LoanOffer
+	var groupID, parentOfferID sql.NullString
+	var pricingAudit, refinanceComparison []byte
+
+	err := row.Scan(
+		&offer.ID, &offer.ApplicationID, &offer.OfferAmount, &offer.InterestRate, &offer.TermMonths,
+		&offer.MonthlyPayment, &offer.TotalInterest, &offer.APR, &offer.ExpiresAt, &offer.Status,
+		&groupID, &parentOfferID, &pricingAudit, &refinanceComparison, &offer.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if groupID.Valid {
+		offer.GroupID = &groupID.String
+	}
+	if parentOfferID.Valid {
+		offer.ParentOfferID = &parentOfferID.String
+	}
+	if len(pricingAudit) > 0 {
+		offer.PricingAudit = &domain.PricingAudit{}
+		if err := json.Unmarshal(pricingAudit, offer.PricingAudit); err != nil {
+			return nil, fmt.Errorf("failed to decode pricing audit: %w", err)
+		}
+	}
+	if len(refinanceComparison) > 0 {
+		offer.RefinanceComparison = &domain.RefinanceComparison{}
+		if err := json.Unmarshal(refinanceComparison, offer.RefinanceComparison); err != nil {
+			return nil, fmt.Errorf("failed to decode refinance comparison: %w", err)
+		}
+	}
+
+	return &offer, nil
+}
+
+// CreateOffer creates a new loan offer
+func (r *LoanRepository) CreateOffer(ctx context.Context, offer *domain.LoanOffer) error {
+	logger := r.logger.With(
+		zap.String("operation", "create_offer"),
+		zap.String("offer_id", offer.ID),
+		zap.String("application_id", offer.ApplicationID),
+	)
+
+	args, err := offerInsertArgs(offer)
+	if err != nil {
+		logger.Error("Failed to encode offer", zap.Error(err))
+		return err
+	}
+
+	if _, err := r.db.Exec(ctx, insertOfferQuery, args...); err != nil {
+		logger.Error("Failed to create offer", zap.Error(err))
+		return fmt.Errorf("failed to create offer: %w", err)
+	}
+
+	logger.Info("Offer created successfully", zap.String("offer_id", offer.ID))
+	return nil
+}
+
+// GetOfferByApplicationID retrieves a loan offer by application ID
+func (r *LoanRepository) GetOfferByApplicationID(ctx context.Context, applicationID string) (*domain.LoanOffer, error) {
+	logger := r.logger.With(
+		zap.String("operation", "get_offer_by_application_id"),
+		zap.String("application_id", applicationID),
+	)
+
+	query := `SELECT ` + offerColumns + `
+		FROM loan_offers WHERE application_id = $1 ORDER BY created_at DESC LIMIT 1`
+
+	offer, err := scanOffer(r.db.QueryRow(ctx, query, applicationID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			logger.Warn("Offer not found", zap.String("application_id", applicationID))
+			return nil, fmt.Errorf("offer not found: %s", applicationID)
+		}
+		logger.Error("Failed to get offer by application ID", zap.Error(err))
+		return nil, fmt.Errorf("failed to get offer: %w", err)
+	}
+
+	logger.Info("Offer retrieved successfully", zap.String("offer_id", offer.ID))
+	return offer, nil
+}
+
+// GetOfferByID retrieves a loan offer by ID
+func (r *LoanRepository) GetOfferByID(ctx context.Context, id string) (*domain.LoanOffer, error) {
+	logger := r.logger.With(
+		zap.String("operation", "get_offer_by_id"),
+		zap.String("offer_id", id),
+	)
+
+	offer, err := scanOffer(r.db.QueryRow(ctx, `SELECT `+offerColumns+` FROM loan_offers WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			logger.Warn("Offer not found")
+			return nil, fmt.Errorf("offer not found: %s", id)
+		}
+		logger.Error("Failed to get offer by ID", zap.Error(err))
+		return nil, fmt.Errorf("failed to get offer: %w", err)
+	}
+
+	return offer, nil
+}
+
+// ListOffers retrieves every offer made for an application, oldest first
+func (r *LoanRepository) ListOffers(ctx context.Context, applicationID string) ([]*domain.LoanOffer, error) {
+	logger := r.logger.With(
+		zap.String("operation", "list_offers"),
+		zap.String("application_id", applicationID),
+	)
+
+	rows, err := r.db.Query(ctx, `SELECT `+offerColumns+`
+		FROM loan_offers WHERE application_id = $1
+		ORDER BY created_at, id`, applicationID)
+	if err != nil {
+		logger.Error("Failed to list offers", zap.Error(err))
+		return nil, fmt.Errorf("failed to list offers: %w", err)
+	}
+	defer rows.Close()
+
+	offers := []*domain.LoanOffer{}
+	for rows.Next() {
+		offer, err := scanOffer(rows)
+		if err != nil {
+			logger.Error("Failed to scan offer row", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan offer: %w", err)
+		}
+		offers = append(offers, offer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return offers, nil
+}
+
+// ListCounterOffers retrieves the counter offers made for an application, newest first
+func (r *LoanRepository) ListCounterOffers(ctx context.Context, applicationID string) ([]*domain.LoanOffer, error) {
+	logger := r.logger.With(
+		zap.String("operation", "list_counter_offers"),
+		zap.String("application_id", applicationID),
+	)
+
+	rows, err := r.db.Query(ctx, `SELECT `+offerColumns+`
+		FROM loan_offers WHERE application_id = $1 AND parent_offer_id IS NOT NULL
+		ORDER BY created_at DESC`, applicationID)
+	if err != nil {
+		logger.Error("Failed to list counter offers", zap.Error(err))
+		return nil, fmt.Errorf("failed to list counter offers: %w", err)
+	}
+	defer rows.Close()
+
+	offers := []*domain.LoanOffer{}
+	for rows.Next() {
+		offer, err := scanOffer(rows)
+		if err != nil {
+			logger.Error("Failed to scan offer row", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan offer: %w", err)
+		}
+		offers = append(offers, offer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return offers, nil
+}
+
+// AcceptCounterOffer marks a pending counter offer selected. Its parent's offer group, if still
+// open, is closed with the counter offer recorded as the selection and its other offers expired.
+func (r *LoanRepository) AcceptCounterOffer(ctx context.Context, offer *domain.LoanOffer) error {
+	logger := r.logger.With(
+		zap.String("operation", "accept_counter_offer"),
+		zap.String("offer_id", offer.ID),
+	)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE loan_offers SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4 AND expires_at > $2`,
+		domain.OfferStatusSelected, now, offer.ID, domain.OfferStatusPending,
+	)
+	if err != nil {
+		logger.Error("Failed to accept counter offer", zap.Error(err))
+		return fmt.Errorf("failed to accept counter offer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		logger.Warn("Counter offer is not open")
+		return fmt.Errorf("counter offer not open: %s", offer.ID)
+	}
+
+	if offer.ParentOfferID != nil {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE loan_offers SET status = $1, updated_at = $2
+			WHERE group_id = (SELECT group_id FROM loan_offers WHERE id = $3) AND status = $4`,
+			domain.OfferStatusExpired, now, *offer.ParentOfferID, domain.OfferStatusPending,
+		); err != nil {
+			logger.Error("Failed to expire parent group offers", zap.Error(err))
+			return fmt.Errorf("failed to expire parent group offers: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE offer_groups SET status = $1, selected_offer_id = $2, updated_at = $3
+			WHERE id = (SELECT group_id FROM loan_offers WHERE id = $4) AND status = $5`,
+			domain.OfferGroupSelected, offer.ID, now, *offer.ParentOfferID, domain.OfferGroupOpen,
+		); err != nil {
+			logger.Error("Failed to close parent offer group", zap.Error(err))
+			return fmt.Errorf("failed to close parent offer group: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit counter offer acceptance", zap.Error(err))
+		return fmt.Errorf("failed to commit counter offer acceptance: %w", err)
+	}
+
+	logger.Info("Counter offer accepted successfully")
+	return nil
+}
+
+// WithdrawApplication moves an application to the withdrawn state, records the withdrawal and
+// expires its open offers in one transaction. The update only applies while the application is
+// still in the state the withdrawal was taken from.
+func (r *LoanRepository) WithdrawApplication(ctx context.Context, app *domain.LoanApplication, withdrawal *domain.ApplicationWithdrawal) error {
+	logger := r.logger.With(
+		zap.String("operation", "withdraw_application"),
+		zap.String("application_id", app.ID),
+	)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE loan_applications SET current_state = $1, status = $2, updated_at = $3
+		WHERE id = $4 AND current_state = $5`,
+		app.CurrentState, app.Status, app.UpdatedAt, app.ID, withdrawal.FromState,
+	)
+	if err != nil {
+		logger.Error("Failed to withdraw application", zap.Error(err))
+		return fmt.Errorf("failed to withdraw application: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		logger.Warn("Application state changed before withdrawal")
+		return fmt.Errorf("application state changed: %s", app.ID)
+	}
+
+	var comment interface{}
+	if withdrawal.Comment != "" {
+		comment = withdrawal.Comment
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO application_withdrawals (
+			id, application_id, from_state, reason, comment, withdrawn_by, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)`,
+		withdrawal.ID, withdrawal.ApplicationID, withdrawal.FromState, withdrawal.Reason, comment,
+		withdrawal.WithdrawnBy, withdrawal.CreatedAt,
+	); err != nil {
+		logger.Error("Failed to record withdrawal", zap.Error(err))
+		return fmt.Errorf("failed to record withdrawal: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE loan_offers SET status = $1, updated_at = $2
+		WHERE application_id = $3 AND status IN ($4, $5)`,
+		domain.OfferStatusExpired, withdrawal.CreatedAt, app.ID, domain.OfferStatusPending, domain.OfferStatusCountered,
+	); err != nil {
+		logger.Error("Failed to expire offers", zap.Error(err))
+		return fmt.Errorf("failed to expire offers: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE offer_groups SET status = $1, updated_at = $2 WHERE application_id = $3 AND status = $4`,
+		domain.OfferGroupExpired, withdrawal.CreatedAt, app.ID, domain.OfferGroupOpen,
+	); err != nil {
+		logger.Error("Failed to expire offer groups", zap.Error(err))
+		return fmt.Errorf("failed to expire offer groups: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit withdrawal", zap.Error(err))
+		return fmt.Errorf("failed to commit withdrawal: %w", err)
+	}
+
+	logger.Info("Application withdrawn successfully", zap.String("reason", string(withdrawal.Reason)))
+	return nil
+}
+
+// TransitionApplicationState moves an application to the transition's target state and records the
+// transition in one transaction. The update only applies while the application is still in the
+// transition's from state.
+func (r *LoanRepository) TransitionApplicationState(ctx context.Context, app *domain.LoanApplication, transition *domain.StateTransition) error {
+	logger := r.logger.With(
+		zap.String("operation", "transition_application_state"),
+		zap.String("application_id", app.ID),
+		zap.String("to_state", string(transition.ToState)),
+	)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE loan_applications SET current_state = $1, status = $2, updated_at = $3
+		WHERE id = $4 AND current_state = $5`,
+		app.CurrentState, app.Status, app.UpdatedAt, app.ID, *transition.FromState,
+	)
+	if err != nil {
+		logger.Error("Failed to update application state", zap.Error(err))
+		return fmt.Errorf("failed to update application state: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		logger.Warn("Application state changed before transition")
+		return fmt.Errorf("application state changed: %s", app.ID)
+	}
+
+	triggeredBy := "system"
+	if transition.UserID != nil {
+		triggeredBy = *transition.UserID
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO state_transitions (
+			id, application_id, from_state, to_state, transition_reason, triggered_by, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)`,
+		transition.ID, transition.ApplicationID, *transition.FromState, transition.ToState,
+		transition.TransitionReason, triggeredBy, transition.CreatedAt,
+	); err != nil {
+		logger.Error("Failed to record state transition", zap.Error(err))
+		return fmt.Errorf("failed to record state transition: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit state transition", zap.Error(err))
+		return fmt.Errorf("failed to commit state transition: %w", err)
+	}
+
+	logger.Info("Application state transitioned successfully", zap.String("transition_id", transition.ID))
+	return nil
+}
+
+// CreateNote stores a note posted on an application
+func (r *LoanRepository) CreateNote(ctx context.Context, note *domain.ApplicationNote) error {
+	logger := r.logger.With(
+		zap.String("operation", "create_note"),
+		zap.String("note_id", note.ID),
+		zap.String("application_id", note.ApplicationID),
+	)
+
+	mentions, err := marshalOptionalJSON(len(note.Mentions) > 0, note.Mentions)
+	if err != nil {
+		return fmt.Errorf("failed to encode mentions: %w", err)
+	}
+	attachments, err := marshalOptionalJSON(len(note.Attachments) > 0, note.Attachments)
+	if err != nil {
+		return fmt.Errorf("failed to encode attachments: %w", err)
+	}
+
+	_, err = r.db.Exec(ctx, `
+		INSERT INTO application_notes (
+			id, application_id, author_id, author_role, visibility, body, mentions, attachments, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)`,
+		note.ID, note.ApplicationID, note.AuthorID, note.AuthorRole, note.Visibility, note.Body,
+		mentions, attachments, note.CreatedAt,
+	)
+	if err != nil {
+		logger.Error("Failed to create note", zap.Error(err))
+		return fmt.Errorf("failed to create note: %w", err)
+	}
+
+	logger.Info("Note created successfully", zap.String("visibility", string(note.Visibility)))
+	return nil
+}
+
+// ListNotes retrieves an application's notes, oldest first. With borrowerVisibleOnly set, internal
+// notes are left out.
+func (r *LoanRepository) ListNotes(ctx context.Context, applicationID string, borrowerVisibleOnly bool) ([]*domain.ApplicationNote, error) {
+	logger := r.logger.With(
+		zap.String("operation", "list_notes"),
+		zap.String("application_id", applicationID),
+	)
+
+	query := `
+		SELECT id, application_id, author_id, author_role, visibility, body, mentions, attachments, created_at
+		FROM application_notes WHERE application_id = $1`
+	args := []interface{}{applicationID}
+	if borrowerVisibleOnly {
+		query += ` AND visibility = $2`
+		args = append(args, domain.NoteVisibilityBorrower)
+	}
+	query += ` ORDER BY created_at, id`
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		logger.Error("Failed to list notes", zap.Error(err))
+		return nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+	defer rows.Close()
+
+	var notes []*domain.ApplicationNote
+	for rows.Next() {
+		var note domain.ApplicationNote
+		var mentions, attachments []byte
+		if err := rows.Scan(
+			&note.ID, &note.ApplicationID, &note.AuthorID, &note.AuthorRole, &note.Visibility, &note.Body,
+			&mentions, &attachments, &note.CreatedAt,
+		); err != nil {
+			logger.Error("Failed to scan note row", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan note: %w", err)
+		}
+
+		if len(mentions) > 0 {
+			if err := json.Unmarshal(mentions, &note.Mentions); err != nil {
+				return nil, fmt.Errorf("failed to decode mentions: %w", err)
+			}
+		}
+		if len(attachments) > 0 {
+			if err := json.Unmarshal(attachments, &note.Attachments); err != nil {
+				return nil, fmt.Errorf("failed to decode attachments: %w", err)
+			}
+		}
+		notes = append(notes, &note)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return notes, nil
 }
 
-// GetApplicationByID retrieves a loan application by ID
-func (r *LoanRepository) GetApplicationByID(ctx context.Context, id string) (*domain.LoanApplication, error) {
-	logger := r.logger.With(
-		zap.String("operation", "get_application_by_id"),
-		zap.String("application_id", id),
-	)
+// marshalOptionalJSON encodes a value for a JSONB column, storing NULL when it is absent
+func marshalOptionalJSON(present bool, value interface{}) (interface{}, error) {
+	if !present {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
 
-	query := `
-		SELECT 
-			id, user_id, application_number, loan_amount, loan_purpose, requested_term_months,
-			annual_income, monthly_income, employment_status, monthly_debt_payments,
-			current_state, status, risk_score, workflow_id, created_at, updated_at
-		FROM loan_applications WHERE id = $1`
+// ExpireOffers marks pending and countered offers, and open offer groups, whose expiry has passed
+// as of the given time expired, returning the number of offers expired
+func (r *LoanRepository) ExpireOffers(ctx context.Context, asOf time.Time) (int, error) {
+	logger := r.logger.With(zap.String("operation", "expire_offers"))
 
-	var app domain.LoanApplication
-	var createdAt, updatedAt time.Time
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&app.ID, &app.UserID, &app.ApplicationNumber, &app.LoanAmount, &app.LoanPurpose, &app.RequestedTerm,
-		&app.AnnualIncome, &app.MonthlyIncome, &app.EmploymentStatus, &app.MonthlyDebt,
-		&app.CurrentState, &app.Status, &app.RiskScore, &app.WorkflowID,
-		&createdAt, &updatedAt,
+	result, err := tx.ExecContext(ctx, `
+		UPDATE loan_offers SET status = $1, updated_at = $2
+		WHERE status IN ($3, $4) AND expires_at <= $2`,
+		domain.OfferStatusExpired, asOf, domain.OfferStatusPending, domain.OfferStatusCountered,
 	)
+	if err != nil {
+		logger.Error("Failed to expire offers", zap.Error(err))
+		return 0, fmt.Errorf("failed to expire offers: %w", err)
+	}
 
+	expired, err := result.RowsAffected()
 	if err != nil {
-		if err == sql.ErrNoRows {
-			logger.Warn("Application not found", zap.String("application_id", id))
-			return nil, fmt.Errorf("application not found: %s", id)
-		}
-		logger.Error("Failed to get application by ID", zap.Error(err))
-		return nil, fmt.Errorf("failed to get application: %w", err)
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
-	app.CreatedAt = createdAt
-	app.UpdatedAt = updatedAt
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE offer_groups SET status = $1, updated_at = $2 WHERE status = $3 AND expires_at <= $2`,
+		domain.OfferGroupExpired, asOf, domain.OfferGroupOpen,
+	); err != nil {
+		logger.Error("Failed to expire offer groups", zap.Error(err))
+		return 0, fmt.Errorf("failed to expire offer groups: %w", err)
+	}
 
-	logger.Info("Application retrieved successfully", zap.String("application_id", id))
-	return &app, nil
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit offer expiry", zap.Error(err))
+		return 0, fmt.Errorf("failed to commit offer expiry: %w", err)
+	}
+
+	return int(expired), nil
 }
 
-// GetApplicationsByUserID retrieves all applications for a user
-func (r *LoanRepository) GetApplicationsByUserID(ctx context.Context, userID string) ([]*domain.LoanApplication, error) {
+// VoidOffers marks an application's offers and offer groups created at or after since that are
+// still pending, selected or countered voided, returning the IDs of the offers voided
+func (r *LoanRepository) VoidOffers(ctx context.Context, applicationID string, since time.Time) ([]string, error) {
 	logger := r.logger.With(
-		zap.String("operation", "get_applications_by_user_id"),
-		zap.String("user_id", userID),
+		zap.String("operation", "void_offers"),
+		zap.String("application_id", applicationID),
 	)
 
-	query := `
-		SELECT 
-			id, user_id, application_number, loan_amount, loan_purpose, requested_term_months,
-			annual_income, monthly_income, employment_status, monthly_debt_payments,
-			current_state, status, risk_score, workflow_id, created_at, updated_at
-		FROM loan_applications WHERE user_id = $1 ORDER BY created_at DESC`
-
-	rows, err := r.db.Query(ctx, query, userID)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		logger.Error("Failed to query applications by user ID", zap.Error(err))
-		return nil, fmt.Errorf("failed to query applications: %w", err)
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE loan_offers SET status = $1, updated_at = $2
+		WHERE application_id = $3 AND created_at >= $4 AND status IN ($5, $6, $7)
+		RETURNING id`,
+		domain.OfferStatusVoided, now, applicationID, since,
+		domain.OfferStatusPending, domain.OfferStatusSelected, domain.OfferStatusCountered,
+	)
+	if err != nil {
+		logger.Error("Failed to void offers", zap.Error(err))
+		return nil, fmt.Errorf("failed to void offers: %w", err)
 	}
-	defer rows.Close()
 
-	var applications []*domain.LoanApplication
+	var voided []string
 	for rows.Next() {
-		var app domain.LoanApplication
-		var createdAt, updatedAt time.Time
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan voided offer: %w", err)
+		}
+		voided = append(voided, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
 
-		err := rows.Scan(
-			&app.ID, &app.UserID, &app.ApplicationNumber, &app.LoanAmount, &app.LoanPurpose, &app.RequestedTerm,
-			&app.AnnualIncome, &app.MonthlyIncome, &app.EmploymentStatus, &app.MonthlyDebt,
-			&app.CurrentState, &app.Status, &app.RiskScore, &app.WorkflowID,
-			&createdAt, &updatedAt,
-		)
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE offer_groups SET status = $1, updated_at = $2
+		WHERE application_id = $3 AND created_at >= $4 AND status IN ($5, $6)`,
+		domain.OfferGroupVoided, now, applicationID, since, domain.OfferGroupOpen, domain.OfferGroupSelected,
+	); err != nil {
+		logger.Error("Failed to void offer groups", zap.Error(err))
+		return nil, fmt.Errorf("failed to void offer groups: %w", err)
+	}
 
-		if err != nil {
-			logger.Error("Failed to scan application row", zap.Error(err))
-			return nil, fmt.Errorf("failed to scan application: %w", err)
-		}
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit offer voiding", zap.Error(err))
+		return nil, fmt.Errorf("failed to commit offer voiding: %w", err)
+	}
 
-		app.CreatedAt = createdAt
-		app.UpdatedAt = updatedAt
-		applications = append(applications, &app)
+	return voided, nil
+}
+
+// ListApplicationsWithLapsedOffers lists approved applications that received offers but have none
+// left open or selected, in ID order after afterID
+func (r *LoanRepository) ListApplicationsWithLapsedOffers(ctx context.Context, afterID string, limit int) ([]domain.LapsedOffers, error) {
+	logger := r.logger.With(zap.String("operation", "list_applications_with_lapsed_offers"))
+
+	// Voided offers were never put to the borrower, so they don't count towards declining them all
+	rows, err := r.db.Query(ctx, `
+		SELECT a.id,
+			COALESCE((
+				SELECT bool_and(o.status = $6) FROM loan_offers o
+				WHERE o.application_id = a.id AND o.status <> $7
+			), FALSE)
+		FROM loan_applications a
+		WHERE a.current_state = $1 AND a.id::text > $2
+			AND EXISTS (SELECT 1 FROM loan_offers o WHERE o.application_id = a.id)
+			AND NOT EXISTS (
+				SELECT 1 FROM loan_offers o WHERE o.application_id = a.id AND o.status IN ($3, $4, $5)
+			)
+		ORDER BY a.id::text
+		LIMIT $8`,
+		domain.StateApproved, afterID, domain.OfferStatusPending, domain.OfferStatusCountered,
+		domain.OfferStatusSelected, domain.OfferStatusDeclined, domain.OfferStatusVoided, limit,
+	)
+	if err != nil {
+		logger.Error("Failed to list applications with lapsed offers", zap.Error(err))
+		return nil, fmt.Errorf("failed to list applications with lapsed offers: %w", err)
+	}
+	defer rows.Close()
+
+	var lapsed []domain.LapsedOffers
+	for rows.Next() {
+		var entry domain.LapsedOffers
+		if err := rows.Scan(&entry.ApplicationID, &entry.AllDeclined); err != nil {
+			return nil, fmt.Errorf("failed to scan application ID: %w", err)
+		}
+		lapsed = append(lapsed, entry)
 	}
 
 	if err := rows.Err(); err != nil {
-		logger.Error("Error iterating over application rows", zap.Error(err))
 		return nil, fmt.Errorf("error iterating over rows: %w", err)
 	}
 
-	logger.Info("Applications retrieved successfully",
-		zap.String("user_id", userID),
-		zap.Int("count", len(applications)))
-	return applications, nil
+	return lapsed, nil
 }
 
-// UpdateApplication updates an existing loan application
-func (r *LoanRepository) UpdateApplication(ctx context.Context, app *domain.LoanApplication) error {
+// CreateNegotiation appends an entry to an application's offer negotiation thread
+func (r *LoanRepository) CreateNegotiation(ctx context.Context, negotiation *domain.OfferNegotiation) error {
 	logger := r.logger.With(
-		zap.String("operation", "update_application"),
-		zap.String("application_id", app.ID),
+		zap.String("operation", "create_negotiation"),
+		zap.String("negotiation_id", negotiation.ID),
+		zap.String("offer_id", negotiation.OfferID),
 	)
 
-	query := `
-		UPDATE loan_applications SET 
-			loan_amount = $1, loan_purpose = $2, requested_term_months = $3,
-			annual_income = $4, monthly_income = $5, employment_status = $6, monthly_debt_payments = $7,
-			current_state = $8, status = $9, risk_score = $10, workflow_id = $11, updated_at = $12
-		WHERE id = $13`
+	var workflowID interface{}
+	if negotiation.WorkflowID != "" {
+		workflowID = negotiation.WorkflowID
+	}
 
-	result, err := r.db.Exec(ctx, query,
-		app.LoanAmount, app.LoanPurpose, app.RequestedTerm,
-		app.AnnualIncome, app.MonthlyIncome, app.EmploymentStatus, app.MonthlyDebt,
-		app.CurrentState, app.Status, app.RiskScore, app.WorkflowID,
-		time.Now().UTC(), app.ID,
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO offer_negotiations (
+			id, application_id, offer_id, action, actor, reason,
+			requested_amount, requested_term_months, counter_offer_id, workflow_id, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+		)`,
+		negotiation.ID, negotiation.ApplicationID, negotiation.OfferID, negotiation.Action, negotiation.Actor,
+		negotiation.Reason, negotiation.RequestedAmount, negotiation.RequestedTermMonths, negotiation.CounterOfferID,
+		workflowID, negotiation.CreatedAt,
 	)
-
 	if err != nil {
-		logger.Error("Failed to update application", zap.Error(err))
-		return fmt.Errorf("failed to update application: %w", err)
+		logger.Error("Failed to create negotiation entry", zap.Error(err))
+		return fmt.Errorf("failed to create negotiation entry: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	logger.Info("Negotiation entry created successfully", zap.String("action", string(negotiation.Action)))
+	return nil
+}
+
+// ListNegotiations retrieves an application's offer negotiation thread, oldest first
+func (r *LoanRepository) ListNegotiations(ctx context.Context, applicationID string) ([]*domain.OfferNegotiation, error) {
+	logger := r.logger.With(
+		zap.String("operation", "list_negotiations"),
+		zap.String("application_id", applicationID),
+	)
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, application_id, offer_id, action, actor, reason,
+			requested_amount, requested_term_months, counter_offer_id, workflow_id, created_at
+		FROM offer_negotiations WHERE application_id = $1 ORDER BY created_at, id`, applicationID)
 	if err != nil {
-		logger.Error("Failed to get rows affected", zap.Error(err))
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		logger.Error("Failed to list negotiation entries", zap.Error(err))
+		return nil, fmt.Errorf("failed to list negotiation entries: %w", err)
 	}
+	defer rows.Close()
 
-	if rowsAffected == 0 {
-		logger.Warn("No application found to update", zap.String("application_id", app.ID))
-		return fmt.Errorf("application not found: %s", app.ID)
+	negotiations := []*domain.OfferNegotiation{}
+	for rows.Next() {
+		var negotiation domain.OfferNegotiation
+		var reason, counterOfferID, workflowID sql.NullString
+		var requestedAmount sql.NullFloat64
+		var requestedTermMonths sql.NullInt64
+		if err := rows.Scan(
+			&negotiation.ID, &negotiation.ApplicationID, &negotiation.OfferID, &negotiation.Action, &negotiation.Actor,
+			&reason, &requestedAmount, &requestedTermMonths, &counterOfferID, &workflowID, &negotiation.CreatedAt,
+		); err != nil {
+			logger.Error("Failed to scan negotiation row", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan negotiation entry: %w", err)
+		}
+
+		negotiation.Reason = reason.String
+		negotiation.WorkflowID = workflowID.String
+		if requestedAmount.Valid {
+			negotiation.RequestedAmount = &requestedAmount.Float64
+		}
+		if requestedTermMonths.Valid {
+			termMonths := int(requestedTermMonths.Int64)
+			negotiation.RequestedTermMonths = &termMonths
+		}
+		if counterOfferID.Valid {
+			negotiation.CounterOfferID = &counterOfferID.String
+		}
+		negotiations = append(negotiations, &negotiation)
 	}
 
-	logger.Info("Application updated successfully", zap.String("application_id", app.ID))
-	return nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return negotiations, nil
 }
 
-// DeleteApplication deletes a loan application by ID
-func (r *LoanRepository) DeleteApplication(ctx context.Context, id string) error {
+// CreateOfferGroup stores an offer group and its offers in one transaction, expiring the
+// application's earlier open groups and their pending offers
+func (r *LoanRepository) CreateOfferGroup(ctx context.Context, group *domain.OfferGroup) error {
 	logger := r.logger.With(
-		zap.String("operation", "delete_application"),
-		zap.String("application_id", id),
+		zap.String("operation", "create_offer_group"),
+		zap.String("group_id", group.ID),
+		zap.String("application_id", group.ApplicationID),
 	)
 
-	query := `DELETE FROM loan_applications WHERE id = $1`
-
-	result, err := r.db.Exec(ctx, query, id)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		logger.Error("Failed to delete application", zap.Error(err))
-		return fmt.Errorf("failed to delete application: %w", err)
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE loan_offers SET status = $1, updated_at = $2
+		WHERE group_id IN (SELECT id FROM offer_groups WHERE application_id = $3 AND status = $4)
+			AND status = $5`,
+		domain.OfferStatusExpired, now, group.ApplicationID, domain.OfferGroupOpen, domain.OfferStatusPending,
+	); err != nil {
+		logger.Error("Failed to expire earlier offers", zap.Error(err))
+		return fmt.Errorf("failed to expire earlier offers: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		logger.Error("Failed to get rows affected", zap.Error(err))
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE offer_groups SET status = $1, updated_at = $2 WHERE application_id = $3 AND status = $4`,
+		domain.OfferGroupExpired, now, group.ApplicationID, domain.OfferGroupOpen,
+	); err != nil {
+		logger.Error("Failed to expire earlier offer groups", zap.Error(err))
+		return fmt.Errorf("failed to expire earlier offer groups: %w", err)
 	}
 
-	if rowsAffected == 0 {
-		logger.Warn("No application found to delete", zap.String("application_id", id))
-		return fmt.Errorf("application not found: %s", id)
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO offer_groups (id, application_id, status, selected_offer_id, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		group.ID, group.ApplicationID, group.Status, group.SelectedOfferID, group.ExpiresAt, group.CreatedAt, group.UpdatedAt,
+	); err != nil {
+		logger.Error("Failed to create offer group", zap.Error(err))
+		return fmt.Errorf("failed to create offer group: %w", err)
 	}
 
-	logger.Info("Application deleted successfully", zap.String("application_id", id))
+	for _, offer := range group.Offers {
+		args, err := offerInsertArgs(offer)
+		if err != nil {
+			logger.Error("Failed to encode offer", zap.Error(err), zap.String("offer_id", offer.ID))
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, insertOfferQuery, args...); err != nil {
+			logger.Error("Failed to create offer", zap.Error(err), zap.String("offer_id", offer.ID))
+			return fmt.Errorf("failed to create offer: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit offer group", zap.Error(err))
+		return fmt.Errorf("failed to commit offer group: %w", err)
+	}
+
+	logger.Info("Offer group created successfully", zap.Int("offer_count", len(group.Offers)))
 	return nil
 }
 
-// CreateOffer creates a new loan offer
-func (r *LoanRepository) CreateOffer(ctx context.Context, offer *domain.LoanOffer) error {
+// ListOfferGroups retrieves an application's offer groups with their offers, newest first
+func (r *LoanRepository) ListOfferGroups(ctx context.Context, applicationID string) ([]*domain.OfferGroup, error) {
 	logger := r.logger.With(
-		zap.String("operation", "create_offer"),
-		zap.String("offer_id", offer.ID),
-		zap.String("application_id", offer.ApplicationID),
+		zap.String("operation", "list_offer_groups"),
+		zap.String("application_id", applicationID),
 	)
 
-	query := `
-		INSERT INTO loan_offers (
-			id, application_id, offer_amount, interest_rate, term_months,
-			monthly_payment, total_interest, apr, expires_at, status, created_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
-		)`
+	rows, err := r.db.Query(ctx, `
+		SELECT id, application_id, status, selected_offer_id, expires_at, created_at, updated_at
+		FROM offer_groups WHERE application_id = $1 ORDER BY created_at DESC`, applicationID)
+	if err != nil {
+		logger.Error("Failed to list offer groups", zap.Error(err))
+		return nil, fmt.Errorf("failed to list offer groups: %w", err)
+	}
+	defer rows.Close()
 
-	_, err := r.db.Exec(ctx, query,
-		offer.ID, offer.ApplicationID, offer.OfferAmount, offer.InterestRate, offer.TermMonths,
-		offer.MonthlyPayment, offer.TotalInterest, offer.APR, offer.ExpiresAt, offer.Status,
-		time.Now().UTC(),
-	)
+	groups := []*domain.OfferGroup{}
+	byID := make(map[string]*domain.OfferGroup)
+	for rows.Next() {
+		var group domain.OfferGroup
+		var selectedOfferID sql.NullString
+		if err := rows.Scan(
+			&group.ID, &group.ApplicationID, &group.Status, &selectedOfferID,
+			&group.ExpiresAt, &group.CreatedAt, &group.UpdatedAt,
+		); err != nil {
+			logger.Error("Failed to scan offer group row", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan offer group: %w", err)
+		}
+		if selectedOfferID.Valid {
+			group.SelectedOfferID = &selectedOfferID.String
+		}
+		group.Offers = []*domain.LoanOffer{}
+		groups = append(groups, &group)
+		byID[group.ID] = &group
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	if len(groups) == 0 {
+		return groups, nil
+	}
 
+	offerRows, err := r.db.Query(ctx, `SELECT `+offerColumns+`
+		FROM loan_offers WHERE application_id = $1 AND group_id IS NOT NULL
+		ORDER BY offer_amount DESC, term_months`, applicationID)
 	if err != nil {
-		logger.Error("Failed to create offer", zap.Error(err))
-		return fmt.Errorf("failed to create offer: %w", err)
+		logger.Error("Failed to list grouped offers", zap.Error(err))
+		return nil, fmt.Errorf("failed to list offers: %w", err)
 	}
+	defer offerRows.Close()
 
-	logger.Info("Offer created successfully", zap.String("offer_id", offer.ID))
-	return nil
+	for offerRows.Next() {
+		offer, err := scanOffer(offerRows)
+		if err != nil {
+			logger.Error("Failed to scan offer row", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan offer: %w", err)
+		}
+		if group, ok := byID[*offer.GroupID]; ok {
+			group.Offers = append(group.Offers, offer)
+		}
+	}
+	if err := offerRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return groups, nil
 }
 
-// GetOfferByApplicationID retrieves a loan offer by application ID
-func (r *LoanRepository) GetOfferByApplicationID(ctx context.Context, applicationID string) (*domain.LoanOffer, error) {
+// SelectOffer marks one offer of an open group selected and the group's other pending offers
+// expired, in one transaction
+func (r *LoanRepository) SelectOffer(ctx context.Context, groupID, offerID string) error {
 	logger := r.logger.With(
-		zap.String("operation", "get_offer_by_application_id"),
-		zap.String("application_id", applicationID),
+		zap.String("operation", "select_offer"),
+		zap.String("group_id", groupID),
+		zap.String("offer_id", offerID),
 	)
 
-	query := `
-		SELECT 
-			id, application_id, offer_amount, interest_rate, term_months,
-			monthly_payment, total_interest, apr, expires_at, status, created_at, updated_at
-		FROM loan_offers WHERE application_id = $1 ORDER BY created_at DESC LIMIT 1`
-
-	var offer domain.LoanOffer
-	var createdAt, updatedAt time.Time
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	err := r.db.QueryRow(ctx, query, applicationID).Scan(
-		&offer.ID, &offer.ApplicationID, &offer.OfferAmount, &offer.InterestRate, &offer.TermMonths,
-		&offer.MonthlyPayment, &offer.TotalInterest, &offer.APR, &offer.ExpiresAt, &offer.Status,
-		&createdAt, &updatedAt,
+	now := time.Now().UTC()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE offer_groups SET status = $1, selected_offer_id = $2, updated_at = $3
+		WHERE id = $4 AND status = $5 AND expires_at > $3`,
+		domain.OfferGroupSelected, offerID, now, groupID, domain.OfferGroupOpen,
 	)
+	if err != nil {
+		logger.Error("Failed to select offer group", zap.Error(err))
+		return fmt.Errorf("failed to select offer group: %w", err)
+	}
 
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		if err == sql.ErrNoRows {
-			logger.Warn("Offer not found", zap.String("application_id", applicationID))
-			return nil, fmt.Errorf("offer not found: %s", applicationID)
-		}
-		logger.Error("Failed to get offer by application ID", zap.Error(err))
-		return nil, fmt.Errorf("failed to get offer: %w", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		logger.Warn("Offer group is not open")
+		return fmt.Errorf("offer group not open: %s", groupID)
 	}
 
-	offer.CreatedAt = createdAt
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE loan_offers SET status = CASE WHEN id = $1 THEN $2 ELSE $3 END, updated_at = $4
+		WHERE group_id = $5 AND (id = $1 OR status = $6)`,
+		offerID, domain.OfferStatusSelected, domain.OfferStatusExpired, now, groupID, domain.OfferStatusPending,
+	); err != nil {
+		logger.Error("Failed to update offer statuses", zap.Error(err))
+		return fmt.Errorf("failed to update offer statuses: %w", err)
+	}
 
-	logger.Info("Offer retrieved successfully", zap.String("offer_id", offer.ID))
-	return &offer, nil
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit offer selection", zap.Error(err))
+		return fmt.Errorf("failed to commit offer selection: %w", err)
+	}
+
+	logger.Info("Offer selected successfully")
+	return nil
 }
 
 // UpdateOffer updates an existing loan offer
@@ -466,6 +1751,42 @@ func (r *LoanRepository) SaveWorkflowExecution(ctx context.Context, execution *d
 	return nil
 }
 
+// ListWorkflowExecutions retrieves every workflow execution of an application, oldest first
+func (r *LoanRepository) ListWorkflowExecutions(ctx context.Context, applicationID string) ([]*domain.WorkflowExecution, error) {
+	logger := r.logger.With(
+		zap.String("operation", "list_workflow_executions"),
+		zap.String("application_id", applicationID),
+	)
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, workflow_id, application_id, status, start_time, end_time, created_at
+		FROM workflow_executions WHERE application_id = $1 ORDER BY start_time, id`, applicationID)
+	if err != nil {
+		logger.Error("Failed to list workflow executions", zap.Error(err))
+		return nil, fmt.Errorf("failed to list workflow executions: %w", err)
+	}
+	defer rows.Close()
+
+	var executions []*domain.WorkflowExecution
+	for rows.Next() {
+		var execution domain.WorkflowExecution
+		if err := rows.Scan(
+			&execution.ID, &execution.WorkflowID, &execution.ApplicationID, &execution.Status,
+			&execution.StartTime, &execution.EndTime, &execution.CreatedAt,
+		); err != nil {
+			logger.Error("Failed to scan workflow execution row", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan workflow execution: %w", err)
+		}
+		executions = append(executions, &execution)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return executions, nil
+}
+
 // GetWorkflowExecutionByApplicationID retrieves a workflow execution by application ID
 func (r *LoanRepository) GetWorkflowExecutionByApplicationID(ctx context.Context, applicationID string) (*domain.WorkflowExecution, error) {
 	logger := r.logger.With(
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// manualReviewColumns are the manual_reviews columns read by scanManualReview
+const manualReviewColumns = `id, application_id, workflow_instance_id, task_reference_name, reason, risk_level,
+	priority, review_type, primary_reviewer_id, status, assigned_to, decision, notes, approved_amount, interest_rate, conditions, denial_reasons,
+	due_at, created_at, claimed_at, completed_at, completed_by`
+
+// ListManualReviews lists manual reviews, most urgent first: high priority before normal before
+// low, then by due date
+func (r *LoanRepository) ListManualReviews(ctx context.Context, query *domain.ManualReviewQuery) ([]*domain.ManualReview, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+manualReviewColumns+`
+		FROM manual_reviews
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR priority = $2)
+			AND ($3 = '' OR assigned_to = $3) AND ($4 = '' OR application_id = $4)
+			AND ($5 = '' OR review_type = $5)
+		ORDER BY CASE priority WHEN 'high' THEN 0 WHEN 'normal' THEN 1 ELSE 2 END, due_at, id
+		LIMIT $6`,
+		string(query.Status), query.Priority, query.AssignedTo, query.ApplicationID,
+		string(query.ReviewType), query.Limit)
+	if err != nil {
+		r.logger.Error("Failed to list manual reviews", zap.Error(err))
+		return nil, fmt.Errorf("failed to list manual reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := make([]*domain.ManualReview, 0)
+	for rows.Next() {
+		review, err := scanManualReview(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan manual review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+	return reviews, nil
+}
+
+// GetManualReview retrieves a manual review
+func (r *LoanRepository) GetManualReview(ctx context.Context, id string) (*domain.ManualReview, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT `+manualReviewColumns+`
+		FROM manual_reviews WHERE id = $1`,
+		id)
+
+	review, err := scanManualReview(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("manual review not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get manual review: %w", err)
+	}
+	return review, nil
+}
+
+// ClaimManualReview assigns a pending review to an underwriter. It reports false when another
+// underwriter claimed it first.
+func (r *LoanRepository) ClaimManualReview(ctx context.Context, review *domain.ManualReview) (bool, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE manual_reviews SET status = $1, assigned_to = $2, claimed_at = $3
+		WHERE id = $4 AND status = $5`,
+		string(domain.ManualReviewInReview), review.AssignedTo, review.ClaimedAt, review.ID,
+		string(domain.ManualReviewPending),
+	)
+	if err != nil {
+		r.logger.Error("Failed to claim manual review",
+			zap.String("review_id", review.ID),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to claim manual review: %w", err)
+	}
+	return manualReviewUpdated(result)
+}
+
+// CompleteManualReview records the decision on a claimed review. It reports false when the review
+// was completed meanwhile.
+func (r *LoanRepository) CompleteManualReview(ctx context.Context, review *domain.ManualReview) (bool, error) {
+	conditions, err := json.Marshal(review.Conditions)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode review conditions: %w", err)
+	}
+	denialReasons, err := json.Marshal(review.DenialReasons)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode denial reasons: %w", err)
+	}
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE manual_reviews SET
+			status = $1, decision = $2, notes = $3, approved_amount = $4, interest_rate = $5,
+			conditions = $6, denial_reasons = $7, completed_at = $8, completed_by = $9
+		WHERE id = $10 AND status = $11`,
+		string(domain.ManualReviewCompleted), review.Decision, review.Notes, review.ApprovedAmount,
+		review.InterestRate, conditions, denialReasons, review.CompletedAt, review.CompletedBy,
+		review.ID, string(domain.ManualReviewInReview),
+	)
+	if err != nil {
+		r.logger.Error("Failed to complete manual review",
+			zap.String("review_id", review.ID),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to complete manual review: %w", err)
+	}
+	return manualReviewUpdated(result)
+}
+
+// RecordManualReviewRedecision adds a note of a re-decision to the reason of an application's
+// open primary reviews that do not have it yet, returning how many it was added to. The decision
+// engine retries notifications, so the same note may arrive more than once.
+func (r *LoanRepository) RecordManualReviewRedecision(ctx context.Context, applicationID, note string) (int, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE manual_reviews SET reason = CONCAT_WS('; ', NULLIF(reason, ''), $1)
+		WHERE application_id = $2 AND review_type = $3 AND status <> $4
+			AND STRPOS(COALESCE(reason, ''), $1) = 0`,
+		note, applicationID, string(domain.ManualReviewPrimary), string(domain.ManualReviewCompleted),
+	)
+	if err != nil {
+		r.logger.Error("Failed to record re-decision on manual reviews",
+			zap.String("application_id", applicationID),
+			zap.Error(err))
+		return 0, fmt.Errorf("failed to record re-decision on manual reviews: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rowsAffected), nil
+}
+
+func manualReviewUpdated(result sql.Result) (bool, error) {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+func scanManualReview(row interface{ Scan(...interface{}) error }) (*domain.ManualReview, error) {
+	var review domain.ManualReview
+	var riskLevel, primaryReviewerID, assignedTo, decision, completedBy sql.NullString
+	var approvedAmount, interestRate sql.NullFloat64
+	var claimedAt, completedAt sql.NullTime
+	var conditions, denialReasons []byte
+	if err := row.Scan(
+		&review.ID, &review.ApplicationID, &review.WorkflowInstanceID, &review.TaskReferenceName,
+		&review.Reason, &riskLevel, &review.Priority, &review.ReviewType, &primaryReviewerID,
+		&review.Status, &assignedTo, &decision,
+		&review.Notes, &approvedAmount, &interestRate, &conditions, &denialReasons, &review.DueAt,
+		&review.CreatedAt, &claimedAt, &completedAt, &completedBy,
+	); err != nil {
+		return nil, err
+	}
+
+	if riskLevel.Valid {
+		review.RiskLevel = &riskLevel.String
+	}
+	if primaryReviewerID.Valid {
+		review.PrimaryReviewerID = &primaryReviewerID.String
+	}
+	if assignedTo.Valid {
+		review.AssignedTo = &assignedTo.String
+	}
+	if decision.Valid {
+		d := domain.ManualReviewDecision(decision.String)
+		review.Decision = &d
+	}
+	if approvedAmount.Valid {
+		review.ApprovedAmount = &approvedAmount.Float64
+	}
+	if interestRate.Valid {
+		review.InterestRate = &interestRate.Float64
+	}
+	if claimedAt.Valid {
+		review.ClaimedAt = &claimedAt.Time
+	}
+	if completedAt.Valid {
+		review.CompletedAt = &completedAt.Time
+	}
+	if completedBy.Valid {
+		review.CompletedBy = &completedBy.String
+	}
+	if err := json.Unmarshal(conditions, &review.Conditions); err != nil {
+		return nil, fmt.Errorf("failed to decode review conditions: %w", err)
+	}
+	if err := json.Unmarshal(denialReasons, &review.DenialReasons); err != nil {
+		return nil, fmt.Errorf("failed to decode denial reasons: %w", err)
+	}
+	return &review, nil
+}
@@ -0,0 +1,16 @@
+-- Migration: 003_replace_ssn_with_token.sql
+-- Description: Store SSN vault tokens instead of plaintext SSNs on users
+-- Note: existing plaintext SSNs are tokenized through the user service token vault by the loan
+-- service's SSN backfill job, which clears each one as it goes. The plaintext column is dropped by
+-- out_of_band/drop_plaintext_ssn.sql, run by hand once the backfill has finished.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS ssn_token VARCHAR(64);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS ssn_last4 CHAR(4);
+
+-- Keep the last four digits for display masking
+UPDATE users SET ssn_last4 = RIGHT(ssn, 4) WHERE ssn IS NOT NULL AND ssn_last4 IS NULL;
+
+-- New users are stored with a token only
+ALTER TABLE users ALTER COLUMN ssn DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_ssn_token ON users(ssn_token);
@@ -0,0 +1,8 @@
+-- Migration: 004_add_address_verification.sql
+-- Description: Store CASS standardization results for applicant addresses
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS zip_plus4 VARCHAR(4);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS delivery_point_barcode VARCHAR(12);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS address_undeliverable BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_users_address_undeliverable ON users(address_undeliverable) WHERE address_undeliverable;
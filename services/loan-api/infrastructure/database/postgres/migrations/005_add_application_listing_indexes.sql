@@ -0,0 +1,6 @@
+-- Migration: 005_add_application_listing_indexes.sql
+-- Description: Support paginated application listings filtered by borrower, status and state
+
+CREATE INDEX IF NOT EXISTS idx_loan_applications_user_created ON loan_applications(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_loan_applications_status_created ON loan_applications(status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_loan_applications_state_created ON loan_applications(current_state, created_at DESC);
@@ -0,0 +1,4 @@
+-- Migration: 006_add_co_borrower.sql
+-- Description: Store an optional co-borrower's identity, income, debts and employment on each application
+
+ALTER TABLE loan_applications ADD COLUMN IF NOT EXISTS co_borrower JSONB;
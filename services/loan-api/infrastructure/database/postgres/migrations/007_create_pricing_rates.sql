@@ -0,0 +1,52 @@
+-- Migration: 007_create_pricing_rates.sql
+-- Description: Rate matrices keyed by product, credit tier, risk level and term with effective dates,
+-- and the pricing audit record attached to each offer
+
+CREATE TABLE IF NOT EXISTS pricing_rates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product VARCHAR(50) NOT NULL,
+    credit_tier VARCHAR(20) NOT NULL CHECK (credit_tier IN ('excellent', 'good', 'fair', 'poor')),
+    risk_level VARCHAR(10) NOT NULL CHECK (risk_level IN ('LOW', 'MEDIUM', 'HIGH')),
+    min_term_months INTEGER NOT NULL,
+    max_term_months INTEGER NOT NULL,
+    interest_rate DECIMAL(6,3) NOT NULL,
+    origination_fee_rate DECIMAL(5,3) NOT NULL DEFAULT 0,
+    effective_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    effective_to TIMESTAMP WITH TIME ZONE,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (min_term_months <= max_term_months),
+    CHECK (effective_to IS NULL OR effective_to > effective_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pricing_rates_lookup
+    ON pricing_rates(product, credit_tier, risk_level, effective_from DESC);
+
+ALTER TABLE loan_offers ADD COLUMN IF NOT EXISTS pricing_audit JSONB;
+
+-- Default personal loan matrix: a base rate per credit tier plus risk and term adjustments
+INSERT INTO pricing_rates (
+    product, credit_tier, risk_level, min_term_months, max_term_months,
+    interest_rate, origination_fee_rate, effective_from, created_by
+)
+SELECT
+    'personal_loan', tier.name, risk.level, term.min_months, term.max_months,
+    tier.base_rate + risk.adjustment + term.adjustment, tier.fee_rate, '2024-01-01T00:00:00Z', 'migration'
+FROM (VALUES
+    ('excellent', 7.990, 1.000),
+    ('good', 10.490, 2.000),
+    ('fair', 14.990, 3.000),
+    ('poor', 19.990, 5.000)
+) AS tier(name, base_rate, fee_rate)
+CROSS JOIN (VALUES
+    ('LOW', 0.000),
+    ('MEDIUM', 1.500),
+    ('HIGH', 3.000)
+) AS risk(level, adjustment)
+CROSS JOIN (VALUES
+    (12, 36, 0.000),
+    (37, 60, 0.500),
+    (61, 84, 1.000)
+) AS term(min_months, max_months, adjustment)
+WHERE NOT EXISTS (SELECT 1 FROM pricing_rates WHERE product = 'personal_loan');
@@ -0,0 +1,21 @@
+-- Migration: 008_create_offer_groups.sql
+-- Description: Group the alternative offers generated together for an application, of which the
+-- borrower selects one
+
+CREATE TABLE IF NOT EXISTS offer_groups (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'selected', 'expired')),
+    selected_offer_id UUID,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_offer_groups_application_id ON offer_groups(application_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_offer_groups_open_expires_at ON offer_groups(expires_at) WHERE status = 'open';
+
+ALTER TABLE loan_offers ADD COLUMN IF NOT EXISTS group_id UUID REFERENCES offer_groups(id);
+ALTER TABLE loan_offers ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_loan_offers_group_id ON loan_offers(group_id);
@@ -0,0 +1,23 @@
+-- Migration: 009_create_offer_negotiations.sql
+-- Description: Offer negotiation threads (declines, requests for different terms and the counter
+-- offers made in response) and the link from a counter offer to the offer it answers
+
+ALTER TABLE loan_offers ADD COLUMN IF NOT EXISTS parent_offer_id UUID REFERENCES loan_offers(id);
+
+CREATE INDEX IF NOT EXISTS idx_loan_offers_parent_offer_id ON loan_offers(parent_offer_id);
+
+CREATE TABLE IF NOT EXISTS offer_negotiations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL,
+    offer_id UUID NOT NULL REFERENCES loan_offers(id),
+    action VARCHAR(30) NOT NULL CHECK (action IN ('declined', 'counter_requested', 'counter_offered', 'counter_accepted')),
+    actor VARCHAR(20) NOT NULL CHECK (actor IN ('borrower', 'lender')),
+    reason TEXT,
+    requested_amount DECIMAL(15,2),
+    requested_term_months INTEGER,
+    counter_offer_id UUID REFERENCES loan_offers(id),
+    workflow_id VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_offer_negotiations_application_id ON offer_negotiations(application_id, created_at);
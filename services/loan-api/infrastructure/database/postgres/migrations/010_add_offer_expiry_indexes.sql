@@ -0,0 +1,6 @@
+-- Migration: 010_add_offer_expiry_indexes.sql
+-- Description: Support the scheduled offer expiry job, which sweeps offers past their expiry and
+-- approved applications left without an open offer
+
+CREATE INDEX IF NOT EXISTS idx_loan_offers_open_expires_at ON loan_offers(expires_at) WHERE status IN ('pending', 'countered');
+CREATE INDEX IF NOT EXISTS idx_loan_offers_application_status ON loan_offers(application_id, status);
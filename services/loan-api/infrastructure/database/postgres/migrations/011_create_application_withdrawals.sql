@@ -0,0 +1,12 @@
+-- Migration: 011_create_application_withdrawals.sql
+-- Description: Record why and by whom an application was withdrawn before funding
+
+CREATE TABLE IF NOT EXISTS application_withdrawals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL UNIQUE,
+    from_state VARCHAR(50) NOT NULL,
+    reason VARCHAR(30) NOT NULL CHECK (reason IN ('found_better_offer', 'no_longer_needed', 'terms_not_acceptable', 'process_too_slow', 'other')),
+    comment TEXT,
+    withdrawn_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,17 @@
+-- Migration: 012_create_application_notes.sql
+-- Description: Notes posted on applications by underwriters and support agents, optionally shared
+-- with the borrower
+
+CREATE TABLE IF NOT EXISTS application_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL,
+    author_id UUID NOT NULL,
+    author_role VARCHAR(20) NOT NULL CHECK (author_role IN ('underwriter', 'support_agent')),
+    visibility VARCHAR(20) NOT NULL DEFAULT 'internal' CHECK (visibility IN ('internal', 'borrower')),
+    body TEXT NOT NULL,
+    mentions JSONB,
+    attachments JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_application_notes_application_id ON application_notes(application_id, created_at);
@@ -0,0 +1,19 @@
+-- Migration: 013_add_timeline_sources.sql
+-- Description: Create the workflow executions table and the state transition actor column the
+-- repository already reads and writes, both of which feed the application timeline
+
+CREATE TABLE IF NOT EXISTS workflow_executions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workflow_id VARCHAR(255) NOT NULL,
+    application_id UUID NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_workflow_executions_application_id ON workflow_executions(application_id, created_at DESC);
+
+-- 'system' for automated transitions, otherwise the ID of the user who made the change
+ALTER TABLE state_transitions ADD COLUMN IF NOT EXISTS triggered_by VARCHAR(255) NOT NULL DEFAULT 'system';
@@ -0,0 +1,60 @@
+-- Migration: 014_create_document_checklists.sql
+-- Description: Rules-driven document checklists. Rules select required documents by product, loan
+-- amount, employment status and underwriting condition type; each application's evaluated
+-- checklist is persisted and grows as underwriting conditions are added.
+
+CREATE TABLE IF NOT EXISTS document_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product VARCHAR(50) NOT NULL DEFAULT 'personal_loan',
+    document_type VARCHAR(50) NOT NULL,
+    applies_to VARCHAR(20) NOT NULL DEFAULT 'all' CHECK (applies_to IN ('borrower', 'co_borrower', 'all')),
+    min_loan_amount DECIMAL(15,2),
+    max_loan_amount DECIMAL(15,2),
+    employment_statuses JSONB, -- NULL matches every employment status
+    condition_type VARCHAR(50), -- NULL for the base checklist
+    description TEXT NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (min_loan_amount IS NULL OR max_loan_amount IS NULL OR min_loan_amount <= max_loan_amount)
+);
+
+CREATE INDEX IF NOT EXISTS idx_document_rules_active ON document_rules(product, condition_type) WHERE active;
+
+-- The default checklist, previously hardcoded, plus tax returns for the self-employed and asset
+-- statements for large loans
+INSERT INTO document_rules (document_type, employment_statuses, min_loan_amount, description) VALUES
+    ('income_verification', NULL, NULL, 'Proof of income'),
+    ('employment_verification', '["full_time", "part_time", "self_employed"]', NULL, 'Proof of employment'),
+    ('bank_statements', NULL, NULL, 'Recent bank statements'),
+    ('identification', NULL, NULL, 'Government issued identification'),
+    ('tax_returns', '["self_employed"]', NULL, 'Tax returns for self-employed income'),
+    ('asset_statement', NULL, 40000, 'Asset statements for loans of 40,000 or more');
+
+CREATE TABLE IF NOT EXISTS underwriting_conditions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL,
+    condition_type VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL,
+    document_types JSONB,
+    created_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_underwriting_conditions_application_id ON underwriting_conditions(application_id, created_at);
+
+CREATE TABLE IF NOT EXISTS application_document_requirements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL,
+    borrower VARCHAR(20) NOT NULL CHECK (borrower IN ('borrower', 'co_borrower')),
+    document_type VARCHAR(50) NOT NULL,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('rule', 'condition', 'default')),
+    rule_id UUID REFERENCES document_rules(id),
+    condition_id UUID REFERENCES underwriting_conditions(id),
+    description TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'required' CHECK (status IN ('required', 'received', 'waived')),
+    document_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (application_id, borrower, document_type)
+);
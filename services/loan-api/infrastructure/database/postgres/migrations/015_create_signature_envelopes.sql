@@ -0,0 +1,20 @@
+-- Migration: 015_create_signature_envelopes.sql
+-- Description: Loan agreements sent to borrowers for electronic signature once an offer is
+-- selected, and the signed documents stored when they complete
+
+CREATE TABLE IF NOT EXISTS signature_envelopes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL,
+    offer_id UUID NOT NULL,
+    provider VARCHAR(20) NOT NULL,
+    provider_envelope_id VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'sent' CHECK (status IN ('sent', 'delivered', 'completed', 'declined', 'voided')),
+    signed_document_id UUID,
+    sent_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, provider_envelope_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_signature_envelopes_application_id ON signature_envelopes(application_id, created_at DESC);
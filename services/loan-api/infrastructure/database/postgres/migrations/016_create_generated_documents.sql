@@ -0,0 +1,18 @@
+-- Migration: 016_create_generated_documents.sql
+-- Description: Loan agreements, TILA disclosures and adverse action notices rendered for an
+-- application and stored with the borrower's documents in the user service
+
+CREATE TABLE IF NOT EXISTS generated_documents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL,
+    document_type VARCHAR(30) NOT NULL CHECK (document_type IN ('loan_agreement', 'tila_disclosure', 'adverse_action_notice')),
+    offer_id UUID,
+    stored_document_id UUID NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    mime_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    generated_by VARCHAR(100),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_generated_documents_application_id ON generated_documents(application_id, created_at DESC);
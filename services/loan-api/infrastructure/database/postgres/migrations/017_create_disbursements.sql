@@ -0,0 +1,58 @@
+-- Migration: 017_create_disbursements.sql
+-- Description: ACH disbursements funding signed loans, the funding batches that originate them
+-- and the audit trail of their status changes
+
+CREATE TABLE IF NOT EXISTS funding_batches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    provider VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'submitted')),
+    submitted_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    total_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    submitted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_funding_batches_created_at ON funding_batches(created_at DESC);
+
+CREATE TABLE IF NOT EXISTS disbursements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL,
+    offer_id UUID NOT NULL,
+    batch_id UUID, -- claimed before the batch row is recorded, so batches are only kept when non-empty
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    provider VARCHAR(20) NOT NULL,
+    provider_transfer_id VARCHAR(100),
+    destination_reference VARCHAR(255),
+    destination_last4 VARCHAR(4) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'queued', 'submitted', 'completed', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    return_code VARCHAR(10),
+    failure_reason TEXT,
+    submitted_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A loan is funded by at most one disbursement that has not failed
+CREATE UNIQUE INDEX IF NOT EXISTS idx_disbursements_active_application ON disbursements(application_id) WHERE status <> 'failed';
+CREATE INDEX IF NOT EXISTS idx_disbursements_application_id ON disbursements(application_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_disbursements_due ON disbursements(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_disbursements_batch_id ON disbursements(batch_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_disbursements_provider_transfer ON disbursements(provider, provider_transfer_id) WHERE provider_transfer_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS disbursement_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    disbursement_id UUID NOT NULL REFERENCES disbursements(id) ON DELETE CASCADE,
+    application_id UUID NOT NULL,
+    event_type VARCHAR(30) NOT NULL CHECK (event_type IN ('scheduled', 'submitted', 'submission_failed', 'completed', 'returned', 'retry_scheduled', 'failed')),
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    detail TEXT,
+    metadata JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_disbursement_events_disbursement_id ON disbursement_events(disbursement_id, created_at);
@@ -0,0 +1,30 @@
+-- Migration: 018_create_bank_accounts.sql
+-- Description: Bank accounts borrowers link to receive loan proceeds, verified through Plaid or
+-- micro-deposits. Account and routing numbers are user service vault tokens.
+
+CREATE TABLE IF NOT EXISTS bank_accounts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    institution_name VARCHAR(100) NOT NULL,
+    account_type VARCHAR(20) NOT NULL CHECK (account_type IN ('checking', 'savings')),
+    account_number_token VARCHAR(64) NOT NULL,
+    routing_number_token VARCHAR(64) NOT NULL,
+    account_last4 VARCHAR(4) NOT NULL,
+    verification_method VARCHAR(20) NOT NULL CHECK (verification_method IN ('plaid', 'micro_deposits')),
+    status VARCHAR(30) NOT NULL CHECK (status IN ('pending_verification', 'verified', 'verification_failed', 'removed')),
+    plaid_account_id VARCHAR(100),
+    provider_reference VARCHAR(255),
+    micro_deposit_attempts INTEGER NOT NULL DEFAULT 0,
+    micro_deposits_sent_at TIMESTAMP WITH TIME ZONE,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A borrower links an account once; it can be added again after removal or failed verification
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bank_accounts_active_number ON bank_accounts(user_id, account_number_token, routing_number_token)
+    WHERE status IN ('pending_verification', 'verified');
+CREATE INDEX IF NOT EXISTS idx_bank_accounts_user_id ON bank_accounts(user_id, created_at DESC);
+
+-- Disbursements are sent to a verified bank account
+ALTER TABLE disbursements ADD COLUMN IF NOT EXISTS bank_account_id UUID REFERENCES bank_accounts(id);
@@ -0,0 +1,39 @@
+-- Migration: 019_create_repayment_schedules.sql
+-- Description: Repayment schedules generated when a loan is funded and their monthly installments
+
+CREATE TABLE IF NOT EXISTS repayment_schedules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL UNIQUE,
+    offer_id UUID NOT NULL,
+    disbursement_id UUID REFERENCES disbursements(id),
+    principal DECIMAL(15,2) NOT NULL CHECK (principal > 0),
+    interest_rate DECIMAL(6,3) NOT NULL,
+    term_months INTEGER NOT NULL CHECK (term_months > 0),
+    payment_amount DECIMAL(15,2) NOT NULL,
+    payment_due_day INTEGER NOT NULL CHECK (payment_due_day BETWEEN 1 AND 28),
+    funded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    first_payment_date DATE NOT NULL,
+    maturity_date DATE NOT NULL,
+    total_interest DECIMAL(15,2) NOT NULL,
+    total_of_payments DECIMAL(15,2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS repayment_installments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    schedule_id UUID NOT NULL REFERENCES repayment_schedules(id) ON DELETE CASCADE,
+    application_id UUID NOT NULL,
+    installment_number INTEGER NOT NULL CHECK (installment_number > 0),
+    due_date DATE NOT NULL,
+    payment_amount DECIMAL(15,2) NOT NULL,
+    principal DECIMAL(15,2) NOT NULL,
+    interest DECIMAL(15,2) NOT NULL,
+    remaining_balance DECIMAL(15,2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (schedule_id, installment_number)
+);
+
+-- Servicing looks up installments coming due across all loans
+CREATE INDEX IF NOT EXISTS idx_repayment_installments_due_date ON repayment_installments(due_date, status);
+CREATE INDEX IF NOT EXISTS idx_repayment_installments_application_id ON repayment_installments(application_id, installment_number);
@@ -0,0 +1,22 @@
+-- Migration: 020_create_prequalifications.sql
+-- Description: Pre-qualification results, kept until they expire so they can be converted into
+-- draft applications
+
+CREATE TABLE IF NOT EXISTS prequalifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    request JSONB NOT NULL, -- the figures the result was evaluated from
+    qualified BOOLEAN NOT NULL,
+    max_loan_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    min_interest_rate DECIMAL(6,3) NOT NULL DEFAULT 0,
+    max_interest_rate DECIMAL(6,3) NOT NULL DEFAULT 0,
+    recommended_terms JSONB NOT NULL DEFAULT '[]',
+    dti_ratio DECIMAL(6,4) NOT NULL,
+    message TEXT NOT NULL,
+    application_id UUID REFERENCES loan_applications(id),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_prequalifications_user_id ON prequalifications(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_prequalifications_expires_at ON prequalifications(expires_at);
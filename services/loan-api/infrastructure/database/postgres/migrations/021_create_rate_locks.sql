@@ -0,0 +1,20 @@
+-- Migration: 021_create_rate_locks.sql
+-- Description: Rate locks guaranteeing an offer's rate for a product's lock window once the borrower
+-- views or accepts it; an offer's latest lock is the one in force
+
+CREATE TABLE IF NOT EXISTS rate_locks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    offer_id UUID NOT NULL REFERENCES loan_offers(id),
+    application_id UUID NOT NULL,
+    product VARCHAR(50) NOT NULL,
+    interest_rate DECIMAL(6,3) NOT NULL,
+    apr DECIMAL(6,3) NOT NULL,
+    trigger VARCHAR(20) NOT NULL CHECK (trigger IN ('viewed', 'accepted', 'repriced')),
+    locked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (expires_at > locked_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_locks_offer_id ON rate_locks(offer_id, locked_at DESC);
+CREATE INDEX IF NOT EXISTS idx_rate_locks_application_id ON rate_locks(application_id);
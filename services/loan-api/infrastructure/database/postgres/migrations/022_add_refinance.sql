@@ -0,0 +1,21 @@
+-- Migration: 022_add_refinance.sql
+-- Description: Refinance applications replace a borrower's funded loan: the new loan pays off the
+-- prior loan's balance and the rest is disbursed to the borrower
+
+ALTER TABLE loan_applications ADD COLUMN IF NOT EXISTS application_type VARCHAR(20) NOT NULL DEFAULT 'new'
+    CHECK (application_type IN ('new', 'refinance'));
+ALTER TABLE loan_applications ADD COLUMN IF NOT EXISTS refinanced_application_id UUID REFERENCES loan_applications(id);
+ALTER TABLE loan_applications ADD CONSTRAINT chk_loan_applications_refinance
+    CHECK ((application_type = 'refinance') = (refinanced_application_id IS NOT NULL));
+
+CREATE INDEX IF NOT EXISTS idx_loan_applications_refinanced_application_id ON loan_applications(refinanced_application_id)
+    WHERE refinanced_application_id IS NOT NULL;
+
+-- How each refinance offer compares with keeping the prior loan
+ALTER TABLE loan_offers ADD COLUMN IF NOT EXISTS refinance_comparison JSONB;
+
+-- The part of a refinance disbursement that pays off the prior loan instead of reaching the borrower
+ALTER TABLE disbursements ADD COLUMN IF NOT EXISTS payoff_application_id UUID REFERENCES loan_applications(id);
+ALTER TABLE disbursements ADD COLUMN IF NOT EXISTS payoff_amount DECIMAL(15,2) NOT NULL DEFAULT 0;
+ALTER TABLE disbursements ADD CONSTRAINT chk_disbursements_payoff_amount
+    CHECK (payoff_amount >= 0 AND payoff_amount < amount);
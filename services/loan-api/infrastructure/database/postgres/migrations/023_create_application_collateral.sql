@@ -0,0 +1,25 @@
+-- Migration: 023_create_application_collateral.sql
+-- Description: Vehicles and other assets securing applications for secured products, with their
+-- latest valuation and the application's loan-to-value ratio at it
+
+CREATE TABLE IF NOT EXISTS application_collateral (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL UNIQUE REFERENCES loan_applications(id),
+    collateral_type VARCHAR(20) NOT NULL CHECK (collateral_type IN ('vehicle', 'asset')),
+    vehicle JSONB,
+    asset JSONB,
+    stated_value DECIMAL(15,2) NOT NULL CHECK (stated_value > 0),
+    value DECIMAL(15,2) NOT NULL CHECK (value >= 0),
+    valuation_source VARCHAR(50) NOT NULL,
+    valuation_reference VARCHAR(255),
+    valued_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ltv_ratio DECIMAL(8,4) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((collateral_type = 'vehicle') = (vehicle IS NOT NULL)),
+    CHECK ((collateral_type = 'asset') = (asset IS NOT NULL))
+);
+
+-- Vehicles are identified by VIN across applications
+CREATE INDEX IF NOT EXISTS idx_application_collateral_vin ON application_collateral((vehicle->>'vin'))
+    WHERE vehicle IS NOT NULL;
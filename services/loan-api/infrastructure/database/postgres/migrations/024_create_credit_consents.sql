@@ -0,0 +1,19 @@
+-- Migration: 024_create_credit_consents.sql
+-- Description: Borrowers' explicit consent to the hard credit pull underwriting makes, recorded per
+-- application before it can be submitted
+
+CREATE TABLE IF NOT EXISTS credit_consents (
+    id UUID PRIMARY KEY,
+    application_id UUID NOT NULL UNIQUE REFERENCES loan_applications(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    disclosure_version VARCHAR(50) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent TEXT,
+    consented_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_credit_consents_user_id ON credit_consents(user_id);
+
+COMMENT ON TABLE credit_consents IS 'Consent to a hard credit pull, passed to the credit_check task as evidence of permissible purpose';
+COMMENT ON COLUMN credit_consents.disclosure_version IS 'Version of the credit pull disclosure the borrower consented to';
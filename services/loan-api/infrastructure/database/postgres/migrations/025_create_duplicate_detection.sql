@@ -0,0 +1,39 @@
+-- Migration: 025_create_duplicate_detection.sql
+-- Description: Identity signals of each application and suspected duplicates across borrowers,
+-- held for admin review before automated approval
+
+CREATE TABLE IF NOT EXISTS application_fingerprints (
+    application_id UUID PRIMARY KEY REFERENCES loan_applications(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    ssn_token VARCHAR(255) NOT NULL DEFAULT '',
+    email_normalized VARCHAR(255) NOT NULL DEFAULT '',
+    device_id VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_application_fingerprints_ssn_token ON application_fingerprints(ssn_token, created_at) WHERE ssn_token <> '';
+CREATE INDEX IF NOT EXISTS idx_application_fingerprints_email ON application_fingerprints(email_normalized, created_at) WHERE email_normalized <> '';
+CREATE INDEX IF NOT EXISTS idx_application_fingerprints_device_id ON application_fingerprints(device_id, created_at) WHERE device_id <> '';
+
+CREATE TABLE IF NOT EXISTS application_duplicate_matches (
+    id UUID PRIMARY KEY,
+    application_id UUID NOT NULL REFERENCES loan_applications(id) ON DELETE CASCADE,
+    candidate_application_id UUID NOT NULL REFERENCES loan_applications(id) ON DELETE CASCADE,
+    candidate_user_id UUID NOT NULL,
+    score DECIMAL(5,4) NOT NULL, -- 0.0000 to 1.0000
+    reasons JSONB NOT NULL DEFAULT '[]',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewed_by VARCHAR(255),
+    review_note TEXT,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(application_id, candidate_application_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_application_duplicate_matches_candidate ON application_duplicate_matches(candidate_application_id);
+CREATE INDEX IF NOT EXISTS idx_application_duplicate_matches_pending ON application_duplicate_matches(created_at) WHERE status = 'pending';
+
+COMMENT ON COLUMN application_fingerprints.ssn_token IS 'Vault token of the applicant SSN; tokens are deterministic so equal SSNs match';
+COMMENT ON COLUMN application_fingerprints.email_normalized IS 'Email lowercased with plus-tags and Gmail dots removed';
+COMMENT ON TABLE application_duplicate_matches IS 'Applications sharing identity signals with another borrower''s recent application; pending matches route automated approval to manual review';
@@ -0,0 +1,74 @@
+-- Migration: 026_create_webhooks.sql
+-- Description: Partner webhook subscriptions, the outbox of events they receive and the delivery log.
+-- State transitions are queued by a trigger so transitions made by the workflow workers are included.
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    url TEXT NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    event_types JSONB NOT NULL DEFAULT '[]', -- empty subscribes to every event
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id UUID PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    application_id UUID NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dispatched_at TIMESTAMP -- set once a delivery is queued for every subscription
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_events_undispatched ON webhook_events(created_at) WHERE dispatched_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL REFERENCES webhook_events(id) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL,
+    application_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP,
+    response_status INTEGER,
+    last_error TEXT,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(subscription_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, created_at);
+
+CREATE OR REPLACE FUNCTION queue_state_transition_webhook_event() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO webhook_events (id, event_type, application_id, payload, created_at)
+    VALUES (
+        uuid_generate_v4(),
+        'application.state_changed',
+        NEW.application_id,
+        jsonb_build_object(
+            'transition_id', NEW.id,
+            'from_state', NEW.from_state,
+            'to_state', NEW.to_state,
+            'reason', NEW.transition_reason,
+            'transitioned_at', NEW.created_at
+        ),
+        CURRENT_TIMESTAMP
+    );
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS state_transitions_webhook_event ON state_transitions;
+CREATE TRIGGER state_transitions_webhook_event
+    AFTER INSERT ON state_transitions
+    FOR EACH ROW EXECUTE FUNCTION queue_state_transition_webhook_event();
+
+COMMENT ON TABLE webhook_events IS 'Outbox of partner webhook events; state transitions are queued by trigger, offer events by loan-api';
+COMMENT ON COLUMN webhook_subscriptions.secret IS 'HMAC-SHA256 key for the X-Webhook-Signature header of deliveries';
@@ -0,0 +1,14 @@
+-- Migration: 027_add_application_search.sql
+-- Description: Trigram and full-text indexes backing the back-office application search across
+-- applicant name, email, application number and notes
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_users_full_name_trgm
+    ON users USING GIN ((first_name || ' ' || last_name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON users USING GIN (email gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_loan_applications_number_trgm
+    ON loan_applications USING GIN (application_number gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_application_notes_body_fts
+    ON application_notes USING GIN (to_tsvector('simple', body));
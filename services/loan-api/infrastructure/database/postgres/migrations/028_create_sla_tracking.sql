@@ -0,0 +1,51 @@
+-- Migration: 028_create_sla_tracking.sql
+-- Description: SLA timers per application state. state_entered_at is kept by a trigger so state
+-- changes made by the workflow workers are timed too; breaches record each SLA escalation once per
+-- stay in a state.
+
+ALTER TABLE loan_applications ADD COLUMN IF NOT EXISTS state_entered_at TIMESTAMP;
+ALTER TABLE loan_applications ADD COLUMN IF NOT EXISTS priority INTEGER NOT NULL DEFAULT 0;
+
+UPDATE loan_applications a SET state_entered_at = COALESCE(
+    (SELECT MAX(t.created_at) FROM state_transitions t
+        WHERE t.application_id = a.id AND t.to_state = a.current_state),
+    a.updated_at
+) WHERE state_entered_at IS NULL;
+
+ALTER TABLE loan_applications ALTER COLUMN state_entered_at SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE loan_applications ALTER COLUMN state_entered_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_loan_applications_state_entered ON loan_applications(current_state, state_entered_at);
+
+CREATE OR REPLACE FUNCTION track_application_state_entered() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.current_state IS DISTINCT FROM OLD.current_state THEN
+        NEW.state_entered_at = CURRENT_TIMESTAMP;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS loan_applications_state_entered ON loan_applications;
+CREATE TRIGGER loan_applications_state_entered
+    BEFORE UPDATE OF current_state ON loan_applications
+    FOR EACH ROW EXECUTE FUNCTION track_application_state_entered();
+
+CREATE TABLE IF NOT EXISTS application_sla_breaches (
+    id UUID PRIMARY KEY,
+    application_id UUID NOT NULL,
+    sla_name VARCHAR(100) NOT NULL,
+    state VARCHAR(50) NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('notify', 'priority_bump', 'auto_withdraw')),
+    state_entered_at TIMESTAMP NOT NULL,
+    due_at TIMESTAMP NOT NULL,
+    breached_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    action_error TEXT, -- set when the escalation action failed
+
+    UNIQUE(application_id, sla_name, state_entered_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_application_sla_breaches_breached ON application_sla_breaches(breached_at);
+
+COMMENT ON COLUMN loan_applications.state_entered_at IS 'When the application entered its current state; maintained by trigger';
+COMMENT ON COLUMN loan_applications.priority IS 'Review priority, raised by SLA escalations';
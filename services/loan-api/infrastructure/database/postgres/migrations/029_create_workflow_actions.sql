@@ -0,0 +1,18 @@
+-- Migration: 029_create_workflow_actions.sql
+-- Description: Audit trail of staff pausing, resuming and terminating workflow executions, with
+-- who acted and why
+
+CREATE TABLE IF NOT EXISTS workflow_actions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workflow_id VARCHAR(255) NOT NULL,
+    application_id UUID REFERENCES loan_applications(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('pause', 'resume', 'terminate')),
+    reason TEXT NOT NULL DEFAULT '',
+    performed_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_workflow_actions_workflow_id ON workflow_actions(workflow_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_workflow_actions_application_id ON workflow_actions(application_id);
+
+CREATE INDEX IF NOT EXISTS idx_workflow_executions_workflow_id ON workflow_executions(workflow_id);
@@ -0,0 +1,22 @@
+-- Migration: 030_create_application_assignments.sql
+-- Description: Loan officer assignments of applications in manual review. Each row is one period
+-- an application spent with an officer; the active assignment has no released_at.
+
+CREATE TABLE IF NOT EXISTS application_assignments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL REFERENCES loan_applications(id) ON DELETE CASCADE,
+    officer_id VARCHAR(255) NOT NULL,
+    method VARCHAR(20) NOT NULL CHECK (method IN ('auto', 'claim', 'reassign')),
+    assigned_by VARCHAR(255) NOT NULL DEFAULT 'system',
+    reason TEXT NOT NULL DEFAULT '',
+    assigned_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    released_at TIMESTAMP WITH TIME ZONE
+);
+
+-- At most one active assignment per application
+CREATE UNIQUE INDEX IF NOT EXISTS idx_application_assignments_active
+    ON application_assignments(application_id) WHERE released_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_application_assignments_officer
+    ON application_assignments(officer_id) WHERE released_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_application_assignments_application
+    ON application_assignments(application_id, assigned_at);
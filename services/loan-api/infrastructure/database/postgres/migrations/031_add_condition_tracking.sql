@@ -0,0 +1,27 @@
+-- Migration: 031_add_condition_tracking.sql
+-- Description: Track underwriting conditions through their lifecycle. The underwriting worker
+-- records the conditions of a conditional approval with the workflow waiting on them; borrowers
+-- upload evidence per condition and underwriters waive them. Once every critical condition of a
+-- workflow is received or waived, or one of them expires, the workflow is resumed.
+
+ALTER TABLE underwriting_conditions
+    ADD COLUMN IF NOT EXISTS source_condition_id VARCHAR(128),
+    ADD COLUMN IF NOT EXISTS priority VARCHAR(20) NOT NULL DEFAULT 'medium' CHECK (priority IN ('critical', 'high', 'medium', 'low')),
+    ADD COLUMN IF NOT EXISTS critical BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'received', 'waived', 'expired')),
+    ADD COLUMN IF NOT EXISTS evidence_document_ids JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS workflow_instance_id VARCHAR(128),
+    ADD COLUMN IF NOT EXISTS task_reference_name VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS due_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS resolved_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS resolved_by VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS resolution_note TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS workflow_resumed_at TIMESTAMP WITH TIME ZONE, -- set once the workflow no longer waits on the conditions
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- A workflow task records each of its conditions once, however often it is delivered
+CREATE UNIQUE INDEX IF NOT EXISTS idx_underwriting_conditions_workflow_source
+    ON underwriting_conditions(workflow_instance_id, source_condition_id) WHERE workflow_instance_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_underwriting_conditions_due ON underwriting_conditions(due_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_underwriting_conditions_waiting
+    ON underwriting_conditions(workflow_instance_id) WHERE workflow_instance_id IS NOT NULL AND workflow_resumed_at IS NULL;
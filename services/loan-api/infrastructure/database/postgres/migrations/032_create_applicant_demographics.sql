@@ -0,0 +1,19 @@
+-- Migration: 032_create_applicant_demographics.sql
+-- Description: Borrowers' answers to the government monitoring questions, kept apart from the
+-- application so underwriting never sees them and copied into the compliance record when the
+-- application is decided
+
+CREATE TABLE IF NOT EXISTS applicant_demographics (
+    id UUID PRIMARY KEY,
+    application_id UUID NOT NULL UNIQUE REFERENCES loan_applications(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    ethnicity JSONB NOT NULL DEFAULT '[]',
+    race JSONB NOT NULL DEFAULT '[]',
+    sex VARCHAR(20) NOT NULL,
+    collection_method VARCHAR(30) NOT NULL,
+    collected_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE applicant_demographics IS 'Government monitoring information, collected separately from the application and not available to underwriting';
+COMMENT ON COLUMN applicant_demographics.ethnicity IS 'Ethnicities selected, or NOT_PROVIDED when the borrower chose not to answer';
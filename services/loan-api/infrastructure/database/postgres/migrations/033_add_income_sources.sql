@@ -0,0 +1,6 @@
+-- Migration: 033_add_income_sources.sql
+-- Description: Store the borrower's documented income by source (wages, bonus and overtime,
+-- self-employment and rental) on each application; its qualifying income replaces monthly_income in
+-- DTI when present
+
+ALTER TABLE loan_applications ADD COLUMN IF NOT EXISTS income_sources JSONB;
@@ -0,0 +1,11 @@
+-- Migration: 034_add_decision_letters.sql
+-- Description: Approval letters and adverse action notices generated when underwriting decides an
+-- application, with the decision's reason codes and when the letter was delivered to the borrower,
+-- which is what the 30-day notice requirement is measured against
+
+ALTER TABLE generated_documents DROP CONSTRAINT IF EXISTS generated_documents_document_type_check;
+ALTER TABLE generated_documents ADD CONSTRAINT generated_documents_document_type_check
+    CHECK (document_type IN ('loan_agreement', 'tila_disclosure', 'adverse_action_notice', 'approval_letter'));
+
+ALTER TABLE generated_documents ADD COLUMN IF NOT EXISTS reason_codes JSONB;
+ALTER TABLE generated_documents ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMP WITH TIME ZONE;
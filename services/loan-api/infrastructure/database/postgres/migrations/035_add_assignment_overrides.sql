@@ -0,0 +1,11 @@
+-- Migration: 035_add_assignment_overrides.sql
+-- Description: Team leads can override the assignment rules and place an application in manual
+-- review with any officer licensed in the borrower's state. Auto-assignment goes round-robin
+-- within the officers eligible for an application, starting with the one assigned longest ago.
+
+ALTER TABLE application_assignments DROP CONSTRAINT IF EXISTS application_assignments_method_check;
+ALTER TABLE application_assignments ADD CONSTRAINT application_assignments_method_check
+    CHECK (method IN ('auto', 'claim', 'reassign', 'override'));
+
+CREATE INDEX IF NOT EXISTS idx_application_assignments_officer_latest
+    ON application_assignments(officer_id, assigned_at DESC);
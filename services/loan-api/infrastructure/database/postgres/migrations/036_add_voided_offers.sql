@@ -0,0 +1,7 @@
+-- Migration: 036_add_voided_offers.sql
+-- Description: When a workflow fails midway, its compensation voids the offers it made so the
+-- borrower cannot select or fund them. Offers are voided along with their groups.
+
+ALTER TABLE offer_groups DROP CONSTRAINT IF EXISTS offer_groups_status_check;
+ALTER TABLE offer_groups ADD CONSTRAINT offer_groups_status_check
+    CHECK (status IN ('open', 'selected', 'expired', 'voided'));
@@ -0,0 +1,7 @@
+-- Migration: 038_add_sla_withdrawal_reason.sql
+-- Description: Allow the sla_breached withdrawal reason, recorded when an SLA rule withdraws an
+-- application on its own. Borrowers cannot choose it.
+
+ALTER TABLE application_withdrawals DROP CONSTRAINT IF EXISTS application_withdrawals_reason_check;
+ALTER TABLE application_withdrawals ADD CONSTRAINT application_withdrawals_reason_check
+    CHECK (reason IN ('found_better_offer', 'no_longer_needed', 'terms_not_acceptable', 'process_too_slow', 'other', 'sla_breached'));
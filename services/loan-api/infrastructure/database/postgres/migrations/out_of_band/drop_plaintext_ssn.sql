@@ -0,0 +1,18 @@
+-- Migration: out_of_band/drop_plaintext_ssn.sql
+-- Description: Drop the plaintext SSN column once the SSN backfill job has tokenized every SSN
+-- stored before 003_replace_ssn_with_token.sql.
+--
+-- Not part of the numbered chain: run it by hand after the loan service logs "SSN backfill
+-- completed" and the check below passes, so it never holds back later migrations. The backfill
+-- job stops looking for plaintext SSNs once the column is gone.
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'users' AND column_name = 'ssn')
+        AND EXISTS (SELECT 1 FROM users WHERE ssn IS NOT NULL AND ssn <> '') THEN
+        RAISE EXCEPTION 'users still has plaintext SSNs; let the SSN backfill job finish before dropping the column';
+    END IF;
+END $$;
+
+DROP INDEX IF EXISTS idx_users_ssn;
+ALTER TABLE users DROP COLUMN IF EXISTS ssn;
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// CreatePreQualification records a pre-qualification result
+func (r *LoanRepository) CreatePreQualification(ctx context.Context, result *domain.PreQualifyResult) error {
+	request, err := json.Marshal(result.Request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pre-qualification request: %w", err)
+	}
+	terms, err := json.Marshal(result.RecommendedTerms)
+	if err != nil {
+		return fmt.Errorf("failed to marshal recommended terms: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO prequalifications (
+			id, user_id, request, qualified, max_loan_amount, min_interest_rate, max_interest_rate,
+			recommended_terms, dti_ratio, message, application_id, expires_at, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+		)`,
+		result.ID, result.UserID, string(request), result.Qualified, result.MaxLoanAmount, result.MinInterestRate,
+		result.MaxInterestRate, string(terms), result.DTIRatio, result.Message, result.ApplicationID,
+		result.ExpiresAt, result.CreatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create pre-qualification",
+			zap.String("user_id", result.UserID),
+			zap.Error(err))
+		return fmt.Errorf("failed to create pre-qualification: %w", err)
+	}
+	return nil
+}
+
+// GetPreQualification retrieves a pre-qualification result by ID
+func (r *LoanRepository) GetPreQualification(ctx context.Context, id string) (*domain.PreQualifyResult, error) {
+	var result domain.PreQualifyResult
+	var request, terms []byte
+	var applicationID sql.NullString
+	err := r.db.QueryRow(ctx, `
+		SELECT id, user_id, request, qualified, max_loan_amount, min_interest_rate, max_interest_rate,
+			recommended_terms, dti_ratio, message, application_id, expires_at, created_at
+		FROM prequalifications WHERE id = $1`,
+		id,
+	).Scan(
+		&result.ID, &result.UserID, &request, &result.Qualified, &result.MaxLoanAmount, &result.MinInterestRate,
+		&result.MaxInterestRate, &terms, &result.DTIRatio, &result.Message, &applicationID, &result.ExpiresAt,
+		&result.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("pre-qualification not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pre-qualification: %w", err)
+	}
+
+	if err := json.Unmarshal(request, &result.Request); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pre-qualification request: %w", err)
+	}
+	if err := json.Unmarshal(terms, &result.RecommendedTerms); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal recommended terms: %w", err)
+	}
+	if applicationID.Valid {
+		result.ApplicationID = &applicationID.String
+	}
+	return &result, nil
+}
+
+// MarkPreQualificationConverted records the application a pre-qualification was converted into. A
+// pre-qualification is only converted once; converting it again fails with "already converted".
+func (r *LoanRepository) MarkPreQualificationConverted(ctx context.Context, id, applicationID string) error {
+	result, err := r.db.Exec(ctx, `
+		UPDATE prequalifications SET application_id = $1
+		WHERE id = $2 AND application_id IS NULL`,
+		applicationID, id,
+	)
+	if err != nil {
+		r.logger.Error("Failed to mark pre-qualification converted",
+			zap.String("prequalification_id", id),
+			zap.Error(err))
+		return fmt.Errorf("failed to mark pre-qualification converted: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("pre-qualification already converted: %s", id)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// PricingRepository implements application.PricingRepository interface
+type PricingRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewPricingRepository creates a new pricing repository
+func NewPricingRepository(db *Connection, logger *zap.Logger) *PricingRepository {
+	return &PricingRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const pricingRateColumns = `
+			id, product, credit_tier, risk_level, min_term_months, max_term_months,
+			interest_rate, origination_fee_rate, effective_from, effective_to, created_by, created_at, updated_at`
+
+// scanPricingRate scans a row selected with pricingRateColumns
+func scanPricingRate(row interface{ Scan(...interface{}) error }) (*domain.PricingRate, error) {
+	var rate domain.PricingRate
+	var createdBy sql.NullString
+	err := row.Scan(
+		&rate.ID, &rate.Product, &rate.CreditTier, &rate.RiskLevel, &rate.MinTermMonths, &rate.MaxTermMonths,
+		&rate.InterestRate, &rate.OriginationFeeRate, &rate.EffectiveFrom, &rate.EffectiveTo, &createdBy,
+		&rate.CreatedAt, &rate.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	rate.CreatedBy = createdBy.String
+	return &rate, nil
+}
+
+// CreateRate creates a new rate matrix entry
+func (r *PricingRepository) CreateRate(ctx context.Context, rate *domain.PricingRate) error {
+	query := `
+		INSERT INTO pricing_rates (` + pricingRateColumns + `
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+		)`
+
+	_, err := r.db.Exec(ctx, query,
+		rate.ID, rate.Product, rate.CreditTier, rate.RiskLevel, rate.MinTermMonths, rate.MaxTermMonths,
+		rate.InterestRate, rate.OriginationFeeRate, rate.EffectiveFrom, rate.EffectiveTo, rate.CreatedBy,
+		rate.CreatedAt, rate.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create pricing rate", zap.Error(err), zap.String("rate_id", rate.ID))
+		return fmt.Errorf("failed to create pricing rate: %w", err)
+	}
+
+	return nil
+}
+
+// GetRate retrieves a rate matrix entry by ID
+func (r *PricingRepository) GetRate(ctx context.Context, id string) (*domain.PricingRate, error) {
+	query := `SELECT ` + pricingRateColumns + ` FROM pricing_rates WHERE id = $1`
+
+	rate, err := scanPricingRate(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("pricing rate not found: %s", id)
+		}
+		r.logger.Error("Failed to get pricing rate", zap.Error(err), zap.String("rate_id", id))
+		return nil, fmt.Errorf("failed to get pricing rate: %w", err)
+	}
+
+	return rate, nil
+}
+
+// UpdateRate updates an existing rate matrix entry
+func (r *PricingRepository) UpdateRate(ctx context.Context, rate *domain.PricingRate) error {
+	query := `
+		UPDATE pricing_rates SET
+			min_term_months = $1, max_term_months = $2, interest_rate = $3, origination_fee_rate = $4,
+			effective_from = $5, effective_to = $6, updated_at = $7
+		WHERE id = $8`
+
+	result, err := r.db.Exec(ctx, query,
+		rate.MinTermMonths, rate.MaxTermMonths, rate.InterestRate, rate.OriginationFeeRate,
+		rate.EffectiveFrom, rate.EffectiveTo, rate.UpdatedAt, rate.ID,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update pricing rate", zap.Error(err), zap.String("rate_id", rate.ID))
+		return fmt.Errorf("failed to update pricing rate: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("pricing rate not found: %s", rate.ID)
+	}
+
+	return nil
+}
+
+// DeleteRate deletes a rate matrix entry
+func (r *PricingRepository) DeleteRate(ctx context.Context, id string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM pricing_rates WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete pricing rate", zap.Error(err), zap.String("rate_id", id))
+		return fmt.Errorf("failed to delete pricing rate: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("pricing rate not found: %s", id)
+	}
+
+	return nil
+}
+
+// ListRates lists rate matrix entries matching the query, ordered by matrix cell and start date
+func (r *PricingRepository) ListRates(ctx context.Context, query *domain.PricingRateQuery) ([]*domain.PricingRate, error) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if query.Product != "" {
+		add("product = $%d", query.Product)
+	}
+	if query.CreditTier != "" {
+		add("credit_tier = $%d", query.CreditTier)
+	}
+	if query.RiskLevel != "" {
+		add("risk_level = $%d", query.RiskLevel)
+	}
+	if !query.ActiveAt.IsZero() {
+		add("effective_from <= $%d", query.ActiveAt)
+		conditions = append(conditions, fmt.Sprintf("(effective_to IS NULL OR effective_to > $%d)", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	sqlQuery := `SELECT ` + pricingRateColumns + ` FROM pricing_rates` + where + `
+		ORDER BY product, credit_tier, risk_level, min_term_months, effective_from, id`
+
+	rows, err := r.db.Query(ctx, sqlQuery, args...)
+	if err != nil {
+		r.logger.Error("Failed to list pricing rates", zap.Error(err))
+		return nil, fmt.Errorf("failed to list pricing rates: %w", err)
+	}
+	defer rows.Close()
+
+	rates := []*domain.PricingRate{}
+	for rows.Next() {
+		rate, err := scanPricingRate(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan pricing rate row", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan pricing rate: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return rates, nil
+}
+
+// FindRate returns the rate in effect at the given time for a product, tier, risk level and term.
+// The latest start date wins, with the ID as a tie-break so the match is always the same row.
+func (r *PricingRepository) FindRate(ctx context.Context, product string, tier domain.CreditTier, riskLevel domain.RiskLevel, termMonths int, at time.Time) (*domain.PricingRate, error) {
+	query := `SELECT ` + pricingRateColumns + ` FROM pricing_rates
+		WHERE product = $1 AND credit_tier = $2 AND risk_level = $3
+			AND min_term_months <= $4 AND max_term_months >= $4
+			AND effective_from <= $5 AND (effective_to IS NULL OR effective_to > $5)
+		ORDER BY effective_from DESC, id
+		LIMIT 1`
+
+	rate, err := scanPricingRate(r.db.QueryRow(ctx, query, product, tier, riskLevel, termMonths, at))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("pricing rate not found for %s/%s/%s/%d", product, tier, riskLevel, termMonths)
+		}
+		r.logger.Error("Failed to find pricing rate", zap.Error(err))
+		return nil, fmt.Errorf("failed to find pricing rate: %w", err)
+	}
+
+	return rate, nil
+}
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+const rateLockColumns = `
+			id, offer_id, application_id, product, interest_rate, apr, trigger, locked_at, expires_at`
+
+// scanRateLock scans a row selected with rateLockColumns
+func scanRateLock(row interface{ Scan(...interface{}) error }) (*domain.RateLock, error) {
+	var lock domain.RateLock
+	if err := row.Scan(
+		&lock.ID, &lock.OfferID, &lock.ApplicationID, &lock.Product, &lock.InterestRate, &lock.APR,
+		&lock.Trigger, &lock.LockedAt, &lock.ExpiresAt,
+	); err != nil {
+		return nil, err
+	}
+	return &lock, nil
+}
+
+const insertRateLockQuery = `
+		INSERT INTO rate_locks (
+			id, offer_id, application_id, product, interest_rate, apr, trigger, locked_at, expires_at, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		)`
+
+// rateLockInsertArgs returns the arguments of insertRateLockQuery for a lock
+func rateLockInsertArgs(lock *domain.RateLock) []interface{} {
+	return []interface{}{
+		lock.ID, lock.OfferID, lock.ApplicationID, lock.Product, lock.InterestRate, lock.APR, lock.Trigger,
+		lock.LockedAt, lock.ExpiresAt, time.Now().UTC(),
+	}
+}
+
+// CreateRateLock records a rate lock on an offer
+func (r *LoanRepository) CreateRateLock(ctx context.Context, lock *domain.RateLock) error {
+	if _, err := r.db.Exec(ctx, insertRateLockQuery, rateLockInsertArgs(lock)...); err != nil {
+		r.logger.Error("Failed to create rate lock",
+			zap.String("offer_id", lock.OfferID),
+			zap.Error(err))
+		return fmt.Errorf("failed to create rate lock: %w", err)
+	}
+	return nil
+}
+
+// GetRateLock retrieves an offer's latest rate lock
+func (r *LoanRepository) GetRateLock(ctx context.Context, offerID string) (*domain.RateLock, error) {
+	lock, err := scanRateLock(r.db.QueryRow(ctx, `SELECT `+rateLockColumns+`
+		FROM rate_locks WHERE offer_id = $1
+		ORDER BY locked_at DESC LIMIT 1`, offerID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("rate lock not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rate lock: %w", err)
+	}
+	return lock, nil
+}
+
+// ListRateLocks retrieves the latest rate lock of each of an application's offers
+func (r *LoanRepository) ListRateLocks(ctx context.Context, applicationID string) ([]*domain.RateLock, error) {
+	rows, err := r.db.Query(ctx, `SELECT DISTINCT ON (offer_id)`+rateLockColumns+`
+		FROM rate_locks WHERE application_id = $1
+		ORDER BY offer_id, locked_at DESC`, applicationID)
+	if err != nil {
+		r.logger.Error("Failed to list rate locks",
+			zap.String("application_id", applicationID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to list rate locks: %w", err)
+	}
+	defer rows.Close()
+
+	locks := []*domain.RateLock{}
+	for rows.Next() {
+		lock, err := scanRateLock(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rate lock: %w", err)
+		}
+		locks = append(locks, lock)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return locks, nil
+}
+
+// RepriceOffer stores a pending offer's new figures and pricing audit together with the rate lock
+// taken on them, in one transaction. An offer that is no longer pending fails with "not pending".
+func (r *LoanRepository) RepriceOffer(ctx context.Context, offer *domain.LoanOffer, lock *domain.RateLock) error {
+	logger := r.logger.With(
+		zap.String("operation", "reprice_offer"),
+		zap.String("offer_id", offer.ID),
+	)
+
+	pricingAudit, err := json.Marshal(offer.PricingAudit)
+	if err != nil {
+		logger.Error("Failed to encode pricing audit", zap.Error(err))
+		return fmt.Errorf("failed to encode pricing audit: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE loan_offers SET
+			interest_rate = $1, monthly_payment = $2, total_interest = $3, apr = $4, pricing_audit = $5,
+			updated_at = $6
+		WHERE id = $7 AND status = $8`,
+		offer.InterestRate, offer.MonthlyPayment, offer.TotalInterest, offer.APR, string(pricingAudit),
+		time.Now().UTC(), offer.ID, domain.OfferStatusPending,
+	)
+	if err != nil {
+		logger.Error("Failed to reprice offer", zap.Error(err))
+		return fmt.Errorf("failed to reprice offer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("offer not pending: %s", offer.ID)
+	}
+
+	if _, err := tx.ExecContext(ctx, insertRateLockQuery, rateLockInsertArgs(lock)...); err != nil {
+		logger.Error("Failed to create rate lock", zap.Error(err))
+		return fmt.Errorf("failed to create rate lock: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit offer repricing", zap.Error(err))
+		return fmt.Errorf("failed to commit offer repricing: %w", err)
+	}
+
+	logger.Info("Offer repriced successfully")
+	return nil
+}
@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// CreateRepaymentSchedule records a loan's repayment schedule with its installments in one
+// transaction. An application has one schedule; creating another fails with "already exists".
+func (r *LoanRepository) CreateRepaymentSchedule(ctx context.Context, schedule *domain.RepaymentSchedule) error {
+	logger := r.logger.With(
+		zap.String("operation", "create_repayment_schedule"),
+		zap.String("application_id", schedule.ApplicationID),
+	)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO repayment_schedules (
+			id, application_id, offer_id, disbursement_id, principal, interest_rate, term_months, payment_amount,
+			payment_due_day, funded_at, first_payment_date, maturity_date, total_interest, total_of_payments,
+			created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+		) ON CONFLICT (application_id) DO NOTHING`,
+		schedule.ID, schedule.ApplicationID, schedule.OfferID, schedule.DisbursementID, schedule.Principal,
+		schedule.InterestRate, schedule.TermMonths, schedule.PaymentAmount, schedule.PaymentDueDay,
+		schedule.FundedAt, schedule.FirstPaymentDate, schedule.MaturityDate, schedule.TotalInterest,
+		schedule.TotalOfPayments, schedule.CreatedAt,
+	)
+	if err != nil {
+		logger.Error("Failed to create repayment schedule", zap.Error(err))
+		return fmt.Errorf("failed to create repayment schedule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("repayment schedule already exists for application: %s", schedule.ApplicationID)
+	}
+
+	for _, installment := range schedule.Installments {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO repayment_installments (
+				id, schedule_id, application_id, installment_number, due_date, payment_amount, principal,
+				interest, remaining_balance, status, created_at
+			) VALUES (
+				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+			)`,
+			installment.ID, installment.ScheduleID, installment.ApplicationID, installment.InstallmentNumber,
+			installment.DueDate, installment.PaymentAmount, installment.Principal, installment.Interest,
+			installment.RemainingBalance, installment.Status, installment.CreatedAt,
+		); err != nil {
+			logger.Error("Failed to create repayment installment",
+				zap.Int("installment_number", installment.InstallmentNumber),
+				zap.Error(err))
+			return fmt.Errorf("failed to create repayment installment: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit repayment schedule", zap.Error(err))
+		return fmt.Errorf("failed to commit repayment schedule: %w", err)
+	}
+
+	return nil
+}
+
+// GetRepaymentSchedule retrieves an application's repayment schedule with its installments in order
+func (r *LoanRepository) GetRepaymentSchedule(ctx context.Context, applicationID string) (*domain.RepaymentSchedule, error) {
+	var schedule domain.RepaymentSchedule
+	var disbursementID sql.NullString
+	err := r.db.QueryRow(ctx, `
+		SELECT id, application_id, offer_id, disbursement_id, principal, interest_rate, term_months,
+			payment_amount, payment_due_day, funded_at, first_payment_date, maturity_date, total_interest,
+			total_of_payments, created_at
+		FROM repayment_schedules WHERE application_id = $1`,
+		applicationID,
+	).Scan(
+		&schedule.ID, &schedule.ApplicationID, &schedule.OfferID, &disbursementID, &schedule.Principal,
+		&schedule.InterestRate, &schedule.TermMonths, &schedule.PaymentAmount, &schedule.PaymentDueDay,
+		&schedule.FundedAt, &schedule.FirstPaymentDate, &schedule.MaturityDate, &schedule.TotalInterest,
+		&schedule.TotalOfPayments, &schedule.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("repayment schedule not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repayment schedule: %w", err)
+	}
+	if disbursementID.Valid {
+		schedule.DisbursementID = &disbursementID.String
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, schedule_id, application_id, installment_number, due_date, payment_amount, principal,
+			interest, remaining_balance, status, created_at
+		FROM repayment_installments WHERE schedule_id = $1
+		ORDER BY installment_number`,
+		schedule.ID)
+	if err != nil {
+		r.logger.Error("Failed to list repayment installments",
+			zap.String("application_id", applicationID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to list repayment installments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var installment domain.RepaymentInstallment
+		if err := rows.Scan(
+			&installment.ID, &installment.ScheduleID, &installment.ApplicationID, &installment.InstallmentNumber,
+			&installment.DueDate, &installment.PaymentAmount, &installment.Principal, &installment.Interest,
+			&installment.RemainingBalance, &installment.Status, &installment.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan repayment installment: %w", err)
+		}
+		schedule.Installments = append(schedule.Installments, &installment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return &schedule, nil
+}
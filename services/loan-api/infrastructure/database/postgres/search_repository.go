@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// searchApplicationsQuery ranks applications by the best of the trigram similarity of the applicant's
+// name, email and application number to the term (exact email and number matches rank 1) and the
+// full-text rank of the best matching note. Substring matches use ILIKE so partial numbers and email
+// fragments are found through the trigram indexes too.
+var searchApplicationsQuery = fmt.Sprintf(`
+	WITH note_hits AS (
+		SELECT DISTINCT ON (n.application_id)
+			n.application_id,
+			ts_rank(to_tsvector('simple', n.body), q.query) AS note_rank,
+			ts_headline('simple', n.body, q.query, 'StartSel=%s, StopSel=%s, MaxWords=20, MinWords=5, MaxFragments=2') AS note_headline
+		FROM application_notes n, plainto_tsquery('simple', $1) AS q(query)
+		WHERE to_tsvector('simple', n.body) @@ q.query
+		ORDER BY n.application_id, note_rank DESC
+	),
+	matched AS (
+		SELECT
+			a.id, a.application_number, a.user_id,
+			COALESCE(u.first_name || ' ' || u.last_name, '') AS applicant_name, COALESCE(u.email, '') AS email,
+			a.loan_amount, a.current_state, a.status, a.created_at,
+			((u.first_name || ' ' || u.last_name) %% $1 OR (u.first_name || ' ' || u.last_name) ILIKE $2) AS name_match,
+			(u.email %% $1 OR u.email ILIKE $2) AS email_match,
+			(a.application_number ILIKE $2) AS number_match,
+			GREATEST(
+				COALESCE(similarity(u.first_name || ' ' || u.last_name, $1), 0),
+				CASE WHEN lower(u.email) = lower($1) THEN 1 ELSE COALESCE(similarity(u.email, $1), 0) END,
+				CASE WHEN lower(a.application_number) = lower($1) THEN 1 ELSE similarity(a.application_number, $1) END,
+				COALESCE(h.note_rank, 0)
+			) AS rank,
+			h.note_headline
+		FROM loan_applications a
+		LEFT JOIN users u ON u.id = a.user_id
+		LEFT JOIN note_hits h ON h.application_id = a.id
+		WHERE (u.first_name || ' ' || u.last_name) %% $1 OR (u.first_name || ' ' || u.last_name) ILIKE $2
+			OR u.email %% $1 OR u.email ILIKE $2
+			OR a.application_number ILIKE $2
+			OR h.application_id IS NOT NULL
+	)
+	SELECT
+		id, application_number, user_id, applicant_name, email, loan_amount, current_state, status, created_at,
+		name_match, email_match, number_match, rank, note_headline, COUNT(*) OVER () AS total
+	FROM matched
+	ORDER BY rank DESC, created_at DESC, id
+	LIMIT $3 OFFSET $4`, domain.SearchHighlightStartTag, domain.SearchHighlightStopTag)
+
+// SearchApplications returns one page of applications matching the search term, best match first,
+// along with the number of matches across every page
+func (r *LoanRepository) SearchApplications(ctx context.Context, query *domain.ApplicationSearchQuery) ([]*domain.ApplicationSearchResult, int, error) {
+	logger := r.logger.With(zap.String("operation", "search_applications"))
+
+	rows, err := r.db.Query(ctx, searchApplicationsQuery,
+		query.Q, "%"+escapeLikePattern(query.Q)+"%", query.PageSize, query.Offset())
+	if err != nil {
+		logger.Error("Failed to search applications", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to search applications: %w", err)
+	}
+	defer rows.Close()
+
+	total := 0
+	results := make([]*domain.ApplicationSearchResult, 0, query.PageSize)
+	for rows.Next() {
+		var result domain.ApplicationSearchResult
+		var nameMatch, emailMatch, numberMatch *bool
+		var noteHeadline *string
+		if err := rows.Scan(
+			&result.ApplicationID, &result.ApplicationNumber, &result.UserID, &result.ApplicantName, &result.Email,
+			&result.LoanAmount, &result.CurrentState, &result.Status, &result.CreatedAt,
+			&nameMatch, &emailMatch, &numberMatch, &result.Rank, &noteHeadline, &total,
+		); err != nil {
+			logger.Error("Failed to scan search result", zap.Error(err))
+			return nil, 0, fmt.Errorf("failed to scan search result: %w", err)
+		}
+
+		result.Highlights = make(map[string]string)
+		if nameMatch != nil && *nameMatch {
+			result.MatchedFields = append(result.MatchedFields, domain.SearchFieldApplicantName)
+			result.Highlights[domain.SearchFieldApplicantName] = domain.HighlightSearchTerm(result.ApplicantName, query.Q)
+		}
+		if emailMatch != nil && *emailMatch {
+			result.MatchedFields = append(result.MatchedFields, domain.SearchFieldEmail)
+			result.Highlights[domain.SearchFieldEmail] = domain.HighlightSearchTerm(result.Email, query.Q)
+		}
+		if numberMatch != nil && *numberMatch {
+			result.MatchedFields = append(result.MatchedFields, domain.SearchFieldApplicationNumber)
+			result.Highlights[domain.SearchFieldApplicationNumber] = domain.HighlightSearchTerm(result.ApplicationNumber, query.Q)
+		}
+		if noteHeadline != nil {
+			result.MatchedFields = append(result.MatchedFields, domain.SearchFieldNotes)
+			result.Highlights[domain.SearchFieldNotes] = *noteHeadline
+		}
+		results = append(results, &result)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating over search results", zap.Error(err))
+		return nil, 0, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return results, total, nil
+}
+
+// escapeLikePattern escapes the LIKE wildcards in a search term so they match literally
+func escapeLikePattern(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
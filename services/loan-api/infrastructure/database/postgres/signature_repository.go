@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// signatureEnvelopeColumns are the columns scanned by scanSignatureEnvelope
+const signatureEnvelopeColumns = `id, application_id, offer_id, provider, provider_envelope_id, status,
+	signed_document_id, sent_at, completed_at, created_at, updated_at`
+
+// CreateSignatureEnvelope records an agreement sent for signature
+func (r *LoanRepository) CreateSignatureEnvelope(ctx context.Context, envelope *domain.SignatureEnvelope) error {
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO signature_envelopes (
+			id, application_id, offer_id, provider, provider_envelope_id, status, sent_at, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)`,
+		envelope.ID, envelope.ApplicationID, envelope.OfferID, envelope.Provider, envelope.ProviderEnvelopeID,
+		envelope.Status, envelope.SentAt, envelope.CreatedAt, envelope.UpdatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create signature envelope",
+			zap.String("application_id", envelope.ApplicationID),
+			zap.Error(err))
+		return fmt.Errorf("failed to create signature envelope: %w", err)
+	}
+	return nil
+}
+
+// GetSignatureEnvelopeByProviderID retrieves an envelope by the provider's envelope ID
+func (r *LoanRepository) GetSignatureEnvelopeByProviderID(ctx context.Context, provider, providerEnvelopeID string) (*domain.SignatureEnvelope, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT `+signatureEnvelopeColumns+`
+		FROM signature_envelopes WHERE provider = $1 AND provider_envelope_id = $2`,
+		provider, providerEnvelopeID)
+
+	envelope, err := scanSignatureEnvelope(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("signature envelope not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signature envelope: %w", err)
+	}
+	return envelope, nil
+}
+
+// GetLatestSignatureEnvelope retrieves the agreement most recently sent for an application
+func (r *LoanRepository) GetLatestSignatureEnvelope(ctx context.Context, applicationID string) (*domain.SignatureEnvelope, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT `+signatureEnvelopeColumns+`
+		FROM signature_envelopes WHERE application_id = $1
+		ORDER BY created_at DESC LIMIT 1`,
+		applicationID)
+
+	envelope, err := scanSignatureEnvelope(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("signature envelope not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signature envelope: %w", err)
+	}
+	return envelope, nil
+}
+
+// UpdateSignatureEnvelope saves an envelope's status and signed document
+func (r *LoanRepository) UpdateSignatureEnvelope(ctx context.Context, envelope *domain.SignatureEnvelope) error {
+	if _, err := r.db.Exec(ctx, `
+		UPDATE signature_envelopes SET status = $1, signed_document_id = $2, completed_at = $3, updated_at = $4
+		WHERE id = $5`,
+		envelope.Status, envelope.SignedDocumentID, envelope.CompletedAt, envelope.UpdatedAt, envelope.ID,
+	); err != nil {
+		r.logger.Error("Failed to update signature envelope",
+			zap.String("envelope_id", envelope.ID),
+			zap.Error(err))
+		return fmt.Errorf("failed to update signature envelope: %w", err)
+	}
+	return nil
+}
+
+// scanSignatureEnvelope scans a row of signatureEnvelopeColumns
+func scanSignatureEnvelope(row interface{ Scan(...interface{}) error }) (*domain.SignatureEnvelope, error) {
+	var envelope domain.SignatureEnvelope
+	var signedDocumentID sql.NullString
+	var completedAt sql.NullTime
+	if err := row.Scan(
+		&envelope.ID, &envelope.ApplicationID, &envelope.OfferID, &envelope.Provider, &envelope.ProviderEnvelopeID,
+		&envelope.Status, &signedDocumentID, &envelope.SentAt, &completedAt, &envelope.CreatedAt, &envelope.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if signedDocumentID.Valid {
+		envelope.SignedDocumentID = &signedDocumentID.String
+	}
+	if completedAt.Valid {
+		envelope.CompletedAt = &completedAt.Time
+	}
+	return &envelope, nil
+}
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// ListSLACandidates lists applications that have been in the rule's state past its SLA as of now
+// and have not been escalated by the rule for this stay, longest waiting first
+func (r *LoanRepository) ListSLACandidates(ctx context.Context, rule *domain.SLARule, now time.Time, limit int) ([]*domain.SLACandidate, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT a.id, a.user_id, a.current_state, a.state_entered_at
+		FROM loan_applications a
+		WHERE a.current_state = $1 AND a.state_entered_at <= $2
+			AND NOT EXISTS (
+				SELECT 1 FROM application_sla_breaches b
+				WHERE b.application_id = a.id AND b.sla_name = $3 AND b.state_entered_at = a.state_entered_at
+			)
+		ORDER BY a.state_entered_at, a.id
+		LIMIT $4`,
+		rule.State, now.Add(-rule.Within), rule.Name, limit)
+	if err != nil {
+		r.logger.Error("Failed to list SLA candidates", zap.String("sla_name", rule.Name), zap.Error(err))
+		return nil, fmt.Errorf("failed to list SLA candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []*domain.SLACandidate
+	for rows.Next() {
+		var candidate domain.SLACandidate
+		if err := rows.Scan(&candidate.ApplicationID, &candidate.UserID, &candidate.State, &candidate.StateEnteredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan SLA candidate: %w", err)
+		}
+		candidates = append(candidates, &candidate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+	return candidates, nil
+}
+
+// CreateSLABreach records an SLA escalation, reporting false when another instance already recorded
+// it for the same stay
+func (r *LoanRepository) CreateSLABreach(ctx context.Context, breach *domain.SLABreach) (bool, error) {
+	result, err := r.db.Exec(ctx, `
+		INSERT INTO application_sla_breaches (
+			id, application_id, sla_name, state, action, state_entered_at, due_at, breached_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)
+		ON CONFLICT (application_id, sla_name, state_entered_at) DO NOTHING`,
+		breach.ID, breach.ApplicationID, breach.SLAName, breach.State, breach.Action,
+		breach.StateEnteredAt, breach.DueAt, breach.BreachedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create SLA breach",
+			zap.String("application_id", breach.ApplicationID),
+			zap.String("sla_name", breach.SLAName),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to create SLA breach: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected == 1, nil
+}
+
+// UpdateSLABreachError records why a breach's escalation action failed
+func (r *LoanRepository) UpdateSLABreachError(ctx context.Context, id, actionError string) error {
+	if _, err := r.db.Exec(ctx, `UPDATE application_sla_breaches SET action_error = $1 WHERE id = $2`, actionError, id); err != nil {
+		r.logger.Error("Failed to update SLA breach", zap.String("breach_id", id), zap.Error(err))
+		return fmt.Errorf("failed to update SLA breach: %w", err)
+	}
+	return nil
+}
+
+// BumpApplicationPriority raises an application's review priority by one
+func (r *LoanRepository) BumpApplicationPriority(ctx context.Context, applicationID string) error {
+	result, err := r.db.Exec(ctx, `UPDATE loan_applications SET priority = priority + 1 WHERE id = $1`, applicationID)
+	if err != nil {
+		r.logger.Error("Failed to bump application priority", zap.String("application_id", applicationID), zap.Error(err))
+		return fmt.Errorf("failed to bump application priority: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("application not found: %s", applicationID)
+	}
+	return nil
+}
+
+// ListSLABreaches lists SLA breaches, newest first, with whether each application is still in the
+// breached stay and its current priority
+func (r *LoanRepository) ListSLABreaches(ctx context.Context, query *domain.SLABreachQuery, now time.Time) ([]*domain.SLABreach, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			b.id, b.application_id, b.sla_name, b.state, b.action, b.state_entered_at, b.due_at, b.breached_at,
+			b.action_error,
+			COALESCE(a.current_state = b.state AND a.state_entered_at = b.state_entered_at, false) AS open,
+			COALESCE(a.priority, 0)
+		FROM application_sla_breaches b
+		LEFT JOIN loan_applications a ON a.id = b.application_id
+		WHERE ($1 = '' OR b.state = $1) AND ($2 = '' OR b.sla_name = $2)
+			AND (NOT $3 OR (a.current_state = b.state AND a.state_entered_at = b.state_entered_at))
+		ORDER BY b.breached_at DESC, b.id
+		LIMIT $4`,
+		string(query.State), query.SLAName, query.OpenOnly, query.Limit)
+	if err != nil {
+		r.logger.Error("Failed to list SLA breaches", zap.Error(err))
+		return nil, fmt.Errorf("failed to list SLA breaches: %w", err)
+	}
+	defer rows.Close()
+
+	breaches := make([]*domain.SLABreach, 0)
+	for rows.Next() {
+		var breach domain.SLABreach
+		if err := rows.Scan(
+			&breach.ID, &breach.ApplicationID, &breach.SLAName, &breach.State, &breach.Action,
+			&breach.StateEnteredAt, &breach.DueAt, &breach.BreachedAt, &breach.ActionError,
+			&breach.Open, &breach.Priority,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan SLA breach: %w", err)
+		}
+		if breach.Open && now.After(breach.DueAt) {
+			breach.OverdueMinutes = int(now.Sub(breach.DueAt).Minutes())
+		}
+		breaches = append(breaches, &breach)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+	return breaches, nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/huuhoait/los-demo/services/loan-api/application"
 	"github.com/huuhoait/los-demo/services/loan-api/domain"
 )
 
@@ -34,25 +35,27 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *domain.User) (str
 
 	query := `
 		INSERT INTO users (
-			first_name, last_name, email, phone_number, date_of_birth, ssn,
+			first_name, last_name, email, phone_number, date_of_birth, ssn_token, ssn_last4,
 			street_address, city, state, zip_code, country, residence_type, time_at_address_months,
 			employer_name, job_title, time_employed_months, work_phone, work_email,
 			bank_name, account_type, account_number, routing_number,
+			zip_plus4, delivery_point_barcode, address_undeliverable,
 			created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
-			$14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14,
+			$15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28
 		) RETURNING id`
 
 	var userID string
 	err := r.db.QueryRow(ctx, query,
-		user.FirstName, user.LastName, user.Email, user.PhoneNumber, user.DateOfBirth, user.SSN,
+		user.FirstName, user.LastName, user.Email, user.PhoneNumber, user.DateOfBirth, user.SSNToken, user.SSNLast4,
 		user.Address.StreetAddress, user.Address.City, user.Address.State, user.Address.ZipCode,
 		user.Address.Country, user.Address.ResidenceType, user.Address.TimeAtAddress,
 		user.EmploymentInfo.EmployerName, user.EmploymentInfo.JobTitle, user.EmploymentInfo.TimeEmployed,
 		user.EmploymentInfo.WorkPhone, user.EmploymentInfo.WorkEmail,
 		user.BankingInfo.BankName, user.BankingInfo.AccountType, user.BankingInfo.AccountNumber,
 		user.BankingInfo.RoutingNumber,
+		user.Address.ZipPlus4, user.Address.DeliveryPointBarcode, user.Address.Undeliverable,
 		time.Now().UTC(), time.Now().UTC(),
 	).Scan(&userID)
 
@@ -75,10 +78,11 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id string) (*domain.Us
 
 	query := `
 		SELECT 
-			id, first_name, last_name, email, phone_number, date_of_birth, ssn,
+			id, first_name, last_name, email, phone_number, date_of_birth, ssn_token, ssn_last4,
 			street_address, city, state, zip_code, country, residence_type, time_at_address_months,
 			employer_name, job_title, time_employed_months, work_phone, work_email,
 			bank_name, account_type, account_number, routing_number,
+			zip_plus4, delivery_point_barcode, address_undeliverable,
 			created_at, updated_at
 		FROM users WHERE id = $1`
 
@@ -87,13 +91,14 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id string) (*domain.Us
 	var createdAt, updatedAt time.Time
 
 	err := r.db.QueryRow(ctx, query, id).Scan(
-		&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.PhoneNumber, &dateOfBirth, &user.SSN,
+		&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.PhoneNumber, &dateOfBirth, &user.SSNToken, &user.SSNLast4,
 		&user.Address.StreetAddress, &user.Address.City, &user.Address.State, &user.Address.ZipCode,
 		&user.Address.Country, &user.Address.ResidenceType, &user.Address.TimeAtAddress,
 		&user.EmploymentInfo.EmployerName, &user.EmploymentInfo.JobTitle, &user.EmploymentInfo.TimeEmployed,
 		&user.EmploymentInfo.WorkPhone, &user.EmploymentInfo.WorkEmail,
 		&user.BankingInfo.BankName, &user.BankingInfo.AccountType, &user.BankingInfo.AccountNumber,
 		&user.BankingInfo.RoutingNumber,
+		&user.Address.ZipPlus4, &user.Address.DeliveryPointBarcode, &user.Address.Undeliverable,
 		&createdAt, &updatedAt,
 	)
 
@@ -123,10 +128,11 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*dom
 
 	query := `
 		SELECT 
-			id, first_name, last_name, email, phone_number, date_of_birth, ssn,
+			id, first_name, last_name, email, phone_number, date_of_birth, ssn_token, ssn_last4,
 			street_address, city, state, zip_code, country, residence_type, time_at_address_months,
 			employer_name, job_title, time_employed_months, work_phone, work_email,
 			bank_name, account_type, account_number, routing_number,
+			zip_plus4, delivery_point_barcode, address_undeliverable,
 			created_at, updated_at
 		FROM users WHERE email = $1`
 
@@ -135,13 +141,14 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*dom
 	var createdAt, updatedAt time.Time
 
 	err := r.db.QueryRow(ctx, query, email).Scan(
-		&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.PhoneNumber, &dateOfBirth, &user.SSN,
+		&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.PhoneNumber, &dateOfBirth, &user.SSNToken, &user.SSNLast4,
 		&user.Address.StreetAddress, &user.Address.City, &user.Address.State, &user.Address.ZipCode,
 		&user.Address.Country, &user.Address.ResidenceType, &user.Address.TimeAtAddress,
 		&user.EmploymentInfo.EmployerName, &user.EmploymentInfo.JobTitle, &user.EmploymentInfo.TimeEmployed,
 		&user.EmploymentInfo.WorkPhone, &user.EmploymentInfo.WorkEmail,
 		&user.BankingInfo.BankName, &user.BankingInfo.AccountType, &user.BankingInfo.AccountNumber,
 		&user.BankingInfo.RoutingNumber,
+		&user.Address.ZipPlus4, &user.Address.DeliveryPointBarcode, &user.Address.Undeliverable,
 		&createdAt, &updatedAt,
 	)
 
@@ -171,21 +178,23 @@ func (r *UserRepository) UpdateUser(ctx context.Context, user *domain.User) erro
 
 	query := `
 		UPDATE users SET 
-			first_name = $1, last_name = $2, email = $3, phone_number = $4, date_of_birth = $5, ssn = $6,
-			street_address = $7, city = $8, state = $9, zip_code = $10, country = $11, residence_type = $12, time_at_address_months = $13,
-			employer_name = $14, job_title = $15, time_employed_months = $16, work_phone = $17, work_email = $18,
-			bank_name = $19, account_type = $20, account_number = $21, routing_number = $22,
-			updated_at = $23
-		WHERE id = $24`
+			first_name = $1, last_name = $2, email = $3, phone_number = $4, date_of_birth = $5, ssn_token = $6, ssn_last4 = $7,
+			street_address = $8, city = $9, state = $10, zip_code = $11, country = $12, residence_type = $13, time_at_address_months = $14,
+			employer_name = $15, job_title = $16, time_employed_months = $17, work_phone = $18, work_email = $19,
+			bank_name = $20, account_type = $21, account_number = $22, routing_number = $23,
+			zip_plus4 = $24, delivery_point_barcode = $25, address_undeliverable = $26,
+			updated_at = $27
+		WHERE id = $28`
 
 	result, err := r.db.Exec(ctx, query,
-		user.FirstName, user.LastName, user.Email, user.PhoneNumber, user.DateOfBirth, user.SSN,
+		user.FirstName, user.LastName, user.Email, user.PhoneNumber, user.DateOfBirth, user.SSNToken, user.SSNLast4,
 		user.Address.StreetAddress, user.Address.City, user.Address.State, user.Address.ZipCode,
 		user.Address.Country, user.Address.ResidenceType, user.Address.TimeAtAddress,
 		user.EmploymentInfo.EmployerName, user.EmploymentInfo.JobTitle, user.EmploymentInfo.TimeEmployed,
 		user.EmploymentInfo.WorkPhone, user.EmploymentInfo.WorkEmail,
 		user.BankingInfo.BankName, user.BankingInfo.AccountType, user.BankingInfo.AccountNumber,
 		user.BankingInfo.RoutingNumber,
+		user.Address.ZipPlus4, user.Address.DeliveryPointBarcode, user.Address.Undeliverable,
 		time.Now().UTC(), user.ID,
 	)
 
@@ -238,3 +247,57 @@ func (r *UserRepository) DeleteUser(ctx context.Context, id string) error {
 	logger.Info("User deleted successfully", zap.String("user_id", id))
 	return nil
 }
+
+// HasPlaintextSSNColumn reports whether users still has the plaintext SSN column, which is dropped
+// out of band once every SSN is tokenized
+func (r *UserRepository) HasPlaintextSSNColumn(ctx context.Context) (bool, error) {
+	var present bool
+	if err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = 'ssn'
+		)`).Scan(&present); err != nil {
+		r.logger.Error("Failed to check for the plaintext SSN column", zap.Error(err))
+		return false, fmt.Errorf("failed to check for the plaintext SSN column: %w", err)
+	}
+	return present, nil
+}
+
+// ListPlaintextSSNs lists users whose SSN is still stored in plaintext
+func (r *UserRepository) ListPlaintextSSNs(ctx context.Context, limit int) ([]application.PlaintextSSN, error) {
+	query := `
+		SELECT id, ssn FROM users
+		WHERE ssn IS NOT NULL AND ssn <> ''
+		ORDER BY id
+		LIMIT $1`
+
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		r.logger.Error("Failed to list plaintext SSNs", zap.Error(err))
+		return nil, fmt.Errorf("failed to list plaintext SSNs: %w", err)
+	}
+	defer rows.Close()
+
+	var users []application.PlaintextSSN
+	for rows.Next() {
+		var user application.PlaintextSSN
+		if err := rows.Scan(&user.UserID, &user.SSN); err != nil {
+			return nil, fmt.Errorf("failed to scan plaintext SSN: %w", err)
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// ReplaceSSNWithToken stores a user's SSN token and clears the plaintext SSN it replaces
+func (r *UserRepository) ReplaceSSNWithToken(ctx context.Context, userID, token, last4 string) error {
+	query := `
+		UPDATE users SET ssn_token = $1, ssn_last4 = $2, ssn = NULL, updated_at = $3
+		WHERE id = $4 AND ssn IS NOT NULL`
+
+	if _, err := r.db.Exec(ctx, query, token, last4, time.Now().UTC(), userID); err != nil {
+		r.logger.Error("Failed to replace SSN with token", zap.String("user_id", userID), zap.Error(err))
+		return fmt.Errorf("failed to replace SSN with token: %w", err)
+	}
+	return nil
+}
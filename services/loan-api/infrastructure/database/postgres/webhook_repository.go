@@ -0,0 +1,363 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// webhookSubscriptionColumns are the columns scanned by scanWebhookSubscription
+const webhookSubscriptionColumns = `id, name, url, secret, event_types, active, created_at, updated_at`
+
+// webhookDeliveryColumns are the columns scanned by scanWebhookDelivery
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_type, application_id, status, attempts,
+	next_attempt_at, response_status, last_error, delivered_at, created_at, updated_at`
+
+// CreateWebhookSubscription records a partner webhook endpoint
+func (r *LoanRepository) CreateWebhookSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	eventTypes, err := json.Marshal(subscription.EventTypes)
+	if err != nil {
+		return fmt.Errorf("failed to encode event types: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO webhook_subscriptions (
+			id, name, url, secret, event_types, active, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)`,
+		subscription.ID, subscription.Name, subscription.URL, subscription.Secret, string(eventTypes),
+		subscription.Active, subscription.CreatedAt, subscription.UpdatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create webhook subscription",
+			zap.String("subscription_id", subscription.ID),
+			zap.Error(err))
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookSubscription retrieves a webhook subscription by ID
+func (r *LoanRepository) GetWebhookSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT `+webhookSubscriptionColumns+`
+		FROM webhook_subscriptions WHERE id = $1`,
+		id)
+
+	subscription, err := scanWebhookSubscription(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("webhook subscription not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+// ListWebhookSubscriptions lists every webhook subscription, newest first
+func (r *LoanRepository) ListWebhookSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+webhookSubscriptionColumns+`
+		FROM webhook_subscriptions
+		ORDER BY created_at DESC, id`)
+	if err != nil {
+		r.logger.Error("Failed to list webhook subscriptions", zap.Error(err))
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []*domain.WebhookSubscription
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// UpdateWebhookSubscription saves whether a webhook subscription is active
+func (r *LoanRepository) UpdateWebhookSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	result, err := r.db.Exec(ctx, `
+		UPDATE webhook_subscriptions SET active = $1, updated_at = $2 WHERE id = $3`,
+		subscription.Active, subscription.UpdatedAt, subscription.ID,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update webhook subscription",
+			zap.String("subscription_id", subscription.ID),
+			zap.Error(err))
+		return fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook subscription not found")
+	}
+	return nil
+}
+
+// CreateWebhookEvent queues an event for delivery to its subscriptions
+func (r *LoanRepository) CreateWebhookEvent(ctx context.Context, event *domain.WebhookEvent) error {
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO webhook_events (id, event_type, application_id, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		event.ID, event.Type, event.ApplicationID, string(event.Data), event.CreatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create webhook event",
+			zap.String("application_id", event.ApplicationID),
+			zap.String("event_type", string(event.Type)),
+			zap.Error(err))
+		return fmt.Errorf("failed to create webhook event: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookEvent retrieves a webhook event by ID
+func (r *LoanRepository) GetWebhookEvent(ctx context.Context, id string) (*domain.WebhookEvent, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT id, event_type, application_id, payload, created_at
+		FROM webhook_events WHERE id = $1`,
+		id)
+
+	event, err := scanWebhookEvent(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("webhook event not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook event: %w", err)
+	}
+	return event, nil
+}
+
+// ListUndispatchedWebhookEvents lists the oldest events not yet queued for delivery
+func (r *LoanRepository) ListUndispatchedWebhookEvents(ctx context.Context, limit int) ([]*domain.WebhookEvent, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, event_type, application_id, payload, created_at
+		FROM webhook_events WHERE dispatched_at IS NULL
+		ORDER BY created_at, id
+		LIMIT $1`,
+		limit)
+	if err != nil {
+		r.logger.Error("Failed to list undispatched webhook events", zap.Error(err))
+		return nil, fmt.Errorf("failed to list undispatched webhook events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.WebhookEvent
+	for rows.Next() {
+		event, err := scanWebhookEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return events, nil
+}
+
+// MarkWebhookEventDispatched records that deliveries of an event were queued
+func (r *LoanRepository) MarkWebhookEventDispatched(ctx context.Context, id string, dispatchedAt time.Time) error {
+	if _, err := r.db.Exec(ctx, `
+		UPDATE webhook_events SET dispatched_at = $1 WHERE id = $2`,
+		dispatchedAt, id,
+	); err != nil {
+		return fmt.Errorf("failed to mark webhook event dispatched: %w", err)
+	}
+	return nil
+}
+
+// CreateWebhookDelivery queues delivery of an event to a subscription; a delivery already queued
+// for the pair is left as is
+func (r *LoanRepository) CreateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO webhook_deliveries (
+			id, subscription_id, event_id, event_type, application_id, status, attempts, next_attempt_at,
+			created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+		delivery.ID, delivery.SubscriptionID, delivery.EventID, delivery.EventType, delivery.ApplicationID,
+		delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.CreatedAt, delivery.UpdatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create webhook delivery",
+			zap.String("subscription_id", delivery.SubscriptionID),
+			zap.String("event_id", delivery.EventID),
+			zap.Error(err))
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookDelivery retrieves a webhook delivery by ID
+func (r *LoanRepository) GetWebhookDelivery(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries WHERE id = $1`,
+		id)
+
+	delivery, err := scanWebhookDelivery(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("webhook delivery not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+// ListDueWebhookDeliveries lists pending deliveries whose next attempt is due, oldest first
+func (r *LoanRepository) ListDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries WHERE status = $1 AND next_attempt_at <= $2
+		ORDER BY next_attempt_at, id
+		LIMIT $3`,
+		domain.WebhookDeliveryPending, now, limit)
+	if err != nil {
+		r.logger.Error("Failed to list due webhook deliveries", zap.Error(err))
+		return nil, fmt.Errorf("failed to list due webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWebhookDeliveries(rows)
+}
+
+// ListWebhookDeliveries lists a subscription's deliveries, newest first, optionally with one status
+func (r *LoanRepository) ListWebhookDeliveries(ctx context.Context, query *domain.WebhookDeliveryQuery) ([]*domain.WebhookDelivery, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries WHERE subscription_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id
+		LIMIT $3`,
+		query.SubscriptionID, string(query.Status), query.Limit)
+	if err != nil {
+		r.logger.Error("Failed to list webhook deliveries",
+			zap.String("subscription_id", query.SubscriptionID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWebhookDeliveries(rows)
+}
+
+// UpdateWebhookDelivery saves the outcome of a delivery attempt
+func (r *LoanRepository) UpdateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	result, err := r.db.Exec(ctx, `
+		UPDATE webhook_deliveries SET
+			status = $1, attempts = $2, next_attempt_at = $3, response_status = $4, last_error = $5,
+			delivered_at = $6, updated_at = $7
+		WHERE id = $8`,
+		delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.ResponseStatus, delivery.LastError,
+		delivery.DeliveredAt, delivery.UpdatedAt, delivery.ID,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update webhook delivery",
+			zap.String("delivery_id", delivery.ID),
+			zap.Error(err))
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook delivery not found")
+	}
+	return nil
+}
+
+func scanWebhookSubscription(row interface{ Scan(...interface{}) error }) (*domain.WebhookSubscription, error) {
+	var subscription domain.WebhookSubscription
+	var eventTypes []byte
+	if err := row.Scan(
+		&subscription.ID, &subscription.Name, &subscription.URL, &subscription.Secret, &eventTypes,
+		&subscription.Active, &subscription.CreatedAt, &subscription.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if len(eventTypes) > 0 {
+		if err := json.Unmarshal(eventTypes, &subscription.EventTypes); err != nil {
+			return nil, fmt.Errorf("failed to decode event types: %w", err)
+		}
+	}
+	return &subscription, nil
+}
+
+func scanWebhookEvent(row interface{ Scan(...interface{}) error }) (*domain.WebhookEvent, error) {
+	var event domain.WebhookEvent
+	var payload []byte
+	if err := row.Scan(&event.ID, &event.Type, &event.ApplicationID, &payload, &event.CreatedAt); err != nil {
+		return nil, err
+	}
+	event.Data = json.RawMessage(payload)
+	return &event, nil
+}
+
+func scanWebhookDeliveries(rows *sql.Rows) ([]*domain.WebhookDelivery, error) {
+	var deliveries []*domain.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func scanWebhookDelivery(row interface{ Scan(...interface{}) error }) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	var nextAttemptAt, deliveredAt sql.NullTime
+	var responseStatus sql.NullInt64
+	var lastError sql.NullString
+	if err := row.Scan(
+		&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType, &delivery.ApplicationID,
+		&delivery.Status, &delivery.Attempts, &nextAttemptAt, &responseStatus, &lastError, &deliveredAt,
+		&delivery.CreatedAt, &delivery.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if nextAttemptAt.Valid {
+		delivery.NextAttemptAt = &nextAttemptAt.Time
+	}
+	if responseStatus.Valid {
+		status := int(responseStatus.Int64)
+		delivery.ResponseStatus = &status
+	}
+	if lastError.Valid {
+		delivery.LastError = &lastError.String
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	return &delivery, nil
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// GetApplicationIDByWorkflowID returns the application a workflow runs for, or "" when the
+// workflow was not started by this service
+func (r *LoanRepository) GetApplicationIDByWorkflowID(ctx context.Context, workflowID string) (string, error) {
+	var applicationID string
+	err := r.db.QueryRow(ctx, `
+		SELECT id::text FROM loan_applications WHERE workflow_id = $1
+		UNION ALL
+		SELECT application_id::text FROM workflow_executions WHERE workflow_id = $1
+		LIMIT 1`, workflowID).Scan(&applicationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		r.logger.Error("Failed to look up workflow application",
+			zap.String("workflow_id", workflowID),
+			zap.Error(err))
+		return "", fmt.Errorf("failed to look up workflow application: %w", err)
+	}
+
+	return applicationID, nil
+}
+
+// RecordWorkflowAction stores who paused, resumed or terminated a workflow and moves its
+// execution record to the status the action leaves behind
+func (r *LoanRepository) RecordWorkflowAction(ctx context.Context, action *domain.WorkflowAction) error {
+	logger := r.logger.With(
+		zap.String("operation", "record_workflow_action"),
+		zap.String("workflow_id", action.WorkflowID),
+		zap.String("action", string(action.Action)),
+	)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO workflow_actions (
+			id, workflow_id, application_id, action, reason, performed_by, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)`,
+		action.ID, action.WorkflowID, action.ApplicationID, action.Action,
+		action.Reason, action.PerformedBy, action.CreatedAt,
+	); err != nil {
+		logger.Error("Failed to record workflow action", zap.Error(err))
+		return fmt.Errorf("failed to record workflow action: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE workflow_executions SET
+			status = $1,
+			end_time = CASE WHEN $1 = $2 THEN $3 ELSE end_time END,
+			updated_at = $3
+		WHERE workflow_id = $4`,
+		action.Action.ExecutionStatus(), domain.WorkflowExecutionTerminated, action.CreatedAt, action.WorkflowID,
+	); err != nil {
+		logger.Error("Failed to update workflow execution status", zap.Error(err))
+		return fmt.Errorf("failed to update workflow execution status: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("Failed to commit workflow action", zap.Error(err))
+		return fmt.Errorf("failed to commit workflow action: %w", err)
+	}
+
+	logger.Info("Workflow action recorded", zap.String("action_id", action.ID))
+	return nil
+}
@@ -0,0 +1,105 @@
+package decisions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// Client looks up the decisions the decision engine stored for applications
+type Client struct {
+	baseURL      string
+	serviceToken string
+	httpClient   *http.Client
+	logger       *zap.Logger
+}
+
+// NewClient creates a new decision engine client
+func NewClient(baseURL, serviceToken string, timeout time.Duration, logger *zap.Logger) *Client {
+	return &Client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		serviceToken: serviceToken,
+		httpClient:   &http.Client{Timeout: timeout},
+		logger:       logger,
+	}
+}
+
+// decisionResponse is the part of the decision engine's stored decision offers are priced from
+type decisionResponse struct {
+	ApplicationID string                  `json:"application_id"`
+	Decision      string                  `json:"decision"`
+	RiskCategory  string                  `json:"risk_category"`
+	CreditScore   int                     `json:"credit_score"`
+	Pricing       *domain.DecisionPricing `json:"pricing"`
+	Provisional   bool                    `json:"provisional"`
+	DecisionDate  time.Time               `json:"decision_date"`
+}
+
+// GetDecision returns the latest decision the decision engine made on the application
+func (c *Client) GetDecision(ctx context.Context, applicationID string) (*domain.UnderwritingDecision, error) {
+	logger := c.logger.With(
+		zap.String("application_id", applicationID),
+		zap.String("operation", "get_decision"),
+	)
+
+	endpoint := c.baseURL + "/api/v1/decisions/" + url.PathEscape(applicationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build decision request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.serviceToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Decision request failed", zap.Error(err))
+		return nil, fmt.Errorf("failed to call decision engine: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("decision not found for application %s", applicationID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("Unexpected decision response", zap.Int("status", resp.StatusCode))
+		return nil, fmt.Errorf("unexpected decision status: %d", resp.StatusCode)
+	}
+
+	var body decisionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode decision response: %w", err)
+	}
+	if body.CreditScore == 0 {
+		return nil, fmt.Errorf("decision for application %s has no credit score", applicationID)
+	}
+
+	return &domain.UnderwritingDecision{
+		ApplicationID: applicationID,
+		Decision:      body.Decision,
+		CreditScore:   body.CreditScore,
+		RiskLevel:     riskLevel(body.RiskCategory),
+		Pricing:       body.Pricing,
+		Provisional:   body.Provisional,
+		DecidedAt:     body.DecisionDate,
+	}, nil
+}
+
+// riskLevel maps the decision engine's risk category to the risk level offers are priced for;
+// critical risk is priced as high
+func riskLevel(category string) domain.RiskLevel {
+	switch strings.ToUpper(category) {
+	case "LOW":
+		return domain.RiskLevelLow
+	case "MEDIUM":
+		return domain.RiskLevelMedium
+	default:
+		return domain.RiskLevelHigh
+	}
+}
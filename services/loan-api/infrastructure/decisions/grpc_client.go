@@ -0,0 +1,89 @@
+package decisions
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	decisionv1 "github.com/huuhoait/los-demo/services/shared/proto/decision/v1"
+)
+
+// GRPCClient looks up decisions through the decision engine's DecisionService. Deadlines, retries
+// and mTLS come from the connection, see rpc.Dial.
+type GRPCClient struct {
+	client decisionv1.DecisionServiceClient
+	logger *zap.Logger
+}
+
+// NewGRPCClient creates a decision engine client over an established connection
+func NewGRPCClient(conn grpc.ClientConnInterface, logger *zap.Logger) *GRPCClient {
+	return &GRPCClient{
+		client: decisionv1.NewDecisionServiceClient(conn),
+		logger: logger,
+	}
+}
+
+// GetDecision returns the latest decision the decision engine made on the application
+func (c *GRPCClient) GetDecision(ctx context.Context, applicationID string) (*domain.UnderwritingDecision, error) {
+	decision, err := c.client.GetDecision(ctx, &decisionv1.GetDecisionRequest{ApplicationId: applicationID})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, fmt.Errorf("decision not found for application %s", applicationID)
+		}
+		c.logger.Error("Decision request failed",
+			zap.String("application_id", applicationID),
+			zap.String("operation", "get_decision"),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to call decision engine: %w", err)
+	}
+	if decision.GetCreditScore() == 0 {
+		return nil, fmt.Errorf("decision for application %s has no credit score", applicationID)
+	}
+
+	return &domain.UnderwritingDecision{
+		ApplicationID: applicationID,
+		Decision:      decision.GetDecision(),
+		CreditScore:   int(decision.GetCreditScore()),
+		RiskLevel:     riskLevel(decision.GetRiskCategory()),
+		Pricing:       decisionPricing(decision.GetPricing()),
+		Provisional:   decision.GetProvisional(),
+		DecidedAt:     decision.GetDecisionDate().AsTime(),
+	}, nil
+}
+
+// decisionPricing converts the decision engine's price; nil when the decision was not priced
+func decisionPricing(pricing *decisionv1.Pricing) *domain.DecisionPricing {
+	if pricing == nil {
+		return nil
+	}
+
+	result := &domain.DecisionPricing{
+		ModelVersion:          pricing.GetModelVersion(),
+		BaseRate:              pricing.GetBaseRate(),
+		Margin:                pricing.GetMargin(),
+		Rate:                  pricing.GetRate(),
+		OriginationFeeRate:    pricing.GetOriginationFeeRate(),
+		NetOriginationFeeRate: pricing.GetNetOriginationFeeRate(),
+	}
+	for _, adjustment := range pricing.GetMarginAdjustments() {
+		result.MarginAdjustments = append(result.MarginAdjustments, domain.PricingMarginAdjustment{
+			Factor:      adjustment.GetFactor(),
+			Adjustment:  adjustment.GetAdjustment(),
+			Description: adjustment.GetDescription(),
+		})
+	}
+	for _, waiver := range pricing.GetFeeWaivers() {
+		result.FeeWaivers = append(result.FeeWaivers, domain.PricingFeeWaiver{
+			Fee:        waiver.GetFee(),
+			Code:       waiver.GetCode(),
+			WaivedRate: waiver.GetWaivedRate(),
+			Reason:     waiver.GetReason(),
+		})
+	}
+	return result
+}
@@ -0,0 +1,473 @@
+package disbursement
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// ProviderDwolla identifies transfers originated through Dwolla
+const ProviderDwolla = "dwolla"
+
+// dwollaMediaType is the HAL media type of every Dwolla request and response
+const dwollaMediaType = "application/vnd.dwolla.v1.hal+json"
+
+// dwollaSignatureHeader carries the hex HMAC of a webhook body
+const dwollaSignatureHeader = "X-Request-Signature-SHA-256"
+
+// dwollaTopics maps transfer webhook topics to disbursement statuses
+var dwollaTopics = map[string]domain.DisbursementStatus{
+	"transfer_completed":          domain.DisbursementCompleted,
+	"transfer_failed":             domain.DisbursementFailed,
+	"transfer_cancelled":          domain.DisbursementFailed,
+	"customer_transfer_completed": domain.DisbursementCompleted,
+	"customer_transfer_failed":    domain.DisbursementFailed,
+	"customer_transfer_cancelled": domain.DisbursementFailed,
+}
+
+// DwollaClient originates ACH credits with the Dwolla API. Borrowers are registered as receive-only
+// customers whose bank account is a funding source the lender's account sends to.
+type DwollaClient struct {
+	baseURL       string
+	apiKey        string
+	apiSecret     string
+	sourceAccount string
+	webhookSecret string
+	httpClient    *http.Client
+	logger        *zap.Logger
+
+	mu          sync.Mutex
+	accessToken string
+	tokenExpiry time.Time
+}
+
+// NewDwollaClient creates a new Dwolla client sending from the lender's funding source
+func NewDwollaClient(baseURL, apiKey, apiSecret, sourceAccount, webhookSecret string, timeout time.Duration, logger *zap.Logger) *DwollaClient {
+	if baseURL == "" {
+		baseURL = "https://api-sandbox.dwolla.com"
+	}
+	return &DwollaClient{
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		apiKey:        apiKey,
+		apiSecret:     apiSecret,
+		sourceAccount: sourceAccount,
+		webhookSecret: webhookSecret,
+		httpClient:    &http.Client{Timeout: timeout},
+		logger:        logger,
+	}
+}
+
+// Name returns the provider name recorded on disbursements
+func (c *DwollaClient) Name() string {
+	return ProviderDwolla
+}
+
+// CreateTransfer originates the disbursement to the destination account and returns the Dwolla
+// transfer ID and the funding source it was sent to. A disbursement already holding a destination
+// reference is sent to it again rather than registering the account a second time. A transfer
+// Dwolla already has for the disbursement that has not failed is returned instead of sending
+// another, so a submission retried after a lost response does not pay the borrower twice.
+func (c *DwollaClient) CreateTransfer(ctx context.Context, disbursement *domain.Disbursement, destination *domain.DisbursementDestination) (string, string, error) {
+	logger := c.logger.With(
+		zap.String("disbursement_id", disbursement.ID),
+		zap.String("operation", "dwolla_create_transfer"),
+	)
+
+	fundingSource := ""
+	if disbursement.DestinationReference != nil {
+		fundingSource = *disbursement.DestinationReference
+	} else {
+		var err error
+		fundingSource, err = c.registerFundingSource(ctx, "customer-"+destination.UserID, "funding-source-"+disbursement.BankAccountID, destination)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	transferID, failed, err := c.findTransfer(ctx, fundingSource, disbursement.ID)
+	if err != nil {
+		return "", fundingSource, fmt.Errorf("failed to look up Dwolla transfers: %w", err)
+	}
+	if transferID != "" {
+		logger.Info("Transfer already created", zap.String("transfer_id", transferID))
+		return transferID, fundingSource, nil
+	}
+
+	// The key stays the same until a transfer for the disbursement fails, however many times the
+	// submission itself is retried
+	transfer, err := c.createResource(ctx, "/transfers", fmt.Sprintf("transfer-%s-%d", disbursement.ID, failed), map[string]interface{}{
+		"_links": map[string]interface{}{
+			"source":      map[string]string{"href": c.baseURL + "/funding-sources/" + url.PathEscape(c.sourceAccount)},
+			"destination": map[string]string{"href": fundingSource},
+		},
+		"amount": map[string]string{
+			"currency": "USD",
+			"value":    fmt.Sprintf("%.2f", disbursement.TransferAmount()),
+		},
+		"correlationId": disbursement.ID,
+	})
+	if err != nil {
+		return "", fundingSource, fmt.Errorf("failed to create Dwolla transfer: %w", err)
+	}
+
+	transferID = path.Base(transfer)
+	logger.Info("Transfer created", zap.String("transfer_id", transferID))
+	return transferID, fundingSource, nil
+}
+
+// findTransfer looks up the transfers made to the funding source's customer for a disbursement by
+// their correlation ID, returning the one that is pending or processed, if any, and how many
+// failed or were cancelled
+func (c *DwollaClient) findTransfer(ctx context.Context, fundingSource, disbursementID string) (string, int, error) {
+	var source struct {
+		Links struct {
+			Customer struct {
+				Href string `json:"href"`
+			} `json:"customer"`
+		} `json:"_links"`
+	}
+	if err := c.get(ctx, strings.TrimPrefix(fundingSource, c.baseURL), &source); err != nil {
+		return "", 0, err
+	}
+	if source.Links.Customer.Href == "" {
+		return "", 0, fmt.Errorf("funding source has no customer")
+	}
+
+	var transfers struct {
+		Embedded struct {
+			Transfers []struct {
+				ID     string `json:"id"`
+				Status string `json:"status"`
+			} `json:"transfers"`
+		} `json:"_embedded"`
+	}
+	query := url.Values{"correlationId": {disbursementID}}.Encode()
+	if err := c.get(ctx, strings.TrimPrefix(source.Links.Customer.Href, c.baseURL)+"/transfers?"+query, &transfers); err != nil {
+		return "", 0, err
+	}
+
+	failed := 0
+	for _, transfer := range transfers.Embedded.Transfers {
+		switch transfer.Status {
+		case "failed", "cancelled":
+			failed++
+		default:
+			return transfer.ID, failed, nil
+		}
+	}
+	return "", failed, nil
+}
+
+// InitiateMicroDeposits registers a borrower's bank account and sends two micro-deposits to it,
+// returning the funding source that disbursements to the account are later sent to
+func (c *DwollaClient) InitiateMicroDeposits(ctx context.Context, account *domain.BankAccount, destination *domain.DisbursementDestination) (string, error) {
+	fundingSource, err := c.registerFundingSource(ctx, "customer-"+destination.UserID, "funding-source-"+account.ID, destination)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := c.createResource(ctx, strings.TrimPrefix(fundingSource, c.baseURL)+"/micro-deposits", "micro-deposits-"+account.ID, map[string]interface{}{}); err != nil {
+		return fundingSource, fmt.Errorf("failed to initiate micro-deposits: %w", err)
+	}
+
+	c.logger.Info("Micro-deposits initiated", zap.String("bank_account_id", account.ID))
+	return fundingSource, nil
+}
+
+// VerifyMicroDeposits confirms the micro-deposit amounts a borrower entered, reporting whether they
+// matched. Dwolla marks the funding source verified when they do.
+func (c *DwollaClient) VerifyMicroDeposits(ctx context.Context, reference string, amount1, amount2 float64) (bool, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"amount1": map[string]string{"value": fmt.Sprintf("%.2f", amount1), "currency": "USD"},
+		"amount2": map[string]string{"value": fmt.Sprintf("%.2f", amount2), "currency": "USD"},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reference+"/micro-deposits", bytes.NewReader(payload))
+	if err != nil {
+		return false, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", dwollaMediaType)
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		return true, nil
+	case http.StatusBadRequest:
+		// Wrong amounts are reported as a validation error on the amounts
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected Dwolla status: %d", resp.StatusCode)
+	}
+}
+
+// registerFundingSource registers the destination account as a funding source of a receive-only
+// customer, returning the existing funding source when the account is already registered
+func (c *DwollaClient) registerFundingSource(ctx context.Context, customerKey, fundingSourceKey string, destination *domain.DisbursementDestination) (string, error) {
+	customer, err := c.createResource(ctx, "/customers", customerKey, map[string]interface{}{
+		"firstName": firstName(destination.Name),
+		"lastName":  lastName(destination.Name),
+		"email":     destination.Email,
+		"type":      "receive-only",
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create Dwolla customer: %w", err)
+	}
+
+	fundingSource, err := c.createResource(ctx, strings.TrimPrefix(customer, c.baseURL)+"/funding-sources", fundingSourceKey, map[string]interface{}{
+		"routingNumber":   destination.RoutingNumber,
+		"accountNumber":   destination.AccountNumber,
+		"bankAccountType": string(destination.AccountType),
+		"name":            destination.BankName,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create Dwolla funding source: %w", err)
+	}
+	return fundingSource, nil
+}
+
+// ParseWebhook verifies a webhook's HMAC signature and returns the transfer event it reports, with
+// the ACH return code of failed transfers. Topics that do not settle a transfer are returned as nil.
+func (c *DwollaClient) ParseWebhook(ctx context.Context, body []byte, header func(string) string) (*domain.TransferEvent, error) {
+	if c.webhookSecret == "" {
+		return nil, fmt.Errorf("webhook secret is not configured")
+	}
+
+	signature, err := hex.DecodeString(header(dwollaSignatureHeader))
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook signature encoding: %w", err)
+	}
+	mac := hmac.New(sha256.New, []byte(c.webhookSecret))
+	mac.Write(body)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, fmt.Errorf("webhook signature mismatch")
+	}
+
+	var notification struct {
+		Topic      string    `json:"topic"`
+		ResourceID string    `json:"resourceId"`
+		Timestamp  time.Time `json:"timestamp"`
+	}
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook: %w", err)
+	}
+
+	status, ok := dwollaTopics[notification.Topic]
+	if !ok {
+		return nil, nil
+	}
+	if notification.ResourceID == "" {
+		return nil, fmt.Errorf("webhook has no transfer ID")
+	}
+
+	event := &domain.TransferEvent{
+		ProviderTransferID: notification.ResourceID,
+		Status:             status,
+		OccurredAt:         notification.Timestamp,
+	}
+	if strings.HasSuffix(notification.Topic, "_cancelled") {
+		event.Reason = "Transfer cancelled"
+		return event, nil
+	}
+	if status == domain.DisbursementFailed {
+		// A missing failure reason leaves the transfer failed without a return code, so it is not retried
+		var failure struct {
+			Code        string `json:"code"`
+			Description string `json:"description"`
+		}
+		if err := c.get(ctx, "/transfers/"+url.PathEscape(notification.ResourceID)+"/failure", &failure); err != nil {
+			c.logger.Warn("Failed to get transfer failure reason",
+				zap.String("transfer_id", notification.ResourceID),
+				zap.Error(err))
+		}
+		event.ReturnCode = failure.Code
+		event.Reason = failure.Description
+	}
+
+	return event, nil
+}
+
+// createResource posts a new resource and returns its URL from the Location header. A resource
+// that already exists is returned from the error's link to it, so registrations can be repeated.
+func (c *DwollaClient) createResource(ctx context.Context, resourcePath, idempotencyKey string, body interface{}) (string, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+resourcePath, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", dwollaMediaType)
+	req.Header.Set("Idempotency-Key", idempotencyKey)
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusCreated || resp.StatusCode == http.StatusOK {
+		if location := resp.Header.Get("Location"); location != "" {
+			return location, nil
+		}
+		return "", fmt.Errorf("Dwolla returned no resource location")
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if existing := existingResource(respBody); existing != "" {
+		return existing, nil
+	}
+	c.logger.Error("Unexpected Dwolla response",
+		zap.String("path", resourcePath),
+		zap.Int("status", resp.StatusCode),
+		zap.ByteString("body", respBody))
+	return "", fmt.Errorf("unexpected Dwolla status: %d", resp.StatusCode)
+}
+
+// get retrieves a resource into out
+func (c *DwollaClient) get(ctx context.Context, resourcePath string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+resourcePath, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected Dwolla status: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// do sends an authenticated request
+func (c *DwollaClient) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	token, err := c.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", dwollaMediaType)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Dwolla: %w", err)
+	}
+	return resp, nil
+}
+
+// token returns an application access token, requesting a new one shortly before it expires
+func (c *DwollaClient) token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.accessToken != "" && time.Now().Before(c.tokenExpiry) {
+		return c.accessToken, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/token",
+		strings.NewReader(url.Values{"grant_type": {"client_credentials"}}.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to build token request: %w", err)
+	}
+	req.SetBasicAuth(c.apiKey, c.apiSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call Dwolla: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.logger.Error("Dwolla token request rejected", zap.Int("status", resp.StatusCode))
+		return "", fmt.Errorf("unexpected token status: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+
+	c.accessToken = result.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - time.Minute)
+	return c.accessToken, nil
+}
+
+// existingResource finds the link to an already existing resource in a Dwolla error: duplicate
+// funding sources link it directly and duplicate customers from the embedded validation error
+func existingResource(body []byte) string {
+	type link struct {
+		About struct {
+			Href string `json:"href"`
+		} `json:"about"`
+	}
+	var dwollaError struct {
+		Code     string `json:"code"`
+		Links    link   `json:"_links"`
+		Embedded struct {
+			Errors []struct {
+				Code  string `json:"code"`
+				Links link   `json:"_links"`
+			} `json:"errors"`
+		} `json:"_embedded"`
+	}
+	if err := json.Unmarshal(body, &dwollaError); err != nil {
+		return ""
+	}
+
+	if dwollaError.Code == "DuplicateResource" {
+		return dwollaError.Links.About.Href
+	}
+	for _, embedded := range dwollaError.Embedded.Errors {
+		if embedded.Code == "Duplicate" {
+			return embedded.Links.About.Href
+		}
+	}
+	return ""
+}
+
+// firstName is the given name of a full name
+func firstName(name string) string {
+	first, _, _ := strings.Cut(strings.TrimSpace(name), " ")
+	return first
+}
+
+// lastName is everything after the given name, or the whole name when there is only one
+func lastName(name string) string {
+	_, last, found := strings.Cut(strings.TrimSpace(name), " ")
+	if !found || strings.TrimSpace(last) == "" {
+		return strings.TrimSpace(name)
+	}
+	return strings.TrimSpace(last)
+}
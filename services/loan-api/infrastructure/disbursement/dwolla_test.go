@@ -0,0 +1,160 @@
+package disbursement
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// fakeDwolla serves the Dwolla endpoints a transfer goes through. Transfers created are listed
+// under the customer as pending, and the idempotency keys they were created with kept.
+type fakeDwolla struct {
+	server       *httptest.Server
+	transfers    []map[string]string
+	keys         []string
+	customerKeys []string
+}
+
+func newFakeDwolla(t *testing.T) *fakeDwolla {
+	fake := &fakeDwolla{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "token", "expires_in": 3600})
+	})
+	mux.HandleFunc("/customers", func(w http.ResponseWriter, r *http.Request) {
+		fake.customerKeys = append(fake.customerKeys, r.Header.Get("Idempotency-Key"))
+		w.Header().Set("Location", fake.server.URL+"/customers/customer-1")
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("/customers/customer-1/funding-sources", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", fake.server.URL+"/funding-sources/source-1")
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("/funding-sources/source-1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"_links": map[string]interface{}{"customer": map[string]string{"href": fake.server.URL + "/customers/customer-1"}},
+		})
+	})
+	mux.HandleFunc("/customers/customer-1/transfers", func(w http.ResponseWriter, r *http.Request) {
+		var matching []map[string]string
+		for _, transfer := range fake.transfers {
+			if transfer["correlationId"] == r.URL.Query().Get("correlationId") {
+				matching = append(matching, transfer)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"_embedded": map[string]interface{}{"transfers": matching}})
+	})
+	mux.HandleFunc("/transfers", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			CorrelationID string `json:"correlationId"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		id := fmt.Sprintf("transfer-%d", len(fake.transfers)+1)
+		fake.keys = append(fake.keys, r.Header.Get("Idempotency-Key"))
+		fake.transfers = append(fake.transfers, map[string]string{"id": id, "status": "pending", "correlationId": body.CorrelationID})
+		w.Header().Set("Location", fake.server.URL+"/transfers/"+id)
+		w.WriteHeader(http.StatusCreated)
+	})
+	fake.server = httptest.NewServer(mux)
+	t.Cleanup(fake.server.Close)
+	return fake
+}
+
+func TestDwollaClient_CreateTransfer(t *testing.T) {
+	tests := []struct {
+		name string
+		// status of each earlier transfer Dwolla has for the disbursement
+		existing      []string
+		wantTransfer  string
+		wantNewKey    string // empty when no transfer should be created
+		wantTransfers int
+	}{
+		{
+			name:          "first submission creates a transfer",
+			wantTransfer:  "transfer-1",
+			wantNewKey:    "transfer-disbursement-1-0",
+			wantTransfers: 1,
+		},
+		{
+			name:          "retried submission returns the pending transfer",
+			existing:      []string{"pending"},
+			wantTransfer:  "transfer-1",
+			wantTransfers: 1,
+		},
+		{
+			name:          "retried submission returns the processed transfer",
+			existing:      []string{"processed"},
+			wantTransfer:  "transfer-1",
+			wantTransfers: 1,
+		},
+		{
+			name:          "retry after a failed transfer creates a new one",
+			existing:      []string{"failed"},
+			wantTransfer:  "transfer-2",
+			wantNewKey:    "transfer-disbursement-1-1",
+			wantTransfers: 2,
+		},
+		{
+			name:          "retry after failed and cancelled transfers",
+			existing:      []string{"failed", "cancelled"},
+			wantTransfer:  "transfer-3",
+			wantNewKey:    "transfer-disbursement-1-2",
+			wantTransfers: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeDwolla(t)
+			for i, status := range tt.existing {
+				fake.transfers = append(fake.transfers, map[string]string{"id": fmt.Sprintf("transfer-%d", i+1), "status": status, "correlationId": "disbursement-1"})
+			}
+			client := NewDwollaClient(fake.server.URL, "key", "secret", "master-source", "", 5*time.Second, zap.NewNop())
+			reference := fake.server.URL + "/funding-sources/source-1"
+			disbursement := &domain.Disbursement{ID: "disbursement-1", ApplicationID: "app-1", BankAccountID: "account-1", Amount: 10000, Attempts: len(tt.existing) + 1}
+			if len(tt.existing) > 0 {
+				disbursement.DestinationReference = &reference
+			}
+
+			transferID, fundingSource, err := client.CreateTransfer(context.Background(), disbursement, &domain.DisbursementDestination{UserID: "user-1", Name: "Jane Doe"})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantTransfer, transferID)
+			assert.Equal(t, reference, fundingSource)
+			assert.Len(t, fake.transfers, tt.wantTransfers)
+			if tt.wantNewKey == "" {
+				assert.Empty(t, fake.keys)
+			} else {
+				assert.Equal(t, []string{tt.wantNewKey}, fake.keys)
+			}
+		})
+	}
+}
+
+func TestDwollaClient_CreateTransferKeyIgnoresAttempts(t *testing.T) {
+	// A submission that failed before Dwolla created a transfer is retried with the same key,
+	// however many attempts the disbursement has counted
+	fake := newFakeDwolla(t)
+	client := NewDwollaClient(fake.server.URL, "key", "secret", "master-source", "", 5*time.Second, zap.NewNop())
+
+	for attempts := 1; attempts <= 3; attempts++ {
+		fake.transfers = nil
+		disbursement := &domain.Disbursement{ID: "disbursement-1", ApplicationID: "app-1", BankAccountID: "account-1", Amount: 10000, Attempts: attempts}
+		_, _, err := client.CreateTransfer(context.Background(), disbursement, &domain.DisbursementDestination{UserID: "user-1", Name: "Jane Doe"})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"transfer-disbursement-1-0", "transfer-disbursement-1-0", "transfer-disbursement-1-0"}, fake.keys)
+	// Customers are registered by borrower, as for micro-deposit verification
+	assert.Equal(t, []string{"customer-user-1", "customer-user-1", "customer-user-1"}, fake.customerKeys)
+}
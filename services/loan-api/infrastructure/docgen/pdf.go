@@ -0,0 +1,206 @@
+package docgen
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Page layout in points on US Letter paper
+const (
+	pageWidth    = 612.0
+	pageHeight   = 792.0
+	marginX      = 72.0
+	marginTop    = 72.0
+	marginBottom = 72.0
+	contentWidth = pageWidth - 2*marginX
+	valueColumn  = marginX + 240.0
+)
+
+// Standard Type 1 fonts every PDF reader provides, so no font files are embedded
+const (
+	fontRegular = "F1" // Helvetica
+	fontBold    = "F2" // Helvetica-Bold
+)
+
+// helveticaWidths are the Helvetica advance widths, in thousandths of an em, of the printable
+// ASCII characters starting at the space
+var helveticaWidths = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 556, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// textWidth approximates the width of text set in a font. Bold glyphs are about a tenth wider.
+func textWidth(text, font string, size float64) float64 {
+	units := 0
+	for _, r := range text {
+		if r >= ' ' && int(r-' ') < len(helveticaWidths) {
+			units += helveticaWidths[r-' ']
+		} else {
+			units += 556
+		}
+	}
+	width := float64(units) * size / 1000
+	if font == fontBold {
+		width *= 1.1
+	}
+	return width
+}
+
+// pdfWriter lays text out top to bottom across as many pages as it needs and writes a PDF 1.4
+// document
+type pdfWriter struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64
+}
+
+func newPDFWriter() *pdfWriter {
+	w := &pdfWriter{}
+	w.newPage()
+	return w
+}
+
+func (w *pdfWriter) newPage() {
+	w.page = &bytes.Buffer{}
+	w.pages = append(w.pages, w.page)
+	w.y = pageHeight - marginTop
+}
+
+// advance moves down a line of the given height, starting a new page when it would not fit
+func (w *pdfWriter) advance(height float64) {
+	if w.y-height < marginBottom {
+		w.newPage()
+	}
+	w.y -= height
+}
+
+// space leaves a vertical gap, which is dropped at the top of a page
+func (w *pdfWriter) space(height float64) {
+	if w.y-height < marginBottom {
+		w.newPage()
+		return
+	}
+	w.y -= height
+}
+
+// show draws text with its baseline at the current position. Hidden text is drawn in white.
+func (w *pdfWriter) show(x float64, text, font string, size float64, hidden bool) {
+	if hidden {
+		w.page.WriteString("1 g\n")
+	}
+	fmt.Fprintf(w.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, w.y, escapeText(text))
+	if hidden {
+		w.page.WriteString("0 g\n")
+	}
+}
+
+// heading writes a single line in bold
+func (w *pdfWriter) heading(text string, size float64) {
+	w.advance(size * 1.4)
+	w.show(marginX, text, fontBold, size, false)
+}
+
+// paragraph writes text wrapped to the page width
+func (w *pdfWriter) paragraph(text, font string, size float64, hidden bool) {
+	for _, line := range wrap(text, font, size, contentWidth) {
+		w.advance(size * 1.4)
+		w.show(marginX, line, font, size, hidden)
+	}
+}
+
+// row writes a label and its value in two columns
+func (w *pdfWriter) row(label, value string, size float64) {
+	lines := wrap(value, fontRegular, size, pageWidth-marginX-valueColumn)
+	for i, line := range lines {
+		w.advance(size * 1.4)
+		if i == 0 {
+			w.show(marginX, label, fontBold, size, false)
+		}
+		w.show(valueColumn, line, fontRegular, size, false)
+	}
+}
+
+// bytes assembles the document: catalog, page tree, fonts and info, then each page and its
+// content stream
+func (w *pdfWriter) bytes(title string, created time.Time) []byte {
+	var out bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	const firstPage = 6
+	kids := make([]string, len(w.pages))
+	for i := range w.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(w.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /CreationDate (D:%s) >>", escapeText(title), created.UTC().Format("20060102150405Z")))
+	for i, page := range w.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, fontRegular, fontBold, firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+// wrap breaks text into lines that fit the width, breaking only between words
+func wrap(text, font string, size, width float64) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	line := words[0]
+	for _, word := range words[1:] {
+		if textWidth(line+" "+word, font, size) > width {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+		line += " " + word
+	}
+	return append(lines, line)
+}
+
+// escapeText encodes text as a PDF string literal in WinAnsi. The standard fonts have no glyphs
+// beyond Latin-1, so other characters are written as question marks.
+func escapeText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= ' ' && r <= '~':
+			b.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,127 @@
+package docgen
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// Font sizes of the template line kinds
+const (
+	titleSize   = 16.0
+	sectionSize = 12.0
+	bodySize    = 10.0
+)
+
+// documentTemplate is a document's template and how its files and titles are named
+type documentTemplate struct {
+	template *template.Template
+	title    string
+	fileName string
+}
+
+// Renderer renders loan documents from templates as PDFs
+type Renderer struct {
+	lender    string
+	templates map[string]documentTemplate
+	logger    *zap.Logger
+}
+
+// NewRenderer creates a new renderer for documents issued by the named lender
+func NewRenderer(lender string, logger *zap.Logger) *Renderer {
+	if lender == "" {
+		lender = "the Lender"
+	}
+
+	funcs := template.FuncMap{
+		"money":   func(amount float64) string { return fmt.Sprintf("$%.2f", amount) },
+		"percent": func(rate float64) string { return fmt.Sprintf("%.2f%%", rate) },
+		"date":    func(t time.Time) string { return t.Format("January 2, 2006") },
+	}
+	parse := func(name, text string) *template.Template {
+		return template.Must(template.New(name).Funcs(funcs).Parse(text))
+	}
+
+	return &Renderer{
+		lender: lender,
+		templates: map[string]documentTemplate{
+			domain.DocumentLoanAgreement:       {parse("agreement", agreementTemplate), "Loan Agreement", "loan-agreement"},
+			domain.DocumentTILADisclosure:      {parse("tila", tilaDisclosureTemplate), "Truth in Lending Disclosure", "tila-disclosure"},
+			domain.DocumentAdverseActionNotice: {parse("adverse_action", adverseActionTemplate), "Notice of Action Taken", "adverse-action-notice"},
+			domain.DocumentApprovalLetter:      {parse("approval_letter", approvalLetterTemplate), "Notice of Approval", "approval-letter"},
+		},
+		logger: logger,
+	}
+}
+
+// Render renders a document of the given type as a PDF. Agreements, disclosures and approval
+// letters need data.Offer; adverse action notices need data.Reasons.
+func (r *Renderer) Render(documentType string, data *domain.DocumentData) (*domain.RenderedDocument, error) {
+	doc, ok := r.templates[documentType]
+	if !ok {
+		return nil, fmt.Errorf("no template for document type %s", documentType)
+	}
+	if documentType != domain.DocumentAdverseActionNotice && data.Offer == nil {
+		return nil, fmt.Errorf("%s requires an offer", documentType)
+	}
+
+	data.Lender = r.lender
+	var text bytes.Buffer
+	if err := doc.template.Execute(&text, struct {
+		*domain.DocumentData
+		SignatureAnchor string
+		DateAnchor      string
+	}{data, domain.AgreementSignatureAnchor, domain.AgreementDateAnchor}); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", documentType, err)
+	}
+
+	title := doc.title + " " + data.Application.ApplicationNumber
+	content := layout(text.String()).bytes(title, data.Date)
+
+	r.logger.Debug("Rendered document",
+		zap.String("document_type", documentType),
+		zap.String("application_id", data.Application.ID),
+		zap.Int("size", len(content)),
+	)
+
+	return &domain.RenderedDocument{
+		Title:    title,
+		FileName: doc.fileName + "-" + data.Application.ApplicationNumber + ".pdf",
+		MimeType: "application/pdf",
+		Content:  content,
+	}, nil
+}
+
+// layout writes rendered template text to PDF pages line by line
+func layout(text string) *pdfWriter {
+	w := newPDFWriter()
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " ")
+		switch {
+		case line == "":
+			w.space(bodySize)
+		case strings.HasPrefix(line, "## "):
+			w.space(bodySize / 2)
+			w.heading(line[3:], sectionSize)
+		case strings.HasPrefix(line, "# "):
+			w.heading(line[2:], titleSize)
+			w.space(bodySize / 2)
+		case strings.HasPrefix(line, "| "):
+			label, value, _ := strings.Cut(line[2:], " | ")
+			w.row(label, value, bodySize)
+		case strings.HasPrefix(line, "~ "):
+			w.paragraph(line[2:], fontRegular, bodySize, true)
+		default:
+			w.paragraph(line, fontRegular, bodySize, false)
+		}
+	}
+	return w
+}
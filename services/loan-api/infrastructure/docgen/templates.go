@@ -0,0 +1,114 @@
+package docgen
+
+// Document templates are text/template sources laid out one line at a time:
+//
+//	# text     title
+//	## text    section heading
+//	| a | b    label and value in two columns
+//	~ text     hidden text, such as e-signature anchors
+//	(blank)    paragraph break
+//
+// Any other line is a paragraph wrapped to the page width.
+
+const agreementTemplate = `# Personal Loan Agreement
+Application number: {{.Application.ApplicationNumber}}
+Date: {{date .Date}}
+
+This agreement is between {{.Lender}} ("Lender") and {{.BorrowerName}} ("Borrower") for a personal loan on the terms below.
+
+## Loan terms
+| Loan amount | {{money .Offer.OfferAmount}}
+| Interest rate | {{percent .Offer.InterestRate}}
+| Annual percentage rate (APR) | {{percent .Offer.APR}}
+| Term | {{.Offer.TermMonths}} months
+| Monthly payment | {{money .Offer.MonthlyPayment}}
+| Total interest | {{money .Offer.TotalInterest}}
+| Total of payments | {{money .TotalOfPayments}}
+| First payment due | {{date .FirstPaymentDate}}
+
+## Repayment
+The Borrower promises to repay the loan amount with interest in {{.Offer.TermMonths}} equal monthly payments of {{money .Offer.MonthlyPayment}}, beginning on {{date .FirstPaymentDate}}. The Borrower may prepay the loan at any time without penalty.
+
+## Signature
+By signing below the Borrower agrees to the terms of this agreement, acknowledges receipt of the Truth in Lending disclosure, and consents to sign this agreement electronically.
+
+Borrower signature:
+~ {{.SignatureAnchor}}
+Name: {{.BorrowerName}}
+Date signed:
+~ {{.DateAnchor}}
+`
+
+const tilaDisclosureTemplate = `# Truth in Lending Disclosure
+Application number: {{.Application.ApplicationNumber}}
+Date: {{date .Date}}
+Creditor: {{.Lender}}
+Borrower: {{.BorrowerName}}
+
+## Federal Truth in Lending disclosures
+| Annual percentage rate | {{percent .Offer.APR}}
+| Finance charge | {{money .Offer.TotalInterest}}
+| Amount financed | {{money .Offer.OfferAmount}}
+| Total of payments | {{money .TotalOfPayments}}
+
+The annual percentage rate is the cost of your credit as a yearly rate. The finance charge is the dollar amount the credit will cost you. The amount financed is the amount of credit provided to you or on your behalf. The total of payments is the amount you will have paid after you have made all payments as scheduled.
+
+## Payment schedule
+| Number of payments | {{.Offer.TermMonths}}
+| Amount of each payment | {{money .Offer.MonthlyPayment}}
+| Payments due | Monthly, beginning {{date .FirstPaymentDate}}
+
+## Other terms
+Prepayment: if you pay off early, you will not have to pay a penalty.
+Security: this loan is unsecured.
+See your loan agreement for any additional information about nonpayment, default and any required repayment in full before the scheduled date.
+`
+
+const approvalLetterTemplate = `# Notice of Approval
+Application number: {{.Application.ApplicationNumber}}
+Date: {{date .Date}}
+
+Dear {{.BorrowerName}},
+
+Thank you for your application for a personal loan. We are pleased to tell you that your application has been approved on the terms below.
+
+## Approved terms
+| Loan amount | {{money .Offer.OfferAmount}}
+| Term | {{.Offer.TermMonths}} months
+{{- if .Offer.InterestRate}}
+| Interest rate | {{percent .Offer.InterestRate}}
+{{- end}}
+{{- if .Offer.APR}}
+| Annual percentage rate (APR) | {{percent .Offer.APR}}
+{{- end}}
+
+## Next steps
+Your loan offer is available in your account. Review and accept it before it expires, then sign your loan agreement electronically. Your Truth in Lending disclosure will show the final cost of your credit before you sign.
+
+Approval is subject to the conditions shown in your account, if any, being satisfied and to no material change in the information you gave us before the loan is funded.
+
+{{.Lender}}
+`
+
+const adverseActionTemplate = `# Notice of Action Taken
+Application number: {{.Application.ApplicationNumber}}
+Date: {{date .Date}}
+
+Dear {{.BorrowerName}},
+
+Thank you for your application for a personal loan of {{money .Application.LoanAmount}}. After careful review, we are unable to approve your application at this time.
+
+## Principal reasons for our decision
+{{range .Reasons}}- {{.}}
+{{end}}
+## Your right to a statement of reasons
+If you believe this summary is incomplete, you may request a statement of specific reasons for our decision within 60 days of this notice. We will provide it within 30 days of receiving your request.
+
+## Your rights under the Fair Credit Reporting Act
+Our decision may have been based in whole or in part on information obtained in a report from a consumer reporting agency. The agency did not make the decision and cannot explain why it was made. You have the right to obtain a free copy of your report from the agency within 60 days of this notice and to dispute the accuracy or completeness of any information in it.
+
+## Equal Credit Opportunity Act notice
+The Federal Equal Credit Opportunity Act prohibits creditors from discriminating against credit applicants on the basis of race, color, religion, national origin, sex, marital status or age (provided the applicant has the capacity to enter into a binding contract); because all or part of the applicant's income derives from any public assistance program; or because the applicant has in good faith exercised any right under the Consumer Credit Protection Act. The federal agency that administers compliance with this law concerning this creditor is the Consumer Financial Protection Bureau, 1700 G Street NW, Washington, DC 20552.
+
+{{.Lender}}
+`
@@ -0,0 +1,260 @@
+package documents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// Client looks up and stores borrower documents held by the user service
+type Client struct {
+	baseURL      string
+	serviceToken string
+	httpClient   *http.Client
+	logger       *zap.Logger
+}
+
+// NewClient creates a new documents client
+func NewClient(baseURL, serviceToken string, timeout time.Duration, logger *zap.Logger) *Client {
+	return &Client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		serviceToken: serviceToken,
+		httpClient:   &http.Client{Timeout: timeout},
+		logger:       logger,
+	}
+}
+
+// ListUserDocuments returns the metadata of every document the user has uploaded
+func (c *Client) ListUserDocuments(ctx context.Context, userID string) ([]*domain.BorrowerDocument, error) {
+	logger := c.logger.With(
+		zap.String("user_id", userID),
+		zap.String("operation", "list_user_documents"),
+	)
+
+	endpoint := c.baseURL + "/internal/v1/users/" + url.PathEscape(userID) + "/documents"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build documents request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.serviceToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Documents request failed", zap.Error(err))
+		return nil, fmt.Errorf("failed to call user service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("Unexpected documents response", zap.Int("status", resp.StatusCode))
+		return nil, fmt.Errorf("unexpected documents status: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Documents []*domain.BorrowerDocument `json:"documents"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode documents response: %w", err)
+	}
+	if !body.Success {
+		return nil, fmt.Errorf("user service returned no documents")
+	}
+
+	return body.Data.Documents, nil
+}
+
+// UploadUserDocument streams a document to the user service, which validates, encrypts and stores
+// it for the user, and returns the stored document's metadata
+func (c *Client) UploadUserDocument(ctx context.Context, userID string, upload *domain.DocumentUpload) (*domain.BorrowerDocument, error) {
+	logger := c.logger.With(
+		zap.String("user_id", userID),
+		zap.String("document_type", upload.DocumentType),
+		zap.String("operation", "upload_user_document"),
+	)
+
+	// Write the multipart body as the request is sent so the file is never buffered whole
+	bodyReader, bodyWriter := io.Pipe()
+	form := multipart.NewWriter(bodyWriter)
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		bodyWriter.CloseWithError(writeDocumentForm(form, upload))
+	}()
+	// The content may be the caller's request body, so stop reading it before returning
+	defer func() {
+		bodyReader.Close()
+		<-written
+	}()
+
+	endpoint := c.baseURL + "/internal/v1/users/" + url.PathEscape(userID) + "/documents"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build upload request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.serviceToken)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Reading the content fails with a LoanError when the document is over the size limit
+		var loanErr *domain.LoanError
+		if errors.As(err, &loanErr) {
+			logger.Warn("Document rejected while streaming", zap.String("error_code", loanErr.Code))
+			return nil, loanErr
+		}
+		logger.Error("Document upload request failed", zap.Error(err))
+		return nil, fmt.Errorf("failed to call user service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		if rejection := documentRejection(resp); rejection != nil {
+			logger.Warn("User service rejected document",
+				zap.Int("status", resp.StatusCode),
+				zap.String("error_code", rejection.Code))
+			return nil, rejection
+		}
+		logger.Error("Unexpected document upload response", zap.Int("status", resp.StatusCode))
+		return nil, fmt.Errorf("unexpected document upload status: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Success bool                     `json:"success"`
+		Data    *domain.BorrowerDocument `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode document upload response: %w", err)
+	}
+	if !body.Success || body.Data == nil {
+		return nil, fmt.Errorf("user service returned no document")
+	}
+
+	logger.Info("Document uploaded to user service", zap.String("document_id", body.Data.ID))
+	return body.Data, nil
+}
+
+// DownloadUserDocument opens a stream of a stored document's content. The caller must close it.
+func (c *Client) DownloadUserDocument(ctx context.Context, userID, documentID string) (*domain.DocumentContent, error) {
+	logger := c.logger.With(
+		zap.String("user_id", userID),
+		zap.String("document_id", documentID),
+		zap.String("operation", "download_user_document"),
+	)
+
+	endpoint := c.baseURL + "/internal/v1/users/" + url.PathEscape(userID) + "/documents/" + url.PathEscape(documentID) + "/content"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build document download request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.serviceToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Document download request failed", zap.Error(err))
+		return nil, fmt.Errorf("failed to call user service: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("document %s not found", documentID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		logger.Error("Unexpected document download response", zap.Int("status", resp.StatusCode))
+		return nil, fmt.Errorf("unexpected document download status: %d", resp.StatusCode)
+	}
+
+	fileName := documentID
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		fileName = params["filename"]
+	}
+
+	return &domain.DocumentContent{
+		FileName: fileName,
+		MimeType: resp.Header.Get("Content-Type"),
+		Size:     resp.ContentLength,
+		Content:  resp.Body,
+	}, nil
+}
+
+// documentRejection maps a user service 4xx response to an upload error for the borrower.
+// Authentication failures are this service's credentials being refused, so they are not the
+// borrower's fault and are left to the caller as upstream failures.
+func documentRejection(resp *http.Response) *domain.LoanError {
+	if resp.StatusCode < 400 || resp.StatusCode >= 500 ||
+		resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil
+	}
+
+	var body struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&body)
+	description := body.Error.Message
+	if description == "" {
+		description = fmt.Sprintf("User service rejected the document with status %d", resp.StatusCode)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusRequestEntityTooLarge:
+		return &domain.LoanError{
+			Code:        domain.LOAN_037,
+			Message:     "Document file too large",
+			Description: description,
+			HTTPStatus:  http.StatusRequestEntityTooLarge,
+		}
+	case http.StatusUnsupportedMediaType:
+		return &domain.LoanError{
+			Code:        domain.LOAN_038,
+			Message:     "Unsupported document format",
+			Description: description,
+			HTTPStatus:  http.StatusUnsupportedMediaType,
+		}
+	default:
+		return &domain.LoanError{
+			Code:        domain.LOAN_020,
+			Message:     "Document rejected",
+			Description: description,
+			HTTPStatus:  resp.StatusCode,
+		}
+	}
+}
+
+// writeDocumentForm writes the document type and file parts of an upload
+func writeDocumentForm(form *multipart.Writer, upload *domain.DocumentUpload) error {
+	if err := form.WriteField("document_type", upload.DocumentType); err != nil {
+		return err
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, upload.FileName))
+	header.Set("Content-Type", upload.MimeType)
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, upload.Content); err != nil {
+		return err
+	}
+
+	return form.Close()
+}
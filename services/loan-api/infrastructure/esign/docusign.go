@@ -0,0 +1,206 @@
+package esign
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// ProviderDocuSign identifies DocuSign envelopes
+const ProviderDocuSign = "docusign"
+
+// docuSignSignatureHeader carries the HMAC of a Connect webhook body
+const docuSignSignatureHeader = "X-DocuSign-Signature-1"
+
+// docuSignEvents maps Connect envelope events to signature statuses
+var docuSignEvents = map[string]domain.SignatureStatus{
+	"envelope-sent":      domain.SignatureSent,
+	"envelope-delivered": domain.SignatureDelivered,
+	"envelope-completed": domain.SignatureCompleted,
+	"envelope-declined":  domain.SignatureDeclined,
+	"envelope-voided":    domain.SignatureVoided,
+}
+
+// DocuSignClient sends agreements for signature with the DocuSign eSignature REST API
+type DocuSignClient struct {
+	baseURL       string
+	accountID     string
+	accessToken   string
+	webhookSecret string
+	httpClient    *http.Client
+	logger        *zap.Logger
+}
+
+// NewDocuSignClient creates a new DocuSign client
+func NewDocuSignClient(baseURL, accountID, accessToken, webhookSecret string, timeout time.Duration, logger *zap.Logger) *DocuSignClient {
+	if baseURL == "" {
+		baseURL = "https://demo.docusign.net/restapi"
+	}
+	return &DocuSignClient{
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		accountID:     accountID,
+		accessToken:   accessToken,
+		webhookSecret: webhookSecret,
+		httpClient:    &http.Client{Timeout: timeout},
+		logger:        logger,
+	}
+}
+
+// Name returns the provider name recorded on envelopes
+func (c *DocuSignClient) Name() string {
+	return ProviderDocuSign
+}
+
+// SendEnvelope creates an envelope from the agreement and sends it to the signer, returning the
+// DocuSign envelope ID
+func (c *DocuSignClient) SendEnvelope(ctx context.Context, agreement *domain.RenderedDocument, signer domain.Signer) (string, error) {
+	logger := c.logger.With(zap.String("operation", "send_envelope"))
+
+	envelope := map[string]interface{}{
+		"emailSubject": "Please sign your " + agreement.Title,
+		"status":       "sent",
+		"documents": []map[string]interface{}{{
+			"documentId":     "1",
+			"name":           agreement.Title,
+			"fileExtension":  strings.TrimPrefix(path.Ext(agreement.FileName), "."),
+			"documentBase64": base64.StdEncoding.EncodeToString(agreement.Content),
+		}},
+		"recipients": map[string]interface{}{
+			"signers": []map[string]interface{}{{
+				"recipientId":  "1",
+				"routingOrder": "1",
+				"name":         signer.Name,
+				"email":        signer.Email,
+				"tabs": map[string]interface{}{
+					"signHereTabs":   []map[string]string{{"anchorString": domain.AgreementSignatureAnchor, "anchorUnits": "pixels", "anchorYOffset": "-5"}},
+					"dateSignedTabs": []map[string]string{{"anchorString": domain.AgreementDateAnchor, "anchorUnits": "pixels"}},
+				},
+			}},
+		},
+	}
+
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode envelope: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.envelopesURL(), bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to build envelope request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Envelope request failed", zap.Error(err))
+		return "", fmt.Errorf("failed to call DocuSign: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		logger.Error("Unexpected envelope response", zap.Int("status", resp.StatusCode), zap.ByteString("body", body))
+		return "", fmt.Errorf("unexpected envelope status: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		EnvelopeID string `json:"envelopeId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode envelope response: %w", err)
+	}
+	if result.EnvelopeID == "" {
+		return "", fmt.Errorf("DocuSign returned no envelope ID")
+	}
+
+	logger.Info("Envelope sent", zap.String("envelope_id", result.EnvelopeID))
+	return result.EnvelopeID, nil
+}
+
+// DownloadSignedDocument downloads the completed envelope's documents combined into one PDF
+func (c *DocuSignClient) DownloadSignedDocument(ctx context.Context, envelopeID string) ([]byte, error) {
+	endpoint := c.envelopesURL() + "/" + url.PathEscape(envelopeID) + "/documents/combined"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build document request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Accept", "application/pdf")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Signed document request failed", zap.String("envelope_id", envelopeID), zap.Error(err))
+		return nil, fmt.Errorf("failed to call DocuSign: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.logger.Error("Unexpected signed document response", zap.String("envelope_id", envelopeID), zap.Int("status", resp.StatusCode))
+		return nil, fmt.Errorf("unexpected signed document status: %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, domain.MaxDocumentSize+1))
+}
+
+// ParseWebhook verifies a Connect notification's HMAC signature and returns the envelope event it
+// reports. Events that do not change the signature status are returned as nil.
+func (c *DocuSignClient) ParseWebhook(body []byte, header func(string) string) (*domain.SignatureEvent, error) {
+	if c.webhookSecret == "" {
+		return nil, fmt.Errorf("webhook secret is not configured")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(header(docuSignSignatureHeader))
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook signature encoding: %w", err)
+	}
+	mac := hmac.New(sha256.New, []byte(c.webhookSecret))
+	mac.Write(body)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, fmt.Errorf("webhook signature mismatch")
+	}
+
+	var notification struct {
+		Event             string    `json:"event"`
+		GeneratedDateTime time.Time `json:"generatedDateTime"`
+		Data              struct {
+			EnvelopeID string `json:"envelopeId"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook: %w", err)
+	}
+
+	status, ok := docuSignEvents[notification.Event]
+	if !ok {
+		return nil, nil
+	}
+	if notification.Data.EnvelopeID == "" {
+		return nil, fmt.Errorf("webhook has no envelope ID")
+	}
+
+	return &domain.SignatureEvent{
+		ProviderEnvelopeID: notification.Data.EnvelopeID,
+		Status:             status,
+		OccurredAt:         notification.GeneratedDateTime,
+	}, nil
+}
+
+// envelopesURL is the envelopes collection of the configured account
+func (c *DocuSignClient) envelopesURL() string {
+	return c.baseURL + "/v2.1/accounts/" + url.PathEscape(c.accountID) + "/envelopes"
+}
@@ -0,0 +1,75 @@
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Client looks up borrower identity verification state in the user service
+type Client struct {
+	baseURL      string
+	serviceToken string
+	httpClient   *http.Client
+	logger       *zap.Logger
+}
+
+// NewClient creates a new identity verification client
+func NewClient(baseURL, serviceToken string, timeout time.Duration, logger *zap.Logger) *Client {
+	return &Client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		serviceToken: serviceToken,
+		httpClient:   &http.Client{Timeout: timeout},
+		logger:       logger,
+	}
+}
+
+// IsIdentityVerificationStale reports whether the user's verified identity has expired.
+// A user who was never verified is not stale.
+func (c *Client) IsIdentityVerificationStale(ctx context.Context, userID string) (bool, error) {
+	logger := c.logger.With(
+		zap.String("user_id", userID),
+		zap.String("operation", "get_identity_verification"),
+	)
+
+	endpoint := c.baseURL + "/internal/v1/users/" + url.PathEscape(userID) + "/identity-verification"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to build identity verification request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.serviceToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Identity verification request failed", zap.Error(err))
+		return false, fmt.Errorf("failed to call user service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("Unexpected identity verification response", zap.Int("status", resp.StatusCode))
+		return false, fmt.Errorf("unexpected identity verification status: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Status string `json:"status"`
+			Stale  bool   `json:"stale"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return false, fmt.Errorf("failed to decode identity verification response: %w", err)
+	}
+	if !body.Success {
+		return false, fmt.Errorf("user service returned no identity verification")
+	}
+
+	return body.Data.Stale, nil
+}
@@ -0,0 +1,83 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Client sends borrower notifications through the user service
+type Client struct {
+	baseURL      string
+	serviceToken string
+	httpClient   *http.Client
+	logger       *zap.Logger
+}
+
+// NewClient creates a new notification client
+func NewClient(baseURL, serviceToken string, timeout time.Duration, logger *zap.Logger) *Client {
+	return &Client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		serviceToken: serviceToken,
+		httpClient:   &http.Client{Timeout: timeout},
+		logger:       logger,
+	}
+}
+
+// SendNotification delivers a notification to the given user
+func (c *Client) SendNotification(ctx context.Context, userID, title, message string, data map[string]interface{}) error {
+	return c.send(ctx, userID, title, message, data, false)
+}
+
+// SendEmailNotification delivers a notification to the given user and emails it to their address
+func (c *Client) SendEmailNotification(ctx context.Context, userID, title, message string, data map[string]interface{}) error {
+	return c.send(ctx, userID, title, message, data, true)
+}
+
+// send asks the user service to deliver a notification, by email as well when email is set
+func (c *Client) send(ctx context.Context, userID, title, message string, data map[string]interface{}, email bool) error {
+	logger := c.logger.With(
+		zap.String("user_id", userID),
+		zap.String("operation", "send_notification"),
+		zap.Bool("email", email),
+	)
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"title":   title,
+		"message": message,
+		"data":    data,
+		"email":   email,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification request: %w", err)
+	}
+
+	endpoint := c.baseURL + "/internal/v1/users/" + url.PathEscape(userID) + "/notifications"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.serviceToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Notification request failed", zap.Error(err))
+		return fmt.Errorf("failed to call user service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("Unexpected notification response", zap.Int("status", resp.StatusCode))
+		return fmt.Errorf("unexpected notification status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
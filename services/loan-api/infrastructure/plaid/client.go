@@ -0,0 +1,175 @@
+package plaid
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// Client links borrowers' bank accounts with Plaid Auth. Items are removed once the account and
+// routing numbers have been retrieved, so no Plaid access token is kept.
+type Client struct {
+	baseURL    string
+	clientID   string
+	secret     string
+	clientName string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewClient creates a new Plaid client
+func NewClient(baseURL, clientID, secret, clientName string, timeout time.Duration, logger *zap.Logger) *Client {
+	if baseURL == "" {
+		baseURL = "https://sandbox.plaid.com"
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		clientID:   clientID,
+		secret:     secret,
+		clientName: clientName,
+		httpClient: &http.Client{Timeout: timeout},
+		logger:     logger,
+	}
+}
+
+// CreateLinkToken creates a token for the borrower's client to open Plaid Link with for Auth
+func (c *Client) CreateLinkToken(ctx context.Context, userID string) (*domain.PlaidLinkToken, error) {
+	var result struct {
+		LinkToken  string    `json:"link_token"`
+		Expiration time.Time `json:"expiration"`
+	}
+	if err := c.post(ctx, "/link/token/create", map[string]interface{}{
+		"client_name":   c.clientName,
+		"user":          map[string]string{"client_user_id": userID},
+		"products":      []string{"auth"},
+		"country_codes": []string{"US"},
+		"language":      "en",
+	}, &result); err != nil {
+		return nil, fmt.Errorf("failed to create link token: %w", err)
+	}
+
+	return &domain.PlaidLinkToken{LinkToken: result.LinkToken, Expiration: result.Expiration}, nil
+}
+
+// AuthenticateAccount exchanges a Link public token and returns the account and routing numbers of
+// the account the borrower selected
+func (c *Client) AuthenticateAccount(ctx context.Context, publicToken, accountID string) (*domain.AuthenticatedAccount, error) {
+	logger := c.logger.With(zap.String("operation", "plaid_authenticate_account"))
+
+	var exchange struct {
+		AccessToken string `json:"access_token"`
+		ItemID      string `json:"item_id"`
+	}
+	if err := c.post(ctx, "/item/public_token/exchange", map[string]interface{}{
+		"public_token": publicToken,
+	}, &exchange); err != nil {
+		return nil, fmt.Errorf("failed to exchange public token: %w", err)
+	}
+	defer c.removeItem(ctx, logger, exchange.AccessToken, exchange.ItemID)
+
+	var auth struct {
+		Accounts []struct {
+			AccountID string `json:"account_id"`
+			Name      string `json:"name"`
+			Subtype   string `json:"subtype"`
+		} `json:"accounts"`
+		Numbers struct {
+			ACH []struct {
+				AccountID string `json:"account_id"`
+				Account   string `json:"account"`
+				Routing   string `json:"routing"`
+			} `json:"ach"`
+		} `json:"numbers"`
+	}
+	if err := c.post(ctx, "/auth/get", map[string]interface{}{
+		"access_token": exchange.AccessToken,
+		"options":      map[string][]string{"account_ids": {accountID}},
+	}, &auth); err != nil {
+		return nil, fmt.Errorf("failed to get account numbers: %w", err)
+	}
+
+	account := &domain.AuthenticatedAccount{AccountID: accountID}
+	for _, candidate := range auth.Accounts {
+		if candidate.AccountID != accountID {
+			continue
+		}
+		switch candidate.Subtype {
+		case "checking":
+			account.AccountType = domain.AccountChecking
+		case "savings":
+			account.AccountType = domain.AccountSavings
+		default:
+			return nil, fmt.Errorf("account subtype %q cannot receive ACH credits", candidate.Subtype)
+		}
+		account.Name = candidate.Name
+	}
+	for _, numbers := range auth.Numbers.ACH {
+		if numbers.AccountID == accountID {
+			account.AccountNumber = numbers.Account
+			account.RoutingNumber = numbers.Routing
+		}
+	}
+	if account.AccountType == "" || account.AccountNumber == "" {
+		return nil, fmt.Errorf("no ACH numbers returned for account %s", accountID)
+	}
+
+	return account, nil
+}
+
+// removeItem revokes the access token once the numbers have been read; failures are only logged
+func (c *Client) removeItem(ctx context.Context, logger *zap.Logger, accessToken, itemID string) {
+	if err := c.post(ctx, "/item/remove", map[string]interface{}{
+		"access_token": accessToken,
+	}, &struct{}{}); err != nil {
+		logger.Warn("Failed to remove Plaid item", zap.String("item_id", itemID), zap.Error(err))
+	}
+}
+
+// post sends an authenticated request and decodes a successful response into out
+func (c *Client) post(ctx context.Context, path string, body map[string]interface{}, out interface{}) error {
+	body["client_id"] = c.clientID
+	body["secret"] = c.secret
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call Plaid: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var plaidError struct {
+			ErrorType    string `json:"error_type"`
+			ErrorCode    string `json:"error_code"`
+			ErrorMessage string `json:"error_message"`
+			RequestID    string `json:"request_id"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&plaidError)
+		c.logger.Error("Unexpected Plaid response",
+			zap.String("path", path),
+			zap.Int("status", resp.StatusCode),
+			zap.String("error_code", plaidError.ErrorCode),
+			zap.String("request_id", plaidError.RequestID))
+		return fmt.Errorf("plaid error %s: %s", plaidError.ErrorCode, plaidError.ErrorMessage)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
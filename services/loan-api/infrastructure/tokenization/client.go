@@ -0,0 +1,133 @@
+package tokenization
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Token types in the user service token vault
+const (
+	TokenTypeSSN               = "ssn"
+	TokenTypeBankAccountNumber = "bank_account_number"
+	TokenTypeRoutingNumber     = "routing_number"
+)
+
+// Client exchanges raw identifiers for opaque tokens, and back, via the user service token vault
+type Client struct {
+	baseURL      string
+	serviceToken string
+	httpClient   *http.Client
+	logger       *zap.Logger
+}
+
+// NewClient creates a new tokenization client
+func NewClient(baseURL, serviceToken string, timeout time.Duration, logger *zap.Logger) *Client {
+	return &Client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		serviceToken: serviceToken,
+		httpClient:   &http.Client{Timeout: timeout},
+		logger:       logger,
+	}
+}
+
+// Tokenize returns the vault token and last four digits for the given value
+func (c *Client) Tokenize(ctx context.Context, tokenType, value string) (string, string, error) {
+	logger := c.logger.With(
+		zap.String("token_type", tokenType),
+		zap.String("operation", "tokenize"),
+	)
+
+	payload, err := json.Marshal(map[string]string{
+		"token_type": tokenType,
+		"value":      value,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal tokenize request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/internal/v1/tokens", bytes.NewReader(payload))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to build tokenize request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.serviceToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Tokenize request failed", zap.Error(err))
+		return "", "", fmt.Errorf("failed to call token vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("Unexpected tokenize response", zap.Int("status", resp.StatusCode))
+		return "", "", fmt.Errorf("unexpected tokenize status: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Token string `json:"token"`
+			Last4 string `json:"last4"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", "", fmt.Errorf("failed to decode tokenize response: %w", err)
+	}
+	if !body.Success || body.Data.Token == "" {
+		return "", "", fmt.Errorf("token vault returned no token")
+	}
+
+	return body.Data.Token, body.Data.Last4, nil
+}
+
+// Detokenize resolves a vault token to the value it stands for
+func (c *Client) Detokenize(ctx context.Context, token string) (string, error) {
+	logger := c.logger.With(zap.String("operation", "detokenize"))
+
+	payload, err := json.Marshal(map[string]string{"token": token})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal detokenize request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/internal/v1/tokens/detokenize", bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to build detokenize request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.serviceToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Detokenize request failed", zap.Error(err))
+		return "", fmt.Errorf("failed to call token vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("Unexpected detokenize response", zap.Int("status", resp.StatusCode))
+		return "", fmt.Errorf("unexpected detokenize status: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Value string `json:"value"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode detokenize response: %w", err)
+	}
+	if !body.Success || body.Data.Value == "" {
+		return "", fmt.Errorf("token vault returned no value")
+	}
+
+	return body.Data.Value, nil
+}
@@ -0,0 +1,111 @@
+package valuation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// Client values collateral with a valuation vendor's API, e.g. a vehicle pricing guide looked up
+// by VIN and mileage or an equipment appraisal service
+type Client struct {
+	baseURL    string
+	apiKey     string
+	source     string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewClient creates a new valuation client; source is recorded as the source of its valuations
+func NewClient(baseURL, apiKey, source string, timeout time.Duration, logger *zap.Logger) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		source:     source,
+		httpClient: &http.Client{Timeout: timeout},
+		logger:     logger,
+	}
+}
+
+// Name returns the valuation source the client reports
+func (c *Client) Name() string {
+	return c.source
+}
+
+// ValueCollateral requests the current value of a vehicle or asset
+func (c *Client) ValueCollateral(ctx context.Context, collateral *domain.Collateral) (*domain.CollateralValuation, error) {
+	body := map[string]interface{}{
+		"collateral_type": collateral.Type,
+		"stated_value":    collateral.StatedValue,
+	}
+	path := "/valuations/assets"
+	switch {
+	case collateral.Vehicle != nil:
+		path = "/valuations/vehicles"
+		body["vehicle"] = collateral.Vehicle
+	case collateral.Asset != nil:
+		body["asset"] = collateral.Asset
+	default:
+		return nil, fmt.Errorf("collateral %s has no details to value", collateral.ID)
+	}
+
+	var result struct {
+		ValuationID string    `json:"valuation_id"`
+		Value       float64   `json:"value"`
+		ValuedAt    time.Time `json:"valued_at"`
+	}
+	if err := c.post(ctx, path, body, &result); err != nil {
+		return nil, fmt.Errorf("failed to value collateral: %w", err)
+	}
+
+	return &domain.CollateralValuation{
+		Source:    c.source,
+		Value:     result.Value,
+		Reference: result.ValuationID,
+		ValuedAt:  result.ValuedAt,
+	}, nil
+}
+
+// post sends an authenticated request and decodes a successful response into out
+func (c *Client) post(ctx context.Context, path string, body map[string]interface{}, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call valuation provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var providerError struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&providerError)
+		c.logger.Error("Unexpected valuation provider response",
+			zap.String("path", path),
+			zap.Int("status", resp.StatusCode),
+			zap.String("error_code", providerError.Code))
+		return fmt.Errorf("valuation provider error %s: %s", providerError.Code, providerError.Message)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
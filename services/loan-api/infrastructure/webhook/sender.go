@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderSignature = "X-Webhook-Signature" // t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+)
+
+// Sender posts webhook payloads to partner endpoints, signed with each subscription's secret.
+// Connections are refused to any resolved address that is not public, so a hostname re-pointed
+// after registration cannot reach internal services, and redirects are not followed.
+type Sender struct {
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewSender creates a new webhook sender
+func NewSender(timeout time.Duration, logger *zap.Logger) *Sender {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: refuseNonPublicAddress,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Sender{
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		logger: logger,
+	}
+}
+
+// Send posts a payload to the subscription's endpoint and returns the response status. Any status
+// outside 2xx is returned as an error along with the status.
+func (s *Sender) Send(ctx context.Context, subscription *domain.WebhookSubscription, delivery *domain.WebhookDelivery, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, time.Now().UTC(), body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	// Drain a little of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+
+	s.logger.Debug("Webhook delivered",
+		zap.String("delivery_id", delivery.ID),
+		zap.String("subscription_id", subscription.ID),
+		zap.Int("status", resp.StatusCode))
+	return resp.StatusCode, nil
+}
+
+// refuseNonPublicAddress runs after DNS resolution, just before each connection is made
+func refuseNonPublicAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid webhook address %q: %w", address, err)
+	}
+	if !domain.IsPublicWebhookAddress(net.ParseIP(host)) {
+		return fmt.Errorf("webhook address %s is not public", host)
+	}
+	return nil
+}
+
+// Sign returns the signature header value for a body sent at the given time. Partners recompute
+// the HMAC over "<t>.<body>" with their secret and reject stale timestamps to prevent replays.
+func Sign(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
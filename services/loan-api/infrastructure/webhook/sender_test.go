@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+func TestSender_RefusesNonPublicAddresses(t *testing.T) {
+	hits := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := NewSender(5*time.Second, zap.NewNop())
+	subscription := &domain.WebhookSubscription{ID: "sub-1", URL: server.URL, Secret: "secret"}
+	delivery := &domain.WebhookDelivery{ID: "delivery-1", EventType: domain.WebhookOfferAccepted}
+
+	status, err := sender.Send(context.Background(), subscription, delivery, []byte(`{}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not public")
+	assert.Zero(t, status)
+	assert.Zero(t, hits)
+}
+
+func TestSender_DoesNotFollowRedirects(t *testing.T) {
+	internalHits := 0
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		internalHits++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer internal.Close()
+
+	partner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internal.URL, http.StatusTemporaryRedirect)
+	}))
+	defer partner.Close()
+
+	sender := NewSender(5*time.Second, zap.NewNop())
+	// The test servers listen on loopback, which the sender refuses; dial them directly so only the
+	// redirect policy is under test
+	sender.httpClient.Transport = http.DefaultTransport
+
+	subscription := &domain.WebhookSubscription{ID: "sub-1", URL: partner.URL, Secret: "secret"}
+	delivery := &domain.WebhookDelivery{ID: "delivery-1", EventType: domain.WebhookOfferAccepted}
+
+	status, err := sender.Send(context.Background(), subscription, delivery, []byte(`{}`))
+	require.Error(t, err)
+	assert.Equal(t, http.StatusTemporaryRedirect, status)
+	assert.Zero(t, internalHits)
+}
@@ -2,6 +2,8 @@ package workflow
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -17,6 +19,7 @@ type ConductorClient interface {
 	TerminateWorkflow(ctx context.Context, workflowID string, reason string) error
 	PauseWorkflow(ctx context.Context, workflowID string, reason string) error
 	ResumeWorkflow(ctx context.Context, workflowID string) error
+	RerunWorkflow(ctx context.Context, workflowID string, taskID string, taskInput map[string]interface{}) error
 	UpdateTask(ctx context.Context, taskID string, workflowInstanceId string, referenceTaskName string, status string, output map[string]interface{}) error
 	GetBaseURL() string
 }
@@ -34,23 +37,33 @@ type WorkflowExecution struct {
 
 // WorkflowStatus represents the status of a workflow
 type WorkflowStatus struct {
-	WorkflowID string                 `json:"workflowId"`
-	Status     string                 `json:"status"`
-	Tasks      []TaskStatus           `json:"tasks"`
-	Input      map[string]interface{} `json:"input"`
-	Output     map[string]interface{} `json:"output"`
+	WorkflowID            string                 `json:"workflowId"`
+	WorkflowName          string                 `json:"workflowName"`
+	WorkflowVersion       int                    `json:"workflowVersion"`
+	CorrelationID         string                 `json:"correlationId"`
+	Status                string                 `json:"status"`
+	ReasonForIncompletion string                 `json:"reasonForIncompletion,omitempty"`
+	Tasks                 []TaskStatus           `json:"tasks"`
+	Input                 map[string]interface{} `json:"input"`
+	Output                map[string]interface{} `json:"output"`
+	StartTime             time.Time              `json:"startTime"`
+	EndTime               *time.Time             `json:"endTime,omitempty"`
 }
 
 // TaskStatus represents the status of a workflow task
 type TaskStatus struct {
-	TaskID            string                 `json:"taskId"`
-	TaskType          string                 `json:"taskType"`
-	Status            string                 `json:"status"`
-	ReferenceTaskName string                 `json:"referenceTaskName"`
-	Input             map[string]interface{} `json:"inputData"`
-	Output            map[string]interface{} `json:"outputData"`
-	StartTime         time.Time              `json:"startTime"`
-	EndTime           *time.Time             `json:"endTime,omitempty"`
+	TaskID                string                 `json:"taskId"`
+	TaskType              string                 `json:"taskType"`
+	Status                string                 `json:"status"`
+	ReferenceTaskName     string                 `json:"referenceTaskName"`
+	Seq                   int                    `json:"seq"`
+	RetryCount            int                    `json:"retryCount"`
+	ReasonForIncompletion string                 `json:"reasonForIncompletion,omitempty"`
+	Input                 map[string]interface{} `json:"inputData"`
+	Output                map[string]interface{} `json:"outputData"`
+	ScheduledTime         *time.Time             `json:"scheduledTime,omitempty"`
+	StartTime             time.Time              `json:"startTime"`
+	EndTime               *time.Time             `json:"endTime,omitempty"`
 }
 
 // LoanWorkflowOrchestrator manages loan processing workflows using Netflix Conductor
@@ -87,8 +100,16 @@ func (o *LoanWorkflowOrchestrator) StartLoanProcessingWorkflow(ctx context.Conte
 		"monthlyDebt":   application.MonthlyDebt,
 		"requestedTerm": application.RequestedTerm,
 		"currentState":  application.CurrentState,
+		"priority":      application.WorkflowPriority(),
 		"startTime":     time.Now().UTC(),
 	}
+	addCoBorrowerInput(workflowInput, application)
+	addCollateralInput(workflowInput, application)
+	workflowInput["dtiRatio"] = application.CalculateDTI()
+
+	checklist := application.DocumentChecklist()
+	workflowInput["requiredDocuments"] = checklist.Borrower
+	workflowInput["coBorrowerRequiredDocuments"] = checklist.CoBorrower
 
 	logger.Info("Starting loan processing workflow",
 		zap.Float64("loan_amount", application.LoanAmount),
@@ -129,6 +150,23 @@ func (o *LoanWorkflowOrchestrator) StartPreQualificationWorkflow(ctx context.Con
 		"employmentStatus": request.EmploymentStatus,
 		"startTime":        time.Now().UTC(),
 	}
+	if request.HasCoBorrower() {
+		workflowInput["coBorrowerAnnualIncome"] = request.CoBorrowerAnnualIncome
+		workflowInput["coBorrowerMonthlyDebt"] = request.CoBorrowerMonthlyDebt
+		workflowInput["coBorrowerEmploymentStatus"] = request.CoBorrowerEmploymentStatus
+	}
+	if request.Obligations != nil {
+		workflowInput["obligations"] = request.Obligations
+	}
+	if request.CoBorrowerObligations != nil {
+		workflowInput["coBorrowerObligations"] = request.CoBorrowerObligations
+	}
+	if request.IncomeSources != nil {
+		workflowInput["incomeSources"] = request.IncomeSources
+	}
+	if request.CoBorrowerIncomeSources != nil {
+		workflowInput["coBorrowerIncomeSources"] = request.CoBorrowerIncomeSources
+	}
 
 	logger.Info("Starting pre-qualification workflow",
 		zap.Float64("loan_amount", request.LoanAmount),
@@ -160,21 +198,9 @@ func (o *LoanWorkflowOrchestrator) StartUnderwritingWorkflow(ctx context.Context
 		zap.String("operation", "start_underwriting_workflow"),
 	)
 
-	workflowInput := map[string]interface{}{
-		"applicationId": application.ID,
-		"userId":        application.UserID,
-		"loanAmount":    application.LoanAmount,
-		"annualIncome":  application.AnnualIncome,
-		"monthlyIncome": application.MonthlyIncome,
-		"monthlyDebt":   application.MonthlyDebt,
-		"dtiRatio":      application.CalculateDTI(),
-		"riskScore":     application.RiskScore,
-		"startTime":     time.Now().UTC(),
-	}
-
 	logger.Info("Starting underwriting workflow")
 
-	execution, err := o.conductorClient.StartWorkflow(ctx, "underwriting_workflow", 1, workflowInput)
+	execution, err := o.conductorClient.StartWorkflow(ctx, "underwriting_workflow", 1, underwritingInput(application))
 	if err != nil {
 		logger.Error("Failed to start underwriting workflow", zap.Error(err))
 		return nil, &domain.LoanError{
@@ -192,6 +218,138 @@ func (o *LoanWorkflowOrchestrator) StartUnderwritingWorkflow(ctx context.Context
 	return execution, nil
 }
 
+// StartReunderwritingWorkflow starts the re-underwriting workflow of an approved application that
+// changed materially before funding. It invalidates the prior underwriting result and runs the
+// underwriting workflow again on the application as it now stands.
+func (o *LoanWorkflowOrchestrator) StartReunderwritingWorkflow(ctx context.Context, application *domain.LoanApplication, changes []domain.MaterialChange) (*WorkflowExecution, error) {
+	logger := o.logger.With(
+		zap.String("application_id", application.ID),
+		zap.String("operation", "start_reunderwriting_workflow"),
+	)
+
+	workflowInput := underwritingInput(application)
+	workflowInput["changes"] = changes
+
+	logger.Info("Starting re-underwriting workflow", zap.Int("changes", len(changes)))
+
+	execution, err := o.conductorClient.StartWorkflow(ctx, "reunderwriting_workflow", 1, workflowInput)
+	if err != nil {
+		logger.Error("Failed to start re-underwriting workflow", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_011,
+			Message:     "Failed to start re-underwriting workflow",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	logger.Info("Re-underwriting workflow started successfully",
+		zap.String("workflow_id", execution.WorkflowID),
+	)
+
+	return execution, nil
+}
+
+// StartCounterOfferWorkflow starts the counter offer workflow for a borrower's request for
+// different terms than an offer's
+func (o *LoanWorkflowOrchestrator) StartCounterOfferWorkflow(ctx context.Context, offer *domain.LoanOffer, negotiation *domain.OfferNegotiation) (*WorkflowExecution, error) {
+	logger := o.logger.With(
+		zap.String("application_id", offer.ApplicationID),
+		zap.String("offer_id", offer.ID),
+		zap.String("operation", "start_counter_offer_workflow"),
+	)
+
+	requestedAmount := offer.OfferAmount
+	if negotiation.RequestedAmount != nil {
+		requestedAmount = *negotiation.RequestedAmount
+	}
+	requestedTermMonths := offer.TermMonths
+	if negotiation.RequestedTermMonths != nil {
+		requestedTermMonths = *negotiation.RequestedTermMonths
+	}
+
+	workflowInput := map[string]interface{}{
+		"applicationId":       offer.ApplicationID,
+		"offerId":             offer.ID,
+		"negotiationId":       negotiation.ID,
+		"requestedAmount":     requestedAmount,
+		"requestedTermMonths": requestedTermMonths,
+		"currentAmount":       offer.OfferAmount,
+		"currentRate":         offer.InterestRate,
+		"currentAPR":          offer.APR,
+		"currentTermMonths":   offer.TermMonths,
+		"reason":              negotiation.Reason,
+		"startTime":           time.Now().UTC(),
+	}
+
+	logger.Info("Starting counter offer workflow")
+
+	execution, err := o.conductorClient.StartWorkflow(ctx, "counter_offer_workflow", 1, workflowInput)
+	if err != nil {
+		logger.Error("Failed to start counter offer workflow", zap.Error(err))
+		return nil, &domain.LoanError{
+			Code:        domain.LOAN_011,
+			Message:     "Failed to start counter offer workflow",
+			Description: err.Error(),
+			HTTPStatus:  500,
+		}
+	}
+
+	logger.Info("Counter offer workflow started successfully",
+		zap.String("workflow_id", execution.WorkflowID),
+	)
+
+	return execution, nil
+}
+
+// underwritingInput is the underwriting workflow's input for an application
+func underwritingInput(application *domain.LoanApplication) map[string]interface{} {
+	workflowInput := map[string]interface{}{
+		"applicationId": application.ID,
+		"userId":        application.UserID,
+		"loanAmount":    application.LoanAmount,
+		"annualIncome":  application.AnnualIncome,
+		"monthlyIncome": application.MonthlyIncome,
+		"monthlyDebt":   application.MonthlyDebt,
+		"dtiRatio":      application.CalculateDTI(),
+		"riskScore":     application.RiskScore,
+		"priority":      application.WorkflowPriority(),
+		"startTime":     time.Now().UTC(),
+	}
+	addCoBorrowerInput(workflowInput, application)
+	addCollateralInput(workflowInput, application)
+	return workflowInput
+}
+
+// addCoBorrowerInput adds the co-borrower's figures and the combined totals used for DTI to a workflow input
+func addCoBorrowerInput(workflowInput map[string]interface{}, application *domain.LoanApplication) {
+	workflowInput["hasCoBorrower"] = application.CoBorrower != nil
+	workflowInput["combinedAnnualIncome"] = application.CombinedAnnualIncome()
+	workflowInput["combinedMonthlyIncome"] = application.CombinedMonthlyIncome()
+	workflowInput["combinedMonthlyDebt"] = application.CombinedMonthlyDebt()
+	if application.CoBorrower == nil {
+		return
+	}
+	workflowInput["coBorrowerAnnualIncome"] = application.CoBorrower.AnnualIncome
+	workflowInput["coBorrowerMonthlyIncome"] = application.CoBorrower.MonthlyIncome
+	workflowInput["coBorrowerMonthlyDebt"] = application.CoBorrower.MonthlyDebt
+	workflowInput["coBorrowerEmploymentStatus"] = application.CoBorrower.EmploymentStatus
+}
+
+// addCollateralInput adds the collateral securing the application, its valuation and the
+// loan-to-value ratio the decision engine checks against policy to a workflow input
+func addCollateralInput(workflowInput map[string]interface{}, application *domain.LoanApplication) {
+	workflowInput["hasCollateral"] = application.Collateral != nil
+	if application.Collateral == nil {
+		return
+	}
+	workflowInput["collateralType"] = application.Collateral.Type
+	workflowInput["collateralValue"] = application.Collateral.Value
+	workflowInput["valuationSource"] = application.Collateral.ValuationSource
+	workflowInput["valuedAt"] = application.Collateral.ValuedAt
+	workflowInput["ltvRatio"] = application.Collateral.LTVRatio
+}
+
 // HandleStateTransition handles state transitions triggered by workflow events
 func (o *LoanWorkflowOrchestrator) HandleStateTransition(ctx context.Context, applicationID string, fromState, toState domain.ApplicationState) error {
 	logger := o.logger.With(
@@ -271,12 +429,7 @@ func (o *LoanWorkflowOrchestrator) GetWorkflowStatus(ctx context.Context, workfl
 	status, err := o.conductorClient.GetWorkflowStatus(ctx, workflowID)
 	if err != nil {
 		logger.Error("Failed to get workflow status", zap.Error(err))
-		return nil, &domain.LoanError{
-			Code:        domain.LOAN_012,
-			Message:     "Failed to get workflow status",
-			Description: err.Error(),
-			HTTPStatus:  500,
-		}
+		return nil, workflowError(workflowID, "Failed to get workflow status", err)
 	}
 
 	logger.Debug("Retrieved workflow status",
@@ -298,12 +451,7 @@ func (o *LoanWorkflowOrchestrator) TerminateWorkflow(ctx context.Context, workfl
 	err := o.conductorClient.TerminateWorkflow(ctx, workflowID, reason)
 	if err != nil {
 		logger.Error("Failed to terminate workflow", zap.Error(err))
-		return &domain.LoanError{
-			Code:        domain.LOAN_012,
-			Message:     "Failed to terminate workflow",
-			Description: err.Error(),
-			HTTPStatus:  500,
-		}
+		return workflowError(workflowID, "Failed to terminate workflow", err)
 	}
 
 	logger.Info("Workflow terminated successfully")
@@ -320,12 +468,7 @@ func (o *LoanWorkflowOrchestrator) PauseWorkflow(ctx context.Context, workflowID
 	err := o.conductorClient.PauseWorkflow(ctx, workflowID, "Paused by user request")
 	if err != nil {
 		logger.Error("Failed to pause workflow", zap.Error(err))
-		return &domain.LoanError{
-			Code:        domain.LOAN_012,
-			Message:     "Failed to pause workflow",
-			Description: err.Error(),
-			HTTPStatus:  500,
-		}
+		return workflowError(workflowID, "Failed to pause workflow", err)
 	}
 
 	logger.Info("Workflow paused successfully")
@@ -342,14 +485,94 @@ func (o *LoanWorkflowOrchestrator) ResumeWorkflow(ctx context.Context, workflowI
 	err := o.conductorClient.ResumeWorkflow(ctx, workflowID)
 	if err != nil {
 		logger.Error("Failed to resume workflow", zap.Error(err))
+		return workflowError(workflowID, "Failed to resume workflow", err)
+	}
+
+	logger.Info("Workflow resumed successfully")
+	return nil
+}
+
+// RerunWorkflowFromTask re-runs a workflow from one of its tasks, with the task's input replaced
+// when taskInput is given
+func (o *LoanWorkflowOrchestrator) RerunWorkflowFromTask(ctx context.Context, workflowID, taskID string, taskInput map[string]interface{}) error {
+	logger := o.logger.With(
+		zap.String("workflow_id", workflowID),
+		zap.String("task_id", taskID),
+		zap.String("operation", "rerun_workflow"),
+	)
+
+	err := o.conductorClient.RerunWorkflow(ctx, workflowID, taskID, taskInput)
+	if err != nil {
+		logger.Error("Failed to re-run workflow", zap.Error(err))
+		return workflowError(workflowID, "Failed to re-run workflow", err)
+	}
+
+	logger.Info("Workflow re-run from task")
+	return nil
+}
+
+// CompleteHumanTask completes the human task a workflow is waiting on with the given output
+func (o *LoanWorkflowOrchestrator) CompleteHumanTask(ctx context.Context, workflowID, referenceTaskName string, output map[string]interface{}) error {
+	logger := o.logger.With(
+		zap.String("workflow_id", workflowID),
+		zap.String("task_reference_name", referenceTaskName),
+		zap.String("operation", "complete_human_task"),
+	)
+
+	status, err := o.conductorClient.GetWorkflowStatus(ctx, workflowID)
+	if err != nil {
+		logger.Error("Failed to get workflow status", zap.Error(err))
+		return workflowError(workflowID, "Failed to complete human task", err)
+	}
+
+	var taskID string
+	for _, task := range status.Tasks {
+		if task.ReferenceTaskName == referenceTaskName && (task.Status == "IN_PROGRESS" || task.Status == "SCHEDULED") {
+			taskID = task.TaskID
+			break
+		}
+	}
+	if taskID == "" {
 		return &domain.LoanError{
-			Code:        domain.LOAN_012,
-			Message:     "Failed to resume workflow",
-			Description: err.Error(),
-			HTTPStatus:  500,
+			Code:        domain.LOAN_062,
+			Message:     "Workflow state conflict",
+			Description: fmt.Sprintf("Workflow %s is not waiting on task %s", workflowID, referenceTaskName),
+			HTTPStatus:  409,
 		}
 	}
 
-	logger.Info("Workflow resumed successfully")
+	if err := o.conductorClient.UpdateTask(ctx, taskID, workflowID, referenceTaskName, "COMPLETED", output); err != nil {
+		logger.Error("Failed to complete human task", zap.Error(err))
+		return workflowError(workflowID, "Failed to complete human task", err)
+	}
+
+	logger.Info("Human task completed", zap.String("task_id", taskID))
 	return nil
 }
+
+// workflowError maps a Conductor client error to the API error for a workflow operation
+func workflowError(workflowID, message string, err error) *domain.LoanError {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return &domain.LoanError{
+			Code:        domain.LOAN_061,
+			Message:     "Workflow not found",
+			Description: fmt.Sprintf("No workflow found with ID: %s", workflowID),
+			HTTPStatus:  404,
+		}
+	case strings.Contains(err.Error(), "state conflict"):
+		return &domain.LoanError{
+			Code:        domain.LOAN_062,
+			Message:     "Workflow state conflict",
+			Description: err.Error(),
+			HTTPStatus:  409,
+		}
+	}
+
+	return &domain.LoanError{
+		Code:        domain.LOAN_012,
+		Message:     message,
+		Description: err.Error(),
+		HTTPStatus:  500,
+	}
+}
@@ -9,9 +9,13 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/antihax/optional"
 	"github.com/conductor-sdk/conductor-go/sdk/client"
+	"github.com/conductor-sdk/conductor-go/sdk/model"
 	"github.com/conductor-sdk/conductor-go/sdk/settings"
 	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
 )
 
 // ConductorClientImpl implements the ConductorClient interface for Netflix Conductor
@@ -69,6 +73,15 @@ func (c *ConductorClientImpl) StartWorkflow(
 		"version": version,
 		"input":   input,
 	}
+	// A "priority" input is also the workflow's Conductor priority, ordering its tasks in their queues
+	if priority, ok := input["priority"].(int); ok {
+		if priority < 0 {
+			priority = 0
+		} else if priority > domain.MaxWorkflowPriority {
+			priority = domain.MaxWorkflowPriority
+		}
+		startRequest["priority"] = priority
+	}
 
 	// Marshal the request
 	jsonData, err := json.Marshal(startRequest)
@@ -140,30 +153,55 @@ func (c *ConductorClientImpl) GetWorkflowStatus(
 	)
 
 	// Get workflow execution using the SDK
-	execution, _, err := c.workflowClient.GetExecutionStatus(ctx, workflowID, nil)
+	execution, resp, err := c.workflowClient.GetExecutionStatus(ctx, workflowID, nil)
 	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			logger.Warn("Workflow not found")
+			return nil, fmt.Errorf("workflow not found: %s", workflowID)
+		}
 		logger.Error("Failed to get workflow status", zap.Error(err))
 		return nil, fmt.Errorf("failed to get workflow status: %w", err)
 	}
 
 	// Convert SDK response to our format
 	status := &WorkflowStatus{
-		WorkflowID: execution.WorkflowId,
-		Status:     string(execution.Status),
-		Input:      execution.Input,
-		Output:     execution.Output,
-		Tasks:      make([]TaskStatus, 0, len(execution.Tasks)),
+		WorkflowID:            execution.WorkflowId,
+		WorkflowName:          execution.WorkflowName,
+		WorkflowVersion:       int(execution.WorkflowVersion),
+		CorrelationID:         execution.CorrelationId,
+		Status:                string(execution.Status),
+		ReasonForIncompletion: execution.ReasonForIncompletion,
+		Input:                 execution.Input,
+		Output:                execution.Output,
+		Tasks:                 make([]TaskStatus, 0, len(execution.Tasks)),
+	}
+
+	if execution.StartTime > 0 {
+		status.StartTime = time.UnixMilli(execution.StartTime)
+	}
+	if execution.EndTime > 0 {
+		endTime := time.UnixMilli(execution.EndTime)
+		status.EndTime = &endTime
 	}
 
 	// Convert tasks
 	for _, task := range execution.Tasks {
 		taskStatus := TaskStatus{
-			TaskID:            task.TaskId,
-			TaskType:          task.TaskType,
-			Status:            string(task.Status),
-			ReferenceTaskName: task.ReferenceTaskName,
-			Input:             task.InputData,
-			Output:            task.OutputData,
+			TaskID:                task.TaskId,
+			TaskType:              task.TaskType,
+			Status:                string(task.Status),
+			ReferenceTaskName:     task.ReferenceTaskName,
+			Seq:                   int(task.Seq),
+			RetryCount:            int(task.RetryCount),
+			ReasonForIncompletion: task.ReasonForIncompletion,
+			Input:                 task.InputData,
+			Output:                task.OutputData,
+		}
+
+		// Handle scheduled time
+		if task.ScheduledTime > 0 {
+			scheduledTime := time.UnixMilli(task.ScheduledTime)
+			taskStatus.ScheduledTime = &scheduledTime
 		}
 
 		// Handle start time
@@ -200,10 +238,12 @@ func (c *ConductorClientImpl) TerminateWorkflow(
 	)
 
 	// Terminate workflow using the SDK
-	_, err := c.workflowClient.Terminate(ctx, workflowID, nil)
+	resp, err := c.workflowClient.Terminate(ctx, workflowID, &client.WorkflowResourceApiTerminateOpts{
+		Reason: optional.NewString(reason),
+	})
 	if err != nil {
 		logger.Error("Failed to terminate workflow", zap.Error(err))
-		return fmt.Errorf("failed to terminate workflow: %w", err)
+		return workflowActionError(resp, workflowID, "terminate", err)
 	}
 
 	logger.Debug("Workflow terminated successfully")
@@ -223,10 +263,10 @@ func (c *ConductorClientImpl) PauseWorkflow(
 	)
 
 	// Pause workflow using the SDK
-	_, err := c.workflowClient.PauseWorkflow(ctx, workflowID)
+	resp, err := c.workflowClient.PauseWorkflow(ctx, workflowID)
 	if err != nil {
 		logger.Error("Failed to pause workflow", zap.Error(err))
-		return fmt.Errorf("failed to pause workflow: %w", err)
+		return workflowActionError(resp, workflowID, "pause", err)
 	}
 
 	logger.Debug("Workflow paused successfully")
@@ -244,16 +284,59 @@ func (c *ConductorClientImpl) ResumeWorkflow(
 	)
 
 	// Resume workflow using the SDK
-	_, err := c.workflowClient.ResumeWorkflow(ctx, workflowID)
+	resp, err := c.workflowClient.ResumeWorkflow(ctx, workflowID)
 	if err != nil {
 		logger.Error("Failed to resume workflow", zap.Error(err))
-		return fmt.Errorf("failed to resume workflow: %w", err)
+		return workflowActionError(resp, workflowID, "resume", err)
 	}
 
 	logger.Debug("Workflow resumed successfully")
 	return nil
 }
 
+// RerunWorkflow re-runs a finished workflow from one of its tasks, replacing the task's input when
+// taskInput is given
+func (c *ConductorClientImpl) RerunWorkflow(
+	ctx context.Context,
+	workflowID string,
+	taskID string,
+	taskInput map[string]interface{},
+) error {
+	logger := c.logger.With(
+		zap.String("workflow_id", workflowID),
+		zap.String("task_id", taskID),
+		zap.String("operation", "rerun_workflow"),
+	)
+
+	// Re-run workflow using the SDK
+	_, resp, err := c.workflowClient.Rerun(ctx, model.RerunWorkflowRequest{
+		ReRunFromWorkflowId: workflowID,
+		ReRunFromTaskId:     taskID,
+		TaskInput:           taskInput,
+	}, workflowID)
+	if err != nil {
+		logger.Error("Failed to re-run workflow", zap.Error(err))
+		return workflowActionError(resp, workflowID, "re-run", err)
+	}
+
+	logger.Debug("Workflow re-run successfully")
+	return nil
+}
+
+// workflowActionError distinguishes unknown workflows and workflows in the wrong state for the
+// action from other Conductor failures
+func workflowActionError(resp *http.Response, workflowID, action string, err error) error {
+	if resp != nil {
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			return fmt.Errorf("workflow not found: %s", workflowID)
+		case http.StatusConflict:
+			return fmt.Errorf("workflow state conflict: cannot %s workflow %s: %w", action, workflowID, err)
+		}
+	}
+	return fmt.Errorf("failed to %s workflow: %w", action, err)
+}
+
 // UpdateTask updates a task with status and output
 func (c *ConductorClientImpl) UpdateTask(
 	ctx context.Context,
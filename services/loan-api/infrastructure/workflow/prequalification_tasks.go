@@ -2,19 +2,23 @@ package workflow
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math"
 	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/huuhoait/los-demo/services/shared/pkg/dti"
 	"github.com/huuhoait/los-demo/services/shared/pkg/i18n"
+	"github.com/huuhoait/los-demo/services/shared/pkg/income"
 )
 
 // PreQualificationTaskHandler handles pre-qualification workflow tasks
 type PreQualificationTaskHandler struct {
 	logger    *zap.Logger
 	localizer *i18n.Localizer
+	dti       *dti.Calculator // nil applies the default debt treatments
 }
 
 // Execute implements the TaskHandler interface
@@ -63,6 +67,8 @@ func (h *PreQualificationTaskHandler) ValidatePreQualifyInput(
 	annualIncome, _ := input["annualIncome"].(float64)
 	monthlyDebt, _ := input["monthlyDebt"].(float64)
 	employmentStatus, _ := input["employmentStatus"].(string)
+	coBorrowerAnnualIncome, _ := input["coBorrowerAnnualIncome"].(float64)
+	coBorrowerMonthlyDebt, _ := input["coBorrowerMonthlyDebt"].(float64)
 
 	// Validate required fields
 	errors := make(map[string]string)
@@ -79,9 +85,10 @@ func (h *PreQualificationTaskHandler) ValidatePreQualifyInput(
 		errors["loanAmount"] = "Loan amount cannot exceed $50,000"
 	}
 
+	// The income minimum applies to the household when there is a co-borrower
 	if annualIncome <= 0 {
 		errors["annualIncome"] = "Annual income must be greater than 0"
-	} else if annualIncome < 25000 {
+	} else if annualIncome+coBorrowerAnnualIncome < 25000 {
 		errors["annualIncome"] = "Annual income must be at least $25,000"
 	}
 
@@ -89,6 +96,14 @@ func (h *PreQualificationTaskHandler) ValidatePreQualifyInput(
 		errors["monthlyDebt"] = "Monthly debt cannot be negative"
 	}
 
+	if coBorrowerAnnualIncome < 0 {
+		errors["coBorrowerAnnualIncome"] = "Co-borrower annual income cannot be negative"
+	}
+
+	if coBorrowerMonthlyDebt < 0 {
+		errors["coBorrowerMonthlyDebt"] = "Co-borrower monthly debt cannot be negative"
+	}
+
 	if employmentStatus == "" {
 		errors["employmentStatus"] = "Employment status is required"
 	}
@@ -119,34 +134,85 @@ func (h *PreQualificationTaskHandler) CalculateDTIRatio(
 	// Extract input parameters
 	annualIncome, _ := input["annualIncome"].(float64)
 	monthlyDebt, _ := input["monthlyDebt"].(float64)
-
-	// Calculate monthly income
-	monthlyIncome := annualIncome / 12
-
-	// Calculate DTI ratio
-	var dtiRatio float64
-	if monthlyIncome > 0 {
-		dtiRatio = monthlyDebt / monthlyIncome
-	} else {
-		dtiRatio = 0
-	}
+	coBorrowerAnnualIncome, _ := input["coBorrowerAnnualIncome"].(float64)
+	coBorrowerMonthlyDebt, _ := input["coBorrowerMonthlyDebt"].(float64)
+
+	// A co-borrower's income and debts are combined with the applicant's, and both are qualified
+	// under the DTI debt treatments as in pre-qualification
+	combinedAnnualIncome := annualIncome + coBorrowerAnnualIncome
+	qualifying := h.dti.Calculate(
+		dti.Borrower{
+			MonthlyIncome: annualIncome / 12,
+			MonthlyDebt:   monthlyDebt,
+			Obligations:   obligationsInput(input, "obligations"),
+			IncomeSources: incomeSourcesInput(input, "incomeSources"),
+		},
+		dti.Borrower{
+			MonthlyIncome: coBorrowerAnnualIncome / 12,
+			MonthlyDebt:   coBorrowerMonthlyDebt,
+			Obligations:   obligationsInput(input, "coBorrowerObligations"),
+			IncomeSources: incomeSourcesInput(input, "coBorrowerIncomeSources"),
+		},
+	)
+	monthlyIncome := qualifying.MonthlyIncome
+	monthlyDebt = qualifying.MonthlyDebt
 
 	// Round to 4 decimal places
-	dtiRatio = math.Round(dtiRatio*10000) / 10000
+	dtiRatio := math.Round(qualifying.Ratio*10000) / 10000
 
 	logger.Info("DTI ratio calculated",
 		zap.Float64("annual_income", annualIncome),
+		zap.Float64("co_borrower_annual_income", coBorrowerAnnualIncome),
 		zap.Float64("monthly_income", monthlyIncome),
 		zap.Float64("monthly_debt", monthlyDebt),
 		zap.Float64("dti_ratio", dtiRatio),
 	)
 
 	return map[string]interface{}{
-		"dtiRatio":      dtiRatio,
-		"monthlyIncome": monthlyIncome,
+		"dtiRatio":             dtiRatio,
+		"monthlyIncome":        monthlyIncome,
+		"monthlyDebt":          monthlyDebt,
+		"combinedAnnualIncome": combinedAnnualIncome,
+		"dtiAdjustments":       qualifying.Adjustments,
+		"incomeComponents":     qualifying.Income,
 	}, nil
 }
 
+// obligationsInput decodes obligations passed in a task's input, nil when there are none
+func obligationsInput(input map[string]interface{}, key string) *dti.Obligations {
+	value, ok := input[key]
+	if !ok || value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var obligations dti.Obligations
+	if err := json.Unmarshal(data, &obligations); err != nil {
+		return nil
+	}
+	return &obligations
+}
+
+// incomeSourcesInput decodes documented income sources passed in a task's input, nil when there
+// are none
+func incomeSourcesInput(input map[string]interface{}, key string) *income.Sources {
+	value, ok := input[key]
+	if !ok || value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var sources income.Sources
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return nil
+	}
+	return &sources
+}
+
 // AssessPreQualifyRisk performs initial risk assessment for pre-qualification
 func (h *PreQualificationTaskHandler) AssessPreQualifyRisk(
 	ctx context.Context,
@@ -570,7 +570,6 @@ func (w *TaskWorker) registerTaskHandlers() {
 	w.taskHandlers["decision_engine_ref"] = loanProcessingHandler
 	w.taskHandlers["auto_approve_ref"] = loanProcessingHandler
 	w.taskHandlers["auto_deny_ref"] = loanProcessingHandler
-	w.taskHandlers["flag_manual_review_ref"] = loanProcessingHandler
 	w.taskHandlers["manual_review_ref"] = loanProcessingHandler
 	w.taskHandlers["process_manual_decision_ref"] = loanProcessingHandler
 	w.taskHandlers["manual_approve_ref"] = loanProcessingHandler
@@ -627,12 +626,13 @@ func (w *TaskWorker) registerTaskHandlersWithRepository(loanRepository tasks.Loa
 	w.taskHandlers["decision_engine_ref"] = loanProcessingHandler
 	w.taskHandlers["auto_approve_ref"] = loanProcessingHandler
 	w.taskHandlers["auto_deny_ref"] = loanProcessingHandler
-	w.taskHandlers["flag_manual_review_ref"] = loanProcessingHandler
 	w.taskHandlers["manual_review_ref"] = loanProcessingHandler
 	w.taskHandlers["process_manual_decision_ref"] = loanProcessingHandler
 	w.taskHandlers["manual_approve_ref"] = loanProcessingHandler
 	w.taskHandlers["manual_deny_ref"] = loanProcessingHandler
 	w.taskHandlers["default_deny_ref"] = loanProcessingHandler
+	// Offer negotiation tasks
+	w.taskHandlers["record_counter_offer_ref"] = loanProcessingHandler
 
 	w.logger.Debug("Task handlers registered with repository", zap.Int("handler_count", len(w.taskHandlers)))
 }
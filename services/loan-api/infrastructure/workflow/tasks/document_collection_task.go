@@ -47,6 +47,7 @@ func (h *DocumentCollectionTaskHandler) Execute(
 	applicationID, _ := input["applicationId"].(string)
 	userID, _ := input["userId"].(string)
 	requiredDocuments, _ := input["requiredDocuments"].([]interface{})
+	coBorrowerRequiredDocuments, _ := input["coBorrowerRequiredDocuments"].([]interface{})
 
 	// Validate required fields
 	if applicationID == "" {
@@ -62,29 +63,23 @@ func (h *DocumentCollectionTaskHandler) Execute(
 	logger.Info("Processing document collection",
 		zap.String("application_id", applicationID),
 		zap.String("user_id", userID),
-		zap.Int("required_documents_count", len(requiredDocuments)))
+		zap.Int("required_documents_count", len(requiredDocuments)),
+		zap.Int("co_borrower_required_documents_count", len(coBorrowerRequiredDocuments)))
 
-	// Convert required documents to string slice
-	requiredDocs := make([]string, len(requiredDocuments))
-	for i, doc := range requiredDocuments {
-		if docStr, ok := doc.(string); ok {
-			requiredDocs[i] = docStr
-		}
-	}
+	// Convert required documents to string slices; each borrower has their own checklist
+	requiredDocs := toDocumentTypes(requiredDocuments)
+	coBorrowerDocs := toDocumentTypes(coBorrowerRequiredDocuments)
 
 	// Process document collection
 	documentResults := h.processDocumentCollection(ctx, applicationID, userID, requiredDocs)
+	coBorrowerResults := h.processDocumentCollection(ctx, applicationID, userID, coBorrowerDocs)
 
 	// Determine overall collection status
-	allDocumentsCollected := true
+	allDocumentsCollected := h.countCollectedDocuments(documentResults) == len(documentResults) &&
+		h.countCollectedDocuments(coBorrowerResults) == len(coBorrowerResults)
 	collectionCompletedAt := time.Now()
-
-	for _, result := range documentResults {
-		if !result.Collected {
-			allDocumentsCollected = false
-			collectionCompletedAt = time.Time{} // Reset if not all collected
-			break
-		}
+	if !allDocumentsCollected {
+		collectionCompletedAt = time.Time{}
 	}
 
 	// Prepare output
@@ -104,6 +99,15 @@ func (h *DocumentCollectionTaskHandler) Execute(
 			"validationErrors": h.getValidationErrors(documentResults),
 		},
 	}
+	if len(coBorrowerDocs) > 0 {
+		output["coBorrowerDocuments"] = map[string]interface{}{
+			"documentDetails":  coBorrowerResults,
+			"totalRequired":    len(coBorrowerDocs),
+			"collected":        h.countCollectedDocuments(coBorrowerResults),
+			"pending":          h.countPendingDocuments(coBorrowerResults),
+			"validationErrors": h.getValidationErrors(coBorrowerResults),
+		}
+	}
 
 	logger.Info("Document collection completed",
 		zap.Bool("all_documents_collected", allDocumentsCollected),
@@ -128,6 +132,7 @@ func (h *DocumentCollectionTaskHandler) ExecuteHumanTask(
 	applicationID, _ := input["applicationId"].(string)
 	userID, _ := input["userId"].(string)
 	requiredDocuments, _ := input["requiredDocuments"].([]interface{})
+	coBorrowerRequiredDocuments, _ := input["coBorrowerRequiredDocuments"].([]interface{})
 
 	// Validate required fields
 	if applicationID == "" {
@@ -151,16 +156,17 @@ func (h *DocumentCollectionTaskHandler) ExecuteHumanTask(
 
 	// Create initial status indicating human intervention is needed
 	output := map[string]interface{}{
-		"taskType":                  "document_collection",
-		"taskStatus":                "PENDING_HUMAN_ACTION",
-		"humanInterventionRequired": true,
-		"applicationId":             applicationID,
-		"userId":                    userID,
-		"requiredDocuments":         requiredDocuments,
-		"message":                   "Document collection requires manual processing by loan officer",
+		"taskType":                    "document_collection",
+		"taskStatus":                  "PENDING_HUMAN_ACTION",
+		"humanInterventionRequired":   true,
+		"applicationId":               applicationID,
+		"userId":                      userID,
+		"requiredDocuments":           requiredDocuments,
+		"coBorrowerRequiredDocuments": coBorrowerRequiredDocuments,
+		"message":                     "Document collection requires manual processing by loan officer",
 		"nextSteps": []string{
-			"1. Review required documents list",
-			"2. Contact applicant for document submission",
+			"1. Review required documents list for each borrower",
+			"2. Contact applicant and any co-borrower for document submission",
 			"3. Verify document authenticity",
 			"4. Update task status when complete",
 		},
@@ -176,6 +182,17 @@ func (h *DocumentCollectionTaskHandler) ExecuteHumanTask(
 	return output, nil
 }
 
+// toDocumentTypes converts a document list from task input to document types
+func toDocumentTypes(documents []interface{}) []string {
+	docTypes := make([]string, 0, len(documents))
+	for _, doc := range documents {
+		if docStr, ok := doc.(string); ok {
+			docTypes = append(docTypes, docStr)
+		}
+	}
+	return docTypes
+}
+
 // processDocumentCollection processes the collection of required documents
 func (h *DocumentCollectionTaskHandler) processDocumentCollection(
 	ctx context.Context,
@@ -435,8 +452,8 @@ func (h *DocumentCollectionTaskHandler) GetHumanTaskInstructions() map[string]in
 	return map[string]interface{}{
 		"taskType": "document_collection",
 		"instructions": []string{
-			"Review the loan application and identify required documents",
-			"Contact the applicant to request missing documents",
+			"Review the loan application and identify required documents for each borrower",
+			"Contact the applicant and any co-borrower to request missing documents",
 			"Verify document authenticity and completeness",
 			"Upload documents to the system",
 			"Mark the task as complete when all documents are collected",
@@ -447,6 +464,12 @@ func (h *DocumentCollectionTaskHandler) GetHumanTaskInstructions() map[string]in
 			"bank_statements",
 			"identification",
 		},
+		"coBorrowerRequiredDocuments": []string{
+			"income_verification",
+			"employment_verification",
+			"bank_statements",
+			"identification",
+		},
 		"estimatedTime": "2-4 hours",
 		"priority":      "High",
 		"assignedTo":    "Loan Officer",
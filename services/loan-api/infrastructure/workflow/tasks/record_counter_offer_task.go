@@ -0,0 +1,145 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// defaultCounterOfferValidity is how long a counter offer can be accepted when the generator
+// does not set an expiration date
+const defaultCounterOfferValidity = 7 * 24 * time.Hour
+
+// RecordCounterOfferTaskHandler stores generated counter offers and adds them to the negotiation thread
+type RecordCounterOfferTaskHandler struct {
+	logger         *zap.Logger
+	loanRepository LoanRepository
+}
+
+// NewRecordCounterOfferTaskHandlerWithRepository creates a new record counter offer task handler with repository
+func NewRecordCounterOfferTaskHandlerWithRepository(logger *zap.Logger, loanRepository LoanRepository) *RecordCounterOfferTaskHandler {
+	return &RecordCounterOfferTaskHandler{
+		logger:         logger,
+		loanRepository: loanRepository,
+	}
+}
+
+// Execute stores the counter offer generated for a borrower's request for different terms
+func (h *RecordCounterOfferTaskHandler) Execute(
+	ctx context.Context,
+	input map[string]interface{},
+) (map[string]interface{}, error) {
+	logger := h.logger.With(zap.String("operation", "record_counter_offer"))
+
+	// Extract input parameters
+	applicationID, _ := input["applicationId"].(string)
+	offerID, _ := input["offerId"].(string)
+	negotiationID, _ := input["negotiationId"].(string)
+	counterOffer, _ := input["counterOffer"].(map[string]interface{})
+
+	// Validate required fields
+	if applicationID == "" || offerID == "" || negotiationID == "" {
+		return nil, fmt.Errorf("application ID, offer ID and negotiation ID are required")
+	}
+	if counterOffer == nil {
+		return nil, fmt.Errorf("counter offer is required")
+	}
+
+	// The counter offer ID derives from the request it answers, so a retried task finds the
+	// offer it already stored instead of storing a second one
+	counterOfferID := domain.CounterOfferID(negotiationID)
+	if existing, err := h.loanRepository.GetOfferByID(ctx, counterOfferID); err == nil {
+		logger.Info("Counter offer already recorded, treating as successful idempotent operation",
+			zap.String("counter_offer_id", counterOfferID))
+		return map[string]interface{}{
+			"success":        true,
+			"counterOfferId": existing.ID,
+			"expiresAt":      existing.ExpiresAt.Format(time.RFC3339),
+			"idempotent":     true,
+		}, nil
+	}
+
+	parent, err := h.loanRepository.GetOfferByID(ctx, offerID)
+	if err != nil {
+		logger.Error("Failed to get offer by ID", zap.Error(err))
+		return nil, fmt.Errorf("failed to get offer: %w", err)
+	}
+
+	amount, _ := counterOffer["offeredAmount"].(float64)
+	rate, _ := counterOffer["offeredRate"].(float64)
+	if amount <= 0 || rate <= 0 {
+		return nil, fmt.Errorf("counter offer amount and rate are required")
+	}
+	termMonths := parent.TermMonths
+	if term, ok := counterOffer["offeredTerm"].(float64); ok && term > 0 {
+		termMonths = int(term)
+	}
+	apr, _ := counterOffer["offeredAPR"].(float64)
+	if apr == 0 {
+		apr = rate
+	}
+	monthlyPayment, _ := counterOffer["monthlyPayment"].(float64)
+	totalInterest, _ := counterOffer["totalInterest"].(float64)
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(defaultCounterOfferValidity)
+	if expiration, _ := counterOffer["expirationDate"].(string); expiration != "" {
+		if parsed, err := time.Parse(time.RFC3339, expiration); err == nil {
+			expiresAt = parsed.UTC()
+		}
+	}
+
+	offer := &domain.LoanOffer{
+		ID:             counterOfferID,
+		ApplicationID:  applicationID,
+		OfferAmount:    amount,
+		InterestRate:   rate,
+		TermMonths:     termMonths,
+		MonthlyPayment: monthlyPayment,
+		TotalInterest:  totalInterest,
+		APR:            apr,
+		ExpiresAt:      expiresAt,
+		Status:         domain.OfferStatusPending,
+		ParentOfferID:  &parent.ID,
+		CreatedAt:      now,
+	}
+
+	if err := h.loanRepository.CreateOffer(ctx, offer); err != nil {
+		logger.Error("Failed to create counter offer", zap.Error(err))
+		return nil, fmt.Errorf("failed to create counter offer: %w", err)
+	}
+
+	reason, _ := counterOffer["offerReason"].(string)
+	negotiation := &domain.OfferNegotiation{
+		ID:             uuid.New().String(),
+		ApplicationID:  applicationID,
+		OfferID:        parent.ID,
+		Action:         domain.NegotiationCounterOffered,
+		Actor:          domain.NegotiationActorLender,
+		Reason:         reason,
+		CounterOfferID: &offer.ID,
+		CreatedAt:      now,
+	}
+	if err := h.loanRepository.CreateNegotiation(ctx, negotiation); err != nil {
+		logger.Warn("Failed to add counter offer to negotiation thread", zap.Error(err))
+		// The counter offer is stored and can still be accepted
+	}
+
+	logger.Info("Counter offer recorded",
+		zap.String("application_id", applicationID),
+		zap.String("offer_id", parent.ID),
+		zap.String("counter_offer_id", offer.ID),
+		zap.Float64("offered_amount", amount),
+		zap.Float64("offered_rate", rate))
+
+	return map[string]interface{}{
+		"success":        true,
+		"counterOfferId": offer.ID,
+		"expiresAt":      expiresAt.Format(time.RFC3339),
+	}, nil
+}
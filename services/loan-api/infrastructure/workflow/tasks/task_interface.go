@@ -14,6 +14,11 @@ type LoanRepository interface {
 	GetApplicationByID(ctx context.Context, id string) (*domain.LoanApplication, error)
 	UpdateApplication(ctx context.Context, app *domain.LoanApplication) error
 	CreateStateTransition(ctx context.Context, transition *domain.StateTransition) error
+	GetOfferByID(ctx context.Context, id string) (*domain.LoanOffer, error)
+	CreateOffer(ctx context.Context, offer *domain.LoanOffer) error
+	CreateNegotiation(ctx context.Context, negotiation *domain.OfferNegotiation) error
+	GetCreditConsent(ctx context.Context, applicationID string) (*domain.CreditConsent, error)
+	ListDuplicateMatches(ctx context.Context, applicationID string) ([]*domain.DuplicateMatch, error)
 }
 
 // TaskHandler defines the interface for all task handlers
@@ -73,6 +78,7 @@ func (f *TaskFactory) registerHandlers() {
 	// Register update_application_state handler with repository if available
 	if f.loanRepository != nil {
 		f.handlers["update_application_state"] = NewUpdateApplicationStateTaskHandlerWithRepository(f.logger, f.loanRepository)
+		f.handlers["record_counter_offer"] = NewRecordCounterOfferTaskHandlerWithRepository(f.logger, f.loanRepository)
 	} else {
 		f.handlers["update_application_state"] = NewUpdateApplicationStateTaskHandler(f.logger)
 	}
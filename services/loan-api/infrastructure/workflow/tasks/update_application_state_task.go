@@ -82,29 +82,40 @@ func (h *UpdateApplicationStateTaskHandler) Execute(
 	// Validate state transition
 	targetState := domain.ApplicationState(toState)
 
+	// An automated approval of a suspected duplicate application is held for manual review until its
+	// matches are linked or dismissed; a retried approval is held the same way
+	if targetState == domain.StateApproved &&
+		(application.CurrentState == domain.StateUnderwriting || fromState == string(domain.StateUnderwriting)) &&
+		h.hasPendingDuplicateMatch(ctx, logger, applicationID) {
+		logger.Warn("Holding suspected duplicate application for manual review")
+		targetState = domain.StateManualReview
+		reason = "Suspected duplicate application held for manual review"
+	}
+
 	// Check if already in target state (idempotent operation)
 	if application.CurrentState == targetState {
 		logger.Info("Application already in target state, treating as successful idempotent operation",
 			zap.String("current_state", string(application.CurrentState)),
-			zap.String("target_state", toState))
+			zap.String("target_state", string(targetState)))
 
 		// Return success response for idempotent operation
 		return map[string]interface{}{
-			"success":       true,
-			"newState":      string(targetState),
-			"previousState": string(application.CurrentState),
-			"newStatus":     string(application.Status),
-			"updatedAt":     time.Now().UTC().Format(time.RFC3339),
-			"idempotent":    true,
-			"message":       "Application already in target state",
+			"success":         true,
+			"newState":        string(targetState),
+			"previousState":   string(application.CurrentState),
+			"newStatus":       string(application.Status),
+			"updatedAt":       time.Now().UTC().Format(time.RFC3339),
+			"idempotent":      true,
+			"message":         "Application already in target state",
+			"creditConsentId": h.creditConsentID(ctx, logger, applicationID, targetState),
 		}, nil
 	}
 
 	if !application.CanTransitionTo(targetState) {
 		logger.Error("Invalid state transition",
 			zap.String("current_state", string(application.CurrentState)),
-			zap.String("target_state", toState))
-		return nil, fmt.Errorf("invalid state transition from %s to %s", application.CurrentState, toState)
+			zap.String("target_state", string(targetState)))
+		return nil, fmt.Errorf("invalid state transition from %s to %s", application.CurrentState, targetState)
 	}
 
 	// Store previous state for transition record
@@ -176,11 +187,12 @@ func (h *UpdateApplicationStateTaskHandler) Execute(
 		zap.Time("updated_at", updatedAt))
 
 	return map[string]interface{}{
-		"success":       true,
-		"updatedAt":     updatedAt,
-		"previousState": string(previousState),
-		"newState":      string(targetState),
-		"newStatus":     string(application.Status),
+		"success":         true,
+		"updatedAt":       updatedAt,
+		"previousState":   string(previousState),
+		"newState":        string(targetState),
+		"newStatus":       string(application.Status),
+		"creditConsentId": h.creditConsentID(ctx, logger, applicationID, targetState),
 		"transition": map[string]interface{}{
 			"id":        transition.ID,
 			"fromState": string(previousState),
@@ -193,6 +205,31 @@ func (h *UpdateApplicationStateTaskHandler) Execute(
 	}, nil
 }
 
+// creditConsentID returns the borrower's consent to the credit pull when the application moves to
+// underwriting, so the underwriting workflow can pass it on to the credit_check task
+func (h *UpdateApplicationStateTaskHandler) creditConsentID(ctx context.Context, logger *zap.Logger, applicationID string, targetState domain.ApplicationState) string {
+	if targetState != domain.StateUnderwriting {
+		return ""
+	}
+
+	consent, err := h.loanRepository.GetCreditConsent(ctx, applicationID)
+	if err != nil {
+		logger.Warn("No credit consent to pass to underwriting", zap.Error(err))
+		return ""
+	}
+	return consent.ID
+}
+
+// hasPendingDuplicateMatch reports whether the application has suspected duplicates awaiting review
+func (h *UpdateApplicationStateTaskHandler) hasPendingDuplicateMatch(ctx context.Context, logger *zap.Logger, applicationID string) bool {
+	matches, err := h.loanRepository.ListDuplicateMatches(ctx, applicationID)
+	if err != nil {
+		logger.Warn("Failed to check duplicate matches", zap.Error(err))
+		return false
+	}
+	return domain.HasPendingDuplicateMatch(matches)
+}
+
 // simulateStateUpdate simulates state update when no repository is available
 func (h *UpdateApplicationStateTaskHandler) simulateStateUpdate(
 	applicationID, fromState, toState, reason string, automated bool,
@@ -38,7 +38,11 @@ func (h *ValidateApplicationTaskHandler) Execute(
 	loanPurpose, _ := input["loanPurpose"].(string)
 	annualIncome, _ := input["annualIncome"].(float64)
 	monthlyIncome, _ := input["monthlyIncome"].(float64)
+	monthlyDebt, _ := input["monthlyDebt"].(float64)
 	requestedTerm, _ := input["requestedTerm"].(float64)
+	hasCoBorrower, _ := input["hasCoBorrower"].(bool)
+	coBorrowerMonthlyIncome, _ := input["coBorrowerMonthlyIncome"].(float64)
+	coBorrowerMonthlyDebt, _ := input["coBorrowerMonthlyDebt"].(float64)
 
 	logger.Info("Extracted input parameters",
 		zap.String("application_id", applicationID),
@@ -47,7 +51,8 @@ func (h *ValidateApplicationTaskHandler) Execute(
 		zap.String("loan_purpose", loanPurpose),
 		zap.Float64("annual_income", annualIncome),
 		zap.Float64("monthly_income", monthlyIncome),
-		zap.Float64("requested_term", requestedTerm))
+		zap.Float64("requested_term", requestedTerm),
+		zap.Bool("has_co_borrower", hasCoBorrower))
 
 	// Validate required fields
 	errors := make(map[string]string)
@@ -106,12 +111,25 @@ func (h *ValidateApplicationTaskHandler) Execute(
 		errors["requestedTerm"] = "Requested term cannot exceed 84 months"
 	}
 
-	// Calculate debt-to-income ratio
+	if monthlyDebt < 0 {
+		errors["monthlyDebt"] = "Monthly debt cannot be negative"
+	}
+
+	if hasCoBorrower {
+		if coBorrowerMonthlyIncome < 0 {
+			errors["coBorrowerMonthlyIncome"] = "Co-borrower monthly income cannot be negative"
+		}
+		if coBorrowerMonthlyDebt < 0 {
+			errors["coBorrowerMonthlyDebt"] = "Co-borrower monthly debt cannot be negative"
+		}
+	}
+
+	// Calculate debt-to-income ratio on the combined income and debts of both borrowers
+	combinedMonthlyIncome := monthlyIncome + coBorrowerMonthlyIncome
+	combinedMonthlyDebt := monthlyDebt + coBorrowerMonthlyDebt
 	var dtiRatio float64
-	if monthlyIncome > 0 {
-		// Assume monthly debt payments are 10% of monthly income for demo
-		monthlyDebt := monthlyIncome * 0.1
-		dtiRatio = (monthlyDebt / monthlyIncome) * 100
+	if combinedMonthlyIncome > 0 {
+		dtiRatio = (combinedMonthlyDebt / combinedMonthlyIncome) * 100
 	}
 
 	// Business rule: DTI ratio should be less than 43%
@@ -127,10 +145,17 @@ func (h *ValidateApplicationTaskHandler) Execute(
 		"loanPurpose":   loanPurpose,
 		"annualIncome":  annualIncome,
 		"monthlyIncome": monthlyIncome,
+		"monthlyDebt":   monthlyDebt,
 		"requestedTerm": int(requestedTerm),
 		"dtiRatio":      dtiRatio,
 		"validatedAt":   time.Now(),
 	}
+	if hasCoBorrower {
+		normalizedData["coBorrowerMonthlyIncome"] = coBorrowerMonthlyIncome
+		normalizedData["coBorrowerMonthlyDebt"] = coBorrowerMonthlyDebt
+		normalizedData["combinedMonthlyIncome"] = combinedMonthlyIncome
+		normalizedData["combinedMonthlyDebt"] = combinedMonthlyDebt
+	}
 
 	// Determine overall validation result
 	isValid := len(errors) == 0
@@ -0,0 +1,250 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// AssignmentHandler handles loan officer assignments and work queues
+type AssignmentHandler struct {
+	assignmentService *application.AssignmentService
+	logger            *zap.Logger
+}
+
+// NewAssignmentHandler creates a new assignment handler
+func NewAssignmentHandler(assignmentService *application.AssignmentService, logger *zap.Logger) *AssignmentHandler {
+	return &AssignmentHandler{
+		assignmentService: assignmentService,
+		logger:            logger,
+	}
+}
+
+// ClaimApplication assigns an unassigned application in manual review to the caller (admin endpoint)
+// @Summary Claim an application for review
+// @Description Assign an unassigned application in manual review to the calling loan officer. Claiming an application already assigned to the caller returns the existing assignment.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ApplicationAssignment} "Application claimed"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 409 {object} middleware.ErrorResponse "Not in manual review, assigned to another officer or officer at capacity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/assignment/claim [post]
+func (h *AssignmentHandler) ClaimApplication(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "claim_application"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	assignment, err := h.assignmentService.Claim(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, assignment, "APPLICATION_CLAIMED", nil)
+}
+
+// ReassignApplication moves an application in manual review to another loan officer (manager endpoint)
+// @Summary Reassign an application
+// @Description Move an application in manual review to another configured loan officer licensed in the borrower's state and not on time off. Managers may assign past an officer's capacity; the previous assignment is kept in the history.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param request body domain.ReassignApplicationRequest true "Officer and reason"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ApplicationAssignment} "Application reassigned"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request or unknown officer"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 409 {object} middleware.ErrorResponse "Application not in manual review, or officer not licensed or on time off"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/assignment/reassign [post]
+func (h *AssignmentHandler) ReassignApplication(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "reassign_application"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	var req domain.ReassignApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	assignment, err := h.assignmentService.Reassign(c.Request.Context(), c.Param("id"), &req, c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, assignment, "APPLICATION_REASSIGNED", nil)
+}
+
+// OverrideAssignment assigns an application to the officer a team lead chooses (admin endpoint)
+// @Summary Override an application's assignment
+// @Description Assign an application in manual review to the chosen loan officer regardless of their capacity, skills or time off. The officer must be licensed in the borrower's state; the override is kept in the history.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param request body domain.ReassignApplicationRequest true "Officer and reason"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ApplicationAssignment} "Application reassigned"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request or unknown officer"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 409 {object} middleware.ErrorResponse "Application not in manual review or officer not licensed in borrower's state"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/assignment/override [post]
+func (h *AssignmentHandler) OverrideAssignment(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "override_assignment"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	var req domain.ReassignApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	assignment, err := h.assignmentService.Override(c.Request.Context(), c.Param("id"), &req, c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, assignment, "APPLICATION_REASSIGNED", nil)
+}
+
+// ListWorkload returns the workload of every loan officer (admin endpoint)
+// @Summary List loan officer workload
+// @Description List each loan officer's open reviews against their capacity, whether they are taking new applications, when their time off ends and when they were last assigned an application
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=[]domain.OfficerWorkload} "Workload retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/officers/workload [get]
+func (h *AssignmentHandler) ListWorkload(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "list_officer_workload"))
+
+	workloads, err := h.assignmentService.Workload(c.Request.Context())
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, workloads, "", nil)
+}
+
+// ListAssignments returns an application's assignment history (admin endpoint)
+// @Summary List application assignments
+// @Description List the loan officers an application has been assigned to, oldest first. The active assignment has no released_at.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=[]domain.ApplicationAssignment} "Assignment history retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/assignments [get]
+func (h *AssignmentHandler) ListAssignments(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "list_assignments"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	assignments, err := h.assignmentService.ListHistory(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, assignments, "", nil)
+}
+
+// ListMyQueue returns the applications assigned to the calling loan officer (admin endpoint)
+// @Summary List my work queue
+// @Description List the applications actively assigned to the calling loan officer, highest priority and longest waiting first
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=[]domain.QueueItem} "Work queue retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/queue/mine [get]
+func (h *AssignmentHandler) ListMyQueue(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "list_my_queue"))
+
+	items, err := h.assignmentService.ListQueue(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, items, "", nil)
+}
+
+// respondError maps service errors to error responses
+func (h *AssignmentHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Assignment operation failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected assignment error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the assignment and work queue routes
+func (h *AssignmentHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		// Admin endpoints (require a back-office role)
+		admin := loans.Group("", middleware.RequireStaff())
+		admin.GET("/queue/mine", h.ListMyQueue)
+		admin.POST("/applications/:id/assignment/claim", h.ClaimApplication)
+		admin.GET("/applications/:id/assignments", h.ListAssignments)
+		admin.POST("/applications/:id/assignment/reassign", middleware.RequireRoles("manager", "admin", "super_admin"), h.ReassignApplication)
+
+		// Team lead endpoints
+		leads := admin.Group("", middleware.RequireRoles("senior_reviewer", "manager", "admin", "super_admin"))
+		leads.GET("/officers/workload", h.ListWorkload)
+		leads.POST("/applications/:id/assignment/override", h.OverrideAssignment)
+	}
+}
@@ -0,0 +1,247 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// BankAccountHandler handles borrowers' bank account linking and verification requests
+type BankAccountHandler struct {
+	bankAccountService *application.BankAccountService
+	logger             *zap.Logger
+}
+
+// NewBankAccountHandler creates a new bank account handler
+func NewBankAccountHandler(bankAccountService *application.BankAccountService, logger *zap.Logger) *BankAccountHandler {
+	return &BankAccountHandler{
+		bankAccountService: bankAccountService,
+		logger:             logger,
+	}
+}
+
+// ListBankAccounts lists the borrower's bank accounts
+// @Summary List bank accounts
+// @Description List the bank accounts the borrower has linked, with their verification status. Account numbers are only shown by their last four digits.
+// @Tags Bank Accounts
+// @Accept json
+// @Produce json
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=[]domain.BankAccount} "Bank accounts retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /bank-accounts [get]
+func (h *BankAccountHandler) ListBankAccounts(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "list_bank_accounts"))
+
+	accounts, err := h.bankAccountService.ListBankAccounts(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, accounts, "", nil)
+}
+
+// AddBankAccount adds a bank account by its numbers and sends micro-deposits to verify it
+// @Summary Add a bank account
+// @Description Add a bank account by its account and routing numbers. Two micro-deposits are sent to the account; it is verified once the borrower confirms their amounts.
+// @Tags Bank Accounts
+// @Accept json
+// @Produce json
+// @Param request body domain.AddBankAccountRequest true "Bank account details"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.BankAccount} "Micro-deposits sent"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request or routing number"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Failure 502 {object} middleware.ErrorResponse "Micro-deposits could not be sent"
+// @Failure 503 {object} middleware.ErrorResponse "Micro-deposit verification unavailable"
+// @Security BearerAuth
+// @Router /bank-accounts [post]
+func (h *BankAccountHandler) AddBankAccount(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "add_bank_account"))
+
+	var req domain.AddBankAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	account, err := h.bankAccountService.AddBankAccount(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	messageKey := "MICRO_DEPOSITS_SENT"
+	if account.Status == domain.BankAccountVerified {
+		messageKey = "BANK_ACCOUNT_VERIFIED"
+	}
+	middleware.CreateSuccessResponse(c, account, messageKey, nil)
+}
+
+// CreatePlaidLinkToken creates a Plaid Link token
+// @Summary Create a Plaid Link token
+// @Description Create the token the borrower's client opens Plaid Link with to select the bank account to link
+// @Tags Bank Accounts
+// @Accept json
+// @Produce json
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.PlaidLinkToken} "Link token created"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 502 {object} middleware.ErrorResponse "Plaid request failed"
+// @Failure 503 {object} middleware.ErrorResponse "Plaid linking unavailable"
+// @Security BearerAuth
+// @Router /bank-accounts/plaid/link-token [post]
+func (h *BankAccountHandler) CreatePlaidLinkToken(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "create_plaid_link_token"))
+
+	token, err := h.bankAccountService.CreatePlaidLinkToken(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, token, "", nil)
+}
+
+// LinkPlaidAccount links the bank account selected in Plaid Link
+// @Summary Link a bank account through Plaid
+// @Description Link the account the borrower selected in Plaid Link. Its numbers are retrieved with Plaid Auth and the account is verified immediately.
+// @Tags Bank Accounts
+// @Accept json
+// @Produce json
+// @Param request body domain.LinkPlaidAccountRequest true "Plaid Link result"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.BankAccount} "Bank account linked"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Failure 502 {object} middleware.ErrorResponse "Plaid request failed"
+// @Failure 503 {object} middleware.ErrorResponse "Plaid linking unavailable"
+// @Security BearerAuth
+// @Router /bank-accounts/plaid [post]
+func (h *BankAccountHandler) LinkPlaidAccount(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "link_plaid_account"))
+
+	var req domain.LinkPlaidAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	account, err := h.bankAccountService.LinkPlaidAccount(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, account, "BANK_ACCOUNT_LINKED", nil)
+}
+
+// VerifyMicroDeposits confirms the micro-deposit amounts sent to a bank account
+// @Summary Verify micro-deposits
+// @Description Confirm the amounts of the two micro-deposits sent to the bank account. The account fails verification after three incorrect attempts and must be added again.
+// @Tags Bank Accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "Bank account ID"
+// @Param request body domain.VerifyMicroDepositsRequest true "Micro-deposit amounts"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.BankAccount} "Bank account verified"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Bank account belongs to another user"
+// @Failure 404 {object} middleware.ErrorResponse "Bank account not found"
+// @Failure 409 {object} middleware.ErrorResponse "Bank account is not awaiting verification"
+// @Failure 422 {object} middleware.ErrorResponse "Incorrect amounts"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /bank-accounts/{id}/verify [post]
+func (h *BankAccountHandler) VerifyMicroDeposits(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "verify_micro_deposits"),
+		zap.String("bank_account_id", c.Param("id")),
+	)
+
+	var req domain.VerifyMicroDepositsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	account, err := h.bankAccountService.VerifyMicroDeposits(c.Request.Context(), c.GetString("user_id"), c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, account, "BANK_ACCOUNT_VERIFIED", nil)
+}
+
+// RemoveBankAccount removes a bank account
+// @Summary Remove a bank account
+// @Description Remove a bank account so loan proceeds are no longer sent to it. Disbursements already scheduled to the account fail and can be scheduled again once another account is verified.
+// @Tags Bank Accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "Bank account ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse "Bank account removed"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Bank account belongs to another user"
+// @Failure 404 {object} middleware.ErrorResponse "Bank account not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /bank-accounts/{id} [delete]
+func (h *BankAccountHandler) RemoveBankAccount(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "remove_bank_account"),
+		zap.String("bank_account_id", c.Param("id")),
+	)
+
+	if err := h.bankAccountService.RemoveBankAccount(c.Request.Context(), c.GetString("user_id"), c.Param("id")); err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, gin.H{"id": c.Param("id")}, "BANK_ACCOUNT_REMOVED", nil)
+}
+
+// respondError writes the error response for a failed bank account request
+func (h *BankAccountHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Bank account request failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected bank account error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the bank account routes
+func (h *BankAccountHandler) RegisterRoutes(router *gin.RouterGroup) {
+	accounts := router.Group("/bank-accounts")
+	{
+		accounts.GET("", h.ListBankAccounts)
+		accounts.POST("", h.AddBankAccount)
+		accounts.POST("/plaid/link-token", h.CreatePlaidLinkToken)
+		accounts.POST("/plaid", h.LinkPlaidAccount)
+		accounts.POST("/:id/verify", h.VerifyMicroDeposits)
+		accounts.DELETE("/:id", h.RemoveBankAccount)
+	}
+}
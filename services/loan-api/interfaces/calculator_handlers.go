@@ -0,0 +1,162 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// CalculatorHandler handles the public loan calculators
+type CalculatorHandler struct {
+	calculatorService *application.CalculatorService
+	logger            *zap.Logger
+}
+
+// NewCalculatorHandler creates a new calculator handler
+func NewCalculatorHandler(calculatorService *application.CalculatorService, logger *zap.Logger) *CalculatorHandler {
+	return &CalculatorHandler{
+		calculatorService: calculatorService,
+		logger:            logger,
+	}
+}
+
+// CalculatePayment calculates a loan's monthly payment and total interest (public endpoint)
+// @Summary Calculate loan payment
+// @Description Calculate the fixed monthly payment, total interest and total paid of a loan. Without annual_rate the lender's base rate is used. Amounts are also returned formatted in the request's language.
+// @Tags Calculators
+// @Accept json
+// @Produce json
+// @Param loan_amount query number true "Loan amount"
+// @Param term_months query int true "Term in months (12-84)"
+// @Param annual_rate query number false "Annual interest rate, in percent"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.PaymentCalculation} "Payment calculated"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Router /calculators/payment [get]
+func (h *CalculatorHandler) CalculatePayment(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "calculate_payment"))
+
+	var req domain.PaymentCalculationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Debug("Invalid query parameters", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	result, err := h.calculatorService.CalculatePayment(c.Request.Context(), &req)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, result, "", nil)
+}
+
+// CalculateDTI calculates a debt-to-income ratio (public endpoint)
+// @Summary Calculate debt-to-income ratio
+// @Description Calculate the debt-to-income ratio of a monthly income and its debt payments against the lender's limit. Student loans, alimony and rental income are qualified under the lender's debt treatments, as in pre-qualification. With loan_amount and term_months the ratio after taking on that loan is included.
+// @Tags Calculators
+// @Accept json
+// @Produce json
+// @Param monthly_income query number true "Gross monthly income"
+// @Param monthly_debt_payments query number false "Monthly debt payments"
+// @Param loan_amount query number false "Prospective loan amount"
+// @Param term_months query int false "Prospective loan term in months (12-84)"
+// @Param annual_rate query number false "Annual interest rate, in percent"
+// @Param student_loan_balance query number false "Student loan balance"
+// @Param student_loan_payment query number false "Documented monthly student loan payment; 0 while deferred"
+// @Param student_loan_income_based query bool false "Student loan is on an income-based repayment plan"
+// @Param alimony_payment query number false "Monthly alimony and child support paid"
+// @Param gross_rental_income query number false "Monthly gross rent received"
+// @Param rental_property_payment query number false "Monthly payment on the rented property"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.DTICalculation} "DTI calculated"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Router /calculators/dti [get]
+func (h *CalculatorHandler) CalculateDTI(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "calculate_dti"))
+
+	var req domain.DTICalculationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Debug("Invalid query parameters", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	result, err := h.calculatorService.CalculateDTI(c.Request.Context(), &req)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, result, "", nil)
+}
+
+// CalculateAffordability calculates the largest affordable loan (public endpoint)
+// @Summary Calculate affordable loan amount
+// @Description Calculate the largest monthly payment and loan amount a monthly income and its debt payments support under the lender's payment and DTI limits, as pre-qualification does. Without a term or rate the 60-month sizing term and the highest rate are used.
+// @Tags Calculators
+// @Accept json
+// @Produce json
+// @Param monthly_income query number true "Gross monthly income"
+// @Param monthly_debt_payments query number false "Monthly debt payments"
+// @Param term_months query int false "Term in months (12-84)"
+// @Param annual_rate query number false "Annual interest rate, in percent"
+// @Param student_loan_balance query number false "Student loan balance"
+// @Param student_loan_payment query number false "Documented monthly student loan payment; 0 while deferred"
+// @Param student_loan_income_based query bool false "Student loan is on an income-based repayment plan"
+// @Param alimony_payment query number false "Monthly alimony and child support paid"
+// @Param gross_rental_income query number false "Monthly gross rent received"
+// @Param rental_property_payment query number false "Monthly payment on the rented property"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.AffordabilityCalculation} "Affordability calculated"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Router /calculators/affordability [get]
+func (h *CalculatorHandler) CalculateAffordability(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "calculate_affordability"))
+
+	var req domain.AffordabilityRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Debug("Invalid query parameters", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	result, err := h.calculatorService.CalculateAffordability(c.Request.Context(), &req)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, result, "", nil)
+}
+
+// respondError writes the error response for a failed calculation
+func (h *CalculatorHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Debug("Calculation rejected",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected calculator error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the calculator routes
+func (h *CalculatorHandler) RegisterRoutes(router *gin.RouterGroup) {
+	calculators := router.Group("/calculators")
+	{
+		// Public endpoints (no authentication), shared with the marketing site
+		calculators.GET("/payment", h.CalculatePayment)
+		calculators.GET("/dti", h.CalculateDTI)
+		calculators.GET("/affordability", h.CalculateAffordability)
+	}
+}
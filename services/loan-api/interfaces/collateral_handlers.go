@@ -0,0 +1,113 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// CollateralHandler handles the collateral securing applications for secured products
+type CollateralHandler struct {
+	collateralService *application.CollateralService
+	logger            *zap.Logger
+}
+
+// NewCollateralHandler creates a new collateral handler
+func NewCollateralHandler(collateralService *application.CollateralService, logger *zap.Logger) *CollateralHandler {
+	return &CollateralHandler{
+		collateralService: collateralService,
+		logger:            logger,
+	}
+}
+
+// GetCollateral retrieves the collateral securing an application
+// @Summary Get collateral
+// @Description Retrieve the vehicle or asset securing an application, its latest valuation and source, and the application's loan-to-value ratio at that valuation. Collateral is pledged when the application is created.
+// @Tags Collateral
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.Collateral} "Collateral retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Application belongs to another user"
+// @Failure 404 {object} middleware.ErrorResponse "Application or collateral not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/collateral [get]
+func (h *CollateralHandler) GetCollateral(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_collateral"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	collateral, err := h.collateralService.GetCollateral(c.Request.Context(), c.Param("id"), middleware.OwnerScope(c))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, collateral, "", nil)
+}
+
+// RevalueCollateral values an application's collateral again (admin endpoint)
+// @Summary Revalue collateral
+// @Description Value an application's collateral again with the valuation provider for its type, e.g. when it was valued at the borrower's stated value or its valuation is too old for underwriting, and update the loan-to-value ratio.
+// @Tags Collateral
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.Collateral} "Collateral revalued"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Application or collateral not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Failure 502 {object} middleware.ErrorResponse "Valuation provider failed"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/collateral/valuation [post]
+func (h *CollateralHandler) RevalueCollateral(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "revalue_collateral"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	collateral, err := h.collateralService.RevalueCollateral(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, collateral, "COLLATERAL_REVALUED", nil)
+}
+
+// respondError writes the error response for a failed collateral request
+func (h *CollateralHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Collateral request failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected collateral error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the collateral routes
+func (h *CollateralHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		loans.GET("/applications/:id/collateral", h.GetCollateral)
+
+		// Admin endpoints (require a back-office role)
+		admin := loans.Group("", middleware.RequireStaff())
+		admin.POST("/applications/:id/collateral/valuation", h.RevalueCollateral)
+	}
+}
@@ -0,0 +1,169 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// ConditionHandler handles underwriting condition tracking: borrowers' evidence uploads and
+// underwriters' waivers
+type ConditionHandler struct {
+	conditionService *application.ConditionService
+	logger           *zap.Logger
+}
+
+// NewConditionHandler creates a new condition handler
+func NewConditionHandler(conditionService *application.ConditionService, logger *zap.Logger) *ConditionHandler {
+	return &ConditionHandler{
+		conditionService: conditionService,
+		logger:           logger,
+	}
+}
+
+// ListConditions lists the underwriting conditions placed on an application
+// @Summary List underwriting conditions
+// @Description List the underwriting conditions placed on an application with their status (pending, received, waived or expired), priority, due date and the evidence uploaded for them
+// @Tags Conditions
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=[]domain.UnderwritingCondition} "Underwriting conditions retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/conditions [get]
+func (h *ConditionHandler) ListConditions(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "list_underwriting_conditions"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	conditions, err := h.conditionService.List(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, conditions, "", nil)
+}
+
+// UploadEvidence uploads a borrower document as evidence for a condition
+// @Summary Upload condition evidence
+// @Description Upload a document as multipart/form-data as evidence for an underwriting condition. The file is stored in the user service and the condition is marked received; once every critical condition of a conditional approval is received or waived, the underwriting workflow resumes and approves the loan.
+// @Tags Conditions
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param condition_id path string true "Condition ID"
+// @Param document_type formData string true "Document type, e.g. pay_stub, bank_statement, letter_of_explanation; sent before the file"
+// @Param file formData file true "Document file (PDF, JPEG or PNG, at most 10 MB)"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ConditionEvidenceResult} "Evidence uploaded"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Application or condition not found"
+// @Failure 409 {object} middleware.ErrorResponse "Condition waived or expired"
+// @Failure 413 {object} middleware.ErrorResponse "Document too large"
+// @Failure 415 {object} middleware.ErrorResponse "Unsupported document format"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/conditions/{condition_id}/evidence [post]
+func (h *ConditionHandler) UploadEvidence(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "upload_condition_evidence"),
+		zap.String("application_id", c.Param("id")),
+		zap.String("condition_id", c.Param("condition_id")),
+	)
+
+	upload, ok := bindDocumentUpload(c, logger)
+	if !ok {
+		return
+	}
+
+	result, err := h.conditionService.UploadEvidence(c.Request.Context(), c.Param("id"), c.Param("condition_id"), upload)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, result, "CONDITION_EVIDENCE_UPLOADED", nil)
+}
+
+// WaiveCondition waives an underwriting condition (staff endpoint)
+// @Summary Waive an underwriting condition
+// @Description Record that a pending or received condition is no longer required, with the reason. Waived conditions count as cleared when deciding whether a conditional approval can go ahead.
+// @Tags Conditions
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param condition_id path string true "Condition ID"
+// @Param request body domain.WaiveConditionRequest true "Waiver reason"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.UnderwritingCondition} "Condition waived"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Condition not found"
+// @Failure 409 {object} middleware.ErrorResponse "Condition already waived or expired"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/conditions/{condition_id}/waive [post]
+func (h *ConditionHandler) WaiveCondition(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "waive_underwriting_condition"),
+		zap.String("application_id", c.Param("id")),
+		zap.String("condition_id", c.Param("condition_id")),
+	)
+
+	var req domain.WaiveConditionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	condition, err := h.conditionService.Waive(c.Request.Context(), c.Param("id"), c.Param("condition_id"), &req, c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, condition, "CONDITION_WAIVED", nil)
+}
+
+// respondError maps service errors to error responses
+func (h *ConditionHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Condition operation failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected condition error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the condition routes
+func (h *ConditionHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		loans.GET("/applications/:id/conditions", h.ListConditions)
+		loans.POST("/applications/:id/conditions/:condition_id/evidence", h.UploadEvidence)
+
+		// Underwriter endpoints (require a back-office role)
+		staff := loans.Group("", middleware.RequireStaff())
+		staff.POST("/applications/:id/conditions/:condition_id/waive", h.WaiveCondition)
+	}
+}
@@ -0,0 +1,152 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// CreditConsentHandler handles borrowers' consent to the credit pull for their applications
+type CreditConsentHandler struct {
+	creditConsentService *application.CreditConsentService
+	logger               *zap.Logger
+}
+
+// NewCreditConsentHandler creates a new credit consent handler
+func NewCreditConsentHandler(creditConsentService *application.CreditConsentService, logger *zap.Logger) *CreditConsentHandler {
+	return &CreditConsentHandler{
+		creditConsentService: creditConsentService,
+		logger:               logger,
+	}
+}
+
+// RecordConsent records the borrower's consent to the credit pull for an application
+// @Summary Consent to credit pull
+// @Description Record the borrower's explicit consent to the hard credit pull made during underwriting, with the version of the disclosure they were shown and the IP address and user agent it was given from. Consent is required before the application can be submitted; consent already recorded is returned as is.
+// @Tags Credit Consent
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param request body domain.CreditConsentRequest true "Consent to the credit pull disclosure"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.CreditConsent} "Credit consent recorded"
+// @Failure 400 {object} middleware.ErrorResponse "Disclosure not accepted or not current, or application already submitted"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Application belongs to another user"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/credit-consent [post]
+func (h *CreditConsentHandler) RecordConsent(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "record_credit_consent"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	var req domain.CreditConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	consent, err := h.creditConsentService.RecordConsent(c.Request.Context(), c.Param("id"), c.GetString("user_id"), c.ClientIP(), c.Request.UserAgent(), &req)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, consent, "CREDIT_CONSENT_RECORDED", nil)
+}
+
+// GetConsent retrieves the borrower's consent to the credit pull for an application
+// @Summary Get credit pull consent
+// @Description Retrieve the borrower's consent to the credit pull for an application
+// @Tags Credit Consent
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.CreditConsent} "Credit consent retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Application belongs to another user"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 409 {object} middleware.ErrorResponse "No consent recorded"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/credit-consent [get]
+func (h *CreditConsentHandler) GetConsent(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_credit_consent"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	consent, err := h.creditConsentService.GetConsent(c.Request.Context(), c.Param("id"), middleware.OwnerScope(c))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, consent, "", nil)
+}
+
+// GetCreditPullEligibility reports whether an application may trigger a hard credit pull (internal endpoint)
+// @Summary Get credit pull eligibility
+// @Description Report whether an application has been submitted, is still open and has the borrower's consent to the credit pull; the decision engine only makes hard pulls for eligible applications
+// @Tags Internal
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.CreditPullEligibility} "Credit pull eligibility"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Router /internal/v1/applications/{id}/credit-pull-eligibility [get]
+func (h *CreditConsentHandler) GetCreditPullEligibility(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_credit_pull_eligibility"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	eligibility, err := h.creditConsentService.GetCreditPullEligibility(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, eligibility, "", nil)
+}
+
+// respondError writes the error response for a failed credit consent request
+func (h *CreditConsentHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Credit consent request failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected credit consent error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the credit consent routes
+func (h *CreditConsentHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		loans.POST("/applications/:id/credit-consent", h.RecordConsent)
+		loans.GET("/applications/:id/credit-consent", h.GetConsent)
+	}
+}
+
+// RegisterInternalRoutes registers the service-to-service credit consent routes on a group already
+// guarded by the internal service token
+func (h *CreditConsentHandler) RegisterInternalRoutes(router *gin.RouterGroup) {
+	router.GET("/applications/:id/credit-pull-eligibility", h.GetCreditPullEligibility)
+}
@@ -0,0 +1,240 @@
+package interfaces
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// DeadLetterHandler handles the dead-letter queue of workflow tasks
+type DeadLetterHandler struct {
+	deadLetterService *application.DeadLetterService
+	logger            *zap.Logger
+}
+
+// NewDeadLetterHandler creates a new dead-letter handler
+func NewDeadLetterHandler(deadLetterService *application.DeadLetterService, logger *zap.Logger) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		deadLetterService: deadLetterService,
+		logger:            logger,
+	}
+}
+
+// ListDeadLetters lists dead-lettered workflow tasks (admin endpoint)
+// @Summary List dead-lettered tasks
+// @Description List workflow tasks workers gave up on, most recently failed first: tasks that failed with a terminal error, or with a transient one after every retry
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param status query string false "Status (open, resubmitted, discarded)"
+// @Param task_type query string false "Task type"
+// @Param application_id query string false "Application ID"
+// @Param limit query int false "Maximum tasks returned (default 50, max 100)"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=[]domain.DeadLetterTask} "Dead-lettered tasks retrieved"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/admin/dead-letters [get]
+func (h *DeadLetterHandler) ListDeadLetters(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "list_dead_letters"))
+
+	var query domain.DeadLetterQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Warn("Invalid query parameters", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	tasks, err := h.deadLetterService.List(c.Request.Context(), &query)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, tasks, "", nil)
+}
+
+// GetDeadLetter returns a dead-lettered task with its input and failure (admin endpoint)
+// @Summary Get a dead-lettered task
+// @Description Retrieve a dead-lettered workflow task with the input it ran with, its error and output, and how many times it was attempted
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Dead-lettered task ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.DeadLetterTask} "Dead-lettered task retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Dead-lettered task not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/admin/dead-letters/{id} [get]
+func (h *DeadLetterHandler) GetDeadLetter(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_dead_letter"),
+		zap.String("dead_letter_id", c.Param("id")),
+	)
+
+	task, err := h.deadLetterService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, task, "", nil)
+}
+
+// UpdateDeadLetterInput corrects the input a dead-lettered task is re-run with (admin endpoint)
+// @Summary Fix a dead-lettered task's input
+// @Description Replace the input an open dead-lettered task will be re-run with, e.g. to correct a bad application or consent ID
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Dead-lettered task ID"
+// @Param request body domain.UpdateDeadLetterInputRequest true "Corrected input and note"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.DeadLetterTask} "Input updated"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Dead-lettered task not found"
+// @Failure 409 {object} middleware.ErrorResponse "Task already resolved"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/admin/dead-letters/{id}/input [put]
+func (h *DeadLetterHandler) UpdateDeadLetterInput(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "update_dead_letter_input"),
+		zap.String("dead_letter_id", c.Param("id")),
+	)
+
+	var req domain.UpdateDeadLetterInputRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	task, err := h.deadLetterService.UpdateInput(c.Request.Context(), c.Param("id"), &req, c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, task, "DEAD_LETTER_UPDATED", nil)
+}
+
+// ResubmitDeadLetter re-runs the workflow from a dead-lettered task (admin endpoint)
+// @Summary Re-submit a dead-lettered task
+// @Description Re-run the task's workflow in Conductor from the task, with its current input, and close the task as re-submitted
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Dead-lettered task ID"
+// @Param request body domain.ResolveDeadLetterRequest false "Note"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.DeadLetterTask} "Workflow re-run"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Dead-lettered task or workflow not found"
+// @Failure 409 {object} middleware.ErrorResponse "Task already resolved or workflow cannot be re-run"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Failure 503 {object} middleware.ErrorResponse "Workflow engine unavailable"
+// @Security BearerAuth
+// @Router /loans/admin/dead-letters/{id}/resubmit [post]
+func (h *DeadLetterHandler) ResubmitDeadLetter(c *gin.Context) {
+	h.resolveDeadLetter(c, "resubmit_dead_letter", "DEAD_LETTER_RESUBMITTED", h.deadLetterService.Resubmit)
+}
+
+// DiscardDeadLetter closes a dead-lettered task without re-running it (admin endpoint)
+// @Summary Discard a dead-lettered task
+// @Description Close a dead-lettered task that should not be re-run, e.g. because the application was withdrawn. A note is required.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Dead-lettered task ID"
+// @Param request body domain.ResolveDeadLetterRequest true "Note"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.DeadLetterTask} "Task discarded"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Dead-lettered task not found"
+// @Failure 409 {object} middleware.ErrorResponse "Task already resolved"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/admin/dead-letters/{id}/discard [post]
+func (h *DeadLetterHandler) DiscardDeadLetter(c *gin.Context) {
+	h.resolveDeadLetter(c, "discard_dead_letter", "DEAD_LETTER_DISCARDED", h.deadLetterService.Discard)
+}
+
+// resolveDeadLetter binds the optional note and runs a re-submit or discard
+func (h *DeadLetterHandler) resolveDeadLetter(
+	c *gin.Context,
+	operation, messageKey string,
+	resolve func(ctx context.Context, id string, req *domain.ResolveDeadLetterRequest, performedBy string) (*domain.DeadLetterTask, error),
+) {
+	logger := h.logger.With(
+		zap.String("operation", operation),
+		zap.String("dead_letter_id", c.Param("id")),
+	)
+
+	// The body is optional for re-submitting
+	var req domain.ResolveDeadLetterRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	task, err := resolve(c.Request.Context(), c.Param("id"), &req, c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, task, messageKey, nil)
+}
+
+// respondError maps service errors to error responses
+func (h *DeadLetterHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Dead-letter operation failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected dead-letter error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the dead-letter routes
+func (h *DeadLetterHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		// Admin endpoints (require a back-office role)
+		admin := loans.Group("", middleware.RequireStaff())
+		admin.GET("/admin/dead-letters", h.ListDeadLetters)
+		admin.GET("/admin/dead-letters/:id", h.GetDeadLetter)
+
+		// Changing workflows is limited to managers and admins, as for workflow actions
+		operators := admin.Group("", middleware.RequireRoles("manager", "admin", "super_admin"))
+		operators.PUT("/admin/dead-letters/:id/input", h.UpdateDeadLetterInput)
+		operators.POST("/admin/dead-letters/:id/resubmit", h.ResubmitDeadLetter)
+		operators.POST("/admin/dead-letters/:id/discard", h.DiscardDeadLetter)
+	}
+}
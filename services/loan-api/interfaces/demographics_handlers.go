@@ -0,0 +1,120 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// DemographicsHandler handles borrowers' answers to the government monitoring questions
+type DemographicsHandler struct {
+	demographicsService *application.DemographicsService
+	logger              *zap.Logger
+}
+
+// NewDemographicsHandler creates a new demographics handler
+func NewDemographicsHandler(demographicsService *application.DemographicsService, logger *zap.Logger) *DemographicsHandler {
+	return &DemographicsHandler{
+		demographicsService: demographicsService,
+		logger:              logger,
+	}
+}
+
+// RecordDemographics records the borrower's answers to the government monitoring questions
+// @Summary Provide demographic information
+// @Description Record the borrower's ethnicity, race and sex for government monitoring, replacing earlier answers. Each question may be answered NOT_PROVIDED. The answers are kept apart from the application, are not available to underwriting and can be changed until the application is decided.
+// @Tags Demographics
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param request body domain.ApplicantDemographicsRequest true "Answers to the government monitoring questions"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ApplicantDemographics} "Demographic information recorded"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid answers"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Application belongs to another user"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 409 {object} middleware.ErrorResponse "Application already decided"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/demographics [put]
+func (h *DemographicsHandler) RecordDemographics(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "record_demographics"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	var req domain.ApplicantDemographicsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	demographics, err := h.demographicsService.RecordDemographics(c.Request.Context(), c.Param("id"), c.GetString("user_id"), &req)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, demographics, "DEMOGRAPHICS_RECORDED", nil)
+}
+
+// GetDemographics retrieves the borrower's answers to the government monitoring questions
+// @Summary Get demographic information
+// @Description Retrieve the borrower's answers to the government monitoring questions for an application; only available to the borrower
+// @Tags Demographics
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ApplicantDemographics} "Demographic information retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Application belongs to another user"
+// @Failure 404 {object} middleware.ErrorResponse "Application or demographic information not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/demographics [get]
+func (h *DemographicsHandler) GetDemographics(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_demographics"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	demographics, err := h.demographicsService.GetDemographics(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, demographics, "", nil)
+}
+
+// respondError writes the error response for a failed demographics request
+func (h *DemographicsHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Demographics request failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected demographics error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the demographics routes
+func (h *DemographicsHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		loans.PUT("/applications/:id/demographics", h.RecordDemographics)
+		loans.GET("/applications/:id/demographics", h.GetDemographics)
+	}
+}
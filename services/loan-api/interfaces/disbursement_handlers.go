@@ -0,0 +1,213 @@
+package interfaces
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// DisbursementHandler handles loan funding requests and disbursement provider callbacks
+type DisbursementHandler struct {
+	disbursementService *application.DisbursementService
+	logger              *zap.Logger
+}
+
+// NewDisbursementHandler creates a new disbursement handler
+func NewDisbursementHandler(disbursementService *application.DisbursementService, logger *zap.Logger) *DisbursementHandler {
+	return &DisbursementHandler{
+		disbursementService: disbursementService,
+		logger:              logger,
+	}
+}
+
+// GetDisbursement retrieves the status of an application's disbursement
+// @Summary Get disbursement status
+// @Description Retrieve the disbursement most recently scheduled for an application, with its audit trail of submissions, returns and retries
+// @Tags Funding
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.Disbursement} "Disbursement retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Application belongs to another user"
+// @Failure 404 {object} middleware.ErrorResponse "No disbursement has been scheduled"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/disbursement [get]
+func (h *DisbursementHandler) GetDisbursement(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_disbursement"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	disbursement, err := h.disbursementService.GetDisbursement(c.Request.Context(), c.Param("id"), middleware.OwnerScope(c))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, disbursement, "", nil)
+}
+
+// ScheduleDisbursement schedules the disbursement of a signed loan (admin endpoint)
+// @Summary Schedule disbursement
+// @Description Schedule the selected offer's amount to be sent to the borrower's verified bank account in the next funding batch. Disbursements are scheduled automatically when the agreement is signed; this schedules one again after a disbursement has failed. A disbursement in progress or completed is returned as is.
+// @Tags Funding
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.Disbursement} "Disbursement scheduled"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 409 {object} middleware.ErrorResponse "Agreement not signed or no verified bank account"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Failure 503 {object} middleware.ErrorResponse "Disbursement unavailable"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/disbursement [post]
+func (h *DisbursementHandler) ScheduleDisbursement(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "schedule_disbursement"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	disbursement, err := h.disbursementService.ScheduleDisbursement(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, disbursement, "DISBURSEMENT_SCHEDULED", nil)
+}
+
+// RunFundingBatch submits a funding batch now (admin endpoint)
+// @Summary Run a funding batch
+// @Description Originate every disbursement that is due in a funding batch without waiting for the scheduled run. The data is null when nothing was due.
+// @Tags Funding
+// @Accept json
+// @Produce json
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.FundingBatch} "Funding batch submitted"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Failure 503 {object} middleware.ErrorResponse "Disbursement unavailable"
+// @Security BearerAuth
+// @Router /funding/batches [post]
+func (h *DisbursementHandler) RunFundingBatch(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "run_funding_batch"))
+
+	batch, err := h.disbursementService.RunBatch(c.Request.Context())
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+	if batch == nil {
+		middleware.CreateSuccessResponse(c, nil, "", nil)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, batch, "FUNDING_BATCH_SUBMITTED", nil)
+}
+
+// GetFundingBatch retrieves a funding batch (admin endpoint)
+// @Summary Get a funding batch
+// @Description Retrieve a funding batch with the disbursements it originated
+// @Tags Funding
+// @Accept json
+// @Produce json
+// @Param id path string true "Funding batch ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.FundingBatch} "Funding batch retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Funding batch not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /funding/batches/{id} [get]
+func (h *DisbursementHandler) GetFundingBatch(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_funding_batch"),
+		zap.String("batch_id", c.Param("id")),
+	)
+
+	batch, err := h.disbursementService.GetFundingBatch(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, batch, "", nil)
+}
+
+// HandleWebhook receives transfer status notifications from the disbursement provider
+// @Summary Disbursement provider webhook
+// @Description Receive transfer notifications from the disbursement provider. Requests are authenticated by the provider's HMAC signature. Completed transfers move the application to funded; returned transfers are retried when the return code allows.
+// @Tags Funding
+// @Accept json
+// @Produce json
+// @Success 200 {object} middleware.SuccessResponse "Notification processed"
+// @Failure 401 {object} middleware.ErrorResponse "Invalid signature"
+// @Failure 500 {object} middleware.ErrorResponse "Processing failed; the provider retries"
+// @Router /webhooks/disbursements [post]
+func (h *DisbursementHandler) HandleWebhook(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "handle_disbursement_webhook"))
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookSize))
+	if err != nil {
+		logger.Warn("Failed to read webhook body", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	if err := h.disbursementService.HandleWebhook(c.Request.Context(), body, c.GetHeader); err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, gin.H{"received": true}, "", nil)
+}
+
+// respondError writes the error response for a failed disbursement request
+func (h *DisbursementHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Disbursement request failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected disbursement error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the loan funding routes
+func (h *DisbursementHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		loans.GET("/applications/:id/disbursement", h.GetDisbursement)
+
+		// Admin endpoints (require a back-office role)
+		admin := loans.Group("", middleware.RequireStaff())
+		admin.POST("/applications/:id/disbursement", h.ScheduleDisbursement)
+	}
+
+	// Admin endpoints (require a back-office role)
+	funding := router.Group("/funding", middleware.RequireStaff())
+	{
+		funding.POST("/batches", h.RunFundingBatch)
+		funding.GET("/batches/:id", h.GetFundingBatch)
+	}
+}
+
+// RegisterWebhookRoutes registers the disbursement provider callback routes
+func (h *DisbursementHandler) RegisterWebhookRoutes(router *gin.RouterGroup) {
+	router.POST("/disbursements", h.HandleWebhook)
+}
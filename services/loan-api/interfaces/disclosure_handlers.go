@@ -0,0 +1,213 @@
+package interfaces
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// DisclosureHandler handles generation and download of loan agreements and disclosures
+type DisclosureHandler struct {
+	documentService *application.DocumentGenerationService
+	logger          *zap.Logger
+}
+
+// NewDisclosureHandler creates a new disclosure handler
+func NewDisclosureHandler(documentService *application.DocumentGenerationService, logger *zap.Logger) *DisclosureHandler {
+	return &DisclosureHandler{
+		documentService: documentService,
+		logger:          logger,
+	}
+}
+
+// ListDocuments lists the agreements and disclosures generated for an application
+// @Summary List generated documents
+// @Description List the loan agreements, TILA disclosures and adverse action notices generated for an application, newest first
+// @Tags Disclosures
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=[]domain.GeneratedDocument} "Documents retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Application belongs to another user"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/disclosures [get]
+func (h *DisclosureHandler) ListDocuments(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "list_generated_documents"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	documents, err := h.documentService.ListGeneratedDocuments(c.Request.Context(), c.Param("id"), middleware.OwnerScope(c))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, documents, "", nil)
+}
+
+// GenerateDocument generates an agreement or disclosure for an application (admin endpoint)
+// @Summary Generate a document
+// @Description Render a loan agreement or TILA disclosure for the selected offer, or an adverse action notice for a denied application, and store it with the borrower's documents. TILA disclosures are generated automatically when an offer is selected.
+// @Tags Disclosures
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param request body domain.GenerateDocumentRequest true "Document to generate"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.GeneratedDocument} "Document generated"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid document type"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 409 {object} middleware.ErrorResponse "Document cannot be generated in the current state"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Failure 502 {object} middleware.ErrorResponse "Document storage unavailable"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/disclosures [post]
+func (h *DisclosureHandler) GenerateDocument(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "generate_document"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	var req domain.GenerateDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	document, err := h.documentService.GenerateDocument(c.Request.Context(), c.Param("id"), req.DocumentType, c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, document, "DOCUMENT_GENERATED", nil)
+}
+
+// IssueDecisionLetter sends the borrower the letter telling them of the underwriting decision
+// @Summary Issue a decision letter
+// @Description Render an approval letter with the approved terms or an adverse action notice with the decision's reasons, store it with the borrower's documents and email it to them. The response records when the letter was delivered; a letter already delivered for the decision is returned without being sent again. Called by the underwriting worker once an application is decided.
+// @Tags Internal
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param request body domain.DecisionLetterRequest true "Decision to notify the borrower of"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.GeneratedDocument} "Decision letter delivered"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Failure 502 {object} middleware.ErrorResponse "Document storage or letter delivery unavailable"
+// @Router /internal/v1/applications/{id}/decision-letters [post]
+func (h *DisclosureHandler) IssueDecisionLetter(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "issue_decision_letter"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	var req domain.DecisionLetterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	document, err := h.documentService.IssueDecisionLetter(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, document, "", nil)
+}
+
+// DownloadDocument downloads a generated agreement or disclosure
+// @Summary Download a generated document
+// @Description Download the PDF of an agreement or disclosure generated for an application
+// @Tags Disclosures
+// @Produce application/pdf
+// @Param id path string true "Application ID"
+// @Param document_id path string true "Generated document ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {file} file "Document PDF"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Application belongs to another user"
+// @Failure 404 {object} middleware.ErrorResponse "Application or document not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Failure 502 {object} middleware.ErrorResponse "Document storage unavailable"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/disclosures/{document_id}/download [get]
+func (h *DisclosureHandler) DownloadDocument(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "download_generated_document"),
+		zap.String("application_id", c.Param("id")),
+		zap.String("document_id", c.Param("document_id")),
+	)
+
+	content, err := h.documentService.DownloadGeneratedDocument(c.Request.Context(), c.Param("id"), c.Param("document_id"), middleware.OwnerScope(c))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+	defer content.Content.Close()
+
+	c.Header("Content-Type", content.MimeType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", content.FileName))
+	if content.Size >= 0 {
+		c.Header("Content-Length", strconv.FormatInt(content.Size, 10))
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	if _, err := io.Copy(c.Writer, content.Content); err != nil {
+		logger.Warn("Failed to stream document", zap.Error(err))
+	}
+}
+
+// respondError writes the error response for a failed document request
+func (h *DisclosureHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Document request failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected document error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the generated document routes
+func (h *DisclosureHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		loans.GET("/applications/:id/disclosures", h.ListDocuments)
+		loans.GET("/applications/:id/disclosures/:document_id/download", h.DownloadDocument)
+
+		// Admin endpoints (require a back-office role)
+		admin := loans.Group("", middleware.RequireStaff())
+		admin.POST("/applications/:id/disclosures", h.GenerateDocument)
+	}
+}
+
+// RegisterInternalRoutes registers the service-to-service document routes on a group already
+// guarded by the internal service token
+func (h *DisclosureHandler) RegisterInternalRoutes(router *gin.RouterGroup) {
+	router.POST("/applications/:id/decision-letters", h.IssueDecisionLetter)
+}
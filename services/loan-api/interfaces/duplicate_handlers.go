@@ -0,0 +1,155 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// DuplicateHandler handles admin review of applications suspected to duplicate another borrower's
+type DuplicateHandler struct {
+	duplicateService *application.DuplicateDetectionService
+	logger           *zap.Logger
+}
+
+// NewDuplicateHandler creates a new duplicate handler
+func NewDuplicateHandler(duplicateService *application.DuplicateDetectionService, logger *zap.Logger) *DuplicateHandler {
+	return &DuplicateHandler{
+		duplicateService: duplicateService,
+		logger:           logger,
+	}
+}
+
+// ListDuplicateMatches lists the suspected duplicates of an application (admin endpoint)
+// @Summary List duplicate matches
+// @Description List other borrowers' recent applications sharing the application's SSN, email or device, with the signals they share and their review status. Pending matches route automated approval to manual review.
+// @Tags Duplicates
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=[]domain.DuplicateMatch} "Duplicate matches retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/duplicates [get]
+func (h *DuplicateHandler) ListDuplicateMatches(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "list_duplicate_matches"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	matches, err := h.duplicateService.ListMatches(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, matches, "", nil)
+}
+
+// LinkDuplicateMatch confirms a suspected duplicate as the same applicant (admin endpoint)
+// @Summary Link duplicate match
+// @Description Confirm that a pending duplicate match is the same applicant.
+// @Tags Duplicates
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param matchId path string true "Duplicate match ID"
+// @Param request body domain.ReviewDuplicateMatchRequest true "Review"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.DuplicateMatch} "Duplicate match linked"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Duplicate match not found"
+// @Failure 409 {object} middleware.ErrorResponse "Duplicate match already reviewed"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/duplicates/{matchId}/link [post]
+func (h *DuplicateHandler) LinkDuplicateMatch(c *gin.Context) {
+	h.reviewDuplicateMatch(c, "link_duplicate_match", "DUPLICATE_MATCH_LINKED", h.duplicateService.LinkMatch)
+}
+
+// DismissDuplicateMatch clears a suspected duplicate as a false positive (admin endpoint)
+// @Summary Dismiss duplicate match
+// @Description Clear a pending duplicate match as a false positive, e.g. a device shared by a household.
+// @Tags Duplicates
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param matchId path string true "Duplicate match ID"
+// @Param request body domain.ReviewDuplicateMatchRequest true "Review"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.DuplicateMatch} "Duplicate match dismissed"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Duplicate match not found"
+// @Failure 409 {object} middleware.ErrorResponse "Duplicate match already reviewed"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/duplicates/{matchId}/dismiss [post]
+func (h *DuplicateHandler) DismissDuplicateMatch(c *gin.Context) {
+	h.reviewDuplicateMatch(c, "dismiss_duplicate_match", "DUPLICATE_MATCH_DISMISSED", h.duplicateService.DismissMatch)
+}
+
+// reviewDuplicateMatch binds a review and records it with the given service method
+func (h *DuplicateHandler) reviewDuplicateMatch(
+	c *gin.Context,
+	operation, messageKey string,
+	review func(ctx context.Context, applicationID, matchID string, req *domain.ReviewDuplicateMatchRequest) (*domain.DuplicateMatch, error),
+) {
+	logger := h.logger.With(
+		zap.String("operation", operation),
+		zap.String("application_id", c.Param("id")),
+		zap.String("match_id", c.Param("matchId")),
+	)
+
+	var req domain.ReviewDuplicateMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	match, err := review(c.Request.Context(), c.Param("id"), c.Param("matchId"), &req)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, match, messageKey, nil)
+}
+
+// respondError writes the error response for a failed duplicate review request
+func (h *DuplicateHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Duplicate review request failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected duplicate review error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the duplicate review routes
+func (h *DuplicateHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		// Admin endpoints (require a back-office role)
+		admin := loans.Group("", middleware.RequireStaff())
+		admin.GET("/applications/:id/duplicates", h.ListDuplicateMatches)
+		admin.POST("/applications/:id/duplicates/:matchId/link", h.LinkDuplicateMatch)
+		admin.POST("/applications/:id/duplicates/:matchId/dismiss", h.DismissDuplicateMatch)
+	}
+}
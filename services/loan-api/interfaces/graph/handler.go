@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/gin-gonic/gin"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// complexityLimit bounds the fields a single query may resolve
+const complexityLimit = 200
+
+// Handler serves the GraphQL API
+type Handler struct {
+	server *handler.Server
+}
+
+// NewHandler creates a new GraphQL handler resolving queries with resolver
+func NewHandler(resolver *Resolver) *Handler {
+	server := handler.New(NewExecutableSchema(Config{
+		Resolvers:  resolver,
+		Directives: DirectiveRoot{Staff: staffDirective},
+	}))
+	server.AddTransport(transport.POST{})
+	server.Use(extension.Introspection{})
+	server.Use(extension.FixedComplexityLimit(complexityLimit))
+	server.SetErrorPresenter(presentError)
+
+	return &Handler{server: server}
+}
+
+// Query executes a GraphQL query for the authenticated caller
+// @Summary Query loan application data
+// @Description Query an application with its offers, documents and timeline in one request. Borrowers can only query their own applications; staff-only fields are null for them.
+// @Tags GraphQL
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Query result, with any errors in errors[].extensions.code"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Security BearerAuth
+// @Router /graphql [post]
+func (h *Handler) Query(c *gin.Context) {
+	h.server.ServeHTTP(c.Writer, c.Request)
+}
+
+// RegisterRoutes registers the GraphQL endpoint; router must authenticate callers
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/graphql", ViewerMiddleware(), h.Query)
+}
+
+// presentError reports service errors with their error code, and without the internal detail in
+// their description
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+
+	var loanErr *domain.LoanError
+	if errors.As(err, &loanErr) {
+		presented.Message = loanErr.Message
+		if presented.Extensions == nil {
+			presented.Extensions = map[string]interface{}{}
+		}
+		presented.Extensions["code"] = loanErr.Code
+	}
+	return presented
+}
@@ -0,0 +1,165 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+	sharedmiddleware "github.com/huuhoait/los-demo/services/shared/pkg/middleware"
+)
+
+const testJWTSecret = "test-secret"
+
+// stubApplicationRepository holds one application and its generated documents
+type stubApplicationRepository struct {
+	application.LoanRepository
+	application *domain.LoanApplication
+	documents   []*domain.GeneratedDocument
+}
+
+func (r *stubApplicationRepository) GetApplicationByID(ctx context.Context, id string) (*domain.LoanApplication, error) {
+	if r.application == nil || r.application.ID != id {
+		return nil, fmt.Errorf("application not found: %s", id)
+	}
+	return r.application, nil
+}
+
+func (r *stubApplicationRepository) ListGeneratedDocuments(ctx context.Context, applicationID string) ([]*domain.GeneratedDocument, error) {
+	return r.documents, nil
+}
+
+// accessToken signs an access token the way the auth service does
+func accessToken(t *testing.T, userID, role string) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, sharedmiddleware.AccessClaims{
+		UserID: userID,
+		Role:   role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{"los-api"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	signed, err := token.SignedString([]byte(testJWTSecret))
+	require.NoError(t, err)
+	return signed
+}
+
+type graphResponse struct {
+	Data struct {
+		Application *struct {
+			ApplicationNumber string  `json:"applicationNumber"`
+			RiskScore         *int    `json:"riskScore"`
+			WorkflowID        *string `json:"workflowId"`
+			Documents         []struct {
+				ID          string  `json:"id"`
+				GeneratedBy *string `json:"generatedBy"`
+			} `json:"documents"`
+		} `json:"application"`
+	} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func TestHandler_Query(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	riskScore := 712
+	workflowID := "workflow-1"
+	underwriter := "underwriter-1"
+	repo := &stubApplicationRepository{
+		application: &domain.LoanApplication{
+			ID:                "app-1",
+			UserID:            "borrower-1",
+			ApplicationNumber: "LN-0001",
+			LoanPurpose:       domain.LoanPurpose("personal"),
+			CurrentState:      domain.ApplicationState("offer_generated"),
+			RiskScore:         &riskScore,
+			WorkflowID:        &workflowID,
+		},
+		documents: []*domain.GeneratedDocument{{ID: "doc-1", DocumentType: "tila_disclosure", GeneratedBy: &underwriter}},
+	}
+	loanService := application.NewLoanService(nil, repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, zap.NewNop(), nil)
+	documentService := application.NewDocumentGenerationService(repo, nil, nil, nil, nil, zap.NewNop())
+
+	router := gin.New()
+	protected := router.Group("/v1", middleware.RequireAuth(testJWTSecret))
+	NewHandler(NewResolver(loanService, documentService, zap.NewNop())).RegisterRoutes(protected)
+
+	query := func(token string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(map[string]interface{}{
+			"query":     `query($id: ID!) { application(id: $id) { applicationNumber riskScore workflowId documents { id generatedBy } } }`,
+			"variables": map[string]interface{}{"id": "app-1"},
+		})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/v1/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, query("").Code)
+	})
+
+	t.Run("owner sees their application without staff fields", func(t *testing.T) {
+		recorder := query(accessToken(t, "borrower-1", "borrower"))
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var resp graphResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		require.Empty(t, resp.Errors)
+		require.NotNil(t, resp.Data.Application)
+		assert.Equal(t, "LN-0001", resp.Data.Application.ApplicationNumber)
+		assert.Nil(t, resp.Data.Application.RiskScore)
+		assert.Nil(t, resp.Data.Application.WorkflowID)
+		require.Len(t, resp.Data.Application.Documents, 1)
+		assert.Nil(t, resp.Data.Application.Documents[0].GeneratedBy)
+	})
+
+	t.Run("another borrower is refused", func(t *testing.T) {
+		recorder := query(accessToken(t, "borrower-2", "borrower"))
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var resp graphResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		assert.Nil(t, resp.Data.Application)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, domain.LOAN_022, resp.Errors[0].Extensions["code"])
+	})
+
+	t.Run("staff see any application with staff fields", func(t *testing.T) {
+		recorder := query(accessToken(t, "underwriter-1", "senior_reviewer"))
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var resp graphResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		require.Empty(t, resp.Errors)
+		require.NotNil(t, resp.Data.Application)
+		require.NotNil(t, resp.Data.Application.RiskScore)
+		assert.Equal(t, riskScore, *resp.Data.Application.RiskScore)
+		require.NotNil(t, resp.Data.Application.WorkflowID)
+		assert.Equal(t, workflowID, *resp.Data.Application.WorkflowID)
+		require.Len(t, resp.Data.Application.Documents, 1)
+		require.NotNil(t, resp.Data.Application.Documents[0].GeneratedBy)
+	})
+}
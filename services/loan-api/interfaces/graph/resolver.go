@@ -0,0 +1,30 @@
+// Package graph serves loan application data over GraphQL at /v1/graphql, so the web frontend
+// can load the application detail screen (application, offers, documents and timeline) in one
+// request. The executable schema in generated.go is generated by gqlgen from schema.graphqls;
+// the field resolvers are in schema.resolvers.go.
+package graph
+
+//go:generate go run github.com/99designs/gqlgen generate
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+)
+
+// Resolver is the root resolver; field resolvers read through the same services as the REST
+// handlers, so ownership checks and error codes match
+type Resolver struct {
+	loanService     *application.LoanService
+	documentService *application.DocumentGenerationService
+	logger          *zap.Logger
+}
+
+// NewResolver creates a new root resolver
+func NewResolver(loanService *application.LoanService, documentService *application.DocumentGenerationService, logger *zap.Logger) *Resolver {
+	return &Resolver{
+		loanService:     loanService,
+		documentService: documentService,
+		logger:          logger,
+	}
+}
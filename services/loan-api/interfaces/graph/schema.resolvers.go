@@ -0,0 +1,107 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// LoanPurpose is the resolver for the loanPurpose field.
+func (r *applicationResolver) LoanPurpose(ctx context.Context, obj *domain.LoanApplication) (string, error) {
+	return string(obj.LoanPurpose), nil
+}
+
+// EmploymentStatus is the resolver for the employmentStatus field.
+func (r *applicationResolver) EmploymentStatus(ctx context.Context, obj *domain.LoanApplication) (string, error) {
+	return string(obj.EmploymentStatus), nil
+}
+
+// CurrentState is the resolver for the currentState field.
+func (r *applicationResolver) CurrentState(ctx context.Context, obj *domain.LoanApplication) (string, error) {
+	return string(obj.CurrentState), nil
+}
+
+// Status is the resolver for the status field.
+func (r *applicationResolver) Status(ctx context.Context, obj *domain.LoanApplication) (string, error) {
+	return string(obj.Status), nil
+}
+
+// ApplicationType is the resolver for the applicationType field.
+func (r *applicationResolver) ApplicationType(ctx context.Context, obj *domain.LoanApplication) (string, error) {
+	return string(obj.ApplicationType), nil
+}
+
+// OfferGroups is the resolver for the offerGroups field.
+func (r *applicationResolver) OfferGroups(ctx context.Context, obj *domain.LoanApplication) ([]*domain.OfferGroup, error) {
+	return r.loanService.ListOfferGroups(ctx, obj.ID)
+}
+
+// Documents is the resolver for the documents field.
+func (r *applicationResolver) Documents(ctx context.Context, obj *domain.LoanApplication) ([]*domain.GeneratedDocument, error) {
+	return r.documentService.ListGeneratedDocuments(ctx, obj.ID, ViewerFromContext(ctx).ownerID())
+}
+
+// Timeline is the resolver for the timeline field.
+func (r *applicationResolver) Timeline(ctx context.Context, obj *domain.LoanApplication) ([]*domain.TimelineEvent, error) {
+	timeline, err := r.loanService.GetTimeline(ctx, obj.ID, ViewerFromContext(ctx).Staff)
+	if err != nil {
+		return nil, err
+	}
+	return timeline.Events, nil
+}
+
+// Status is the resolver for the status field.
+func (r *offerGroupResolver) Status(ctx context.Context, obj *domain.OfferGroup) (string, error) {
+	return string(obj.Status), nil
+}
+
+// Application is the resolver for the application field.
+func (r *queryResolver) Application(ctx context.Context, id string) (*domain.LoanApplication, error) {
+	application, err := r.loanService.GetApplication(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := ViewerFromContext(ctx).authorizeApplication(application); err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+// Type is the resolver for the type field.
+func (r *timelineEventResolver) Type(ctx context.Context, obj *domain.TimelineEvent) (string, error) {
+	return string(obj.Type), nil
+}
+
+// ActorType is the resolver for the actorType field.
+func (r *timelineEventResolver) ActorType(ctx context.Context, obj *domain.TimelineEvent) (string, error) {
+	return obj.Actor.Type, nil
+}
+
+// ActorID is the resolver for the actorId field.
+func (r *timelineEventResolver) ActorID(ctx context.Context, obj *domain.TimelineEvent) (*string, error) {
+	if obj.Actor.ID == "" {
+		return nil, nil
+	}
+	return &obj.Actor.ID, nil
+}
+
+// Application returns ApplicationResolver implementation.
+func (r *Resolver) Application() ApplicationResolver { return &applicationResolver{r} }
+
+// OfferGroup returns OfferGroupResolver implementation.
+func (r *Resolver) OfferGroup() OfferGroupResolver { return &offerGroupResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// TimelineEvent returns TimelineEventResolver implementation.
+func (r *Resolver) TimelineEvent() TimelineEventResolver { return &timelineEventResolver{r} }
+
+type applicationResolver struct{ *Resolver }
+type offerGroupResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type timelineEventResolver struct{ *Resolver }
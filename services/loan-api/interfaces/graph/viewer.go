@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gin-gonic/gin"
+
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// Viewer is the caller a GraphQL request is resolved for
+type Viewer struct {
+	UserID string
+	Staff  bool // back-office roles
+}
+
+type viewerKey struct{}
+
+// WithViewer returns a context carrying the viewer
+func WithViewer(ctx context.Context, viewer *Viewer) context.Context {
+	return context.WithValue(ctx, viewerKey{}, viewer)
+}
+
+// ViewerFromContext returns the request's viewer; a request without one is an anonymous borrower
+func ViewerFromContext(ctx context.Context) *Viewer {
+	if viewer, ok := ctx.Value(viewerKey{}).(*Viewer); ok {
+		return viewer
+	}
+	return &Viewer{}
+}
+
+// ViewerMiddleware records the authenticated caller on the request context for the resolvers.
+// Staff callers hold a back-office role; everyone else is resolved as the borrower.
+func ViewerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		viewer := &Viewer{
+			UserID: c.GetString("user_id"),
+			Staff:  middleware.IsStaff(c),
+		}
+		c.Request = c.Request.WithContext(WithViewer(c.Request.Context(), viewer))
+		c.Next()
+	}
+}
+
+// ownerID is the user ID services check ownership against; empty for staff, who see every
+// application
+func (v *Viewer) ownerID() string {
+	if v.Staff {
+		return ""
+	}
+	return v.UserID
+}
+
+// authorizeApplication rejects borrowers querying another borrower's application
+func (v *Viewer) authorizeApplication(application *domain.LoanApplication) error {
+	if v.Staff || application.UserID == v.UserID {
+		return nil
+	}
+	return &domain.LoanError{
+		Code:        domain.LOAN_022,
+		Message:     "Unauthorized access",
+		Description: "Applications can only be viewed by their owner",
+		HTTPStatus:  403,
+	}
+}
+
+// staffDirective implements @staff: the field resolves for staff viewers and is null for borrowers
+func staffDirective(ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
+	if !ViewerFromContext(ctx).Staff {
+		return nil, nil
+	}
+	return next(ctx)
+}
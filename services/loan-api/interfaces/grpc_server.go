@@ -0,0 +1,139 @@
+package interfaces
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/shared/pkg/rpc"
+	loanv1 "github.com/huuhoait/los-demo/services/shared/proto/loan/v1"
+)
+
+// LoanServer serves the LoanService gRPC contract, letting the workers and the decision engine
+// read and move applications without the public REST API. Callers are authenticated by the
+// transport's mTLS.
+type LoanServer struct {
+	loanv1.UnimplementedLoanServiceServer
+	loanService *application.LoanService
+	logger      *zap.Logger
+}
+
+// NewLoanServer creates a new LoanService gRPC server
+func NewLoanServer(loanService *application.LoanService, logger *zap.Logger) *LoanServer {
+	return &LoanServer{
+		loanService: loanService,
+		logger:      logger,
+	}
+}
+
+// GetApplication returns an application
+func (s *LoanServer) GetApplication(ctx context.Context, req *loanv1.GetApplicationRequest) (*loanv1.Application, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	app, err := s.loanService.GetApplication(ctx, req.GetId())
+	if err != nil {
+		return nil, s.loanStatus("get_application", req.GetId(), err)
+	}
+	return applicationMessage(app), nil
+}
+
+// UpdateApplicationState moves an application to another state through the state machine
+func (s *LoanServer) UpdateApplicationState(ctx context.Context, req *loanv1.UpdateApplicationStateRequest) (*loanv1.Application, error) {
+	if req.GetId() == "" || req.GetToState() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id and to_state are required")
+	}
+
+	metadata := make(map[string]interface{}, len(req.GetMetadata()))
+	for key, value := range req.GetMetadata() {
+		metadata[key] = value
+	}
+
+	app, err := s.loanService.UpdateApplicationState(ctx, &domain.StateUpdateRequest{
+		ApplicationID: req.GetId(),
+		ToState:       domain.ApplicationState(req.GetToState()),
+		Reason:        req.GetReason(),
+		Automated:     req.GetAutomated(),
+		Metadata:      metadata,
+	})
+	if err != nil {
+		return nil, s.loanStatus("update_application_state", req.GetId(), err)
+	}
+	return applicationMessage(app), nil
+}
+
+// GetOffer returns an application's latest offer. Reading it does not lock the offer's rate; only
+// offers shown to the borrower are locked.
+func (s *LoanServer) GetOffer(ctx context.Context, req *loanv1.GetOfferRequest) (*loanv1.Offer, error) {
+	if req.GetApplicationId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "application_id is required")
+	}
+
+	offer, err := s.loanService.GetLatestOffer(ctx, req.GetApplicationId())
+	if err != nil {
+		return nil, s.loanStatus("get_offer", req.GetApplicationId(), err)
+	}
+
+	return &loanv1.Offer{
+		Id:             offer.ID,
+		ApplicationId:  offer.ApplicationID,
+		OfferAmount:    offer.OfferAmount,
+		InterestRate:   offer.InterestRate,
+		TermMonths:     int32(offer.TermMonths),
+		MonthlyPayment: offer.MonthlyPayment,
+		TotalInterest:  offer.TotalInterest,
+		Apr:            offer.APR,
+		Status:         offer.Status,
+		ExpiresAt:      timestamppb.New(offer.ExpiresAt),
+	}, nil
+}
+
+// loanStatus reports a loan error with the gRPC code matching its HTTP status
+func (s *LoanServer) loanStatus(operation, applicationID string, err error) error {
+	logger := s.logger.With(
+		zap.String("rpc", operation),
+		zap.String("application_id", applicationID),
+	)
+
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Loan service request failed", zap.String("error_code", loanErr.Code), zap.Error(err))
+		return status.Errorf(rpc.CodeFromHTTPStatus(loanErr.HTTPStatus), "%s: %s", loanErr.Code, loanErr.Description)
+	}
+
+	logger.Error("Unexpected loan service error", zap.Error(err))
+	return status.Error(codes.Internal, "internal error")
+}
+
+// applicationMessage converts an application to its LoanService message
+func applicationMessage(app *domain.LoanApplication) *loanv1.Application {
+	message := &loanv1.Application{
+		Id:                  app.ID,
+		UserId:              app.UserID,
+		ApplicationNumber:   app.ApplicationNumber,
+		LoanAmount:          app.LoanAmount,
+		LoanPurpose:         string(app.LoanPurpose),
+		RequestedTermMonths: int32(app.RequestedTerm),
+		AnnualIncome:        app.AnnualIncome,
+		MonthlyIncome:       app.MonthlyIncome,
+		EmploymentStatus:    string(app.EmploymentStatus),
+		MonthlyDebtPayments: app.MonthlyDebt,
+		CurrentState:        string(app.CurrentState),
+		Status:              string(app.Status),
+		ApplicationType:     string(app.ApplicationType),
+		CreatedAt:           timestamppb.New(app.CreatedAt),
+		UpdatedAt:           timestamppb.New(app.UpdatedAt),
+	}
+	if app.RiskScore != nil {
+		message.RiskScore = int32(*app.RiskScore)
+	}
+	if app.WorkflowID != nil {
+		message.WorkflowId = *app.WorkflowID
+	}
+	return message
+}
@@ -2,11 +2,14 @@ package interfaces
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
+	"mime"
 	"net/http"
+	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -44,9 +47,13 @@ func NewLoanHandler(loanService *application.LoanService, logger *zap.Logger, lo
 // @Produce json
 // @Param application body domain.CreateApplicationRequest true "Loan application details"
 // @Param X-Language header string false "Language preference (en, vi)"
+// @Param Idempotency-Key header string false "Client-generated key; a retry with the same key and body replays the original response"
+// @Param X-Device-ID header string false "Fingerprint of the applicant's device, used when device_id is not in the body"
 // @Success 200 {object} middleware.SuccessResponse{data=domain.LoanApplication} "Application created successfully"
 // @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
 // @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 409 {object} middleware.ErrorResponse "A request with this idempotency key is still in progress"
+// @Failure 422 {object} middleware.ErrorResponse "Idempotency key reused with a different request"
 // @Failure 500 {object} middleware.ErrorResponse "Internal server error"
 // @Security BearerAuth
 // @Router /loans/applications [post]
@@ -74,6 +81,9 @@ func (h *LoanHandler) CreateApplication(c *gin.Context) {
 		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, errorDetails)
 		return
 	}
+	if req.DeviceID == "" {
+		req.DeviceID = c.GetHeader("X-Device-ID")
+	}
 
 	application, err := h.loanService.CreateApplication(c.Request.Context(), &req)
 	if err != nil {
@@ -185,7 +195,8 @@ func (h *LoanHandler) UpdateApplication(c *gin.Context) {
 	middleware.CreateSuccessResponse(c, application, "APPLICATION_UPDATED", nil)
 }
 
-// SubmitApplication submits a draft application for processing
+// SubmitApplication submits a draft application for processing once the borrower has consented to
+// the credit pull
 // POST /v1/loans/applications/:id/submit
 func (h *LoanHandler) SubmitApplication(c *gin.Context) {
 	logger := h.logger.With(
@@ -221,67 +232,92 @@ func (h *LoanHandler) SubmitApplication(c *gin.Context) {
 	middleware.CreateSuccessResponse(c, application, "APPLICATION_SUBMITTED", nil)
 }
 
-// GetApplicationsByUser retrieves all applications for the current user
-// @Summary Get all loan applications for the current user
-// @Description Retrieve all loan applications associated with the authenticated user
+// WithdrawApplication cancels an application before it is funded
+// @Summary Withdraw a loan application
+// @Description Withdraw an application that has not been funded with a reason; open offers expire, the application's workflow is terminated and the borrower is notified
 // @Tags Applications
 // @Accept json
 // @Produce json
+// @Param id path string true "Application ID"
+// @Param request body domain.WithdrawApplicationRequest true "Withdrawal reason"
 // @Param X-Language header string false "Language preference (en, vi)"
-// @Success 200 {object} middleware.SuccessResponse{data=[]domain.LoanApplication} "Applications retrieved successfully"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ApplicationWithdrawal} "Application withdrawn"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
 // @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Application belongs to another user"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 409 {object} middleware.ErrorResponse "Application cannot be withdrawn in its current state"
 // @Failure 500 {object} middleware.ErrorResponse "Internal server error"
 // @Security BearerAuth
-// @Router /loans/applications [get]
-func (h *LoanHandler) GetApplicationsByUser(c *gin.Context) {
+// @Router /loans/applications/{id}/withdraw [post]
+func (h *LoanHandler) WithdrawApplication(c *gin.Context) {
 	logger := h.logger.With(
-		zap.String("operation", "get_applications_by_user"),
+		zap.String("operation", "withdraw_application"),
 	)
 
-	// Get user ID from context
-	userID, exists := c.Get("user_id")
-	if !exists {
-		logger.Error("User ID not found in context")
-		middleware.CreateErrorResponse(c, http.StatusUnauthorized, domain.LOAN_022, nil)
+	applicationID := c.Param("id")
+	if applicationID == "" {
+		logger.Warn("Missing application ID")
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
 		return
 	}
 
-	applications, err := h.loanService.GetApplicationsByUser(c.Request.Context(), userID.(string))
+	var req domain.WithdrawApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	// Ownership is enforced when the caller is a borrower; staff may withdraw any application
+	userID := middleware.OwnerScope(c)
+
+	withdrawal, err := h.loanService.WithdrawApplication(c.Request.Context(), applicationID, userID, &req)
 	if err != nil {
 		if loanErr, ok := err.(*domain.LoanError); ok {
-			logger.Warn("Failed to get applications",
+			logger.Warn("Failed to withdraw application",
 				zap.String("error_code", loanErr.Code),
-				zap.String("user_id", userID.(string)),
+				zap.String("application_id", applicationID),
 				zap.Error(err))
 			middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
 			return
 		}
 
-		logger.Error("Unexpected error getting applications", zap.Error(err))
+		logger.Error("Unexpected error withdrawing application", zap.Error(err))
 		middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
 		return
 	}
 
-	middleware.CreateSuccessResponse(c, applications, "", nil)
+	logger.Info("Application withdrawn successfully",
+		zap.String("application_id", applicationID))
+
+	middleware.CreateSuccessResponse(c, withdrawal, "APPLICATION_WITHDRAWN", nil)
 }
 
-// PreQualify performs pre-qualification check
-// @Summary Perform loan pre-qualification
-// @Description Check if a user qualifies for a loan based on income, debt, and other factors
-// @Tags Pre-qualification
+// GetApplicationsByUser retrieves the current user's applications, one page at a time
+// @Summary Get loan applications for the current user
+// @Description Retrieve a filtered, sorted page of the authenticated user's loan applications
+// @Tags Applications
 // @Accept json
 // @Produce json
-// @Param request body domain.PreQualifyRequest true "Pre-qualification request"
+// @Param status query string false "Filter by status"
+// @Param state query string false "Filter by state"
+// @Param created_from query string false "Created on or after this date (YYYY-MM-DD)"
+// @Param created_to query string false "Created on or before this date (YYYY-MM-DD)"
+// @Param sort_by query string false "Sort field: created_at, updated_at or loan_amount (default: created_at)"
+// @Param sort_order query string false "Sort order: asc or desc (default: desc)"
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Page size (default: 20, max: 100)"
 // @Param X-Language header string false "Language preference (en, vi)"
-// @Success 200 {object} middleware.SuccessResponse{data=domain.PreQualifyResult} "Pre-qualification completed"
-// @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ApplicationPage} "Applications retrieved successfully"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid query parameters"
 // @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
 // @Failure 500 {object} middleware.ErrorResponse "Internal server error"
 // @Security BearerAuth
-// @Router /loans/prequalify [post]
-func (h *LoanHandler) PreQualify(c *gin.Context) {
+// @Router /loans/applications [get]
+func (h *LoanHandler) GetApplicationsByUser(c *gin.Context) {
 	logger := h.logger.With(
-		zap.String("operation", "pre_qualify"),
+		zap.String("operation", "get_applications_by_user"),
 	)
 
 	// Get user ID from context
@@ -292,26 +328,94 @@ func (h *LoanHandler) PreQualify(c *gin.Context) {
 		return
 	}
 
-	var req domain.PreQualifyRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request format", zap.Error(err))
+	var query domain.ApplicationListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Warn("Invalid query parameters", zap.Error(err))
 		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
 		return
 	}
+	// Borrowers only ever see their own applications
+	query.UserID = userID.(string)
 
-	// TODO: Implement pre-qualification workflow initiation
-	// For now, return a placeholder response
-	logger.Info("Pre-qualification workflow initiated",
-		zap.String("user_id", userID.(string)))
+	h.listApplications(c, logger, &query)
+}
 
-	middleware.CreateSuccessResponse(c, gin.H{
-		"message": "Pre-qualification workflow initiated",
-		"status":  "pending",
-	}, "PRE_QUALIFICATION_SUCCESS", nil)
+// GetAllApplications lists applications across all users (admin endpoint)
+// @Summary List all loan applications
+// @Description Retrieve a filtered, sorted page of loan applications across all users (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param user_id query string false "Filter by user ID"
+// @Param status query string false "Filter by status"
+// @Param state query string false "Filter by state"
+// @Param created_from query string false "Created on or after this date (YYYY-MM-DD)"
+// @Param created_to query string false "Created on or before this date (YYYY-MM-DD)"
+// @Param sort_by query string false "Sort field: created_at, updated_at or loan_amount (default: created_at)"
+// @Param sort_order query string false "Sort order: asc or desc (default: desc)"
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Page size (default: 20, max: 100)"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ApplicationPage} "Applications retrieved successfully"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid query parameters"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/all [get]
+func (h *LoanHandler) GetAllApplications(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_all_applications"),
+	)
+
+	var query domain.ApplicationListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Warn("Invalid query parameters", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	h.listApplications(c, logger, &query)
+}
+
+// listApplications runs an application listing query and writes the page or error response
+func (h *LoanHandler) listApplications(c *gin.Context, logger *zap.Logger, query *domain.ApplicationListQuery) {
+	page, err := h.loanService.ListApplications(c.Request.Context(), query)
+	if err != nil {
+		if loanErr, ok := err.(*domain.LoanError); ok {
+			logger.Warn("Failed to list applications",
+				zap.String("error_code", loanErr.Code),
+				zap.String("user_id", query.UserID),
+				zap.Error(err))
+			middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+			return
+		}
+
+		logger.Error("Unexpected error listing applications", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, page, "", nil)
 }
 
-// GenerateOffer generates a loan offer for an application
-// POST /v1/loans/applications/:id/offer
+// GenerateOffer prices and stores a group of alternative loan offers for an approved application
+// @Summary Generate loan offers
+// @Description Price offers for an approved application across 36, 48 and 60 month terms and amount options from the credit score and risk level of its underwriting decision; each offer carries a pricing audit record. Earlier open offer groups expire.
+// @Tags Offers
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.OfferGroup} "Offers generated"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 409 {object} middleware.ErrorResponse "Application is not approved, or its underwriting decision is missing or provisional"
+// @Failure 422 {object} middleware.ErrorResponse "No pricing rate available"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Failure 502 {object} middleware.ErrorResponse "Underwriting decision unavailable"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/offer [post]
 func (h *LoanHandler) GenerateOffer(c *gin.Context) {
 	logger := h.logger.With(
 		zap.String("operation", "generate_offer"),
@@ -324,141 +428,866 @@ func (h *LoanHandler) GenerateOffer(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement offer generation workflow initiation
-	// For now, return a placeholder response
-	logger.Info("Offer generation workflow initiated",
-		zap.String("application_id", applicationID))
+	group, err := h.loanService.GenerateOffer(c.Request.Context(), applicationID)
+	if err != nil {
+		if loanErr, ok := err.(*domain.LoanError); ok {
+			logger.Warn("Failed to generate offer",
+				zap.String("error_code", loanErr.Code),
+				zap.String("application_id", applicationID),
+				zap.Error(err))
+			middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+			return
+		}
 
-	middleware.CreateSuccessResponse(c, gin.H{
-		"message":        "Offer generation workflow initiated",
-		"status":         "pending",
-		"application_id": applicationID,
-	}, "OFFER_GENERATED", nil)
+		logger.Error("Unexpected error generating offer", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+		return
+	}
+
+	logger.Info("Offers generated",
+		zap.String("application_id", applicationID),
+		zap.String("group_id", group.ID),
+		zap.Int("offer_count", len(group.Offers)))
+
+	middleware.CreateSuccessResponse(c, group, "OFFER_GENERATED", nil)
 }
 
-// AcceptOffer accepts a loan offer
-// POST /v1/loans/applications/:id/accept-offer
-func (h *LoanHandler) AcceptOffer(c *gin.Context) {
+// GetOffer retrieves the latest offer for an application with its pricing audit
+// @Summary Get the offer for an application
+// @Description Retrieve the latest offer for an application, including how it was priced and its rate lock; viewing a pending offer locks its rate
+// @Tags Offers
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.LoanOffer} "Offer retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Offer not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/offer [get]
+func (h *LoanHandler) GetOffer(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_offer"),
+	)
+
+	applicationID := c.Param("id")
+	offer, err := h.loanService.GetOffer(c.Request.Context(), applicationID)
+	if err != nil {
+		if loanErr, ok := err.(*domain.LoanError); ok {
+			logger.Warn("Failed to get offer",
+				zap.String("error_code", loanErr.Code),
+				zap.String("application_id", applicationID),
+				zap.Error(err))
+			middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+			return
+		}
+
+		logger.Error("Unexpected error getting offer", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, offer, "", nil)
+}
+
+// ListOffers lists the offer groups generated for an application
+// @Summary List offer groups
+// @Description List an application's offer groups with their offers and rate locks, newest first; viewing pending offers locks their rates
+// @Tags Offers
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=[]domain.OfferGroup} "Offer groups retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/offers [get]
+func (h *LoanHandler) ListOffers(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "list_offers"),
+	)
+
+	applicationID := c.Param("id")
+	groups, err := h.loanService.ListOfferGroups(c.Request.Context(), applicationID)
+	if err != nil {
+		h.respondOfferError(c, logger, applicationID, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, groups, "", nil)
+}
+
+// CompareOffers compares the offers of a group side by side
+// @Summary Compare offers
+// @Description Compare a group's offers by monthly payment, total interest, APR and amount; defaults to the latest group. Viewing pending offers locks their rates.
+// @Tags Offers
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param group_id query string false "Offer group ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.OfferComparison} "Offers compared"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Offer group not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/offers/compare [get]
+func (h *LoanHandler) CompareOffers(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "compare_offers"),
+	)
+
+	applicationID := c.Param("id")
+	comparison, err := h.loanService.CompareOffers(c.Request.Context(), applicationID, c.Query("group_id"))
+	if err != nil {
+		h.respondOfferError(c, logger, applicationID, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, comparison, "", nil)
+}
+
+// SelectOffer selects one offer of an open group, expiring the rest
+// @Summary Select an offer
+// @Description Select one offer from an open offer group, or a pending counter offer; the group's other offers expire. An offer whose rate lock has expired must be repriced first; the selected offer's rate is locked again.
+// @Tags Offers
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param offer_id path string true "Offer ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.OfferGroup} "Offer selected"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Offer not found"
+// @Failure 409 {object} middleware.ErrorResponse "Offer was declined or countered, or its rate lock expired"
+// @Failure 410 {object} middleware.ErrorResponse "Offer group expired or already selected"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/offers/{offer_id}/select [post]
+func (h *LoanHandler) SelectOffer(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "select_offer"),
+		zap.String("offer_id", c.Param("offer_id")),
+	)
+
+	applicationID := c.Param("id")
+	group, err := h.loanService.SelectOffer(c.Request.Context(), applicationID, c.Param("offer_id"))
+	if err != nil {
+		h.respondOfferError(c, logger, applicationID, err)
+		return
+	}
+
+	logger.Info("Offer selected",
+		zap.String("application_id", applicationID),
+		zap.String("group_id", group.ID))
+
+	middleware.CreateSuccessResponse(c, group, "OFFER_SELECTED", nil)
+}
+
+// DeclineOffer declines an offer or counter offer
+// @Summary Decline an offer
+// @Description Decline a pending offer or counter offer with a reason; the decline is added to the negotiation thread
+// @Tags Offers
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param offer_id path string true "Offer ID"
+// @Param request body domain.DeclineOfferRequest true "Decline reason"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.OfferNegotiation} "Offer declined"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Offer not found"
+// @Failure 409 {object} middleware.ErrorResponse "Offer is no longer pending"
+// @Failure 410 {object} middleware.ErrorResponse "Offer expired"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/offers/{offer_id}/decline [post]
+func (h *LoanHandler) DeclineOffer(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "decline_offer"),
+		zap.String("offer_id", c.Param("offer_id")),
+	)
+
+	var req domain.DeclineOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	applicationID := c.Param("id")
+	negotiation, err := h.loanService.DeclineOffer(c.Request.Context(), applicationID, c.Param("offer_id"), &req)
+	if err != nil {
+		h.respondOfferError(c, logger, applicationID, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, negotiation, "OFFER_DECLINED", nil)
+}
+
+// RequestCounterOffer asks for different terms than an offer's
+// @Summary Request a counter offer
+// @Description Ask for a different amount or term than a pending offer's; a counter offer with its own expiration is generated and added to the negotiation thread
+// @Tags Offers
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param offer_id path string true "Offer ID"
+// @Param request body domain.CounterOfferRequest true "Requested terms"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.OfferNegotiation} "Counter offer requested"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Offer not found"
+// @Failure 409 {object} middleware.ErrorResponse "Offer is no longer pending or is a counter offer"
+// @Failure 410 {object} middleware.ErrorResponse "Offer expired"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error, or the counter offer workflow could not be started"
+// @Failure 503 {object} middleware.ErrorResponse "Workflow engine unavailable"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/offers/{offer_id}/counter [post]
+func (h *LoanHandler) RequestCounterOffer(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "request_counter_offer"),
+		zap.String("offer_id", c.Param("offer_id")),
+	)
+
+	var req domain.CounterOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, map[string]interface{}{
+			"field_errors": getFieldErrors(err),
+		})
+		return
+	}
+
+	applicationID := c.Param("id")
+	negotiation, err := h.loanService.RequestCounterOffer(c.Request.Context(), applicationID, c.Param("offer_id"), &req)
+	if err != nil {
+		h.respondOfferError(c, logger, applicationID, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, negotiation, "COUNTER_OFFER_REQUESTED", nil)
+}
+
+// RespondToCounterOffer accepts or declines a counter offer
+// @Summary Respond to a counter offer
+// @Description Accept or decline a pending counter offer; a decline needs a reason. The counter offer workflow waiting on the borrower resumes, and on acceptance it prices the accepted terms and records the final underwriting result. Accepting closes the offer group with the counter offer selected, as the select endpoint does.
+// @Tags Offers
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param offer_id path string true "Counter offer ID"
+// @Param request body domain.CounterOfferResponseRequest true "Response"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.CounterOfferResponseResult} "Counter offer accepted or declined"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Counter offer not found"
+// @Failure 409 {object} middleware.ErrorResponse "Counter offer is no longer pending, or its rate lock expired"
+// @Failure 410 {object} middleware.ErrorResponse "Counter offer expired"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/offers/{offer_id}/respond [post]
+func (h *LoanHandler) RespondToCounterOffer(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "respond_to_counter_offer"),
+		zap.String("offer_id", c.Param("offer_id")),
+	)
+
+	var req domain.CounterOfferResponseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, map[string]interface{}{
+			"field_errors": getFieldErrors(err),
+		})
+		return
+	}
+
+	applicationID := c.Param("id")
+	result, err := h.loanService.RespondToCounterOffer(c.Request.Context(), applicationID, c.Param("offer_id"), &req)
+	if err != nil {
+		h.respondOfferError(c, logger, applicationID, err)
+		return
+	}
+
+	messageKey := "OFFER_SELECTED"
+	if result.Response == domain.CounterOfferDeclined {
+		messageKey = "OFFER_DECLINED"
+	}
+	middleware.CreateSuccessResponse(c, result, messageKey, nil)
+}
+
+// GetNegotiation retrieves an application's offer negotiation thread
+// @Summary Get the offer negotiation thread
+// @Description Retrieve declines, requests for different terms and the counter offers made in response; pending counter offers are accepted or declined through the respond endpoint
+// @Tags Offers
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.NegotiationThread} "Negotiation retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/negotiation [get]
+func (h *LoanHandler) GetNegotiation(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_negotiation"),
+	)
+
+	applicationID := c.Param("id")
+	thread, err := h.loanService.GetNegotiation(c.Request.Context(), applicationID)
+	if err != nil {
+		h.respondOfferError(c, logger, applicationID, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, thread, "", nil)
+}
+
+func (h *LoanHandler) respondOfferError(c *gin.Context, logger *zap.Logger, applicationID string, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Offer request failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("application_id", applicationID),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected offer error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// AddNote posts a note on an application (staff endpoint)
+// @Summary Add an application note
+// @Description Post a note on an application as the authenticated staff member, with optional mentions and attachments. The author and author role are taken from the access token. Notes are internal unless visibility is borrower; mentioned users are notified.
+// @Tags Notes
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param request body domain.CreateNoteRequest true "Note"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ApplicationNote} "Note added"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/notes [post]
+func (h *LoanHandler) AddNote(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "add_note"),
+	)
+
+	var req domain.CreateNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	authorRole, ok := domain.NoteAuthorRoleFor(c.GetString("user_role"))
+	req.AuthorID = c.GetString("user_id")
+	if req.AuthorID == "" || !ok {
+		logger.Warn("Note author cannot be derived from the caller")
+		middleware.CreateErrorResponse(c, http.StatusForbidden, domain.LOAN_022, nil)
+		return
+	}
+	req.AuthorRole = authorRole
+
+	applicationID := c.Param("id")
+	note, err := h.loanService.AddNote(c.Request.Context(), applicationID, &req)
+	if err != nil {
+		h.respondNoteError(c, logger, applicationID, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, note, "NOTE_ADDED", nil)
+}
+
+// ListNotes retrieves the notes shared with the borrower
+// @Summary List application notes
+// @Description Retrieve the notes on an application that are shared with the borrower, oldest first
+// @Tags Notes
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=[]domain.ApplicationNote} "Notes retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/notes [get]
+func (h *LoanHandler) ListNotes(c *gin.Context) {
+	h.listNotes(c, false)
+}
+
+// ListAllNotes retrieves every note on an application, internal ones included (staff endpoint)
+// @Summary List all application notes
+// @Description Retrieve every note on an application, internal notes included, oldest first
+// @Tags Notes
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=[]domain.ApplicationNote} "Notes retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/notes/all [get]
+func (h *LoanHandler) ListAllNotes(c *gin.Context) {
+	h.listNotes(c, true)
+}
+
+func (h *LoanHandler) listNotes(c *gin.Context, includeInternal bool) {
+	logger := h.logger.With(
+		zap.String("operation", "list_notes"),
+		zap.Bool("include_internal", includeInternal),
+	)
+
+	applicationID := c.Param("id")
+	notes, err := h.loanService.ListNotes(c.Request.Context(), applicationID, includeInternal)
+	if err != nil {
+		h.respondNoteError(c, logger, applicationID, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, notes, "", nil)
+}
+
+// GetTimeline retrieves an application's activity feed as the borrower sees it
+// @Summary Get the application timeline
+// @Description Retrieve state changes, offers, negotiation, documents, workflow events and the notes shared with the borrower as one chronological feed with localized descriptions
+// @Tags Applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ApplicationTimeline} "Timeline retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/timeline [get]
+func (h *LoanHandler) GetTimeline(c *gin.Context) {
+	h.getTimeline(c, false)
+}
+
+// GetFullTimeline retrieves an application's activity feed with internal notes and staff identities (staff endpoint)
+// @Summary Get the full application timeline
+// @Description Retrieve the application timeline including internal notes and the staff who acted
+// @Tags Applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ApplicationTimeline} "Timeline retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/timeline/all [get]
+func (h *LoanHandler) GetFullTimeline(c *gin.Context) {
+	h.getTimeline(c, true)
+}
+
+func (h *LoanHandler) getTimeline(c *gin.Context, includeInternal bool) {
+	logger := h.logger.With(
+		zap.String("operation", "get_timeline"),
+		zap.Bool("include_internal", includeInternal),
+	)
+
+	applicationID := c.Param("id")
+	timeline, err := h.loanService.GetTimeline(c.Request.Context(), applicationID, includeInternal)
+	if err != nil {
+		if loanErr, ok := err.(*domain.LoanError); ok {
+			logger.Warn("Failed to get timeline",
+				zap.String("error_code", loanErr.Code),
+				zap.String("application_id", applicationID),
+				zap.Error(err))
+			middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+			return
+		}
+
+		logger.Error("Unexpected error getting timeline", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, timeline, "", nil)
+}
+
+func (h *LoanHandler) respondNoteError(c *gin.Context, logger *zap.Logger, applicationID string, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Note request failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("application_id", applicationID),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected note error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// AcceptOffer accepts a loan offer
+// POST /v1/loans/applications/:id/accept-offer
+func (h *LoanHandler) AcceptOffer(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "accept_offer"),
+	)
+
+	applicationID := c.Param("id")
+	if applicationID == "" {
+		logger.Warn("Missing application ID")
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	var req domain.AcceptOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	// TODO: Implement offer acceptance workflow initiation
+	// For now, return a placeholder response
+	logger.Info("Offer acceptance workflow initiated",
+		zap.String("application_id", applicationID),
+		zap.String("offer_id", req.OfferID))
+
+	middleware.CreateSuccessResponse(c, gin.H{
+		"message":        "Offer acceptance workflow initiated",
+		"status":         "pending",
+		"application_id": applicationID,
+		"offer_id":       req.OfferID,
+	}, "OFFER_ACCEPTED", nil)
+}
+
+// TransitionState transitions an application state (admin endpoint)
+// POST /v1/loans/applications/:id/transition
+func (h *LoanHandler) TransitionState(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "transition_state"),
+	)
+
+	applicationID := c.Param("id")
+	if applicationID == "" {
+		logger.Warn("Missing application ID")
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	var req struct {
+		FromState string `json:"from_state" binding:"required"`
+		ToState   string `json:"to_state" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	// Validate state values
+	_ = domain.ApplicationState(req.FromState)
+	_ = domain.ApplicationState(req.ToState)
+
+	// TODO: Implement state transition workflow initiation
+	// For now, return a placeholder response
+	logger.Info("State transition workflow initiated",
+		zap.String("application_id", applicationID),
+		zap.String("from_state", req.FromState),
+		zap.String("to_state", req.ToState))
+
+	middleware.CreateSuccessResponse(c, gin.H{
+		"message":        "State transition workflow initiated",
+		"status":         "pending",
+		"application_id": applicationID,
+		"from_state":     req.FromState,
+		"to_state":       req.ToState,
+	}, "STATE_TRANSITION_SUCCESS", nil)
+}
+
+// BulkTransition moves a batch of applications to one state (admin endpoint)
+// @Summary Bulk transition applications
+// @Description Move up to 100 applications, e.g. ones stuck behind a failed workflow, to a target state. Each application is checked against the state machine and moved in its own transaction; the report gives every application's outcome (succeeded, skipped when already in the target state, or failed with its error code). Withdrawals go through the withdraw endpoint.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body domain.BulkTransitionRequest true "Applications and target state"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.BulkTransitionReport} "Bulk transition processed"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/bulk-transition [post]
+func (h *LoanHandler) BulkTransition(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "bulk_transition"),
+	)
+
+	var req domain.BulkTransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid bulk transition request", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	report, err := h.loanService.BulkTransition(c.Request.Context(), &req)
+	if err != nil {
+		if loanErr, ok := err.(*domain.LoanError); ok {
+			logger.Warn("Bulk transition rejected",
+				zap.String("error_code", loanErr.Code),
+				zap.String("description", loanErr.Description),
+				zap.Error(err))
+			middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+			return
+		}
+
+		logger.Error("Unexpected bulk transition error", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, report, "BULK_TRANSITION_COMPLETED", nil)
+}
+
+// GetApplicationStats gets application statistics (admin endpoint)
+// @Summary Get application statistics
+// @Description Retrieve counts by status and state, approval rate, average amount and funnel conversion for recent applications (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param days query int false "Number of days to look back (default: 30, max: 365)"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ApplicationStats} "Statistics retrieved successfully"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid days parameter"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/stats [get]
+func (h *LoanHandler) GetApplicationStats(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_application_stats"),
+	)
+
+	days := domain.DefaultStatsDays
+	if daysStr := c.Query("days"); daysStr != "" {
+		parsedDays, err := strconv.Atoi(daysStr)
+		if err != nil {
+			logger.Warn("Invalid days parameter", zap.String("days", daysStr))
+			middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+			return
+		}
+		days = parsedDays
+	}
+
+	stats, err := h.loanService.GetApplicationStats(c.Request.Context(), days)
+	if err != nil {
+		if loanErr, ok := err.(*domain.LoanError); ok {
+			logger.Warn("Failed to get application stats",
+				zap.String("error_code", loanErr.Code),
+				zap.Int("days", days),
+				zap.Error(err))
+			middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+			return
+		}
+
+		logger.Error("Unexpected error getting application stats", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, stats, "", nil)
+}
+
+// ReassignApplications moves applications between users after a duplicate merge (internal endpoint)
+// @Summary Reassign applications to another user
+// @Description Move every application owned by a merged duplicate user to the surviving user
+// @Tags Internal
+// @Accept json
+// @Produce json
+// @Param request body domain.ReassignApplicationsRequest true "Source and target user IDs"
+// @Success 200 {object} middleware.SuccessResponse{data=map[string]interface{}} "Applications reassigned"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Router /internal/v1/applications/reassign [post]
+func (h *LoanHandler) ReassignApplications(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "reassign_applications"),
+	)
+
+	var req domain.ReassignApplicationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid reassign request", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	count, err := h.loanService.ReassignApplications(c.Request.Context(), req.FromUserID, req.ToUserID)
+	if err != nil {
+		if loanErr, ok := err.(*domain.LoanError); ok {
+			logger.Warn("Failed to reassign applications",
+				zap.String("error_code", loanErr.Code),
+				zap.Error(err))
+			middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+			return
+		}
+
+		logger.Error("Unexpected error reassigning applications", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, gin.H{"reassigned": count}, "", nil)
+}
+
+// ReportDerogatoryInformation re-underwrites an approved application when derogatory credit
+// information about the borrower arrives before funding (internal endpoint)
+// @Summary Report derogatory information
+// @Description Invalidate the decision of an application awaiting funding, block its funding and start re-underwriting
+// @Tags Internal
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param request body domain.DerogatoryInformationRequest true "Derogatory item"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.Reunderwriting} "Re-underwriting outcome"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 409 {object} middleware.ErrorResponse "Funds already in flight"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Router /internal/v1/applications/{id}/derogatory-information [post]
+func (h *LoanHandler) ReportDerogatoryInformation(c *gin.Context) {
 	logger := h.logger.With(
-		zap.String("operation", "accept_offer"),
+		zap.String("operation", "report_derogatory_information"),
 	)
 
 	applicationID := c.Param("id")
-	if applicationID == "" {
-		logger.Warn("Missing application ID")
+	var req domain.DerogatoryInformationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid derogatory information request", zap.Error(err))
 		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
 		return
 	}
 
-	var req domain.AcceptOfferRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request format", zap.Error(err))
-		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+	reunderwriting, err := h.loanService.ReportDerogatoryInformation(c.Request.Context(), applicationID, &req)
+	if err != nil {
+		if loanErr, ok := err.(*domain.LoanError); ok {
+			logger.Warn("Failed to report derogatory information",
+				zap.String("error_code", loanErr.Code),
+				zap.String("application_id", applicationID),
+				zap.Error(err))
+			middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+			return
+		}
+
+		logger.Error("Unexpected error reporting derogatory information", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
 		return
 	}
 
-	// TODO: Implement offer acceptance workflow initiation
-	// For now, return a placeholder response
-	logger.Info("Offer acceptance workflow initiated",
-		zap.String("application_id", applicationID),
-		zap.String("offer_id", req.OfferID))
-
-	middleware.CreateSuccessResponse(c, gin.H{
-		"message":        "Offer acceptance workflow initiated",
-		"status":         "pending",
-		"application_id": applicationID,
-		"offer_id":       req.OfferID,
-	}, "OFFER_ACCEPTED", nil)
+	middleware.CreateSuccessResponse(c, reunderwriting, "", nil)
 }
 
-// TransitionState transitions an application state (admin endpoint)
-// POST /v1/loans/applications/:id/transition
-func (h *LoanHandler) TransitionState(c *gin.Context) {
+// CompensateWorkflow undoes a side effect a failed workflow left on an application (internal endpoint)
+// @Summary Compensate a failed workflow
+// @Description Roll back the application's state, void the workflow's offers or notify the borrower after a workflow fails midway
+// @Tags Internal
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param request body domain.CompensationRequest true "Compensation step"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.Compensation} "Compensation outcome"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Application or workflow not found"
+// @Failure 409 {object} middleware.ErrorResponse "State conflict"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Router /internal/v1/applications/{id}/compensations [post]
+func (h *LoanHandler) CompensateWorkflow(c *gin.Context) {
 	logger := h.logger.With(
-		zap.String("operation", "transition_state"),
+		zap.String("operation", "compensate_workflow"),
 	)
 
 	applicationID := c.Param("id")
-	if applicationID == "" {
-		logger.Warn("Missing application ID")
+	var req domain.CompensationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid compensation request", zap.Error(err))
 		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
 		return
 	}
 
-	var req struct {
-		FromState string `json:"from_state" binding:"required"`
-		ToState   string `json:"to_state" binding:"required"`
-	}
+	compensation, err := h.loanService.CompensateWorkflow(c.Request.Context(), applicationID, &req)
+	if err != nil {
+		if loanErr, ok := err.(*domain.LoanError); ok {
+			logger.Warn("Failed to compensate workflow",
+				zap.String("error_code", loanErr.Code),
+				zap.String("application_id", applicationID),
+				zap.String("workflow_id", req.WorkflowID),
+				zap.Error(err))
+			middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+			return
+		}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request format", zap.Error(err))
-		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		logger.Error("Unexpected error compensating workflow", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
 		return
 	}
 
-	// Validate state values
-	_ = domain.ApplicationState(req.FromState)
-	_ = domain.ApplicationState(req.ToState)
-
-	// TODO: Implement state transition workflow initiation
-	// For now, return a placeholder response
-	logger.Info("State transition workflow initiated",
-		zap.String("application_id", applicationID),
-		zap.String("from_state", req.FromState),
-		zap.String("to_state", req.ToState))
-
-	middleware.CreateSuccessResponse(c, gin.H{
-		"message":        "State transition workflow initiated",
-		"status":         "pending",
-		"application_id": applicationID,
-		"from_state":     req.FromState,
-		"to_state":       req.ToState,
-	}, "STATE_TRANSITION_SUCCESS", nil)
+	middleware.CreateSuccessResponse(c, compensation, "", nil)
 }
 
-// GetApplicationStats gets application statistics (admin endpoint)
-// @Summary Get application statistics
-// @Description Retrieve statistics about loan applications (admin only)
-// @Tags Admin
-// @Accept json
+// GetUserLoanActivity reports a user's open applications and last loan closure (internal endpoint)
+// @Summary Get a user's loan activity
+// @Description Count open applications and find the most recent loan closure, used for document retention
+// @Tags Internal
 // @Produce json
-// @Param status query string false "Filter by status"
-// @Param state query string false "Filter by state"
-// @Param days query int false "Number of days to look back (default: 30)"
-// @Param X-Language header string false "Language preference (en, vi)"
-// @Success 200 {object} middleware.SuccessResponse{data=map[string]interface{}} "Statistics retrieved successfully"
+// @Param user_id path string true "User ID"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.UserLoanActivity} "Loan activity"
 // @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
-// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
 // @Failure 500 {object} middleware.ErrorResponse "Internal server error"
-// @Security BearerAuth
-// @Router /loans/stats [get]
-func (h *LoanHandler) GetApplicationStats(c *gin.Context) {
+// @Router /internal/v1/users/{user_id}/loan-activity [get]
+func (h *LoanHandler) GetUserLoanActivity(c *gin.Context) {
 	logger := h.logger.With(
-		zap.String("operation", "get_application_stats"),
+		zap.String("operation", "get_user_loan_activity"),
+		zap.String("user_id", c.Param("user_id")),
 	)
 
-	// Get query parameters for filtering
-	status := c.Query("status")
-	state := c.Query("state")
-	daysStr := c.Query("days")
-
-	days := 30 // Default to 30 days
-	if daysStr != "" {
-		if parsedDays, err := strconv.Atoi(daysStr); err == nil {
-			days = parsedDays
+	activity, err := h.loanService.GetUserLoanActivity(c.Request.Context(), c.Param("user_id"))
+	if err != nil {
+		if loanErr, ok := err.(*domain.LoanError); ok {
+			logger.Warn("Failed to get user loan activity",
+				zap.String("error_code", loanErr.Code),
+				zap.Error(err))
+			middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+			return
 		}
-	}
 
-	// TODO: Implement real database query for application statistics
-	// This would aggregate data from the applications table
-	middleware.CreateErrorResponse(c, http.StatusNotImplemented, domain.LOAN_023, map[string]interface{}{
-		"message": "Application statistics not implemented - database repository required",
-	})
+		logger.Error("Unexpected error getting user loan activity", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+		return
+	}
 
-	logger.Info("Application statistics requested but not implemented",
-		zap.Int("days", days),
-		zap.String("status_filter", status),
-		zap.String("state_filter", state))
+	middleware.CreateSuccessResponse(c, activity, "", nil)
 }
 
 // Health check endpoint
@@ -494,11 +1323,13 @@ func (h *LoanHandler) Health(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Workflow ID"
 // @Param X-Language header string false "Language preference (en, vi)"
-// @Success 200 {object} middleware.SuccessResponse{data=map[string]interface{}} "Workflow status retrieved successfully"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.WorkflowStatusDetail} "Workflow status retrieved successfully"
 // @Failure 400 {object} middleware.ErrorResponse "Invalid workflow ID"
 // @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
 // @Failure 404 {object} middleware.ErrorResponse "Workflow not found"
 // @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Failure 503 {object} middleware.ErrorResponse "Workflow engine unavailable"
 // @Security BearerAuth
 // @Router /workflows/{id}/status [get]
 func (h *LoanHandler) GetWorkflowStatus(c *gin.Context) {
@@ -508,214 +1339,278 @@ func (h *LoanHandler) GetWorkflowStatus(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement real workflow status retrieval
-	// This would call the workflow service to get actual status
-	middleware.CreateErrorResponse(c, http.StatusNotImplemented, domain.LOAN_023, map[string]interface{}{
-		"message": "Workflow status retrieval not implemented - workflow service required",
-	})
+	logger := h.logger.With(
+		zap.String("workflow_id", workflowID),
+		zap.String("operation", "get_workflow_status"),
+	)
+
+	status, err := h.loanService.GetWorkflowStatus(c.Request.Context(), workflowID)
+	if err != nil {
+		if loanErr, ok := err.(*domain.LoanError); ok {
+			logger.Warn("Failed to get workflow status",
+				zap.String("error_code", loanErr.Code),
+				zap.Error(err))
+			middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+			return
+		}
+
+		logger.Error("Unexpected error getting workflow status", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+		return
+	}
 
+	middleware.CreateSuccessResponse(c, status, "", nil)
 }
 
 // PauseWorkflow pauses a running workflow
 // @Summary Pause workflow
-// @Description Pause a running workflow execution
+// @Description Pause a running workflow execution in Conductor and record who paused it and why
 // @Tags Workflows
 // @Accept json
 // @Produce json
 // @Param id path string true "Workflow ID"
+// @Param request body domain.WorkflowActionRequest false "Why the workflow is paused"
 // @Param X-Language header string false "Language preference (en, vi)"
-// @Success 200 {object} middleware.SuccessResponse{data=map[string]interface{}} "Workflow paused successfully"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.WorkflowAction} "Workflow paused successfully"
 // @Failure 400 {object} middleware.ErrorResponse "Invalid workflow ID"
 // @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
 // @Failure 404 {object} middleware.ErrorResponse "Workflow not found"
+// @Failure 409 {object} middleware.ErrorResponse "Workflow cannot be paused in its current state"
 // @Failure 500 {object} middleware.ErrorResponse "Internal server error"
 // @Security BearerAuth
 // @Router /workflows/{id}/pause [post]
 func (h *LoanHandler) PauseWorkflow(c *gin.Context) {
-	workflowID := c.Param("id")
-	if workflowID == "" {
-		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
-		return
-	}
-
-	logger := h.logger.With(
-		zap.String("workflow_id", workflowID),
-		zap.String("operation", "pause_workflow"),
-	)
-
-	logger.Info("Workflow pause requested")
-
-	// For now, just return success
-	// In a real implementation, this would call the workflow service
-	middleware.CreateSuccessResponse(c, gin.H{"status": "paused"}, "WORKFLOW_PAUSED", nil)
+	h.performWorkflowAction(c, "pause_workflow", "WORKFLOW_PAUSED", h.loanService.PauseWorkflow)
 }
 
 // ResumeWorkflow resumes a paused workflow
 // @Summary Resume workflow
-// @Description Resume a paused workflow execution
+// @Description Resume a paused workflow execution in Conductor and record who resumed it
 // @Tags Workflows
 // @Accept json
 // @Produce json
 // @Param id path string true "Workflow ID"
+// @Param request body domain.WorkflowActionRequest false "Why the workflow is resumed"
 // @Param X-Language header string false "Language preference (en, vi)"
-// @Success 200 {object} middleware.SuccessResponse{data=map[string]interface{}} "Workflow resumed successfully"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.WorkflowAction} "Workflow resumed successfully"
 // @Failure 400 {object} middleware.ErrorResponse "Invalid workflow ID"
 // @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
 // @Failure 404 {object} middleware.ErrorResponse "Workflow not found"
+// @Failure 409 {object} middleware.ErrorResponse "Workflow cannot be resumed in its current state"
 // @Failure 500 {object} middleware.ErrorResponse "Internal server error"
 // @Security BearerAuth
 // @Router /workflows/{id}/resume [post]
 func (h *LoanHandler) ResumeWorkflow(c *gin.Context) {
-	workflowID := c.Param("id")
-	if workflowID == "" {
-		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
-		return
-	}
-
-	logger := h.logger.With(
-		zap.String("workflow_id", workflowID),
-		zap.String("operation", "resume_workflow"),
-	)
-
-	logger.Info("Workflow resume requested")
-
-	// For now, just return success
-	// In a real implementation, this would call the workflow service
-	middleware.CreateSuccessResponse(c, gin.H{"status": "resumed"}, "WORKFLOW_RESUMED", nil)
+	h.performWorkflowAction(c, "resume_workflow", "WORKFLOW_RESUMED", h.loanService.ResumeWorkflow)
 }
 
 // TerminateWorkflow terminates a running workflow
 // @Summary Terminate workflow
-// @Description Terminate a running workflow execution
+// @Description Terminate a workflow execution in Conductor (managers and admins only), record who terminated it and why, and move an application left in underwriting to manual review
 // @Tags Workflows
 // @Accept json
 // @Produce json
 // @Param id path string true "Workflow ID"
-// @Param reason body map[string]string true "Termination reason"
+// @Param request body domain.WorkflowActionRequest true "Why the workflow is terminated"
 // @Param X-Language header string false "Language preference (en, vi)"
-// @Success 200 {object} middleware.SuccessResponse{data=map[string]interface{}} "Workflow terminated successfully"
-// @Failure 400 {object} middleware.ErrorResponse "Invalid workflow ID"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.WorkflowAction} "Workflow terminated successfully"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid workflow ID or missing reason"
 // @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
 // @Failure 404 {object} middleware.ErrorResponse "Workflow not found"
+// @Failure 409 {object} middleware.ErrorResponse "Workflow cannot be terminated in its current state"
 // @Failure 500 {object} middleware.ErrorResponse "Internal server error"
 // @Security BearerAuth
 // @Router /workflows/{id}/terminate [post]
 func (h *LoanHandler) TerminateWorkflow(c *gin.Context) {
+	h.performWorkflowAction(c, "terminate_workflow", "WORKFLOW_TERMINATED", h.loanService.TerminateWorkflow)
+}
+
+// performWorkflowAction binds a pause, resume or terminate request and runs it for the caller
+func (h *LoanHandler) performWorkflowAction(c *gin.Context, operation, successKey string, perform func(ctx context.Context, workflowID, performedBy, reason string) (*domain.WorkflowAction, error)) {
 	workflowID := c.Param("id")
 	if workflowID == "" {
 		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
 		return
 	}
 
-	var req map[string]string
-	if err := c.ShouldBindJSON(&req); err != nil {
+	logger := h.logger.With(
+		zap.String("workflow_id", workflowID),
+		zap.String("operation", operation),
+	)
+
+	// The body is optional for pause and resume
+	var req domain.WorkflowActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		logger.Warn("Invalid request format", zap.Error(err))
 		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
 		return
 	}
 
-	reason := req["reason"]
-	if reason == "" {
-		reason = "User requested termination"
+	action, err := perform(c.Request.Context(), workflowID, c.GetString("user_id"), req.Reason)
+	if err != nil {
+		if loanErr, ok := err.(*domain.LoanError); ok {
+			logger.Warn("Workflow action failed",
+				zap.String("error_code", loanErr.Code),
+				zap.Error(err))
+			middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+			return
+		}
+
+		logger.Error("Unexpected error performing workflow action", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+		return
 	}
 
-	logger := h.logger.With(
-		zap.String("workflow_id", workflowID),
-		zap.String("reason", reason),
-		zap.String("operation", "terminate_workflow"),
-	)
+	middleware.CreateSuccessResponse(c, action, successKey, nil)
+}
 
-	logger.Info("Workflow termination requested")
-
-	// For now, just return success
-	// In a real implementation, this would call the workflow service
-	middleware.CreateSuccessResponse(c, gin.H{"status": "terminated"}, "WORKFLOW_TERMINATED", nil)
-}
-
-// DocumentUploadRequest represents a document upload request
-type DocumentUploadRequest struct {
-	ApplicationID string                 `json:"applicationId" validate:"required"`
-	UserID        string                 `json:"userId" validate:"required"`
-	DocumentType  string                 `json:"documentType" validate:"required"`
-	FileName      string                 `json:"fileName" validate:"required"`
-	FileSize      int64                  `json:"fileSize" validate:"required,min=1"`
-	ContentType   string                 `json:"contentType" validate:"required"`
-	Metadata      map[string]interface{} `json:"metadata,omitempty"`
-}
-
-// DocumentUploadResponse represents a document upload response
-type DocumentUploadResponse struct {
-	Success          bool                   `json:"success"`
-	DocumentID       string                 `json:"documentId,omitempty"`
-	UploadedAt       time.Time              `json:"uploadedAt,omitempty"`
-	ValidationStatus string                 `json:"validationStatus,omitempty"`
-	Errors           []string               `json:"errors,omitempty"`
-	Metadata         map[string]interface{} `json:"metadata,omitempty"`
-}
-
-// DocumentCollectionStatus represents the status of document collection
-type DocumentCollectionStatus struct {
-	ApplicationID         string                  `json:"applicationId"`
-	UserID                string                  `json:"userId"`
-	Status                string                  `json:"status"`
-	TotalRequired         int                     `json:"totalRequired"`
-	Collected             int                     `json:"collected"`
-	Pending               int                     `json:"pending"`
-	Documents             map[string]DocumentInfo `json:"documents"`
-	CollectionStarted     time.Time               `json:"collectionStarted"`
-	CollectionCompletedAt *time.Time              `json:"collectionCompletedAt,omitempty"`
-	ValidationErrors      map[string][]string     `json:"validationErrors,omitempty"`
-}
-
-// DocumentInfo represents information about a specific document
-type DocumentInfo struct {
-	DocumentType string                 `json:"documentType"`
-	Collected    bool                   `json:"collected"`
-	Validated    bool                   `json:"validated"`
-	FileName     string                 `json:"fileName,omitempty"`
-	FileSize     int64                  `json:"fileSize,omitempty"`
-	UploadedAt   *time.Time             `json:"uploadedAt,omitempty"`
-	ValidatedAt  *time.Time             `json:"validatedAt,omitempty"`
-	Errors       []string               `json:"errors,omitempty"`
-	Metadata     map[string]interface{} `json:"metadata,omitempty"`
-}
-
-// UploadDocument handles document upload for loan applications
+// UploadDocument uploads a borrower document for a loan application
 // @Summary Upload a document for a loan application
-// @Description Upload a document file for a specific loan application
+// @Description Upload a borrower document as multipart/form-data. The file is streamed to the user service for storage and recorded against the outstanding checklist requirement it satisfies; once every requirement is received the application moves to documents_submitted.
 // @Tags Documents
-// @Accept json
+// @Accept multipart/form-data
 // @Produce json
-// @Param document body DocumentUploadRequest true "Document upload details"
+// @Param id path string true "Application ID"
+// @Param document_type formData string true "Document type, e.g. pay_stub, w2, bank_statement, drivers_license; sent before the file"
+// @Param requirement formData string false "Checklist document type the file is uploaded for, e.g. income_verification; sent before the file"
+// @Param file formData file true "Document file (PDF, JPEG or PNG, at most 10 MB)"
 // @Param X-Language header string false "Language preference (en, vi)"
-// @Success 200 {object} DocumentUploadResponse "Document uploaded successfully"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.DocumentUploadResult} "Document uploaded successfully"
 // @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
 // @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 409 {object} middleware.ErrorResponse "Application no longer collects documents"
+// @Failure 413 {object} middleware.ErrorResponse "Document too large"
+// @Failure 415 {object} middleware.ErrorResponse "Unsupported document format"
+// @Failure 422 {object} middleware.ErrorResponse "Document not required by the checklist"
 // @Failure 500 {object} middleware.ErrorResponse "Internal server error"
 // @Security BearerAuth
-// @Router /loans/documents/upload [post]
+// @Router /loans/applications/{id}/documents [post]
 func (h *LoanHandler) UploadDocument(c *gin.Context) {
 	logger := h.logger.With(zap.String("operation", "upload_document"))
 
-	// TODO: Implement document upload workflow initiation
-	// For now, return a placeholder response
-	logger.Info("Document upload workflow initiated")
+	applicationID := c.Param("id")
+	if applicationID == "" {
+		logger.Warn("Missing application ID")
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
 
-	middleware.CreateSuccessResponse(c, gin.H{
-		"message": "Document upload workflow initiated",
-		"status":  "pending",
-	}, "DOCUMENT_UPLOAD_SUCCESS", nil)
+	upload, ok := bindDocumentUpload(c, logger)
+	if !ok {
+		return
+	}
+
+	result, err := h.loanService.UploadDocument(c.Request.Context(), applicationID, c.GetString("user_id"), upload)
+	if err != nil {
+		h.respondChecklistError(c, logger, applicationID, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, result, "DOCUMENT_UPLOAD_SUCCESS", nil)
+}
+
+// maxDocumentFormField is the largest form field value accepted alongside a document upload
+const maxDocumentFormField = 256
+
+// bindDocumentUpload reads a multipart document upload with its user service document type,
+// streaming the file part rather than buffering it. The document_type and requirement fields must
+// come before the file part. On failure the error response has been written; otherwise the upload
+// content must be consumed before the handler returns.
+func bindDocumentUpload(c *gin.Context, logger *zap.Logger) (*domain.DocumentUpload, bool) {
+	// Leave room for the form fields around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, domain.MaxDocumentSize+1<<20)
+
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		logger.Warn("Document upload is not a multipart form", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return nil, false
+	}
+
+	upload := &domain.DocumentUpload{}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			respondDocumentReadError(c, logger, err)
+			return nil, false
+		}
+
+		switch part.FormName() {
+		case "document_type", "requirement":
+			value, err := io.ReadAll(io.LimitReader(part, maxDocumentFormField+1))
+			if err != nil || len(value) > maxDocumentFormField {
+				logger.Warn("Invalid document form field", zap.String("field", part.FormName()), zap.Error(err))
+				middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+				return nil, false
+			}
+			if part.FormName() == "document_type" {
+				upload.DocumentType = string(value)
+			} else {
+				upload.Requirement = string(value)
+			}
+			continue
+		case "file":
+		default:
+			continue
+		}
+
+		if upload.DocumentType == "" {
+			logger.Warn("Missing document type before the file")
+			middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+			return nil, false
+		}
+
+		// Detect the format from the content rather than trusting the client
+		head := make([]byte, 512)
+		n, err := io.ReadFull(part, head)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			respondDocumentReadError(c, logger, err)
+			return nil, false
+		}
+		if n == 0 {
+			logger.Warn("Empty document file")
+			middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+			return nil, false
+		}
+		mimeType, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+
+		upload.FileName = filepath.Base(part.FileName())
+		upload.MimeType = mimeType
+		upload.Content = domain.LimitDocumentContent(io.MultiReader(bytes.NewReader(head[:n]), part))
+		return upload, true
+	}
+}
+
+// respondDocumentReadError writes the error response for a document upload that could not be read
+func respondDocumentReadError(c *gin.Context, logger *zap.Logger, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		logger.Warn("Document upload too large")
+		middleware.CreateErrorResponse(c, http.StatusRequestEntityTooLarge, domain.LOAN_037, nil)
+		return
+	}
+	if err == io.EOF {
+		logger.Warn("Missing document file")
+	} else {
+		logger.Warn("Failed to read document upload", zap.Error(err))
+	}
+	middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
 }
 
 // GetDocumentCollectionStatus retrieves the status of document collection for an application
 // @Summary Get document collection status
-// @Description Retrieve the current status of document collection for a loan application
+// @Description Retrieve the application's document checklist, evaluated from the checklist rules and any underwriting conditions, with the collection status of each required document
 // @Tags Documents
 // @Accept json
 // @Produce json
 // @Param id path string true "Application ID"
-// @Param userId query string true "User ID"
 // @Param X-Language header string false "Language preference (en, vi)"
-// @Success 200 {object} DocumentCollectionStatus "Document collection status retrieved successfully"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.DocumentChecklistStatus} "Document collection status retrieved successfully"
 // @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
 // @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
 // @Failure 404 {object} middleware.ErrorResponse "Application not found"
@@ -727,41 +1622,69 @@ func (h *LoanHandler) GetDocumentCollectionStatus(c *gin.Context) {
 
 	applicationID := c.Param("id")
 	if applicationID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Application ID is required",
-		})
+		logger.Warn("Missing application ID")
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
 		return
 	}
 
-	userID := c.Query("userId")
-	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "User ID is required",
-		})
+	status, err := h.loanService.GetDocumentChecklistStatus(c.Request.Context(), applicationID)
+	if err != nil {
+		h.respondChecklistError(c, logger, applicationID, err)
 		return
 	}
 
-	logger.Info("Retrieving document collection status",
-		zap.String("application_id", applicationID),
-		zap.String("user_id", userID))
+	middleware.CreateSuccessResponse(c, status, "DOCUMENT_STATUS_RETRIEVED", nil)
+}
 
-	// TODO: Implement document collection status retrieval
-	// For now, return a placeholder response
-	logger.Info("Document collection status requested",
-		zap.String("application_id", applicationID),
-		zap.String("user_id", userID))
+// AddUnderwritingCondition places an underwriting condition on an application (staff endpoint)
+// @Summary Add an underwriting condition
+// @Description Place an underwriting condition on an application. The document types it names, and those required by checklist rules for its condition type, are added to the application's document checklist.
+// @Tags Documents
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param request body domain.AddConditionRequest true "Underwriting condition"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.DocumentChecklistStatus} "Condition added; the updated checklist is returned"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/conditions [post]
+func (h *LoanHandler) AddUnderwritingCondition(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "add_underwriting_condition"))
 
-	// Return placeholder status
-	placeholderStatus := gin.H{
-		"application_id": applicationID,
-		"user_id":        userID,
-		"status":         "pending",
-		"total_required": 3,
-		"collected":      0,
-		"pending":        3,
+	var req domain.AddConditionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	applicationID := c.Param("id")
+	status, err := h.loanService.AddUnderwritingCondition(c.Request.Context(), applicationID, &req)
+	if err != nil {
+		h.respondChecklistError(c, logger, applicationID, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, status, "CONDITION_ADDED", nil)
+}
+
+// respondChecklistError writes the error response for a failed document checklist request
+func (h *LoanHandler) respondChecklistError(c *gin.Context, logger *zap.Logger, applicationID string, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Document checklist request failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("application_id", applicationID),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
 	}
 
-	middleware.CreateSuccessResponse(c, placeholderStatus, "DOCUMENT_STATUS_RETRIEVED", nil)
+	logger.Error("Unexpected document checklist error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
 }
 
 // CompleteDocumentCollection marks document collection as completed
@@ -885,44 +1808,73 @@ func getRequestBody(c *gin.Context) string {
 	return string(body)
 }
 
-// RegisterRoutes registers all loan service routes
-func (h *LoanHandler) RegisterRoutes(router *gin.RouterGroup) {
-	// Public routes
-	router.GET("/health", h.Health)
-
+// RegisterRoutes registers all loan service routes. The idempotency middleware guards application
+// creation so clients can safely retry it with an Idempotency-Key header.
+func (h *LoanHandler) RegisterRoutes(router *gin.RouterGroup, idempotency gin.HandlerFunc) {
 	// Protected routes (require authentication)
 	loans := router.Group("/loans")
 	{
 		// Application management
-		loans.POST("/applications", h.CreateApplication)
+		loans.POST("/applications", idempotency, h.CreateApplication)
 		loans.GET("/applications", h.GetApplicationsByUser)
 		loans.GET("/applications/:id", h.GetApplication)
 		loans.PUT("/applications/:id", h.UpdateApplication)
 		loans.POST("/applications/:id/submit", h.SubmitApplication)
-
-		// Pre-qualification
-		loans.POST("/prequalify", h.PreQualify)
+		loans.POST("/applications/:id/withdraw", h.WithdrawApplication)
+		loans.GET("/applications/:id/timeline", h.GetTimeline)
 
 		// Offers
 		loans.POST("/applications/:id/offer", h.GenerateOffer)
+		loans.GET("/applications/:id/offer", h.GetOffer)
+		loans.GET("/applications/:id/offers", h.ListOffers)
+		loans.GET("/applications/:id/offers/compare", h.CompareOffers)
+		loans.POST("/applications/:id/offers/:offer_id/select", h.SelectOffer)
+		loans.POST("/applications/:id/offers/:offer_id/decline", h.DeclineOffer)
+		loans.POST("/applications/:id/offers/:offer_id/counter", h.RequestCounterOffer)
+		loans.POST("/applications/:id/offers/:offer_id/respond", h.RespondToCounterOffer)
+		loans.GET("/applications/:id/negotiation", h.GetNegotiation)
 		loans.POST("/applications/:id/accept-offer", h.AcceptOffer)
 
-		// Admin endpoints (would typically require admin role)
-		loans.POST("/applications/:id/transition", h.TransitionState)
-		loans.GET("/stats", h.GetApplicationStats)
+		// Notes shared with the borrower
+		loans.GET("/applications/:id/notes", h.ListNotes)
+
+		// Admin endpoints (require a back-office role)
+		admin := loans.Group("", middleware.RequireStaff())
+		admin.GET("/applications/all", h.GetAllApplications)
+		admin.POST("/applications/:id/transition", h.TransitionState)
+		admin.POST("/applications/bulk-transition", h.BulkTransition)
+		admin.POST("/applications/:id/notes", h.AddNote)
+		admin.GET("/applications/:id/notes/all", h.ListAllNotes)
+		admin.GET("/applications/:id/timeline/all", h.GetFullTimeline)
+		admin.POST("/applications/:id/conditions", h.AddUnderwritingCondition)
+		admin.GET("/stats", h.GetApplicationStats)
 
 		// Document management
-		loans.POST("/documents/upload", h.UploadDocument)
+		loans.POST("/applications/:id/documents", h.UploadDocument)
 		loans.GET("/applications/:id/documents/status", h.GetDocumentCollectionStatus)
 		loans.POST("/applications/:id/documents/complete", h.CompleteDocumentCollection)
 	}
 
-	// Workflow management routes
-	workflows := router.Group("/workflows")
+	// Workflow management routes (require a back-office role)
+	workflows := router.Group("/workflows", middleware.RequireStaff())
 	{
 		workflows.GET("/:id/status", h.GetWorkflowStatus)
 		workflows.POST("/:id/pause", h.PauseWorkflow)
 		workflows.POST("/:id/resume", h.ResumeWorkflow)
-		workflows.POST("/:id/terminate", h.TerminateWorkflow)
+		workflows.POST("/:id/terminate", middleware.RequireRoles("manager", "admin", "super_admin"), h.TerminateWorkflow)
 	}
 }
+
+// RegisterPublicRoutes registers routes served without authentication
+func (h *LoanHandler) RegisterPublicRoutes(router *gin.RouterGroup) {
+	router.GET("/health", h.Health)
+}
+
+// RegisterInternalRoutes registers service-to-service routes guarded by the internal service token
+func (h *LoanHandler) RegisterInternalRoutes(router *gin.RouterGroup, serviceToken string) {
+	router.Use(middleware.RequireServiceToken(serviceToken))
+	router.POST("/applications/reassign", h.ReassignApplications)
+	router.POST("/applications/:id/derogatory-information", h.ReportDerogatoryInformation)
+	router.POST("/applications/:id/compensations", h.CompensateWorkflow)
+	router.GET("/users/:user_id/loan-activity", h.GetUserLoanActivity)
+}
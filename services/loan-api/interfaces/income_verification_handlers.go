@@ -0,0 +1,113 @@
+package interfaces
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// IncomeVerificationHandler handles payroll income verification requests and provider callbacks
+type IncomeVerificationHandler struct {
+	incomeVerificationService *application.IncomeVerificationService
+	logger                    *zap.Logger
+}
+
+// NewIncomeVerificationHandler creates a new income verification handler
+func NewIncomeVerificationHandler(incomeVerificationService *application.IncomeVerificationService, logger *zap.Logger) *IncomeVerificationHandler {
+	return &IncomeVerificationHandler{
+		incomeVerificationService: incomeVerificationService,
+		logger:                    logger,
+	}
+}
+
+// GetIncomeVerification retrieves the income verification of an application
+// @Summary Get income verification
+// @Description Retrieve the income verification of an application. While a payroll verification is pending, the response carries the connect token the borrower links their payroll account with; once their pay data syncs, it carries the verified income and its variance from the stated income.
+// @Tags Income Verification
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.IncomeVerification} "Income verification retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Income verification not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/income-verification [get]
+func (h *IncomeVerificationHandler) GetIncomeVerification(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_income_verification"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	verification, err := h.incomeVerificationService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, verification, "", nil)
+}
+
+// HandleWebhook receives pay data notifications from the payroll provider
+// @Summary Payroll provider webhook
+// @Description Receive notifications from the payroll provider. Requests are authenticated by the provider's HMAC signature. When a borrower's paystubs have synced, their pending income verification is completed with the income computed from them.
+// @Tags Income Verification
+// @Accept json
+// @Produce json
+// @Success 200 {object} middleware.SuccessResponse "Notification processed"
+// @Failure 401 {object} middleware.ErrorResponse "Invalid signature"
+// @Failure 500 {object} middleware.ErrorResponse "Processing failed; the provider retries"
+// @Failure 503 {object} middleware.ErrorResponse "Payroll income verification unavailable"
+// @Router /webhooks/payroll [post]
+func (h *IncomeVerificationHandler) HandleWebhook(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "handle_payroll_webhook"))
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookSize))
+	if err != nil {
+		logger.Warn("Failed to read webhook body", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	if err := h.incomeVerificationService.HandleWebhook(c.Request.Context(), body, c.GetHeader); err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, gin.H{"received": true}, "", nil)
+}
+
+// respondError writes the error response for a failed income verification request
+func (h *IncomeVerificationHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Income verification request failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected income verification error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the income verification routes
+func (h *IncomeVerificationHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		loans.GET("/applications/:id/income-verification", h.GetIncomeVerification)
+	}
+}
+
+// RegisterWebhookRoutes registers the payroll provider callback routes
+func (h *IncomeVerificationHandler) RegisterWebhookRoutes(router *gin.RouterGroup) {
+	router.POST("/payroll", h.HandleWebhook)
+}
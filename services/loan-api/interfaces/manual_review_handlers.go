@@ -0,0 +1,310 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// reviewManagerRoles may complete reviews claimed by another underwriter
+var reviewManagerRoles = map[string]bool{"manager": true, "admin": true, "super_admin": true}
+
+// ManualReviewHandler handles the underwriters' manual review queue
+type ManualReviewHandler struct {
+	manualReviewService *application.ManualReviewService
+	logger              *zap.Logger
+}
+
+// NewManualReviewHandler creates a new manual review handler
+func NewManualReviewHandler(manualReviewService *application.ManualReviewService, logger *zap.Logger) *ManualReviewHandler {
+	return &ManualReviewHandler{
+		manualReviewService: manualReviewService,
+		logger:              logger,
+	}
+}
+
+// ListReviews lists the manual review queue (staff endpoint)
+// @Summary List manual reviews
+// @Description List applications the underwriting workflow routed to manual review, most urgent first: by priority, then due date
+// @Tags Manual Review
+// @Accept json
+// @Produce json
+// @Param status query string false "Status (pending, in_review, completed)"
+// @Param priority query string false "Priority (high, normal, low)"
+// @Param assigned_to query string false "Underwriter user ID"
+// @Param application_id query string false "Application ID"
+// @Param review_type query string false "Review type (primary, secondary)"
+// @Param limit query int false "Maximum reviews returned (default 50, max 100)"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=[]domain.ManualReview} "Manual reviews retrieved"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/reviews [get]
+func (h *ManualReviewHandler) ListReviews(c *gin.Context) {
+	h.listReviews(c, "list_manual_reviews", "")
+}
+
+// ListMyReviews lists the reviews claimed by the caller (staff endpoint)
+// @Summary List my manual reviews
+// @Description List the manual reviews the calling underwriter has claimed, most urgent first
+// @Tags Manual Review
+// @Accept json
+// @Produce json
+// @Param status query string false "Status (in_review, completed)"
+// @Param limit query int false "Maximum reviews returned (default 50, max 100)"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=[]domain.ManualReview} "Manual reviews retrieved"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/reviews/mine [get]
+func (h *ManualReviewHandler) ListMyReviews(c *gin.Context) {
+	h.listReviews(c, "list_my_manual_reviews", c.GetString("user_id"))
+}
+
+// listReviews binds the queue filters, restricted to an underwriter when assignedTo is set
+func (h *ManualReviewHandler) listReviews(c *gin.Context, operation, assignedTo string) {
+	logger := h.logger.With(zap.String("operation", operation))
+
+	var query domain.ManualReviewQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Warn("Invalid query parameters", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+	if assignedTo != "" {
+		query.AssignedTo = assignedTo
+	}
+
+	reviews, err := h.manualReviewService.List(c.Request.Context(), &query)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, reviews, "", nil)
+}
+
+// GetReview returns a manual review (staff endpoint)
+// @Summary Get a manual review
+// @Description Retrieve a manual review with its reason, priority, due date, assignee and decision
+// @Tags Manual Review
+// @Accept json
+// @Produce json
+// @Param id path string true "Manual review ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ManualReview} "Manual review retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Manual review not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/reviews/{id} [get]
+func (h *ManualReviewHandler) GetReview(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_manual_review"),
+		zap.String("review_id", c.Param("id")),
+	)
+
+	review, err := h.manualReviewService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, review, "", nil)
+}
+
+// ClaimReview takes a pending review for the caller (staff endpoint)
+// @Summary Claim a manual review
+// @Description Claim a pending manual review. The application is assigned to the caller, subject to the loan officer assignment rules: it must not be assigned to another officer and the caller must have capacity. A secondary review leaves the application with its first approver and cannot be claimed by them.
+// @Tags Manual Review
+// @Accept json
+// @Produce json
+// @Param id path string true "Manual review ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ManualReview} "Manual review claimed"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Manual review not found"
+// @Failure 409 {object} middleware.ErrorResponse "Review already claimed or completed, application assigned to another officer, officer at capacity, or caller made the first approval"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/reviews/{id}/claim [post]
+func (h *ManualReviewHandler) ClaimReview(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "claim_manual_review"),
+		zap.String("review_id", c.Param("id")),
+	)
+
+	review, err := h.manualReviewService.Claim(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, review, "MANUAL_REVIEW_CLAIMED", nil)
+}
+
+// CompleteReview records the decision on a claimed review (staff endpoint)
+// @Summary Complete a manual review
+// @Description Record an APPROVE, CONDITIONAL or DENY decision with notes on a claimed review and complete the underwriting workflow's waiting review task with it. An approval requires the approved amount and interest rate; a conditional approval also requires its conditions, which the borrower must clear before the loan is approved. Only the underwriter who claimed the review, or a manager, can complete it.
+// @Tags Manual Review
+// @Accept json
+// @Produce json
+// @Param id path string true "Manual review ID"
+// @Param request body domain.CompleteManualReviewRequest true "Decision and notes"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ManualReview} "Manual review completed"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Manual review or workflow not found"
+// @Failure 409 {object} middleware.ErrorResponse "Review not claimed or workflow not waiting on the review"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Failure 503 {object} middleware.ErrorResponse "Workflow engine unavailable"
+// @Security BearerAuth
+// @Router /loans/reviews/{id}/complete [post]
+func (h *ManualReviewHandler) CompleteReview(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "complete_manual_review"),
+		zap.String("review_id", c.Param("id")),
+	)
+
+	var req domain.CompleteManualReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	review, err := h.manualReviewService.Complete(c.Request.Context(), c.Param("id"), &req,
+		c.GetString("user_id"), reviewManagerRoles[c.GetString("user_role")])
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, review, "MANUAL_REVIEW_COMPLETED", nil)
+}
+
+// SignOffReview records a second underwriter's sign-off of a large approval (staff endpoint)
+// @Summary Sign off a secondary review
+// @Description Approve or deny a large approval as its second underwriter and complete the underwriting workflow's waiting secondary review task: APPROVE lets final approval go ahead and DENY denies the application. Only the underwriter who claimed the secondary review can sign it off, and never the underwriter who approved the loan.
+// @Tags Manual Review
+// @Accept json
+// @Produce json
+// @Param id path string true "Manual review ID"
+// @Param request body domain.SignOffManualReviewRequest true "Decision and notes"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ManualReview} "Secondary review signed off"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 404 {object} middleware.ErrorResponse "Manual review or workflow not found"
+// @Failure 409 {object} middleware.ErrorResponse "Not a claimed secondary review, caller made the first approval, or workflow not waiting on the review"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Failure 503 {object} middleware.ErrorResponse "Workflow engine unavailable"
+// @Security BearerAuth
+// @Router /loans/reviews/{id}/sign-off [post]
+func (h *ManualReviewHandler) SignOffReview(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "sign_off_manual_review"),
+		zap.String("review_id", c.Param("id")),
+	)
+
+	var req domain.SignOffManualReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	review, err := h.manualReviewService.SignOff(c.Request.Context(), c.Param("id"), &req, c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, review, "MANUAL_REVIEW_SIGNED_OFF", nil)
+}
+
+// RecordRedecision records a re-decision with late data on the application's open review (internal endpoint)
+// @Summary Record a re-decision
+// @Description Record the decision engine's decision on an application made again once data its provisional decision was made without arrived. The decision is noted in the reason of the application's open primary review, where provisional approvals wait for it.
+// @Tags Internal
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param request body domain.Redecision true "Re-decision"
+// @Success 200 {object} middleware.SuccessResponse "Re-decision recorded"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Router /internal/v1/applications/{id}/redecisions [post]
+func (h *ManualReviewHandler) RecordRedecision(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "record_redecision"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	var req domain.Redecision
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	if err := h.manualReviewService.RecordRedecision(c.Request.Context(), c.Param("id"), &req); err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, nil, "", nil)
+}
+
+// respondError maps service errors to error responses
+func (h *ManualReviewHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Manual review operation failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected manual review error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the manual review routes
+func (h *ManualReviewHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		// Underwriter endpoints (require a back-office role)
+		reviews := loans.Group("/reviews", middleware.RequireStaff())
+		reviews.GET("", h.ListReviews)
+		reviews.GET("/mine", h.ListMyReviews)
+		reviews.GET("/:id", h.GetReview)
+		reviews.POST("/:id/claim", h.ClaimReview)
+		reviews.POST("/:id/complete", h.CompleteReview)
+		reviews.POST("/:id/sign-off", h.SignOffReview)
+	}
+}
+
+// RegisterInternalRoutes registers the service-to-service manual review routes on a group already
+// guarded by the internal service token
+func (h *ManualReviewHandler) RegisterInternalRoutes(router *gin.RouterGroup) {
+	router.POST("/applications/:id/redecisions", h.RecordRedecision)
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireServiceToken admits internal callers presenting the shared service bearer token.
+// An empty configured token rejects every call so internal routes are closed by default.
+func RequireServiceToken(serviceToken string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if serviceToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(serviceToken)) != 1 {
+			CreateErrorResponse(c, http.StatusUnauthorized, "LOAN_022", nil)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,175 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// PreQualificationHandler handles pre-qualification requests and their conversion into applications
+type PreQualificationHandler struct {
+	preQualificationService *application.PreQualificationService
+	logger                  *zap.Logger
+}
+
+// NewPreQualificationHandler creates a new pre-qualification handler
+func NewPreQualificationHandler(preQualificationService *application.PreQualificationService, logger *zap.Logger) *PreQualificationHandler {
+	return &PreQualificationHandler{
+		preQualificationService: preQualificationService,
+		logger:                  logger,
+	}
+}
+
+// PreQualify performs pre-qualification check
+// @Summary Perform loan pre-qualification
+// @Description Check if a user qualifies for a loan based on income, debt, and other factors, without a credit pull. The result is kept until it expires and can be converted into a draft application while it is qualified.
+// @Tags Pre-qualification
+// @Accept json
+// @Produce json
+// @Param request body domain.PreQualifyRequest true "Pre-qualification request"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.PreQualifyResult} "Pre-qualification completed"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/prequalify [post]
+func (h *PreQualificationHandler) PreQualify(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "pre_qualify"),
+	)
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		logger.Error("User ID not found in context")
+		middleware.CreateErrorResponse(c, http.StatusUnauthorized, domain.LOAN_022, nil)
+		return
+	}
+
+	var req domain.PreQualifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	result, err := h.preQualificationService.PreQualify(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	messageKey := "PRE_QUALIFICATION_SUCCESS"
+	if !result.Qualified {
+		messageKey = "PRE_QUALIFICATION_NOT_QUALIFIED"
+	}
+	middleware.CreateSuccessResponse(c, result, messageKey, nil)
+}
+
+// GetPreQualification retrieves a pre-qualification result
+// @Summary Get pre-qualification
+// @Description Retrieve a pre-qualification result, when it expires and the application it was converted into, if any
+// @Tags Pre-qualification
+// @Accept json
+// @Produce json
+// @Param id path string true "Pre-qualification ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.PreQualifyResult} "Pre-qualification retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Pre-qualification belongs to another user"
+// @Failure 404 {object} middleware.ErrorResponse "Pre-qualification not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/prequalifications/{id} [get]
+func (h *PreQualificationHandler) GetPreQualification(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_prequalification"),
+		zap.String("prequalification_id", c.Param("id")),
+	)
+
+	result, err := h.preQualificationService.GetPreQualification(c.Request.Context(), c.Param("id"), middleware.OwnerScope(c))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, result, "", nil)
+}
+
+// ConvertPreQualification starts a draft application from a pre-qualification
+// @Summary Convert pre-qualification into an application
+// @Description Start a draft application from a qualified, unexpired pre-qualification, carrying over the borrower's income, debts and employment status. The loan amount defaults to the pre-qualified amount and the term must be one of the recommended terms. Converting again returns the same application.
+// @Tags Pre-qualification
+// @Accept json
+// @Produce json
+// @Param id path string true "Pre-qualification ID"
+// @Param request body domain.ConvertPreQualificationRequest true "Application details"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.LoanApplication} "Draft application created"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request data, amount or term"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Pre-qualification belongs to another user"
+// @Failure 404 {object} middleware.ErrorResponse "Pre-qualification not found"
+// @Failure 409 {object} middleware.ErrorResponse "Borrower did not pre-qualify"
+// @Failure 410 {object} middleware.ErrorResponse "Pre-qualification expired"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/prequalifications/{id}/convert [post]
+func (h *PreQualificationHandler) ConvertPreQualification(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "convert_prequalification"),
+		zap.String("prequalification_id", c.Param("id")),
+	)
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		logger.Error("User ID not found in context")
+		middleware.CreateErrorResponse(c, http.StatusUnauthorized, domain.LOAN_022, nil)
+		return
+	}
+
+	var req domain.ConvertPreQualificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	app, err := h.preQualificationService.ConvertToApplication(c.Request.Context(), c.Param("id"), userID, &req)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, app, "PRE_QUALIFICATION_CONVERTED", nil)
+}
+
+// respondError writes the error response for a failed pre-qualification request
+func (h *PreQualificationHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Pre-qualification request failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected pre-qualification error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the pre-qualification routes
+func (h *PreQualificationHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		loans.POST("/prequalify", h.PreQualify)
+		loans.GET("/prequalifications/:id", h.GetPreQualification)
+		loans.POST("/prequalifications/:id/convert", h.ConvertPreQualification)
+	}
+}
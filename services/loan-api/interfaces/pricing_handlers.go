@@ -0,0 +1,225 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// PricingHandler handles the admin API for pricing rate matrices
+type PricingHandler struct {
+	pricingService *application.PricingService
+	logger         *zap.Logger
+}
+
+// NewPricingHandler creates a new pricing handler
+func NewPricingHandler(pricingService *application.PricingService, logger *zap.Logger) *PricingHandler {
+	return &PricingHandler{
+		pricingService: pricingService,
+		logger:         logger,
+	}
+}
+
+// ListRates lists rate matrix entries
+// @Summary List pricing rates
+// @Description List rate matrix entries, optionally filtered by product, credit tier, risk level and the date they are in effect
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Param product query string false "Product"
+// @Param credit_tier query string false "Credit tier (excellent, good, fair, poor)"
+// @Param risk_level query string false "Risk level (LOW, MEDIUM, HIGH)"
+// @Param active_at query string false "Only rates in effect on this date (YYYY-MM-DD)"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=[]domain.PricingRate} "Rates retrieved"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid query parameters"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /pricing/rates [get]
+func (h *PricingHandler) ListRates(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "list_pricing_rates"),
+	)
+
+	var query domain.PricingRateQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Warn("Invalid query parameters", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	rates, err := h.pricingService.ListRates(c.Request.Context(), &query)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, rates, "", nil)
+}
+
+// GetRate retrieves a rate matrix entry
+// @Summary Get a pricing rate
+// @Tags Pricing
+// @Produce json
+// @Param id path string true "Rate ID"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.PricingRate} "Rate retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Rate not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /pricing/rates/{id} [get]
+func (h *PricingHandler) GetRate(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_pricing_rate"),
+		zap.String("rate_id", c.Param("id")),
+	)
+
+	rate, err := h.pricingService.GetRate(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, rate, "", nil)
+}
+
+// CreateRate adds a rate matrix entry
+// @Summary Create a pricing rate
+// @Description Add a rate for a product, credit tier, risk level and term range from an effective date
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Param request body domain.CreatePricingRateRequest true "Rate details"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.PricingRate} "Rate created"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Requires a manager or admin role"
+// @Failure 409 {object} middleware.ErrorResponse "Rate overlaps an existing rate"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /pricing/rates [post]
+func (h *PricingHandler) CreateRate(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "create_pricing_rate"),
+	)
+
+	var req domain.CreatePricingRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, map[string]interface{}{
+			"field_errors": getFieldErrors(err),
+		})
+		return
+	}
+
+	rate, err := h.pricingService.CreateRate(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, rate, "PRICING_RATE_CREATED", nil)
+}
+
+// UpdateRate changes a rate matrix entry
+// @Summary Update a pricing rate
+// @Description Change a rate that has not taken effect, or set the end date of one that has
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Param id path string true "Rate ID"
+// @Param request body domain.UpdatePricingRateRequest true "Fields to change"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.PricingRate} "Rate updated"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request data"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Requires a manager or admin role"
+// @Failure 404 {object} middleware.ErrorResponse "Rate not found"
+// @Failure 409 {object} middleware.ErrorResponse "Rate is in effect or overlaps an existing rate"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /pricing/rates/{id} [put]
+func (h *PricingHandler) UpdateRate(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "update_pricing_rate"),
+		zap.String("rate_id", c.Param("id")),
+	)
+
+	var req domain.UpdatePricingRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	rate, err := h.pricingService.UpdateRate(c.Request.Context(), c.GetString("user_id"), c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, rate, "PRICING_RATE_UPDATED", nil)
+}
+
+// DeleteRate removes a rate matrix entry that has not taken effect
+// @Summary Delete a pricing rate
+// @Tags Pricing
+// @Produce json
+// @Param id path string true "Rate ID"
+// @Success 200 {object} middleware.SuccessResponse "Rate deleted"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Requires a manager or admin role"
+// @Failure 404 {object} middleware.ErrorResponse "Rate not found"
+// @Failure 409 {object} middleware.ErrorResponse "Rate is already in effect"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /pricing/rates/{id} [delete]
+func (h *PricingHandler) DeleteRate(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "delete_pricing_rate"),
+		zap.String("rate_id", c.Param("id")),
+	)
+
+	if err := h.pricingService.DeleteRate(c.Request.Context(), c.GetString("user_id"), c.Param("id")); err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, gin.H{"id": c.Param("id")}, "PRICING_RATE_DELETED", nil)
+}
+
+func (h *PricingHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Pricing request failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected pricing error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the pricing routes; changing rates requires a manager or admin role
+func (h *PricingHandler) RegisterRoutes(router *gin.RouterGroup) {
+	pricing := router.Group("/pricing")
+	{
+		pricing.GET("/rates", h.ListRates)
+		pricing.GET("/rates/:id", h.GetRate)
+
+		// Admin endpoints; rates price every applicant, so reviewers cannot change them
+		admin := pricing.Group("", middleware.RequireRoles("manager", "admin", "super_admin"))
+		admin.POST("/rates", h.CreateRate)
+		admin.PUT("/rates/:id", h.UpdateRate)
+		admin.DELETE("/rates/:id", h.DeleteRate)
+	}
+}
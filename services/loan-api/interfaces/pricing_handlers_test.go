@@ -0,0 +1,59 @@
+package interfaces
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+)
+
+// emptyPricingRepository holds no rates
+type emptyPricingRepository struct {
+	application.PricingRepository
+}
+
+func (emptyPricingRepository) GetRate(ctx context.Context, id string) (*domain.PricingRate, error) {
+	return nil, fmt.Errorf("pricing rate not found: %s", id)
+}
+
+func TestPricingHandler_RateChangesRequireManagerOrAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		role       string
+		wantStatus int
+	}{
+		{role: "borrower", wantStatus: http.StatusForbidden},
+		{role: "junior_reviewer", wantStatus: http.StatusForbidden},
+		{role: "senior_reviewer", wantStatus: http.StatusForbidden},
+		{role: "manager", wantStatus: http.StatusNotFound},
+		{role: "admin", wantStatus: http.StatusNotFound},
+		{role: "super_admin", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			router := gin.New()
+			api := router.Group("/v1", func(c *gin.Context) {
+				c.Set("user_id", "user-1")
+				c.Set("user_role", tt.role)
+			})
+			service := application.NewPricingService(emptyPricingRepository{}, nil, zap.NewNop())
+			NewPricingHandler(service, zap.NewNop()).RegisterRoutes(api)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/v1/pricing/rates/rate-1", nil))
+
+			// Callers allowed past the role check reach the service, which has no such rate
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// RateLockHandler handles repricing offers whose rate lock has expired
+type RateLockHandler struct {
+	rateLockService *application.RateLockService
+	logger          *zap.Logger
+}
+
+// NewRateLockHandler creates a new rate lock handler
+func NewRateLockHandler(rateLockService *application.RateLockService, logger *zap.Logger) *RateLockHandler {
+	return &RateLockHandler{
+		rateLockService: rateLockService,
+		logger:          logger,
+	}
+}
+
+// RepriceOffer prices an offer again at current rates once its rate lock has expired
+// @Summary Reprice an offer
+// @Description Price a pending offer again at the rates in effect now and lock the new rate for the product's lock window. Required before selecting an offer whose rate lock expired; an offer whose rate is still locked is returned unchanged. Counter offers cannot be repriced.
+// @Tags Offers
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param offer_id path string true "Offer ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.LoanOffer} "Offer repriced"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Offer not found"
+// @Failure 409 {object} middleware.ErrorResponse "Offer is not pending or is a counter offer"
+// @Failure 410 {object} middleware.ErrorResponse "Offer expired"
+// @Failure 422 {object} middleware.ErrorResponse "No pricing rate available"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/offers/{offer_id}/reprice [post]
+func (h *RateLockHandler) RepriceOffer(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "reprice_offer"),
+		zap.String("application_id", c.Param("id")),
+		zap.String("offer_id", c.Param("offer_id")),
+	)
+
+	offer, err := h.rateLockService.RepriceOffer(c.Request.Context(), c.Param("id"), c.Param("offer_id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, offer, "OFFER_REPRICED", nil)
+}
+
+// respondError writes the error response for a failed repricing request
+func (h *RateLockHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Offer repricing failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected offer repricing error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the rate lock routes
+func (h *RateLockHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		loans.POST("/applications/:id/offers/:offer_id/reprice", h.RepriceOffer)
+	}
+}
@@ -0,0 +1,80 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// RefinanceHandler handles payoff quotes for loans borrowers may refinance
+type RefinanceHandler struct {
+	refinanceService *application.RefinanceService
+	logger           *zap.Logger
+}
+
+// NewRefinanceHandler creates a new refinance handler
+func NewRefinanceHandler(refinanceService *application.RefinanceService, logger *zap.Logger) *RefinanceHandler {
+	return &RefinanceHandler{
+		refinanceService: refinanceService,
+		logger:           logger,
+	}
+}
+
+// GetPayoffQuote quotes the payoff of a funded loan
+// @Summary Get payoff quote
+// @Description Quote a funded loan's current principal balance and the amount that pays it off through the quote's good-through date, with the payments and interest still scheduled on it. Installments due by now are taken as paid. A refinance application's loan amount must cover this payoff.
+// @Tags Refinance
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID of the funded loan"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.PayoffQuote} "Payoff quoted"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Loan belongs to another user"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 409 {object} middleware.ErrorResponse "Loan is not funded or has no balance left"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/payoff [get]
+func (h *RefinanceHandler) GetPayoffQuote(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_payoff_quote"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	quote, err := h.refinanceService.GetPayoffQuote(c.Request.Context(), c.Param("id"), middleware.OwnerScope(c))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, quote, "", nil)
+}
+
+// respondError writes the error response for a failed payoff quote request
+func (h *RefinanceHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Payoff quote request failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected payoff quote error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the refinance routes
+func (h *RefinanceHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		loans.GET("/applications/:id/payoff", h.GetPayoffQuote)
+	}
+}
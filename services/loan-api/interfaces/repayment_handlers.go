@@ -0,0 +1,113 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// RepaymentHandler handles requests for funded loans' repayment schedules
+type RepaymentHandler struct {
+	repaymentService *application.RepaymentScheduleService
+	logger           *zap.Logger
+}
+
+// NewRepaymentHandler creates a new repayment handler
+func NewRepaymentHandler(repaymentService *application.RepaymentScheduleService, logger *zap.Logger) *RepaymentHandler {
+	return &RepaymentHandler{
+		repaymentService: repaymentService,
+		logger:           logger,
+	}
+}
+
+// GetRepaymentSchedule retrieves a funded loan's repayment schedule
+// @Summary Get repayment schedule
+// @Description Retrieve the repayment schedule generated when the loan was funded: the payment due day, first payment and maturity dates, and each installment's due date, amount and principal and interest split
+// @Tags Repayment
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.RepaymentSchedule} "Repayment schedule retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 403 {object} middleware.ErrorResponse "Application belongs to another user"
+// @Failure 404 {object} middleware.ErrorResponse "No repayment schedule has been generated"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/repayment-schedule [get]
+func (h *RepaymentHandler) GetRepaymentSchedule(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_repayment_schedule"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	schedule, err := h.repaymentService.GetSchedule(c.Request.Context(), c.Param("id"), middleware.OwnerScope(c))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, schedule, "", nil)
+}
+
+// GenerateRepaymentSchedule generates a funded loan's repayment schedule (admin endpoint)
+// @Summary Generate repayment schedule
+// @Description Generate the repayment schedule of a funded loan. Schedules are generated automatically when the disbursement settles; this generates one for loans funded otherwise or whose generation failed. An existing schedule is returned as is.
+// @Tags Repayment
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.RepaymentSchedule} "Repayment schedule generated"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 409 {object} middleware.ErrorResponse "Loan not funded"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/repayment-schedule [post]
+func (h *RepaymentHandler) GenerateRepaymentSchedule(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "generate_repayment_schedule"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	schedule, err := h.repaymentService.GenerateSchedule(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, schedule, "REPAYMENT_SCHEDULE_GENERATED", nil)
+}
+
+// respondError writes the error response for a failed repayment schedule request
+func (h *RepaymentHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Repayment schedule request failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected repayment schedule error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the repayment schedule routes
+func (h *RepaymentHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		loans.GET("/applications/:id/repayment-schedule", h.GetRepaymentSchedule)
+
+		// Admin endpoints (require a back-office role)
+		admin := loans.Group("", middleware.RequireStaff())
+		admin.POST("/applications/:id/repayment-schedule", h.GenerateRepaymentSchedule)
+	}
+}
@@ -0,0 +1,81 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// SearchHandler handles back-office application search
+type SearchHandler struct {
+	searchService *application.ApplicationSearchService
+	logger        *zap.Logger
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(searchService *application.ApplicationSearchService, logger *zap.Logger) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+		logger:        logger,
+	}
+}
+
+// SearchApplications searches applications for back-office lookup (admin endpoint)
+// @Summary Search applications
+// @Description Search applications by applicant name, email, application number or note text. Names, emails and numbers match on substrings and by trigram similarity, so misspellings are found; notes match by full-text search. Results are ranked best match first and the matched text of each field is highlighted with <mark> tags.
+// @Tags Applications
+// @Accept json
+// @Produce json
+// @Param q query string true "Search term (at least 2 characters)"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Results per page (default 20, max 100)"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.ApplicationSearchPage} "Search results retrieved"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/admin/search [get]
+func (h *SearchHandler) SearchApplications(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "search_applications"))
+
+	var query domain.ApplicationSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Warn("Invalid query parameters", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	page, err := h.searchService.Search(c.Request.Context(), &query)
+	if err != nil {
+		if loanErr, ok := err.(*domain.LoanError); ok {
+			logger.Warn("Application search failed",
+				zap.String("error_code", loanErr.Code),
+				zap.String("description", loanErr.Description),
+				zap.Error(err))
+			middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+			return
+		}
+
+		logger.Error("Unexpected search error", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, page, "", nil)
+}
+
+// RegisterRoutes registers the search routes
+func (h *SearchHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		// Admin endpoints (require a back-office role)
+		admin := loans.Group("", middleware.RequireStaff())
+		admin.GET("/admin/search", h.SearchApplications)
+	}
+}
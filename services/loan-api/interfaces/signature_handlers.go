@@ -0,0 +1,147 @@
+package interfaces
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// maxWebhookSize bounds the body read from e-signature, disbursement and payroll provider callbacks
+const maxWebhookSize = 1 << 20
+
+// SignatureHandler handles loan agreement e-signature requests and provider callbacks
+type SignatureHandler struct {
+	signatureService *application.SignatureService
+	logger           *zap.Logger
+}
+
+// NewSignatureHandler creates a new signature handler
+func NewSignatureHandler(signatureService *application.SignatureService, logger *zap.Logger) *SignatureHandler {
+	return &SignatureHandler{
+		signatureService: signatureService,
+		logger:           logger,
+	}
+}
+
+// GetSignature retrieves the status of an application's loan agreement signature
+// @Summary Get agreement signature status
+// @Description Retrieve the loan agreement most recently sent to the borrower for electronic signature and its status
+// @Tags Signatures
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.SignatureEnvelope} "Signature status retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "No agreement has been sent"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/signature [get]
+func (h *SignatureHandler) GetSignature(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "get_signature"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	envelope, err := h.signatureService.GetSignature(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, envelope, "", nil)
+}
+
+// SendAgreement sends the loan agreement for the selected offer to the borrower for signature
+// @Summary Send agreement for signature
+// @Description Send the loan agreement for the application's selected offer to the borrower for electronic signature. Agreements are sent automatically when an offer is selected; this sends one again if that failed. An agreement already awaiting signature is returned as is.
+// @Tags Signatures
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.SignatureEnvelope} "Agreement sent"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Application not found"
+// @Failure 409 {object} middleware.ErrorResponse "Application not approved or no offer selected"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Failure 503 {object} middleware.ErrorResponse "E-signature unavailable"
+// @Security BearerAuth
+// @Router /loans/applications/{id}/signature [post]
+func (h *SignatureHandler) SendAgreement(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "send_agreement"),
+		zap.String("application_id", c.Param("id")),
+	)
+
+	envelope, err := h.signatureService.SendAgreement(c.Request.Context(), c.Param("id"), "")
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, envelope, "AGREEMENT_SENT", nil)
+}
+
+// HandleWebhook receives envelope status notifications from the e-signature provider
+// @Summary E-signature provider webhook
+// @Description Receive envelope notifications from the e-signature provider. Requests are authenticated by the provider's HMAC signature. Completed envelopes store the signed agreement and move the application to documents_signed.
+// @Tags Signatures
+// @Accept json
+// @Produce json
+// @Success 200 {object} middleware.SuccessResponse "Notification processed"
+// @Failure 401 {object} middleware.ErrorResponse "Invalid signature"
+// @Failure 500 {object} middleware.ErrorResponse "Processing failed; the provider retries"
+// @Router /webhooks/esign [post]
+func (h *SignatureHandler) HandleWebhook(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "handle_signature_webhook"))
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookSize))
+	if err != nil {
+		logger.Warn("Failed to read webhook body", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	if err := h.signatureService.HandleWebhook(c.Request.Context(), body, c.GetHeader); err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, gin.H{"received": true}, "", nil)
+}
+
+// respondError writes the error response for a failed signature request
+func (h *SignatureHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Signature request failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected signature error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the loan agreement signature routes
+func (h *SignatureHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		loans.GET("/applications/:id/signature", h.GetSignature)
+		loans.POST("/applications/:id/signature", h.SendAgreement)
+	}
+}
+
+// RegisterWebhookRoutes registers the e-signature provider callback routes
+func (h *SignatureHandler) RegisterWebhookRoutes(router *gin.RouterGroup) {
+	router.POST("/esign", h.HandleWebhook)
+}
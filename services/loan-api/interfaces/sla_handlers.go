@@ -0,0 +1,82 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// SLAHandler handles the application SLA breach listing
+type SLAHandler struct {
+	slaService *application.SLAService
+	logger     *zap.Logger
+}
+
+// NewSLAHandler creates a new SLA handler
+func NewSLAHandler(slaService *application.SLAService, logger *zap.Logger) *SLAHandler {
+	return &SLAHandler{
+		slaService: slaService,
+		logger:     logger,
+	}
+}
+
+// ListBreaches lists applications that overstayed a state's SLA (admin endpoint)
+// @Summary List SLA breaches
+// @Description List SLA escalations, newest first: which SLA an application breached, in which state, when it was due and the action taken (notify, priority_bump or auto_withdraw). Open breaches are applications still in the breached state, with how long they are overdue.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param state query string false "Application state"
+// @Param sla query string false "SLA name"
+// @Param open_only query bool false "Only applications still in the breached state"
+// @Param limit query int false "Maximum breaches returned (default 50, max 100)"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=[]domain.SLABreach} "SLA breaches retrieved"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/admin/sla-breaches [get]
+func (h *SLAHandler) ListBreaches(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "list_sla_breaches"))
+
+	var query domain.SLABreachQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Warn("Invalid query parameters", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	breaches, err := h.slaService.ListBreaches(c.Request.Context(), &query)
+	if err != nil {
+		if loanErr, ok := err.(*domain.LoanError); ok {
+			logger.Warn("SLA breach listing failed",
+				zap.String("error_code", loanErr.Code),
+				zap.String("description", loanErr.Description),
+				zap.Error(err))
+			middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+			return
+		}
+
+		logger.Error("Unexpected SLA error", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, breaches, "", nil)
+}
+
+// RegisterRoutes registers the SLA routes
+func (h *SLAHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		// Admin endpoints (require a back-office role)
+		admin := loans.Group("", middleware.RequireStaff())
+		admin.GET("/admin/sla-breaches", h.ListBreaches)
+	}
+}
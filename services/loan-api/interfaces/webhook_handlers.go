@@ -0,0 +1,210 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/loan-api/application"
+	"github.com/huuhoait/los-demo/services/loan-api/domain"
+	"github.com/huuhoait/los-demo/services/loan-api/interfaces/middleware"
+)
+
+// WebhookHandler handles partner webhook subscriptions and their delivery logs
+type WebhookHandler struct {
+	webhookService *application.WebhookService
+	logger         *zap.Logger
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService *application.WebhookService, logger *zap.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		logger:         logger,
+	}
+}
+
+// CreateSubscription registers a partner webhook endpoint (admin endpoint)
+// @Summary Create webhook subscription
+// @Description Register an HTTPS endpoint for application state change and offer events. Each delivery is signed in the X-Webhook-Signature header as t=<unix time>,v1=<hex HMAC-SHA256 of "<t>.<body>">, keyed by the returned secret, which is not shown again.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param request body domain.CreateWebhookSubscriptionRequest true "Subscription"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.WebhookSubscription} "Webhook subscription created"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/webhook-subscriptions [post]
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "create_webhook_subscription"))
+
+	var req domain.CreateWebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request format", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+
+	subscription, err := h.webhookService.CreateSubscription(c.Request.Context(), &req)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, subscription, "WEBHOOK_SUBSCRIPTION_CREATED", nil)
+}
+
+// ListSubscriptions lists the partner webhook subscriptions (admin endpoint)
+// @Summary List webhook subscriptions
+// @Description List every webhook subscription, active or not, without its secret.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=[]domain.WebhookSubscription} "Webhook subscriptions retrieved"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/webhook-subscriptions [get]
+func (h *WebhookHandler) ListSubscriptions(c *gin.Context) {
+	logger := h.logger.With(zap.String("operation", "list_webhook_subscriptions"))
+
+	subscriptions, err := h.webhookService.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, subscriptions, "", nil)
+}
+
+// DeactivateSubscription stops a partner webhook subscription (admin endpoint)
+// @Summary Deactivate webhook subscription
+// @Description Stop sending new events to a subscription. Deliveries already queued are still attempted.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.WebhookSubscription} "Webhook subscription deactivated"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Webhook subscription not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/webhook-subscriptions/{id} [delete]
+func (h *WebhookHandler) DeactivateSubscription(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "deactivate_webhook_subscription"),
+		zap.String("subscription_id", c.Param("id")),
+	)
+
+	subscription, err := h.webhookService.DeactivateSubscription(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, subscription, "WEBHOOK_SUBSCRIPTION_DEACTIVATED", nil)
+}
+
+// ListDeliveries lists a subscription's delivery log (admin endpoint)
+// @Summary List webhook deliveries
+// @Description List a subscription's deliveries, newest first, with their attempts, last response status and error.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param status query string false "Delivery status (pending, delivered, failed)"
+// @Param limit query int false "Maximum deliveries returned (default 50, max 100)"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=[]domain.WebhookDelivery} "Webhook deliveries retrieved"
+// @Failure 400 {object} middleware.ErrorResponse "Invalid request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Webhook subscription not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/webhook-subscriptions/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "list_webhook_deliveries"),
+		zap.String("subscription_id", c.Param("id")),
+	)
+
+	var query domain.WebhookDeliveryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Warn("Invalid query parameters", zap.Error(err))
+		middleware.CreateErrorResponse(c, http.StatusBadRequest, domain.LOAN_020, nil)
+		return
+	}
+	query.SubscriptionID = c.Param("id")
+
+	deliveries, err := h.webhookService.ListDeliveries(c.Request.Context(), &query)
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, deliveries, "", nil)
+}
+
+// Redeliver queues a webhook delivery again (admin endpoint)
+// @Summary Redeliver webhook
+// @Description Queue a delivered or failed delivery again with a fresh set of attempts, e.g. after a partner outage.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Delivery ID"
+// @Param X-Language header string false "Language preference (en, vi)"
+// @Success 200 {object} middleware.SuccessResponse{data=domain.WebhookDelivery} "Webhook delivery queued"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 404 {object} middleware.ErrorResponse "Webhook delivery not found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /loans/webhook-deliveries/{id}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "redeliver_webhook"),
+		zap.String("delivery_id", c.Param("id")),
+	)
+
+	delivery, err := h.webhookService.Redeliver(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, logger, err)
+		return
+	}
+
+	middleware.CreateSuccessResponse(c, delivery, "WEBHOOK_DELIVERY_QUEUED", nil)
+}
+
+// respondError writes the error response for a failed webhook request
+func (h *WebhookHandler) respondError(c *gin.Context, logger *zap.Logger, err error) {
+	if loanErr, ok := err.(*domain.LoanError); ok {
+		logger.Warn("Webhook request failed",
+			zap.String("error_code", loanErr.Code),
+			zap.String("description", loanErr.Description),
+			zap.Error(err))
+		middleware.CreateErrorResponse(c, loanErr.HTTPStatus, loanErr.Code, nil)
+		return
+	}
+
+	logger.Error("Unexpected webhook error", zap.Error(err))
+	middleware.CreateErrorResponse(c, http.StatusInternalServerError, domain.LOAN_023, nil)
+}
+
+// RegisterRoutes registers the webhook routes
+func (h *WebhookHandler) RegisterRoutes(router *gin.RouterGroup) {
+	loans := router.Group("/loans")
+	{
+		// Admin endpoints (require a back-office role)
+		admin := loans.Group("", middleware.RequireStaff())
+		admin.POST("/webhook-subscriptions", h.CreateSubscription)
+		admin.GET("/webhook-subscriptions", h.ListSubscriptions)
+		admin.DELETE("/webhook-subscriptions/:id", h.DeactivateSubscription)
+		admin.GET("/webhook-subscriptions/:id/deliveries", h.ListDeliveries)
+		admin.POST("/webhook-deliveries/:id/redeliver", h.Redeliver)
+	}
+}
@@ -74,6 +74,7 @@ deploy_tasks() {
         "prequalification_tasks.json"
         "loan_processing_tasks.json"
         "underwriting_tasks.json"
+        "compensation_tasks.json"
     )
     
     for task_file in "${task_files[@]}"; do
@@ -105,6 +106,9 @@ deploy_workflows() {
         "prequalification_workflow.json"
         "loan_processing_workflow.json"
         "underwriting_workflow.json"
+        "counter_offer_workflow.json"
+        "reunderwriting_workflow.json"
+        "loan_compensation_workflow.json"
     )
     
     for workflow_file in "${workflow_files[@]}"; do
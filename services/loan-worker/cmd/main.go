@@ -72,10 +72,10 @@ func main() {
 	loanRepo := dbFactory.GetLoanRepository()
 
 	// Initialize workflow orchestrator with real Conductor client
-	conductorClient := workflow.NewConductorClientImpl(
-		cfg.Conductor.BaseURL,
-		logger,
-	)
+	conductorClient, err := workflow.NewConductorClientImpl(cfg.Conductor, logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize Conductor client", zap.Error(err))
+	}
 
 	// Initialize and start task worker with repository
 	taskWorker := workflow.NewTaskWorkerWithRepository(conductorClient, logger, localizer, loanRepo)
@@ -3,6 +3,7 @@ package workflow
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
 	"go.uber.org/zap"
@@ -20,6 +21,7 @@ type ConductorClient interface {
 	ResumeWorkflow(ctx context.Context, workflowID string) error
 	UpdateTask(ctx context.Context, taskID string, workflowInstanceId string, referenceTaskName string, status string, output map[string]interface{}) error
 	GetBaseURL() string
+	Transport() http.RoundTripper
 }
 
 // WorkflowExecution represents a workflow execution instance
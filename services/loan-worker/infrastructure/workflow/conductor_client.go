@@ -7,42 +7,37 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 
-	"github.com/conductor-sdk/conductor-go/sdk/client"
-	"github.com/conductor-sdk/conductor-go/sdk/settings"
 	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/conductor"
+	"github.com/huuhoait/los-demo/services/shared/pkg/config"
 )
 
 // ConductorClientImpl implements the ConductorClient interface for Netflix Conductor
 type ConductorClientImpl struct {
-	workflowClient client.WorkflowClient
-	taskClient     client.TaskClient
-	logger         *zap.Logger
-	baseURL        string
+	httpClient *http.Client
+	transport  http.RoundTripper
+	logger     *zap.Logger
+	baseURL    string
 }
 
-// NewConductorClientImpl creates a new Conductor client implementation
-func NewConductorClientImpl(baseURL string, logger *zap.Logger) *ConductorClientImpl {
-	// Create authentication settings (no auth for local development)
-	authSettings := settings.NewAuthenticationSettings("", "")
-
-	// Create HTTP settings
-	httpSettings := settings.NewHttpSettings(baseURL)
-
-	// Create the API client
-	apiClient := client.NewAPIClient(authSettings, httpSettings)
-
-	// Create workflow and task clients
-	workflowClient := client.NewWorkflowClient(apiClient)
-	taskClient := client.NewTaskClient(apiClient)
+// NewConductorClientImpl creates a new Conductor client implementation. Requests carry the
+// configured credentials and are sent over the configured TLS settings.
+func NewConductorClientImpl(cfg config.ConductorConfig, logger *zap.Logger) (*ConductorClientImpl, error) {
+	transport, err := conductor.NewTransport(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure conductor transport: %w", err)
+	}
 
 	return &ConductorClientImpl{
-		workflowClient: workflowClient,
-		taskClient:     taskClient,
-		logger:         logger,
-		baseURL:        baseURL,
-	}
+		httpClient: &http.Client{Timeout: 30 * time.Second, Transport: transport},
+		transport:  transport,
+		logger:     logger,
+		baseURL:    cfg.BaseURL,
+	}, nil
 }
 
 // StartWorkflow starts a new workflow execution
@@ -89,8 +84,7 @@ func (c *ConductorClientImpl) StartWorkflow(
 	req.Header.Set("Accept", "text/plain")
 
 	// Execute the request
-	httpClient := &http.Client{Timeout: 30 * time.Second}
-	resp, err := httpClient.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		logger.Error("Failed to execute workflow start request", zap.Error(err))
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -139,17 +133,17 @@ func (c *ConductorClientImpl) GetWorkflowStatus(
 		zap.String("operation", "get_workflow_status"),
 	)
 
-	// Get workflow execution using the SDK
-	execution, _, err := c.workflowClient.GetExecutionStatus(ctx, workflowID, nil)
-	if err != nil {
+	// Get workflow execution with its tasks
+	var execution conductorWorkflow
+	if err := c.workflowRequest(ctx, "GET", workflowID, "?includeTasks=true", &execution); err != nil {
 		logger.Error("Failed to get workflow status", zap.Error(err))
 		return nil, fmt.Errorf("failed to get workflow status: %w", err)
 	}
 
-	// Convert SDK response to our format
+	// Convert Conductor response to our format
 	status := &WorkflowStatus{
-		WorkflowID: execution.WorkflowId,
-		Status:     string(execution.Status),
+		WorkflowID: execution.WorkflowID,
+		Status:     execution.Status,
 		Input:      execution.Input,
 		Output:     execution.Output,
 		Tasks:      make([]TaskStatus, 0, len(execution.Tasks)),
@@ -158,9 +152,9 @@ func (c *ConductorClientImpl) GetWorkflowStatus(
 	// Convert tasks
 	for _, task := range execution.Tasks {
 		taskStatus := TaskStatus{
-			TaskID:            task.TaskId,
+			TaskID:            task.TaskID,
 			TaskType:          task.TaskType,
-			Status:            string(task.Status),
+			Status:            task.Status,
 			ReferenceTaskName: task.ReferenceTaskName,
 			Input:             task.InputData,
 			Output:            task.OutputData,
@@ -199,9 +193,8 @@ func (c *ConductorClientImpl) TerminateWorkflow(
 		zap.String("operation", "terminate_workflow"),
 	)
 
-	// Terminate workflow using the SDK
-	_, err := c.workflowClient.Terminate(ctx, workflowID, nil)
-	if err != nil {
+	// Terminate workflow, recording the reason on it
+	if err := c.workflowRequest(ctx, "DELETE", workflowID, "?reason="+url.QueryEscape(reason), nil); err != nil {
 		logger.Error("Failed to terminate workflow", zap.Error(err))
 		return fmt.Errorf("failed to terminate workflow: %w", err)
 	}
@@ -222,9 +215,8 @@ func (c *ConductorClientImpl) PauseWorkflow(
 		zap.String("operation", "pause_workflow"),
 	)
 
-	// Pause workflow using the SDK
-	_, err := c.workflowClient.PauseWorkflow(ctx, workflowID)
-	if err != nil {
+	// Pause workflow
+	if err := c.workflowRequest(ctx, "PUT", workflowID, "/pause", nil); err != nil {
 		logger.Error("Failed to pause workflow", zap.Error(err))
 		return fmt.Errorf("failed to pause workflow: %w", err)
 	}
@@ -243,9 +235,8 @@ func (c *ConductorClientImpl) ResumeWorkflow(
 		zap.String("operation", "resume_workflow"),
 	)
 
-	// Resume workflow using the SDK
-	_, err := c.workflowClient.ResumeWorkflow(ctx, workflowID)
-	if err != nil {
+	// Resume workflow
+	if err := c.workflowRequest(ctx, "PUT", workflowID, "/resume", nil); err != nil {
 		logger.Error("Failed to resume workflow", zap.Error(err))
 		return fmt.Errorf("failed to resume workflow: %w", err)
 	}
@@ -307,11 +298,6 @@ func (c *ConductorClientImpl) UpdateTask(
 		return fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
-	// Create HTTP client
-	httpClient := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
 	// Create HTTP request
 	url := fmt.Sprintf("%s/api/tasks", c.baseURL)
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
@@ -324,7 +310,7 @@ func (c *ConductorClientImpl) UpdateTask(
 	req.Header.Set("Accept", "application/json")
 
 	// Execute request
-	resp, err := httpClient.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		logger.Error("Failed to execute HTTP request", zap.Error(err))
 		return fmt.Errorf("failed to execute HTTP request: %w", err)
@@ -360,3 +346,56 @@ func (c *ConductorClientImpl) UpdateTask(
 func (c *ConductorClientImpl) GetBaseURL() string {
 	return c.baseURL
 }
+
+// Transport returns the transport requests to Conductor are sent over, with the client's
+// credentials and TLS settings
+func (c *ConductorClientImpl) Transport() http.RoundTripper {
+	return c.transport
+}
+
+// conductorWorkflow is a workflow execution as returned by Conductor
+type conductorWorkflow struct {
+	WorkflowID string                 `json:"workflowId"`
+	Status     string                 `json:"status"`
+	Input      map[string]interface{} `json:"input"`
+	Output     map[string]interface{} `json:"output"`
+	Tasks      []struct {
+		TaskID            string                 `json:"taskId"`
+		TaskType          string                 `json:"taskType"`
+		Status            string                 `json:"status"`
+		ReferenceTaskName string                 `json:"referenceTaskName"`
+		InputData         map[string]interface{} `json:"inputData"`
+		OutputData        map[string]interface{} `json:"outputData"`
+		StartTime         int64                  `json:"startTime"`
+		EndTime           int64                  `json:"endTime"`
+	} `json:"tasks"`
+}
+
+// workflowRequest sends a request about a workflow to Conductor's workflow API, decoding the
+// response into result when given
+func (c *ConductorClientImpl) workflowRequest(ctx context.Context, method, workflowID, suffix string, result interface{}) error {
+	endpoint := fmt.Sprintf("%s/api/workflow/%s%s", c.baseURL, url.PathEscape(workflowID), suffix)
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("workflow request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode workflow response: %w", err)
+	}
+	return nil
+}
@@ -59,7 +59,8 @@ func NewTaskWorker(
 		workerID:        fmt.Sprintf("worker_%d", time.Now().UnixNano()),
 		pollInterval:    5 * time.Second,
 		httpClient: &http.Client{
-			Timeout: 35 * time.Second,
+			Timeout:   35 * time.Second,
+			Transport: conductorClient.Transport(),
 		},
 	}
 
@@ -84,7 +85,8 @@ func NewTaskWorkerWithRepository(
 		workerID:        fmt.Sprintf("worker_%d", time.Now().UnixNano()),
 		pollInterval:    5 * time.Second,
 		httpClient: &http.Client{
-			Timeout: 35 * time.Second,
+			Timeout:   35 * time.Second,
+			Transport: conductorClient.Transport(),
 		},
 	}
 
@@ -0,0 +1,241 @@
+package address
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Config holds address verification provider configuration
+type Config struct {
+	Provider  string `yaml:"provider" json:"provider"` // smartystreets or local
+	BaseURL   string `yaml:"base_url" json:"base_url"`
+	AuthID    string `yaml:"auth_id" json:"-"`
+	AuthToken string `yaml:"auth_token" json:"-"`
+	Timeout   int    `yaml:"timeout" json:"timeout"` // seconds
+}
+
+// Address is a postal address as submitted or as standardized by the provider
+type Address struct {
+	Street    string `json:"street"`
+	Secondary string `json:"secondary,omitempty"`
+	City      string `json:"city"`
+	State     string `json:"state"`
+	ZipCode   string `json:"zip_code"`
+	Plus4     string `json:"plus4,omitempty"`
+	Country   string `json:"country,omitempty"`
+}
+
+// Result is the outcome of verifying an address
+type Result struct {
+	// Standardized is the CASS-formatted address; nil when the provider found no match
+	Standardized *Address `json:"standardized,omitempty"`
+	// DeliveryPointBarcode is the 12-digit ZIP+4+delivery point code
+	DeliveryPointBarcode string `json:"delivery_point_barcode,omitempty"`
+	// DPVMatchCode is the USPS delivery point validation code (Y, S, D, N)
+	DPVMatchCode string    `json:"dpv_match_code,omitempty"`
+	Deliverable  bool      `json:"deliverable"`
+	Suggestions  []Address `json:"suggestions,omitempty"`
+	Provider     string    `json:"provider"`
+}
+
+// Verifier verifies and standardizes postal addresses
+type Verifier interface {
+	Verify(ctx context.Context, addr Address) (*Result, error)
+}
+
+// NewVerifier creates the verifier selected by configuration
+func NewVerifier(config Config, logger *zap.Logger) Verifier {
+	if strings.EqualFold(config.Provider, "smartystreets") {
+		timeout := time.Duration(config.Timeout) * time.Second
+		if timeout == 0 {
+			timeout = 5 * time.Second
+		}
+		return NewSmartyStreetsVerifier(config.BaseURL, config.AuthID, config.AuthToken, timeout, logger)
+	}
+	return NewLocalVerifier()
+}
+
+// SmartyStreetsVerifier verifies US addresses with the SmartyStreets US Street API
+type SmartyStreetsVerifier struct {
+	baseURL    string
+	authID     string
+	authToken  string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewSmartyStreetsVerifier creates a new SmartyStreets verifier
+func NewSmartyStreetsVerifier(baseURL, authID, authToken string, timeout time.Duration, logger *zap.Logger) *SmartyStreetsVerifier {
+	if baseURL == "" {
+		baseURL = "https://us-street.api.smartystreets.com"
+	}
+	return &SmartyStreetsVerifier{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		authID:     authID,
+		authToken:  authToken,
+		httpClient: &http.Client{Timeout: timeout},
+		logger:     logger,
+	}
+}
+
+type smartyCandidate struct {
+	DeliveryLine1        string `json:"delivery_line_1"`
+	DeliveryLine2        string `json:"delivery_line_2"`
+	DeliveryPointBarcode string `json:"delivery_point_barcode"`
+	Components           struct {
+		CityName          string `json:"city_name"`
+		StateAbbreviation string `json:"state_abbreviation"`
+		Zipcode           string `json:"zipcode"`
+		Plus4Code         string `json:"plus4_code"`
+	} `json:"components"`
+	Analysis struct {
+		DPVMatchCode string `json:"dpv_match_code"`
+		DPVVacant    string `json:"dpv_vacant"`
+	} `json:"analysis"`
+}
+
+// Verify looks the address up and returns the CASS-standardized candidate
+func (v *SmartyStreetsVerifier) Verify(ctx context.Context, addr Address) (*Result, error) {
+	logger := v.logger.With(
+		zap.String("operation", "verify_address"),
+		zap.String("zip_code", addr.ZipCode),
+	)
+
+	query := url.Values{}
+	query.Set("auth-id", v.authID)
+	query.Set("auth-token", v.authToken)
+	query.Set("street", addr.Street)
+	query.Set("secondary", addr.Secondary)
+	query.Set("city", addr.City)
+	query.Set("state", addr.State)
+	query.Set("zipcode", addr.ZipCode)
+	query.Set("candidates", "5")
+	query.Set("match", "enhanced")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.baseURL+"/street-address?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build address verification request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Address verification request failed", zap.Error(err))
+		return nil, fmt.Errorf("failed to call address provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("Unexpected address verification response", zap.Int("status", resp.StatusCode))
+		return nil, fmt.Errorf("unexpected address provider status: %d", resp.StatusCode)
+	}
+
+	var candidates []smartyCandidate
+	if err := json.NewDecoder(resp.Body).Decode(&candidates); err != nil {
+		return nil, fmt.Errorf("failed to decode address verification response: %w", err)
+	}
+
+	result := &Result{Provider: "smartystreets"}
+	if len(candidates) == 0 {
+		logger.Info("Address not found by provider")
+		return result, nil
+	}
+
+	best := candidates[0]
+	result.Standardized = best.toAddress(addr.Country)
+	result.DeliveryPointBarcode = best.DeliveryPointBarcode
+	result.DPVMatchCode = best.Analysis.DPVMatchCode
+	// Y is a confirmed delivery point; S and D are missing or invalid secondary numbers
+	result.Deliverable = best.Analysis.DPVMatchCode == "Y" && best.Analysis.DPVVacant != "Y"
+
+	for _, candidate := range candidates[1:] {
+		result.Suggestions = append(result.Suggestions, *candidate.toAddress(addr.Country))
+	}
+	if !result.Deliverable {
+		// The closest match is still the best suggestion when it is not deliverable as entered
+		result.Suggestions = append([]Address{*result.Standardized}, result.Suggestions...)
+	}
+
+	return result, nil
+}
+
+func (c smartyCandidate) toAddress(country string) *Address {
+	return &Address{
+		Street:    c.DeliveryLine1,
+		Secondary: c.DeliveryLine2,
+		City:      c.Components.CityName,
+		State:     c.Components.StateAbbreviation,
+		ZipCode:   c.Components.Zipcode,
+		Plus4:     c.Components.Plus4Code,
+		Country:   country,
+	}
+}
+
+// LocalVerifier formats addresses to CASS conventions without a provider lookup.
+// It cannot confirm deliverability and is intended for development and tests.
+type LocalVerifier struct{}
+
+// NewLocalVerifier creates a new local verifier
+func NewLocalVerifier() *LocalVerifier {
+	return &LocalVerifier{}
+}
+
+var (
+	whitespacePattern = regexp.MustCompile(`\s+`)
+	zipPattern        = regexp.MustCompile(`^(\d{5})(?:-?(\d{4}))?$`)
+	streetSuffixes    = map[string]string{
+		"AVENUE": "AVE", "BOULEVARD": "BLVD", "CIRCLE": "CIR", "COURT": "CT",
+		"DRIVE": "DR", "HIGHWAY": "HWY", "LANE": "LN", "PARKWAY": "PKWY",
+		"PLACE": "PL", "ROAD": "RD", "SQUARE": "SQ", "STREET": "ST",
+		"TERRACE": "TER", "TRAIL": "TRL", "WAY": "WAY",
+		"NORTH": "N", "SOUTH": "S", "EAST": "E", "WEST": "W",
+		"APARTMENT": "APT", "SUITE": "STE",
+	}
+)
+
+// Verify standardizes casing, punctuation, street suffixes and ZIP format
+func (v *LocalVerifier) Verify(ctx context.Context, addr Address) (*Result, error) {
+	standardized := &Address{
+		Street:    standardizeLine(addr.Street),
+		Secondary: standardizeLine(addr.Secondary),
+		City:      strings.ToUpper(collapse(addr.City)),
+		State:     strings.ToUpper(collapse(addr.State)),
+		Country:   addr.Country,
+	}
+
+	zip := strings.ReplaceAll(collapse(addr.ZipCode), " ", "")
+	if m := zipPattern.FindStringSubmatch(zip); m != nil {
+		standardized.ZipCode = m[1]
+		standardized.Plus4 = m[2]
+	} else {
+		standardized.ZipCode = zip
+	}
+
+	return &Result{
+		Standardized: standardized,
+		Deliverable:  zipPattern.MatchString(zip) && standardized.Street != "",
+		Provider:     "local",
+	}, nil
+}
+
+func standardizeLine(line string) string {
+	words := strings.Fields(strings.ToUpper(strings.NewReplacer(".", "", ",", "").Replace(line)))
+	for i, word := range words {
+		if abbr, ok := streetSuffixes[word]; ok {
+			words[i] = abbr
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func collapse(s string) string {
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(s, " "))
+}
@@ -0,0 +1,322 @@
+package conductor
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/config"
+)
+
+const (
+	// defaultAuthHeader is the header Conductor reads credentials from
+	defaultAuthHeader = "X-Authorization"
+	// defaultTokenTTL is how long a JWT exchanged for an access key is reused
+	defaultTokenTTL = 30 * time.Minute
+	// tokenRequestTimeout bounds the exchange of an access key for a JWT
+	tokenRequestTimeout = 10 * time.Second
+)
+
+// NewTransport returns the HTTP transport for requests to Conductor. It verifies the server
+// against the configured CA bundle, presents the client certificate for mutual TLS and adds the
+// configured credentials to every request. A request refused with 401 is retried once with fresh
+// credentials, so a rotated key or token is picked up without a restart.
+func NewTransport(cfg config.ConductorConfig) (http.RoundTripper, error) {
+	tlsConfig, err := newTLSConfig(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.TLSClientConfig = tlsConfig
+
+	auth := cfg.Auth
+	header := auth.Header
+	if header == "" {
+		header = defaultAuthHeader
+	}
+
+	switch {
+	case auth.KeyID != "":
+		if auth.KeySecret == "" && auth.KeySecretFile == "" {
+			return nil, fmt.Errorf("conductor auth key_secret or key_secret_file is required with key_id")
+		}
+		ttl := defaultTokenTTL
+		if auth.TokenTTL > 0 {
+			ttl = time.Duration(auth.TokenTTL) * time.Second
+		}
+		return &authTransport{
+			base:   base,
+			header: header,
+			tokens: &accessKeyTokens{
+				tokenURL: strings.TrimRight(cfg.BaseURL, "/") + "/api/token",
+				keyID:    auth.KeyID,
+				secret:   newSecret(auth.KeySecret, auth.KeySecretFile),
+				client:   &http.Client{Transport: base, Timeout: tokenRequestTimeout},
+				ttl:      ttl,
+			},
+		}, nil
+	case auth.Token != "" || auth.TokenFile != "":
+		return &authTransport{
+			base:   base,
+			header: header,
+			tokens: &staticToken{secret: newSecret(auth.Token, auth.TokenFile)},
+		}, nil
+	default:
+		return base, nil
+	}
+}
+
+// newTLSConfig builds the TLS configuration for connections to Conductor
+func newTLSConfig(cfg config.ConductorTLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+	}
+
+	if cfg.CAFile != "" {
+		bundle, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read conductor CA bundle: %w", err)
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("conductor CA bundle %s contains no certificates", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = roots
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		if cfg.CertFile == "" || cfg.KeyFile == "" {
+			return nil, fmt.Errorf("conductor TLS cert_file and key_file must be set together")
+		}
+		certificate := &clientCertificate{certFile: cfg.CertFile, keyFile: cfg.KeyFile}
+		if _, err := certificate.get(); err != nil {
+			return nil, err
+		}
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return certificate.get()
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// clientCertificate is the certificate presented for mutual TLS, loaded again when its
+// certificate file changes
+type clientCertificate struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	current *tls.Certificate
+	modTime time.Time
+}
+
+func (c *clientCertificate) get() (*tls.Certificate, error) {
+	info, err := os.Stat(c.certFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read conductor client certificate: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.current != nil && info.ModTime().Equal(c.modTime) {
+		return c.current, nil
+	}
+	certificate, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load conductor client certificate: %w", err)
+	}
+	c.current, c.modTime = &certificate, info.ModTime()
+	return c.current, nil
+}
+
+// secret is a credential given in the config or in a file. A file is read again when it changes,
+// so a credential rotated by whatever mounts it is picked up on the next request.
+type secret struct {
+	path string
+
+	mu      sync.Mutex
+	value   string
+	modTime time.Time
+}
+
+func newSecret(value, path string) *secret {
+	if path != "" {
+		return &secret{path: path}
+	}
+	return &secret{value: value}
+}
+
+func (s *secret) get() (string, error) {
+	if s.path == "" {
+		return s.value, nil
+	}
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read conductor credential: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.value != "" && info.ModTime().Equal(s.modTime) {
+		return s.value, nil
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read conductor credential: %w", err)
+	}
+	s.value, s.modTime = strings.TrimSpace(string(data)), info.ModTime()
+	if s.value == "" {
+		return "", fmt.Errorf("conductor credential file %s is empty", s.path)
+	}
+	return s.value, nil
+}
+
+// tokenSource supplies the credential sent with each request
+type tokenSource interface {
+	token(ctx context.Context) (string, error)
+	// invalidate discards a credential the server refused
+	invalidate()
+}
+
+// staticToken is an API key or pre-issued JWT
+type staticToken struct {
+	secret *secret
+}
+
+func (t *staticToken) token(context.Context) (string, error) {
+	return t.secret.get()
+}
+
+func (t *staticToken) invalidate() {}
+
+// accessKeyTokens exchanges an access key for a JWT, reusing it until its TTL passes, the key
+// secret changes or the server refuses it
+type accessKeyTokens struct {
+	tokenURL string
+	keyID    string
+	secret   *secret
+	client   *http.Client
+	ttl      time.Duration
+
+	mu        sync.Mutex
+	current   string
+	keySecret string // the secret current was issued for
+	expiresAt time.Time
+}
+
+func (t *accessKeyTokens) token(ctx context.Context) (string, error) {
+	keySecret, err := t.secret.get()
+	if err != nil {
+		return "", err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.current != "" && t.keySecret == keySecret && time.Now().Before(t.expiresAt) {
+		return t.current, nil
+	}
+
+	body, err := json.Marshal(map[string]string{"keyId": t.keyID, "keySecret": keySecret})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode conductor token request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.tokenURL, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create conductor token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request conductor token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("conductor token request failed with status: %d", resp.StatusCode)
+	}
+	var result struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode conductor token: %w", err)
+	}
+	if result.Token == "" {
+		return "", fmt.Errorf("conductor returned an empty token")
+	}
+
+	t.current, t.keySecret, t.expiresAt = result.Token, keySecret, time.Now().Add(t.ttl)
+	return t.current, nil
+}
+
+func (t *accessKeyTokens) invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.current = ""
+}
+
+// authTransport adds the client's credentials to each request
+type authTransport struct {
+	base   http.RoundTripper
+	header string
+	tokens tokenSource
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.tokens.token(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate with conductor: %w", err)
+	}
+
+	resp, err := t.base.RoundTrip(t.authenticated(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		// The body has been sent and cannot be replayed
+		return resp, nil
+	}
+
+	// The token may have expired early or the credentials been rotated: retry once with fresh ones
+	t.tokens.invalidate()
+	retryToken, err := t.tokens.token(req.Context())
+	if err != nil || retryToken == token {
+		return resp, nil
+	}
+	retry := t.authenticated(req, retryToken)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return t.base.RoundTrip(retry)
+}
+
+// authenticated copies the request with the credential set
+func (t *authTransport) authenticated(req *http.Request, token string) *http.Request {
+	authenticated := req.Clone(req.Context())
+	if strings.EqualFold(t.header, "Authorization") {
+		token = "Bearer " + token
+	}
+	authenticated.Header.Set(t.header, token)
+	return authenticated
+}
@@ -8,6 +8,10 @@ import (
 	"time"
 
 	"gopkg.in/yaml.v2"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/address"
+	"github.com/huuhoait/los-demo/services/shared/pkg/dti"
+	"github.com/huuhoait/los-demo/services/shared/pkg/payroll"
 )
 
 // BaseConfig contains common configuration fields for all services
@@ -22,6 +26,23 @@ type BaseConfig struct {
 	Conductor   ConductorConfig `yaml:"conductor" json:"conductor"`
 	Security    SecurityConfig  `yaml:"security" json:"security"`
 	Application AppConfig       `yaml:"application" json:"application"`
+	Services    ServicesConfig  `yaml:"services" json:"services"`
+
+	AddressVerification address.Config           `yaml:"address_verification" json:"address_verification"`
+	DTI                 dti.Config               `yaml:"dti" json:"dti"`
+	ESign               ESignConfig              `yaml:"esign" json:"esign"`
+	Disbursement        DisbursementConfig       `yaml:"disbursement" json:"disbursement"`
+	Plaid               PlaidConfig              `yaml:"plaid" json:"plaid"`
+	RateLock            RateLockConfig           `yaml:"rate_lock" json:"rate_lock"`
+	Valuation           ValuationConfig          `yaml:"valuation" json:"valuation"`
+	Webhooks            WebhookConfig            `yaml:"webhooks" json:"webhooks"`
+	SLA                 SLAConfig                `yaml:"sla" json:"sla"`
+	Assignment          AssignmentConfig         `yaml:"assignment" json:"assignment"`
+	DeadLetter          DeadLetterConfig         `yaml:"dead_letter" json:"dead_letter"`
+	UnderwritingPolicy  UnderwritingPolicyConfig `yaml:"underwriting_policy" json:"underwriting_policy"`
+	SecondaryReview     SecondaryReviewConfig    `yaml:"secondary_review" json:"secondary_review"`
+	Payroll             payroll.Config           `yaml:"payroll" json:"payroll"`
+	GRPC                GRPCConfig               `yaml:"grpc" json:"grpc"`
 }
 
 // ServiceConfig holds service-specific configuration
@@ -84,6 +105,81 @@ type ConductorConfig struct {
 	WorkerPoolSize  int    `yaml:"worker_pool_size" json:"worker_pool_size"`
 	PollingInterval int    `yaml:"polling_interval_ms" json:"polling_interval_ms"`
 	UpdateRetryTime int    `yaml:"update_retry_time_ms" json:"update_retry_time_ms"`
+
+	// TaskRetries overrides the worker's retry policy per task type; other tasks use
+	// RetryAttempts and RetryDelay
+	TaskRetries map[string]TaskRetryConfig `yaml:"task_retries" json:"task_retries"`
+
+	// TaskConcurrency limits how many tasks of a type a worker runs at once, giving the task type a
+	// pool of that many polling workers; other task types share WorkerPoolSize workers
+	TaskConcurrency map[string]int `yaml:"task_concurrency" json:"task_concurrency"`
+
+	// TaskPriority orders the task types waiting for a worker of their pool, highest first;
+	// unlisted task types have priority 0. A worker freed goes to the highest priority task type.
+	TaskPriority map[string]int `yaml:"task_priority" json:"task_priority"`
+
+	// PollTimeout is how long, in milliseconds, a batch poll waits for a task to be queued before
+	// returning empty. A poll that comes back empty is followed by a wait of PollingInterval,
+	// doubling with each further empty poll up to MaxPollBackoff milliseconds.
+	PollTimeout    int `yaml:"poll_timeout_ms" json:"poll_timeout_ms"`
+	MaxPollBackoff int `yaml:"max_poll_backoff_ms" json:"max_poll_backoff_ms"`
+
+	// TaskPollCount caps how many tasks of a type one batch poll claims; by default it is the size
+	// of the task type's pool. A poll never claims more tasks than the pool has idle workers.
+	TaskPollCount map[string]int `yaml:"task_poll_count" json:"task_poll_count"`
+
+	// QueueMetricsInterval is how often, in seconds, the worker logs each task type's queue depth
+	// and execution counters; 0 disables them
+	QueueMetricsInterval int `yaml:"queue_metrics_interval_seconds" json:"queue_metrics_interval_seconds"`
+
+	// DrainTimeout is how long, in seconds, a stopping worker waits for its running tasks to
+	// finish; tasks still running then are handed back to Conductor to be polled again after
+	// DrainCallbackAfter seconds
+	DrainTimeout       int `yaml:"drain_timeout_seconds" json:"drain_timeout_seconds"`
+	DrainCallbackAfter int `yaml:"drain_callback_after_seconds" json:"drain_callback_after_seconds"`
+
+	// DefinitionsDir holds the workflow definitions, one per JSON or YAML file, and in its tasks
+	// subdirectory the task definitions a worker registers at startup in place of its built-in
+	// ones. Only definitions Conductor lacks or has differently are registered.
+	DefinitionsDir string `yaml:"definitions_dir" json:"definitions_dir"`
+
+	// Auth authenticates requests to Conductor and TLS secures the connection; credentials kept in
+	// files are read again when the files change, so they can be rotated without a restart
+	Auth ConductorAuthConfig `yaml:"auth" json:"auth"`
+	TLS  ConductorTLSConfig  `yaml:"tls" json:"tls"`
+}
+
+// ConductorAuthConfig holds the credentials a client presents to Conductor. An access key (KeyID
+// with KeySecret or KeySecretFile) is exchanged at the server's /api/token endpoint for a JWT,
+// reused for TokenTTL seconds; otherwise Token or TokenFile is sent as is, as an API key or a
+// pre-issued JWT. Either is sent in Header, X-Authorization by default, or as a bearer token when
+// Header is Authorization.
+type ConductorAuthConfig struct {
+	KeyID         string `yaml:"key_id" json:"key_id"`
+	KeySecret     string `yaml:"key_secret" json:"-"`
+	KeySecretFile string `yaml:"key_secret_file" json:"key_secret_file"`
+	Token         string `yaml:"token" json:"-"`
+	TokenFile     string `yaml:"token_file" json:"token_file"`
+	Header        string `yaml:"header" json:"header"`
+	TokenTTL      int    `yaml:"token_ttl_seconds" json:"token_ttl_seconds"`
+}
+
+// ConductorTLSConfig holds how a client verifies Conductor's certificate and, for mutual TLS, the
+// certificate it presents. CAFile is added to the system roots; the client certificate is read
+// again when CertFile changes.
+type ConductorTLSConfig struct {
+	CAFile     string `yaml:"ca_file" json:"ca_file"`
+	CertFile   string `yaml:"cert_file" json:"cert_file"`
+	KeyFile    string `yaml:"key_file" json:"key_file"`
+	ServerName string `yaml:"server_name" json:"server_name"`
+}
+
+// TaskRetryConfig holds how a worker retries a task type that failed with a retryable error
+type TaskRetryConfig struct {
+	MaxAttempts       int     `yaml:"max_attempts" json:"max_attempts"`             // including the first attempt
+	InitialBackoff    int     `yaml:"initial_backoff_ms" json:"initial_backoff_ms"` // milliseconds
+	MaxBackoff        int     `yaml:"max_backoff_ms" json:"max_backoff_ms"`         // milliseconds
+	BackoffMultiplier float64 `yaml:"backoff_multiplier" json:"backoff_multiplier"`
 }
 
 // SecurityConfig holds security-related configuration
@@ -94,6 +190,9 @@ type SecurityConfig struct {
 	CORSAllowedOrigins []string `yaml:"cors_allowed_origins" json:"cors_allowed_origins"`
 	CORSAllowedMethods []string `yaml:"cors_allowed_methods" json:"cors_allowed_methods"`
 	CORSAllowedHeaders []string `yaml:"cors_allowed_headers" json:"cors_allowed_headers"`
+
+	// InternalServiceToken authenticates calls to /internal routes from other services
+	InternalServiceToken string `yaml:"internal_service_token" json:"-"`
 }
 
 // AppConfig holds application-specific configuration
@@ -108,6 +207,189 @@ type AppConfig struct {
 	MaxInterestRate      float64 `yaml:"max_interest_rate" json:"max_interest_rate"`
 	MinInterestRate      float64 `yaml:"min_interest_rate" json:"min_interest_rate"`
 	OfferExpirationHours int     `yaml:"offer_expiration_hours" json:"offer_expiration_hours"`
+
+	// PreQualificationTTLHours is how long a pre-qualification can be converted into an application
+	PreQualificationTTLHours int `yaml:"prequalification_ttl_hours" json:"prequalification_ttl_hours"`
+
+	// LenderName is the creditor named on generated agreements and disclosures
+	LenderName string `yaml:"lender_name" json:"lender_name"`
+
+	// CreditConsentDisclosureVersion is the credit pull disclosure borrowers must consent to before
+	// an application is submitted; empty accepts consent to any version
+	CreditConsentDisclosureVersion string `yaml:"credit_consent_disclosure_version" json:"credit_consent_disclosure_version"`
+
+	// DuplicateWindowDays is how far back, in days, new applications are compared with other
+	// borrowers' applications for shared SSNs, emails and devices
+	DuplicateWindowDays int `yaml:"duplicate_window_days" json:"duplicate_window_days"`
+
+	// OfferExpiryCheckInterval is how often, in seconds, lapsed offers are expired; 0 disables the job
+	OfferExpiryCheckInterval int `yaml:"offer_expiry_check_interval" json:"offer_expiry_check_interval"`
+	OfferExpiryBatchSize     int `yaml:"offer_expiry_batch_size" json:"offer_expiry_batch_size"`
+
+	// ConditionExpiryCheckInterval is how often, in seconds, underwriting conditions past their due
+	// date are expired and workflows whose critical conditions cleared are resumed; 0 disables the job
+	ConditionExpiryCheckInterval int `yaml:"condition_expiry_check_interval" json:"condition_expiry_check_interval"`
+	ConditionExpiryBatchSize     int `yaml:"condition_expiry_batch_size" json:"condition_expiry_batch_size"`
+}
+
+// GRPCConfig holds gRPC transport configuration shared by internal servers and clients; the rpc
+// package builds them from it. It is plain settings so services that load config without serving
+// or calling gRPC do not depend on grpc.
+type GRPCConfig struct {
+	Port int `yaml:"port" json:"port"` // 0 disables the gRPC server
+
+	// mTLS: servers require client certificates signed by the CA, clients verify the server against
+	// it. Without a certificate the transport is plaintext, for local development only.
+	CertFile   string `yaml:"cert_file" json:"cert_file"`
+	KeyFile    string `yaml:"key_file" json:"-"`
+	CAFile     string `yaml:"ca_file" json:"ca_file"`
+	ServerName string `yaml:"server_name" json:"server_name"` // overrides the name clients verify
+
+	Timeout     int `yaml:"timeout" json:"timeout"`           // seconds; the deadline of calls made without one
+	MaxAttempts int `yaml:"max_attempts" json:"max_attempts"` // attempts of a call on UNAVAILABLE, including the first
+}
+
+// TLSEnabled reports whether the transport uses mTLS
+func (c GRPCConfig) TLSEnabled() bool {
+	return c.CertFile != ""
+}
+
+// ServicesConfig holds endpoints of other internal services
+type ServicesConfig struct {
+	UserService    ServiceEndpointConfig `yaml:"user_service" json:"user_service"`
+	DecisionEngine ServiceEndpointConfig `yaml:"decision_engine" json:"decision_engine"`
+	LoanService    ServiceEndpointConfig `yaml:"loan_service" json:"loan_service"`
+}
+
+// ServiceEndpointConfig holds connection settings for an internal service
+type ServiceEndpointConfig struct {
+	BaseURL      string `yaml:"base_url" json:"base_url"`
+	GRPCTarget   string `yaml:"grpc_target" json:"grpc_target"` // host:port of the service's gRPC server
+	Timeout      int    `yaml:"timeout" json:"timeout"`
+	ServiceToken string `yaml:"service_token" json:"-"`
+}
+
+// ESignConfig holds electronic signature provider configuration
+type ESignConfig struct {
+	Provider      string `yaml:"provider" json:"provider"` // docusign; empty disables e-signature
+	BaseURL       string `yaml:"base_url" json:"base_url"`
+	AccountID     string `yaml:"account_id" json:"account_id"`
+	AccessToken   string `yaml:"access_token" json:"-"`
+	WebhookSecret string `yaml:"webhook_secret" json:"-"` // HMAC key for Connect webhook signatures
+	Timeout       int    `yaml:"timeout" json:"timeout"`  // seconds
+}
+
+// DisbursementConfig holds ACH origination provider and funding batch configuration
+type DisbursementConfig struct {
+	Provider      string `yaml:"provider" json:"provider"` // dwolla; empty disables disbursement
+	BaseURL       string `yaml:"base_url" json:"base_url"`
+	APIKey        string `yaml:"api_key" json:"-"`
+	APISecret     string `yaml:"api_secret" json:"-"`
+	SourceAccount string `yaml:"source_account" json:"source_account"` // the lender's funding account at the provider
+	WebhookSecret string `yaml:"webhook_secret" json:"-"`              // HMAC key for transfer webhook signatures
+	Timeout       int    `yaml:"timeout" json:"timeout"`               // seconds
+
+	// BatchInterval is how often, in seconds, a funding batch is submitted; 0 disables the job
+	BatchInterval int `yaml:"batch_interval" json:"batch_interval"`
+	BatchSize     int `yaml:"batch_size" json:"batch_size"`
+	MaxAttempts   int `yaml:"max_attempts" json:"max_attempts"` // originations of one disbursement, including retries
+	RetryDelay    int `yaml:"retry_delay" json:"retry_delay"`   // minutes before a returned transfer is retried
+}
+
+// PlaidConfig holds Plaid configuration for linking and verifying borrowers' bank accounts
+type PlaidConfig struct {
+	BaseURL    string `yaml:"base_url" json:"base_url"`
+	ClientID   string `yaml:"client_id" json:"client_id"` // empty disables Plaid linking
+	Secret     string `yaml:"secret" json:"-"`
+	ClientName string `yaml:"client_name" json:"client_name"` // shown to borrowers in Plaid Link
+	Timeout    int    `yaml:"timeout" json:"timeout"`         // seconds
+}
+
+// RateLockConfig holds how long offered rates stay locked once a borrower views or accepts an offer
+type RateLockConfig struct {
+	WindowHours        int            `yaml:"window_hours" json:"window_hours"`                 // products without their own window
+	ProductWindowHours map[string]int `yaml:"product_window_hours" json:"product_window_hours"` // keyed by product
+}
+
+// ValuationConfig holds the collateral valuation provider configuration
+type ValuationConfig struct {
+	BaseURL         string   `yaml:"base_url" json:"base_url"` // empty values collateral at the borrower's stated value
+	APIKey          string   `yaml:"api_key" json:"-"`
+	Source          string   `yaml:"source" json:"source"`                     // recorded as the valuation source, e.g. a pricing guide
+	CollateralTypes []string `yaml:"collateral_types" json:"collateral_types"` // collateral types the provider values
+	Timeout         int      `yaml:"timeout" json:"timeout"`                   // seconds
+}
+
+// WebhookConfig holds delivery of application and offer events to partner webhook subscriptions
+type WebhookConfig struct {
+	// DispatchInterval is how often, in seconds, new events are fanned out and due deliveries
+	// attempted; 0 disables delivery
+	DispatchInterval int `yaml:"dispatch_interval" json:"dispatch_interval"`
+	BatchSize        int `yaml:"batch_size" json:"batch_size"`
+	MaxAttempts      int `yaml:"max_attempts" json:"max_attempts"` // attempts of one delivery before it fails
+	RetryDelay       int `yaml:"retry_delay" json:"retry_delay"`   // seconds before the first retry, doubled after each failure
+	Timeout          int `yaml:"timeout" json:"timeout"`           // seconds
+}
+
+// SLAConfig holds how long applications may stay in each state and how overstays are escalated
+type SLAConfig struct {
+	CheckInterval int             `yaml:"check_interval" json:"check_interval"` // seconds; 0 disables escalation
+	BatchSize     int             `yaml:"batch_size" json:"batch_size"`         // applications escalated per rule and check
+	Rules         []SLARuleConfig `yaml:"rules" json:"rules"`
+}
+
+// SLARuleConfig escalates applications that stay in a state longer than WithinHours
+type SLARuleConfig struct {
+	Name        string `yaml:"name" json:"name"`
+	State       string `yaml:"state" json:"state"`
+	WithinHours int    `yaml:"within_hours" json:"within_hours"`
+	Action      string `yaml:"action" json:"action"`       // notify, priority_bump or auto_withdraw
+	Recipient   string `yaml:"recipient" json:"recipient"` // user notified by notify; empty notifies the borrower
+}
+
+// AssignmentConfig holds the loan officers manual reviews are assigned to
+type AssignmentConfig struct {
+	AssignInterval  int                 `yaml:"assign_interval" json:"assign_interval"`     // seconds; 0 disables auto-assignment
+	BatchSize       int                 `yaml:"batch_size" json:"batch_size"`               // applications assigned per run
+	HighValueAmount float64             `yaml:"high_value_amount" json:"high_value_amount"` // loans at or above it need the high_value skill
+	Officers        []LoanOfficerConfig `yaml:"officers" json:"officers"`
+}
+
+// LoanOfficerConfig is a loan officer who takes manual reviews up to Capacity at a time
+type LoanOfficerConfig struct {
+	UserID   string   `yaml:"user_id" json:"user_id"`
+	Name     string   `yaml:"name" json:"name"`
+	Capacity int      `yaml:"capacity" json:"capacity"`
+	Skills   []string `yaml:"skills" json:"skills"` // high_value, joint and preferred loan purposes
+	// LicensedStates are the borrower states the officer may review loans in; empty means any
+	LicensedStates []string        `yaml:"licensed_states" json:"licensed_states"`
+	TimeOff        []TimeOffConfig `yaml:"time_off" json:"time_off"`
+}
+
+// TimeOffConfig is a period a loan officer takes no new applications, as inclusive YYYY-MM-DD dates
+type TimeOffConfig struct {
+	Start string `yaml:"start" json:"start"`
+	End   string `yaml:"end" json:"end"`
+}
+
+// DeadLetterConfig holds when the depth of the dead-letter queue of workflow tasks is alerted on
+type DeadLetterConfig struct {
+	CheckInterval  int    `yaml:"check_interval" json:"check_interval"`   // seconds; 0 disables depth alerts
+	AlertThreshold int    `yaml:"alert_threshold" json:"alert_threshold"` // open tasks that trigger an alert
+	AlertRecipient string `yaml:"alert_recipient" json:"alert_recipient"` // staff recipient of the alert
+}
+
+// UnderwritingPolicyConfig holds how workers pick up underwriting policy versions put in force
+type UnderwritingPolicyConfig struct {
+	RefreshInterval  int  `yaml:"refresh_interval" json:"refresh_interval"`     // seconds between polls of the decision engine; default 60
+	ListenForChanges bool `yaml:"listen_for_changes" json:"listen_for_changes"` // also switch on database notifications
+}
+
+// SecondaryReviewConfig holds when an approval needs a second underwriter's sign-off before the
+// loan is finally approved
+type SecondaryReviewConfig struct {
+	ApprovalAmount float64 `yaml:"approval_amount" json:"approval_amount"` // approvals above it are signed off; 0 disables
+	DueHours       int     `yaml:"due_hours" json:"due_hours"`             // time the second underwriter has; default 8
 }
 
 // LoggingConfig holds logging configuration
@@ -229,6 +511,107 @@ func overrideWithEnvVars(config *BaseConfig) {
 			config.Conductor.PollingInterval = pi
 		}
 	}
+	if keyID := os.Getenv("CONDUCTOR_AUTH_KEY"); keyID != "" {
+		config.Conductor.Auth.KeyID = keyID
+	}
+	if keySecret := os.Getenv("CONDUCTOR_AUTH_SECRET"); keySecret != "" {
+		config.Conductor.Auth.KeySecret = keySecret
+	}
+	if token := os.Getenv("CONDUCTOR_AUTH_TOKEN"); token != "" {
+		config.Conductor.Auth.Token = token
+	}
+	if caFile := os.Getenv("CONDUCTOR_CA_FILE"); caFile != "" {
+		config.Conductor.TLS.CAFile = caFile
+	}
+	if definitionsDir := os.Getenv("CONDUCTOR_DEFINITIONS_DIR"); definitionsDir != "" {
+		config.Conductor.DefinitionsDir = definitionsDir
+	}
+
+	// Internal services configuration
+	if baseURL := os.Getenv("USER_SERVICE_URL"); baseURL != "" {
+		config.Services.UserService.BaseURL = baseURL
+	}
+	if token := os.Getenv("USER_SERVICE_TOKEN"); token != "" {
+		config.Services.UserService.ServiceToken = token
+	}
+	if baseURL := os.Getenv("DECISION_ENGINE_URL"); baseURL != "" {
+		config.Services.DecisionEngine.BaseURL = baseURL
+	}
+	if token := os.Getenv("DECISION_ENGINE_TOKEN"); token != "" {
+		config.Services.DecisionEngine.ServiceToken = token
+	}
+	if target := os.Getenv("DECISION_ENGINE_GRPC_TARGET"); target != "" {
+		config.Services.DecisionEngine.GRPCTarget = target
+	}
+	if baseURL := os.Getenv("LOAN_SERVICE_URL"); baseURL != "" {
+		config.Services.LoanService.BaseURL = baseURL
+	}
+	if token := os.Getenv("LOAN_SERVICE_TOKEN"); token != "" {
+		config.Services.LoanService.ServiceToken = token
+	}
+	if target := os.Getenv("LOAN_SERVICE_GRPC_TARGET"); target != "" {
+		config.Services.LoanService.GRPCTarget = target
+	}
+	if token := os.Getenv("INTERNAL_SERVICE_TOKEN"); token != "" {
+		config.Security.InternalServiceToken = token
+	}
+
+	// Address verification configuration
+	if authID := os.Getenv("ADDRESS_VERIFICATION_AUTH_ID"); authID != "" {
+		config.AddressVerification.AuthID = authID
+	}
+	if authToken := os.Getenv("ADDRESS_VERIFICATION_AUTH_TOKEN"); authToken != "" {
+		config.AddressVerification.AuthToken = authToken
+	}
+
+	// E-signature configuration
+	if accountID := os.Getenv("ESIGN_ACCOUNT_ID"); accountID != "" {
+		config.ESign.AccountID = accountID
+	}
+	if accessToken := os.Getenv("ESIGN_ACCESS_TOKEN"); accessToken != "" {
+		config.ESign.AccessToken = accessToken
+	}
+	if webhookSecret := os.Getenv("ESIGN_WEBHOOK_SECRET"); webhookSecret != "" {
+		config.ESign.WebhookSecret = webhookSecret
+	}
+
+	// Payroll income verification configuration
+	if clientID := os.Getenv("PAYROLL_CLIENT_ID"); clientID != "" {
+		config.Payroll.ClientID = clientID
+	}
+	if clientSecret := os.Getenv("PAYROLL_CLIENT_SECRET"); clientSecret != "" {
+		config.Payroll.ClientSecret = clientSecret
+	}
+	if webhookSecret := os.Getenv("PAYROLL_WEBHOOK_SECRET"); webhookSecret != "" {
+		config.Payroll.WebhookSecret = webhookSecret
+	}
+
+	// Disbursement configuration
+	if apiKey := os.Getenv("DISBURSEMENT_API_KEY"); apiKey != "" {
+		config.Disbursement.APIKey = apiKey
+	}
+	if apiSecret := os.Getenv("DISBURSEMENT_API_SECRET"); apiSecret != "" {
+		config.Disbursement.APISecret = apiSecret
+	}
+	if sourceAccount := os.Getenv("DISBURSEMENT_SOURCE_ACCOUNT"); sourceAccount != "" {
+		config.Disbursement.SourceAccount = sourceAccount
+	}
+	if webhookSecret := os.Getenv("DISBURSEMENT_WEBHOOK_SECRET"); webhookSecret != "" {
+		config.Disbursement.WebhookSecret = webhookSecret
+	}
+
+	// Plaid configuration
+	if clientID := os.Getenv("PLAID_CLIENT_ID"); clientID != "" {
+		config.Plaid.ClientID = clientID
+	}
+	if secret := os.Getenv("PLAID_SECRET"); secret != "" {
+		config.Plaid.Secret = secret
+	}
+
+	// Collateral valuation configuration
+	if apiKey := os.Getenv("VALUATION_API_KEY"); apiKey != "" {
+		config.Valuation.APIKey = apiKey
+	}
 
 	// Security configuration
 	if jwtSecret := os.Getenv("JWT_SECRET"); jwtSecret != "" {
@@ -592,6 +975,65 @@ func SetDefaults(config *BaseConfig) {
 		config.Conductor.UpdateRetryTime = 1000
 	}
 
+	// Set internal service defaults
+	if config.Services.UserService.Timeout == 0 {
+		config.Services.UserService.Timeout = 5
+	}
+	if config.Services.DecisionEngine.Timeout == 0 {
+		config.Services.DecisionEngine.Timeout = 5
+	}
+	if config.Services.LoanService.Timeout == 0 {
+		config.Services.LoanService.Timeout = 10
+	}
+	if config.ESign.Timeout == 0 {
+		config.ESign.Timeout = 30
+	}
+	if config.Disbursement.Timeout == 0 {
+		config.Disbursement.Timeout = 30
+	}
+	if config.Disbursement.BatchSize == 0 {
+		config.Disbursement.BatchSize = 100
+	}
+	if config.Disbursement.MaxAttempts == 0 {
+		config.Disbursement.MaxAttempts = 3
+	}
+	if config.Disbursement.RetryDelay == 0 {
+		config.Disbursement.RetryDelay = 1440
+	}
+	if config.Plaid.Timeout == 0 {
+		config.Plaid.Timeout = 30
+	}
+	if config.Valuation.Timeout == 0 {
+		config.Valuation.Timeout = 30
+	}
+	if len(config.Valuation.CollateralTypes) == 0 {
+		config.Valuation.CollateralTypes = []string{"vehicle"}
+	}
+	if config.Webhooks.BatchSize == 0 {
+		config.Webhooks.BatchSize = 100
+	}
+	if config.Webhooks.MaxAttempts == 0 {
+		config.Webhooks.MaxAttempts = 8
+	}
+	if config.Webhooks.RetryDelay == 0 {
+		config.Webhooks.RetryDelay = 60
+	}
+	if config.Webhooks.Timeout == 0 {
+		config.Webhooks.Timeout = 10
+	}
+	if config.SLA.BatchSize == 0 {
+		config.SLA.BatchSize = 100
+	}
+	if config.Assignment.BatchSize == 0 {
+		config.Assignment.BatchSize = 100
+	}
+	if config.GRPC.Timeout == 0 {
+		config.GRPC.Timeout = 5
+	}
+	if config.GRPC.MaxAttempts == 0 {
+		config.GRPC.MaxAttempts = 3
+	}
+
 	// Set security defaults
 	if config.Security.JWTSecret == "" {
 		config.Security.JWTSecret = "your-secret-key-change-in-production"
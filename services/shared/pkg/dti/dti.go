@@ -0,0 +1,230 @@
+package dti
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/income"
+)
+
+// Debt treatment defaults
+const (
+	// DefaultStudentLoanPaymentRate is the share of a student loan balance counted as its monthly
+	// payment when no qualifying payment is documented
+	DefaultStudentLoanPaymentRate = 0.01
+	// DefaultRentalIncomeFactor is the share of gross rent counted, leaving 25% for vacancy and
+	// maintenance
+	DefaultRentalIncomeFactor = 0.75
+)
+
+// Treatments recorded on adjustments
+const (
+	TreatmentStudentLoan = "student_loan"
+	TreatmentAlimony     = "alimony"
+	TreatmentRental      = "rental_income"
+)
+
+// Config holds the debt treatments applied when qualifying income and debts
+type Config struct {
+	// StudentLoanIBR counts the documented income-based repayment of a loan on an IBR plan, even
+	// when it is zero; otherwise a zero payment is replaced by a share of the balance
+	StudentLoanIBR         bool    `yaml:"student_loan_ibr" json:"student_loan_ibr"`
+	StudentLoanPaymentRate float64 `yaml:"student_loan_payment_rate" json:"student_loan_payment_rate"`
+	// AlimonyAsIncomeReduction deducts alimony and child support paid from income instead of
+	// counting it as a debt
+	AlimonyAsIncomeReduction bool    `yaml:"alimony_as_income_reduction" json:"alimony_as_income_reduction"`
+	RentalIncomeFactor       float64 `yaml:"rental_income_factor" json:"rental_income_factor"` // share of gross rent counted, 0 to 1
+	// Income holds the rules documented income sources are qualified under; rental properties
+	// default to RentalIncomeFactor
+	Income income.Config `yaml:"income" json:"income"`
+}
+
+// WithDefaults fills unset settings with the defaults
+func (c Config) WithDefaults() Config {
+	if c.StudentLoanPaymentRate <= 0 {
+		c.StudentLoanPaymentRate = DefaultStudentLoanPaymentRate
+	}
+	if c.RentalIncomeFactor <= 0 || c.RentalIncomeFactor > 1 {
+		c.RentalIncomeFactor = DefaultRentalIncomeFactor
+	}
+	if c.Income.RentalIncomeFactor <= 0 || c.Income.RentalIncomeFactor > 1 {
+		c.Income.RentalIncomeFactor = c.RentalIncomeFactor
+	}
+	c.Income = c.Income.WithDefaults()
+	return c
+}
+
+// StudentLoan is a student loan and the payment on it
+type StudentLoan struct {
+	Balance        float64 `json:"balance"`
+	MonthlyPayment float64 `json:"monthly_payment"`        // documented payment; zero while deferred
+	IncomeBased    bool    `json:"income_based,omitempty"` // repaid under an income-based plan
+}
+
+// Obligations are the debts and income that need a treatment before they count toward DTI. They
+// are not included in a borrower's stated monthly debt.
+type Obligations struct {
+	StudentLoans          []StudentLoan `json:"student_loans,omitempty"`
+	AlimonyPayment        float64       `json:"alimony_payment,omitempty"`         // monthly alimony and child support paid
+	GrossRentalIncome     float64       `json:"gross_rental_income,omitempty"`     // monthly rent received
+	RentalPropertyPayment float64       `json:"rental_property_payment,omitempty"` // monthly payment on the rented property
+}
+
+// Borrower is one borrower's monthly income and debts
+type Borrower struct {
+	MonthlyIncome float64
+	MonthlyDebt   float64 // recurring debt payments other than the obligations
+	Obligations   *Obligations
+	// IncomeSources is the borrower's documented income by source; when set, its qualifying
+	// income replaces MonthlyIncome
+	IncomeSources *income.Sources
+}
+
+// Adjustment is the amount a treatment added to qualifying income (positive) or debt
+type Adjustment struct {
+	Treatment   string  `json:"treatment"`
+	Income      float64 `json:"income,omitempty"`
+	Debt        float64 `json:"debt,omitempty"`
+	Description string  `json:"description"`
+}
+
+// Result is the qualifying income and debt of one or more borrowers and the ratio between them
+type Result struct {
+	MonthlyIncome float64            `json:"monthly_income"`
+	MonthlyDebt   float64            `json:"monthly_debt"`
+	Ratio         float64            `json:"ratio"`
+	Adjustments   []Adjustment       `json:"adjustments,omitempty"`
+	Income        []income.Component `json:"income,omitempty"` // qualifying income of each documented source
+}
+
+// RatioWith returns the ratio after taking on a further monthly payment
+func (r Result) RatioWith(payment float64) float64 {
+	if r.MonthlyIncome <= 0 {
+		return 0
+	}
+	return (r.MonthlyDebt + payment) / r.MonthlyIncome
+}
+
+// Calculator computes debt-to-income ratios under configured debt treatments. A nil calculator
+// uses the default treatments.
+type Calculator struct {
+	config Config
+	income *income.Calculator
+}
+
+// NewCalculator creates a new DTI calculator
+func NewCalculator(config Config) *Calculator {
+	config = config.WithDefaults()
+	return &Calculator{config: config, income: income.NewCalculator(config.Income)}
+}
+
+// Config returns the treatments the calculator applies
+func (c *Calculator) Config() Config {
+	if c == nil {
+		return Config{}.WithDefaults()
+	}
+	return c.config
+}
+
+// Calculate combines the borrowers' income and debts, qualifies their documented income sources
+// under the income rules, applies the debt treatments to their obligations and returns the
+// qualifying ratio. Income is zero or less only when obligations reduce it away, and the ratio is
+// then zero.
+func (c *Calculator) Calculate(borrowers ...Borrower) Result {
+	config := c.Config()
+	var incomeCalculator *income.Calculator
+	if c != nil {
+		incomeCalculator = c.income
+	} else {
+		incomeCalculator = income.NewCalculator(config.Income)
+	}
+
+	var result Result
+	for _, borrower := range borrowers {
+		if borrower.IncomeSources != nil {
+			qualified := incomeCalculator.Qualify(*borrower.IncomeSources)
+			result.MonthlyIncome += qualified.MonthlyIncome
+			result.MonthlyDebt += qualified.MonthlyDebt
+			result.Income = append(result.Income, qualified.Components...)
+		} else {
+			result.MonthlyIncome += borrower.MonthlyIncome
+		}
+		result.MonthlyDebt += borrower.MonthlyDebt
+		if borrower.Obligations != nil {
+			for _, adjustment := range config.treat(borrower.Obligations) {
+				result.MonthlyIncome += adjustment.Income
+				result.MonthlyDebt += adjustment.Debt
+				result.Adjustments = append(result.Adjustments, adjustment)
+			}
+		}
+	}
+	if result.MonthlyIncome > 0 {
+		result.Ratio = result.MonthlyDebt / result.MonthlyIncome
+	}
+	return result
+}
+
+// Ratio returns the qualifying ratio of a single borrower
+func (c *Calculator) Ratio(monthlyIncome, monthlyDebt float64, obligations *Obligations) float64 {
+	return c.Calculate(Borrower{
+		MonthlyIncome: monthlyIncome,
+		MonthlyDebt:   monthlyDebt,
+		Obligations:   obligations,
+	}).Ratio
+}
+
+// treat applies the debt treatments to a borrower's obligations
+func (c Config) treat(obligations *Obligations) []Adjustment {
+	var adjustments []Adjustment
+
+	for _, loan := range obligations.StudentLoans {
+		payment := loan.MonthlyPayment
+		description := "Documented student loan payment"
+		switch {
+		case loan.IncomeBased && c.StudentLoanIBR:
+			description = "Income-based student loan payment"
+		case payment <= 0:
+			payment = roundCents(loan.Balance * c.StudentLoanPaymentRate)
+			description = fmt.Sprintf("%.1f%% of the $%.0f student loan balance", c.StudentLoanPaymentRate*100, loan.Balance)
+		}
+		adjustments = append(adjustments, Adjustment{
+			Treatment:   TreatmentStudentLoan,
+			Debt:        payment,
+			Description: description,
+		})
+	}
+
+	if obligations.AlimonyPayment > 0 {
+		adjustment := Adjustment{Treatment: TreatmentAlimony}
+		if c.AlimonyAsIncomeReduction {
+			adjustment.Income = -obligations.AlimonyPayment
+			adjustment.Description = "Alimony and child support paid deducted from income"
+		} else {
+			adjustment.Debt = obligations.AlimonyPayment
+			adjustment.Description = "Alimony and child support paid counted as debt"
+		}
+		adjustments = append(adjustments, adjustment)
+	}
+
+	// Net rent counts as income when positive; a shortfall on the property counts as debt
+	if obligations.GrossRentalIncome > 0 || obligations.RentalPropertyPayment > 0 {
+		net := roundCents(obligations.GrossRentalIncome*c.RentalIncomeFactor - obligations.RentalPropertyPayment)
+		adjustment := Adjustment{
+			Treatment:   TreatmentRental,
+			Description: fmt.Sprintf("%.0f%% of gross rent less the property payment", c.RentalIncomeFactor*100),
+		}
+		if net >= 0 {
+			adjustment.Income = net
+		} else {
+			adjustment.Debt = -net
+		}
+		adjustments = append(adjustments, adjustment)
+	}
+
+	return adjustments
+}
+
+// roundCents rounds an amount to cents
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,50 @@
+package i18n
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// currencyFormat is how an amount in US dollars is written in a language
+type currencyFormat struct {
+	group   string // thousands separator
+	decimal string
+	prefix  string
+	suffix  string
+}
+
+var currencyFormats = map[string]currencyFormat{
+	"en": {group: ",", decimal: ".", prefix: "$"},
+	"vi": {group: ".", decimal: ",", suffix: " US$"},
+}
+
+// FormatCurrency writes a US dollar amount, rounded to cents, the way the language writes money,
+// e.g. "$12,345.67" in English and "12.345,67 US$" in Vietnamese. Unknown languages use English.
+func FormatCurrency(lang string, amount float64) string {
+	format, ok := currencyFormats[lang]
+	if !ok {
+		format = currencyFormats["en"]
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	cents := int64(math.Round(amount * 100))
+	whole := strconv.FormatInt(cents/100, 10)
+	fraction := cents % 100
+
+	var b strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(format.group)
+		}
+		b.WriteRune(digit)
+	}
+
+	return sign + format.prefix + b.String() + format.decimal + strconv.FormatInt(fraction/10, 10) +
+		strconv.FormatInt(fraction%10, 10) + format.suffix
+}
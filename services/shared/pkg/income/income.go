@@ -0,0 +1,272 @@
+package income
+
+import (
+	"fmt"
+	"math"
+)
+
+// Qualification rule defaults
+const (
+	// DefaultVariableIncomeMonths is the period bonus, overtime and commission income is averaged over
+	DefaultVariableIncomeMonths = 24
+	// DefaultMinVariableIncomeMonths is the shortest history of variable income that is counted
+	DefaultMinVariableIncomeMonths = 12
+	// DefaultSelfEmploymentMonths is the period self-employment income is averaged over
+	DefaultSelfEmploymentMonths = 24
+	// DefaultMinSelfEmploymentMonths is the shortest time in business whose income is counted
+	DefaultMinSelfEmploymentMonths = 24
+	// DefaultRentalIncomeFactor is the share of gross rent counted, leaving 25% for vacancy and
+	// maintenance
+	DefaultRentalIncomeFactor = 0.75
+)
+
+// Income source types recorded on components
+const (
+	SourceW2             = "w2"
+	SourceBonus          = "bonus"
+	SourceOvertime       = "overtime"
+	SourceCommission     = "commission"
+	SourceSelfEmployment = "self_employment"
+	SourceRental         = "rental"
+)
+
+// Config holds the rules income is qualified under
+type Config struct {
+	VariableIncomeMonths    int `yaml:"variable_income_months" json:"variable_income_months"`
+	MinVariableIncomeMonths int `yaml:"min_variable_income_months" json:"min_variable_income_months"`
+	SelfEmploymentMonths    int `yaml:"self_employment_months" json:"self_employment_months"`
+	MinSelfEmploymentMonths int `yaml:"min_self_employment_months" json:"min_self_employment_months"`
+	// AverageDecliningIncome averages variable and self-employment income that is declining;
+	// otherwise the lower, most recent year is used
+	AverageDecliningIncome bool    `yaml:"average_declining_income" json:"average_declining_income"`
+	RentalIncomeFactor     float64 `yaml:"rental_income_factor" json:"rental_income_factor"` // share of gross rent counted, 0 to 1
+}
+
+// WithDefaults fills unset rules with the defaults
+func (c Config) WithDefaults() Config {
+	if c.VariableIncomeMonths <= 0 {
+		c.VariableIncomeMonths = DefaultVariableIncomeMonths
+	}
+	if c.MinVariableIncomeMonths <= 0 {
+		c.MinVariableIncomeMonths = DefaultMinVariableIncomeMonths
+	}
+	if c.SelfEmploymentMonths <= 0 {
+		c.SelfEmploymentMonths = DefaultSelfEmploymentMonths
+	}
+	if c.MinSelfEmploymentMonths <= 0 {
+		c.MinSelfEmploymentMonths = DefaultMinSelfEmploymentMonths
+	}
+	if c.RentalIncomeFactor <= 0 || c.RentalIncomeFactor > 1 {
+		c.RentalIncomeFactor = DefaultRentalIncomeFactor
+	}
+	return c
+}
+
+// Wage is base pay from an employer, as on a W-2
+type Wage struct {
+	Employer    string  `json:"employer,omitempty"`
+	MonthlyBase float64 `json:"monthly_base"`
+}
+
+// VariableIncome is bonus, overtime or commission income from an employer
+type VariableIncome struct {
+	Type             string    `json:"type"` // bonus, overtime or commission
+	Employer         string    `json:"employer,omitempty"`
+	YearToDate       float64   `json:"year_to_date,omitempty"`
+	YearToDateMonths int       `json:"year_to_date_months,omitempty"` // months the year-to-date amount covers
+	PriorYears       []float64 `json:"prior_years,omitempty"`         // annual amounts, most recent first
+}
+
+// SelfEmployment is a business the borrower owns
+type SelfEmployment struct {
+	Business         string    `json:"business,omitempty"`
+	NetIncome        []float64 `json:"net_income"` // annual net profit, most recent year first
+	MonthsInBusiness int       `json:"months_in_business"`
+}
+
+// RentalProperty is a property the borrower lets
+type RentalProperty struct {
+	Property         string  `json:"property,omitempty"`
+	GrossMonthlyRent float64 `json:"gross_monthly_rent"`
+	MonthlyPayment   float64 `json:"monthly_payment,omitempty"` // payment on the property
+}
+
+// Sources is a borrower's documented income by source
+type Sources struct {
+	Wages          []Wage           `json:"wages,omitempty"`
+	Variable       []VariableIncome `json:"variable,omitempty"`
+	SelfEmployment []SelfEmployment `json:"self_employment,omitempty"`
+	Rental         []RentalProperty `json:"rental,omitempty"`
+}
+
+// Component is the qualifying monthly income of one source, or the debt a rental shortfall adds
+type Component struct {
+	Source        string  `json:"source"`
+	Name          string  `json:"name,omitempty"` // employer, business or property
+	MonthlyIncome float64 `json:"monthly_income"`
+	MonthlyDebt   float64 `json:"monthly_debt,omitempty"`
+	Excluded      bool    `json:"excluded,omitempty"` // not counted under the qualification rules
+	Description   string  `json:"description"`
+}
+
+// Result is a borrower's qualifying monthly income across their sources
+type Result struct {
+	MonthlyIncome float64     `json:"monthly_income"`
+	MonthlyDebt   float64     `json:"monthly_debt,omitempty"` // rental shortfalls
+	Components    []Component `json:"components"`
+}
+
+// Calculator qualifies income under configured rules. A nil calculator uses the default rules.
+type Calculator struct {
+	config Config
+}
+
+// NewCalculator creates a new income calculator
+func NewCalculator(config Config) *Calculator {
+	return &Calculator{config: config.WithDefaults()}
+}
+
+// Config returns the rules the calculator applies
+func (c *Calculator) Config() Config {
+	if c == nil {
+		return Config{}.WithDefaults()
+	}
+	return c.config
+}
+
+// Qualify combines a borrower's income sources into their qualifying monthly income
+func (c *Calculator) Qualify(sources Sources) Result {
+	config := c.Config()
+	result := Result{Components: []Component{}}
+	add := func(component Component) {
+		if !component.Excluded {
+			result.MonthlyIncome += component.MonthlyIncome
+			result.MonthlyDebt += component.MonthlyDebt
+		}
+		result.Components = append(result.Components, component)
+	}
+
+	for _, wage := range sources.Wages {
+		add(Component{
+			Source:        SourceW2,
+			Name:          wage.Employer,
+			MonthlyIncome: roundCents(wage.MonthlyBase),
+			Description:   "Base pay",
+		})
+	}
+	for _, variable := range sources.Variable {
+		add(config.variable(variable))
+	}
+	for _, business := range sources.SelfEmployment {
+		add(config.selfEmployment(business))
+	}
+	for _, property := range sources.Rental {
+		add(config.rental(property))
+	}
+
+	result.MonthlyIncome = roundCents(result.MonthlyIncome)
+	result.MonthlyDebt = roundCents(result.MonthlyDebt)
+	return result
+}
+
+// variable averages bonus, overtime or commission income over the averaging period, from the
+// year to date back through the prior years
+func (c Config) variable(income VariableIncome) Component {
+	component := Component{Source: income.Type, Name: income.Employer}
+
+	history := income.YearToDateMonths + 12*len(income.PriorYears)
+	if history < c.MinVariableIncomeMonths {
+		component.Excluded = true
+		component.Description = fmt.Sprintf("%d months of history, %d required", history, c.MinVariableIncomeMonths)
+		return component
+	}
+
+	// Take the year to date, then whole prior years, and the part of a prior year that completes
+	// the averaging period
+	var total float64
+	months := 0
+	if income.YearToDateMonths > 0 {
+		total, months = income.YearToDate, income.YearToDateMonths
+	}
+	for _, year := range income.PriorYears {
+		if months >= c.VariableIncomeMonths {
+			break
+		}
+		take := min(12, c.VariableIncomeMonths-months)
+		total += year * float64(take) / 12
+		months += take
+	}
+	average := total / float64(months)
+	component.MonthlyIncome = roundCents(average)
+	component.Description = fmt.Sprintf("Averaged over %d months", months)
+
+	// A most recent year below the one before is taken instead of the average
+	recent, previous, ok := income.recentYears()
+	if ok && recent < previous && !c.AverageDecliningIncome && recent/12 < average {
+		component.MonthlyIncome = roundCents(recent / 12)
+		component.Description = "Declining; most recent year used"
+	}
+	return component
+}
+
+// recentYears returns the annual amounts of the most recent year, annualizing the year to date,
+// and of the year before it
+func (v VariableIncome) recentYears() (recent, previous float64, ok bool) {
+	if v.YearToDateMonths > 0 {
+		if len(v.PriorYears) == 0 {
+			return 0, 0, false
+		}
+		return v.YearToDate / float64(v.YearToDateMonths) * 12, v.PriorYears[0], true
+	}
+	if len(v.PriorYears) < 2 {
+		return 0, 0, false
+	}
+	return v.PriorYears[0], v.PriorYears[1], true
+}
+
+// selfEmployment averages a business's net profit over the averaging period. A loss reduces
+// income.
+func (c Config) selfEmployment(business SelfEmployment) Component {
+	component := Component{Source: SourceSelfEmployment, Name: business.Business}
+
+	if business.MonthsInBusiness < c.MinSelfEmploymentMonths || len(business.NetIncome) == 0 {
+		component.Excluded = true
+		component.Description = fmt.Sprintf("%d months in business, %d required", business.MonthsInBusiness, c.MinSelfEmploymentMonths)
+		return component
+	}
+
+	years := min(len(business.NetIncome), int(math.Ceil(float64(c.SelfEmploymentMonths)/12)))
+	var total float64
+	for _, net := range business.NetIncome[:years] {
+		total += net
+	}
+	component.MonthlyIncome = roundCents(total / float64(12*years))
+	component.Description = fmt.Sprintf("Net profit averaged over %d years", years)
+
+	if years > 1 && business.NetIncome[0] < business.NetIncome[1] && !c.AverageDecliningIncome {
+		component.MonthlyIncome = roundCents(business.NetIncome[0] / 12)
+		component.Description = "Declining; most recent year used"
+	}
+	return component
+}
+
+// rental counts a share of a property's gross rent less its payment. A shortfall counts as debt.
+func (c Config) rental(property RentalProperty) Component {
+	component := Component{
+		Source:      SourceRental,
+		Name:        property.Property,
+		Description: fmt.Sprintf("%.0f%% of gross rent less the property payment", c.RentalIncomeFactor*100),
+	}
+	net := roundCents(property.GrossMonthlyRent*c.RentalIncomeFactor - property.MonthlyPayment)
+	if net >= 0 {
+		component.MonthlyIncome = net
+	} else {
+		component.MonthlyDebt = -net
+	}
+	return component
+}
+
+// roundCents rounds an amount to cents
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,294 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// IdempotencyKeyHeader is the request header clients set to make a retried request safe
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set on responses replayed from a stored result
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength bounds client-supplied keys
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyRecord is the stored state of a request made with an idempotency key
+type IdempotencyRecord struct {
+	RequestHash string `json:"request_hash"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// IdempotencyStore persists idempotency records
+type IdempotencyStore interface {
+	// Reserve claims the key with an in-progress record. If the key is already held the existing
+	// record is returned and nothing is written.
+	Reserve(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, error)
+	// Complete replaces the reservation with the finished response
+	Complete(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error
+	// Release drops the reservation so the request can be retried
+	Release(ctx context.Context, key string) error
+}
+
+// IdempotencyConfig configures IdempotencyMiddleware
+type IdempotencyConfig struct {
+	Store IdempotencyStore
+	// KeyPrefix namespaces keys per service
+	KeyPrefix string
+	// TTL is how long a completed response is replayed; defaults to 24 hours
+	TTL time.Duration
+	// LockTimeout is how long an in-progress reservation survives a crashed request; defaults to one minute
+	LockTimeout time.Duration
+	Logger      *zap.Logger
+}
+
+// IdempotencyMiddleware replays the stored response when a request is retried with the same
+// Idempotency-Key header, and rejects reuse of a key with a different payload. Keys are scoped to
+// the route and, when authenticated, to the user. Requests without the header pass through.
+// Server errors are not stored, so a request that failed that way can be retried with the same key.
+func IdempotencyMiddleware(config IdempotencyConfig) gin.HandlerFunc {
+	if config.TTL <= 0 {
+		config.TTL = 24 * time.Hour
+	}
+	if config.LockTimeout <= 0 {
+		config.LockTimeout = time.Minute
+	}
+	if config.Logger == nil {
+		config.Logger = zap.NewNop()
+	}
+
+	return func(c *gin.Context) {
+		idempotencyKey := c.GetHeader(IdempotencyKeyHeader)
+		if idempotencyKey == "" {
+			c.Next()
+			return
+		}
+
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Idempotency key is too long",
+			})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Unable to read request body",
+			})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		key := config.KeyPrefix + ":" + c.Request.Method + ":" + c.Request.URL.Path + ":" + c.GetString("user_id") + ":" + idempotencyKey
+		logger := config.Logger.With(zap.String("idempotency_key", idempotencyKey), zap.String("path", c.Request.URL.Path))
+
+		existing, err := config.Store.Reserve(c.Request.Context(), key, &IdempotencyRecord{RequestHash: requestHash}, config.LockTimeout)
+		if err != nil {
+			// Without the store the request is handled as if no key had been sent
+			logger.Warn("Idempotency store unavailable, processing request without replay protection", zap.Error(err))
+			c.Next()
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.RequestHash != requestHash:
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"success": false,
+					"message": "Idempotency key was already used with a different request",
+				})
+			case !existing.Completed:
+				c.Header("Retry-After", "1")
+				c.JSON(http.StatusConflict, gin.H{
+					"success": false,
+					"message": "A request with this idempotency key is still in progress",
+				})
+			default:
+				logger.Info("Replaying stored response", zap.Int("status", existing.StatusCode))
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		// A detached context still records the outcome if the client went away mid-request
+		storeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			if err := config.Store.Release(storeCtx, key); err != nil {
+				logger.Warn("Failed to release idempotency key", zap.Error(err))
+			}
+			return
+		}
+
+		if err := config.Store.Complete(storeCtx, key, &IdempotencyRecord{
+			RequestHash: requestHash,
+			Completed:   true,
+			StatusCode:  status,
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		}, config.TTL); err != nil {
+			logger.Warn("Failed to store idempotent response", zap.Error(err))
+		}
+	}
+}
+
+// idempotencyWriter keeps a copy of the response body for storage
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// RedisIdempotencyStore keeps idempotency records in Redis
+type RedisIdempotencyStore struct {
+	client redis.UniversalClient
+}
+
+// NewRedisIdempotencyStore creates a Redis-backed idempotency store
+func NewRedisIdempotencyStore(client redis.UniversalClient) *RedisIdempotencyStore {
+	return &RedisIdempotencyStore{client: client}
+}
+
+func (s *RedisIdempotencyStore) Reserve(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	// A held key can expire between SETNX and GET, in which case the reservation is tried again
+	for attempt := 0; attempt < 2; attempt++ {
+		reserved, err := s.client.SetNX(ctx, key, data, ttl).Result()
+		if err != nil {
+			return nil, err
+		}
+		if reserved {
+			return nil, nil
+		}
+
+		stored, err := s.client.Get(ctx, key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var existing IdempotencyRecord
+		if err := json.Unmarshal(stored, &existing); err != nil {
+			return nil, err
+		}
+		return &existing, nil
+	}
+
+	return nil, errors.New("idempotency key could not be reserved")
+}
+
+func (s *RedisIdempotencyStore) Complete(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, key, data, ttl).Err()
+}
+
+func (s *RedisIdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, key).Err()
+}
+
+// MemoryIdempotencyStore keeps idempotency records in process memory, for single-instance
+// development setups without Redis
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]memoryIdempotencyEntry
+}
+
+type memoryIdempotencyEntry struct {
+	record    IdempotencyRecord
+	expiresAt time.Time
+}
+
+// NewMemoryIdempotencyStore creates an in-memory idempotency store
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: make(map[string]memoryIdempotencyEntry)}
+}
+
+func (s *MemoryIdempotencyStore) Reserve(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if entry, ok := s.records[key]; ok && now.Before(entry.expiresAt) {
+		existing := entry.record
+		return &existing, nil
+	}
+
+	// Expired entries are dropped lazily as new keys arrive
+	for k, entry := range s.records {
+		if !now.Before(entry.expiresAt) {
+			delete(s.records, k)
+		}
+	}
+
+	s.records[key] = memoryIdempotencyEntry{record: *record, expiresAt: now.Add(ttl)}
+	return nil, nil
+}
+
+func (s *MemoryIdempotencyStore) Complete(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[key] = memoryIdempotencyEntry{record: *record, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
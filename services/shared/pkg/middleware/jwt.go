@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// AccessClaims mirrors the claims the auth service puts in access tokens
+type AccessClaims struct {
+	UserID    string `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID string `json:"session_id"`
+	jwt.RegisteredClaims
+}
+
+// JWTAuthConfig configures JWTAuthMiddleware
+type JWTAuthConfig struct {
+	Secret   string
+	Audience string
+	// Unauthorized writes the 401 response; defaults to a plain JSON body
+	Unauthorized gin.HandlerFunc
+}
+
+// ParseAccessToken verifies an HS256 access token and returns its claims
+func ParseAccessToken(tokenString, secret, audience string) (*AccessClaims, error) {
+	options := []jwt.ParserOption{jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()})}
+	if audience != "" {
+		options = append(options, jwt.WithAudience(audience))
+	}
+
+	claims := &AccessClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid || claims.UserID == "" {
+		return nil, fmt.Errorf("invalid access token")
+	}
+
+	return claims, nil
+}
+
+// JWTAuthMiddleware validates bearer access tokens and maps their claims to
+// user_id, user_email and user_role in the gin context
+func JWTAuthMiddleware(config JWTAuthConfig) gin.HandlerFunc {
+	unauthorized := config.Unauthorized
+	if unauthorized == nil {
+		unauthorized = func(c *gin.Context) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"message": "Invalid authorization token",
+			})
+		}
+	}
+
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		token := strings.TrimPrefix(header, "Bearer ")
+		if header == "" || token == header {
+			unauthorized(c)
+			c.Abort()
+			return
+		}
+
+		claims, err := ParseAccessToken(token, config.Secret, config.Audience)
+		if err != nil {
+			unauthorized(c)
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", claims.UserID)
+		c.Set("user_email", claims.Email)
+		c.Set("user_role", claims.Role)
+
+		c.Next()
+	}
+}
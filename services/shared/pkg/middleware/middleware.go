@@ -45,12 +45,14 @@ func CORSMiddleware() gin.HandlerFunc {
 		"X-Request-ID",
 		"X-Language",
 		"X-Requested-With",
+		IdempotencyKeyHeader,
 	}
 	config.ExposeHeaders = []string{
 		"X-Request-ID",
 		"X-Total-Count",
 		"X-Page",
 		"X-Per-Page",
+		IdempotentReplayedHeader,
 	}
 	config.AllowCredentials = true
 	config.MaxAge = 12 * time.Hour
@@ -0,0 +1,244 @@
+package payroll
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// argyleSignatureHeader carries the HMAC of a webhook body
+const argyleSignatureHeader = "X-Argyle-Signature"
+
+// argyleReadyEvents are the webhook events after which a user's pay data can be read
+var argyleReadyEvents = map[string]bool{
+	"paystubs.fully_synced": true,
+}
+
+// ArgyleClient verifies income with the Argyle API: the borrower connects their payroll account
+// in Argyle Link with a user token, and Argyle syncs their paystubs and employments
+type ArgyleClient struct {
+	baseURL       string
+	clientID      string
+	clientSecret  string
+	webhookSecret string
+	httpClient    *http.Client
+	logger        *zap.Logger
+}
+
+// NewArgyleClient creates a new Argyle client
+func NewArgyleClient(baseURL, clientID, clientSecret, webhookSecret string, timeout time.Duration, logger *zap.Logger) *ArgyleClient {
+	if baseURL == "" {
+		baseURL = "https://api-sandbox.argyle.com"
+	}
+	return &ArgyleClient{
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		clientID:      clientID,
+		clientSecret:  clientSecret,
+		webhookSecret: webhookSecret,
+		httpClient:    &http.Client{Timeout: timeout},
+		logger:        logger,
+	}
+}
+
+// Name returns the provider name recorded on verifications
+func (c *ArgyleClient) Name() string {
+	return ProviderArgyle
+}
+
+// CreateSession creates an Argyle user for the borrower and returns its user token for Link
+func (c *ArgyleClient) CreateSession(ctx context.Context, externalID string) (*Session, error) {
+	payload, err := json.Marshal(map[string]string{"external_id": externalID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode user: %w", err)
+	}
+
+	var result struct {
+		ID        string `json:"id"`
+		UserToken string `json:"user_token"`
+	}
+	if err := c.do(ctx, http.MethodPost, c.baseURL+"/v2/users", bytes.NewReader(payload), &result); err != nil {
+		return nil, err
+	}
+	if result.ID == "" {
+		return nil, fmt.Errorf("Argyle returned no user ID")
+	}
+
+	c.logger.Info("Argyle user created", zap.String("argyle_user_id", result.ID))
+	return &Session{ProviderUserID: result.ID, ConnectToken: result.UserToken}, nil
+}
+
+// GetPaystubs lists the user's paystubs paid since the given date
+func (c *ArgyleClient) GetPaystubs(ctx context.Context, providerUserID string, since time.Time) ([]Paystub, error) {
+	query := url.Values{"user": {providerUserID}, "limit": {"200"}}
+	if !since.IsZero() {
+		query.Set("from_start_date", since.Format("2006-01-02"))
+	}
+
+	paystubs := make([]Paystub, 0)
+	next := c.baseURL + "/v2/paystubs?" + query.Encode()
+	for next != "" {
+		var page struct {
+			Results []struct {
+				Employer      string    `json:"employer"`
+				GrossPay      string    `json:"gross_pay"`
+				PaystubDate   time.Time `json:"paystub_date"`
+				PaystubPeriod struct {
+					StartDate string `json:"start_date"`
+					EndDate   string `json:"end_date"`
+				} `json:"paystub_period"`
+			} `json:"results"`
+			Next *string `json:"next"`
+		}
+		if err := c.do(ctx, http.MethodGet, next, nil, &page); err != nil {
+			return nil, err
+		}
+
+		for _, result := range page.Results {
+			gross, _ := strconv.ParseFloat(result.GrossPay, 64)
+			paystubs = append(paystubs, Paystub{
+				Employer:    result.Employer,
+				GrossPay:    gross,
+				PayDate:     result.PaystubDate,
+				PeriodStart: argyleDate(result.PaystubPeriod.StartDate),
+				PeriodEnd:   argyleDate(result.PaystubPeriod.EndDate),
+			})
+		}
+
+		next = ""
+		if page.Next != nil {
+			next = *page.Next
+		}
+	}
+	return paystubs, nil
+}
+
+// GetEmployments lists the user's employments
+func (c *ArgyleClient) GetEmployments(ctx context.Context, providerUserID string) ([]Employment, error) {
+	var page struct {
+		Results []struct {
+			Employer     string    `json:"employer"`
+			JobTitle     string    `json:"job_title"`
+			Status       string    `json:"status"`
+			Type         string    `json:"type"`
+			HireDatetime time.Time `json:"hire_datetime"`
+			PayCycle     string    `json:"pay_cycle"`
+			BasePay      struct {
+				Amount string `json:"amount"`
+				Period string `json:"period"`
+			} `json:"base_pay"`
+		} `json:"results"`
+	}
+	endpoint := c.baseURL + "/v2/employments?" + url.Values{"user": {providerUserID}}.Encode()
+	if err := c.do(ctx, http.MethodGet, endpoint, nil, &page); err != nil {
+		return nil, err
+	}
+
+	employments := make([]Employment, 0, len(page.Results))
+	for _, result := range page.Results {
+		basePay, _ := strconv.ParseFloat(result.BasePay.Amount, 64)
+		employments = append(employments, Employment{
+			Employer:       result.Employer,
+			JobTitle:       result.JobTitle,
+			Status:         result.Status,
+			EmploymentType: result.Type,
+			HireDate:       result.HireDatetime,
+			BasePay:        basePay,
+			BasePayPeriod:  result.BasePay.Period,
+			PayCycle:       result.PayCycle,
+		})
+	}
+	return employments, nil
+}
+
+// ParseWebhook verifies a webhook's HMAC signature and returns the event it reports. Events other
+// than a completed paystub sync are returned as nil.
+func (c *ArgyleClient) ParseWebhook(body []byte, header func(string) string) (*Event, error) {
+	if c.webhookSecret == "" {
+		return nil, fmt.Errorf("webhook secret is not configured")
+	}
+
+	signature, err := hex.DecodeString(header(argyleSignatureHeader))
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook signature encoding: %w", err)
+	}
+	mac := hmac.New(sha512.New, []byte(c.webhookSecret))
+	mac.Write(body)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, fmt.Errorf("webhook signature mismatch")
+	}
+
+	var notification struct {
+		Event string `json:"event"`
+		Data  struct {
+			User string `json:"user"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook: %w", err)
+	}
+
+	if !argyleReadyEvents[notification.Event] {
+		return nil, nil
+	}
+	if notification.Data.User == "" {
+		return nil, fmt.Errorf("webhook has no user ID")
+	}
+
+	return &Event{
+		Name:           notification.Event,
+		ProviderUserID: notification.Data.User,
+		OccurredAt:     time.Now().UTC(),
+	}, nil
+}
+
+// do sends an authenticated request and decodes the JSON response into result
+func (c *ArgyleClient) do(ctx context.Context, method, endpoint string, body io.Reader, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to build Argyle request: %w", err)
+	}
+	req.SetBasicAuth(c.clientID, c.clientSecret)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Argyle request failed", zap.String("method", method), zap.Error(err))
+		return fmt.Errorf("failed to call Argyle: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		c.logger.Error("Unexpected Argyle response",
+			zap.String("method", method),
+			zap.Int("status", resp.StatusCode),
+			zap.ByteString("body", respBody))
+		return fmt.Errorf("unexpected Argyle status: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode Argyle response: %w", err)
+	}
+	return nil
+}
+
+// argyleDate parses a date-only field, returning the zero time when it is empty or malformed
+func argyleDate(value string) time.Time {
+	date, _ := time.Parse("2006-01-02", value)
+	return date
+}
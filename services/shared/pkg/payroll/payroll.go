@@ -0,0 +1,234 @@
+package payroll
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ProviderArgyle identifies the Argyle payroll aggregator
+const ProviderArgyle = "argyle"
+
+// DefaultVarianceTolerance is the share verified income may differ from stated income before the
+// difference is flagged for an underwriter
+const DefaultVarianceTolerance = 0.10
+
+// ErrNoIncomeData is returned when the borrower has not connected a payroll account yet, or the
+// provider has not synced any pay data from it
+var ErrNoIncomeData = errors.New("no payroll income data")
+
+// Config holds payroll aggregator configuration
+type Config struct {
+	Provider          string  `yaml:"provider" json:"provider"` // argyle; empty disables payroll verification
+	BaseURL           string  `yaml:"base_url" json:"base_url"`
+	ClientID          string  `yaml:"client_id" json:"-"`
+	ClientSecret      string  `yaml:"client_secret" json:"-"`
+	WebhookSecret     string  `yaml:"webhook_secret" json:"-"`                      // HMAC key for webhook signatures
+	Timeout           int     `yaml:"timeout" json:"timeout"`                       // seconds
+	VarianceTolerance float64 `yaml:"variance_tolerance" json:"variance_tolerance"` // e.g. 0.10 flags differences over 10%
+}
+
+// Session is a payroll verification started for a borrower. The borrower connects their payroll
+// account in the provider's widget with the connect token; the provider then syncs their pay data.
+type Session struct {
+	ProviderUserID string `json:"provider_user_id"`
+	ConnectToken   string `json:"connect_token"`
+}
+
+// Paystub is a pay statement synced from the borrower's payroll account
+type Paystub struct {
+	Employer    string    `json:"employer"`
+	GrossPay    float64   `json:"gross_pay"`
+	PayDate     time.Time `json:"pay_date"`
+	PeriodStart time.Time `json:"period_start,omitempty"`
+	PeriodEnd   time.Time `json:"period_end,omitempty"`
+}
+
+// Employment is an employment record synced from the borrower's payroll account
+type Employment struct {
+	Employer       string    `json:"employer"`
+	JobTitle       string    `json:"job_title"`
+	Status         string    `json:"status"` // active, inactive, terminated
+	EmploymentType string    `json:"employment_type"`
+	HireDate       time.Time `json:"hire_date,omitempty"`
+	BasePay        float64   `json:"base_pay"`
+	BasePayPeriod  string    `json:"base_pay_period"` // annual, monthly, biweekly, weekly, hourly
+	PayCycle       string    `json:"pay_cycle"`
+}
+
+// Event is a provider notification that a borrower's pay data has finished syncing
+type Event struct {
+	Name           string    `json:"name"`
+	ProviderUserID string    `json:"provider_user_id"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// Provider verifies income from a borrower's payroll account
+type Provider interface {
+	Name() string
+	// CreateSession registers the borrower with the provider under externalID
+	CreateSession(ctx context.Context, externalID string) (*Session, error)
+	GetPaystubs(ctx context.Context, providerUserID string, since time.Time) ([]Paystub, error)
+	GetEmployments(ctx context.Context, providerUserID string) ([]Employment, error)
+	// ParseWebhook verifies a provider notification and returns the event it reports, or nil for
+	// events that do not mean pay data is ready
+	ParseWebhook(body []byte, header func(string) string) (*Event, error)
+}
+
+// NewProvider creates the provider selected by configuration, or nil when payroll verification is
+// disabled
+func NewProvider(config Config, logger *zap.Logger) Provider {
+	if strings.EqualFold(config.Provider, ProviderArgyle) {
+		timeout := time.Duration(config.Timeout) * time.Second
+		if timeout == 0 {
+			timeout = 10 * time.Second
+		}
+		return NewArgyleClient(config.BaseURL, config.ClientID, config.ClientSecret, config.WebhookSecret, timeout, logger)
+	}
+	return nil
+}
+
+// Income is the income verified from payroll data and how it compares with the stated income
+type Income struct {
+	VerifiedAnnualIncome  float64   `json:"verified_annual_income"`
+	VerifiedMonthlyIncome float64   `json:"verified_monthly_income"`
+	StatedAnnualIncome    float64   `json:"stated_annual_income"`
+	Variance              float64   `json:"variance"`         // verified minus stated
+	VariancePercent       float64   `json:"variance_percent"` // of stated income
+	VarianceFlagged       bool      `json:"variance_flagged"` // outside the tolerance
+	Basis                 string    `json:"basis"`            // paystubs or base_pay
+	PaystubCount          int       `json:"paystub_count"`
+	PayFrequency          string    `json:"pay_frequency"`
+	LastPayDate           time.Time `json:"last_pay_date,omitempty"`
+	Employer              string    `json:"employer"`
+	JobTitle              string    `json:"job_title"`
+	EmploymentType        string    `json:"employment_type"`
+	HireDate              time.Time `json:"hire_date,omitempty"`
+}
+
+// Pay periods per year by pay frequency
+var periodsPerYear = map[string]float64{
+	"weekly":      52,
+	"biweekly":    26,
+	"semimonthly": 24,
+	"monthly":     12,
+	"annual":      1,
+	"hourly":      2080, // full-time hours
+}
+
+// ComputeIncome annualizes the borrower's gross pay and compares it with the stated annual income.
+// Pay from the last 12 months of paystubs is averaged per pay period and annualized by the pay
+// frequency the pay dates show; with fewer than two paystubs, the base pay of the active
+// employment is used. tolerance is the share the two may differ before the difference is flagged.
+func ComputeIncome(paystubs []Paystub, employments []Employment, statedAnnualIncome, tolerance float64) (*Income, error) {
+	if tolerance <= 0 {
+		tolerance = DefaultVarianceTolerance
+	}
+
+	income := &Income{StatedAnnualIncome: statedAnnualIncome}
+	employment := currentEmployment(employments)
+	if employment != nil {
+		income.Employer = employment.Employer
+		income.JobTitle = employment.JobTitle
+		income.EmploymentType = employment.EmploymentType
+		income.HireDate = employment.HireDate
+	}
+
+	recent := recentPaystubs(paystubs)
+	switch {
+	case len(recent) >= 2:
+		frequency := payFrequency(recent)
+		var gross float64
+		for _, stub := range recent {
+			gross += stub.GrossPay
+		}
+		income.VerifiedAnnualIncome = gross / float64(len(recent)) * periodsPerYear[frequency]
+		income.Basis = "paystubs"
+		income.PaystubCount = len(recent)
+		income.PayFrequency = frequency
+		income.LastPayDate = recent[0].PayDate
+		if income.Employer == "" {
+			income.Employer = recent[0].Employer
+		}
+	case employment != nil && employment.BasePay > 0 && periodsPerYear[strings.ToLower(employment.BasePayPeriod)] > 0:
+		income.VerifiedAnnualIncome = employment.BasePay * periodsPerYear[strings.ToLower(employment.BasePayPeriod)]
+		income.Basis = "base_pay"
+		income.PaystubCount = len(recent)
+		income.PayFrequency = strings.ToLower(employment.PayCycle)
+	default:
+		return nil, ErrNoIncomeData
+	}
+
+	income.VerifiedAnnualIncome = math.Round(income.VerifiedAnnualIncome*100) / 100
+	income.VerifiedMonthlyIncome = math.Round(income.VerifiedAnnualIncome/12*100) / 100
+	income.Variance = math.Round((income.VerifiedAnnualIncome-statedAnnualIncome)*100) / 100
+	if statedAnnualIncome > 0 {
+		income.VariancePercent = math.Round(income.Variance/statedAnnualIncome*10000) / 100
+		income.VarianceFlagged = math.Abs(income.Variance/statedAnnualIncome) > tolerance
+	}
+	return income, nil
+}
+
+// recentPaystubs returns the paystubs from the 12 months before the latest pay date with gross
+// pay, newest first
+func recentPaystubs(paystubs []Paystub) []Paystub {
+	recent := make([]Paystub, 0, len(paystubs))
+	for _, stub := range paystubs {
+		if stub.GrossPay > 0 && !stub.PayDate.IsZero() {
+			recent = append(recent, stub)
+		}
+	}
+	sort.Slice(recent, func(i, j int) bool { return recent[i].PayDate.After(recent[j].PayDate) })
+	if len(recent) == 0 {
+		return recent
+	}
+
+	cutoff := recent[0].PayDate.AddDate(-1, 0, 0)
+	for i, stub := range recent {
+		if !stub.PayDate.After(cutoff) {
+			return recent[:i]
+		}
+	}
+	return recent
+}
+
+// payFrequency infers the pay frequency from the median days between pay dates, newest first
+func payFrequency(paystubs []Paystub) string {
+	gaps := make([]float64, 0, len(paystubs)-1)
+	for i := 1; i < len(paystubs); i++ {
+		gaps = append(gaps, paystubs[i-1].PayDate.Sub(paystubs[i].PayDate).Hours()/24)
+	}
+	sort.Float64s(gaps)
+	median := gaps[len(gaps)/2]
+
+	switch {
+	case median <= 10:
+		return "weekly"
+	case median <= 14.5:
+		return "biweekly"
+	case median <= 20:
+		return "semimonthly"
+	default:
+		return "monthly"
+	}
+}
+
+// currentEmployment returns the active employment, or the most recently started one
+func currentEmployment(employments []Employment) *Employment {
+	var current *Employment
+	for i := range employments {
+		employment := &employments[i]
+		if strings.EqualFold(employment.Status, "active") {
+			return employment
+		}
+		if current == nil || employment.HireDate.After(current.HireDate) {
+			current = employment
+		}
+	}
+	return current
+}
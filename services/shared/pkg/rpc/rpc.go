@@ -0,0 +1,222 @@
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/config"
+)
+
+// Config holds gRPC transport configuration shared by internal servers and clients
+type Config = config.GRPCConfig
+
+// NewServer creates a gRPC server with mTLS, panic recovery and request logging
+func NewServer(config Config, logger *zap.Logger, opts ...grpc.ServerOption) (*grpc.Server, error) {
+	if config.TLSEnabled() {
+		tlsConfig, err := loadTLSConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		tlsConfig.ClientCAs = tlsConfig.RootCAs
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	} else {
+		logger.Warn("gRPC server running without TLS")
+	}
+
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(recoveryInterceptor(logger), loggingInterceptor(logger)),
+		grpc.KeepaliveParams(keepalive.ServerParameters{MaxConnectionIdle: 5 * time.Minute}),
+	)
+	return grpc.NewServer(opts...), nil
+}
+
+// Dial creates a client connection to an internal service. Calls without a deadline get the
+// configured timeout, and calls failing with UNAVAILABLE are retried with backoff.
+func Dial(target string, config Config, logger *zap.Logger, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	if config.TLSEnabled() {
+		tlsConfig, err := loadTLSConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		logger.Warn("gRPC client connecting without TLS", zap.String("target", target))
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	timeout := time.Duration(config.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	opts = append(opts,
+		grpc.WithDefaultServiceConfig(retryServiceConfig(config.MaxAttempts)),
+		grpc.WithChainUnaryInterceptor(deadlineInterceptor(timeout)),
+	)
+
+	conn, err := grpc.Dial(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", target, err)
+	}
+	return conn, nil
+}
+
+// loadTLSConfig loads the service's certificate and the CA both sides verify against
+func loadTLSConfig(config Config) (*tls.Config, error) {
+	certificate, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load gRPC certificate: %w", err)
+	}
+
+	ca, err := os.ReadFile(config.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gRPC CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("no certificates found in gRPC CA %s", config.CAFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		RootCAs:      pool,
+		ServerName:   config.ServerName,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// retryServiceConfig retries every method on UNAVAILABLE with exponential backoff, up to the
+// configured attempts; one attempt or fewer disables retries
+func retryServiceConfig(maxAttempts int) string {
+	if maxAttempts < 2 {
+		return `{"methodConfig": [{"name": [{}]}]}`
+	}
+	if maxAttempts > 5 {
+		maxAttempts = 5 // gRPC caps retry attempts at five
+	}
+	return fmt.Sprintf(`{
+		"methodConfig": [{
+			"name": [{}],
+			"retryPolicy": {
+				"maxAttempts": %d,
+				"initialBackoff": "0.1s",
+				"maxBackoff": "2s",
+				"backoffMultiplier": 2,
+				"retryableStatusCodes": ["UNAVAILABLE"]
+			}
+		}]
+	}`, maxAttempts)
+}
+
+// deadlineInterceptor bounds calls made without a deadline
+func deadlineInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// recoveryInterceptor turns a handler panic into an INTERNAL error
+func recoveryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("gRPC handler panicked",
+					zap.String("method", info.FullMethod),
+					zap.Any("panic", r),
+					zap.String("stack", string(debug.Stack())))
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// loggingInterceptor logs each call with its status and latency
+func loggingInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		fields := []zap.Field{
+			zap.String("method", info.FullMethod),
+			zap.String("code", status.Code(err).String()),
+			zap.Duration("latency", time.Since(start)),
+		}
+		if err != nil {
+			logger.Warn("gRPC request failed", append(fields, zap.Error(err))...)
+		} else {
+			logger.Info("gRPC request", fields...)
+		}
+		return resp, err
+	}
+}
+
+// CodeFromHTTPStatus maps the HTTP status of a service error to the gRPC code reporting it, so
+// errors read the same over REST and gRPC
+func CodeFromHTTPStatus(status int) codes.Code {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
+
+// HTTPStatusFromCode maps a gRPC code back to the HTTP status a REST caller would have seen
+func HTTPStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.FailedPrecondition, codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
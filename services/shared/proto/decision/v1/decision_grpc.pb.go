@@ -0,0 +1,148 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.24.4
+// source: decision/v1/decision.proto
+
+// DecisionService runs the decision engine's credit policy for an application
+
+package decisionv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	DecisionService_MakeDecision_FullMethodName = "/los.decision.v1.DecisionService/MakeDecision"
+	DecisionService_GetDecision_FullMethodName  = "/los.decision.v1.DecisionService/GetDecision"
+)
+
+// DecisionServiceClient is the client API for DecisionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DecisionServiceClient interface {
+	MakeDecision(ctx context.Context, in *MakeDecisionRequest, opts ...grpc.CallOption) (*Decision, error)
+	GetDecision(ctx context.Context, in *GetDecisionRequest, opts ...grpc.CallOption) (*Decision, error)
+}
+
+type decisionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDecisionServiceClient(cc grpc.ClientConnInterface) DecisionServiceClient {
+	return &decisionServiceClient{cc}
+}
+
+func (c *decisionServiceClient) MakeDecision(ctx context.Context, in *MakeDecisionRequest, opts ...grpc.CallOption) (*Decision, error) {
+	out := new(Decision)
+	err := c.cc.Invoke(ctx, DecisionService_MakeDecision_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decisionServiceClient) GetDecision(ctx context.Context, in *GetDecisionRequest, opts ...grpc.CallOption) (*Decision, error) {
+	out := new(Decision)
+	err := c.cc.Invoke(ctx, DecisionService_GetDecision_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DecisionServiceServer is the server API for DecisionService service.
+// All implementations must embed UnimplementedDecisionServiceServer
+// for forward compatibility
+type DecisionServiceServer interface {
+	MakeDecision(context.Context, *MakeDecisionRequest) (*Decision, error)
+	GetDecision(context.Context, *GetDecisionRequest) (*Decision, error)
+	mustEmbedUnimplementedDecisionServiceServer()
+}
+
+// UnimplementedDecisionServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedDecisionServiceServer struct {
+}
+
+func (UnimplementedDecisionServiceServer) MakeDecision(context.Context, *MakeDecisionRequest) (*Decision, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MakeDecision not implemented")
+}
+func (UnimplementedDecisionServiceServer) GetDecision(context.Context, *GetDecisionRequest) (*Decision, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDecision not implemented")
+}
+func (UnimplementedDecisionServiceServer) mustEmbedUnimplementedDecisionServiceServer() {}
+
+// UnsafeDecisionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DecisionServiceServer will
+// result in compilation errors.
+type UnsafeDecisionServiceServer interface {
+	mustEmbedUnimplementedDecisionServiceServer()
+}
+
+func RegisterDecisionServiceServer(s grpc.ServiceRegistrar, srv DecisionServiceServer) {
+	s.RegisterService(&DecisionService_ServiceDesc, srv)
+}
+
+func _DecisionService_MakeDecision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MakeDecisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecisionServiceServer).MakeDecision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DecisionService_MakeDecision_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecisionServiceServer).MakeDecision(ctx, req.(*MakeDecisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DecisionService_GetDecision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDecisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecisionServiceServer).GetDecision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DecisionService_GetDecision_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecisionServiceServer).GetDecision(ctx, req.(*GetDecisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DecisionService_ServiceDesc is the grpc.ServiceDesc for DecisionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DecisionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "los.decision.v1.DecisionService",
+	HandlerType: (*DecisionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "MakeDecision",
+			Handler:    _DecisionService_MakeDecision_Handler,
+		},
+		{
+			MethodName: "GetDecision",
+			Handler:    _DecisionService_GetDecision_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "decision/v1/decision.proto",
+}
@@ -0,0 +1,16 @@
+// Package proto holds the protobuf contracts between internal services: UserService,
+// DecisionService and LoanService. REST remains the API for external clients.
+//
+// loan-api reads decisions through DecisionService; LoanService and UserService are served for
+// internal callers. The underwriting worker keeps calling the decision engine over REST: its
+// decisions carry the stages, adverse action reasons, policy version and debt and income
+// inputs, and it uses the fraud screening and policy endpoints, none of which DecisionService
+// defines.
+//
+// The generated Go stubs are checked in; regenerate them with protoc, protoc-gen-go and
+// protoc-gen-go-grpc on the PATH:
+//
+//	go generate ./shared/proto/...
+package proto
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative user/v1/user.proto decision/v1/decision.proto loan/v1/loan.proto
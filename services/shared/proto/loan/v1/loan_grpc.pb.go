@@ -0,0 +1,186 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.24.4
+// source: loan/v1/loan.proto
+
+// LoanService lets the workers and the decision engine read and move loan applications without
+// going through the public REST API
+
+package loanv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	LoanService_GetApplication_FullMethodName         = "/los.loan.v1.LoanService/GetApplication"
+	LoanService_UpdateApplicationState_FullMethodName = "/los.loan.v1.LoanService/UpdateApplicationState"
+	LoanService_GetOffer_FullMethodName               = "/los.loan.v1.LoanService/GetOffer"
+)
+
+// LoanServiceClient is the client API for LoanService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LoanServiceClient interface {
+	GetApplication(ctx context.Context, in *GetApplicationRequest, opts ...grpc.CallOption) (*Application, error)
+	UpdateApplicationState(ctx context.Context, in *UpdateApplicationStateRequest, opts ...grpc.CallOption) (*Application, error)
+	GetOffer(ctx context.Context, in *GetOfferRequest, opts ...grpc.CallOption) (*Offer, error)
+}
+
+type loanServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLoanServiceClient(cc grpc.ClientConnInterface) LoanServiceClient {
+	return &loanServiceClient{cc}
+}
+
+func (c *loanServiceClient) GetApplication(ctx context.Context, in *GetApplicationRequest, opts ...grpc.CallOption) (*Application, error) {
+	out := new(Application)
+	err := c.cc.Invoke(ctx, LoanService_GetApplication_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanServiceClient) UpdateApplicationState(ctx context.Context, in *UpdateApplicationStateRequest, opts ...grpc.CallOption) (*Application, error) {
+	out := new(Application)
+	err := c.cc.Invoke(ctx, LoanService_UpdateApplicationState_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanServiceClient) GetOffer(ctx context.Context, in *GetOfferRequest, opts ...grpc.CallOption) (*Offer, error) {
+	out := new(Offer)
+	err := c.cc.Invoke(ctx, LoanService_GetOffer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LoanServiceServer is the server API for LoanService service.
+// All implementations must embed UnimplementedLoanServiceServer
+// for forward compatibility
+type LoanServiceServer interface {
+	GetApplication(context.Context, *GetApplicationRequest) (*Application, error)
+	UpdateApplicationState(context.Context, *UpdateApplicationStateRequest) (*Application, error)
+	GetOffer(context.Context, *GetOfferRequest) (*Offer, error)
+	mustEmbedUnimplementedLoanServiceServer()
+}
+
+// UnimplementedLoanServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedLoanServiceServer struct {
+}
+
+func (UnimplementedLoanServiceServer) GetApplication(context.Context, *GetApplicationRequest) (*Application, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetApplication not implemented")
+}
+func (UnimplementedLoanServiceServer) UpdateApplicationState(context.Context, *UpdateApplicationStateRequest) (*Application, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateApplicationState not implemented")
+}
+func (UnimplementedLoanServiceServer) GetOffer(context.Context, *GetOfferRequest) (*Offer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOffer not implemented")
+}
+func (UnimplementedLoanServiceServer) mustEmbedUnimplementedLoanServiceServer() {}
+
+// UnsafeLoanServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LoanServiceServer will
+// result in compilation errors.
+type UnsafeLoanServiceServer interface {
+	mustEmbedUnimplementedLoanServiceServer()
+}
+
+func RegisterLoanServiceServer(s grpc.ServiceRegistrar, srv LoanServiceServer) {
+	s.RegisterService(&LoanService_ServiceDesc, srv)
+}
+
+func _LoanService_GetApplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetApplicationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServiceServer).GetApplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanService_GetApplication_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServiceServer).GetApplication(ctx, req.(*GetApplicationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanService_UpdateApplicationState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateApplicationStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServiceServer).UpdateApplicationState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanService_UpdateApplicationState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServiceServer).UpdateApplicationState(ctx, req.(*UpdateApplicationStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoanService_GetOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServiceServer).GetOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoanService_GetOffer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServiceServer).GetOffer(ctx, req.(*GetOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LoanService_ServiceDesc is the grpc.ServiceDesc for LoanService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LoanService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "los.loan.v1.LoanService",
+	HandlerType: (*LoanServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetApplication",
+			Handler:    _LoanService_GetApplication_Handler,
+		},
+		{
+			MethodName: "UpdateApplicationState",
+			Handler:    _LoanService_UpdateApplicationState_Handler,
+		},
+		{
+			MethodName: "GetOffer",
+			Handler:    _LoanService_GetOffer_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "loan/v1/loan.proto",
+}
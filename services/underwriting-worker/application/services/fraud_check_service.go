@@ -0,0 +1,377 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// velocityWindow is how far back applications sharing identity elements are counted
+const velocityWindow = 90 * 24 * time.Hour
+
+// Fraud risk score thresholds
+const (
+	fraudMediumRiskScore = 25.0
+	fraudHighRiskScore   = 50.0
+	fraudDenyScore       = 80.0
+)
+
+// disposableEmailDomains are throwaway email providers; subdomains are matched too
+var disposableEmailDomains = map[string]bool{
+	"10minutemail.com": true, "20minutemail.com": true, "burnermail.io": true, "dispostable.com": true,
+	"emailondeck.com": true, "fakeinbox.com": true, "getairmail.com": true, "getnada.com": true,
+	"guerrillamail.com": true, "guerrillamail.net": true, "guerrillamailblock.com": true,
+	"mailcatch.com": true, "maildrop.cc": true, "mailinator.com": true, "mailnesia.com": true,
+	"mintemail.com": true, "mohmal.com": true, "mytemp.email": true, "sharklasers.com": true,
+	"spamgourmet.com": true, "temp-mail.org": true, "tempail.com": true, "tempmail.com": true,
+	"tempmailo.com": true, "throwawaymail.com": true, "trashmail.com": true, "yopmail.com": true,
+}
+
+// FraudCheckService assesses applications for fraud from the identity elements the loan service
+// records: devices and SSNs, phone numbers and email addresses shared with other borrowers,
+// throwaway email providers and fraud alerts on the borrower's credit file. The decision engine's
+// fraud vendor screening is added when it is configured. Checks whose data is not available are
+// skipped rather than failed.
+type FraudCheckService struct {
+	logger        *zap.Logger
+	signals       domain.FraudSignalRepository
+	creditReports domain.CreditReportRepository
+	reports       domain.FraudReportRepository
+	vendor        domain.FraudDetectionService
+}
+
+// NewFraudCheckService creates a new fraud check service. Any dependency may be nil.
+func NewFraudCheckService(
+	logger *zap.Logger,
+	signals domain.FraudSignalRepository,
+	creditReports domain.CreditReportRepository,
+	reports domain.FraudReportRepository,
+	vendor domain.FraudDetectionService,
+) *FraudCheckService {
+	return &FraudCheckService{
+		logger:        logger,
+		signals:       signals,
+		creditReports: creditReports,
+		reports:       reports,
+		vendor:        vendor,
+	}
+}
+
+// Assess runs the fraud checks on an application and saves the resulting fraud report. Identity
+// elements the loan service has not recorded are taken from the request.
+func (s *FraudCheckService) Assess(ctx context.Context, request *domain.FraudScreeningRequest) (*domain.FraudReport, error) {
+	logger := s.logger.With(zap.String("application_id", request.ApplicationID))
+
+	identity, err := s.identity(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var velocity *domain.IdentityVelocity
+	if s.signals != nil {
+		if velocity, err = s.signals.GetVelocity(ctx, identity, time.Now().Add(-velocityWindow)); err != nil {
+			return nil, err
+		}
+	}
+
+	var creditReport *domain.CreditReport
+	if s.creditReports != nil {
+		// The credit check may not have run yet; the bureau alert check is skipped then
+		if creditReport, err = s.creditReports.GetByApplicationID(ctx, request.ApplicationID); err != nil {
+			logger.Debug("No credit report for bureau fraud alerts", zap.Error(err))
+			creditReport = nil
+		}
+	}
+
+	report := &domain.FraudReport{
+		ID:            request.ApplicationID + "_fraud_report",
+		ApplicationID: request.ApplicationID,
+		UserID:        identity.UserID,
+		Checks: []domain.FraudCheck{
+			checkDevice(identity, velocity),
+			checkIdentityVelocity(identity, velocity),
+			checkDisposableEmail(identity),
+			checkBureauAlerts(creditReport, time.Now()),
+		},
+		CreatedAt: time.Now().UTC(),
+	}
+	if s.vendor != nil {
+		report.Checks = append(report.Checks, s.screenWithVendor(ctx, logger, request, identity, report))
+	}
+	scoreFraudReport(report)
+
+	if s.reports != nil {
+		if err := s.reports.Create(ctx, report); err != nil {
+			return nil, fmt.Errorf("failed to save fraud report: %w", err)
+		}
+	}
+
+	logger.Info("Fraud checks completed",
+		zap.Float64("fraud_risk_score", report.RiskScore),
+		zap.String("fraud_risk_level", string(report.RiskLevel)),
+		zap.String("recommendation", report.Recommendation),
+		zap.Strings("reason_codes", report.ReasonCodes))
+	return report, nil
+}
+
+// identity returns the identity the application was made with, completed from the request
+func (s *FraudCheckService) identity(ctx context.Context, request *domain.FraudScreeningRequest) (*domain.ApplicationIdentity, error) {
+	identity := &domain.ApplicationIdentity{ApplicationID: request.ApplicationID, UserID: request.UserID}
+	if s.signals != nil {
+		recorded, err := s.signals.GetIdentity(ctx, request.ApplicationID)
+		if err != nil {
+			return nil, err
+		}
+		identity = recorded
+	}
+	if identity.SSNToken == "" {
+		identity.SSNToken = request.SSNToken
+	}
+	if identity.Email == "" {
+		identity.Email = strings.ToLower(strings.TrimSpace(request.Email))
+	}
+	if identity.Phone == "" {
+		identity.Phone = request.Phone
+	}
+	if identity.DeviceID == "" {
+		identity.DeviceID = request.DeviceID
+	}
+	return identity, nil
+}
+
+// checkDevice flags a device other borrowers applied from, or that many applications came from
+func checkDevice(identity *domain.ApplicationIdentity, velocity *domain.IdentityVelocity) domain.FraudCheck {
+	check := domain.FraudCheck{Name: domain.FraudCheckDevice}
+	switch {
+	case identity.DeviceID == "":
+		return skipCheck(check, "No device fingerprint was captured with the application")
+	case velocity == nil:
+		return skipCheck(check, "Application history is not available")
+	}
+
+	switch {
+	case velocity.DeviceBorrowers >= 3:
+		check.Findings = append(check.Findings, fraudFinding("DEVICE_SHARED_MANY", "high", 35,
+			"Device used by %d other borrowers in the last 90 days", velocity.DeviceBorrowers))
+	case velocity.DeviceBorrowers > 0:
+		check.Findings = append(check.Findings, fraudFinding("DEVICE_SHARED", "medium", 15,
+			"Device used by %d other borrower(s) in the last 90 days", velocity.DeviceBorrowers))
+	}
+	if velocity.DeviceApplications >= 5 {
+		check.Findings = append(check.Findings, fraudFinding("DEVICE_VELOCITY", "medium", 20,
+			"%d applications from the device in the last 90 days", velocity.DeviceApplications))
+	}
+	return evaluateCheck(check)
+}
+
+// checkIdentityVelocity flags an SSN, phone number or email address other borrowers applied with,
+// and an SSN applied with repeatedly
+func checkIdentityVelocity(identity *domain.ApplicationIdentity, velocity *domain.IdentityVelocity) domain.FraudCheck {
+	check := domain.FraudCheck{Name: domain.FraudCheckIdentityVelocity}
+	if velocity == nil {
+		return skipCheck(check, "Application history is not available")
+	}
+
+	if velocity.SSNBorrowers > 0 {
+		check.Findings = append(check.Findings, fraudFinding("SSN_MULTIPLE_BORROWERS", "high", 40,
+			"SSN applied with by %d other borrower(s) in the last 90 days", velocity.SSNBorrowers))
+	}
+	if velocity.SSNApplications >= 3 {
+		check.Findings = append(check.Findings, fraudFinding("SSN_VELOCITY", "medium", 15,
+			"%d applications with the SSN in the last 90 days", velocity.SSNApplications))
+	}
+	switch {
+	case velocity.PhoneBorrowers >= 3:
+		check.Findings = append(check.Findings, fraudFinding("PHONE_SHARED_MANY", "high", 30,
+			"Phone number shared with %d other borrowers who applied in the last 90 days", velocity.PhoneBorrowers))
+	case velocity.PhoneBorrowers > 0:
+		check.Findings = append(check.Findings, fraudFinding("PHONE_SHARED", "medium", 15,
+			"Phone number shared with %d other borrower(s) who applied in the last 90 days", velocity.PhoneBorrowers))
+	}
+	if velocity.EmailBorrowers > 0 {
+		check.Findings = append(check.Findings, fraudFinding("EMAIL_SHARED", "medium", 20,
+			"Email address applied with by %d other borrower(s) in the last 90 days", velocity.EmailBorrowers))
+	}
+	if identity.SSNToken == "" {
+		check.Note = "No SSN on file"
+	}
+	return evaluateCheck(check)
+}
+
+// checkDisposableEmail flags an email address at a throwaway email provider
+func checkDisposableEmail(identity *domain.ApplicationIdentity) domain.FraudCheck {
+	check := domain.FraudCheck{Name: domain.FraudCheckDisposableEmail}
+	at := strings.LastIndex(identity.Email, "@")
+	if at < 0 {
+		return skipCheck(check, "No email address on file")
+	}
+
+	emailDomain := identity.Email[at+1:]
+	for emailDomain != "" {
+		if disposableEmailDomains[emailDomain] {
+			check.Findings = append(check.Findings, fraudFinding("DISPOSABLE_EMAIL", "medium", 25,
+				"Email address at disposable email provider %s", emailDomain))
+			break
+		}
+		dot := strings.Index(emailDomain, ".")
+		if dot < 0 {
+			break
+		}
+		emailDomain = emailDomain[dot+1:]
+	}
+	return evaluateCheck(check)
+}
+
+// checkBureauAlerts flags fraud alerts in force on the borrower's credit file. An alert requires
+// the borrower's identity to be verified before credit is extended.
+func checkBureauAlerts(creditReport *domain.CreditReport, now time.Time) domain.FraudCheck {
+	check := domain.FraudCheck{Name: domain.FraudCheckBureauAlerts}
+	if creditReport == nil {
+		return skipCheck(check, "No credit report has been pulled for the application")
+	}
+
+	for _, alert := range creditReport.FraudAlerts {
+		if !alert.IsActive(now) {
+			continue
+		}
+		points := 25.0
+		if alert.Type == "EXTENDED" {
+			points = 35 // the borrower is a reported identity theft victim
+		}
+		description := fmt.Sprintf("%s fraud alert on the credit file since %s", strings.ToLower(strings.ReplaceAll(alert.Type, "_", " ")), alert.PlacedAt.Format("2006-01-02"))
+		if alert.ContactPhone != "" {
+			description += "; verify identity at " + alert.ContactPhone
+		}
+		check.Findings = append(check.Findings, domain.FraudFinding{
+			Code:        "BUREAU_FRAUD_ALERT_" + alert.Type,
+			Description: description,
+			Severity:    "high",
+			Score:       points,
+		})
+	}
+	return evaluateCheck(check)
+}
+
+// screenWithVendor adds the decision engine's fraud vendor screening to a report. A screening that
+// fails is recorded as skipped; the built-in checks still stand.
+func (s *FraudCheckService) screenWithVendor(ctx context.Context, logger *zap.Logger, request *domain.FraudScreeningRequest, identity *domain.ApplicationIdentity, report *domain.FraudReport) domain.FraudCheck {
+	check := domain.FraudCheck{Name: domain.FraudCheckVendor}
+
+	screening := *request
+	screening.UserID = identity.UserID
+	screening.SSNToken = identity.SSNToken
+	screening.Email = identity.Email
+	screening.Phone = identity.Phone
+	screening.DeviceID = identity.DeviceID
+	result, err := s.vendor.ScreenApplication(ctx, &screening)
+	if err != nil {
+		logger.Warn("Fraud vendor screening failed, using built-in fraud checks only", zap.Error(err))
+		report.VendorUnavailable = true
+		return skipCheck(check, "Fraud vendor screening failed")
+	}
+
+	report.Vendor = result.Vendor
+	report.VendorScore = result.Score
+	report.VendorSignals = result.Signals
+	report.VendorRecommendation = result.Recommendation
+	report.VendorUnavailable = result.Unavailable
+	if result.Unavailable {
+		return skipCheck(check, "Fraud vendor unavailable")
+	}
+
+	severity := "low"
+	switch result.Recommendation {
+	case domain.FraudRecommendationDeny:
+		severity = "high"
+	case domain.FraudRecommendationReview:
+		severity = "medium"
+	}
+	for _, code := range result.ReasonCodes {
+		check.Findings = append(check.Findings, domain.FraudFinding{Code: code, Severity: severity})
+	}
+	for _, reason := range result.ReviewReasons {
+		check.Findings = append(check.Findings, domain.FraudFinding{Code: "VENDOR_REVIEW", Description: reason, Severity: severity})
+	}
+	check.Status = domain.FraudCheckPassed
+	if result.Recommendation != domain.FraudRecommendationClear {
+		check.Status = domain.FraudCheckFlagged
+	}
+	// The vendor's score stands on its own rather than adding to the built-in checks
+	check.Score = math.Round(result.Score * 100)
+	return check
+}
+
+// scoreFraudReport adds up a report's fraud risk score and sets its level, recommendation and indicators. The
+// score is the larger of the built-in checks' total and the vendor's score. A fraud alert on the
+// credit file always sends the application to review.
+func scoreFraudReport(report *domain.FraudReport) {
+	var builtIn, vendor float64
+	report.Indicators = []string{}
+	report.ReasonCodes = []string{}
+	for _, check := range report.Checks {
+		if check.Name == domain.FraudCheckVendor {
+			vendor = check.Score
+		} else {
+			builtIn += check.Score
+		}
+		if check.Name == domain.FraudCheckBureauAlerts && len(check.Findings) > 0 {
+			report.IdentityVerification = true
+		}
+		for _, f := range check.Findings {
+			report.ReasonCodes = append(report.ReasonCodes, f.Code)
+			if f.Description != "" {
+				report.Indicators = append(report.Indicators, f.Description)
+			} else {
+				report.Indicators = append(report.Indicators, check.Name+": "+f.Code)
+			}
+		}
+	}
+	report.RiskScore = math.Min(100, math.Max(builtIn, vendor))
+
+	switch {
+	case report.RiskScore > fraudHighRiskScore:
+		report.RiskLevel = domain.RiskHigh
+	case report.RiskScore > fraudMediumRiskScore:
+		report.RiskLevel = domain.RiskMedium
+	default:
+		report.RiskLevel = domain.RiskLow
+	}
+
+	switch {
+	case report.RiskScore >= fraudDenyScore || report.VendorRecommendation == domain.FraudRecommendationDeny:
+		report.Recommendation = domain.FraudRecommendationDeny
+	case report.RiskScore > fraudMediumRiskScore || report.IdentityVerification ||
+		report.VendorRecommendation == domain.FraudRecommendationReview:
+		report.Recommendation = domain.FraudRecommendationReview
+	default:
+		report.Recommendation = domain.FraudRecommendationClear
+	}
+}
+
+func fraudFinding(code, severity string, points float64, format string, args ...interface{}) domain.FraudFinding {
+	return domain.FraudFinding{Code: code, Description: fmt.Sprintf(format, args...), Severity: severity, Score: points}
+}
+
+func skipCheck(check domain.FraudCheck, note string) domain.FraudCheck {
+	check.Status = domain.FraudCheckSkipped
+	check.Note = note
+	return check
+}
+
+// evaluateCheck sets a check's status and score from its findings
+func evaluateCheck(check domain.FraudCheck) domain.FraudCheck {
+	check.Status = domain.FraudCheckPassed
+	for _, f := range check.Findings {
+		check.Score += f.Score
+	}
+	if len(check.Findings) > 0 {
+		check.Status = domain.FraudCheckFlagged
+	}
+	return check
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/payroll"
+
+	"underwriting_worker/domain"
+)
+
+// IncomeVerificationService implements domain.IncomeVerificationService. With a payroll aggregator
+// connected, income is verified from the borrower's payroll account: the first run starts a
+// verification and leaves it pending until the borrower connects their account, and a later run,
+// or the loan service on the aggregator's webhook, computes the verified income from the synced
+// paystubs. Without one, the stated employment is recorded and the application is left
+// unverified, pending income documents and underwriter review.
+type IncomeVerificationService struct {
+	logger            *zap.Logger
+	borrowers         domain.BorrowerRepository
+	payroll           payroll.Provider
+	verifications     domain.IncomeVerificationRepository
+	varianceTolerance float64
+}
+
+// NewIncomeVerificationService creates a new income verification service. A nil payroll provider
+// leaves income to document review.
+func NewIncomeVerificationService(logger *zap.Logger, borrowers domain.BorrowerRepository, payrollProvider payroll.Provider, verifications domain.IncomeVerificationRepository, varianceTolerance float64) *IncomeVerificationService {
+	return &IncomeVerificationService{
+		logger:            logger,
+		borrowers:         borrowers,
+		payroll:           payrollProvider,
+		verifications:     verifications,
+		varianceTolerance: varianceTolerance,
+	}
+}
+
+// VerifyIncome verifies income from payroll data when an aggregator is connected, and otherwise
+// records the borrower's stated employment and requests income documents
+func (s *IncomeVerificationService) VerifyIncome(ctx context.Context, request *domain.IncomeVerificationRequest) (*domain.IncomeVerification, error) {
+	if s.payroll == nil {
+		return s.requestDocuments(ctx, request)
+	}
+
+	if pending := s.pendingPayrollVerification(ctx, request.ApplicationID); pending != nil {
+		return s.completePayrollVerification(ctx, pending, request.AnnualSalary)
+	}
+	return s.startPayrollVerification(ctx, request)
+}
+
+// startPayrollVerification registers the borrower with the aggregator and records a pending
+// verification holding the token they connect their payroll account with
+func (s *IncomeVerificationService) startPayrollVerification(ctx context.Context, request *domain.IncomeVerificationRequest) (*domain.IncomeVerification, error) {
+	session, err := s.payroll.CreateSession(ctx, request.ApplicationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start payroll verification: %w", err)
+	}
+
+	s.logger.Info("Payroll income verification started",
+		zap.String("application_id", request.ApplicationID),
+		zap.String("provider", s.payroll.Name()))
+
+	now := time.Now()
+	return &domain.IncomeVerification{
+		ID:                 request.ApplicationID + "_income_verification",
+		ApplicationID:      request.ApplicationID,
+		UserID:             request.UserID,
+		VerificationMethod: domain.IncomeMethodPayroll,
+		VerificationStatus: domain.IncomePending,
+		StatedAnnualIncome: request.AnnualSalary,
+		VerificationNotes:  "Waiting for the borrower to connect their payroll account",
+		DocumentsProvided:  []string{},
+		VerificationData: map[string]interface{}{
+			"provider":             s.payroll.Name(),
+			"provider_user_id":     session.ProviderUserID,
+			"connect_token":        session.ConnectToken,
+			"stated_annual_income": request.AnnualSalary,
+		},
+		VerifiedAt: now,
+		CreatedAt:  now,
+	}, nil
+}
+
+// completePayrollVerification computes the verified income from the borrower's synced payroll
+// data. The verification stays pending while there is no data yet.
+func (s *IncomeVerificationService) completePayrollVerification(ctx context.Context, verification *domain.IncomeVerification, statedAnnualIncome float64) (*domain.IncomeVerification, error) {
+	providerUserID, _ := verification.VerificationData["provider_user_id"].(string)
+
+	paystubs, err := s.payroll.GetPaystubs(ctx, providerUserID, time.Now().AddDate(-1, 0, -31))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paystubs: %w", err)
+	}
+	employments, err := s.payroll.GetEmployments(ctx, providerUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employments: %w", err)
+	}
+
+	income, err := payroll.ComputeIncome(paystubs, employments, statedAnnualIncome, s.varianceTolerance)
+	if errors.Is(err, payroll.ErrNoIncomeData) {
+		s.logger.Info("Payroll data not synced yet, income verification still pending",
+			zap.String("application_id", verification.ApplicationID))
+		return verification, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	applyPayrollIncome(verification, income)
+	s.logger.Info("Income verified from payroll data",
+		zap.String("application_id", verification.ApplicationID),
+		zap.Float64("verified_annual_income", income.VerifiedAnnualIncome),
+		zap.Float64("variance_percent", income.VariancePercent),
+		zap.Bool("variance_flagged", income.VarianceFlagged))
+	return verification, nil
+}
+
+// pendingPayrollVerification returns the application's payroll verification waiting for data, or
+// nil when there is none
+func (s *IncomeVerificationService) pendingPayrollVerification(ctx context.Context, applicationID string) *domain.IncomeVerification {
+	if s.verifications == nil {
+		return nil
+	}
+	verification, err := s.verifications.GetByApplicationID(ctx, applicationID)
+	if err != nil || verification.VerificationMethod != domain.IncomeMethodPayroll ||
+		verification.VerificationStatus != domain.IncomePending {
+		return nil
+	}
+	if providerUserID, _ := verification.VerificationData["provider_user_id"].(string); providerUserID == "" {
+		return nil
+	}
+	return verification
+}
+
+// applyPayrollIncome records income verified from payroll data on a verification
+func applyPayrollIncome(verification *domain.IncomeVerification, income *payroll.Income) {
+	now := time.Now()
+	verification.VerificationStatus = domain.IncomeVerified
+	verification.VerifiedAnnualIncome = income.VerifiedAnnualIncome
+	verification.VerifiedMonthlyIncome = income.VerifiedMonthlyIncome
+	verification.StatedAnnualIncome = income.StatedAnnualIncome
+	verification.IncomeVariance = income.Variance
+	verification.IncomeVariancePercent = income.VariancePercent
+	verification.IncomeVarianceFlagged = income.VarianceFlagged
+	verification.EmployerName = income.Employer
+	verification.JobTitle = income.JobTitle
+	verification.EmploymentType = income.EmploymentType
+	verification.EmploymentStartDate = income.HireDate
+	verification.PayFrequency = income.PayFrequency
+	verification.LastPayStubDate = income.LastPayDate
+	verification.DocumentsProvided = []string{"payroll_" + income.Basis}
+	verification.VerificationNotes = fmt.Sprintf("Income verified from %d payroll paystubs", income.PaystubCount)
+	if income.Basis == "base_pay" {
+		verification.VerificationNotes = "Income verified from the base pay of the payroll employment"
+	}
+	if verification.VerificationData == nil {
+		verification.VerificationData = map[string]interface{}{}
+	}
+	verification.VerificationData["payroll_income"] = income
+	verification.VerifiedAt = now
+}
+
+// requestDocuments records the borrower's stated employment and requests income documents
+func (s *IncomeVerificationService) requestDocuments(ctx context.Context, request *domain.IncomeVerificationRequest) (*domain.IncomeVerification, error) {
+	borrower, err := s.borrowers.GetByUserID(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get borrower employment: %w", err)
+	}
+
+	employerName := request.EmployerName
+	if employerName == "" {
+		employerName = borrower.EmployerName
+	}
+	jobTitle := request.JobTitle
+	if jobTitle == "" {
+		jobTitle = borrower.JobTitle
+	}
+
+	s.logger.Info("Income requires document verification",
+		zap.String("application_id", request.ApplicationID),
+		zap.Bool("employer_on_file", employerName != ""))
+
+	now := time.Now()
+	return &domain.IncomeVerification{
+		ID:                 request.ApplicationID + "_income_verification",
+		ApplicationID:      request.ApplicationID,
+		UserID:             request.UserID,
+		VerificationMethod: "document_review",
+		VerificationStatus: domain.IncomeUnverified,
+		EmployerName:       employerName,
+		JobTitle:           jobTitle,
+		PayFrequency:       request.PayFrequency,
+		StatedAnnualIncome: request.AnnualSalary,
+		VerificationNotes:  "Stated income requires pay stubs, W-2 or tax returns for underwriter review",
+		DocumentsProvided:  []string{},
+		VerificationData: map[string]interface{}{
+			"stated_annual_income": request.AnnualSalary,
+			"requested_method":     request.VerificationMethod,
+			"required_documents":   []string{"pay_stub", "w2_form", "tax_return"},
+		},
+		VerifiedAt: now,
+		CreatedAt:  now,
+	}, nil
+}
+
+// VerifyEmployment returns the borrower's stated employment, unverified
+func (s *IncomeVerificationService) VerifyEmployment(ctx context.Context, request *domain.EmploymentVerificationRequest) (*domain.EmploymentVerification, error) {
+	borrower, err := s.borrowers.GetByUserID(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get borrower employment: %w", err)
+	}
+	return &domain.EmploymentVerification{
+		Verified:           false,
+		EmployerName:       borrower.EmployerName,
+		JobTitle:           borrower.JobTitle,
+		EmploymentType:     request.EmploymentType,
+		Status:             "pending_documents",
+		VerificationMethod: "document_review",
+		Notes:              "Employment requires verification of employment by an underwriter",
+	}, nil
+}
+
+// GetSupportedVerificationMethods returns the verification methods available
+func (s *IncomeVerificationService) GetSupportedVerificationMethods() []string {
+	if s.payroll != nil {
+		return []string{domain.IncomeMethodPayroll, "document_review"}
+	}
+	return []string{"document_review"}
+}
+
+// GetServiceName returns the service name
+func (s *IncomeVerificationService) GetServiceName() string {
+	return "income-verification"
+}
+
+// IsAvailable reports whether borrower employment can be read
+func (s *IncomeVerificationService) IsAvailable(ctx context.Context) bool {
+	return s.borrowers != nil
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/dti"
+
+	"underwriting_worker/domain"
+)
+
+// riskModelVersion identifies the scoring below in saved assessments
+const riskModelVersion = "weighted_v1.0"
+
+// Risk score component weights
+const (
+	creditRiskWeight = 0.4
+	incomeRiskWeight = 0.3
+	debtRiskWeight   = 0.2
+	fraudRiskWeight  = 0.1
+)
+
+// RiskScoringService implements domain.RiskScoringService by weighting the credit analysis of the
+// bureau report, the decision engine's fraud screening, the borrower's income and their DTI
+type RiskScoringService struct {
+	logger         *zap.Logger
+	creditService  *CreditService
+	fraudDetection domain.FraudDetectionService
+	dti            *dti.Calculator
+}
+
+// NewRiskScoringService creates a new risk scoring service
+func NewRiskScoringService(
+	logger *zap.Logger,
+	creditService *CreditService,
+	fraudDetection domain.FraudDetectionService,
+	dtiCalculator *dti.Calculator,
+) *RiskScoringService {
+	return &RiskScoringService{
+		logger:         logger,
+		creditService:  creditService,
+		fraudDetection: fraudDetection,
+		dti:            dtiCalculator,
+	}
+}
+
+// CalculateRiskScore scores an application's overall risk. A fraud screening that cannot be
+// completed fails the scoring, so callers do not assess an unscreened application as clean.
+func (s *RiskScoringService) CalculateRiskScore(ctx context.Context, application *domain.LoanApplication, creditReport *domain.CreditReport) (*domain.RiskAssessment, error) {
+	creditAnalysis, err := s.creditService.AnalyzeCreditRisk(ctx, creditReport)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze credit risk: %w", err)
+	}
+
+	fraudScore, err := s.CalculateFraudScore(ctx, application)
+	if err != nil {
+		return nil, err
+	}
+
+	creditRisk := creditAnalysis.RiskScore
+	incomeRisk := s.incomeRiskScore(application)
+	debtRisk := s.debtRiskScore(application)
+	fraudRisk := fraudScore.Score
+
+	riskScore := creditRisk*creditRiskWeight + incomeRisk*incomeRiskWeight + debtRisk*debtRiskWeight + fraudRisk*fraudRiskWeight
+	riskLevel := domain.GetRiskLevel(riskScore)
+
+	factors, err := s.GetRiskFactors(ctx, application)
+	if err != nil {
+		return nil, err
+	}
+	for _, factor := range creditAnalysis.RiskFactors {
+		factors = append(factors, domain.RiskFactor{
+			FactorID:    "credit_" + factor.Factor,
+			FactorType:  "credit",
+			Description: factor.Description,
+			Impact:      factor.Impact,
+			Score:       factor.Score,
+			Weight:      creditRiskWeight,
+		})
+	}
+
+	s.logger.Info("Risk score calculated",
+		zap.String("application_id", application.ID),
+		zap.Float64("risk_score", riskScore),
+		zap.String("risk_level", string(riskLevel)),
+		zap.Float64("fraud_score", fraudRisk))
+
+	return &domain.RiskAssessment{
+		ID:                   application.ID + "_risk_assessment",
+		ApplicationID:        application.ID,
+		UserID:               application.UserID,
+		OverallRiskLevel:     riskLevel,
+		RiskScore:            riskScore,
+		CreditRiskScore:      creditRisk,
+		IncomeRiskScore:      incomeRisk,
+		DebtRiskScore:        debtRisk,
+		FraudRiskScore:       fraudRisk,
+		RiskFactors:          factors,
+		MitigatingFactors:    []domain.MitigatingFactor{},
+		ProbabilityOfDefault: math.Min(riskScore/100*0.3, 0.3),
+		RecommendedAction:    recommendedAction(riskLevel),
+		ConfidenceLevel:      fraudScore.Confidence,
+		ModelVersion:         riskModelVersion,
+		AssessmentData:       map[string]interface{}{},
+		CreatedAt:            time.Now(),
+	}, nil
+}
+
+// CalculateFraudScore screens an application with the decision engine's fraud vendor, scaling
+// its 0-1 score to 0-100
+func (s *RiskScoringService) CalculateFraudScore(ctx context.Context, application *domain.LoanApplication) (*domain.FraudScore, error) {
+	if s.fraudDetection == nil {
+		return nil, fmt.Errorf("fraud screening not available")
+	}
+
+	result, err := s.fraudDetection.ScreenApplication(ctx, &domain.FraudScreeningRequest{
+		ApplicationID: application.ID,
+		UserID:        application.UserID,
+		LoanAmount:    application.LoanAmount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to screen application: %w", err)
+	}
+	if result.Unavailable {
+		return nil, fmt.Errorf("fraud vendor %s unavailable", result.Vendor)
+	}
+
+	score := &domain.FraudScore{
+		Score:        result.Score * 100,
+		RiskLevel:    domain.GetRiskLevel(result.Score * 100),
+		Confidence:   0.9,
+		ModelVersion: result.Vendor,
+	}
+	for _, signal := range result.Signals {
+		score.Indicators = append(score.Indicators, domain.FraudIndicator{
+			Type:        signal.Category,
+			Description: signal.Description,
+			Severity:    string(domain.GetRiskLevel(signal.Score * 100)),
+			Score:       signal.Score * 100,
+		})
+	}
+	return score, nil
+}
+
+// GetRiskFactors returns the income and debt risk factors of an application
+func (s *RiskScoringService) GetRiskFactors(ctx context.Context, application *domain.LoanApplication) ([]domain.RiskFactor, error) {
+	factors := []domain.RiskFactor{}
+
+	if dtiRatio := application.CalculateDTI(s.dti); dtiRatio > 0.43 {
+		factors = append(factors, domain.RiskFactor{
+			FactorID:    "high_dti",
+			FactorType:  "debt",
+			Description: fmt.Sprintf("Debt-to-income ratio of %.1f%% exceeds 43%%", dtiRatio*100),
+			Impact:      "high",
+			Score:       s.debtRiskScore(application),
+			Weight:      debtRiskWeight,
+		})
+	}
+	if application.IncomeVerificationStatus != domain.IncomeVerified {
+		factors = append(factors, domain.RiskFactor{
+			FactorID:    "income_not_verified",
+			FactorType:  "income",
+			Description: "Stated income has not been verified",
+			Impact:      "medium",
+			Score:       s.incomeRiskScore(application),
+			Weight:      incomeRiskWeight,
+		})
+	}
+	return factors, nil
+}
+
+// GetServiceName returns the service name
+func (s *RiskScoringService) GetServiceName() string {
+	return "risk-scoring"
+}
+
+// GetModelVersion returns the version of the scoring model
+func (s *RiskScoringService) GetModelVersion() string {
+	return riskModelVersion
+}
+
+// IsAvailable reports whether applications can be scored, which needs fraud screening
+func (s *RiskScoringService) IsAvailable(ctx context.Context) bool {
+	return s.fraudDetection != nil
+}
+
+// incomeRiskScore scores the adequacy, source and verification of the borrower's income (0-100)
+func (s *RiskScoringService) incomeRiskScore(application *domain.LoanApplication) float64 {
+	score := 0.0
+
+	switch {
+	case application.AnnualIncome < 25000:
+		score += 40
+	case application.AnnualIncome < 40000:
+		score += 20
+	case application.AnnualIncome < 60000:
+		score += 10
+	}
+
+	switch application.EmploymentStatus {
+	case "unemployed":
+		score += 50
+	case "part_time":
+		score += 30
+	case "self_employed":
+		score += 20
+	case "retired":
+		score += 15
+	}
+
+	switch application.IncomeVerificationStatus {
+	case domain.IncomeFailed:
+		score += 30
+	case domain.IncomeUnverified:
+		score += 20
+	}
+
+	return math.Min(score, 100)
+}
+
+// debtRiskScore scores the borrower's DTI (0-100)
+func (s *RiskScoringService) debtRiskScore(application *domain.LoanApplication) float64 {
+	switch dtiRatio := application.CalculateDTI(s.dti); {
+	case dtiRatio > 0.5:
+		return 80
+	case dtiRatio > 0.43:
+		return 60
+	case dtiRatio > 0.36:
+		return 40
+	case dtiRatio > 0.28:
+		return 20
+	default:
+		return 10
+	}
+}
+
+// recommendedAction returns the action recommended for a risk level
+func recommendedAction(riskLevel domain.RiskLevel) string {
+	switch riskLevel {
+	case domain.RiskLow:
+		return "approve_standard_terms"
+	case domain.RiskMedium:
+		return "approve_with_conditions"
+	case domain.RiskCritical:
+		return "decline"
+	default:
+		return "manual_review_required"
+	}
+}
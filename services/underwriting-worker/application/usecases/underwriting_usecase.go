@@ -18,7 +18,7 @@ type UnderwritingUseCase struct {
 	riskAssessmentRepo        domain.RiskAssessmentRepository
 	incomeVerificationRepo    domain.IncomeVerificationRepository
 	underwritingResultRepo    domain.UnderwritingResultRepository
-	underwritingPolicyRepo    domain.UnderwritingPolicyRepository
+	underwritingPolicyRepo    domain.UnderwritingPolicyProvider
 	underwritingWorkflowRepo  domain.UnderwritingWorkflowRepository
 	creditBureauService       domain.CreditBureauService
 	riskScoringService        domain.RiskScoringService
@@ -37,7 +37,7 @@ func NewUnderwritingUseCase(
 	riskAssessmentRepo domain.RiskAssessmentRepository,
 	incomeVerificationRepo domain.IncomeVerificationRepository,
 	underwritingResultRepo domain.UnderwritingResultRepository,
-	underwritingPolicyRepo domain.UnderwritingPolicyRepository,
+	underwritingPolicyRepo domain.UnderwritingPolicyProvider,
 	underwritingWorkflowRepo domain.UnderwritingWorkflowRepository,
 	creditBureauService domain.CreditBureauService,
 	riskScoringService domain.RiskScoringService,
@@ -206,7 +206,7 @@ func (uc *UnderwritingUseCase) performCreditCheck(ctx context.Context, applicati
 	request := &domain.CreditReportRequest{
 		UserID:        application.UserID,
 		ApplicationID: application.ID,
-		SSN:           "", // Would be retrieved from user profile
+		SSNToken:      "", // Would be retrieved from user profile
 		ReportType:    "full",
 		Permissible:   "loan_application",
 	}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -12,6 +13,7 @@ import (
 	"go.uber.org/zap/zapcore"
 
 	"underwriting_worker/infrastructure/workflow/tasks"
+	"underwriting_worker/interfaces"
 
 	"github.com/huuhoait/los-demo/services/shared/pkg/config"
 )
@@ -51,6 +53,25 @@ func main() {
 		}
 	}()
 
+	// Serve the task history of applications to support
+	var server *http.Server
+	if cfg.Server.Port > 0 {
+		mux := http.NewServeMux()
+		interfaces.NewTaskHistoryHandler(taskWorker.TaskRuns(), cfg.Security.JWTSecret, logger).RegisterRoutes(mux)
+		server = &http.Server{
+			Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
+			Handler:      mux,
+			ReadTimeout:  time.Duration(cfg.Server.ReadTimeout) * time.Second,
+			WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second,
+		}
+		go func() {
+			logger.Info("Starting task history API", zap.String("addr", server.Addr))
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("Task history API stopped with error", zap.Error(err))
+			}
+		}()
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -62,6 +83,12 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	if server != nil {
+		if err := server.Shutdown(ctx); err != nil {
+			logger.Error("Error stopping task history API", zap.Error(err))
+		}
+	}
+
 	// Stop the task worker gracefully
 	if err := taskWorker.Stop(ctx); err != nil {
 		logger.Error("Error stopping task worker", zap.Error(err))
@@ -56,6 +56,7 @@ type UnderwritingResultRepository interface {
 	List(ctx context.Context, filter UnderwritingResultFilter) ([]*UnderwritingResult, error)
 	GetPendingReviews(ctx context.Context) ([]*UnderwritingResult, error)
 	GetApprovedOffers(ctx context.Context, userID string) ([]*UnderwritingResult, error)
+	Invalidate(ctx context.Context, applicationID, reason string, invalidatedAt time.Time) (int, error)
 }
 
 // UnderwritingPolicyRepository defines the interface for underwriting policies data access
@@ -70,6 +71,17 @@ type UnderwritingPolicyRepository interface {
 	SetActive(ctx context.Context, id string) error
 }
 
+// UnderwritingPolicyProvider supplies the underwriting policy in force
+type UnderwritingPolicyProvider interface {
+	GetActive(ctx context.Context) (*UnderwritingPolicy, error)
+}
+
+// PolicyPinRepository records the underwriting policy version each workflow run decides under
+type PolicyPinRepository interface {
+	// Pin pins the run to the version unless it is already pinned, and returns its pinned version
+	Pin(ctx context.Context, workflowInstanceID, policyVersion string) (string, error)
+}
+
 // UnderwritingWorkflowRepository defines the interface for workflow data access
 type UnderwritingWorkflowRepository interface {
 	Create(ctx context.Context, workflow *UnderwritingWorkflow) error
@@ -82,6 +94,77 @@ type UnderwritingWorkflowRepository interface {
 	GetActiveWorkflows(ctx context.Context) ([]*UnderwritingWorkflow, error)
 }
 
+// BorrowerRepository looks up the borrower profile kept by the loan service
+type BorrowerRepository interface {
+	GetByUserID(ctx context.Context, userID string) (*Borrower, error)
+}
+
+// TaskExecutionRepository records task executions so re-delivered tasks are not run twice
+type TaskExecutionRepository interface {
+	// Claim records the start of an execution. When an execution with the same key exists and is
+	// completed, or still running within the lease, it is returned and claimed is false.
+	Claim(ctx context.Context, execution *TaskExecution, lease time.Duration) (existing *TaskExecution, claimed bool, err error)
+	Complete(ctx context.Context, idempotencyKey string, output map[string]interface{}) error
+	Release(ctx context.Context, idempotencyKey string) error
+}
+
+// TaskRunRepository keeps the history of the task runs of the worker
+type TaskRunRepository interface {
+	Record(ctx context.Context, run *TaskRun) error
+	// ListByApplicationID returns the runs of an application's tasks, oldest first
+	ListByApplicationID(ctx context.Context, applicationID string) ([]*TaskRun, error)
+}
+
+// DeadLetterRepository keeps the tasks that failed for good for an operator to fix and re-submit
+type DeadLetterRepository interface {
+	// Create adds a task to the dead-letter queue; a task already in it is left unchanged
+	Create(ctx context.Context, task *DeadLetterTask) error
+}
+
+// ManualReviewRepository queues applications for an underwriter to review and decide
+type ManualReviewRepository interface {
+	// Enqueue queues a review. When the workflow task already has a review, it is returned and
+	// created is false.
+	Enqueue(ctx context.Context, review *ManualReview) (existing *ManualReview, created bool, err error)
+	// GetByTask retrieves the review queued for a workflow's human task
+	GetByTask(ctx context.Context, workflowInstanceID, taskReferenceName string) (*ManualReview, error)
+}
+
+// ConditionRepository records the conditions of conditional approvals in the loan API's
+// underwriting_conditions table
+type ConditionRepository interface {
+	// Track records the conditions a workflow waits on and returns every condition recorded for the
+	// workflow. Conditions the workflow already recorded are kept as they are.
+	Track(ctx context.Context, conditions []*TrackedCondition) ([]*TrackedCondition, error)
+}
+
+// FraudSignalRepository reads the identity elements the loan service records with applications
+type FraudSignalRepository interface {
+	GetIdentity(ctx context.Context, applicationID string) (*ApplicationIdentity, error)
+	// GetVelocity counts the applications since a time that share the identity's elements
+	GetVelocity(ctx context.Context, identity *ApplicationIdentity, since time.Time) (*IdentityVelocity, error)
+}
+
+// ComplianceRecordRepository keeps the compliance record of each decided application
+type ComplianceRecordRepository interface {
+	// Save saves the compliance record of an application, replacing an earlier one
+	Save(ctx context.Context, record *ComplianceRecord) error
+	GetByApplicationID(ctx context.Context, applicationID string) (*ComplianceRecord, error)
+	// RecordNoticeDelivery records when the borrower was sent the notice of the action taken
+	RecordNoticeDelivery(ctx context.Context, applicationID, documentID string, deliveredAt time.Time) error
+}
+
+// ApplicantDemographicsRepository reads the demographics borrowers gave the loan service
+type ApplicantDemographicsRepository interface {
+	GetByApplicationID(ctx context.Context, applicationID string) (*ApplicantDemographics, error)
+}
+
+// FraudReportRepository keeps the fraud report of each application
+type FraudReportRepository interface {
+	Create(ctx context.Context, report *FraudReport) error
+	GetByApplicationID(ctx context.Context, applicationID string) (*FraudReport, error)
+}
+
 // CreditBureauService defines the interface for credit bureau integration
 type CreditBureauService interface {
 	GetCreditReport(ctx context.Context, request *CreditReportRequest) (*CreditReport, error)
@@ -121,6 +204,11 @@ type DecisionEngineService interface {
 	IsAvailable(ctx context.Context) bool
 }
 
+// FraudDetectionService screens applications for device, identity and synthetic-identity fraud
+type FraudDetectionService interface {
+	ScreenApplication(ctx context.Context, request *FraudScreeningRequest) (*FraudScreeningResult, error)
+}
+
 // WorkflowOrchestrator defines the interface for workflow orchestration
 type WorkflowOrchestrator interface {
 	StartUnderwritingWorkflow(ctx context.Context, applicationID string) (*WorkflowExecution, error)
@@ -261,7 +349,7 @@ type AuditFilter struct {
 type CreditReportRequest struct {
 	UserID        string
 	ApplicationID string
-	SSN           string
+	SSNToken      string // vault token; only the decision engine bureau client can detokenize
 	FirstName     string
 	LastName      string
 	DateOfBirth   time.Time
@@ -270,6 +358,19 @@ type CreditReportRequest struct {
 	Permissible   string // loan_application, account_review, etc.
 }
 
+// Borrower is the identity and employment a borrower gave the loan service, used to pull their
+// credit and verify their income
+type Borrower struct {
+	UserID       string
+	FirstName    string
+	LastName     string
+	DateOfBirth  time.Time
+	SSNToken     string
+	Address      Address
+	EmployerName string
+	JobTitle     string
+}
+
 type CreditScore struct {
 	Score        int
 	ScoreRange   CreditScoreRange
@@ -364,10 +465,58 @@ type DecisionResponse struct {
 	CounterOffer         *CounterOfferTerms
 	ManualReviewRequired bool
 	PolicyVersion        string
+	Pricing              *DecisionPricing // set by the decision engine on approvals
+	Stages               []DecisionStage  // knockout and scoring stage results, in evaluation order
 	DecisionData         map[string]interface{}
 	ProcessingTime       time.Duration
 }
 
+// Decision stages and their outcomes, as reported by the decision engine
+const (
+	StageKnockout = "KNOCKOUT"
+	StageScoring  = "SCORING"
+
+	StageStatusPassed     = "PASSED"
+	StageStatusKnockedOut = "KNOCKED_OUT"
+	StageStatusCompleted  = "COMPLETED"
+	StageStatusSkipped    = "SKIPPED"
+)
+
+// DecisionStage is the outcome of one stage of a decision. A knocked-out decision is a final
+// denial: it is never counter-offered or sent to manual review.
+type DecisionStage struct {
+	Stage    string   `json:"stage"`
+	Status   string   `json:"status"`
+	Decision string   `json:"decision,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+	RuleHits []string `json:"rule_hits,omitempty"`
+}
+
+// KnockedOut reports whether a hard knockout denied the decision
+func (r *DecisionResponse) KnockedOut() bool {
+	for _, stage := range r.Stages {
+		if stage.Stage == StageKnockout {
+			return stage.Status == StageStatusKnockedOut
+		}
+	}
+	return false
+}
+
+// DecisionPricing is the decision engine's risk-based price, the same one loan offers are priced
+// from, so underwriting results and offers agree
+type DecisionPricing struct {
+	ModelVersion          string
+	BaseRate              float64
+	MarginAdjustments     []RateFactor
+	Rate                  float64
+	OriginationFeeRate    float64
+	NetOriginationFeeRate float64
+	LoanAmount            float64
+	TermMonths            int
+	MonthlyPayment        float64
+	APR                   float64
+}
+
 type InterestRateRequest struct {
 	CreditScore    int
 	DTIRatio       float64
@@ -418,6 +567,43 @@ type PolicyRecommendation struct {
 	Action      string
 }
 
+// Fraud screening recommendations, as reported by the decision engine
+const (
+	FraudRecommendationClear  = "CLEAR"
+	FraudRecommendationReview = "REVIEW"
+	FraudRecommendationDeny   = "DENY"
+)
+
+type FraudScreeningRequest struct {
+	ApplicationID string  `json:"application_id"`
+	UserID        string  `json:"user_id"`
+	LoanAmount    float64 `json:"loan_amount"`
+	DeviceID      string  `json:"device_id,omitempty"`
+	IPAddress     string  `json:"ip_address,omitempty"`
+	Email         string  `json:"email,omitempty"`
+	Phone         string  `json:"phone,omitempty"`
+	SSNToken      string  `json:"ssn_token,omitempty"`
+}
+
+// FraudScreeningResult is the fraud vendor's assessment; Score is normalized to 0 (clean) to 1
+type FraudScreeningResult struct {
+	Vendor         string        `json:"vendor"`
+	Score          float64       `json:"score"`
+	Signals        []FraudSignal `json:"signals,omitempty"`
+	ReasonCodes    []string      `json:"reason_codes,omitempty"`
+	Recommendation string        `json:"recommendation"`
+	ReviewReasons  []string      `json:"review_reasons,omitempty"`
+	Unavailable    bool          `json:"unavailable,omitempty"`
+}
+
+// FraudSignal is one device, identity or synthetic-identity signal behind a fraud score
+type FraudSignal struct {
+	Category    string  `json:"category"` // DEVICE, IDENTITY or SYNTHETIC
+	Code        string  `json:"code"`
+	Description string  `json:"description,omitempty"`
+	Score       float64 `json:"score"`
+}
+
 type WorkflowExecution struct {
 	WorkflowID     string
 	ExecutionID    string
@@ -2,6 +2,9 @@ package domain
 
 import (
 	"time"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/dti"
+	"github.com/huuhoait/los-demo/services/shared/pkg/income"
 )
 
 // UnderwritingDecision represents the final underwriting decision
@@ -55,8 +58,12 @@ const (
 	IncomeUnverified IncomeVerificationStatus = "unverified"
 	IncomePartial    IncomeVerificationStatus = "partial"
 	IncomeFailed     IncomeVerificationStatus = "failed"
+	IncomePending    IncomeVerificationStatus = "pending" // waiting for the borrower's payroll data
 )
 
+// IncomeMethodPayroll is the verification method of income verified from payroll data
+const IncomeMethodPayroll = "payroll"
+
 // LoanApplication represents a loan application for underwriting
 type LoanApplication struct {
 	ID                       string                   `json:"id" db:"id"`
@@ -71,6 +78,8 @@ type LoanApplication struct {
 	EmploymentStatus         string                   `json:"employment_status" db:"employment_status"`
 	IncomeVerificationStatus IncomeVerificationStatus `json:"income_verification_status" db:"income_verification_status"`
 	DTIRatio                 float64                  `json:"dti_ratio" db:"dti_ratio"`
+	Obligations              *dti.Obligations         `json:"obligations,omitempty"`    // qualified under the DTI debt treatments, not part of MonthlyDebt
+	IncomeSources            *income.Sources          `json:"income_sources,omitempty"` // documented income by source; its qualifying income replaces MonthlyIncome
 	CurrentState             string                   `json:"current_state" db:"current_state"`
 	Status                   string                   `json:"status" db:"status"`
 	SubmittedAt              time.Time                `json:"submitted_at" db:"submitted_at"`
@@ -97,6 +106,7 @@ type CreditReport struct {
 	DerogatoryCounts    DerogatoryCounts       `json:"derogatory_counts"`
 	RiskFactors         []string               `json:"risk_factors"`
 	CreditMix           []string               `json:"credit_mix"`
+	FraudAlerts         []CreditFraudAlert     `json:"fraud_alerts,omitempty"`
 	ReportData          map[string]interface{} `json:"report_data" db:"report_data"`
 	CreatedAt           time.Time              `json:"created_at" db:"created_at"`
 }
@@ -148,6 +158,20 @@ type PaymentHistory struct {
 	PaymentScore    float64 `json:"payment_score"` // 0-100
 }
 
+// CreditFraudAlert is a fraud alert on the borrower's credit file. Credit may only be extended
+// after verifying the borrower's identity, by calling the contact phone when one is given.
+type CreditFraudAlert struct {
+	Type         string    `json:"type"` // INITIAL, EXTENDED or ACTIVE_DUTY
+	ContactPhone string    `json:"contact_phone,omitempty"`
+	PlacedAt     time.Time `json:"placed_at"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+}
+
+// IsActive reports whether the alert is in force at a time
+func (a CreditFraudAlert) IsActive(at time.Time) bool {
+	return a.ExpiresAt.IsZero() || at.Before(a.ExpiresAt)
+}
+
 // DerogatoryCounts represents counts of derogatory items
 type DerogatoryCounts struct {
 	Bankruptcies int `json:"bankruptcies"`
@@ -216,6 +240,10 @@ type IncomeVerification struct {
 	LastPayStubDate       time.Time                `json:"last_pay_stub_date" db:"last_pay_stub_date"`
 	TaxReturnYear         int                      `json:"tax_return_year" db:"tax_return_year"`
 	W2Income              float64                  `json:"w2_income" db:"w2_income"`
+	StatedAnnualIncome    float64                  `json:"stated_annual_income" db:"stated_annual_income"`
+	IncomeVariance        float64                  `json:"income_variance" db:"income_variance"`                 // verified minus stated
+	IncomeVariancePercent float64                  `json:"income_variance_percent" db:"income_variance_percent"` // of stated income
+	IncomeVarianceFlagged bool                     `json:"income_variance_flagged" db:"income_variance_flagged"` // outside the tolerance
 	VerificationNotes     string                   `json:"verification_notes" db:"verification_notes"`
 	DocumentsProvided     []string                 `json:"documents_provided"`
 	VerificationData      map[string]interface{}   `json:"verification_data" db:"verification_data"`
@@ -251,6 +279,11 @@ type UnderwritingResult struct {
 	ProcessingTime       time.Duration           `json:"processing_time"`
 	CreatedAt            time.Time               `json:"created_at" db:"created_at"`
 	UpdatedAt            time.Time               `json:"updated_at" db:"updated_at"`
+
+	// InvalidatedAt is when a material change to the application before funding made the result
+	// stale; an invalidated result no longer decides the application
+	InvalidatedAt      *time.Time `json:"invalidated_at,omitempty" db:"invalidated_at"`
+	InvalidationReason string     `json:"invalidation_reason,omitempty" db:"invalidation_reason"`
 }
 
 // UnderwritingCondition represents conditions for loan approval
@@ -370,6 +403,291 @@ type WorkflowStep struct {
 	MaxRetries    int                    `json:"max_retries"`
 }
 
+// TaskExecution is one execution of a workflow task, kept so a task Conductor delivers again is
+// answered with the output of the execution that already completed
+type TaskExecution struct {
+	IdempotencyKey     string                 `json:"idempotency_key" db:"idempotency_key"`
+	TaskID             string                 `json:"task_id" db:"task_id"`
+	TaskType           string                 `json:"task_type" db:"task_type"`
+	ApplicationID      string                 `json:"application_id" db:"application_id"`
+	WorkflowInstanceID string                 `json:"workflow_instance_id" db:"workflow_instance_id"`
+	Status             TaskExecutionStatus    `json:"status" db:"status"`
+	Output             map[string]interface{} `json:"output" db:"output"`
+	StartedAt          time.Time              `json:"started_at" db:"started_at"`
+	CompletedAt        *time.Time             `json:"completed_at" db:"completed_at"`
+}
+
+// TaskExecutionStatus represents the status of a task execution
+type TaskExecutionStatus string
+
+const (
+	TaskExecutionRunning   TaskExecutionStatus = "running"
+	TaskExecutionCompleted TaskExecutionStatus = "completed"
+)
+
+// TaskRun is the record of one run of a workflow task by the worker, kept so support can see
+// which steps ran for an application, with what data and how they ended
+type TaskRun struct {
+	ID                 string                 `json:"id" db:"id"`
+	TaskID             string                 `json:"task_id" db:"task_id"`
+	TaskType           string                 `json:"task_type" db:"task_type"`
+	WorkflowInstanceID string                 `json:"workflow_instance_id" db:"workflow_instance_id"`
+	ApplicationID      string                 `json:"application_id,omitempty" db:"application_id"`
+	WorkerID           string                 `json:"worker_id" db:"worker_id"`
+	Status             TaskRunStatus          `json:"status" db:"status"`
+	Input              map[string]interface{} `json:"input" db:"input"`
+	Output             map[string]interface{} `json:"output,omitempty" db:"output"`
+	Error              string                 `json:"error,omitempty" db:"error"`
+	StartedAt          time.Time              `json:"started_at" db:"started_at"`
+	CompletedAt        time.Time              `json:"completed_at" db:"completed_at"`
+	DurationMs         int64                  `json:"duration_ms" db:"duration_ms"`
+}
+
+// TaskRunStatus is how a task run ended
+type TaskRunStatus string
+
+const (
+	TaskRunCompleted      TaskRunStatus = "completed"
+	TaskRunDeduplicated   TaskRunStatus = "deduplicated" // answered with the output of an earlier run
+	TaskRunFailed         TaskRunStatus = "failed"       // left for Conductor to retry
+	TaskRunFailedTerminal TaskRunStatus = "failed_terminal"
+)
+
+// DeadLetterTask is a workflow task that failed with a terminal error, or with a retryable one
+// after every retry, kept with its input and failure until an operator resolves it
+type DeadLetterTask struct {
+	ID                 string                 `json:"id" db:"id"`
+	TaskID             string                 `json:"task_id" db:"task_id"`
+	TaskType           string                 `json:"task_type" db:"task_type"`
+	WorkflowInstanceID string                 `json:"workflow_instance_id" db:"workflow_instance_id"`
+	ApplicationID      string                 `json:"application_id,omitempty" db:"application_id"`
+	Input              map[string]interface{} `json:"input" db:"input"`
+	Output             map[string]interface{} `json:"output,omitempty" db:"output"`
+	Error              string                 `json:"error" db:"error"`
+	Terminal           bool                   `json:"terminal" db:"terminal"`                   // retrying would fail the same way
+	Attempts           int                    `json:"attempts" db:"attempts"`                   // attempts by the worker on the last delivery
+	ConductorRetries   int                    `json:"conductor_retries" db:"conductor_retries"` // deliveries Conductor retried before this one
+	FailedAt           time.Time              `json:"failed_at" db:"failed_at"`
+}
+
+// ManualReviewStatus represents where a manual review is in the review queue
+type ManualReviewStatus string
+
+const (
+	ManualReviewPending   ManualReviewStatus = "pending"
+	ManualReviewInReview  ManualReviewStatus = "in_review"
+	ManualReviewCompleted ManualReviewStatus = "completed"
+)
+
+// ManualReviewType is why an application is queued for an underwriter
+type ManualReviewType string
+
+const (
+	ManualReviewPrimary   ManualReviewType = "primary"   // the workflow routed the application to review
+	ManualReviewSecondary ManualReviewType = "secondary" // a large approval needs a second underwriter's sign-off
+)
+
+// ManualReview is an application queued for an underwriter, whose decision completes the
+// workflow's waiting human task
+type ManualReview struct {
+	ID                 string             `json:"id" db:"id"`
+	ApplicationID      string             `json:"application_id" db:"application_id"`
+	WorkflowInstanceID string             `json:"workflow_instance_id" db:"workflow_instance_id"`
+	TaskReferenceName  string             `json:"task_reference_name" db:"task_reference_name"` // human task completed by the decision
+	Reason             string             `json:"reason" db:"reason"`
+	RiskLevel          string             `json:"risk_level,omitempty" db:"risk_level"`
+	Priority           string             `json:"priority" db:"priority"`
+	ReviewType         ManualReviewType   `json:"review_type" db:"review_type"`
+	PrimaryReviewerID  string             `json:"primary_reviewer_id,omitempty" db:"primary_reviewer_id"` // first approver of a secondary review; empty for an automated approval
+	Status             ManualReviewStatus `json:"status" db:"status"`
+	Decision           string             `json:"decision,omitempty" db:"decision"`
+	CompletedBy        string             `json:"completed_by,omitempty" db:"completed_by"`
+	DueAt              time.Time          `json:"due_at" db:"due_at"`
+	CreatedAt          time.Time          `json:"created_at" db:"created_at"`
+}
+
+// Statuses of a tracked condition. Evidence uploads, waivers and expiry are recorded by the loan API.
+const (
+	TrackedConditionPending  = "pending"
+	TrackedConditionReceived = "received"
+	TrackedConditionWaived   = "waived"
+	TrackedConditionExpired  = "expired"
+)
+
+// TrackedCondition is a condition of a conditional approval recorded for the borrower to clear.
+// The loan API resumes the workflow's waiting task (TaskReferenceName) once every critical
+// condition is received or waived, or one expires.
+type TrackedCondition struct {
+	ID                 string    `json:"id" db:"id"`
+	ApplicationID      string    `json:"application_id" db:"application_id"`
+	WorkflowInstanceID string    `json:"workflow_instance_id" db:"workflow_instance_id"`
+	TaskReferenceName  string    `json:"task_reference_name" db:"task_reference_name"`
+	SourceConditionID  string    `json:"source_condition_id" db:"source_condition_id"` // the condition's ID in the decision
+	ConditionType      string    `json:"condition_type" db:"condition_type"`
+	Description        string    `json:"description" db:"description"`
+	Priority           string    `json:"priority" db:"priority"`
+	Critical           bool      `json:"critical" db:"critical"`
+	Status             string    `json:"status" db:"status"`
+	DueAt              time.Time `json:"due_at" db:"due_at"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+}
+
+// FraudCheckStatus is the outcome of one fraud check
+type FraudCheckStatus string
+
+const (
+	FraudCheckPassed  FraudCheckStatus = "passed"
+	FraudCheckFlagged FraudCheckStatus = "flagged"
+	FraudCheckSkipped FraudCheckStatus = "skipped" // the data the check needs is not available
+)
+
+// Fraud checks run on each application
+const (
+	FraudCheckDevice           = "device_fingerprint"
+	FraudCheckIdentityVelocity = "identity_velocity"
+	FraudCheckDisposableEmail  = "disposable_email"
+	FraudCheckBureauAlerts     = "bureau_fraud_alerts"
+	FraudCheckVendor           = "vendor_screening"
+)
+
+// FraudFinding is one fraud indicator found by a check; Score is what it adds to the fraud risk
+// score
+type FraudFinding struct {
+	Code        string  `json:"code"`
+	Description string  `json:"description"`
+	Severity    string  `json:"severity"` // low, medium or high
+	Score       float64 `json:"score"`
+}
+
+// FraudCheck is the outcome of one fraud check and what it found
+type FraudCheck struct {
+	Name     string           `json:"name"`
+	Status   FraudCheckStatus `json:"status"`
+	Score    float64          `json:"score"`
+	Findings []FraudFinding   `json:"findings,omitempty"`
+	Note     string           `json:"note,omitempty"`
+}
+
+// FraudReport is the fraud assessment of an application: the outcome of each check, the 0-100
+// fraud risk score they add up to and the recommendation that follows from it
+type FraudReport struct {
+	ID                   string        `json:"id" db:"id"`
+	ApplicationID        string        `json:"application_id" db:"application_id"`
+	UserID               string        `json:"user_id" db:"user_id"`
+	RiskScore            float64       `json:"risk_score" db:"risk_score"`
+	RiskLevel            RiskLevel     `json:"risk_level" db:"risk_level"`
+	Recommendation       string        `json:"recommendation" db:"recommendation"` // CLEAR, REVIEW or DENY
+	Checks               []FraudCheck  `json:"checks"`
+	Indicators           []string      `json:"indicators"`
+	ReasonCodes          []string      `json:"reason_codes"`
+	IdentityVerification bool          `json:"identity_verification_required"` // a bureau fraud alert is in force
+	Vendor               string        `json:"vendor,omitempty"`
+	VendorScore          float64       `json:"vendor_score,omitempty"` // the vendor's normalized 0-1 score
+	VendorRecommendation string        `json:"vendor_recommendation,omitempty"`
+	VendorSignals        []FraudSignal `json:"vendor_signals,omitempty"`
+	VendorUnavailable    bool          `json:"vendor_unavailable,omitempty"`
+	CreatedAt            time.Time     `json:"created_at" db:"created_at"`
+}
+
+// ApplicationIdentity is the identity an application was made with, as recorded by the loan service
+type ApplicationIdentity struct {
+	ApplicationID string
+	UserID        string
+	SSNToken      string
+	Email         string // normalized
+	Phone         string
+	DeviceID      string
+}
+
+// IdentityVelocity counts the recent applications sharing an application's identity elements
+type IdentityVelocity struct {
+	SSNApplications    int // applications with the SSN, this one included
+	SSNBorrowers       int // other borrowers who applied with the SSN
+	PhoneBorrowers     int // other borrowers with the phone number who applied
+	EmailBorrowers     int // other borrowers who applied with the email address
+	DeviceApplications int // applications from the device, this one included
+	DeviceBorrowers    int // other borrowers who applied from the device
+}
+
+// ComplianceAction is the action taken on an application, as reported in regulatory exports
+type ComplianceAction string
+
+const (
+	ComplianceActionApproved  ComplianceAction = "APPROVED" // originated, or approved but not accepted, once the offer is settled
+	ComplianceActionDenied    ComplianceAction = "DENIED"
+	ComplianceActionWithdrawn ComplianceAction = "WITHDRAWN" // withdrawn by the borrower before a decision
+)
+
+// MaxComplianceDenialReasons is how many denial reasons a compliance record reports
+const MaxComplianceDenialReasons = 4
+
+// DecisionNoticeDays is how many days after the application the borrower must be notified of the
+// action taken on it
+const DecisionNoticeDays = 30
+
+// ComplianceRecord is the regulatorily required data of an application captured when it is
+// decided, for the reporting service to export. The borrower's demographics are stored apart from
+// the rest of the record.
+type ComplianceRecord struct {
+	ID                 string                 `json:"id"`
+	ApplicationID      string                 `json:"application_id"`
+	ApplicationNumber  string                 `json:"application_number"`
+	UserID             string                 `json:"user_id"`
+	WorkflowInstanceID string                 `json:"workflow_instance_id,omitempty"`
+	ApplicationDate    time.Time              `json:"application_date"`
+	ActionTaken        ComplianceAction       `json:"action_taken"`
+	ActionTakenDate    time.Time              `json:"action_taken_date"`
+	DenialReasons      []DecisionReason       `json:"denial_reasons"`
+	LoanAmount         float64                `json:"loan_amount"`
+	LoanTermMonths     int                    `json:"loan_term_months"`
+	LoanPurpose        string                 `json:"loan_purpose"`
+	InterestRate       float64                `json:"interest_rate,omitempty"` // approved applications only
+	APR                float64                `json:"apr,omitempty"`
+	PolicyVersion      string                 `json:"policy_version,omitempty"`
+	Demographics       *ApplicantDemographics `json:"-"` // nil when the borrower gave none
+	NoticeDocumentID   string                 `json:"-"` // the approval letter or adverse action notice sent
+	NoticeDeliveredAt  *time.Time             `json:"-"` // nil until the borrower is sent the notice
+	CapturedAt         time.Time              `json:"captured_at"`
+}
+
+// NoticeDueDate is when the borrower must have been notified of the action taken by
+func (r *ComplianceRecord) NoticeDueDate() time.Time {
+	return r.ApplicationDate.AddDate(0, 0, DecisionNoticeDays)
+}
+
+// DecisionLetter is the approval letter or adverse action notice the loan service sent the
+// borrower, as stored with the application's documents
+type DecisionLetter struct {
+	ID           string     `json:"id"`
+	DocumentType string     `json:"document_type"`
+	ReasonCodes  []string   `json:"reason_codes,omitempty"`
+	DeliveredAt  *time.Time `json:"delivered_at,omitempty"`
+}
+
+// Compensation is the outcome of a step the loan service ran to undo a side effect of a workflow
+// that failed midway; a step with nothing left to undo is not applied
+type Compensation struct {
+	Action                string     `json:"action"`
+	Applied               bool       `json:"applied"`
+	Reason                string     `json:"reason,omitempty"`
+	FromState             *string    `json:"from_state,omitempty"`
+	ToState               *string    `json:"to_state,omitempty"`
+	VoidedOffers          []string   `json:"voided_offers,omitempty"`
+	DisbursementCancelled bool       `json:"disbursement_cancelled"`
+	CompletedAt           *time.Time `json:"completed_at,omitempty"`
+}
+
+// ApplicantDemographics is a borrower's answers to the government monitoring questions, collected
+// by the loan service apart from the application. Underwriting never reads them; they are only
+// copied into the compliance record.
+type ApplicantDemographics struct {
+	Ethnicity        []string  `json:"ethnicity"`
+	Race             []string  `json:"race"`
+	Sex              string    `json:"sex"`
+	CollectionMethod string    `json:"collection_method"`
+	CollectedAt      time.Time `json:"collected_at"`
+}
+
 // ValidationResult represents validation results
 type ValidationResult struct {
 	Valid    bool              `json:"valid"`
@@ -415,12 +733,16 @@ func NewUnderwritingError(code, message, description string, httpStatus int) *Un
 
 // Helper methods for validation and business logic
 
-// CalculateDTI calculates debt-to-income ratio
-func (app *LoanApplication) CalculateDTI() float64 {
-	if app.MonthlyIncome <= 0 {
-		return 0
-	}
-	return app.MonthlyDebt / app.MonthlyIncome
+// CalculateDTI calculates the debt-to-income ratio, qualifying the applicant's obligations and
+// documented income sources under the calculator's debt treatments and income rules; a nil
+// calculator applies the defaults
+func (app *LoanApplication) CalculateDTI(calculator *dti.Calculator) float64 {
+	return calculator.Calculate(dti.Borrower{
+		MonthlyIncome: app.MonthlyIncome,
+		MonthlyDebt:   app.MonthlyDebt,
+		Obligations:   app.Obligations,
+		IncomeSources: app.IncomeSources,
+	}).Ratio
 }
 
 // GetCreditScoreRange returns the credit score range based on score
@@ -512,8 +834,8 @@ func (app *LoanApplication) Validate() *ValidationResult {
 	}
 
 	// Validate business rules
-	dti := app.CalculateDTI()
-	if dti > 0.5 {
+	dtiRatio := app.CalculateDTI(nil)
+	if dtiRatio > 0.5 {
 		result.Warnings["dti_ratio"] = "DTI ratio is high (>50%)"
 	}
 
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// ApplicantDemographicsRepository implements domain.ApplicantDemographicsRepository over the loan
+// service's applicant_demographics table
+type ApplicantDemographicsRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewApplicantDemographicsRepository creates a new applicant demographics repository
+func NewApplicantDemographicsRepository(db *Connection, logger *zap.Logger) *ApplicantDemographicsRepository {
+	return &ApplicantDemographicsRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetByApplicationID retrieves the demographics the borrower gave for an application
+func (r *ApplicantDemographicsRepository) GetByApplicationID(ctx context.Context, applicationID string) (*domain.ApplicantDemographics, error) {
+	var demographics domain.ApplicantDemographics
+	var ethnicity, race []byte
+	err := r.db.QueryRow(ctx, `
+		SELECT ethnicity, race, sex, collection_method, collected_at
+		FROM applicant_demographics WHERE application_id = $1`,
+		applicationID,
+	).Scan(&ethnicity, &race, &demographics.Sex, &demographics.CollectionMethod, &demographics.CollectedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("applicant demographics not found for application: %s", applicationID)
+		}
+		r.logger.Error("Failed to get applicant demographics", zap.String("application_id", applicationID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get applicant demographics: %w", err)
+	}
+
+	if err := json.Unmarshal(ethnicity, &demographics.Ethnicity); err != nil {
+		return nil, fmt.Errorf("failed to decode ethnicity: %w", err)
+	}
+	if err := json.Unmarshal(race, &demographics.Race); err != nil {
+		return nil, fmt.Errorf("failed to decode race: %w", err)
+	}
+	return &demographics, nil
+}
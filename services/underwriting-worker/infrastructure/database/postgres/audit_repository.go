@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// auditSource marks events written by the underwriting worker
+const auditSource = "underwriting_worker"
+
+// Audit event types
+const (
+	auditEventUnderwriting = "underwriting"
+	auditEventDecision     = "decision"
+	auditEventAccess       = "access"
+)
+
+// AuditRepository implements domain.AuditLogger over the underwriting_audit_events table
+type AuditRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *Connection, logger *zap.Logger) *AuditRepository {
+	return &AuditRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// LogUnderwritingEvent records an underwriting step
+func (r *AuditRepository) LogUnderwritingEvent(ctx context.Context, event *domain.UnderwritingEvent) error {
+	data := map[string]interface{}{
+		"underwriting_event": event.EventType,
+		"data":               event.EventData,
+	}
+	if event.UnderwriterID != "" {
+		data["underwriter_id"] = event.UnderwriterID
+	}
+	return r.insert(ctx, event.EventID, auditEventUnderwriting, event.ApplicationID, event.UserID, data,
+		event.IPAddress, event.UserAgent, event.Timestamp)
+}
+
+// LogDecisionEvent records an underwriting decision
+func (r *AuditRepository) LogDecisionEvent(ctx context.Context, event *domain.DecisionEvent) error {
+	data := map[string]interface{}{
+		"decision":       string(event.Decision),
+		"decision_data":  event.DecisionData,
+		"policy_version": event.PolicyVersion,
+		"model_version":  event.ModelVersion,
+		"automated":      event.Automated,
+	}
+	if event.UnderwriterID != "" {
+		data["underwriter_id"] = event.UnderwriterID
+	}
+	return r.insert(ctx, event.EventID, auditEventDecision, event.ApplicationID, event.UserID, data,
+		"", "", event.Timestamp)
+}
+
+// LogAccessEvent records access to borrower data, such as a credit report pull
+func (r *AuditRepository) LogAccessEvent(ctx context.Context, event *domain.AccessEvent) error {
+	data := map[string]interface{}{
+		"action":   event.Action,
+		"resource": event.Resource,
+		"success":  event.Success,
+	}
+	if event.ErrorMessage != "" {
+		data["error"] = event.ErrorMessage
+	}
+	return r.insert(ctx, event.EventID, auditEventAccess, event.ApplicationID, event.UserID, data,
+		event.IPAddress, event.UserAgent, event.Timestamp)
+}
+
+// GetEvents retrieves audit events matching the filter
+func (r *AuditRepository) GetEvents(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEvent, error) {
+	where := &conditions{}
+	if filter.UserID != "" {
+		where.add("user_id = $%d", filter.UserID)
+	}
+	if filter.ApplicationID != "" {
+		where.add("application_id = $%d", filter.ApplicationID)
+	}
+	if filter.EventType != "" {
+		where.add("event_type = $%d", filter.EventType)
+	}
+	if filter.DateFrom != nil {
+		where.add("created_at >= $%d", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		where.add("created_at <= $%d", *filter.DateTo)
+	}
+
+	query := `
+		SELECT id, event_type, COALESCE(application_id, ''), COALESCE(user_id, ''), event_data, source,
+			COALESCE(ip_address, ''), COALESCE(user_agent, ''), created_at
+		FROM underwriting_audit_events` + where.where() +
+		orderBy(filter.OrderBy, filter.OrderDir, auditSortColumns) +
+		where.page(filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(ctx, query, where.args...)
+	if err != nil {
+		r.logger.Error("Failed to get audit events", zap.Error(err))
+		return nil, fmt.Errorf("failed to get audit events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*domain.AuditEvent{}
+	for rows.Next() {
+		var event domain.AuditEvent
+		var data []byte
+		if err := rows.Scan(
+			&event.ID, &event.EventType, &event.ApplicationID, &event.UserID, &data, &event.Source,
+			&event.IPAddress, &event.UserAgent, &event.Timestamp,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		if err := json.Unmarshal(data, &event.EventData); err != nil {
+			return nil, fmt.Errorf("failed to decode audit event data: %w", err)
+		}
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
+
+// insert writes one audit event. The trail is append-only: every event gets its own row, and the
+// caller's event id is kept with the event data since callers may reuse one.
+func (r *AuditRepository) insert(
+	ctx context.Context,
+	eventID, eventType, applicationID, userID string,
+	data map[string]interface{},
+	ipAddress, userAgent string,
+	timestamp time.Time,
+) error {
+	if eventID != "" {
+		data["event_id"] = eventID
+	}
+	if timestamp.IsZero() {
+		timestamp = time.Now().UTC()
+	}
+	document, err := marshalDocument(data)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO underwriting_audit_events (
+			id, event_type, application_id, user_id, event_data, source, ip_address, user_agent, created_at
+		) VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6, NULLIF($7, ''), NULLIF($8, ''), $9)`,
+		newID(), eventType, applicationID, userID, document, auditSource, ipAddress, userAgent, timestamp,
+	); err != nil {
+		r.logger.Error("Failed to write audit event",
+			zap.String("event_type", eventType),
+			zap.String("application_id", applicationID),
+			zap.Error(err))
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+	return nil
+}
+
+// auditSortColumns are the columns audit events can be listed by
+var auditSortColumns = map[string]string{
+	"created_at": "created_at",
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// BorrowerRepository implements domain.BorrowerRepository over the loan service's users table
+type BorrowerRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewBorrowerRepository creates a new borrower repository
+func NewBorrowerRepository(db *Connection, logger *zap.Logger) *BorrowerRepository {
+	return &BorrowerRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetByUserID retrieves a borrower's identity and employment
+func (r *BorrowerRepository) GetByUserID(ctx context.Context, userID string) (*domain.Borrower, error) {
+	query := `
+		SELECT
+			id, first_name, last_name, date_of_birth, COALESCE(ssn_token, ''),
+			COALESCE(street_address, ''), COALESCE(city, ''), COALESCE(state, ''), COALESCE(zip_code, ''), COALESCE(country, ''),
+			COALESCE(employer_name, ''), COALESCE(job_title, '')
+		FROM users WHERE id = $1`
+
+	var borrower domain.Borrower
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&borrower.UserID, &borrower.FirstName, &borrower.LastName, &borrower.DateOfBirth, &borrower.SSNToken,
+		&borrower.Address.StreetAddress, &borrower.Address.City, &borrower.Address.State,
+		&borrower.Address.ZipCode, &borrower.Address.Country,
+		&borrower.EmployerName, &borrower.JobTitle,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("borrower not found: %s", userID)
+		}
+		r.logger.Error("Failed to get borrower", zap.String("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get borrower: %w", err)
+	}
+	return &borrower, nil
+}
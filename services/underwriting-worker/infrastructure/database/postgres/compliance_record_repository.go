@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// ComplianceRecordRepository implements domain.ComplianceRecordRepository
+type ComplianceRecordRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewComplianceRecordRepository creates a new compliance record repository
+func NewComplianceRecordRepository(db *Connection, logger *zap.Logger) *ComplianceRecordRepository {
+	return &ComplianceRecordRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Save saves the compliance record of an application, replacing an earlier one. The notice delivery
+// recorded for the earlier record is kept while the action taken is unchanged.
+func (r *ComplianceRecordRepository) Save(ctx context.Context, record *domain.ComplianceRecord) error {
+	if record.ID == "" {
+		record.ID = newID()
+	}
+	if record.CapturedAt.IsZero() {
+		record.CapturedAt = time.Now().UTC()
+	}
+	document, err := marshalDocument(record)
+	if err != nil {
+		return err
+	}
+	var demographics interface{}
+	if record.Demographics != nil {
+		if demographics, err = marshalDocument(record.Demographics); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		INSERT INTO underwriting_compliance_records (
+			id, application_id, action_taken, action_taken_date, record, demographics, captured_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (application_id) DO UPDATE SET
+			action_taken = EXCLUDED.action_taken, action_taken_date = EXCLUDED.action_taken_date,
+			record = EXCLUDED.record, demographics = EXCLUDED.demographics, captured_at = EXCLUDED.captured_at,
+			notice_document_id = CASE WHEN underwriting_compliance_records.action_taken = EXCLUDED.action_taken
+				THEN underwriting_compliance_records.notice_document_id END,
+			notice_delivered_at = CASE WHEN underwriting_compliance_records.action_taken = EXCLUDED.action_taken
+				THEN underwriting_compliance_records.notice_delivered_at END
+		RETURNING id, notice_document_id, notice_delivered_at`
+
+	var noticeDocumentID sql.NullString
+	var noticeDeliveredAt sql.NullTime
+	if err := r.db.QueryRow(ctx, query,
+		record.ID, record.ApplicationID, string(record.ActionTaken), record.ActionTakenDate, document,
+		demographics, record.CapturedAt,
+	).Scan(&record.ID, &noticeDocumentID, &noticeDeliveredAt); err != nil {
+		r.logger.Error("Failed to save compliance record",
+			zap.String("application_id", record.ApplicationID),
+			zap.Error(err))
+		return fmt.Errorf("failed to save compliance record: %w", err)
+	}
+	setNoticeDelivery(record, noticeDocumentID, noticeDeliveredAt)
+	return nil
+}
+
+// GetByApplicationID retrieves the compliance record of an application
+func (r *ComplianceRecordRepository) GetByApplicationID(ctx context.Context, applicationID string) (*domain.ComplianceRecord, error) {
+	var document, demographics []byte
+	var noticeDocumentID sql.NullString
+	var noticeDeliveredAt sql.NullTime
+	err := r.db.QueryRow(ctx, `
+		SELECT record, demographics, notice_document_id, notice_delivered_at
+		FROM underwriting_compliance_records WHERE application_id = $1`,
+		applicationID,
+	).Scan(&document, &demographics, &noticeDocumentID, &noticeDeliveredAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("compliance record not found for application: %s", applicationID)
+		}
+		r.logger.Error("Failed to get compliance record", zap.String("application_id", applicationID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get compliance record: %w", err)
+	}
+
+	var record domain.ComplianceRecord
+	if err := json.Unmarshal(document, &record); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	if demographics != nil {
+		record.Demographics = &domain.ApplicantDemographics{}
+		if err := json.Unmarshal(demographics, record.Demographics); err != nil {
+			return nil, fmt.Errorf("failed to decode demographics: %w", err)
+		}
+	}
+	setNoticeDelivery(&record, noticeDocumentID, noticeDeliveredAt)
+	return &record, nil
+}
+
+// RecordNoticeDelivery records when the borrower was sent the notice of the action taken on an
+// application
+func (r *ComplianceRecordRepository) RecordNoticeDelivery(ctx context.Context, applicationID, documentID string, deliveredAt time.Time) error {
+	result, err := r.db.Exec(ctx, `
+		UPDATE underwriting_compliance_records SET notice_document_id = $2, notice_delivered_at = $3
+		WHERE application_id = $1`,
+		applicationID, documentID, deliveredAt)
+	if err != nil {
+		r.logger.Error("Failed to record notice delivery", zap.String("application_id", applicationID), zap.Error(err))
+		return fmt.Errorf("failed to record notice delivery: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("compliance record not found for application: %s", applicationID)
+	}
+	return nil
+}
+
+// setNoticeDelivery sets the notice delivery columns of a compliance record
+func setNoticeDelivery(record *domain.ComplianceRecord, documentID sql.NullString, deliveredAt sql.NullTime) {
+	record.NoticeDocumentID = documentID.String
+	record.NoticeDeliveredAt = nil
+	if deliveredAt.Valid {
+		record.NoticeDeliveredAt = &deliveredAt.Time
+	}
+}
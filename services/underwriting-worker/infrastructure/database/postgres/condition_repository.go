@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// ConditionRepository implements domain.ConditionRepository on the loan API's
+// underwriting_conditions table
+type ConditionRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewConditionRepository creates a new condition repository
+func NewConditionRepository(db *Connection, logger *zap.Logger) *ConditionRepository {
+	return &ConditionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Track records the conditions a workflow waits on. A workflow task records each condition once:
+// when the task is delivered again, the conditions already recorded are returned as they stand.
+// When none of the conditions is critical the workflow does not wait, so they are recorded as
+// already resumed.
+func (r *ConditionRepository) Track(ctx context.Context, conditions []*domain.TrackedCondition) ([]*domain.TrackedCondition, error) {
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	var resumedAt interface{}
+	critical := false
+	for _, condition := range conditions {
+		critical = critical || condition.Critical
+	}
+	if !critical {
+		resumedAt = time.Now().UTC()
+	}
+
+	for _, condition := range conditions {
+		if condition.CreatedAt.IsZero() {
+			condition.CreatedAt = time.Now().UTC()
+		}
+		condition.Status = domain.TrackedConditionPending
+
+		if _, err := r.db.Exec(ctx, `
+			INSERT INTO underwriting_conditions (
+				application_id, workflow_instance_id, task_reference_name, source_condition_id,
+				condition_type, description, priority, critical, status, due_at, workflow_resumed_at,
+				created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $12)
+			ON CONFLICT (workflow_instance_id, source_condition_id) WHERE workflow_instance_id IS NOT NULL
+			DO NOTHING`,
+			condition.ApplicationID, condition.WorkflowInstanceID, condition.TaskReferenceName,
+			condition.SourceConditionID, condition.ConditionType, condition.Description,
+			condition.Priority, condition.Critical, condition.Status, condition.DueAt, resumedAt,
+			condition.CreatedAt,
+		); err != nil {
+			r.logger.Error("Failed to record condition",
+				zap.String("application_id", condition.ApplicationID),
+				zap.String("source_condition_id", condition.SourceConditionID),
+				zap.Error(err))
+			return nil, fmt.Errorf("failed to record condition: %w", err)
+		}
+	}
+
+	return r.listByWorkflow(ctx, conditions[0].WorkflowInstanceID)
+}
+
+func (r *ConditionRepository) listByWorkflow(ctx context.Context, workflowInstanceID string) ([]*domain.TrackedCondition, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, application_id, workflow_instance_id, task_reference_name, source_condition_id,
+			condition_type, description, priority, critical, status, due_at, created_at
+		FROM underwriting_conditions
+		WHERE workflow_instance_id = $1
+		ORDER BY created_at, source_condition_id`,
+		workflowInstanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recorded conditions: %w", err)
+	}
+	defer rows.Close()
+
+	var conditions []*domain.TrackedCondition
+	for rows.Next() {
+		condition := &domain.TrackedCondition{}
+		var taskReferenceName, sourceConditionID sql.NullString
+		var dueAt sql.NullTime
+		if err := rows.Scan(
+			&condition.ID, &condition.ApplicationID, &condition.WorkflowInstanceID, &taskReferenceName,
+			&sourceConditionID, &condition.ConditionType, &condition.Description, &condition.Priority,
+			&condition.Critical, &condition.Status, &dueAt, &condition.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan recorded condition: %w", err)
+		}
+		condition.TaskReferenceName = taskReferenceName.String
+		condition.SourceConditionID = sourceConditionID.String
+		condition.DueAt = dueAt.Time
+		conditions = append(conditions, condition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+	return conditions, nil
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// Config holds database configuration
+type Config struct {
+	Host            string
+	Port            string
+	User            string
+	Password        string
+	Database        string
+	SSLMode         string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// DefaultConfig returns default database configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Host:            "localhost",
+		Port:            "5432",
+		User:            "postgres",
+		Password:        "password",
+		Database:        "loan_service",
+		SSLMode:         "disable",
+		MaxOpenConns:    25,
+		MaxIdleConns:    5,
+		ConnMaxLifetime: 5 * time.Minute,
+	}
+}
+
+// Connection represents a database connection
+type Connection struct {
+	db     *sql.DB
+	logger *zap.Logger
+	config *Config
+}
+
+// NewConnection creates a new database connection
+func NewConnection(config *Config, logger *zap.Logger) (*Connection, error) {
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.User, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+
+	// Configure connection pool
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+
+	// Test the connection
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	logger.Info("Database connection established successfully",
+		zap.String("host", config.Host),
+		zap.String("port", config.Port),
+		zap.String("database", config.Database),
+	)
+
+	return &Connection{
+		db:     db,
+		logger: logger,
+		config: config,
+	}, nil
+}
+
+// GetDB returns the underlying sql.DB instance
+func (c *Connection) GetDB() *sql.DB {
+	return c.db
+}
+
+// Close closes the database connection
+func (c *Connection) Close() error {
+	if c.db != nil {
+		c.logger.Info("Closing database connection")
+		return c.db.Close()
+	}
+	return nil
+}
+
+// HealthCheck checks if the database is healthy
+func (c *Connection) HealthCheck(ctx context.Context) error {
+	return c.db.PingContext(ctx)
+}
+
+// BeginTx starts a new transaction
+func (c *Connection) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return c.db.BeginTx(ctx, opts)
+}
+
+// Exec executes a query without returning rows
+func (c *Connection) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.db.ExecContext(ctx, query, args...)
+}
+
+// Query executes a query that returns rows
+func (c *Connection) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.db.QueryContext(ctx, query, args...)
+}
+
+// QueryRow executes a query that returns a single row
+func (c *Connection) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.db.QueryRowContext(ctx, query, args...)
+}
+
+// Listen calls handle with the payload of each notification on the channel until ctx is cancelled.
+// The listener reconnects on its own after losing the connection.
+func (c *Connection) Listen(ctx context.Context, channel string, handle func(payload string)) error {
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		c.config.Host, c.config.Port, c.config.User, c.config.Password, c.config.Database, c.config.SSLMode)
+
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			c.logger.Warn("Database listener connection problem", zap.String("channel", channel), zap.Error(err))
+		}
+	})
+	if err := listener.Listen(channel); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to listen on %s: %w", channel, err)
+	}
+
+	go func() {
+		defer listener.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case notification := <-listener.Notify:
+				// A nil notification follows a reconnect, when notifications may have been missed
+				if notification == nil {
+					handle("")
+					continue
+				}
+				handle(notification.Extra)
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// CreditReportRepository implements domain.CreditReportRepository
+type CreditReportRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewCreditReportRepository creates a new credit report repository
+func NewCreditReportRepository(db *Connection, logger *zap.Logger) *CreditReportRepository {
+	return &CreditReportRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create saves a credit report, replacing an earlier report with the same id
+func (r *CreditReportRepository) Create(ctx context.Context, report *domain.CreditReport) error {
+	if report.ID == "" {
+		report.ID = newID()
+	}
+	if report.CreatedAt.IsZero() {
+		report.CreatedAt = time.Now().UTC()
+	}
+	document, err := marshalDocument(report)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO underwriting_credit_reports (
+			id, application_id, user_id, credit_score, report_provider, report_date, report, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		ON CONFLICT (id) DO UPDATE SET
+			credit_score = EXCLUDED.credit_score, report_provider = EXCLUDED.report_provider,
+			report_date = EXCLUDED.report_date, report = EXCLUDED.report, updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.Exec(ctx, query,
+		report.ID, report.ApplicationID, report.UserID, report.CreditScore, report.ReportProvider,
+		report.ReportDate, document, report.CreatedAt,
+	); err != nil {
+		r.logger.Error("Failed to save credit report",
+			zap.String("report_id", report.ID),
+			zap.String("application_id", report.ApplicationID),
+			zap.Error(err))
+		return fmt.Errorf("failed to save credit report: %w", err)
+	}
+	return nil
+}
+
+// GetByApplicationID retrieves the latest credit report pulled for an application
+func (r *CreditReportRepository) GetByApplicationID(ctx context.Context, applicationID string) (*domain.CreditReport, error) {
+	report, err := queryDocument[domain.CreditReport](ctx, r.db, `
+		SELECT report FROM underwriting_credit_reports WHERE application_id = $1
+		ORDER BY report_date DESC, created_at DESC LIMIT 1`, applicationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("credit report not found for application: %s", applicationID)
+		}
+		r.logger.Error("Failed to get credit report", zap.String("application_id", applicationID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get credit report: %w", err)
+	}
+	return report, nil
+}
+
+// GetByUserID retrieves a user's credit reports, newest first
+func (r *CreditReportRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.CreditReport, error) {
+	return r.List(ctx, domain.CreditReportFilter{UserID: userID, OrderBy: "report_date"})
+}
+
+// Update replaces a saved credit report
+func (r *CreditReportRepository) Update(ctx context.Context, report *domain.CreditReport) error {
+	document, err := marshalDocument(report)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE underwriting_credit_reports SET
+			credit_score = $1, report_provider = $2, report_date = $3, report = $4, updated_at = $5
+		WHERE id = $6`,
+		report.CreditScore, report.ReportProvider, report.ReportDate, document, time.Now().UTC(), report.ID)
+	if err != nil {
+		r.logger.Error("Failed to update credit report", zap.String("report_id", report.ID), zap.Error(err))
+		return fmt.Errorf("failed to update credit report: %w", err)
+	}
+	return requireRow(result, "credit report", report.ID)
+}
+
+// Delete deletes a credit report
+func (r *CreditReportRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM underwriting_credit_reports WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete credit report", zap.String("report_id", id), zap.Error(err))
+		return fmt.Errorf("failed to delete credit report: %w", err)
+	}
+	return requireRow(result, "credit report", id)
+}
+
+// List retrieves credit reports matching the filter
+func (r *CreditReportRepository) List(ctx context.Context, filter domain.CreditReportFilter) ([]*domain.CreditReport, error) {
+	where := &conditions{}
+	if filter.UserID != "" {
+		where.add("user_id = $%d", filter.UserID)
+	}
+	if filter.ApplicationID != "" {
+		where.add("application_id = $%d", filter.ApplicationID)
+	}
+	if filter.Provider != "" {
+		where.add("report_provider = $%d", filter.Provider)
+	}
+	if filter.DateFrom != nil {
+		where.add("report_date >= $%d", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		where.add("report_date <= $%d", *filter.DateTo)
+	}
+	if filter.MinScore != nil {
+		where.add("credit_score >= $%d", *filter.MinScore)
+	}
+	if filter.MaxScore != nil {
+		where.add("credit_score <= $%d", *filter.MaxScore)
+	}
+
+	query := `SELECT report FROM underwriting_credit_reports` + where.where() +
+		orderBy(filter.OrderBy, filter.OrderDir, creditReportSortColumns) +
+		where.page(filter.Limit, filter.Offset)
+
+	reports, err := queryDocuments[domain.CreditReport](ctx, r.db, query, where.args...)
+	if err != nil {
+		r.logger.Error("Failed to list credit reports", zap.Error(err))
+		return nil, fmt.Errorf("failed to list credit reports: %w", err)
+	}
+	return reports, nil
+}
+
+// creditReportSortColumns are the columns credit reports can be listed by
+var creditReportSortColumns = map[string]string{
+	"created_at":   "created_at",
+	"report_date":  "report_date",
+	"credit_score": "credit_score",
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// DeadLetterRepository implements domain.DeadLetterRepository
+type DeadLetterRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewDeadLetterRepository creates a new dead-letter repository
+func NewDeadLetterRepository(db *Connection, logger *zap.Logger) *DeadLetterRepository {
+	return &DeadLetterRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create adds a task to the dead-letter queue. A task is dead-lettered once: a repeated report of
+// the same task, e.g. after a lost result update, is ignored.
+func (r *DeadLetterRepository) Create(ctx context.Context, task *domain.DeadLetterTask) error {
+	if task.ID == "" {
+		task.ID = newID()
+	}
+	if task.FailedAt.IsZero() {
+		task.FailedAt = time.Now().UTC()
+	}
+
+	input, err := marshalDocument(task.Input)
+	if err != nil {
+		return err
+	}
+	var output []byte
+	if task.Output != nil {
+		if output, err = marshalDocument(task.Output); err != nil {
+			return err
+		}
+	}
+
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO dead_letter_tasks (
+			id, task_id, task_type, workflow_instance_id, application_id, input, output, error,
+			terminal, attempts, conductor_retries, failed_at, updated_at
+		) VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10, $11, $12, $12)
+		ON CONFLICT (task_id) DO NOTHING`,
+		task.ID, task.TaskID, task.TaskType, task.WorkflowInstanceID, task.ApplicationID, input, output,
+		task.Error, task.Terminal, task.Attempts, task.ConductorRetries, task.FailedAt,
+	); err != nil {
+		r.logger.Error("Failed to dead-letter task", zap.String("task_id", task.TaskID), zap.Error(err))
+		return fmt.Errorf("failed to dead-letter task: %w", err)
+	}
+	return nil
+}
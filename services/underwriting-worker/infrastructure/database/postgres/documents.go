@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// conditions accumulates the WHERE conditions of a filtered query and their arguments
+type conditions struct {
+	clauses []string
+	args    []interface{}
+}
+
+// add adds a condition; the clause's %d is replaced by the argument's placeholder number
+func (c *conditions) add(clause string, value interface{}) {
+	c.args = append(c.args, value)
+	c.clauses = append(c.clauses, fmt.Sprintf(clause, len(c.args)))
+}
+
+// where returns the WHERE clause, or nothing when there are no conditions
+func (c *conditions) where() string {
+	if len(c.clauses) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(c.clauses, " AND ")
+}
+
+// page returns the LIMIT and OFFSET clause for a page, adding its arguments
+func (c *conditions) page(limit, offset int) string {
+	if limit <= 0 {
+		return ""
+	}
+	c.args = append(c.args, limit, offset)
+	return fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(c.args)-1, len(c.args))
+}
+
+// orderBy returns the ORDER BY clause for a requested sort. Only listed columns can be sorted by,
+// anything else sorts newest first; id breaks ties so pages stay stable.
+func orderBy(requested, direction string, columns map[string]string) string {
+	column, ok := columns[requested]
+	if !ok {
+		column = "created_at"
+		direction = "DESC"
+	}
+	if !strings.EqualFold(direction, "ASC") {
+		direction = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, id", column, strings.ToUpper(direction))
+}
+
+// requireRow reports a missing record when a write matched no rows
+func requireRow(result sql.Result, kind, id string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s not found: %s", kind, id)
+	}
+	return nil
+}
+
+// nullTime stores a zero time as NULL
+func nullTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+// newID generates an id for a record saved without one
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to generate record id: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// marshalDocument encodes a record for its JSONB document column
+func marshalDocument(record interface{}) ([]byte, error) {
+	document, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document: %w", err)
+	}
+	return document, nil
+}
+
+// queryDocument reads the single JSONB document a query selects. It returns sql.ErrNoRows when
+// there is none.
+func queryDocument[T any](ctx context.Context, db *Connection, query string, args ...interface{}) (*T, error) {
+	var document []byte
+	if err := db.QueryRow(ctx, query, args...).Scan(&document); err != nil {
+		return nil, err
+	}
+
+	record := new(T)
+	if err := json.Unmarshal(document, record); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	return record, nil
+}
+
+// queryDocuments reads the JSONB documents a query selects
+func queryDocuments[T any](ctx context.Context, db *Connection, query string, args ...interface{}) ([]*T, error) {
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []*T{}
+	for rows.Next() {
+		var document []byte
+		if err := rows.Scan(&document); err != nil {
+			return nil, err
+		}
+		record := new(T)
+		if err := json.Unmarshal(document, record); err != nil {
+			return nil, fmt.Errorf("failed to decode document: %w", err)
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
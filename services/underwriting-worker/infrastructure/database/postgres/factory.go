@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// Factory manages database repositories
+type Factory struct {
+	connection *Connection
+	logger     *zap.Logger
+}
+
+// NewFactory creates a new database factory
+func NewFactory(connection *Connection, logger *zap.Logger) *Factory {
+	return &Factory{
+		connection: connection,
+		logger:     logger,
+	}
+}
+
+// GetLoanApplicationRepository returns a new LoanApplicationRepository instance
+func (f *Factory) GetLoanApplicationRepository() *LoanApplicationRepository {
+	return NewLoanApplicationRepository(f.connection, f.logger)
+}
+
+// GetPolicyPinRepository returns a new PolicyPinRepository instance
+func (f *Factory) GetPolicyPinRepository() *PolicyPinRepository {
+	return NewPolicyPinRepository(f.connection, f.logger)
+}
+
+// ListenForPolicyChanges calls handle with the version of each underwriting policy put in force,
+// by any worker instance, until ctx is cancelled. The version is empty after a reconnect, when a
+// change may have been missed.
+func (f *Factory) ListenForPolicyChanges(ctx context.Context, handle func(version string)) error {
+	return f.connection.Listen(ctx, "underwriting_policy_changed", handle)
+}
+
+// GetBorrowerRepository returns a new BorrowerRepository instance
+func (f *Factory) GetBorrowerRepository() *BorrowerRepository {
+	return NewBorrowerRepository(f.connection, f.logger)
+}
+
+// GetCreditReportRepository returns a new CreditReportRepository instance
+func (f *Factory) GetCreditReportRepository() *CreditReportRepository {
+	return NewCreditReportRepository(f.connection, f.logger)
+}
+
+// GetRiskAssessmentRepository returns a new RiskAssessmentRepository instance
+func (f *Factory) GetRiskAssessmentRepository() *RiskAssessmentRepository {
+	return NewRiskAssessmentRepository(f.connection, f.logger)
+}
+
+// GetIncomeVerificationRepository returns a new IncomeVerificationRepository instance
+func (f *Factory) GetIncomeVerificationRepository() *IncomeVerificationRepository {
+	return NewIncomeVerificationRepository(f.connection, f.logger)
+}
+
+// GetUnderwritingResultRepository returns a new UnderwritingResultRepository instance
+func (f *Factory) GetUnderwritingResultRepository() *UnderwritingResultRepository {
+	return NewUnderwritingResultRepository(f.connection, f.logger)
+}
+
+// GetUnderwritingPolicyRepository returns a new UnderwritingPolicyRepository instance
+func (f *Factory) GetUnderwritingPolicyRepository() *UnderwritingPolicyRepository {
+	return NewUnderwritingPolicyRepository(f.connection, f.logger)
+}
+
+// GetWorkflowRepository returns a new WorkflowRepository instance
+func (f *Factory) GetWorkflowRepository() *WorkflowRepository {
+	return NewWorkflowRepository(f.connection, f.logger)
+}
+
+// GetTaskExecutionRepository returns a new TaskExecutionRepository instance
+func (f *Factory) GetTaskExecutionRepository() *TaskExecutionRepository {
+	return NewTaskExecutionRepository(f.connection, f.logger)
+}
+
+// GetTaskRunRepository returns a new TaskRunRepository instance
+func (f *Factory) GetTaskRunRepository() *TaskRunRepository {
+	return NewTaskRunRepository(f.connection, f.logger)
+}
+
+// GetDeadLetterRepository returns a new DeadLetterRepository instance
+func (f *Factory) GetDeadLetterRepository() *DeadLetterRepository {
+	return NewDeadLetterRepository(f.connection, f.logger)
+}
+
+// GetManualReviewRepository returns a new ManualReviewRepository instance
+func (f *Factory) GetManualReviewRepository() *ManualReviewRepository {
+	return NewManualReviewRepository(f.connection, f.logger)
+}
+
+// GetConditionRepository returns a new ConditionRepository instance
+func (f *Factory) GetConditionRepository() *ConditionRepository {
+	return NewConditionRepository(f.connection, f.logger)
+}
+
+// GetFraudSignalRepository returns a new FraudSignalRepository instance
+func (f *Factory) GetFraudSignalRepository() *FraudSignalRepository {
+	return NewFraudSignalRepository(f.connection, f.logger)
+}
+
+// GetComplianceRecordRepository returns a new ComplianceRecordRepository instance
+func (f *Factory) GetComplianceRecordRepository() *ComplianceRecordRepository {
+	return NewComplianceRecordRepository(f.connection, f.logger)
+}
+
+// GetApplicantDemographicsRepository returns a new ApplicantDemographicsRepository instance
+func (f *Factory) GetApplicantDemographicsRepository() *ApplicantDemographicsRepository {
+	return NewApplicantDemographicsRepository(f.connection, f.logger)
+}
+
+// GetFraudReportRepository returns a new FraudReportRepository instance
+func (f *Factory) GetFraudReportRepository() *FraudReportRepository {
+	return NewFraudReportRepository(f.connection, f.logger)
+}
+
+// GetAuditRepository returns a new AuditRepository instance
+func (f *Factory) GetAuditRepository() *AuditRepository {
+	return NewAuditRepository(f.connection, f.logger)
+}
+
+// GetConnection returns the database connection
+func (f *Factory) GetConnection() *Connection {
+	return f.connection
+}
+
+// Close closes the database connection
+func (f *Factory) Close() error {
+	return f.connection.Close()
+}
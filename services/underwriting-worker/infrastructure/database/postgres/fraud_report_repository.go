@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// FraudReportRepository implements domain.FraudReportRepository
+type FraudReportRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewFraudReportRepository creates a new fraud report repository
+func NewFraudReportRepository(db *Connection, logger *zap.Logger) *FraudReportRepository {
+	return &FraudReportRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create saves a fraud report, replacing an earlier report with the same id
+func (r *FraudReportRepository) Create(ctx context.Context, report *domain.FraudReport) error {
+	if report.ID == "" {
+		report.ID = newID()
+	}
+	if report.CreatedAt.IsZero() {
+		report.CreatedAt = time.Now().UTC()
+	}
+	document, err := marshalDocument(report)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO underwriting_fraud_reports (
+			id, application_id, user_id, risk_level, risk_score, recommendation, report, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		ON CONFLICT (id) DO UPDATE SET
+			risk_level = EXCLUDED.risk_level, risk_score = EXCLUDED.risk_score,
+			recommendation = EXCLUDED.recommendation, report = EXCLUDED.report,
+			created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.Exec(ctx, query,
+		report.ID, report.ApplicationID, report.UserID, string(report.RiskLevel), report.RiskScore,
+		report.Recommendation, document, report.CreatedAt,
+	); err != nil {
+		r.logger.Error("Failed to save fraud report",
+			zap.String("report_id", report.ID),
+			zap.String("application_id", report.ApplicationID),
+			zap.Error(err))
+		return fmt.Errorf("failed to save fraud report: %w", err)
+	}
+	return nil
+}
+
+// GetByApplicationID retrieves the latest fraud report of an application
+func (r *FraudReportRepository) GetByApplicationID(ctx context.Context, applicationID string) (*domain.FraudReport, error) {
+	report, err := queryDocument[domain.FraudReport](ctx, r.db, `
+		SELECT report FROM underwriting_fraud_reports WHERE application_id = $1
+		ORDER BY created_at DESC LIMIT 1`, applicationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("fraud report not found for application: %s", applicationID)
+		}
+		r.logger.Error("Failed to get fraud report", zap.String("application_id", applicationID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get fraud report: %w", err)
+	}
+	return report, nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// FraudSignalRepository implements domain.FraudSignalRepository over the loan service's users and
+// application_fingerprints tables. The loan service records the SSN token, normalized email and
+// device of each application in application_fingerprints for its duplicate detection.
+type FraudSignalRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewFraudSignalRepository creates a new fraud signal repository
+func NewFraudSignalRepository(db *Connection, logger *zap.Logger) *FraudSignalRepository {
+	return &FraudSignalRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetIdentity retrieves the identity an application was made with. Applications recorded before
+// fingerprints were kept fall back to the borrower's SSN and email on file.
+func (r *FraudSignalRepository) GetIdentity(ctx context.Context, applicationID string) (*domain.ApplicationIdentity, error) {
+	query := `
+		SELECT
+			a.id::text, a.user_id::text,
+			COALESCE(NULLIF(f.ssn_token, ''), u.ssn_token, ''),
+			COALESCE(NULLIF(f.email_normalized, ''), LOWER(u.email), ''),
+			COALESCE(u.phone_number, ''), COALESCE(f.device_id, '')
+		FROM loan_applications a
+		JOIN users u ON u.id = a.user_id
+		LEFT JOIN application_fingerprints f ON f.application_id = a.id
+		WHERE a.id = $1`
+
+	var identity domain.ApplicationIdentity
+	err := r.db.QueryRow(ctx, query, applicationID).Scan(
+		&identity.ApplicationID, &identity.UserID, &identity.SSNToken,
+		&identity.Email, &identity.Phone, &identity.DeviceID,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("application not found: %s", applicationID)
+		}
+		r.logger.Error("Failed to get application identity", zap.String("application_id", applicationID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get application identity: %w", err)
+	}
+	return &identity, nil
+}
+
+// GetVelocity counts the applications since a time that share the identity's SSN, phone number,
+// email address or device. Empty elements count nothing.
+func (r *FraudSignalRepository) GetVelocity(ctx context.Context, identity *domain.ApplicationIdentity, since time.Time) (*domain.IdentityVelocity, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM application_fingerprints
+				WHERE $1 <> '' AND ssn_token = $1 AND created_at >= $5),
+			(SELECT COUNT(DISTINCT user_id) FROM application_fingerprints
+				WHERE $1 <> '' AND ssn_token = $1 AND user_id::text <> $6 AND created_at >= $5),
+			(SELECT COUNT(DISTINCT u.id) FROM users u JOIN loan_applications a ON a.user_id = u.id
+				WHERE $2 <> '' AND u.phone_number = $2 AND u.id::text <> $6 AND a.created_at >= $5),
+			(SELECT COUNT(DISTINCT user_id) FROM application_fingerprints
+				WHERE $3 <> '' AND email_normalized = $3 AND user_id::text <> $6 AND created_at >= $5),
+			(SELECT COUNT(*) FROM application_fingerprints
+				WHERE $4 <> '' AND device_id = $4 AND created_at >= $5),
+			(SELECT COUNT(DISTINCT user_id) FROM application_fingerprints
+				WHERE $4 <> '' AND device_id = $4 AND user_id::text <> $6 AND created_at >= $5)`
+
+	var velocity domain.IdentityVelocity
+	err := r.db.QueryRow(ctx, query,
+		identity.SSNToken, identity.Phone, identity.Email, identity.DeviceID, since, identity.UserID,
+	).Scan(
+		&velocity.SSNApplications, &velocity.SSNBorrowers, &velocity.PhoneBorrowers,
+		&velocity.EmailBorrowers, &velocity.DeviceApplications, &velocity.DeviceBorrowers,
+	)
+	if err != nil {
+		r.logger.Error("Failed to count identity velocity",
+			zap.String("application_id", identity.ApplicationID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to count identity velocity: %w", err)
+	}
+	return &velocity, nil
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// IncomeVerificationRepository implements domain.IncomeVerificationRepository
+type IncomeVerificationRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewIncomeVerificationRepository creates a new income verification repository
+func NewIncomeVerificationRepository(db *Connection, logger *zap.Logger) *IncomeVerificationRepository {
+	return &IncomeVerificationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create saves an income verification, replacing an earlier verification with the same id
+func (r *IncomeVerificationRepository) Create(ctx context.Context, verification *domain.IncomeVerification) error {
+	if verification.ID == "" {
+		verification.ID = newID()
+	}
+	if verification.CreatedAt.IsZero() {
+		verification.CreatedAt = time.Now().UTC()
+	}
+	document, err := marshalDocument(verification)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO underwriting_income_verifications (
+			id, application_id, user_id, verification_method, verification_status, verification, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (id) DO UPDATE SET
+			verification_method = EXCLUDED.verification_method, verification_status = EXCLUDED.verification_status,
+			verification = EXCLUDED.verification, created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.Exec(ctx, query,
+		verification.ID, verification.ApplicationID, verification.UserID, verification.VerificationMethod,
+		string(verification.VerificationStatus), document, verification.CreatedAt,
+	); err != nil {
+		r.logger.Error("Failed to save income verification",
+			zap.String("verification_id", verification.ID),
+			zap.String("application_id", verification.ApplicationID),
+			zap.Error(err))
+		return fmt.Errorf("failed to save income verification: %w", err)
+	}
+	return nil
+}
+
+// GetByApplicationID retrieves the latest income verification of an application
+func (r *IncomeVerificationRepository) GetByApplicationID(ctx context.Context, applicationID string) (*domain.IncomeVerification, error) {
+	verification, err := queryDocument[domain.IncomeVerification](ctx, r.db, `
+		SELECT verification FROM underwriting_income_verifications WHERE application_id = $1
+		ORDER BY created_at DESC LIMIT 1`, applicationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("income verification not found for application: %s", applicationID)
+		}
+		r.logger.Error("Failed to get income verification", zap.String("application_id", applicationID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get income verification: %w", err)
+	}
+	return verification, nil
+}
+
+// GetByUserID retrieves a user's income verifications, newest first
+func (r *IncomeVerificationRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.IncomeVerification, error) {
+	return r.List(ctx, domain.IncomeVerificationFilter{UserID: userID})
+}
+
+// Update replaces a saved income verification
+func (r *IncomeVerificationRepository) Update(ctx context.Context, verification *domain.IncomeVerification) error {
+	document, err := marshalDocument(verification)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE underwriting_income_verifications SET
+			verification_method = $1, verification_status = $2, verification = $3, updated_at = $4
+		WHERE id = $5`,
+		verification.VerificationMethod, string(verification.VerificationStatus), document, time.Now().UTC(), verification.ID)
+	if err != nil {
+		r.logger.Error("Failed to update income verification", zap.String("verification_id", verification.ID), zap.Error(err))
+		return fmt.Errorf("failed to update income verification: %w", err)
+	}
+	return requireRow(result, "income verification", verification.ID)
+}
+
+// Delete deletes an income verification
+func (r *IncomeVerificationRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM underwriting_income_verifications WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete income verification", zap.String("verification_id", id), zap.Error(err))
+		return fmt.Errorf("failed to delete income verification: %w", err)
+	}
+	return requireRow(result, "income verification", id)
+}
+
+// List retrieves income verifications matching the filter
+func (r *IncomeVerificationRepository) List(ctx context.Context, filter domain.IncomeVerificationFilter) ([]*domain.IncomeVerification, error) {
+	where := &conditions{}
+	if filter.UserID != "" {
+		where.add("user_id = $%d", filter.UserID)
+	}
+	if filter.ApplicationID != "" {
+		where.add("application_id = $%d", filter.ApplicationID)
+	}
+	if filter.Status != "" {
+		where.add("verification_status = $%d", filter.Status)
+	}
+	if filter.Method != "" {
+		where.add("verification_method = $%d", filter.Method)
+	}
+	if filter.DateFrom != nil {
+		where.add("created_at >= $%d", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		where.add("created_at <= $%d", *filter.DateTo)
+	}
+
+	query := `SELECT verification FROM underwriting_income_verifications` + where.where() +
+		orderBy(filter.OrderBy, filter.OrderDir, incomeVerificationSortColumns) +
+		where.page(filter.Limit, filter.Offset)
+
+	verifications, err := queryDocuments[domain.IncomeVerification](ctx, r.db, query, where.args...)
+	if err != nil {
+		r.logger.Error("Failed to list income verifications", zap.Error(err))
+		return nil, fmt.Errorf("failed to list income verifications: %w", err)
+	}
+	return verifications, nil
+}
+
+// incomeVerificationSortColumns are the columns income verifications can be listed by
+var incomeVerificationSortColumns = map[string]string{
+	"created_at": "created_at",
+}
@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/income"
+
+	"underwriting_worker/domain"
+)
+
+const loanApplicationColumns = `
+	id, user_id, application_number, loan_amount, loan_purpose, requested_term_months,
+	annual_income, monthly_income, employment_status, monthly_debt_payments,
+	current_state, status, income_sources, created_at, updated_at`
+
+// LoanApplicationRepository implements domain.LoanApplicationRepository over the loan service's
+// loan_applications table. Applications are owned by the loan service; the worker only moves them
+// through underwriting states.
+type LoanApplicationRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewLoanApplicationRepository creates a new loan application repository
+func NewLoanApplicationRepository(db *Connection, logger *zap.Logger) *LoanApplicationRepository {
+	return &LoanApplicationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetByID retrieves a loan application by ID
+func (r *LoanApplicationRepository) GetByID(ctx context.Context, id string) (*domain.LoanApplication, error) {
+	query := `SELECT ` + loanApplicationColumns + ` FROM loan_applications WHERE id = $1`
+
+	app, err := scanLoanApplication(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("application not found: %s", id)
+		}
+		r.logger.Error("Failed to get application by ID", zap.String("application_id", id), zap.Error(err))
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	return app, nil
+}
+
+// GetByApplicationNumber retrieves a loan application by its application number
+func (r *LoanApplicationRepository) GetByApplicationNumber(ctx context.Context, applicationNumber string) (*domain.LoanApplication, error) {
+	query := `SELECT ` + loanApplicationColumns + ` FROM loan_applications WHERE application_number = $1`
+
+	app, err := scanLoanApplication(r.db.QueryRow(ctx, query, applicationNumber))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("application not found: %s", applicationNumber)
+		}
+		r.logger.Error("Failed to get application by number", zap.String("application_number", applicationNumber), zap.Error(err))
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	return app, nil
+}
+
+// Update saves the application's underwriting state and status. The loan service owns the rest
+// of the application, so other fields are not written.
+func (r *LoanApplicationRepository) Update(ctx context.Context, app *domain.LoanApplication) error {
+	query := `
+		UPDATE loan_applications SET current_state = $1, status = $2, updated_at = $3
+		WHERE id = $4`
+
+	result, err := r.db.Exec(ctx, query, app.CurrentState, app.Status, time.Now().UTC(), app.ID)
+	if err != nil {
+		r.logger.Error("Failed to update application", zap.String("application_id", app.ID), zap.Error(err))
+		return fmt.Errorf("failed to update application: %w", err)
+	}
+	return requireRow(result, "application", app.ID)
+}
+
+// UpdateStatus updates the application's status
+func (r *LoanApplicationRepository) UpdateStatus(ctx context.Context, id string, status string) error {
+	query := `UPDATE loan_applications SET status = $1, updated_at = $2 WHERE id = $3`
+
+	result, err := r.db.Exec(ctx, query, status, time.Now().UTC(), id)
+	if err != nil {
+		r.logger.Error("Failed to update application status", zap.String("application_id", id), zap.Error(err))
+		return fmt.Errorf("failed to update application status: %w", err)
+	}
+	return requireRow(result, "application", id)
+}
+
+// List retrieves loan applications matching the filter
+func (r *LoanApplicationRepository) List(ctx context.Context, filter domain.ApplicationFilter) ([]*domain.LoanApplication, error) {
+	where := applicationConditions(filter)
+	query := `SELECT ` + loanApplicationColumns + ` FROM loan_applications` + where.where() +
+		orderBy(filter.OrderBy, filter.OrderDir, applicationSortColumns) +
+		where.page(filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(ctx, query, where.args...)
+	if err != nil {
+		r.logger.Error("Failed to list applications", zap.Error(err))
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+	defer rows.Close()
+
+	applications := []*domain.LoanApplication{}
+	for rows.Next() {
+		app, err := scanLoanApplication(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan application: %w", err)
+		}
+		applications = append(applications, app)
+	}
+	return applications, rows.Err()
+}
+
+// Count counts loan applications matching the filter
+func (r *LoanApplicationRepository) Count(ctx context.Context, filter domain.ApplicationFilter) (int, error) {
+	where := applicationConditions(filter)
+
+	var count int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM loan_applications`+where.where(), where.args...).Scan(&count); err != nil {
+		r.logger.Error("Failed to count applications", zap.Error(err))
+		return 0, fmt.Errorf("failed to count applications: %w", err)
+	}
+	return count, nil
+}
+
+// applicationSortColumns are the columns applications can be listed by
+var applicationSortColumns = map[string]string{
+	"created_at":  "created_at",
+	"updated_at":  "updated_at",
+	"loan_amount": "loan_amount",
+}
+
+// applicationConditions builds the conditions of an application filter
+func applicationConditions(filter domain.ApplicationFilter) *conditions {
+	where := &conditions{}
+
+	if filter.UserID != "" {
+		where.add("user_id = $%d", filter.UserID)
+	}
+	if filter.Status != "" {
+		where.add("status = $%d", filter.Status)
+	}
+	if filter.State != "" {
+		where.add("current_state = $%d", filter.State)
+	}
+	if filter.DateFrom != nil {
+		where.add("created_at >= $%d", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		where.add("created_at <= $%d", *filter.DateTo)
+	}
+	if filter.LoanAmountMin != nil {
+		where.add("loan_amount >= $%d", *filter.LoanAmountMin)
+	}
+	if filter.LoanAmountMax != nil {
+		where.add("loan_amount <= $%d", *filter.LoanAmountMax)
+	}
+	return where
+}
+
+// scanLoanApplication scans a loan_applications row
+func scanLoanApplication(row rowScanner) (*domain.LoanApplication, error) {
+	var app domain.LoanApplication
+	var incomeSources []byte
+	err := row.Scan(
+		&app.ID, &app.UserID, &app.ApplicationNumber, &app.LoanAmount, &app.LoanPurpose, &app.RequestedTerm,
+		&app.AnnualIncome, &app.MonthlyIncome, &app.EmploymentStatus, &app.MonthlyDebt,
+		&app.CurrentState, &app.Status, &incomeSources, &app.CreatedAt, &app.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if incomeSources != nil {
+		app.IncomeSources = &income.Sources{}
+		if err := json.Unmarshal(incomeSources, app.IncomeSources); err != nil {
+			return nil, fmt.Errorf("failed to decode income sources: %w", err)
+		}
+	}
+	return &app, nil
+}
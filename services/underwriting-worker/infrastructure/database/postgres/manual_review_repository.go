@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// ManualReviewRepository implements domain.ManualReviewRepository
+type ManualReviewRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewManualReviewRepository creates a new manual review repository
+func NewManualReviewRepository(db *Connection, logger *zap.Logger) *ManualReviewRepository {
+	return &ManualReviewRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Enqueue queues a review for an underwriter. A workflow task is queued once: when the task is
+// delivered again, the review already queued for it is returned.
+func (r *ManualReviewRepository) Enqueue(ctx context.Context, review *domain.ManualReview) (*domain.ManualReview, bool, error) {
+	if review.ID == "" {
+		review.ID = newID()
+	}
+	if review.CreatedAt.IsZero() {
+		review.CreatedAt = time.Now().UTC()
+	}
+	if review.ReviewType == "" {
+		review.ReviewType = domain.ManualReviewPrimary
+	}
+	review.Status = domain.ManualReviewPending
+
+	var id string
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO manual_reviews (
+			id, application_id, workflow_instance_id, task_reference_name, reason, risk_level,
+			priority, status, review_type, primary_reviewer_id, due_at, created_at
+		) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, NULLIF($10, ''), $11, $12)
+		ON CONFLICT (workflow_instance_id, task_reference_name) DO NOTHING
+		RETURNING id`,
+		review.ID, review.ApplicationID, review.WorkflowInstanceID, review.TaskReferenceName,
+		review.Reason, review.RiskLevel, review.Priority, string(review.Status),
+		string(review.ReviewType), review.PrimaryReviewerID, review.DueAt, review.CreatedAt,
+	).Scan(&id)
+	if err == nil {
+		return nil, true, nil
+	}
+	if err != sql.ErrNoRows {
+		r.logger.Error("Failed to queue manual review",
+			zap.String("application_id", review.ApplicationID),
+			zap.Error(err))
+		return nil, false, fmt.Errorf("failed to queue manual review: %w", err)
+	}
+
+	existing, err := r.GetByTask(ctx, review.WorkflowInstanceID, review.TaskReferenceName)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get queued manual review: %w", err)
+	}
+	return existing, false, nil
+}
+
+// GetByTask retrieves the review queued for a workflow's human task
+func (r *ManualReviewRepository) GetByTask(ctx context.Context, workflowInstanceID, taskReferenceName string) (*domain.ManualReview, error) {
+	review := &domain.ManualReview{}
+	var riskLevel, primaryReviewerID, decision, completedBy sql.NullString
+	err := r.db.QueryRow(ctx, `
+		SELECT id, application_id, workflow_instance_id, task_reference_name, reason, risk_level,
+			priority, status, review_type, primary_reviewer_id, decision, completed_by, due_at, created_at
+		FROM manual_reviews
+		WHERE workflow_instance_id = $1 AND task_reference_name = $2`,
+		workflowInstanceID, taskReferenceName,
+	).Scan(
+		&review.ID, &review.ApplicationID, &review.WorkflowInstanceID, &review.TaskReferenceName,
+		&review.Reason, &riskLevel, &review.Priority, &review.Status, &review.ReviewType,
+		&primaryReviewerID, &decision, &completedBy, &review.DueAt, &review.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("manual review not found: %s/%s", workflowInstanceID, taskReferenceName)
+		}
+		return nil, fmt.Errorf("failed to get manual review: %w", err)
+	}
+	review.RiskLevel = riskLevel.String
+	review.PrimaryReviewerID = primaryReviewerID.String
+	review.Decision = decision.String
+	review.CompletedBy = completedBy.String
+	return review, nil
+}
@@ -0,0 +1,101 @@
+-- Migration: 001_create_underwriting_tables.sql
+-- Description: Tables the underwriting worker keeps its work in. The worker runs against the loan
+-- service database: it reads loan_applications and users from there and adds the tables below.
+-- Each record keeps the columns it is looked up by plus the full document as JSONB. Record ids
+-- are derived from the application, so re-running a task replaces its earlier record.
+
+CREATE TABLE IF NOT EXISTS underwriting_credit_reports (
+    id VARCHAR(128) PRIMARY KEY,
+    application_id VARCHAR(64) NOT NULL,
+    user_id VARCHAR(64) NOT NULL,
+    credit_score INTEGER NOT NULL,
+    report_provider VARCHAR(50) NOT NULL,
+    report_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    report JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_underwriting_credit_reports_application ON underwriting_credit_reports(application_id, report_date DESC);
+CREATE INDEX IF NOT EXISTS idx_underwriting_credit_reports_user ON underwriting_credit_reports(user_id, report_date DESC);
+
+CREATE TABLE IF NOT EXISTS underwriting_risk_assessments (
+    id VARCHAR(128) PRIMARY KEY,
+    application_id VARCHAR(64) NOT NULL,
+    user_id VARCHAR(64) NOT NULL,
+    overall_risk_level VARCHAR(20) NOT NULL,
+    risk_score DECIMAL(6,2) NOT NULL,
+    assessment JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_underwriting_risk_assessments_application ON underwriting_risk_assessments(application_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_underwriting_risk_assessments_user ON underwriting_risk_assessments(user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS underwriting_income_verifications (
+    id VARCHAR(128) PRIMARY KEY,
+    application_id VARCHAR(64) NOT NULL,
+    user_id VARCHAR(64) NOT NULL,
+    verification_method VARCHAR(50) NOT NULL,
+    verification_status VARCHAR(20) NOT NULL,
+    verification JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_underwriting_income_verifications_application ON underwriting_income_verifications(application_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_underwriting_income_verifications_user ON underwriting_income_verifications(user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS underwriting_results (
+    id VARCHAR(128) PRIMARY KEY,
+    application_id VARCHAR(64) NOT NULL,
+    user_id VARCHAR(64) NOT NULL,
+    decision VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    underwriter_id VARCHAR(64),
+    automated_decision BOOLEAN NOT NULL DEFAULT true,
+    manual_review_required BOOLEAN NOT NULL DEFAULT false,
+    offer_expiration_date TIMESTAMP WITH TIME ZONE,
+    result JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_underwriting_results_application ON underwriting_results(application_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_underwriting_results_user ON underwriting_results(user_id, created_at DESC);
+-- Results waiting for an underwriter: sent to manual review and not yet taken by anyone
+CREATE INDEX IF NOT EXISTS idx_underwriting_results_pending_review ON underwriting_results(created_at) WHERE manual_review_required AND underwriter_id IS NULL;
+
+CREATE TABLE IF NOT EXISTS underwriting_workflows (
+    id VARCHAR(128) PRIMARY KEY,
+    application_id VARCHAR(64) NOT NULL,
+    workflow_id VARCHAR(255) NOT NULL UNIQUE,
+    current_step VARCHAR(100),
+    status VARCHAR(20) NOT NULL,
+    workflow JSONB NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_underwriting_workflows_application ON underwriting_workflows(application_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_underwriting_workflows_active ON underwriting_workflows(started_at) WHERE status IN ('pending', 'in_progress', 'on_hold');
+
+-- Audit trail of underwriting activity: credit report access, underwriting steps and decisions
+CREATE TABLE IF NOT EXISTS underwriting_audit_events (
+    id VARCHAR(128) PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    application_id VARCHAR(64),
+    user_id VARCHAR(64),
+    event_data JSONB NOT NULL DEFAULT '{}',
+    source VARCHAR(50) NOT NULL,
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_underwriting_audit_events_application ON underwriting_audit_events(application_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_underwriting_audit_events_user ON underwriting_audit_events(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_underwriting_audit_events_type ON underwriting_audit_events(event_type, created_at);
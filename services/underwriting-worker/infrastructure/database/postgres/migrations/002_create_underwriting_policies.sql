@@ -0,0 +1,19 @@
+-- Migration: 002_create_underwriting_policies.sql
+-- Description: Underwriting policy versions the worker has decided under. Policies are approved in
+-- the decision engine; the worker keeps each version it loads so decisions can be traced to the
+-- policy applied and underwriting can continue on the last policy while the engine is down.
+
+CREATE TABLE IF NOT EXISTS underwriting_policies (
+    id VARCHAR(128) PRIMARY KEY,
+    policy_name VARCHAR(255) NOT NULL,
+    policy_version VARCHAR(64) NOT NULL UNIQUE,
+    effective_date TIMESTAMP WITH TIME ZONE,
+    is_active BOOLEAN NOT NULL DEFAULT false,
+    created_by VARCHAR(64),
+    policy JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Only one policy is in force at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_underwriting_policies_active ON underwriting_policies(is_active) WHERE is_active;
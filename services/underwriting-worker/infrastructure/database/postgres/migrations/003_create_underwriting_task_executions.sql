@@ -0,0 +1,18 @@
+-- Migration: 003_create_underwriting_task_executions.sql
+-- Description: Task executions of the underwriting worker. Conductor delivers a task again when a
+-- worker times out or a result update is lost; the worker claims each execution here and answers
+-- a task that already completed with its stored output instead of pulling credit or deciding again.
+
+CREATE TABLE IF NOT EXISTS underwriting_task_executions (
+    idempotency_key VARCHAR(255) PRIMARY KEY,
+    task_id VARCHAR(128) NOT NULL,
+    task_type VARCHAR(100) NOT NULL,
+    application_id VARCHAR(64) NOT NULL,
+    workflow_instance_id VARCHAR(128),
+    status VARCHAR(20) NOT NULL,
+    output JSONB,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_underwriting_task_executions_application ON underwriting_task_executions(application_id, task_type);
@@ -0,0 +1,29 @@
+-- Migration: 004_create_dead_letter_tasks.sql
+-- Description: Dead-letter queue of workflow tasks that failed for good: with a terminal error, or
+-- with a transient one after the worker's and Conductor's retries ran out. Each row keeps the
+-- task's input and failure for an operator to inspect, fix the input and re-submit the workflow
+-- from the task through the loan API.
+
+CREATE TABLE IF NOT EXISTS dead_letter_tasks (
+    id VARCHAR(128) PRIMARY KEY,
+    task_id VARCHAR(128) NOT NULL UNIQUE,
+    task_type VARCHAR(100) NOT NULL,
+    workflow_instance_id VARCHAR(128) NOT NULL,
+    application_id VARCHAR(64),
+    input JSONB NOT NULL DEFAULT '{}',
+    output JSONB,
+    error TEXT NOT NULL,
+    terminal BOOLEAN NOT NULL DEFAULT FALSE,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    conductor_retries INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resubmitted', 'discarded')),
+    resolved_by VARCHAR(255),
+    resolution_note TEXT NOT NULL DEFAULT '',
+    failed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_dead_letter_tasks_open ON dead_letter_tasks(failed_at) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_dead_letter_tasks_task_type ON dead_letter_tasks(task_type, failed_at);
+CREATE INDEX IF NOT EXISTS idx_dead_letter_tasks_application ON dead_letter_tasks(application_id);
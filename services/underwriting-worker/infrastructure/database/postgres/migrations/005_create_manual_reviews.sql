@@ -0,0 +1,32 @@
+-- Migration: 005_create_manual_reviews.sql
+-- Description: Queue of applications waiting for an underwriter. The worker queues a review when
+-- the workflow routes an application to manual review; underwriters claim and complete reviews
+-- through the loan API, which completes the workflow's waiting human task with the decision.
+
+CREATE TABLE IF NOT EXISTS manual_reviews (
+    id VARCHAR(128) PRIMARY KEY,
+    application_id VARCHAR(64) NOT NULL,
+    workflow_instance_id VARCHAR(128) NOT NULL,
+    task_reference_name VARCHAR(100) NOT NULL DEFAULT 'manual_review_ref',
+    reason TEXT NOT NULL DEFAULT '',
+    risk_level VARCHAR(20),
+    priority VARCHAR(20) NOT NULL DEFAULT 'normal' CHECK (priority IN ('high', 'normal', 'low')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'in_review', 'completed')),
+    assigned_to VARCHAR(255),
+    decision VARCHAR(20) CHECK (decision IN ('APPROVE', 'DENY')),
+    notes TEXT NOT NULL DEFAULT '',
+    approved_amount DECIMAL(12,2),
+    interest_rate DECIMAL(6,4),
+    conditions JSONB NOT NULL DEFAULT '[]',
+    denial_reasons JSONB NOT NULL DEFAULT '[]',
+    due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    claimed_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    completed_by VARCHAR(255),
+    UNIQUE (workflow_instance_id, task_reference_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_manual_reviews_queue ON manual_reviews(priority, due_at) WHERE status <> 'completed';
+CREATE INDEX IF NOT EXISTS idx_manual_reviews_assigned_to ON manual_reviews(assigned_to, status);
+CREATE INDEX IF NOT EXISTS idx_manual_reviews_application ON manual_reviews(application_id);
@@ -0,0 +1,19 @@
+-- Migration: 006_create_fraud_reports.sql
+-- Description: Fraud report of each application: device fingerprint, identity velocity,
+-- disposable email and bureau fraud alert checks, and the fraud vendor's screening when one is
+-- configured. Identity velocity is counted from the loan service's application_fingerprints.
+
+CREATE TABLE IF NOT EXISTS underwriting_fraud_reports (
+    id VARCHAR(128) PRIMARY KEY,
+    application_id VARCHAR(64) NOT NULL,
+    user_id VARCHAR(64) NOT NULL,
+    risk_level VARCHAR(20) NOT NULL,
+    risk_score DECIMAL(6,2) NOT NULL,
+    recommendation VARCHAR(20) NOT NULL,
+    report JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_underwriting_fraud_reports_application ON underwriting_fraud_reports(application_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_underwriting_fraud_reports_review ON underwriting_fraud_reports(created_at DESC) WHERE recommendation <> 'CLEAR';
@@ -0,0 +1,26 @@
+-- Migration: 007_create_workflow_policy_pins.sql
+-- Description: The underwriting policy version each workflow run decides under. A run is pinned to
+-- the version in force when its first policy-governed task runs, so a version approved mid-run
+-- does not change the rules between its tasks. Putting a policy in force notifies the
+-- underwriting_policy_changed channel, so every worker instance switches to it at once.
+
+CREATE TABLE IF NOT EXISTS workflow_policy_pins (
+    workflow_instance_id VARCHAR(128) PRIMARY KEY,
+    policy_version VARCHAR(64) NOT NULL,
+    pinned_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_workflow_policy_pins_version ON workflow_policy_pins(policy_version);
+
+CREATE OR REPLACE FUNCTION notify_underwriting_policy_changed() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('underwriting_policy_changed', NEW.policy_version);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS underwriting_policy_activated ON underwriting_policies;
+CREATE TRIGGER underwriting_policy_activated
+    AFTER UPDATE OF is_active ON underwriting_policies
+    FOR EACH ROW WHEN (NEW.is_active AND NOT OLD.is_active)
+    EXECUTE FUNCTION notify_underwriting_policy_changed();
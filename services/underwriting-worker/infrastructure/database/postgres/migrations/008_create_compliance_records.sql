@@ -0,0 +1,16 @@
+-- Migration: 008_create_compliance_records.sql
+-- Description: Regulatorily required data of each decided application, captured by the
+-- capture_compliance_data task for the reporting service's exports. The borrower's demographics,
+-- copied from the loan service's applicant_demographics, are kept in a column of their own.
+
+CREATE TABLE IF NOT EXISTS underwriting_compliance_records (
+    id VARCHAR(128) PRIMARY KEY,
+    application_id VARCHAR(64) NOT NULL UNIQUE,
+    action_taken VARCHAR(20) NOT NULL,
+    action_taken_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    record JSONB NOT NULL,
+    demographics JSONB,
+    captured_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_underwriting_compliance_records_action_date ON underwriting_compliance_records(action_taken_date);
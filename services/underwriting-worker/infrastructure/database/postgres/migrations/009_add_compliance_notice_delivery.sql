@@ -0,0 +1,11 @@
+-- Migration: 009_add_compliance_notice_delivery.sql
+-- Description: When the borrower was sent the approval letter or adverse action notice of the
+-- action taken, recorded by the generate_decision_letter task. The notice must reach the borrower
+-- within 30 days of the application; a recaptured record whose action changed is cleared of the
+-- earlier notice.
+
+ALTER TABLE underwriting_compliance_records ADD COLUMN IF NOT EXISTS notice_document_id VARCHAR(64);
+ALTER TABLE underwriting_compliance_records ADD COLUMN IF NOT EXISTS notice_delivered_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_underwriting_compliance_records_notice_pending
+    ON underwriting_compliance_records(action_taken_date) WHERE notice_delivered_at IS NULL;
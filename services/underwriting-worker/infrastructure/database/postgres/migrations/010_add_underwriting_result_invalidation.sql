@@ -0,0 +1,11 @@
+-- Migration: 010_add_underwriting_result_invalidation.sql
+-- Description: When and why an underwriting result was made stale by a material change to the
+-- application before funding, such as a new loan amount or derogatory information, recorded by
+-- the invalidate_underwriting_result task of the re-underwriting workflow. Only results still in
+-- force decide the application.
+
+ALTER TABLE underwriting_results ADD COLUMN IF NOT EXISTS invalidated_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE underwriting_results ADD COLUMN IF NOT EXISTS invalidation_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_underwriting_results_application_current
+    ON underwriting_results(application_id, created_at DESC) WHERE invalidated_at IS NULL;
@@ -0,0 +1,25 @@
+-- Migration: 011_create_underwriting_task_runs.sql
+-- Description: History of every workflow task the underwriting worker ran, failed ones and
+-- redeliveries answered from a stored output included, with the task's input and output, how it
+-- ended, how long it took and the worker that ran it. Support reads an application's history
+-- through the worker's API instead of searching Conductor. Unlike underwriting_task_executions,
+-- rows are never updated or removed.
+
+CREATE TABLE IF NOT EXISTS underwriting_task_runs (
+    id VARCHAR(128) PRIMARY KEY,
+    task_id VARCHAR(128) NOT NULL,
+    task_type VARCHAR(100) NOT NULL,
+    workflow_instance_id VARCHAR(128),
+    application_id VARCHAR(64),
+    worker_id VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('completed', 'deduplicated', 'failed', 'failed_terminal')),
+    input JSONB NOT NULL DEFAULT '{}',
+    output JSONB,
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    duration_ms BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_underwriting_task_runs_application ON underwriting_task_runs(application_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_underwriting_task_runs_workflow ON underwriting_task_runs(workflow_instance_id, started_at);
@@ -0,0 +1,11 @@
+-- Migration: 012_add_secondary_reviews.sql
+-- Description: Four-eyes sign-off of large approvals. The require_secondary_review task queues a
+-- secondary review when an approval exceeds secondary_review.approval_amount; an underwriter other
+-- than the first approver signs it off through the loan API before final_approval runs. Secondary
+-- reviews share the manual review queue and keep who made the first approval.
+
+ALTER TABLE manual_reviews ADD COLUMN IF NOT EXISTS review_type VARCHAR(20) NOT NULL DEFAULT 'primary'
+    CHECK (review_type IN ('primary', 'secondary'));
+ALTER TABLE manual_reviews ADD COLUMN IF NOT EXISTS primary_reviewer_id VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_manual_reviews_type ON manual_reviews(review_type, status);
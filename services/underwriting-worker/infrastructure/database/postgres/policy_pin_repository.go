@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// PolicyPinRepository implements domain.PolicyPinRepository
+type PolicyPinRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewPolicyPinRepository creates a new policy pin repository
+func NewPolicyPinRepository(db *Connection, logger *zap.Logger) *PolicyPinRepository {
+	return &PolicyPinRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Pin pins a workflow run to a policy version unless it is already pinned, and returns the version
+// the run is pinned to
+func (r *PolicyPinRepository) Pin(ctx context.Context, workflowInstanceID, policyVersion string) (string, error) {
+	var pinned string
+	err := r.db.QueryRow(ctx, `
+		WITH inserted AS (
+			INSERT INTO workflow_policy_pins (workflow_instance_id, policy_version)
+			VALUES ($1, $2)
+			ON CONFLICT (workflow_instance_id) DO NOTHING
+			RETURNING policy_version
+		)
+		SELECT policy_version FROM inserted
+		UNION ALL
+		SELECT policy_version FROM workflow_policy_pins WHERE workflow_instance_id = $1
+		LIMIT 1`,
+		workflowInstanceID, policyVersion,
+	).Scan(&pinned)
+	if err != nil {
+		r.logger.Error("Failed to pin workflow policy version",
+			zap.String("workflow_instance_id", workflowInstanceID),
+			zap.String("policy_version", policyVersion),
+			zap.Error(err))
+		return "", fmt.Errorf("failed to pin workflow policy version: %w", err)
+	}
+	return pinned, nil
+}
@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// RiskAssessmentRepository implements domain.RiskAssessmentRepository
+type RiskAssessmentRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewRiskAssessmentRepository creates a new risk assessment repository
+func NewRiskAssessmentRepository(db *Connection, logger *zap.Logger) *RiskAssessmentRepository {
+	return &RiskAssessmentRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create saves a risk assessment, replacing an earlier assessment with the same id
+func (r *RiskAssessmentRepository) Create(ctx context.Context, assessment *domain.RiskAssessment) error {
+	if assessment.ID == "" {
+		assessment.ID = newID()
+	}
+	if assessment.CreatedAt.IsZero() {
+		assessment.CreatedAt = time.Now().UTC()
+	}
+	document, err := marshalDocument(assessment)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO underwriting_risk_assessments (
+			id, application_id, user_id, overall_risk_level, risk_score, assessment, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (id) DO UPDATE SET
+			overall_risk_level = EXCLUDED.overall_risk_level, risk_score = EXCLUDED.risk_score,
+			assessment = EXCLUDED.assessment, created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.Exec(ctx, query,
+		assessment.ID, assessment.ApplicationID, assessment.UserID, string(assessment.OverallRiskLevel),
+		assessment.RiskScore, document, assessment.CreatedAt,
+	); err != nil {
+		r.logger.Error("Failed to save risk assessment",
+			zap.String("assessment_id", assessment.ID),
+			zap.String("application_id", assessment.ApplicationID),
+			zap.Error(err))
+		return fmt.Errorf("failed to save risk assessment: %w", err)
+	}
+	return nil
+}
+
+// GetByApplicationID retrieves the latest risk assessment of an application
+func (r *RiskAssessmentRepository) GetByApplicationID(ctx context.Context, applicationID string) (*domain.RiskAssessment, error) {
+	assessment, err := queryDocument[domain.RiskAssessment](ctx, r.db, `
+		SELECT assessment FROM underwriting_risk_assessments WHERE application_id = $1
+		ORDER BY created_at DESC LIMIT 1`, applicationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("risk assessment not found for application: %s", applicationID)
+		}
+		r.logger.Error("Failed to get risk assessment", zap.String("application_id", applicationID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get risk assessment: %w", err)
+	}
+	return assessment, nil
+}
+
+// GetByUserID retrieves a user's risk assessments, newest first
+func (r *RiskAssessmentRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.RiskAssessment, error) {
+	return r.List(ctx, domain.RiskAssessmentFilter{UserID: userID})
+}
+
+// Update replaces a saved risk assessment
+func (r *RiskAssessmentRepository) Update(ctx context.Context, assessment *domain.RiskAssessment) error {
+	document, err := marshalDocument(assessment)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE underwriting_risk_assessments SET
+			overall_risk_level = $1, risk_score = $2, assessment = $3, updated_at = $4
+		WHERE id = $5`,
+		string(assessment.OverallRiskLevel), assessment.RiskScore, document, time.Now().UTC(), assessment.ID)
+	if err != nil {
+		r.logger.Error("Failed to update risk assessment", zap.String("assessment_id", assessment.ID), zap.Error(err))
+		return fmt.Errorf("failed to update risk assessment: %w", err)
+	}
+	return requireRow(result, "risk assessment", assessment.ID)
+}
+
+// Delete deletes a risk assessment
+func (r *RiskAssessmentRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM underwriting_risk_assessments WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete risk assessment", zap.String("assessment_id", id), zap.Error(err))
+		return fmt.Errorf("failed to delete risk assessment: %w", err)
+	}
+	return requireRow(result, "risk assessment", id)
+}
+
+// List retrieves risk assessments matching the filter
+func (r *RiskAssessmentRepository) List(ctx context.Context, filter domain.RiskAssessmentFilter) ([]*domain.RiskAssessment, error) {
+	where := &conditions{}
+	if filter.UserID != "" {
+		where.add("user_id = $%d", filter.UserID)
+	}
+	if filter.ApplicationID != "" {
+		where.add("application_id = $%d", filter.ApplicationID)
+	}
+	if filter.RiskLevel != "" {
+		where.add("overall_risk_level = $%d", filter.RiskLevel)
+	}
+	if filter.DateFrom != nil {
+		where.add("created_at >= $%d", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		where.add("created_at <= $%d", *filter.DateTo)
+	}
+	if filter.MinScore != nil {
+		where.add("risk_score >= $%d", *filter.MinScore)
+	}
+	if filter.MaxScore != nil {
+		where.add("risk_score <= $%d", *filter.MaxScore)
+	}
+
+	query := `SELECT assessment FROM underwriting_risk_assessments` + where.where() +
+		orderBy(filter.OrderBy, filter.OrderDir, riskAssessmentSortColumns) +
+		where.page(filter.Limit, filter.Offset)
+
+	assessments, err := queryDocuments[domain.RiskAssessment](ctx, r.db, query, where.args...)
+	if err != nil {
+		r.logger.Error("Failed to list risk assessments", zap.Error(err))
+		return nil, fmt.Errorf("failed to list risk assessments: %w", err)
+	}
+	return assessments, nil
+}
+
+// riskAssessmentSortColumns are the columns risk assessments can be listed by
+var riskAssessmentSortColumns = map[string]string{
+	"created_at": "created_at",
+	"risk_score": "risk_score",
+}
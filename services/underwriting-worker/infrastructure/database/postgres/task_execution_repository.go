@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// TaskExecutionRepository implements domain.TaskExecutionRepository
+type TaskExecutionRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewTaskExecutionRepository creates a new task execution repository
+func NewTaskExecutionRepository(db *Connection, logger *zap.Logger) *TaskExecutionRepository {
+	return &TaskExecutionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Claim records the start of an execution. An execution still running past its lease is taken
+// over, since the worker running it is presumed gone.
+func (r *TaskExecutionRepository) Claim(ctx context.Context, execution *domain.TaskExecution, lease time.Duration) (*domain.TaskExecution, bool, error) {
+	if execution.StartedAt.IsZero() {
+		execution.StartedAt = time.Now().UTC()
+	}
+	execution.Status = domain.TaskExecutionRunning
+
+	var key string
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO underwriting_task_executions (
+			idempotency_key, task_id, task_type, application_id, workflow_instance_id, status, started_at
+		) VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7)
+		ON CONFLICT (idempotency_key) DO UPDATE SET
+			task_id = EXCLUDED.task_id, started_at = EXCLUDED.started_at
+		WHERE underwriting_task_executions.status = $6 AND underwriting_task_executions.started_at < $8
+		RETURNING idempotency_key`,
+		execution.IdempotencyKey, execution.TaskID, execution.TaskType, execution.ApplicationID,
+		execution.WorkflowInstanceID, string(domain.TaskExecutionRunning), execution.StartedAt,
+		execution.StartedAt.Add(-lease),
+	).Scan(&key)
+	if err == nil {
+		return nil, true, nil
+	}
+	if err != sql.ErrNoRows {
+		r.logger.Error("Failed to claim task execution", zap.String("idempotency_key", execution.IdempotencyKey), zap.Error(err))
+		return nil, false, fmt.Errorf("failed to claim task execution: %w", err)
+	}
+
+	existing, err := r.get(ctx, execution.IdempotencyKey)
+	if err != nil {
+		return nil, false, err
+	}
+	return existing, false, nil
+}
+
+// Complete stores the output of a claimed execution
+func (r *TaskExecutionRepository) Complete(ctx context.Context, idempotencyKey string, output map[string]interface{}) error {
+	document, err := marshalDocument(output)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE underwriting_task_executions SET status = $1, output = $2, completed_at = $3
+		WHERE idempotency_key = $4`,
+		string(domain.TaskExecutionCompleted), document, time.Now().UTC(), idempotencyKey)
+	if err != nil {
+		r.logger.Error("Failed to complete task execution", zap.String("idempotency_key", idempotencyKey), zap.Error(err))
+		return fmt.Errorf("failed to complete task execution: %w", err)
+	}
+	return requireRow(result, "task execution", idempotencyKey)
+}
+
+// Release drops a claimed execution that did not complete, so the task can run again
+func (r *TaskExecutionRepository) Release(ctx context.Context, idempotencyKey string) error {
+	if _, err := r.db.Exec(ctx, `
+		DELETE FROM underwriting_task_executions WHERE idempotency_key = $1 AND status = $2`,
+		idempotencyKey, string(domain.TaskExecutionRunning),
+	); err != nil {
+		r.logger.Error("Failed to release task execution", zap.String("idempotency_key", idempotencyKey), zap.Error(err))
+		return fmt.Errorf("failed to release task execution: %w", err)
+	}
+	return nil
+}
+
+// get retrieves a task execution by its idempotency key
+func (r *TaskExecutionRepository) get(ctx context.Context, idempotencyKey string) (*domain.TaskExecution, error) {
+	var execution domain.TaskExecution
+	var status string
+	var output []byte
+	var completedAt sql.NullTime
+	err := r.db.QueryRow(ctx, `
+		SELECT idempotency_key, task_id, task_type, application_id, COALESCE(workflow_instance_id, ''),
+			status, output, started_at, completed_at
+		FROM underwriting_task_executions WHERE idempotency_key = $1`, idempotencyKey,
+	).Scan(
+		&execution.IdempotencyKey, &execution.TaskID, &execution.TaskType, &execution.ApplicationID,
+		&execution.WorkflowInstanceID, &status, &output, &execution.StartedAt, &completedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("task execution not found: %s", idempotencyKey)
+		}
+		return nil, fmt.Errorf("failed to get task execution: %w", err)
+	}
+
+	execution.Status = domain.TaskExecutionStatus(status)
+	if completedAt.Valid {
+		execution.CompletedAt = &completedAt.Time
+	}
+	if output != nil {
+		if err := json.Unmarshal(output, &execution.Output); err != nil {
+			return nil, fmt.Errorf("failed to decode task output: %w", err)
+		}
+	}
+	return &execution, nil
+}
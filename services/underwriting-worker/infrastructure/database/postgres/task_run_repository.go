@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// TaskRunRepository implements domain.TaskRunRepository
+type TaskRunRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewTaskRunRepository creates a new task run repository
+func NewTaskRunRepository(db *Connection, logger *zap.Logger) *TaskRunRepository {
+	return &TaskRunRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Record adds a task run to the history
+func (r *TaskRunRepository) Record(ctx context.Context, run *domain.TaskRun) error {
+	if run.ID == "" {
+		run.ID = newID()
+	}
+
+	input, err := marshalDocument(run.Input)
+	if err != nil {
+		return err
+	}
+	var output []byte
+	if run.Output != nil {
+		if output, err = marshalDocument(run.Output); err != nil {
+			return err
+		}
+	}
+
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO underwriting_task_runs (
+			id, task_id, task_type, workflow_instance_id, application_id, worker_id, status, input,
+			output, error, started_at, completed_at, duration_ms
+		) VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7, $8, $9, $10, $11, $12, $13)`,
+		run.ID, run.TaskID, run.TaskType, run.WorkflowInstanceID, run.ApplicationID, run.WorkerID,
+		string(run.Status), input, output, run.Error, run.StartedAt, run.CompletedAt, run.DurationMs,
+	); err != nil {
+		r.logger.Error("Failed to record task run", zap.String("task_id", run.TaskID), zap.Error(err))
+		return fmt.Errorf("failed to record task run: %w", err)
+	}
+	return nil
+}
+
+// ListByApplicationID returns the runs of an application's tasks, oldest first
+func (r *TaskRunRepository) ListByApplicationID(ctx context.Context, applicationID string) ([]*domain.TaskRun, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, task_id, task_type, COALESCE(workflow_instance_id, ''), COALESCE(application_id, ''),
+			worker_id, status, input, output, error, started_at, completed_at, duration_ms
+		FROM underwriting_task_runs
+		WHERE application_id = $1
+		ORDER BY started_at, completed_at`, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []*domain.TaskRun{}
+	for rows.Next() {
+		var run domain.TaskRun
+		var status string
+		var input, output []byte
+		if err := rows.Scan(
+			&run.ID, &run.TaskID, &run.TaskType, &run.WorkflowInstanceID, &run.ApplicationID,
+			&run.WorkerID, &status, &input, &output, &run.Error, &run.StartedAt, &run.CompletedAt,
+			&run.DurationMs,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan task run: %w", err)
+		}
+		run.Status = domain.TaskRunStatus(status)
+		if err := json.Unmarshal(input, &run.Input); err != nil {
+			return nil, fmt.Errorf("failed to decode task input: %w", err)
+		}
+		if output != nil {
+			if err := json.Unmarshal(output, &run.Output); err != nil {
+				return nil, fmt.Errorf("failed to decode task output: %w", err)
+			}
+		}
+		runs = append(runs, &run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list task runs: %w", err)
+	}
+	return runs, nil
+}
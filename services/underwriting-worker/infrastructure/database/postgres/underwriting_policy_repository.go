@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// UnderwritingPolicyRepository implements domain.UnderwritingPolicyRepository
+type UnderwritingPolicyRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewUnderwritingPolicyRepository creates a new underwriting policy repository
+func NewUnderwritingPolicyRepository(db *Connection, logger *zap.Logger) *UnderwritingPolicyRepository {
+	return &UnderwritingPolicyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create saves an underwriting policy, replacing an earlier policy with the same id. Whether the
+// policy is in force is only changed through SetActive.
+func (r *UnderwritingPolicyRepository) Create(ctx context.Context, policy *domain.UnderwritingPolicy) error {
+	if policy.ID == "" {
+		policy.ID = newID()
+	}
+	now := time.Now().UTC()
+	if policy.CreatedAt.IsZero() {
+		policy.CreatedAt = now
+	}
+	policy.UpdatedAt = now
+	document, err := marshalDocument(policy)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO underwriting_policies (
+			id, policy_name, policy_version, effective_date, created_by, policy, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE SET
+			policy_name = EXCLUDED.policy_name, policy_version = EXCLUDED.policy_version,
+			effective_date = EXCLUDED.effective_date, created_by = EXCLUDED.created_by,
+			policy = jsonb_set(EXCLUDED.policy, '{is_active}', to_jsonb(underwriting_policies.is_active)),
+			updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.Exec(ctx, query,
+		policy.ID, policy.PolicyName, policy.PolicyVersion, nullTime(policy.EffectiveDate),
+		policy.CreatedBy, document, policy.CreatedAt, policy.UpdatedAt,
+	); err != nil {
+		r.logger.Error("Failed to save underwriting policy",
+			zap.String("policy_id", policy.ID),
+			zap.String("policy_version", policy.PolicyVersion),
+			zap.Error(err))
+		return fmt.Errorf("failed to save underwriting policy: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an underwriting policy by id
+func (r *UnderwritingPolicyRepository) GetByID(ctx context.Context, id string) (*domain.UnderwritingPolicy, error) {
+	return r.get(ctx, `SELECT policy FROM underwriting_policies WHERE id = $1`, "policy", id)
+}
+
+// GetActive retrieves the underwriting policy in force
+func (r *UnderwritingPolicyRepository) GetActive(ctx context.Context) (*domain.UnderwritingPolicy, error) {
+	policy, err := queryDocument[domain.UnderwritingPolicy](ctx, r.db,
+		`SELECT policy FROM underwriting_policies WHERE is_active`)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("no active underwriting policy")
+		}
+		r.logger.Error("Failed to get active underwriting policy", zap.Error(err))
+		return nil, fmt.Errorf("failed to get underwriting policy: %w", err)
+	}
+	return policy, nil
+}
+
+// GetByVersion retrieves an underwriting policy by version
+func (r *UnderwritingPolicyRepository) GetByVersion(ctx context.Context, version string) (*domain.UnderwritingPolicy, error) {
+	return r.get(ctx, `SELECT policy FROM underwriting_policies WHERE policy_version = $1`, "policy version", version)
+}
+
+// Update replaces a saved underwriting policy
+func (r *UnderwritingPolicyRepository) Update(ctx context.Context, policy *domain.UnderwritingPolicy) error {
+	policy.UpdatedAt = time.Now().UTC()
+	document, err := marshalDocument(policy)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE underwriting_policies SET
+			policy_name = $1, policy_version = $2, effective_date = $3, created_by = NULLIF($4, ''),
+			policy = jsonb_set($5::jsonb, '{is_active}', to_jsonb(is_active)), updated_at = $6
+		WHERE id = $7`,
+		policy.PolicyName, policy.PolicyVersion, nullTime(policy.EffectiveDate), policy.CreatedBy,
+		document, policy.UpdatedAt, policy.ID)
+	if err != nil {
+		r.logger.Error("Failed to update underwriting policy", zap.String("policy_id", policy.ID), zap.Error(err))
+		return fmt.Errorf("failed to update underwriting policy: %w", err)
+	}
+	return requireRow(result, "underwriting policy", policy.ID)
+}
+
+// Delete deletes an underwriting policy
+func (r *UnderwritingPolicyRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM underwriting_policies WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete underwriting policy", zap.String("policy_id", id), zap.Error(err))
+		return fmt.Errorf("failed to delete underwriting policy: %w", err)
+	}
+	return requireRow(result, "underwriting policy", id)
+}
+
+// List retrieves underwriting policies matching the filter
+func (r *UnderwritingPolicyRepository) List(ctx context.Context, filter domain.PolicyFilter) ([]*domain.UnderwritingPolicy, error) {
+	where := &conditions{}
+	if filter.PolicyName != "" {
+		where.add("policy_name = $%d", filter.PolicyName)
+	}
+	if filter.Version != "" {
+		where.add("policy_version = $%d", filter.Version)
+	}
+	if filter.IsActive != nil {
+		where.add("is_active = $%d", *filter.IsActive)
+	}
+	if filter.DateFrom != nil {
+		where.add("effective_date >= $%d", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		where.add("effective_date <= $%d", *filter.DateTo)
+	}
+	if filter.CreatedBy != "" {
+		where.add("created_by = $%d", filter.CreatedBy)
+	}
+
+	query := `SELECT policy FROM underwriting_policies` + where.where() +
+		orderBy(filter.OrderBy, filter.OrderDir, policySortColumns) +
+		where.page(filter.Limit, filter.Offset)
+
+	policies, err := queryDocuments[domain.UnderwritingPolicy](ctx, r.db, query, where.args...)
+	if err != nil {
+		r.logger.Error("Failed to list underwriting policies", zap.Error(err))
+		return nil, fmt.Errorf("failed to list underwriting policies: %w", err)
+	}
+	return policies, nil
+}
+
+// SetActive puts a policy in force, retiring the policy in force before it
+func (r *UnderwritingPolicyRepository) SetActive(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE underwriting_policies SET
+			is_active = false, policy = jsonb_set(policy, '{is_active}', 'false'), updated_at = $1
+		WHERE is_active AND id <> $2`, now, id); err != nil {
+		return fmt.Errorf("failed to retire active underwriting policy: %w", err)
+	}
+	result, err := tx.ExecContext(ctx, `
+		UPDATE underwriting_policies SET
+			is_active = true, policy = jsonb_set(policy, '{is_active}', 'true'), updated_at = $1
+		WHERE id = $2`, now, id)
+	if err != nil {
+		r.logger.Error("Failed to activate underwriting policy", zap.String("policy_id", id), zap.Error(err))
+		return fmt.Errorf("failed to activate underwriting policy: %w", err)
+	}
+	if err := requireRow(result, "underwriting policy", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// get retrieves the single policy a query selects
+func (r *UnderwritingPolicyRepository) get(ctx context.Context, query, kind, key string) (*domain.UnderwritingPolicy, error) {
+	policy, err := queryDocument[domain.UnderwritingPolicy](ctx, r.db, query, key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("underwriting %s not found: %s", kind, key)
+		}
+		r.logger.Error("Failed to get underwriting policy", zap.String("key", key), zap.Error(err))
+		return nil, fmt.Errorf("failed to get underwriting policy: %w", err)
+	}
+	return policy, nil
+}
+
+// policySortColumns are the columns policies can be listed by
+var policySortColumns = map[string]string{
+	"created_at":     "created_at",
+	"effective_date": "effective_date",
+	"policy_version": "policy_version",
+}
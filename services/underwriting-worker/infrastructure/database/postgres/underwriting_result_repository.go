@@ -0,0 +1,230 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// UnderwritingResultRepository implements domain.UnderwritingResultRepository
+type UnderwritingResultRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewUnderwritingResultRepository creates a new underwriting result repository
+func NewUnderwritingResultRepository(db *Connection, logger *zap.Logger) *UnderwritingResultRepository {
+	return &UnderwritingResultRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create saves an underwriting result, replacing an earlier result with the same id
+func (r *UnderwritingResultRepository) Create(ctx context.Context, result *domain.UnderwritingResult) error {
+	if result.ID == "" {
+		result.ID = newID()
+	}
+	now := time.Now().UTC()
+	if result.CreatedAt.IsZero() {
+		result.CreatedAt = now
+	}
+	result.UpdatedAt = now
+	document, err := marshalDocument(result)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO underwriting_results (
+			id, application_id, user_id, decision, status, underwriter_id, automated_decision,
+			manual_review_required, offer_expiration_date, result, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (id) DO UPDATE SET
+			decision = EXCLUDED.decision, status = EXCLUDED.status, underwriter_id = EXCLUDED.underwriter_id,
+			automated_decision = EXCLUDED.automated_decision, manual_review_required = EXCLUDED.manual_review_required,
+			offer_expiration_date = EXCLUDED.offer_expiration_date, result = EXCLUDED.result,
+			created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.Exec(ctx, query,
+		result.ID, result.ApplicationID, result.UserID, string(result.Decision), string(result.Status),
+		result.UnderwriterID, result.AutomatedDecision, result.ManualReviewRequired,
+		nullTime(result.OfferExpirationDate), document, result.CreatedAt, result.UpdatedAt,
+	); err != nil {
+		r.logger.Error("Failed to save underwriting result",
+			zap.String("result_id", result.ID),
+			zap.String("application_id", result.ApplicationID),
+			zap.Error(err))
+		return fmt.Errorf("failed to save underwriting result: %w", err)
+	}
+	return nil
+}
+
+// GetByApplicationID retrieves the latest underwriting result of an application that has not been
+// invalidated
+func (r *UnderwritingResultRepository) GetByApplicationID(ctx context.Context, applicationID string) (*domain.UnderwritingResult, error) {
+	result, err := queryDocument[domain.UnderwritingResult](ctx, r.db, `
+		SELECT result FROM underwriting_results WHERE application_id = $1 AND invalidated_at IS NULL
+		ORDER BY created_at DESC LIMIT 1`, applicationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("underwriting result not found for application: %s", applicationID)
+		}
+		r.logger.Error("Failed to get underwriting result", zap.String("application_id", applicationID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get underwriting result: %w", err)
+	}
+	return result, nil
+}
+
+// GetByUserID retrieves a user's underwriting results, newest first
+func (r *UnderwritingResultRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.UnderwritingResult, error) {
+	return r.List(ctx, domain.UnderwritingResultFilter{UserID: userID})
+}
+
+// GetByID retrieves an underwriting result by ID
+func (r *UnderwritingResultRepository) GetByID(ctx context.Context, id string) (*domain.UnderwritingResult, error) {
+	result, err := queryDocument[domain.UnderwritingResult](ctx, r.db,
+		`SELECT result FROM underwriting_results WHERE id = $1`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("underwriting result not found: %s", id)
+		}
+		r.logger.Error("Failed to get underwriting result", zap.String("result_id", id), zap.Error(err))
+		return nil, fmt.Errorf("failed to get underwriting result: %w", err)
+	}
+	return result, nil
+}
+
+// Update replaces a saved underwriting result
+func (r *UnderwritingResultRepository) Update(ctx context.Context, result *domain.UnderwritingResult) error {
+	result.UpdatedAt = time.Now().UTC()
+	document, err := marshalDocument(result)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.db.Exec(ctx, `
+		UPDATE underwriting_results SET
+			decision = $1, status = $2, underwriter_id = NULLIF($3, ''), automated_decision = $4,
+			manual_review_required = $5, offer_expiration_date = $6, result = $7, updated_at = $8
+		WHERE id = $9`,
+		string(result.Decision), string(result.Status), result.UnderwriterID, result.AutomatedDecision,
+		result.ManualReviewRequired, nullTime(result.OfferExpirationDate), document, result.UpdatedAt, result.ID)
+	if err != nil {
+		r.logger.Error("Failed to update underwriting result", zap.String("result_id", result.ID), zap.Error(err))
+		return fmt.Errorf("failed to update underwriting result: %w", err)
+	}
+	return requireRow(res, "underwriting result", result.ID)
+}
+
+// Delete deletes an underwriting result
+func (r *UnderwritingResultRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM underwriting_results WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete underwriting result", zap.String("result_id", id), zap.Error(err))
+		return fmt.Errorf("failed to delete underwriting result: %w", err)
+	}
+	return requireRow(result, "underwriting result", id)
+}
+
+// List retrieves underwriting results matching the filter
+func (r *UnderwritingResultRepository) List(ctx context.Context, filter domain.UnderwritingResultFilter) ([]*domain.UnderwritingResult, error) {
+	where := &conditions{}
+	if filter.UserID != "" {
+		where.add("user_id = $%d", filter.UserID)
+	}
+	if filter.ApplicationID != "" {
+		where.add("application_id = $%d", filter.ApplicationID)
+	}
+	if filter.Decision != "" {
+		where.add("decision = $%d", filter.Decision)
+	}
+	if filter.Status != "" {
+		where.add("status = $%d", filter.Status)
+	}
+	if filter.UnderwriterID != "" {
+		where.add("underwriter_id = $%d", filter.UnderwriterID)
+	}
+	if filter.DateFrom != nil {
+		where.add("created_at >= $%d", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		where.add("created_at <= $%d", *filter.DateTo)
+	}
+	if filter.Automated != nil {
+		where.add("automated_decision = $%d", *filter.Automated)
+	}
+
+	query := `SELECT result FROM underwriting_results` + where.where() +
+		orderBy(filter.OrderBy, filter.OrderDir, underwritingResultSortColumns) +
+		where.page(filter.Limit, filter.Offset)
+
+	results, err := queryDocuments[domain.UnderwritingResult](ctx, r.db, query, where.args...)
+	if err != nil {
+		r.logger.Error("Failed to list underwriting results", zap.Error(err))
+		return nil, fmt.Errorf("failed to list underwriting results: %w", err)
+	}
+	return results, nil
+}
+
+// GetPendingReviews retrieves results sent to manual review that no underwriter has taken yet,
+// oldest first
+func (r *UnderwritingResultRepository) GetPendingReviews(ctx context.Context) ([]*domain.UnderwritingResult, error) {
+	results, err := queryDocuments[domain.UnderwritingResult](ctx, r.db, `
+		SELECT result FROM underwriting_results
+		WHERE manual_review_required AND underwriter_id IS NULL AND invalidated_at IS NULL
+		ORDER BY created_at, id`)
+	if err != nil {
+		r.logger.Error("Failed to get pending reviews", zap.Error(err))
+		return nil, fmt.Errorf("failed to get pending reviews: %w", err)
+	}
+	return results, nil
+}
+
+// GetApprovedOffers retrieves a user's approved, conditional and counter offers that have not
+// expired or been invalidated
+func (r *UnderwritingResultRepository) GetApprovedOffers(ctx context.Context, userID string) ([]*domain.UnderwritingResult, error) {
+	results, err := queryDocuments[domain.UnderwritingResult](ctx, r.db, `
+		SELECT result FROM underwriting_results
+		WHERE user_id = $1 AND decision IN ($2, $3, $4) AND offer_expiration_date > $5 AND invalidated_at IS NULL
+		ORDER BY created_at DESC, id`,
+		userID, string(domain.DecisionApproved), string(domain.DecisionConditional), string(domain.DecisionCounterOffer),
+		time.Now().UTC())
+	if err != nil {
+		r.logger.Error("Failed to get approved offers", zap.String("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get approved offers: %w", err)
+	}
+	return results, nil
+}
+
+// Invalidate marks the application's underwriting results that are still in force as stale, with
+// the reason, and returns how many were invalidated; results invalidated earlier are left as they were
+func (r *UnderwritingResultRepository) Invalidate(ctx context.Context, applicationID, reason string, invalidatedAt time.Time) (int, error) {
+	invalidatedAt = invalidatedAt.UTC()
+	res, err := r.db.Exec(ctx, `
+		UPDATE underwriting_results SET
+			invalidated_at = $1, invalidation_reason = $2, updated_at = $1,
+			result = result || jsonb_build_object('invalidated_at', $3::text, 'invalidation_reason', $2::text)
+		WHERE application_id = $4 AND invalidated_at IS NULL`,
+		invalidatedAt, reason, invalidatedAt.Format(time.RFC3339Nano), applicationID)
+	if err != nil {
+		r.logger.Error("Failed to invalidate underwriting results", zap.String("application_id", applicationID), zap.Error(err))
+		return 0, fmt.Errorf("failed to invalidate underwriting results: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rows), nil
+}
+
+// underwritingResultSortColumns are the columns underwriting results can be listed by
+var underwritingResultSortColumns = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
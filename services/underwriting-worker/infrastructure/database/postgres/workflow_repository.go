@@ -0,0 +1,229 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// WorkflowRepository implements domain.UnderwritingWorkflowRepository
+type WorkflowRepository struct {
+	db     *Connection
+	logger *zap.Logger
+}
+
+// NewWorkflowRepository creates a new underwriting workflow repository
+func NewWorkflowRepository(db *Connection, logger *zap.Logger) *WorkflowRepository {
+	return &WorkflowRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create saves a new underwriting workflow
+func (r *WorkflowRepository) Create(ctx context.Context, workflow *domain.UnderwritingWorkflow) error {
+	if workflow.ID == "" {
+		workflow.ID = newID()
+	}
+	now := time.Now().UTC()
+	if workflow.CreatedAt.IsZero() {
+		workflow.CreatedAt = now
+	}
+	if workflow.StartedAt.IsZero() {
+		workflow.StartedAt = now
+	}
+	workflow.UpdatedAt = now
+	document, err := marshalDocument(workflow)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO underwriting_workflows (
+			id, application_id, workflow_id, current_step, status, workflow, started_at, completed_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		workflow.ID, workflow.ApplicationID, workflow.WorkflowID, workflow.CurrentStep, string(workflow.Status),
+		document, workflow.StartedAt, workflow.CompletedAt, workflow.CreatedAt, workflow.UpdatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create workflow",
+			zap.String("workflow_id", workflow.WorkflowID),
+			zap.String("application_id", workflow.ApplicationID),
+			zap.Error(err))
+		return fmt.Errorf("failed to create workflow: %w", err)
+	}
+	return nil
+}
+
+// GetByApplicationID retrieves the latest underwriting workflow of an application
+func (r *WorkflowRepository) GetByApplicationID(ctx context.Context, applicationID string) (*domain.UnderwritingWorkflow, error) {
+	workflow, err := queryDocument[domain.UnderwritingWorkflow](ctx, r.db, `
+		SELECT workflow FROM underwriting_workflows WHERE application_id = $1
+		ORDER BY created_at DESC LIMIT 1`, applicationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("workflow not found for application: %s", applicationID)
+		}
+		r.logger.Error("Failed to get workflow", zap.String("application_id", applicationID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get workflow: %w", err)
+	}
+	return workflow, nil
+}
+
+// GetByWorkflowID retrieves an underwriting workflow by its workflow engine id
+func (r *WorkflowRepository) GetByWorkflowID(ctx context.Context, workflowID string) (*domain.UnderwritingWorkflow, error) {
+	workflow, err := queryDocument[domain.UnderwritingWorkflow](ctx, r.db,
+		`SELECT workflow FROM underwriting_workflows WHERE workflow_id = $1`, workflowID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("workflow not found: %s", workflowID)
+		}
+		r.logger.Error("Failed to get workflow", zap.String("workflow_id", workflowID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get workflow: %w", err)
+	}
+	return workflow, nil
+}
+
+// Update replaces a saved underwriting workflow
+func (r *WorkflowRepository) Update(ctx context.Context, workflow *domain.UnderwritingWorkflow) error {
+	workflow.UpdatedAt = time.Now().UTC()
+	document, err := marshalDocument(workflow)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE underwriting_workflows SET
+			current_step = $1, status = $2, workflow = $3, completed_at = $4, updated_at = $5
+		WHERE workflow_id = $6`,
+		workflow.CurrentStep, string(workflow.Status), document, workflow.CompletedAt, workflow.UpdatedAt, workflow.WorkflowID)
+	if err != nil {
+		r.logger.Error("Failed to update workflow", zap.String("workflow_id", workflow.WorkflowID), zap.Error(err))
+		return fmt.Errorf("failed to update workflow: %w", err)
+	}
+	return requireRow(result, "workflow", workflow.WorkflowID)
+}
+
+// UpdateStep replaces one step of a workflow, adding it when the workflow does not have it yet.
+// The workflow row is locked while the step is written so concurrent task updates do not
+// overwrite each other.
+func (r *WorkflowRepository) UpdateStep(ctx context.Context, workflowID string, stepID string, step domain.WorkflowStep) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var document []byte
+	if err := tx.QueryRowContext(ctx,
+		`SELECT workflow FROM underwriting_workflows WHERE workflow_id = $1 FOR UPDATE`, workflowID,
+	).Scan(&document); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("workflow not found: %s", workflowID)
+		}
+		return fmt.Errorf("failed to get workflow: %w", err)
+	}
+
+	var workflow domain.UnderwritingWorkflow
+	if err := json.Unmarshal(document, &workflow); err != nil {
+		return fmt.Errorf("failed to decode document: %w", err)
+	}
+
+	step.StepID = stepID
+	replaced := false
+	for i := range workflow.Steps {
+		if workflow.Steps[i].StepID == stepID {
+			workflow.Steps[i] = step
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		workflow.Steps = append(workflow.Steps, step)
+	}
+	workflow.CurrentStep = stepID
+	workflow.UpdatedAt = time.Now().UTC()
+
+	if document, err = marshalDocument(workflow); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE underwriting_workflows SET current_step = $1, workflow = $2, updated_at = $3
+		WHERE workflow_id = $4`,
+		workflow.CurrentStep, document, workflow.UpdatedAt, workflowID,
+	); err != nil {
+		r.logger.Error("Failed to update workflow step",
+			zap.String("workflow_id", workflowID),
+			zap.String("step_id", stepID),
+			zap.Error(err))
+		return fmt.Errorf("failed to update workflow step: %w", err)
+	}
+	return tx.Commit()
+}
+
+// Delete deletes an underwriting workflow
+func (r *WorkflowRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM underwriting_workflows WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete workflow", zap.String("id", id), zap.Error(err))
+		return fmt.Errorf("failed to delete workflow: %w", err)
+	}
+	return requireRow(result, "workflow", id)
+}
+
+// List retrieves underwriting workflows matching the filter
+func (r *WorkflowRepository) List(ctx context.Context, filter domain.WorkflowFilter) ([]*domain.UnderwritingWorkflow, error) {
+	where := &conditions{}
+	if filter.ApplicationID != "" {
+		where.add("application_id = $%d", filter.ApplicationID)
+	}
+	if filter.Status != "" {
+		where.add("status = $%d", filter.Status)
+	}
+	if filter.CurrentStep != "" {
+		where.add("current_step = $%d", filter.CurrentStep)
+	}
+	if filter.DateFrom != nil {
+		where.add("started_at >= $%d", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		where.add("started_at <= $%d", *filter.DateTo)
+	}
+
+	query := `SELECT workflow FROM underwriting_workflows` + where.where() +
+		orderBy(filter.OrderBy, filter.OrderDir, workflowSortColumns) +
+		where.page(filter.Limit, filter.Offset)
+
+	workflows, err := queryDocuments[domain.UnderwritingWorkflow](ctx, r.db, query, where.args...)
+	if err != nil {
+		r.logger.Error("Failed to list workflows", zap.Error(err))
+		return nil, fmt.Errorf("failed to list workflows: %w", err)
+	}
+	return workflows, nil
+}
+
+// GetActiveWorkflows retrieves workflows that have not completed or been cancelled, oldest first
+func (r *WorkflowRepository) GetActiveWorkflows(ctx context.Context) ([]*domain.UnderwritingWorkflow, error) {
+	workflows, err := queryDocuments[domain.UnderwritingWorkflow](ctx, r.db, `
+		SELECT workflow FROM underwriting_workflows
+		WHERE status IN ($1, $2, $3)
+		ORDER BY started_at, id`,
+		string(domain.StatusPending), string(domain.StatusInProgress), string(domain.StatusOnHold))
+	if err != nil {
+		r.logger.Error("Failed to get active workflows", zap.Error(err))
+		return nil, fmt.Errorf("failed to get active workflows: %w", err)
+	}
+	return workflows, nil
+}
+
+// workflowSortColumns are the columns workflows can be listed by
+var workflowSortColumns = map[string]string{
+	"created_at": "created_at",
+	"started_at": "started_at",
+	"updated_at": "updated_at",
+}
@@ -0,0 +1,96 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Compensation steps of the loan service run by the failure workflow
+const (
+	compensationRollbackState  = "rollback_state"
+	compensationVoidOffers     = "void_offers"
+	compensationNotifyBorrower = "notify_borrower"
+)
+
+// handleStateRollback has the loan service return the application of a workflow that failed
+// midway to the state it was in when the workflow started, cancelling a disbursement still
+// waiting for funding. It runs first in loan_compensation_workflow, the failure workflow of the
+// loan workflows.
+func (w *UnderwritingTaskWorker) handleStateRollback(ctx context.Context, input map[string]interface{}) (map[string]interface{}, error) {
+	return w.compensate(ctx, "rollback_application_state", compensationRollbackState, input)
+}
+
+// handleOfferVoiding has the loan service void the offers a failed workflow made, including one
+// the borrower selected or accepted, so they cannot go on to be signed or funded
+func (w *UnderwritingTaskWorker) handleOfferVoiding(ctx context.Context, input map[string]interface{}) (map[string]interface{}, error) {
+	return w.compensate(ctx, "void_workflow_offers", compensationVoidOffers, input)
+}
+
+// handleFailureNotification has the loan service tell the borrower their application could not
+// be processed. It runs last, once the application has been rolled back.
+func (w *UnderwritingTaskWorker) handleFailureNotification(ctx context.Context, input map[string]interface{}) (map[string]interface{}, error) {
+	return w.compensate(ctx, "notify_borrower_of_failure", compensationNotifyBorrower, input)
+}
+
+// compensate runs one compensation step of the loan service for the failed workflow in the input
+func (w *UnderwritingTaskWorker) compensate(ctx context.Context, operation, action string, input map[string]interface{}) (map[string]interface{}, error) {
+	logger := w.logger.With(zap.String("operation", operation))
+
+	applicationID, ok := input["applicationId"].(string)
+	if !ok || applicationID == "" {
+		return nil, fmt.Errorf("application ID is required")
+	}
+	workflowID, ok := input["workflowId"].(string)
+	if !ok || workflowID == "" {
+		return nil, fmt.Errorf("failed workflow ID is required")
+	}
+	if w.loanService == nil {
+		return nil, fmt.Errorf("loan service is not configured")
+	}
+	logger = logger.With(zap.String("application_id", applicationID), zap.String("workflow_id", workflowID))
+
+	request := &compensationRequest{
+		Action:     action,
+		WorkflowID: workflowID,
+	}
+	request.WorkflowName, _ = input["workflowName"].(string)
+	request.Reason, _ = input["reason"].(string)
+	// Conductor gives the failed workflow's start time in epoch milliseconds
+	if startTime, ok := input["workflowStartTime"].(float64); ok && startTime > 0 {
+		startedAt := time.UnixMilli(int64(startTime)).UTC()
+		request.WorkflowStartedAt = &startedAt
+	}
+
+	compensation, err := w.loanService.Compensate(ctx, applicationID, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compensate workflow: %w", err)
+	}
+
+	logger.Info("Compensation step completed",
+		zap.Bool("applied", compensation.Applied),
+		zap.String("reason", compensation.Reason))
+
+	output := map[string]interface{}{
+		"applied": compensation.Applied,
+		"reason":  compensation.Reason,
+	}
+	switch action {
+	case compensationRollbackState:
+		output["fromState"] = compensation.FromState
+		output["toState"] = compensation.ToState
+		output["disbursementCancelled"] = compensation.DisbursementCancelled
+	case compensationVoidOffers:
+		voidedOffers := compensation.VoidedOffers
+		if voidedOffers == nil {
+			voidedOffers = []string{}
+		}
+		output["voidedOffers"] = voidedOffers
+	}
+	if compensation.CompletedAt != nil {
+		output["completedAt"] = compensation.CompletedAt.Format(time.RFC3339)
+	}
+	return output, nil
+}
@@ -0,0 +1,185 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// handleComplianceCapture captures the regulatorily required data of a decided application: the
+// application date, the action taken, the denial reasons and the demographics the borrower gave
+// the loan service. It runs last in the underwriting workflow, and again when a borrower accepts a
+// counter offer; a run that ends without a final action, such as one left in manual review,
+// captures nothing.
+func (w *UnderwritingTaskWorker) handleComplianceCapture(ctx context.Context, input map[string]interface{}) (map[string]interface{}, error) {
+	logger := w.logger.With(zap.String("operation", "capture_compliance_data"))
+
+	applicationID, ok := input["applicationId"].(string)
+	if !ok || applicationID == "" {
+		return nil, fmt.Errorf("application ID is required")
+	}
+	if w.complianceRecords == nil || w.applications == nil {
+		return nil, fmt.Errorf("compliance record store is not configured")
+	}
+	logger = logger.With(zap.String("application_id", applicationID))
+
+	application, err := w.applications.GetByID(ctx, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+
+	var result *domain.UnderwritingResult
+	if w.underwritingResults != nil {
+		if result, err = w.underwritingResults.GetByApplicationID(ctx, applicationID); err != nil && !strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("failed to get underwriting result: %w", err)
+		}
+	}
+
+	decision, _ := input["decision"].(string)
+	action, final := complianceAction(decision, application.CurrentState, result)
+	if !final {
+		logger.Info("Application has no final action yet, compliance data not captured",
+			zap.String("decision", decision),
+			zap.String("current_state", application.CurrentState))
+		return map[string]interface{}{
+			"captured": false,
+			"reason":   "no final action taken on the application",
+		}, nil
+	}
+
+	applicationDate := application.SubmittedAt
+	if applicationDate.IsZero() {
+		applicationDate = application.CreatedAt
+	}
+	workflowInstanceID, _ := input["workflowInstanceId"].(string)
+	record := &domain.ComplianceRecord{
+		ApplicationID:      applicationID,
+		ApplicationNumber:  application.ApplicationNumber,
+		UserID:             application.UserID,
+		WorkflowInstanceID: workflowInstanceID,
+		ApplicationDate:    applicationDate,
+		ActionTaken:        action,
+		ActionTakenDate:    time.Now().UTC(),
+		DenialReasons:      []domain.DecisionReason{},
+		LoanAmount:         application.LoanAmount,
+		LoanTermMonths:     application.RequestedTerm,
+		LoanPurpose:        application.LoanPurpose,
+	}
+	if result != nil {
+		record.PolicyVersion = result.PolicyVersion
+		if action == domain.ComplianceActionApproved && result.ApprovedAmount > 0 {
+			record.LoanAmount = result.ApprovedAmount
+			record.LoanTermMonths = result.ApprovedTerm
+			record.InterestRate = result.InterestRate
+			record.APR = result.APR
+		}
+	}
+	if action == domain.ComplianceActionDenied {
+		record.DenialReasons = denialReasons(input["denialReasons"], result)
+	}
+
+	// Demographics are optional for the borrower; reports carry "not provided" without them
+	if w.applicantDemographics != nil {
+		demographics, err := w.applicantDemographics.GetByApplicationID(ctx, applicationID)
+		if err != nil && !strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("failed to get applicant demographics: %w", err)
+		}
+		record.Demographics = demographics
+	}
+
+	if err := w.complianceRecords.Save(ctx, record); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Compliance data captured",
+		zap.String("compliance_record_id", record.ID),
+		zap.String("action_taken", string(record.ActionTaken)),
+		zap.Int("denial_reasons", len(record.DenialReasons)),
+		zap.Bool("demographics_provided", record.Demographics != nil))
+
+	return map[string]interface{}{
+		"captured":             true,
+		"complianceRecordId":   record.ID,
+		"actionTaken":          string(record.ActionTaken),
+		"actionTakenDate":      record.ActionTakenDate.Format(time.RFC3339),
+		"demographicsProvided": record.Demographics != nil,
+	}, nil
+}
+
+// complianceAction determines the action taken on an application from the workflow's decision,
+// or without one from the application's state and then its underwriting result. It reports false
+// when no final action has been taken.
+func complianceAction(decision, state string, result *domain.UnderwritingResult) (domain.ComplianceAction, bool) {
+	if decision != "" {
+		switch strings.ToUpper(decision) {
+		case "APPROVED", "APPROVE", "ACCEPTED":
+			return domain.ComplianceActionApproved, true
+		case "DENIED", "DENY":
+			return domain.ComplianceActionDenied, true
+		case "WITHDRAWN":
+			return domain.ComplianceActionWithdrawn, true
+		}
+		return "", false
+	}
+
+	switch state {
+	case "approved":
+		return domain.ComplianceActionApproved, true
+	case "denied":
+		return domain.ComplianceActionDenied, true
+	case "withdrawn":
+		return domain.ComplianceActionWithdrawn, true
+	}
+
+	if result != nil {
+		switch result.Decision {
+		case domain.DecisionApproved:
+			return domain.ComplianceActionApproved, true
+		case domain.DecisionDenied:
+			return domain.ComplianceActionDenied, true
+		}
+	}
+	return "", false
+}
+
+// denialReasons returns the reasons an application was denied for, most significant first: those
+// the workflow passed, as reason codes or reason objects, or else the denial reasons of its
+// underwriting result
+func denialReasons(passed interface{}, result *domain.UnderwritingResult) []domain.DecisionReason {
+	reasons := []domain.DecisionReason{}
+	if items, ok := passed.([]interface{}); ok {
+		for _, item := range items {
+			switch reason := item.(type) {
+			case string:
+				reasons = append(reasons, domain.DecisionReason{ReasonCode: reason, ReasonType: "denial"})
+			case map[string]interface{}:
+				// Decision reasons also carry the approval and condition reasons
+				if reasonType, _ := reason["reasonType"].(string); reasonType != "" && reasonType != "denial" {
+					continue
+				}
+				code, _ := reason["reasonCode"].(string)
+				description, _ := reason["description"].(string)
+				impact, _ := reason["impact"].(string)
+				weight, _ := reason["weight"].(float64)
+				reasons = append(reasons, domain.DecisionReason{ReasonCode: code, ReasonType: "denial", Description: description, Impact: impact, Weight: weight})
+			}
+		}
+	}
+	if len(reasons) == 0 && result != nil {
+		for _, reason := range result.DecisionReasons {
+			if reason.ReasonType == "denial" {
+				reasons = append(reasons, reason)
+			}
+		}
+	}
+
+	if len(reasons) > domain.MaxComplianceDenialReasons {
+		reasons = reasons[:domain.MaxComplianceDenialReasons]
+	}
+	return reasons
+}
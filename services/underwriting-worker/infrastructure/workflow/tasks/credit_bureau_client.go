@@ -0,0 +1,337 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// CreditBureauClient implements domain.CreditBureauService over the decision engine's bureau
+// pulls, so the worker never holds bureau credentials or raw SSNs. The borrower's identity is
+// read from the loan service when a request does not carry it.
+type CreditBureauClient struct {
+	engine       *DecisionEngineClient
+	borrowers    domain.BorrowerRepository
+	applications domain.LoanApplicationRepository
+	logger       *zap.Logger
+}
+
+// NewCreditBureauClient creates a credit bureau client
+func NewCreditBureauClient(
+	engine *DecisionEngineClient,
+	borrowers domain.BorrowerRepository,
+	applications domain.LoanApplicationRepository,
+	logger *zap.Logger,
+) *CreditBureauClient {
+	return &CreditBureauClient{
+		engine:       engine,
+		borrowers:    borrowers,
+		applications: applications,
+		logger:       logger,
+	}
+}
+
+// bureauPullRequest is the decision engine's bureau pull request
+type bureauPullRequest struct {
+	UserID        string `json:"user_id"`
+	SSNToken      string `json:"ssn_token"`
+	FirstName     string `json:"first_name"`
+	LastName      string `json:"last_name"`
+	DateOfBirth   string `json:"date_of_birth"`
+	Address       string `json:"address,omitempty"`
+	ReportType    string `json:"report_type"`
+	PullType      string `json:"pull_type"`
+	ApplicationID string `json:"application_id"`
+	ForceRefresh  bool   `json:"force_refresh,omitempty"`
+}
+
+// bureauPullResponse is the part of the decision engine's bureau pull the worker uses
+type bureauPullResponse struct {
+	Report *struct {
+		Bureau   string        `json:"bureau"`
+		Report   *engineReport `json:"report"`
+		PullType string        `json:"pull_type"`
+		PulledAt time.Time     `json:"pulled_at"`
+		Cached   bool          `json:"cached"`
+	} `json:"report"`
+	FailedOver bool `json:"failed_over"`
+}
+
+// engineReport is the decision engine's normalized credit report
+type engineReport struct {
+	CreditScore int    `json:"credit_score"`
+	ScoreModel  string `json:"score_model"`
+	Bureau      string `json:"bureau"`
+	Accounts    []struct {
+		AccountID      string    `json:"account_id"`
+		AccountType    string    `json:"account_type"`
+		Creditor       string    `json:"creditor"`
+		Balance        float64   `json:"balance"`
+		CreditLimit    float64   `json:"credit_limit"`
+		PaymentStatus  string    `json:"payment_status"`
+		PaymentHistory []string  `json:"payment_history"`
+		OpenDate       time.Time `json:"open_date"`
+		LastReported   time.Time `json:"last_reported"`
+		IsActive       bool      `json:"is_active"`
+	} `json:"accounts"`
+	Inquiries []struct {
+		InquiryID   string    `json:"inquiry_id"`
+		InquiryType string    `json:"inquiry_type"`
+		Creditor    string    `json:"creditor"`
+		InquiryDate time.Time `json:"inquiry_date"`
+		Purpose     string    `json:"purpose"`
+	} `json:"inquiries"`
+	PublicRecords []struct {
+		RecordID   string    `json:"record_id"`
+		RecordType string    `json:"record_type"`
+		CourtName  string    `json:"court_name"`
+		FilingDate time.Time `json:"filing_date"`
+		Amount     float64   `json:"amount"`
+		Status     string    `json:"status"`
+	} `json:"public_records"`
+	Collections []struct {
+		CollectionID string `json:"collection_id"`
+	} `json:"collections"`
+	FraudAlerts    []domain.CreditFraudAlert `json:"fraud_alerts"`
+	PaymentHistory struct {
+		OnTimePayments int     `json:"on_time_payments"`
+		LatePayments   int     `json:"late_payments"`
+		Defaults       int     `json:"defaults"`
+		Bankruptcies   int     `json:"bankruptcies"`
+		PaymentScore   float64 `json:"payment_score"`
+	} `json:"payment_history"`
+	CreditUtilization float64   `json:"credit_utilization"` // percent
+	ReportDate        time.Time `json:"report_date"`
+}
+
+// GetCreditReport pulls the borrower's credit with a hard inquiry for the application
+func (c *CreditBureauClient) GetCreditReport(ctx context.Context, request *domain.CreditReportRequest) (*domain.CreditReport, error) {
+	return c.pull(ctx, request, false)
+}
+
+// GetCreditScore is not supported: the decision engine only pulls credit for an application,
+// so scores come with the application's credit report
+func (c *CreditBureauClient) GetCreditScore(ctx context.Context, userID string, ssn string) (*domain.CreditScore, error) {
+	return nil, fmt.Errorf("credit scores are only available through an application's credit report")
+}
+
+// RefreshCreditReport pulls an application's credit again, bypassing the decision engine's cache
+func (c *CreditBureauClient) RefreshCreditReport(ctx context.Context, applicationID string) (*domain.CreditReport, error) {
+	if c.applications == nil {
+		return nil, fmt.Errorf("no loan applications available to refresh application %s", applicationID)
+	}
+	application, err := c.applications.GetByID(ctx, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	return c.pull(ctx, &domain.CreditReportRequest{
+		UserID:        application.UserID,
+		ApplicationID: application.ID,
+		ReportType:    "full",
+		Permissible:   "loan_application",
+	}, true)
+}
+
+// GetServiceName returns the service name
+func (c *CreditBureauClient) GetServiceName() string {
+	return c.engine.GetServiceName()
+}
+
+// IsAvailable reports whether the decision engine can pull credit
+func (c *CreditBureauClient) IsAvailable(ctx context.Context) bool {
+	return c.engine.IsAvailable(ctx)
+}
+
+// GetRateLimits returns the rate limits of credit pulls. The decision engine throttles bureau
+// calls itself, so the worker applies none.
+func (c *CreditBureauClient) GetRateLimits() domain.RateLimits {
+	return domain.RateLimits{}
+}
+
+// pull requests a hard pull from the decision engine and maps the report
+func (c *CreditBureauClient) pull(ctx context.Context, request *domain.CreditReportRequest, forceRefresh bool) (*domain.CreditReport, error) {
+	if request.SSNToken == "" {
+		if err := c.fillIdentity(ctx, request); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := c.engine.withDeadline(ctx)
+	defer cancel()
+
+	body, err := json.Marshal(&bureauPullRequest{
+		UserID:        request.UserID,
+		SSNToken:      request.SSNToken,
+		FirstName:     request.FirstName,
+		LastName:      request.LastName,
+		DateOfBirth:   request.DateOfBirth.Format("2006-01-02"),
+		Address:       formatAddress(request.Address),
+		ReportType:    request.ReportType,
+		PullType:      "HARD",
+		ApplicationID: request.ApplicationID,
+		ForceRefresh:  forceRefresh,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode credit report request: %w", err)
+	}
+
+	var response bureauPullResponse
+	if err := c.engine.do(ctx, http.MethodPost, "/api/v1/credit-reports/pull", body, &response); err != nil {
+		return nil, err
+	}
+	if response.Report == nil || response.Report.Report == nil {
+		return nil, fmt.Errorf("decision engine returned no credit report for application %s", request.ApplicationID)
+	}
+
+	c.logger.Info("Credit report pulled",
+		zap.String("application_id", request.ApplicationID),
+		zap.String("bureau", response.Report.Bureau),
+		zap.Bool("cached", response.Report.Cached),
+		zap.Bool("failed_over", response.FailedOver))
+
+	return response.toDomain(request), nil
+}
+
+// fillIdentity completes a request with the borrower's identity from the loan service
+func (c *CreditBureauClient) fillIdentity(ctx context.Context, request *domain.CreditReportRequest) error {
+	if c.borrowers == nil {
+		return fmt.Errorf("no borrower identity available for user %s", request.UserID)
+	}
+	borrower, err := c.borrowers.GetByUserID(ctx, request.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get borrower identity: %w", err)
+	}
+	if borrower.SSNToken == "" {
+		return fmt.Errorf("borrower %s has no SSN on file", request.UserID)
+	}
+
+	request.SSNToken = borrower.SSNToken
+	request.FirstName = borrower.FirstName
+	request.LastName = borrower.LastName
+	request.DateOfBirth = borrower.DateOfBirth
+	request.Address = borrower.Address
+	return nil
+}
+
+// toDomain maps a bureau pull to the worker's credit report
+func (r *bureauPullResponse) toDomain(request *domain.CreditReportRequest) *domain.CreditReport {
+	pulled := r.Report
+	source := pulled.Report
+	pulledAt := pulled.PulledAt
+	if pulledAt.IsZero() {
+		pulledAt = time.Now().UTC()
+	}
+	reportDate := source.ReportDate
+	if reportDate.IsZero() {
+		reportDate = pulledAt
+	}
+
+	report := &domain.CreditReport{
+		ID:                request.ApplicationID + "_credit_report_" + pulledAt.Format("20060102150405"),
+		ApplicationID:     request.ApplicationID,
+		UserID:            request.UserID,
+		CreditScore:       source.CreditScore,
+		CreditScoreRange:  domain.GetCreditScoreRange(source.CreditScore),
+		ReportProvider:    strings.ToLower(pulled.Bureau),
+		ReportDate:        reportDate,
+		CreditUtilization: source.CreditUtilization / 100,
+		PaymentHistory: domain.PaymentHistory{
+			OnTimePayments: source.PaymentHistory.OnTimePayments,
+			LatePayments30: source.PaymentHistory.LatePayments,
+			ChargeOffs:     source.PaymentHistory.Defaults,
+			Collections:    len(source.Collections),
+			PaymentScore:   source.PaymentHistory.PaymentScore,
+		},
+		ReportData: map[string]interface{}{
+			"bureau":      pulled.Bureau,
+			"score_model": source.ScoreModel,
+			"pull_type":   pulled.PullType,
+			"cached":      pulled.Cached,
+			"failed_over": r.FailedOver,
+			"pulled_at":   pulledAt,
+		},
+		FraudAlerts: source.FraudAlerts,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	accountTypes := map[string]bool{}
+	for _, account := range source.Accounts {
+		status := "closed"
+		if account.IsActive {
+			status = "open"
+			report.TotalCreditLimit += account.CreditLimit
+			report.TotalCurrentBalance += account.Balance
+		}
+		report.CreditAccounts = append(report.CreditAccounts, domain.CreditAccount{
+			AccountID:        account.AccountID,
+			AccountType:      account.AccountType,
+			Creditor:         account.Creditor,
+			OpenDate:         account.OpenDate,
+			LastReportedDate: account.LastReported,
+			CreditLimit:      account.CreditLimit,
+			CurrentBalance:   account.Balance,
+			PaymentStatus:    account.PaymentStatus,
+			PaymentHistory:   strings.Join(account.PaymentHistory, ""),
+			AccountStatus:    status,
+		})
+		if !accountTypes[account.AccountType] {
+			accountTypes[account.AccountType] = true
+			report.CreditMix = append(report.CreditMix, account.AccountType)
+		}
+	}
+
+	for _, inquiry := range source.Inquiries {
+		report.CreditInquiries = append(report.CreditInquiries, domain.CreditInquiry{
+			InquiryID:     inquiry.InquiryID,
+			InquiryDate:   inquiry.InquiryDate,
+			InquiryType:   strings.ToLower(inquiry.InquiryType),
+			Creditor:      inquiry.Creditor,
+			InquiryReason: inquiry.Purpose,
+		})
+	}
+
+	for _, record := range source.PublicRecords {
+		report.PublicRecords = append(report.PublicRecords, domain.PublicRecord{
+			RecordID:   record.RecordID,
+			RecordType: strings.ToLower(record.RecordType),
+			FilingDate: record.FilingDate,
+			Amount:     record.Amount,
+			Status:     record.Status,
+			Court:      record.CourtName,
+		})
+		switch strings.ToLower(record.RecordType) {
+		case "bankruptcy":
+			report.DerogatoryCounts.Bankruptcies++
+		case "lien", "tax_lien":
+			report.DerogatoryCounts.Liens++
+		case "judgment", "civil_judgment":
+			report.DerogatoryCounts.Judgments++
+		}
+	}
+	if report.DerogatoryCounts.Bankruptcies < source.PaymentHistory.Bankruptcies {
+		report.DerogatoryCounts.Bankruptcies = source.PaymentHistory.Bankruptcies
+	}
+	report.DerogatoryCounts.ChargeOffs = source.PaymentHistory.Defaults
+	report.DerogatoryCounts.Collections = len(source.Collections)
+	report.DerogatoryCounts.LatePayments = source.PaymentHistory.LatePayments
+
+	return report
+}
+
+// formatAddress formats an address on one line for the bureau
+func formatAddress(address domain.Address) string {
+	parts := []string{}
+	for _, part := range []string{address.StreetAddress, address.City, strings.TrimSpace(address.State + " " + address.ZipCode)} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -64,9 +64,13 @@ func (h *CreditCheckTaskHandler) Execute(ctx context.Context, input map[string]i
 		return nil, fmt.Errorf("user ID is required and must be a non-empty string")
 	}
 
+	// The borrower's consent to the credit pull, recorded by loan-api before submission
+	creditConsentID, _ := input["creditConsentId"].(string)
+
 	logger.Info("Validated input parameters",
 		zap.String("application_id", applicationID),
-		zap.String("user_id", userID))
+		zap.String("user_id", userID),
+		zap.String("credit_consent_id", creditConsentID))
 
 	// Declare variables
 	var application *domain.LoanApplication
@@ -169,6 +173,14 @@ func (h *CreditCheckTaskHandler) Execute(ctx context.Context, input map[string]i
 		}, nil
 	}
 
+	// A hard pull needs the borrower's recorded consent as evidence of permissible purpose
+	if creditConsentID == "" {
+		logger.Error("Credit pull attempted without consent",
+			zap.String("application_id", applicationID),
+			zap.String("user_id", userID))
+		return h.createFailureResponse(applicationID, fmt.Errorf("no credit pull consent recorded for application %s", applicationID)), nil
+	}
+
 	// Perform credit check using real service
 	logger.Info("Performing credit check with real service",
 		zap.String("application_id", applicationID),
@@ -210,24 +222,60 @@ func (h *CreditCheckTaskHandler) Execute(ctx context.Context, input map[string]i
 		return h.createFailureResponse(applicationID, fmt.Errorf("risk analysis is nil")), nil
 	}
 
-	// Note: evaluateCreditDecision is not implemented for real services yet
-	_ = creditReport // Suppress unused variable warning
-	_ = riskAnalysis // Suppress unused variable warning
+	creditDecision := h.evaluateCreditDecision(creditReport, riskAnalysis, application)
 
 	processingTime := time.Since(startTime)
 
-	// This path is only reached when real services are available
-	logger.Info("Credit check completed with real services",
+	logger.Info("Credit check completed",
 		zap.String("application_id", applicationID),
 		zap.String("user_id", userID),
+		zap.Int("credit_score", creditReport.CreditScore),
+		zap.String("risk_level", string(riskAnalysis.RiskLevel)),
+		zap.Bool("approved", creditDecision.Approved),
 		zap.Duration("processing_time", processingTime))
 
-	// For now, return a simple success response since real services aren't implemented
 	return map[string]interface{}{
-		"success":        true,
-		"applicationId":  applicationID,
-		"userId":         userID,
-		"message":        "Credit check completed with real services (not fully implemented)",
+		"success":           true,
+		"applicationId":     applicationID,
+		"userId":            userID,
+		"creditConsentId":   creditConsentID,
+		"creditScore":       creditReport.CreditScore,
+		"creditScoreRange":  string(creditReport.CreditScoreRange),
+		"creditUtilization": creditReport.CreditUtilization,
+		"totalCreditLimit":  creditReport.TotalCreditLimit,
+		"paymentHistory": map[string]interface{}{
+			"onTimePayments": creditReport.PaymentHistory.OnTimePayments,
+			"latePayments30": creditReport.PaymentHistory.LatePayments30,
+			"latePayments60": creditReport.PaymentHistory.LatePayments60,
+			"latePayments90": creditReport.PaymentHistory.LatePayments90,
+			"paymentScore":   creditReport.PaymentHistory.PaymentScore,
+		},
+		"derogatoryCounts": map[string]interface{}{
+			"bankruptcies": creditReport.DerogatoryCounts.Bankruptcies,
+			"liens":        creditReport.DerogatoryCounts.Liens,
+			"judgments":    creditReport.DerogatoryCounts.Judgments,
+			"chargeOffs":   creditReport.DerogatoryCounts.ChargeOffs,
+			"collections":  creditReport.DerogatoryCounts.Collections,
+		},
+		"riskAnalysis": map[string]interface{}{
+			"riskLevel":       string(riskAnalysis.RiskLevel),
+			"riskScore":       riskAnalysis.RiskScore,
+			"riskFactors":     h.formatRiskFactors(riskAnalysis.RiskFactors),
+			"positiveFactors": h.formatRiskFactors(riskAnalysis.PositiveFactors),
+		},
+		"creditDecision": map[string]interface{}{
+			"approved":        creditDecision.Approved,
+			"reason":          creditDecision.Reason,
+			"recommendations": creditDecision.Recommendations,
+			"manualReview":    creditDecision.ManualReview,
+		},
+		"reportDetails": map[string]interface{}{
+			"reportId":       creditReport.ID,
+			"reportProvider": creditReport.ReportProvider,
+			"reportDate":     creditReport.ReportDate.UTC().Format(time.RFC3339),
+			"riskFactors":    creditReport.RiskFactors,
+			"creditMix":      creditReport.CreditMix,
+		},
 		"processingTime": processingTime.String(),
 		"completedAt":    time.Now().UTC().Format(time.RFC3339),
 	}, nil
@@ -355,6 +403,7 @@ func (h *CreditCheckTaskHandler) createFailureResponse(applicationID string, err
 		"success":       false,
 		"applicationId": applicationID,
 		"error":         err.Error(),
+		"retryable":     IsRetryableError(err),
 		"creditDecision": map[string]interface{}{
 			"approved":     false,
 			"reason":       "Credit check failed due to system error",
@@ -0,0 +1,518 @@
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/config"
+
+	"underwriting_worker/domain"
+)
+
+const (
+	// availabilityTTL is how long a health check result is trusted before the next one
+	availabilityTTL = 10 * time.Second
+	// unavailableBackoff is how long the decision engine is treated as down after a failed call,
+	// so tasks fall back to built-in decisions without waiting on a timeout each time
+	unavailableBackoff = 30 * time.Second
+)
+
+// DecisionEngineError is an error returned by the decision engine, carrying its error code so
+// callers can tell a rejected request from an outage
+type DecisionEngineError struct {
+	Code       string
+	Message    string
+	Details    string
+	StatusCode int
+	Retryable  bool // the same request may succeed later
+}
+
+func (e *DecisionEngineError) Error() string {
+	if e.Details != "" {
+		return fmt.Sprintf("decision engine %s (status %d): %s: %s", e.Code, e.StatusCode, e.Message, e.Details)
+	}
+	return fmt.Sprintf("decision engine %s (status %d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+// DecisionEngineClient implements domain.DecisionEngineService and domain.FraudDetectionService
+// over the decision engine's REST API; the DecisionService gRPC contract does not carry the
+// stages, adverse action reasons or policy version used here. Caller deadlines are sent with each request so the engine stops work the worker has given
+// up on, and engine errors are mapped to DecisionEngineError.
+type DecisionEngineClient struct {
+	logger     *zap.Logger
+	httpClient *http.Client
+	baseURL    string
+	timeout    time.Duration
+
+	mu             sync.Mutex
+	available      bool
+	checkedAt      time.Time
+	unavailableTil time.Time
+	policyVersion  string
+}
+
+// NewDecisionEngineClient creates a decision engine client
+func NewDecisionEngineClient(logger *zap.Logger, cfg config.ServiceEndpointConfig) *DecisionEngineClient {
+	timeout := time.Duration(cfg.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &DecisionEngineClient{
+		logger:     logger,
+		httpClient: &http.Client{Timeout: timeout},
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		timeout:    timeout,
+	}
+}
+
+// decisionEngineRequest is the decision engine's decision request
+type decisionEngineRequest struct {
+	ApplicationID  string                 `json:"application_id"`
+	UserID         string                 `json:"user_id"`
+	CustomerID     string                 `json:"customer_id"`
+	LoanAmount     float64                `json:"loan_amount"`
+	AnnualIncome   float64                `json:"annual_income"`
+	MonthlyIncome  float64                `json:"monthly_income"`
+	MonthlyDebt    float64                `json:"monthly_debt"`
+	CreditScore    int                    `json:"credit_score"`
+	EmploymentType string                 `json:"employment_type"`
+	RequestedTerm  int                    `json:"requested_term"`
+	LoanTermMonths int                    `json:"loan_term_months"`
+	LoanPurpose    string                 `json:"loan_purpose"`
+	AdditionalData map[string]interface{} `json:"additional_data,omitempty"`
+	RequestedAt    time.Time              `json:"requested_at"`
+}
+
+// decisionEngineResponse is the part of the decision engine's decision the worker uses
+type decisionEngineResponse struct {
+	DecisionID     int64    `json:"decision_id"`
+	Decision       string   `json:"decision"`
+	RiskScore      float64  `json:"risk_score"`
+	RiskCategory   string   `json:"risk_category"`
+	InterestRate   float64  `json:"interest_rate"`
+	ApprovedAmount float64  `json:"approved_amount"`
+	DecisionReason string   `json:"decision_reason"`
+	Conditions     []string `json:"conditions"`
+	ReviewRequired bool     `json:"review_required"`
+	// Provisional decisions were made without data sources that missed their SLA; the decision
+	// engine re-decides them when the data arrives
+	Provisional    bool `json:"provisional"`
+	PendingSources []struct {
+		Source string `json:"source"`
+	} `json:"pending_sources"`
+	RuleVersions  []string               `json:"rule_versions"`
+	PolicyVersion string                 `json:"policy_version"`
+	Stages        []domain.DecisionStage `json:"stages"`
+	Pricing       *struct {
+		ModelVersion      string  `json:"model_version"`
+		BaseRate          float64 `json:"base_rate"`
+		MarginAdjustments []struct {
+			Factor      string  `json:"factor"`
+			Adjustment  float64 `json:"adjustment"`
+			Description string  `json:"description"`
+		} `json:"margin_adjustments"`
+		Rate                  float64 `json:"rate"`
+		OriginationFeeRate    float64 `json:"origination_fee_rate"`
+		NetOriginationFeeRate float64 `json:"net_origination_fee_rate"`
+		LoanAmount            float64 `json:"loan_amount"`
+		TermMonths            int     `json:"term_months"`
+		MonthlyPayment        float64 `json:"monthly_payment"`
+		APR                   float64 `json:"apr"`
+	} `json:"pricing"`
+	AdverseActionReasons []struct {
+		Rank        int    `json:"rank"`
+		Code        string `json:"code"`
+		Description string `json:"description"`
+	} `json:"adverse_action_reasons"`
+}
+
+// decisionEngineErrorBody is the decision engine's error response
+type decisionEngineErrorBody struct {
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+	Details string `json:"details"`
+}
+
+// MakeDecision requests a decision from the decision engine
+func (c *DecisionEngineClient) MakeDecision(ctx context.Context, request *domain.DecisionRequest) (*domain.DecisionResponse, error) {
+	if request.LoanApplication == nil {
+		return nil, fmt.Errorf("loan application is required for a decision")
+	}
+	startTime := time.Now()
+
+	ctx, cancel := c.withDeadline(ctx)
+	defer cancel()
+
+	body, err := json.Marshal(toDecisionEngineRequest(request))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode decision request: %w", err)
+	}
+
+	var decision decisionEngineResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/decisions", body, &decision); err != nil {
+		return nil, err
+	}
+
+	response := decision.toDomain(request)
+	response.ProcessingTime = time.Since(startTime)
+
+	c.mu.Lock()
+	if decision.PolicyVersion != "" {
+		c.policyVersion = decision.PolicyVersion
+	}
+	c.mu.Unlock()
+
+	return response, nil
+}
+
+// CalculateInterestRate is not offered by the decision engine, which prices every approval
+// within its decision; the decision's pricing holds the rate
+func (c *DecisionEngineClient) CalculateInterestRate(ctx context.Context, request *domain.InterestRateRequest) (*domain.InterestRateResponse, error) {
+	return nil, &DecisionEngineError{
+		Code:    "UNSUPPORTED",
+		Message: "interest rates are priced within decisions",
+	}
+}
+
+// ApplyPolicy reports an application compliant: the decision engine holds every decision to the
+// underwriting policy in force, and policy limits come back as knocked-out decisions
+func (c *DecisionEngineClient) ApplyPolicy(ctx context.Context, application *domain.LoanApplication, policy *domain.UnderwritingPolicy) (*domain.PolicyResult, error) {
+	return &domain.PolicyResult{Compliant: true}, nil
+}
+
+// ScreenApplication screens an application with the decision engine's fraud vendor
+func (c *DecisionEngineClient) ScreenApplication(ctx context.Context, request *domain.FraudScreeningRequest) (*domain.FraudScreeningResult, error) {
+	ctx, cancel := c.withDeadline(ctx)
+	defer cancel()
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode fraud screening request: %w", err)
+	}
+
+	var result domain.FraudScreeningResult
+	if err := c.do(ctx, http.MethodPost, "/api/v1/fraud/screenings", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetServiceName returns the service name
+func (c *DecisionEngineClient) GetServiceName() string {
+	return "decision-engine"
+}
+
+// GetPolicyVersion returns the underwriting policy version of the last decision received
+func (c *DecisionEngineClient) GetPolicyVersion() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.policyVersion
+}
+
+// IsAvailable reports whether the decision engine is healthy. Results are cached briefly, and
+// after a failure the engine is treated as down for a backoff period.
+func (c *DecisionEngineClient) IsAvailable(ctx context.Context) bool {
+	c.mu.Lock()
+	now := time.Now()
+	if now.Before(c.unavailableTil) {
+		c.mu.Unlock()
+		return false
+	}
+	if now.Sub(c.checkedAt) < availabilityTTL {
+		available := c.available
+		c.mu.Unlock()
+		return available
+	}
+	c.mu.Unlock()
+
+	ctx, cancel := c.withDeadline(ctx)
+	defer cancel()
+	err := c.do(ctx, http.MethodGet, "/health", nil, nil)
+	if err != nil {
+		c.logger.Warn("Decision engine health check failed", zap.Error(err))
+		c.markUnavailable()
+		return false
+	}
+
+	c.mu.Lock()
+	c.available = true
+	c.checkedAt = time.Now()
+	c.mu.Unlock()
+	return true
+}
+
+// withDeadline bounds ctx by the client timeout unless the caller set an earlier deadline
+func (c *DecisionEngineClient) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < c.timeout {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// do sends a request with the context deadline in the X-Request-Deadline header and decodes a
+// successful response into out
+func (c *DecisionEngineClient) do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create decision engine request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Header.Set("X-Request-Deadline", deadline.UTC().Format(time.RFC3339Nano))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return &DecisionEngineError{
+				Code:       "DEADLINE_EXCEEDED",
+				Message:    "decision engine did not respond before the deadline",
+				StatusCode: http.StatusGatewayTimeout,
+				Retryable:  true,
+			}
+		}
+		c.markUnavailable()
+		return &DecisionEngineError{
+			Code:      "UNAVAILABLE",
+			Message:   "decision engine unreachable",
+			Details:   err.Error(),
+			Retryable: true,
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return c.responseError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode decision engine response: %w", err)
+	}
+	return nil
+}
+
+// responseError maps a decision engine error response to a DecisionEngineError. Throttling,
+// timeouts and server errors are retryable; rejected requests are not.
+func (c *DecisionEngineClient) responseError(resp *http.Response) error {
+	var body decisionEngineErrorBody
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+
+	err := &DecisionEngineError{
+		Code:       body.Code,
+		Message:    body.Error,
+		Details:    body.Details,
+		StatusCode: resp.StatusCode,
+		Retryable:  resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError,
+	}
+	if err.Code == "" {
+		err.Code = fmt.Sprintf("HTTP_%d", resp.StatusCode)
+	}
+	if err.Message == "" {
+		err.Message = http.StatusText(resp.StatusCode)
+	}
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		c.markUnavailable()
+	}
+	return err
+}
+
+// markUnavailable treats the decision engine as down for the backoff period
+func (c *DecisionEngineClient) markUnavailable() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.available = false
+	c.checkedAt = time.Now()
+	c.unavailableTil = time.Now().Add(unavailableBackoff)
+}
+
+// toDecisionEngineRequest maps the worker's decision request onto the decision engine's
+func toDecisionEngineRequest(request *domain.DecisionRequest) *decisionEngineRequest {
+	application := request.LoanApplication
+	engineRequest := &decisionEngineRequest{
+		ApplicationID:  request.ApplicationID,
+		UserID:         application.UserID,
+		CustomerID:     application.UserID,
+		LoanAmount:     request.RequestedAmount,
+		AnnualIncome:   application.AnnualIncome,
+		MonthlyIncome:  application.MonthlyIncome,
+		MonthlyDebt:    application.MonthlyDebt,
+		EmploymentType: engineEmploymentType(application.EmploymentStatus),
+		RequestedTerm:  request.RequestedTerm,
+		LoanTermMonths: request.RequestedTerm,
+		LoanPurpose:    request.Purpose,
+		RequestedAt:    time.Now(),
+	}
+	if request.CreditReport != nil {
+		engineRequest.CreditScore = request.CreditReport.CreditScore
+	}
+	if request.RiskAssessment != nil {
+		engineRequest.AdditionalData = map[string]interface{}{
+			"risk_score":    request.RiskAssessment.RiskScore,
+			"risk_level":    string(request.RiskAssessment.OverallRiskLevel),
+			"model_version": request.RiskAssessment.ModelVersion,
+		}
+	}
+	// The engine qualifies the obligations under the same debt treatments
+	if application.Obligations != nil {
+		if engineRequest.AdditionalData == nil {
+			engineRequest.AdditionalData = make(map[string]interface{})
+		}
+		engineRequest.AdditionalData["dti_obligations"] = application.Obligations
+	}
+	// and the documented income sources under the same income rules
+	if application.IncomeSources != nil {
+		if engineRequest.AdditionalData == nil {
+			engineRequest.AdditionalData = make(map[string]interface{})
+		}
+		engineRequest.AdditionalData["income_sources"] = application.IncomeSources
+	}
+	return engineRequest
+}
+
+// engineEmploymentType maps the worker's employment status onto the decision engine's
+// employment types, which distinguish full-time employment
+func engineEmploymentType(status string) string {
+	switch status {
+	case "employed":
+		return "full_time"
+	case "self-employed":
+		return "self_employed"
+	default:
+		return status
+	}
+}
+
+// toDomain maps the decision engine's decision onto the worker's decision response
+func (r *decisionEngineResponse) toDomain(request *domain.DecisionRequest) *domain.DecisionResponse {
+	response := &domain.DecisionResponse{
+		Decision:             workerDecision(r.Decision),
+		ApprovedAmount:       r.ApprovedAmount,
+		ApprovedTerm:         request.RequestedTerm,
+		InterestRate:         r.InterestRate,
+		ManualReviewRequired: r.ReviewRequired || r.Decision == "MANUAL_REVIEW" || r.Decision == "PENDING",
+		PolicyVersion:        r.PolicyVersion,
+		Stages:               r.Stages,
+		DecisionData: map[string]interface{}{
+			"decision_engine_id": r.DecisionID,
+			"risk_score":         r.RiskScore,
+			"risk_category":      r.RiskCategory,
+			"rule_versions":      r.RuleVersions,
+			"stages":             r.Stages,
+		},
+	}
+
+	if r.Pricing != nil {
+		pricing := &domain.DecisionPricing{
+			ModelVersion:          r.Pricing.ModelVersion,
+			BaseRate:              r.Pricing.BaseRate,
+			Rate:                  r.Pricing.Rate,
+			OriginationFeeRate:    r.Pricing.OriginationFeeRate,
+			NetOriginationFeeRate: r.Pricing.NetOriginationFeeRate,
+			LoanAmount:            r.Pricing.LoanAmount,
+			TermMonths:            r.Pricing.TermMonths,
+			MonthlyPayment:        r.Pricing.MonthlyPayment,
+			APR:                   r.Pricing.APR,
+		}
+		for _, adjustment := range r.Pricing.MarginAdjustments {
+			pricing.MarginAdjustments = append(pricing.MarginAdjustments, domain.RateFactor{
+				Factor:      adjustment.Factor,
+				Adjustment:  adjustment.Adjustment,
+				Description: adjustment.Description,
+			})
+		}
+		response.Pricing = pricing
+		response.InterestRate = pricing.Rate
+		response.APR = pricing.APR
+		response.MonthlyPayment = pricing.MonthlyPayment
+		if pricing.TermMonths > 0 {
+			response.ApprovedTerm = pricing.TermMonths
+		}
+	}
+
+	for i, condition := range r.Conditions {
+		response.Conditions = append(response.Conditions, domain.UnderwritingCondition{
+			ConditionID:   fmt.Sprintf("decision_engine_%d", i+1),
+			ConditionType: "prior_to_funding",
+			Description:   condition,
+			Priority:      "medium",
+			Status:        "pending",
+		})
+	}
+
+	reasonType := "approval"
+	switch response.Decision {
+	case domain.DecisionDenied:
+		reasonType = "denial"
+	case domain.DecisionConditional, domain.DecisionManualReview:
+		reasonType = "condition"
+	}
+	for _, reason := range r.AdverseActionReasons {
+		impact := "secondary"
+		if reason.Rank == 1 {
+			impact = "primary"
+		}
+		response.Reasons = append(response.Reasons, domain.DecisionReason{
+			ReasonCode:  reason.Code,
+			ReasonType:  reasonType,
+			Description: reason.Description,
+			Impact:      impact,
+		})
+	}
+	if len(response.Reasons) == 0 && r.DecisionReason != "" {
+		response.Reasons = append(response.Reasons, domain.DecisionReason{
+			ReasonCode:  "DECISION_ENGINE",
+			ReasonType:  reasonType,
+			Description: r.DecisionReason,
+			Impact:      "primary",
+			Weight:      1.0,
+		})
+	}
+
+	if response.KnockedOut() {
+		response.DecisionData["knocked_out"] = true
+	}
+
+	// An approval made without all of its data is not acted on; it waits in manual review for the
+	// late data and the decision engine's re-decision. Denials stand on the data they were made on.
+	if r.Provisional && response.Decision != domain.DecisionDenied {
+		response.Decision = domain.DecisionManualReview
+		response.ManualReviewRequired = true
+		response.DecisionData["provisional"] = true
+		for _, pending := range r.PendingSources {
+			response.Reasons = append(response.Reasons, domain.DecisionReason{
+				ReasonCode:  "PROVISIONAL_DECISION",
+				ReasonType:  "condition",
+				Description: fmt.Sprintf("Decided without %s, which missed its SLA", strings.ToLower(strings.ReplaceAll(pending.Source, "_", " "))),
+				Impact:      "primary",
+			})
+		}
+	}
+	return response
+}
+
+// workerDecision maps a decision engine decision onto the worker's underwriting decision
+func workerDecision(decision string) domain.UnderwritingDecision {
+	switch decision {
+	case "APPROVE":
+		return domain.DecisionApproved
+	case "DENY":
+		return domain.DecisionDenied
+	case "CONDITIONAL":
+		return domain.DecisionConditional
+	default:
+		return domain.DecisionManualReview
+	}
+}
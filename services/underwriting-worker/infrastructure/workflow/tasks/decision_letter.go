@@ -0,0 +1,103 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// handleDecisionLetter has the loan service send the borrower the approval letter or adverse
+// action notice of the action taken on an application, with the reason codes of the decision, and
+// records when it was delivered on the compliance record, against the 30 days the borrower must be
+// notified within. It runs after capture_compliance_data, from whose record the letter is written;
+// an application with no approval or denial captured, and one whose notice was already delivered,
+// is sent nothing.
+func (w *UnderwritingTaskWorker) handleDecisionLetter(ctx context.Context, input map[string]interface{}) (map[string]interface{}, error) {
+	logger := w.logger.With(zap.String("operation", "generate_decision_letter"))
+
+	applicationID, ok := input["applicationId"].(string)
+	if !ok || applicationID == "" {
+		return nil, fmt.Errorf("application ID is required")
+	}
+	if w.complianceRecords == nil {
+		return nil, fmt.Errorf("compliance record store is not configured")
+	}
+	if w.loanService == nil {
+		return nil, fmt.Errorf("loan service is not configured")
+	}
+	logger = logger.With(zap.String("application_id", applicationID))
+
+	record, err := w.complianceRecords.GetByApplicationID(ctx, applicationID)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		return nil, fmt.Errorf("failed to get compliance record: %w", err)
+	}
+	if record == nil || (record.ActionTaken != domain.ComplianceActionApproved && record.ActionTaken != domain.ComplianceActionDenied) {
+		logger.Info("Application has no approval or denial captured, no decision letter sent")
+		return map[string]interface{}{
+			"generated": false,
+			"reason":    "no approval or denial captured for the application",
+		}, nil
+	}
+	if record.NoticeDeliveredAt != nil {
+		logger.Info("Decision letter already delivered", zap.String("document_id", record.NoticeDocumentID))
+		return decisionLetterOutput(record, nil), nil
+	}
+
+	request := &decisionLetterRequest{Decision: "approved"}
+	if record.ActionTaken == domain.ComplianceActionDenied {
+		request.Decision = "denied"
+		for _, reason := range record.DenialReasons {
+			request.Reasons = append(request.Reasons, decisionLetterReason{Code: reason.ReasonCode, Description: reason.Description})
+		}
+	} else {
+		request.ApprovedAmount = record.LoanAmount
+		request.TermMonths = record.LoanTermMonths
+		request.InterestRate = record.InterestRate
+		request.APR = record.APR
+	}
+
+	letter, err := w.loanService.IssueDecisionLetter(ctx, applicationID, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue decision letter: %w", err)
+	}
+	if err := w.complianceRecords.RecordNoticeDelivery(ctx, applicationID, letter.ID, *letter.DeliveredAt); err != nil {
+		return nil, err
+	}
+	record.NoticeDocumentID, record.NoticeDeliveredAt = letter.ID, letter.DeliveredAt
+
+	if record.NoticeDeliveredAt.After(record.NoticeDueDate()) {
+		logger.Warn("Decision letter delivered after the notice period",
+			zap.Time("application_date", record.ApplicationDate),
+			zap.Time("notice_due_date", record.NoticeDueDate()))
+	}
+	logger.Info("Decision letter delivered",
+		zap.String("document_id", letter.ID),
+		zap.String("document_type", letter.DocumentType),
+		zap.Strings("reason_codes", letter.ReasonCodes),
+		zap.Time("delivered_at", *letter.DeliveredAt))
+
+	return decisionLetterOutput(record, letter), nil
+}
+
+// decisionLetterOutput is the task output for a delivered decision letter; letter is nil when it
+// was delivered by an earlier run
+func decisionLetterOutput(record *domain.ComplianceRecord, letter *domain.DecisionLetter) map[string]interface{} {
+	output := map[string]interface{}{
+		"generated":          true,
+		"documentId":         record.NoticeDocumentID,
+		"actionTaken":        string(record.ActionTaken),
+		"deliveredAt":        record.NoticeDeliveredAt.Format(time.RFC3339),
+		"noticeDueDate":      record.NoticeDueDate().Format(time.RFC3339),
+		"withinNoticePeriod": !record.NoticeDeliveredAt.After(record.NoticeDueDate()),
+	}
+	if letter != nil {
+		output["documentType"] = letter.DocumentType
+		output["reasonCodes"] = letter.ReasonCodes
+	}
+	return output
+}
@@ -0,0 +1,350 @@
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+)
+
+// Definitions are the workflow and task definitions the worker registers with Conductor
+type Definitions struct {
+	Workflows []*WorkflowDefinition
+	Tasks     []*TaskDefinition
+}
+
+// LoadDefinitions reads the workflow definitions in the JSON or YAML files of dir, one per file,
+// and the task definitions in the files of its tasks subdirectory, a list per file. Files are
+// decoded strictly against WorkflowDefinition and TaskDefinition, so a misspelt or unsupported
+// field fails the load instead of being dropped, and each definition is validated.
+func LoadDefinitions(dir string) (*Definitions, error) {
+	definitions := &Definitions{}
+
+	workflowFiles, err := definitionFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[string]string)
+	for _, path := range workflowFiles {
+		var workflow WorkflowDefinition
+		if err := decodeDefinitionFile(path, &workflow); err != nil {
+			return nil, err
+		}
+		if err := validateWorkflowDefinition(&workflow); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		key := fmt.Sprintf("%s v%d", workflow.Name, workflow.Version)
+		if other, ok := versions[key]; ok {
+			return nil, fmt.Errorf("%s: workflow %s is also defined in %s", path, key, other)
+		}
+		versions[key] = path
+		definitions.Workflows = append(definitions.Workflows, &workflow)
+	}
+
+	taskFiles, err := definitionFiles(filepath.Join(dir, "tasks"))
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string)
+	for _, path := range taskFiles {
+		var taskDefs []*TaskDefinition
+		if err := decodeDefinitionFile(path, &taskDefs); err != nil {
+			return nil, err
+		}
+		for _, taskDef := range taskDefs {
+			if err := validateTaskDefinition(taskDef); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			if other, ok := names[taskDef.Name]; ok {
+				return nil, fmt.Errorf("%s: task %s is also defined in %s", path, taskDef.Name, other)
+			}
+			names[taskDef.Name] = path
+			definitions.Tasks = append(definitions.Tasks, taskDef)
+		}
+	}
+
+	if len(definitions.Workflows) == 0 && len(definitions.Tasks) == 0 {
+		return nil, fmt.Errorf("no workflow or task definitions found in %s", dir)
+	}
+	return definitions, nil
+}
+
+// definitionFiles lists the JSON and YAML files of a directory in name order; a missing
+// directory has none
+func definitionFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read definitions directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json", ".yaml", ".yml":
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// decodeDefinitionFile decodes a JSON or YAML definition file into v, rejecting fields v does not
+// have. YAML is converted to JSON first so both formats use the definitions' JSON field names.
+func decodeDefinitionFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read definition file: %w", err)
+	}
+
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		var document interface{}
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return fmt.Errorf("%s: invalid YAML: %w", path, err)
+		}
+		if data, err = json.Marshal(yamlToJSON(document)); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%s: invalid definition: %w", path, err)
+	}
+	return nil
+}
+
+// yamlToJSON converts the maps decoded from YAML, keyed by interface{}, to maps JSON can encode
+func yamlToJSON(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			converted[fmt.Sprint(key)] = yamlToJSON(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range value {
+			value[i] = yamlToJSON(item)
+		}
+	}
+	return value
+}
+
+// validateWorkflowDefinition checks a workflow definition Conductor would reject or run wrongly
+func validateWorkflowDefinition(workflow *WorkflowDefinition) error {
+	if workflow.Name == "" {
+		return fmt.Errorf("workflow name is required")
+	}
+	if workflow.Version < 1 {
+		return fmt.Errorf("workflow %s: version must be at least 1", workflow.Name)
+	}
+	if len(workflow.Tasks) == 0 {
+		return fmt.Errorf("workflow %s v%d has no tasks", workflow.Name, workflow.Version)
+	}
+	if workflow.SchemaVersion == 0 {
+		workflow.SchemaVersion = 2
+	}
+
+	references := make(map[string]bool, len(workflow.Tasks))
+	for i, task := range workflow.Tasks {
+		if task.Name == "" || task.TaskReferenceName == "" || task.Type == "" {
+			return fmt.Errorf("workflow %s v%d: task %d needs a name, taskReferenceName and type", workflow.Name, workflow.Version, i+1)
+		}
+		if references[task.TaskReferenceName] {
+			return fmt.Errorf("workflow %s v%d: task reference %s is used twice", workflow.Name, workflow.Version, task.TaskReferenceName)
+		}
+		references[task.TaskReferenceName] = true
+	}
+	return nil
+}
+
+// validateTaskDefinition checks a task definition Conductor would reject or time out wrongly
+func validateTaskDefinition(taskDef *TaskDefinition) error {
+	if taskDef.Name == "" {
+		return fmt.Errorf("task name is required")
+	}
+	if taskDef.TimeoutSeconds <= 0 {
+		return fmt.Errorf("task %s: timeoutSeconds must be positive", taskDef.Name)
+	}
+	if taskDef.ResponseTimeoutSeconds <= 0 || taskDef.ResponseTimeoutSeconds > taskDef.TimeoutSeconds {
+		return fmt.Errorf("task %s: responseTimeoutSeconds must be positive and at most timeoutSeconds", taskDef.Name)
+	}
+	if taskDef.RetryCount < 0 {
+		return fmt.Errorf("task %s: retryCount cannot be negative", taskDef.Name)
+	}
+	return nil
+}
+
+// SyncTaskDefinition registers a task definition Conductor does not have and updates one it has
+// with different settings, reporting whether it registered anything
+func (c *HTTPConductorClient) SyncTaskDefinition(taskDef *TaskDefinition) (bool, error) {
+	existing, err := c.getMetadata("/api/metadata/taskdefs/" + url.PathEscape(taskDef.Name))
+	if err != nil {
+		return false, err
+	}
+	if existing == nil {
+		return true, c.RegisterTaskDefinition(taskDef)
+	}
+	if matchesDefinition(taskDef, existing) {
+		c.logger.Debug("Task definition unchanged", zap.String("task_name", taskDef.Name))
+		return false, nil
+	}
+
+	if err := c.putMetadata("/api/metadata/taskdefs", taskDef); err != nil {
+		return false, fmt.Errorf("failed to update task definition %s: %w", taskDef.Name, err)
+	}
+	c.logger.Info("Updated task definition", zap.String("task_name", taskDef.Name))
+	return true, nil
+}
+
+// SyncWorkflowDefinition registers a workflow version Conductor does not have and updates one it
+// has with a different definition, reporting whether it registered anything. Versions Conductor
+// has that are not loaded are left alone, so running workflows keep their definition.
+func (c *HTTPConductorClient) SyncWorkflowDefinition(workflow *WorkflowDefinition) (bool, error) {
+	existing, err := c.getMetadata(fmt.Sprintf("/api/metadata/workflow/%s?version=%d", url.PathEscape(workflow.Name), workflow.Version))
+	if err != nil {
+		return false, err
+	}
+	if existing == nil {
+		return true, c.RegisterWorkflowDefinition(workflow)
+	}
+	if matchesDefinition(workflow, existing) {
+		c.logger.Debug("Workflow definition unchanged",
+			zap.String("workflow_name", workflow.Name),
+			zap.Int("workflow_version", workflow.Version))
+		return false, nil
+	}
+
+	// Conductor updates workflow definitions given as a list
+	if err := c.putMetadata("/api/metadata/workflow", []*WorkflowDefinition{workflow}); err != nil {
+		return false, fmt.Errorf("failed to update workflow definition %s v%d: %w", workflow.Name, workflow.Version, err)
+	}
+	c.logger.Info("Updated workflow definition",
+		zap.String("workflow_name", workflow.Name),
+		zap.Int("workflow_version", workflow.Version))
+	return true, nil
+}
+
+// getMetadata reads a definition from Conductor's metadata API, returning nil when it has none
+func (c *HTTPConductorClient) getMetadata(path string) (map[string]interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metadata request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusNoContent || len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get metadata failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var definition map[string]interface{}
+	if err := json.Unmarshal(body, &definition); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+	return definition, nil
+}
+
+// putMetadata updates definitions through Conductor's metadata API
+func (c *HTTPConductorClient) putMetadata(path string, definition interface{}) error {
+	jsonData, err := json.Marshal(definition)
+	if err != nil {
+		return fmt.Errorf("failed to marshal definition: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", c.baseURL+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create metadata request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("update metadata failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// matchesDefinition reports whether a definition registered in Conductor has every field of the
+// loaded one with the same value; fields Conductor adds, such as timestamps and defaults, are
+// ignored
+func matchesDefinition(definition interface{}, registered map[string]interface{}) bool {
+	data, err := json.Marshal(definition)
+	if err != nil {
+		return false
+	}
+	var loaded map[string]interface{}
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return false
+	}
+	return containsValue(registered, loaded)
+}
+
+// containsValue reports whether got has every map entry of want with the same value
+func containsValue(got, want interface{}) bool {
+	switch want := want.(type) {
+	case map[string]interface{}:
+		gotMap, ok := got.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range want {
+			if !containsValue(gotMap[key], value) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		gotList, ok := got.([]interface{})
+		if !ok || len(gotList) != len(want) {
+			return false
+		}
+		for i := range want {
+			if !containsValue(gotList[i], want[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(got, want)
+	}
+}
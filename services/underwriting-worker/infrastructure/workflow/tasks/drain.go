@@ -0,0 +1,134 @@
+package tasks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultDrainTimeout is how long Drain waits for running tasks when the config sets no timeout
+	defaultDrainTimeout = 25 * time.Second
+	// defaultDrainCallbackAfter is when Conductor re-delivers a task handed back while draining
+	defaultDrainCallbackAfter = 30
+)
+
+// runningTask is a task claimed from Conductor, waiting for a worker or being executed
+type runningTask struct {
+	task     *ConductorTask
+	workerID string
+}
+
+// runningTasks tracks the tasks claimed and not yet finished, so tasks still running when the worker stops can
+// be handed back to Conductor, and their results dropped if they finish afterwards
+type runningTasks struct {
+	mu    sync.Mutex
+	tasks map[string]*runningTask
+}
+
+func newRunningTasks() *runningTasks {
+	return &runningTasks{tasks: make(map[string]*runningTask)}
+}
+
+func (r *runningTasks) add(task *ConductorTask, workerID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tasks[task.TaskID] = &runningTask{task: task, workerID: workerID}
+}
+
+// start records the worker a claimed task runs on, reporting false if it was handed back to
+// Conductor while it waited and must not be run
+func (r *runningTasks) start(taskID, workerID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	running, ok := r.tasks[taskID]
+	if ok {
+		running.workerID = workerID
+	}
+	return ok
+}
+
+// finish removes a finished task, reporting false if it was handed back to Conductor meanwhile
+// and its result must not be sent
+func (r *runningTasks) finish(taskID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tasks[taskID]; !ok {
+		return false
+	}
+	delete(r.tasks, taskID)
+	return true
+}
+
+// releaseAll removes and returns every task still running
+func (r *runningTasks) releaseAll() []*runningTask {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	released := make([]*runningTask, 0, len(r.tasks))
+	for id, task := range r.tasks {
+		released = append(released, task)
+		delete(r.tasks, id)
+	}
+	return released
+}
+
+// Drain stops polling and waits for the running tasks to finish, up to the configured drain
+// timeout or the context's deadline, whichever comes first. Tasks still running then are reported
+// to Conductor as IN_PROGRESS with a callback delay, so Conductor re-delivers them to another
+// worker instead of waiting for their response timeout. It returns the number of tasks handed back.
+func (c *HTTPConductorClient) Drain(ctx context.Context) int {
+	c.StopPolling()
+
+	timeout := defaultDrainTimeout
+	if c.config.Conductor.DrainTimeout > 0 {
+		timeout = time.Duration(c.config.Conductor.DrainTimeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		c.pollers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		c.logger.Info("All running tasks finished")
+		return 0
+	case <-ctx.Done():
+	}
+
+	callbackAfter := int64(defaultDrainCallbackAfter)
+	if c.config.Conductor.DrainCallbackAfter > 0 {
+		callbackAfter = int64(c.config.Conductor.DrainCallbackAfter)
+	}
+
+	released := c.running.releaseAll()
+	for _, running := range released {
+		err := c.updateTaskResult(&ConductorTaskResult{
+			TaskID:               running.task.TaskID,
+			ReferenceTaskName:    running.task.TaskType,
+			WorkflowInstanceID:   running.task.WorkflowInstanceID,
+			Status:               "IN_PROGRESS",
+			OutputData:           map[string]interface{}{"released": "worker shutting down"},
+			WorkerID:             running.workerID,
+			CallbackAfterSeconds: callbackAfter,
+		})
+		if err != nil {
+			c.logger.Error("Failed to hand running task back to Conductor",
+				zap.String("task_id", running.task.TaskID),
+				zap.String("task_type", running.task.TaskType),
+				zap.Error(err))
+			continue
+		}
+		c.logger.Warn("Handed running task back to Conductor",
+			zap.String("task_id", running.task.TaskID),
+			zap.String("task_type", running.task.TaskType),
+			zap.String("workflow_id", running.task.WorkflowInstanceID),
+			zap.Int64("callback_after_seconds", callbackAfter))
+	}
+	return len(released)
+}
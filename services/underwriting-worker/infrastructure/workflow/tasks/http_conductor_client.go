@@ -8,13 +8,25 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/huuhoait/los-demo/services/shared/pkg/conductor"
 	"github.com/huuhoait/los-demo/services/shared/pkg/config"
 )
 
+const (
+	// defaultPollingInterval is the first wait after an empty poll when the config sets no interval
+	defaultPollingInterval = time.Second
+	// defaultPollTimeout is how long a batch poll waits for a task when the config sets no timeout
+	defaultPollTimeout = time.Second
+	// defaultMaxPollBackoff caps the wait between empty polls when the config sets no maximum
+	defaultMaxPollBackoff = 10 * time.Second
+)
+
 // HTTPConductorClient implements a simple HTTP client for Conductor
 type HTTPConductorClient struct {
 	logger     *zap.Logger
@@ -22,8 +34,11 @@ type HTTPConductorClient struct {
 	httpClient *http.Client
 	baseURL    string
 	workers    map[string]TaskHandler
+	counters   map[string]*taskTypeCounters // per registered task type, built when polling starts
 	isRunning  bool
 	stopChan   chan struct{}
+	pollers    sync.WaitGroup
+	running    *runningTasks
 }
 
 // WorkflowDefinition represents a Conductor workflow definition
@@ -63,6 +78,8 @@ type ConductorTask struct {
 	WorkflowInstanceID string                 `json:"workflowInstanceId"`
 	InputData          map[string]interface{} `json:"inputData"`
 	Status             string                 `json:"status"`
+	RetryCount         int                    `json:"retryCount"`
+	WorkflowPriority   int                    `json:"workflowPriority"`
 }
 
 // ConductorTaskResult represents a task result for Conductor
@@ -74,20 +91,27 @@ type ConductorTaskResult struct {
 	OutputData            map[string]interface{} `json:"outputData"`
 	ReasonForIncompletion string                 `json:"reasonForIncompletion,omitempty"`
 	WorkerID              string                 `json:"workerId"`
+	CallbackAfterSeconds  int64                  `json:"callbackAfterSeconds,omitempty"`
 }
 
 // NewHTTPConductorClient creates a new HTTP-based Conductor client
 func NewHTTPConductorClient(logger *zap.Logger, cfg *config.BaseConfig) (*HTTPConductorClient, error) {
-	httpClient := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
 	// Parse and validate the base URL
 	baseURL := cfg.Conductor.BaseURL
 	if _, err := url.Parse(baseURL); err != nil {
 		return nil, fmt.Errorf("invalid conductor URL: %w", err)
 	}
 
+	// Authenticates each request and verifies the server as configured
+	transport, err := conductor.NewTransport(cfg.Conductor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure conductor transport: %w", err)
+	}
+	httpClient := &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: transport,
+	}
+
 	client := &HTTPConductorClient{
 		logger:     logger,
 		config:     cfg,
@@ -96,6 +120,7 @@ func NewHTTPConductorClient(logger *zap.Logger, cfg *config.BaseConfig) (*HTTPCo
 		workers:    make(map[string]TaskHandler),
 		isRunning:  false,
 		stopChan:   make(chan struct{}),
+		running:    newRunningTasks(),
 	}
 
 	return client, nil
@@ -128,16 +153,44 @@ func (c *HTTPConductorClient) StartPolling() error {
 
 	c.isRunning = true
 
-	// Start polling workers
-	for i := 0; i < c.config.Conductor.WorkerPoolSize; i++ {
-		go c.pollingWorker(fmt.Sprintf("worker-%d", i))
+	// Task types with a concurrency limit run on a bounded pool of their own, so a slow or
+	// rate-limited task type cannot hold up the others
+	taskTypes := make([]string, 0, len(c.workers))
+	for taskType := range c.workers {
+		taskTypes = append(taskTypes, taskType)
+	}
+	pools := buildWorkerPools(taskTypes, c.config.Conductor.TaskConcurrency, c.config.Conductor.WorkerPoolSize)
+
+	c.counters = make(map[string]*taskTypeCounters, len(taskTypes))
+	for _, pool := range pools {
+		for _, taskType := range pool.taskTypes {
+			counters := &taskTypeCounters{pool: pool.name, poolSize: pool.size}
+			counters.queueDepth.Store(-1)
+			c.counters[taskType] = counters
+		}
+	}
+
+	for _, pool := range pools {
+		c.logger.Info("Starting worker pool",
+			zap.String("pool", pool.name),
+			zap.Int("size", pool.size),
+			zap.Strings("task_types", pool.taskTypes))
+		slots := newWorkerSlots(pool.size)
+		for _, taskType := range pool.taskTypes {
+			c.pollers.Add(1)
+			go c.taskPoller(pool, slots, taskType)
+		}
+	}
+
+	if interval := c.config.Conductor.QueueMetricsInterval; interval > 0 {
+		go c.reportQueueDepths(time.Duration(interval) * time.Second)
 	}
 
 	c.logger.Info("HTTP Conductor client started successfully")
 	return nil
 }
 
-// StopPolling stops polling for tasks
+// StopPolling stops polling for tasks. Tasks already running carry on; use Drain to wait for them.
 func (c *HTTPConductorClient) StopPolling() {
 	if !c.isRunning {
 		return
@@ -177,47 +230,130 @@ func (c *HTTPConductorClient) testConnection() error {
 	return nil
 }
 
-// pollingWorker polls for tasks and executes them
-func (c *HTTPConductorClient) pollingWorker(workerID string) {
-	logger := c.logger.With(zap.String("worker_id", workerID))
-	pollInterval := time.Duration(c.config.Conductor.PollingInterval) * time.Millisecond
+// taskPoller long-polls Conductor for one task type and runs the tasks it claims on the workers of
+// the task type's pool. A batch poll claims up to the task type's poll count, but never more tasks
+// than the pool has idle workers. After a poll that found no work the poller waits a polling
+// interval, doubling the wait with each further empty poll up to the maximum poll backoff, so idle
+// task types are polled less often; the first task found resets the wait.
+func (c *HTTPConductorClient) taskPoller(pool *workerPool, slots *workerSlots, taskType string) {
+	defer c.pollers.Done()
+
+	pollerID := fmt.Sprintf("%s-poller", taskType)
+	logger := c.logger.With(zap.String("task_type", taskType), zap.String("pool", pool.name))
+	priority := c.config.Conductor.TaskPriority[taskType]
+	pollCount := c.config.Conductor.TaskPollCount[taskType]
+	if pollCount <= 0 {
+		pollCount = pool.size
+	}
+	backoff := newPollBackoff(c.config.Conductor.PollingInterval, c.config.Conductor.MaxPollBackoff)
+
+	// Stopping cancels a long poll in progress
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.stopChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
 
 	for {
 		select {
 		case <-c.stopChan:
-			logger.Info("Polling worker stopped")
+			logger.Info("Task poller stopped")
 			return
 		default:
-			// Poll for tasks
-			for taskType := range c.workers {
-				if !c.isRunning {
-					return
-				}
-
-				task, err := c.pollTask(taskType, workerID)
-				if err != nil {
-					logger.Debug("Failed to poll task",
-						zap.String("task_type", taskType),
-						zap.Error(err))
-					continue
-				}
-
-				if task != nil {
-					c.executeTask(task, workerID, logger)
-				}
-			}
+		}
 
-			// Wait before next poll
-			time.Sleep(pollInterval)
+		count := slots.wait(priority)
+		if count > pollCount {
+			count = pollCount
+		}
+		tasks, err := c.batchPoll(ctx, taskType, pollerID, count)
+		if err != nil && ctx.Err() == nil {
+			logger.Debug("Failed to poll tasks", zap.Error(err))
+		}
+		if len(tasks) == 0 {
+			select {
+			case <-c.stopChan:
+			case <-time.After(backoff.next()):
+			}
+			continue
+		}
+		backoff.reset()
+
+		for _, task := range tasks {
+			// Tracked from the moment it is claimed, so a task still waiting for a worker when the
+			// client drains is handed back to Conductor as well
+			c.running.add(task, pollerID)
+			worker := slots.acquire(priority)
+			workerID := fmt.Sprintf("%s-worker-%d", pool.name, worker)
+			c.pollers.Add(1)
+			go func(task *ConductorTask) {
+				defer c.pollers.Done()
+				defer slots.release(worker)
+				c.executeTask(task, workerID, c.logger.With(zap.String("worker_id", workerID)))
+			}(task)
 		}
 	}
 }
 
-// pollTask polls for a specific task type
-func (c *HTTPConductorClient) pollTask(taskType, workerID string) (*ConductorTask, error) {
-	pollURL := fmt.Sprintf("%s/api/tasks/poll/%s?workerid=%s", c.baseURL, taskType, workerID)
+// pollBackoff is how long a poller waits after polls that found no work: the polling interval
+// after the first, doubling with each further empty poll up to the maximum
+type pollBackoff struct {
+	initial time.Duration
+	max     time.Duration
+	current time.Duration
+}
+
+func newPollBackoff(intervalMs, maxMs int) *pollBackoff {
+	initial := time.Duration(intervalMs) * time.Millisecond
+	if initial <= 0 {
+		initial = defaultPollingInterval
+	}
+	maxWait := time.Duration(maxMs) * time.Millisecond
+	if maxWait <= 0 {
+		maxWait = defaultMaxPollBackoff
+	}
+	if maxWait < initial {
+		maxWait = initial
+	}
+	return &pollBackoff{initial: initial, max: maxWait}
+}
+
+func (b *pollBackoff) next() time.Duration {
+	if b.current == 0 {
+		b.current = b.initial
+	} else {
+		b.current *= 2
+	}
+	if b.current > b.max {
+		b.current = b.max
+	}
+	return b.current
+}
+
+func (b *pollBackoff) reset() {
+	b.current = 0
+}
+
+// batchPoll claims up to count tasks of a type, waiting up to the poll timeout for one to be
+// queued when there are none
+func (c *HTTPConductorClient) batchPoll(ctx context.Context, taskType, workerID string, count int) ([]*ConductorTask, error) {
+	timeout := defaultPollTimeout
+	if c.config.Conductor.PollTimeout > 0 {
+		timeout = time.Duration(c.config.Conductor.PollTimeout) * time.Millisecond
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	query := url.Values{}
+	query.Set("workerid", workerID)
+	query.Set("count", strconv.Itoa(count))
+	query.Set("timeout", strconv.FormatInt(timeout.Milliseconds(), 10))
+	pollURL := fmt.Sprintf("%s/api/tasks/poll/batch/%s?%s", c.baseURL, url.PathEscape(taskType), query.Encode())
+
+	// Allow Conductor the whole long poll before giving up on the request
+	ctx, cancel := context.WithTimeout(ctx, timeout+5*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", pollURL, nil)
@@ -227,22 +363,22 @@ func (c *HTTPConductorClient) pollTask(taskType, workerID string) (*ConductorTas
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to poll task: %w", err)
+		return nil, fmt.Errorf("failed to poll tasks: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNoContent || resp.StatusCode == 204 {
+	if resp.StatusCode == http.StatusNoContent {
 		// No tasks available
 		return nil, nil
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		c.logger.Debug("Poll task failed",
+		c.logger.Debug("Poll tasks failed",
 			zap.String("task_type", taskType),
 			zap.Int("status_code", resp.StatusCode),
 			zap.String("response", string(body)))
-		return nil, fmt.Errorf("poll task failed with status: %d", resp.StatusCode)
+		return nil, fmt.Errorf("poll tasks failed with status: %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -255,24 +391,28 @@ func (c *HTTPConductorClient) pollTask(taskType, workerID string) (*ConductorTas
 		return nil, nil
 	}
 
-	var task ConductorTask
-	if err := json.Unmarshal(body, &task); err != nil {
-		c.logger.Error("Failed to unmarshal task",
+	var polled []ConductorTask
+	if err := json.Unmarshal(body, &polled); err != nil {
+		c.logger.Error("Failed to unmarshal tasks",
 			zap.String("task_type", taskType),
 			zap.String("response_body", string(body)),
 			zap.Error(err))
-		return nil, fmt.Errorf("failed to unmarshal task: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal tasks: %w", err)
 	}
 
-	// Validate task has required fields
-	if task.TaskID == "" || task.TaskType == "" {
-		c.logger.Warn("Received invalid task",
-			zap.String("task_id", task.TaskID),
-			zap.String("task_type", task.TaskType))
-		return nil, nil
+	tasks := make([]*ConductorTask, 0, len(polled))
+	for i := range polled {
+		// Validate task has required fields
+		if polled[i].TaskID == "" || polled[i].TaskType == "" {
+			c.logger.Warn("Received invalid task",
+				zap.String("task_id", polled[i].TaskID),
+				zap.String("task_type", polled[i].TaskType))
+			continue
+		}
+		tasks = append(tasks, &polled[i])
 	}
 
-	return &task, nil
+	return tasks, nil
 }
 
 // executeTask executes a task
@@ -281,11 +421,20 @@ func (c *HTTPConductorClient) executeTask(task *ConductorTask, workerID string,
 
 	logger.Info("Executing task",
 		zap.String("task_id", task.TaskID),
-		zap.String("task_type", task.TaskType))
+		zap.String("task_type", task.TaskType),
+		zap.Int("workflow_priority", task.WorkflowPriority))
+
+	// A task handed back to Conductor while it waited for a worker is not run
+	if !c.running.start(task.TaskID, workerID) {
+		logger.Warn("Task handed back to Conductor before it started; skipping it",
+			zap.String("task_id", task.TaskID))
+		return
+	}
 
 	handler, exists := c.workers[task.TaskType]
 	if !exists {
 		logger.Error("No handler registered for task type", zap.String("task_type", task.TaskType))
+		c.running.finish(task.TaskID)
 		c.updateTaskResult(&ConductorTaskResult{
 			TaskID:                task.TaskID,
 			Status:                "FAILED",
@@ -302,10 +451,17 @@ func (c *HTTPConductorClient) executeTask(task *ConductorTask, workerID string,
 		WorkflowInstanceID: task.WorkflowInstanceID,
 		InputData:          task.InputData,
 		Status:             task.Status,
+		RetryCount:         task.RetryCount,
+		WorkerID:           workerID,
 		CreatedTime:        time.Now(),
 		UpdatedTime:        time.Now(),
 	}
 
+	if counters := c.counters[task.TaskType]; counters != nil {
+		counters.inFlight.Add(1)
+		defer counters.inFlight.Add(-1)
+	}
+
 	// Execute the handler with recovery
 	var result *MockTaskResult
 	var handlerErr error
@@ -323,6 +479,12 @@ func (c *HTTPConductorClient) executeTask(task *ConductorTask, workerID string,
 	}()
 
 	processingTime := time.Since(startTime)
+	if counters := c.counters[task.TaskType]; counters != nil {
+		counters.executed.Add(1)
+		if handlerErr != nil || result == nil || result.Status == "FAILED" || result.Status == "FAILED_WITH_TERMINAL_ERROR" {
+			counters.failed.Add(1)
+		}
+	}
 
 	// Convert result back to Conductor format
 	conductorResult := &ConductorTaskResult{
@@ -386,11 +548,19 @@ func (c *HTTPConductorClient) executeTask(task *ConductorTask, workerID string,
 		conductorResult.OutputData["timestamp"] = time.Now().UTC().Format(time.RFC3339)
 
 		// Only set reason for incompletion if provided and status is FAILED
-		if result.ReasonForIncompletion != "" && (status == "FAILED" || status == "TIMED_OUT") {
+		if result.ReasonForIncompletion != "" && (status == "FAILED" || status == "FAILED_WITH_TERMINAL_ERROR" || status == "TIMED_OUT") {
 			conductorResult.ReasonForIncompletion = result.ReasonForIncompletion
 		}
 	}
 
+	// A task handed back to Conductor while draining may already be running on another worker
+	if !c.running.finish(task.TaskID) {
+		logger.Warn("Task finished after being handed back to Conductor; dropping its result",
+			zap.String("task_id", task.TaskID),
+			zap.String("status", conductorResult.Status))
+		return
+	}
+
 	// Update task result in Conductor
 	if err := c.updateTaskResult(conductorResult); err != nil {
 		logger.Error("Failed to update task result", zap.Error(err))
@@ -647,8 +817,9 @@ func (c *HTTPConductorClient) CreateUnderwritingWorkflowDefinition() *WorkflowDe
 				TaskReferenceName: "credit_check_task",
 				Type:              "SIMPLE",
 				InputParameters: map[string]interface{}{
-					"applicationId": "${workflow.input.applicationId}",
-					"userId":        "${workflow.input.userId}",
+					"applicationId":   "${workflow.input.applicationId}",
+					"userId":          "${workflow.input.userId}",
+					"creditConsentId": "${workflow.input.creditConsentId}",
 				},
 			},
 			{
@@ -687,6 +858,25 @@ func (c *HTTPConductorClient) CreateUnderwritingWorkflowDefinition() *WorkflowDe
 					"newState":      "underwriting_completed",
 				},
 			},
+			{
+				Name:              "capture_compliance_data",
+				TaskReferenceName: "capture_compliance_data_task",
+				Type:              "SIMPLE",
+				InputParameters: map[string]interface{}{
+					"applicationId":      "${workflow.input.applicationId}",
+					"workflowInstanceId": "${workflow.workflowId}",
+					"decision":           "${underwriting_decision_task.output.underwritingResult.decision}",
+					"denialReasons":      "${underwriting_decision_task.output.decisionReasons}",
+				},
+			},
+			{
+				Name:              "generate_decision_letter",
+				TaskReferenceName: "generate_decision_letter_task",
+				Type:              "SIMPLE",
+				InputParameters: map[string]interface{}{
+					"applicationId": "${workflow.input.applicationId}",
+				},
+			},
 		},
 		InputParameters: []string{"applicationId", "userId"},
 		OutputParameters: map[string]interface{}{
@@ -707,7 +897,7 @@ func (c *HTTPConductorClient) CreateTaskDefinitions() []*TaskDefinition {
 			TimeoutSeconds:         300,
 			ResponseTimeoutSeconds: 280,
 			RetryCount:             3,
-			InputKeys:              []string{"applicationId", "userId"},
+			InputKeys:              []string{"applicationId", "userId", "creditConsentId"},
 			OutputKeys:             []string{"creditScore", "creditDecision", "riskAnalysis"},
 		},
 		{
@@ -735,7 +925,7 @@ func (c *HTTPConductorClient) CreateTaskDefinitions() []*TaskDefinition {
 			ResponseTimeoutSeconds: 100,
 			RetryCount:             2,
 			InputKeys:              []string{"applicationId", "userId"},
-			OutputKeys:             []string{"decision", "approvedAmount", "interestRate", "conditions"},
+			OutputKeys:             []string{"decision", "approvedAmount", "interestRate", "conditions", "knockedOut", "stages"},
 		},
 		{
 			Name:                   "update_application_state",
@@ -762,7 +952,7 @@ func (c *HTTPConductorClient) CreateTaskDefinitions() []*TaskDefinition {
 			ResponseTimeoutSeconds: 160,
 			RetryCount:             2,
 			InputKeys:              []string{"applicationId"},
-			OutputKeys:             []string{"fraudRiskScore", "fraudIndicators"},
+			OutputKeys:             []string{"fraudReportId", "fraudRiskScore", "fraudRiskLevel", "fraudIndicators", "fraudReasonCodes", "fraudRecommendation", "identityVerificationRequired", "manualReviewRequired"},
 		},
 		{
 			Name:                   "calculate_interest_rate",
@@ -779,8 +969,17 @@ func (c *HTTPConductorClient) CreateTaskDefinitions() []*TaskDefinition {
 			TimeoutSeconds:         120,
 			ResponseTimeoutSeconds: 100,
 			RetryCount:             1,
-			InputKeys:              []string{"applicationId", "approvedAmount"},
-			OutputKeys:             []string{"loanNumber", "approvalDetails"},
+			InputKeys:              []string{"applicationId", "workflowInstanceId", "approvedAmount", "interestRate", "secondaryReviewTaskReferenceName"},
+			OutputKeys:             []string{"loanNumber", "approvalDetails", "secondaryReviewId", "secondApprover"},
+		},
+		{
+			Name:                   "require_secondary_review",
+			Description:            "Queues approvals above the single approver limit for a second underwriter's sign-off",
+			TimeoutSeconds:         60,
+			ResponseTimeoutSeconds: 50,
+			RetryCount:             2,
+			InputKeys:              []string{"applicationId", "workflowInstanceId", "taskReferenceName", "approvedAmount", "approvedBy"},
+			OutputKeys:             []string{"secondaryReview", "reviewId", "reviewStatus", "approvalLimit", "dueDate"},
 		},
 		{
 			Name:                   "process_denial",
@@ -793,30 +992,94 @@ func (c *HTTPConductorClient) CreateTaskDefinitions() []*TaskDefinition {
 		},
 		{
 			Name:                   "assign_manual_review",
-			Description:            "Assigns application for manual review",
+			Description:            "Queues application for manual review by an underwriter",
 			TimeoutSeconds:         60,
 			ResponseTimeoutSeconds: 50,
 			RetryCount:             2,
-			InputKeys:              []string{"applicationId", "reviewReason"},
-			OutputKeys:             []string{"assignedTo", "reviewPriority"},
+			InputKeys:              []string{"applicationId", "workflowInstanceId", "taskReferenceName", "reviewReason", "reviewReasons", "riskLevel", "priority"},
+			OutputKeys:             []string{"reviewId", "reviewStatus", "priority", "dueDate"},
 		},
 		{
 			Name:                   "process_conditional_approval",
-			Description:            "Processes conditional approval",
-			TimeoutSeconds:         120,
-			ResponseTimeoutSeconds: 100,
-			RetryCount:             1,
-			InputKeys:              []string{"applicationId", "conditions"},
-			OutputKeys:             []string{"conditionalApproval", "nextSteps"},
+			Description:            "Records the conditions of a conditional approval for the borrower to clear",
+			TimeoutSeconds:         60,
+			ResponseTimeoutSeconds: 50,
+			RetryCount:             2,
+			InputKeys:              []string{"applicationId", "workflowInstanceId", "taskReferenceName", "approvedAmount", "conditions"},
+			OutputKeys:             []string{"conditionIds", "criticalOutstanding", "conditionsStatus"},
 		},
 		{
 			Name:                   "generate_counter_offer",
-			Description:            "Generates counter offer terms",
+			Description:            "Decides and prices the counter offer terms a borrower requested",
 			TimeoutSeconds:         90,
 			ResponseTimeoutSeconds: 80,
 			RetryCount:             1,
-			InputKeys:              []string{"applicationId", "requestedAmount"},
-			OutputKeys:             []string{"counterOffer", "offerTerms"},
+			InputKeys: []string{"applicationId", "offerId", "requestedAmount", "requestedTermMonths",
+				"currentAmount", "currentRate", "currentAPR", "currentTermMonths"},
+			OutputKeys: []string{"counterOffer", "offerTerms"},
+		},
+		{
+			Name:                   "finalize_counter_offer",
+			Description:            "Re-decides and prices accepted counter offer terms and records the final underwriting result",
+			TimeoutSeconds:         60,
+			ResponseTimeoutSeconds: 50,
+			RetryCount:             3,
+			InputKeys:              []string{"applicationId", "counterOfferId", "acceptedAmount", "acceptedTermMonths", "acceptedRate"},
+			OutputKeys:             []string{"decision", "manualReviewRequired", "underwritingResultId", "underwritingResult"},
+		},
+		{
+			Name:                   "capture_compliance_data",
+			Description:            "Captures the regulatorily required data of a decided application for compliance reporting",
+			TimeoutSeconds:         60,
+			ResponseTimeoutSeconds: 50,
+			RetryCount:             3,
+			InputKeys:              []string{"applicationId", "workflowInstanceId", "decision", "denialReasons"},
+			OutputKeys:             []string{"captured", "complianceRecordId", "actionTaken", "actionTakenDate"},
+		},
+		{
+			Name:                   "generate_decision_letter",
+			Description:            "Sends the borrower the approval letter or adverse action notice and records its delivery",
+			TimeoutSeconds:         120,
+			ResponseTimeoutSeconds: 100,
+			RetryCount:             3,
+			InputKeys:              []string{"applicationId"},
+			OutputKeys:             []string{"generated", "documentId", "documentType", "reasonCodes", "deliveredAt", "noticeDueDate", "withinNoticePeriod"},
+		},
+		{
+			Name:                   "invalidate_underwriting_result",
+			Description:            "Invalidates the underwriting result of an application that changed materially before funding",
+			TimeoutSeconds:         60,
+			ResponseTimeoutSeconds: 50,
+			RetryCount:             3,
+			InputKeys:              []string{"applicationId", "changes"},
+			OutputKeys:             []string{"invalidated", "resultCount", "reason", "invalidatedAt"},
+		},
+		{
+			Name:                   "rollback_application_state",
+			Description:            "Returns the application of a failed workflow to its state before the workflow",
+			TimeoutSeconds:         60,
+			ResponseTimeoutSeconds: 50,
+			RetryCount:             5,
+			InputKeys:              []string{"applicationId", "workflowId", "workflowName", "workflowStartTime", "reason"},
+			OutputKeys:             []string{"applied", "reason", "fromState", "toState", "disbursementCancelled", "completedAt"},
+		},
+		{
+			Name:                   "void_workflow_offers",
+			Description:            "Voids the offers a failed workflow made",
+			TimeoutSeconds:         60,
+			ResponseTimeoutSeconds: 50,
+			RetryCount:             5,
+			InputKeys:              []string{"applicationId", "workflowId", "workflowName", "workflowStartTime", "reason"},
+			OutputKeys:             []string{"applied", "reason", "voidedOffers", "completedAt"},
+		},
+		{
+			Name:                   "notify_borrower_of_failure",
+			Description:            "Tells the borrower their application could not be processed after a workflow failed",
+			TimeoutSeconds:         60,
+			ResponseTimeoutSeconds: 50,
+			RetryCount:             5,
+			InputKeys:              []string{"applicationId", "workflowId", "workflowName", "workflowStartTime", "reason"},
+			OutputKeys:             []string{"applied", "reason", "completedAt"},
 		},
 	}
 }
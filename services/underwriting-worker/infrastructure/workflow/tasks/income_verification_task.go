@@ -211,6 +211,7 @@ func (h *IncomeVerificationTaskHandler) Execute(ctx context.Context, input map[s
 			"verifiedMonthlyIncome": verification.VerifiedMonthlyIncome,
 			"incomeVariance":        incomeAnalysis.IncomeVariance,
 			"incomeVariancePercent": incomeAnalysis.IncomeVariancePercent,
+			"incomeVarianceFlagged": verification.IncomeVarianceFlagged,
 			"employerName":          verification.EmployerName,
 			"jobTitle":              verification.JobTitle,
 			"employmentType":        verification.EmploymentType,
@@ -345,6 +346,11 @@ func (h *IncomeVerificationTaskHandler) enhanceIncomeVerification(
 	verification *domain.IncomeVerification,
 	application *domain.LoanApplication,
 ) {
+	// Payroll data is what the aggregator reported; there is nothing to fill in
+	if verification.VerificationMethod == domain.IncomeMethodPayroll {
+		return
+	}
+
 	// If verification was successful, perform additional checks
 	if verification.VerificationStatus == domain.IncomeVerified {
 		// Calculate monthly income if not provided
@@ -463,6 +469,10 @@ func (h *IncomeVerificationTaskHandler) analyzeIncomeVerification(
 		}
 
 		analysis.VerificationScore = score
+	} else if verification.VerificationStatus == domain.IncomePending {
+		analysis.RiskFactors = append(analysis.RiskFactors, "income_verification_pending")
+		analysis.Recommendations = append(analysis.Recommendations,
+			"Borrower must connect their payroll account to complete income verification")
 	} else {
 		// Unverified income
 		analysis.RiskFactors = append(analysis.RiskFactors, "income_not_verified")
@@ -488,6 +498,10 @@ func (h *IncomeVerificationTaskHandler) createResponseFromExisting(
 			"verificationMethod":    verification.VerificationMethod,
 			"verifiedAnnualIncome":  verification.VerifiedAnnualIncome,
 			"verifiedMonthlyIncome": verification.VerifiedMonthlyIncome,
+			"statedAnnualIncome":    verification.StatedAnnualIncome,
+			"incomeVariance":        verification.IncomeVariance,
+			"incomeVariancePercent": verification.IncomeVariancePercent,
+			"incomeVarianceFlagged": verification.IncomeVarianceFlagged,
 			"employerName":          verification.EmployerName,
 			"jobTitle":              verification.JobTitle,
 			"employmentType":        verification.EmploymentType,
@@ -505,6 +519,7 @@ func (h *IncomeVerificationTaskHandler) createFailureResponse(applicationID stri
 		"success":       false,
 		"applicationId": applicationID,
 		"error":         err.Error(),
+		"retryable":     IsRetryableError(err),
 		"incomeVerification": map[string]interface{}{
 			"verificationStatus": string(domain.IncomeFailed),
 			"verificationMethod": "error",
@@ -0,0 +1,159 @@
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/config"
+
+	"underwriting_worker/domain"
+)
+
+// LoanServiceClient calls the loan service's internal API for the work underwriting hands back to
+// it, such as sending the borrower the letter telling them of the decision
+type LoanServiceClient struct {
+	logger       *zap.Logger
+	httpClient   *http.Client
+	baseURL      string
+	serviceToken string
+}
+
+// NewLoanServiceClient creates a loan service client
+func NewLoanServiceClient(logger *zap.Logger, cfg config.ServiceEndpointConfig) *LoanServiceClient {
+	timeout := time.Duration(cfg.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &LoanServiceClient{
+		logger:       logger,
+		httpClient:   &http.Client{Timeout: timeout},
+		baseURL:      strings.TrimRight(cfg.BaseURL, "/"),
+		serviceToken: cfg.ServiceToken,
+	}
+}
+
+// decisionLetterRequest is the loan service's decision letter request
+type decisionLetterRequest struct {
+	Decision       string                 `json:"decision"` // approved or denied
+	Reasons        []decisionLetterReason `json:"reasons,omitempty"`
+	ApprovedAmount float64                `json:"approved_amount,omitempty"`
+	TermMonths     int                    `json:"term_months,omitempty"`
+	InterestRate   float64                `json:"interest_rate,omitempty"`
+	APR            float64                `json:"apr,omitempty"`
+}
+
+// decisionLetterReason is a reason code of the decision and its description
+type decisionLetterReason struct {
+	Code        string `json:"code"`
+	Description string `json:"description,omitempty"`
+}
+
+// IssueDecisionLetter has the loan service send the borrower the approval letter or adverse action
+// notice of the action taken on an application. The letter returned records when it was delivered;
+// a letter the loan service already delivered is returned without being sent again.
+func (c *LoanServiceClient) IssueDecisionLetter(ctx context.Context, applicationID string, request *decisionLetterRequest) (*domain.DecisionLetter, error) {
+	logger := c.logger.With(
+		zap.String("operation", "issue_decision_letter"),
+		zap.String("application_id", applicationID),
+		zap.String("decision", request.Decision),
+	)
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode decision letter request: %w", err)
+	}
+
+	endpoint := c.baseURL + "/internal/v1/applications/" + url.PathEscape(applicationID) + "/decision-letters"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build decision letter request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.serviceToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Loan service request failed", zap.Error(err))
+		return nil, fmt.Errorf("failed to call loan service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("Unexpected loan service response", zap.Int("status", resp.StatusCode))
+		return nil, fmt.Errorf("unexpected decision letter status: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data domain.DecisionLetter `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode decision letter response: %w", err)
+	}
+	if result.Data.ID == "" || result.Data.DeliveredAt == nil {
+		return nil, fmt.Errorf("loan service returned no delivered decision letter")
+	}
+
+	return &result.Data, nil
+}
+
+// compensationRequest is the loan service's request for one compensation step of a failed workflow
+type compensationRequest struct {
+	Action            string     `json:"action"`
+	WorkflowID        string     `json:"workflow_id"`
+	WorkflowName      string     `json:"workflow_name,omitempty"`
+	WorkflowStartedAt *time.Time `json:"workflow_started_at,omitempty"`
+	Reason            string     `json:"reason,omitempty"`
+}
+
+// Compensate has the loan service undo one side effect a failed workflow left on an application.
+// The loan service can repeat a step safely, so a retried task does not undo twice.
+func (c *LoanServiceClient) Compensate(ctx context.Context, applicationID string, request *compensationRequest) (*domain.Compensation, error) {
+	logger := c.logger.With(
+		zap.String("operation", "compensate_workflow"),
+		zap.String("application_id", applicationID),
+		zap.String("workflow_id", request.WorkflowID),
+		zap.String("action", request.Action),
+	)
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode compensation request: %w", err)
+	}
+
+	endpoint := c.baseURL + "/internal/v1/applications/" + url.PathEscape(applicationID) + "/compensations"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build compensation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.serviceToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Loan service request failed", zap.Error(err))
+		return nil, fmt.Errorf("failed to call loan service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("Unexpected loan service response", zap.Int("status", resp.StatusCode))
+		return nil, fmt.Errorf("unexpected compensation status: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data domain.Compensation `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode compensation response: %w", err)
+	}
+
+	return &result.Data, nil
+}
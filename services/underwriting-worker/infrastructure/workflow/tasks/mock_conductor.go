@@ -15,6 +15,8 @@ type MockTask struct {
 	WorkflowInstanceID string                 `json:"workflowInstanceId"`
 	InputData          map[string]interface{} `json:"inputData"`
 	Status             string                 `json:"status"`
+	RetryCount         int                    `json:"retryCount"`         // times Conductor has retried the task
+	WorkerID           string                 `json:"workerId,omitempty"` // polling worker running the task
 	CreatedTime        time.Time              `json:"createdTime"`
 	UpdatedTime        time.Time              `json:"updatedTime"`
 }
@@ -176,6 +178,7 @@ func (c *MockConductorClient) processTask(task *MockTask, workerID string, logge
 	}
 
 	// Execute the task handler
+	task.WorkerID = workerID
 	result, err := handler(task)
 
 	processingTime := time.Since(startTime)
@@ -0,0 +1,329 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/config"
+
+	"underwriting_worker/domain"
+)
+
+// PolicyClient supplies the underwriting policy in force from the decision engine, which owns
+// policy approval. The policy is cached and refreshed in the background; when the decision engine
+// cannot be reached the last policy fetched stays in force. Each version loaded is saved to the
+// store, when there is one, so decisions can be traced to their policy and a restarted worker can
+// keep deciding under the last policy while the decision engine is down.
+//
+// A workflow run is pinned to the version in force when its first policy-governed task runs, and
+// its later tasks decide under that version even after a newer one has been put in force.
+type PolicyClient struct {
+	logger     *zap.Logger
+	httpClient *http.Client
+	baseURL    string
+	store      domain.UnderwritingPolicyRepository
+	pins       domain.PolicyPinRepository
+
+	mu       sync.RWMutex
+	policy   *domain.UnderwritingPolicy
+	versions map[string]*domain.UnderwritingPolicy // every version loaded, for pinned runs
+}
+
+// taskScopeKey is the context key of the task a policy is looked up for
+type taskScopeKey struct{}
+
+// taskScope identifies the task, and the workflow run it belongs to, a policy is looked up for
+type taskScope struct {
+	taskName           string
+	workflowInstanceID string
+}
+
+// withTaskScope scopes policy lookups made with the returned context to a task of a workflow run
+func withTaskScope(ctx context.Context, taskName, workflowInstanceID string) context.Context {
+	return context.WithValue(ctx, taskScopeKey{}, taskScope{taskName: taskName, workflowInstanceID: workflowInstanceID})
+}
+
+// decisionEnginePolicy is the decision engine's representation of an approved policy version
+type decisionEnginePolicy struct {
+	Version       string     `json:"version"`
+	Name          string     `json:"name"`
+	EffectiveFrom *time.Time `json:"effective_from"`
+	CreatedBy     string     `json:"created_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	Thresholds    struct {
+		MinCreditScore      int      `json:"min_credit_score"`
+		MaxDTIRatio         float64  `json:"max_dti_ratio"`
+		MinAnnualIncome     float64  `json:"min_annual_income"`
+		MinLoanAmount       float64  `json:"min_loan_amount"`
+		MaxLoanAmount       float64  `json:"max_loan_amount"`
+		AllowedLoanTerms    []int    `json:"allowed_loan_terms"`
+		AllowedLoanPurposes []string `json:"allowed_loan_purposes"`
+		AutoApproval        struct {
+			MinCreditScore int     `json:"min_credit_score"`
+			MaxDTIRatio    float64 `json:"max_dti_ratio"`
+			MaxLoanAmount  float64 `json:"max_loan_amount"`
+		} `json:"auto_approval"`
+	} `json:"thresholds"`
+}
+
+// NewPolicyClient creates a policy client for the decision engine
+func NewPolicyClient(logger *zap.Logger, cfg config.ServiceEndpointConfig, store domain.UnderwritingPolicyRepository, pins domain.PolicyPinRepository) *PolicyClient {
+	return &PolicyClient{
+		logger:     logger,
+		httpClient: &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second},
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		store:      store,
+		pins:       pins,
+		versions:   map[string]*domain.UnderwritingPolicy{},
+	}
+}
+
+// GetActive returns the underwriting policy governing the task ctx is scoped to: the version its
+// workflow run is pinned to, pinning the run to the version in force on its first lookup. Without
+// a task scope or a pin store it returns the version in force.
+func (c *PolicyClient) GetActive(ctx context.Context) (*domain.UnderwritingPolicy, error) {
+	current, err := c.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, ok := ctx.Value(taskScopeKey{}).(taskScope)
+	if !ok {
+		return current, nil
+	}
+	logger := c.logger.With(
+		zap.String("task_name", scope.taskName),
+		zap.String("workflow_instance_id", scope.workflowInstanceID))
+
+	policy, pinned := current, false
+	if c.pins != nil && scope.workflowInstanceID != "" {
+		version, err := c.pins.Pin(ctx, scope.workflowInstanceID, current.PolicyVersion)
+		switch {
+		case err != nil:
+			logger.Warn("Failed to pin workflow to its underwriting policy, using the policy in force", zap.Error(err))
+		case version == current.PolicyVersion:
+			pinned = true
+		default:
+			if policy, err = c.byVersion(ctx, version); err != nil {
+				logger.Warn("Pinned underwriting policy unavailable, using the policy in force",
+					zap.String("pinned_version", version),
+					zap.Error(err))
+				policy = current
+			} else {
+				pinned = true
+			}
+		}
+	}
+
+	logger.Info("Underwriting policy governing task",
+		zap.String("policy_version", policy.PolicyVersion),
+		zap.String("current_version", current.PolicyVersion),
+		zap.Bool("pinned", pinned))
+	return policy, nil
+}
+
+// current returns the underwriting policy in force, fetching it when none has been cached yet
+func (c *PolicyClient) current(ctx context.Context) (*domain.UnderwritingPolicy, error) {
+	c.mu.RLock()
+	policy := c.policy
+	c.mu.RUnlock()
+	if policy != nil {
+		return policy, nil
+	}
+
+	if err := c.Refresh(ctx); err != nil {
+		if c.store == nil {
+			return nil, err
+		}
+		stored, storeErr := c.store.GetActive(ctx)
+		if storeErr != nil {
+			return nil, err
+		}
+		c.logger.Warn("Decision engine unavailable, using the last stored underwriting policy",
+			zap.String("policy_version", stored.PolicyVersion),
+			zap.Error(err))
+		c.mu.Lock()
+		if c.policy == nil {
+			c.policy = stored
+		}
+		c.versions[stored.PolicyVersion] = stored
+		c.mu.Unlock()
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.policy, nil
+}
+
+// Refresh fetches the policy in force from the decision engine and caches it
+func (c *PolicyClient) Refresh(ctx context.Context) error {
+	policy, err := c.fetch(ctx, "active")
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	previous := c.policy
+	c.policy = policy
+	c.versions[policy.PolicyVersion] = policy
+	c.mu.Unlock()
+
+	if previous == nil || previous.PolicyVersion != policy.PolicyVersion {
+		c.logger.Info("Underwriting policy loaded",
+			zap.String("policy_version", policy.PolicyVersion),
+			zap.Time("effective_date", policy.EffectiveDate))
+		c.save(ctx, policy)
+	}
+	return nil
+}
+
+// OnPolicyChanged switches to a policy version put in force by another worker instance, loading it
+// from the store. An empty version, or one the store cannot supply, refreshes from the decision
+// engine instead.
+func (c *PolicyClient) OnPolicyChanged(ctx context.Context, version string) {
+	c.mu.RLock()
+	previous := c.policy
+	c.mu.RUnlock()
+	if version != "" && previous != nil && previous.PolicyVersion == version {
+		return
+	}
+
+	if version != "" {
+		if policy, err := c.byVersion(ctx, version); err == nil {
+			c.mu.Lock()
+			c.policy = policy
+			c.mu.Unlock()
+			c.logger.Info("Underwriting policy changed",
+				zap.String("policy_version", policy.PolicyVersion),
+				zap.Time("effective_date", policy.EffectiveDate))
+			return
+		}
+	}
+	if err := c.Refresh(ctx); err != nil {
+		c.logger.Warn("Failed to load changed underwriting policy, keeping the cached policy",
+			zap.String("policy_version", version),
+			zap.Error(err))
+	}
+}
+
+// byVersion returns a policy version, from the cache, the store or the decision engine
+func (c *PolicyClient) byVersion(ctx context.Context, version string) (*domain.UnderwritingPolicy, error) {
+	c.mu.RLock()
+	policy, ok := c.versions[version]
+	c.mu.RUnlock()
+	if ok {
+		return policy, nil
+	}
+
+	if c.store != nil {
+		if stored, err := c.store.GetByVersion(ctx, version); err == nil {
+			policy = stored
+		}
+	}
+	if policy == nil {
+		var err error
+		if policy, err = c.fetch(ctx, url.PathEscape(version)); err != nil {
+			return nil, err
+		}
+	}
+
+	c.mu.Lock()
+	c.versions[version] = policy
+	c.mu.Unlock()
+	return policy, nil
+}
+
+// fetch fetches a policy version, or "active" for the one in force, from the decision engine
+func (c *PolicyClient) fetch(ctx context.Context, version string) (*domain.UnderwritingPolicy, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/policies/"+version, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create policy request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch underwriting policy: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch underwriting policy: decision engine returned status %d", resp.StatusCode)
+	}
+
+	var fetched decisionEnginePolicy
+	if err := json.NewDecoder(resp.Body).Decode(&fetched); err != nil {
+		return nil, fmt.Errorf("failed to decode underwriting policy: %w", err)
+	}
+	return fetched.toDomain(), nil
+}
+
+// save stores a policy version and puts it in force in the store. Failures are only logged: the
+// policy is already in force in the worker.
+func (c *PolicyClient) save(ctx context.Context, policy *domain.UnderwritingPolicy) {
+	if c.store == nil {
+		return
+	}
+	stored := *policy
+	if err := c.store.Create(ctx, &stored); err != nil {
+		c.logger.Error("Failed to store underwriting policy", zap.String("policy_version", policy.PolicyVersion), zap.Error(err))
+		return
+	}
+	if err := c.store.SetActive(ctx, stored.ID); err != nil {
+		c.logger.Error("Failed to activate stored underwriting policy", zap.String("policy_version", policy.PolicyVersion), zap.Error(err))
+	}
+}
+
+// Start refreshes the policy every interval until ctx is cancelled, so versions approved in the
+// decision engine take effect without a restart
+func (c *PolicyClient) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Refresh(ctx); err != nil {
+				c.logger.Warn("Failed to refresh underwriting policy, keeping the cached policy", zap.Error(err))
+			}
+		}
+	}
+}
+
+// toDomain maps the decision engine's policy onto the worker's underwriting policy
+func (p *decisionEnginePolicy) toDomain() *domain.UnderwritingPolicy {
+	policy := &domain.UnderwritingPolicy{
+		ID:                  p.Version,
+		PolicyName:          p.Name,
+		PolicyVersion:       p.Version,
+		MinCreditScore:      p.Thresholds.MinCreditScore,
+		MaxDTIRatio:         p.Thresholds.MaxDTIRatio,
+		MinAnnualIncome:     p.Thresholds.MinAnnualIncome,
+		MinLoanAmount:       p.Thresholds.MinLoanAmount,
+		MaxLoanAmount:       p.Thresholds.MaxLoanAmount,
+		AllowedLoanTerms:    p.Thresholds.AllowedLoanTerms,
+		AllowedLoanPurposes: p.Thresholds.AllowedLoanPurposes,
+		AutoApprovalThresholds: domain.AutoApprovalThresholds{
+			MinCreditScore: p.Thresholds.AutoApproval.MinCreditScore,
+			MaxDTIRatio:    p.Thresholds.AutoApproval.MaxDTIRatio,
+			MaxLoanAmount:  p.Thresholds.AutoApproval.MaxLoanAmount,
+		},
+		IsActive:  true,
+		CreatedBy: p.CreatedBy,
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+	if p.EffectiveFrom != nil {
+		policy.EffectiveDate = *p.EffectiveFrom
+	}
+	return policy
+}
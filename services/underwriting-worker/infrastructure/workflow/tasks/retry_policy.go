@@ -0,0 +1,130 @@
+package tasks
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"math"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/config"
+)
+
+// Retry policy defaults for tasks the configuration does not cover
+const (
+	defaultTaskMaxAttempts       = 3
+	defaultTaskInitialBackoff    = time.Second
+	defaultTaskMaxBackoff        = 30 * time.Second
+	defaultTaskBackoffMultiplier = 2.0
+)
+
+// RetryPolicy is how the worker retries a task that failed with a retryable error before
+// reporting it to Conductor
+type RetryPolicy struct {
+	MaxAttempts       int // including the first attempt
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	BackoffMultiplier float64
+}
+
+// Backoff returns how long to wait after the given failed attempt, counting from 1
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := float64(p.InitialBackoff) * math.Pow(p.BackoffMultiplier, float64(attempt-1))
+	if backoff > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+	return time.Duration(backoff)
+}
+
+// RetryPolicies holds the retry policy of each task type
+type RetryPolicies struct {
+	defaults RetryPolicy
+	tasks    map[string]RetryPolicy
+}
+
+// NewRetryPolicies builds the retry policies from the Conductor configuration. Every task retries
+// with retry_attempts and retry_delay unless task_retries overrides it; unset override fields
+// keep the default.
+func NewRetryPolicies(cfg config.ConductorConfig) *RetryPolicies {
+	defaults := RetryPolicy{
+		MaxAttempts:       defaultTaskMaxAttempts,
+		InitialBackoff:    defaultTaskInitialBackoff,
+		MaxBackoff:        defaultTaskMaxBackoff,
+		BackoffMultiplier: defaultTaskBackoffMultiplier,
+	}
+	if cfg.RetryAttempts > 0 {
+		defaults.MaxAttempts = cfg.RetryAttempts
+	}
+	if cfg.RetryDelay > 0 {
+		defaults.InitialBackoff = time.Duration(cfg.RetryDelay) * time.Millisecond
+	}
+
+	policies := &RetryPolicies{defaults: defaults, tasks: make(map[string]RetryPolicy, len(cfg.TaskRetries))}
+	for taskType, override := range cfg.TaskRetries {
+		policy := defaults
+		if override.MaxAttempts > 0 {
+			policy.MaxAttempts = override.MaxAttempts
+		}
+		if override.InitialBackoff > 0 {
+			policy.InitialBackoff = time.Duration(override.InitialBackoff) * time.Millisecond
+		}
+		if override.MaxBackoff > 0 {
+			policy.MaxBackoff = time.Duration(override.MaxBackoff) * time.Millisecond
+		}
+		if override.BackoffMultiplier >= 1 {
+			policy.BackoffMultiplier = override.BackoffMultiplier
+		}
+		policies.tasks[taskType] = policy
+	}
+	return policies
+}
+
+// For returns the retry policy of a task type
+func (p *RetryPolicies) For(taskType string) RetryPolicy {
+	if policy, ok := p.tasks[taskType]; ok {
+		return policy
+	}
+	return p.defaults
+}
+
+// IsRetryableError reports whether a task failure is transient: the decision engine or a bureau
+// behind it being unavailable, a network timeout, or the database dropping the connection,
+// running out of resources or aborting a transaction. Any other failure is terminal, since
+// running the task again would fail the same way.
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var engineErr *DecisionEngineError
+	if errors.As(err, &engineErr) {
+		return engineErr.Retryable
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch code := string(pqErr.Code); {
+		case strings.HasPrefix(code, "08"), // connection exception
+			strings.HasPrefix(code, "53"), // insufficient resources
+			code == "40001",               // serialization failure
+			code == "40P01",               // deadlock detected
+			code == "57P03":               // cannot connect now
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,64 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// handleUnderwritingResultInvalidation invalidates the underwriting result of an application that
+// changed materially after it was decided but before it was funded, so the stale decision is no
+// longer read as the application's. It runs first in the re-underwriting workflow, which then
+// underwrites the application again; an application with no result in force is left as it is.
+func (w *UnderwritingTaskWorker) handleUnderwritingResultInvalidation(ctx context.Context, input map[string]interface{}) (map[string]interface{}, error) {
+	logger := w.logger.With(zap.String("operation", "invalidate_underwriting_result"))
+
+	applicationID, ok := input["applicationId"].(string)
+	if !ok || applicationID == "" {
+		return nil, fmt.Errorf("application ID is required")
+	}
+	if w.underwritingResults == nil {
+		return nil, fmt.Errorf("underwriting result store is not configured")
+	}
+	logger = logger.With(zap.String("application_id", applicationID))
+
+	reason := materialChangeReason(input["changes"])
+	if reason == "" {
+		reason = "Material change to the application before funding"
+	}
+
+	invalidatedAt := time.Now().UTC()
+	invalidated, err := w.underwritingResults.Invalidate(ctx, applicationID, reason, invalidatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Underwriting result invalidated",
+		zap.Int("invalidated", invalidated),
+		zap.String("reason", reason))
+
+	return map[string]interface{}{
+		"invalidated":   invalidated > 0,
+		"resultCount":   invalidated,
+		"reason":        reason,
+		"invalidatedAt": invalidatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// materialChangeReason joins the descriptions of the material changes in the workflow input
+func materialChangeReason(changes interface{}) string {
+	list, _ := changes.([]interface{})
+	descriptions := make([]string, 0, len(list))
+	for _, item := range list {
+		change, _ := item.(map[string]interface{})
+		if description, _ := change["description"].(string); description != "" {
+			descriptions = append(descriptions, description)
+		} else if changeType, _ := change["type"].(string); changeType != "" {
+			descriptions = append(descriptions, changeType)
+		}
+	}
+	return strings.Join(descriptions, "; ")
+}
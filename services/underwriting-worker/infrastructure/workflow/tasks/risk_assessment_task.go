@@ -7,6 +7,8 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/huuhoait/los-demo/services/shared/pkg/dti"
+
 	"underwriting_worker/application/usecases"
 	"underwriting_worker/domain"
 )
@@ -19,6 +21,7 @@ type RiskAssessmentTaskHandler struct {
 	creditReportRepo    domain.CreditReportRepository
 	riskAssessmentRepo  domain.RiskAssessmentRepository
 	riskScoringService  domain.RiskScoringService
+	dti                 *dti.Calculator
 }
 
 // NewRiskAssessmentTaskHandler creates a new risk assessment task handler
@@ -29,6 +32,7 @@ func NewRiskAssessmentTaskHandler(
 	creditReportRepo domain.CreditReportRepository,
 	riskAssessmentRepo domain.RiskAssessmentRepository,
 	riskScoringService domain.RiskScoringService,
+	dtiCalculator *dti.Calculator,
 ) *RiskAssessmentTaskHandler {
 	return &RiskAssessmentTaskHandler{
 		logger:              logger,
@@ -37,6 +41,7 @@ func NewRiskAssessmentTaskHandler(
 		creditReportRepo:    creditReportRepo,
 		riskAssessmentRepo:  riskAssessmentRepo,
 		riskScoringService:  riskScoringService,
+		dti:                 dtiCalculator,
 	}
 }
 
@@ -58,6 +63,10 @@ func (h *RiskAssessmentTaskHandler) Execute(ctx context.Context, input map[strin
 		return nil, fmt.Errorf("user ID is required")
 	}
 
+	if h.loanApplicationRepo == nil || h.creditReportRepo == nil || h.riskAssessmentRepo == nil {
+		return nil, fmt.Errorf("underwriting database not available")
+	}
+
 	// Get loan application
 	application, err := h.loanApplicationRepo.GetByID(ctx, applicationID)
 	if err != nil {
@@ -161,9 +170,16 @@ func (h *RiskAssessmentTaskHandler) performRiskAssessment(
 	creditReport *domain.CreditReport,
 ) (*domain.RiskAssessment, error) {
 	// Use risk scoring service
-	riskAssessment, err := h.riskScoringService.CalculateRiskScore(ctx, application, creditReport)
+	var riskAssessment *domain.RiskAssessment
+	err := fmt.Errorf("risk scoring service not available")
+	if h.riskScoringService != nil {
+		riskAssessment, err = h.riskScoringService.CalculateRiskScore(ctx, application, creditReport)
+	}
 	if err != nil {
 		// If service fails, create a basic risk assessment
+		h.logger.Warn("Risk scoring failed, using basic risk assessment",
+			zap.String("application_id", application.ID),
+			zap.Error(err))
 		riskAssessment = h.createBasicRiskAssessment(application, creditReport)
 	}
 
@@ -227,7 +243,7 @@ func (h *RiskAssessmentTaskHandler) enhanceRiskAssessment(
 	// Update assessment data with detailed analysis
 	assessment.AssessmentData = map[string]interface{}{
 		"credit_utilization":   creditReport.CreditUtilization,
-		"dti_ratio":            application.CalculateDTI(),
+		"dti_ratio":            application.CalculateDTI(h.dti),
 		"loan_to_income":       application.LoanAmount / application.AnnualIncome,
 		"credit_age_months":    h.calculateCreditAge(creditReport),
 		"derogatory_count":     creditReport.GetDerogatoriesCount(),
@@ -337,16 +353,16 @@ func (h *RiskAssessmentTaskHandler) calculateIncomeRiskScore(application *domain
 }
 
 func (h *RiskAssessmentTaskHandler) calculateDebtRiskScore(application *domain.LoanApplication) float64 {
-	dti := application.CalculateDTI()
+	dtiRatio := application.CalculateDTI(h.dti)
 
 	switch {
-	case dti > 0.5:
+	case dtiRatio > 0.5:
 		return 80
-	case dti > 0.43:
+	case dtiRatio > 0.43:
 		return 60
-	case dti > 0.36:
+	case dtiRatio > 0.36:
 		return 40
-	case dti > 0.28:
+	case dtiRatio > 0.28:
 		return 20
 	default:
 		return 10
@@ -435,12 +451,12 @@ func (h *RiskAssessmentTaskHandler) identifyRiskFactors(
 	}
 
 	// Debt-related risk factors
-	dti := application.CalculateDTI()
-	if dti > 0.4 {
+	dtiRatio := application.CalculateDTI(h.dti)
+	if dtiRatio > 0.4 {
 		factors = append(factors, domain.RiskFactor{
 			FactorID:    "high_dti_ratio",
 			FactorType:  "debt",
-			Description: fmt.Sprintf("Debt-to-income ratio %.1f%% is high", dti*100),
+			Description: fmt.Sprintf("Debt-to-income ratio %.1f%% is high", dtiRatio*100),
 			Impact:      "high",
 			Score:       assessment.DebtRiskScore,
 			Weight:      0.2,
@@ -667,6 +683,7 @@ func (h *RiskAssessmentTaskHandler) createFailureResponse(applicationID string,
 		"success":       false,
 		"applicationId": applicationID,
 		"error":         err.Error(),
+		"retryable":     IsRetryableError(err),
 		"riskAssessment": map[string]interface{}{
 			"overallRiskLevel":  string(domain.RiskCritical),
 			"recommendedAction": "manual_review_required",
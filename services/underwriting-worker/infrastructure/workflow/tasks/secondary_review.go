@@ -0,0 +1,142 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"underwriting_worker/domain"
+)
+
+// Secondary review outcomes of require_secondary_review, as the workflow branches on them
+const (
+	secondaryReviewRequired    = "REQUIRED"
+	secondaryReviewNotRequired = "NOT_REQUIRED"
+)
+
+// defaultSecondaryReviewDue is the time the second underwriter has when none is configured
+const defaultSecondaryReviewDue = 8 * time.Hour
+
+// handleSecondaryReviewRequirement queues an approval above secondary_review.approval_amount for a
+// second underwriter's sign-off. The loan API has an underwriter other than the first approver
+// (approvedBy, empty for an automated approval) sign it off, which completes the workflow's waiting
+// human task (taskReferenceName, secondary_review_ref by default) with APPROVE or DENY. The output's
+// secondaryReview is REQUIRED when the workflow must wait for the sign-off and NOT_REQUIRED
+// otherwise.
+func (w *UnderwritingTaskWorker) handleSecondaryReviewRequirement(ctx context.Context, input map[string]interface{}) (map[string]interface{}, error) {
+	logger := w.logger.With(zap.String("operation", "require_secondary_review"))
+
+	applicationID, ok := input["applicationId"].(string)
+	if !ok || applicationID == "" {
+		return nil, fmt.Errorf("application ID is required")
+	}
+	logger = logger.With(zap.String("application_id", applicationID))
+
+	approvedAmount, _ := input["approvedAmount"].(float64)
+	limit := w.config.SecondaryReview.ApprovalAmount
+	if !w.requiresSecondaryReview(approvedAmount) {
+		logger.Info("Approval within the single approver limit",
+			zap.Float64("approved_amount", approvedAmount),
+			zap.Float64("approval_limit", limit))
+		return map[string]interface{}{
+			"secondaryReview": secondaryReviewNotRequired,
+			"approvedAmount":  approvedAmount,
+			"approvalLimit":   limit,
+		}, nil
+	}
+
+	workflowInstanceID, ok := input["workflowInstanceId"].(string)
+	if !ok || workflowInstanceID == "" {
+		return nil, fmt.Errorf("workflow instance ID is required")
+	}
+	if w.manualReviews == nil {
+		return nil, fmt.Errorf("manual review queue is not configured")
+	}
+
+	taskReferenceName, _ := input["taskReferenceName"].(string)
+	if taskReferenceName == "" {
+		taskReferenceName = "secondary_review_ref"
+	}
+	approvedBy, _ := input["approvedBy"].(string)
+	firstApprover := approvedBy
+	if firstApprover == "" {
+		firstApprover = "automated underwriting"
+	}
+
+	due := time.Duration(w.config.SecondaryReview.DueHours) * time.Hour
+	if due <= 0 {
+		due = defaultSecondaryReviewDue
+	}
+	now := time.Now().UTC()
+	review := &domain.ManualReview{
+		ApplicationID:      applicationID,
+		WorkflowInstanceID: workflowInstanceID,
+		TaskReferenceName:  taskReferenceName,
+		Reason:             fmt.Sprintf("Approval of $%.2f exceeds the $%.2f single approver limit; approved by %s", approvedAmount, limit, firstApprover),
+		Priority:           "high",
+		ReviewType:         domain.ManualReviewSecondary,
+		PrimaryReviewerID:  approvedBy,
+		DueAt:              now.Add(due),
+		CreatedAt:          now,
+	}
+	existing, created, err := w.manualReviews.Enqueue(ctx, review)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		review = existing
+	}
+
+	logger.Info("Secondary review queued",
+		zap.String("review_id", review.ID),
+		zap.Float64("approved_amount", approvedAmount),
+		zap.String("primary_reviewer_id", approvedBy),
+		zap.Bool("already_queued", !created))
+
+	return map[string]interface{}{
+		"secondaryReview": secondaryReviewRequired,
+		"reviewId":        review.ID,
+		"reviewStatus":    string(review.Status),
+		"approvedAmount":  approvedAmount,
+		"approvalLimit":   limit,
+		"dueDate":         review.DueAt.Format(time.RFC3339),
+	}, nil
+}
+
+// requiresSecondaryReview reports whether an approval of the amount needs a second underwriter
+func (w *UnderwritingTaskWorker) requiresSecondaryReview(approvedAmount float64) bool {
+	limit := w.config.SecondaryReview.ApprovalAmount
+	return limit > 0 && approvedAmount > limit
+}
+
+// requireSecondaryApproval checks that an approval needing a second underwriter was signed off,
+// so final_approval cannot complete a large loan on one underwriter's word. It returns the signed
+// off review, or nil when the approval needs none.
+func (w *UnderwritingTaskWorker) requireSecondaryApproval(ctx context.Context, input map[string]interface{}, approvedAmount float64) (*domain.ManualReview, error) {
+	if !w.requiresSecondaryReview(approvedAmount) {
+		return nil, nil
+	}
+
+	workflowInstanceID, _ := input["workflowInstanceId"].(string)
+	if workflowInstanceID == "" {
+		return nil, fmt.Errorf("approval of $%.2f requires a second underwriter's sign-off and no workflow instance ID was given", approvedAmount)
+	}
+	if w.manualReviews == nil {
+		return nil, fmt.Errorf("manual review queue is not configured")
+	}
+	taskReferenceName, _ := input["secondaryReviewTaskReferenceName"].(string)
+	if taskReferenceName == "" {
+		taskReferenceName = "secondary_review_ref"
+	}
+
+	review, err := w.manualReviews.GetByTask(ctx, workflowInstanceID, taskReferenceName)
+	if err != nil {
+		return nil, fmt.Errorf("approval of $%.2f requires a second underwriter's sign-off: %w", approvedAmount, err)
+	}
+	if review.ReviewType != domain.ManualReviewSecondary || review.Status != domain.ManualReviewCompleted || review.Decision != "APPROVE" {
+		return nil, fmt.Errorf("approval of $%.2f requires a second underwriter's sign-off; secondary review %s is %s", approvedAmount, review.ID, review.Status)
+	}
+	return review, nil
+}
@@ -2,12 +2,15 @@ package tasks
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/huuhoait/los-demo/services/shared/pkg/dti"
+
 	"underwriting_worker/application/usecases"
 	"underwriting_worker/domain"
 )
@@ -21,8 +24,9 @@ type UnderwritingDecisionTaskHandler struct {
 	riskAssessmentRepo     domain.RiskAssessmentRepository
 	incomeVerificationRepo domain.IncomeVerificationRepository
 	underwritingResultRepo domain.UnderwritingResultRepository
-	underwritingPolicyRepo domain.UnderwritingPolicyRepository
+	underwritingPolicyRepo domain.UnderwritingPolicyProvider
 	decisionEngineService  domain.DecisionEngineService
+	dti                    *dti.Calculator
 }
 
 // NewUnderwritingDecisionTaskHandler creates a new underwriting decision task handler
@@ -34,8 +38,9 @@ func NewUnderwritingDecisionTaskHandler(
 	riskAssessmentRepo domain.RiskAssessmentRepository,
 	incomeVerificationRepo domain.IncomeVerificationRepository,
 	underwritingResultRepo domain.UnderwritingResultRepository,
-	underwritingPolicyRepo domain.UnderwritingPolicyRepository,
+	underwritingPolicyRepo domain.UnderwritingPolicyProvider,
 	decisionEngineService domain.DecisionEngineService,
+	dtiCalculator *dti.Calculator,
 ) *UnderwritingDecisionTaskHandler {
 	return &UnderwritingDecisionTaskHandler{
 		logger:                 logger,
@@ -47,6 +52,7 @@ func NewUnderwritingDecisionTaskHandler(
 		underwritingResultRepo: underwritingResultRepo,
 		underwritingPolicyRepo: underwritingPolicyRepo,
 		decisionEngineService:  decisionEngineService,
+		dti:                    dtiCalculator,
 	}
 }
 
@@ -118,6 +124,11 @@ func (h *UnderwritingDecisionTaskHandler) gatherUnderwritingData(ctx context.Con
 	*domain.UnderwritingPolicy,
 	error,
 ) {
+	if h.loanApplicationRepo == nil || h.creditReportRepo == nil || h.riskAssessmentRepo == nil ||
+		h.incomeVerificationRepo == nil || h.underwritingResultRepo == nil {
+		return nil, nil, nil, nil, nil, fmt.Errorf("underwriting database not available")
+	}
+
 	// Get loan application
 	application, err := h.loanApplicationRepo.GetByID(ctx, applicationID)
 	if err != nil {
@@ -180,7 +191,12 @@ func (h *UnderwritingDecisionTaskHandler) makeUnderwritingDecision(
 	if h.decisionEngineService != nil && h.decisionEngineService.IsAvailable(ctx) {
 		decisionResponse, err = h.decisionEngineService.MakeDecision(ctx, request)
 		if err != nil {
-			h.logger.Warn("Decision engine service failed, using built-in logic", zap.Error(err))
+			fields := []zap.Field{zap.Error(err)}
+			var engineErr *DecisionEngineError
+			if errors.As(err, &engineErr) {
+				fields = append(fields, zap.String("code", engineErr.Code), zap.Bool("retryable", engineErr.Retryable))
+			}
+			h.logger.Warn("Decision engine service failed, using built-in logic", fields...)
 			decisionResponse = h.makeBuiltInDecision(application, creditReport, riskAssessment, incomeVerification, policy)
 		}
 	} else {
@@ -213,12 +229,71 @@ func (h *UnderwritingDecisionTaskHandler) makeUnderwritingDecision(
 		UpdatedAt:            time.Now(),
 	}
 
+	if result.DecisionData == nil {
+		result.DecisionData = map[string]interface{}{}
+	}
+	if len(decisionResponse.Stages) > 0 {
+		result.DecisionData["stages"] = decisionResponse.Stages
+	}
+
+	// A hard knockout is a final denial: no pricing, counteroffer or manual review
+	if decisionResponse.KnockedOut() {
+		result.Decision = domain.DecisionDenied
+		result.ApprovedAmount = 0
+		result.CounterOfferTerms = nil
+		result.ManualReviewRequired = false
+		result.AutomatedDecision = true
+		result.DecisionData["knocked_out"] = true
+		return result, nil
+	}
+
+	// Price from the decision engine when it priced the decision, so the result matches the offers
+	if pricing := decisionResponse.Pricing; pricing != nil {
+		result.InterestRate = pricing.Rate
+		result.APR = pricing.APR
+		result.DecisionData["pricing"] = pricing
+	}
+
 	// Calculate financial details
 	h.calculateFinancialDetails(result)
 
 	return result, nil
 }
 
+// decideTerms has the decision engine decide and price an application on terms other than those
+// applied for, as a borrower's counter offer request or accepted counter offer. There is no
+// built-in fallback: terms the engine has not priced are never offered.
+func (h *UnderwritingDecisionTaskHandler) decideTerms(ctx context.Context, applicationID string, amount float64, termMonths int) (
+	*domain.LoanApplication,
+	*domain.DecisionResponse,
+	error,
+) {
+	if h.decisionEngineService == nil {
+		return nil, nil, fmt.Errorf("decision engine is not configured")
+	}
+
+	application, creditReport, riskAssessment, incomeVerification, policy, err := h.gatherUnderwritingData(ctx, applicationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decision, err := h.decisionEngineService.MakeDecision(ctx, &domain.DecisionRequest{
+		ApplicationID:      application.ID,
+		LoanApplication:    application,
+		CreditReport:       creditReport,
+		RiskAssessment:     riskAssessment,
+		IncomeVerification: incomeVerification,
+		Policy:             policy,
+		RequestedAmount:    amount,
+		RequestedTerm:      termMonths,
+		Purpose:            application.LoanPurpose,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("decision engine failed: %w", err)
+	}
+	return application, decision, nil
+}
+
 // makeBuiltInDecision makes a decision using built-in logic when external service is unavailable
 func (h *UnderwritingDecisionTaskHandler) makeBuiltInDecision(
 	application *domain.LoanApplication,
@@ -249,6 +324,10 @@ func (h *UnderwritingDecisionTaskHandler) makeBuiltInDecision(
 	if !policyCheck.Compliant {
 		response.Decision = domain.DecisionDenied
 		response.Reasons = h.convertPolicyViolationsToReasons(policyCheck.Violations)
+		response.Stages = []domain.DecisionStage{
+			{Stage: domain.StageKnockout, Status: domain.StageStatusKnockedOut, Decision: string(domain.DecisionDenied)},
+			{Stage: domain.StageScoring, Status: domain.StageStatusSkipped},
+		}
 		return response
 	}
 
@@ -264,6 +343,11 @@ func (h *UnderwritingDecisionTaskHandler) makeBuiltInDecision(
 		response = h.makeDenialDecision(application, creditReport, riskAssessment, policy)
 	}
 
+	response.Stages = []domain.DecisionStage{
+		{Stage: domain.StageKnockout, Status: domain.StageStatusPassed},
+		{Stage: domain.StageScoring, Status: domain.StageStatusCompleted, Decision: string(response.Decision)},
+	}
+
 	// Apply income verification requirements
 	if incomeVerification.VerificationStatus != domain.IncomeVerified {
 		response.ManualReviewRequired = true
@@ -275,6 +359,18 @@ func (h *UnderwritingDecisionTaskHandler) makeBuiltInDecision(
 			Status:        "pending",
 			DueDate:       time.Now().Add(7 * 24 * time.Hour),
 		})
+	} else if incomeVerification.IncomeVarianceFlagged {
+		// Verified income differs from stated income by more than the tolerance
+		response.ManualReviewRequired = true
+		response.Conditions = append(response.Conditions, domain.UnderwritingCondition{
+			ConditionID:   "income_variance_review",
+			ConditionType: "prior_to_closing",
+			Description: fmt.Sprintf("Verified income $%.0f differs from stated income $%.0f by %.1f%%",
+				incomeVerification.VerifiedAnnualIncome, incomeVerification.StatedAnnualIncome, incomeVerification.IncomeVariancePercent),
+			Priority: "high",
+			Status:   "pending",
+			DueDate:  time.Now().Add(3 * 24 * time.Hour),
+		})
 	}
 
 	return response
@@ -304,22 +400,26 @@ func (h *UnderwritingDecisionTaskHandler) checkPolicyCompliance(
 	}
 
 	// Check maximum DTI ratio
-	dti := application.CalculateDTI()
-	if dti > policy.MaxDTIRatio {
+	dtiRatio := application.CalculateDTI(h.dti)
+	if dtiRatio > policy.MaxDTIRatio {
 		result.Compliant = false
 		result.Violations = append(result.Violations, PolicyViolation{
 			RuleID:      "max_dti_ratio",
-			Description: fmt.Sprintf("DTI ratio %.1f%% exceeds maximum %.1f%%", dti*100, policy.MaxDTIRatio*100),
+			Description: fmt.Sprintf("DTI ratio %.1f%% exceeds maximum %.1f%%", dtiRatio*100, policy.MaxDTIRatio*100),
 			Severity:    "critical",
 		})
 	}
 
-	// Check minimum annual income
-	if application.AnnualIncome < policy.MinAnnualIncome {
+	// Check minimum annual income, using the verified income once income is verified
+	annualIncome := application.AnnualIncome
+	if incomeVerification.VerificationStatus == domain.IncomeVerified && incomeVerification.VerifiedAnnualIncome > 0 {
+		annualIncome = incomeVerification.VerifiedAnnualIncome
+	}
+	if annualIncome < policy.MinAnnualIncome {
 		result.Compliant = false
 		result.Violations = append(result.Violations, PolicyViolation{
 			RuleID:      "min_annual_income",
-			Description: fmt.Sprintf("Annual income $%.0f below minimum $%.0f", application.AnnualIncome, policy.MinAnnualIncome),
+			Description: fmt.Sprintf("Annual income $%.0f below minimum $%.0f", annualIncome, policy.MinAnnualIncome),
 			Severity:    "critical",
 		})
 	}
@@ -614,6 +714,8 @@ func (h *UnderwritingDecisionTaskHandler) createSuccessResponse(
 			"policyVersion":        result.PolicyVersion,
 			"modelVersion":         result.ModelVersion,
 		},
+		"knockedOut":      result.DecisionData["knocked_out"] == true,
+		"stages":          result.DecisionData["stages"],
 		"conditions":      h.formatConditions(result.Conditions),
 		"decisionReasons": h.formatDecisionReasons(result.DecisionReasons),
 		"counterOffer":    h.formatCounterOffer(result.CounterOfferTerms),
@@ -623,7 +725,7 @@ func (h *UnderwritingDecisionTaskHandler) createSuccessResponse(
 			"creditScore":     creditReport.CreditScore,
 			"riskLevel":       string(riskAssessment.OverallRiskLevel),
 			"incomeVerified":  incomeVerification.VerificationStatus == domain.IncomeVerified,
-			"dtiRatio":        application.CalculateDTI(),
+			"dtiRatio":        application.CalculateDTI(h.dti),
 		},
 		"processingTime": result.ProcessingTime.String(),
 		"completedAt":    time.Now().UTC().Format(time.RFC3339),
@@ -635,6 +737,7 @@ func (h *UnderwritingDecisionTaskHandler) createFailureResponse(applicationID st
 		"success":       false,
 		"applicationId": applicationID,
 		"error":         err.Error(),
+		"retryable":     IsRetryableError(err),
 		"underwritingResult": map[string]interface{}{
 			"decision":             string(domain.DecisionManualReview),
 			"manualReviewRequired": true,
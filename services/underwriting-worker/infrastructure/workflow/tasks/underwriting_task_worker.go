@@ -2,12 +2,22 @@ package tasks
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
+	"os"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/huuhoait/los-demo/services/shared/pkg/config"
+	"github.com/huuhoait/los-demo/services/shared/pkg/dti"
+	"github.com/huuhoait/los-demo/services/shared/pkg/payroll"
+
+	"underwriting_worker/application/services"
+	"underwriting_worker/domain"
+	"underwriting_worker/infrastructure/database/postgres"
 )
 
 // UnderwritingTaskWorker handles all underwriting-related workflow tasks
@@ -17,6 +27,26 @@ type UnderwritingTaskWorker struct {
 	conductorClient               *HTTPConductorClient
 	mockConductorClient           *MockConductorClient
 	useMockConductor              bool
+	policyClient                  *PolicyClient
+	decisionEngineClient          *DecisionEngineClient
+	loanService                   *LoanServiceClient
+	fraudDetection                domain.FraudDetectionService
+	database                      *postgres.Factory
+	taskExecutions                domain.TaskExecutionRepository
+	taskRuns                      domain.TaskRunRepository
+	instanceID                    string // host the worker runs on, prefixed to polling worker IDs
+	retryPolicies                 *RetryPolicies
+	conductorRetries              map[string]int // retries of each task type by Conductor
+	definitions                   *Definitions   // registered with Conductor at startup
+	deadLetters                   domain.DeadLetterRepository
+	manualReviews                 domain.ManualReviewRepository
+	conditions                    domain.ConditionRepository
+	applications                  domain.LoanApplicationRepository
+	underwritingResults           domain.UnderwritingResultRepository
+	complianceRecords             domain.ComplianceRecordRepository
+	applicantDemographics         domain.ApplicantDemographicsRepository
+	fraudChecks                   *services.FraudCheckService
+	dti                           *dti.Calculator
 	creditCheckHandler            *CreditCheckTaskHandler
 	incomeVerificationHandler     *IncomeVerificationTaskHandler
 	riskAssessmentHandler         *RiskAssessmentTaskHandler
@@ -41,9 +71,65 @@ func NewUnderwritingTaskWorker(logger *zap.Logger, cfg *config.BaseConfig) *Unde
 	worker := &UnderwritingTaskWorker{
 		logger:              logger,
 		config:              cfg,
+		dti:                 dti.NewCalculator(cfg.DTI),
 		conductorClient:     httpConductorClient,
 		mockConductorClient: mockConductorClient,
 		useMockConductor:    useMockConductor,
+		retryPolicies:       NewRetryPolicies(cfg.Conductor),
+		conductorRetries:    map[string]int{},
+		instanceID:          "underwriting-worker",
+	}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		worker.instanceID = hostname
+	}
+	if httpConductorClient != nil {
+		worker.definitions = &Definitions{
+			Workflows: []*WorkflowDefinition{httpConductorClient.CreateUnderwritingWorkflowDefinition()},
+			Tasks:     httpConductorClient.CreateTaskDefinitions(),
+		}
+	}
+
+	// Underwriting reads applications and borrowers from the loan service database and keeps its
+	// own records there
+	dbConnection, err := postgres.NewConnection(&postgres.Config{
+		Host:            cfg.Database.Host,
+		Port:            fmt.Sprintf("%d", cfg.Database.Port),
+		User:            cfg.Database.User,
+		Password:        cfg.Database.Password,
+		Database:        cfg.Database.Name,
+		SSLMode:         cfg.Database.SSLMode,
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+	}, logger)
+	if err != nil {
+		logger.Warn("Failed to initialize database connection, task handlers will use mock data", zap.Error(err))
+	} else {
+		worker.database = postgres.NewFactory(dbConnection, logger)
+	}
+
+	// Take underwriting policies and decisions from the decision engine, where policies are approved
+	if cfg.Services.DecisionEngine.BaseURL != "" {
+		var (
+			policyStore domain.UnderwritingPolicyRepository
+			policyPins  domain.PolicyPinRepository
+		)
+		if worker.database != nil {
+			policyStore = worker.database.GetUnderwritingPolicyRepository()
+			policyPins = worker.database.GetPolicyPinRepository()
+		}
+		worker.policyClient = NewPolicyClient(logger.With(zap.String("component", "policy_client")), cfg.Services.DecisionEngine, policyStore, policyPins)
+		worker.decisionEngineClient = NewDecisionEngineClient(logger.With(zap.String("component", "decision_engine_client")), cfg.Services.DecisionEngine)
+		worker.fraudDetection = worker.decisionEngineClient
+	} else {
+		logger.Warn("Decision engine URL not configured, underwriting policies will not be loaded and decisions will use built-in logic")
+	}
+
+	// Decision letters are generated, stored and sent to the borrower by the loan service
+	if cfg.Services.LoanService.BaseURL != "" {
+		worker.loanService = NewLoanServiceClient(logger.With(zap.String("component", "loan_service_client")), cfg.Services.LoanService)
+	} else {
+		logger.Warn("Loan service URL not configured, decision letters will not be sent")
 	}
 
 	// Initialize task handlers
@@ -54,53 +140,118 @@ func NewUnderwritingTaskWorker(logger *zap.Logger, cfg *config.BaseConfig) *Unde
 
 // initializeTaskHandlers initializes all task handlers
 func (w *UnderwritingTaskWorker) initializeTaskHandlers() {
-	// Note: In a real implementation, these handlers would be initialized with actual repositories and services
-	// For this example, we'll create mock implementations
-
 	w.logger.Info("Initializing underwriting task handlers")
 
-	// Initialize handlers with mock dependencies
-	// In a real implementation, these would be properly injected
+	// Handlers fall back to mock data or built-in logic for any dependency left nil, so only set
+	// the ones that are actually available
+	var (
+		loanApplicationRepo    domain.LoanApplicationRepository
+		creditReportRepo       domain.CreditReportRepository
+		riskAssessmentRepo     domain.RiskAssessmentRepository
+		incomeVerificationRepo domain.IncomeVerificationRepository
+		underwritingResultRepo domain.UnderwritingResultRepository
+		creditService          *services.CreditService
+		riskScoringService     domain.RiskScoringService
+		incomeService          domain.IncomeVerificationService
+		fraudSignals           domain.FraudSignalRepository
+		fraudReports           domain.FraudReportRepository
+	)
+	if w.database != nil {
+		loanApplicationRepo = w.database.GetLoanApplicationRepository()
+		creditReportRepo = w.database.GetCreditReportRepository()
+		riskAssessmentRepo = w.database.GetRiskAssessmentRepository()
+		incomeVerificationRepo = w.database.GetIncomeVerificationRepository()
+		underwritingResultRepo = w.database.GetUnderwritingResultRepository()
+		w.taskExecutions = w.database.GetTaskExecutionRepository()
+		w.taskRuns = w.database.GetTaskRunRepository()
+		w.deadLetters = w.database.GetDeadLetterRepository()
+		w.manualReviews = w.database.GetManualReviewRepository()
+		w.conditions = w.database.GetConditionRepository()
+		w.applications = loanApplicationRepo
+		w.underwritingResults = underwritingResultRepo
+		w.complianceRecords = w.database.GetComplianceRecordRepository()
+		w.applicantDemographics = w.database.GetApplicantDemographicsRepository()
+		fraudSignals = w.database.GetFraudSignalRepository()
+		fraudReports = w.database.GetFraudReportRepository()
+		borrowers := w.database.GetBorrowerRepository()
+
+		// Income is verified from the borrower's payroll account when an aggregator is configured
+		incomeService = services.NewIncomeVerificationService(
+			w.logger.With(zap.String("service", "income_verification")), borrowers,
+			payroll.NewProvider(w.config.Payroll, w.logger.With(zap.String("component", "payroll_client"))),
+			incomeVerificationRepo, w.config.Payroll.VarianceTolerance)
+
+		// Credit is pulled through the decision engine, which holds the bureau connections
+		if w.decisionEngineClient != nil {
+			creditBureau := NewCreditBureauClient(w.decisionEngineClient, borrowers, loanApplicationRepo,
+				w.logger.With(zap.String("component", "credit_bureau_client")))
+			creditService = services.NewCreditService(
+				w.logger.With(zap.String("service", "credit")),
+				creditReportRepo, creditBureau, w.database.GetAuditRepository())
+			riskScoringService = services.NewRiskScoringService(
+				w.logger.With(zap.String("service", "risk_scoring")),
+				creditService, w.fraudDetection, w.dti)
+		}
+	}
+
+	// Fraud checks read the loan service's identity records and add the decision engine's fraud
+	// vendor screening when it is configured
+	w.fraudChecks = services.NewFraudCheckService(
+		w.logger.With(zap.String("service", "fraud_checks")),
+		fraudSignals, creditReportRepo, fraudReports, w.fraudDetection)
+
+	var policies domain.UnderwritingPolicyProvider
+	if w.policyClient != nil {
+		policies = w.policyClient
+	}
+	var decisionEngine domain.DecisionEngineService
+	if w.decisionEngineClient != nil {
+		decisionEngine = w.decisionEngineClient
+	}
+
+	// The handlers run each underwriting step themselves, so they are not given the use case
 	w.creditCheckHandler = NewCreditCheckTaskHandler(
 		w.logger.With(zap.String("handler", "credit_check")),
-		nil, // creditService - would be injected
-		nil, // underwritingUseCase - would be injected
-		nil, // loanApplicationRepo - would be injected
-		nil, // creditReportRepo - would be injected
+		creditService,
+		nil,
+		loanApplicationRepo,
+		creditReportRepo,
 	)
 
 	w.incomeVerificationHandler = NewIncomeVerificationTaskHandler(
 		w.logger.With(zap.String("handler", "income_verification")),
-		nil, // underwritingUseCase - would be injected
-		nil, // loanApplicationRepo - would be injected
-		nil, // incomeVerificationRepo - would be injected
-		nil, // incomeVerificationService - would be injected
+		nil,
+		loanApplicationRepo,
+		incomeVerificationRepo,
+		incomeService,
 	)
 
 	w.riskAssessmentHandler = NewRiskAssessmentTaskHandler(
 		w.logger.With(zap.String("handler", "risk_assessment")),
-		nil, // underwritingUseCase - would be injected
-		nil, // loanApplicationRepo - would be injected
-		nil, // creditReportRepo - would be injected
-		nil, // riskAssessmentRepo - would be injected
-		nil, // riskScoringService - would be injected
+		nil,
+		loanApplicationRepo,
+		creditReportRepo,
+		riskAssessmentRepo,
+		riskScoringService,
+		w.dti,
 	)
 
 	w.underwritingDecisionHandler = NewUnderwritingDecisionTaskHandler(
 		w.logger.With(zap.String("handler", "underwriting_decision")),
-		nil, // underwritingUseCase - would be injected
-		nil, // loanApplicationRepo - would be injected
-		nil, // creditReportRepo - would be injected
-		nil, // riskAssessmentRepo - would be injected
-		nil, // incomeVerificationRepo - would be injected
-		nil, // underwritingResultRepo - would be injected
-		nil, // underwritingPolicyRepo - would be injected
-		nil, // decisionEngineService - would be injected
+		nil,
+		loanApplicationRepo,
+		creditReportRepo,
+		riskAssessmentRepo,
+		incomeVerificationRepo,
+		underwritingResultRepo,
+		policies,
+		decisionEngine,
+		w.dti,
 	)
 
 	w.updateApplicationStateHandler = NewUpdateApplicationStateTaskHandler(
 		w.logger.With(zap.String("handler", "update_application_state")),
-		nil, // loanApplicationRepo - would be injected
+		loanApplicationRepo,
 	)
 
 	w.logger.Info("All underwriting task handlers initialized successfully")
@@ -119,6 +270,31 @@ func (w *UnderwritingTaskWorker) Start(ctx context.Context) error {
 		zap.Int("worker_pool_size", w.config.Conductor.WorkerPoolSize),
 		zap.Int("polling_interval_ms", w.config.Conductor.PollingInterval))
 
+	// Load the underwriting policy in force and keep it current
+	if w.policyClient != nil {
+		if err := w.policyClient.Refresh(ctx); err != nil {
+			w.logger.Warn("Failed to load underwriting policy, will retry in the background", zap.Error(err))
+		}
+		refreshInterval := time.Duration(w.config.UnderwritingPolicy.RefreshInterval) * time.Second
+		if refreshInterval <= 0 {
+			refreshInterval = time.Minute
+		}
+		go w.policyClient.Start(ctx, refreshInterval)
+
+		// Switch as soon as any worker instance puts a new version in force
+		if w.config.UnderwritingPolicy.ListenForChanges && w.database != nil {
+			if err := w.database.ListenForPolicyChanges(ctx, func(version string) {
+				w.policyClient.OnPolicyChanged(ctx, version)
+			}); err != nil {
+				w.logger.Warn("Failed to listen for underwriting policy changes, relying on refreshes", zap.Error(err))
+			}
+		}
+	}
+
+	if err := w.loadDefinitions(); err != nil {
+		return fmt.Errorf("failed to load workflow definitions: %w", err)
+	}
+
 	// Register underwriting workflow tasks
 	w.registerUnderwritingTasks()
 
@@ -142,6 +318,51 @@ func (w *UnderwritingTaskWorker) Start(ctx context.Context) error {
 	return w.conductorClient.StartPolling()
 }
 
+// loadDefinitions replaces the built-in workflow and task definitions with those in
+// conductor.definitions_dir when it is set, so workflow edits are deployed without recompiling,
+// and takes the number of times Conductor retries each task type from them
+func (w *UnderwritingTaskWorker) loadDefinitions() error {
+	if dir := w.config.Conductor.DefinitionsDir; dir != "" {
+		definitions, err := LoadDefinitions(dir)
+		if err != nil {
+			return err
+		}
+		w.definitions = definitions
+		w.logger.Info("Loaded workflow definitions",
+			zap.String("definitions_dir", dir),
+			zap.Int("workflows", len(definitions.Workflows)),
+			zap.Int("tasks", len(definitions.Tasks)))
+	}
+
+	if w.definitions != nil {
+		for _, taskDef := range w.definitions.Tasks {
+			w.conductorRetries[taskDef.Name] = taskDef.RetryCount
+		}
+	}
+	return nil
+}
+
+// registerTaskDefinition registers a task definition with Conductor. Definitions loaded from files
+// are the source of truth, so a changed one replaces Conductor's; the built-in ones are only
+// registered when missing, leaving definitions deployed with the loan service in place.
+func (w *UnderwritingTaskWorker) registerTaskDefinition(taskDef *TaskDefinition) error {
+	if w.config.Conductor.DefinitionsDir == "" {
+		return w.conductorClient.RegisterTaskDefinition(taskDef)
+	}
+	_, err := w.conductorClient.SyncTaskDefinition(taskDef)
+	return err
+}
+
+// registerWorkflowDefinition registers a workflow definition with Conductor, like
+// registerTaskDefinition
+func (w *UnderwritingTaskWorker) registerWorkflowDefinition(workflow *WorkflowDefinition) error {
+	if w.config.Conductor.DefinitionsDir == "" {
+		return w.conductorClient.RegisterWorkflowDefinition(workflow)
+	}
+	_, err := w.conductorClient.SyncWorkflowDefinition(workflow)
+	return err
+}
+
 // registerTaskDefinitionsOnly registers only the task definitions (without workflow)
 func (w *UnderwritingTaskWorker) registerTaskDefinitionsOnly() error {
 	if w.conductorClient == nil {
@@ -151,12 +372,12 @@ func (w *UnderwritingTaskWorker) registerTaskDefinitionsOnly() error {
 	w.logger.Info("Registering task definitions only with Conductor")
 
 	// Register task definitions
-	taskDefs := w.conductorClient.CreateTaskDefinitions()
+	taskDefs := w.definitions.Tasks
 	successfulRegistrations := 0
 	totalTasks := len(taskDefs)
 
 	for _, taskDef := range taskDefs {
-		if err := w.conductorClient.RegisterTaskDefinition(taskDef); err != nil {
+		if err := w.registerTaskDefinition(taskDef); err != nil {
 			w.logger.Error("Failed to register task definition",
 				zap.String("task_name", taskDef.Name),
 				zap.Error(err))
@@ -192,7 +413,16 @@ func (w *UnderwritingTaskWorker) Stop(ctx context.Context) error {
 	if w.useMockConductor {
 		w.mockConductorClient.StopPolling()
 	} else {
-		w.conductorClient.StopPolling()
+		// Let running tasks finish, or hand them back to Conductor, before their database goes away
+		if released := w.conductorClient.Drain(ctx); released > 0 {
+			w.logger.Warn("Stopped with tasks still running", zap.Int("released", released))
+		}
+	}
+
+	if w.database != nil {
+		if err := w.database.Close(); err != nil {
+			w.logger.Error("Failed to close database connection", zap.Error(err))
+		}
 	}
 
 	return nil
@@ -261,10 +491,53 @@ func (w *UnderwritingTaskWorker) registerAdditionalUnderwritingTasks() {
 	// Register counter offer task
 	w.registerWorker("generate_counter_offer", w.wrapTaskHandler("generate_counter_offer", w.handleCounterOfferGeneration))
 	w.logger.Info("Registered task: generate_counter_offer")
+
+	// Register accepted counter offer task
+	w.registerWorker("finalize_counter_offer", w.wrapTaskHandler("finalize_counter_offer", w.handleCounterOfferFinalization))
+	w.logger.Info("Registered task: finalize_counter_offer")
+
+	// Register compliance data capture task
+	w.registerWorker("capture_compliance_data", w.wrapTaskHandler("capture_compliance_data", w.handleComplianceCapture))
+	w.logger.Info("Registered task: capture_compliance_data")
+
+	// Register decision letter task
+	w.registerWorker("generate_decision_letter", w.wrapTaskHandler("generate_decision_letter", w.handleDecisionLetter))
+	w.logger.Info("Registered task: generate_decision_letter")
+
+	// Register secondary review task
+	w.registerWorker("require_secondary_review", w.wrapTaskHandler("require_secondary_review", w.handleSecondaryReviewRequirement))
+	w.logger.Info("Registered task: require_secondary_review")
+
+	// Register re-underwriting invalidation task
+	w.registerWorker("invalidate_underwriting_result", w.wrapTaskHandler("invalidate_underwriting_result", w.handleUnderwritingResultInvalidation))
+	w.logger.Info("Registered task: invalidate_underwriting_result")
+
+	// Register failed workflow compensation tasks
+	w.registerWorker("rollback_application_state", w.wrapTaskHandler("rollback_application_state", w.handleStateRollback))
+	w.logger.Info("Registered task: rollback_application_state")
+
+	w.registerWorker("void_workflow_offers", w.wrapTaskHandler("void_workflow_offers", w.handleOfferVoiding))
+	w.logger.Info("Registered task: void_workflow_offers")
+
+	w.registerWorker("notify_borrower_of_failure", w.wrapTaskHandler("notify_borrower_of_failure", w.handleFailureNotification))
+	w.logger.Info("Registered task: notify_borrower_of_failure")
 }
 
-// wrapTaskHandler wraps a task handler with common logging and error handling
+// wrapTaskHandler wraps a task handler with common logging and error handling, and records every
+// run of the task in the task run history
 func (w *UnderwritingTaskWorker) wrapTaskHandler(taskName string, handler func(context.Context, map[string]interface{}) (map[string]interface{}, error)) TaskHandler {
+	run := w.runTaskHandler(taskName, handler)
+	return func(task *MockTask) (*MockTaskResult, error) {
+		startedAt := time.Now().UTC()
+		result, err := run(task)
+		w.recordTaskRun(taskName, task, result, startedAt)
+		return result, err
+	}
+}
+
+// runTaskHandler runs a task handler, answering redelivered tasks, retrying transient failures
+// and dead-lettering tasks that failed for good
+func (w *UnderwritingTaskWorker) runTaskHandler(taskName string, handler func(context.Context, map[string]interface{}) (map[string]interface{}, error)) TaskHandler {
 	return func(task *MockTask) (*MockTaskResult, error) {
 		startTime := time.Now()
 		logger := w.logger.With(
@@ -296,33 +569,107 @@ func (w *UnderwritingTaskWorker) wrapTaskHandler(taskName string, handler func(c
 			}, nil
 		}
 
-		// Execute the task handler
-		ctx := context.Background()
-		outputData, err := handler(ctx, task.InputData)
+		ctx := withTaskScope(context.Background(), taskName, task.WorkflowInstanceID)
+
+		// Answer a task delivered again with the output of the execution that already completed
+		idempotencyKey := taskIdempotencyKey(taskName, task)
+		if w.taskExecutions != nil && idempotencyKey != "" {
+			applicationID, _ := task.InputData["applicationId"].(string)
+			existing, claimed, err := w.taskExecutions.Claim(ctx, &domain.TaskExecution{
+				IdempotencyKey:     idempotencyKey,
+				TaskID:             task.TaskID,
+				TaskType:           taskName,
+				ApplicationID:      applicationID,
+				WorkflowInstanceID: task.WorkflowInstanceID,
+			}, taskExecutionLease)
+			if err != nil {
+				// Running without the claim risks a duplicate; failing lets Conductor retry
+				logger.Error("Failed to claim task execution", zap.Error(err))
+				return w.failedTaskResult(task, startTime, "failed to claim task execution: "+err.Error()), nil
+			}
+			if !claimed {
+				if existing.Status == domain.TaskExecutionCompleted {
+					logger.Info("Task already completed, returning stored output",
+						zap.String("completed_task_id", existing.TaskID))
+					output := make(map[string]interface{}, len(existing.Output)+1)
+					for k, v := range existing.Output {
+						output[k] = v
+					}
+					output["deduplicated"] = true
+					return &MockTaskResult{
+						TaskID:        task.TaskID,
+						Status:        "COMPLETED",
+						OutputData:    output,
+						WorkerID:      fmt.Sprintf("underwriting-worker-%d", time.Now().Unix()),
+						CompletedTime: time.Now(),
+					}, nil
+				}
+				logger.Warn("Task is already running, deferring to the running execution",
+					zap.String("running_task_id", existing.TaskID))
+				return w.failedTaskResult(task, startTime, "task already running as "+existing.TaskID), nil
+			}
+		}
+
+		// Execute the task handler, retrying transient failures with backoff
+		policy := w.retryPolicies.For(taskName)
+		var outputData map[string]interface{}
+		var err error
+		attempt := 1
+		for {
+			outputData, err = handler(ctx, task.InputData)
+			if !isRetryableFailure(outputData, err) || attempt >= policy.MaxAttempts {
+				break
+			}
+			backoff := policy.Backoff(attempt)
+			logger.Warn("Task failed with a retryable error, retrying",
+				zap.String("error", failureReason(outputData, err)),
+				zap.Int("attempt", attempt),
+				zap.Int("max_attempts", policy.MaxAttempts),
+				zap.Duration("backoff", backoff))
+			time.Sleep(backoff)
+			attempt++
+		}
 
 		processingTime := time.Since(startTime)
 
+		// Only completed work is kept: failed executions are released so a retry runs them again
+		if w.taskExecutions != nil && idempotencyKey != "" {
+			if err == nil && outputData["success"] != false {
+				if completeErr := w.taskExecutions.Complete(ctx, idempotencyKey, outputData); completeErr != nil {
+					logger.Error("Failed to store task output", zap.Error(completeErr))
+				}
+			} else if releaseErr := w.taskExecutions.Release(ctx, idempotencyKey); releaseErr != nil {
+				logger.Error("Failed to release task execution", zap.Error(releaseErr))
+			}
+		}
+
 		if err != nil {
 			logger.Error("Task execution failed",
 				zap.Error(err),
+				zap.Int("attempts", attempt),
 				zap.Duration("processing_time", processingTime),
 				zap.String("task_id", task.TaskID),
 				zap.String("workflow_instance_id", task.WorkflowInstanceID))
 
-			return &MockTaskResult{
-				TaskID:                task.TaskID,
-				Status:                "FAILED",
-				ReasonForIncompletion: err.Error(),
-				OutputData: map[string]interface{}{
-					"error":           err.Error(),
-					"processing_time": processingTime.String(),
-					"failed_at":       time.Now().UTC().Format(time.RFC3339),
-					"task_type":       task.TaskType,
-					"workflow_id":     task.WorkflowInstanceID,
-				},
-				WorkerID:      fmt.Sprintf("underwriting-worker-%d", time.Now().Unix()),
-				CompletedTime: time.Now(),
-			}, nil
+			result := w.failedTaskResult(task, startTime, err.Error())
+			terminal := !IsRetryableError(err)
+			if terminal {
+				// Running the task again would fail the same way, so Conductor must not retry it
+				result.Status = "FAILED_WITH_TERMINAL_ERROR"
+			}
+			w.deadLetter(ctx, logger, taskName, task, nil, err.Error(), terminal, attempt)
+			return result, nil
+		}
+
+		// A transient failure that outlasted the retries is left for Conductor to retry later;
+		// other failure responses complete the task for the workflow to route
+		if isRetryableFailure(outputData, nil) {
+			logger.Error("Task failed after exhausting retries",
+				zap.String("error", failureReason(outputData, nil)),
+				zap.Int("attempts", attempt),
+				zap.Duration("processing_time", processingTime))
+			w.deadLetter(ctx, logger, taskName, task, outputData, failureReason(outputData, nil), false, attempt)
+			return w.failedTaskResult(task, startTime, failureReason(outputData, nil)), nil
 		}
 
 		logger.Info("Task execution completed successfully",
@@ -341,6 +688,149 @@ func (w *UnderwritingTaskWorker) wrapTaskHandler(taskName string, handler func(c
 	}
 }
 
+// recordTaskRun adds a task run to the history. The history is for support, so failing to record a
+// run is logged and does not fail the task.
+func (w *UnderwritingTaskWorker) recordTaskRun(taskName string, task *MockTask, result *MockTaskResult, startedAt time.Time) {
+	if w.taskRuns == nil || result == nil {
+		return
+	}
+
+	completedAt := time.Now().UTC()
+	applicationID, _ := task.InputData["applicationId"].(string)
+	run := &domain.TaskRun{
+		TaskID:             task.TaskID,
+		TaskType:           taskName,
+		WorkflowInstanceID: task.WorkflowInstanceID,
+		ApplicationID:      applicationID,
+		WorkerID:           w.instanceID,
+		Input:              task.InputData,
+		Output:             result.OutputData,
+		Error:              result.ReasonForIncompletion,
+		StartedAt:          startedAt,
+		CompletedAt:        completedAt,
+		DurationMs:         completedAt.Sub(startedAt).Milliseconds(),
+	}
+	if task.WorkerID != "" {
+		run.WorkerID += "/" + task.WorkerID
+	}
+	if run.Input == nil {
+		run.Input = map[string]interface{}{}
+	}
+	switch result.Status {
+	case "COMPLETED":
+		run.Status = domain.TaskRunCompleted
+		if deduplicated, _ := result.OutputData["deduplicated"].(bool); deduplicated {
+			run.Status = domain.TaskRunDeduplicated
+		}
+	case "FAILED_WITH_TERMINAL_ERROR":
+		run.Status = domain.TaskRunFailedTerminal
+	default:
+		run.Status = domain.TaskRunFailed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := w.taskRuns.Record(ctx, run); err != nil {
+		w.logger.Warn("Failed to record task run",
+			zap.String("task_name", taskName),
+			zap.String("task_id", task.TaskID),
+			zap.Error(err))
+	}
+}
+
+// TaskRuns returns the task run history, or nil when the worker has no database
+func (w *UnderwritingTaskWorker) TaskRuns() domain.TaskRunRepository {
+	return w.taskRuns
+}
+
+// taskExecutionLease is how long a task execution is assumed to still be running; a delivery
+// after that takes the execution over
+const taskExecutionLease = 10 * time.Minute
+
+// taskIdempotencyKey identifies a task execution: a task type for an application within one
+// workflow run, so Conductor's retries are deduplicated while a new underwriting run is not.
+// Tasks without an application are keyed on their task id.
+func taskIdempotencyKey(taskName string, task *MockTask) string {
+	if applicationID, _ := task.InputData["applicationId"].(string); applicationID != "" {
+		return fmt.Sprintf("%s:%s:%s", taskName, applicationID, task.WorkflowInstanceID)
+	}
+	if task.TaskID != "" {
+		return taskName + ":" + task.TaskID
+	}
+	return ""
+}
+
+// isRetryableFailure reports whether a handler failed with a retryable error, either returned or
+// flagged in its failure response
+func isRetryableFailure(output map[string]interface{}, err error) bool {
+	if err != nil {
+		return IsRetryableError(err)
+	}
+	retryable, _ := output["retryable"].(bool)
+	return retryable
+}
+
+// failureReason returns the error a handler returned or put in its failure response
+func failureReason(output map[string]interface{}, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	reason, _ := output["error"].(string)
+	return reason
+}
+
+// deadLetter puts a failed task in the dead-letter queue when it will not run again: its error is
+// terminal, or Conductor has used up the task's retries
+func (w *UnderwritingTaskWorker) deadLetter(ctx context.Context, logger *zap.Logger, taskName string, task *MockTask, output map[string]interface{}, reason string, terminal bool, attempts int) {
+	if !terminal && task.RetryCount < w.conductorRetries[taskName] {
+		return
+	}
+	if w.deadLetters == nil {
+		logger.Error("Task failed for good and no dead-letter queue is available",
+			zap.String("error", reason), zap.Bool("terminal", terminal))
+		return
+	}
+
+	applicationID, _ := task.InputData["applicationId"].(string)
+	if err := w.deadLetters.Create(ctx, &domain.DeadLetterTask{
+		TaskID:             task.TaskID,
+		TaskType:           taskName,
+		WorkflowInstanceID: task.WorkflowInstanceID,
+		ApplicationID:      applicationID,
+		Input:              task.InputData,
+		Output:             output,
+		Error:              reason,
+		Terminal:           terminal,
+		Attempts:           attempts,
+		ConductorRetries:   task.RetryCount,
+	}); err != nil {
+		logger.Error("Failed to dead-letter task", zap.String("error", reason), zap.Error(err))
+		return
+	}
+	logger.Warn("Task dead-lettered",
+		zap.String("error", reason),
+		zap.Bool("terminal", terminal),
+		zap.Int("conductor_retries", task.RetryCount))
+}
+
+// failedTaskResult returns a failed task result Conductor will retry
+func (w *UnderwritingTaskWorker) failedTaskResult(task *MockTask, startTime time.Time, reason string) *MockTaskResult {
+	return &MockTaskResult{
+		TaskID:                task.TaskID,
+		Status:                "FAILED",
+		ReasonForIncompletion: reason,
+		OutputData: map[string]interface{}{
+			"error":           reason,
+			"processing_time": time.Since(startTime).String(),
+			"failed_at":       time.Now().UTC().Format(time.RFC3339),
+			"task_type":       task.TaskType,
+			"workflow_id":     task.WorkflowInstanceID,
+		},
+		WorkerID:      fmt.Sprintf("underwriting-worker-%d", time.Now().Unix()),
+		CompletedTime: time.Now(),
+	}
+}
+
 // registerWorker registers a worker with the appropriate client
 func (w *UnderwritingTaskWorker) registerWorker(taskType string, handler TaskHandler) {
 	if w.useMockConductor {
@@ -359,29 +849,18 @@ func (w *UnderwritingTaskWorker) registerWorkflowDefinitions() error {
 	w.logger.Info("Registering workflow and task definitions with Conductor")
 
 	// Register task definitions
-	taskDefs := w.conductorClient.CreateTaskDefinitions()
+	taskDefs := w.definitions.Tasks
 	successfulRegistrations := 0
 	totalTasks := len(taskDefs)
 
 	for _, taskDef := range taskDefs {
-		// Convert to HTTP client format
-		httpTaskDef := &TaskDefinition{
-			Name:                   taskDef.Name,
-			Description:            taskDef.Description,
-			TimeoutSeconds:         taskDef.TimeoutSeconds,
-			ResponseTimeoutSeconds: taskDef.ResponseTimeoutSeconds,
-			RetryCount:             taskDef.RetryCount,
-			InputKeys:              taskDef.InputKeys,
-			OutputKeys:             taskDef.OutputKeys,
-		}
-
-		if err := w.conductorClient.RegisterTaskDefinition(httpTaskDef); err != nil {
+		if err := w.registerTaskDefinition(taskDef); err != nil {
 			w.logger.Error("Failed to register task definition",
-				zap.String("task_name", httpTaskDef.Name),
+				zap.String("task_name", taskDef.Name),
 				zap.Error(err))
 			// Continue with other tasks but track failures
 		} else {
-			w.logger.Info("Registered task definition", zap.String("task_name", httpTaskDef.Name))
+			w.logger.Info("Registered task definition", zap.String("task_name", taskDef.Name))
 			successfulRegistrations++
 		}
 	}
@@ -396,15 +875,20 @@ func (w *UnderwritingTaskWorker) registerWorkflowDefinitions() error {
 		w.logger.Warn("Very few task definitions registered successfully, this may cause issues")
 	}
 
-	// Register workflow definition
-	workflowDef := w.conductorClient.CreateUnderwritingWorkflowDefinition()
-	if err := w.conductorClient.RegisterWorkflowDefinition(workflowDef); err != nil {
-		w.logger.Error("Failed to register workflow definition", zap.Error(err))
-		return err
-	}
+	// Register workflow definitions
+	for _, workflowDef := range w.definitions.Workflows {
+		if err := w.registerWorkflowDefinition(workflowDef); err != nil {
+			w.logger.Error("Failed to register workflow definition",
+				zap.String("workflow_name", workflowDef.Name),
+				zap.Int("workflow_version", workflowDef.Version),
+				zap.Error(err))
+			return err
+		}
 
-	w.logger.Info("Successfully registered workflow definition",
-		zap.String("workflow_name", workflowDef.Name))
+		w.logger.Info("Successfully registered workflow definition",
+			zap.String("workflow_name", workflowDef.Name),
+			zap.Int("workflow_version", workflowDef.Version))
+	}
 
 	// Add a small delay to ensure definitions are propagated in Conductor
 	w.logger.Info("Waiting for task definitions to propagate in Conductor...")
@@ -507,7 +991,7 @@ func (w *UnderwritingTaskWorker) handlePolicyComplianceCheck(ctx context.Context
 		violations = append(violations, "Credit score below minimum threshold")
 	}
 
-	if dtiRatio, ok := input["dtiRatio"].(float64); ok && dtiRatio > 0.43 {
+	if dtiRatio, ok := w.qualifyingDTI(input); ok && dtiRatio > w.config.Application.MaxDTIRatio {
 		compliant = false
 		violations = append(violations, "DTI ratio exceeds maximum allowed")
 	}
@@ -526,7 +1010,30 @@ func (w *UnderwritingTaskWorker) handlePolicyComplianceCheck(ctx context.Context
 	}, nil
 }
 
-// handleFraudDetection handles fraud detection analysis
+// qualifyingDTI returns the DTI ratio of a task's input. When the input carries obligations they are
+// qualified under the debt treatments with the combined income and debts; otherwise the ratio the
+// loan service computed is used.
+func (w *UnderwritingTaskWorker) qualifyingDTI(input map[string]interface{}) (float64, bool) {
+	if obligations, ok := input["obligations"]; ok && obligations != nil {
+		monthlyIncome, hasIncome := input["combinedMonthlyIncome"].(float64)
+		if !hasIncome {
+			monthlyIncome, hasIncome = input["monthlyIncome"].(float64)
+		}
+		monthlyDebt, hasDebt := input["combinedMonthlyDebt"].(float64)
+		if !hasDebt {
+			monthlyDebt, _ = input["monthlyDebt"].(float64)
+		}
+		data, err := json.Marshal(obligations)
+		var decoded dti.Obligations
+		if hasIncome && err == nil && json.Unmarshal(data, &decoded) == nil {
+			return w.dti.Ratio(monthlyIncome, monthlyDebt, &decoded), true
+		}
+	}
+	dtiRatio, ok := input["dtiRatio"].(float64)
+	return dtiRatio, ok
+}
+
+// handleFraudDetection runs the fraud checks on an application and saves its fraud report
 func (w *UnderwritingTaskWorker) handleFraudDetection(ctx context.Context, input map[string]interface{}) (map[string]interface{}, error) {
 	logger := w.logger.With(zap.String("operation", "fraud_detection"))
 	logger.Info("Performing fraud detection analysis")
@@ -536,35 +1043,44 @@ func (w *UnderwritingTaskWorker) handleFraudDetection(ctx context.Context, input
 		return nil, fmt.Errorf("application ID is required")
 	}
 
-	// Mock fraud detection
-	fraudRiskScore := 15.0 // Low fraud risk
-	fraudIndicators := []string{}
-
-	// Simulate fraud indicators
-	if loanAmount, ok := input["loanAmount"].(float64); ok && loanAmount > 100000 {
-		fraudRiskScore += 10
-		fraudIndicators = append(fraudIndicators, "High loan amount requested")
+	detail := func(key string) string {
+		value, _ := input[key].(string)
+		return value
 	}
-
-	fraudRiskLevel := "low"
-	if fraudRiskScore > 50 {
-		fraudRiskLevel = "high"
-	} else if fraudRiskScore > 25 {
-		fraudRiskLevel = "medium"
+	loanAmount, _ := input["loanAmount"].(float64)
+
+	report, err := w.fraudChecks.Assess(ctx, &domain.FraudScreeningRequest{
+		ApplicationID: applicationID,
+		UserID:        detail("userId"),
+		LoanAmount:    loanAmount,
+		DeviceID:      detail("deviceId"),
+		IPAddress:     detail("ipAddress"),
+		Email:         detail("email"),
+		Phone:         detail("phone"),
+		SSNToken:      detail("ssnToken"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fraud detection failed: %w", err)
 	}
 
-	logger.Info("Fraud detection completed",
-		zap.String("application_id", applicationID),
-		zap.Float64("fraud_risk_score", fraudRiskScore),
-		zap.String("fraud_risk_level", fraudRiskLevel))
-
+	// fraudRiskScore is on the 0-100 scale; fraudScore is the vendor's normalized 0-1 score
 	return map[string]interface{}{
-		"success":         true,
-		"applicationId":   applicationID,
-		"fraudRiskScore":  fraudRiskScore,
-		"fraudRiskLevel":  fraudRiskLevel,
-		"fraudIndicators": fraudIndicators,
-		"completedAt":     time.Now().UTC().Format(time.RFC3339),
+		"success":                      true,
+		"applicationId":                applicationID,
+		"fraudReportId":                report.ID,
+		"fraudRiskScore":               report.RiskScore,
+		"fraudScore":                   report.VendorScore,
+		"fraudRiskLevel":               string(report.RiskLevel),
+		"fraudIndicators":              report.Indicators,
+		"fraudReasonCodes":             report.ReasonCodes,
+		"fraudChecks":                  report.Checks,
+		"fraudSignals":                 report.VendorSignals,
+		"fraudRecommendation":          report.Recommendation,
+		"fraudVendor":                  report.Vendor,
+		"fraudUnavailable":             report.VendorUnavailable,
+		"identityVerificationRequired": report.IdentityVerification,
+		"manualReviewRequired":         report.Recommendation == domain.FraudRecommendationReview,
+		"completedAt":                  time.Now().UTC().Format(time.RFC3339),
 	}, nil
 }
 
@@ -638,7 +1154,8 @@ func (w *UnderwritingTaskWorker) handleInterestRateCalculation(ctx context.Conte
 	}, nil
 }
 
-// handleFinalApproval handles final loan approval processing
+// handleFinalApproval handles final loan approval processing. An approval above
+// secondary_review.approval_amount is refused until a second underwriter signs it off.
 func (w *UnderwritingTaskWorker) handleFinalApproval(ctx context.Context, input map[string]interface{}) (map[string]interface{}, error) {
 	logger := w.logger.With(zap.String("operation", "final_approval"))
 	logger.Info("Processing final approval")
@@ -653,6 +1170,13 @@ func (w *UnderwritingTaskWorker) handleFinalApproval(ctx context.Context, input
 	interestRate, _ := input["interestRate"].(float64)
 	term, _ := input["approvedTerm"].(float64)
 
+	// Large approvals need a second underwriter's sign-off before the loan is approved
+	signOff, err := w.requireSecondaryApproval(ctx, input, approvedAmount)
+	if err != nil {
+		logger.Error("Final approval blocked", zap.String("application_id", applicationID), zap.Error(err))
+		return nil, err
+	}
+
 	// Generate loan terms
 	offerExpirationDate := time.Now().Add(7 * 24 * time.Hour)
 	loanNumber := fmt.Sprintf("UW-%s-%d", applicationID[:8], time.Now().Unix())
@@ -661,9 +1185,10 @@ func (w *UnderwritingTaskWorker) handleFinalApproval(ctx context.Context, input
 		zap.String("application_id", applicationID),
 		zap.String("loan_number", loanNumber),
 		zap.Float64("approved_amount", approvedAmount),
-		zap.Float64("interest_rate", interestRate))
+		zap.Float64("interest_rate", interestRate),
+		zap.Bool("signed_off", signOff != nil))
 
-	return map[string]interface{}{
+	output := map[string]interface{}{
 		"success":       true,
 		"applicationId": applicationID,
 		"loanNumber":    loanNumber,
@@ -679,7 +1204,12 @@ func (w *UnderwritingTaskWorker) handleFinalApproval(ctx context.Context, input
 			"Schedule loan closing",
 		},
 		"completedAt": time.Now().UTC().Format(time.RFC3339),
-	}, nil
+	}
+	if signOff != nil {
+		output["secondaryReviewId"] = signOff.ID
+		output["secondApprover"] = signOff.CompletedBy
+	}
+	return output, nil
 }
 
 // handleDenialProcessing handles loan denial processing
@@ -711,37 +1241,82 @@ func (w *UnderwritingTaskWorker) handleDenialProcessing(ctx context.Context, inp
 	}, nil
 }
 
-// handleManualReviewAssignment handles manual review assignment
+// Time an underwriter has to complete a manual review, by priority
+var manualReviewDue = map[string]time.Duration{
+	"high":   8 * time.Hour,
+	"normal": 24 * time.Hour,
+	"low":    48 * time.Hour,
+}
+
+// handleManualReviewAssignment queues the application for an underwriter. Underwriters claim and
+// complete the review through the loan API, which completes the workflow's waiting human task
+// (taskReferenceName, manual_review_ref by default) with their decision.
 func (w *UnderwritingTaskWorker) handleManualReviewAssignment(ctx context.Context, input map[string]interface{}) (map[string]interface{}, error) {
 	logger := w.logger.With(zap.String("operation", "assign_manual_review"))
-	logger.Info("Assigning manual review")
+	logger.Info("Queuing manual review")
 
 	applicationID, ok := input["applicationId"].(string)
 	if !ok || applicationID == "" {
 		return nil, fmt.Errorf("application ID is required")
 	}
+	workflowInstanceID, ok := input["workflowInstanceId"].(string)
+	if !ok || workflowInstanceID == "" {
+		return nil, fmt.Errorf("workflow instance ID is required")
+	}
+	if w.manualReviews == nil {
+		return nil, fmt.Errorf("manual review queue is not configured")
+	}
 
-	// Assign to next available underwriter
-	assignedUnderwriter := "underwriter_1" // Mock assignment
-	reviewPriority := "normal"
+	taskReferenceName, _ := input["taskReferenceName"].(string)
+	if taskReferenceName == "" {
+		taskReferenceName = "manual_review_ref"
+	}
+	riskLevel, _ := input["riskLevel"].(string)
 
-	if riskLevel, ok := input["riskLevel"].(string); ok && riskLevel == "high" {
+	// High-risk applications are reviewed first; an explicit priority overrides the risk level
+	reviewPriority := "normal"
+	if strings.EqualFold(riskLevel, "high") {
 		reviewPriority = "high"
 	}
+	switch priority := strings.ToLower(fmt.Sprint(input["priority"])); priority {
+	case "high", "low":
+		reviewPriority = priority
+	case "normal", "medium":
+		reviewPriority = "normal"
+	}
 
-	dueDate := time.Now().Add(24 * time.Hour) // 1 day for review
+	now := time.Now().UTC()
+	review := &domain.ManualReview{
+		ApplicationID:      applicationID,
+		WorkflowInstanceID: workflowInstanceID,
+		TaskReferenceName:  taskReferenceName,
+		Reason:             manualReviewReason(input),
+		RiskLevel:          strings.ToLower(riskLevel),
+		Priority:           reviewPriority,
+		DueAt:              now.Add(manualReviewDue[reviewPriority]),
+		CreatedAt:          now,
+	}
+	existing, created, err := w.manualReviews.Enqueue(ctx, review)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		review = existing
+	}
 
-	logger.Info("Manual review assigned",
+	logger.Info("Manual review queued",
 		zap.String("application_id", applicationID),
-		zap.String("assigned_underwriter", assignedUnderwriter),
-		zap.String("priority", reviewPriority))
+		zap.String("review_id", review.ID),
+		zap.String("priority", review.Priority),
+		zap.Bool("already_queued", !created))
 
 	return map[string]interface{}{
 		"success":       true,
 		"applicationId": applicationID,
-		"assignedTo":    assignedUnderwriter,
-		"priority":      reviewPriority,
-		"dueDate":       dueDate.Format(time.RFC3339),
+		"reviewId":      review.ID,
+		"reviewStatus":  string(review.Status),
+		"priority":      review.Priority,
+		"dueDate":       review.DueAt.Format(time.RFC3339),
 		"reviewInstructions": []string{
 			"Review credit history in detail",
 			"Verify income documentation",
@@ -752,7 +1327,29 @@ func (w *UnderwritingTaskWorker) handleManualReviewAssignment(ctx context.Contex
 	}, nil
 }
 
-// handleConditionalApproval handles conditional approval processing
+// manualReviewReason joins the reasons the workflow gives for reviewing an application
+func manualReviewReason(input map[string]interface{}) string {
+	if reason, ok := input["reviewReason"].(string); ok && reason != "" {
+		return reason
+	}
+	reasons, _ := input["reviewReasons"].([]interface{})
+	parts := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		if s := strings.TrimSpace(fmt.Sprint(reason)); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// conditionDue is how long the borrower has to clear a condition the decision gives no due date for
+const conditionDue = 14 * 24 * time.Hour
+
+// handleConditionalApproval records the conditions of a conditional approval for the borrower to
+// clear. The loan API tracks them and completes the workflow's waiting task (taskReferenceName,
+// wait_for_conditions_ref by default) once every critical condition is received or waived. The
+// output's conditionsStatus is PENDING while a critical condition is outstanding and CLEARED when
+// the workflow need not wait.
 func (w *UnderwritingTaskWorker) handleConditionalApproval(ctx context.Context, input map[string]interface{}) (map[string]interface{}, error) {
 	logger := w.logger.With(zap.String("operation", "process_conditional_approval"))
 	logger.Info("Processing conditional approval")
@@ -761,30 +1358,119 @@ func (w *UnderwritingTaskWorker) handleConditionalApproval(ctx context.Context,
 	if !ok || applicationID == "" {
 		return nil, fmt.Errorf("application ID is required")
 	}
+	workflowInstanceID, ok := input["workflowInstanceId"].(string)
+	if !ok || workflowInstanceID == "" {
+		return nil, fmt.Errorf("workflow instance ID is required")
+	}
+	if w.conditions == nil {
+		return nil, fmt.Errorf("condition store is not configured")
+	}
 
-	conditions, _ := input["conditions"].([]interface{})
+	taskReferenceName, _ := input["taskReferenceName"].(string)
+	if taskReferenceName == "" {
+		taskReferenceName = "wait_for_conditions_ref"
+	}
 	approvedAmount, _ := input["approvedAmount"].(float64)
 
+	now := time.Now().UTC()
+	raw, _ := input["conditions"].([]interface{})
+	conditions := make([]*domain.TrackedCondition, 0, len(raw))
+	for i, item := range raw {
+		condition := trackedCondition(item, i, now)
+		if condition == nil {
+			continue
+		}
+		condition.ApplicationID = applicationID
+		condition.WorkflowInstanceID = workflowInstanceID
+		condition.TaskReferenceName = taskReferenceName
+		condition.CreatedAt = now
+		conditions = append(conditions, condition)
+	}
+
+	recorded, err := w.conditions.Track(ctx, conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	conditionIDs := make([]string, 0, len(recorded))
+	criticalOutstanding := 0
+	for _, condition := range recorded {
+		conditionIDs = append(conditionIDs, condition.ID)
+		if condition.Critical && condition.Status == domain.TrackedConditionPending {
+			criticalOutstanding++
+		}
+	}
+	conditionsStatus := "CLEARED"
+	if criticalOutstanding > 0 {
+		conditionsStatus = "PENDING"
+	}
+
 	logger.Info("Conditional approval processed",
 		zap.String("application_id", applicationID),
 		zap.Float64("approved_amount", approvedAmount),
-		zap.Any("conditions", conditions))
+		zap.Int("conditions", len(recorded)),
+		zap.Int("critical_outstanding", criticalOutstanding))
 
 	return map[string]interface{}{
-		"success":        true,
-		"applicationId":  applicationID,
-		"approvedAmount": approvedAmount,
-		"conditions":     conditions,
-		"nextSteps": []string{
-			"Send conditional approval to customer",
-			"Track condition fulfillment",
-			"Schedule final approval upon completion",
-		},
-		"completedAt": time.Now().UTC().Format(time.RFC3339),
+		"success":             true,
+		"applicationId":       applicationID,
+		"approvedAmount":      approvedAmount,
+		"conditionIds":        conditionIDs,
+		"criticalOutstanding": criticalOutstanding,
+		"conditionsStatus":    conditionsStatus,
+		"completedAt":         now.Format(time.RFC3339),
 	}, nil
 }
 
-// handleCounterOfferGeneration handles counter offer generation
+// trackedCondition reads a condition from the task input: either a decision's condition object or,
+// from a reviewer, its description. Conditions are critical unless they are of low or medium
+// priority, or ongoing. It returns nil for an empty condition.
+func trackedCondition(item interface{}, index int, now time.Time) *domain.TrackedCondition {
+	condition := &domain.TrackedCondition{
+		SourceConditionID: fmt.Sprintf("condition_%d", index+1),
+		ConditionType:     "prior_to_funding",
+		Priority:          "high",
+		DueAt:             now.Add(conditionDue),
+	}
+
+	switch v := item.(type) {
+	case string:
+		condition.Description = strings.TrimSpace(v)
+	case map[string]interface{}:
+		if id, _ := v["condition_id"].(string); id != "" {
+			condition.SourceConditionID = id
+		}
+		if conditionType, _ := v["condition_type"].(string); conditionType != "" {
+			condition.ConditionType = conditionType
+		}
+		if priority, _ := v["priority"].(string); priority != "" {
+			condition.Priority = strings.ToLower(priority)
+		}
+		if dueDate, _ := v["due_date"].(string); dueDate != "" {
+			if due, err := time.Parse(time.RFC3339, dueDate); err == nil && due.After(now) {
+				condition.DueAt = due
+			}
+		}
+		description, _ := v["description"].(string)
+		condition.Description = strings.TrimSpace(description)
+	}
+	if condition.Description == "" {
+		return nil
+	}
+
+	switch condition.Priority {
+	case "critical", "high", "medium", "low":
+	default:
+		condition.Priority = "high"
+	}
+	condition.Critical = (condition.Priority == "critical" || condition.Priority == "high") &&
+		condition.ConditionType != "ongoing"
+	return condition
+}
+
+// handleCounterOfferGeneration handles counter offer generation. The decision engine decides and
+// prices the application on the terms requested: terms it approves are offered as it priced them,
+// capped at the amount it allows; otherwise, given an offerId, the borrower's current offer stands.
 func (w *UnderwritingTaskWorker) handleCounterOfferGeneration(ctx context.Context, input map[string]interface{}) (map[string]interface{}, error) {
 	logger := w.logger.With(zap.String("operation", "generate_counter_offer"))
 	logger.Info("Generating counter offer")
@@ -795,26 +1481,77 @@ func (w *UnderwritingTaskWorker) handleCounterOfferGeneration(ctx context.Contex
 	}
 
 	requestedAmount, _ := input["requestedAmount"].(float64)
+	requestedTerm, _ := input["requestedTermMonths"].(float64)
+	currentAmount, _ := input["currentAmount"].(float64)
+	currentRate, _ := input["currentRate"].(float64)
+	currentTerm, _ := input["currentTermMonths"].(float64)
+	if requestedTerm == 0 {
+		requestedTerm = currentTerm
+	}
+	if requestedAmount <= 0 || requestedTerm <= 0 {
+		return nil, fmt.Errorf("requested amount and term are required")
+	}
+	if w.underwritingDecisionHandler == nil {
+		return nil, fmt.Errorf("underwriting decision handler is not configured")
+	}
+
+	_, decision, err := w.underwritingDecisionHandler.decideTerms(ctx, applicationID, requestedAmount, int(requestedTerm))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decide requested terms: %w", err)
+	}
+
+	var offeredAmount, offeredRate, offeredAPR float64
+	var offeredTerm int
+	var offerReason string
+	if pricing := pricedApproval(decision); pricing != nil {
+		offeredAmount = math.Floor(math.Min(pricing.LoanAmount, requestedAmount)/100) * 100
+		offeredTerm = pricing.TermMonths
+		if offeredTerm == 0 {
+			offeredTerm = int(requestedTerm)
+		}
+		offeredRate = pricing.Rate
+		offeredAPR = pricing.APR
+		offerReason = "Requested terms granted"
+		if offeredAmount < requestedAmount {
+			offerReason = "Requested amount reduced to the amount approved"
+		}
+	} else {
+		// Nothing the engine did not approve is offered; the borrower keeps the offer they have
+		if currentAmount <= 0 || currentRate <= 0 || currentTerm <= 0 {
+			return nil, fmt.Errorf("requested terms were not approved (%s) and there is no current offer to keep", decision.Decision)
+		}
+		offeredAmount = currentAmount
+		offeredTerm = int(currentTerm)
+		offeredRate = currentRate
+		offeredAPR, _ = input["currentAPR"].(float64)
+		offerReason = "Requested terms not approved; current offer terms stand"
+	}
+	if offeredAPR == 0 {
+		offeredAPR = offeredRate
+	}
 
-	// Generate reduced counter offer
-	counterOfferAmount := requestedAmount * 0.75 // 75% of requested
-	higherRate := 12.5                           // Higher interest rate
+	monthlyPayment, totalInterest := amortize(offeredAmount, offeredRate, offeredTerm)
 
 	expirationDate := time.Now().Add(7 * 24 * time.Hour)
 
 	logger.Info("Counter offer generated",
 		zap.String("application_id", applicationID),
+		zap.String("decision", string(decision.Decision)),
 		zap.Float64("requested_amount", requestedAmount),
-		zap.Float64("counter_offer_amount", counterOfferAmount),
-		zap.Float64("offered_rate", higherRate))
+		zap.Float64("counter_offer_amount", offeredAmount),
+		zap.Float64("offered_rate", offeredRate))
 
 	return map[string]interface{}{
 		"success":       true,
 		"applicationId": applicationID,
 		"counterOffer": map[string]interface{}{
-			"offeredAmount":  counterOfferAmount,
-			"offeredRate":    higherRate,
-			"offerReason":    "Reduced amount to mitigate risk profile",
+			"offeredAmount":  offeredAmount,
+			"offeredTerm":    offeredTerm,
+			"offeredRate":    offeredRate,
+			"offeredAPR":     offeredAPR,
+			"monthlyPayment": monthlyPayment,
+			"totalInterest":  totalInterest,
+			"offerReason":    offerReason,
 			"expirationDate": expirationDate.Format(time.RFC3339),
 		},
 		"nextSteps": []string{
@@ -826,6 +1563,177 @@ func (w *UnderwritingTaskWorker) handleCounterOfferGeneration(ctx context.Contex
 	}, nil
 }
 
+// pricedApproval returns the decision engine's pricing of a decision that can be offered as is: an
+// approval, outright or conditional, that needs no manual review
+func pricedApproval(decision *domain.DecisionResponse) *domain.DecisionPricing {
+	if decision.KnockedOut() || decision.ManualReviewRequired || decision.Pricing == nil || decision.Pricing.LoanAmount <= 0 {
+		return nil
+	}
+	switch decision.Decision {
+	case domain.DecisionApproved, domain.DecisionConditional:
+		return decision.Pricing
+	}
+	return nil
+}
+
+// amortize returns the monthly payment and total interest of a fully amortizing loan at an annual
+// rate in percent, rounded to cents
+func amortize(amount, annualRate float64, termMonths int) (monthlyPayment, totalInterest float64) {
+	if termMonths <= 0 {
+		return 0, 0
+	}
+	term := float64(termMonths)
+	monthlyRate := annualRate / 12 / 100
+	if monthlyRate > 0 {
+		monthlyPayment = amount * (monthlyRate * math.Pow(1+monthlyRate, term)) / (math.Pow(1+monthlyRate, term) - 1)
+	} else {
+		monthlyPayment = amount / term
+	}
+	totalInterest = monthlyPayment*term - amount
+	return math.Round(monthlyPayment*100) / 100, math.Round(totalInterest*100) / 100
+}
+
+// handleCounterOfferFinalization has the decision engine re-decide and price the counter offer
+// terms a borrower accepted and records the application's final underwriting result. Terms the
+// engine no longer approves as accepted are sent to manual review rather than approved. The result's
+// ID derives from the counter offer, so a retried task overwrites the result it already recorded.
+func (w *UnderwritingTaskWorker) handleCounterOfferFinalization(ctx context.Context, input map[string]interface{}) (map[string]interface{}, error) {
+	logger := w.logger.With(zap.String("operation", "finalize_counter_offer"))
+	logger.Info("Finalizing accepted counter offer")
+
+	applicationID, ok := input["applicationId"].(string)
+	if !ok || applicationID == "" {
+		return nil, fmt.Errorf("application ID is required")
+	}
+	counterOfferID, ok := input["counterOfferId"].(string)
+	if !ok || counterOfferID == "" {
+		return nil, fmt.Errorf("counter offer ID is required")
+	}
+	amount, _ := input["acceptedAmount"].(float64)
+	acceptedRate, _ := input["acceptedRate"].(float64)
+	term, _ := input["acceptedTermMonths"].(float64)
+	if amount <= 0 || acceptedRate <= 0 || term <= 0 {
+		return nil, fmt.Errorf("accepted amount, rate and term are required")
+	}
+	if w.underwritingResults == nil {
+		return nil, fmt.Errorf("underwriting result store is not configured")
+	}
+	if w.underwritingDecisionHandler == nil {
+		return nil, fmt.Errorf("underwriting decision handler is not configured")
+	}
+
+	termMonths := int(term)
+	application, decision, err := w.underwritingDecisionHandler.decideTerms(ctx, applicationID, amount, termMonths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decide accepted terms: %w", err)
+	}
+
+	offerReason, _ := input["offerReason"].(string)
+	offerID, _ := input["offerId"].(string)
+	workflowInstanceID, _ := input["workflowInstanceId"].(string)
+	now := time.Now().UTC()
+
+	decisionData := map[string]interface{}{}
+	for key, value := range decision.DecisionData {
+		decisionData[key] = value
+	}
+	decisionData["counterOfferId"] = counterOfferID
+	decisionData["offerId"] = offerID
+	decisionData["workflowInstanceId"] = workflowInstanceID
+	decisionData["acceptedRate"] = acceptedRate
+
+	result := &domain.UnderwritingResult{
+		ID:                   "counter-offer-" + counterOfferID,
+		ApplicationID:        applicationID,
+		UserID:               application.UserID,
+		Decision:             domain.DecisionApproved,
+		Status:               domain.StatusCompleted,
+		AutomatedDecision:    !decision.ManualReviewRequired,
+		ManualReviewRequired: decision.ManualReviewRequired,
+		PolicyVersion:        decision.PolicyVersion,
+		Conditions:           decision.Conditions,
+		DecisionData:         decisionData,
+		ProcessingTime:       decision.ProcessingTime,
+		CreatedAt:            now,
+	}
+
+	pricing := pricedApproval(decision)
+	if pricing != nil && pricing.LoanAmount >= amount {
+		rate, apr := pricing.Rate, pricing.APR
+		if apr == 0 {
+			apr = rate
+		}
+		monthlyPayment, totalInterest := amortize(amount, rate, termMonths)
+		if rate != acceptedRate {
+			logger.Warn("Accepted counter offer repriced",
+				zap.String("application_id", applicationID),
+				zap.Float64("accepted_rate", acceptedRate),
+				zap.Float64("interest_rate", rate))
+		}
+
+		result.Decision = decision.Decision
+		result.ApprovedAmount = amount
+		result.ApprovedTerm = termMonths
+		result.InterestRate = rate
+		result.APR = apr
+		result.MonthlyPayment = monthlyPayment
+		result.TotalInterest = totalInterest
+		result.TotalPayment = math.Round((amount+totalInterest)*100) / 100
+		result.DecisionReasons = append([]domain.DecisionReason{{
+			ReasonCode:  "COUNTER_OFFER_ACCEPTED",
+			ReasonType:  "approval",
+			Description: "Borrower accepted the counter offer terms",
+			Impact:      "primary",
+			Weight:      1,
+		}}, decision.Reasons...)
+		result.CounterOfferTerms = &domain.CounterOfferTerms{
+			OfferedAmount:  amount,
+			OfferedTerm:    termMonths,
+			OfferedRate:    rate,
+			OfferedAPR:     apr,
+			MonthlyPayment: monthlyPayment,
+			TotalInterest:  totalInterest,
+			OfferReason:    offerReason,
+		}
+		result.DecisionData["pricing"] = pricing
+	} else {
+		// The engine's decision changed since the counter offer was made; an underwriter decides
+		result.Decision = domain.DecisionManualReview
+		result.AutomatedDecision = false
+		result.ManualReviewRequired = true
+		result.DecisionReasons = append([]domain.DecisionReason{{
+			ReasonCode:  "COUNTER_OFFER_NOT_APPROVED",
+			ReasonType:  "condition",
+			Description: fmt.Sprintf("Accepted counter offer terms were not approved on re-decision (%s)", decision.Decision),
+			Impact:      "primary",
+			Weight:      1,
+		}}, decision.Reasons...)
+	}
+
+	if err := w.underwritingResults.Create(ctx, result); err != nil {
+		return nil, fmt.Errorf("failed to save underwriting result: %w", err)
+	}
+
+	logger.Info("Accepted counter offer finalized",
+		zap.String("application_id", applicationID),
+		zap.String("counter_offer_id", counterOfferID),
+		zap.String("underwriting_result_id", result.ID),
+		zap.String("decision", string(result.Decision)),
+		zap.Float64("approved_amount", result.ApprovedAmount),
+		zap.Float64("interest_rate", result.InterestRate),
+		zap.Float64("monthly_payment", result.MonthlyPayment))
+
+	return map[string]interface{}{
+		"success":              true,
+		"applicationId":        applicationID,
+		"decision":             result.Decision,
+		"manualReviewRequired": result.ManualReviewRequired,
+		"underwritingResultId": result.ID,
+		"underwritingResult":   result,
+		"completedAt":          now.Format(time.RFC3339),
+	}, nil
+}
+
 // GetCreditCheckHandler returns the credit check handler for debugging purposes
 func (w *UnderwritingTaskWorker) GetCreditCheckHandler() *CreditCheckTaskHandler {
 	return w.creditCheckHandler
@@ -77,6 +77,16 @@ func (h *UpdateApplicationStateTaskHandler) Execute(ctx context.Context, input m
 
 		oldState := application.CurrentState
 
+		// A repeated update finds the application already moved; writing again would only bump it
+		if oldState == newState {
+			logger.Info("Application already in target state, skipping update",
+				zap.String("application_id", applicationID),
+				zap.String("state", newState))
+			response := h.createSuccessResponse(applicationID, oldState, newState, time.Since(startTime), metadata)
+			response["idempotent"] = true
+			return response, nil
+		}
+
 		// Update state
 		application.CurrentState = newState
 		application.UpdatedAt = time.Now()
@@ -160,6 +170,7 @@ func (h *UpdateApplicationStateTaskHandler) createFailureResponse(applicationID
 		"success":       false,
 		"applicationId": applicationID,
 		"error":         err.Error(),
+		"retryable":     IsRetryableError(err),
 		"completedAt":   time.Now().UTC().Format(time.RFC3339),
 	}
 }
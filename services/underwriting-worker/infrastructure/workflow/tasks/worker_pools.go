@@ -0,0 +1,221 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// sharedPoolName names the pool polling every task type without a concurrency limit of its own
+const sharedPoolName = "shared"
+
+// workerPool is a fixed number of workers dedicated to some task types. Each task type of the pool
+// has a poller of its own claiming tasks for the pool's idle workers, and each worker runs one task
+// at a time, so a pool never runs more than its size concurrently.
+type workerPool struct {
+	name      string
+	taskTypes []string
+	size      int
+}
+
+// TaskTypeStats is a snapshot of a task type's queue and execution counters
+type TaskTypeStats struct {
+	TaskType   string `json:"task_type"`
+	Pool       string `json:"pool"`
+	PoolSize   int    `json:"pool_size"`
+	QueueDepth int64  `json:"queue_depth"` // tasks waiting in Conductor, as of the last check; -1 before the first
+	InFlight   int64  `json:"in_flight"`
+	Executed   int64  `json:"executed"`
+	Failed     int64  `json:"failed"`
+}
+
+// taskTypeCounters are the live counters of a task type, updated by the polling workers
+type taskTypeCounters struct {
+	pool       string
+	poolSize   int
+	queueDepth atomic.Int64
+	inFlight   atomic.Int64
+	executed   atomic.Int64
+	failed     atomic.Int64
+}
+
+// buildWorkerPools splits the registered task types into pools. A task type with a concurrency
+// limit gets a pool of that size to itself; the others share a pool of sharedSize workers.
+func buildWorkerPools(taskTypes []string, concurrency map[string]int, sharedSize int) []*workerPool {
+	sort.Strings(taskTypes)
+
+	var pools []*workerPool
+	shared := &workerPool{name: sharedPoolName, size: sharedSize}
+	for _, taskType := range taskTypes {
+		if limit := concurrency[taskType]; limit > 0 {
+			pools = append(pools, &workerPool{name: taskType, taskTypes: []string{taskType}, size: limit})
+			continue
+		}
+		shared.taskTypes = append(shared.taskTypes, taskType)
+	}
+	if len(shared.taskTypes) > 0 {
+		if shared.size <= 0 {
+			shared.size = 1
+		}
+		pools = append(pools, shared)
+	}
+	return pools
+}
+
+// workerSlots hands a pool's idle workers to the tasks its pollers claim. A worker freed while
+// several task types wait for one goes to the highest priority task type, so urgent work is not
+// queued behind bulk work sharing the pool.
+type workerSlots struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	idle    []int       // indices of the idle workers
+	waiting map[int]int // pollers waiting for a worker, by task priority
+}
+
+func newWorkerSlots(size int) *workerSlots {
+	s := &workerSlots{waiting: make(map[int]int)}
+	s.cond = sync.NewCond(&s.mu)
+	for i := size - 1; i >= 0; i-- {
+		s.idle = append(s.idle, i)
+	}
+	return s
+}
+
+// wait blocks until a worker is idle for a task of the given priority and returns how many are
+func (s *workerSlots) wait(priority int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.awaitTurn(priority)
+	return len(s.idle)
+}
+
+// acquire blocks until a worker is idle for a task of the given priority and takes it
+func (s *workerSlots) acquire(priority int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.awaitTurn(priority)
+	worker := s.idle[len(s.idle)-1]
+	s.idle = s.idle[:len(s.idle)-1]
+	return worker
+}
+
+// release hands a worker back once its task is done
+func (s *workerSlots) release(worker int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.idle = append(s.idle, worker)
+	s.cond.Broadcast()
+}
+
+// awaitTurn waits, with the lock held, until a worker is idle and no higher priority task type is
+// waiting for one
+func (s *workerSlots) awaitTurn(priority int) {
+	s.waiting[priority]++
+	for len(s.idle) == 0 || s.outranked(priority) {
+		s.cond.Wait()
+	}
+	s.waiting[priority]--
+	// Lower priority task types held back by this one may take the workers left
+	s.cond.Broadcast()
+}
+
+func (s *workerSlots) outranked(priority int) bool {
+	for p, n := range s.waiting {
+		if p > priority && n > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Stats returns the queue and execution counters of each registered task type
+func (c *HTTPConductorClient) Stats() []TaskTypeStats {
+	stats := make([]TaskTypeStats, 0, len(c.counters))
+	for taskType, counters := range c.counters {
+		stats = append(stats, TaskTypeStats{
+			TaskType:   taskType,
+			Pool:       counters.pool,
+			PoolSize:   counters.poolSize,
+			QueueDepth: counters.queueDepth.Load(),
+			InFlight:   counters.inFlight.Load(),
+			Executed:   counters.executed.Load(),
+			Failed:     counters.failed.Load(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].TaskType < stats[j].TaskType })
+	return stats
+}
+
+// reportQueueDepths refreshes the queue depth of every registered task type from Conductor on the
+// given interval and logs each task type's counters, until the client stops
+func (c *HTTPConductorClient) reportQueueDepths(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopChan:
+			return
+		case <-ticker.C:
+			if err := c.refreshQueueDepths(); err != nil {
+				c.logger.Warn("Failed to get task queue depths", zap.Error(err))
+			}
+			for _, stats := range c.Stats() {
+				c.logger.Info("Task queue metrics",
+					zap.String("task_type", stats.TaskType),
+					zap.String("pool", stats.Pool),
+					zap.Int("pool_size", stats.PoolSize),
+					zap.Int64("queue_depth", stats.QueueDepth),
+					zap.Int64("in_flight", stats.InFlight),
+					zap.Int64("executed", stats.Executed),
+					zap.Int64("failed", stats.Failed))
+			}
+		}
+	}
+}
+
+// refreshQueueDepths reads the number of tasks waiting in Conductor for each registered task type
+func (c *HTTPConductorClient) refreshQueueDepths() error {
+	query := url.Values{}
+	for taskType := range c.counters {
+		query.Add("taskType", taskType)
+	}
+	sizesURL := fmt.Sprintf("%s/api/tasks/queue/sizes?%s", c.baseURL, query.Encode())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", sizesURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create queue sizes request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to get queue sizes: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("queue sizes request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var sizes map[string]int64
+	if err := json.NewDecoder(resp.Body).Decode(&sizes); err != nil {
+		return fmt.Errorf("failed to decode queue sizes: %w", err)
+	}
+	for taskType, counters := range c.counters {
+		counters.queueDepth.Store(sizes[taskType])
+	}
+	return nil
+}
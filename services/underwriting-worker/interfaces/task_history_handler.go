@@ -0,0 +1,116 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	sharedmiddleware "github.com/huuhoait/los-demo/services/shared/pkg/middleware"
+
+	"underwriting_worker/domain"
+)
+
+// staffRoles are the back-office roles issued by the auth service that may read task history
+var staffRoles = map[string]bool{
+	"junior_reviewer": true,
+	"senior_reviewer": true,
+	"manager":         true,
+	"admin":           true,
+	"super_admin":     true,
+}
+
+// TaskHistoryHandler serves the history of the workflow tasks run for an application, so support
+// can see which underwriting steps ran and with what data without going to Conductor
+type TaskHistoryHandler struct {
+	taskRuns  domain.TaskRunRepository
+	jwtSecret string
+	logger    *zap.Logger
+}
+
+// NewTaskHistoryHandler creates a new task history handler; taskRuns is nil when the worker has
+// no database
+func NewTaskHistoryHandler(taskRuns domain.TaskRunRepository, jwtSecret string, logger *zap.Logger) *TaskHistoryHandler {
+	return &TaskHistoryHandler{
+		taskRuns:  taskRuns,
+		jwtSecret: jwtSecret,
+		logger:    logger,
+	}
+}
+
+// RegisterRoutes registers the task history routes
+func (h *TaskHistoryHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/underwriting/applications/{id}/tasks", h.requireStaff(h.ListApplicationTasks))
+}
+
+// ListApplicationTasks returns every task run for an application, oldest first
+func (h *TaskHistoryHandler) ListApplicationTasks(w http.ResponseWriter, r *http.Request) {
+	applicationID := r.PathValue("id")
+	logger := h.logger.With(
+		zap.String("operation", "list_application_tasks"),
+		zap.String("application_id", applicationID),
+	)
+
+	if h.taskRuns == nil {
+		writeError(w, http.StatusServiceUnavailable, "task history is not available")
+		return
+	}
+
+	runs, err := h.taskRuns.ListByApplicationID(r.Context(), applicationID)
+	if err != nil {
+		logger.Error("Failed to list task runs", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to list task runs")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    runs,
+		"metadata": map[string]interface{}{
+			"application_id": applicationID,
+			"count":          len(runs),
+			"timestamp":      time.Now().UTC().Format(time.RFC3339),
+		},
+	})
+}
+
+// requireStaff rejects requests without a valid access token for a back-office role
+func (h *TaskHistoryHandler) requireStaff(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		token := strings.TrimPrefix(header, "Bearer ")
+		if header == "" || token == header {
+			writeError(w, http.StatusUnauthorized, "invalid authorization token")
+			return
+		}
+
+		claims, err := sharedmiddleware.ParseAccessToken(token, h.jwtSecret, "los-api")
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, "invalid authorization token")
+			return
+		}
+		if !staffRoles[claims.Role] {
+			writeError(w, http.StatusForbidden, "insufficient permissions")
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// writeError writes an error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"success": false,
+		"error":   map[string]interface{}{"message": message},
+	})
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,502 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// passwordResetTokenTTL bounds how long an admin-forced reset link stays usable
+const passwordResetTokenTTL = 24 * time.Hour
+
+// Admin account management for UserServiceImpl
+
+// SuspendUser blocks a user's account and revokes their auth sessions
+func (s *UserServiceImpl) SuspendUser(ctx context.Context, userID, actorID, reason string) (*domain.User, error) {
+	logger := s.logger.With(
+		zap.String("operation", "suspend_user"),
+		zap.String("user_id", userID),
+		zap.String("actor_id", actorID),
+	)
+
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Status == domain.UserStatusSuspended {
+		return nil, &domain.UserError{
+			Code:    domain.USER_054,
+			Message: s.localizer.Localize(ctx, domain.USER_054, nil),
+			Field:   "status",
+		}
+	}
+
+	// Block login first so a failed propagation never leaves a suspended user able to sign in
+	if err := s.authAccounts.DeactivateAccount(ctx, userID); err != nil {
+		logger.Error("Failed to deactivate auth account", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_034,
+			Message: s.localizer.Localize(ctx, domain.USER_034, nil),
+		}
+	}
+
+	previousStatus := user.Status
+	if err := s.setUserStatus(ctx, userID, domain.UserStatusSuspended); err != nil {
+		logger.Error("Failed to suspend user", zap.Error(err))
+		return nil, err
+	}
+
+	if err := s.auditService.LogSecurityEvent(ctx, userID, "user_suspended", map[string]interface{}{
+		"actor_id":        actorID,
+		"reason":          reason,
+		"previous_status": previousStatus,
+	}); err != nil {
+		logger.Warn("Failed to log audit event", zap.Error(err))
+	}
+
+	user.Status = domain.UserStatusSuspended
+	logger.Info("User suspended successfully")
+	return user, nil
+}
+
+// ReactivateUser lifts a suspension and restores the user's ability to log in
+func (s *UserServiceImpl) ReactivateUser(ctx context.Context, userID, actorID string) (*domain.User, error) {
+	logger := s.logger.With(
+		zap.String("operation", "reactivate_user"),
+		zap.String("user_id", userID),
+		zap.String("actor_id", actorID),
+	)
+
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Status != domain.UserStatusSuspended {
+		return nil, &domain.UserError{
+			Code:    domain.USER_054,
+			Message: s.localizer.Localize(ctx, domain.USER_054, nil),
+			Field:   "status",
+		}
+	}
+
+	if err := s.setUserStatus(ctx, userID, domain.UserStatusActive); err != nil {
+		logger.Error("Failed to reactivate user", zap.Error(err))
+		return nil, err
+	}
+
+	if err := s.authAccounts.ReactivateAccount(ctx, userID); err != nil {
+		logger.Error("Failed to reactivate auth account", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_034,
+			Message: s.localizer.Localize(ctx, domain.USER_034, nil),
+		}
+	}
+
+	if err := s.auditService.LogSecurityEvent(ctx, userID, "user_reactivated", map[string]interface{}{
+		"actor_id": actorID,
+	}); err != nil {
+		logger.Warn("Failed to log audit event", zap.Error(err))
+	}
+
+	user.Status = domain.UserStatusActive
+	logger.Info("User reactivated successfully")
+	return user, nil
+}
+
+// ForcePasswordReset revokes the user's password and sessions in auth, then emails a one-time
+// reset token; only its hash is stored
+func (s *UserServiceImpl) ForcePasswordReset(ctx context.Context, userID, actorID string) error {
+	logger := s.logger.With(
+		zap.String("operation", "force_password_reset"),
+		zap.String("user_id", userID),
+		zap.String("actor_id", actorID),
+	)
+
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// Lock out the old password first; a failed email then still leaves the account secured
+	if err := s.authAccounts.RevokeCredentials(ctx, userID); err != nil {
+		logger.Error("Failed to revoke auth credentials", zap.Error(err))
+		return &domain.UserError{
+			Code:    domain.USER_034,
+			Message: s.localizer.Localize(ctx, domain.USER_034, nil),
+		}
+	}
+
+	token, err := generateResetToken()
+	if err != nil {
+		logger.Error("Failed to generate reset token", zap.Error(err))
+		return &domain.UserError{
+			Code:    domain.USER_028,
+			Message: s.localizer.Localize(ctx, domain.USER_028, nil),
+		}
+	}
+
+	tokenHash := sha256.Sum256([]byte(token))
+	expiresAt := time.Now().Add(passwordResetTokenTTL)
+	if err := s.userRepo.UpdateUser(ctx, userID, map[string]interface{}{
+		"password_reset_token":   hex.EncodeToString(tokenHash[:]),
+		"password_reset_expires": expiresAt,
+		"updated_at":             time.Now(),
+	}); err != nil {
+		logger.Error("Failed to store reset token", zap.Error(err))
+		return &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	if err := s.notificationService.SendPasswordReset(ctx, userID, user.Email, token); err != nil {
+		logger.Error("Failed to send password reset", zap.Error(err))
+		return &domain.UserError{
+			Code:    domain.USER_029,
+			Message: s.localizer.Localize(ctx, domain.USER_029, nil),
+		}
+	}
+
+	if err := s.auditService.LogSecurityEvent(ctx, userID, "password_reset_forced", map[string]interface{}{
+		"actor_id":   actorID,
+		"expires_at": expiresAt,
+	}); err != nil {
+		logger.Warn("Failed to log audit event", zap.Error(err))
+	}
+
+	logger.Info("Password reset forced successfully")
+	return nil
+}
+
+// RedeemPasswordReset sets a new password in auth with a token issued by ForcePasswordReset.
+// The token is consumed before the password is set, so it cannot be used twice.
+func (s *UserServiceImpl) RedeemPasswordReset(ctx context.Context, request *domain.RedeemPasswordResetRequest) error {
+	logger := s.logger.With(zap.String("operation", "redeem_password_reset"))
+
+	tokenHash := sha256.Sum256([]byte(request.Token))
+	userID, err := s.userRepo.ConsumePasswordResetToken(ctx, hex.EncodeToString(tokenHash[:]))
+	if err != nil {
+		logger.Error("Failed to consume reset token", zap.Error(err))
+		return &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+	if userID == "" {
+		return &domain.UserError{
+			Code:    domain.USER_064,
+			Message: s.localizer.Localize(ctx, domain.USER_064, nil),
+			Field:   "token",
+		}
+	}
+	logger = logger.With(zap.String("user_id", userID))
+
+	if err := s.authAccounts.SetPassword(ctx, userID, request.Password); err != nil {
+		logger.Error("Failed to set auth password", zap.Error(err))
+		return &domain.UserError{
+			Code:    domain.USER_034,
+			Message: s.localizer.Localize(ctx, domain.USER_034, nil),
+		}
+	}
+
+	if err := s.auditService.LogSecurityEvent(ctx, userID, "password_reset_redeemed", nil); err != nil {
+		logger.Warn("Failed to log audit event", zap.Error(err))
+	}
+
+	logger.Info("Password reset redeemed successfully")
+	return nil
+}
+
+// GetKYCHistory returns the user's KYC verifications together with their recorded status changes
+func (s *UserServiceImpl) GetKYCHistory(ctx context.Context, userID string) (*domain.KYCHistory, error) {
+	logger := s.logger.With(
+		zap.String("operation", "get_kyc_history"),
+		zap.String("user_id", userID),
+	)
+
+	if _, err := s.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	verifications, err := s.kycRepo.ListKYCVerifications(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to list KYC verifications", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	entries, err := s.auditService.GetAuditHistory(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to get audit history", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	events := make([]*domain.AuditEntry, 0)
+	for _, entry := range entries {
+		if entry.Category == domain.AuditCategoryKYC {
+			events = append(events, entry)
+		}
+	}
+
+	return &domain.KYCHistory{
+		UserID:        userID,
+		Verifications: verifications,
+		Events:        events,
+	}, nil
+}
+
+// AddUserNote attaches an internal admin note to a user
+func (s *UserServiceImpl) AddUserNote(ctx context.Context, userID, authorID, note string) (*domain.UserNote, error) {
+	logger := s.logger.With(
+		zap.String("operation", "add_user_note"),
+		zap.String("user_id", userID),
+		zap.String("author_id", authorID),
+	)
+
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, &domain.UserError{
+			Code:    domain.USER_005,
+			Message: s.localizer.Localize(ctx, domain.USER_005, nil),
+			Field:   "note",
+		}
+	}
+
+	if _, err := s.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	userNote := &domain.UserNote{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		AuthorID:  authorID,
+		Note:      note,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.noteRepo.CreateNote(ctx, userNote); err != nil {
+		logger.Error("Failed to create user note", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	if err := s.auditService.LogSecurityEvent(ctx, userID, "user_annotated", map[string]interface{}{
+		"actor_id": authorID,
+		"note_id":  userNote.ID,
+	}); err != nil {
+		logger.Warn("Failed to log audit event", zap.Error(err))
+	}
+
+	logger.Info("User note added successfully", zap.String("note_id", userNote.ID))
+	return userNote, nil
+}
+
+// GetUserNotes returns a user's admin notes, newest first
+func (s *UserServiceImpl) GetUserNotes(ctx context.Context, userID string) ([]*domain.UserNote, error) {
+	if _, err := s.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	notes, err := s.noteRepo.ListNotes(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to list user notes", zap.String("user_id", userID), zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	return notes, nil
+}
+
+// ExportAuditHistory returns the user's full audit trail; the export itself is recorded as a data access
+func (s *UserServiceImpl) ExportAuditHistory(ctx context.Context, userID, actorID string) ([]*domain.AuditEntry, error) {
+	logger := s.logger.With(
+		zap.String("operation", "export_audit_history"),
+		zap.String("user_id", userID),
+		zap.String("actor_id", actorID),
+	)
+
+	entries, err := s.auditService.GetAuditHistory(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to get audit history", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	if err := s.auditService.LogDataAccess(ctx, userID, actorID, "audit_history"); err != nil {
+		logger.Warn("Failed to log audit event", zap.Error(err))
+	}
+
+	logger.Info("Audit history exported", zap.Int("entries", len(entries)))
+	return entries, nil
+}
+
+// SetLegalHold places or lifts a legal hold; while held, the user's documents are exempt from retention purges
+func (s *UserServiceImpl) SetLegalHold(ctx context.Context, userID, actorID string, request *domain.LegalHoldRequest) (*domain.LegalHold, error) {
+	logger := s.logger.With(
+		zap.String("operation", "set_legal_hold"),
+		zap.String("user_id", userID),
+		zap.String("actor_id", actorID),
+	)
+
+	hold := *request.Hold
+	reason := strings.TrimSpace(request.Reason)
+	if hold && reason == "" {
+		return nil, &domain.UserError{
+			Code:    domain.USER_005,
+			Message: s.localizer.Localize(ctx, domain.USER_005, nil),
+			Field:   "reason",
+		}
+	}
+
+	if _, err := s.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	legalHold := &domain.LegalHold{
+		UserID: userID,
+		Hold:   hold,
+		Reason: reason,
+		SetBy:  actorID,
+		SetAt:  &now,
+	}
+
+	if err := s.userRepo.SetLegalHold(ctx, legalHold); err != nil {
+		logger.Error("Failed to set legal hold", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	eventType := "legal_hold_released"
+	if hold {
+		eventType = "legal_hold_placed"
+	}
+	if err := s.auditService.LogSecurityEvent(ctx, userID, eventType, map[string]interface{}{
+		"actor_id": actorID,
+		"reason":   reason,
+	}); err != nil {
+		logger.Warn("Failed to log audit event", zap.Error(err))
+	}
+
+	logger.Info("Legal hold updated", zap.Bool("hold", hold))
+	return legalHold, nil
+}
+
+// QueryAuditEvents searches the audit store for compliance review; the search itself is audited
+func (s *UserServiceImpl) QueryAuditEvents(ctx context.Context, actorID string, query *domain.AuditQuery) ([]*domain.AuditEntry, error) {
+	logger := s.logger.With(
+		zap.String("operation", "query_audit_events"),
+		zap.String("actor_id", actorID),
+	)
+
+	if query.Limit <= 0 {
+		query.Limit = 100
+	}
+	if query.Limit > 1000 || query.Offset < 0 {
+		return nil, &domain.UserError{
+			Code:    domain.USER_042,
+			Message: s.localizer.Localize(ctx, domain.USER_042, nil),
+			Field:   "limit",
+		}
+	}
+	if query.UserID != "" {
+		if _, err := uuid.Parse(query.UserID); err != nil {
+			return nil, &domain.UserError{
+				Code:    domain.USER_042,
+				Message: s.localizer.Localize(ctx, domain.USER_042, nil),
+				Field:   "user_id",
+			}
+		}
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.From.After(query.To) {
+		return nil, &domain.UserError{
+			Code:    domain.USER_042,
+			Message: s.localizer.Localize(ctx, domain.USER_042, nil),
+			Field:   "from",
+		}
+	}
+
+	entries, err := s.auditService.QueryAuditEvents(ctx, query)
+	if err != nil {
+		logger.Error("Failed to query audit events", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	if err := s.auditService.LogSecurityEvent(ctx, query.UserID, "audit_queried", map[string]interface{}{
+		"actor_id":   actorID,
+		"event_type": query.EventType,
+		"filter_by":  query.ActorID,
+		"results":    len(entries),
+	}); err != nil {
+		logger.Warn("Failed to log audit event", zap.Error(err))
+	}
+
+	return entries, nil
+}
+
+// VerifyAuditChain re-walks the audit hash chain and reports the first broken link, if any
+func (s *UserServiceImpl) VerifyAuditChain(ctx context.Context) (*domain.AuditChainVerification, error) {
+	result, err := s.auditService.VerifyChain(ctx)
+	if err != nil {
+		s.logger.Error("Failed to verify audit chain", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	return result, nil
+}
+
+func (s *UserServiceImpl) setUserStatus(ctx context.Context, userID, status string) error {
+	if err := s.userRepo.UpdateUser(ctx, userID, map[string]interface{}{
+		"status":     status,
+		"updated_at": time.Now(),
+	}); err != nil {
+		return &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	if err := s.cacheService.InvalidateUserCache(ctx, userID); err != nil {
+		s.logger.Warn("Failed to invalidate user cache", zap.String("user_id", userID), zap.Error(err))
+	}
+	return nil
+}
+
+// generateResetToken returns a 256-bit random URL-safe token
+func generateResetToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate reset token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
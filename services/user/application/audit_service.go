@@ -0,0 +1,141 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// auditVerifyBatchSize bounds how many entries are loaded at a time while re-walking the chain
+const auditVerifyBatchSize = 500
+
+// AuditServiceImpl records audit events to the append-only, hash-chained audit store
+type AuditServiceImpl struct {
+	auditRepo domain.AuditRepository
+	logger    *zap.Logger
+}
+
+func NewAuditService(auditRepo domain.AuditRepository, logger *zap.Logger) domain.AuditService {
+	return &AuditServiceImpl{
+		auditRepo: auditRepo,
+		logger:    logger,
+	}
+}
+
+func (a *AuditServiceImpl) LogUserCreated(ctx context.Context, userID, email string, metadata map[string]interface{}) error {
+	return a.record(ctx, userID, "user_created", domain.AuditCategoryProfile, metadata)
+}
+
+func (a *AuditServiceImpl) LogUserUpdated(ctx context.Context, userID string, changes map[string]interface{}) error {
+	return a.record(ctx, userID, "user_updated", domain.AuditCategoryProfile, changes)
+}
+
+func (a *AuditServiceImpl) LogProfileUpdated(ctx context.Context, userID string, changes map[string]interface{}) error {
+	return a.record(ctx, userID, "profile_updated", domain.AuditCategoryProfile, changes)
+}
+
+func (a *AuditServiceImpl) LogDocumentUploaded(ctx context.Context, userID, documentID, documentType string) error {
+	return a.record(ctx, userID, "document_uploaded", domain.AuditCategoryDocument, map[string]interface{}{
+		"document_id":   documentID,
+		"document_type": documentType,
+	})
+}
+
+func (a *AuditServiceImpl) LogKYCStatusChanged(ctx context.Context, userID, verificationType string, oldStatus, newStatus domain.KYCStatus) error {
+	return a.record(ctx, userID, "kyc_status_changed", domain.AuditCategoryKYC, map[string]interface{}{
+		"verification_type": verificationType,
+		"old_status":        oldStatus,
+		"new_status":        newStatus,
+	})
+}
+
+func (a *AuditServiceImpl) LogSecurityEvent(ctx context.Context, userID, eventType string, metadata map[string]interface{}) error {
+	return a.record(ctx, userID, eventType, domain.AuditCategorySecurity, metadata)
+}
+
+func (a *AuditServiceImpl) LogDataAccess(ctx context.Context, userID, accessedBy, dataType string) error {
+	return a.record(ctx, userID, "data_accessed", domain.AuditCategorySecurity, map[string]interface{}{
+		"actor_id":  accessedBy,
+		"data_type": dataType,
+	})
+}
+
+func (a *AuditServiceImpl) GetAuditHistory(ctx context.Context, userID string) ([]*domain.AuditEntry, error) {
+	return a.auditRepo.QueryEntries(ctx, &domain.AuditQuery{UserID: userID})
+}
+
+func (a *AuditServiceImpl) QueryAuditEvents(ctx context.Context, query *domain.AuditQuery) ([]*domain.AuditEntry, error) {
+	return a.auditRepo.QueryEntries(ctx, query)
+}
+
+// VerifyChain re-walks the whole chain from the first entry, recomputing every hash and link
+func (a *AuditServiceImpl) VerifyChain(ctx context.Context) (*domain.AuditChainVerification, error) {
+	result := &domain.AuditChainVerification{Valid: true}
+
+	var afterSequence int64
+	prevHash := ""
+	for {
+		entries, err := a.auditRepo.ListEntriesAfter(ctx, afterSequence, auditVerifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			break
+		}
+
+		for _, entry := range entries {
+			result.EntriesChecked++
+			if entry.PrevHash != prevHash || entry.ComputeHash() != entry.Hash {
+				sequence := entry.Sequence
+				result.Valid = false
+				result.BrokenAt = &sequence
+				a.logger.Error("Audit chain verification failed", zap.Int64("sequence", sequence), zap.String("entry_id", entry.ID))
+				result.VerifiedAt = time.Now()
+				return result, nil
+			}
+			prevHash = entry.Hash
+			afterSequence = entry.Sequence
+		}
+	}
+
+	result.VerifiedAt = time.Now()
+	return result, nil
+}
+
+// record appends an event; an actor_id in metadata is lifted onto the entry so it can be queried
+func (a *AuditServiceImpl) record(ctx context.Context, userID, eventType, category string, metadata map[string]interface{}) error {
+	entry := &domain.AuditEntry{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		EventType: eventType,
+		Category:  category,
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+	if actorID, ok := metadata["actor_id"].(string); ok {
+		entry.ActorID = actorID
+	}
+	if len(metadata) > 0 {
+		encoded, err := json.Marshal(metadata)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit metadata: %w", err)
+		}
+		entry.Metadata = encoded
+	}
+
+	if err := a.auditRepo.AppendEntry(ctx, entry); err != nil {
+		a.logger.Error("Failed to append audit event",
+			zap.String("user_id", userID),
+			zap.String("event_type", eventType),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,442 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// coApplicantInviteTTL bounds how long an emailed co-applicant invitation can be accepted
+const coApplicantInviteTTL = 7 * 24 * time.Hour
+
+// Co-applicant management for UserServiceImpl
+
+// InviteCoApplicant creates a pending link and emails the invitee a single-use token
+func (s *UserServiceImpl) InviteCoApplicant(ctx context.Context, userID string, request *domain.InviteCoApplicantRequest) (*domain.CoApplicantLink, error) {
+	logger := s.logger.With(
+		zap.String("operation", "invite_co_applicant"),
+		zap.String("user_id", userID),
+	)
+
+	if !request.Relationship.IsValid() {
+		return nil, &domain.UserError{
+			Code:    domain.USER_058,
+			Message: s.localizer.Localize(ctx, domain.USER_058, nil),
+			Field:   "relationship",
+		}
+	}
+	if err := s.validationService.ValidateEmail(request.Email); err != nil {
+		return nil, &domain.UserError{
+			Code:    domain.USER_001,
+			Message: s.localizer.Localize(ctx, domain.USER_001, nil),
+			Field:   "email",
+		}
+	}
+
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if domain.NormalizeEmail(user.Email) == domain.NormalizeEmail(request.Email) {
+		return nil, &domain.UserError{
+			Code:    domain.USER_061,
+			Message: s.localizer.Localize(ctx, domain.USER_061, nil),
+			Field:   "email",
+		}
+	}
+
+	token, err := generateResetToken()
+	if err != nil {
+		logger.Error("Failed to generate invite token", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_028,
+			Message: s.localizer.Localize(ctx, domain.USER_028, nil),
+		}
+	}
+
+	now := time.Now()
+	link := &domain.CoApplicantLink{
+		ID:                             uuid.New().String(),
+		PrimaryUserID:                  userID,
+		InviteEmail:                    request.Email,
+		Relationship:                   request.Relationship,
+		Status:                         domain.CoApplicantStatusPending,
+		InviteTokenHash:                hashInviteToken(token),
+		InviteExpiresAt:                now.Add(coApplicantInviteTTL),
+		PrimarySharedDocumentTypes:     domain.DocumentTypeList{},
+		CoApplicantSharedDocumentTypes: domain.DocumentTypeList{},
+		CreatedAt:                      now,
+		UpdatedAt:                      now,
+	}
+
+	if err := s.coApplicantRepo.CreateLink(ctx, link); err != nil {
+		if err.Error() == "already exists" {
+			return nil, &domain.UserError{
+				Code:    domain.USER_059,
+				Message: s.localizer.Localize(ctx, domain.USER_059, nil),
+				Field:   "email",
+			}
+		}
+		logger.Error("Failed to create co-applicant link", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	if err := s.notificationService.SendCoApplicantInvite(ctx, userID, request.Email, token); err != nil {
+		logger.Error("Failed to send co-applicant invite", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_029,
+			Message: s.localizer.Localize(ctx, domain.USER_029, nil),
+		}
+	}
+
+	if err := s.auditService.LogSecurityEvent(ctx, userID, "co_applicant_invited", map[string]interface{}{
+		"link_id":      link.ID,
+		"relationship": link.Relationship,
+	}); err != nil {
+		logger.Warn("Failed to log audit event", zap.Error(err))
+	}
+
+	logger.Info("Co-applicant invited", zap.String("link_id", link.ID))
+	return link, nil
+}
+
+// AcceptCoApplicantInvite attaches the caller to the link; the invite must be addressed to their verified email
+func (s *UserServiceImpl) AcceptCoApplicantInvite(ctx context.Context, userID, token string) (*domain.CoApplicantLink, error) {
+	return s.respondToInvite(ctx, userID, token, domain.CoApplicantStatusAccepted)
+}
+
+func (s *UserServiceImpl) DeclineCoApplicantInvite(ctx context.Context, userID, token string) (*domain.CoApplicantLink, error) {
+	return s.respondToInvite(ctx, userID, token, domain.CoApplicantStatusDeclined)
+}
+
+func (s *UserServiceImpl) respondToInvite(ctx context.Context, userID, token string, status domain.CoApplicantStatus) (*domain.CoApplicantLink, error) {
+	logger := s.logger.With(
+		zap.String("operation", "respond_co_applicant_invite"),
+		zap.String("user_id", userID),
+		zap.String("status", string(status)),
+	)
+
+	link, err := s.coApplicantRepo.GetLinkByTokenHash(ctx, hashInviteToken(token))
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, &domain.UserError{
+				Code:    domain.USER_060,
+				Message: s.localizer.Localize(ctx, domain.USER_060, nil),
+			}
+		}
+		logger.Error("Failed to get co-applicant invite", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+	if link.Status != domain.CoApplicantStatusPending || time.Now().After(link.InviteExpiresAt) {
+		return nil, &domain.UserError{
+			Code:    domain.USER_060,
+			Message: s.localizer.Localize(ctx, domain.USER_060, nil),
+		}
+	}
+
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Only the verified owner of the invited address can act on the invite, which is what makes the link a verified identity
+	if link.PrimaryUserID == userID || !user.EmailVerified ||
+		domain.NormalizeEmail(user.Email) != domain.NormalizeEmail(link.InviteEmail) {
+		logger.Warn("Co-applicant invite used by a different account", zap.String("link_id", link.ID))
+		return nil, &domain.UserError{
+			Code:    domain.USER_061,
+			Message: s.localizer.Localize(ctx, domain.USER_061, nil),
+		}
+	}
+
+	now := time.Now()
+	link.Status = status
+	link.RespondedAt = &now
+	link.UpdatedAt = now
+	if status == domain.CoApplicantStatusAccepted {
+		link.CoApplicantUserID = userID
+	}
+
+	if err := s.coApplicantRepo.UpdateLink(ctx, link); err != nil {
+		logger.Error("Failed to update co-applicant link", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	for _, party := range []string{link.PrimaryUserID, userID} {
+		if err := s.auditService.LogSecurityEvent(ctx, party, "co_applicant_"+string(status), map[string]interface{}{
+			"actor_id":     userID,
+			"link_id":      link.ID,
+			"relationship": link.Relationship,
+		}); err != nil {
+			logger.Warn("Failed to log audit event", zap.Error(err))
+		}
+	}
+
+	logger.Info("Co-applicant invite answered", zap.String("link_id", link.ID))
+	return link, nil
+}
+
+// RevokeCoApplicantLink ends a pending or active link; either party may revoke it
+func (s *UserServiceImpl) RevokeCoApplicantLink(ctx context.Context, userID, linkID string) (*domain.CoApplicantLink, error) {
+	logger := s.logger.With(
+		zap.String("operation", "revoke_co_applicant_link"),
+		zap.String("user_id", userID),
+		zap.String("link_id", linkID),
+	)
+
+	link, err := s.getLinkForUser(ctx, userID, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.Status != domain.CoApplicantStatusPending && link.Status != domain.CoApplicantStatusAccepted {
+		return nil, &domain.UserError{
+			Code:    domain.USER_054,
+			Message: s.localizer.Localize(ctx, domain.USER_054, nil),
+		}
+	}
+
+	link.Status = domain.CoApplicantStatusRevoked
+	link.UpdatedAt = time.Now()
+	if err := s.coApplicantRepo.UpdateLink(ctx, link); err != nil {
+		logger.Error("Failed to revoke co-applicant link", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	if err := s.auditService.LogSecurityEvent(ctx, userID, "co_applicant_revoked", map[string]interface{}{
+		"actor_id": userID,
+		"link_id":  link.ID,
+	}); err != nil {
+		logger.Warn("Failed to log audit event", zap.Error(err))
+	}
+
+	logger.Info("Co-applicant link revoked")
+	return link, nil
+}
+
+func (s *UserServiceImpl) ListCoApplicants(ctx context.Context, userID string) ([]*domain.CoApplicantLink, error) {
+	links, err := s.coApplicantRepo.ListLinksForUser(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to list co-applicants", zap.Error(err), zap.String("user_id", userID))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	return links, nil
+}
+
+// UpdateDocumentSharing replaces the document types the caller shares with the other party on the link
+func (s *UserServiceImpl) UpdateDocumentSharing(ctx context.Context, userID, linkID string, documentTypes []string) (*domain.CoApplicantLink, error) {
+	logger := s.logger.With(
+		zap.String("operation", "update_document_sharing"),
+		zap.String("user_id", userID),
+		zap.String("link_id", linkID),
+	)
+
+	link, err := s.getLinkForUser(ctx, userID, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.Status != domain.CoApplicantStatusPending && link.Status != domain.CoApplicantStatusAccepted {
+		return nil, &domain.UserError{
+			Code:    domain.USER_054,
+			Message: s.localizer.Localize(ctx, domain.USER_054, nil),
+		}
+	}
+
+	shared := make(domain.DocumentTypeList, 0, len(documentTypes))
+	for _, documentType := range documentTypes {
+		if err := s.validationService.ValidateDocumentType(documentType); err != nil {
+			return nil, &domain.UserError{
+				Code:    domain.USER_017,
+				Message: s.localizer.Localize(ctx, domain.USER_017, nil),
+				Field:   "document_types",
+			}
+		}
+		if !shared.Contains(documentType) {
+			shared = append(shared, documentType)
+		}
+	}
+
+	if link.PrimaryUserID == userID {
+		link.PrimarySharedDocumentTypes = shared
+	} else {
+		link.CoApplicantSharedDocumentTypes = shared
+	}
+	link.UpdatedAt = time.Now()
+
+	if err := s.coApplicantRepo.UpdateLink(ctx, link); err != nil {
+		logger.Error("Failed to update document sharing", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	if err := s.auditService.LogSecurityEvent(ctx, userID, "document_sharing_updated", map[string]interface{}{
+		"actor_id":       userID,
+		"link_id":        link.ID,
+		"document_types": shared,
+	}); err != nil {
+		logger.Warn("Failed to log audit event", zap.Error(err))
+	}
+
+	return link, nil
+}
+
+// GetSharedDocuments lists the other party's documents of the types they chose to share; the link must be accepted
+func (s *UserServiceImpl) GetSharedDocuments(ctx context.Context, userID, linkID string) ([]*domain.Document, error) {
+	logger := s.logger.With(
+		zap.String("operation", "get_shared_documents"),
+		zap.String("user_id", userID),
+		zap.String("link_id", linkID),
+	)
+
+	link, err := s.getLinkForUser(ctx, userID, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.Status != domain.CoApplicantStatusAccepted {
+		return nil, &domain.UserError{
+			Code:    domain.USER_053,
+			Message: s.localizer.Localize(ctx, domain.USER_053, nil),
+		}
+	}
+
+	ownerID := link.Counterpart(userID)
+	visible := link.CoApplicantSharedDocumentTypes
+	if link.CoApplicantUserID == userID {
+		visible = link.PrimarySharedDocumentTypes
+	}
+
+	shared := make([]*domain.Document, 0)
+	if len(visible) == 0 {
+		return shared, nil
+	}
+
+	documents, err := s.documentRepo.GetDocumentsByUserID(ctx, ownerID)
+	if err != nil {
+		logger.Error("Failed to get documents", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+	for _, document := range documents {
+		if visible.Contains(document.DocumentType) {
+			shared = append(shared, document)
+		}
+	}
+
+	if err := s.auditService.LogDataAccess(ctx, ownerID, userID, "shared_documents"); err != nil {
+		logger.Warn("Failed to log audit event", zap.Error(err))
+	}
+
+	return shared, nil
+}
+
+// GetCoApplicantIdentities returns the parties actively linked to the user with their verification state,
+// so loan applications can reference every identity on a joint application
+func (s *UserServiceImpl) GetCoApplicantIdentities(ctx context.Context, userID string) ([]*domain.CoApplicantIdentity, error) {
+	links, err := s.ListCoApplicants(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	identities := make([]*domain.CoApplicantIdentity, 0, len(links))
+	for _, link := range links {
+		if link.Status != domain.CoApplicantStatusAccepted {
+			continue
+		}
+
+		identity := &domain.CoApplicantIdentity{
+			LinkID:       link.ID,
+			UserID:       link.Counterpart(userID),
+			Role:         "co_applicant",
+			Relationship: link.Relationship,
+		}
+		if identity.UserID == link.PrimaryUserID {
+			identity.Role = "primary"
+		}
+
+		user, err := s.userRepo.GetUserByID(ctx, identity.UserID)
+		if err != nil {
+			// A deleted party no longer counts as a linked identity
+			if err.Error() == "not found" {
+				continue
+			}
+			s.logger.Error("Failed to get co-applicant", zap.Error(err), zap.String("user_id", identity.UserID))
+			return nil, &domain.UserError{
+				Code:    domain.USER_026,
+				Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+			}
+		}
+		identity.EmailVerified = user.EmailVerified
+
+		kycStatus, err := s.kycRepo.GetKYCStatus(ctx, identity.UserID)
+		if err != nil && err.Error() != "not found" {
+			s.logger.Error("Failed to get co-applicant KYC status", zap.Error(err), zap.String("user_id", identity.UserID))
+			return nil, &domain.UserError{
+				Code:    domain.USER_026,
+				Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+			}
+		}
+		identity.KYCVerified = kycStatus["identity"] == domain.KYCStatusVerified
+
+		identities = append(identities, identity)
+	}
+
+	return identities, nil
+}
+
+// getLinkForUser loads a link the user is party to; links belonging to others are reported as not found
+func (s *UserServiceImpl) getLinkForUser(ctx context.Context, userID, linkID string) (*domain.CoApplicantLink, error) {
+	notFound := &domain.UserError{
+		Code:    domain.USER_062,
+		Message: s.localizer.Localize(ctx, domain.USER_062, nil),
+	}
+	if _, err := uuid.Parse(linkID); err != nil {
+		return nil, notFound
+	}
+
+	link, err := s.coApplicantRepo.GetLink(ctx, linkID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, notFound
+		}
+		s.logger.Error("Failed to get co-applicant link", zap.Error(err), zap.String("link_id", linkID))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+	if !link.Involves(userID) {
+		return nil, notFound
+	}
+
+	return link, nil
+}
+
+// hashInviteToken stores invite tokens the same way as password reset tokens, as a SHA-256 hex digest
+func hashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
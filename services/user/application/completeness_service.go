@@ -0,0 +1,128 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// completenessCacheTTL is how long a computed score is cached; profile and document changes invalidate it sooner
+const completenessCacheTTL = 3600 // seconds
+
+// completenessWeights assigns each profile item its share of the 100-point score
+var completenessWeights = []struct {
+	item   string
+	weight int
+}{
+	{domain.CompletenessItemPersonalInfo, 10},
+	{domain.CompletenessItemDateOfBirth, 10},
+	{domain.CompletenessItemSSN, 15},
+	{domain.CompletenessItemAddress, 15},
+	{domain.CompletenessItemEmployer, 20},
+	{domain.CompletenessItemIdentityDocument, 15},
+	{domain.CompletenessItemIncomeDocument, 15},
+}
+
+// Profile completeness methods for UserServiceImpl
+
+func (s *UserServiceImpl) GetProfileCompleteness(ctx context.Context, userID string) (*domain.ProfileCompleteness, error) {
+	logger := s.logger.With(
+		zap.String("operation", "get_profile_completeness"),
+		zap.String("user_id", userID),
+	)
+
+	// Try cache first
+	if cached, err := s.cacheService.GetCachedCompleteness(ctx, userID); err == nil && cached != nil {
+		logger.Debug("Completeness found in cache")
+		return cached, nil
+	}
+
+	profile, err := s.userRepo.GetProfile(ctx, userID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, &domain.UserError{
+				Code:    domain.USER_031,
+				Message: s.localizer.Localize(ctx, domain.USER_031, nil),
+			}
+		}
+		logger.Error("Failed to get profile", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	documents, err := s.documentRepo.GetDocumentsByUserID(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to get documents", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	completeness := scoreProfileCompleteness(profile, documents)
+	completeness.UserID = userID
+
+	if err := s.cacheService.CacheCompleteness(ctx, userID, completeness, completenessCacheTTL); err != nil {
+		logger.Warn("Failed to cache completeness", zap.Error(err))
+	}
+
+	return completeness, nil
+}
+
+// invalidateCompleteness drops a cached score after a change that can affect it
+func (s *UserServiceImpl) invalidateCompleteness(ctx context.Context, userID string) {
+	if err := s.cacheService.InvalidateCompleteness(ctx, userID); err != nil {
+		s.logger.Warn("Failed to invalidate completeness cache", zap.Error(err), zap.String("user_id", userID))
+	}
+}
+
+// scoreProfileCompleteness sums the weights of the profile items that are filled in
+func scoreProfileCompleteness(profile *domain.UserProfile, documents []*domain.Document) *domain.ProfileCompleteness {
+	hasIdentityDocument, hasIncomeDocument := false, false
+	for _, document := range documents {
+		switch document.DocumentType {
+		case domain.DocumentTypeDriversLicense, domain.DocumentTypePassport:
+			hasIdentityDocument = true
+		case domain.DocumentTypePayStub, domain.DocumentTypeBankStatement, domain.DocumentTypeW2, domain.DocumentType1099:
+			hasIncomeDocument = true
+		}
+	}
+
+	present := map[string]bool{
+		domain.CompletenessItemPersonalInfo: profile.FirstName != "" && profile.LastName != "" && profile.Phone != "",
+		domain.CompletenessItemDateOfBirth:  !profile.DateOfBirth.IsZero(),
+		domain.CompletenessItemSSN:          profile.SSNToken != "",
+		domain.CompletenessItemAddress: profile.Address.Street != "" && profile.Address.City != "" &&
+			profile.Address.State != "" && profile.Address.ZipCode != "",
+		domain.CompletenessItemEmployer:         profile.EmploymentInfo.EmployerName != "",
+		domain.CompletenessItemIdentityDocument: hasIdentityDocument,
+		domain.CompletenessItemIncomeDocument:   hasIncomeDocument,
+	}
+
+	completeness := &domain.ProfileCompleteness{
+		Items:        make([]domain.CompletenessItem, 0, len(completenessWeights)),
+		MissingItems: []string{},
+		CalculatedAt: time.Now(),
+	}
+	for _, w := range completenessWeights {
+		complete := present[w.item]
+		completeness.Items = append(completeness.Items, domain.CompletenessItem{
+			Item:     w.item,
+			Weight:   w.weight,
+			Complete: complete,
+		})
+		if complete {
+			completeness.Score += w.weight
+		} else {
+			completeness.MissingItems = append(completeness.MissingItems, w.item)
+		}
+	}
+	completeness.Complete = len(completeness.MissingItems) == 0
+
+	return completeness
+}
@@ -0,0 +1,224 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// Consent management methods for UserServiceImpl
+
+func (s *UserServiceImpl) RecordConsent(ctx context.Context, userID string, request *domain.RecordConsentRequest) (*domain.Consent, error) {
+	logger := s.logger.With(
+		zap.String("operation", "record_consent"),
+		zap.String("user_id", userID),
+		zap.String("consent_type", string(request.ConsentType)),
+		zap.String("version", request.Version),
+	)
+
+	if !request.ConsentType.IsValid() {
+		logger.Warn("Invalid consent type")
+		return nil, &domain.UserError{
+			Code:    domain.USER_036,
+			Message: s.localizer.Localize(ctx, domain.USER_036, nil),
+			Field:   "consent_type",
+		}
+	}
+
+	if strings.TrimSpace(request.Version) == "" {
+		return nil, &domain.UserError{
+			Code:    domain.USER_005,
+			Message: s.localizer.Localize(ctx, domain.USER_005, nil),
+			Field:   "version",
+		}
+	}
+
+	textHash, err := s.resolveConsentTextHash(request)
+	if err != nil {
+		logger.Warn("Consent text validation failed", zap.Error(err))
+		return nil, err
+	}
+
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		if err.Error() == "not found" {
+			return nil, &domain.UserError{
+				Code:    domain.USER_030,
+				Message: s.localizer.Localize(ctx, domain.USER_030, nil),
+			}
+		}
+		logger.Error("Failed to get user", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	now := time.Now()
+	consent := &domain.Consent{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		ConsentType: request.ConsentType,
+		Version:     strings.TrimSpace(request.Version),
+		TextHash:    textHash,
+		IPAddress:   request.IPAddress,
+		UserAgent:   request.UserAgent,
+		ConsentedAt: now,
+		CreatedAt:   now,
+	}
+
+	if err := s.consentRepo.CreateConsent(ctx, consent); err != nil {
+		logger.Error("Failed to store consent", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	if err := s.auditService.LogSecurityEvent(ctx, userID, "consent_recorded", map[string]interface{}{
+		"consent_id":   consent.ID,
+		"consent_type": consent.ConsentType,
+		"version":      consent.Version,
+		"text_hash":    consent.TextHash,
+		"ip_address":   consent.IPAddress,
+	}); err != nil {
+		logger.Warn("Failed to log consent audit event", zap.Error(err))
+	}
+
+	logger.Info("Consent recorded successfully", zap.String("consent_id", consent.ID))
+	return consent, nil
+}
+
+func (s *UserServiceImpl) GetConsents(ctx context.Context, userID string) ([]*domain.Consent, error) {
+	logger := s.logger.With(
+		zap.String("operation", "get_consents"),
+		zap.String("user_id", userID),
+	)
+
+	consents, err := s.consentRepo.ListConsents(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to list consents", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	return consents, nil
+}
+
+func (s *UserServiceImpl) GetConsent(ctx context.Context, userID string, consentType domain.ConsentType) (*domain.Consent, error) {
+	logger := s.logger.With(
+		zap.String("operation", "get_consent"),
+		zap.String("user_id", userID),
+		zap.String("consent_type", string(consentType)),
+	)
+
+	if !consentType.IsValid() {
+		return nil, &domain.UserError{
+			Code:    domain.USER_036,
+			Message: s.localizer.Localize(ctx, domain.USER_036, nil),
+			Field:   "consent_type",
+		}
+	}
+
+	consent, err := s.consentRepo.GetLatestConsent(ctx, userID, consentType)
+	if err != nil {
+		logger.Error("Failed to get consent", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	if consent == nil || !consent.IsActive() {
+		return nil, &domain.UserError{
+			Code:    domain.USER_037,
+			Message: s.localizer.Localize(ctx, domain.USER_037, nil),
+		}
+	}
+
+	return consent, nil
+}
+
+func (s *UserServiceImpl) RevokeConsent(ctx context.Context, userID string, consentType domain.ConsentType) error {
+	logger := s.logger.With(
+		zap.String("operation", "revoke_consent"),
+		zap.String("user_id", userID),
+		zap.String("consent_type", string(consentType)),
+	)
+
+	if !consentType.IsValid() {
+		return &domain.UserError{
+			Code:    domain.USER_036,
+			Message: s.localizer.Localize(ctx, domain.USER_036, nil),
+			Field:   "consent_type",
+		}
+	}
+
+	if err := s.consentRepo.RevokeConsent(ctx, userID, consentType); err != nil {
+		if err.Error() == "not found" {
+			return &domain.UserError{
+				Code:    domain.USER_037,
+				Message: s.localizer.Localize(ctx, domain.USER_037, nil),
+			}
+		}
+		logger.Error("Failed to revoke consent", zap.Error(err))
+		return &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	if err := s.auditService.LogSecurityEvent(ctx, userID, "consent_revoked", map[string]interface{}{
+		"consent_type": consentType,
+	}); err != nil {
+		logger.Warn("Failed to log consent audit event", zap.Error(err))
+	}
+
+	logger.Info("Consent revoked successfully")
+	return nil
+}
+
+// resolveConsentTextHash returns the SHA-256 hash of the disclosure the user agreed to.
+// When both text and hash are supplied they must match.
+func (s *UserServiceImpl) resolveConsentTextHash(request *domain.RecordConsentRequest) (string, error) {
+	providedHash := strings.ToLower(strings.TrimSpace(request.TextHash))
+
+	if request.ConsentText == "" {
+		if providedHash == "" {
+			return "", &domain.UserError{
+				Code:    domain.USER_038,
+				Message: s.localizer.Localize(context.Background(), domain.USER_038, nil),
+				Field:   "consent_text",
+			}
+		}
+		if _, err := hex.DecodeString(providedHash); err != nil || len(providedHash) != sha256.Size*2 {
+			return "", &domain.UserError{
+				Code:    domain.USER_038,
+				Message: s.localizer.Localize(context.Background(), domain.USER_038, nil),
+				Field:   "text_hash",
+			}
+		}
+		return providedHash, nil
+	}
+
+	sum := sha256.Sum256([]byte(request.ConsentText))
+	computedHash := hex.EncodeToString(sum[:])
+
+	if providedHash != "" && providedHash != computedHash {
+		return "", &domain.UserError{
+			Code:    domain.USER_063,
+			Message: s.localizer.Localize(context.Background(), domain.USER_063, nil),
+			Field:   "text_hash",
+		}
+	}
+
+	return computedHash, nil
+}
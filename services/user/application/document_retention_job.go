@@ -0,0 +1,212 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// DocumentRetentionJob purges documents whose retention period has elapsed. Files are deleted from
+// storage and their database rows are tombstoned, so the fact that a document existed stays auditable.
+// Users on legal hold are never purged.
+type DocumentRetentionJob struct {
+	documentRepo   domain.DocumentRepository
+	storageService domain.DocumentStorageService
+	loanAccounts   domain.LoanAccountService
+	auditService   domain.AuditService
+	rules          []domain.RetentionRule
+	batchSize      int
+	interval       time.Duration
+	logger         *zap.Logger
+}
+
+// DocumentRetentionResult summarizes a single purge pass
+type DocumentRetentionResult struct {
+	Scanned  int `json:"scanned"`
+	Purged   int `json:"purged"`
+	Retained int `json:"retained"`
+	Failures int `json:"failures"`
+}
+
+func NewDocumentRetentionJob(
+	documentRepo domain.DocumentRepository,
+	storageService domain.DocumentStorageService,
+	loanAccounts domain.LoanAccountService,
+	auditService domain.AuditService,
+	rules []domain.RetentionRule,
+	batchSize int,
+	interval time.Duration,
+	logger *zap.Logger,
+) *DocumentRetentionJob {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	// A misconfigured rule is dropped rather than guessed at, since guessing could delete too early
+	valid := make([]domain.RetentionRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.DocumentType == "" || rule.RetainMonths <= 0 ||
+			(rule.Anchor != domain.RetentionAnchorUpload && rule.Anchor != domain.RetentionAnchorLoanClosure) {
+			logger.Warn("Ignoring invalid document retention rule",
+				zap.String("document_type", rule.DocumentType),
+				zap.String("anchor", string(rule.Anchor)),
+				zap.Int("retain_months", rule.RetainMonths),
+			)
+			continue
+		}
+		valid = append(valid, rule)
+	}
+
+	return &DocumentRetentionJob{
+		documentRepo:   documentRepo,
+		storageService: storageService,
+		loanAccounts:   loanAccounts,
+		auditService:   auditService,
+		rules:          valid,
+		batchSize:      batchSize,
+		interval:       interval,
+		logger:         logger,
+	}
+}
+
+// Start runs purge passes on the configured interval until the context is cancelled
+func (j *DocumentRetentionJob) Start(ctx context.Context) {
+	if j.interval <= 0 || len(j.rules) == 0 {
+		j.logger.Info("Document retention job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Document retention job stopped")
+			return
+		case <-ticker.C:
+			if _, err := j.RunOnce(ctx); err != nil {
+				j.logger.Error("Document retention pass failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RunOnce applies every retention rule to the documents it covers
+func (j *DocumentRetentionJob) RunOnce(ctx context.Context) (*DocumentRetentionResult, error) {
+	logger := j.logger.With(zap.String("operation", "document_retention"))
+	result := &DocumentRetentionResult{}
+	now := time.Now()
+
+	// Loan activity is looked up once per user per pass
+	activities := make(map[string]*domain.LoanActivity)
+
+	for _, rule := range j.rules {
+		cutoff := now.AddDate(0, -rule.RetainMonths, 0)
+
+		// Every anchor is at or after upload, so only documents uploaded before the cutoff can be due
+		afterID := ""
+		for {
+			documents, err := j.documentRepo.ListRetentionCandidates(ctx, rule.DocumentType, cutoff, afterID, j.batchSize)
+			if err != nil {
+				return result, err
+			}
+			if len(documents) == 0 {
+				break
+			}
+			afterID = documents[len(documents)-1].ID
+
+			for _, document := range documents {
+				result.Scanned++
+
+				due, err := j.isDue(ctx, rule, cutoff, document, activities)
+				if err != nil {
+					logger.Warn("Failed to evaluate document retention",
+						zap.String("document_id", document.ID),
+						zap.String("user_id", document.UserID),
+						zap.Error(err),
+					)
+					result.Failures++
+					continue
+				}
+				if !due {
+					result.Retained++
+					continue
+				}
+
+				if err := j.purge(ctx, rule, document); err != nil {
+					logger.Warn("Failed to purge document", zap.String("document_id", document.ID), zap.Error(err))
+					result.Failures++
+					continue
+				}
+				result.Purged++
+			}
+
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+		}
+	}
+
+	logger.Info("Document retention pass completed",
+		zap.Int("scanned", result.Scanned),
+		zap.Int("purged", result.Purged),
+		zap.Int("retained", result.Retained),
+		zap.Int("failures", result.Failures),
+	)
+
+	return result, nil
+}
+
+// isDue reports whether the document's retention period, counted from the rule's anchor, ended before cutoff
+func (j *DocumentRetentionJob) isDue(ctx context.Context, rule domain.RetentionRule, cutoff time.Time, document *domain.Document, activities map[string]*domain.LoanActivity) (bool, error) {
+	if rule.Anchor == domain.RetentionAnchorUpload {
+		return document.CreatedAt.Before(cutoff), nil
+	}
+
+	activity, ok := activities[document.UserID]
+	if !ok {
+		var err error
+		activity, err = j.loanAccounts.GetLoanActivity(ctx, document.UserID)
+		if err != nil {
+			return false, err
+		}
+		activities[document.UserID] = activity
+	}
+
+	if activity.OpenApplications > 0 {
+		return false, nil
+	}
+	if activity.LastClosedAt != nil && !activity.LastClosedAt.Before(cutoff) {
+		return false, nil
+	}
+	return document.CreatedAt.Before(cutoff), nil
+}
+
+// purge deletes the stored file, then tombstones the row; a storage failure leaves the row for the next pass
+func (j *DocumentRetentionJob) purge(ctx context.Context, rule domain.RetentionRule, document *domain.Document) error {
+	if document.FilePath != "" {
+		if err := j.storageService.DeleteFile(ctx, document.FilePath); err != nil {
+			return fmt.Errorf("failed to delete stored file: %w", err)
+		}
+	}
+
+	reason := fmt.Sprintf("retention:%s:%s+%dm", rule.DocumentType, rule.Anchor, rule.RetainMonths)
+	if err := j.documentRepo.TombstoneDocument(ctx, document.ID, reason); err != nil {
+		return fmt.Errorf("failed to tombstone document: %w", err)
+	}
+
+	if err := j.auditService.LogSecurityEvent(ctx, document.UserID, "document_purged", map[string]interface{}{
+		"document_id":   document.ID,
+		"document_type": document.DocumentType,
+		"reason":        reason,
+	}); err != nil {
+		j.logger.Warn("Failed to log audit event", zap.String("document_id", document.ID), zap.Error(err))
+	}
+
+	return nil
+}
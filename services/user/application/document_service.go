@@ -45,8 +45,11 @@ func (s *UserServiceImpl) UploadDocument(ctx context.Context, userID string, doc
 		}
 	}
 
-	// Check for existing document of same type
-	existingDocs, err := s.documentRepo.GetDocumentsByType(ctx, userID, document.Type)
+	// Check for existing document of same type; loan documents are issued for every loan
+	var existingDocs []*domain.Document
+	if !domain.IsLoanDocumentType(document.Type) {
+		existingDocs, err = s.documentRepo.GetDocumentsByType(ctx, userID, document.Type)
+	}
 	if err != nil && err.Error() != "not found" {
 		logger.Error("Failed to check existing documents", zap.Error(err))
 		return nil, &domain.UserError{
@@ -125,11 +128,20 @@ func (s *UserServiceImpl) UploadDocument(ctx context.Context, userID string, doc
 		}
 	}
 
+	s.invalidateCompleteness(ctx, userID)
+
 	// Log audit event
 	if err := s.auditService.LogDocumentUploaded(ctx, userID, documentID, document.Type); err != nil {
 		logger.Warn("Failed to log document upload audit event", zap.Error(err))
 	}
 
+	s.publishWebhookEvent(ctx, domain.WebhookEventDocumentUploaded, map[string]interface{}{
+		"user_id":       userID,
+		"document_id":   documentID,
+		"document_type": document.Type,
+		"uploaded_at":   doc.CreatedAt,
+	})
+
 	logger.Info("Document uploaded successfully",
 		zap.String("document_id", documentID),
 		zap.String("storage_key", storageKey),
@@ -261,6 +273,14 @@ func (s *UserServiceImpl) DownloadDocument(ctx context.Context, userID, document
 		}
 	}
 
+	// Purged documents remain as tombstones with no stored file
+	if document.PurgedAt != nil {
+		return nil, &domain.UserError{
+			Code:    domain.USER_019,
+			Message: s.localizer.Localize(ctx, domain.USER_019, nil),
+		}
+	}
+
 	// Download from storage
 	fileReader, err := s.storageService.DownloadFile(ctx, document.FilePath)
 	if err != nil {
@@ -367,6 +387,8 @@ func (s *UserServiceImpl) DeleteDocument(ctx context.Context, userID, documentID
 		}
 	}
 
+	s.invalidateCompleteness(ctx, userID)
+
 	logger.Info("Document deleted successfully")
 	return nil
 }
@@ -404,24 +426,20 @@ func (s *UserServiceImpl) InitiateKYC(ctx context.Context, userID string) (*doma
 		}
 	}
 
-	// Check if KYC is already completed
-	kycStatus, err := s.kycRepo.GetKYCStatus(ctx, userID)
+	// Check if KYC is already completed; an expired result may be re-verified
+	existing, err := s.kycRepo.GetKYCVerification(ctx, userID, "identity")
 	if err != nil && err.Error() != "not found" {
-		logger.Error("Failed to get KYC status", zap.Error(err))
+		logger.Error("Failed to get KYC verification", zap.Error(err))
 		return nil, &domain.UserError{
 			Code:    domain.USER_026,
 			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
 		}
 	}
 
-	if kycStatus != nil {
-		for _, status := range kycStatus {
-			if status == domain.KYCStatusVerified {
-				return nil, &domain.UserError{
-					Code:    domain.USER_010,
-					Message: s.localizer.Localize(ctx, domain.USER_010, nil),
-				}
-			}
+	if existing != nil && existing.Status == domain.KYCStatusVerified && !existing.IsExpired(time.Now()) {
+		return nil, &domain.UserError{
+			Code:    domain.USER_010,
+			Message: s.localizer.Localize(ctx, domain.USER_010, nil),
 		}
 	}
 
@@ -435,23 +453,48 @@ func (s *UserServiceImpl) InitiateKYC(ctx context.Context, userID string) (*doma
 		}
 	}
 
-	// Create KYC verification record
-	verification := &domain.KYCVerification{
-		ID:                uuid.New().String(),
-		UserID:            userID,
-		VerificationType:  "identity",
-		Provider:          s.kycProvider.GetProviderName(),
-		Status:            domain.KYCStatusPending,
-		ProviderReference: session.ProviderReference,
-		CreatedAt:         time.Now(),
-		UpdatedAt:         time.Now(),
-	}
+	if existing != nil {
+		// Each user has one identity record, so a retry or re-verification restarts it in place
+		if err := s.kycRepo.UpdateKYCVerification(ctx, existing.ID, map[string]interface{}{
+			"provider":           s.kycProvider.GetProviderName(),
+			"status":             domain.KYCStatusPending,
+			"provider_reference": session.ProviderReference,
+			"expires_at":         nil,
+			"updated_at":         time.Now(),
+		}); err != nil {
+			logger.Error("Failed to restart KYC verification record", zap.Error(err))
+			return nil, &domain.UserError{
+				Code:    domain.USER_026,
+				Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+			}
+		}
 
-	if err := s.kycRepo.CreateKYCVerification(ctx, verification); err != nil {
-		logger.Error("Failed to create KYC verification record", zap.Error(err))
-		return nil, &domain.UserError{
-			Code:    domain.USER_026,
-			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		if err := s.cacheService.InvalidateKYCStatus(ctx, userID); err != nil {
+			logger.Warn("Failed to invalidate KYC status cache", zap.Error(err))
+		}
+
+		if err := s.auditService.LogKYCStatusChanged(ctx, userID, existing.VerificationType, existing.Status, domain.KYCStatusPending); err != nil {
+			logger.Warn("Failed to log KYC status change audit event", zap.Error(err))
+		}
+	} else {
+		// Create KYC verification record
+		verification := &domain.KYCVerification{
+			ID:                uuid.New().String(),
+			UserID:            userID,
+			VerificationType:  "identity",
+			Provider:          s.kycProvider.GetProviderName(),
+			Status:            domain.KYCStatusPending,
+			ProviderReference: session.ProviderReference,
+			CreatedAt:         time.Now(),
+			UpdatedAt:         time.Now(),
+		}
+
+		if err := s.kycRepo.CreateKYCVerification(ctx, verification); err != nil {
+			logger.Error("Failed to create KYC verification record", zap.Error(err))
+			return nil, &domain.UserError{
+				Code:    domain.USER_026,
+				Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+			}
 		}
 	}
 
@@ -523,10 +566,20 @@ func (s *UserServiceImpl) UpdateKYCStatus(ctx context.Context, userID, verificat
 	oldStatus := existingVerification.Status
 
 	// Update KYC verification
+	now := time.Now()
 	updates := map[string]interface{}{
 		"status":            status,
 		"verification_data": data,
-		"updated_at":        time.Now(),
+		"expires_at":        nil,
+		"updated_at":        now,
+	}
+
+	// Only a verified result expires, after the validity period of the provider that produced it
+	if status == domain.KYCStatusVerified {
+		updates["verified_at"] = now
+		if validity := s.kycExpiry.ValidityFor(existingVerification.Provider); validity > 0 {
+			updates["expires_at"] = now.Add(validity)
+		}
 	}
 
 	if err := s.kycRepo.UpdateKYCVerification(ctx, existingVerification.ID, updates); err != nil {
@@ -547,45 +600,69 @@ func (s *UserServiceImpl) UpdateKYCStatus(ctx context.Context, userID, verificat
 		logger.Warn("Failed to log KYC status change audit event", zap.Error(err))
 	}
 
+	if status == domain.KYCStatusVerified && oldStatus != domain.KYCStatusVerified {
+		s.publishWebhookEvent(ctx, domain.WebhookEventKYCVerified, map[string]interface{}{
+			"user_id":           userID,
+			"verification_type": verificationType,
+			"verified_at":       time.Now(),
+		})
+	}
+
 	logger.Info("KYC status updated successfully")
 	return nil
 }
 
-// Search and listing methods
-
-func (s *UserServiceImpl) SearchUsers(ctx context.Context, criteria map[string]interface{}, offset, limit int) ([]*domain.User, error) {
-	logger := s.logger.With(
-		zap.String("operation", "search_users"),
-		zap.Int("offset", offset),
-		zap.Int("limit", limit),
-	)
-
-	users, err := s.userRepo.SearchUsers(ctx, criteria)
+// GetIdentityVerificationStatus reports whether the user's identity verification is still current
+func (s *UserServiceImpl) GetIdentityVerificationStatus(ctx context.Context, userID string) (*domain.IdentityVerificationStatus, error) {
+	verification, err := s.kycRepo.GetKYCVerification(ctx, userID, "identity")
 	if err != nil {
-		logger.Error("Failed to search users", zap.Error(err))
+		if err.Error() == "not found" {
+			return &domain.IdentityVerificationStatus{UserID: userID}, nil
+		}
+		s.logger.Error("Failed to get identity verification", zap.Error(err), zap.String("user_id", userID))
 		return nil, &domain.UserError{
 			Code:    domain.USER_026,
 			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
 		}
 	}
 
-	// Remove password hashes from response
-	for _, user := range users {
-		user.PasswordHash = ""
+	status := &domain.IdentityVerificationStatus{
+		UserID:    userID,
+		Status:    verification.Status,
+		Provider:  verification.Provider,
+		ExpiresAt: verification.ExpiresAt,
 	}
 
-	logger.Info("User search completed", zap.Int("result_count", len(users)))
-	return users, nil
+	// The expiry job runs periodically, so a result that lapsed since its last pass is already stale
+	if verification.IsExpired(time.Now()) {
+		status.Status = domain.KYCStatusStale
+	}
+	status.Stale = status.Status == domain.KYCStatusStale
+
+	return status, nil
 }
 
-func (s *UserServiceImpl) ListUsers(ctx context.Context, offset, limit int) ([]*domain.User, error) {
+// Search and listing methods
+
+func (s *UserServiceImpl) ListUsers(ctx context.Context, query *domain.UserListQuery) (*domain.UserPage, error) {
+	query.ApplyDefaults()
+
 	logger := s.logger.With(
 		zap.String("operation", "list_users"),
-		zap.Int("offset", offset),
-		zap.Int("limit", limit),
+		zap.String("sort_by", string(query.SortBy)),
+		zap.String("sort_order", string(query.SortOrder)),
+		zap.Int("limit", query.Limit),
 	)
 
-	users, err := s.userRepo.ListUsers(ctx, offset, limit)
+	if err := s.validateListQuery(ctx, query); err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	fetch := *query
+	fetch.Limit = query.Limit + 1
+
+	users, err := s.userRepo.ListUsers(ctx, &fetch)
 	if err != nil {
 		logger.Error("Failed to list users", zap.Error(err))
 		return nil, &domain.UserError{
@@ -594,13 +671,79 @@ func (s *UserServiceImpl) ListUsers(ctx context.Context, offset, limit int) ([]*
 		}
 	}
 
+	total, err := s.userRepo.CountUsers(ctx, query)
+	if err != nil {
+		logger.Error("Failed to count users", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	page := &domain.UserPage{
+		Users:      users,
+		TotalCount: total,
+		Limit:      query.Limit,
+	}
+	if len(users) > query.Limit {
+		page.Users = users[:query.Limit]
+		page.HasMore = true
+		page.NextCursor = domain.NewUserCursor(page.Users[len(page.Users)-1], query.SortBy).Encode()
+	}
+
 	// Remove password hashes from response
-	for _, user := range users {
+	for _, user := range page.Users {
 		user.PasswordHash = ""
 	}
 
-	logger.Info("User listing completed", zap.Int("result_count", len(users)))
-	return users, nil
+	logger.Info("User listing completed",
+		zap.Int("result_count", len(page.Users)),
+		zap.Int("total_count", total),
+	)
+	return page, nil
+}
+
+// validateListQuery checks filter and sort values and decodes the cursor into query.After
+func (s *UserServiceImpl) validateListQuery(ctx context.Context, query *domain.UserListQuery) error {
+	invalid := func(field string) error {
+		return &domain.UserError{
+			Code:    domain.USER_042,
+			Message: s.localizer.Localize(ctx, domain.USER_042, nil),
+			Field:   field,
+		}
+	}
+
+	if !query.SortBy.IsValid() {
+		return invalid("sort_by")
+	}
+	if query.SortOrder != domain.SortOrderAsc && query.SortOrder != domain.SortOrderDesc {
+		return invalid("sort_order")
+	}
+	if query.KYCStatus != "" && !query.KYCStatus.IsValid() {
+		return invalid("kyc_status")
+	}
+	if query.CreatedFrom != nil && query.CreatedTo != nil && query.CreatedFrom.After(*query.CreatedTo) {
+		return invalid("created_from")
+	}
+	if strings.Contains(query.EmailDomain, "@") {
+		return invalid("email_domain")
+	}
+
+	if query.Cursor == "" {
+		return nil
+	}
+
+	// A cursor is only meaningful for the ordering it was issued under
+	cursor, err := domain.DecodeUserCursor(query.Cursor)
+	if err != nil || cursor.SortBy != query.SortBy {
+		return &domain.UserError{
+			Code:    domain.USER_043,
+			Message: s.localizer.Localize(ctx, domain.USER_043, nil),
+			Field:   "cursor",
+		}
+	}
+	query.After = cursor
+	return nil
 }
 
 // Verification methods (email and phone)
@@ -632,12 +775,16 @@ func (s *UserServiceImpl) SendEmailVerification(ctx context.Context, userID stri
 		}
 	}
 
-	// Generate verification code (in real implementation, store this securely)
-	verificationCode := generateVerificationCode()
+	verificationCode, err := s.issueVerificationCode(ctx, domain.VerificationChannelEmail, userID, domain.NormalizeEmail(user.Email))
+	if err != nil {
+		logger.Warn("Failed to issue email verification code", zap.Error(err))
+		return err
+	}
 
 	// Send verification email
 	if err := s.notificationService.SendEmailVerification(ctx, userID, user.Email, verificationCode); err != nil {
 		logger.Error("Failed to send email verification", zap.Error(err))
+		s.abandonVerificationCode(ctx, logger, domain.VerificationChannelEmail, userID)
 		return &domain.UserError{
 			Code:    domain.USER_029,
 			Message: s.localizer.Localize(ctx, domain.USER_029, nil),
@@ -654,8 +801,10 @@ func (s *UserServiceImpl) VerifyEmail(ctx context.Context, userID, verificationC
 		zap.String("user_id", userID),
 	)
 
-	// In real implementation, validate the verification code against stored value
-	// For now, we'll assume validation passed
+	if err := s.checkVerificationCode(ctx, domain.VerificationChannelEmail, userID, verificationCode); err != nil {
+		logger.Warn("Email verification code rejected", zap.Error(err))
+		return err
+	}
 
 	// Update user's email verification status
 	updates := map[string]interface{}{
@@ -714,12 +863,16 @@ func (s *UserServiceImpl) SendPhoneVerification(ctx context.Context, userID stri
 		}
 	}
 
-	// Generate verification code
-	verificationCode := generateVerificationCode()
+	verificationCode, err := s.issueVerificationCode(ctx, domain.VerificationChannelPhone, userID, domain.NormalizePhone(user.Phone))
+	if err != nil {
+		logger.Warn("Failed to issue phone verification code", zap.Error(err))
+		return err
+	}
 
 	// Send verification SMS
 	if err := s.notificationService.SendPhoneVerification(ctx, userID, user.Phone, verificationCode); err != nil {
 		logger.Error("Failed to send phone verification", zap.Error(err))
+		s.abandonVerificationCode(ctx, logger, domain.VerificationChannelPhone, userID)
 		return &domain.UserError{
 			Code:    domain.USER_029,
 			Message: s.localizer.Localize(ctx, domain.USER_029, nil),
@@ -736,8 +889,10 @@ func (s *UserServiceImpl) VerifyPhone(ctx context.Context, userID, verificationC
 		zap.String("user_id", userID),
 	)
 
-	// In real implementation, validate the verification code against stored value
-	// For now, we'll assume validation passed
+	if err := s.checkVerificationCode(ctx, domain.VerificationChannelPhone, userID, verificationCode); err != nil {
+		logger.Warn("Phone verification code rejected", zap.Error(err))
+		return err
+	}
 
 	// Update user's phone verification status
 	updates := map[string]interface{}{
@@ -831,8 +986,3 @@ func getFileExtension(mimeType string) string {
 		return ""
 	}
 }
-
-func generateVerificationCode() string {
-	// In real implementation, use crypto/rand for secure random generation
-	return fmt.Sprintf("%06d", time.Now().Unix()%1000000)
-}
@@ -0,0 +1,273 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// Duplicate scoring weights; a candidate is flagged once its score reaches duplicateThreshold
+const (
+	duplicateWeightEmail       = 0.6
+	duplicateWeightPhone       = 0.4
+	duplicateWeightName        = 0.2
+	duplicateWeightDateOfBirth = 0.2
+	duplicateWeightSSNLast4    = 0.4
+	duplicateThreshold         = 0.6
+
+	// Names at or above this similarity are treated as the same person (typos, transpositions)
+	nameSimilarityThreshold = 0.85
+)
+
+// Duplicate detection and merge methods for UserServiceImpl
+
+// detectDuplicates scores existing users against the probe and records any likely duplicates for review
+func (s *UserServiceImpl) detectDuplicates(ctx context.Context, probe *domain.DuplicateProbe) ([]*domain.DuplicateMatch, error) {
+	logger := s.logger.With(
+		zap.String("operation", "detect_duplicates"),
+		zap.String("user_id", probe.UserID),
+	)
+
+	candidates, err := s.duplicateRepo.FindCandidates(ctx, probe)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*domain.DuplicateMatch
+	for _, candidate := range candidates {
+		score, reasons := scoreDuplicate(probe, candidate)
+		if score < duplicateThreshold {
+			continue
+		}
+
+		match := &domain.DuplicateMatch{
+			ID:              uuid.New().String(),
+			UserID:          probe.UserID,
+			CandidateUserID: candidate.UserID,
+			Score:           score,
+			Reasons:         reasons,
+			Status:          domain.DuplicateMatchPending,
+			CreatedAt:       time.Now(),
+		}
+		if err := s.duplicateRepo.CreateMatch(ctx, match); err != nil {
+			logger.Warn("Failed to record duplicate match", zap.Error(err), zap.String("candidate_user_id", candidate.UserID))
+			continue
+		}
+		matches = append(matches, match)
+	}
+
+	if len(matches) > 0 {
+		logger.Warn("Potential duplicate user detected", zap.Int("match_count", len(matches)))
+	}
+	return matches, nil
+}
+
+func (s *UserServiceImpl) GetDuplicates(ctx context.Context, userID string) ([]*domain.DuplicateMatch, error) {
+	logger := s.logger.With(
+		zap.String("operation", "get_duplicates"),
+		zap.String("user_id", userID),
+	)
+
+	matches, err := s.duplicateRepo.ListMatches(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to list duplicate matches", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	return matches, nil
+}
+
+// MergeUsers folds a duplicate user into the survivor. Each step is idempotent, so a merge that
+// fails partway can simply be retried.
+func (s *UserServiceImpl) MergeUsers(ctx context.Context, request *domain.MergeUsersRequest) (*domain.UserMerge, error) {
+	logger := s.logger.With(
+		zap.String("operation", "merge_users"),
+		zap.String("survivor_id", request.SurvivorID),
+		zap.String("duplicate_id", request.DuplicateID),
+		zap.String("performed_by", request.PerformedBy),
+	)
+
+	if request.SurvivorID == request.DuplicateID {
+		return nil, &domain.UserError{
+			Code:    domain.USER_045,
+			Message: s.localizer.Localize(ctx, domain.USER_045, nil),
+			Field:   "duplicate_id",
+		}
+	}
+
+	for _, ref := range []struct{ field, userID string }{
+		{"survivor_id", request.SurvivorID},
+		{"duplicate_id", request.DuplicateID},
+	} {
+		field := ref.field
+		if _, err := s.userRepo.GetUserByID(ctx, ref.userID); err != nil {
+			if err.Error() == "not found" {
+				return nil, &domain.UserError{
+					Code:    domain.USER_030,
+					Message: s.localizer.Localize(ctx, domain.USER_030, nil),
+					Field:   field,
+				}
+			}
+			logger.Error("Failed to get user", zap.Error(err), zap.String("field", field))
+			return nil, &domain.UserError{
+				Code:    domain.USER_026,
+				Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+			}
+		}
+	}
+
+	// Loan applications live in the loan service, so they move first over its internal API
+	loansMoved, err := s.loanAccounts.ReassignApplications(ctx, request.DuplicateID, request.SurvivorID)
+	if err != nil {
+		logger.Error("Failed to reassign loan applications", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_034,
+			Message: s.localizer.Localize(ctx, domain.USER_034, nil),
+		}
+	}
+
+	if err := s.authAccounts.DeactivateAccount(ctx, request.DuplicateID); err != nil {
+		logger.Error("Failed to deactivate duplicate auth account", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_034,
+			Message: s.localizer.Localize(ctx, domain.USER_034, nil),
+		}
+	}
+
+	merge := &domain.UserMerge{
+		ID:                    uuid.New().String(),
+		SurvivorID:            request.SurvivorID,
+		MergedID:              request.DuplicateID,
+		PerformedBy:           request.PerformedBy,
+		Reason:                request.Reason,
+		LoanApplicationsMoved: loansMoved,
+		CreatedAt:             time.Now(),
+	}
+
+	if err := s.duplicateRepo.MergeUsers(ctx, merge); err != nil {
+		logger.Error("Failed to merge users", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	for _, userID := range []string{request.SurvivorID, request.DuplicateID} {
+		if err := s.cacheService.InvalidateUserCache(ctx, userID); err != nil {
+			logger.Warn("Failed to invalidate user cache", zap.Error(err), zap.String("user_id", userID))
+		}
+		s.invalidateCompleteness(ctx, userID)
+
+		if err := s.auditService.LogSecurityEvent(ctx, userID, "users_merged", map[string]interface{}{
+			"merge_id":                merge.ID,
+			"survivor_id":             merge.SurvivorID,
+			"merged_id":               merge.MergedID,
+			"performed_by":            merge.PerformedBy,
+			"reason":                  merge.Reason,
+			"documents_moved":         merge.DocumentsMoved,
+			"kyc_records_moved":       merge.KYCRecordsMoved,
+			"consents_moved":          merge.ConsentsMoved,
+			"loan_applications_moved": merge.LoanApplicationsMoved,
+		}); err != nil {
+			logger.Warn("Failed to log audit event", zap.Error(err), zap.String("user_id", userID))
+		}
+	}
+
+	logger.Info("Users merged successfully",
+		zap.String("merge_id", merge.ID),
+		zap.Int("documents_moved", merge.DocumentsMoved),
+		zap.Int("kyc_records_moved", merge.KYCRecordsMoved),
+		zap.Int("consents_moved", merge.ConsentsMoved),
+		zap.Int("loan_applications_moved", merge.LoanApplicationsMoved),
+	)
+	return merge, nil
+}
+
+// scoreDuplicate weighs the attributes a candidate shares with the probe
+func scoreDuplicate(probe *domain.DuplicateProbe, candidate *domain.DuplicateCandidate) (float64, domain.MatchReasons) {
+	score := 0.0
+	reasons := domain.MatchReasons{}
+
+	if probe.EmailNormalized != "" && probe.EmailNormalized == candidate.EmailNormalized {
+		score += duplicateWeightEmail
+		reasons = append(reasons, domain.MatchReasonEmail)
+	}
+	if probe.PhoneNormalized != "" && probe.PhoneNormalized == candidate.PhoneNormalized {
+		score += duplicateWeightPhone
+		reasons = append(reasons, domain.MatchReasonPhone)
+	}
+
+	probeName := normalizeName(probe.FirstName + " " + probe.LastName)
+	candidateName := normalizeName(candidate.FirstName + " " + candidate.LastName)
+	if probeName != "" && nameSimilarity(probeName, candidateName) >= nameSimilarityThreshold {
+		score += duplicateWeightName
+		reasons = append(reasons, domain.MatchReasonName)
+	}
+
+	// SSN last four only counts alongside a matching date of birth; on its own it is too common
+	if probe.DateOfBirth != nil && candidate.DateOfBirth != nil &&
+		probe.DateOfBirth.Format("2006-01-02") == candidate.DateOfBirth.Format("2006-01-02") {
+		score += duplicateWeightDateOfBirth
+		reasons = append(reasons, domain.MatchReasonDateOfBirth)
+
+		if probe.SSNLast4 != "" && probe.SSNLast4 == candidate.SSNLast4 {
+			score += duplicateWeightSSNLast4
+			reasons = append(reasons, domain.MatchReasonSSNLast4)
+		}
+	}
+
+	if score > 1 {
+		score = 1
+	}
+	return score, reasons
+}
+
+// normalizeName lowercases a name, strips punctuation and orders the tokens so "Smith John" matches "John Smith"
+func normalizeName(name string) string {
+	tokens := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z')
+	})
+	sort.Strings(tokens)
+	return strings.Join(tokens, " ")
+}
+
+// nameSimilarity returns 1 minus the normalized Levenshtein distance between two names
+func nameSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return 1 - float64(prev[len(rb)])/float64(max(len(ra), len(rb)))
+}
@@ -0,0 +1,169 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// KeyRotationJob re-encrypts token vault entries and document keys that were written under an
+// older key version, so retired master keys can eventually be disabled in the KMS.
+type KeyRotationJob struct {
+	vaultRepo         domain.TokenVaultRepository
+	documentRepo      domain.DocumentRepository
+	encryptionService domain.EncryptionService
+	batchSize         int
+	interval          time.Duration
+	logger            *zap.Logger
+}
+
+// KeyRotationResult summarizes a single re-encryption pass
+type KeyRotationResult struct {
+	TokensScanned    int `json:"tokens_scanned"`
+	TokensRotated    int `json:"tokens_rotated"`
+	DocumentsScanned int `json:"documents_scanned"`
+	DocumentsRotated int `json:"documents_rotated"`
+	Failures         int `json:"failures"`
+}
+
+func NewKeyRotationJob(
+	vaultRepo domain.TokenVaultRepository,
+	documentRepo domain.DocumentRepository,
+	encryptionService domain.EncryptionService,
+	batchSize int,
+	interval time.Duration,
+	logger *zap.Logger,
+) *KeyRotationJob {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &KeyRotationJob{
+		vaultRepo:         vaultRepo,
+		documentRepo:      documentRepo,
+		encryptionService: encryptionService,
+		batchSize:         batchSize,
+		interval:          interval,
+		logger:            logger,
+	}
+}
+
+// Start runs re-encryption passes on the configured interval until the context is cancelled
+func (j *KeyRotationJob) Start(ctx context.Context) {
+	if j.interval <= 0 {
+		j.logger.Info("Key rotation job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Key rotation job stopped")
+			return
+		case <-ticker.C:
+			if _, err := j.RunOnce(ctx); err != nil {
+				j.logger.Error("Key rotation pass failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RunOnce performs a single pass over vault tokens and documents
+func (j *KeyRotationJob) RunOnce(ctx context.Context) (*KeyRotationResult, error) {
+	logger := j.logger.With(zap.String("operation", "key_rotation"))
+	result := &KeyRotationResult{}
+
+	if err := j.rotateTokens(ctx, logger, result); err != nil {
+		return result, err
+	}
+
+	if err := j.rotateDocuments(ctx, logger, result); err != nil {
+		return result, err
+	}
+
+	logger.Info("Key rotation pass completed",
+		zap.Int("tokens_scanned", result.TokensScanned),
+		zap.Int("tokens_rotated", result.TokensRotated),
+		zap.Int("documents_scanned", result.DocumentsScanned),
+		zap.Int("documents_rotated", result.DocumentsRotated),
+		zap.Int("failures", result.Failures),
+	)
+
+	return result, nil
+}
+
+func (j *KeyRotationJob) rotateTokens(ctx context.Context, logger *zap.Logger, result *KeyRotationResult) error {
+	for offset := 0; ; offset += j.batchSize {
+		tokens, err := j.vaultRepo.ListTokens(ctx, offset, j.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, token := range tokens {
+			result.TokensScanned++
+			if !j.encryptionService.NeedsReEncryption(token.ValueEncrypted) {
+				continue
+			}
+
+			rotated, err := j.encryptionService.ReEncryptField(token.ValueEncrypted)
+			if err != nil {
+				result.Failures++
+				logger.Warn("Failed to re-encrypt token value", zap.String("token_id", token.ID), zap.Error(err))
+				continue
+			}
+
+			if err := j.vaultRepo.UpdateTokenValue(ctx, token.Token, rotated); err != nil {
+				result.Failures++
+				logger.Warn("Failed to store re-encrypted token value", zap.String("token_id", token.ID), zap.Error(err))
+				continue
+			}
+			result.TokensRotated++
+		}
+
+		if len(tokens) < j.batchSize || ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+func (j *KeyRotationJob) rotateDocuments(ctx context.Context, logger *zap.Logger, result *KeyRotationResult) error {
+	for offset := 0; ; offset += j.batchSize {
+		documents, err := j.documentRepo.ListDocuments(ctx, offset, j.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, document := range documents {
+			result.DocumentsScanned++
+			if !j.encryptionService.NeedsReEncryption(document.EncryptionKey) {
+				continue
+			}
+
+			// Only the wrapped file key is rotated; the file content keeps its data key
+			rotated, err := j.encryptionService.ReEncryptField(document.EncryptionKey)
+			if err != nil {
+				result.Failures++
+				logger.Warn("Failed to re-encrypt document key", zap.String("document_id", document.ID), zap.Error(err))
+				continue
+			}
+
+			if err := j.documentRepo.UpdateDocument(ctx, document.ID, map[string]interface{}{
+				"encryption_key": rotated,
+			}); err != nil {
+				result.Failures++
+				logger.Warn("Failed to store re-encrypted document key", zap.String("document_id", document.ID), zap.Error(err))
+				continue
+			}
+			result.DocumentsRotated++
+		}
+
+		if len(documents) < j.batchSize || ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
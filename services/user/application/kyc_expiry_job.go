@@ -0,0 +1,148 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// KYCExpiryJob moves verified KYC results past their expiry date to the stale status and prompts
+// the user to verify again. Loan applications are refused while identity verification is stale.
+type KYCExpiryJob struct {
+	kycRepo             domain.KYCRepository
+	cacheService        domain.CacheService
+	notificationService domain.NotificationService
+	auditService        domain.AuditService
+	batchSize           int
+	interval            time.Duration
+	logger              *zap.Logger
+}
+
+// KYCExpiryResult summarizes a single expiry pass
+type KYCExpiryResult struct {
+	Expired  int `json:"expired"`
+	Prompted int `json:"prompted"`
+	Failures int `json:"failures"`
+}
+
+func NewKYCExpiryJob(
+	kycRepo domain.KYCRepository,
+	cacheService domain.CacheService,
+	notificationService domain.NotificationService,
+	auditService domain.AuditService,
+	batchSize int,
+	interval time.Duration,
+	logger *zap.Logger,
+) *KYCExpiryJob {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &KYCExpiryJob{
+		kycRepo:             kycRepo,
+		cacheService:        cacheService,
+		notificationService: notificationService,
+		auditService:        auditService,
+		batchSize:           batchSize,
+		interval:            interval,
+		logger:              logger,
+	}
+}
+
+// Start runs expiry passes on the configured interval until the context is cancelled
+func (j *KYCExpiryJob) Start(ctx context.Context) {
+	if j.interval <= 0 {
+		j.logger.Info("KYC expiry job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("KYC expiry job stopped")
+			return
+		case <-ticker.C:
+			if _, err := j.RunOnce(ctx); err != nil {
+				j.logger.Error("KYC expiry pass failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RunOnce marks every verification that has expired as of now stale
+func (j *KYCExpiryJob) RunOnce(ctx context.Context) (*KYCExpiryResult, error) {
+	logger := j.logger.With(zap.String("operation", "kyc_expiry"))
+	result := &KYCExpiryResult{}
+	asOf := time.Now()
+
+	// Expired rows drop out of the scan once updated; the cursor skips the ones that failed
+	afterID := ""
+	for {
+		verifications, err := j.kycRepo.ListExpiredVerifications(ctx, asOf, afterID, j.batchSize)
+		if err != nil {
+			return result, err
+		}
+
+		for _, verification := range verifications {
+			afterID = verification.ID
+			j.expire(ctx, logger, verification, result)
+		}
+
+		if len(verifications) < j.batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	logger.Info("KYC expiry pass completed",
+		zap.Int("expired", result.Expired),
+		zap.Int("prompted", result.Prompted),
+		zap.Int("failures", result.Failures),
+	)
+
+	return result, ctx.Err()
+}
+
+func (j *KYCExpiryJob) expire(ctx context.Context, logger *zap.Logger, verification *domain.KYCVerification, result *KYCExpiryResult) {
+	logger = logger.With(
+		zap.String("verification_id", verification.ID),
+		zap.String("user_id", verification.UserID),
+		zap.String("verification_type", verification.VerificationType),
+	)
+
+	if err := j.kycRepo.UpdateKYCVerification(ctx, verification.ID, map[string]interface{}{
+		"status":     domain.KYCStatusStale,
+		"updated_at": time.Now(),
+	}); err != nil {
+		result.Failures++
+		logger.Warn("Failed to mark KYC verification stale", zap.Error(err))
+		return
+	}
+	result.Expired++
+
+	if err := j.cacheService.InvalidateKYCStatus(ctx, verification.UserID); err != nil {
+		logger.Warn("Failed to invalidate KYC status cache", zap.Error(err))
+	}
+
+	if err := j.auditService.LogKYCStatusChanged(ctx, verification.UserID, verification.VerificationType,
+		domain.KYCStatusVerified, domain.KYCStatusStale); err != nil {
+		logger.Warn("Failed to log KYC status change audit event", zap.Error(err))
+	}
+
+	if err := j.notificationService.SendPushNotification(ctx, verification.UserID,
+		"Identity verification expired",
+		"Your identity verification has expired. Please verify again before applying for a new loan.",
+		map[string]interface{}{
+			"action":            "kyc_reinitiate",
+			"verification_type": verification.VerificationType,
+		},
+	); err != nil {
+		logger.Warn("Failed to send KYC re-verification prompt", zap.Error(err))
+		return
+	}
+	result.Prompted++
+}
@@ -0,0 +1,213 @@
+package application
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/i18n"
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+var nonDigitPattern = regexp.MustCompile(`[^\d]`)
+
+// TokenizationServiceImpl swaps SSNs and tax IDs for opaque tokens backed by an encrypted vault.
+// Values are looked up by keyed hash so the same identifier always maps to the same token.
+type TokenizationServiceImpl struct {
+	vaultRepo         domain.TokenVaultRepository
+	encryptionService domain.EncryptionService
+	validationService domain.ValidationService
+	auditService      domain.AuditService
+	hashKey           []byte
+	logger            *zap.Logger
+	localizer         *i18n.Localizer
+}
+
+func NewTokenizationService(
+	vaultRepo domain.TokenVaultRepository,
+	encryptionService domain.EncryptionService,
+	validationService domain.ValidationService,
+	auditService domain.AuditService,
+	hashKey string,
+	logger *zap.Logger,
+	localizer *i18n.Localizer,
+) domain.TokenizationService {
+	return &TokenizationServiceImpl{
+		vaultRepo:         vaultRepo,
+		encryptionService: encryptionService,
+		validationService: validationService,
+		auditService:      auditService,
+		hashKey:           []byte(hashKey),
+		logger:            logger,
+		localizer:         localizer,
+	}
+}
+
+func (s *TokenizationServiceImpl) Tokenize(ctx context.Context, tokenType domain.TokenType, value string) (*domain.PIIToken, error) {
+	logger := s.logger.With(
+		zap.String("operation", "tokenize"),
+		zap.String("token_type", string(tokenType)),
+	)
+
+	if !tokenType.IsValid() {
+		return nil, &domain.UserError{
+			Code:    domain.USER_039,
+			Message: s.localizer.Localize(ctx, domain.USER_039, nil),
+			Field:   "token_type",
+		}
+	}
+
+	normalized := nonDigitPattern.ReplaceAllString(value, "")
+	if tokenType == domain.TokenTypeSSN {
+		if err := s.validationService.ValidateSSN(normalized); err != nil {
+			return nil, &domain.UserError{
+				Code:    domain.USER_003,
+				Message: s.localizer.Localize(ctx, domain.USER_003, nil),
+				Field:   "value",
+			}
+		}
+	} else if !validTokenValueLength(tokenType, normalized) {
+		return nil, &domain.UserError{
+			Code:    domain.USER_005,
+			Message: s.localizer.Localize(ctx, domain.USER_005, nil),
+			Field:   "value",
+		}
+	}
+
+	valueHash := s.hashValue(tokenType, normalized)
+
+	existing, err := s.vaultRepo.GetTokenByValueHash(ctx, tokenType, valueHash)
+	if err != nil {
+		logger.Error("Failed to look up token", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	encrypted, err := s.encryptionService.EncryptField(normalized)
+	if err != nil {
+		logger.Error("Failed to encrypt value", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_028,
+			Message: s.localizer.Localize(ctx, domain.USER_028, nil),
+		}
+	}
+
+	tokenValue, err := generateToken(tokenType)
+	if err != nil {
+		logger.Error("Failed to generate token", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_028,
+			Message: s.localizer.Localize(ctx, domain.USER_028, nil),
+		}
+	}
+
+	now := time.Now()
+	token := &domain.PIIToken{
+		ID:             uuid.New().String(),
+		Token:          tokenValue,
+		TokenType:      tokenType,
+		ValueHash:      valueHash,
+		ValueEncrypted: encrypted,
+		Last4:          normalized[len(normalized)-4:],
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := s.vaultRepo.CreateToken(ctx, token); err != nil {
+		// A concurrent request tokenized the same value first; both callers get its token
+		if errors.Is(err, domain.ErrTokenExists) {
+			existing, lookupErr := s.vaultRepo.GetTokenByValueHash(ctx, tokenType, valueHash)
+			if lookupErr == nil && existing != nil {
+				return existing, nil
+			}
+			if lookupErr != nil {
+				err = lookupErr
+			}
+		}
+		logger.Error("Failed to store token", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	logger.Info("Token issued", zap.String("token_id", token.ID))
+	return token, nil
+}
+
+func (s *TokenizationServiceImpl) Detokenize(ctx context.Context, token string) (string, error) {
+	logger := s.logger.With(zap.String("operation", "detokenize"))
+
+	piiToken, err := s.vaultRepo.GetToken(ctx, token)
+	if err != nil {
+		if err.Error() == "not found" {
+			return "", &domain.UserError{
+				Code:    domain.USER_040,
+				Message: s.localizer.Localize(ctx, domain.USER_040, nil),
+				Field:   "token",
+			}
+		}
+		logger.Error("Failed to get token", zap.Error(err))
+		return "", &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	value, err := s.encryptionService.DecryptField(piiToken.ValueEncrypted)
+	if err != nil {
+		logger.Error("Failed to decrypt token value", zap.Error(err), zap.String("token_id", piiToken.ID))
+		return "", &domain.UserError{
+			Code:    domain.USER_028,
+			Message: s.localizer.Localize(ctx, domain.USER_028, nil),
+		}
+	}
+
+	if err := s.auditService.LogSecurityEvent(ctx, "", "pii_detokenized", map[string]interface{}{
+		"token_id":   piiToken.ID,
+		"token_type": piiToken.TokenType,
+	}); err != nil {
+		logger.Warn("Failed to log audit event", zap.Error(err))
+	}
+
+	return value, nil
+}
+
+// hashValue derives the vault lookup key; keyed so the hash cannot be brute-forced from the 9-digit space
+func (s *TokenizationServiceImpl) hashValue(tokenType domain.TokenType, value string) string {
+	mac := hmac.New(sha256.New, s.hashKey)
+	mac.Write([]byte(string(tokenType) + ":" + value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validTokenValueLength checks the digit count of a normalized non-SSN value: US bank account
+// numbers run from 4 to 17 digits, while routing numbers and tax IDs have 9
+func validTokenValueLength(tokenType domain.TokenType, normalized string) bool {
+	if tokenType == domain.TokenTypeBankAccountNumber {
+		return len(normalized) >= 4 && len(normalized) <= 17
+	}
+	return len(normalized) == 9
+}
+
+// generateToken creates a random token that carries no information about the value
+func generateToken(tokenType domain.TokenType) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tok_%s_%s", tokenType, hex.EncodeToString(buf)), nil
+}
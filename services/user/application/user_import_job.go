@@ -0,0 +1,473 @@
+package application
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/i18n"
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+const (
+	// importProgressInterval is how many rows are processed between progress saves
+	importProgressInterval = 100
+	// importMaxRowErrors caps the stored error report; FailedRows still counts every failure
+	importMaxRowErrors = 1000
+	// importMaxLineBytes bounds a single NDJSON line
+	importMaxLineBytes = 1 << 20
+	// importStaleAfter is how long a processing import may go without progress before it is reclaimed.
+	// Upserts are keyed on external_id, so reprocessing a file from the start is safe.
+	importStaleAfter = 10 * time.Minute
+	// importInviteTTL is how long the set-password link sent to an imported user stays usable
+	importInviteTTL = 7 * 24 * time.Hour
+)
+
+// importRequiredColumns must be present in every import; the remaining columns are optional
+var importRequiredColumns = []string{"external_id", "email", "first_name", "last_name"}
+
+// UserImportJob processes bulk user imports staged in document storage. Rows are streamed from the
+// file one at a time, validated, and upserted keyed on external_id; invalid rows are reported per row
+// without failing the import. Each imported user gets a login identity in auth and an invite to set a password.
+type UserImportJob struct {
+	importRepo          domain.UserImportRepository
+	userRepo            domain.UserRepository
+	storageService      domain.DocumentStorageService
+	validationService   domain.ValidationService
+	authAccounts        domain.AuthAccountService
+	notificationService domain.NotificationService
+	auditService        domain.AuditService
+	interval            time.Duration
+	logger              *zap.Logger
+	localizer           *i18n.Localizer
+}
+
+// UserImportJobResult summarizes a single pass over pending imports
+type UserImportJobResult struct {
+	Imports int `json:"imports"`
+	Rows    int `json:"rows"`
+	Failed  int `json:"failed"`
+}
+
+func NewUserImportJob(
+	importRepo domain.UserImportRepository,
+	userRepo domain.UserRepository,
+	storageService domain.DocumentStorageService,
+	validationService domain.ValidationService,
+	authAccounts domain.AuthAccountService,
+	notificationService domain.NotificationService,
+	auditService domain.AuditService,
+	interval time.Duration,
+	logger *zap.Logger,
+	localizer *i18n.Localizer,
+) *UserImportJob {
+	return &UserImportJob{
+		importRepo:          importRepo,
+		userRepo:            userRepo,
+		storageService:      storageService,
+		validationService:   validationService,
+		authAccounts:        authAccounts,
+		notificationService: notificationService,
+		auditService:        auditService,
+		interval:            interval,
+		logger:              logger,
+		localizer:           localizer,
+	}
+}
+
+// Start processes pending imports on the configured interval until the context is cancelled
+func (j *UserImportJob) Start(ctx context.Context) {
+	if j.interval <= 0 {
+		j.logger.Info("User import job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("User import job stopped")
+			return
+		case <-ticker.C:
+			if _, err := j.RunOnce(ctx); err != nil {
+				j.logger.Error("User import pass failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RunOnce claims and processes imports until none are waiting
+func (j *UserImportJob) RunOnce(ctx context.Context) (*UserImportJobResult, error) {
+	result := &UserImportJobResult{}
+
+	for ctx.Err() == nil {
+		userImport, err := j.importRepo.ClaimImport(ctx, importStaleAfter)
+		if err != nil {
+			return result, err
+		}
+		if userImport == nil {
+			break
+		}
+
+		j.process(ctx, userImport)
+		result.Imports++
+		result.Rows += userImport.TotalRows
+		result.Failed += userImport.FailedRows
+	}
+
+	return result, ctx.Err()
+}
+
+// process runs one import to completion, recording a fatal error on the import rather than returning it
+func (j *UserImportJob) process(ctx context.Context, userImport *domain.UserImport) {
+	logger := j.logger.With(
+		zap.String("operation", "process_user_import"),
+		zap.String("import_id", userImport.ID),
+		zap.String("format", string(userImport.Format)),
+	)
+	logger.Info("Processing user import")
+
+	// A reclaimed import starts over, so counters from the interrupted attempt are discarded
+	userImport.TotalRows = 0
+	userImport.CreatedRows = 0
+	userImport.UpdatedRows = 0
+	userImport.FailedRows = 0
+	userImport.Errors = domain.ImportRowErrors{}
+	userImport.ErrorMessage = ""
+
+	if err := j.importRows(ctx, userImport, logger); err != nil {
+		if ctx.Err() != nil {
+			// Shutting down; the import is reclaimed and restarted once stale
+			logger.Info("User import interrupted", zap.Int("rows_processed", userImport.TotalRows))
+			return
+		}
+		logger.Error("User import failed", zap.Error(err))
+		userImport.Status = domain.ImportStatusFailed
+		userImport.ErrorMessage = err.Error()
+	} else {
+		userImport.Status = domain.ImportStatusCompleted
+	}
+
+	now := time.Now()
+	userImport.CompletedAt = &now
+	userImport.UpdatedAt = now
+	if err := j.importRepo.UpdateImport(ctx, userImport); err != nil {
+		// The import stays in processing and is reclaimed once stale
+		logger.Error("Failed to save user import result", zap.Error(err))
+		return
+	}
+
+	// The staged file holds borrower PII and is only kept while the import can still be retried
+	if err := j.storageService.DeleteFile(ctx, userImport.StorageKey); err != nil {
+		logger.Warn("Failed to delete staged import file", zap.String("storage_key", userImport.StorageKey), zap.Error(err))
+	}
+
+	if err := j.auditService.LogSecurityEvent(ctx, "", "users_imported", map[string]interface{}{
+		"actor_id":     userImport.SubmittedBy,
+		"import_id":    userImport.ID,
+		"status":       userImport.Status,
+		"total_rows":   userImport.TotalRows,
+		"created_rows": userImport.CreatedRows,
+		"updated_rows": userImport.UpdatedRows,
+		"failed_rows":  userImport.FailedRows,
+	}); err != nil {
+		logger.Warn("Failed to log audit event", zap.Error(err))
+	}
+
+	logger.Info("User import finished",
+		zap.String("status", string(userImport.Status)),
+		zap.Int("total_rows", userImport.TotalRows),
+		zap.Int("created_rows", userImport.CreatedRows),
+		zap.Int("updated_rows", userImport.UpdatedRows),
+		zap.Int("failed_rows", userImport.FailedRows),
+	)
+}
+
+// importRows streams the staged file and applies each row; it only returns errors that stop the whole import
+func (j *UserImportJob) importRows(ctx context.Context, userImport *domain.UserImport, logger *zap.Logger) error {
+	content, err := j.storageService.DownloadFile(ctx, userImport.StorageKey)
+	if err != nil {
+		return fmt.Errorf("failed to read import file: %w", err)
+	}
+	defer content.Close()
+
+	apply := func(rowNum int, row *domain.UserImportRow, rowErr *domain.ImportRowError) error {
+		var outcome domain.ImportOutcome
+		if rowErr == nil {
+			outcome, rowErr = j.applyRow(ctx, row)
+		}
+
+		userImport.TotalRows++
+		switch {
+		case rowErr != nil:
+			rowErr.Row = rowNum
+			userImport.FailedRows++
+			if len(userImport.Errors) < importMaxRowErrors {
+				userImport.Errors = append(userImport.Errors, *rowErr)
+			}
+		case outcome == domain.ImportOutcomeCreated:
+			userImport.CreatedRows++
+		default:
+			userImport.UpdatedRows++
+		}
+
+		if userImport.TotalRows%importProgressInterval == 0 {
+			userImport.UpdatedAt = time.Now()
+			if err := j.importRepo.UpdateImport(ctx, userImport); err != nil {
+				logger.Warn("Failed to save user import progress", zap.Error(err))
+			}
+		}
+		return ctx.Err()
+	}
+
+	switch userImport.Format {
+	case domain.ImportFormatCSV:
+		return j.readCSV(content, apply)
+	case domain.ImportFormatNDJSON:
+		return j.readNDJSON(content, apply)
+	default:
+		return fmt.Errorf("unsupported import format %q", userImport.Format)
+	}
+}
+
+type importRowFunc func(rowNum int, row *domain.UserImportRow, rowErr *domain.ImportRowError) error
+
+// readCSV maps columns by header name, so column order and extra columns don't matter
+func (j *UserImportJob) readCSV(content io.Reader, apply importRowFunc) error {
+	reader := csv.NewReader(content)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range importRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			return fmt.Errorf("CSV header is missing required column %q", name)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	for rowNum := 1; ; rowNum++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return fmt.Errorf("failed to read CSV: %w", err)
+			}
+			if err := apply(rowNum, nil, j.rowError(domain.USER_042, "", "")); err != nil {
+				return err
+			}
+			continue
+		}
+
+		row := &domain.UserImportRow{
+			ExternalID:  field(record, "external_id"),
+			Email:       field(record, "email"),
+			Phone:       field(record, "phone"),
+			FirstName:   field(record, "first_name"),
+			LastName:    field(record, "last_name"),
+			DateOfBirth: field(record, "date_of_birth"),
+		}
+		if err := apply(rowNum, row, nil); err != nil {
+			return err
+		}
+	}
+}
+
+// readNDJSON reads one JSON object per line; blank lines are skipped and not counted
+func (j *UserImportJob) readNDJSON(content io.Reader, apply importRowFunc) error {
+	scanner := bufio.NewScanner(content)
+	scanner.Buffer(make([]byte, 0, 64*1024), importMaxLineBytes)
+
+	rowNum := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		rowNum++
+
+		var row domain.UserImportRow
+		if err := json.Unmarshal([]byte(line), &row); err != nil {
+			if err := apply(rowNum, nil, j.rowError(domain.USER_042, "", "")); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := apply(rowNum, &row, nil); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read NDJSON: %w", err)
+	}
+
+	return nil
+}
+
+// applyRow validates and upserts a single borrower, returning the reason it was rejected if any
+func (j *UserImportJob) applyRow(ctx context.Context, row *domain.UserImportRow) (domain.ImportOutcome, *domain.ImportRowError) {
+	row.ExternalID = strings.TrimSpace(row.ExternalID)
+	row.Email = strings.TrimSpace(row.Email)
+	row.Phone = strings.TrimSpace(row.Phone)
+	row.FirstName = strings.TrimSpace(row.FirstName)
+	row.LastName = strings.TrimSpace(row.LastName)
+	row.DateOfBirth = strings.TrimSpace(row.DateOfBirth)
+
+	required := map[string]string{
+		"external_id": row.ExternalID,
+		"email":       row.Email,
+		"first_name":  row.FirstName,
+		"last_name":   row.LastName,
+	}
+	for _, name := range importRequiredColumns {
+		if required[name] == "" {
+			return "", j.rowError(domain.USER_005, row.ExternalID, name)
+		}
+	}
+	if len(row.ExternalID) > 100 {
+		return "", j.rowError(domain.USER_042, row.ExternalID, "external_id")
+	}
+
+	if err := j.validationService.ValidateEmail(row.Email); err != nil {
+		return "", j.rowError(domain.USER_001, row.ExternalID, "email")
+	}
+	if row.Phone != "" {
+		if err := j.validationService.ValidatePhone(row.Phone); err != nil {
+			return "", j.rowError(domain.USER_002, row.ExternalID, "phone")
+		}
+	}
+
+	var dateOfBirth time.Time
+	if row.DateOfBirth != "" {
+		parsed, err := time.Parse("2006-01-02", row.DateOfBirth)
+		if err != nil {
+			return "", j.rowError(domain.USER_004, row.ExternalID, "date_of_birth")
+		}
+		if err := j.validationService.ValidateDateOfBirth(parsed); err != nil {
+			return "", j.rowError(domain.USER_004, row.ExternalID, "date_of_birth")
+		}
+		dateOfBirth = parsed
+	}
+
+	now := time.Now()
+	user := &domain.User{
+		ID:              uuid.New().String(),
+		ExternalID:      row.ExternalID,
+		Email:           row.Email,
+		EmailNormalized: domain.NormalizeEmail(row.Email),
+		PasswordHash:    domain.ImportedPasswordHash,
+		Phone:           row.Phone,
+		PhoneNormalized: domain.NormalizePhone(row.Phone),
+		Status:          domain.UserStatusActive,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	profile := &domain.UserProfile{
+		ID:          uuid.New().String(),
+		FirstName:   row.FirstName,
+		LastName:    row.LastName,
+		DateOfBirth: dateOfBirth,
+		Phone:       row.Phone,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	outcome, err := j.importRepo.UpsertImportedUser(ctx, user, profile)
+	if err != nil {
+		j.logger.Warn("Failed to upsert imported user", zap.String("external_id", row.ExternalID), zap.Error(err))
+		return "", j.rowError(domain.USER_026, row.ExternalID, "")
+	}
+	if outcome == domain.ImportOutcomeEmailConflict {
+		return "", j.rowError(domain.USER_006, row.ExternalID, "email")
+	}
+
+	// Retried on every run until the invite has gone out
+	if user.InviteSentAt == nil {
+		if rowErr := j.inviteUser(ctx, user, row); rowErr != nil {
+			return "", rowErr
+		}
+	}
+
+	return outcome, nil
+}
+
+// inviteUser gives an imported user a login identity without a password and emails them a link to
+// set one, redeemed like an admin-forced password reset
+func (j *UserImportJob) inviteUser(ctx context.Context, user *domain.User, row *domain.UserImportRow) *domain.ImportRowError {
+	logger := j.logger.With(zap.String("external_id", row.ExternalID), zap.String("user_id", user.ID))
+
+	if err := j.authAccounts.ProvisionAccount(ctx, user.ID, user.Email, row.FirstName, row.LastName); err != nil {
+		if errors.Is(err, domain.ErrAccountEmailTaken) {
+			return j.rowError(domain.USER_006, row.ExternalID, "email")
+		}
+		logger.Warn("Failed to provision auth account", zap.Error(err))
+		return j.rowError(domain.USER_034, row.ExternalID, "")
+	}
+
+	token, err := generateResetToken()
+	if err != nil {
+		logger.Warn("Failed to generate invite token", zap.Error(err))
+		return j.rowError(domain.USER_028, row.ExternalID, "")
+	}
+	tokenHash := sha256.Sum256([]byte(token))
+	if err := j.userRepo.UpdateUser(ctx, user.ID, map[string]interface{}{
+		"password_reset_token":   hex.EncodeToString(tokenHash[:]),
+		"password_reset_expires": time.Now().Add(importInviteTTL),
+		"updated_at":             time.Now(),
+	}); err != nil {
+		logger.Warn("Failed to store invite token", zap.Error(err))
+		return j.rowError(domain.USER_026, row.ExternalID, "")
+	}
+
+	if err := j.notificationService.SendAccountInvite(ctx, user.ID, user.Email, token); err != nil {
+		logger.Warn("Failed to send account invite", zap.Error(err))
+		return j.rowError(domain.USER_029, row.ExternalID, "")
+	}
+
+	if err := j.userRepo.UpdateUser(ctx, user.ID, map[string]interface{}{
+		"invite_sent_at": time.Now(),
+	}); err != nil {
+		logger.Warn("Failed to record invite", zap.Error(err))
+		return j.rowError(domain.USER_026, row.ExternalID, "")
+	}
+
+	return nil
+}
+
+func (j *UserImportJob) rowError(code, externalID, field string) *domain.ImportRowError {
+	return &domain.ImportRowError{
+		ExternalID: externalID,
+		Field:      field,
+		Code:       code,
+		Message:    j.localizer.Localize(context.Background(), code, nil),
+	}
+}
@@ -0,0 +1,109 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// Bulk user import for UserServiceImpl; rows are processed asynchronously by UserImportJob
+
+// importContentTypes is the content type the staged file is stored with
+var importContentTypes = map[domain.ImportFormat]string{
+	domain.ImportFormatCSV:    "text/csv",
+	domain.ImportFormatNDJSON: "application/x-ndjson",
+}
+
+// SubmitUserImport stages the uploaded file and queues it for processing
+func (s *UserServiceImpl) SubmitUserImport(ctx context.Context, actorID string, format domain.ImportFormat, content io.Reader) (*domain.UserImport, error) {
+	logger := s.logger.With(
+		zap.String("operation", "submit_user_import"),
+		zap.String("actor_id", actorID),
+		zap.String("format", string(format)),
+	)
+
+	if !format.IsValid() {
+		return nil, &domain.UserError{
+			Code:    domain.USER_055,
+			Message: s.localizer.Localize(ctx, domain.USER_055, nil),
+			Field:   "format",
+		}
+	}
+
+	now := time.Now()
+	userImport := &domain.UserImport{
+		ID:          uuid.New().String(),
+		Status:      domain.ImportStatusPending,
+		Format:      format,
+		SubmittedBy: actorID,
+		Errors:      domain.ImportRowErrors{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	userImport.StorageKey = fmt.Sprintf("imports/%s.%s", userImport.ID, format)
+
+	if err := s.storageService.UploadFile(ctx, userImport.StorageKey, content, importContentTypes[format], map[string]string{
+		"import_id":    userImport.ID,
+		"submitted_by": actorID,
+	}); err != nil {
+		logger.Error("Failed to stage import file", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_016,
+			Message: s.localizer.Localize(ctx, domain.USER_016, nil),
+		}
+	}
+
+	if err := s.importRepo.CreateImport(ctx, userImport); err != nil {
+		logger.Error("Failed to create user import", zap.Error(err))
+		if err := s.storageService.DeleteFile(ctx, userImport.StorageKey); err != nil {
+			logger.Warn("Failed to delete staged import file", zap.Error(err))
+		}
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	if err := s.auditService.LogSecurityEvent(ctx, "", "user_import_submitted", map[string]interface{}{
+		"actor_id":  actorID,
+		"import_id": userImport.ID,
+		"format":    format,
+	}); err != nil {
+		logger.Warn("Failed to log audit event", zap.Error(err))
+	}
+
+	logger.Info("User import queued", zap.String("import_id", userImport.ID))
+	return userImport, nil
+}
+
+func (s *UserServiceImpl) GetUserImport(ctx context.Context, importID string) (*domain.UserImport, error) {
+	if _, err := uuid.Parse(importID); err != nil {
+		return nil, &domain.UserError{
+			Code:    domain.USER_056,
+			Message: s.localizer.Localize(ctx, domain.USER_056, nil),
+		}
+	}
+
+	userImport, err := s.importRepo.GetImport(ctx, importID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, &domain.UserError{
+				Code:    domain.USER_056,
+				Message: s.localizer.Localize(ctx, domain.USER_056, nil),
+			}
+		}
+		s.logger.Error("Failed to get user import", zap.Error(err), zap.String("import_id", importID))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	return userImport, nil
+}
@@ -18,13 +18,27 @@ type UserServiceImpl struct {
 	userRepo            domain.UserRepository
 	kycRepo             domain.KYCRepository
 	documentRepo        domain.DocumentRepository
+	consentRepo         domain.ConsentRepository
 	storageService      domain.DocumentStorageService
 	encryptionService   domain.EncryptionService
+	tokenizationService domain.TokenizationService
 	kycProvider         domain.KYCProviderService
 	notificationService domain.NotificationService
 	validationService   domain.ValidationService
+	addressVerifier     domain.AddressVerificationService
 	auditService        domain.AuditService
 	cacheService        domain.CacheService
+	verificationCodes   domain.VerificationCodeStore
+	duplicateRepo       domain.DuplicateRepository
+	webhookRepo         domain.WebhookRepository
+	noteRepo            domain.UserNoteRepository
+	importRepo          domain.UserImportRepository
+	coApplicantRepo     domain.CoApplicantRepository
+	authAccounts        domain.AuthAccountService
+	loanAccounts        domain.LoanAccountService
+	restoreWindow       time.Duration
+	verificationPolicy  domain.VerificationPolicy
+	kycExpiry           domain.KYCExpiryPolicy
 	logger              *zap.Logger
 	localizer           *i18n.Localizer
 }
@@ -33,13 +47,27 @@ func NewUserService(
 	userRepo domain.UserRepository,
 	kycRepo domain.KYCRepository,
 	documentRepo domain.DocumentRepository,
+	consentRepo domain.ConsentRepository,
 	storageService domain.DocumentStorageService,
 	encryptionService domain.EncryptionService,
+	tokenizationService domain.TokenizationService,
 	kycProvider domain.KYCProviderService,
 	notificationService domain.NotificationService,
 	validationService domain.ValidationService,
+	addressVerifier domain.AddressVerificationService,
 	auditService domain.AuditService,
 	cacheService domain.CacheService,
+	verificationCodes domain.VerificationCodeStore,
+	duplicateRepo domain.DuplicateRepository,
+	webhookRepo domain.WebhookRepository,
+	noteRepo domain.UserNoteRepository,
+	importRepo domain.UserImportRepository,
+	coApplicantRepo domain.CoApplicantRepository,
+	authAccounts domain.AuthAccountService,
+	loanAccounts domain.LoanAccountService,
+	restoreWindow time.Duration,
+	verificationPolicy domain.VerificationPolicy,
+	kycExpiry domain.KYCExpiryPolicy,
 	logger *zap.Logger,
 	localizer *i18n.Localizer,
 ) domain.UserService {
@@ -47,13 +75,27 @@ func NewUserService(
 		userRepo:            userRepo,
 		kycRepo:             kycRepo,
 		documentRepo:        documentRepo,
+		consentRepo:         consentRepo,
 		storageService:      storageService,
 		encryptionService:   encryptionService,
+		tokenizationService: tokenizationService,
 		kycProvider:         kycProvider,
 		notificationService: notificationService,
 		validationService:   validationService,
+		addressVerifier:     addressVerifier,
 		auditService:        auditService,
 		cacheService:        cacheService,
+		verificationCodes:   verificationCodes,
+		duplicateRepo:       duplicateRepo,
+		webhookRepo:         webhookRepo,
+		noteRepo:            noteRepo,
+		importRepo:          importRepo,
+		coApplicantRepo:     coApplicantRepo,
+		authAccounts:        authAccounts,
+		loanAccounts:        loanAccounts,
+		restoreWindow:       restoreWindow,
+		verificationPolicy:  verificationPolicy,
+		kycExpiry:           kycExpiry,
 		logger:              logger,
 		localizer:           localizer,
 	}
@@ -104,13 +146,15 @@ func (s *UserServiceImpl) CreateUser(ctx context.Context, request *domain.Create
 
 	// Create user entity
 	user := &domain.User{
-		ID:           uuid.New().String(),
-		Email:        strings.ToLower(strings.TrimSpace(request.Email)),
-		PasswordHash: string(passwordHash),
-		Phone:        request.Phone,
-		Status:       "active",
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		ID:              uuid.New().String(),
+		Email:           strings.ToLower(strings.TrimSpace(request.Email)),
+		EmailNormalized: domain.NormalizeEmail(request.Email),
+		PasswordHash:    string(passwordHash),
+		Phone:           request.Phone,
+		PhoneNormalized: domain.NormalizePhone(request.Phone),
+		Status:          "active",
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
 	}
 
 	// Create user in database
@@ -132,6 +176,9 @@ func (s *UserServiceImpl) CreateUser(ctx context.Context, request *domain.Create
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+	if request.DateOfBirth != nil {
+		profile.DateOfBirth = *request.DateOfBirth
+	}
 
 	if err := s.userRepo.CreateProfile(ctx, profile); err != nil {
 		logger.Error("Failed to create user profile", zap.Error(err))
@@ -139,6 +186,24 @@ func (s *UserServiceImpl) CreateUser(ctx context.Context, request *domain.Create
 		// In production, you might want to implement compensating transactions
 	}
 
+	// Flag likely re-registrations for admin review; creation is never blocked on a fuzzy match
+	if _, err := s.detectDuplicates(ctx, &domain.DuplicateProbe{
+		UserID:          user.ID,
+		EmailNormalized: user.EmailNormalized,
+		PhoneNormalized: user.PhoneNormalized,
+		FirstName:       request.FirstName,
+		LastName:        request.LastName,
+		DateOfBirth:     request.DateOfBirth,
+	}); err != nil {
+		logger.Warn("Failed to run duplicate detection", zap.Error(err))
+	}
+
+	s.publishWebhookEvent(ctx, domain.WebhookEventUserCreated, map[string]interface{}{
+		"user_id":    user.ID,
+		"email":      user.Email,
+		"created_at": user.CreatedAt,
+	})
+
 	// Send welcome email
 	if err := s.notificationService.SendWelcomeEmail(ctx, user.ID, user.Email, request.FirstName); err != nil {
 		logger.Warn("Failed to send welcome email", zap.Error(err))
@@ -262,6 +327,7 @@ func (s *UserServiceImpl) UpdateUser(ctx context.Context, userID string, request
 
 	if request.Phone != nil && *request.Phone != existingUser.Phone {
 		updates["phone"] = *request.Phone
+		updates["phone_normalized"] = domain.NormalizePhone(*request.Phone)
 		updates["phone_verified"] = false
 		changes["phone"] = map[string]interface{}{
 			"old": existingUser.Phone,
@@ -319,17 +385,21 @@ func (s *UserServiceImpl) DeleteUser(ctx context.Context, userID string) error {
 		}
 	}
 
-	// Soft delete user
-	updates := map[string]interface{}{
-		"status":     "deleted",
-		"updated_at": time.Now(),
+	// Block login before the soft delete so a failed propagation never leaves a deleted user able to sign in
+	if err := s.authAccounts.DeactivateAccount(ctx, userID); err != nil {
+		logger.Error("Failed to deactivate auth account", zap.Error(err))
+		return &domain.UserError{
+			Code:    domain.USER_034,
+			Message: s.localizer.Localize(ctx, domain.USER_034, nil),
+		}
 	}
 
-	if err := s.userRepo.UpdateUser(ctx, userID, updates); err != nil {
+	// Soft delete user
+	if err := s.userRepo.DeleteUser(ctx, userID); err != nil {
 		logger.Error("Failed to delete user", zap.Error(err))
 		return &domain.UserError{
-			Code:        domain.USER_026,
-			Message:     s.localizer.Localize(context.Background(), domain.USER_026, nil),
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
 		}
 	}
 
@@ -338,10 +408,74 @@ func (s *UserServiceImpl) DeleteUser(ctx context.Context, userID string) error {
 		logger.Warn("Failed to invalidate user cache", zap.Error(err))
 	}
 
+	if err := s.auditService.LogSecurityEvent(ctx, userID, "user_soft_deleted", nil); err != nil {
+		logger.Warn("Failed to log audit event", zap.Error(err))
+	}
+
 	logger.Info("User deleted successfully")
 	return nil
 }
 
+// RestoreUser reverses a soft delete while the user is still within the restore window
+func (s *UserServiceImpl) RestoreUser(ctx context.Context, userID string) (*domain.User, error) {
+	logger := s.logger.With(
+		zap.String("operation", "restore_user"),
+		zap.String("user_id", userID),
+	)
+
+	user, err := s.userRepo.GetDeletedUser(ctx, userID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, &domain.UserError{
+				Code:    domain.USER_030,
+				Message: s.localizer.Localize(ctx, domain.USER_030, nil),
+			}
+		}
+		logger.Error("Failed to get deleted user", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	if user.DeletedAt != nil && time.Since(*user.DeletedAt) > s.restoreWindow {
+		logger.Warn("Restore window expired", zap.Time("deleted_at", *user.DeletedAt))
+		return nil, &domain.UserError{
+			Code:    domain.USER_044,
+			Message: s.localizer.Localize(ctx, domain.USER_044, nil),
+		}
+	}
+
+	if err := s.userRepo.RestoreUser(ctx, userID); err != nil {
+		logger.Error("Failed to restore user", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	// The user stays locked out of auth until reactivation succeeds, which is the safe failure mode
+	if err := s.authAccounts.ReactivateAccount(ctx, userID); err != nil {
+		logger.Warn("Failed to reactivate auth account", zap.Error(err))
+	}
+
+	if err := s.cacheService.InvalidateUserCache(ctx, userID); err != nil {
+		logger.Warn("Failed to invalidate user cache", zap.Error(err))
+	}
+
+	if err := s.auditService.LogSecurityEvent(ctx, userID, "user_restored", map[string]interface{}{
+		"deleted_at": user.DeletedAt,
+	}); err != nil {
+		logger.Warn("Failed to log audit event", zap.Error(err))
+	}
+
+	user.DeletedAt = nil
+	user.PasswordHash = ""
+
+	logger.Info("User restored successfully")
+	return user, nil
+}
+
 func (s *UserServiceImpl) GetProfile(ctx context.Context, userID string) (*domain.UserProfile, error) {
 	logger := s.logger.With(
 		zap.String("operation", "get_profile"),
@@ -370,20 +504,6 @@ func (s *UserServiceImpl) GetProfile(ctx context.Context, userID string) (*domai
 		}
 	}
 
-	// Decrypt SSN if present
-	if profile.SSNEncrypted != "" {
-		decryptedSSN, err := s.encryptionService.DecryptField(profile.SSNEncrypted)
-		if err != nil {
-			logger.Error("Failed to decrypt SSN", zap.Error(err))
-			// Don't return error, just log it
-		} else {
-			// Mask SSN for security (show only last 4 digits)
-			if len(decryptedSSN) >= 4 {
-				profile.SSNEncrypted = "***-**-" + decryptedSSN[len(decryptedSSN)-4:]
-			}
-		}
-	}
-
 	// Cache the profile
 	if err := s.cacheService.CacheProfile(ctx, userID, profile, 3600); err != nil {
 		logger.Warn("Failed to cache profile", zap.Error(err))
@@ -430,6 +550,19 @@ func (s *UserServiceImpl) UpdateProfile(ctx context.Context, userID string, requ
 		}
 	}
 
+	// SSNs are exchanged for vault tokens at ingestion; only the token is stored on the profile
+	if request.SSN != nil {
+		token, err := s.tokenizationService.Tokenize(ctx, domain.TokenTypeSSN, *request.SSN)
+		if err != nil {
+			logger.Error("Failed to tokenize SSN", zap.Error(err))
+			return nil, err
+		}
+		if token.Token != existingProfile.SSNToken {
+			updates["ssn_token"] = token.Token
+			changes["ssn_token"] = "updated"
+		}
+	}
+
 	if request.Phone != nil && *request.Phone != existingProfile.Phone {
 		updates["phone"] = *request.Phone
 		changes["phone"] = map[string]interface{}{
@@ -438,11 +571,23 @@ func (s *UserServiceImpl) UpdateProfile(ctx context.Context, userID string, requ
 		}
 	}
 
+	var addressVerification *domain.AddressVerification
 	if request.Address != nil {
-		updates["address"] = *request.Address
+		address := request.Address
+
+		// Standardize to CASS format; a provider outage should not block the update
+		standardized, verification, err := s.addressVerifier.StandardizeAddress(ctx, request.Address)
+		if err != nil {
+			logger.Warn("Address verification failed, storing address as submitted", zap.Error(err))
+		} else {
+			address = standardized
+			addressVerification = verification
+		}
+
+		updates["address"] = *address
 		changes["address"] = map[string]interface{}{
 			"old": existingProfile.Address,
-			"new": *request.Address,
+			"new": *address,
 		}
 	}
 
@@ -472,6 +617,7 @@ func (s *UserServiceImpl) UpdateProfile(ctx context.Context, userID string, requ
 		if err := s.cacheService.InvalidateProfileCache(ctx, userID); err != nil {
 			logger.Warn("Failed to invalidate profile cache", zap.Error(err))
 		}
+		s.invalidateCompleteness(ctx, userID)
 
 		// Log audit event
 		if err := s.auditService.LogProfileUpdated(ctx, userID, changes); err != nil {
@@ -482,7 +628,47 @@ func (s *UserServiceImpl) UpdateProfile(ctx context.Context, userID string, requ
 	}
 
 	// Get updated profile
-	return s.GetProfile(ctx, userID)
+	profile, err := s.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	profile.AddressVerification = addressVerification
+	return profile, nil
+}
+
+// SendUserNotification sends a push notification another service requested for one of its users,
+// and emails it to the user as well when the request asks for it
+func (s *UserServiceImpl) SendUserNotification(ctx context.Context, userID string, request *domain.UserNotificationRequest) error {
+	logger := s.logger.With(
+		zap.String("operation", "send_user_notification"),
+		zap.String("user_id", userID),
+	)
+
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if request.Email {
+		if err := s.notificationService.SendNotificationEmail(ctx, userID, user.Email, request.Title, request.Message); err != nil {
+			logger.Error("Failed to send notification email", zap.Error(err))
+			return &domain.UserError{
+				Code:    domain.USER_029,
+				Message: s.localizer.Localize(ctx, domain.USER_029, nil),
+			}
+		}
+	}
+
+	if err := s.notificationService.SendPushNotification(ctx, userID, request.Title, request.Message, request.Data); err != nil {
+		logger.Error("Failed to send push notification", zap.Error(err))
+		return &domain.UserError{
+			Code:    domain.USER_029,
+			Message: s.localizer.Localize(ctx, domain.USER_029, nil),
+		}
+	}
+
+	logger.Info("User notification sent")
+	return nil
 }
 
 // Validation methods
@@ -0,0 +1,164 @@
+package application
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"expvar"
+	"fmt"
+	"math/big"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// Verification code helpers for UserServiceImpl
+
+// throttledVerificationSends counts rejected sends by channel and reason ("cooldown", "user" or
+// "destination"); it is published with the other expvar metrics
+var throttledVerificationSends = expvar.NewMap("user_verification_sends_throttled")
+
+// issueVerificationCode enforces the resend cooldown and send rate limits, then generates and stores a
+// fresh hashed code. The plaintext code is returned only so it can be delivered to destination.
+func (s *UserServiceImpl) issueVerificationCode(ctx context.Context, channel domain.VerificationChannel, userID, destination string) (string, error) {
+	logger := s.logger.With(
+		zap.String("operation", "issue_verification_code"),
+		zap.String("channel", string(channel)),
+		zap.String("user_id", userID),
+	)
+
+	retryAfter, err := s.verificationCodes.AcquireResendCooldown(ctx, channel, userID, s.verificationPolicy.ResendCooldown)
+	if err != nil {
+		return "", &domain.UserError{
+			Code:    domain.USER_027,
+			Message: s.localizer.Localize(ctx, domain.USER_027, nil),
+		}
+	}
+	if retryAfter > 0 {
+		throttledVerificationSends.Add(string(channel)+".cooldown", 1)
+		return "", &domain.UserError{
+			Code:       domain.USER_049,
+			Message:    s.localizer.Localize(ctx, domain.USER_049, nil),
+			RetryAfter: retryAfter,
+		}
+	}
+
+	exhausted, retryAfter, err := s.verificationCodes.AcquireSendQuota(ctx, channel, userID, destination, s.verificationPolicy.RateLimits)
+	if err != nil {
+		return "", &domain.UserError{
+			Code:    domain.USER_027,
+			Message: s.localizer.Localize(ctx, domain.USER_027, nil),
+		}
+	}
+	if exhausted != nil {
+		throttledVerificationSends.Add(string(channel)+"."+string(exhausted.Scope), 1)
+		logger.Warn("Verification send rate limited",
+			zap.String("scope", string(exhausted.Scope)),
+			zap.Int("limit", exhausted.Limit),
+			zap.Duration("window", exhausted.Window),
+			zap.Duration("retry_after", retryAfter),
+		)
+		return "", &domain.UserError{
+			Code:       domain.USER_057,
+			Message:    s.localizer.Localize(ctx, domain.USER_057, nil),
+			RetryAfter: retryAfter,
+		}
+	}
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		return "", &domain.UserError{
+			Code:    domain.USER_028,
+			Message: s.localizer.Localize(ctx, domain.USER_028, nil),
+		}
+	}
+
+	if err := s.verificationCodes.SaveCode(ctx, channel, userID, &domain.VerificationCode{
+		CodeHash:  s.hashVerificationCode(channel, userID, code),
+		ExpiresAt: time.Now().Add(s.verificationPolicy.CodeTTL),
+	}); err != nil {
+		return "", &domain.UserError{
+			Code:    domain.USER_027,
+			Message: s.localizer.Localize(ctx, domain.USER_027, nil),
+		}
+	}
+
+	return code, nil
+}
+
+// checkVerificationCode validates a submitted code, counting the attempt and consuming the code on
+// success. The attempt is counted and checked atomically in the store; if it cannot be, the code is
+// rejected.
+func (s *UserServiceImpl) checkVerificationCode(ctx context.Context, channel domain.VerificationChannel, userID, code string) error {
+	logger := s.logger.With(
+		zap.String("operation", "check_verification_code"),
+		zap.String("channel", string(channel)),
+		zap.String("user_id", userID),
+	)
+
+	result, err := s.verificationCodes.CheckCode(ctx, channel, userID, s.hashVerificationCode(channel, userID, code), s.verificationPolicy.MaxAttempts)
+	if err != nil {
+		return &domain.UserError{
+			Code:    domain.USER_027,
+			Message: s.localizer.Localize(ctx, domain.USER_027, nil),
+		}
+	}
+
+	switch result {
+	case domain.VerificationCodeVerified:
+		return nil
+	case domain.VerificationCodeMissing:
+		return &domain.UserError{
+			Code:    domain.USER_047,
+			Message: s.localizer.Localize(ctx, domain.USER_047, nil),
+		}
+	case domain.VerificationCodeMismatch:
+		return &domain.UserError{
+			Code:    domain.USER_046,
+			Message: s.localizer.Localize(ctx, domain.USER_046, nil),
+			Field:   "verification_code",
+		}
+	default:
+		logger.Warn("Verification code locked after too many attempts", zap.String("result", string(result)))
+		return &domain.UserError{
+			Code:    domain.USER_048,
+			Message: s.localizer.Localize(ctx, domain.USER_048, nil),
+		}
+	}
+}
+
+// abandonVerificationCode discards a code that could not be delivered and releases the resend
+// cooldown, so the user can ask for another straight away
+func (s *UserServiceImpl) abandonVerificationCode(ctx context.Context, logger *zap.Logger, channel domain.VerificationChannel, userID string) {
+	s.discardVerificationCode(ctx, logger, channel, userID)
+	if err := s.verificationCodes.ReleaseResendCooldown(ctx, channel, userID); err != nil {
+		logger.Warn("Failed to release resend cooldown", zap.Error(err))
+	}
+}
+
+func (s *UserServiceImpl) discardVerificationCode(ctx context.Context, logger *zap.Logger, channel domain.VerificationChannel, userID string) {
+	if err := s.verificationCodes.DeleteCode(ctx, channel, userID); err != nil {
+		logger.Warn("Failed to delete verification code", zap.Error(err))
+	}
+}
+
+// hashVerificationCode binds a code to its user and channel under the server secret, so a leaked
+// Redis snapshot cannot be brute-forced offline over the small code space
+func (s *UserServiceImpl) hashVerificationCode(channel domain.VerificationChannel, userID, code string) string {
+	mac := hmac.New(sha256.New, []byte(s.verificationPolicy.HashSecret))
+	mac.Write([]byte(fmt.Sprintf("%s:%s:%s", channel, userID, code)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// generateVerificationCode returns a uniformly random six-digit code
+func generateVerificationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate verification code: %w", err)
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
@@ -0,0 +1,196 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// maxWebhookRetryDelay caps the exponential backoff between delivery attempts
+const maxWebhookRetryDelay = 6 * time.Hour
+
+// WebhookDeliveryJob sends queued webhook deliveries, retrying failures with exponential backoff
+// and dead-lettering deliveries that exhaust their attempts.
+type WebhookDeliveryJob struct {
+	webhookRepo       domain.WebhookRepository
+	sender            domain.WebhookSender
+	encryptionService domain.EncryptionService
+	batchSize         int
+	maxAttempts       int
+	retryBaseDelay    time.Duration
+	interval          time.Duration
+	logger            *zap.Logger
+}
+
+// WebhookDeliveryResult summarizes a single delivery pass
+type WebhookDeliveryResult struct {
+	Claimed      int `json:"claimed"`
+	Succeeded    int `json:"succeeded"`
+	Retried      int `json:"retried"`
+	DeadLettered int `json:"dead_lettered"`
+}
+
+func NewWebhookDeliveryJob(
+	webhookRepo domain.WebhookRepository,
+	sender domain.WebhookSender,
+	encryptionService domain.EncryptionService,
+	batchSize int,
+	maxAttempts int,
+	retryBaseDelay time.Duration,
+	interval time.Duration,
+	logger *zap.Logger,
+) *WebhookDeliveryJob {
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 8
+	}
+	if retryBaseDelay <= 0 {
+		retryBaseDelay = 30 * time.Second
+	}
+	return &WebhookDeliveryJob{
+		webhookRepo:       webhookRepo,
+		sender:            sender,
+		encryptionService: encryptionService,
+		batchSize:         batchSize,
+		maxAttempts:       maxAttempts,
+		retryBaseDelay:    retryBaseDelay,
+		interval:          interval,
+		logger:            logger,
+	}
+}
+
+// Start runs delivery passes on the configured interval until the context is cancelled
+func (j *WebhookDeliveryJob) Start(ctx context.Context) {
+	if j.interval <= 0 {
+		j.logger.Info("Webhook delivery job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Webhook delivery job stopped")
+			return
+		case <-ticker.C:
+			if _, err := j.RunOnce(ctx); err != nil {
+				j.logger.Error("Webhook delivery pass failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RunOnce claims and attempts one batch of due deliveries
+func (j *WebhookDeliveryJob) RunOnce(ctx context.Context) (*WebhookDeliveryResult, error) {
+	logger := j.logger.With(zap.String("operation", "webhook_delivery"))
+	result := &WebhookDeliveryResult{}
+
+	// The lease outlasts a full batch of timed-out sends so a slow pass is not picked up twice
+	deliveries, err := j.webhookRepo.ClaimDueDeliveries(ctx, j.batchSize, 5*time.Minute)
+	if err != nil {
+		return result, err
+	}
+	result.Claimed = len(deliveries)
+
+	endpoints := make(map[string]*domain.WebhookEndpoint)
+	for _, delivery := range deliveries {
+		endpoint, ok := endpoints[delivery.EndpointID]
+		if !ok {
+			endpoint, err = j.webhookRepo.GetEndpoint(ctx, delivery.EndpointID)
+			if err != nil && err.Error() != "not found" {
+				logger.Warn("Failed to load webhook endpoint", zap.String("endpoint_id", delivery.EndpointID), zap.Error(err))
+				continue
+			}
+			endpoints[delivery.EndpointID] = endpoint
+		}
+
+		j.attempt(ctx, logger, endpoint, delivery)
+
+		switch delivery.Status {
+		case domain.WebhookDeliverySucceeded:
+			result.Succeeded++
+		case domain.WebhookDeliveryDeadLettered:
+			result.DeadLettered++
+		default:
+			result.Retried++
+		}
+
+		delivery.UpdatedAt = time.Now()
+		if err := j.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+			logger.Warn("Failed to store webhook delivery outcome", zap.String("delivery_id", delivery.ID), zap.Error(err))
+		}
+	}
+
+	if result.Claimed > 0 {
+		logger.Info("Webhook delivery pass completed",
+			zap.Int("claimed", result.Claimed),
+			zap.Int("succeeded", result.Succeeded),
+			zap.Int("retried", result.Retried),
+			zap.Int("dead_lettered", result.DeadLettered),
+		)
+	}
+
+	return result, ctx.Err()
+}
+
+// attempt sends a delivery once and records the outcome on it
+func (j *WebhookDeliveryJob) attempt(ctx context.Context, logger *zap.Logger, endpoint *domain.WebhookEndpoint, delivery *domain.WebhookDelivery) {
+	if endpoint == nil || !endpoint.Active {
+		delivery.Status = domain.WebhookDeliveryDeadLettered
+		delivery.LastError = "endpoint disabled"
+		return
+	}
+
+	delivery.Attempts++
+
+	secret, err := j.encryptionService.DecryptField(endpoint.SecretEncrypted)
+	if err != nil {
+		j.fail(logger, delivery, 0, fmt.Errorf("failed to decrypt endpoint secret: %w", err))
+		return
+	}
+
+	status, err := j.sender.Send(ctx, endpoint.URL, secret, delivery)
+	delivery.ResponseStatus = status
+	if err == nil && (status < http.StatusOK || status >= http.StatusMultipleChoices) {
+		err = fmt.Errorf("receiver responded with status %d", status)
+	}
+	if err != nil {
+		j.fail(logger, delivery, status, err)
+		return
+	}
+
+	now := time.Now()
+	delivery.Status = domain.WebhookDeliverySucceeded
+	delivery.DeliveredAt = &now
+	delivery.LastError = ""
+}
+
+// fail schedules the next attempt, or dead-letters the delivery once its attempts are spent
+func (j *WebhookDeliveryJob) fail(logger *zap.Logger, delivery *domain.WebhookDelivery, status int, err error) {
+	delivery.LastError = err.Error()
+
+	if delivery.Attempts >= j.maxAttempts {
+		delivery.Status = domain.WebhookDeliveryDeadLettered
+		logger.Warn("Webhook delivery dead-lettered",
+			zap.String("delivery_id", delivery.ID),
+			zap.Int("attempts", delivery.Attempts),
+			zap.Int("response_status", status),
+			zap.Error(err),
+		)
+		return
+	}
+
+	// Bound the shift so large attempt counts cannot overflow the duration
+	delay := min(j.retryBaseDelay<<min(delivery.Attempts-1, 16), maxWebhookRetryDelay)
+	delivery.Status = domain.WebhookDeliveryPending
+	delivery.NextAttemptAt = time.Now().Add(delay)
+}
@@ -0,0 +1,276 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// Delivery listing bounds for the admin API
+const (
+	defaultWebhookDeliveryLimit = 50
+	maxWebhookDeliveryLimit     = 200
+)
+
+// Webhook management methods for UserServiceImpl
+
+func (s *UserServiceImpl) RegisterWebhook(ctx context.Context, request *domain.RegisterWebhookRequest) (*domain.WebhookEndpoint, error) {
+	logger := s.logger.With(
+		zap.String("operation", "register_webhook"),
+		zap.String("url", request.URL),
+	)
+
+	if err := s.validateWebhookRequest(ctx, request); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		logger.Error("Failed to generate webhook secret", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_028,
+			Message: s.localizer.Localize(ctx, domain.USER_028, nil),
+		}
+	}
+
+	secretEncrypted, err := s.encryptionService.EncryptField(secret)
+	if err != nil {
+		logger.Error("Failed to encrypt webhook secret", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_028,
+			Message: s.localizer.Localize(ctx, domain.USER_028, nil),
+		}
+	}
+
+	endpoint := &domain.WebhookEndpoint{
+		ID:              uuid.New().String(),
+		URL:             request.URL,
+		Description:     request.Description,
+		Events:          domain.WebhookEventTypes(request.Events),
+		Active:          true,
+		SecretEncrypted: secretEncrypted,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	if err := s.webhookRepo.CreateEndpoint(ctx, endpoint); err != nil {
+		logger.Error("Failed to create webhook endpoint", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	logger.Info("Webhook endpoint registered", zap.String("endpoint_id", endpoint.ID))
+
+	// The signing secret is only ever shown in this response
+	endpoint.Secret = secret
+	return endpoint, nil
+}
+
+func (s *UserServiceImpl) ListWebhooks(ctx context.Context) ([]*domain.WebhookEndpoint, error) {
+	endpoints, err := s.webhookRepo.ListEndpoints(ctx)
+	if err != nil {
+		s.logger.Error("Failed to list webhook endpoints", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	return endpoints, nil
+}
+
+func (s *UserServiceImpl) DisableWebhook(ctx context.Context, endpointID string) error {
+	logger := s.logger.With(
+		zap.String("operation", "disable_webhook"),
+		zap.String("endpoint_id", endpointID),
+	)
+
+	if err := s.webhookRepo.DisableEndpoint(ctx, endpointID); err != nil {
+		if err.Error() == "not found" {
+			return &domain.UserError{
+				Code:    domain.USER_051,
+				Message: s.localizer.Localize(ctx, domain.USER_051, nil),
+			}
+		}
+		logger.Error("Failed to disable webhook endpoint", zap.Error(err))
+		return &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	logger.Info("Webhook endpoint disabled")
+	return nil
+}
+
+func (s *UserServiceImpl) ListWebhookDeliveries(ctx context.Context, query *domain.WebhookDeliveryQuery) ([]*domain.WebhookDelivery, error) {
+	switch query.Status {
+	case "", domain.WebhookDeliveryPending, domain.WebhookDeliverySucceeded, domain.WebhookDeliveryDeadLettered:
+	default:
+		return nil, &domain.UserError{
+			Code:    domain.USER_042,
+			Message: s.localizer.Localize(ctx, domain.USER_042, nil),
+			Field:   "status",
+		}
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = defaultWebhookDeliveryLimit
+	}
+	query.Limit = min(query.Limit, maxWebhookDeliveryLimit)
+
+	deliveries, err := s.webhookRepo.ListDeliveries(ctx, query)
+	if err != nil {
+		s.logger.Error("Failed to list webhook deliveries", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	return deliveries, nil
+}
+
+// ReplayWebhookDelivery requeues a delivery with a fresh attempt budget
+func (s *UserServiceImpl) ReplayWebhookDelivery(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error) {
+	logger := s.logger.With(
+		zap.String("operation", "replay_webhook_delivery"),
+		zap.String("delivery_id", deliveryID),
+	)
+
+	delivery, err := s.webhookRepo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, &domain.UserError{
+				Code:    domain.USER_052,
+				Message: s.localizer.Localize(ctx, domain.USER_052, nil),
+			}
+		}
+		logger.Error("Failed to get webhook delivery", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	delivery.Status = domain.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	delivery.LastError = ""
+	delivery.UpdatedAt = time.Now()
+
+	if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		logger.Error("Failed to requeue webhook delivery", zap.Error(err))
+		return nil, &domain.UserError{
+			Code:    domain.USER_026,
+			Message: s.localizer.Localize(ctx, domain.USER_026, nil),
+		}
+	}
+
+	logger.Info("Webhook delivery requeued")
+	return delivery, nil
+}
+
+// publishWebhookEvent queues a delivery for every active endpoint subscribed to eventType.
+// Delivery itself happens in WebhookDeliveryJob, so callers never wait on receivers.
+func (s *UserServiceImpl) publishWebhookEvent(ctx context.Context, eventType string, data interface{}) {
+	logger := s.logger.With(
+		zap.String("operation", "publish_webhook_event"),
+		zap.String("event_type", eventType),
+	)
+
+	endpoints, err := s.webhookRepo.ListEndpointsForEvent(ctx, eventType)
+	if err != nil {
+		logger.Warn("Failed to list webhook endpoints", zap.Error(err))
+		return
+	}
+	if len(endpoints) == 0 {
+		return
+	}
+
+	event := domain.WebhookEvent{
+		ID:        uuid.New().String(),
+		Type:      eventType,
+		CreatedAt: time.Now(),
+		Data:      data,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logger.Warn("Failed to encode webhook event", zap.Error(err))
+		return
+	}
+
+	for _, endpoint := range endpoints {
+		delivery := &domain.WebhookDelivery{
+			ID:            uuid.New().String(),
+			EndpointID:    endpoint.ID,
+			EventID:       event.ID,
+			EventType:     eventType,
+			Payload:       domain.WebhookPayload(payload),
+			Status:        domain.WebhookDeliveryPending,
+			NextAttemptAt: time.Now(),
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+		}
+		if err := s.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+			logger.Warn("Failed to queue webhook delivery", zap.Error(err), zap.String("endpoint_id", endpoint.ID))
+		}
+	}
+}
+
+func (s *UserServiceImpl) validateWebhookRequest(ctx context.Context, request *domain.RegisterWebhookRequest) error {
+	invalid := func(field string) error {
+		return &domain.UserError{
+			Code:    domain.USER_050,
+			Message: s.localizer.Localize(ctx, domain.USER_050, nil),
+			Field:   field,
+		}
+	}
+
+	parsed, err := url.Parse(request.URL)
+	if err != nil || parsed.Hostname() == "" || parsed.Scheme != "https" || parsed.User != nil {
+		return invalid("url")
+	}
+
+	// Every address the host resolves to must be public. The sender checks again when it dials,
+	// since the DNS answer can change after registration.
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, parsed.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return invalid("url")
+	}
+	for _, addr := range addrs {
+		if !domain.IsPublicWebhookAddress(addr.IP) {
+			return invalid("url")
+		}
+	}
+
+	if len(request.Events) == 0 {
+		return invalid("events")
+	}
+	for _, eventType := range request.Events {
+		if !domain.IsValidWebhookEvent(eventType) {
+			return invalid("events")
+		}
+	}
+
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(bytes), nil
+}
@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -17,15 +19,22 @@ import (
 	_ "github.com/lib/pq"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
+	"github.com/huuhoait/los-demo/services/shared/pkg/address"
 	"github.com/huuhoait/los-demo/services/shared/pkg/config"
 	"github.com/huuhoait/los-demo/services/shared/pkg/i18n"
 	"github.com/huuhoait/los-demo/services/shared/pkg/logger"
 	sharedMiddleware "github.com/huuhoait/los-demo/services/shared/pkg/middleware"
+	"github.com/huuhoait/los-demo/services/shared/pkg/rpc"
+	userv1 "github.com/huuhoait/los-demo/services/shared/proto/user/v1"
 	"github.com/huuhoait/los-demo/services/user/application"
 	"github.com/huuhoait/los-demo/services/user/domain"
 	"github.com/huuhoait/los-demo/services/user/infrastructure"
 	"github.com/huuhoait/los-demo/services/user/interfaces"
+	"github.com/huuhoait/los-demo/services/user/interfaces/middleware"
 )
 
 func main() {
@@ -79,6 +88,15 @@ func main() {
 		appLogger.Fatal("Failed to initialize application", zap.Error(err))
 	}
 
+	// Start background jobs
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go app.KeyRotationJob.Start(jobCtx)
+	go app.WebhookDeliveryJob.Start(jobCtx)
+	go app.RetentionJob.Start(jobCtx)
+	go app.ImportJob.Start(jobCtx)
+	go app.KYCExpiryJob.Start(jobCtx)
+
 	// Initialize HTTP server
 	server := initializeHTTPServer(app, cfg, appLogger, localizer)
 
@@ -90,24 +108,82 @@ func main() {
 		}
 	}()
 
+	// Process metrics, including throttled verification sends, are only served on the internal debug listener
+	debugServer := newDebugServer(cfg.Server.DebugAddr)
+	if debugServer != nil {
+		go func() {
+			appLogger.Info("Starting debug server", zap.String("address", debugServer.Addr))
+			if err := debugServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				appLogger.Error("Failed to start debug server", zap.Error(err))
+			}
+		}()
+	}
+
+	// Serve UserService to internal callers over gRPC; REST stays the external API
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Port > 0 {
+		grpcServer, err = rpc.NewServer(cfg.GRPC, appLogger.Logger)
+		if err != nil {
+			appLogger.Fatal("Failed to create gRPC server", zap.Error(err))
+		}
+		userv1.RegisterUserServiceServer(grpcServer, interfaces.NewUserServer(app.UserService, appLogger.Logger))
+		healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.Port))
+		if err != nil {
+			appLogger.Fatal("Failed to listen for gRPC", zap.Error(err))
+		}
+		go func() {
+			appLogger.Info("Starting gRPC server", zap.Int("port", cfg.GRPC.Port))
+			if err := grpcServer.Serve(listener); err != nil {
+				appLogger.Error("gRPC server stopped", zap.Error(err))
+			}
+		}()
+	}
+
 	// Wait for interrupt signal to gracefully shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	appLogger.Info("Shutting down User Service...")
+	stopJobs()
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	if debugServer != nil {
+		if err := debugServer.Shutdown(ctx); err != nil {
+			appLogger.Warn("Debug server forced to shutdown", zap.Error(err))
+		}
+	}
+
 	if err := server.Shutdown(ctx); err != nil {
 		appLogger.Fatal("Server forced to shutdown", zap.Error(err))
 	}
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
 
 	appLogger.Info("User Service shutdown complete")
 }
 
+// newDebugServer serves expvar metrics on a separate address, meant to be bound to loopback or a
+// private network; an empty address disables it
+func newDebugServer(addr string) *http.Server {
+	if addr == "" {
+		return nil
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
+
 func initializeDatabase(cfg *config.Config, appLogger *logger.Logger) (*sqlx.DB, error) {
 	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Database.Host,
@@ -169,9 +245,14 @@ func initializeRedis(cfg *config.Config, appLogger *logger.Logger) *redis.Client
 }
 
 type Application struct {
-	UserService domain.UserService
-	UserHandler *interfaces.UserHandler
-	Logger      *zap.Logger
+	UserService        domain.UserService
+	UserHandler        *interfaces.UserHandler
+	KeyRotationJob     *application.KeyRotationJob
+	WebhookDeliveryJob *application.WebhookDeliveryJob
+	RetentionJob       *application.DocumentRetentionJob
+	ImportJob          *application.UserImportJob
+	KYCExpiryJob       *application.KYCExpiryJob
+	Logger             *zap.Logger
 }
 
 func initializeApplication(
@@ -185,11 +266,30 @@ func initializeApplication(
 	userRepo := infrastructure.NewPostgresUserRepository(db, appLogger.Logger)
 	kycRepo := infrastructure.NewPostgresKYCRepository(db, appLogger.Logger)
 	documentRepo := infrastructure.NewPostgresDocumentRepository(db, appLogger.Logger)
+	consentRepo := infrastructure.NewPostgresConsentRepository(db, appLogger.Logger)
+	tokenVaultRepo := infrastructure.NewPostgresTokenVaultRepository(db, appLogger.Logger)
+	duplicateRepo := infrastructure.NewPostgresDuplicateRepository(db, appLogger.Logger)
+	webhookRepo := infrastructure.NewPostgresWebhookRepository(db, appLogger.Logger)
+	noteRepo := infrastructure.NewPostgresUserNoteRepository(db, appLogger.Logger)
+	importRepo := infrastructure.NewPostgresUserImportRepository(db, appLogger.Logger)
+	coApplicantRepo := infrastructure.NewPostgresCoApplicantRepository(db, appLogger.Logger)
+	auditRepo := infrastructure.NewPostgresAuditRepository(db, appLogger.Logger)
 
 	// Initialize infrastructure services
 	cacheService := infrastructure.NewRedisCacheService(redisClient, appLogger.Logger)
+	verificationCodes := infrastructure.NewRedisVerificationCodeStore(redisClient, appLogger.Logger)
 	validationService := infrastructure.NewValidationService(appLogger.Logger)
-	encryptionService := infrastructure.NewAESEncryptionService(cfg.Encryption.MasterKey, appLogger.Logger)
+	addressVerifier := infrastructure.NewAddressVerificationService(
+		address.NewVerifier(cfg.AddressVerification, appLogger.Logger),
+		appLogger.Logger,
+	)
+	encryptionService, err := initializeEncryption(cfg, appLogger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize encryption: %w", err)
+	}
+
+	// Audit events go to the append-only, hash-chained audit store
+	auditService := application.NewAuditService(auditRepo, appLogger.Logger)
 
 	// Mock services for development (replace with real implementations in production)
 	kycProvider := infrastructure.NewMockKYCProviderService(appLogger.Logger)
@@ -197,39 +297,219 @@ func initializeApplication(
 	// TODO: Initialize real services
 	var storageService domain.DocumentStorageService
 	var notificationService domain.NotificationService
-	var auditService domain.AuditService
 
 	// For now, use mock implementations
 	storageService = NewMockStorageService(appLogger.Logger)
 	notificationService = NewMockNotificationService(appLogger.Logger)
-	auditService = NewMockAuditService(appLogger.Logger)
+
+	// Soft-deleted users are deactivated in the auth service so they cannot log in
+	authAccounts := infrastructure.NewAuthServiceClient(
+		cfg.ExternalServices.AuthService.URL,
+		cfg.ExternalServices.AuthService.ServiceToken,
+		time.Duration(cfg.ExternalServices.AuthService.Timeout)*time.Second,
+		appLogger.Logger,
+	)
+	loanAccounts := infrastructure.NewLoanServiceClient(
+		cfg.ExternalServices.LoanService.URL,
+		cfg.ExternalServices.LoanService.ServiceToken,
+		time.Duration(cfg.ExternalServices.LoanService.Timeout)*time.Second,
+		appLogger.Logger,
+	)
+
+	// Raw SSNs and tax IDs are swapped for vault tokens before they reach any other store
+	tokenizationService := application.NewTokenizationService(
+		tokenVaultRepo,
+		encryptionService,
+		validationService,
+		auditService,
+		cfg.Tokenization.HashKey,
+		appLogger.Logger,
+		localizer,
+	)
+
+	// Verification send limits, counted per user and per email address or phone number
+	verificationRateLimits := make([]domain.VerificationRateLimit, 0, len(cfg.Verification.RateLimits))
+	for _, limit := range cfg.Verification.RateLimits {
+		verificationRateLimits = append(verificationRateLimits, domain.VerificationRateLimit{
+			Scope:  domain.VerificationRateScope(limit.Scope),
+			Limit:  limit.Limit,
+			Window: time.Duration(limit.Window) * time.Second,
+		})
+	}
+
+	// KYC results expire after a validity period that can differ per provider
+	kycProviderValidity := make(map[string]time.Duration, len(cfg.KYC.ProviderValidityDays))
+	for provider, days := range cfg.KYC.ProviderValidityDays {
+		kycProviderValidity[provider] = time.Duration(days) * 24 * time.Hour
+	}
+	kycExpiry := domain.KYCExpiryPolicy{
+		DefaultValidity:  time.Duration(cfg.KYC.ValidityDays) * 24 * time.Hour,
+		ProviderValidity: kycProviderValidity,
+	}
 
 	// Initialize user service
 	userService := application.NewUserService(
 		userRepo,
 		kycRepo,
 		documentRepo,
+		consentRepo,
 		storageService,
 		encryptionService,
+		tokenizationService,
 		kycProvider,
 		notificationService,
 		validationService,
+		addressVerifier,
 		auditService,
 		cacheService,
+		verificationCodes,
+		duplicateRepo,
+		webhookRepo,
+		noteRepo,
+		importRepo,
+		coApplicantRepo,
+		authAccounts,
+		loanAccounts,
+		time.Duration(cfg.Retention.RestoreWindowDays)*24*time.Hour,
+		domain.VerificationPolicy{
+			CodeTTL:        time.Duration(cfg.Verification.CodeTTL) * time.Second,
+			MaxAttempts:    cfg.Verification.MaxAttempts,
+			ResendCooldown: time.Duration(cfg.Verification.ResendCooldown) * time.Second,
+			HashSecret:     cfg.Verification.HashSecret,
+			RateLimits:     verificationRateLimits,
+		},
+		kycExpiry,
 		appLogger.Logger,
 		localizer,
 	)
 
 	// Initialize handlers
-	userHandler := interfaces.NewUserHandler(userService, appLogger.Logger, localizer)
+	userHandler := interfaces.NewUserHandler(userService, tokenizationService, appLogger.Logger, localizer)
+
+	// Background re-encryption of data written under retired key versions
+	keyRotationJob := application.NewKeyRotationJob(
+		tokenVaultRepo,
+		documentRepo,
+		encryptionService,
+		cfg.Encryption.ReEncryptionBatchSize,
+		time.Duration(cfg.Encryption.ReEncryptionInterval)*time.Second,
+		appLogger.Logger,
+	)
+
+	// Background delivery of queued webhook events
+	webhookDeliveryJob := application.NewWebhookDeliveryJob(
+		webhookRepo,
+		infrastructure.NewHTTPWebhookSender(time.Duration(cfg.Webhooks.Timeout)*time.Second, appLogger.Logger),
+		encryptionService,
+		cfg.Webhooks.BatchSize,
+		cfg.Webhooks.MaxAttempts,
+		time.Duration(cfg.Webhooks.RetryBaseDelay)*time.Second,
+		time.Duration(cfg.Webhooks.DeliveryInterval)*time.Second,
+		appLogger.Logger,
+	)
+
+	// Scheduled purge of documents past their retention period
+	retentionRules := make([]domain.RetentionRule, 0, len(cfg.Retention.DocumentRules))
+	for _, rule := range cfg.Retention.DocumentRules {
+		retentionRules = append(retentionRules, domain.RetentionRule{
+			DocumentType: rule.DocumentType,
+			Anchor:       domain.RetentionAnchor(rule.Anchor),
+			RetainMonths: rule.RetainMonths,
+		})
+	}
+	retentionJob := application.NewDocumentRetentionJob(
+		documentRepo,
+		storageService,
+		loanAccounts,
+		auditService,
+		retentionRules,
+		cfg.Retention.PurgeBatchSize,
+		time.Duration(cfg.Retention.PurgeInterval)*time.Second,
+		appLogger.Logger,
+	)
+
+	// Asynchronous processing of bulk user imports
+	importJob := application.NewUserImportJob(
+		importRepo,
+		userRepo,
+		storageService,
+		validationService,
+		authAccounts,
+		notificationService,
+		auditService,
+		time.Duration(cfg.Imports.ProcessInterval)*time.Second,
+		appLogger.Logger,
+		localizer,
+	)
+
+	// Scheduled expiry of verified KYC results, prompting users to verify again
+	kycExpiryJob := application.NewKYCExpiryJob(
+		kycRepo,
+		cacheService,
+		notificationService,
+		auditService,
+		cfg.KYC.ExpiryBatchSize,
+		time.Duration(cfg.KYC.ExpiryCheckInterval)*time.Second,
+		appLogger.Logger,
+	)
 
 	return &Application{
-		UserService: userService,
-		UserHandler: userHandler,
-		Logger:      appLogger.Logger,
+		UserService:        userService,
+		UserHandler:        userHandler,
+		KeyRotationJob:     keyRotationJob,
+		WebhookDeliveryJob: webhookDeliveryJob,
+		RetentionJob:       retentionJob,
+		ImportJob:          importJob,
+		KYCExpiryJob:       kycExpiryJob,
+		Logger:             appLogger.Logger,
 	}, nil
 }
 
+func initializeEncryption(cfg *config.Config, appLogger *logger.Logger) (domain.EncryptionService, error) {
+	// Legacy single-key service is kept to read values written before envelope encryption
+	legacy := infrastructure.NewAESEncryptionService(cfg.Encryption.MasterKey, appLogger.Logger)
+
+	currentVersion := cfg.Encryption.CurrentKeyVersion
+	if currentVersion == "" {
+		currentVersion = "v1"
+	}
+
+	// Data keys are wrapped by the KMS; the local keyring is for development only
+	var keyProvider domain.KeyProvider
+	switch provider := cfg.Encryption.Provider; provider {
+	case "kms":
+		kms := cfg.Encryption.KMS
+		client, err := infrastructure.NewVaultTransitClient(kms.Address, kms.Token, kms.Mount, time.Duration(kms.Timeout)*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		keyProvider, err = infrastructure.NewKMSKeyProvider(client, kms.KeyIDs, currentVersion)
+		if err != nil {
+			return nil, err
+		}
+	case "", "local":
+		if cfg.Environment == "production" {
+			return nil, fmt.Errorf("the local key provider cannot be used in production")
+		}
+		keys := cfg.Encryption.Keys
+		if len(keys) == 0 {
+			keys = map[string]string{"v1": cfg.Encryption.MasterKey}
+		}
+		var err error
+		keyProvider, err = infrastructure.NewLocalKeyProvider(keys, currentVersion)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown key provider %q", provider)
+	}
+
+	appLogger.Info("Envelope encryption initialized",
+		zap.String("key_provider", cfg.Encryption.Provider),
+		zap.String("key_version", currentVersion))
+	return infrastructure.NewEnvelopeEncryptionService(keyProvider, legacy, appLogger.Logger), nil
+}
+
 func initializeHTTPServer(app *Application, cfg *config.Config, appLogger *logger.Logger, localizer *i18n.Localizer) *http.Server {
 	// Set Gin mode
 	if cfg.Environment == "production" {
@@ -268,6 +548,16 @@ func initializeHTTPServer(app *Application, cfg *config.Config, appLogger *logge
 	v1 := router.Group("/api/v1")
 	app.UserHandler.RegisterRoutes(v1)
 
+	// Admin routes accept auth service access tokens and check the caller's role
+	rbac := middleware.NewRBACMiddleware(cfg.Security.JWTSecret, localizer, appLogger.Logger)
+	app.UserHandler.RegisterAdminRoutes(v1.Group("/admin"), rbac)
+	app.UserHandler.RegisterImportRoutes(v1.Group("/users/import"), rbac)
+
+	// Internal service-to-service routes
+	serviceAuth := middleware.NewServiceAuthMiddleware(cfg.Tokenization.ServiceClients, localizer, appLogger.Logger)
+	internal := router.Group("/internal/v1")
+	app.UserHandler.RegisterInternalRoutes(internal, serviceAuth)
+
 	return &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
 		Handler:      router,
@@ -352,60 +642,32 @@ func (m *MockNotificationService) SendPasswordReset(ctx context.Context, userID,
 	return nil
 }
 
-func (m *MockNotificationService) SendPhoneVerification(ctx context.Context, userID, phone, verificationCode string) error {
-	m.logger.Info("Mock phone verification sent", zap.String("user_id", userID), zap.String("code", verificationCode))
+func (m *MockNotificationService) SendAccountInvite(ctx context.Context, userID, email, inviteToken string) error {
+	m.logger.Info("Mock account invite sent", zap.String("user_id", userID))
 	return nil
 }
 
-func (m *MockNotificationService) SendSecurityAlert(ctx context.Context, userID, phone, alertMessage string) error {
-	m.logger.Info("Mock security alert sent", zap.String("user_id", userID))
+func (m *MockNotificationService) SendCoApplicantInvite(ctx context.Context, inviterUserID, email, inviteToken string) error {
+	m.logger.Info("Mock co-applicant invite sent", zap.String("inviter_user_id", inviterUserID))
 	return nil
 }
 
-func (m *MockNotificationService) SendPushNotification(ctx context.Context, userID, title, message string, data map[string]interface{}) error {
-	m.logger.Info("Mock push notification sent", zap.String("user_id", userID), zap.String("title", title))
+func (m *MockNotificationService) SendNotificationEmail(ctx context.Context, userID, email, subject, message string) error {
+	m.logger.Info("Mock notification email sent", zap.String("user_id", userID), zap.String("subject", subject))
 	return nil
 }
 
-type MockAuditService struct {
-	logger *zap.Logger
-}
-
-func NewMockAuditService(logger *zap.Logger) domain.AuditService {
-	return &MockAuditService{logger: logger}
-}
-
-func (m *MockAuditService) LogUserCreated(ctx context.Context, userID, email string, metadata map[string]interface{}) error {
-	m.logger.Info("Mock audit: user created", zap.String("user_id", userID), zap.String("email", email))
-	return nil
-}
-
-func (m *MockAuditService) LogUserUpdated(ctx context.Context, userID string, changes map[string]interface{}) error {
-	m.logger.Info("Mock audit: user updated", zap.String("user_id", userID))
-	return nil
-}
-
-func (m *MockAuditService) LogProfileUpdated(ctx context.Context, userID string, changes map[string]interface{}) error {
-	m.logger.Info("Mock audit: profile updated", zap.String("user_id", userID))
-	return nil
-}
-
-func (m *MockAuditService) LogDocumentUploaded(ctx context.Context, userID, documentID, documentType string) error {
-	m.logger.Info("Mock audit: document uploaded", zap.String("user_id", userID), zap.String("document_id", documentID))
-	return nil
-}
-
-func (m *MockAuditService) LogKYCStatusChanged(ctx context.Context, userID, verificationType string, oldStatus, newStatus domain.KYCStatus) error {
-	m.logger.Info("Mock audit: KYC status changed", zap.String("user_id", userID), zap.String("type", verificationType))
+func (m *MockNotificationService) SendPhoneVerification(ctx context.Context, userID, phone, verificationCode string) error {
+	m.logger.Info("Mock phone verification sent", zap.String("user_id", userID), zap.String("code", verificationCode))
 	return nil
 }
 
-func (m *MockAuditService) LogSecurityEvent(ctx context.Context, userID, eventType string, metadata map[string]interface{}) error {
-	m.logger.Info("Mock audit: security event", zap.String("user_id", userID), zap.String("event_type", eventType))
+func (m *MockNotificationService) SendSecurityAlert(ctx context.Context, userID, phone, alertMessage string) error {
+	m.logger.Info("Mock security alert sent", zap.String("user_id", userID))
 	return nil
 }
 
-func (m *MockAuditService) LogDataAccess(ctx context.Context, userID, accessedBy, dataType string) error {
-	m.logger.Info("Mock audit: data access", zap.String("user_id", userID), zap.String("data_type", dataType))
+func (m *MockNotificationService) SendPushNotification(ctx context.Context, userID, title, message string, data map[string]interface{}) error {
+	m.logger.Info("Mock push notification sent", zap.String("user_id", userID), zap.String("title", title))
 	return nil
 }
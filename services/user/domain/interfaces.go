@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"io"
 	"time"
 )
@@ -13,6 +14,9 @@ type UserError struct {
 	Field        string                 `json:"field,omitempty"`
 	TemplateData map[string]interface{} `json:"template_data,omitempty"`
 	Cause        error                  `json:"-"`
+
+	// RetryAfter tells throttled clients when to try again; it is sent as the Retry-After header
+	RetryAfter time.Duration `json:"-"`
 }
 
 // Error implements the error interface
@@ -72,14 +76,37 @@ type UserRepository interface {
 	UpdateUser(ctx context.Context, userID string, updates map[string]interface{}) error
 	DeleteUser(ctx context.Context, userID string) error
 
+	// Soft delete recovery; GetDeletedUser only returns users that have been soft-deleted
+	GetDeletedUser(ctx context.Context, userID string) (*User, error)
+	RestoreUser(ctx context.Context, userID string) error
+
+	// Legal hold exempts a user's documents from retention purges
+	SetLegalHold(ctx context.Context, hold *LegalHold) error
+
+	// ConsumePasswordResetToken clears an unexpired reset token matching tokenHash and returns its
+	// user's ID, or an empty ID if none matches; each token can be consumed once
+	ConsumePasswordResetToken(ctx context.Context, tokenHash string) (string, error)
+
 	// User profile operations
 	CreateProfile(ctx context.Context, profile *UserProfile) error
 	GetProfile(ctx context.Context, userID string) (*UserProfile, error)
 	UpdateProfile(ctx context.Context, userID string, updates map[string]interface{}) error
 
 	// User search and listing
-	ListUsers(ctx context.Context, offset, limit int) ([]*User, error)
-	SearchUsers(ctx context.Context, criteria map[string]interface{}) ([]*User, error)
+	ListUsers(ctx context.Context, query *UserListQuery) ([]*User, error)
+	CountUsers(ctx context.Context, query *UserListQuery) (int, error)
+}
+
+// DuplicateRepository defines the interface for duplicate detection and user merges
+type DuplicateRepository interface {
+	// FindCandidates returns live users sharing an email, phone, or DOB-anchored identity with the probe
+	FindCandidates(ctx context.Context, probe *DuplicateProbe) ([]*DuplicateCandidate, error)
+	CreateMatch(ctx context.Context, match *DuplicateMatch) error
+	ListMatches(ctx context.Context, userID string) ([]*DuplicateMatch, error)
+
+	// MergeUsers moves documents, KYC records and consents to the survivor, soft-deletes the duplicate
+	// and records the merge in one transaction; moved counts are written back onto merge
+	MergeUsers(ctx context.Context, merge *UserMerge) error
 }
 
 // KYCRepository defines the interface for KYC operations
@@ -93,6 +120,9 @@ type KYCRepository interface {
 	// KYC status tracking
 	GetKYCStatus(ctx context.Context, userID string) (map[string]KYCStatus, error)
 	UpdateKYCStatus(ctx context.Context, userID, verificationType string, status KYCStatus) error
+
+	// ListExpiredVerifications returns verified records whose expiry is at or before asOf, ordered by ID after afterID
+	ListExpiredVerifications(ctx context.Context, asOf time.Time, afterID string, limit int) ([]*KYCVerification, error)
 }
 
 // DocumentRepository defines the interface for document operations
@@ -104,6 +134,35 @@ type DocumentRepository interface {
 	GetDocumentsByType(ctx context.Context, userID, documentType string) ([]*Document, error)
 	UpdateDocument(ctx context.Context, documentID string, updates map[string]interface{}) error
 	DeleteDocument(ctx context.Context, documentID string) error
+
+	// Key rotation support
+	ListDocuments(ctx context.Context, offset, limit int) ([]*Document, error)
+
+	// Retention support; candidates exclude purged documents and users on legal hold, ordered by ID after afterID
+	ListRetentionCandidates(ctx context.Context, documentType string, uploadedBefore time.Time, afterID string, limit int) ([]*Document, error)
+	TombstoneDocument(ctx context.Context, documentID, reason string) error
+}
+
+// ConsentRepository defines the interface for consent operations
+type ConsentRepository interface {
+	CreateConsent(ctx context.Context, consent *Consent) error
+	GetLatestConsent(ctx context.Context, userID string, consentType ConsentType) (*Consent, error)
+	ListConsents(ctx context.Context, userID string) ([]*Consent, error)
+	RevokeConsent(ctx context.Context, userID string, consentType ConsentType) error
+}
+
+// ErrTokenExists is returned by CreateToken when the value was tokenized concurrently
+var ErrTokenExists = errors.New("token already exists")
+
+// TokenVaultRepository defines the interface for token vault storage
+type TokenVaultRepository interface {
+	CreateToken(ctx context.Context, token *PIIToken) error
+	GetToken(ctx context.Context, token string) (*PIIToken, error)
+	GetTokenByValueHash(ctx context.Context, tokenType TokenType, valueHash string) (*PIIToken, error)
+	UpdateTokenValue(ctx context.Context, token, valueEncrypted string) error
+
+	// Key rotation support
+	ListTokens(ctx context.Context, offset, limit int) ([]*PIIToken, error)
 }
 
 // DocumentStorageService defines the interface for file storage operations
@@ -133,6 +192,27 @@ type EncryptionService interface {
 	// Key management
 	GenerateKey() (string, error)
 	RotateKey(oldKey string) (string, error)
+
+	// Key versioning for rotation
+	KeyVersion(ciphertext string) string
+	NeedsReEncryption(ciphertext string) bool
+	ReEncryptField(ciphertext string) (string, error)
+}
+
+// KeyProvider defines the interface for a KMS that wraps per-record data keys
+type KeyProvider interface {
+	// GenerateDataKey returns a plaintext data key, the same key wrapped by the master key, and the master key version
+	GenerateDataKey(ctx context.Context) (plaintext []byte, wrapped []byte, keyVersion string, err error)
+	DecryptDataKey(ctx context.Context, wrappedKey []byte, keyVersion string) ([]byte, error)
+	CurrentKeyVersion() string
+}
+
+// TokenizationService defines the interface for swapping sensitive identifiers for opaque tokens
+type TokenizationService interface {
+	// Tokenize returns the existing token for the value or issues a new one
+	Tokenize(ctx context.Context, tokenType TokenType, value string) (*PIIToken, error)
+	// Detokenize resolves a token to the original value; only exposed to services holding the detokenize scope
+	Detokenize(ctx context.Context, token string) (string, error)
 }
 
 // KYCProviderService defines the interface for external KYC providers
@@ -152,12 +232,43 @@ type KYCProviderService interface {
 	GetProviderName() string
 }
 
+// AddressVerificationService defines the interface for postal address standardization
+type AddressVerificationService interface {
+	// StandardizeAddress returns the CASS-standardized address, flagged if undeliverable, with any suggestions
+	StandardizeAddress(ctx context.Context, address *Address) (*Address, *AddressVerification, error)
+}
+
+// AuthAccountService defines the interface for propagating account status to the auth service
+type AuthAccountService interface {
+	// ProvisionAccount creates or updates the user's login identity; it has no password until a reset is redeemed
+	ProvisionAccount(ctx context.Context, userID, email, firstName, lastName string) error
+	// DeactivateAccount blocks login and revokes all sessions for the user
+	DeactivateAccount(ctx context.Context, userID string) error
+	ReactivateAccount(ctx context.Context, userID string) error
+	// RevokeCredentials invalidates the user's password and revokes all sessions
+	RevokeCredentials(ctx context.Context, userID string) error
+	SetPassword(ctx context.Context, userID, password string) error
+}
+
+// ErrAccountEmailTaken is returned by ProvisionAccount when auth holds the email for another user
+var ErrAccountEmailTaken = errors.New("email registered to another account")
+
+// LoanAccountService defines the interface for loan data held by the loan service
+type LoanAccountService interface {
+	ReassignApplications(ctx context.Context, fromUserID, toUserID string) (int, error)
+	GetLoanActivity(ctx context.Context, userID string) (*LoanActivity, error)
+}
+
 // NotificationService defines the interface for user notifications
 type NotificationService interface {
 	// Email notifications
 	SendWelcomeEmail(ctx context.Context, userID, email, firstName string) error
 	SendEmailVerification(ctx context.Context, userID, email, verificationCode string) error
 	SendPasswordReset(ctx context.Context, userID, email, resetToken string) error
+	// SendAccountInvite invites an imported user to set a password with a reset token
+	SendAccountInvite(ctx context.Context, userID, email, inviteToken string) error
+	SendCoApplicantInvite(ctx context.Context, inviterUserID, email, inviteToken string) error
+	SendNotificationEmail(ctx context.Context, userID, email, subject, message string) error
 
 	// SMS notifications
 	SendPhoneVerification(ctx context.Context, userID, phone, verificationCode string) error
@@ -195,6 +306,53 @@ type AuditService interface {
 	// Security events
 	LogSecurityEvent(ctx context.Context, userID, eventType string, metadata map[string]interface{}) error
 	LogDataAccess(ctx context.Context, userID, accessedBy, dataType string) error
+
+	// Compliance review
+	GetAuditHistory(ctx context.Context, userID string) ([]*AuditEntry, error) // oldest first
+	QueryAuditEvents(ctx context.Context, query *AuditQuery) ([]*AuditEntry, error)
+	VerifyChain(ctx context.Context) (*AuditChainVerification, error)
+}
+
+// CoApplicantRepository defines the interface for co-applicant links
+type CoApplicantRepository interface {
+	CreateLink(ctx context.Context, link *CoApplicantLink) error
+	GetLink(ctx context.Context, linkID string) (*CoApplicantLink, error)
+	GetLinkByTokenHash(ctx context.Context, tokenHash string) (*CoApplicantLink, error)
+	UpdateLink(ctx context.Context, link *CoApplicantLink) error
+
+	// ListLinksForUser returns links where the user is either party, newest first
+	ListLinksForUser(ctx context.Context, userID string) ([]*CoApplicantLink, error)
+}
+
+// UserImportRepository defines the interface for bulk import jobs and the upserts they perform
+type UserImportRepository interface {
+	CreateImport(ctx context.Context, userImport *UserImport) error
+	GetImport(ctx context.Context, importID string) (*UserImport, error)
+	UpdateImport(ctx context.Context, userImport *UserImport) error
+
+	// ClaimImport marks the oldest pending import, or one whose processing stalled for longer than
+	// staleAfter, as processing and returns it; it returns nil when there is nothing to claim
+	ClaimImport(ctx context.Context, staleAfter time.Duration) (*UserImport, error)
+
+	// UpsertImportedUser creates or updates the user and profile keyed on user.ExternalID in one transaction.
+	// user.ID and user.InviteSentAt are set from the stored user.
+	UpsertImportedUser(ctx context.Context, user *User, profile *UserProfile) (ImportOutcome, error)
+}
+
+// AuditRepository defines the interface for the append-only audit event store
+type AuditRepository interface {
+	// AppendEntry links the entry to the current chain head, assigning Sequence, PrevHash and Hash
+	AppendEntry(ctx context.Context, entry *AuditEntry) error
+	QueryEntries(ctx context.Context, query *AuditQuery) ([]*AuditEntry, error)
+
+	// ListEntriesAfter returns entries in chain order starting after the given sequence
+	ListEntriesAfter(ctx context.Context, afterSequence int64, limit int) ([]*AuditEntry, error)
+}
+
+// UserNoteRepository defines the interface for admin annotations on users
+type UserNoteRepository interface {
+	CreateNote(ctx context.Context, note *UserNote) error
+	ListNotes(ctx context.Context, userID string) ([]*UserNote, error)
 }
 
 // CacheService defines the interface for caching operations
@@ -213,6 +371,54 @@ type CacheService interface {
 	CacheKYCStatus(ctx context.Context, userID string, status map[string]KYCStatus, ttl int) error
 	GetCachedKYCStatus(ctx context.Context, userID string) (map[string]KYCStatus, error)
 	InvalidateKYCStatus(ctx context.Context, userID string) error
+
+	// Profile completeness caching
+	CacheCompleteness(ctx context.Context, userID string, completeness *ProfileCompleteness, ttl int) error
+	GetCachedCompleteness(ctx context.Context, userID string) (*ProfileCompleteness, error)
+	InvalidateCompleteness(ctx context.Context, userID string) error
+}
+
+// VerificationCodeStore defines the interface for short-lived email and phone verification codes
+type VerificationCodeStore interface {
+	// SaveCode replaces any outstanding code for the channel and resets its attempt counter
+	SaveCode(ctx context.Context, channel VerificationChannel, userID string, code *VerificationCode) error
+	// CheckCode counts an attempt and compares the code hash in one atomic step, so concurrent
+	// guesses cannot exceed maxAttempts. The code is deleted once verified or locked.
+	CheckCode(ctx context.Context, channel VerificationChannel, userID, codeHash string, maxAttempts int) (VerificationCheckResult, error)
+	DeleteCode(ctx context.Context, channel VerificationChannel, userID string) error
+
+	// AcquireResendCooldown starts the cooldown and returns zero, or returns the time left while a previous send is still cooling down
+	AcquireResendCooldown(ctx context.Context, channel VerificationChannel, userID string, cooldown time.Duration) (time.Duration, error)
+	// ReleaseResendCooldown ends the cooldown early, for a send that failed
+	ReleaseResendCooldown(ctx context.Context, channel VerificationChannel, userID string) error
+
+	// AcquireSendQuota counts a send against every limit unless one is already exhausted, in which case
+	// nothing is counted and the exhausted limit is returned with the time until its window resets
+	AcquireSendQuota(ctx context.Context, channel VerificationChannel, userID, destination string, limits []VerificationRateLimit) (*VerificationRateLimit, time.Duration, error)
+}
+
+// WebhookRepository defines the interface for webhook endpoints and their deliveries
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, endpoint *WebhookEndpoint) error
+	GetEndpoint(ctx context.Context, endpointID string) (*WebhookEndpoint, error)
+	ListEndpoints(ctx context.Context) ([]*WebhookEndpoint, error)
+	ListEndpointsForEvent(ctx context.Context, eventType string) ([]*WebhookEndpoint, error)
+	DisableEndpoint(ctx context.Context, endpointID string) error
+
+	CreateDelivery(ctx context.Context, delivery *WebhookDelivery) error
+	GetDelivery(ctx context.Context, deliveryID string) (*WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, query *WebhookDeliveryQuery) ([]*WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *WebhookDelivery) error
+
+	// ClaimDueDeliveries leases pending deliveries whose next attempt is due, pushing their next
+	// attempt out by lease so concurrent workers skip them
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*WebhookDelivery, error)
+}
+
+// WebhookSender defines the interface for posting signed webhook payloads
+type WebhookSender interface {
+	// Send posts the delivery payload and returns the receiver's HTTP status
+	Send(ctx context.Context, url, secret string, delivery *WebhookDelivery) (int, error)
 }
 
 // UserService defines the main business logic interface
@@ -223,10 +429,12 @@ type UserService interface {
 	GetUserByEmail(ctx context.Context, email string) (*User, error)
 	UpdateUser(ctx context.Context, userID string, request *UpdateUserRequest) (*User, error)
 	DeleteUser(ctx context.Context, userID string) error
+	RestoreUser(ctx context.Context, userID string) (*User, error)
 
 	// Profile management
 	GetProfile(ctx context.Context, userID string) (*UserProfile, error)
 	UpdateProfile(ctx context.Context, userID string, request *UpdateProfileRequest) (*UserProfile, error)
+	GetProfileCompleteness(ctx context.Context, userID string) (*ProfileCompleteness, error)
 
 	// Email and phone verification
 	SendEmailVerification(ctx context.Context, userID string) error
@@ -238,6 +446,10 @@ type UserService interface {
 	InitiateKYC(ctx context.Context, userID string) (*KYCSession, error)
 	GetKYCStatus(ctx context.Context, userID string) (map[string]KYCStatus, error)
 	UpdateKYCStatus(ctx context.Context, userID, verificationType string, status KYCStatus, data map[string]interface{}) error
+	GetIdentityVerificationStatus(ctx context.Context, userID string) (*IdentityVerificationStatus, error)
+
+	// Notifications requested by other services
+	SendUserNotification(ctx context.Context, userID string, request *UserNotificationRequest) error
 
 	// Document management
 	UploadDocument(ctx context.Context, userID string, document *DocumentUpload) (*Document, error)
@@ -247,8 +459,53 @@ type UserService interface {
 	DeleteDocument(ctx context.Context, userID, documentID string) error
 
 	// Search and listing
-	SearchUsers(ctx context.Context, criteria map[string]interface{}, offset, limit int) ([]*User, error)
-	ListUsers(ctx context.Context, offset, limit int) ([]*User, error)
+	ListUsers(ctx context.Context, query *UserListQuery) (*UserPage, error)
+
+	// Duplicate detection and merge
+	GetDuplicates(ctx context.Context, userID string) ([]*DuplicateMatch, error)
+	MergeUsers(ctx context.Context, request *MergeUsersRequest) (*UserMerge, error)
+
+	// Admin account management
+	SuspendUser(ctx context.Context, userID, actorID, reason string) (*User, error)
+	ReactivateUser(ctx context.Context, userID, actorID string) (*User, error)
+	ForcePasswordReset(ctx context.Context, userID, actorID string) error
+	RedeemPasswordReset(ctx context.Context, request *RedeemPasswordResetRequest) error
+	GetKYCHistory(ctx context.Context, userID string) (*KYCHistory, error)
+	AddUserNote(ctx context.Context, userID, authorID, note string) (*UserNote, error)
+	GetUserNotes(ctx context.Context, userID string) ([]*UserNote, error)
+	ExportAuditHistory(ctx context.Context, userID, actorID string) ([]*AuditEntry, error)
+	SetLegalHold(ctx context.Context, userID, actorID string, request *LegalHoldRequest) (*LegalHold, error)
+
+	// Co-applicants
+	InviteCoApplicant(ctx context.Context, userID string, request *InviteCoApplicantRequest) (*CoApplicantLink, error)
+	AcceptCoApplicantInvite(ctx context.Context, userID, token string) (*CoApplicantLink, error)
+	DeclineCoApplicantInvite(ctx context.Context, userID, token string) (*CoApplicantLink, error)
+	RevokeCoApplicantLink(ctx context.Context, userID, linkID string) (*CoApplicantLink, error)
+	ListCoApplicants(ctx context.Context, userID string) ([]*CoApplicantLink, error)
+	UpdateDocumentSharing(ctx context.Context, userID, linkID string, documentTypes []string) (*CoApplicantLink, error)
+	GetSharedDocuments(ctx context.Context, userID, linkID string) ([]*Document, error)
+	GetCoApplicantIdentities(ctx context.Context, userID string) ([]*CoApplicantIdentity, error)
+
+	// Bulk import
+	SubmitUserImport(ctx context.Context, actorID string, format ImportFormat, content io.Reader) (*UserImport, error)
+	GetUserImport(ctx context.Context, importID string) (*UserImport, error)
+
+	// Compliance audit review
+	QueryAuditEvents(ctx context.Context, actorID string, query *AuditQuery) ([]*AuditEntry, error)
+	VerifyAuditChain(ctx context.Context) (*AuditChainVerification, error)
+
+	// Webhooks
+	RegisterWebhook(ctx context.Context, request *RegisterWebhookRequest) (*WebhookEndpoint, error)
+	ListWebhooks(ctx context.Context) ([]*WebhookEndpoint, error)
+	DisableWebhook(ctx context.Context, endpointID string) error
+	ListWebhookDeliveries(ctx context.Context, query *WebhookDeliveryQuery) ([]*WebhookDelivery, error)
+	ReplayWebhookDelivery(ctx context.Context, deliveryID string) (*WebhookDelivery, error)
+
+	// Consent management
+	RecordConsent(ctx context.Context, userID string, request *RecordConsentRequest) (*Consent, error)
+	GetConsents(ctx context.Context, userID string) ([]*Consent, error)
+	GetConsent(ctx context.Context, userID string, consentType ConsentType) (*Consent, error)
+	RevokeConsent(ctx context.Context, userID string, consentType ConsentType) error
 }
 
 // Error code constants for user service
@@ -295,4 +552,57 @@ const (
 	USER_033 = "USER_033" // Rate limit exceeded
 	USER_034 = "USER_034" // Service unavailable
 	USER_035 = "USER_035" // Data integrity error
+
+	// Consent errors
+	USER_036 = "USER_036" // Invalid consent type
+	USER_037 = "USER_037" // Consent not found
+	USER_038 = "USER_038" // Consent text or hash required
+
+	// Tokenization errors
+	USER_039 = "USER_039" // Invalid token type
+	USER_040 = "USER_040" // Token not found
+	USER_041 = "USER_041" // Insufficient token scope
+
+	// Listing errors
+	USER_042 = "USER_042" // Invalid list query parameter
+	USER_043 = "USER_043" // Invalid pagination cursor
+
+	// Lifecycle errors
+	USER_044 = "USER_044" // Restore window expired
+	USER_045 = "USER_045" // Invalid merge request
+
+	// Verification code errors
+	USER_046 = "USER_046" // Invalid verification code
+	USER_047 = "USER_047" // Verification code expired
+	USER_048 = "USER_048" // Too many verification attempts
+	USER_049 = "USER_049" // Verification code resend cooldown
+
+	// Webhook errors
+	USER_050 = "USER_050" // Invalid webhook endpoint
+	USER_051 = "USER_051" // Webhook endpoint not found
+	USER_052 = "USER_052" // Webhook delivery not found
+
+	// Admin errors
+	USER_053 = "USER_053" // Insufficient permissions
+	USER_054 = "USER_054" // Invalid account status transition
+
+	// Import errors
+	USER_055 = "USER_055" // Unsupported import format
+	USER_056 = "USER_056" // Import not found
+
+	// Rate limiting errors
+	USER_057 = "USER_057" // Verification send rate limit exceeded
+
+	// Co-applicant errors
+	USER_058 = "USER_058" // Invalid co-applicant relationship
+	USER_059 = "USER_059" // Co-applicant already linked or invited
+	USER_060 = "USER_060" // Co-applicant invitation not found or expired
+	USER_061 = "USER_061" // Invitation was sent to a different or unverified email
+	USER_062 = "USER_062" // Co-applicant link not found
+
+	// Consent errors, continued
+	USER_063 = "USER_063" // Consent text does not match its hash
+
+	// Password reset errors
+	USER_064 = "USER_064" // Password reset token invalid or expired
 )
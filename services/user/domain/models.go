@@ -1,23 +1,38 @@
 package domain
 
 import (
+	"crypto/sha256"
 	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net"
+	"strings"
 	"time"
 )
 
 // User represents the core user entity
 type User struct {
-	ID            string    `json:"id" db:"id"`
-	Email         string    `json:"email" db:"email"`
-	PasswordHash  string    `json:"-" db:"password_hash"`
-	Phone         string    `json:"phone" db:"phone"`
-	EmailVerified bool      `json:"email_verified" db:"email_verified"`
-	PhoneVerified bool      `json:"phone_verified" db:"phone_verified"`
-	Status        string    `json:"status" db:"status"`
-	CreatedAt     time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	ID            string     `json:"id" db:"id"`
+	Email         string     `json:"email" db:"email"`
+	PasswordHash  string     `json:"-" db:"password_hash"`
+	Phone         string     `json:"phone" db:"phone"`
+	EmailVerified bool       `json:"email_verified" db:"email_verified"`
+	PhoneVerified bool       `json:"phone_verified" db:"phone_verified"`
+	Status        string     `json:"status" db:"status"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+
+	// ExternalID is the borrower's ID in a legacy system, set for imported users
+	ExternalID string `json:"external_id,omitempty" db:"external_id"`
+	// InviteSentAt is when an imported user was invited to set a password
+	InviteSentAt *time.Time `json:"invite_sent_at,omitempty" db:"invite_sent_at"`
+
+	// Normalized contact details used for duplicate detection
+	EmailNormalized string `json:"-" db:"email_normalized"`
+	PhoneNormalized string `json:"-" db:"phone_normalized"`
 }
 
 // UserProfile represents the extended user profile information
@@ -27,13 +42,16 @@ type UserProfile struct {
 	FirstName      string         `json:"first_name" db:"first_name"`
 	LastName       string         `json:"last_name" db:"last_name"`
 	DateOfBirth    time.Time      `json:"date_of_birth" db:"date_of_birth"`
-	SSNEncrypted   string         `json:"-" db:"ssn_encrypted"`
+	SSNToken       string         `json:"ssn_token,omitempty" db:"ssn_token"`
 	Phone          string         `json:"phone" db:"phone"`
 	Address        Address        `json:"address" db:"address"`
 	EmploymentInfo EmploymentInfo `json:"employment_info" db:"employment_info"`
 	FinancialInfo  FinancialInfo  `json:"financial_info" db:"financial_info"`
 	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+
+	// AddressVerification is set on profile updates so callers can act on suggestions; not persisted
+	AddressVerification *AddressVerification `json:"address_verification,omitempty" db:"-"`
 }
 
 // Address represents a physical address
@@ -43,6 +61,20 @@ type Address struct {
 	State   string `json:"state"`
 	ZipCode string `json:"zip_code"`
 	Country string `json:"country"`
+
+	// Populated by address verification
+	ZipPlus4             string `json:"zip_plus4,omitempty"`
+	DeliveryPointBarcode string `json:"delivery_point_barcode,omitempty"`
+	Standardized         bool   `json:"standardized,omitempty"`
+	Undeliverable        bool   `json:"undeliverable,omitempty"`
+}
+
+// AddressVerification reports the outcome of verifying a submitted address
+type AddressVerification struct {
+	Provider     string    `json:"provider"`
+	Deliverable  bool      `json:"deliverable"`
+	DPVMatchCode string    `json:"dpv_match_code,omitempty"`
+	Suggestions  []Address `json:"suggestions,omitempty"`
 }
 
 // Value implements the driver.Valuer interface for database storage
@@ -133,6 +165,7 @@ type KYCVerification struct {
 	Status            KYCStatus              `json:"status" db:"status"`
 	ProviderReference string                 `json:"provider_reference" db:"provider_reference"`
 	VerificationData  map[string]interface{} `json:"verification_data" db:"verification_data"`
+	ExpiresAt         *time.Time             `json:"expires_at,omitempty" db:"expires_at"`
 	CreatedAt         time.Time              `json:"created_at" db:"created_at"`
 	UpdatedAt         time.Time              `json:"updated_at" db:"updated_at"`
 }
@@ -145,8 +178,55 @@ const (
 	KYCStatusVerified     KYCStatus = "verified"
 	KYCStatusFailed       KYCStatus = "failed"
 	KYCStatusManualReview KYCStatus = "manual_review"
+	KYCStatusStale        KYCStatus = "stale" // was verified, but the result has expired
 )
 
+// IsValid checks if the KYC status is one of the known statuses
+func (s KYCStatus) IsValid() bool {
+	switch s {
+	case KYCStatusPending, KYCStatusVerified, KYCStatusFailed, KYCStatusManualReview, KYCStatusStale:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsExpired reports whether a verified result has passed its expiry date
+func (v *KYCVerification) IsExpired(now time.Time) bool {
+	return v.Status == KYCStatusVerified && v.ExpiresAt != nil && !now.Before(*v.ExpiresAt)
+}
+
+// KYCExpiryPolicy sets how long a verified KYC result stays valid. A zero validity never expires.
+type KYCExpiryPolicy struct {
+	DefaultValidity  time.Duration
+	ProviderValidity map[string]time.Duration
+}
+
+// ValidityFor returns the validity period for results from the given provider
+func (p KYCExpiryPolicy) ValidityFor(provider string) time.Duration {
+	if validity, ok := p.ProviderValidity[provider]; ok {
+		return validity
+	}
+	return p.DefaultValidity
+}
+
+// IdentityVerificationStatus is a user's identity verification state as seen by loan applications
+type IdentityVerificationStatus struct {
+	UserID    string     `json:"user_id"`
+	Status    KYCStatus  `json:"status,omitempty"`
+	Provider  string     `json:"provider,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Stale     bool       `json:"stale"`
+}
+
+// UserNotificationRequest is a notification another service asks to have sent to a user
+type UserNotificationRequest struct {
+	Title   string                 `json:"title" binding:"required,max=200"`
+	Message string                 `json:"message" binding:"required,max=2000"`
+	Data    map[string]interface{} `json:"data,omitempty"`
+	Email   bool                   `json:"email,omitempty"` // also email the notification to the user's address
+}
+
 // Document represents a user-uploaded document
 type Document struct {
 	ID            string    `json:"id" db:"id"`
@@ -158,6 +238,9 @@ type Document struct {
 	EncryptionKey string    `json:"-" db:"encryption_key"`
 	UploadIP      string    `json:"upload_ip" db:"upload_ip"`
 	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+
+	// PurgedAt is set when retention removed the file; the row is kept as a tombstone
+	PurgedAt *time.Time `json:"purged_at,omitempty" db:"purged_at"`
 }
 
 // DocumentType constants
@@ -169,8 +252,24 @@ const (
 	DocumentTypeUtilityBill    = "utility_bill"
 	DocumentTypeW2             = "w2"
 	DocumentType1099           = "1099"
+	DocumentTypeLoanAgreement  = "loan_agreement" // signed agreements stored by the loan service
+
+	// Disclosures generated and stored by the loan service
+	DocumentTypeTILADisclosure      = "tila_disclosure"
+	DocumentTypeAdverseActionNotice = "adverse_action_notice"
 )
 
+// IsLoanDocumentType reports whether documents of the type are produced by the loan service for
+// each loan, so a user may hold several of them
+func IsLoanDocumentType(documentType string) bool {
+	switch documentType {
+	case DocumentTypeLoanAgreement, DocumentTypeTILADisclosure, DocumentTypeAdverseActionNotice:
+		return true
+	default:
+		return false
+	}
+}
+
 // CreateUserRequest represents a request to create a new user
 type CreateUserRequest struct {
 	Email     string `json:"email" validate:"required,email"`
@@ -178,6 +277,8 @@ type CreateUserRequest struct {
 	Phone     string `json:"phone" validate:"required,phone"`
 	FirstName string `json:"first_name" validate:"required,min=1,max=100"`
 	LastName  string `json:"last_name" validate:"required,min=1,max=100"`
+
+	DateOfBirth *time.Time `json:"date_of_birth,omitempty"`
 }
 
 // UpdateUserRequest represents a request to update user information
@@ -190,6 +291,7 @@ type UpdateUserRequest struct {
 // UpdateProfileRequest represents a request to update user profile
 type UpdateProfileRequest struct {
 	DateOfBirth    *time.Time      `json:"date_of_birth,omitempty"`
+	SSN            *string         `json:"ssn,omitempty"` // tokenized on ingestion, never persisted in the clear
 	Phone          *string         `json:"phone,omitempty" validate:"omitempty,phone"`
 	Address        *Address        `json:"address,omitempty"`
 	EmploymentInfo *EmploymentInfo `json:"employment_info,omitempty"`
@@ -225,6 +327,1004 @@ type DocumentStream struct {
 	Size        int64  `json:"size"`
 }
 
+// ConsentType identifies the disclosure a user has agreed to
+type ConsentType string
+
+// ConsentType constants
+const (
+	ConsentTypeCreditPull     ConsentType = "credit_pull"
+	ConsentTypeESign          ConsentType = "e_sign"
+	ConsentTypeCommunications ConsentType = "communications"
+	ConsentTypeDataSharing    ConsentType = "data_sharing"
+)
+
+// IsValid checks if the consent type is one of the supported types
+func (t ConsentType) IsValid() bool {
+	switch t {
+	case ConsentTypeCreditPull, ConsentTypeESign, ConsentTypeCommunications, ConsentTypeDataSharing:
+		return true
+	}
+	return false
+}
+
+// Consent represents a recorded user consent to a specific disclosure version
+type Consent struct {
+	ID          string      `json:"id" db:"id"`
+	UserID      string      `json:"user_id" db:"user_id"`
+	ConsentType ConsentType `json:"consent_type" db:"consent_type"`
+	Version     string      `json:"version" db:"version"`
+	TextHash    string      `json:"text_hash" db:"text_hash"`
+	IPAddress   string      `json:"ip_address" db:"ip_address"`
+	UserAgent   string      `json:"user_agent,omitempty" db:"user_agent"`
+	ConsentedAt time.Time   `json:"consented_at" db:"consented_at"`
+	RevokedAt   *time.Time  `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
+}
+
+// IsActive checks if the consent has not been revoked
+func (c *Consent) IsActive() bool {
+	return c.RevokedAt == nil
+}
+
+// RecordConsentRequest represents a request to record a user consent.
+// Either the disclosure text or its SHA-256 hash must be supplied.
+type RecordConsentRequest struct {
+	ConsentType ConsentType `json:"consent_type" validate:"required"`
+	Version     string      `json:"version" validate:"required,max=50"`
+	ConsentText string      `json:"consent_text,omitempty"`
+	TextHash    string      `json:"text_hash,omitempty" validate:"omitempty,len=64,hexadecimal"`
+	IPAddress   string      `json:"-"`
+	UserAgent   string      `json:"-"`
+}
+
+// TokenType identifies the kind of sensitive value held in the token vault
+type TokenType string
+
+// TokenType constants
+const (
+	TokenTypeSSN               TokenType = "ssn"
+	TokenTypeTaxID             TokenType = "tax_id"
+	TokenTypeBankAccountNumber TokenType = "bank_account_number"
+	TokenTypeRoutingNumber     TokenType = "routing_number"
+)
+
+// IsValid checks if the token type is one of the supported types
+func (t TokenType) IsValid() bool {
+	switch t {
+	case TokenTypeSSN, TokenTypeTaxID, TokenTypeBankAccountNumber, TokenTypeRoutingNumber:
+		return true
+	default:
+		return false
+	}
+}
+
+// PIIToken maps an opaque token to an encrypted sensitive value in the vault
+type PIIToken struct {
+	ID             string    `json:"id" db:"id"`
+	Token          string    `json:"token" db:"token"`
+	TokenType      TokenType `json:"token_type" db:"token_type"`
+	ValueHash      string    `json:"-" db:"value_hash"`
+	ValueEncrypted string    `json:"-" db:"value_encrypted"`
+	Last4          string    `json:"last4" db:"last4"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TokenizeRequest represents a request to exchange a sensitive value for a token
+type TokenizeRequest struct {
+	TokenType TokenType `json:"token_type" validate:"required"`
+	Value     string    `json:"value" validate:"required"`
+}
+
+// DetokenizeRequest represents a request to resolve a token back to its value
+type DetokenizeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// UserSortField identifies a column users can be ordered by when listing
+type UserSortField string
+
+// UserSortField constants
+const (
+	UserSortCreatedAt UserSortField = "created_at"
+	UserSortUpdatedAt UserSortField = "updated_at"
+	UserSortEmail     UserSortField = "email"
+)
+
+// IsValid checks if the sort field is supported
+func (f UserSortField) IsValid() bool {
+	switch f {
+	case UserSortCreatedAt, UserSortUpdatedAt, UserSortEmail:
+		return true
+	default:
+		return false
+	}
+}
+
+// SortOrder represents the direction of a listing
+type SortOrder string
+
+// SortOrder constants
+const (
+	SortOrderAsc  SortOrder = "asc"
+	SortOrderDesc SortOrder = "desc"
+)
+
+// Page size limits for user listing
+const (
+	DefaultUserPageSize = 50
+	MaxUserPageSize     = 100
+)
+
+// UserListQuery describes the filters, ordering and page window for listing users
+type UserListQuery struct {
+	Email         string        `json:"email,omitempty" form:"email"`
+	EmailDomain   string        `json:"email_domain,omitempty" form:"email_domain"`
+	Phone         string        `json:"phone,omitempty" form:"phone"`
+	Status        string        `json:"status,omitempty" form:"status"`
+	EmailVerified *bool         `json:"email_verified,omitempty" form:"email_verified"`
+	PhoneVerified *bool         `json:"phone_verified,omitempty" form:"phone_verified"`
+	KYCStatus     KYCStatus     `json:"kyc_status,omitempty" form:"kyc_status"`
+	CreatedFrom   *time.Time    `json:"created_from,omitempty" form:"created_from"`
+	CreatedTo     *time.Time    `json:"created_to,omitempty" form:"created_to"`
+	SortBy        UserSortField `json:"sort_by,omitempty" form:"sort_by"`
+	SortOrder     SortOrder     `json:"sort_order,omitempty" form:"sort_order"`
+	Cursor        string        `json:"cursor,omitempty" form:"cursor"`
+	Limit         int           `json:"limit,omitempty" form:"limit"`
+
+	// After is the decoded cursor; set by the service, never bound from the request
+	After *UserCursor `json:"-" form:"-"`
+}
+
+// ApplyDefaults fills in the default ordering and clamps the page size
+func (q *UserListQuery) ApplyDefaults() {
+	if q.SortBy == "" {
+		q.SortBy = UserSortCreatedAt
+	}
+	if q.SortOrder == "" {
+		q.SortOrder = SortOrderDesc
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultUserPageSize
+	}
+	if q.Limit > MaxUserPageSize {
+		q.Limit = MaxUserPageSize
+	}
+}
+
+// UserCursor is the keyset position of the last user on a page
+type UserCursor struct {
+	SortBy UserSortField `json:"s"`
+	Value  string        `json:"v"`
+	ID     string        `json:"id"`
+}
+
+// NewUserCursor builds the cursor pointing just past the given user for a sort field
+func NewUserCursor(user *User, sortBy UserSortField) *UserCursor {
+	cursor := &UserCursor{SortBy: sortBy, ID: user.ID}
+	switch sortBy {
+	case UserSortUpdatedAt:
+		cursor.Value = user.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case UserSortEmail:
+		cursor.Value = user.Email
+	default:
+		cursor.Value = user.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return cursor
+}
+
+// Encode returns the opaque string form handed to clients
+func (c *UserCursor) Encode() string {
+	bytes, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+// DecodeUserCursor parses a cursor previously produced by Encode
+func DecodeUserCursor(encoded string) (*UserCursor, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor: %w", err)
+	}
+
+	var cursor UserCursor
+	if err := json.Unmarshal(bytes, &cursor); err != nil {
+		return nil, fmt.Errorf("malformed cursor: %w", err)
+	}
+	if !cursor.SortBy.IsValid() || cursor.ID == "" {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	if cursor.SortBy != UserSortEmail {
+		if _, err := time.Parse(time.RFC3339Nano, cursor.Value); err != nil {
+			return nil, fmt.Errorf("malformed cursor: %w", err)
+		}
+	}
+
+	return &cursor, nil
+}
+
+// UserPage is one page of a user listing along with pagination metadata
+type UserPage struct {
+	Users      []*User `json:"users"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+	HasMore    bool    `json:"has_more"`
+	TotalCount int     `json:"total_count"`
+	Limit      int     `json:"limit"`
+}
+
+// DuplicateMatchStatus represents the review state of a potential duplicate
+type DuplicateMatchStatus string
+
+// DuplicateMatchStatus constants
+const (
+	DuplicateMatchPending   DuplicateMatchStatus = "pending"
+	DuplicateMatchMerged    DuplicateMatchStatus = "merged"
+	DuplicateMatchDismissed DuplicateMatchStatus = "dismissed"
+)
+
+// Duplicate match reasons
+const (
+	MatchReasonEmail       = "email"
+	MatchReasonPhone       = "phone"
+	MatchReasonName        = "name"
+	MatchReasonDateOfBirth = "date_of_birth"
+	MatchReasonSSNLast4    = "ssn_last4"
+)
+
+// DuplicateProbe carries the identifying attributes of a user being checked for duplicates
+type DuplicateProbe struct {
+	UserID          string
+	EmailNormalized string
+	PhoneNormalized string
+	FirstName       string
+	LastName        string
+	DateOfBirth     *time.Time
+	SSNLast4        string
+}
+
+// DuplicateCandidate is an existing user that shares at least one identifying attribute with a probe
+type DuplicateCandidate struct {
+	UserID          string     `db:"user_id"`
+	EmailNormalized string     `db:"email_normalized"`
+	PhoneNormalized string     `db:"phone_normalized"`
+	FirstName       string     `db:"first_name"`
+	LastName        string     `db:"last_name"`
+	DateOfBirth     *time.Time `db:"date_of_birth"`
+	SSNLast4        string     `db:"ssn_last4"`
+}
+
+// MatchReasons lists the attributes that contributed to a duplicate score
+type MatchReasons []string
+
+// Value implements the driver.Valuer interface for database storage
+func (r MatchReasons) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (r *MatchReasons) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into MatchReasons", value)
+	}
+
+	return json.Unmarshal(bytes, r)
+}
+
+// DuplicateMatch records a scored potential duplicate for admin review
+type DuplicateMatch struct {
+	ID              string               `json:"id" db:"id"`
+	UserID          string               `json:"user_id" db:"user_id"`
+	CandidateUserID string               `json:"candidate_user_id" db:"candidate_user_id"`
+	Score           float64              `json:"score" db:"score"`
+	Reasons         MatchReasons         `json:"reasons" db:"reasons"`
+	Status          DuplicateMatchStatus `json:"status" db:"status"`
+	CreatedAt       time.Time            `json:"created_at" db:"created_at"`
+	ReviewedAt      *time.Time           `json:"reviewed_at,omitempty" db:"reviewed_at"`
+}
+
+// MergeUsersRequest represents an admin request to fold a duplicate user into a surviving user
+type MergeUsersRequest struct {
+	SurvivorID  string `json:"survivor_id" validate:"required"`
+	DuplicateID string `json:"duplicate_id" validate:"required"`
+	Reason      string `json:"reason" validate:"required,max=500"`
+	PerformedBy string `json:"-"` // the authenticated admin, never taken from the request body
+}
+
+// UserMerge is the audit record of a completed merge
+type UserMerge struct {
+	ID                    string    `json:"id" db:"id"`
+	SurvivorID            string    `json:"survivor_id" db:"survivor_id"`
+	MergedID              string    `json:"merged_id" db:"merged_id"`
+	PerformedBy           string    `json:"performed_by" db:"performed_by"`
+	Reason                string    `json:"reason" db:"reason"`
+	DocumentsMoved        int       `json:"documents_moved" db:"documents_moved"`
+	KYCRecordsMoved       int       `json:"kyc_records_moved" db:"kyc_records_moved"`
+	ConsentsMoved         int       `json:"consents_moved" db:"consents_moved"`
+	LoanApplicationsMoved int       `json:"loan_applications_moved" db:"loan_applications_moved"`
+	CreatedAt             time.Time `json:"created_at" db:"created_at"`
+}
+
+// NormalizeEmail canonicalizes an email for duplicate matching: lowercased, plus-tags dropped,
+// and dots removed from Gmail local parts since Gmail ignores them
+func NormalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+
+	local, domainPart := email[:at], email[at+1:]
+	if plus := strings.Index(local, "+"); plus >= 0 {
+		local = local[:plus]
+	}
+	if domainPart == "googlemail.com" {
+		domainPart = "gmail.com"
+	}
+	if domainPart == "gmail.com" {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+
+	return local + "@" + domainPart
+}
+
+// NormalizePhone reduces a phone number to its digits, dropping the US country code
+func NormalizePhone(phone string) string {
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+
+	normalized := digits.String()
+	if len(normalized) == 11 && strings.HasPrefix(normalized, "1") {
+		normalized = normalized[1:]
+	}
+	return normalized
+}
+
+// Profile completeness items
+const (
+	CompletenessItemPersonalInfo     = "personal_info"
+	CompletenessItemDateOfBirth      = "date_of_birth"
+	CompletenessItemSSN              = "ssn"
+	CompletenessItemAddress          = "address"
+	CompletenessItemEmployer         = "employer"
+	CompletenessItemIdentityDocument = "identity_document"
+	CompletenessItemIncomeDocument   = "income_document"
+)
+
+// CompletenessItem reports whether a single weighted part of the profile is filled in
+type CompletenessItem struct {
+	Item     string `json:"item"`
+	Weight   int    `json:"weight"`
+	Complete bool   `json:"complete"`
+}
+
+// ProfileCompleteness scores how ready a user's profile is for loan submission
+type ProfileCompleteness struct {
+	UserID       string             `json:"user_id"`
+	Score        int                `json:"score"` // 0-100, sum of the weights of complete items
+	Complete     bool               `json:"complete"`
+	Items        []CompletenessItem `json:"items"`
+	MissingItems []string           `json:"missing_items"`
+	CalculatedAt time.Time          `json:"calculated_at"`
+}
+
+// VerificationChannel identifies where a verification code was delivered
+type VerificationChannel string
+
+// VerificationChannel constants
+const (
+	VerificationChannelEmail VerificationChannel = "email"
+	VerificationChannelPhone VerificationChannel = "phone"
+)
+
+// VerificationCode is an outstanding one-time code; only its hash is ever stored
+type VerificationCode struct {
+	CodeHash  string    `json:"code_hash"`
+	Attempts  int       `json:"attempts"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// VerificationCheckResult is the outcome of checking a submitted code against the outstanding one
+type VerificationCheckResult string
+
+const (
+	VerificationCodeMissing  VerificationCheckResult = "missing"  // no code outstanding, or it expired
+	VerificationCodeMismatch VerificationCheckResult = "mismatch" // wrong code, attempts left
+	VerificationCodeLocked   VerificationCheckResult = "locked"   // attempts used up; the code is discarded
+	VerificationCodeVerified VerificationCheckResult = "verified" // right code; the code is consumed
+)
+
+// VerificationPolicy bounds how verification codes are issued and checked
+type VerificationPolicy struct {
+	CodeTTL        time.Duration
+	MaxAttempts    int
+	ResendCooldown time.Duration
+	HashSecret     string
+	RateLimits     []VerificationRateLimit
+}
+
+// VerificationRateScope selects what a verification send limit is counted against
+type VerificationRateScope string
+
+// VerificationRateScope constants
+const (
+	VerificationRateScopeUser        VerificationRateScope = "user"
+	VerificationRateScopeDestination VerificationRateScope = "destination" // the email address or phone number
+)
+
+// VerificationRateLimit allows at most Limit verification sends per Window for each user or destination
+type VerificationRateLimit struct {
+	Scope  VerificationRateScope
+	Limit  int
+	Window time.Duration
+}
+
+// Webhook event types
+const (
+	WebhookEventUserCreated      = "user.created"
+	WebhookEventKYCVerified      = "kyc.verified"
+	WebhookEventDocumentUploaded = "document.uploaded"
+)
+
+// IsValidWebhookEvent reports whether eventType is one the service emits
+func IsValidWebhookEvent(eventType string) bool {
+	switch eventType {
+	case WebhookEventUserCreated, WebhookEventKYCVerified, WebhookEventDocumentUploaded:
+		return true
+	}
+	return false
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which net.IP does not treat as private
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPublicWebhookAddress reports whether a webhook may be delivered to ip. Loopback, private,
+// link-local, multicast and unspecified addresses are refused so endpoints cannot reach internal services.
+func IsPublicWebhookAddress(ip net.IP) bool {
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	if ip4 := ip.To4(); ip4 != nil && (ip4[0] == 0 || sharedAddressSpace.Contains(ip4)) {
+		return false
+	}
+	return true
+}
+
+// WebhookEventTypes lists the events an endpoint subscribes to
+type WebhookEventTypes []string
+
+// Value implements the driver.Valuer interface for database storage
+func (e WebhookEventTypes) Value() (driver.Value, error) {
+	return json.Marshal(e)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (e *WebhookEventTypes) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into WebhookEventTypes", value)
+	}
+
+	return json.Unmarshal(bytes, e)
+}
+
+// Contains reports whether the endpoint subscribes to eventType
+func (e WebhookEventTypes) Contains(eventType string) bool {
+	for _, t := range e {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEndpoint is a registered receiver of user lifecycle events
+type WebhookEndpoint struct {
+	ID              string            `json:"id" db:"id"`
+	URL             string            `json:"url" db:"url"`
+	Description     string            `json:"description,omitempty" db:"description"`
+	Events          WebhookEventTypes `json:"events" db:"events"`
+	Active          bool              `json:"active" db:"active"`
+	SecretEncrypted string            `json:"-" db:"secret_encrypted"`
+	CreatedAt       time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at" db:"updated_at"`
+
+	// Secret is only returned once, when the endpoint is registered
+	Secret string `json:"secret,omitempty" db:"-"`
+}
+
+// RegisterWebhookRequest represents a request to register a webhook endpoint
+type RegisterWebhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Events      []string `json:"events" binding:"required,min=1"`
+	Description string   `json:"description"`
+}
+
+// WebhookDeliveryStatus represents the state of a single event delivery
+type WebhookDeliveryStatus string
+
+// WebhookDeliveryStatus constants
+const (
+	WebhookDeliveryPending      WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded    WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryDeadLettered WebhookDeliveryStatus = "dead_lettered"
+)
+
+// WebhookEvent is the signed envelope posted to endpoints
+type WebhookEvent struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookPayload is the raw JSON body of a delivery
+type WebhookPayload json.RawMessage
+
+// Value implements the driver.Valuer interface for database storage
+func (p WebhookPayload) Value() (driver.Value, error) {
+	return string(p), nil
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (p *WebhookPayload) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*p = nil
+	case []byte:
+		*p = append(WebhookPayload(nil), v...)
+	case string:
+		*p = WebhookPayload(v)
+	default:
+		return fmt.Errorf("cannot scan %T into WebhookPayload", value)
+	}
+	return nil
+}
+
+// MarshalJSON embeds the payload as JSON rather than a base64 string
+func (p WebhookPayload) MarshalJSON() ([]byte, error) {
+	if len(p) == 0 {
+		return []byte("null"), nil
+	}
+	return p, nil
+}
+
+// WebhookDelivery tracks one event being delivered to one endpoint
+type WebhookDelivery struct {
+	ID             string                `json:"id" db:"id"`
+	EndpointID     string                `json:"endpoint_id" db:"endpoint_id"`
+	EventID        string                `json:"event_id" db:"event_id"`
+	EventType      string                `json:"event_type" db:"event_type"`
+	Payload        WebhookPayload        `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError      string                `json:"last_error,omitempty" db:"last_error"`
+	ResponseStatus int                   `json:"response_status,omitempty" db:"response_status"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookDeliveryQuery filters the admin delivery listing
+type WebhookDeliveryQuery struct {
+	EndpointID string                `form:"endpoint_id"`
+	Status     WebhookDeliveryStatus `form:"status"`
+	Limit      int                   `form:"limit"`
+}
+
+// User account status values
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+)
+
+// SuspendUserRequest represents an admin request to suspend an account
+type SuspendUserRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// AnnotateUserRequest represents an admin note to attach to a user
+type AnnotateUserRequest struct {
+	Note string `json:"note" binding:"required"`
+}
+
+// UserNote is an internal admin annotation on a user; it is never shown to the user
+type UserNote struct {
+	ID        string    `json:"id" db:"id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	AuthorID  string    `json:"author_id" db:"author_id"`
+	Note      string    `json:"note" db:"note"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// AuditEntry is a single recorded audit event. Entries form a hash chain: each entry's Hash covers
+// its own fields and the previous entry's hash, so altering or removing any entry breaks every later link.
+type AuditEntry struct {
+	ID        string        `json:"id" db:"id"`
+	Sequence  int64         `json:"sequence" db:"sequence"`
+	UserID    string        `json:"user_id" db:"user_id"`
+	ActorID   string        `json:"actor_id,omitempty" db:"actor_id"`
+	EventType string        `json:"event_type" db:"event_type"`
+	Category  string        `json:"category" db:"category"`
+	Metadata  AuditMetadata `json:"metadata,omitempty" db:"metadata"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+	PrevHash  string        `json:"prev_hash" db:"prev_hash"`
+	Hash      string        `json:"hash" db:"hash"`
+}
+
+// ComputeHash returns the chain hash of the entry over PrevHash and its content fields.
+// CreatedAt must already be truncated to the storage precision (microseconds).
+func (e *AuditEntry) ComputeHash() string {
+	fields, _ := json.Marshal([]string{
+		e.PrevHash,
+		e.ID,
+		e.UserID,
+		e.ActorID,
+		e.EventType,
+		e.Category,
+		string(e.Metadata),
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	sum := sha256.Sum256(fields)
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditMetadata is the event's JSON metadata, kept byte-for-byte as hashed
+type AuditMetadata json.RawMessage
+
+// Value implements the driver.Valuer interface for database storage
+func (m AuditMetadata) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return string(m), nil
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (m *AuditMetadata) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+	case []byte:
+		*m = append(AuditMetadata(nil), v...)
+	case string:
+		*m = AuditMetadata(v)
+	default:
+		return fmt.Errorf("cannot scan %T into AuditMetadata", value)
+	}
+	return nil
+}
+
+// MarshalJSON embeds the metadata as JSON rather than a base64 string
+func (m AuditMetadata) MarshalJSON() ([]byte, error) {
+	if len(m) == 0 {
+		return []byte("null"), nil
+	}
+	return m, nil
+}
+
+// AuditQuery filters audit events for compliance review; zero values are not filtered on
+type AuditQuery struct {
+	UserID    string    `form:"user_id"`
+	ActorID   string    `form:"actor_id"`
+	EventType string    `form:"event_type"`
+	From      time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit     int       `form:"limit"`
+	Offset    int       `form:"offset"`
+}
+
+// AuditChainVerification reports the result of re-walking the audit hash chain
+type AuditChainVerification struct {
+	Valid          bool      `json:"valid"`
+	EntriesChecked int       `json:"entries_checked"`
+	BrokenAt       *int64    `json:"broken_at,omitempty"` // sequence of the first entry whose link does not verify
+	VerifiedAt     time.Time `json:"verified_at"`
+}
+
+// Audit event categories
+const (
+	AuditCategoryProfile  = "profile"
+	AuditCategorySecurity = "security"
+	AuditCategoryDocument = "document"
+	AuditCategoryKYC      = "kyc"
+)
+
+// KYCHistory combines a user's KYC verification records with the status changes recorded against them
+type KYCHistory struct {
+	UserID        string             `json:"user_id"`
+	Verifications []*KYCVerification `json:"verifications"`
+	Events        []*AuditEntry      `json:"events"`
+}
+
+// RetentionAnchor is the event a document's retention period is counted from
+type RetentionAnchor string
+
+// RetentionAnchor constants
+const (
+	RetentionAnchorUpload      RetentionAnchor = "upload"
+	RetentionAnchorLoanClosure RetentionAnchor = "loan_closure"
+)
+
+// RetentionRule keeps documents of one type for RetainMonths after the anchor event.
+// Loan-closure rules retain documents while the user has an open application, and fall back
+// to the upload date for users who never had a loan.
+type RetentionRule struct {
+	DocumentType string          `json:"document_type"`
+	Anchor       RetentionAnchor `json:"anchor"`
+	RetainMonths int             `json:"retain_months"`
+}
+
+// LoanActivity summarizes a user's loans as reported by the loan service
+type LoanActivity struct {
+	UserID           string     `json:"user_id"`
+	OpenApplications int        `json:"open_applications"`
+	LastClosedAt     *time.Time `json:"last_closed_at,omitempty"`
+}
+
+// LegalHoldRequest places or lifts a legal hold, which exempts a user's documents from retention purges
+type LegalHoldRequest struct {
+	Hold   *bool  `json:"hold" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+// RedeemPasswordResetRequest sets a new password with the token sent by a forced password reset
+type RedeemPasswordResetRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// LegalHold is a user's current legal hold state
+type LegalHold struct {
+	UserID string     `json:"user_id" db:"id"`
+	Hold   bool       `json:"hold" db:"legal_hold"`
+	Reason string     `json:"reason,omitempty" db:"legal_hold_reason"`
+	SetBy  string     `json:"set_by,omitempty" db:"legal_hold_set_by"`
+	SetAt  *time.Time `json:"set_at,omitempty" db:"legal_hold_set_at"`
+}
+
+// CoApplicantRelationship describes how a co-applicant is related to the primary applicant
+type CoApplicantRelationship string
+
+// CoApplicantRelationship constants
+const (
+	CoApplicantRelationshipSpouse   CoApplicantRelationship = "spouse"
+	CoApplicantRelationshipCoSigner CoApplicantRelationship = "co_signer"
+)
+
+// IsValid checks if the relationship is supported
+func (r CoApplicantRelationship) IsValid() bool {
+	return r == CoApplicantRelationshipSpouse || r == CoApplicantRelationshipCoSigner
+}
+
+// CoApplicantStatus represents the state of a co-applicant link
+type CoApplicantStatus string
+
+// CoApplicantStatus constants
+const (
+	CoApplicantStatusPending  CoApplicantStatus = "pending"
+	CoApplicantStatusAccepted CoApplicantStatus = "accepted"
+	CoApplicantStatusDeclined CoApplicantStatus = "declined"
+	CoApplicantStatusRevoked  CoApplicantStatus = "revoked"
+)
+
+// DocumentTypeList is a set of document types stored as a JSON array
+type DocumentTypeList []string
+
+// Value implements the driver.Valuer interface for database storage
+func (l DocumentTypeList) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (l *DocumentTypeList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into DocumentTypeList", value)
+	}
+
+	return json.Unmarshal(bytes, l)
+}
+
+// Contains reports whether the list includes the document type
+func (l DocumentTypeList) Contains(documentType string) bool {
+	for _, t := range l {
+		if t == documentType {
+			return true
+		}
+	}
+	return false
+}
+
+// CoApplicantLink joins a primary applicant to a spouse or co-signer invited by email. Each side
+// chooses which of their own document types the other side may view; nothing is shared by default.
+type CoApplicantLink struct {
+	ID                string                  `json:"id" db:"id"`
+	PrimaryUserID     string                  `json:"primary_user_id" db:"primary_user_id"`
+	CoApplicantUserID string                  `json:"co_applicant_user_id,omitempty" db:"co_applicant_user_id"` // set on acceptance
+	InviteEmail       string                  `json:"invite_email" db:"invite_email"`
+	Relationship      CoApplicantRelationship `json:"relationship" db:"relationship"`
+	Status            CoApplicantStatus       `json:"status" db:"status"`
+	InviteTokenHash   string                  `json:"-" db:"invite_token_hash"`
+	InviteExpiresAt   time.Time               `json:"invite_expires_at" db:"invite_expires_at"`
+
+	PrimarySharedDocumentTypes     DocumentTypeList `json:"primary_shared_document_types" db:"primary_shared_document_types"`
+	CoApplicantSharedDocumentTypes DocumentTypeList `json:"co_applicant_shared_document_types" db:"co_applicant_shared_document_types"`
+
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	RespondedAt *time.Time `json:"responded_at,omitempty" db:"responded_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Involves reports whether the user is either party to the link
+func (l *CoApplicantLink) Involves(userID string) bool {
+	return userID != "" && (l.PrimaryUserID == userID || l.CoApplicantUserID == userID)
+}
+
+// Counterpart returns the other party's user ID, which is empty for the primary until the invite is accepted
+func (l *CoApplicantLink) Counterpart(userID string) string {
+	if l.PrimaryUserID == userID {
+		return l.CoApplicantUserID
+	}
+	return l.PrimaryUserID
+}
+
+// InviteCoApplicantRequest represents a request to invite a co-applicant by email
+type InviteCoApplicantRequest struct {
+	Email        string                  `json:"email" binding:"required,email"`
+	Relationship CoApplicantRelationship `json:"relationship" binding:"required"`
+}
+
+// RespondCoApplicantInviteRequest carries the token from the invitation email
+type RespondCoApplicantInviteRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// UpdateDocumentSharingRequest replaces the document types the caller shares with the other party
+type UpdateDocumentSharingRequest struct {
+	DocumentTypes []string `json:"document_types" binding:"required"`
+}
+
+// CoApplicantIdentity is a linked party as seen by loan applications
+type CoApplicantIdentity struct {
+	LinkID        string                  `json:"link_id"`
+	UserID        string                  `json:"user_id"`
+	Role          string                  `json:"role"` // "primary" or "co_applicant", relative to the queried user
+	Relationship  CoApplicantRelationship `json:"relationship"`
+	EmailVerified bool                    `json:"email_verified"`
+	KYCVerified   bool                    `json:"kyc_verified"`
+}
+
+// ImportFormat is the encoding of a bulk user import file
+type ImportFormat string
+
+// ImportFormat constants
+const (
+	ImportFormatCSV    ImportFormat = "csv"
+	ImportFormatNDJSON ImportFormat = "ndjson"
+)
+
+// IsValid checks if the import format is supported
+func (f ImportFormat) IsValid() bool {
+	return f == ImportFormatCSV || f == ImportFormatNDJSON
+}
+
+// ImportStatus represents the processing state of a bulk user import
+type ImportStatus string
+
+// ImportStatus constants
+const (
+	ImportStatusPending    ImportStatus = "pending"
+	ImportStatusProcessing ImportStatus = "processing"
+	ImportStatusCompleted  ImportStatus = "completed"
+	ImportStatusFailed     ImportStatus = "failed"
+)
+
+// ImportedPasswordHash is stored for imported borrowers; it never matches a bcrypt comparison,
+// so they must set a password through the reset flow before their first login
+const ImportedPasswordHash = "!imported"
+
+// UserImportRow is one borrower from a legacy system export. CSV headers and NDJSON keys use the json names.
+type UserImportRow struct {
+	ExternalID  string `json:"external_id"`
+	Email       string `json:"email"`
+	Phone       string `json:"phone"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	DateOfBirth string `json:"date_of_birth"` // YYYY-MM-DD
+}
+
+// ImportOutcome is the result of upserting one imported borrower
+type ImportOutcome string
+
+// ImportOutcome constants
+const (
+	ImportOutcomeCreated       ImportOutcome = "created"
+	ImportOutcomeUpdated       ImportOutcome = "updated"
+	ImportOutcomeEmailConflict ImportOutcome = "email_conflict" // email belongs to a user with a different external_id
+)
+
+// ImportRowError reports why a single row was rejected
+type ImportRowError struct {
+	Row        int    `json:"row"` // 1-based data row, excluding any CSV header
+	ExternalID string `json:"external_id,omitempty"`
+	Field      string `json:"field,omitempty"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+// ImportRowErrors is the error report stored with an import
+type ImportRowErrors []ImportRowError
+
+// Value implements the driver.Valuer interface for database storage
+func (e ImportRowErrors) Value() (driver.Value, error) {
+	if e == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(e)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (e *ImportRowErrors) Scan(value interface{}) error {
+	if value == nil {
+		*e = nil
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into ImportRowErrors", value)
+	}
+
+	return json.Unmarshal(bytes, e)
+}
+
+// UserImport tracks an asynchronous bulk user import
+type UserImport struct {
+	ID           string          `json:"id" db:"id"`
+	Status       ImportStatus    `json:"status" db:"status"`
+	Format       ImportFormat    `json:"format" db:"format"`
+	StorageKey   string          `json:"-" db:"storage_key"`
+	SubmittedBy  string          `json:"submitted_by" db:"submitted_by"`
+	TotalRows    int             `json:"total_rows" db:"total_rows"`
+	CreatedRows  int             `json:"created_rows" db:"created_rows"`
+	UpdatedRows  int             `json:"updated_rows" db:"updated_rows"`
+	FailedRows   int             `json:"failed_rows" db:"failed_rows"`
+	Errors       ImportRowErrors `json:"errors" db:"errors"` // capped; FailedRows holds the full count
+	ErrorMessage string          `json:"error_message,omitempty" db:"error_message"`
+	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
+	StartedAt    *time.Time      `json:"started_at,omitempty" db:"started_at"`
+	CompletedAt  *time.Time      `json:"completed_at,omitempty" db:"completed_at"`
+	UpdatedAt    time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// Audit export formats
+const (
+	AuditExportJSON = "json"
+	AuditExportCSV  = "csv"
+)
+
 // GetFullName returns the user's full name
 func (u *UserProfile) GetFullName() string {
 	return fmt.Sprintf("%s %s", u.FirstName, u.LastName)
@@ -253,7 +1353,7 @@ func (u *UserProfile) IsComplete() bool {
 	return u.FirstName != "" &&
 		u.LastName != "" &&
 		!u.DateOfBirth.IsZero() &&
-		u.SSNEncrypted != "" &&
+		u.SSNToken != "" &&
 		u.Phone != "" &&
 		u.Address.Street != "" &&
 		u.Address.City != "" &&
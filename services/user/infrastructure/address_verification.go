@@ -0,0 +1,79 @@
+package infrastructure
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/shared/pkg/address"
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// AddressVerificationService adapts the shared address verifier to user profile addresses
+type AddressVerificationService struct {
+	verifier address.Verifier
+	logger   *zap.Logger
+}
+
+func NewAddressVerificationService(verifier address.Verifier, logger *zap.Logger) domain.AddressVerificationService {
+	return &AddressVerificationService{
+		verifier: verifier,
+		logger:   logger,
+	}
+}
+
+func (s *AddressVerificationService) StandardizeAddress(ctx context.Context, addr *domain.Address) (*domain.Address, *domain.AddressVerification, error) {
+	result, err := s.verifier.Verify(ctx, address.Address{
+		Street:  addr.Street,
+		City:    addr.City,
+		State:   addr.State,
+		ZipCode: addr.ZipCode,
+		Country: addr.Country,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	verification := &domain.AddressVerification{
+		Provider:     result.Provider,
+		Deliverable:  result.Deliverable,
+		DPVMatchCode: result.DPVMatchCode,
+	}
+	for _, suggestion := range result.Suggestions {
+		verification.Suggestions = append(verification.Suggestions, toDomainAddress(suggestion))
+	}
+
+	// Without a provider match the submitted address is kept as entered and flagged
+	standardized := *addr
+	if result.Standardized != nil {
+		standardized = toDomainAddress(*result.Standardized)
+		standardized.DeliveryPointBarcode = result.DeliveryPointBarcode
+		standardized.Standardized = true
+	}
+	standardized.Undeliverable = !result.Deliverable
+
+	if standardized.Undeliverable {
+		s.logger.Info("Address flagged as undeliverable",
+			zap.String("provider", result.Provider),
+			zap.String("dpv_match_code", result.DPVMatchCode),
+			zap.Int("suggestions", len(verification.Suggestions)),
+		)
+	}
+
+	return &standardized, verification, nil
+}
+
+func toDomainAddress(addr address.Address) domain.Address {
+	street := addr.Street
+	if addr.Secondary != "" {
+		street += " " + addr.Secondary
+	}
+	return domain.Address{
+		Street:   street,
+		City:     addr.City,
+		State:    addr.State,
+		ZipCode:  addr.ZipCode,
+		Country:  addr.Country,
+		ZipPlus4: addr.Plus4,
+	}
+}
@@ -0,0 +1,129 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// auditChainLockKey is the advisory lock serializing appends, so every entry links to the true chain head
+const auditChainLockKey = 0x61756469 // "audi"
+
+const auditEntryColumns = `id, sequence, COALESCE(user_id::text, '') AS user_id, COALESCE(actor_id, '') AS actor_id,
+	event_type, category, metadata, created_at, prev_hash, hash`
+
+// Audit Repository implementation
+
+type PostgresAuditRepository struct {
+	db     *sqlx.DB
+	logger *zap.Logger
+}
+
+func NewPostgresAuditRepository(db *sqlx.DB, logger *zap.Logger) domain.AuditRepository {
+	return &PostgresAuditRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *PostgresAuditRepository) AppendEntry(ctx context.Context, entry *domain.AuditEntry) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin audit transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, auditChainLockKey); err != nil {
+		return fmt.Errorf("failed to lock audit chain: %w", err)
+	}
+
+	var prevHash string
+	err = tx.GetContext(ctx, &prevHash, `SELECT hash FROM audit_events ORDER BY sequence DESC LIMIT 1`)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to read audit chain head: %w", err)
+	}
+
+	entry.PrevHash = prevHash
+	entry.Hash = entry.ComputeHash()
+
+	query := `
+		INSERT INTO audit_events (id, user_id, actor_id, event_type, category, metadata, created_at, prev_hash, hash)
+		VALUES ($1, NULLIF($2, '')::uuid, NULLIF($3, ''), $4, $5, $6, $7, $8, $9)
+		RETURNING sequence`
+
+	err = tx.GetContext(ctx, &entry.Sequence, query,
+		entry.ID, entry.UserID, entry.ActorID, entry.EventType, entry.Category,
+		entry.Metadata, entry.CreatedAt, entry.PrevHash, entry.Hash,
+	)
+	if err != nil {
+		r.logger.Error("Failed to insert audit event", zap.Error(err), zap.String("event_type", entry.EventType))
+		return fmt.Errorf("failed to insert audit event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit audit event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresAuditRepository) QueryEntries(ctx context.Context, query *domain.AuditQuery) ([]*domain.AuditEntry, error) {
+	conditions := []string{"1=1"}
+	args := []interface{}{}
+
+	if query.UserID != "" {
+		args = append(args, query.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d::uuid", len(args)))
+	}
+	if query.ActorID != "" {
+		args = append(args, query.ActorID)
+		conditions = append(conditions, fmt.Sprintf("actor_id = $%d", len(args)))
+	}
+	if query.EventType != "" {
+		args = append(args, query.EventType)
+		conditions = append(conditions, fmt.Sprintf("event_type = $%d", len(args)))
+	}
+	if !query.From.IsZero() {
+		args = append(args, query.From)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if !query.To.IsZero() {
+		args = append(args, query.To)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	sqlQuery := fmt.Sprintf(`SELECT %s FROM audit_events WHERE %s ORDER BY sequence`,
+		auditEntryColumns,
+		strings.Join(conditions, " AND "),
+	)
+	if query.Limit > 0 {
+		args = append(args, query.Limit, query.Offset)
+		sqlQuery += fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+	}
+
+	entries := make([]*domain.AuditEntry, 0)
+	if err := r.db.SelectContext(ctx, &entries, sqlQuery, args...); err != nil {
+		r.logger.Error("Failed to query audit events", zap.Error(err))
+		return nil, fmt.Errorf("failed to query audit events: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (r *PostgresAuditRepository) ListEntriesAfter(ctx context.Context, afterSequence int64, limit int) ([]*domain.AuditEntry, error) {
+	query := `SELECT ` + auditEntryColumns + ` FROM audit_events WHERE sequence > $1 ORDER BY sequence LIMIT $2`
+
+	var entries []*domain.AuditEntry
+	if err := r.db.SelectContext(ctx, &entries, query, afterSequence, limit); err != nil {
+		r.logger.Error("Failed to list audit events", zap.Error(err))
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,114 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// AuthServiceClient propagates account status changes to the auth service over its internal API
+type AuthServiceClient struct {
+	baseURL      string
+	serviceToken string
+	httpClient   *http.Client
+	logger       *zap.Logger
+}
+
+func NewAuthServiceClient(baseURL, serviceToken string, timeout time.Duration, logger *zap.Logger) domain.AuthAccountService {
+	return &AuthServiceClient{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		serviceToken: serviceToken,
+		httpClient:   &http.Client{Timeout: timeout},
+		logger:       logger,
+	}
+}
+
+func (c *AuthServiceClient) ProvisionAccount(ctx context.Context, userID, email, firstName, lastName string) error {
+	return c.send(ctx, http.MethodPut, userID, "", "provision", map[string]string{
+		"email":      email,
+		"first_name": firstName,
+		"last_name":  lastName,
+	}, false)
+}
+
+func (c *AuthServiceClient) DeactivateAccount(ctx context.Context, userID string) error {
+	return c.send(ctx, http.MethodPost, userID, "deactivate", "deactivate", nil, true)
+}
+
+func (c *AuthServiceClient) ReactivateAccount(ctx context.Context, userID string) error {
+	return c.send(ctx, http.MethodPost, userID, "reactivate", "reactivate", nil, true)
+}
+
+func (c *AuthServiceClient) RevokeCredentials(ctx context.Context, userID string) error {
+	return c.send(ctx, http.MethodPost, userID, "revoke-credentials", "revoke_credentials", nil, true)
+}
+
+// SetPassword fails for a user unknown to auth, since the new password could never be used
+func (c *AuthServiceClient) SetPassword(ctx context.Context, userID, password string) error {
+	return c.send(ctx, http.MethodPut, userID, "password", "set_password", map[string]string{"password": password}, false)
+}
+
+// send calls an internal per-user auth endpoint, at action below the user's path; with allowMissing
+// a user unknown to auth is not an error, as it has no credentials to revoke
+func (c *AuthServiceClient) send(ctx context.Context, method, userID, action, operation string, payload interface{}, allowMissing bool) error {
+	logger := c.logger.With(
+		zap.String("operation", operation+"_auth_account"),
+		zap.String("user_id", userID),
+	)
+
+	var body io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s request: %w", operation, err)
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	url := fmt.Sprintf("%s/internal/v1/users/%s", c.baseURL, userID)
+	if action != "" {
+		url += "/" + action
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return fmt.Errorf("failed to build %s request: %w", operation, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.serviceToken)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Auth service request failed", zap.Error(err))
+		return fmt.Errorf("failed to call auth service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && allowMissing {
+		logger.Warn("User not found in auth service")
+		return nil
+	}
+
+	if resp.StatusCode == http.StatusConflict {
+		logger.Warn("Email registered to another auth account")
+		return domain.ErrAccountEmailTaken
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("Unexpected auth service response", zap.Int("status", resp.StatusCode))
+		return fmt.Errorf("unexpected %s status: %d", operation, resp.StatusCode)
+	}
+
+	logger.Info("Auth account updated")
+	return nil
+}
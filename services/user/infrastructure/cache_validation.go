@@ -190,6 +190,60 @@ func (r *RedisCacheService) InvalidateKYCStatus(ctx context.Context, userID stri
 	return nil
 }
 
+func (r *RedisCacheService) CacheCompleteness(ctx context.Context, userID string, completeness *domain.ProfileCompleteness, ttl int) error {
+	key := fmt.Sprintf("completeness:%s", userID)
+
+	data, err := json.Marshal(completeness)
+	if err != nil {
+		r.logger.Error("Failed to marshal completeness for cache", zap.Error(err))
+		return fmt.Errorf("failed to marshal completeness: %w", err)
+	}
+
+	err = r.client.Set(ctx, key, data, time.Duration(ttl)*time.Second).Err()
+	if err != nil {
+		r.logger.Error("Failed to cache completeness", zap.Error(err), zap.String("user_id", userID))
+		return fmt.Errorf("failed to cache completeness: %w", err)
+	}
+
+	r.logger.Debug("Completeness cached successfully", zap.String("user_id", userID))
+	return nil
+}
+
+func (r *RedisCacheService) GetCachedCompleteness(ctx context.Context, userID string) (*domain.ProfileCompleteness, error) {
+	key := fmt.Sprintf("completeness:%s", userID)
+
+	data, err := r.client.Get(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, errors.NewNotFoundError("completeness not in cache")
+		}
+		r.logger.Error("Failed to get cached completeness", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to get cached completeness: %w", err)
+	}
+
+	var completeness domain.ProfileCompleteness
+	err = json.Unmarshal([]byte(data), &completeness)
+	if err != nil {
+		r.logger.Error("Failed to unmarshal cached completeness", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal cached completeness: %w", err)
+	}
+
+	return &completeness, nil
+}
+
+func (r *RedisCacheService) InvalidateCompleteness(ctx context.Context, userID string) error {
+	key := fmt.Sprintf("completeness:%s", userID)
+
+	err := r.client.Del(ctx, key).Err()
+	if err != nil {
+		r.logger.Error("Failed to invalidate completeness cache", zap.Error(err), zap.String("user_id", userID))
+		return fmt.Errorf("failed to invalidate completeness cache: %w", err)
+	}
+
+	r.logger.Debug("Completeness cache invalidated", zap.String("user_id", userID))
+	return nil
+}
+
 // ValidationService implements data validation
 type ValidationService struct {
 	logger *zap.Logger
@@ -397,6 +451,9 @@ func (v *ValidationService) ValidateDocumentType(docType string) error {
 		domain.DocumentTypeUtilityBill,
 		domain.DocumentTypeW2,
 		domain.DocumentType1099,
+		domain.DocumentTypeLoanAgreement,
+		domain.DocumentTypeTILADisclosure,
+		domain.DocumentTypeAdverseActionNotice,
 	}
 
 	for _, validType := range validTypes {
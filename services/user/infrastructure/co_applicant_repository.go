@@ -0,0 +1,109 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+const coApplicantLinkColumns = `id, primary_user_id, COALESCE(co_applicant_user_id::text, '') AS co_applicant_user_id,
+	invite_email, relationship, status, invite_token_hash, invite_expires_at, primary_shared_document_types,
+	co_applicant_shared_document_types, created_at, responded_at, updated_at`
+
+// Co-Applicant Repository implementation
+
+type PostgresCoApplicantRepository struct {
+	db     *sqlx.DB
+	logger *zap.Logger
+}
+
+func NewPostgresCoApplicantRepository(db *sqlx.DB, logger *zap.Logger) domain.CoApplicantRepository {
+	return &PostgresCoApplicantRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *PostgresCoApplicantRepository) CreateLink(ctx context.Context, link *domain.CoApplicantLink) error {
+	query := `
+		INSERT INTO co_applicant_links (id, primary_user_id, invite_email, relationship, status, invite_token_hash,
+			invite_expires_at, primary_shared_document_types, co_applicant_shared_document_types, created_at, updated_at)
+		VALUES (:id, :primary_user_id, :invite_email, :relationship, :status, :invite_token_hash,
+			:invite_expires_at, :primary_shared_document_types, :co_applicant_shared_document_types, :created_at, :updated_at)`
+
+	_, err := r.db.NamedExecContext(ctx, query, link)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation {
+			return errors.New("already exists")
+		}
+		r.logger.Error("Failed to create co-applicant link", zap.Error(err), zap.String("primary_user_id", link.PrimaryUserID))
+		return fmt.Errorf("failed to create co-applicant link: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresCoApplicantRepository) GetLink(ctx context.Context, linkID string) (*domain.CoApplicantLink, error) {
+	return r.getLink(ctx, `id = $1`, linkID)
+}
+
+func (r *PostgresCoApplicantRepository) GetLinkByTokenHash(ctx context.Context, tokenHash string) (*domain.CoApplicantLink, error) {
+	return r.getLink(ctx, `invite_token_hash = $1`, tokenHash)
+}
+
+func (r *PostgresCoApplicantRepository) getLink(ctx context.Context, condition string, arg interface{}) (*domain.CoApplicantLink, error) {
+	var link domain.CoApplicantLink
+	query := `SELECT ` + coApplicantLinkColumns + ` FROM co_applicant_links WHERE ` + condition
+
+	if err := r.db.GetContext(ctx, &link, query, arg); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("not found")
+		}
+		r.logger.Error("Failed to get co-applicant link", zap.Error(err))
+		return nil, fmt.Errorf("failed to get co-applicant link: %w", err)
+	}
+
+	return &link, nil
+}
+
+func (r *PostgresCoApplicantRepository) UpdateLink(ctx context.Context, link *domain.CoApplicantLink) error {
+	query := `
+		UPDATE co_applicant_links
+		SET co_applicant_user_id = NULLIF(:co_applicant_user_id, '')::uuid, status = :status,
+			primary_shared_document_types = :primary_shared_document_types,
+			co_applicant_shared_document_types = :co_applicant_shared_document_types,
+			responded_at = :responded_at, updated_at = :updated_at
+		WHERE id = :id`
+
+	_, err := r.db.NamedExecContext(ctx, query, link)
+	if err != nil {
+		r.logger.Error("Failed to update co-applicant link", zap.Error(err), zap.String("link_id", link.ID))
+		return fmt.Errorf("failed to update co-applicant link: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresCoApplicantRepository) ListLinksForUser(ctx context.Context, userID string) ([]*domain.CoApplicantLink, error) {
+	links := make([]*domain.CoApplicantLink, 0)
+	query := `
+		SELECT ` + coApplicantLinkColumns + `
+		FROM co_applicant_links
+		WHERE primary_user_id = $1 OR co_applicant_user_id = $1
+		ORDER BY created_at DESC`
+
+	if err := r.db.SelectContext(ctx, &links, query, userID); err != nil {
+		r.logger.Error("Failed to list co-applicant links", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to list co-applicant links: %w", err)
+	}
+
+	return links, nil
+}
@@ -0,0 +1,113 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// Consent Repository implementation
+
+type PostgresConsentRepository struct {
+	db     *sqlx.DB
+	logger *zap.Logger
+}
+
+func NewPostgresConsentRepository(db *sqlx.DB, logger *zap.Logger) domain.ConsentRepository {
+	return &PostgresConsentRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *PostgresConsentRepository) CreateConsent(ctx context.Context, consent *domain.Consent) error {
+	query := `
+		INSERT INTO user_consents (id, user_id, consent_type, version, text_hash, ip_address, user_agent, consented_at, created_at)
+		VALUES (:id, :user_id, :consent_type, :version, :text_hash, :ip_address, :user_agent, :consented_at, :created_at)`
+
+	_, err := r.db.NamedExecContext(ctx, query, consent)
+	if err != nil {
+		r.logger.Error("Failed to create consent", zap.Error(err), zap.String("user_id", consent.UserID))
+		return fmt.Errorf("failed to create consent: %w", err)
+	}
+
+	r.logger.Info("Consent created successfully",
+		zap.String("consent_id", consent.ID),
+		zap.String("consent_type", string(consent.ConsentType)),
+	)
+	return nil
+}
+
+// GetLatestConsent returns the most recent consent of the given type, or nil if none was recorded
+func (r *PostgresConsentRepository) GetLatestConsent(ctx context.Context, userID string, consentType domain.ConsentType) (*domain.Consent, error) {
+	var consent domain.Consent
+	query := `
+		SELECT id, user_id, consent_type, version, text_hash, ip_address, user_agent, consented_at, revoked_at, created_at
+		FROM user_consents
+		WHERE user_id = $1 AND consent_type = $2
+		ORDER BY consented_at DESC
+		LIMIT 1`
+
+	err := r.db.GetContext(ctx, &consent, query, userID, consentType)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get consent", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to get consent: %w", err)
+	}
+
+	return &consent, nil
+}
+
+func (r *PostgresConsentRepository) ListConsents(ctx context.Context, userID string) ([]*domain.Consent, error) {
+	var consents []*domain.Consent
+	query := `
+		SELECT id, user_id, consent_type, version, text_hash, ip_address, user_agent, consented_at, revoked_at, created_at
+		FROM user_consents
+		WHERE user_id = $1
+		ORDER BY consented_at DESC`
+
+	err := r.db.SelectContext(ctx, &consents, query, userID)
+	if err != nil {
+		r.logger.Error("Failed to list consents", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to list consents: %w", err)
+	}
+
+	return consents, nil
+}
+
+func (r *PostgresConsentRepository) RevokeConsent(ctx context.Context, userID string, consentType domain.ConsentType) error {
+	query := `
+		UPDATE user_consents
+		SET revoked_at = NOW()
+		WHERE user_id = $1 AND consent_type = $2 AND revoked_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, userID, consentType)
+	if err != nil {
+		r.logger.Error("Failed to revoke consent", zap.Error(err), zap.String("user_id", userID))
+		return fmt.Errorf("failed to revoke consent: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Error("Failed to get rows affected", zap.Error(err))
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("not found")
+	}
+
+	r.logger.Info("Consent revoked successfully",
+		zap.String("user_id", userID),
+		zap.String("consent_type", string(consentType)),
+	)
+	return nil
+}
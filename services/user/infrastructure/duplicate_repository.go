@@ -0,0 +1,167 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// Duplicate Repository implementation
+
+type PostgresDuplicateRepository struct {
+	db     *sqlx.DB
+	logger *zap.Logger
+}
+
+func NewPostgresDuplicateRepository(db *sqlx.DB, logger *zap.Logger) domain.DuplicateRepository {
+	return &PostgresDuplicateRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// maxDuplicateCandidates bounds how many users a single probe is scored against
+const maxDuplicateCandidates = 20
+
+func (r *PostgresDuplicateRepository) FindCandidates(ctx context.Context, probe *domain.DuplicateProbe) ([]*domain.DuplicateCandidate, error) {
+	var candidates []*domain.DuplicateCandidate
+	query := `
+		SELECT u.id AS user_id,
+		       COALESCE(u.email_normalized, '') AS email_normalized,
+		       COALESCE(u.phone_normalized, '') AS phone_normalized,
+		       COALESCE(p.first_name, '') AS first_name,
+		       COALESCE(p.last_name, '') AS last_name,
+		       p.date_of_birth,
+		       COALESCE(t.last4, '') AS ssn_last4
+		FROM users u
+		LEFT JOIN user_profiles p ON p.user_id = u.id
+		LEFT JOIN pii_tokens t ON t.token = p.ssn_token
+		WHERE u.deleted_at IS NULL
+		  AND u.id != $1
+		  AND (
+		        (u.email_normalized = $2 AND $2 <> '')
+		     OR (u.phone_normalized = $3 AND $3 <> '')
+		     OR (p.date_of_birth = $4 AND (t.last4 = $5 OR LOWER(p.last_name) = LOWER($6)))
+		  )
+		LIMIT $7`
+
+	err := r.db.SelectContext(ctx, &candidates, query,
+		probe.UserID,
+		probe.EmailNormalized,
+		probe.PhoneNormalized,
+		probe.DateOfBirth,
+		probe.SSNLast4,
+		probe.LastName,
+		maxDuplicateCandidates,
+	)
+	if err != nil {
+		r.logger.Error("Failed to find duplicate candidates", zap.Error(err), zap.String("user_id", probe.UserID))
+		return nil, fmt.Errorf("failed to find duplicate candidates: %w", err)
+	}
+
+	return candidates, nil
+}
+
+func (r *PostgresDuplicateRepository) CreateMatch(ctx context.Context, match *domain.DuplicateMatch) error {
+	query := `
+		INSERT INTO user_duplicate_matches (id, user_id, candidate_user_id, score, reasons, status, created_at)
+		VALUES (:id, :user_id, :candidate_user_id, :score, :reasons, :status, :created_at)
+		ON CONFLICT (user_id, candidate_user_id) DO NOTHING`
+
+	_, err := r.db.NamedExecContext(ctx, query, match)
+	if err != nil {
+		r.logger.Error("Failed to create duplicate match", zap.Error(err), zap.String("user_id", match.UserID))
+		return fmt.Errorf("failed to create duplicate match: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresDuplicateRepository) ListMatches(ctx context.Context, userID string) ([]*domain.DuplicateMatch, error) {
+	var matches []*domain.DuplicateMatch
+	query := `
+		SELECT id, user_id, candidate_user_id, score, reasons, status, created_at, reviewed_at
+		FROM user_duplicate_matches
+		WHERE user_id = $1 OR candidate_user_id = $1
+		ORDER BY score DESC, created_at DESC`
+
+	err := r.db.SelectContext(ctx, &matches, query, userID)
+	if err != nil {
+		r.logger.Error("Failed to list duplicate matches", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to list duplicate matches: %w", err)
+	}
+
+	return matches, nil
+}
+
+func (r *PostgresDuplicateRepository) MergeUsers(ctx context.Context, merge *domain.UserMerge) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin merge transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	moved := func(query string) (int, error) {
+		result, err := tx.ExecContext(ctx, query, merge.SurvivorID, merge.MergedID)
+		if err != nil {
+			return 0, err
+		}
+		rows, err := result.RowsAffected()
+		return int(rows), err
+	}
+
+	if merge.DocumentsMoved, err = moved(`UPDATE user_documents SET user_id = $1 WHERE user_id = $2`); err != nil {
+		r.logger.Error("Failed to move documents", zap.Error(err), zap.String("merged_id", merge.MergedID))
+		return fmt.Errorf("failed to move documents: %w", err)
+	}
+
+	// A user holds at most one verification per type; the survivor's own record wins and the
+	// duplicate's stays attached to the soft-deleted user for the audit trail
+	if merge.KYCRecordsMoved, err = moved(`
+		UPDATE kyc_verifications SET user_id = $1, updated_at = NOW()
+		WHERE user_id = $2
+		  AND verification_type NOT IN (SELECT verification_type FROM kyc_verifications WHERE user_id = $1)`); err != nil {
+		r.logger.Error("Failed to move KYC records", zap.Error(err), zap.String("merged_id", merge.MergedID))
+		return fmt.Errorf("failed to move KYC records: %w", err)
+	}
+
+	if merge.ConsentsMoved, err = moved(`UPDATE user_consents SET user_id = $1 WHERE user_id = $2`); err != nil {
+		r.logger.Error("Failed to move consents", zap.Error(err), zap.String("merged_id", merge.MergedID))
+		return fmt.Errorf("failed to move consents: %w", err)
+	}
+
+	if _, err := moved(`
+		UPDATE users SET merged_into = $1, deleted_at = COALESCE(deleted_at, NOW()), updated_at = NOW()
+		WHERE id = $2`); err != nil {
+		r.logger.Error("Failed to retire merged user", zap.Error(err), zap.String("merged_id", merge.MergedID))
+		return fmt.Errorf("failed to retire merged user: %w", err)
+	}
+
+	if _, err := moved(`
+		UPDATE user_duplicate_matches SET status = 'merged', reviewed_at = NOW()
+		WHERE (user_id = $1 AND candidate_user_id = $2) OR (user_id = $2 AND candidate_user_id = $1)`); err != nil {
+		r.logger.Error("Failed to resolve duplicate matches", zap.Error(err), zap.String("merged_id", merge.MergedID))
+		return fmt.Errorf("failed to resolve duplicate matches: %w", err)
+	}
+
+	query := `
+		INSERT INTO user_merges (id, survivor_id, merged_id, performed_by, reason, documents_moved,
+			kyc_records_moved, consents_moved, loan_applications_moved, created_at)
+		VALUES (:id, :survivor_id, :merged_id, :performed_by, :reason, :documents_moved,
+			:kyc_records_moved, :consents_moved, :loan_applications_moved, :created_at)`
+	if _, err := tx.NamedExecContext(ctx, query, merge); err != nil {
+		r.logger.Error("Failed to record user merge", zap.Error(err), zap.String("merge_id", merge.ID))
+		return fmt.Errorf("failed to record user merge: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit merge transaction: %w", err)
+	}
+
+	r.logger.Info("Users merged", zap.String("survivor_id", merge.SurvivorID), zap.String("merged_id", merge.MergedID))
+	return nil
+}
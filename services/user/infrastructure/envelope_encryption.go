@@ -0,0 +1,327 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// envelopePrefix marks ciphertexts produced by EnvelopeEncryptionService.
+// Format: env1:<key_version>:<base64 wrapped data key>:<base64 nonce+ciphertext>
+const envelopePrefix = "env1"
+
+// KMSClient abstracts the key management service API used to wrap data keys
+type KMSClient interface {
+	GenerateDataKey(ctx context.Context, keyID string) (plaintext []byte, ciphertextBlob []byte, err error)
+	Decrypt(ctx context.Context, keyID string, ciphertextBlob []byte) ([]byte, error)
+}
+
+// KMSKeyProvider implements domain.KeyProvider on top of a KMS client.
+// Key versions map to KMS key IDs so that retired keys can still unwrap old data keys.
+type KMSKeyProvider struct {
+	client         KMSClient
+	keyIDs         map[string]string
+	currentVersion string
+}
+
+func NewKMSKeyProvider(client KMSClient, keyIDs map[string]string, currentVersion string) (domain.KeyProvider, error) {
+	if _, ok := keyIDs[currentVersion]; !ok {
+		return nil, fmt.Errorf("no KMS key configured for current version %q", currentVersion)
+	}
+	return &KMSKeyProvider{
+		client:         client,
+		keyIDs:         keyIDs,
+		currentVersion: currentVersion,
+	}, nil
+}
+
+func (p *KMSKeyProvider) CurrentKeyVersion() string {
+	return p.currentVersion
+}
+
+func (p *KMSKeyProvider) GenerateDataKey(ctx context.Context) ([]byte, []byte, string, error) {
+	plaintext, wrapped, err := p.client.GenerateDataKey(ctx, p.keyIDs[p.currentVersion])
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+	return plaintext, wrapped, p.currentVersion, nil
+}
+
+func (p *KMSKeyProvider) DecryptDataKey(ctx context.Context, wrappedKey []byte, keyVersion string) ([]byte, error) {
+	keyID, ok := p.keyIDs[keyVersion]
+	if !ok {
+		return nil, fmt.Errorf("unknown key version %q", keyVersion)
+	}
+	plaintext, err := p.client.Decrypt(ctx, keyID, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	return plaintext, nil
+}
+
+// LocalKeyProvider implements domain.KeyProvider with a static keyring of master keys.
+// Intended for development and tests where no KMS is available.
+type LocalKeyProvider struct {
+	masterKeys     map[string][]byte
+	currentVersion string
+}
+
+func NewLocalKeyProvider(masterKeys map[string]string, currentVersion string) (domain.KeyProvider, error) {
+	keys := make(map[string][]byte, len(masterKeys))
+	for version, secret := range masterKeys {
+		hash := sha256.Sum256([]byte(secret))
+		keys[version] = hash[:]
+	}
+	if _, ok := keys[currentVersion]; !ok {
+		return nil, fmt.Errorf("no master key configured for current version %q", currentVersion)
+	}
+	return &LocalKeyProvider{
+		masterKeys:     keys,
+		currentVersion: currentVersion,
+	}, nil
+}
+
+func (p *LocalKeyProvider) CurrentKeyVersion() string {
+	return p.currentVersion
+}
+
+func (p *LocalKeyProvider) GenerateDataKey(ctx context.Context) ([]byte, []byte, string, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	wrapped, err := sealGCM(p.masterKeys[p.currentVersion], dataKey)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	return dataKey, wrapped, p.currentVersion, nil
+}
+
+func (p *LocalKeyProvider) DecryptDataKey(ctx context.Context, wrappedKey []byte, keyVersion string) ([]byte, error) {
+	masterKey, ok := p.masterKeys[keyVersion]
+	if !ok {
+		return nil, fmt.Errorf("unknown key version %q", keyVersion)
+	}
+	dataKey, err := openGCM(masterKey, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	return dataKey, nil
+}
+
+// EnvelopeEncryptionService encrypts each value with its own data key wrapped by a KeyProvider.
+// Values written by the legacy single-key AESEncryptionService are still readable through the fallback.
+type EnvelopeEncryptionService struct {
+	keyProvider domain.KeyProvider
+	legacy      domain.EncryptionService
+	logger      *zap.Logger
+}
+
+func NewEnvelopeEncryptionService(keyProvider domain.KeyProvider, legacy domain.EncryptionService, logger *zap.Logger) domain.EncryptionService {
+	return &EnvelopeEncryptionService{
+		keyProvider: keyProvider,
+		legacy:      legacy,
+		logger:      logger,
+	}
+}
+
+func (e *EnvelopeEncryptionService) EncryptField(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	ctx := context.Background()
+	dataKey, wrappedKey, keyVersion, err := e.keyProvider.GenerateDataKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := sealGCM(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt field: %w", err)
+	}
+
+	return strings.Join([]string{
+		envelopePrefix,
+		keyVersion,
+		base64.StdEncoding.EncodeToString(wrappedKey),
+		base64.StdEncoding.EncodeToString(ciphertext),
+	}, ":"), nil
+}
+
+func (e *EnvelopeEncryptionService) DecryptField(ciphertext string) (string, error) {
+	if ciphertext == "" {
+		return "", nil
+	}
+
+	if !strings.HasPrefix(ciphertext, envelopePrefix+":") {
+		if e.legacy == nil {
+			return "", fmt.Errorf("unsupported ciphertext format")
+		}
+		return e.legacy.DecryptField(ciphertext)
+	}
+
+	parts := strings.Split(ciphertext, ":")
+	if len(parts) != 4 {
+		return "", fmt.Errorf("malformed envelope ciphertext")
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("failed to decode wrapped key: %w", err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return "", fmt.Errorf("failed to decode ciphertext: %w", err)
+	}
+
+	dataKey, err := e.keyProvider.DecryptDataKey(context.Background(), wrappedKey, parts[1])
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := openGCM(dataKey, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt field: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+func (e *EnvelopeEncryptionService) EncryptFile(content []byte) ([]byte, string, error) {
+	// Generate a random AES-256 key for this file
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, "", fmt.Errorf("failed to generate file encryption key: %w", err)
+	}
+
+	ciphertext, err := sealGCM(key, content)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encrypt file: %w", err)
+	}
+
+	// The file key is itself stored as an envelope-encrypted field
+	encryptedKey, err := e.EncryptField(base64.StdEncoding.EncodeToString(key))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encrypt file key: %w", err)
+	}
+
+	return ciphertext, encryptedKey, nil
+}
+
+func (e *EnvelopeEncryptionService) DecryptFile(encryptedContent []byte, encryptedKey string) ([]byte, error) {
+	keyStr, err := e.DecryptField(encryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt file key: %w", err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(keyStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode file key: %w", err)
+	}
+
+	plaintext, err := openGCM(key, encryptedContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt file content: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func (e *EnvelopeEncryptionService) GenerateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+func (e *EnvelopeEncryptionService) RotateKey(oldKey string) (string, error) {
+	return e.ReEncryptField(oldKey)
+}
+
+func (e *EnvelopeEncryptionService) KeyVersion(ciphertext string) string {
+	if !strings.HasPrefix(ciphertext, envelopePrefix+":") {
+		return ""
+	}
+	parts := strings.SplitN(ciphertext, ":", 3)
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[1]
+}
+
+func (e *EnvelopeEncryptionService) NeedsReEncryption(ciphertext string) bool {
+	if ciphertext == "" {
+		return false
+	}
+	return e.KeyVersion(ciphertext) != e.keyProvider.CurrentKeyVersion()
+}
+
+func (e *EnvelopeEncryptionService) ReEncryptField(ciphertext string) (string, error) {
+	if !e.NeedsReEncryption(ciphertext) {
+		return ciphertext, nil
+	}
+
+	plaintext, err := e.DecryptField(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt for re-encryption: %w", err)
+	}
+
+	return e.EncryptField(plaintext)
+}
+
+// sealGCM encrypts plaintext with AES-GCM and prepends the nonce
+func sealGCM(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// openGCM decrypts data produced by sealGCM
+func openGCM(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(data) < nonceSize {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+
+	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
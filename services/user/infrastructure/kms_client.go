@@ -0,0 +1,103 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// VaultTransitClient implements KMSClient with the transit secrets engine of a Vault server.
+// Data keys are generated and unwrapped by Vault; the key encryption keys never leave it.
+type VaultTransitClient struct {
+	address    string
+	token      string
+	mount      string
+	httpClient *http.Client
+}
+
+func NewVaultTransitClient(address, token, mount string, timeout time.Duration) (KMSClient, error) {
+	if address == "" || token == "" {
+		return nil, fmt.Errorf("vault address and token are required")
+	}
+	if mount == "" {
+		mount = "transit"
+	}
+	return &VaultTransitClient{
+		address:    strings.TrimRight(address, "/"),
+		token:      token,
+		mount:      strings.Trim(mount, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// GenerateDataKey returns a new 256-bit data key and the key wrapped by the named transit key
+func (c *VaultTransitClient) GenerateDataKey(ctx context.Context, keyID string) ([]byte, []byte, error) {
+	var response struct {
+		Data struct {
+			Plaintext  string `json:"plaintext"`
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	if err := c.post(ctx, "datakey/plaintext/"+url.PathEscape(keyID), map[string]interface{}{"bits": 256}, &response); err != nil {
+		return nil, nil, err
+	}
+
+	plaintext, err := base64.StdEncoding.DecodeString(response.Data.Plaintext)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode data key: %w", err)
+	}
+	// The wrapped key is Vault's ciphertext string ("vault:v1:..."), kept as is to unwrap it later
+	return plaintext, []byte(response.Data.Ciphertext), nil
+}
+
+// Decrypt unwraps a data key wrapped by the named transit key
+func (c *VaultTransitClient) Decrypt(ctx context.Context, keyID string, ciphertextBlob []byte) ([]byte, error) {
+	var response struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	if err := c.post(ctx, "decrypt/"+url.PathEscape(keyID), map[string]interface{}{"ciphertext": string(ciphertextBlob)}, &response); err != nil {
+		return nil, err
+	}
+
+	plaintext, err := base64.StdEncoding.DecodeString(response.Data.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode data key: %w", err)
+	}
+	return plaintext, nil
+}
+
+func (c *VaultTransitClient) post(ctx context.Context, path string, payload interface{}, out interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vault request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/v1/%s/%s", c.address, c.mount, path), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build vault request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected vault status: %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode vault response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// LoanServiceClient reads and moves loan data over the loan service's internal API
+type LoanServiceClient struct {
+	baseURL      string
+	serviceToken string
+	httpClient   *http.Client
+	logger       *zap.Logger
+}
+
+func NewLoanServiceClient(baseURL, serviceToken string, timeout time.Duration, logger *zap.Logger) domain.LoanAccountService {
+	return &LoanServiceClient{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		serviceToken: serviceToken,
+		httpClient:   &http.Client{Timeout: timeout},
+		logger:       logger,
+	}
+}
+
+func (c *LoanServiceClient) ReassignApplications(ctx context.Context, fromUserID, toUserID string) (int, error) {
+	logger := c.logger.With(
+		zap.String("operation", "reassign_loan_applications"),
+		zap.String("from_user_id", fromUserID),
+		zap.String("to_user_id", toUserID),
+	)
+
+	body, err := json.Marshal(map[string]string{
+		"from_user_id": fromUserID,
+		"to_user_id":   toUserID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode reassign request: %w", err)
+	}
+
+	url := c.baseURL + "/internal/v1/applications/reassign"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build reassign request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.serviceToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Loan service request failed", zap.Error(err))
+		return 0, fmt.Errorf("failed to call loan service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("Unexpected loan service response", zap.Int("status", resp.StatusCode))
+		return 0, fmt.Errorf("unexpected reassign status: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			Reassigned int `json:"reassigned"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode reassign response: %w", err)
+	}
+
+	logger.Info("Loan applications reassigned", zap.Int("reassigned", result.Data.Reassigned))
+	return result.Data.Reassigned, nil
+}
+
+func (c *LoanServiceClient) GetLoanActivity(ctx context.Context, userID string) (*domain.LoanActivity, error) {
+	url := fmt.Sprintf("%s/internal/v1/users/%s/loan-activity", c.baseURL, userID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build loan activity request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.serviceToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Loan service request failed", zap.String("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to call loan service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.logger.Error("Unexpected loan service response", zap.String("user_id", userID), zap.Int("status", resp.StatusCode))
+		return nil, fmt.Errorf("unexpected loan activity status: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data domain.LoanActivity `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode loan activity response: %w", err)
+	}
+
+	return &result.Data, nil
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"go.uber.org/zap"
@@ -28,8 +29,8 @@ func NewPostgresUserRepository(db *sqlx.DB, logger *zap.Logger) domain.UserRepos
 
 func (r *PostgresUserRepository) CreateUser(ctx context.Context, user *domain.User) error {
 	query := `
-		INSERT INTO users (id, email, password_hash, phone, email_verified, phone_verified, status, created_at, updated_at)
-		VALUES (:id, :email, :password_hash, :phone, :email_verified, :phone_verified, :status, :created_at, :updated_at)`
+		INSERT INTO users (id, email, email_normalized, password_hash, phone, phone_normalized, email_verified, phone_verified, status, created_at, updated_at)
+		VALUES (:id, :email, :email_normalized, :password_hash, :phone, :phone_normalized, :email_verified, :phone_verified, :status, :created_at, :updated_at)`
 
 	_, err := r.db.NamedExecContext(ctx, query, user)
 	if err != nil {
@@ -46,7 +47,7 @@ func (r *PostgresUserRepository) GetUserByID(ctx context.Context, userID string)
 	query := `
 		SELECT id, email, password_hash, phone, email_verified, phone_verified, status, created_at, updated_at
 		FROM users 
-		WHERE id = $1 AND status != 'deleted'`
+		WHERE id = $1 AND deleted_at IS NULL`
 
 	err := r.db.GetContext(ctx, &user, query, userID)
 	if err != nil {
@@ -65,7 +66,7 @@ func (r *PostgresUserRepository) GetUserByEmail(ctx context.Context, email strin
 	query := `
 		SELECT id, email, password_hash, phone, email_verified, phone_verified, status, created_at, updated_at
 		FROM users 
-		WHERE email = $1 AND status != 'deleted'`
+		WHERE email = $1 AND deleted_at IS NULL`
 
 	err := r.db.GetContext(ctx, &user, query, email)
 	if err != nil {
@@ -98,7 +99,7 @@ func (r *PostgresUserRepository) UpdateUser(ctx context.Context, userID string,
 	query := fmt.Sprintf(`
 		UPDATE users 
 		SET %s
-		WHERE id = $%d`,
+		WHERE id = $%d AND deleted_at IS NULL`,
 		strings.Join(setParts, ", "),
 		argIndex,
 	)
@@ -125,7 +126,7 @@ func (r *PostgresUserRepository) UpdateUser(ctx context.Context, userID string,
 }
 
 func (r *PostgresUserRepository) DeleteUser(ctx context.Context, userID string) error {
-	query := `UPDATE users SET status = 'deleted', updated_at = NOW() WHERE id = $1`
+	query := `UPDATE users SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
 
 	result, err := r.db.ExecContext(ctx, query, userID)
 	if err != nil {
@@ -147,12 +148,101 @@ func (r *PostgresUserRepository) DeleteUser(ctx context.Context, userID string)
 	return nil
 }
 
+func (r *PostgresUserRepository) GetDeletedUser(ctx context.Context, userID string) (*domain.User, error) {
+	var user domain.User
+	query := `
+		SELECT id, email, password_hash, phone, email_verified, phone_verified, status, created_at, updated_at, deleted_at
+		FROM users 
+		WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	err := r.db.GetContext(ctx, &user, query, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("user not found")
+		}
+		r.logger.Error("Failed to get deleted user", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to get deleted user: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (r *PostgresUserRepository) RestoreUser(ctx context.Context, userID string) error {
+	query := `UPDATE users SET deleted_at = NULL, updated_at = NOW() WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	result, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		r.logger.Error("Failed to restore user", zap.Error(err), zap.String("user_id", userID))
+		return fmt.Errorf("failed to restore user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Error("Failed to get rows affected", zap.Error(err))
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("user not found")
+	}
+
+	r.logger.Info("User restored successfully", zap.String("user_id", userID))
+	return nil
+}
+
+func (r *PostgresUserRepository) SetLegalHold(ctx context.Context, hold *domain.LegalHold) error {
+	query := `
+		UPDATE users
+		SET legal_hold = :legal_hold, legal_hold_reason = :legal_hold_reason,
+			legal_hold_set_by = :legal_hold_set_by, legal_hold_set_at = :legal_hold_set_at
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecContext(ctx, query, hold)
+	if err != nil {
+		r.logger.Error("Failed to set legal hold", zap.Error(err), zap.String("user_id", hold.UserID))
+		return fmt.Errorf("failed to set legal hold: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Error("Failed to get rows affected", zap.Error(err))
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("user not found")
+	}
+
+	r.logger.Info("Legal hold updated", zap.String("user_id", hold.UserID), zap.Bool("hold", hold.Hold))
+	return nil
+}
+
 // User profile operations
 
+func (r *PostgresUserRepository) ConsumePasswordResetToken(ctx context.Context, tokenHash string) (string, error) {
+	query := `
+		UPDATE users
+		SET password_reset_token = NULL, password_reset_expires = NULL, updated_at = NOW()
+		WHERE password_reset_token = $1 AND password_reset_expires > NOW() AND deleted_at IS NULL
+		RETURNING id`
+
+	var userID string
+	err := r.db.GetContext(ctx, &userID, query, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		r.logger.Error("Failed to consume password reset token", zap.Error(err))
+		return "", fmt.Errorf("failed to consume password reset token: %w", err)
+	}
+
+	return userID, nil
+}
+
 func (r *PostgresUserRepository) CreateProfile(ctx context.Context, profile *domain.UserProfile) error {
 	query := `
-		INSERT INTO user_profiles (id, user_id, first_name, last_name, date_of_birth, ssn_encrypted, phone, address, employment_info, financial_info, created_at, updated_at)
-		VALUES (:id, :user_id, :first_name, :last_name, :date_of_birth, :ssn_encrypted, :phone, :address, :employment_info, :financial_info, :created_at, :updated_at)`
+		INSERT INTO user_profiles (id, user_id, first_name, last_name, date_of_birth, ssn_token, phone, address, employment_info, financial_info, created_at, updated_at)
+		VALUES (:id, :user_id, :first_name, :last_name, :date_of_birth, :ssn_token, :phone, :address, :employment_info, :financial_info, :created_at, :updated_at)`
 
 	_, err := r.db.NamedExecContext(ctx, query, profile)
 	if err != nil {
@@ -167,9 +257,9 @@ func (r *PostgresUserRepository) CreateProfile(ctx context.Context, profile *dom
 func (r *PostgresUserRepository) GetProfile(ctx context.Context, userID string) (*domain.UserProfile, error) {
 	var profile domain.UserProfile
 	query := `
-		SELECT id, user_id, first_name, last_name, date_of_birth, ssn_encrypted, phone, address, employment_info, financial_info, created_at, updated_at
+		SELECT id, user_id, first_name, last_name, date_of_birth, ssn_token, phone, address, employment_info, financial_info, created_at, updated_at
 		FROM user_profiles 
-		WHERE user_id = $1`
+		WHERE user_id = $1 AND user_id IN (SELECT id FROM users WHERE deleted_at IS NULL)`
 
 	err := r.db.GetContext(ctx, &profile, query, userID)
 	if err != nil {
@@ -202,7 +292,7 @@ func (r *PostgresUserRepository) UpdateProfile(ctx context.Context, userID strin
 	query := fmt.Sprintf(`
 		UPDATE user_profiles 
 		SET %s
-		WHERE user_id = $%d`,
+		WHERE user_id = $%d AND user_id IN (SELECT id FROM users WHERE deleted_at IS NULL)`,
 		strings.Join(setParts, ", "),
 		argIndex,
 	)
@@ -230,16 +320,40 @@ func (r *PostgresUserRepository) UpdateProfile(ctx context.Context, userID strin
 
 // User search and listing
 
-func (r *PostgresUserRepository) ListUsers(ctx context.Context, offset, limit int) ([]*domain.User, error) {
-	var users []*domain.User
-	query := `
+func (r *PostgresUserRepository) ListUsers(ctx context.Context, query *domain.UserListQuery) ([]*domain.User, error) {
+	whereParts, args := buildUserFilter(query)
+
+	// Keyset pagination on (sort column, id) keeps pages stable while rows are inserted
+	sortColumn := string(query.SortBy)
+	direction, comparator := "DESC", "<"
+	if query.SortOrder == domain.SortOrderAsc {
+		direction, comparator = "ASC", ">"
+	}
+
+	if query.After != nil {
+		valueCast := "::timestamp"
+		if query.After.SortBy == domain.UserSortEmail {
+			valueCast = ""
+		}
+		whereParts = append(whereParts, fmt.Sprintf("(%s, id) %s ($%d%s, $%d::uuid)",
+			sortColumn, comparator, len(args)+1, valueCast, len(args)+2))
+		args = append(args, query.After.Value, query.After.ID)
+	}
+
+	sqlQuery := fmt.Sprintf(`
 		SELECT id, email, password_hash, phone, email_verified, phone_verified, status, created_at, updated_at
 		FROM users 
-		WHERE status != 'deleted'
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2`
+		WHERE %s
+		ORDER BY %s %s, id %s
+		LIMIT $%d`,
+		strings.Join(whereParts, " AND "),
+		sortColumn, direction, direction,
+		len(args)+1,
+	)
+	args = append(args, query.Limit)
 
-	err := r.db.SelectContext(ctx, &users, query, limit, offset)
+	var users []*domain.User
+	err := r.db.SelectContext(ctx, &users, sqlQuery, args...)
 	if err != nil {
 		r.logger.Error("Failed to list users", zap.Error(err))
 		return nil, fmt.Errorf("failed to list users: %w", err)
@@ -249,55 +363,60 @@ func (r *PostgresUserRepository) ListUsers(ctx context.Context, offset, limit in
 	return users, nil
 }
 
-func (r *PostgresUserRepository) SearchUsers(ctx context.Context, criteria map[string]interface{}) ([]*domain.User, error) {
-	// Build dynamic search query
-	whereParts := []string{"status != 'deleted'"}
-	args := make([]interface{}, 0)
-	argIndex := 1
+// CountUsers returns the number of users matching the query filters, ignoring the cursor
+func (r *PostgresUserRepository) CountUsers(ctx context.Context, query *domain.UserListQuery) (int, error) {
+	whereParts, args := buildUserFilter(query)
 
-	for field, value := range criteria {
-		switch field {
-		case "email":
-			whereParts = append(whereParts, fmt.Sprintf("email ILIKE $%d", argIndex))
-			args = append(args, "%"+value.(string)+"%")
-			argIndex++
-		case "phone":
-			whereParts = append(whereParts, fmt.Sprintf("phone = $%d", argIndex))
-			args = append(args, value)
-			argIndex++
-		case "status":
-			whereParts = append(whereParts, fmt.Sprintf("status = $%d", argIndex))
-			args = append(args, value)
-			argIndex++
-		case "email_verified":
-			whereParts = append(whereParts, fmt.Sprintf("email_verified = $%d", argIndex))
-			args = append(args, value)
-			argIndex++
-		case "phone_verified":
-			whereParts = append(whereParts, fmt.Sprintf("phone_verified = $%d", argIndex))
-			args = append(args, value)
-			argIndex++
-		}
+	var count int
+	sqlQuery := fmt.Sprintf(`SELECT COUNT(*) FROM users WHERE %s`, strings.Join(whereParts, " AND "))
+	err := r.db.GetContext(ctx, &count, sqlQuery, args...)
+	if err != nil {
+		r.logger.Error("Failed to count users", zap.Error(err))
+		return 0, fmt.Errorf("failed to count users: %w", err)
 	}
 
-	query := fmt.Sprintf(`
-		SELECT id, email, password_hash, phone, email_verified, phone_verified, status, created_at, updated_at
-		FROM users 
-		WHERE %s
-		ORDER BY created_at DESC
-		LIMIT 100`,
-		strings.Join(whereParts, " AND "),
-	)
+	return count, nil
+}
 
-	var users []*domain.User
-	err := r.db.SelectContext(ctx, &users, query, args...)
-	if err != nil {
-		r.logger.Error("Failed to search users", zap.Error(err))
-		return nil, fmt.Errorf("failed to search users: %w", err)
+// buildUserFilter translates the query filters into WHERE clauses and positional arguments
+func buildUserFilter(query *domain.UserListQuery) ([]string, []interface{}) {
+	whereParts := []string{"deleted_at IS NULL"}
+	args := make([]interface{}, 0)
+
+	add := func(clause string, value interface{}) {
+		args = append(args, value)
+		whereParts = append(whereParts, fmt.Sprintf(clause, len(args)))
 	}
 
-	r.logger.Info("User search completed", zap.Int("count", len(users)))
-	return users, nil
+	if query.Email != "" {
+		add("email ILIKE $%d", "%"+query.Email+"%")
+	}
+	if query.EmailDomain != "" {
+		add("LOWER(SPLIT_PART(email, '@', 2)) = LOWER($%d)", query.EmailDomain)
+	}
+	if query.Phone != "" {
+		add("phone = $%d", query.Phone)
+	}
+	if query.Status != "" {
+		add("status = $%d", query.Status)
+	}
+	if query.EmailVerified != nil {
+		add("email_verified = $%d", *query.EmailVerified)
+	}
+	if query.PhoneVerified != nil {
+		add("phone_verified = $%d", *query.PhoneVerified)
+	}
+	if query.KYCStatus != "" {
+		add("EXISTS (SELECT 1 FROM kyc_verifications k WHERE k.user_id = users.id AND k.status::text = $%d)", string(query.KYCStatus))
+	}
+	if query.CreatedFrom != nil {
+		add("created_at >= $%d", *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		add("created_at < $%d", *query.CreatedTo)
+	}
+
+	return whereParts, args
 }
 
 // KYC Repository implementation
@@ -316,8 +435,8 @@ func NewPostgresKYCRepository(db *sqlx.DB, logger *zap.Logger) domain.KYCReposit
 
 func (r *PostgresKYCRepository) CreateKYCVerification(ctx context.Context, verification *domain.KYCVerification) error {
 	query := `
-		INSERT INTO kyc_verifications (id, user_id, verification_type, provider, status, provider_reference, verification_data, created_at, updated_at)
-		VALUES (:id, :user_id, :verification_type, :provider, :status, :provider_reference, :verification_data, :created_at, :updated_at)`
+		INSERT INTO kyc_verifications (id, user_id, verification_type, provider, status, provider_reference, verification_data, expires_at, created_at, updated_at)
+		VALUES (:id, :user_id, :verification_type, :provider, :status, :provider_reference, :verification_data, :expires_at, :created_at, :updated_at)`
 
 	_, err := r.db.NamedExecContext(ctx, query, verification)
 	if err != nil {
@@ -332,7 +451,7 @@ func (r *PostgresKYCRepository) CreateKYCVerification(ctx context.Context, verif
 func (r *PostgresKYCRepository) GetKYCVerification(ctx context.Context, userID, verificationType string) (*domain.KYCVerification, error) {
 	var verification domain.KYCVerification
 	query := `
-		SELECT id, user_id, verification_type, provider, status, provider_reference, verification_data, created_at, updated_at
+		SELECT id, user_id, verification_type, provider, status, provider_reference, verification_data, expires_at, created_at, updated_at
 		FROM kyc_verifications 
 		WHERE user_id = $1 AND verification_type = $2
 		ORDER BY created_at DESC
@@ -398,7 +517,7 @@ func (r *PostgresKYCRepository) UpdateKYCVerification(ctx context.Context, verif
 func (r *PostgresKYCRepository) ListKYCVerifications(ctx context.Context, userID string) ([]*domain.KYCVerification, error) {
 	var verifications []*domain.KYCVerification
 	query := `
-		SELECT id, user_id, verification_type, provider, status, provider_reference, verification_data, created_at, updated_at
+		SELECT id, user_id, verification_type, provider, status, provider_reference, verification_data, expires_at, created_at, updated_at
 		FROM kyc_verifications 
 		WHERE user_id = $1
 		ORDER BY created_at DESC`
@@ -466,6 +585,24 @@ func (r *PostgresKYCRepository) UpdateKYCStatus(ctx context.Context, userID, ver
 	return nil
 }
 
+func (r *PostgresKYCRepository) ListExpiredVerifications(ctx context.Context, asOf time.Time, afterID string, limit int) ([]*domain.KYCVerification, error) {
+	var verifications []*domain.KYCVerification
+	query := `
+		SELECT id, user_id, verification_type, provider, status, provider_reference, verification_data, expires_at, created_at, updated_at
+		FROM kyc_verifications
+		WHERE status = 'verified' AND expires_at <= $1 AND id::text > $2
+		ORDER BY id::text
+		LIMIT $3`
+
+	err := r.db.SelectContext(ctx, &verifications, query, asOf, afterID, limit)
+	if err != nil {
+		r.logger.Error("Failed to list expired KYC verifications", zap.Error(err))
+		return nil, fmt.Errorf("failed to list expired KYC verifications: %w", err)
+	}
+
+	return verifications, nil
+}
+
 // Document Repository implementation
 
 type PostgresDocumentRepository struct {
@@ -498,7 +635,7 @@ func (r *PostgresDocumentRepository) CreateDocument(ctx context.Context, documen
 func (r *PostgresDocumentRepository) GetDocument(ctx context.Context, documentID string) (*domain.Document, error) {
 	var document domain.Document
 	query := `
-		SELECT id, user_id, document_type, file_path, file_size, mime_type, encryption_key, upload_ip, created_at
+		SELECT id, user_id, document_type, file_path, file_size, mime_type, encryption_key, upload_ip, created_at, purged_at
 		FROM user_documents 
 		WHERE id = $1`
 
@@ -519,7 +656,7 @@ func (r *PostgresDocumentRepository) GetDocumentsByUserID(ctx context.Context, u
 	query := `
 		SELECT id, user_id, document_type, file_path, file_size, mime_type, encryption_key, upload_ip, created_at
 		FROM user_documents 
-		WHERE user_id = $1
+		WHERE user_id = $1 AND purged_at IS NULL
 		ORDER BY created_at DESC`
 
 	err := r.db.SelectContext(ctx, &documents, query, userID)
@@ -532,12 +669,78 @@ func (r *PostgresDocumentRepository) GetDocumentsByUserID(ctx context.Context, u
 	return documents, nil
 }
 
+// ListDocuments returns all documents ordered for stable batch iteration
+func (r *PostgresDocumentRepository) ListDocuments(ctx context.Context, offset, limit int) ([]*domain.Document, error) {
+	var documents []*domain.Document
+	query := `
+		SELECT id, user_id, document_type, file_path, file_size, mime_type, encryption_key, upload_ip, created_at
+		FROM user_documents 
+		ORDER BY id
+		LIMIT $1 OFFSET $2`
+
+	err := r.db.SelectContext(ctx, &documents, query, limit, offset)
+	if err != nil {
+		r.logger.Error("Failed to list documents", zap.Error(err))
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	return documents, nil
+}
+
+// ListRetentionCandidates returns live documents of a type uploaded before the cutoff, skipping users on legal hold
+func (r *PostgresDocumentRepository) ListRetentionCandidates(ctx context.Context, documentType string, uploadedBefore time.Time, afterID string, limit int) ([]*domain.Document, error) {
+	var documents []*domain.Document
+	query := `
+		SELECT d.id, d.user_id, d.document_type, d.file_path, d.file_size, d.mime_type, d.encryption_key, d.upload_ip, d.created_at
+		FROM user_documents d
+		JOIN users u ON u.id = d.user_id
+		WHERE d.document_type = $1 AND d.created_at < $2 AND d.purged_at IS NULL
+			AND NOT u.legal_hold AND d.id::text > $3
+		ORDER BY d.id
+		LIMIT $4`
+
+	err := r.db.SelectContext(ctx, &documents, query, documentType, uploadedBefore, afterID, limit)
+	if err != nil {
+		r.logger.Error("Failed to list retention candidates", zap.Error(err), zap.String("document_type", documentType))
+		return nil, fmt.Errorf("failed to list retention candidates: %w", err)
+	}
+
+	return documents, nil
+}
+
+// TombstoneDocument marks a document purged and clears its storage location and key
+func (r *PostgresDocumentRepository) TombstoneDocument(ctx context.Context, documentID, reason string) error {
+	query := `
+		UPDATE user_documents
+		SET purged_at = NOW(), purge_reason = $2, file_path = '', encryption_key = ''
+		WHERE id = $1 AND purged_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, documentID, reason)
+	if err != nil {
+		r.logger.Error("Failed to tombstone document", zap.Error(err), zap.String("document_id", documentID))
+		return fmt.Errorf("failed to tombstone document: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Error("Failed to get rows affected", zap.Error(err))
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("document not found")
+	}
+
+	r.logger.Info("Document tombstoned", zap.String("document_id", documentID), zap.String("reason", reason))
+	return nil
+}
+
 func (r *PostgresDocumentRepository) GetDocumentsByType(ctx context.Context, userID, documentType string) ([]*domain.Document, error) {
 	var documents []*domain.Document
 	query := `
 		SELECT id, user_id, document_type, file_path, file_size, mime_type, encryption_key, upload_ip, created_at
 		FROM user_documents 
-		WHERE user_id = $1 AND document_type = $2
+		WHERE user_id = $1 AND document_type = $2 AND purged_at IS NULL
 		ORDER BY created_at DESC`
 
 	err := r.db.SelectContext(ctx, &documents, query, userID, documentType)
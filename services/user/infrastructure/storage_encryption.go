@@ -389,6 +389,19 @@ func (e *AESEncryptionService) RotateKey(oldKey string) (string, error) {
 	return e.GenerateKey()
 }
 
+// KeyVersion returns an empty version as the legacy service has a single unversioned key
+func (e *AESEncryptionService) KeyVersion(ciphertext string) string {
+	return ""
+}
+
+func (e *AESEncryptionService) NeedsReEncryption(ciphertext string) bool {
+	return false
+}
+
+func (e *AESEncryptionService) ReEncryptField(ciphertext string) (string, error) {
+	return ciphertext, nil
+}
+
 // MockKYCProviderService implements a mock KYC provider for testing/development
 type MockKYCProviderService struct {
 	providerName string
@@ -0,0 +1,129 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// Token Vault Repository implementation
+
+type PostgresTokenVaultRepository struct {
+	db     *sqlx.DB
+	logger *zap.Logger
+}
+
+func NewPostgresTokenVaultRepository(db *sqlx.DB, logger *zap.Logger) domain.TokenVaultRepository {
+	return &PostgresTokenVaultRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *PostgresTokenVaultRepository) CreateToken(ctx context.Context, token *domain.PIIToken) error {
+	query := `
+		INSERT INTO pii_tokens (id, token, token_type, value_hash, value_encrypted, last4, created_at, updated_at)
+		VALUES (:id, :token, :token_type, :value_hash, :value_encrypted, :last4, :created_at, :updated_at)`
+
+	_, err := r.db.NamedExecContext(ctx, query, token)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation && pqErr.Constraint == "idx_pii_tokens_type_hash" {
+			return domain.ErrTokenExists
+		}
+		r.logger.Error("Failed to create token", zap.Error(err), zap.String("token_type", string(token.TokenType)))
+		return fmt.Errorf("failed to create token: %w", err)
+	}
+
+	r.logger.Info("Token created successfully", zap.String("token_id", token.ID))
+	return nil
+}
+
+func (r *PostgresTokenVaultRepository) GetToken(ctx context.Context, token string) (*domain.PIIToken, error) {
+	var piiToken domain.PIIToken
+	query := `
+		SELECT id, token, token_type, value_hash, value_encrypted, last4, created_at, updated_at
+		FROM pii_tokens
+		WHERE token = $1`
+
+	err := r.db.GetContext(ctx, &piiToken, query, token)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("not found")
+		}
+		r.logger.Error("Failed to get token", zap.Error(err))
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+
+	return &piiToken, nil
+}
+
+// GetTokenByValueHash returns the token already issued for a value, or nil if none exists
+func (r *PostgresTokenVaultRepository) GetTokenByValueHash(ctx context.Context, tokenType domain.TokenType, valueHash string) (*domain.PIIToken, error) {
+	var piiToken domain.PIIToken
+	query := `
+		SELECT id, token, token_type, value_hash, value_encrypted, last4, created_at, updated_at
+		FROM pii_tokens
+		WHERE token_type = $1 AND value_hash = $2`
+
+	err := r.db.GetContext(ctx, &piiToken, query, tokenType, valueHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get token by value hash", zap.Error(err))
+		return nil, fmt.Errorf("failed to get token by value hash: %w", err)
+	}
+
+	return &piiToken, nil
+}
+
+func (r *PostgresTokenVaultRepository) UpdateTokenValue(ctx context.Context, token, valueEncrypted string) error {
+	query := `
+		UPDATE pii_tokens
+		SET value_encrypted = $1, updated_at = NOW()
+		WHERE token = $2`
+
+	result, err := r.db.ExecContext(ctx, query, valueEncrypted, token)
+	if err != nil {
+		r.logger.Error("Failed to update token value", zap.Error(err))
+		return fmt.Errorf("failed to update token value: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Error("Failed to get rows affected", zap.Error(err))
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("not found")
+	}
+
+	return nil
+}
+
+// ListTokens returns vault entries ordered for stable batch iteration
+func (r *PostgresTokenVaultRepository) ListTokens(ctx context.Context, offset, limit int) ([]*domain.PIIToken, error) {
+	var tokens []*domain.PIIToken
+	query := `
+		SELECT id, token, token_type, value_hash, value_encrypted, last4, created_at, updated_at
+		FROM pii_tokens
+		ORDER BY id
+		LIMIT $1 OFFSET $2`
+
+	err := r.db.SelectContext(ctx, &tokens, query, limit, offset)
+	if err != nil {
+		r.logger.Error("Failed to list tokens", zap.Error(err))
+		return nil, fmt.Errorf("failed to list tokens: %w", err)
+	}
+
+	return tokens, nil
+}
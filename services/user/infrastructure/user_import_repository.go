@@ -0,0 +1,201 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// pqUniqueViolation is the Postgres error code for a unique constraint violation
+const pqUniqueViolation = "23505"
+
+const userImportColumns = `id, status, format, storage_key, submitted_by, total_rows, created_rows, updated_rows,
+	failed_rows, errors, error_message, created_at, started_at, completed_at, updated_at`
+
+// User Import Repository implementation
+
+type PostgresUserImportRepository struct {
+	db     *sqlx.DB
+	logger *zap.Logger
+}
+
+func NewPostgresUserImportRepository(db *sqlx.DB, logger *zap.Logger) domain.UserImportRepository {
+	return &PostgresUserImportRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *PostgresUserImportRepository) CreateImport(ctx context.Context, userImport *domain.UserImport) error {
+	query := `
+		INSERT INTO user_imports (id, status, format, storage_key, submitted_by, errors, created_at, updated_at)
+		VALUES (:id, :status, :format, :storage_key, :submitted_by, :errors, :created_at, :updated_at)`
+
+	_, err := r.db.NamedExecContext(ctx, query, userImport)
+	if err != nil {
+		r.logger.Error("Failed to create user import", zap.Error(err), zap.String("import_id", userImport.ID))
+		return fmt.Errorf("failed to create user import: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresUserImportRepository) GetImport(ctx context.Context, importID string) (*domain.UserImport, error) {
+	var userImport domain.UserImport
+	query := `SELECT ` + userImportColumns + ` FROM user_imports WHERE id = $1`
+
+	if err := r.db.GetContext(ctx, &userImport, query, importID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("not found")
+		}
+		r.logger.Error("Failed to get user import", zap.Error(err), zap.String("import_id", importID))
+		return nil, fmt.Errorf("failed to get user import: %w", err)
+	}
+
+	return &userImport, nil
+}
+
+func (r *PostgresUserImportRepository) UpdateImport(ctx context.Context, userImport *domain.UserImport) error {
+	query := `
+		UPDATE user_imports
+		SET status = :status, total_rows = :total_rows, created_rows = :created_rows, updated_rows = :updated_rows,
+			failed_rows = :failed_rows, errors = :errors, error_message = :error_message,
+			completed_at = :completed_at, updated_at = :updated_at
+		WHERE id = :id`
+
+	_, err := r.db.NamedExecContext(ctx, query, userImport)
+	if err != nil {
+		r.logger.Error("Failed to update user import", zap.Error(err), zap.String("import_id", userImport.ID))
+		return fmt.Errorf("failed to update user import: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimImport uses SKIP LOCKED so concurrent workers never claim the same import. Progress updates
+// bump updated_at, so only an import whose worker died is old enough to be reclaimed.
+func (r *PostgresUserImportRepository) ClaimImport(ctx context.Context, staleAfter time.Duration) (*domain.UserImport, error) {
+	query := `
+		UPDATE user_imports
+		SET status = 'processing', started_at = COALESCE(started_at, NOW()), updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM user_imports
+			WHERE status = 'pending' OR (status = 'processing' AND updated_at < $1)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + userImportColumns
+
+	var userImport domain.UserImport
+	if err := r.db.GetContext(ctx, &userImport, query, time.Now().Add(-staleAfter)); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to claim user import", zap.Error(err))
+		return nil, fmt.Errorf("failed to claim user import: %w", err)
+	}
+
+	return &userImport, nil
+}
+
+// UpsertImportedUser is idempotent: replaying the same row updates the user in place. Credentials, status
+// and verification flags of an existing user are left alone, except that a changed email is no longer verified.
+func (r *PostgresUserImportRepository) UpsertImportedUser(ctx context.Context, user *domain.User, profile *domain.UserProfile) (domain.ImportOutcome, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin import transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// users.email is unique, so an email held by a different borrower can't be taken over; emails
+	// differing only in case or surrounding spaces belong to the same person
+	var conflicts int
+	err = tx.GetContext(ctx, &conflicts,
+		`SELECT COUNT(*) FROM users WHERE LOWER(TRIM(email)) = LOWER(TRIM($1)) AND external_id IS DISTINCT FROM $2`,
+		user.Email, user.ExternalID,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to check email conflict: %w", err)
+	}
+	if conflicts > 0 {
+		return domain.ImportOutcomeEmailConflict, nil
+	}
+
+	userQuery := `
+		INSERT INTO users (id, external_id, email, email_normalized, password_hash, phone, phone_normalized,
+			email_verified, phone_verified, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), FALSE, FALSE, $8, $9, $9)
+		ON CONFLICT (external_id) WHERE external_id IS NOT NULL DO UPDATE
+		SET email = EXCLUDED.email,
+			email_normalized = EXCLUDED.email_normalized,
+			email_verified = users.email_verified AND users.email = EXCLUDED.email,
+			phone = EXCLUDED.phone,
+			phone_normalized = EXCLUDED.phone_normalized,
+			phone_verified = users.phone_verified AND users.phone IS NOT DISTINCT FROM EXCLUDED.phone,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, invite_sent_at, (xmax = 0) AS inserted`
+
+	var result struct {
+		ID           string     `db:"id"`
+		InviteSentAt *time.Time `db:"invite_sent_at"`
+		Inserted     bool       `db:"inserted"`
+	}
+	err = tx.GetContext(ctx, &result, userQuery,
+		user.ID, user.ExternalID, user.Email, user.EmailNormalized, user.PasswordHash,
+		user.Phone, user.PhoneNormalized, user.Status, user.UpdatedAt,
+	)
+	if err != nil {
+		// A concurrent insert can still win the race for the email
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation && pqErr.Constraint == "users_email_key" {
+			return domain.ImportOutcomeEmailConflict, nil
+		}
+		r.logger.Error("Failed to upsert imported user", zap.Error(err), zap.String("external_id", user.ExternalID))
+		return "", fmt.Errorf("failed to upsert imported user: %w", err)
+	}
+	user.ID = result.ID
+	user.InviteSentAt = result.InviteSentAt
+	profile.UserID = result.ID
+
+	// Fields missing from the row keep their stored values
+	var dateOfBirth *time.Time
+	if !profile.DateOfBirth.IsZero() {
+		dateOfBirth = &profile.DateOfBirth
+	}
+	profileQuery := `
+		INSERT INTO user_profiles (id, user_id, first_name, last_name, date_of_birth, phone, address, employment_info, financial_info, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10, $10)
+		ON CONFLICT (user_id) DO UPDATE
+		SET first_name = EXCLUDED.first_name,
+			last_name = EXCLUDED.last_name,
+			date_of_birth = COALESCE(EXCLUDED.date_of_birth, user_profiles.date_of_birth),
+			phone = COALESCE(EXCLUDED.phone, user_profiles.phone),
+			updated_at = EXCLUDED.updated_at`
+
+	_, err = tx.ExecContext(ctx, profileQuery,
+		profile.ID, profile.UserID, profile.FirstName, profile.LastName, dateOfBirth, profile.Phone,
+		profile.Address, profile.EmploymentInfo, profile.FinancialInfo, profile.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to upsert imported profile", zap.Error(err), zap.String("external_id", user.ExternalID))
+		return "", fmt.Errorf("failed to upsert imported profile: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit imported user: %w", err)
+	}
+
+	if result.Inserted {
+		return domain.ImportOutcomeCreated, nil
+	}
+	return domain.ImportOutcomeUpdated, nil
+}
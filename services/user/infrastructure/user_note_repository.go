@@ -0,0 +1,55 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// User Note Repository implementation
+
+type PostgresUserNoteRepository struct {
+	db     *sqlx.DB
+	logger *zap.Logger
+}
+
+func NewPostgresUserNoteRepository(db *sqlx.DB, logger *zap.Logger) domain.UserNoteRepository {
+	return &PostgresUserNoteRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *PostgresUserNoteRepository) CreateNote(ctx context.Context, note *domain.UserNote) error {
+	query := `
+		INSERT INTO user_notes (id, user_id, author_id, note, created_at)
+		VALUES (:id, :user_id, :author_id, :note, :created_at)`
+
+	_, err := r.db.NamedExecContext(ctx, query, note)
+	if err != nil {
+		r.logger.Error("Failed to create user note", zap.Error(err), zap.String("user_id", note.UserID))
+		return fmt.Errorf("failed to create user note: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresUserNoteRepository) ListNotes(ctx context.Context, userID string) ([]*domain.UserNote, error) {
+	notes := make([]*domain.UserNote, 0)
+	query := `
+		SELECT id, user_id, author_id, note, created_at
+		FROM user_notes
+		WHERE user_id = $1
+		ORDER BY created_at DESC`
+
+	if err := r.db.SelectContext(ctx, &notes, query, userID); err != nil {
+		r.logger.Error("Failed to list user notes", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to list user notes: %w", err)
+	}
+
+	return notes, nil
+}
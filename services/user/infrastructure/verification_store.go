@@ -0,0 +1,206 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// checkCodeScript counts the attempt before comparing, so every guess is counted however many
+// arrive at once, and only touches a code that still exists, so a late guess cannot recreate an
+// expired code without a TTL. ARGV holds the submitted hash, the maximum attempts and the time.
+var checkCodeScript = redis.NewScript(`
+local stored = redis.call("HMGET", KEYS[1], "code_hash", "expires_at")
+if not stored[1] then
+	return "missing"
+end
+if tonumber(stored[2] or "0") <= tonumber(ARGV[3]) then
+	redis.call("DEL", KEYS[1])
+	return "missing"
+end
+local attempts = redis.call("HINCRBY", KEYS[1], "attempts", 1)
+if attempts > tonumber(ARGV[2]) then
+	redis.call("DEL", KEYS[1])
+	return "locked"
+end
+if stored[1] == ARGV[1] then
+	redis.call("DEL", KEYS[1])
+	return "verified"
+end
+if attempts >= tonumber(ARGV[2]) then
+	redis.call("DEL", KEYS[1])
+	return "locked"
+end
+return "mismatch"
+`)
+
+// acquireSendQuotaScript checks every fixed-window counter before incrementing any of them, so a
+// rejected send does not use up quota. KEYS holds one counter per limit and ARGV holds each limit's
+// maximum and window in milliseconds. Returns {0, 0} when allowed, or the 1-based index of the
+// exhausted limit and the milliseconds until its window resets.
+var acquireSendQuotaScript = redis.NewScript(`
+for i, key in ipairs(KEYS) do
+	local count = tonumber(redis.call("GET", key) or "0")
+	if count >= tonumber(ARGV[2 * i - 1]) then
+		local ttl = redis.call("PTTL", key)
+		if ttl < 0 then
+			ttl = tonumber(ARGV[2 * i])
+		end
+		return {i, ttl}
+	end
+end
+for i, key in ipairs(KEYS) do
+	if redis.call("INCR", key) == 1 then
+		redis.call("PEXPIRE", key, ARGV[2 * i])
+	end
+end
+return {0, 0}
+`)
+
+// RedisVerificationCodeStore keeps hashed verification codes in Redis with a TTL
+type RedisVerificationCodeStore struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+func NewRedisVerificationCodeStore(client *redis.Client, logger *zap.Logger) domain.VerificationCodeStore {
+	return &RedisVerificationCodeStore{
+		client: client,
+		logger: logger,
+	}
+}
+
+func verificationCodeKey(channel domain.VerificationChannel, userID string) string {
+	return fmt.Sprintf("verification_code:%s:%s", channel, userID)
+}
+
+func verificationCooldownKey(channel domain.VerificationChannel, userID string) string {
+	return fmt.Sprintf("verification_cooldown:%s:%s", channel, userID)
+}
+
+// verificationRateKey keys a send counter by limit; destinations are hashed so addresses stay out of Redis keys
+func verificationRateKey(channel domain.VerificationChannel, limit domain.VerificationRateLimit, userID, destination string) string {
+	subject := userID
+	if limit.Scope == domain.VerificationRateScopeDestination {
+		sum := sha256.Sum256([]byte(destination))
+		subject = hex.EncodeToString(sum[:16])
+	}
+	return fmt.Sprintf("verification_rate:%s:%s:%s:%d", channel, limit.Scope, subject, int64(limit.Window/time.Second))
+}
+
+func (r *RedisVerificationCodeStore) SaveCode(ctx context.Context, channel domain.VerificationChannel, userID string, code *domain.VerificationCode) error {
+	key := verificationCodeKey(channel, userID)
+	ttl := time.Until(code.ExpiresAt)
+	if ttl <= 0 {
+		return fmt.Errorf("verification code already expired")
+	}
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key,
+			"code_hash", code.CodeHash,
+			"attempts", code.Attempts,
+			"expires_at", code.ExpiresAt.Unix(),
+		)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("Failed to save verification code", zap.Error(err), zap.String("user_id", userID))
+		return fmt.Errorf("failed to save verification code: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RedisVerificationCodeStore) CheckCode(ctx context.Context, channel domain.VerificationChannel, userID, codeHash string, maxAttempts int) (domain.VerificationCheckResult, error) {
+	result, err := checkCodeScript.Run(ctx, r.client, []string{verificationCodeKey(channel, userID)},
+		codeHash, maxAttempts, time.Now().Unix()).Text()
+	if err != nil {
+		r.logger.Error("Failed to check verification code", zap.Error(err), zap.String("user_id", userID))
+		return "", fmt.Errorf("failed to check verification code: %w", err)
+	}
+
+	return domain.VerificationCheckResult(result), nil
+}
+
+func (r *RedisVerificationCodeStore) DeleteCode(ctx context.Context, channel domain.VerificationChannel, userID string) error {
+	if err := r.client.Del(ctx, verificationCodeKey(channel, userID)).Err(); err != nil {
+		r.logger.Error("Failed to delete verification code", zap.Error(err), zap.String("user_id", userID))
+		return fmt.Errorf("failed to delete verification code: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RedisVerificationCodeStore) AcquireResendCooldown(ctx context.Context, channel domain.VerificationChannel, userID string, cooldown time.Duration) (time.Duration, error) {
+	if cooldown <= 0 {
+		return 0, nil
+	}
+
+	key := verificationCooldownKey(channel, userID)
+	acquired, err := r.client.SetNX(ctx, key, 1, cooldown).Result()
+	if err != nil {
+		r.logger.Error("Failed to acquire resend cooldown", zap.Error(err), zap.String("user_id", userID))
+		return 0, fmt.Errorf("failed to acquire resend cooldown: %w", err)
+	}
+	if acquired {
+		return 0, nil
+	}
+
+	remaining, err := r.client.PTTL(ctx, key).Result()
+	if err != nil || remaining <= 0 {
+		// The cooldown is in force either way; fall back to its full length
+		return cooldown, nil
+	}
+
+	return remaining, nil
+}
+
+func (r *RedisVerificationCodeStore) ReleaseResendCooldown(ctx context.Context, channel domain.VerificationChannel, userID string) error {
+	if err := r.client.Del(ctx, verificationCooldownKey(channel, userID)).Err(); err != nil {
+		r.logger.Error("Failed to release resend cooldown", zap.Error(err), zap.String("user_id", userID))
+		return fmt.Errorf("failed to release resend cooldown: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RedisVerificationCodeStore) AcquireSendQuota(ctx context.Context, channel domain.VerificationChannel, userID, destination string, limits []domain.VerificationRateLimit) (*domain.VerificationRateLimit, time.Duration, error) {
+	active := make([]domain.VerificationRateLimit, 0, len(limits))
+	keys := make([]string, 0, len(limits))
+	args := make([]interface{}, 0, 2*len(limits))
+	for _, limit := range limits {
+		if limit.Limit <= 0 || limit.Window <= 0 {
+			continue
+		}
+		if limit.Scope == domain.VerificationRateScopeDestination && destination == "" {
+			continue
+		}
+		active = append(active, limit)
+		keys = append(keys, verificationRateKey(channel, limit, userID, destination))
+		args = append(args, limit.Limit, limit.Window.Milliseconds())
+	}
+	if len(active) == 0 {
+		return nil, 0, nil
+	}
+
+	result, err := acquireSendQuotaScript.Run(ctx, r.client, keys, args...).Int64Slice()
+	if err != nil {
+		r.logger.Error("Failed to acquire verification send quota", zap.Error(err), zap.String("user_id", userID))
+		return nil, 0, fmt.Errorf("failed to acquire verification send quota: %w", err)
+	}
+	if len(result) != 2 || result[0] == 0 {
+		return nil, 0, nil
+	}
+
+	exhausted := active[result[0]-1]
+	return &exhausted, time.Duration(result[1]) * time.Millisecond, nil
+}
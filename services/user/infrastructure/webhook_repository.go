@@ -0,0 +1,212 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// Webhook Repository implementation
+
+type PostgresWebhookRepository struct {
+	db     *sqlx.DB
+	logger *zap.Logger
+}
+
+func NewPostgresWebhookRepository(db *sqlx.DB, logger *zap.Logger) domain.WebhookRepository {
+	return &PostgresWebhookRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const webhookDeliveryColumns = `id, endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+	COALESCE(last_error, '') AS last_error, COALESCE(response_status, 0) AS response_status, delivered_at, created_at, updated_at`
+
+func (r *PostgresWebhookRepository) CreateEndpoint(ctx context.Context, endpoint *domain.WebhookEndpoint) error {
+	query := `
+		INSERT INTO webhook_endpoints (id, url, description, events, active, secret_encrypted, created_at, updated_at)
+		VALUES (:id, :url, :description, :events, :active, :secret_encrypted, :created_at, :updated_at)`
+
+	_, err := r.db.NamedExecContext(ctx, query, endpoint)
+	if err != nil {
+		r.logger.Error("Failed to create webhook endpoint", zap.Error(err), zap.String("endpoint_id", endpoint.ID))
+		return fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresWebhookRepository) GetEndpoint(ctx context.Context, endpointID string) (*domain.WebhookEndpoint, error) {
+	var endpoint domain.WebhookEndpoint
+	query := `
+		SELECT id, url, COALESCE(description, '') AS description, events, active, secret_encrypted, created_at, updated_at
+		FROM webhook_endpoints
+		WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &endpoint, query, endpointID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("not found")
+		}
+		r.logger.Error("Failed to get webhook endpoint", zap.Error(err), zap.String("endpoint_id", endpointID))
+		return nil, fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+
+	return &endpoint, nil
+}
+
+func (r *PostgresWebhookRepository) ListEndpoints(ctx context.Context) ([]*domain.WebhookEndpoint, error) {
+	var endpoints []*domain.WebhookEndpoint
+	query := `
+		SELECT id, url, COALESCE(description, '') AS description, events, active, secret_encrypted, created_at, updated_at
+		FROM webhook_endpoints
+		ORDER BY created_at DESC`
+
+	if err := r.db.SelectContext(ctx, &endpoints, query); err != nil {
+		r.logger.Error("Failed to list webhook endpoints", zap.Error(err))
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+
+	return endpoints, nil
+}
+
+func (r *PostgresWebhookRepository) ListEndpointsForEvent(ctx context.Context, eventType string) ([]*domain.WebhookEndpoint, error) {
+	var endpoints []*domain.WebhookEndpoint
+	query := `
+		SELECT id, url, COALESCE(description, '') AS description, events, active, secret_encrypted, created_at, updated_at
+		FROM webhook_endpoints
+		WHERE active = TRUE AND events @> jsonb_build_array($1::text)`
+
+	if err := r.db.SelectContext(ctx, &endpoints, query, eventType); err != nil {
+		r.logger.Error("Failed to list webhook endpoints for event", zap.Error(err), zap.String("event_type", eventType))
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+
+	return endpoints, nil
+}
+
+func (r *PostgresWebhookRepository) DisableEndpoint(ctx context.Context, endpointID string) error {
+	query := `UPDATE webhook_endpoints SET active = FALSE, updated_at = NOW() WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, endpointID)
+	if err != nil {
+		r.logger.Error("Failed to disable webhook endpoint", zap.Error(err), zap.String("endpoint_id", endpointID))
+		return fmt.Errorf("failed to disable webhook endpoint: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.New("not found")
+	}
+
+	return nil
+}
+
+func (r *PostgresWebhookRepository) CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at, updated_at)
+		VALUES (:id, :endpoint_id, :event_id, :event_type, :payload, :status, :attempts, :next_attempt_at, :created_at, :updated_at)`
+
+	_, err := r.db.NamedExecContext(ctx, query, delivery)
+	if err != nil {
+		r.logger.Error("Failed to create webhook delivery", zap.Error(err), zap.String("endpoint_id", delivery.EndpointID))
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresWebhookRepository) GetDelivery(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &delivery, query, deliveryID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("not found")
+		}
+		r.logger.Error("Failed to get webhook delivery", zap.Error(err), zap.String("delivery_id", deliveryID))
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return &delivery, nil
+}
+
+func (r *PostgresWebhookRepository) ListDeliveries(ctx context.Context, query *domain.WebhookDeliveryQuery) ([]*domain.WebhookDelivery, error) {
+	conditions := []string{"1=1"}
+	args := []interface{}{}
+
+	if query.EndpointID != "" {
+		args = append(args, query.EndpointID)
+		conditions = append(conditions, fmt.Sprintf("endpoint_id = $%d", len(args)))
+	}
+	if query.Status != "" {
+		args = append(args, query.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	args = append(args, query.Limit)
+
+	sqlQuery := fmt.Sprintf(`SELECT %s FROM webhook_deliveries WHERE %s ORDER BY created_at DESC LIMIT $%d`,
+		webhookDeliveryColumns,
+		strings.Join(conditions, " AND "),
+		len(args),
+	)
+
+	var deliveries []*domain.WebhookDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, sqlQuery, args...); err != nil {
+		r.logger.Error("Failed to list webhook deliveries", zap.Error(err))
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *PostgresWebhookRepository) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = :status, attempts = :attempts, next_attempt_at = :next_attempt_at, last_error = :last_error,
+			response_status = :response_status, delivered_at = :delivered_at, updated_at = :updated_at
+		WHERE id = :id`
+
+	_, err := r.db.NamedExecContext(ctx, query, delivery)
+	if err != nil {
+		r.logger.Error("Failed to update webhook delivery", zap.Error(err), zap.String("delivery_id", delivery.ID))
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = NOW() + $2 * INTERVAL '1 second', updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+
+	var deliveries []*domain.WebhookDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, query, limit, int(lease.Seconds())); err != nil {
+		r.logger.Error("Failed to claim webhook deliveries", zap.Error(err))
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,110 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// Webhook request headers
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// HTTPWebhookSender posts webhook payloads signed with the endpoint secret.
+// The signature header has the form "t=<unix>,v1=<hex HMAC-SHA256 of "<t>.<body>">", so receivers
+// can reject replays by checking the timestamp.
+// Only https URLs are sent to, and connections are refused to any resolved address that is not
+// public, so a hostname re-pointed after registration cannot reach internal services. Redirects
+// are not followed.
+type HTTPWebhookSender struct {
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+func NewHTTPWebhookSender(timeout time.Duration, logger *zap.Logger) domain.WebhookSender {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: refuseNonPublicAddress,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &HTTPWebhookSender{
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		logger: logger,
+	}
+}
+
+func (s *HTTPWebhookSender) Send(ctx context.Context, endpointURL, secret string, delivery *domain.WebhookDelivery) (int, error) {
+	parsed, err := url.Parse(endpointURL)
+	if err != nil || parsed.Scheme != "https" {
+		return 0, fmt.Errorf("webhook url must use https")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpointURL, bytes.NewReader([]byte(delivery.Payload)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build webhook request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(WebhookSignatureHeader, fmt.Sprintf("t=%s,v1=%s", timestamp, signWebhookPayload(secret, timestamp, []byte(delivery.Payload))))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		s.logger.Debug("Webhook request failed", zap.String("delivery_id", delivery.ID), zap.Error(err))
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Drain a bounded amount so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	return resp.StatusCode, nil
+}
+
+// refuseNonPublicAddress runs after DNS resolution, just before each connection is made
+func refuseNonPublicAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid webhook address %q: %w", address, err)
+	}
+	if !domain.IsPublicWebhookAddress(net.ParseIP(host)) {
+		return fmt.Errorf("webhook address %s is not public", host)
+	}
+	return nil
+}
+
+func signWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,348 @@
+package interfaces
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+	"github.com/huuhoait/los-demo/services/user/interfaces/middleware"
+)
+
+// RegisterAdminRoutes registers admin routes; every route requires an access token whose role grants the permission
+func (h *UserHandler) RegisterAdminRoutes(router *gin.RouterGroup, rbac *middleware.RBACMiddleware) {
+	router.Use(rbac.Authenticate())
+
+	manageUsers := rbac.RequirePermission(middleware.PermissionManageUsers)
+	viewAudit := rbac.RequirePermission(middleware.PermissionViewAudit)
+	manageWebhooks := rbac.RequirePermission(middleware.PermissionManageWebhooks)
+
+	// Account lifecycle
+	router.POST("/users/:id/restore", manageUsers, h.RestoreUser)
+	router.POST("/users/:id/suspend", manageUsers, h.SuspendUser)
+	router.POST("/users/:id/reactivate", manageUsers, h.ReactivateUser)
+	router.POST("/users/:id/force-password-reset", manageUsers, h.ForcePasswordReset)
+	router.PUT("/users/:id/legal-hold", manageUsers, h.SetLegalHold)
+
+	// Duplicate detection and merge
+	router.GET("/users/:id/duplicates", manageUsers, h.GetDuplicates)
+	router.POST("/users/merge", manageUsers, h.MergeUsers)
+
+	// Annotations
+	router.POST("/users/:id/notes", manageUsers, h.AddUserNote)
+	router.GET("/users/:id/notes", manageUsers, h.GetUserNotes)
+
+	// History and audit
+	router.GET("/users/:id/kyc-history", viewAudit, h.GetKYCHistory)
+	router.GET("/users/:id/audit/export", viewAudit, h.ExportAuditHistory)
+	router.GET("/audit/events", viewAudit, h.QueryAuditEvents)
+	router.GET("/audit/verify", viewAudit, h.VerifyAuditChain)
+
+	// Webhook administration
+	router.POST("/webhooks", manageWebhooks, h.RegisterWebhook)
+	router.GET("/webhooks", manageWebhooks, h.ListWebhooks)
+	router.DELETE("/webhooks/:webhook_id", manageWebhooks, h.DisableWebhook)
+	router.GET("/webhooks/deliveries", manageWebhooks, h.ListWebhookDeliveries)
+	router.POST("/webhooks/deliveries/:delivery_id/replay", manageWebhooks, h.ReplayWebhookDelivery)
+}
+
+// Admin User Management Handlers
+
+func (h *UserHandler) SuspendUser(c *gin.Context) {
+	userID := c.Param("id")
+	logger := h.logger.With(
+		zap.String("operation", "suspend_user"),
+		zap.String("user_id", userID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	var request domain.SuspendUserRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Invalid request body", zap.Error(err))
+		h.respondValidationError(c, map[string]string{
+			"reason": "required",
+		})
+		return
+	}
+
+	user, err := h.userService.SuspendUser(c.Request.Context(), userID, c.GetString("user_id"), request.Reason)
+	if err != nil {
+		logger.Error("Failed to suspend user", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("User suspended successfully")
+	h.respondSuccess(c, http.StatusOK, user)
+}
+
+func (h *UserHandler) ReactivateUser(c *gin.Context) {
+	userID := c.Param("id")
+	logger := h.logger.With(
+		zap.String("operation", "reactivate_user"),
+		zap.String("user_id", userID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	user, err := h.userService.ReactivateUser(c.Request.Context(), userID, c.GetString("user_id"))
+	if err != nil {
+		logger.Error("Failed to reactivate user", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("User reactivated successfully")
+	h.respondSuccess(c, http.StatusOK, user)
+}
+
+func (h *UserHandler) ForcePasswordReset(c *gin.Context) {
+	userID := c.Param("id")
+	logger := h.logger.With(
+		zap.String("operation", "force_password_reset"),
+		zap.String("user_id", userID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	if err := h.userService.ForcePasswordReset(c.Request.Context(), userID, c.GetString("user_id")); err != nil {
+		logger.Error("Failed to force password reset", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("Password reset forced successfully")
+	h.respondSuccess(c, http.StatusAccepted, gin.H{
+		"user_id": userID,
+	})
+}
+
+func (h *UserHandler) RedeemPasswordReset(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "redeem_password_reset"),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	var request domain.RedeemPasswordResetRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Invalid request body", zap.Error(err))
+		h.respondValidationError(c, map[string]string{
+			"token":    "required",
+			"password": "required, at least 8 characters",
+		})
+		return
+	}
+
+	if err := h.userService.RedeemPasswordReset(c.Request.Context(), &request); err != nil {
+		logger.Error("Failed to redeem password reset", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("Password reset redeemed successfully")
+	h.respondSuccess(c, http.StatusOK, gin.H{
+		"password_reset": true,
+	})
+}
+
+func (h *UserHandler) SetLegalHold(c *gin.Context) {
+	userID := c.Param("id")
+	logger := h.logger.With(
+		zap.String("operation", "set_legal_hold"),
+		zap.String("user_id", userID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	var request domain.LegalHoldRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Invalid request body", zap.Error(err))
+		h.respondValidationError(c, map[string]string{
+			"hold": "required",
+		})
+		return
+	}
+
+	hold, err := h.userService.SetLegalHold(c.Request.Context(), userID, c.GetString("user_id"), &request)
+	if err != nil {
+		logger.Error("Failed to set legal hold", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("Legal hold updated", zap.Bool("hold", hold.Hold))
+	h.respondSuccess(c, http.StatusOK, hold)
+}
+
+func (h *UserHandler) GetKYCHistory(c *gin.Context) {
+	userID := c.Param("id")
+	logger := h.logger.With(
+		zap.String("operation", "get_kyc_history"),
+		zap.String("user_id", userID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	history, err := h.userService.GetKYCHistory(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error("Failed to get KYC history", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, history)
+}
+
+func (h *UserHandler) AddUserNote(c *gin.Context) {
+	userID := c.Param("id")
+	logger := h.logger.With(
+		zap.String("operation", "add_user_note"),
+		zap.String("user_id", userID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	var request domain.AnnotateUserRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Invalid request body", zap.Error(err))
+		h.respondValidationError(c, map[string]string{
+			"note": "required",
+		})
+		return
+	}
+
+	note, err := h.userService.AddUserNote(c.Request.Context(), userID, c.GetString("user_id"), request.Note)
+	if err != nil {
+		logger.Error("Failed to add user note", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("User note added successfully", zap.String("note_id", note.ID))
+	h.respondSuccess(c, http.StatusCreated, note)
+}
+
+func (h *UserHandler) GetUserNotes(c *gin.Context) {
+	userID := c.Param("id")
+	logger := h.logger.With(
+		zap.String("operation", "get_user_notes"),
+		zap.String("user_id", userID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	notes, err := h.userService.GetUserNotes(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error("Failed to get user notes", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, gin.H{
+		"notes": notes,
+		"count": len(notes),
+	})
+}
+
+// ExportAuditHistory streams the user's audit trail as a JSON or CSV attachment
+func (h *UserHandler) ExportAuditHistory(c *gin.Context) {
+	userID := c.Param("id")
+	logger := h.logger.With(
+		zap.String("operation", "export_audit_history"),
+		zap.String("user_id", userID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	format := c.DefaultQuery("format", domain.AuditExportJSON)
+	if format != domain.AuditExportJSON && format != domain.AuditExportCSV {
+		h.respondError(c, &domain.UserError{
+			Code:    domain.USER_042,
+			Message: h.localizer.Localize(c.Request.Context(), domain.USER_042, nil),
+			Field:   "format",
+		})
+		return
+	}
+
+	entries, err := h.userService.ExportAuditHistory(c.Request.Context(), userID, c.GetString("user_id"))
+	if err != nil {
+		logger.Error("Failed to export audit history", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("audit-%s-%s.%s", userID, time.Now().UTC().Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == domain.AuditExportJSON {
+		c.JSON(http.StatusOK, entries)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Status(http.StatusOK)
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"id", "user_id", "actor_id", "event_type", "category", "metadata", "created_at", "hash"})
+	for _, entry := range entries {
+		writer.Write([]string{
+			entry.ID,
+			entry.UserID,
+			entry.ActorID,
+			entry.EventType,
+			entry.Category,
+			string(entry.Metadata),
+			entry.CreatedAt.UTC().Format(time.RFC3339),
+			entry.Hash,
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		logger.Error("Failed to write audit export", zap.Error(err))
+	}
+}
+
+func (h *UserHandler) QueryAuditEvents(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "query_audit_events"),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	var query domain.AuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Error("Invalid query parameters", zap.Error(err))
+		h.respondValidationError(c, map[string]string{
+			"query": "invalid_format",
+		})
+		return
+	}
+
+	entries, err := h.userService.QueryAuditEvents(c.Request.Context(), c.GetString("user_id"), &query)
+	if err != nil {
+		logger.Error("Failed to query audit events", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, gin.H{
+		"events": entries,
+		"count":  len(entries),
+		"limit":  query.Limit,
+		"offset": query.Offset,
+	})
+}
+
+func (h *UserHandler) VerifyAuditChain(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "verify_audit_chain"),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	result, err := h.userService.VerifyAuditChain(c.Request.Context())
+	if err != nil {
+		logger.Error("Failed to verify audit chain", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	if !result.Valid {
+		logger.Error("Audit chain is broken", zap.Int64p("broken_at", result.BrokenAt))
+	}
+	h.respondSuccess(c, http.StatusOK, result)
+}
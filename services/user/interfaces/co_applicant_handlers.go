@@ -0,0 +1,196 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// Co-Applicant Handlers
+
+func (h *UserHandler) InviteCoApplicant(c *gin.Context) {
+	userID := c.Param("id")
+	logger := h.logger.With(
+		zap.String("operation", "invite_co_applicant"),
+		zap.String("user_id", userID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	var request domain.InviteCoApplicantRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Invalid request body", zap.Error(err))
+		h.respondValidationError(c, map[string]string{
+			"email":        "required",
+			"relationship": "required",
+		})
+		return
+	}
+
+	link, err := h.userService.InviteCoApplicant(c.Request.Context(), userID, &request)
+	if err != nil {
+		logger.Error("Failed to invite co-applicant", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("Co-applicant invited successfully", zap.String("link_id", link.ID))
+	h.respondSuccess(c, http.StatusCreated, link)
+}
+
+func (h *UserHandler) ListCoApplicants(c *gin.Context) {
+	userID := c.Param("id")
+	logger := h.logger.With(
+		zap.String("operation", "list_co_applicants"),
+		zap.String("user_id", userID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	links, err := h.userService.ListCoApplicants(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error("Failed to list co-applicants", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, gin.H{
+		"co_applicants": links,
+		"count":         len(links),
+	})
+}
+
+func (h *UserHandler) AcceptCoApplicantInvite(c *gin.Context) {
+	h.respondToCoApplicantInvite(c, "accept_co_applicant_invite", h.userService.AcceptCoApplicantInvite)
+}
+
+func (h *UserHandler) DeclineCoApplicantInvite(c *gin.Context) {
+	h.respondToCoApplicantInvite(c, "decline_co_applicant_invite", h.userService.DeclineCoApplicantInvite)
+}
+
+func (h *UserHandler) respondToCoApplicantInvite(c *gin.Context, operation string, respond func(ctx context.Context, userID, token string) (*domain.CoApplicantLink, error)) {
+	userID := c.Param("id")
+	logger := h.logger.With(
+		zap.String("operation", operation),
+		zap.String("user_id", userID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	var request domain.RespondCoApplicantInviteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Invalid request body", zap.Error(err))
+		h.respondValidationError(c, map[string]string{
+			"token": "required",
+		})
+		return
+	}
+
+	link, err := respond(c.Request.Context(), userID, request.Token)
+	if err != nil {
+		logger.Error("Failed to respond to co-applicant invite", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("Co-applicant invite answered", zap.String("link_id", link.ID), zap.String("status", string(link.Status)))
+	h.respondSuccess(c, http.StatusOK, link)
+}
+
+func (h *UserHandler) RevokeCoApplicantLink(c *gin.Context) {
+	userID := c.Param("id")
+	linkID := c.Param("link_id")
+	logger := h.logger.With(
+		zap.String("operation", "revoke_co_applicant_link"),
+		zap.String("user_id", userID),
+		zap.String("link_id", linkID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	link, err := h.userService.RevokeCoApplicantLink(c.Request.Context(), userID, linkID)
+	if err != nil {
+		logger.Error("Failed to revoke co-applicant link", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("Co-applicant link revoked successfully")
+	h.respondSuccess(c, http.StatusOK, link)
+}
+
+func (h *UserHandler) UpdateDocumentSharing(c *gin.Context) {
+	userID := c.Param("id")
+	linkID := c.Param("link_id")
+	logger := h.logger.With(
+		zap.String("operation", "update_document_sharing"),
+		zap.String("user_id", userID),
+		zap.String("link_id", linkID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	var request domain.UpdateDocumentSharingRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Invalid request body", zap.Error(err))
+		h.respondValidationError(c, map[string]string{
+			"document_types": "required",
+		})
+		return
+	}
+
+	link, err := h.userService.UpdateDocumentSharing(c.Request.Context(), userID, linkID, request.DocumentTypes)
+	if err != nil {
+		logger.Error("Failed to update document sharing", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("Document sharing updated successfully")
+	h.respondSuccess(c, http.StatusOK, link)
+}
+
+func (h *UserHandler) GetSharedDocuments(c *gin.Context) {
+	userID := c.Param("id")
+	linkID := c.Param("link_id")
+	logger := h.logger.With(
+		zap.String("operation", "get_shared_documents"),
+		zap.String("user_id", userID),
+		zap.String("link_id", linkID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	documents, err := h.userService.GetSharedDocuments(c.Request.Context(), userID, linkID)
+	if err != nil {
+		logger.Error("Failed to get shared documents", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, gin.H{
+		"documents": documents,
+		"count":     len(documents),
+	})
+}
+
+// GetCoApplicantIdentities serves loan applications that need every verified identity linked to a borrower
+func (h *UserHandler) GetCoApplicantIdentities(c *gin.Context) {
+	userID := c.Param("user_id")
+	logger := h.logger.With(
+		zap.String("operation", "get_co_applicant_identities"),
+		zap.String("user_id", userID),
+		zap.String("client", c.GetString("service_client")),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	identities, err := h.userService.GetCoApplicantIdentities(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error("Failed to get co-applicant identities", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, gin.H{
+		"user_id":       userID,
+		"co_applicants": identities,
+	})
+}
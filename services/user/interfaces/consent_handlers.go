@@ -0,0 +1,132 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// Consent Management Handlers
+
+func (h *UserHandler) RecordConsent(c *gin.Context) {
+	userID := c.Param("id")
+	logger := h.logger.With(
+		zap.String("operation", "record_consent"),
+		zap.String("user_id", userID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	var request domain.RecordConsentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Invalid request body", zap.Error(err))
+		h.respondValidationError(c, map[string]string{
+			"request_body": "invalid_format",
+		})
+		return
+	}
+
+	// Capture the origin of the consent from the request rather than trusting the payload
+	request.IPAddress = h.getClientIP(c)
+	request.UserAgent = c.GetHeader("User-Agent")
+
+	consent, err := h.userService.RecordConsent(c.Request.Context(), userID, &request)
+	if err != nil {
+		logger.Error("Failed to record consent", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("Consent recorded successfully", zap.String("consent_id", consent.ID))
+	h.respondSuccessWithMessage(c, http.StatusCreated, "consent_recorded", consent, map[string]interface{}{
+		"consent_type": consent.ConsentType,
+	})
+}
+
+func (h *UserHandler) GetConsents(c *gin.Context) {
+	userID := c.Param("id")
+	logger := h.logger.With(
+		zap.String("operation", "get_consents"),
+		zap.String("user_id", userID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	consents, err := h.userService.GetConsents(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error("Failed to get consents", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("Consents retrieved successfully", zap.Int("count", len(consents)))
+	h.respondSuccess(c, http.StatusOK, gin.H{
+		"consents": consents,
+		"count":    len(consents),
+	})
+}
+
+func (h *UserHandler) GetConsent(c *gin.Context) {
+	userID := c.Param("id")
+	consentType := domain.ConsentType(c.Param("type"))
+	logger := h.logger.With(
+		zap.String("operation", "get_consent"),
+		zap.String("user_id", userID),
+		zap.String("consent_type", string(consentType)),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	consent, err := h.userService.GetConsent(c.Request.Context(), userID, consentType)
+	if err != nil {
+		logger.Info("Active consent not available", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, consent)
+}
+
+// GetUserConsent returns a user's active consent of a type to an internal service
+func (h *UserHandler) GetUserConsent(c *gin.Context) {
+	userID := c.Param("user_id")
+	consentType := domain.ConsentType(c.Param("type"))
+	logger := h.logger.With(
+		zap.String("operation", "get_user_consent"),
+		zap.String("user_id", userID),
+		zap.String("consent_type", string(consentType)),
+		zap.String("client", c.GetString("service_client")),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	consent, err := h.userService.GetConsent(c.Request.Context(), userID, consentType)
+	if err != nil {
+		logger.Info("Active consent not available", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, consent)
+}
+
+func (h *UserHandler) RevokeConsent(c *gin.Context) {
+	userID := c.Param("id")
+	consentType := domain.ConsentType(c.Param("type"))
+	logger := h.logger.With(
+		zap.String("operation", "revoke_consent"),
+		zap.String("user_id", userID),
+		zap.String("consent_type", string(consentType)),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	if err := h.userService.RevokeConsent(c.Request.Context(), userID, consentType); err != nil {
+		logger.Error("Failed to revoke consent", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("Consent revoked successfully")
+	h.respondSuccessWithMessage(c, http.StatusOK, "consent_revoked", nil, map[string]interface{}{
+		"consent_type": consentType,
+	})
+}
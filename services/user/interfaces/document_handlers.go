@@ -24,6 +24,24 @@ func (h *UserHandler) UploadDocument(c *gin.Context) {
 		zap.String("request_id", c.GetString("request_id")),
 	)
 
+	h.uploadDocument(c, logger, userID)
+}
+
+// UploadUserDocument stores a document uploaded through another service on the user's behalf
+func (h *UserHandler) UploadUserDocument(c *gin.Context) {
+	userID := c.Param("user_id")
+	logger := h.logger.With(
+		zap.String("operation", "upload_user_document"),
+		zap.String("user_id", userID),
+		zap.String("client", c.GetString("service_client")),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	h.uploadDocument(c, logger, userID)
+}
+
+// uploadDocument reads a multipart document upload and stores it for the user
+func (h *UserHandler) uploadDocument(c *gin.Context, logger *zap.Logger, userID string) {
 	// Parse multipart form
 	err := c.Request.ParseMultipartForm(10 << 20) // 10 MB max
 	if err != nil {
@@ -140,6 +158,29 @@ func (h *UserHandler) GetDocuments(c *gin.Context) {
 	})
 }
 
+// ListUserDocuments lists a user's document metadata for other services
+func (h *UserHandler) ListUserDocuments(c *gin.Context) {
+	userID := c.Param("user_id")
+	logger := h.logger.With(
+		zap.String("operation", "list_user_documents"),
+		zap.String("user_id", userID),
+		zap.String("client", c.GetString("service_client")),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	documents, err := h.userService.GetDocuments(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error("Failed to get documents", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, gin.H{
+		"documents": documents,
+		"count":     len(documents),
+	})
+}
+
 func (h *UserHandler) GetDocument(c *gin.Context) {
 	userID := c.Param("id")
 	documentID := c.Param("doc_id")
@@ -171,6 +212,26 @@ func (h *UserHandler) DownloadDocument(c *gin.Context) {
 		zap.String("request_id", c.GetString("request_id")),
 	)
 
+	h.downloadDocument(c, logger, userID, documentID)
+}
+
+// DownloadUserDocument streams a user's document content to other services
+func (h *UserHandler) DownloadUserDocument(c *gin.Context) {
+	userID := c.Param("user_id")
+	documentID := c.Param("document_id")
+	logger := h.logger.With(
+		zap.String("operation", "download_user_document"),
+		zap.String("user_id", userID),
+		zap.String("document_id", documentID),
+		zap.String("client", c.GetString("service_client")),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	h.downloadDocument(c, logger, userID, documentID)
+}
+
+// downloadDocument writes a user's document content as a file download
+func (h *UserHandler) downloadDocument(c *gin.Context, logger *zap.Logger, userID, documentID string) {
 	documentStream, err := h.userService.DownloadDocument(c.Request.Context(), userID, documentID)
 	if err != nil {
 		logger.Error("Failed to download document", zap.Error(err))
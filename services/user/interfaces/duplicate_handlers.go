@@ -0,0 +1,70 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// Duplicate Detection Handlers
+
+func (h *UserHandler) GetDuplicates(c *gin.Context) {
+	userID := c.Param("id")
+	logger := h.logger.With(
+		zap.String("operation", "get_duplicates"),
+		zap.String("user_id", userID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	matches, err := h.userService.GetDuplicates(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error("Failed to get duplicates", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, gin.H{
+		"duplicates": matches,
+		"count":      len(matches),
+	})
+}
+
+func (h *UserHandler) MergeUsers(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "merge_users"),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	var request domain.MergeUsersRequest
+	if err := c.ShouldBindJSON(&request); err != nil || request.SurvivorID == "" || request.DuplicateID == "" || request.Reason == "" {
+		logger.Error("Invalid request body", zap.Error(err))
+		h.respondValidationError(c, map[string]string{
+			"request_body": "invalid_format",
+		})
+		return
+	}
+
+	// Merges are attributed to the authenticated admin only
+	request.PerformedBy = c.GetString("user_id")
+	if request.PerformedBy == "" {
+		logger.Warn("Merge requested without an authenticated admin")
+		h.respondError(c, &domain.UserError{
+			Code:    domain.USER_032,
+			Message: h.localizer.Localize(c.Request.Context(), domain.USER_032, nil),
+		})
+		return
+	}
+
+	merge, err := h.userService.MergeUsers(c.Request.Context(), &request)
+	if err != nil {
+		logger.Error("Failed to merge users", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("Users merged successfully", zap.String("merge_id", merge.ID))
+	h.respondSuccess(c, http.StatusOK, merge)
+}
@@ -0,0 +1,118 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	userv1 "github.com/huuhoait/los-demo/services/shared/proto/user/v1"
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// UserServer serves the UserService gRPC contract to the other internal services. Callers are
+// authenticated by the transport's mTLS. Raw SSNs never leave the service; callers get the vault
+// token only.
+type UserServer struct {
+	userv1.UnimplementedUserServiceServer
+	userService domain.UserService
+	logger      *zap.Logger
+}
+
+// NewUserServer creates a new UserService gRPC server
+func NewUserServer(userService domain.UserService, logger *zap.Logger) *UserServer {
+	return &UserServer{
+		userService: userService,
+		logger:      logger,
+	}
+}
+
+// GetUser returns a user with their profile
+func (s *UserServer) GetUser(ctx context.Context, req *userv1.GetUserRequest) (*userv1.User, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	user, err := s.userService.GetUser(ctx, req.GetId())
+	if err != nil {
+		return nil, s.userStatus("get_user", err)
+	}
+	return s.userMessage(ctx, user)
+}
+
+// GetUserByEmail returns the user registered with an email address, with their profile
+func (s *UserServer) GetUserByEmail(ctx context.Context, req *userv1.GetUserByEmailRequest) (*userv1.User, error) {
+	if req.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	user, err := s.userService.GetUserByEmail(ctx, req.GetEmail())
+	if err != nil {
+		return nil, s.userStatus("get_user_by_email", err)
+	}
+	return s.userMessage(ctx, user)
+}
+
+// userMessage converts a user and their profile, when they have one, to a UserService message
+func (s *UserServer) userMessage(ctx context.Context, user *domain.User) (*userv1.User, error) {
+	message := &userv1.User{
+		Id:          user.ID,
+		Email:       user.Email,
+		PhoneNumber: user.Phone,
+		CreatedAt:   timestamppb.New(user.CreatedAt),
+		UpdatedAt:   timestamppb.New(user.UpdatedAt),
+	}
+
+	profile, err := s.userService.GetProfile(ctx, user.ID)
+	if err != nil {
+		var userErr *domain.UserError
+		if errors.As(err, &userErr) && userErr.Code == domain.USER_030 {
+			// Users who have not completed a profile are returned without one
+			return message, nil
+		}
+		return nil, s.userStatus("get_profile", err)
+	}
+
+	message.FirstName = profile.FirstName
+	message.LastName = profile.LastName
+	message.SsnToken = profile.SSNToken
+	if !profile.DateOfBirth.IsZero() {
+		message.DateOfBirth = timestamppb.New(profile.DateOfBirth)
+	}
+	if profile.Phone != "" {
+		message.PhoneNumber = profile.Phone
+	}
+	message.Address = &userv1.Address{
+		StreetAddress: profile.Address.Street,
+		City:          profile.Address.City,
+		State:         profile.Address.State,
+		ZipCode:       profile.Address.ZipCode,
+		Country:       profile.Address.Country,
+	}
+	message.EmploymentInfo = &userv1.EmploymentInfo{
+		EmployerName: profile.EmploymentInfo.EmployerName,
+		JobTitle:     profile.EmploymentInfo.JobTitle,
+		WorkPhone:    profile.EmploymentInfo.EmployerPhone,
+	}
+	return message, nil
+}
+
+// userStatus reports a user service error with its gRPC code
+func (s *UserServer) userStatus(operation string, err error) error {
+	logger := s.logger.With(zap.String("rpc", operation))
+
+	var userErr *domain.UserError
+	if errors.As(err, &userErr) {
+		if userErr.Code == domain.USER_030 {
+			return status.Errorf(codes.NotFound, "%s: %s", userErr.Code, userErr.Message)
+		}
+		logger.Error("User service request failed", zap.String("error_code", userErr.Code), zap.Error(err))
+		return status.Errorf(codes.Internal, "%s: %s", userErr.Code, userErr.Message)
+	}
+
+	logger.Error("Unexpected user service error", zap.Error(err))
+	return status.Error(codes.Internal, "internal error")
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -14,16 +15,18 @@ import (
 )
 
 type UserHandler struct {
-	userService domain.UserService
-	logger      *zap.Logger
-	localizer   *i18n.Localizer
+	userService         domain.UserService
+	tokenizationService domain.TokenizationService
+	logger              *zap.Logger
+	localizer           *i18n.Localizer
 }
 
-func NewUserHandler(userService domain.UserService, logger *zap.Logger, localizer *i18n.Localizer) *UserHandler {
+func NewUserHandler(userService domain.UserService, tokenizationService domain.TokenizationService, logger *zap.Logger, localizer *i18n.Localizer) *UserHandler {
 	return &UserHandler{
-		userService: userService,
-		logger:      logger,
-		localizer:   localizer,
+		userService:         userService,
+		tokenizationService: tokenizationService,
+		logger:              logger,
+		localizer:           localizer,
 	}
 }
 
@@ -40,6 +43,10 @@ func (h *UserHandler) RegisterRoutes(router *gin.RouterGroup) {
 	// Profile management routes
 	router.GET("/users/:id/profile", h.GetProfile)
 	router.PUT("/users/:id/profile", h.UpdateProfile)
+	router.GET("/users/:id/completeness", h.GetProfileCompleteness)
+
+	// Password reset links sent by an administrator are redeemed without a session
+	router.POST("/password-reset/redeem", h.RedeemPasswordReset)
 
 	// Verification routes
 	router.POST("/users/:id/verify-email", h.SendEmailVerification)
@@ -58,6 +65,21 @@ func (h *UserHandler) RegisterRoutes(router *gin.RouterGroup) {
 	router.GET("/users/:id/documents/:doc_id", h.GetDocument)
 	router.GET("/users/:id/documents/:doc_id/download", h.DownloadDocument)
 	router.DELETE("/users/:id/documents/:doc_id", h.DeleteDocument)
+
+	// Consent routes
+	router.POST("/users/:id/consents", h.RecordConsent)
+	router.GET("/users/:id/consents", h.GetConsents)
+	router.GET("/users/:id/consents/:type", h.GetConsent)
+	router.DELETE("/users/:id/consents/:type", h.RevokeConsent)
+
+	// Co-applicant routes
+	router.POST("/users/:id/co-applicants", h.InviteCoApplicant)
+	router.GET("/users/:id/co-applicants", h.ListCoApplicants)
+	router.POST("/users/:id/co-applicants/accept", h.AcceptCoApplicantInvite)
+	router.POST("/users/:id/co-applicants/decline", h.DeclineCoApplicantInvite)
+	router.DELETE("/users/:id/co-applicants/:link_id", h.RevokeCoApplicantLink)
+	router.PUT("/users/:id/co-applicants/:link_id/sharing", h.UpdateDocumentSharing)
+	router.GET("/users/:id/co-applicants/:link_id/documents", h.GetSharedDocuments)
 }
 
 // User Management Handlers
@@ -158,42 +180,49 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	h.respondSuccess(c, http.StatusNoContent, nil)
 }
 
-func (h *UserHandler) ListUsers(c *gin.Context) {
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	userID := c.Param("id")
 	logger := h.logger.With(
-		zap.String("operation", "list_users"),
+		zap.String("operation", "restore_user"),
+		zap.String("user_id", userID),
 		zap.String("request_id", c.GetString("request_id")),
 	)
 
-	// Parse pagination parameters
-	offset := 0
-	limit := 50 // Default limit
-
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			offset = o
-		}
+	user, err := h.userService.RestoreUser(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error("Failed to restore user", zap.Error(err))
+		h.respondError(c, err)
+		return
 	}
 
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
+	logger.Info("User restored successfully")
+	h.respondSuccess(c, http.StatusOK, user)
+}
+
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "list_users"),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	var query domain.UserListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Error("Invalid query parameters", zap.Error(err))
+		h.respondValidationError(c, map[string]string{
+			"query": "invalid_format",
+		})
+		return
 	}
 
-	users, err := h.userService.ListUsers(c.Request.Context(), offset, limit)
+	page, err := h.userService.ListUsers(c.Request.Context(), &query)
 	if err != nil {
 		logger.Error("Failed to list users", zap.Error(err))
 		h.respondError(c, err)
 		return
 	}
 
-	logger.Info("Users listed successfully", zap.Int("count", len(users)))
-	h.respondSuccess(c, http.StatusOK, gin.H{
-		"users":  users,
-		"offset": offset,
-		"limit":  limit,
-		"count":  len(users),
-	})
+	logger.Info("Users listed successfully", zap.Int("count", len(page.Users)))
+	h.respondUserPage(c, page)
 }
 
 func (h *UserHandler) SearchUsers(c *gin.Context) {
@@ -202,65 +231,37 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
 		zap.String("request_id", c.GetString("request_id")),
 	)
 
-	var request struct {
-		Email         string `json:"email,omitempty"`
-		Phone         string `json:"phone,omitempty"`
-		Status        string `json:"status,omitempty"`
-		EmailVerified *bool  `json:"email_verified,omitempty"`
-		PhoneVerified *bool  `json:"phone_verified,omitempty"`
-		Offset        int    `json:"offset,omitempty"`
-		Limit         int    `json:"limit,omitempty"`
-	}
-
-	if err := c.ShouldBindJSON(&request); err != nil {
+	var query domain.UserListQuery
+	if err := c.ShouldBindJSON(&query); err != nil {
 		logger.Error("Invalid request body", zap.Error(err))
-		h.respondError(c, &domain.UserError{
-			Code:        domain.USER_005,
-			Message:     h.localizer.GetErrorMessage("en", domain.USER_005, nil),
-			Description: "Invalid request body",
+		h.respondValidationError(c, map[string]string{
+			"request_body": "invalid_format",
 		})
 		return
 	}
 
-	// Build search criteria
-	criteria := make(map[string]interface{})
-	if request.Email != "" {
-		criteria["email"] = request.Email
-	}
-	if request.Phone != "" {
-		criteria["phone"] = request.Phone
-	}
-	if request.Status != "" {
-		criteria["status"] = request.Status
-	}
-	if request.EmailVerified != nil {
-		criteria["email_verified"] = *request.EmailVerified
-	}
-	if request.PhoneVerified != nil {
-		criteria["phone_verified"] = *request.PhoneVerified
-	}
-
-	// Default pagination
-	offset := request.Offset
-	limit := request.Limit
-	if limit <= 0 || limit > 100 {
-		limit = 50
-	}
-
-	users, err := h.userService.SearchUsers(c.Request.Context(), criteria, offset, limit)
+	page, err := h.userService.ListUsers(c.Request.Context(), &query)
 	if err != nil {
 		logger.Error("Failed to search users", zap.Error(err))
 		h.respondError(c, err)
 		return
 	}
 
-	logger.Info("User search completed", zap.Int("count", len(users)))
+	logger.Info("User search completed", zap.Int("count", len(page.Users)))
+	h.respondUserPage(c, page)
+}
+
+// respondUserPage writes a user listing with its pagination metadata
+func (h *UserHandler) respondUserPage(c *gin.Context, page *domain.UserPage) {
 	h.respondSuccess(c, http.StatusOK, gin.H{
-		"users":    users,
-		"criteria": criteria,
-		"offset":   offset,
-		"limit":    limit,
-		"count":    len(users),
+		"users": page.Users,
+		"pagination": gin.H{
+			"limit":       page.Limit,
+			"count":       len(page.Users),
+			"total_count": page.TotalCount,
+			"has_more":    page.HasMore,
+			"next_cursor": page.NextCursor,
+		},
 	})
 }
 
@@ -315,6 +316,24 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	h.respondSuccess(c, http.StatusOK, profile)
 }
 
+func (h *UserHandler) GetProfileCompleteness(c *gin.Context) {
+	userID := c.Param("id")
+	logger := h.logger.With(
+		zap.String("operation", "get_profile_completeness"),
+		zap.String("user_id", userID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	completeness, err := h.userService.GetProfileCompleteness(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error("Failed to get profile completeness", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, completeness)
+}
+
 // Verification Handlers
 
 func (h *UserHandler) SendEmailVerification(c *gin.Context) {
@@ -509,6 +528,56 @@ func (h *UserHandler) UpdateKYCStatus(c *gin.Context) {
 	})
 }
 
+// GetIdentityVerificationStatus serves loan applications that must refuse borrowers with stale identity verification
+func (h *UserHandler) GetIdentityVerificationStatus(c *gin.Context) {
+	userID := c.Param("user_id")
+	logger := h.logger.With(
+		zap.String("operation", "get_identity_verification_status"),
+		zap.String("user_id", userID),
+		zap.String("client", c.GetString("service_client")),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	status, err := h.userService.GetIdentityVerificationStatus(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error("Failed to get identity verification status", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, status)
+}
+
+func (h *UserHandler) SendUserNotification(c *gin.Context) {
+	userID := c.Param("user_id")
+	logger := h.logger.With(
+		zap.String("operation", "send_user_notification"),
+		zap.String("user_id", userID),
+		zap.String("client", c.GetString("service_client")),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	var request domain.UserNotificationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Invalid request body", zap.Error(err))
+		h.respondValidationError(c, map[string]string{
+			"request_body": "invalid_format",
+		})
+		return
+	}
+
+	if err := h.userService.SendUserNotification(c.Request.Context(), userID, &request); err != nil {
+		logger.Error("Failed to send user notification", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, gin.H{
+		"user_id": userID,
+		"sent":    true,
+	})
+}
+
 // Helper methods
 
 func (h *UserHandler) respondSuccess(c *gin.Context, status int, data interface{}) {
@@ -569,6 +638,11 @@ func (h *UserHandler) respondError(c *gin.Context, err error) {
 			response["error"].(gin.H)["field"] = domainErr.Field
 		}
 
+		// Retry-After is in whole seconds, rounded up so clients never retry early
+		if domainErr.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.FormatInt(int64((domainErr.RetryAfter+time.Second-1)/time.Second), 10))
+		}
+
 		c.JSON(statusCode, response)
 		return
 	}
@@ -605,14 +679,28 @@ func (h *UserHandler) getHTTPStatusFromErrorCode(code string) int {
 		strings.HasPrefix(code, "USER_012"), strings.HasPrefix(code, "USER_017"):
 		return http.StatusBadRequest
 	case code == domain.USER_006, code == domain.USER_007, code == domain.USER_008,
-		code == domain.USER_010, code == domain.USER_020:
+		code == domain.USER_010, code == domain.USER_020, code == domain.USER_054,
+		code == domain.USER_059:
 		return http.StatusConflict
-	case code == domain.USER_030, code == domain.USER_031, code == domain.USER_014:
+	case code == domain.USER_036, code == domain.USER_038, code == domain.USER_039,
+		code == domain.USER_042, code == domain.USER_043, code == domain.USER_045,
+		code == domain.USER_046, code == domain.USER_047, code == domain.USER_050,
+		code == domain.USER_055, code == domain.USER_058, code == domain.USER_063,
+		code == domain.USER_064:
+		return http.StatusBadRequest
+	case code == domain.USER_030, code == domain.USER_031, code == domain.USER_014,
+		code == domain.USER_037, code == domain.USER_040, code == domain.USER_051,
+		code == domain.USER_052, code == domain.USER_056, code == domain.USER_060,
+		code == domain.USER_062:
 		return http.StatusNotFound
-	case code == domain.USER_032:
+	case code == domain.USER_032, code == domain.USER_041, code == domain.USER_053,
+		code == domain.USER_061:
 		return http.StatusForbidden
-	case code == domain.USER_033:
+	case code == domain.USER_033, code == domain.USER_048, code == domain.USER_049,
+		code == domain.USER_057:
 		return http.StatusTooManyRequests
+	case code == domain.USER_019, code == domain.USER_044:
+		return http.StatusGone
 	case strings.HasPrefix(code, "USER_026"), strings.HasPrefix(code, "USER_027"),
 		strings.HasPrefix(code, "USER_028"), strings.HasPrefix(code, "USER_029"),
 		strings.HasPrefix(code, "USER_034"), strings.HasPrefix(code, "USER_035"):
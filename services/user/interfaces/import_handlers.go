@@ -0,0 +1,134 @@
+package interfaces
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+	"github.com/huuhoait/los-demo/services/user/interfaces/middleware"
+)
+
+// maxImportBytes bounds a single bulk import upload
+const maxImportBytes = 50 << 20
+
+// RegisterImportRoutes registers bulk user import routes; callers need an access token that can manage users
+func (h *UserHandler) RegisterImportRoutes(router *gin.RouterGroup, rbac *middleware.RBACMiddleware) {
+	router.Use(rbac.Authenticate(), rbac.RequirePermission(middleware.PermissionManageUsers))
+
+	router.POST("", h.SubmitUserImport)
+	router.GET("/:import_id", h.GetUserImport)
+}
+
+// SubmitUserImport accepts a CSV or NDJSON file, either as the multipart "file" field or as the raw body,
+// and streams it to storage for asynchronous processing. The format comes from the format query
+// parameter, falling back to the file extension or content type.
+func (h *UserHandler) SubmitUserImport(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "submit_user_import"),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+
+	format := domain.ImportFormat(strings.ToLower(c.Query("format")))
+	var content io.Reader = c.Request.Body
+
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		reader, err := c.Request.MultipartReader()
+		if err != nil {
+			logger.Error("Failed to read multipart form", zap.Error(err))
+			h.respondValidationError(c, map[string]string{
+				"file": "required",
+			})
+			return
+		}
+
+		// Parts are read in order without buffering, so the file is streamed straight through
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				logger.Error("Import file part not found", zap.Error(err))
+				h.respondValidationError(c, map[string]string{
+					"file": "required",
+				})
+				return
+			}
+			if part.FormName() == "file" {
+				if format == "" {
+					format = domain.ImportFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(part.FileName())), "."))
+				}
+				content = part
+				break
+			}
+		}
+	} else if format == "" {
+		switch mediaType {
+		case "text/csv":
+			format = domain.ImportFormatCSV
+		case "application/x-ndjson", "application/ndjson", "application/jsonl":
+			format = domain.ImportFormatNDJSON
+		}
+	}
+
+	body := &importBodyReader{reader: content}
+	userImport, err := h.userService.SubmitUserImport(c.Request.Context(), c.GetString("user_id"), format, body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(body.err, &tooLarge) {
+			logger.Warn("Import file too large", zap.Int64("limit", tooLarge.Limit))
+			h.respondError(c, &domain.UserError{
+				Code:    domain.USER_012,
+				Message: h.localizer.Localize(c.Request.Context(), domain.USER_012, nil),
+				Field:   "file",
+			})
+			return
+		}
+		logger.Error("Failed to submit user import", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("User import submitted", zap.String("import_id", userImport.ID))
+	c.Header("Location", c.Request.URL.Path+"/"+userImport.ID)
+	h.respondSuccess(c, http.StatusAccepted, userImport)
+}
+
+func (h *UserHandler) GetUserImport(c *gin.Context) {
+	importID := c.Param("import_id")
+	logger := h.logger.With(
+		zap.String("operation", "get_user_import"),
+		zap.String("import_id", importID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	userImport, err := h.userService.GetUserImport(c.Request.Context(), importID)
+	if err != nil {
+		logger.Error("Failed to get user import", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, userImport)
+}
+
+// importBodyReader remembers the read error, since storage failures wrap it beyond recognition
+type importBodyReader struct {
+	reader io.Reader
+	err    error
+}
+
+func (r *importBodyReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
@@ -0,0 +1,85 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+	"github.com/huuhoait/los-demo/services/user/interfaces/middleware"
+)
+
+// RegisterInternalRoutes registers service-to-service routes guarded by scoped service tokens
+func (h *UserHandler) RegisterInternalRoutes(router *gin.RouterGroup, serviceAuth *middleware.ServiceAuthMiddleware) {
+	router.POST("/tokens", serviceAuth.RequireScope(middleware.ScopeTokenize), h.Tokenize)
+	router.POST("/tokens/detokenize", serviceAuth.RequireScope(middleware.ScopeDetokenize), h.Detokenize)
+	router.GET("/users/:user_id/co-applicants", serviceAuth.RequireScope(middleware.ScopeReadCoApplicants), h.GetCoApplicantIdentities)
+	router.GET("/users/:user_id/identity-verification", serviceAuth.RequireScope(middleware.ScopeReadKYC), h.GetIdentityVerificationStatus)
+	router.GET("/users/:user_id/consents/:type", serviceAuth.RequireScope(middleware.ScopeReadConsents), h.GetUserConsent)
+	router.POST("/users/:user_id/notifications", serviceAuth.RequireScope(middleware.ScopeNotify), h.SendUserNotification)
+	router.GET("/users/:user_id/documents", serviceAuth.RequireScope(middleware.ScopeReadDocuments), h.ListUserDocuments)
+	router.POST("/users/:user_id/documents", serviceAuth.RequireScope(middleware.ScopeWriteDocuments), h.UploadUserDocument)
+	router.GET("/users/:user_id/documents/:document_id/content", serviceAuth.RequireScope(middleware.ScopeReadDocuments), h.DownloadUserDocument)
+}
+
+// Tokenization Handlers
+
+func (h *UserHandler) Tokenize(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "tokenize"),
+		zap.String("client", c.GetString("service_client")),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	var request domain.TokenizeRequest
+	if err := c.ShouldBindJSON(&request); err != nil || request.Value == "" {
+		logger.Error("Invalid request body", zap.Error(err))
+		h.respondValidationError(c, map[string]string{
+			"request_body": "invalid_format",
+		})
+		return
+	}
+
+	token, err := h.tokenizationService.Tokenize(c.Request.Context(), request.TokenType, request.Value)
+	if err != nil {
+		logger.Error("Failed to tokenize value", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, gin.H{
+		"token":      token.Token,
+		"token_type": token.TokenType,
+		"last4":      token.Last4,
+	})
+}
+
+func (h *UserHandler) Detokenize(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "detokenize"),
+		zap.String("client", c.GetString("service_client")),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	var request domain.DetokenizeRequest
+	if err := c.ShouldBindJSON(&request); err != nil || request.Token == "" {
+		logger.Error("Invalid request body", zap.Error(err))
+		h.respondValidationError(c, map[string]string{
+			"request_body": "invalid_format",
+		})
+		return
+	}
+
+	value, err := h.tokenizationService.Detokenize(c.Request.Context(), request.Token)
+	if err != nil {
+		logger.Error("Failed to detokenize value", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("Token resolved for internal caller")
+	h.respondSuccess(c, http.StatusOK, gin.H{
+		"value": value,
+	})
+}
@@ -0,0 +1,122 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/huuhoait/los-demo/services/user/domain"
+)
+
+// Webhook Administration Handlers
+
+func (h *UserHandler) RegisterWebhook(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "register_webhook"),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	var request domain.RegisterWebhookRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Invalid request body", zap.Error(err))
+		h.respondValidationError(c, map[string]string{
+			"request_body": "invalid_format",
+		})
+		return
+	}
+
+	endpoint, err := h.userService.RegisterWebhook(c.Request.Context(), &request)
+	if err != nil {
+		logger.Error("Failed to register webhook", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("Webhook registered successfully", zap.String("endpoint_id", endpoint.ID))
+	h.respondSuccess(c, http.StatusCreated, endpoint)
+}
+
+func (h *UserHandler) ListWebhooks(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "list_webhooks"),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	endpoints, err := h.userService.ListWebhooks(c.Request.Context())
+	if err != nil {
+		logger.Error("Failed to list webhooks", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, gin.H{
+		"webhooks": endpoints,
+		"count":    len(endpoints),
+	})
+}
+
+func (h *UserHandler) DisableWebhook(c *gin.Context) {
+	endpointID := c.Param("webhook_id")
+	logger := h.logger.With(
+		zap.String("operation", "disable_webhook"),
+		zap.String("endpoint_id", endpointID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	if err := h.userService.DisableWebhook(c.Request.Context(), endpointID); err != nil {
+		logger.Error("Failed to disable webhook", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("Webhook disabled successfully")
+	h.respondSuccess(c, http.StatusNoContent, nil)
+}
+
+func (h *UserHandler) ListWebhookDeliveries(c *gin.Context) {
+	logger := h.logger.With(
+		zap.String("operation", "list_webhook_deliveries"),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	var query domain.WebhookDeliveryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Error("Invalid query parameters", zap.Error(err))
+		h.respondValidationError(c, map[string]string{
+			"query": "invalid_format",
+		})
+		return
+	}
+
+	deliveries, err := h.userService.ListWebhookDeliveries(c.Request.Context(), &query)
+	if err != nil {
+		logger.Error("Failed to list webhook deliveries", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, gin.H{
+		"deliveries": deliveries,
+		"count":      len(deliveries),
+	})
+}
+
+func (h *UserHandler) ReplayWebhookDelivery(c *gin.Context) {
+	deliveryID := c.Param("delivery_id")
+	logger := h.logger.With(
+		zap.String("operation", "replay_webhook_delivery"),
+		zap.String("delivery_id", deliveryID),
+		zap.String("request_id", c.GetString("request_id")),
+	)
+
+	delivery, err := h.userService.ReplayWebhookDelivery(c.Request.Context(), deliveryID)
+	if err != nil {
+		logger.Error("Failed to replay webhook delivery", zap.Error(err))
+		h.respondError(c, err)
+		return
+	}
+
+	logger.Info("Webhook delivery requeued")
+	h.respondSuccess(c, http.StatusAccepted, delivery)
+}
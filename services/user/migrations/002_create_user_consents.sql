@@ -0,0 +1,31 @@
+-- User Consents Schema
+-- Records explicit user consent to disclosures (credit pulls, e-sign, communications, data sharing)
+
+CREATE TYPE consent_type AS ENUM ('credit_pull', 'e_sign', 'communications', 'data_sharing');
+
+-- User consents table - Append-only record of consents; revocation is tracked via revoked_at
+CREATE TABLE user_consents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    consent_type consent_type NOT NULL,
+
+    -- Disclosure details
+    version VARCHAR(50) NOT NULL,
+    text_hash CHAR(64) NOT NULL, -- SHA-256 of the disclosure text shown to the user
+
+    -- Context
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+
+    -- Metadata
+    consented_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_consents_user_id ON user_consents(user_id);
+CREATE INDEX idx_user_consents_user_type ON user_consents(user_id, consent_type, consented_at DESC);
+CREATE INDEX idx_user_consents_active ON user_consents(user_id, consent_type) WHERE revoked_at IS NULL;
+
+COMMENT ON TABLE user_consents IS 'User consents to versioned disclosures, used as evidence for credit pulls and e-sign';
+COMMENT ON COLUMN user_consents.text_hash IS 'SHA-256 hash of the exact disclosure text the user agreed to';
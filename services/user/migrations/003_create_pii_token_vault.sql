@@ -0,0 +1,29 @@
+-- PII Token Vault Schema
+-- Raw SSNs and tax IDs are stored only here, encrypted; other tables and services hold opaque tokens
+
+CREATE TYPE pii_token_type AS ENUM ('ssn', 'tax_id');
+
+CREATE TABLE pii_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    token VARCHAR(64) NOT NULL UNIQUE,
+    token_type pii_token_type NOT NULL,
+
+    -- Value storage
+    value_hash CHAR(64) NOT NULL, -- HMAC-SHA256 of the normalized value, used for deterministic lookup
+    value_encrypted TEXT NOT NULL, -- Envelope-encrypted value
+    last4 CHAR(4) NOT NULL,
+
+    -- Metadata
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_pii_tokens_type_hash ON pii_tokens(token_type, value_hash);
+
+-- Profiles reference the vault instead of holding encrypted SSNs
+ALTER TABLE user_profiles ADD COLUMN ssn_token VARCHAR(64);
+CREATE INDEX idx_user_profiles_ssn_token ON user_profiles(ssn_token);
+
+-- ssn_encrypted is retained until existing values are backfilled into the vault, then dropped
+COMMENT ON COLUMN user_profiles.ssn_encrypted IS 'Deprecated: superseded by ssn_token';
+COMMENT ON TABLE pii_tokens IS 'Token vault for SSNs and tax IDs; detokenization is restricted to scoped internal callers';
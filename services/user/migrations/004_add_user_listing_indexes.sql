@@ -0,0 +1,12 @@
+-- Indexes backing keyset pagination of user listings
+-- Each sort column is paired with id so (column, id) cursors resolve with an index scan
+
+CREATE INDEX IF NOT EXISTS idx_users_created_at_id ON users(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_users_updated_at_id ON users(updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_users_email_id ON users(email, id);
+
+-- Supports the email_domain filter
+CREATE INDEX IF NOT EXISTS idx_users_email_domain ON users(LOWER(SPLIT_PART(email, '@', 2)));
+
+-- Supports the kyc_status filter
+CREATE INDEX IF NOT EXISTS idx_kyc_verifications_user_status ON kyc_verifications(user_id, status);
@@ -0,0 +1,11 @@
+-- User soft delete
+-- deleted_at marks a user as removed; rows are kept so an admin can restore them within the retention window
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- Most queries only touch live users
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Users deleted before soft delete existed were only marked with status 'deleted'. They are taken
+-- to have been deleted when they were last updated, which starts their restore window.
+UPDATE users SET deleted_at = updated_at WHERE status::text = 'deleted' AND deleted_at IS NULL;
@@ -0,0 +1,51 @@
+-- Duplicate user detection and merge
+-- Normalized contact columns back fuzzy matching on signup; merges are recorded for the audit trail
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_normalized VARCHAR(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_normalized VARCHAR(20);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS merged_into UUID REFERENCES users(id);
+
+-- Backfill existing users; plus-tags and Gmail dots are picked up as users are re-normalized by the service
+UPDATE users SET email_normalized = LOWER(email) WHERE email_normalized IS NULL;
+UPDATE users SET phone_normalized = REGEXP_REPLACE(phone, '\D', '', 'g') WHERE phone_normalized IS NULL AND phone IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_email_normalized ON users(email_normalized) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_users_phone_normalized ON users(phone_normalized) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_user_profiles_dob_last_name ON user_profiles(date_of_birth, LOWER(last_name));
+
+-- Potential duplicates awaiting admin review
+CREATE TABLE user_duplicate_matches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    candidate_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    score DECIMAL(5,4) NOT NULL, -- 0.0000 to 1.0000
+    reasons JSONB NOT NULL DEFAULT '[]',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP,
+
+    UNIQUE(user_id, candidate_user_id)
+);
+
+CREATE INDEX idx_user_duplicate_matches_candidate ON user_duplicate_matches(candidate_user_id);
+CREATE INDEX idx_user_duplicate_matches_pending ON user_duplicate_matches(created_at) WHERE status = 'pending';
+
+-- Append-only record of completed merges
+CREATE TABLE user_merges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    survivor_id UUID NOT NULL REFERENCES users(id),
+    merged_id UUID NOT NULL REFERENCES users(id),
+    performed_by VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL,
+    documents_moved INTEGER NOT NULL DEFAULT 0,
+    kyc_records_moved INTEGER NOT NULL DEFAULT 0,
+    consents_moved INTEGER NOT NULL DEFAULT 0,
+    loan_applications_moved INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_merges_survivor_id ON user_merges(survivor_id);
+CREATE INDEX idx_user_merges_merged_id ON user_merges(merged_id);
+
+COMMENT ON COLUMN users.merged_into IS 'Surviving user this account was merged into, if any';
+COMMENT ON TABLE user_merges IS 'Audit trail of admin user merges';
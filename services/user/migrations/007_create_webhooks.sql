@@ -0,0 +1,42 @@
+-- User lifecycle webhooks
+-- Endpoints subscribe to events; each event fans out to one delivery row per endpoint
+
+CREATE TABLE webhook_endpoints (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    url TEXT NOT NULL,
+    description VARCHAR(255),
+    events JSONB NOT NULL DEFAULT '[]', -- e.g. ["user.created", "kyc.verified"]
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    secret_encrypted TEXT NOT NULL, -- HMAC signing secret, encrypted with the service keyring
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_endpoints_events ON webhook_endpoints USING GIN (events) WHERE active;
+
+CREATE TABLE webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    endpoint_id UUID NOT NULL REFERENCES webhook_endpoints(id),
+    event_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+
+    -- Delivery state; pending rows are retried until they succeed or are dead-lettered
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    response_status INTEGER,
+    delivered_at TIMESTAMP,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT valid_webhook_delivery_status CHECK (status IN ('pending', 'succeeded', 'dead_lettered'))
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_endpoint ON webhook_deliveries(endpoint_id, created_at DESC);
+CREATE INDEX idx_webhook_deliveries_status ON webhook_deliveries(status, created_at DESC);
+
+COMMENT ON TABLE webhook_deliveries IS 'Outbox of signed webhook deliveries; dead_lettered rows can be replayed by an admin';
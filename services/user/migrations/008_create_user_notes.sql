@@ -0,0 +1,12 @@
+-- Admin annotations on users
+-- Notes are internal to operations staff and append-only; author_id is the auth service user id of the admin
+
+CREATE TABLE user_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    author_id UUID NOT NULL,
+    note TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_notes_user_id ON user_notes(user_id, created_at DESC);
@@ -0,0 +1,30 @@
+-- Document retention
+-- Purged documents keep their row as a tombstone (purged_at, purge_reason) after the stored file is deleted.
+-- Users on legal hold are exempt from purges until the hold is released.
+
+-- Uploaded documents as stored by the document repository. The documents table from 001 is not
+-- used by the service; its document_type enum cannot hold the loan document types.
+CREATE TABLE IF NOT EXISTS user_documents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    document_type VARCHAR(50) NOT NULL,
+    file_path VARCHAR(500) NOT NULL,
+    file_size BIGINT NOT NULL,
+    mime_type VARCHAR(100) NOT NULL,
+    encryption_key TEXT NOT NULL,
+    upload_ip VARCHAR(45),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_documents_user_id ON user_documents(user_id, created_at DESC);
+
+ALTER TABLE user_documents ADD COLUMN IF NOT EXISTS purged_at TIMESTAMP;
+ALTER TABLE user_documents ADD COLUMN IF NOT EXISTS purge_reason VARCHAR(255);
+
+-- Purge passes scan live documents by type and age
+CREATE INDEX IF NOT EXISTS idx_user_documents_retention ON user_documents(document_type, created_at) WHERE purged_at IS NULL;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS legal_hold BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS legal_hold_reason TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS legal_hold_set_by UUID;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS legal_hold_set_at TIMESTAMP;
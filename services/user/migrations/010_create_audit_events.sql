@@ -0,0 +1,35 @@
+-- Append-only audit event store
+-- Each event's hash covers its content and the previous event's hash, so any edit, insertion or
+-- deletion breaks the chain from that point on. UPDATE and DELETE are rejected outright.
+
+CREATE TABLE audit_events (
+    sequence BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL UNIQUE,
+    user_id UUID, -- the user the event is about; no foreign key so history survives user removal
+    actor_id VARCHAR(100), -- admin or service that caused the event, if any
+    event_type VARCHAR(100) NOT NULL,
+    category VARCHAR(50) NOT NULL, -- profile, security, document, kyc
+    metadata JSON, -- JSON rather than JSONB so the hashed bytes are stored verbatim
+    created_at TIMESTAMPTZ NOT NULL,
+    prev_hash VARCHAR(64) NOT NULL,
+    hash VARCHAR(64) NOT NULL UNIQUE
+);
+
+CREATE INDEX idx_audit_events_user ON audit_events(user_id, created_at);
+CREATE INDEX idx_audit_events_actor ON audit_events(actor_id, created_at) WHERE actor_id IS NOT NULL;
+CREATE INDEX idx_audit_events_type ON audit_events(event_type, created_at);
+CREATE INDEX idx_audit_events_created_at ON audit_events(created_at);
+
+CREATE OR REPLACE FUNCTION reject_audit_event_mutation() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION reject_audit_event_mutation();
+
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_audit_event_mutation();
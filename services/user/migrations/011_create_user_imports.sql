@@ -0,0 +1,27 @@
+-- Bulk user import
+-- Borrowers migrated from a legacy system are keyed on external_id so re-running an import updates them in place.
+-- Uploaded files are staged in document storage and processed asynchronously; per-row failures are kept in errors.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS external_id VARCHAR(100);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_external_id ON users(external_id) WHERE external_id IS NOT NULL;
+
+CREATE TABLE user_imports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'completed', 'failed')),
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'ndjson')),
+    storage_key VARCHAR(500) NOT NULL,
+    submitted_by VARCHAR(100) NOT NULL,
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    created_rows INTEGER NOT NULL DEFAULT 0,
+    updated_rows INTEGER NOT NULL DEFAULT 0,
+    failed_rows INTEGER NOT NULL DEFAULT 0,
+    errors JSONB NOT NULL DEFAULT '[]',
+    error_message TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Workers claim the oldest unfinished import
+CREATE INDEX idx_user_imports_claim ON user_imports(status, created_at) WHERE status IN ('pending', 'processing');
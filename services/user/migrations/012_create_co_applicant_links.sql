@@ -0,0 +1,29 @@
+-- Co-applicant links
+-- A primary applicant invites a spouse or co-signer by email; the invitee's account is attached when they accept.
+-- Invite tokens are stored as SHA-256 hashes. Shared document types are chosen by each side independently.
+
+CREATE TABLE co_applicant_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    primary_user_id UUID NOT NULL REFERENCES users(id),
+    co_applicant_user_id UUID REFERENCES users(id),
+    invite_email VARCHAR(255) NOT NULL,
+    relationship VARCHAR(20) NOT NULL CHECK (relationship IN ('spouse', 'co_signer')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'revoked')),
+    invite_token_hash VARCHAR(64) NOT NULL UNIQUE,
+    invite_expires_at TIMESTAMP NOT NULL,
+    primary_shared_document_types JSONB NOT NULL DEFAULT '[]',
+    co_applicant_shared_document_types JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    responded_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT co_applicant_not_self CHECK (co_applicant_user_id IS NULL OR co_applicant_user_id <> primary_user_id)
+);
+
+-- Only one open invitation or active link per primary applicant and invitee
+CREATE UNIQUE INDEX idx_co_applicant_links_active_email ON co_applicant_links(primary_user_id, LOWER(invite_email))
+    WHERE status IN ('pending', 'accepted');
+
+CREATE INDEX idx_co_applicant_links_primary ON co_applicant_links(primary_user_id, created_at DESC);
+CREATE INDEX idx_co_applicant_links_co_applicant ON co_applicant_links(co_applicant_user_id, created_at DESC)
+    WHERE co_applicant_user_id IS NOT NULL;
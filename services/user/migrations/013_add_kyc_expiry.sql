@@ -0,0 +1,22 @@
+-- KYC verification expiry
+-- Verified results carry an expires_at set from the provider's validity period. The expiry job moves
+-- lapsed results to 'stale', which blocks new loan applications until identity is re-verified.
+
+ALTER TYPE kyc_status ADD VALUE IF NOT EXISTS 'stale';
+
+-- When the result was verified; expires_at is counted from it
+ALTER TABLE kyc_verifications ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP;
+
+-- Results verified before this migration: the last review or update is the best record of when
+UPDATE kyc_verifications
+SET verified_at = COALESCE(reviewed_at, updated_at, created_at)
+WHERE status = 'verified' AND verified_at IS NULL;
+
+-- Give them the default validity period (kyc.validity_days, 365), so they lapse like new results
+-- instead of staying current forever
+UPDATE kyc_verifications
+SET expires_at = verified_at + INTERVAL '365 days'
+WHERE status = 'verified' AND expires_at IS NULL;
+
+-- Supports the expiry job's scan for lapsed verified results
+CREATE INDEX IF NOT EXISTS idx_kyc_verifications_status_expires_at ON kyc_verifications(status, expires_at);
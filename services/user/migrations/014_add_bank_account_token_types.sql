@@ -0,0 +1,6 @@
+-- Bank account token types
+-- Borrowers' bank account and routing numbers are held in the vault so the loan service only stores
+-- tokens; the loan service detokenizes them to originate disbursements.
+
+ALTER TYPE pii_token_type ADD VALUE IF NOT EXISTS 'bank_account_number';
+ALTER TYPE pii_token_type ADD VALUE IF NOT EXISTS 'routing_number';
@@ -0,0 +1,4 @@
+-- Imported users are invited to set a password once they have a login identity in auth.
+-- invite_sent_at stays NULL until the invite is sent, so a re-run of the import retries failed invites.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS invite_sent_at TIMESTAMP;